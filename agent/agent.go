@@ -12,7 +12,6 @@ import (
 	"net/http"
 	"net/netip"
 	"os"
-	"os/exec"
 	"os/user"
 	"path/filepath"
 	"sort"
@@ -28,13 +27,21 @@ import (
 	"go.uber.org/atomic"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 	"golang.org/x/xerrors"
 	"tailscale.com/net/speedtest"
 	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
 	"tailscale.com/types/netlogtype"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentconnlog"
+	"github.com/coder/coder/v2/agent/agentlogfiles"
+	"github.com/coder/coder/v2/agent/agentrecord"
+	"github.com/coder/coder/v2/agent/agentscripts"
+	"github.com/coder/coder/v2/agent/agentsocket"
 	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/agent/agentupdate"
 	"github.com/coder/coder/v2/agent/reconnectingpty"
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/gitauth"
@@ -68,19 +75,38 @@ type Options struct {
 	PrometheusRegistry           *prometheus.Registry
 	ReportMetadataInterval       time.Duration
 	ServiceBannerRefreshInterval time.Duration
+	// SocketPath is the path of the Unix socket processes in the workspace
+	// use to talk to the agent. The socket is disabled if empty.
+	SocketPath string
+	// Updater replaces the agent binary when its version differs from
+	// coderd. Self-updates are disabled if nil.
+	Updater Updater
+	// ExecState is handed over by the agent that updated itself, nil if
+	// this agent wasn't started by an update.
+	ExecState *agentupdate.ExecState
+	// UpdateRetryInterval is how often a postponed self-update is retried.
+	UpdateRetryInterval time.Duration
 }
 
 type Client interface {
 	Manifest(ctx context.Context) (agentsdk.Manifest, error)
 	Listen(ctx context.Context) (net.Conn, error)
+	Peers(ctx context.Context) ([]agentsdk.Peer, error)
+	ListenPeer(ctx context.Context, agentID uuid.UUID) (net.Conn, error)
 	DERPMapUpdates(ctx context.Context) (<-chan agentsdk.DERPMapUpdate, io.Closer, error)
 	ReportStats(ctx context.Context, log slog.Logger, statsChan <-chan *agentsdk.Stats, setInterval func(time.Duration)) (io.Closer, error)
 	PostLifecycle(ctx context.Context, state agentsdk.PostLifecycleRequest) error
+	PostAutostopInhibitors(ctx context.Context, req agentsdk.PostAutostopInhibitorsRequest) error
+	PostUpdateState(ctx context.Context, req agentsdk.PostUpdateStateRequest) error
 	PostAppHealth(ctx context.Context, req agentsdk.PostAppHealthsRequest) error
 	PostStartup(ctx context.Context, req agentsdk.PostStartupRequest) error
 	PostMetadata(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error
 	PatchLogs(ctx context.Context, req agentsdk.PatchLogs) error
+	PostScriptStatus(ctx context.Context, req agentsdk.PostScriptStatusRequest) error
 	GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerConfig, error)
+	PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error
+	PostSessionRecordingChunk(ctx context.Context, id uuid.UUID, req agentsdk.PostSessionRecordingChunkRequest) error
+	PostConnection(ctx context.Context, req agentsdk.PostConnectionRequest) error
 }
 
 type Agent interface {
@@ -114,6 +140,18 @@ func New(options Options) Agent {
 	if options.ServiceBannerRefreshInterval == 0 {
 		options.ServiceBannerRefreshInterval = 2 * time.Minute
 	}
+	if options.UpdateRetryInterval == 0 {
+		options.UpdateRetryInterval = time.Minute
+	}
+
+	envVars := make(map[string]string, len(options.EnvironmentVariables)+1)
+	for k, v := range options.EnvironmentVariables {
+		envVars[k] = v
+	}
+	if options.SocketPath != "" {
+		// Let processes in the workspace find the agent socket.
+		envVars[agentsocket.EnvSocketPath] = options.SocketPath
+	}
 
 	prometheusRegistry := options.PrometheusRegistry
 	if prometheusRegistry == nil {
@@ -124,10 +162,11 @@ func New(options Options) Agent {
 	a := &agent{
 		tailnetListenPort:            options.TailnetListenPort,
 		reconnectingPTYTimeout:       options.ReconnectingPTYTimeout,
+		reconnectingPTYSessions:      map[uuid.UUID]*codersdk.WorkspaceAgentReconnectingPTYSession{},
 		logger:                       options.Logger,
 		closeCancel:                  cancelFunc,
 		closed:                       make(chan struct{}),
-		envVars:                      options.EnvironmentVariables,
+		envVars:                      envVars,
 		client:                       options.Client,
 		exchangeToken:                options.ExchangeToken,
 		filesystem:                   options.Filesystem,
@@ -136,6 +175,9 @@ func New(options Options) Agent {
 		lifecycleUpdate:              make(chan struct{}, 1),
 		lifecycleReported:            make(chan codersdk.WorkspaceAgentLifecycle, 1),
 		lifecycleStates:              []agentsdk.PostLifecycleRequest{{State: codersdk.WorkspaceAgentLifecycleCreated}},
+		autostopInhibitorsUpdate:     make(chan struct{}, 1),
+		autostopInhibitors:           map[uuid.UUID]codersdk.WorkspaceAgentAutostopInhibitor{},
+		peers:                        map[uuid.UUID]*codersdk.WorkspaceAgentConn{},
 		ignorePorts:                  options.IgnorePorts,
 		connStatsChan:                make(chan *agentsdk.Stats, 1),
 		reportMetadataInterval:       options.ReportMetadataInterval,
@@ -143,6 +185,10 @@ func New(options Options) Agent {
 		sshMaxTimeout:                options.SSHMaxTimeout,
 		subsystems:                   options.Subsystems,
 		addresses:                    options.Addresses,
+		socketPath:                   options.SocketPath,
+		updater:                      options.Updater,
+		execState:                    options.ExecState,
+		updateRetryInterval:          options.UpdateRetryInterval,
 
 		prometheusRegistry: prometheusRegistry,
 		metrics:            newAgentMetrics(prometheusRegistry),
@@ -167,6 +213,10 @@ type agent struct {
 
 	reconnectingPTYs       sync.Map
 	reconnectingPTYTimeout time.Duration
+	// reconnectingPTYSessions describes the sessions in reconnectingPTYs so
+	// they can be listed.
+	reconnectingPTYSessionsMu sync.Mutex
+	reconnectingPTYSessions   map[uuid.UUID]*codersdk.WorkspaceAgentReconnectingPTYSession
 
 	connCloseWait sync.WaitGroup
 	closeCancel   context.CancelFunc
@@ -182,19 +232,38 @@ type agent struct {
 	sessionToken                 atomic.Pointer[string]
 	sshServer                    *agentssh.Server
 	sshMaxTimeout                time.Duration
+	connReporter                 *agentconnlog.Reporter
+
+	scriptRunner  *agentscripts.Runner
+	logFileTailer *agentlogfiles.Tailer
 
 	lifecycleUpdate   chan struct{}
 	lifecycleReported chan codersdk.WorkspaceAgentLifecycle
 	lifecycleMu       sync.RWMutex // Protects following.
 	lifecycleStates   []agentsdk.PostLifecycleRequest
 
+	autostopInhibitorsUpdate chan struct{}
+	autostopInhibitorsMu     sync.Mutex // Protects following.
+	autostopInhibitors       map[uuid.UUID]codersdk.WorkspaceAgentAutostopInhibitor
+
 	network       *tailnet.Conn
 	addresses     []netip.Prefix
 	connStatsChan chan *agentsdk.Stats
 	latestStat    atomic.Pointer[agentsdk.Stats]
 
+	peersMu   sync.Mutex
+	peers     map[uuid.UUID]*codersdk.WorkspaceAgentConn
+	peerDials singleflight.Group
+
 	connCountReconnectingPTY atomic.Int64
 
+	socketPath   string
+	socketServer *http.Server
+
+	updater             Updater
+	execState           *agentupdate.ExecState
+	updateRetryInterval time.Duration
+
 	prometheusRegistry *prometheus.Registry
 	metrics            *agentMetrics
 }
@@ -212,7 +281,24 @@ func (a *agent) init(ctx context.Context) {
 	sshSrv.AgentToken = func() string { return *a.sessionToken.Load() }
 	sshSrv.Manifest = &a.manifest
 	sshSrv.ServiceBanner = &a.serviceBanner
+	sshSrv.RecordingClient = a.client
+	a.connReporter = agentconnlog.New(a.logger.Named("connection-log"), a.client)
+	sshSrv.ConnectionReporter = a.connReporter
 	a.sshServer = sshSrv
+	a.scriptRunner = agentscripts.New(agentscripts.Options{
+		LogDir:           a.logDir,
+		Logger:           a.logger,
+		SSHServer:        sshSrv,
+		Filesystem:       a.filesystem,
+		PatchLogs:        a.client.PatchLogs,
+		PostScriptStatus: a.client.PostScriptStatus,
+	})
+	a.logFileTailer = agentlogfiles.New(agentlogfiles.Options{
+		Logger:     a.logger.Named("log-files"),
+		Filesystem: a.filesystem,
+		PatchLogs:  a.client.PatchLogs,
+	})
+	a.startSocketServer(ctx)
 
 	go a.runLoop(ctx)
 }
@@ -224,6 +310,7 @@ func (a *agent) init(ctx context.Context) {
 func (a *agent) runLoop(ctx context.Context) {
 	go a.reportLifecycleLoop(ctx)
 	go a.reportMetadataLoop(ctx)
+	go a.reportAutostopInhibitorsLoop(ctx)
 	go a.fetchServiceBannerLoop(ctx)
 
 	for retrier := retry.New(100*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
@@ -406,6 +493,11 @@ func (a *agent) reportMetadataLoop(ctx context.Context) {
 		// mutex logic and overloading the API.
 		for _, md := range manifest.Metadata {
 			md := md
+			// Metadata without a script is only set through the agent
+			// socket.
+			if md.Script == "" {
+				continue
+			}
 			// We send the result to the channel in the goroutine to avoid
 			// sending the same result multiple times. So, we don't care about
 			// the return values.
@@ -612,10 +704,16 @@ func (a *agent) run(ctx context.Context) error {
 	}
 
 	oldManifest := a.manifest.Swap(&manifest)
+	// Report the inhibitors held, coderd may have stale ones from before the
+	// agent restarted or lost its connection.
+	a.notifyAutostopInhibitors()
 
 	// The startup script should only execute on the first run!
 	if oldManifest == nil {
-		a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStarting)
+		startedBeforeUpdate := a.startedBeforeUpdate()
+		if !startedBeforeUpdate {
+			a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStarting)
+		}
 
 		// Perform overrides early so that Git auth can work even if users
 		// connect to a workspace that is not yet ready. We don't run this
@@ -631,48 +729,47 @@ func (a *agent) run(ctx context.Context) error {
 			}
 		}
 
-		lifecycleState := codersdk.WorkspaceAgentLifecycleReady
-		scriptDone := make(chan error, 1)
-		err = a.trackConnGoroutine(func() {
-			defer close(scriptDone)
-			scriptDone <- a.runStartupScript(ctx, manifest.StartupScript)
-		})
+		err = a.scriptRunner.Init(manifest.Scripts)
 		if err != nil {
-			return xerrors.Errorf("track startup script: %w", err)
+			return xerrors.Errorf("init script runner: %w", err)
 		}
-		go func() {
-			var timeout <-chan time.Time
-			// If timeout is zero, an older version of the coder
-			// provider was used. Otherwise a timeout is always > 0.
-			if manifest.StartupScriptTimeout > 0 {
-				t := time.NewTimer(manifest.StartupScriptTimeout)
-				defer t.Stop()
-				timeout = t.C
-			}
-
-			var err error
-			select {
-			case err = <-scriptDone:
-			case <-timeout:
-				a.logger.Warn(ctx, "script timed out", slog.F("lifecycle", "startup"), slog.F("timeout", manifest.StartupScriptTimeout))
-				a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStartTimeout)
-				err = <-scriptDone // The script can still complete after a timeout.
-			}
-			if err != nil {
-				if errors.Is(err, context.Canceled) {
-					return
+		a.logFileTailer.Start(manifest.LogFiles)
+		if startedBeforeUpdate {
+			// The agent that updated itself already ran the startup
+			// scripts, only the cron scripts are started again.
+			a.setLifecycle(ctx, a.execState.Lifecycle)
+			a.scriptRunner.StartCron()
+		} else {
+			err = a.trackConnGoroutine(func() {
+				err := a.scriptRunner.Execute(ctx, func(script codersdk.WorkspaceAgentScript) bool {
+					return script.RunOnStart
+				})
+				if err != nil {
+					if errors.Is(err, context.Canceled) {
+						return
+					}
+					a.logger.Warn(ctx, "startup script(s) failed", slog.Error(err))
+					if errors.Is(err, agentscripts.ErrTimeout) {
+						a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStartTimeout)
+					} else {
+						a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStartError)
+					}
+				} else {
+					a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleReady)
 				}
-				lifecycleState = codersdk.WorkspaceAgentLifecycleStartError
+				a.scriptRunner.StartCron()
+			})
+			if err != nil {
+				return xerrors.Errorf("track conn goroutine: %w", err)
 			}
-			a.setLifecycle(ctx, lifecycleState)
-		}()
+		}
 	}
 
 	// This automatically closes when the context ends!
 	appReporterCtx, appReporterCtxCancel := context.WithCancel(ctx)
 	defer appReporterCtxCancel()
 	go NewWorkspaceAppHealthReporter(
-		a.logger, manifest.Apps, a.client.PostAppHealth)(appReporterCtx)
+		a.logger, manifest.Apps, a.sshServer.CreateCommand, a.client.PostAppHealth)(appReporterCtx)
 
 	a.closeMutex.Lock()
 	network := a.network
@@ -708,6 +805,15 @@ func (a *agent) run(ctx context.Context) error {
 		network.SetBlockEndpoints(manifest.DisableDirectConnections)
 	}
 
+	// The update hands the tailnet key over to the updated agent, so it
+	// only starts once the network exists.
+	err = a.trackConnGoroutine(func() {
+		a.checkForUpdate(ctx, manifest)
+	})
+	if err != nil {
+		return xerrors.Errorf("track conn goroutine: %w", err)
+	}
+
 	eg, egCtx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
 		a.logger.Debug(egCtx, "running tailnet connection coordinator")
@@ -759,6 +865,12 @@ func (a *agent) trackConnGoroutine(fn func()) error {
 }
 
 func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *tailcfg.DERPMap, derpForceWebSockets, disableDirectConnections bool) (_ *tailnet.Conn, err error) {
+	var nodePrivateKey key.NodePrivate
+	if a.execState != nil {
+		// Keep the identity of the agent that updated itself, so peers
+		// keep their connections.
+		nodePrivateKey = a.execState.NodePrivateKey
+	}
 	network, err := tailnet.NewConn(&tailnet.Options{
 		ID:                  agentID,
 		Addresses:           a.wireguardAddresses(agentID),
@@ -767,6 +879,7 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 		Logger:              a.logger.Named("net.tailnet"),
 		ListenPort:          a.tailnetListenPort,
 		BlockEndpoints:      disableDirectConnections,
+		NodePrivateKey:      nodePrivateKey,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -975,98 +1088,12 @@ func (a *agent) runDERPMapSubscriber(ctx context.Context, network *tailnet.Conn)
 			if update.DERPMap != nil && !tailnet.CompareDERPMaps(network.DERPMap(), update.DERPMap) {
 				a.logger.Info(ctx, "updating derp map due to detected changes")
 				network.SetDERPMap(update.DERPMap)
+				a.setPeersDERPMap(update.DERPMap)
 			}
 		}
 	}
 }
 
-func (a *agent) runStartupScript(ctx context.Context, script string) error {
-	return a.runScript(ctx, "startup", script)
-}
-
-func (a *agent) runShutdownScript(ctx context.Context, script string) error {
-	return a.runScript(ctx, "shutdown", script)
-}
-
-func (a *agent) runScript(ctx context.Context, lifecycle, script string) (err error) {
-	if script == "" {
-		return nil
-	}
-
-	logger := a.logger.With(slog.F("lifecycle", lifecycle))
-
-	logger.Info(ctx, fmt.Sprintf("running %s script", lifecycle), slog.F("script", script))
-	fileWriter, err := a.filesystem.OpenFile(filepath.Join(a.logDir, fmt.Sprintf("coder-%s-script.log", lifecycle)), os.O_CREATE|os.O_RDWR, 0o600)
-	if err != nil {
-		return xerrors.Errorf("open %s script log file: %w", lifecycle, err)
-	}
-	defer func() {
-		err := fileWriter.Close()
-		if err != nil {
-			logger.Warn(ctx, fmt.Sprintf("close %s script log file", lifecycle), slog.Error(err))
-		}
-	}()
-
-	cmdPty, err := a.sshServer.CreateCommand(ctx, script, nil)
-	if err != nil {
-		return xerrors.Errorf("%s script: create command: %w", lifecycle, err)
-	}
-	cmd := cmdPty.AsExec()
-
-	var stdout, stderr io.Writer = fileWriter, fileWriter
-	if lifecycle == "startup" {
-		send, flushAndClose := agentsdk.LogsSender(a.client.PatchLogs, logger)
-		// If ctx is canceled here (or in a writer below), we may be
-		// discarding logs, but that's okay because we're shutting down
-		// anyway. We could consider creating a new context here if we
-		// want better control over flush during shutdown.
-		defer func() {
-			if err := flushAndClose(ctx); err != nil {
-				logger.Warn(ctx, "flush startup logs failed", slog.Error(err))
-			}
-		}()
-
-		infoW := agentsdk.StartupLogsWriter(ctx, send, codersdk.WorkspaceAgentLogSourceStartupScript, codersdk.LogLevelInfo)
-		defer infoW.Close()
-		errW := agentsdk.StartupLogsWriter(ctx, send, codersdk.WorkspaceAgentLogSourceStartupScript, codersdk.LogLevelError)
-		defer errW.Close()
-
-		stdout = io.MultiWriter(fileWriter, infoW)
-		stderr = io.MultiWriter(fileWriter, errW)
-	}
-
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	start := time.Now()
-	defer func() {
-		end := time.Now()
-		execTime := end.Sub(start)
-		exitCode := 0
-		if err != nil {
-			exitCode = 255 // Unknown status.
-			var exitError *exec.ExitError
-			if xerrors.As(err, &exitError) {
-				exitCode = exitError.ExitCode()
-			}
-			logger.Warn(ctx, fmt.Sprintf("%s script failed", lifecycle), slog.F("execution_time", execTime), slog.F("exit_code", exitCode), slog.Error(err))
-		} else {
-			logger.Info(ctx, fmt.Sprintf("%s script completed", lifecycle), slog.F("execution_time", execTime), slog.F("exit_code", exitCode))
-		}
-	}()
-
-	err = cmd.Run()
-	if err != nil {
-		// cmd.Run does not return a context canceled error, it returns "signal: killed".
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
-
-		return xerrors.Errorf("%s script: run: %w", lifecycle, err)
-	}
-	return nil
-}
-
 func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, msg codersdk.WorkspaceAgentReconnectingPTYInit, conn net.Conn) (retErr error) {
 	defer conn.Close()
 	a.metrics.connectionsTotal.Add(1)
@@ -1078,6 +1105,16 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 	connLogger := logger.With(slog.F("message_id", msg.ID), slog.F("connection_id", connectionID))
 	connLogger.Debug(ctx, "starting handler")
 
+	logged := a.connReporter.Connect(codersdk.ConnectionTypeReconnectingPTY, conn.RemoteAddr(), "")
+	defer func() {
+		var reason string
+		if retErr != nil {
+			reason = retErr.Error()
+		}
+		logged.Disconnect(nil, reason)
+	}()
+	conn = logged.Conn(conn)
+
 	defer func() {
 		if err := retErr; err != nil {
 			a.closeMutex.Lock()
@@ -1097,8 +1134,23 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 
 	var rpty reconnectingpty.ReconnectingPTY
 	sendConnected := make(chan reconnectingpty.ReconnectingPTY, 1)
-	// On store, reserve this ID to prevent multiple concurrent new connections.
-	waitReady, ok := a.reconnectingPTYs.LoadOrStore(msg.ID, sendConnected)
+	var (
+		waitReady any
+		ok        bool
+	)
+	if msg.AttachOnly {
+		// Connections that may only attach must never spawn a session.
+		waitReady, ok = a.reconnectingPTYs.Load(msg.ID)
+		if !ok {
+			// Closing the connection tells the caller the session is gone,
+			// which is expected for sessions that ended.
+			connLogger.Debug(ctx, "reconnecting pty does not exist, closing connection")
+			return nil
+		}
+	} else {
+		// On store, reserve this ID to prevent multiple concurrent new connections.
+		waitReady, ok = a.reconnectingPTYs.LoadOrStore(msg.ID, sendConnected)
+	}
 	if ok {
 		close(sendConnected) // Unused.
 		connLogger.Debug(ctx, "connecting to existing reconnecting pty")
@@ -1134,9 +1186,20 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			Metrics: a.metrics.reconnectingPTYErrors,
 		}, logger.With(slog.F("message_id", msg.ID)))
 
+		a.reconnectingPTYSessionsMu.Lock()
+		a.reconnectingPTYSessions[msg.ID] = &codersdk.WorkspaceAgentReconnectingPTYSession{
+			ID:        msg.ID,
+			Command:   msg.Command,
+			CreatedAt: time.Now(),
+		}
+		a.reconnectingPTYSessionsMu.Unlock()
+
 		if err = a.trackConnGoroutine(func() {
 			rpty.Wait()
 			a.reconnectingPTYs.Delete(msg.ID)
+			a.reconnectingPTYSessionsMu.Lock()
+			delete(a.reconnectingPTYSessions, msg.ID)
+			a.reconnectingPTYSessionsMu.Unlock()
 		}); err != nil {
 			rpty.Close(err)
 			return xerrors.Errorf("start routine: %w", err)
@@ -1145,7 +1208,54 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		connected = true
 		sendConnected <- rpty
 	}
-	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, connLogger)
+
+	// Every connection is recorded separately, since that's what each user
+	// saw and typed.
+	if manifest := a.manifest.Load(); manifest != nil && manifest.RecordSessions {
+		recorder := agentrecord.New(connLogger.Named("recorder"), a.client, agentrecord.Options{
+			Type:        codersdk.WorkspaceSessionRecordingTypeReconnectingPTY,
+			ReconnectID: msg.ID,
+			Command:     msg.Command,
+			Term:        "xterm-256color",
+			Width:       msg.Width,
+			Height:      msg.Height,
+			RecordInput: manifest.RecordSessionInput,
+		})
+		defer func() {
+			_ = recorder.Close()
+		}()
+		conn = reconnectingpty.RecordConn(conn, recorder, msg.ReadOnly)
+		defer conn.Close()
+	}
+
+	a.addReconnectingPTYConnections(msg.ID, 1)
+	defer a.addReconnectingPTYConnections(msg.ID, -1)
+	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, msg.ReadOnly, connLogger)
+}
+
+// addReconnectingPTYConnections adjusts the number of connections attached to
+// a reconnecting PTY session.
+func (a *agent) addReconnectingPTYConnections(id uuid.UUID, delta int64) {
+	a.reconnectingPTYSessionsMu.Lock()
+	defer a.reconnectingPTYSessionsMu.Unlock()
+	if session, ok := a.reconnectingPTYSessions[id]; ok {
+		session.Connections += delta
+	}
+}
+
+// reconnectingPTYSessionsList returns the live reconnecting PTY sessions,
+// oldest first.
+func (a *agent) reconnectingPTYSessionsList() []codersdk.WorkspaceAgentReconnectingPTYSession {
+	a.reconnectingPTYSessionsMu.Lock()
+	defer a.reconnectingPTYSessionsMu.Unlock()
+	sessions := make([]codersdk.WorkspaceAgentReconnectingPTYSession, 0, len(a.reconnectingPTYSessions))
+	for _, session := range a.reconnectingPTYSessions {
+		sessions = append(sessions, *session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions
 }
 
 // startReportingConnectionStats runs the connection stats reporting goroutine.
@@ -1336,31 +1446,14 @@ func (a *agent) Close() error {
 	}
 
 	lifecycleState := codersdk.WorkspaceAgentLifecycleOff
-	if manifest := a.manifest.Load(); manifest != nil && manifest.ShutdownScript != "" {
-		scriptDone := make(chan error, 1)
-		go func() {
-			defer close(scriptDone)
-			scriptDone <- a.runShutdownScript(ctx, manifest.ShutdownScript)
-		}()
-
-		var timeout <-chan time.Time
-		// If timeout is zero, an older version of the coder
-		// provider was used. Otherwise a timeout is always > 0.
-		if manifest.ShutdownScriptTimeout > 0 {
-			t := time.NewTimer(manifest.ShutdownScriptTimeout)
-			defer t.Stop()
-			timeout = t.C
-		}
-
-		var err error
-		select {
-		case err = <-scriptDone:
-		case <-timeout:
-			a.logger.Warn(ctx, "script timed out", slog.F("lifecycle", "shutdown"), slog.F("timeout", manifest.ShutdownScriptTimeout))
-			a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleShutdownTimeout)
-			err = <-scriptDone // The script can still complete after a timeout.
-		}
-		if err != nil {
+	err = a.scriptRunner.Execute(ctx, func(script codersdk.WorkspaceAgentScript) bool {
+		return script.RunOnStop
+	})
+	if err != nil {
+		a.logger.Warn(ctx, "shutdown script(s) failed", slog.Error(err))
+		if errors.Is(err, agentscripts.ErrTimeout) {
+			lifecycleState = codersdk.WorkspaceAgentLifecycleShutdownTimeout
+		} else {
 			lifecycleState = codersdk.WorkspaceAgentLifecycleShutdownError
 		}
 	}
@@ -1387,11 +1480,19 @@ lifecycleWaitLoop:
 
 	close(a.closed)
 	a.closeCancel()
+	if a.socketServer != nil {
+		_ = a.socketServer.Close()
+	}
+	_ = a.scriptRunner.Close()
+	_ = a.logFileTailer.Close()
 	_ = a.sshServer.Close()
+	a.closePeers()
 	if a.network != nil {
 		_ = a.network.Close()
 	}
 	a.connCloseWait.Wait()
+	// Report connections that were closed above.
+	_ = a.connReporter.Close()
 
 	return nil
 }
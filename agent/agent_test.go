@@ -39,12 +39,15 @@ import (
 	"golang.org/x/xerrors"
 	"tailscale.com/net/speedtest"
 	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentsocket"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/agent/agentupdate"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -1069,8 +1072,11 @@ func TestAgent_StartupScript(t *testing.T) {
 			logger,
 			uuid.New(),
 			agentsdk.Manifest{
-				StartupScript: command,
-				DERPMap:       &tailcfg.DERPMap{},
+				DERPMap: &tailcfg.DERPMap{},
+				Scripts: []codersdk.WorkspaceAgentScript{{
+					Script:     command,
+					RunOnStart: true,
+				}},
 			},
 			make(chan *agentsdk.Stats),
 			tailnet.NewCoordinator(logger),
@@ -1101,8 +1107,11 @@ func TestAgent_StartupScript(t *testing.T) {
 			logger,
 			uuid.New(),
 			agentsdk.Manifest{
-				StartupScript: command,
-				DERPMap:       &tailcfg.DERPMap{},
+				DERPMap: &tailcfg.DERPMap{},
+				Scripts: []codersdk.WorkspaceAgentScript{{
+					Script:     command,
+					RunOnStart: true,
+				}},
 			},
 			make(chan *agentsdk.Stats, 50),
 			tailnet.NewCoordinator(logger),
@@ -1133,6 +1142,191 @@ func TestAgent_StartupScript(t *testing.T) {
 	})
 }
 
+func TestAgent_LogFiles(t *testing.T) {
+	t.Parallel()
+
+	//nolint:dogsled
+	_, client, _, fs, _ := setupAgent(t, agentsdk.Manifest{
+		LogFiles: []codersdk.WorkspaceAgentLogFile{{
+			ID:          uuid.New(),
+			LogSourceID: uuid.New(),
+			Path:        "/var/log/app.log",
+		}},
+	}, 0)
+	require.Eventually(t, func() bool {
+		got := client.GetLifecycleStates()
+		return len(got) > 0 && got[len(got)-1] == codersdk.WorkspaceAgentLifecycleReady
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// The file is tailed once it is created.
+	err := afero.WriteFile(fs, "/var/log/app.log", []byte("listening on :8080\n"), 0o600)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		logs := client.GetStartupLogs()
+		return len(logs) == 1 && logs[0].Output == "listening on :8080"
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestAgent_Update(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		updater := &fakeUpdater{needsUpdate: true}
+		//nolint:dogsled
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			DisableAutoUpdate: true,
+		}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.Updater = updater
+		})
+		require.Eventually(t, func() bool {
+			states := client.GetUpdateStates()
+			return len(states) == 1 && states[0].State == codersdk.WorkspaceAgentUpdateStateDisabled
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Empty(t, updater.execStates())
+	})
+
+	t.Run("UpToDate", func(t *testing.T) {
+		t.Parallel()
+		updater := &fakeUpdater{}
+		//nolint:dogsled
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.Updater = updater
+		})
+		require.Eventually(t, func() bool {
+			states := client.GetUpdateStates()
+			return len(states) == 1 && states[0].State == codersdk.WorkspaceAgentUpdateStateUpToDate
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		t.Parallel()
+		updater := &fakeUpdater{needsUpdate: true, execErr: xerrors.New("exec failed")}
+		//nolint:dogsled
+		_, client, _, _, agnt := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			// The failed exec is logged as an error.
+			o.Logger = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Named("agent").Leveled(slog.LevelDebug)
+			o.Updater = updater
+			o.UpdateRetryInterval = testutil.IntervalFast
+		})
+		require.Eventually(t, func() bool {
+			return len(updater.execStates()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// The updated agent keeps the tailnet identity and doesn't run the
+		// startup scripts again.
+		state := updater.execStates()[0]
+		require.Equal(t, codersdk.WorkspaceAgentLifecycleReady, state.Lifecycle)
+		require.True(t, agnt.TailnetConn().NodePrivateKey().Equal(state.NodePrivateKey))
+
+		require.Eventually(t, func() bool {
+			states := client.GetUpdateStates()
+			return len(states) == 2 &&
+				states[0].State == codersdk.WorkspaceAgentUpdateStateUpdating &&
+				states[1].State == codersdk.WorkspaceAgentUpdateStateFailed &&
+				strings.Contains(states[1].Error, "exec failed")
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("AutostopInhibited", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("The agent socket only works on Linux and macOS.")
+		}
+
+		socketPath := filepath.Join(t.TempDir(), "agent.sock")
+		updater := &fakeUpdater{needsUpdate: true, execErr: xerrors.New("exec failed"), checkGate: make(chan struct{})}
+		//nolint:dogsled
+		_, _, _, _, _ = setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.Logger = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Named("agent").Leveled(slog.LevelDebug)
+			o.SocketPath = socketPath
+			o.Updater = updater
+			o.UpdateRetryInterval = testutil.IntervalFast
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, release, err := agentsocket.NewClient(socketPath).InhibitAutostop(ctx, agentsocket.InhibitAutostopRequest{
+			Reason: "make all",
+		})
+		require.NoError(t, err)
+		close(updater.checkGate)
+
+		// The inhibitor would be lost with the agent process.
+		require.Never(t, func() bool {
+			return len(updater.execStates()) > 0
+		}, testutil.IntervalSlow, testutil.IntervalFast)
+
+		require.NoError(t, release.Close())
+		require.Eventually(t, func() bool {
+			return len(updater.execStates()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("ExecState", func(t *testing.T) {
+		t.Parallel()
+		nodePrivateKey := key.NewNode()
+		//nolint:dogsled
+		_, client, _, _, agnt := setupAgent(t, agentsdk.Manifest{
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "echo started",
+				RunOnStart: true,
+			}},
+		}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.Updater = &fakeUpdater{}
+			o.ExecState = &agentupdate.ExecState{
+				PreviousVersion: "v2.1.0",
+				NodePrivateKey:  nodePrivateKey,
+				Lifecycle:       codersdk.WorkspaceAgentLifecycleReady,
+			}
+		})
+		require.Eventually(t, func() bool {
+			states := client.GetUpdateStates()
+			return len(states) == 1 && states[0].State == codersdk.WorkspaceAgentUpdateStateUpdated
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, []codersdk.WorkspaceAgentLifecycle{codersdk.WorkspaceAgentLifecycleReady}, client.GetLifecycleStates())
+		require.Empty(t, client.GetStartupLogs())
+		require.True(t, agnt.TailnetConn().NodePrivateKey().Equal(nodePrivateKey))
+	})
+}
+
+type fakeUpdater struct {
+	needsUpdate bool
+	execErr     error
+	// checkGate blocks Check until it is closed, if set.
+	checkGate chan struct{}
+
+	mu    sync.Mutex
+	execs []agentupdate.ExecState
+}
+
+func (u *fakeUpdater) Check(ctx context.Context) (string, bool, error) {
+	if u.checkGate != nil {
+		select {
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		case <-u.checkGate:
+		}
+	}
+	return "v2.2.0", u.needsUpdate, nil
+}
+
+func (*fakeUpdater) Download(context.Context, string) (string, error) {
+	return "/tmp/coder-updated", nil
+}
+
+func (u *fakeUpdater) Exec(_ string, state agentupdate.ExecState) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.execs = append(u.execs, state)
+	return u.execErr
+}
+
+func (u *fakeUpdater) execStates() []agentupdate.ExecState {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]agentupdate.ExecState{}, u.execs...)
+}
+
 func TestAgent_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -1204,6 +1398,204 @@ func TestAgent_Metadata(t *testing.T) {
 	})
 }
 
+func TestAgent_Socket(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The session command only works on Linux and macOS.")
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	//nolint:dogsled
+	conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+		AgentName:     "dev",
+		OwnerName:     "alice",
+		WorkspaceName: "workspace",
+		Metadata: []codersdk.WorkspaceAgentMetadataDescription{{
+			Key:         "branch",
+			DisplayName: "Branch",
+		}},
+	}, 0, func(_ *agenttest.Client, opts *agent.Options) {
+		opts.SocketPath = socketPath
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+	socket := agentsocket.NewClient(socketPath)
+
+	status, err := socket.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "dev", status.AgentName)
+	require.Equal(t, "alice", status.OwnerName)
+	require.Equal(t, "workspace", status.WorkspaceName)
+	require.Equal(t, []string{"branch"}, status.MetadataKeys)
+
+	err = socket.PostLogs(ctx, agentsocket.PostLogsRequest{
+		Logs: []agentsdk.Log{{Output: "hello from the socket"}},
+	})
+	require.NoError(t, err)
+	logs := client.GetStartupLogs()
+	require.Len(t, logs, 1)
+	require.Equal(t, "hello from the socket", logs[0].Output)
+	require.Equal(t, codersdk.LogLevelInfo, logs[0].Level)
+
+	err = socket.PostMetadata(ctx, "branch", agentsocket.PostMetadataRequest{Value: "main"})
+	require.NoError(t, err)
+	require.Equal(t, "main", client.GetMetadata()["branch"].Value)
+
+	err = socket.PostMetadata(ctx, "unknown", agentsocket.PostMetadataRequest{Value: "main"})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Sessions know where to find the socket.
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	output, err := session.Output("echo $" + agentsocket.EnvSocketPath)
+	require.NoError(t, err)
+	require.Equal(t, socketPath, strings.TrimSpace(string(output)))
+}
+
+func TestAgent_AutostopInhibitors(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The agent socket only works on Linux and macOS.")
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	//nolint:dogsled
+	_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, opts *agent.Options) {
+		opts.SocketPath = socketPath
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+	socket := agentsocket.NewClient(socketPath)
+
+	_, _, err := socket.InhibitAutostop(ctx, agentsocket.InhibitAutostopRequest{})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	inhibitor, release, err := socket.InhibitAutostop(ctx, agentsocket.InhibitAutostopRequest{
+		Reason: "make all",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "make all", inhibitor.Reason)
+
+	require.Eventually(t, func() bool {
+		inhibitors := client.GetAutostopInhibitors()
+		return len(inhibitors) == 1 && inhibitors[0].ID == inhibitor.ID
+	}, testutil.WaitShort, testutil.IntervalFast)
+	status, err := socket.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, status.AutostopInhibitors, 1)
+	require.Equal(t, "make all", status.AutostopInhibitors[0].Reason)
+
+	// Closing the connection releases the inhibitor.
+	require.NoError(t, release.Close())
+	require.Eventually(t, func() bool {
+		return len(client.GetAutostopInhibitors()) == 0
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestAgent_Peers(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The agent socket only works on Linux and macOS.")
+	}
+
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	derpMap, _ := tailnettest.RunDERPAndSTUN(t)
+	coordinator := tailnet.NewCoordinator(logger)
+	t.Cleanup(func() {
+		_ = coordinator.Close()
+	})
+	newAgent := func(manifest agentsdk.Manifest, socketPath string) *agenttest.Client {
+		manifest.AgentID = uuid.New()
+		manifest.DERPMap = derpMap
+		client := agenttest.NewClient(t, logger.Named(manifest.WorkspaceName), manifest.AgentID, manifest, make(chan *agentsdk.Stats, 50), coordinator)
+		closer := agent.New(agent.Options{
+			Client:     client,
+			Filesystem: afero.NewMemMapFs(),
+			Logger:     logger.Named(manifest.WorkspaceName),
+			SocketPath: socketPath,
+		})
+		t.Cleanup(func() {
+			_ = closer.Close()
+		})
+		return client
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	frontend := newAgent(agentsdk.Manifest{
+		AgentName:     "main",
+		OwnerName:     "alice",
+		WorkspaceName: "frontend",
+	}, socketPath)
+	backend := newAgent(agentsdk.Manifest{
+		AgentName:     "main",
+		OwnerName:     "alice",
+		WorkspaceName: "backend",
+	}, "")
+	backendManifest, err := backend.Manifest(context.Background())
+	require.NoError(t, err)
+	frontend.SetPeers([]agentsdk.Peer{{
+		AgentID:       backendManifest.AgentID,
+		AgentName:     "main",
+		WorkspaceName: "backend",
+		OwnerName:     "alice",
+		Status:        codersdk.WorkspaceAgentConnected,
+	}})
+
+	// The backend serves an echo server on a port of its host.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	socket := agentsocket.NewClient(socketPath)
+	require.Eventually(t, func() bool {
+		_, err := socket.Status(ctx)
+		return err == nil
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	peers, err := socket.Peers(ctx)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	require.Equal(t, backendManifest.AgentID, peers[0].AgentID)
+
+	for _, name := range []string{"backend", "alice/backend.main", backendManifest.AgentID.String()} {
+		conn, err := socket.DialPeer(ctx, name, port)
+		require.NoError(t, err, name)
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+		b := make([]byte, 4)
+		_, err = io.ReadFull(conn, b)
+		require.NoError(t, err)
+		require.Equal(t, "ping", string(b))
+		require.NoError(t, conn.Close())
+	}
+
+	// Agents that aren't peers can't be dialed.
+	_, err = socket.DialPeer(ctx, "bob/backend", port)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
 func TestAgentMetadata_Timing(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		// Shell scripting in Windows is a pain, and we have already tested
@@ -1287,8 +1679,11 @@ func TestAgent_Lifecycle(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
-			StartupScript:        "sleep 3",
-			StartupScriptTimeout: time.Nanosecond,
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "sleep 3",
+				Timeout:    time.Nanosecond,
+				RunOnStart: true,
+			}},
 		}, 0)
 
 		want := []codersdk.WorkspaceAgentLifecycle{
@@ -1299,18 +1694,27 @@ func TestAgent_Lifecycle(t *testing.T) {
 		var got []codersdk.WorkspaceAgentLifecycle
 		assert.Eventually(t, func() bool {
 			got = client.GetLifecycleStates()
-			return slices.Contains(got, want[len(want)-1])
+			return len(got) > 0 && got[len(got)-1] == want[len(want)-1]
 		}, testutil.WaitShort, testutil.IntervalMedium)
 
-		require.Equal(t, want, got[:len(want)])
+		// The timeout is the final state, it must not be followed by an error.
+		// Backlogged states are reported with a backoff of a few seconds.
+		require.Never(t, func() bool {
+			got = client.GetLifecycleStates()
+			return got[len(got)-1] != want[len(want)-1]
+		}, 3*testutil.IntervalSlow, testutil.IntervalMedium)
+		require.Equal(t, want, got)
 	})
 
 	t.Run("StartError", func(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
-			StartupScript:        "false",
-			StartupScriptTimeout: 30 * time.Second,
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "false",
+				Timeout:    30 * time.Second,
+				RunOnStart: true,
+			}},
 		}, 0)
 
 		want := []codersdk.WorkspaceAgentLifecycle{
@@ -1331,8 +1735,11 @@ func TestAgent_Lifecycle(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
-			StartupScript:        "true",
-			StartupScriptTimeout: 30 * time.Second,
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "true",
+				Timeout:    30 * time.Second,
+				RunOnStart: true,
+			}},
 		}, 0)
 
 		want := []codersdk.WorkspaceAgentLifecycle{
@@ -1353,8 +1760,10 @@ func TestAgent_Lifecycle(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, closer := setupAgent(t, agentsdk.Manifest{
-			ShutdownScript:       "sleep 3",
-			StartupScriptTimeout: 30 * time.Second,
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:    "sleep 3",
+				RunOnStop: true,
+			}},
 		}, 0)
 
 		assert.Eventually(t, func() bool {
@@ -1391,8 +1800,11 @@ func TestAgent_Lifecycle(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, closer := setupAgent(t, agentsdk.Manifest{
-			ShutdownScript:        "sleep 3",
-			ShutdownScriptTimeout: time.Nanosecond,
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:    "sleep 3",
+				Timeout:   time.Nanosecond,
+				RunOnStop: true,
+			}},
 		}, 0)
 
 		assert.Eventually(t, func() bool {
@@ -1430,8 +1842,11 @@ func TestAgent_Lifecycle(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, closer := setupAgent(t, agentsdk.Manifest{
-			ShutdownScript:        "false",
-			ShutdownScriptTimeout: 30 * time.Second,
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:    "false",
+				Timeout:   30 * time.Second,
+				RunOnStop: true,
+			}},
 		}, 0)
 
 		assert.Eventually(t, func() bool {
@@ -1475,9 +1890,16 @@ func TestAgent_Lifecycle(t *testing.T) {
 			logger,
 			uuid.New(),
 			agentsdk.Manifest{
-				DERPMap:        derpMap,
-				StartupScript:  "echo 1",
-				ShutdownScript: "echo " + expected,
+				DERPMap: derpMap,
+				Scripts: []codersdk.WorkspaceAgentScript{{
+					LogPath:    "coder-startup-script.log",
+					Script:     "echo 1",
+					RunOnStart: true,
+				}, {
+					LogPath:   "coder-shutdown-script.log",
+					Script:    "echo " + expected,
+					RunOnStop: true,
+				}},
 			},
 			make(chan *agentsdk.Stats, 50),
 			tailnet.NewCoordinator(logger),
@@ -1528,9 +1950,12 @@ func TestAgent_Startup(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
-			StartupScript:        "true",
-			StartupScriptTimeout: 30 * time.Second,
-			Directory:            "",
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "true",
+				Timeout:    30 * time.Second,
+				RunOnStart: true,
+			}},
+			Directory: "",
 		}, 0)
 		assert.Eventually(t, func() bool {
 			return client.GetStartup().Version != ""
@@ -1542,9 +1967,12 @@ func TestAgent_Startup(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
-			StartupScript:        "true",
-			StartupScriptTimeout: 30 * time.Second,
-			Directory:            "~",
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "true",
+				Timeout:    30 * time.Second,
+				RunOnStart: true,
+			}},
+			Directory: "~",
 		}, 0)
 		assert.Eventually(t, func() bool {
 			return client.GetStartup().Version != ""
@@ -1558,9 +1986,12 @@ func TestAgent_Startup(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
-			StartupScript:        "true",
-			StartupScriptTimeout: 30 * time.Second,
-			Directory:            "coder/coder",
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "true",
+				Timeout:    30 * time.Second,
+				RunOnStart: true,
+			}},
+			Directory: "coder/coder",
 		}, 0)
 		assert.Eventually(t, func() bool {
 			return client.GetStartup().Version != ""
@@ -1574,9 +2005,12 @@ func TestAgent_Startup(t *testing.T) {
 		t.Parallel()
 
 		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
-			StartupScript:        "true",
-			StartupScriptTimeout: 30 * time.Second,
-			Directory:            "$HOME",
+			Scripts: []codersdk.WorkspaceAgentScript{{
+				Script:     "true",
+				Timeout:    30 * time.Second,
+				RunOnStart: true,
+			}},
+			Directory: "$HOME",
 		}, 0)
 		assert.Eventually(t, func() bool {
 			return client.GetStartup().Version != ""
@@ -1708,6 +2142,294 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	}
 }
 
+func TestAgent_ReconnectingPTYSharing(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+	// Attaching to a session that does not exist must not spawn one.
+	missing, err := conn.ReconnectingPTY(ctx, uuid.New(), 80, 80, "bash", codersdk.AgentReconnectingPTYInitAttachOnly())
+	require.NoError(t, err)
+	defer missing.Close()
+	require.ErrorIs(t, testutil.ReadUntil(ctx, t, missing, nil), io.EOF)
+
+	id := uuid.New()
+	writer, err := conn.ReconnectingPTY(ctx, id, 80, 80, "bash")
+	require.NoError(t, err)
+	defer writer.Close()
+
+	reader, err := conn.ReconnectingPTY(ctx, id, 80, 80, "", codersdk.AgentReconnectingPTYInitReadOnly(), codersdk.AgentReconnectingPTYInitAttachOnly())
+	require.NoError(t, err)
+	defer reader.Close()
+
+	require.Eventually(t, func() bool {
+		sessions, err := conn.ReconnectingPTYSessions(ctx)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return len(sessions.Sessions) == 1 &&
+			sessions.Sessions[0].ID == id &&
+			sessions.Sessions[0].Command == "bash" &&
+			sessions.Sessions[0].Connections == 2
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// Brief pause to reduce the likelihood that we send keystrokes while
+	// the shell is simultaneously sending a prompt.
+	time.Sleep(100 * time.Millisecond)
+
+	// Input from the read-only connection is discarded.
+	data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+		Data: "echo readonly\r\n",
+	})
+	require.NoError(t, err)
+	_, err = reader.Write(data)
+	require.NoError(t, err)
+
+	data, err = json.Marshal(codersdk.ReconnectingPTYRequest{
+		Data: "echo test\r\n",
+	})
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+
+	sawReadOnlyInput := false
+	matchEchoOutput := func(line string) bool {
+		if strings.Contains(line, "readonly") {
+			sawReadOnlyInput = true
+		}
+		return strings.Contains(line, "test") && !strings.Contains(line, "echo")
+	}
+	require.NoError(t, testutil.ReadUntil(ctx, t, writer, matchEchoOutput), "find echo output")
+	require.False(t, sawReadOnlyInput, "read-only input was written to the pty")
+
+	// The read-only connection still sees the output.
+	require.NoError(t, testutil.ReadUntil(ctx, t, reader, matchEchoOutput), "find echo output")
+}
+
+func TestAgent_SessionRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	// recording waits for the final chunk of the only recording and
+	// returns the whole recording.
+	recording := func(ctx context.Context, t *testing.T, client *agenttest.Client) (agentsdk.PostSessionRecordingRequest, string) {
+		var data []byte
+		var req agentsdk.PostSessionRecordingRequest
+		require.Eventually(t, func() bool {
+			recordings := client.GetSessionRecordings()
+			if len(recordings) != 1 {
+				return false
+			}
+			req = recordings[0]
+			chunks := client.GetSessionRecordingChunks(req.ID)
+			if len(chunks) == 0 || !chunks[len(chunks)-1].Final {
+				return false
+			}
+			data = nil
+			for _, chunk := range chunks {
+				data = append(data, chunk.Data...)
+			}
+			return true
+		}, testutil.WaitLong, testutil.IntervalFast)
+		return req, string(data)
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			RecordSessions:     true,
+			RecordSessionInput: true,
+		}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		ptty := ptytest.New(t)
+		session.Stdout = ptty.Output()
+		session.Stderr = ptty.Output()
+		session.Stdin = ptty.Input()
+		err = session.Start("sh")
+		require.NoError(t, err)
+		_ = ptty.Peek(ctx, 1) // wait for the prompt
+		ptty.WriteLine("echo $((40 + 2))")
+		ptty.ExpectMatch("42")
+		ptty.WriteLine("exit")
+		err = session.Wait()
+		require.NoError(t, err)
+
+		req, data := recording(ctx, t, client)
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeSSH, req.Type)
+		require.Equal(t, "sh", req.Command)
+		require.True(t, req.IncludesInput)
+		require.Contains(t, data, `"width":80`)
+		require.Contains(t, data, `"i", "echo $((40 + 2))`)
+		// The output may be split into events anywhere.
+		require.Contains(t, data, `42\r\n`)
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			RecordSessions: true,
+		}, 0)
+		id := uuid.New()
+		netConn, err := conn.ReconnectingPTY(ctx, id, 24, 80, "bash")
+		require.NoError(t, err)
+
+		// Brief pause to reduce the likelihood that we send keystrokes while
+		// the shell is simultaneously sending a prompt.
+		time.Sleep(100 * time.Millisecond)
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo $((40 + 2))\r\n",
+		})
+		require.NoError(t, err)
+		_, err = netConn.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, testutil.ReadUntil(ctx, t, netConn, func(line string) bool {
+			return strings.Contains(line, "42")
+		}))
+		// The recording of a connection ends when it disconnects.
+		require.NoError(t, netConn.Close())
+
+		req, cast := recording(ctx, t, client)
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeReconnectingPTY, req.Type)
+		require.Equal(t, id, req.ReconnectID)
+		require.False(t, req.IncludesInput)
+		require.Contains(t, cast, "42")
+		require.NotContains(t, cast, `"i", `)
+	})
+}
+
+func TestAgent_ConnectionLog(t *testing.T) {
+	t.Parallel()
+
+	// disconnect waits for the report of the connection of the given type
+	// being closed.
+	disconnect := func(t *testing.T, client *agenttest.Client, connectionType codersdk.ConnectionType) agentsdk.PostConnectionRequest {
+		var req agentsdk.PostConnectionRequest
+		require.Eventually(t, func() bool {
+			for _, c := range client.GetConnections() {
+				if c.Type == connectionType && c.DisconnectTime != nil {
+					req = c
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitLong, testutil.IntervalFast)
+		// The connection was reported when it opened, too.
+		var opened bool
+		for _, c := range client.GetConnections() {
+			if c.ID == req.ID && c.DisconnectTime == nil {
+				opened = true
+			}
+		}
+		require.True(t, opened, "connection was not reported when it opened")
+		return req
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		output, err := session.Output("echo hello; exit 3")
+		var exitErr *ssh.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		require.Equal(t, 3, exitErr.ExitStatus())
+		require.Equal(t, "hello", strings.TrimSpace(string(output)))
+
+		req := disconnect(t, client, codersdk.ConnectionTypeSSH)
+		require.NotNil(t, req.ExitCode)
+		require.EqualValues(t, 3, *req.ExitCode)
+		require.GreaterOrEqual(t, req.BytesSent, int64(len(output)))
+		require.NotEmpty(t, req.IP)
+	})
+
+	t.Run("PortForward", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		local, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer local.Close()
+		go func() {
+			conn, err := local.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_, _ = io.Copy(conn, conn)
+		}()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		forwarded, err := sshClient.Dial("tcp", local.Addr().String())
+		require.NoError(t, err)
+		_, err = forwarded.Write([]byte("test"))
+		require.NoError(t, err)
+		b := make([]byte, 4)
+		_, err = io.ReadFull(forwarded, b)
+		require.NoError(t, err)
+		require.NoError(t, forwarded.Close())
+
+		req := disconnect(t, client, codersdk.ConnectionTypePortForwarding)
+		require.Equal(t, strconv.Itoa(local.Addr().(*net.TCPAddr).Port), req.SlugOrPort)
+		require.Nil(t, req.ExitCode)
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("ConPTY appears to be inconsistent on Windows.")
+		}
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		netConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 24, 80, "bash")
+		require.NoError(t, err)
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo $((40 + 2))\r\n",
+		})
+		require.NoError(t, err)
+		_, err = netConn.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, testutil.ReadUntil(ctx, t, netConn, func(line string) bool {
+			return strings.Contains(line, "42")
+		}))
+		require.NoError(t, netConn.Close())
+
+		req := disconnect(t, client, codersdk.ConnectionTypeReconnectingPTY)
+		require.Positive(t, req.BytesSent)
+		require.Positive(t, req.BytesReceived)
+	})
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,203 @@
+// Package agentconnlog reports connections to the workspace agent to coderd,
+// which keeps them in the connection log.
+package agentconnlog
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/retry"
+)
+
+const (
+	// postTimeout bounds how long a report is retried for.
+	postTimeout = 30 * time.Second
+	// closeTimeout bounds how long Close waits for pending reports.
+	closeTimeout = 5 * time.Second
+)
+
+// Client reports connections.
+type Client interface {
+	PostConnection(ctx context.Context, req agentsdk.PostConnectionRequest) error
+}
+
+// Reporter reports connections in the background. A nil Reporter reports
+// nothing, so callers don't have to check whether logging is enabled.
+type Reporter struct {
+	logger slog.Logger
+	client Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a Reporter. Close must be called to wait for pending reports.
+func New(logger slog.Logger, client Client) *Reporter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Reporter{
+		logger: logger,
+		client: client,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Connect reports that a connection was opened from remoteAddr. slugOrPort
+// is the port that is forwarded to, if any. Disconnect must be called on the
+// returned Connection when it closes.
+func (r *Reporter) Connect(connectionType codersdk.ConnectionType, remoteAddr net.Addr, slugOrPort string) *Connection {
+	if r == nil {
+		return nil
+	}
+	c := &Connection{
+		r: r,
+		req: agentsdk.PostConnectionRequest{
+			ID:          uuid.New(),
+			Type:        connectionType,
+			ConnectTime: time.Now(),
+			IP:          addrIP(remoteAddr),
+			SlugOrPort:  slugOrPort,
+		},
+	}
+	r.post(c.req)
+	return c
+}
+
+// Close waits for pending reports to be sent. Reports that are still being
+// retried after a few seconds are dropped.
+func (r *Reporter) Close() error {
+	if r == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(closeTimeout):
+	}
+	r.cancel()
+	<-done
+	return nil
+}
+
+func (r *Reporter) post(req agentsdk.PostConnectionRequest) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ctx, cancel := context.WithTimeout(r.ctx, postTimeout)
+		defer cancel()
+		// Reports are idempotent, so they can be retried until they
+		// succeed.
+		var err error
+		for retrier := retry.New(100*time.Millisecond, 5*time.Second); retrier.Wait(ctx); {
+			err = r.client.PostConnection(ctx, req)
+			if err == nil {
+				return
+			}
+		}
+		r.logger.Warn(context.Background(), "failed to report connection",
+			slog.F("connection_id", req.ID),
+			slog.F("type", req.Type),
+			slog.Error(err))
+	}()
+}
+
+// Connection is an open connection. Its methods may be called on a nil
+// Connection.
+type Connection struct {
+	r   *Reporter
+	req agentsdk.PostConnectionRequest
+
+	sent     atomic.Int64
+	received atomic.Int64
+	once     sync.Once
+}
+
+// AddSent counts bytes sent from the workspace to the client.
+func (c *Connection) AddSent(n int) {
+	if c == nil {
+		return
+	}
+	c.sent.Add(int64(n))
+}
+
+// AddReceived counts bytes received from the client.
+func (c *Connection) AddReceived(n int) {
+	if c == nil {
+		return
+	}
+	c.received.Add(int64(n))
+}
+
+// Disconnect reports that the connection closed. exitCode is nil unless a
+// process ran for the connection. Only the first call has an effect.
+func (c *Connection) Disconnect(exitCode *int32, reason string) {
+	if c == nil {
+		return
+	}
+	c.once.Do(func() {
+		now := time.Now()
+		req := c.req
+		req.DisconnectTime = &now
+		req.ExitCode = exitCode
+		req.BytesSent = c.sent.Load()
+		req.BytesReceived = c.received.Load()
+		req.DisconnectReason = reason
+		c.r.post(req)
+	})
+}
+
+// Conn wraps conn to count the bytes sent over it.
+func (c *Connection) Conn(conn net.Conn) net.Conn {
+	if c == nil {
+		return conn
+	}
+	return &countingConn{Conn: conn, c: c}
+}
+
+type countingConn struct {
+	net.Conn
+	c *Connection
+}
+
+func (cc *countingConn) Read(p []byte) (int, error) {
+	n, err := cc.Conn.Read(p)
+	cc.c.AddReceived(n)
+	return n, err
+}
+
+func (cc *countingConn) Write(p []byte) (int, error) {
+	n, err := cc.Conn.Write(p)
+	cc.c.AddSent(n)
+	return n, err
+}
+
+// addrIP returns the IP of addr without the port.
+func addrIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP.String()
+	case *net.UDPAddr:
+		return a.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
@@ -0,0 +1,84 @@
+package agentconnlog_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentconnlog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestReporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := &fakeClient{failures: 2}
+		reporter := agentconnlog.New(slogtest.Make(t, nil), client)
+
+		conn := reporter.Connect(codersdk.ConnectionTypePortForwarding, &net.TCPAddr{IP: net.ParseIP("fd7a:115c:a1e0::1"), Port: 1234}, "8080")
+		conn.AddSent(10)
+		conn.AddReceived(5)
+		exitCode := int32(1)
+		conn.Disconnect(&exitCode, "")
+		// Only the first disconnect is reported.
+		conn.Disconnect(nil, "again")
+		require.NoError(t, reporter.Close())
+
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		require.Len(t, client.reports, 2)
+		// The reports are sent concurrently, so they may arrive in any order.
+		opened, closed := client.reports[0], client.reports[1]
+		if opened.DisconnectTime != nil {
+			opened, closed = closed, opened
+		}
+		require.Equal(t, opened.ID, closed.ID)
+		require.Equal(t, codersdk.ConnectionTypePortForwarding, opened.Type)
+		require.Equal(t, "fd7a:115c:a1e0::1", opened.IP)
+		require.Equal(t, "8080", opened.SlugOrPort)
+		require.Nil(t, opened.ExitCode)
+		require.NotNil(t, closed.DisconnectTime)
+		require.Equal(t, &exitCode, closed.ExitCode)
+		require.EqualValues(t, 10, closed.BytesSent)
+		require.EqualValues(t, 5, closed.BytesReceived)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		t.Parallel()
+		var reporter *agentconnlog.Reporter
+		conn := reporter.Connect(codersdk.ConnectionTypeSSH, nil, "")
+		conn.AddSent(1)
+		conn.Disconnect(nil, "")
+		require.NoError(t, reporter.Close())
+	})
+}
+
+type fakeClient struct {
+	mu       sync.Mutex
+	failures int
+	reports  []agentsdk.PostConnectionRequest
+}
+
+func (c *fakeClient) PostConnection(_ context.Context, req agentsdk.PostConnectionRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return xerrors.New("unavailable")
+	}
+	c.reports = append(c.reports, req)
+	return nil
+}
@@ -0,0 +1,188 @@
+// Package agentlogfiles tails files in the workspace into the workspace agent
+// logs.
+package agentlogfiles
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// maxLineLength is the length of the output column of the workspace agent
+// logs. Longer lines are truncated.
+const maxLineLength = 1024
+
+// Options are a set of options for the tailer.
+type Options struct {
+	Logger     slog.Logger
+	Filesystem afero.Fs
+	PatchLogs  func(ctx context.Context, req agentsdk.PatchLogs) error
+	// PollInterval is how often files are checked for new lines. Defaults to
+	// one second.
+	PollInterval time.Duration
+}
+
+// New creates a tailer. Files are tailed once Start is called.
+func New(opts Options) *Tailer {
+	if opts.PollInterval == 0 {
+		opts.PollInterval = time.Second
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Tailer{
+		Options: opts,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Tailer sends lines appended to files in the workspace to the workspace
+// agent logs, each file with its own log source.
+type Tailer struct {
+	Options
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	closeMutex sync.Mutex
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+// Start tails the files until the tailer is closed. Only lines appended after
+// Start is called are sent, so restarting the agent doesn't send lines twice.
+// Files that don't exist yet are tailed once they are created.
+func (t *Tailer) Start(logFiles []codersdk.WorkspaceAgentLogFile) {
+	t.closeMutex.Lock()
+	defer t.closeMutex.Unlock()
+	if t.closed {
+		return
+	}
+	for _, logFile := range logFiles {
+		logFile := logFile
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.tail(logFile)
+		}()
+	}
+}
+
+// Close stops tailing and flushes the lines read so far.
+func (t *Tailer) Close() error {
+	t.closeMutex.Lock()
+	defer t.closeMutex.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	t.cancel()
+	t.wg.Wait()
+	return nil
+}
+
+func (t *Tailer) tail(logFile codersdk.WorkspaceAgentLogFile) {
+	logger := t.Logger.With(slog.F("log_file", logFile.Path), slog.F("log_source_id", logFile.LogSourceID))
+	path, err := expandPath(logFile.Path)
+	if err != nil {
+		logger.Warn(t.ctx, "unable to expand log file path", slog.Error(err))
+		return
+	}
+
+	send, flushAndClose := agentsdk.LogsSender(logFile.LogSourceID, t.PatchLogs, logger)
+	defer func() {
+		// The tailer context is canceled at this point, give the remaining
+		// lines a moment to be sent.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := flushAndClose(ctx); err != nil {
+			logger.Warn(ctx, "flush log file lines failed", slog.Error(err))
+		}
+	}()
+	writer := agentsdk.LogsWriter(t.ctx, func(ctx context.Context, logs ...agentsdk.Log) error {
+		for i, log := range logs {
+			if len(log.Output) > maxLineLength {
+				log.Output = strings.ToValidUTF8(log.Output[:maxLineLength], "")
+			}
+			logs[i] = log
+		}
+		return send(ctx, logs...)
+	}, codersdk.LogLevelInfo)
+	defer writer.Close()
+
+	// Skip what the file already contains.
+	var offset int64
+	if info, err := t.Filesystem.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	ticker := time.NewTicker(t.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		offset, err = t.read(path, offset, writer)
+		if err != nil && !xerrors.Is(err, os.ErrNotExist) {
+			logger.Warn(t.ctx, "read log file", slog.Error(err))
+		}
+	}
+}
+
+// read writes what was appended to the file since offset and returns the new
+// offset. A file smaller than offset was truncated or rotated, so it is read
+// from the start.
+func (t *Tailer) read(path string, offset int64, w io.Writer) (int64, error) {
+	file, err := t.Filesystem.Open(path)
+	if err != nil {
+		return offset, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return offset, xerrors.Errorf("stat: %w", err)
+	}
+	size := info.Size()
+	if size < offset {
+		offset = 0
+	}
+	if size == offset {
+		return offset, nil
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return offset, xerrors.Errorf("seek: %w", err)
+	}
+	n, err := io.Copy(w, io.LimitReader(file, size-offset))
+	if err != nil {
+		return offset + n, xerrors.Errorf("copy: %w", err)
+	}
+	return offset + n, nil
+}
+
+// expandPath resolves paths relative to the home directory of the user.
+func expandPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", xerrors.Errorf("get home dir: %w", err)
+	}
+	if path == "~" {
+		return home, nil
+	}
+	path = strings.TrimPrefix(path, "~/")
+	return filepath.Join(home, path), nil
+}
@@ -0,0 +1,135 @@
+package agentlogfiles_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentlogfiles"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestTailer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AppendedLines", func(t *testing.T) {
+		t.Parallel()
+		fs, tailer, logs := setup(t)
+		sourceID := uuid.New()
+		require.NoError(t, afero.WriteFile(fs, "/var/log/app.log", []byte("before the agent started\n"), 0o600))
+		tailer.Start([]codersdk.WorkspaceAgentLogFile{{
+			ID:          uuid.New(),
+			LogSourceID: sourceID,
+			Path:        "/var/log/app.log",
+		}})
+
+		// Lines already in the file are skipped.
+		writeFile(t, fs, "/var/log/app.log", "first\nsecond\npartial", os.O_APPEND)
+		got := receive(t, logs, 2)
+		require.Equal(t, sourceID, got[0].LogSourceID)
+		require.Equal(t, "first", got[0].Logs[0].Output)
+		require.Equal(t, codersdk.LogLevelInfo, got[0].Logs[0].Level)
+		require.Equal(t, "second", got[1].Logs[0].Output)
+
+		// Partial lines are sent once they are complete.
+		writeFile(t, fs, "/var/log/app.log", " line\n", os.O_APPEND)
+		got = receive(t, logs, 1)
+		require.Equal(t, "partial line", got[0].Logs[0].Output)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		t.Parallel()
+		fs, tailer, logs := setup(t)
+		require.NoError(t, afero.WriteFile(fs, "/var/log/app.log", []byte("a long line from before the rotation\n"), 0o600))
+		tailer.Start([]codersdk.WorkspaceAgentLogFile{{
+			ID:          uuid.New(),
+			LogSourceID: uuid.New(),
+			Path:        "/var/log/app.log",
+		}})
+
+		writeFile(t, fs, "/var/log/app.log", "rotated\n", os.O_TRUNC)
+		got := receive(t, logs, 1)
+		require.Equal(t, "rotated", got[0].Logs[0].Output)
+	})
+
+	t.Run("CreatedLater", func(t *testing.T) {
+		t.Parallel()
+		fs, tailer, logs := setup(t)
+		tailer.Start([]codersdk.WorkspaceAgentLogFile{{
+			ID:          uuid.New(),
+			LogSourceID: uuid.New(),
+			Path:        "/var/log/app.log",
+		}})
+
+		writeFile(t, fs, "/var/log/app.log", strings.Repeat("a", 2000)+"\n", os.O_APPEND)
+		got := receive(t, logs, 1)
+		// Long lines are truncated to fit the logs.
+		require.Len(t, got[0].Logs[0].Output, 1024)
+	})
+}
+
+func setup(t *testing.T) (afero.Fs, *agentlogfiles.Tailer, <-chan agentsdk.PatchLogs) {
+	t.Helper()
+	fs := afero.NewMemMapFs()
+	logs := make(chan agentsdk.PatchLogs, 10)
+	tailer := agentlogfiles.New(agentlogfiles.Options{
+		Logger:     slogtest.Make(t, nil),
+		Filesystem: fs,
+		PatchLogs: func(ctx context.Context, req agentsdk.PatchLogs) error {
+			for _, log := range req.Logs {
+				// Send lines one by one, they may be batched.
+				select {
+				case logs <- agentsdk.PatchLogs{LogSourceID: req.LogSourceID, Logs: []agentsdk.Log{log}}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		},
+		PollInterval: testutil.IntervalFast,
+	})
+	t.Cleanup(func() {
+		_ = tailer.Close()
+	})
+	return fs, tailer, logs
+}
+
+// writeFile writes to the file once the tailer had time to stat it.
+func writeFile(t *testing.T, fs afero.Fs, path, data string, flag int) {
+	t.Helper()
+	time.Sleep(testutil.IntervalMedium)
+	file, err := fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|flag, 0o600)
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.WriteString(data)
+	require.NoError(t, err)
+}
+
+func receive(t *testing.T, logs <-chan agentsdk.PatchLogs, n int) []agentsdk.PatchLogs {
+	t.Helper()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	got := make([]agentsdk.PatchLogs, 0, n)
+	for len(got) < n {
+		select {
+		case log := <-logs:
+			got = append(got, log)
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for logs, got %d of %d", len(got), n)
+		}
+	}
+	return got
+}
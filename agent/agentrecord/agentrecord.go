@@ -0,0 +1,336 @@
+// Package agentrecord records interactive sessions in asciicast v2 format
+// and uploads them to coderd in chunks.
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/ for the format.
+package agentrecord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+const (
+	defaultFlushInterval = 5 * time.Second
+	defaultMaxChunkSize  = 64 << 10
+	// maxPendingSize bounds the memory used by chunks that could not be
+	// uploaded. The oldest chunks are dropped first.
+	maxPendingSize = 8 << 20
+	// closeTimeout bounds how long Close tries to upload the rest of the
+	// recording.
+	closeTimeout = 10 * time.Second
+)
+
+// Client uploads recordings.
+type Client interface {
+	PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error
+	PostSessionRecordingChunk(ctx context.Context, id uuid.UUID, req agentsdk.PostSessionRecordingChunkRequest) error
+}
+
+// Options describe the recorded session.
+type Options struct {
+	Type codersdk.WorkspaceSessionRecordingType
+	// ReconnectID is set for reconnecting PTY sessions.
+	ReconnectID uuid.UUID
+	Command     string
+	Term        string
+	Width       uint16
+	Height      uint16
+	// RecordInput records what the user types in addition to the output.
+	RecordInput bool
+
+	// FlushInterval and MaxChunkSize control how often chunks are uploaded.
+	// They default to 5 seconds and 64 KiB.
+	FlushInterval time.Duration
+	MaxChunkSize  int
+}
+
+type chunk struct {
+	index int32
+	data  []byte
+	final bool
+}
+
+// Recorder records a single session. Writes never fail so that a broken
+// connection to coderd can't break the session being recorded.
+type Recorder struct {
+	id     uuid.UUID
+	opts   Options
+	client Client
+	logger slog.Logger
+	start  time.Time
+
+	mu          sync.Mutex // Protects following.
+	buf         bytes.Buffer
+	partial     map[string][]byte
+	nextIndex   int32
+	closed      bool
+	flushSignal chan struct{}
+
+	// Only accessed by the flushing goroutine, or by Close once it exited.
+	created      bool
+	pending      []chunk
+	pendingBytes int
+
+	done     chan struct{}
+	loopDone chan struct{}
+}
+
+// New starts recording a session. Close must be called when the session
+// ends.
+func New(logger slog.Logger, client Client, opts Options) *Recorder {
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+	if opts.MaxChunkSize == 0 {
+		opts.MaxChunkSize = defaultMaxChunkSize
+	}
+	r := &Recorder{
+		id:          uuid.New(),
+		opts:        opts,
+		client:      client,
+		logger:      logger,
+		start:       time.Now(),
+		partial:     map[string][]byte{},
+		flushSignal: make(chan struct{}, 1),
+		done:        make(chan struct{}),
+		loopDone:    make(chan struct{}),
+	}
+	r.logger = logger.With(slog.F("recording_id", r.id))
+
+	header := map[string]any{
+		"version":   2,
+		"width":     opts.Width,
+		"height":    opts.Height,
+		"timestamp": r.start.Unix(),
+	}
+	if opts.Command != "" {
+		header["command"] = opts.Command
+	}
+	if opts.Term != "" {
+		header["env"] = map[string]string{"TERM": opts.Term}
+	}
+	data, _ := json.Marshal(header)
+	r.buf.Write(data)
+	r.buf.WriteByte('\n')
+
+	go r.flushLoop()
+	return r
+}
+
+// ID returns the ID of the recording.
+func (r *Recorder) ID() uuid.UUID {
+	return r.id
+}
+
+// OutputWriter returns a writer that records output of the session.
+func (r *Recorder) OutputWriter() *StreamWriter {
+	return &StreamWriter{r: r, code: "o"}
+}
+
+// InputWriter returns a writer that records input of the session. Nothing
+// is recorded unless Options.RecordInput is set.
+func (r *Recorder) InputWriter() *StreamWriter {
+	return &StreamWriter{r: r, code: "i"}
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(width, height uint16) {
+	r.event("r", []byte(fmt.Sprintf("%dx%d", width, height)))
+}
+
+// StreamWriter records the output or input of a session.
+type StreamWriter struct {
+	r    *Recorder
+	code string
+}
+
+// Write always succeeds.
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	if w.code == "i" && !w.r.opts.RecordInput {
+		return len(p), nil
+	}
+	w.r.event(w.code, p)
+	return len(p), nil
+}
+
+func (r *Recorder) event(code string, p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+
+	// Multi-byte characters may be split between writes. Hold back the
+	// incomplete tail until the rest arrives so it isn't mangled.
+	data := append(r.partial[code], p...)
+	cut := incompleteUTF8Suffix(data)
+	r.partial[code] = append([]byte(nil), data[len(data)-cut:]...)
+	data = data[:len(data)-cut]
+	if len(data) == 0 {
+		return
+	}
+
+	r.writeEventLocked(code, data)
+	if r.buf.Len() >= r.opts.MaxChunkSize {
+		select {
+		case r.flushSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Recorder) writeEventLocked(code string, data []byte) {
+	elapsed := time.Since(r.start).Seconds()
+	// Invalid UTF-8 is replaced by json.Marshal.
+	str, _ := json.Marshal(string(data))
+	r.buf.WriteByte('[')
+	r.buf.WriteString(strconv.FormatFloat(elapsed, 'f', 6, 64))
+	r.buf.WriteString(`, "`)
+	r.buf.WriteString(code)
+	r.buf.WriteString(`", `)
+	r.buf.Write(str)
+	r.buf.WriteString("]\n")
+}
+
+// incompleteUTF8Suffix returns the length of an incomplete UTF-8 sequence at
+// the end of p.
+func incompleteUTF8Suffix(p []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(p); i++ {
+		c := p[len(p)-i]
+		if utf8.RuneStart(c) {
+			if !utf8.FullRune(p[len(p)-i:]) {
+				return i
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+func (r *Recorder) flushLoop() {
+	defer close(r.loopDone)
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.flushSignal:
+		}
+		r.cutChunk(false)
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		r.upload(ctx)
+		cancel()
+	}
+}
+
+// cutChunk moves buffered events into a pending chunk.
+func (r *Recorder) cutChunk(final bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buf.Len() == 0 && !final {
+		return
+	}
+	data := append([]byte(nil), r.buf.Bytes()...)
+	r.buf.Reset()
+	r.pending = append(r.pending, chunk{index: r.nextIndex, data: data, final: final})
+	r.pendingBytes += len(data)
+	r.nextIndex++
+}
+
+// upload uploads all pending chunks in order. Chunks that fail to upload are
+// retried by the next call.
+func (r *Recorder) upload(ctx context.Context) bool {
+	for r.pendingBytes > maxPendingSize && len(r.pending) > 1 {
+		r.logger.Warn(ctx, "dropping session recording chunk that could not be uploaded", slog.F("index", r.pending[0].index))
+		r.pendingBytes -= len(r.pending[0].data)
+		r.pending = r.pending[1:]
+	}
+
+	if !r.created {
+		err := r.client.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+			ID:            r.id,
+			Type:          r.opts.Type,
+			ReconnectID:   r.opts.ReconnectID,
+			Command:       r.opts.Command,
+			IncludesInput: r.opts.RecordInput,
+		})
+		if err != nil {
+			r.logger.Warn(ctx, "create session recording", slog.Error(err))
+			return false
+		}
+		r.created = true
+	}
+
+	for len(r.pending) > 0 {
+		c := r.pending[0]
+		err := r.client.PostSessionRecordingChunk(ctx, r.id, agentsdk.PostSessionRecordingChunkRequest{
+			Index: c.index,
+			Data:  c.data,
+			Final: c.final,
+		})
+		var sdkErr *codersdk.Error
+		if errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusConflict {
+			// The recording has ended, retrying won't help.
+			r.logger.Warn(ctx, "session recording has ended, dropping chunks", slog.F("pending_chunks", len(r.pending)))
+			r.pending = nil
+			r.pendingBytes = 0
+			return true
+		}
+		if err != nil {
+			r.logger.Warn(ctx, "upload session recording chunk", slog.F("index", c.index), slog.Error(err))
+			return false
+		}
+		r.pendingBytes -= len(c.data)
+		r.pending = r.pending[1:]
+	}
+	return true
+}
+
+// Close stops recording and uploads the rest of the recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	// Flush anything that was held back, even if it's incomplete.
+	for _, code := range []string{"o", "i"} {
+		if len(r.partial[code]) > 0 {
+			r.writeEventLocked(code, r.partial[code])
+		}
+	}
+	r.mu.Unlock()
+
+	close(r.done)
+	<-r.loopDone
+
+	r.cutChunk(true)
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	for !r.upload(ctx) {
+		select {
+		case <-ctx.Done():
+			r.logger.Warn(ctx, "gave up uploading session recording", slog.F("pending_chunks", len(r.pending)))
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+	return nil
+}
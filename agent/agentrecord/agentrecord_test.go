@@ -0,0 +1,180 @@
+package agentrecord_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentrecord"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := &fakeClient{}
+		recorder := agentrecord.New(slogtest.Make(t, nil).Leveled(slog.LevelDebug), client, agentrecord.Options{
+			Type:        codersdk.WorkspaceSessionRecordingTypeSSH,
+			Command:     "bash",
+			Term:        "xterm",
+			Width:       80,
+			Height:      24,
+			RecordInput: true,
+		})
+
+		output := recorder.OutputWriter()
+		_, err := output.Write([]byte("hello \xc3"))
+		require.NoError(t, err)
+		// The rest of the é arrives in the next write.
+		_, err = output.Write([]byte("\xa9\r\n"))
+		require.NoError(t, err)
+		_, err = recorder.InputWriter().Write([]byte("ls\r"))
+		require.NoError(t, err)
+		recorder.Resize(100, 30)
+		require.NoError(t, recorder.Close())
+
+		require.Equal(t, []agentsdk.PostSessionRecordingRequest{{
+			ID:            recorder.ID(),
+			Type:          codersdk.WorkspaceSessionRecordingTypeSSH,
+			Command:       "bash",
+			IncludesInput: true,
+		}}, client.recordings)
+
+		header, events := parseCast(t, client.data(t))
+		require.EqualValues(t, 2, header["version"])
+		require.EqualValues(t, 80, header["width"])
+		require.EqualValues(t, 24, header["height"])
+		require.Equal(t, "bash", header["command"])
+		require.Equal(t, [][2]string{
+			{"o", "hello "},
+			{"o", "é\r\n"},
+			{"i", "ls\r"},
+			{"r", "100x30"},
+		}, events)
+	})
+
+	t.Run("NoInput", func(t *testing.T) {
+		t.Parallel()
+		client := &fakeClient{}
+		recorder := agentrecord.New(slogtest.Make(t, nil), client, agentrecord.Options{
+			Type: codersdk.WorkspaceSessionRecordingTypeReconnectingPTY,
+		})
+		_, err := recorder.InputWriter().Write([]byte("secret\r"))
+		require.NoError(t, err)
+		_, err = recorder.OutputWriter().Write([]byte("$ "))
+		require.NoError(t, err)
+		require.NoError(t, recorder.Close())
+
+		_, events := parseCast(t, client.data(t))
+		require.Equal(t, [][2]string{{"o", "$ "}}, events)
+	})
+
+	t.Run("Chunks", func(t *testing.T) {
+		t.Parallel()
+		// Fail the first uploads to make sure chunks are retried in order.
+		client := &fakeClient{failures: 3}
+		recorder := agentrecord.New(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), client, agentrecord.Options{
+			Type:          codersdk.WorkspaceSessionRecordingTypeSSH,
+			FlushInterval: testutil.IntervalFast,
+			MaxChunkSize:  64,
+		})
+		output := recorder.OutputWriter()
+		for i := 0; i < 20; i++ {
+			_, err := output.Write(bytes.Repeat([]byte("x"), 16))
+			require.NoError(t, err)
+			time.Sleep(time.Millisecond)
+		}
+		require.NoError(t, recorder.Close())
+
+		client.mu.Lock()
+		require.Greater(t, len(client.chunks), 1)
+		client.mu.Unlock()
+		_, events := parseCast(t, client.data(t))
+		require.Len(t, events, 20)
+	})
+}
+
+type fakeClient struct {
+	mu         sync.Mutex
+	failures   int
+	recordings []agentsdk.PostSessionRecordingRequest
+	chunks     []agentsdk.PostSessionRecordingChunkRequest
+}
+
+func (c *fakeClient) PostSessionRecording(_ context.Context, req agentsdk.PostSessionRecordingRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return xerrors.New("unavailable")
+	}
+	c.recordings = append(c.recordings, req)
+	return nil
+}
+
+func (c *fakeClient) PostSessionRecordingChunk(_ context.Context, id uuid.UUID, req agentsdk.PostSessionRecordingChunkRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.recordings) == 0 || c.recordings[0].ID != id {
+		return xerrors.Errorf("unknown recording %s", id)
+	}
+	if c.failures > 0 {
+		c.failures--
+		return xerrors.New("unavailable")
+	}
+	c.chunks = append(c.chunks, req)
+	return nil
+}
+
+// data checks the chunks are complete and returns the recording.
+func (c *fakeClient) data(t *testing.T) []byte {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var data []byte
+	for i, chunk := range c.chunks {
+		require.EqualValues(t, i, chunk.Index)
+		require.Equal(t, i == len(c.chunks)-1, chunk.Final)
+		data = append(data, chunk.Data...)
+	}
+	return data
+}
+
+func parseCast(t *testing.T, data []byte) (map[string]any, [][2]string) {
+	t.Helper()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	require.True(t, scanner.Scan())
+	var header map[string]any
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+
+	var events [][2]string
+	for scanner.Scan() {
+		var event []any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		require.Len(t, event, 3)
+		code, _ := event[1].(string)
+		data, _ := event[2].(string)
+		events = append(events, [2]string{code, data})
+	}
+	require.NoError(t, scanner.Err())
+	return header, events
+}
@@ -0,0 +1,304 @@
+package agentscripts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/afero"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+var (
+	// ErrTimeout is returned when a script times out.
+	ErrTimeout = xerrors.New("script timed out")
+
+	parser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.DowOptional)
+)
+
+// Options are a set of options for the runner.
+type Options struct {
+	LogDir           string
+	Logger           slog.Logger
+	SSHServer        *agentssh.Server
+	Filesystem       afero.Fs
+	PatchLogs        func(ctx context.Context, req agentsdk.PatchLogs) error
+	PostScriptStatus func(ctx context.Context, req agentsdk.PostScriptStatusRequest) error
+}
+
+// New creates a runner for the provided scripts.
+func New(opts Options) *Runner {
+	cronCtx, cronCtxCancel := context.WithCancel(context.Background())
+	return &Runner{
+		Options:       opts,
+		cronCtx:       cronCtx,
+		cronCtxCancel: cronCtxCancel,
+		cron:          cron.New(cron.WithParser(parser)),
+		closed:        make(chan struct{}),
+	}
+}
+
+// Runner executes the scripts of an agent on start, on stop and on a
+// cron schedule.
+type Runner struct {
+	Options
+
+	cronCtx       context.Context
+	cronCtxCancel context.CancelFunc
+	cmdCloseWait  sync.WaitGroup
+	closed        chan struct{}
+	closeMutex    sync.Mutex
+	cron          *cron.Cron
+	initialized   bool
+	scripts       []codersdk.WorkspaceAgentScript
+}
+
+// Init initializes the runner with the provided scripts.
+// It also schedules any scripts that have a schedule.
+// This function must be called before Execute.
+func (r *Runner) Init(scripts []codersdk.WorkspaceAgentScript) error {
+	if r.isClosed() {
+		return xerrors.New("runner is closed")
+	}
+	if r.initialized {
+		return xerrors.New("init: already initialized")
+	}
+	r.initialized = true
+	r.scripts = scripts
+	r.Logger.Info(r.cronCtx, "initializing agent scripts", slog.F("script_count", len(scripts)), slog.F("log_dir", r.LogDir))
+
+	for _, script := range scripts {
+		if script.Cron == "" {
+			continue
+		}
+		script := script
+		_, err := r.cron.AddFunc(script.Cron, func() {
+			err := r.trackRun(r.cronCtx, script)
+			if err != nil {
+				r.Logger.Warn(r.cronCtx, "run agent script on schedule", slog.Error(err))
+			}
+		})
+		if err != nil {
+			return xerrors.Errorf("add schedule: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartCron starts the cron scheduler.
+// This is done async to allow for the caller to execute scripts prior.
+func (r *Runner) StartCron() {
+	// cron.Start() and cron.Stop() does not guarantee that the cron
+	// scheduler has started or stopped, so we guard it with the closed
+	// channel.
+	r.closeMutex.Lock()
+	defer r.closeMutex.Unlock()
+	if r.isClosed() {
+		return
+	}
+	r.cron.Start()
+}
+
+// Execute runs all scripts matching the filter concurrently and waits
+// for them to complete. A failing script does not prevent the others
+// from running, the returned error wraps all failures.
+func (r *Runner) Execute(ctx context.Context, filter func(script codersdk.WorkspaceAgentScript) bool) error {
+	if filter == nil {
+		// Execute em' all!
+		filter = func(script codersdk.WorkspaceAgentScript) bool {
+			return true
+		}
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	var eg errgroup.Group
+	for _, script := range r.scripts {
+		if !filter(script) {
+			continue
+		}
+		script := script
+		eg.Go(func() error {
+			err := r.trackRun(ctx, script)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, xerrors.Errorf("run agent script %q: %w", script.LogSourceID, err))
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return errors.Join(errs...)
+}
+
+// trackRun wraps "run" with tracking so that Close waits for the command
+// to exit.
+func (r *Runner) trackRun(ctx context.Context, script codersdk.WorkspaceAgentScript) error {
+	r.closeMutex.Lock()
+	if r.isClosed() {
+		r.closeMutex.Unlock()
+		return xerrors.New("runner is closed")
+	}
+	r.cmdCloseWait.Add(1)
+	r.closeMutex.Unlock()
+	defer r.cmdCloseWait.Done()
+	return r.run(ctx, script)
+}
+
+// run executes the provided script with the timeout.
+// If the timeout is exceeded, the process is sent an interrupt signal.
+// If the process does not exit after a few seconds, it is forcefully killed.
+// This function immediately returns after a timeout, and does not wait for the process to exit.
+func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript) error {
+	logPath := script.LogPath
+	if logPath == "" {
+		logPath = fmt.Sprintf("coder-script-%s.log", script.LogSourceID)
+	}
+	if !filepath.IsAbs(logPath) {
+		logPath = filepath.Join(r.LogDir, logPath)
+	}
+	logger := r.Logger.With(slog.F("log_path", logPath), slog.F("log_source_id", script.LogSourceID))
+
+	var cmdCtx context.Context
+	var cancel context.CancelFunc
+	if script.Timeout > 0 {
+		cmdCtx, cancel = context.WithTimeout(ctx, script.Timeout)
+	} else {
+		cmdCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	cmdPty, err := r.SSHServer.CreateCommand(cmdCtx, script.Script, nil)
+	if err != nil {
+		return xerrors.Errorf("%s script: create command: %w", logPath, err)
+	}
+	cmd := cmdPty.AsExec()
+	cmd.SysProcAttr = cmdSysProcAttr()
+	cmd.WaitDelay = 10 * time.Second
+	cmd.Cancel = cmdCancel(cmd)
+
+	fileWriter, err := r.Filesystem.OpenFile(logPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return xerrors.Errorf("open %s script log file: %w", logPath, err)
+	}
+	defer func() {
+		err := fileWriter.Close()
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("close %s script log file", logPath), slog.Error(err))
+		}
+	}()
+
+	send, flushAndClose := agentsdk.LogsSender(script.LogSourceID, r.PatchLogs, logger)
+	// If ctx is canceled here (or in a writer below), we may be
+	// discarding logs, but that's okay because we're shutting down
+	// anyway. We could consider creating a new context here if we
+	// want better control over flush during shutdown.
+	defer func() {
+		if err := flushAndClose(ctx); err != nil {
+			logger.Warn(ctx, "flush startup logs failed", slog.Error(err))
+		}
+	}()
+
+	infoW := agentsdk.LogsWriter(ctx, send, codersdk.LogLevelInfo)
+	defer infoW.Close()
+	errW := agentsdk.LogsWriter(ctx, send, codersdk.LogLevelError)
+	defer errW.Close()
+	cmd.Stdout = io.MultiWriter(fileWriter, infoW)
+	cmd.Stderr = io.MultiWriter(fileWriter, errW)
+
+	start := time.Now()
+	r.postStatus(ctx, logger, agentsdk.PostScriptStatusRequest{
+		ScriptID:  script.ID,
+		Status:    codersdk.WorkspaceAgentScriptStatusRunning,
+		StartedAt: start,
+	})
+
+	logger.Info(ctx, "running agent script", slog.F("script", script.Script))
+	err = cmd.Run()
+	end := time.Now()
+	execTime := end.Sub(start)
+
+	status := codersdk.WorkspaceAgentScriptStatusOK
+	exitCode := 0
+	if err != nil {
+		status = codersdk.WorkspaceAgentScriptStatusExitFailure
+		exitCode = 255 // Unknown status.
+		var exitError *exec.ExitError
+		if xerrors.As(err, &exitError) {
+			exitCode = exitError.ExitCode()
+		}
+		// cmd.Run does not return a context error, it returns "signal: killed".
+		switch {
+		case errors.Is(cmdCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil:
+			status = codersdk.WorkspaceAgentScriptStatusTimedOut
+			err = ErrTimeout
+		case ctx.Err() != nil:
+			err = ctx.Err()
+		}
+		logger.Warn(ctx, "agent script failed", slog.F("execution_time", execTime), slog.F("exit_code", exitCode), slog.Error(err))
+	} else {
+		logger.Info(ctx, "agent script completed", slog.F("execution_time", execTime), slog.F("exit_code", exitCode))
+	}
+
+	r.postStatus(ctx, logger, agentsdk.PostScriptStatusRequest{
+		ScriptID:  script.ID,
+		Status:    status,
+		ExitCode:  int32(exitCode),
+		StartedAt: start,
+		EndedAt:   &end,
+	})
+	return err
+}
+
+// postStatus reports the status of a script. Failures are only logged
+// since the script itself has already run.
+func (r *Runner) postStatus(ctx context.Context, logger slog.Logger, req agentsdk.PostScriptStatusRequest) {
+	if r.PostScriptStatus == nil || ctx.Err() != nil {
+		return
+	}
+	err := r.PostScriptStatus(ctx, req)
+	if err != nil {
+		logger.Warn(ctx, "post agent script status", slog.F("status", req.Status), slog.Error(err))
+	}
+}
+
+func (r *Runner) Close() error {
+	r.closeMutex.Lock()
+	if r.isClosed() {
+		r.closeMutex.Unlock()
+		return nil
+	}
+	close(r.closed)
+	r.cronCtxCancel()
+	// Scheduled runs take closeMutex in trackRun, so it must be
+	// released before waiting for them to stop.
+	r.closeMutex.Unlock()
+	<-r.cron.Stop().Done()
+	r.cmdCloseWait.Wait()
+	return nil
+}
+
+func (r *Runner) isClosed() bool {
+	select {
+	case <-r.closed:
+		return true
+	default:
+		return false
+	}
+}
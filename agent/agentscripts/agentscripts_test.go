@@ -0,0 +1,179 @@
+package agentscripts_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentscripts"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestExecuteBasic(t *testing.T) {
+	t.Parallel()
+	logs := make(chan agentsdk.PatchLogs, 1)
+	runner, statuses := setup(t, func(ctx context.Context, req agentsdk.PatchLogs) error {
+		logs <- req
+		return nil
+	})
+	defer runner.Close()
+	sourceID := uuid.New()
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		ID:          uuid.New(),
+		LogSourceID: sourceID,
+		Script:      "echo hello",
+		RunOnStart:  true,
+	}})
+	require.NoError(t, err)
+	require.NoError(t, runner.Execute(context.Background(), func(script codersdk.WorkspaceAgentScript) bool {
+		return script.RunOnStart
+	}))
+	log := <-logs
+	require.Equal(t, sourceID, log.LogSourceID)
+	require.Equal(t, "hello", log.Logs[0].Output)
+
+	got := statuses()
+	require.Len(t, got, 2)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusRunning, got[0].Status)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusOK, got[1].Status)
+	require.NotNil(t, got[1].EndedAt)
+}
+
+func TestExecuteFailures(t *testing.T) {
+	t.Parallel()
+	runner, statuses := setup(t, nil)
+	defer runner.Close()
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		ID:         uuid.New(),
+		Script:     "exit 3",
+		RunOnStart: true,
+	}, {
+		ID:         uuid.New(),
+		Script:     "true",
+		RunOnStart: true,
+	}, {
+		ID:        uuid.New(),
+		Script:    "exit 1",
+		RunOnStop: true,
+	}})
+	require.NoError(t, err)
+	err = runner.Execute(context.Background(), func(script codersdk.WorkspaceAgentScript) bool {
+		return script.RunOnStart
+	})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, agentscripts.ErrTimeout)
+
+	var exitCodes []int32
+	for _, status := range statuses() {
+		if status.Status.Finished() {
+			exitCodes = append(exitCodes, status.ExitCode)
+		}
+	}
+	require.ElementsMatch(t, []int32{0, 3}, exitCodes)
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+	runner, statuses := setup(t, nil)
+	defer runner.Close()
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		ID:         uuid.New(),
+		Script:     "sleep infinity",
+		Timeout:    time.Millisecond,
+		RunOnStart: true,
+	}})
+	require.NoError(t, err)
+	require.ErrorIs(t, runner.Execute(context.Background(), nil), agentscripts.ErrTimeout)
+
+	got := statuses()
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusTimedOut, got[len(got)-1].Status)
+}
+
+func TestCronClose(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("script uses a POSIX shell")
+	}
+	runner, _ := setup(t, nil)
+	dir := t.TempDir()
+	started := filepath.Join(dir, "started")
+	stopped := filepath.Join(dir, "stopped")
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		ID:   uuid.New(),
+		Cron: "* * * * * *",
+		Script: fmt.Sprintf("trap 'sleep 1; touch %s; exit 0' HUP; touch %s; while true; do sleep 0.1; done",
+			stopped, started),
+	}})
+	require.NoError(t, err)
+	runner.StartCron()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(started)
+		return err == nil
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// Close must wait for the scheduled run to exit.
+	require.NoError(t, runner.Close())
+	require.FileExists(t, stopped)
+}
+
+func setup(t *testing.T, patchLogs func(ctx context.Context, req agentsdk.PatchLogs) error) (*agentscripts.Runner, func() []agentsdk.PostScriptStatusRequest) {
+	t.Helper()
+	if patchLogs == nil {
+		// noop
+		patchLogs = func(ctx context.Context, req agentsdk.PatchLogs) error {
+			return nil
+		}
+	}
+	fs := afero.NewMemMapFs()
+	logger := slogtest.Make(t, nil)
+	s, err := agentssh.NewServer(context.Background(), logger, prometheus.NewRegistry(), fs, 0, "")
+	require.NoError(t, err)
+	s.AgentToken = func() string { return "" }
+	s.Manifest = atomic.NewPointer(&agentsdk.Manifest{})
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	var (
+		mu       sync.Mutex
+		statuses []agentsdk.PostScriptStatusRequest
+	)
+	runner := agentscripts.New(agentscripts.Options{
+		LogDir:     t.TempDir(),
+		Logger:     logger,
+		SSHServer:  s,
+		Filesystem: fs,
+		PatchLogs:  patchLogs,
+		PostScriptStatus: func(ctx context.Context, req agentsdk.PostScriptStatusRequest) error {
+			mu.Lock()
+			defer mu.Unlock()
+			statuses = append(statuses, req)
+			return nil
+		},
+	})
+	return runner, func() []agentsdk.PostScriptStatusRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]agentsdk.PostScriptStatusRequest{}, statuses...)
+	}
+}
@@ -0,0 +1,279 @@
+// Package agentsocket implements the API the agent serves on a local Unix
+// socket to processes running in the workspace. It allows scripts and IDE
+// extensions to talk to their agent without the agent token.
+package agentsocket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// EnvSocketPath is the environment variable that holds the path of the agent
+// socket. The agent sets it in workspace sessions.
+const EnvSocketPath = "CODER_AGENT_SOCKET_PATH"
+
+// UpgradeTCP is the protocol DialPeer upgrades its request to.
+const UpgradeTCP = "tcp"
+
+// Status describes the agent and the workspace it runs in.
+type Status struct {
+	AgentID       uuid.UUID                        `json:"agent_id"`
+	AgentName     string                           `json:"agent_name"`
+	WorkspaceID   uuid.UUID                        `json:"workspace_id"`
+	WorkspaceName string                           `json:"workspace_name"`
+	OwnerName     string                           `json:"owner_name"`
+	Lifecycle     codersdk.WorkspaceAgentLifecycle `json:"lifecycle"`
+	Version       string                           `json:"version"`
+	Directory     string                           `json:"directory"`
+	// MetadataKeys are the metadata keys defined in the template. Only these
+	// can be set with PostMetadata.
+	MetadataKeys []string `json:"metadata_keys"`
+	// AutostopInhibitors are the autostop inhibitors held in the workspace.
+	AutostopInhibitors []codersdk.WorkspaceAgentAutostopInhibitor `json:"autostop_inhibitors"`
+}
+
+// PostLogsRequest contains log lines to append to the workspace agent logs.
+type PostLogsRequest struct {
+	Logs []agentsdk.Log `json:"logs"`
+}
+
+// PostMetadataRequest sets the value of a metadata item.
+type PostMetadataRequest struct {
+	Value string `json:"value"`
+	Error string `json:"error"`
+}
+
+// InhibitAutostopRequest holds an autostop inhibitor.
+type InhibitAutostopRequest struct {
+	// Reason is shown on the workspace while the inhibitor is held.
+	Reason string `json:"reason"`
+}
+
+// DefaultPath returns the default path of the agent socket. It's in a
+// directory only the current user can access: $XDG_RUNTIME_DIR/coder-agent
+// if it's set, otherwise coder-agent-<uid> in the temporary directory.
+func DefaultPath() string {
+	if path := os.Getenv("CLIDOCGEN_AGENT_SOCKET_PATH"); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "coder-agent", "agent.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("coder-agent-%d", os.Getuid()), "agent.sock")
+}
+
+// Listen listens on the Unix socket at path. Only the user the agent runs as
+// may connect to it. The directory of the socket is created if it doesn't
+// exist, and must be owned by the user or root. A socket left behind by an
+// agent that is no longer running is removed first, but a path owned by
+// another user is never reused.
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create socket directory: %w", err)
+	}
+	dirInfo, err := os.Lstat(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("stat socket directory: %w", err)
+	}
+	if !dirInfo.IsDir() {
+		return nil, xerrors.Errorf("socket directory %q is not a directory", dir)
+	}
+	if !trustedOwner(dirInfo) {
+		return nil, xerrors.Errorf("socket directory %q is owned by another user", dir)
+	}
+
+	info, err := os.Lstat(path)
+	switch {
+	case err == nil:
+		if !ownedByCurrentUser(info) {
+			return nil, xerrors.Errorf("%q is owned by another user", path)
+		}
+		if info.Mode().Type() != os.ModeSocket {
+			return nil, xerrors.Errorf("%q exists and is not a socket", path)
+		}
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			_ = conn.Close()
+			return nil, xerrors.Errorf("another agent is listening on %q", path)
+		}
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, xerrors.Errorf("remove stale socket: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return nil, xerrors.Errorf("stat socket: %w", err)
+	}
+
+	listener, err := listenUnix(path)
+	if err != nil {
+		return nil, xerrors.Errorf("listen: %w", err)
+	}
+	return listener, nil
+}
+
+// Client talks to the agent over its socket.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the agent listening on the Unix socket at
+// path.
+func NewClient(path string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				// Connections are cheap, so don't keep them around.
+				DisableKeepAlives: true,
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", path)
+				},
+			},
+		},
+	}
+}
+
+// Status returns the status of the agent.
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	res, err := c.request(ctx, http.MethodGet, "/api/v0/status", nil)
+	if err != nil {
+		return Status{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Status{}, codersdk.ReadBodyAsError(res)
+	}
+	var status Status
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
+
+// PostLogs appends lines to the workspace agent logs. They are attributed to
+// the "External" log source.
+func (c *Client) PostLogs(ctx context.Context, req PostLogsRequest) error {
+	res, err := c.request(ctx, http.MethodPost, "/api/v0/logs", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PostMetadata sets the value of a metadata item defined in the template.
+func (c *Client) PostMetadata(ctx context.Context, key string, req PostMetadataRequest) error {
+	res, err := c.request(ctx, http.MethodPost, "/api/v0/metadata/"+key, req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// InhibitAutostop holds an autostop inhibitor until the returned closer is
+// closed or the connection to the agent is lost. The deadline of the
+// workspace is extended while it is held, up to the maximum lifetime set in
+// the template. Reading from the closer blocks until the connection is lost.
+func (c *Client) InhibitAutostop(ctx context.Context, req InhibitAutostopRequest) (codersdk.WorkspaceAgentAutostopInhibitor, io.ReadCloser, error) {
+	res, err := c.request(ctx, http.MethodPost, "/api/v0/autostop-inhibitors", req)
+	if err != nil {
+		return codersdk.WorkspaceAgentAutostopInhibitor{}, nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return codersdk.WorkspaceAgentAutostopInhibitor{}, nil, codersdk.ReadBodyAsError(res)
+	}
+	// The agent keeps the response open for as long as the inhibitor is
+	// held, so only the first value is read.
+	var inhibitor codersdk.WorkspaceAgentAutostopInhibitor
+	err = json.NewDecoder(res.Body).Decode(&inhibitor)
+	if err != nil {
+		_ = res.Body.Close()
+		return codersdk.WorkspaceAgentAutostopInhibitor{}, nil, xerrors.Errorf("decode inhibitor: %w", err)
+	}
+	return inhibitor, res.Body, nil
+}
+
+// Peers returns the workspace agents this agent is allowed to dial.
+func (c *Client) Peers(ctx context.Context) ([]agentsdk.Peer, error) {
+	res, err := c.request(ctx, http.MethodGet, "/api/v0/peers", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	var peers []agentsdk.Peer
+	return peers, json.NewDecoder(res.Body).Decode(&peers)
+}
+
+// DialPeer connects to a TCP port of another workspace agent through this
+// agent. The peer is either an agent ID or "[owner/]workspace[.agent]".
+func (c *Client) DialPeer(ctx context.Context, peer string, port uint16) (io.ReadWriteCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://agent/api/v0/peers/%s/dial/%d", url.PathEscape(peer), port), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", UpgradeTCP)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("connect to the agent: %w", err)
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		defer res.Body.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	// The body of a switching protocols response is the connection itself.
+	conn, ok := res.Body.(io.ReadWriteCloser)
+	if !ok {
+		_ = res.Body.Close()
+		return nil, xerrors.Errorf("unexpected body type %T", res.Body)
+	}
+	return conn, nil
+}
+
+func (c *Client) request(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return nil, xerrors.Errorf("encode body: %w", err)
+		}
+	}
+	// The host is ignored by the dialer.
+	req, err := http.NewRequestWithContext(ctx, method, "http://agent"+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("connect to the agent: %w", err)
+	}
+	return res, nil
+}
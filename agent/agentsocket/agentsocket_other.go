@@ -0,0 +1,39 @@
+//go:build !windows
+
+package agentsocket
+
+import (
+	"net"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// umaskMu serializes changes of the process umask by listenUnix.
+var umaskMu sync.Mutex
+
+// listenUnix creates the socket with the umask cleared for the group and
+// others, so there is no window where other users can connect to it.
+func listenUnix(path string) (net.Listener, error) {
+	umaskMu.Lock()
+	defer umaskMu.Unlock()
+	oldMask := syscall.Umask(0o177)
+	defer syscall.Umask(oldMask)
+	return net.Listen("unix", path)
+}
+
+// trustedOwner reports whether the file is owned by the current user or by
+// root. Only they can replace the socket in a directory they own.
+func trustedOwner(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return int(stat.Uid) == os.Getuid() || stat.Uid == 0
+}
+
+// ownedByCurrentUser reports whether the file is owned by the current user.
+func ownedByCurrentUser(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(stat.Uid) == os.Getuid()
+}
@@ -0,0 +1,85 @@
+package agentsocket_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentsocket"
+)
+
+func TestListen(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix file modes are not used on Windows.")
+	}
+
+	t.Run("Permissions", func(t *testing.T) {
+		t.Parallel()
+		dir := filepath.Join(t.TempDir(), "coder-agent")
+		path := filepath.Join(dir, "agent.sock")
+
+		listener, err := agentsocket.Listen(path)
+		require.NoError(t, err)
+		defer listener.Close()
+
+		info, err := os.Stat(dir)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+		info, err = os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.ModeSocket, info.Mode().Type())
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		// The socket isn't taken over while the agent is listening.
+		_, err = agentsocket.Listen(path)
+		require.ErrorContains(t, err, "another agent is listening")
+	})
+
+	t.Run("StaleSocket", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "agent.sock")
+
+		listener, err := agentsocket.Listen(path)
+		require.NoError(t, err)
+		// Leave the socket file behind like an agent that crashed.
+		listener.(interface{ SetUnlinkOnClose(bool) }).SetUnlinkOnClose(false)
+		require.NoError(t, listener.Close())
+
+		listener, err = agentsocket.Listen(path)
+		require.NoError(t, err)
+		require.NoError(t, listener.Close())
+	})
+
+	t.Run("NotASocket", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "agent.sock")
+		err := os.WriteFile(path, []byte("data"), 0o600)
+		require.NoError(t, err)
+
+		_, err = agentsocket.Listen(path)
+		require.ErrorContains(t, err, "is not a socket")
+		// The file is left alone.
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "data", string(data))
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		if os.Getuid() != 0 {
+			t.Skip("Changing the owner of a file requires root.")
+		}
+		dir := filepath.Join(t.TempDir(), "coder-agent")
+		err := os.Mkdir(dir, 0o777)
+		require.NoError(t, err)
+		err = os.Chown(dir, 65534, 65534)
+		require.NoError(t, err)
+
+		_, err = agentsocket.Listen(filepath.Join(dir, "agent.sock"))
+		require.ErrorContains(t, err, "owned by another user")
+	})
+}
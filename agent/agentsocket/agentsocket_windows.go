@@ -0,0 +1,23 @@
+package agentsocket
+
+import (
+	"net"
+	"os"
+)
+
+// listenUnix listens on the socket. Windows has no umask, the socket is only
+// accessible to the user by the ACL of its directory.
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
+
+// trustedOwner is always true on Windows, where files have no Unix owner.
+func trustedOwner(_ os.FileInfo) bool {
+	return true
+}
+
+// ownedByCurrentUser is always true on Windows, where files have no Unix
+// owner.
+func ownedByCurrentUser(_ os.FileInfo) bool {
+	return true
+}
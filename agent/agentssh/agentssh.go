@@ -28,6 +28,8 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/v2/agent/agentconnlog"
+	"github.com/coder/coder/v2/agent/agentrecord"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -68,6 +70,12 @@ type Server struct {
 	AgentToken    func() string
 	Manifest      *atomic.Pointer[agentsdk.Manifest]
 	ServiceBanner *atomic.Pointer[codersdk.ServiceBannerConfig]
+	// RecordingClient uploads recordings of PTY sessions if the manifest
+	// asks for them.
+	RecordingClient agentrecord.Client
+	// ConnectionReporter reports sessions and port forwards to the
+	// connection log. It may be nil.
+	ConnectionReporter *agentconnlog.Reporter
 
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
@@ -109,7 +117,7 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 
 	srv := &ssh.Server{
 		ChannelHandlers: map[string]ssh.ChannelHandler{
-			"direct-tcpip":                   ssh.DirectTCPIPHandler,
+			"direct-tcpip":                   s.directTCPIPHandler,
 			"direct-streamlocal@openssh.com": directStreamLocalHandler,
 			"session":                        ssh.DefaultSessionHandler,
 		},
@@ -202,6 +210,15 @@ func (s *Server) sessionHandler(session ssh.Session) {
 	}
 	defer s.trackSession(session, false)
 
+	logged := &loggedSession{
+		Session: session,
+		conn:    s.ConnectionReporter.Connect(sessionConnectionType(session.Environ()), session.RemoteAddr(), ""),
+	}
+	defer func() {
+		logged.conn.Disconnect(logged.exitCode, "")
+	}()
+	session = logged
+
 	extraEnv := make([]string, 0)
 	x11, hasX11 := session.X11()
 	if hasX11 {
@@ -382,6 +399,20 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "start_command").Add(1)
 		return xerrors.Errorf("start command: %w", err)
 	}
+
+	var (
+		output io.Reader = ptty.OutputReader()
+		input  io.Reader = session
+	)
+	recorder := s.startRecording(session, sshPty)
+	if recorder != nil {
+		defer func() {
+			_ = recorder.Close()
+		}()
+		output = io.TeeReader(output, recorder.OutputWriter())
+		input = io.TeeReader(input, recorder.InputWriter())
+	}
+
 	defer func() {
 		closeErr := ptty.Close()
 		if closeErr != nil {
@@ -394,6 +425,9 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	}()
 	go func() {
 		for win := range windowSize {
+			if recorder != nil {
+				recorder.Resize(uint16(win.Width), uint16(win.Height))
+			}
 			resizeErr := ptty.Resize(uint16(win.Height), uint16(win.Width))
 			// If the pty is closed, then command has exited, no need to log.
 			if resizeErr != nil && !errors.Is(resizeErr, pty.ErrClosed) {
@@ -404,7 +438,7 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	}()
 
 	go func() {
-		_, err := io.Copy(ptty.InputWriter(), session)
+		_, err := io.Copy(ptty.InputWriter(), input)
 		if err != nil {
 			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "input_io_copy").Add(1)
 		}
@@ -418,7 +452,7 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	n, err := io.Copy(session, output)
 	s.logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
@@ -441,6 +475,26 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	return nil
 }
 
+// startRecording starts recording the session if the manifest asks for it.
+// It returns nil otherwise.
+func (s *Server) startRecording(session ptySession, sshPty ssh.Pty) *agentrecord.Recorder {
+	if s.RecordingClient == nil || s.Manifest == nil {
+		return nil
+	}
+	manifest := s.Manifest.Load()
+	if manifest == nil || !manifest.RecordSessions {
+		return nil
+	}
+	return agentrecord.New(s.logger.Named("recorder"), s.RecordingClient, agentrecord.Options{
+		Type:        codersdk.WorkspaceSessionRecordingTypeSSH,
+		Command:     session.RawCommand(),
+		Term:        sshPty.Term,
+		Width:       uint16(sshPty.Window.Width),
+		Height:      uint16(sshPty.Window.Height),
+		RecordInput: manifest.RecordSessionInput,
+	})
+}
+
 func (s *Server) sftpHandler(session ssh.Session) {
 	s.metrics.sftpConnectionsTotal.Add(1)
 
@@ -0,0 +1,133 @@
+package agentssh
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gliderlabs/ssh"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/v2/agent/agentconnlog"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// sessionConnectionType returns the connection type of a session from the
+// magic session type in its environment.
+func sessionConnectionType(env []string) codersdk.ConnectionType {
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, MagicSessionTypeEnvironmentVariable+"=") {
+			continue
+		}
+		switch strings.TrimPrefix(kv, MagicSessionTypeEnvironmentVariable+"=") {
+		case MagicSessionTypeVSCode:
+			return codersdk.ConnectionTypeVSCode
+		case MagicSessionTypeJetBrains:
+			return codersdk.ConnectionTypeJetBrains
+		}
+	}
+	return codersdk.ConnectionTypeSSH
+}
+
+// loggedSession counts the bytes of a session and remembers its exit code
+// for the connection log.
+type loggedSession struct {
+	ssh.Session
+	conn *agentconnlog.Connection
+
+	exitCode *int32
+}
+
+func (s *loggedSession) Read(p []byte) (int, error) {
+	n, err := s.Session.Read(p)
+	s.conn.AddReceived(n)
+	return n, err
+}
+
+func (s *loggedSession) Write(p []byte) (int, error) {
+	n, err := s.Session.Write(p)
+	s.conn.AddSent(n)
+	return n, err
+}
+
+func (s *loggedSession) Stderr() io.ReadWriter {
+	return &loggedStderr{ReadWriter: s.Session.Stderr(), conn: s.conn}
+}
+
+func (s *loggedSession) Exit(code int) error {
+	exitCode := int32(code)
+	s.exitCode = &exitCode
+	return s.Session.Exit(code)
+}
+
+type loggedStderr struct {
+	io.ReadWriter
+	conn *agentconnlog.Connection
+}
+
+func (s *loggedStderr) Write(p []byte) (int, error) {
+	n, err := s.ReadWriter.Write(p)
+	s.conn.AddSent(n)
+	return n, err
+}
+
+// directTCPIPHandler logs local port forwards and hands them to
+// ssh.DirectTCPIPHandler.
+func (s *Server) directTCPIPHandler(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	var data struct {
+		DestAddr   string
+		DestPort   uint32
+		OriginAddr string
+		OriginPort uint32
+	}
+	if s.ConnectionReporter == nil || gossh.Unmarshal(newChan.ExtraData(), &data) != nil {
+		ssh.DirectTCPIPHandler(srv, conn, newChan, ctx)
+		return
+	}
+
+	c := s.ConnectionReporter.Connect(codersdk.ConnectionTypePortForwarding, conn.RemoteAddr(), strconv.FormatUint(uint64(data.DestPort), 10))
+	ssh.DirectTCPIPHandler(srv, conn, &loggedNewChannel{NewChannel: newChan, conn: c}, ctx)
+}
+
+// loggedNewChannel reports the end of a forwarded connection when its
+// channel is rejected or closed.
+type loggedNewChannel struct {
+	gossh.NewChannel
+	conn *agentconnlog.Connection
+}
+
+func (c *loggedNewChannel) Accept() (gossh.Channel, <-chan *gossh.Request, error) {
+	ch, reqs, err := c.NewChannel.Accept()
+	if err != nil {
+		c.conn.Disconnect(nil, err.Error())
+		return nil, nil, err
+	}
+	return &loggedChannel{Channel: ch, conn: c.conn}, reqs, nil
+}
+
+func (c *loggedNewChannel) Reject(reason gossh.RejectionReason, message string) error {
+	c.conn.Disconnect(nil, message)
+	return c.NewChannel.Reject(reason, message)
+}
+
+type loggedChannel struct {
+	gossh.Channel
+	conn *agentconnlog.Connection
+}
+
+func (c *loggedChannel) Read(p []byte) (int, error) {
+	n, err := c.Channel.Read(p)
+	c.conn.AddReceived(n)
+	return n, err
+}
+
+func (c *loggedChannel) Write(p []byte) (int, error) {
+	n, err := c.Channel.Write(p)
+	c.conn.AddSent(n)
+	return n, err
+}
+
+func (c *loggedChannel) Close() error {
+	c.conn.Disconnect(nil, "")
+	return c.Channel.Close()
+}
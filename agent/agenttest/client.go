@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -52,11 +53,18 @@ type Client struct {
 	PatchWorkspaceLogs   func() error
 	GetServiceBannerFunc func() (codersdk.ServiceBannerConfig, error)
 
-	mu              sync.Mutex // Protects following.
-	lifecycleStates []codersdk.WorkspaceAgentLifecycle
-	startup         agentsdk.PostStartupRequest
-	logs            []agentsdk.Log
-	derpMapUpdates  chan agentsdk.DERPMapUpdate
+	mu                 sync.Mutex // Protects following.
+	peers              []agentsdk.Peer
+	lifecycleStates    []codersdk.WorkspaceAgentLifecycle
+	autostopInhibitors []codersdk.WorkspaceAgentAutostopInhibitor
+	updateStates       []agentsdk.PostUpdateStateRequest
+	startup            agentsdk.PostStartupRequest
+	logs               []agentsdk.Log
+	scriptStatuses     []agentsdk.PostScriptStatusRequest
+	recordings         []agentsdk.PostSessionRecordingRequest
+	recordingChunks    map[uuid.UUID][]agentsdk.PostSessionRecordingChunkRequest
+	connections        []agentsdk.PostConnectionRequest
+	derpMapUpdates     chan agentsdk.DERPMapUpdate
 }
 
 func (c *Client) Manifest(_ context.Context) (agentsdk.Manifest, error) {
@@ -79,6 +87,46 @@ func (c *Client) Listen(_ context.Context) (net.Conn, error) {
 	return clientConn, nil
 }
 
+// SetPeers sets the agents returned by Peers. Agents that are not peers
+// cannot be dialed.
+func (c *Client) SetPeers(peers []agentsdk.Peer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.peers = peers
+}
+
+func (c *Client) Peers(ctx context.Context) ([]agentsdk.Peer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logger.Debug(ctx, "get peers")
+	return slices.Clone(c.peers), nil
+}
+
+func (c *Client) ListenPeer(ctx context.Context, agentID uuid.UUID) (net.Conn, error) {
+	c.mu.Lock()
+	isPeer := slices.ContainsFunc(c.peers, func(peer agentsdk.Peer) bool {
+		return peer.AgentID == agentID
+	})
+	c.mu.Unlock()
+	if !isPeer {
+		return nil, xerrors.Errorf("agent %s is not a peer", agentID)
+	}
+	c.logger.Debug(ctx, "listen peer", slog.F("agent_id", agentID))
+
+	clientConn, serverConn := net.Pipe()
+	closed := make(chan struct{})
+	c.t.Cleanup(func() {
+		_ = serverConn.Close()
+		_ = clientConn.Close()
+		<-closed
+	})
+	go func() {
+		_ = c.coordinator.ServeClient(serverConn, uuid.New(), agentID)
+		close(closed)
+	}()
+	return clientConn, nil
+}
+
 func (c *Client) ReportStats(ctx context.Context, _ slog.Logger, statsChan <-chan *agentsdk.Stats, setInterval func(time.Duration)) (io.Closer, error) {
 	doneCh := make(chan struct{})
 	ctx, cancel := context.WithCancel(ctx)
@@ -125,6 +173,34 @@ func (c *Client) PostLifecycle(ctx context.Context, req agentsdk.PostLifecycleRe
 	return nil
 }
 
+func (c *Client) GetAutostopInhibitors() []codersdk.WorkspaceAgentAutostopInhibitor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.autostopInhibitors
+}
+
+func (c *Client) PostAutostopInhibitors(ctx context.Context, req agentsdk.PostAutostopInhibitorsRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.autostopInhibitors = req.Inhibitors
+	c.logger.Debug(ctx, "post autostop inhibitors", slog.F("req", req))
+	return nil
+}
+
+func (c *Client) GetUpdateStates() []agentsdk.PostUpdateStateRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.updateStates
+}
+
+func (c *Client) PostUpdateState(ctx context.Context, req agentsdk.PostUpdateStateRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updateStates = append(c.updateStates, req)
+	c.logger.Debug(ctx, "post update state", slog.F("req", req))
+	return nil
+}
+
 func (c *Client) PostAppHealth(ctx context.Context, req agentsdk.PostAppHealthsRequest) error {
 	c.logger.Debug(ctx, "post app health", slog.F("req", req))
 	return nil
@@ -178,6 +254,68 @@ func (c *Client) PatchLogs(ctx context.Context, logs agentsdk.PatchLogs) error {
 	return nil
 }
 
+func (c *Client) GetScriptStatuses() []agentsdk.PostScriptStatusRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.scriptStatuses
+}
+
+func (c *Client) PostScriptStatus(ctx context.Context, req agentsdk.PostScriptStatusRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scriptStatuses = append(c.scriptStatuses, req)
+	c.logger.Debug(ctx, "post script status", slog.F("req", req))
+	return nil
+}
+
+func (c *Client) GetSessionRecordings() []agentsdk.PostSessionRecordingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recordings
+}
+
+// GetSessionRecordingChunks returns the chunks uploaded for a recording.
+func (c *Client) GetSessionRecordingChunks(id uuid.UUID) []agentsdk.PostSessionRecordingChunkRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recordingChunks[id]
+}
+
+func (c *Client) PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordings = append(c.recordings, req)
+	c.logger.Debug(ctx, "post session recording", slog.F("req", req))
+	return nil
+}
+
+func (c *Client) PostSessionRecordingChunk(ctx context.Context, id uuid.UUID, req agentsdk.PostSessionRecordingChunkRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.recordingChunks == nil {
+		c.recordingChunks = map[uuid.UUID][]agentsdk.PostSessionRecordingChunkRequest{}
+	}
+	c.recordingChunks[id] = append(c.recordingChunks[id], req)
+	c.logger.Debug(ctx, "post session recording chunk", slog.F("id", id), slog.F("index", req.Index), slog.F("final", req.Final))
+	return nil
+}
+
+// GetConnections returns the connections reported to the connection log, in
+// the order they were reported.
+func (c *Client) GetConnections() []agentsdk.PostConnectionRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]agentsdk.PostConnectionRequest(nil), c.connections...)
+}
+
+func (c *Client) PostConnection(ctx context.Context, req agentsdk.PostConnectionRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connections = append(c.connections, req)
+	c.logger.Debug(ctx, "post connection", slog.F("req", req))
+	return nil
+}
+
 func (c *Client) SetServiceBannerFunc(f func() (codersdk.ServiceBannerConfig, error)) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -0,0 +1,309 @@
+// Package agentupdate replaces the agent binary with the one served by coderd
+// when their versions differ.
+package agentupdate
+
+import (
+	"context"
+	"crypto/sha1" //#nosec // Not used for cryptography, coderd serves SHA1 hashes.
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/xerrors"
+	"tailscale.com/types/key"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// EnvExecStateFile is the environment variable the agent that updated itself
+// passes the path of the file with the ExecState in to the updated agent. The
+// state holds the node private key, so it isn't passed in the environment
+// itself: the initial environment of a process stays readable in
+// /proc/<pid>/environ.
+const EnvExecStateFile = "CODER_AGENT_EXEC_STATE_FILE"
+
+// ExecState is handed over by the agent that updated itself to the updated
+// agent, so the workspace doesn't notice the agent was replaced.
+type ExecState struct {
+	// PreviousVersion is the version of the agent that updated itself.
+	PreviousVersion string `json:"previous_version"`
+	// NodePrivateKey is the tailnet key of the agent. Peers keep their
+	// connections to the agent when it is reused.
+	NodePrivateKey key.NodePrivate `json:"node_private_key"`
+	// Lifecycle is the lifecycle state of the agent. The startup scripts
+	// aren't run again if they already ran.
+	Lifecycle codersdk.WorkspaceAgentLifecycle `json:"lifecycle"`
+}
+
+// ReadExecState returns the state handed over by the agent that updated
+// itself, or nil if the agent wasn't started by an update. The state file is
+// removed once it is read, and its path is removed from the environment so it
+// doesn't leak into the processes started by the agent.
+func ReadExecState() (*ExecState, error) {
+	path, ok := os.LookupEnv(EnvExecStateFile)
+	if !ok {
+		return nil, nil
+	}
+	err := os.Unsetenv(EnvExecStateFile)
+	if err != nil {
+		return nil, xerrors.Errorf("unset %s: %w", EnvExecStateFile, err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read exec state: %w", err)
+	}
+	err = os.Remove(path)
+	if err != nil {
+		return nil, xerrors.Errorf("remove exec state: %w", err)
+	}
+	var state ExecState
+	err = json.Unmarshal(raw, &state)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal exec state: %w", err)
+	}
+	return &state, nil
+}
+
+// NeedsUpdate returns true if the agent version differs from the server
+// version. Development builds and invalid versions are never updated:
+// semver.Compare considers all invalid versions equal, and smaller than any
+// valid one.
+func NeedsUpdate(agentVersion, serverVersion string) bool {
+	if buildinfo.IsDevVersion(agentVersion) || buildinfo.IsDevVersion(serverVersion) {
+		return false
+	}
+	if !semver.IsValid(agentVersion) || !semver.IsValid(serverVersion) {
+		return false
+	}
+	return semver.Compare(agentVersion, serverVersion) != 0
+}
+
+// Options are a set of options for the updater.
+type Options struct {
+	Logger slog.Logger
+	// Client is used to fetch the version and the agent binary of coderd.
+	Client *codersdk.Client
+	// ExecutablePath is the path of the running agent binary. It is
+	// replaced by the updated binary.
+	ExecutablePath string
+	// Exec replaces the running process. Defaults to syscall.Exec, tests
+	// override it.
+	Exec func(argv0 string, argv []string, envv []string) error
+}
+
+// New creates an updater.
+func New(opts Options) *Updater {
+	if opts.Exec == nil {
+		opts.Exec = execProcess
+	}
+	return &Updater{Options: opts}
+}
+
+// Updater downloads the agent binary served by coderd and executes it in
+// place of the running agent.
+type Updater struct {
+	Options
+}
+
+// Check returns the version of coderd, and whether the agent must be updated
+// to it.
+func (u *Updater) Check(ctx context.Context) (string, bool, error) {
+	buildInfo, err := u.Client.BuildInfo(ctx)
+	if err != nil {
+		return "", false, xerrors.Errorf("get build info: %w", err)
+	}
+	return buildInfo.Version, NeedsUpdate(buildinfo.Version(), buildInfo.Version), nil
+}
+
+// Download fetches the agent binary for this platform from coderd next to
+// the running binary and returns its path. The binary is verified against
+// the hash coderd lists for it in coder.sha1, and by checking the version it
+// reports.
+func (u *Updater) Download(ctx context.Context, serverVersion string) (string, error) {
+	if !semver.IsValid(serverVersion) {
+		return "", xerrors.Errorf("invalid server version %q", serverVersion)
+	}
+	name := binaryName(runtime.GOOS, runtime.GOARCH)
+	wantHash, err := u.binaryHash(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	binURL, err := u.Client.URL.Parse("/bin/" + name)
+	if err != nil {
+		return "", xerrors.Errorf("parse binary url: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, binURL.String(), nil)
+	if err != nil {
+		return "", xerrors.Errorf("create request: %w", err)
+	}
+	res, err := u.Client.HTTPClient.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("download binary: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", xerrors.Errorf("download binary: unexpected status code %d", res.StatusCode)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(u.ExecutablePath), ".coder-agent-update-*")
+	if err != nil {
+		return "", xerrors.Errorf("create binary: %w", err)
+	}
+	path := file.Name()
+	remove := true
+	defer func() {
+		if remove {
+			_ = os.Remove(path)
+		}
+	}()
+	hash := sha1.New() //#nosec // Not used for cryptography.
+	_, err = io.Copy(io.MultiWriter(file, hash), res.Body)
+	if err != nil {
+		_ = file.Close()
+		return "", xerrors.Errorf("write binary: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		return "", xerrors.Errorf("close binary: %w", err)
+	}
+	gotHash := hex.EncodeToString(hash.Sum(nil))
+	if gotHash != wantHash {
+		return "", xerrors.Errorf("binary hash %q does not match the expected hash %q", gotHash, wantHash)
+	}
+	err = os.Chmod(path, 0o755) //nolint:gosec // The binary must be executable.
+	if err != nil {
+		return "", xerrors.Errorf("chmod binary: %w", err)
+	}
+
+	version, err := binaryVersion(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if !semver.IsValid(version) || semver.Compare(version, serverVersion) != 0 {
+		return "", xerrors.Errorf("downloaded binary has version %q, expected %q", version, serverVersion)
+	}
+	remove = false
+	u.Logger.Info(ctx, "downloaded agent binary", slog.F("path", path), slog.F("version", version))
+	return path, nil
+}
+
+// Exec replaces the running agent binary with the downloaded one and
+// executes it with the same arguments. It only returns on failure.
+func (u *Updater) Exec(path string, state ExecState) error {
+	statePath, err := writeExecState(state)
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	err = os.Rename(path, u.ExecutablePath)
+	if err != nil {
+		_ = os.Remove(path)
+		_ = os.Remove(statePath)
+		return xerrors.Errorf("replace binary: %w", err)
+	}
+	env := append(os.Environ(), fmt.Sprintf("%s=%s", EnvExecStateFile, statePath))
+	err = u.Options.Exec(u.ExecutablePath, os.Args, env)
+	if err != nil {
+		_ = os.Remove(statePath)
+		return xerrors.Errorf("exec updated binary: %w", err)
+	}
+	return nil
+}
+
+// writeExecState writes the state to a file only the agent user can read,
+// and returns its path.
+func writeExecState(state ExecState) (string, error) {
+	rawState, err := json.Marshal(state)
+	if err != nil {
+		return "", xerrors.Errorf("marshal exec state: %w", err)
+	}
+	// CreateTemp creates the file with mode 0600.
+	file, err := os.CreateTemp("", "coder-agent-exec-state-*")
+	if err != nil {
+		return "", xerrors.Errorf("create exec state: %w", err)
+	}
+	_, err = file.Write(rawState)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", xerrors.Errorf("write exec state: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", xerrors.Errorf("close exec state: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// binaryHash returns the SHA1 hash of the binary with the name from the
+// coder.sha1 file coderd serves next to the binaries. Its lines are in the
+// format of shasum: "<hash> *<name>".
+func (u *Updater) binaryHash(ctx context.Context, name string) (string, error) {
+	sumsURL, err := u.Client.URL.Parse("/bin/coder.sha1")
+	if err != nil {
+		return "", xerrors.Errorf("parse checksums url: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sumsURL.String(), nil)
+	if err != nil {
+		return "", xerrors.Errorf("create request: %w", err)
+	}
+	res, err := u.Client.HTTPClient.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("download checksums: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", xerrors.Errorf("download checksums: unexpected status code %d", res.StatusCode)
+	}
+	sums, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return "", xerrors.Errorf("read checksums: %w", err)
+	}
+	for _, line := range strings.Split(string(sums), "\n") {
+		hash, file, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok && strings.TrimPrefix(strings.TrimSpace(file), "*") == name {
+			return strings.ToLower(hash), nil
+		}
+	}
+	return "", xerrors.Errorf("no checksum for %q in coder.sha1", name)
+}
+
+// binaryName returns the name coderd serves the binary of the platform as.
+func binaryName(goos, goarch string) string {
+	if goarch == "arm" {
+		goarch = "armv7"
+	}
+	name := fmt.Sprintf("coder-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// binaryVersion runs the binary to get its version.
+func binaryVersion(ctx context.Context, path string) (string, error) {
+	out, err := exec.CommandContext(ctx, path, "version", "--output", "json").Output()
+	if err != nil {
+		return "", xerrors.Errorf("run downloaded binary: %w", err)
+	}
+	var info struct {
+		Version string `json:"version"`
+	}
+	err = json.Unmarshal(out, &info)
+	if err != nil {
+		return "", xerrors.Errorf("unmarshal version of downloaded binary: %w", err)
+	}
+	return info.Version, nil
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package agentupdate
+
+import "syscall"
+
+func execProcess(argv0 string, argv []string, envv []string) error {
+	return syscall.Exec(argv0, argv, envv)
+}
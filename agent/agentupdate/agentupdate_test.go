@@ -0,0 +1,188 @@
+package agentupdate_test
+
+import (
+	"crypto/sha1" //#nosec // Not used for cryptography.
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/types/key"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agentupdate"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNeedsUpdate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		agentVersion  string
+		serverVersion string
+		needsUpdate   bool
+	}{
+		{"Same", "v2.1.0", "v2.1.0", false},
+		{"SameWithBuild", "v2.1.0+abcdef", "v2.1.0+123456", false},
+		{"Patch", "v2.1.0", "v2.1.1", true},
+		{"Minor", "v2.1.0", "v2.2.0", true},
+		{"Downgrade", "v2.2.0", "v2.1.0", true},
+		{"DevAgent", "v0.0.0-devel+abcdef", "v2.1.0", false},
+		{"DevServer", "v2.1.0", "v0.0.0-devel+abcdef", false},
+		{"InvalidAgent", "2.1.0", "v2.1.0", false},
+		{"InvalidServer", "v2.1.0", "latest", false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.needsUpdate, agentupdate.NeedsUpdate(tc.agentVersion, tc.serverVersion))
+		})
+	}
+}
+
+//nolint:paralleltest // Uses t.Setenv.
+func TestReadExecState(t *testing.T) {
+	state := agentupdate.ExecState{
+		PreviousVersion: "v2.1.0",
+		NodePrivateKey:  key.NewNode(),
+		Lifecycle:       codersdk.WorkspaceAgentLifecycleReady,
+	}
+
+	var env []string
+	u := agentupdate.New(agentupdate.Options{
+		Logger:         slogtest.Make(t, nil),
+		ExecutablePath: filepath.Join(t.TempDir(), "coder"),
+		Exec: func(_ string, _ []string, envv []string) error {
+			env = envv
+			return nil
+		},
+	})
+	path := filepath.Join(t.TempDir(), "coder-new")
+	require.NoError(t, os.WriteFile(path, []byte("binary"), 0o600))
+	require.NoError(t, u.Exec(path, state))
+
+	privateKey, err := state.NodePrivateKey.MarshalText()
+	require.NoError(t, err)
+	prefix := agentupdate.EnvExecStateFile + "="
+	var statePath string
+	for _, e := range env {
+		if strings.HasPrefix(e, prefix) {
+			statePath = strings.TrimPrefix(e, prefix)
+		}
+		require.NotContains(t, e, string(privateKey), "the node key must not be in the environment")
+	}
+	require.NotEmpty(t, statePath)
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(statePath)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+	t.Setenv(agentupdate.EnvExecStateFile, statePath)
+
+	got, err := agentupdate.ReadExecState()
+	require.NoError(t, err)
+	require.Equal(t, state.PreviousVersion, got.PreviousVersion)
+	require.Equal(t, state.Lifecycle, got.Lifecycle)
+	require.True(t, state.NodePrivateKey.Equal(got.NodePrivateKey))
+	_, ok := os.LookupEnv(agentupdate.EnvExecStateFile)
+	require.False(t, ok, "exec state must be removed from the environment")
+	_, err = os.Stat(statePath)
+	require.ErrorIs(t, err, os.ErrNotExist, "exec state file must be removed")
+
+	got, err = agentupdate.ReadExecState()
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake binary is a shell script")
+	}
+
+	binary := []byte("#!/bin/sh\necho '{\"version\":\"v2.2.0\"}'\n")
+	hash := sha1.Sum(binary) //#nosec // Not used for cryptography.
+	binaryName := fmt.Sprintf("coder-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOARCH == "arm" {
+		binaryName = fmt.Sprintf("coder-%s-armv7", runtime.GOOS)
+	}
+	newUpdater := func(t *testing.T, sums string) *agentupdate.Updater {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/bin/coder.sha1":
+				_, _ = rw.Write([]byte(sums))
+			case "/bin/" + binaryName:
+				// The ETag must not be trusted, it is computed from the
+				// same file that is served.
+				rw.Header().Set("ETag", fmt.Sprintf("%q", hex.EncodeToString(hash[:])))
+				_, _ = rw.Write(binary)
+			default:
+				http.NotFound(rw, r)
+			}
+		}))
+		t.Cleanup(srv.Close)
+		srvURL, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		return agentupdate.New(agentupdate.Options{
+			Logger:         slogtest.Make(t, nil),
+			Client:         codersdk.New(srvURL),
+			ExecutablePath: filepath.Join(t.TempDir(), "coder"),
+		})
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		u := newUpdater(t, fmt.Sprintf("0000 *coder-other-arch\n%s *%s\n", hex.EncodeToString(hash[:]), binaryName))
+		path, err := u.Download(ctx, "v2.2.0")
+		require.NoError(t, err)
+		require.Equal(t, filepath.Dir(u.ExecutablePath), filepath.Dir(path))
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, binary, got)
+	})
+
+	t.Run("HashMismatch", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		u := newUpdater(t, fmt.Sprintf("0000 *%s\n", binaryName))
+		_, err := u.Download(ctx, "v2.2.0")
+		require.ErrorContains(t, err, "does not match the expected hash")
+		entries, err := os.ReadDir(filepath.Dir(u.ExecutablePath))
+		require.NoError(t, err)
+		require.Empty(t, entries, "failed downloads must be removed")
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		u := newUpdater(t, fmt.Sprintf("%s *%s\n", hex.EncodeToString(hash[:]), binaryName))
+		_, err := u.Download(ctx, "v2.3.0")
+		require.ErrorContains(t, err, `downloaded binary has version "v2.2.0", expected "v2.3.0"`)
+	})
+
+	t.Run("MissingChecksum", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		u := newUpdater(t, fmt.Sprintf("%s *coder-other-arch\n", hex.EncodeToString(hash[:])))
+		_, err := u.Download(ctx, "v2.2.0")
+		require.ErrorContains(t, err, "no checksum")
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		u := newUpdater(t, fmt.Sprintf("%s *%s\n", hex.EncodeToString(hash[:]), binaryName))
+		_, err := u.Download(ctx, "latest")
+		require.ErrorContains(t, err, "invalid server version")
+	})
+}
@@ -0,0 +1,9 @@
+package agentupdate
+
+import "golang.org/x/xerrors"
+
+// execProcess fails on Windows, a process can't replace itself and the binary
+// of a running process can't be replaced.
+func execProcess(_ string, _ []string, _ []string) error {
+	return xerrors.New("agent self-update is not supported on Windows")
+}
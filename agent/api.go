@@ -28,10 +28,18 @@ func (a *agent) apiHandler() http.Handler {
 
 	lp := &listeningPortsHandler{ignorePorts: cpy}
 	r.Get("/api/v0/listening-ports", lp.handler)
+	r.Get("/api/v0/reconnecting-ptys", a.handleReconnectingPTYSessions)
 
 	return r
 }
 
+// handleReconnectingPTYSessions lists the live reconnecting PTY sessions.
+func (a *agent) handleReconnectingPTYSessions(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentReconnectingPTYSessionsResponse{
+		Sessions: a.reconnectingPTYSessionsList(),
+	})
+}
+
 type listeningPortsHandler struct {
 	mut         sync.Mutex
 	ports       []codersdk.WorkspaceAgentListeningPort
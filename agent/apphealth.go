@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/pty"
 	"github.com/coder/retry"
 )
 
@@ -21,11 +24,14 @@ type WorkspaceAgentApps func(context.Context) ([]codersdk.WorkspaceApp, error)
 // PostWorkspaceAgentAppHealth updates the workspace app health.
 type PostWorkspaceAgentAppHealth func(context.Context, agentsdk.PostAppHealthsRequest) error
 
+// CreateAppHealthCommand creates the command of a command health check.
+type CreateAppHealthCommand func(ctx context.Context, script string, env []string) (*pty.Cmd, error)
+
 // WorkspaceAppHealthReporter is a function that checks and reports the health of the workspace apps until the passed context is canceled.
 type WorkspaceAppHealthReporter func(ctx context.Context)
 
 // NewWorkspaceAppHealthReporter creates a WorkspaceAppHealthReporter that reports app health to coderd.
-func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.WorkspaceApp, postWorkspaceAgentAppHealth PostWorkspaceAgentAppHealth) WorkspaceAppHealthReporter {
+func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.WorkspaceApp, createCommand CreateAppHealthCommand, postWorkspaceAgentAppHealth PostWorkspaceAgentAppHealth) WorkspaceAppHealthReporter {
 	runHealthcheckLoop := func(ctx context.Context) error {
 		// no need to run this loop if no apps for this workspace.
 		if len(apps) == 0 {
@@ -65,27 +71,7 @@ func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.Workspace
 						return
 					case <-t.C:
 					}
-					// we set the http timeout to the healthcheck interval to prevent getting too backed up.
-					client := &http.Client{
-						Timeout: time.Duration(app.Healthcheck.Interval) * time.Second,
-					}
-					err := func() error {
-						req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.Healthcheck.URL, nil)
-						if err != nil {
-							return err
-						}
-						res, err := client.Do(req)
-						if err != nil {
-							return err
-						}
-						// successful healthcheck is a non-5XX status code
-						_ = res.Body.Close()
-						if res.StatusCode >= http.StatusInternalServerError {
-							return xerrors.Errorf("error status code: %d", res.StatusCode)
-						}
-
-						return nil
-					}()
+					err := checkAppHealth(ctx, app, createCommand)
 					if err != nil {
 						mu.Lock()
 						if failures[app.ID] < int(app.Healthcheck.Threshold) {
@@ -155,7 +141,58 @@ func NewWorkspaceAppHealthReporter(logger slog.Logger, apps []codersdk.Workspace
 }
 
 func shouldStartTicker(app codersdk.WorkspaceApp) bool {
-	return app.Healthcheck.URL != "" && app.Healthcheck.Interval > 0 && app.Healthcheck.Threshold > 0
+	hasCheck := app.Healthcheck.URL != "" || app.Healthcheck.TCP != "" || app.Healthcheck.Command != ""
+	return hasCheck && app.Healthcheck.Interval > 0 && app.Healthcheck.Threshold > 0
+}
+
+// checkAppHealth runs a single health check of the app. Only one of the URL,
+// TCP address or command is set.
+func checkAppHealth(ctx context.Context, app codersdk.WorkspaceApp, createCommand CreateAppHealthCommand) error {
+	// we set the timeout to the healthcheck interval to prevent getting too backed up.
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(app.Healthcheck.Interval)*time.Second)
+	defer cancel()
+
+	switch {
+	case app.Healthcheck.TCP != "":
+		// successful healthcheck is an accepted connection
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", app.Healthcheck.TCP)
+		if err != nil {
+			return err
+		}
+		_ = conn.Close()
+		return nil
+
+	case app.Healthcheck.Command != "":
+		// successful healthcheck is a zero exit code
+		cmdPty, err := createCommand(ctx, app.Healthcheck.Command, nil)
+		if err != nil {
+			return xerrors.Errorf("create command: %w", err)
+		}
+		cmd := cmdPty.AsExec()
+		err = cmd.Run()
+		if err != nil {
+			// a command killed by the timeout fails with a misleading error.
+			return errors.Join(err, ctx.Err())
+		}
+		return nil
+
+	default:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.Healthcheck.URL, nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		// successful healthcheck is a non-5XX status code
+		_ = res.Body.Close()
+		if res.StatusCode >= http.StatusInternalServerError {
+			return xerrors.Errorf("error status code: %d", res.StatusCode)
+		}
+		return nil
+	}
 }
 
 func healthChanged(old map[uuid.UUID]codersdk.WorkspaceAppHealth, new map[uuid.UUID]codersdk.WorkspaceAppHealth) bool {
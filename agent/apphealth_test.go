@@ -2,13 +2,16 @@ package agent_test
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog"
@@ -17,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/pty"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -161,6 +165,118 @@ func TestAppHealth_NotSpamming(t *testing.T) {
 	require.LessOrEqual(t, atomic.LoadInt32(counter), int32(2))
 }
 
+func TestAppHealth_TCP(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	// Nothing listens on this address once the listener is closed.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddr := closed.Addr().String()
+	_ = closed.Close()
+
+	apps := []codersdk.WorkspaceApp{
+		{
+			ID:   uuid.New(),
+			Slug: "app1",
+			Healthcheck: codersdk.Healthcheck{
+				TCP:       listener.Addr().String(),
+				Interval:  1,
+				Threshold: 1,
+			},
+			Health: codersdk.WorkspaceAppHealthInitializing,
+		},
+		{
+			ID:   uuid.New(),
+			Slug: "app2",
+			Healthcheck: codersdk.Healthcheck{
+				TCP:       closedAddr,
+				Interval:  1,
+				Threshold: 1,
+			},
+			Health: codersdk.WorkspaceAppHealthInitializing,
+		},
+	}
+	getApps, closeFn := setupAppReporter(ctx, t, apps, nil)
+	defer closeFn()
+	require.Eventually(t, func() bool {
+		apps, err := getApps(ctx)
+		if err != nil {
+			return false
+		}
+
+		return apps[0].Health == codersdk.WorkspaceAppHealthHealthy &&
+			apps[1].Health == codersdk.WorkspaceAppHealthUnhealthy
+	}, testutil.WaitLong, testutil.IntervalSlow)
+}
+
+func TestAppHealth_Command(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands require sh")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	apps := []codersdk.WorkspaceApp{
+		{
+			ID:   uuid.New(),
+			Slug: "app1",
+			Healthcheck: codersdk.Healthcheck{
+				Command:   "exit 0",
+				Interval:  1,
+				Threshold: 1,
+			},
+			Health: codersdk.WorkspaceAppHealthInitializing,
+		},
+		{
+			ID:   uuid.New(),
+			Slug: "app2",
+			Healthcheck: codersdk.Healthcheck{
+				Command:   "exit 1",
+				Interval:  1,
+				Threshold: 1,
+			},
+			Health: codersdk.WorkspaceAppHealthInitializing,
+		},
+		{
+			ID:   uuid.New(),
+			Slug: "app3",
+			Healthcheck: codersdk.Healthcheck{
+				// sleep longer than the interval to cause the health check to time out
+				Command:   "sleep 5",
+				Interval:  1,
+				Threshold: 1,
+			},
+			Health: codersdk.WorkspaceAppHealthInitializing,
+		},
+	}
+	getApps, closeFn := setupAppReporter(ctx, t, apps, nil)
+	defer closeFn()
+	require.Eventually(t, func() bool {
+		apps, err := getApps(ctx)
+		if err != nil {
+			return false
+		}
+
+		return apps[0].Health == codersdk.WorkspaceAppHealthHealthy &&
+			apps[1].Health == codersdk.WorkspaceAppHealthUnhealthy &&
+			apps[2].Health == codersdk.WorkspaceAppHealthUnhealthy
+	}, testutil.WaitLong, testutil.IntervalSlow)
+}
+
 func setupAppReporter(ctx context.Context, t *testing.T, apps []codersdk.WorkspaceApp, handlers []http.Handler) (agent.WorkspaceAgentApps, func()) {
 	closers := []func(){}
 	for i, handler := range handlers {
@@ -197,7 +313,11 @@ func setupAppReporter(ctx context.Context, t *testing.T, apps []codersdk.Workspa
 		return nil
 	}
 
-	go agent.NewWorkspaceAppHealthReporter(slogtest.Make(t, nil).Leveled(slog.LevelDebug), apps, postWorkspaceAgentAppHealth)(ctx)
+	createCommand := func(ctx context.Context, script string, _ []string) (*pty.Cmd, error) {
+		return pty.CommandContext(ctx, "sh", "-c", script), nil
+	}
+
+	go agent.NewWorkspaceAppHealthReporter(slogtest.Make(t, nil).Leveled(slog.LevelDebug), apps, createCommand, postWorkspaceAgentAppHealth)(ctx)
 
 	return workspaceAgentApps, func() {
 		for _, closeFn := range closers {
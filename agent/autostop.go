@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/retry"
+)
+
+// addAutostopInhibitor holds an inhibitor until the returned function is
+// called. coderd extends the deadline of the workspace while any is held.
+func (a *agent) addAutostopInhibitor(reason string) (codersdk.WorkspaceAgentAutostopInhibitor, func()) {
+	inhibitor := codersdk.WorkspaceAgentAutostopInhibitor{
+		ID:        uuid.New(),
+		Reason:    reason,
+		CreatedAt: dbtime.Now(),
+	}
+	a.autostopInhibitorsMu.Lock()
+	a.autostopInhibitors[inhibitor.ID] = inhibitor
+	a.autostopInhibitorsMu.Unlock()
+	a.notifyAutostopInhibitors()
+
+	return inhibitor, func() {
+		a.autostopInhibitorsMu.Lock()
+		delete(a.autostopInhibitors, inhibitor.ID)
+		a.autostopInhibitorsMu.Unlock()
+		a.notifyAutostopInhibitors()
+	}
+}
+
+// listAutostopInhibitors returns the inhibitors held, oldest first.
+func (a *agent) listAutostopInhibitors() []codersdk.WorkspaceAgentAutostopInhibitor {
+	a.autostopInhibitorsMu.Lock()
+	inhibitors := maps.Values(a.autostopInhibitors)
+	a.autostopInhibitorsMu.Unlock()
+	slices.SortFunc(inhibitors, func(a, b codersdk.WorkspaceAgentAutostopInhibitor) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return inhibitors
+}
+
+func (a *agent) notifyAutostopInhibitors() {
+	select {
+	case a.autostopInhibitorsUpdate <- struct{}{}:
+	default:
+	}
+}
+
+// reportAutostopInhibitorsLoop reports the full set of inhibitors whenever it
+// changes. It is also triggered when the agent (re)connects, which clears
+// inhibitors left behind by a previous agent process.
+func (a *agent) reportAutostopInhibitorsLoop(ctx context.Context) {
+	for {
+		select {
+		case <-a.autostopInhibitorsUpdate:
+		case <-ctx.Done():
+			return
+		}
+
+		for r := retry.New(time.Second, 15*time.Second); r.Wait(ctx); {
+			inhibitors := a.listAutostopInhibitors()
+			a.logger.Debug(ctx, "reporting autostop inhibitors", slog.F("inhibitors", inhibitors))
+
+			err := a.client.PostAutostopInhibitors(ctx, agentsdk.PostAutostopInhibitorsRequest{
+				Inhibitors: inhibitors,
+			})
+			if err == nil {
+				break
+			}
+			if xerrors.Is(err, context.Canceled) || xerrors.Is(err, context.DeadlineExceeded) {
+				return
+			}
+			a.logger.Warn(ctx, "agent failed to report autostop inhibitors", slog.Error(err))
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/retry"
+)
+
+// resolvePeer finds the peer matching name, which is either the ID of an
+// agent or "[owner/]workspace[.agent]". The owner defaults to the owner of
+// this workspace, the agent may be omitted if the workspace has only one.
+func (a *agent) resolvePeer(ctx context.Context, name string) (agentsdk.Peer, error) {
+	peers, err := a.client.Peers(ctx)
+	if err != nil {
+		return agentsdk.Peer{}, xerrors.Errorf("list peers: %w", err)
+	}
+	if id, err := uuid.Parse(name); err == nil {
+		for _, peer := range peers {
+			if peer.AgentID == id {
+				return peer, nil
+			}
+		}
+		return agentsdk.Peer{}, xerrors.Errorf("no peer with agent ID %s", id)
+	}
+
+	owner := ""
+	if manifest := a.manifest.Load(); manifest != nil {
+		owner = manifest.OwnerName
+	}
+	workspace, agentName := name, ""
+	if parts := strings.SplitN(workspace, "/", 2); len(parts) == 2 {
+		owner, workspace = parts[0], parts[1]
+	}
+	if parts := strings.SplitN(workspace, ".", 2); len(parts) == 2 {
+		workspace, agentName = parts[0], parts[1]
+	}
+
+	var matches []agentsdk.Peer
+	for _, peer := range peers {
+		if !strings.EqualFold(peer.OwnerName, owner) || !strings.EqualFold(peer.WorkspaceName, workspace) {
+			continue
+		}
+		if agentName != "" && peer.AgentName != agentName {
+			continue
+		}
+		matches = append(matches, peer)
+	}
+	switch len(matches) {
+	case 0:
+		return agentsdk.Peer{}, xerrors.Errorf("no peer named %q", name)
+	case 1:
+		return matches[0], nil
+	default:
+		agentNames := make([]string, 0, len(matches))
+		for _, peer := range matches {
+			agentNames = append(agentNames, peer.AgentName)
+		}
+		return agentsdk.Peer{}, xerrors.Errorf("workspace %q has multiple agents, specify one of: %s", workspace, strings.Join(agentNames, ", "))
+	}
+}
+
+// peerDialTimeout is how long a peer has to become reachable. Dials are
+// shared by concurrent requests, so it doesn't depend on any of them.
+const peerDialTimeout = 30 * time.Second
+
+// dialPeer returns a connection to the agent with the given ID. Connections
+// are kept open and reused until the agent closes or they are evicted.
+func (a *agent) dialPeer(ctx context.Context, agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
+	a.peersMu.Lock()
+	conn, ok := a.peers[agentID]
+	a.peersMu.Unlock()
+	if ok {
+		return conn, nil
+	}
+
+	// Concurrent requests for the same peer wait for a single dial, which
+	// happens outside of peersMu so that other peers remain usable.
+	result := a.peerDials.DoChan(agentID.String(), func() (interface{}, error) {
+		return a.dialPeerConn(agentID)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, xerrors.Errorf("timed out waiting for peer to become reachable: %w", ctx.Err())
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		//nolint:forcetypeassert
+		return res.Val.(*codersdk.WorkspaceAgentConn), nil
+	}
+}
+
+// dialPeerConn connects to the agent with the given ID and publishes the
+// connection for reuse once the agent is reachable.
+func (a *agent) dialPeerConn(agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
+	a.closeMutex.Lock()
+	network := a.network
+	closed := a.isClosed()
+	a.closeMutex.Unlock()
+	if closed {
+		return nil, xerrors.New("agent is closed")
+	}
+	manifest := a.manifest.Load()
+	if network == nil || manifest == nil {
+		return nil, xerrors.New("agent is not connected to coder")
+	}
+
+	logger := a.logger.Named("peer").With(slog.F("peer_agent_id", agentID))
+	conn, err := tailnet.NewConn(&tailnet.Options{
+		Addresses:           []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+		DERPMap:             network.DERPMap(),
+		DERPForceWebSockets: manifest.DERPForceWebSockets,
+		Logger:              logger,
+		BlockEndpoints:      manifest.DisableDirectConnections,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("create tailnet: %w", err)
+	}
+
+	// The connection outlives the request that dialed it, so it is only
+	// canceled when the connection is closed.
+	coordinateCtx, cancel := context.WithCancel(context.Background())
+	closedCoordinator := make(chan struct{})
+	go func() {
+		defer close(closedCoordinator)
+		a.runPeerCoordinator(coordinateCtx, logger, agentID, conn)
+	}()
+
+	peerConn := codersdk.NewWorkspaceAgentConn(conn, codersdk.WorkspaceAgentConnOptions{
+		AgentID: agentID,
+		AgentIP: codersdk.WorkspaceAgentIP,
+		CloseFunc: func() error {
+			cancel()
+			<-closedCoordinator
+			return conn.Close()
+		},
+	})
+	reachableCtx, cancelReachable := context.WithTimeout(context.Background(), peerDialTimeout)
+	defer cancelReachable()
+	if !peerConn.AwaitReachable(reachableCtx) {
+		_ = peerConn.Close()
+		return nil, xerrors.Errorf("timed out waiting for peer to become reachable: %w", reachableCtx.Err())
+	}
+
+	a.peersMu.Lock()
+	defer a.peersMu.Unlock()
+	// closePeers may have run while dialing, in which case nothing would
+	// close this connection.
+	if a.isClosed() {
+		_ = peerConn.Close()
+		return nil, xerrors.New("agent is closed")
+	}
+	a.peers[agentID] = peerConn
+	return peerConn, nil
+}
+
+// evictPeer closes the connection to a peer that can no longer be used, so
+// that the next request dials it again.
+func (a *agent) evictPeer(agentID uuid.UUID, conn *codersdk.WorkspaceAgentConn) {
+	a.peersMu.Lock()
+	if a.peers[agentID] != conn {
+		a.peersMu.Unlock()
+		return
+	}
+	delete(a.peers, agentID)
+	a.peersMu.Unlock()
+	_ = conn.Close()
+}
+
+// runPeerCoordinator exchanges node updates with the peer through coderd
+// until the context is canceled.
+func (a *agent) runPeerCoordinator(ctx context.Context, logger slog.Logger, agentID uuid.UUID, conn *tailnet.Conn) {
+	for retrier := retry.New(50*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
+		coordinator, err := a.client.ListenPeer(ctx, agentID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Debug(ctx, "failed to dial peer coordinator", slog.Error(err))
+			continue
+		}
+		sendNodes, errChan := tailnet.ServeCoordinator(coordinator, func(nodes []*tailnet.Node) error {
+			return conn.UpdateNodes(nodes, false)
+		})
+		conn.SetNodeCallback(sendNodes)
+		select {
+		case <-ctx.Done():
+		case err = <-errChan:
+			logger.Debug(ctx, "peer coordinator exited", slog.Error(err))
+		}
+		_ = coordinator.Close()
+	}
+}
+
+// setPeersDERPMap keeps the DERP map of peer connections in sync with the
+// one of the agent.
+func (a *agent) setPeersDERPMap(derpMap *tailcfg.DERPMap) {
+	a.peersMu.Lock()
+	defer a.peersMu.Unlock()
+	for _, conn := range a.peers {
+		conn.SetDERPMap(derpMap)
+	}
+}
+
+func (a *agent) closePeers() {
+	a.peersMu.Lock()
+	defer a.peersMu.Unlock()
+	for id, conn := range a.peers {
+		_ = conn.Close()
+		delete(a.peers, id)
+	}
+}
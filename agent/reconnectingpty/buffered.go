@@ -171,8 +171,8 @@ func (rpty *bufferedReconnectingPTY) lifecycle(ctx context.Context, logger slog.
 	rpty.state.setState(StateDone, reasonErr)
 }
 
-func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, logger slog.Logger) error {
-	logger.Info(ctx, "attach to reconnecting pty")
+func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, readOnly bool, logger slog.Logger) error {
+	logger.Info(ctx, "attach to reconnecting pty", slog.F("read_only", readOnly))
 
 	// This will kill the heartbeat once we hit EOF or an error.
 	ctx, cancel := context.WithCancel(ctx)
@@ -196,16 +196,19 @@ func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string,
 
 	go heartbeat(ctx, rpty.timer, rpty.timeout)
 
-	// Resize the PTY to initial height + width.
-	err = rpty.ptty.Resize(height, width)
-	if err != nil {
-		// We can continue after this, it's not fatal!
-		logger.Warn(ctx, "reconnecting PTY initial resize failed, but will continue", slog.Error(err))
-		rpty.metrics.WithLabelValues("resize").Add(1)
+	// Resize the PTY to initial height + width.  Read-only connections watch
+	// the PTY at whatever size the writers use.
+	if !readOnly {
+		err = rpty.ptty.Resize(height, width)
+		if err != nil {
+			// We can continue after this, it's not fatal!
+			logger.Warn(ctx, "reconnecting PTY initial resize failed, but will continue", slog.Error(err))
+			rpty.metrics.WithLabelValues("resize").Add(1)
+		}
 	}
 
 	// Pipe conn -> pty and block.  pty -> conn is handled in newBuffered().
-	readConnLoop(ctx, conn, rpty.ptty, rpty.metrics, logger)
+	readConnLoop(ctx, conn, rpty.ptty, readOnly, rpty.metrics, logger)
 	return nil
 }
 
@@ -42,8 +42,9 @@ type ReconnectingPTY interface {
 	// history, then blocks until EOF, an error, or the context's end.  The
 	// connection is expected to send JSON-encoded messages and accept raw output
 	// from the ptty.  If the context ends or the process dies the connection will
-	// be detached.
-	Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, logger slog.Logger) error
+	// be detached.  Read-only connections receive output but their input and
+	// resizes are discarded.
+	Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, readOnly bool, logger slog.Logger) error
 	// Wait waits for the reconnecting pty to close.  The underlying process might
 	// still be exiting.
 	Wait()
@@ -193,8 +194,9 @@ func (s *ptyState) waitForStateOrContext(ctx context.Context, state State) (Stat
 }
 
 // readConnLoop reads messages from conn and writes to ptty as needed.  Blocks
-// until EOF or an error writing to ptty or reading from conn.
-func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *prometheus.CounterVec, logger slog.Logger) {
+// until EOF or an error writing to ptty or reading from conn.  Messages from
+// read-only connections are still read to detect EOF but are discarded.
+func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, readOnly bool, metrics *prometheus.CounterVec, logger slog.Logger) {
 	decoder := json.NewDecoder(conn)
 	var req codersdk.ReconnectingPTYRequest
 	for {
@@ -206,6 +208,9 @@ func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *
 			logger.Warn(ctx, "reconnecting pty failed with read error", slog.Error(err))
 			return
 		}
+		if readOnly {
+			continue
+		}
 		_, err = ptty.InputWriter().Write([]byte(req.Data))
 		if err != nil {
 			logger.Warn(ctx, "reconnecting pty failed with write error", slog.Error(err))
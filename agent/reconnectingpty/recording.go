@@ -0,0 +1,76 @@
+package reconnectingpty
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+
+	"github.com/coder/coder/v2/agent/agentrecord"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// RecordConn wraps a connection to a reconnecting PTY so that the output it
+// receives is recorded. Unless the connection is read-only, its input and
+// resizes are recorded too.
+func RecordConn(conn net.Conn, recorder *agentrecord.Recorder, readOnly bool) net.Conn {
+	rc := &recordedConn{
+		Conn:   conn,
+		output: recorder.OutputWriter(),
+	}
+	if readOnly {
+		return rc
+	}
+
+	// The input is a stream of JSON messages. Decode a copy of it so the
+	// session itself isn't affected by what happens here.
+	pr, pw := io.Pipe()
+	rc.input = pw
+	go func() {
+		input := recorder.InputWriter()
+		decoder := json.NewDecoder(pr)
+		for {
+			var req codersdk.ReconnectingPTYRequest
+			if err := decoder.Decode(&req); err != nil {
+				// Keep draining so reads from the connection never block.
+				_, _ = io.Copy(io.Discard, pr)
+				return
+			}
+			if req.Data != "" {
+				_, _ = input.Write([]byte(req.Data))
+			}
+			if req.Height > 0 && req.Width > 0 {
+				recorder.Resize(req.Width, req.Height)
+			}
+		}
+	}()
+	return rc
+}
+
+type recordedConn struct {
+	net.Conn
+	output io.Writer
+	input  *io.PipeWriter
+}
+
+func (c *recordedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 && c.input != nil {
+		_, _ = c.input.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *recordedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		_, _ = c.output.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *recordedConn) Close() error {
+	if c.input != nil {
+		_ = c.input.Close()
+	}
+	return c.Conn.Close()
+}
@@ -148,8 +148,8 @@ func (rpty *screenReconnectingPTY) lifecycle(ctx context.Context, logger slog.Lo
 	rpty.state.setState(StateDone, reasonErr)
 }
 
-func (rpty *screenReconnectingPTY) Attach(ctx context.Context, _ string, conn net.Conn, height, width uint16, logger slog.Logger) error {
-	logger.Info(ctx, "attach to reconnecting pty")
+func (rpty *screenReconnectingPTY) Attach(ctx context.Context, _ string, conn net.Conn, height, width uint16, readOnly bool, logger slog.Logger) error {
+	logger.Info(ctx, "attach to reconnecting pty", slog.F("read_only", readOnly))
 
 	// This will kill the heartbeat once we hit EOF or an error.
 	ctx, cancel := context.WithCancel(ctx)
@@ -187,8 +187,9 @@ func (rpty *screenReconnectingPTY) Attach(ctx context.Context, _ string, conn ne
 		}
 	}()
 
-	// Pipe conn -> pty and block.
-	readConnLoop(ctx, conn, ptty, rpty.metrics, logger)
+	// Pipe conn -> pty and block.  Each connection has its own screen client so
+	// read-only connections only need to drop their input.
+	readConnLoop(ctx, conn, ptty, readOnly, rpty.metrics, logger)
 	return nil
 }
 
@@ -0,0 +1,290 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentsocket"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// startSocketServer serves the agent socket API to processes running in the
+// workspace. Failing to listen isn't fatal, the agent works without it.
+func (a *agent) startSocketServer(ctx context.Context) {
+	if a.socketPath == "" {
+		return
+	}
+	listener, err := agentsocket.Listen(a.socketPath)
+	if err != nil {
+		a.logger.Warn(ctx, "unable to listen on agent socket", slog.F("path", a.socketPath), slog.Error(err))
+		return
+	}
+	//nolint:gosec // The socket is only reachable by the workspace user.
+	a.socketServer = &http.Server{
+		Handler: a.socketHandler(),
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	go func() {
+		err := a.socketServer.Serve(listener)
+		if err != nil && !xerrors.Is(err, http.ErrServerClosed) {
+			a.logger.Warn(ctx, "agent socket server exited", slog.Error(err))
+		}
+	}()
+	a.logger.Debug(ctx, "serving agent socket", slog.F("path", a.socketPath))
+}
+
+func (a *agent) socketHandler() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/v0/status", a.handleSocketStatus)
+	r.Post("/api/v0/logs", a.handleSocketLogs)
+	r.Post("/api/v0/metadata/{key}", a.handleSocketMetadata)
+	r.Post("/api/v0/autostop-inhibitors", a.handleSocketInhibitAutostop)
+	r.Get("/api/v0/peers", a.handleSocketPeers)
+	r.Get("/api/v0/peers/{peer}/dial/{port}", a.handleSocketDialPeer)
+	return r
+}
+
+func (a *agent) handleSocketStatus(rw http.ResponseWriter, r *http.Request) {
+	a.lifecycleMu.RLock()
+	lifecycle := a.lifecycleStates[len(a.lifecycleStates)-1].State
+	a.lifecycleMu.RUnlock()
+
+	status := agentsocket.Status{
+		Lifecycle:          lifecycle,
+		Version:            buildinfo.Version(),
+		MetadataKeys:       []string{},
+		AutostopInhibitors: a.listAutostopInhibitors(),
+	}
+	// The manifest is nil until the agent first connects to coderd.
+	if manifest := a.manifest.Load(); manifest != nil {
+		status.AgentID = manifest.AgentID
+		status.AgentName = manifest.AgentName
+		status.WorkspaceID = manifest.WorkspaceID
+		status.WorkspaceName = manifest.WorkspaceName
+		status.OwnerName = manifest.OwnerName
+		status.Directory = manifest.Directory
+		for _, md := range manifest.Metadata {
+			status.MetadataKeys = append(status.MetadataKeys, md.Key)
+		}
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, status)
+}
+
+func (a *agent) handleSocketLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsocket.PostLogsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if len(req.Logs) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "No logs provided.",
+		})
+		return
+	}
+	for i, log := range req.Logs {
+		if log.Level == "" {
+			log.Level = codersdk.LogLevelInfo
+		}
+		if log.CreatedAt.IsZero() {
+			log.CreatedAt = time.Now()
+		}
+		req.Logs[i] = log
+	}
+
+	err := a.client.PatchLogs(ctx, agentsdk.PatchLogs{Logs: req.Logs})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to send logs to Coder.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Logs sent.",
+	})
+}
+
+func (a *agent) handleSocketMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := chi.URLParam(r, "key")
+	var req agentsocket.PostMetadataRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// coderd ignores values for keys the template doesn't define, so reject
+	// them here to give the caller a useful error.
+	manifest := a.manifest.Load()
+	if manifest == nil || !slices.ContainsFunc(manifest.Metadata, func(md codersdk.WorkspaceAgentMetadataDescription) bool {
+		return md.Key == key
+	}) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Metadata key %q is not defined in the template.", key),
+		})
+		return
+	}
+
+	err := a.client.PostMetadata(ctx, key, agentsdk.PostMetadataRequest{
+		CollectedAt: time.Now(),
+		Value:       req.Value,
+		Error:       req.Error,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to send metadata to Coder.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Metadata updated.",
+	})
+}
+
+// handleSocketInhibitAutostop holds an autostop inhibitor for as long as the
+// caller keeps the request open.
+func (a *agent) handleSocketInhibitAutostop(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsocket.InhibitAutostopRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Reason == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A reason is required to inhibit autostop.",
+		})
+		return
+	}
+
+	inhibitor, release := a.addAutostopInhibitor(req.Reason)
+	defer release()
+	a.logger.Debug(ctx, "autostop inhibitor held", slog.F("inhibitor", inhibitor))
+
+	httpapi.Write(ctx, rw, http.StatusOK, inhibitor)
+	if flusher, ok := rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	<-ctx.Done()
+	a.logger.Debug(context.Background(), "autostop inhibitor released", slog.F("inhibitor", inhibitor))
+}
+
+func (a *agent) handleSocketPeers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	peers, err := a.client.Peers(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to list peers.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, peers)
+}
+
+// handleSocketDialPeer dials a TCP port of another workspace agent and
+// upgrades the request to a raw connection to it.
+func (a *agent) handleSocketDialPeer(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	port, err := strconv.ParseUint(chi.URLParam(r, "port"), 10, 16)
+	if err != nil || port == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid port %q.", chi.URLParam(r, "port")),
+		})
+		return
+	}
+	if !strings.EqualFold(r.Header.Get("Upgrade"), agentsocket.UpgradeTCP) {
+		httpapi.Write(ctx, rw, http.StatusUpgradeRequired, codersdk.Response{
+			Message: fmt.Sprintf("The request must be upgraded to %q.", agentsocket.UpgradeTCP),
+		})
+		return
+	}
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "The connection cannot be upgraded.",
+		})
+		return
+	}
+
+	// The peer name may contain an escaped "/" between owner and workspace.
+	name, err := url.PathUnescape(chi.URLParam(r, "peer"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid peer name.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	peer, err := a.resolvePeer(ctx, name)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Peer not found.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	peerConn, err := a.dialPeer(dialCtx, peer.AgentID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to connect to peer.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	conn, err := peerConn.DialContext(dialCtx, "tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		// The peer may have restarted with a new tailnet node, dial it
+		// again next time.
+		if !peerConn.AwaitReachable(dialCtx) {
+			a.evictPeer(peer.AgentID, peerConn)
+		}
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: fmt.Sprintf("Failed to dial port %d of peer.", port),
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer conn.Close()
+
+	clientConn, buf, err := hijacker.Hijack()
+	if err != nil {
+		a.logger.Warn(ctx, "hijack peer dial connection", slog.Error(err))
+		return
+	}
+	defer clientConn.Close()
+	_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: " + agentsocket.UpgradeTCP + "\r\n\r\n")
+	err = buf.Flush()
+	if err != nil {
+		return
+	}
+	// Forward anything the client sent after the request.
+	if buffered := buf.Reader.Buffered(); buffered > 0 {
+		data, _ := buf.Reader.Peek(buffered)
+		_, err = conn.Write(data)
+		if err != nil {
+			return
+		}
+	}
+	a.logger.Debug(ctx, "dialed peer", slog.F("peer_agent_id", peer.AgentID), slog.F("port", port))
+	agentssh.Bicopy(context.Background(), clientConn, conn)
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentupdate"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// Updater replaces the agent binary when its version differs from coderd.
+// It is implemented by *agentupdate.Updater.
+type Updater interface {
+	// Check returns the version of coderd, and whether the agent must be
+	// updated to it.
+	Check(ctx context.Context) (serverVersion string, needsUpdate bool, err error)
+	Download(ctx context.Context, serverVersion string) (string, error)
+	Exec(path string, state agentupdate.ExecState) error
+}
+
+// checkForUpdate replaces the agent with the binary served by coderd when
+// their versions differ. The update waits for the startup scripts to finish
+// and for sessions and autostop inhibitors to end, since they don't survive
+// the agent process being replaced.
+func (a *agent) checkForUpdate(ctx context.Context, manifest agentsdk.Manifest) {
+	if a.updater == nil {
+		return
+	}
+	if manifest.DisableAutoUpdate {
+		a.reportUpdateState(ctx, codersdk.WorkspaceAgentUpdateStateDisabled, "")
+		return
+	}
+
+	serverVersion, needsUpdate, err := a.updater.Check(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		a.logger.Warn(ctx, "check for agent update", slog.Error(err))
+		a.reportUpdateState(ctx, codersdk.WorkspaceAgentUpdateStateFailed, err.Error())
+		return
+	}
+	if !needsUpdate {
+		state := codersdk.WorkspaceAgentUpdateStateUpToDate
+		if a.execState != nil {
+			state = codersdk.WorkspaceAgentUpdateStateUpdated
+		}
+		a.reportUpdateState(ctx, state, "")
+		return
+	}
+
+	version := buildinfo.Version()
+	logger := a.logger.With(slog.F("version", version), slog.F("server_version", serverVersion))
+	ticker := time.NewTicker(a.updateRetryInterval)
+	defer ticker.Stop()
+	for {
+		reason := a.updateBlockedReason()
+		if reason == "" {
+			break
+		}
+		logger.Info(ctx, "postponing agent update", slog.F("reason", reason))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	logger.Info(ctx, "updating agent")
+	a.reportUpdateState(ctx, codersdk.WorkspaceAgentUpdateStateUpdating, "")
+	path, err := a.updater.Download(ctx, serverVersion)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Error(ctx, "download agent binary", slog.Error(err))
+		a.reportUpdateState(ctx, codersdk.WorkspaceAgentUpdateStateFailed, err.Error())
+		return
+	}
+
+	a.lifecycleMu.RLock()
+	state := agentupdate.ExecState{
+		PreviousVersion: version,
+		Lifecycle:       a.lifecycleStates[len(a.lifecycleStates)-1].State,
+	}
+	a.lifecycleMu.RUnlock()
+	a.closeMutex.Lock()
+	if a.network != nil {
+		state.NodePrivateKey = a.network.NodePrivateKey()
+	}
+	a.closeMutex.Unlock()
+
+	// Exec only returns if the agent couldn't be replaced.
+	err = a.updater.Exec(path, state)
+	logger.Error(ctx, "execute updated agent", slog.Error(err))
+	a.reportUpdateState(ctx, codersdk.WorkspaceAgentUpdateStateFailed, err.Error())
+}
+
+// updateBlockedReason returns why the agent can't be replaced right now, or
+// an empty string if it can.
+func (a *agent) updateBlockedReason() string {
+	a.lifecycleMu.RLock()
+	lifecycle := a.lifecycleStates[len(a.lifecycleStates)-1].State
+	a.lifecycleMu.RUnlock()
+	if lifecycle.Starting() {
+		return "startup scripts are running"
+	}
+	if lifecycle.ShuttingDown() {
+		return "agent is shutting down"
+	}
+
+	sshStats := a.sshServer.ConnStats()
+	if sshStats.Sessions+sshStats.VSCode+sshStats.JetBrains+a.connCountReconnectingPTY.Load() > 0 {
+		return "sessions are active"
+	}
+	// Inhibitors are held through connections to the agent socket, which
+	// are dropped when the agent is replaced.
+	if len(a.listAutostopInhibitors()) > 0 {
+		return "autostop is inhibited"
+	}
+	return ""
+}
+
+// startedBeforeUpdate returns true if the agent that updated itself already
+// ran the startup scripts.
+func (a *agent) startedBeforeUpdate() bool {
+	if a.execState == nil {
+		return false
+	}
+	switch a.execState.Lifecycle {
+	case codersdk.WorkspaceAgentLifecycleStartTimeout, codersdk.WorkspaceAgentLifecycleStartError, codersdk.WorkspaceAgentLifecycleReady:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *agent) reportUpdateState(ctx context.Context, state codersdk.WorkspaceAgentUpdateState, updateErr string) {
+	err := a.client.PostUpdateState(ctx, agentsdk.PostUpdateStateRequest{
+		State: state,
+		Error: updateErr,
+	})
+	if err != nil && ctx.Err() == nil {
+		a.logger.Warn(ctx, "report update state", slog.F("state", state), slog.Error(err))
+	}
+}
@@ -96,4 +96,11 @@ func TestBuildInfo(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("IsDevVersion", func(t *testing.T) {
+		t.Parallel()
+		require.True(t, buildinfo.IsDevVersion("v0.0.0-devel+123abac"))
+		require.False(t, buildinfo.IsDevVersion("v1.1.1-devel+123abac"))
+		require.False(t, buildinfo.IsDevVersion("v1.2.3"))
+	})
 }
@@ -29,6 +29,8 @@ import (
 	"cdr.dev/slog/sloggers/slogjson"
 	"cdr.dev/slog/sloggers/slogstackdriver"
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentsocket"
+	"github.com/coder/coder/v2/agent/agentupdate"
 	"github.com/coder/coder/v2/agent/reaper"
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/clibase"
@@ -49,12 +51,20 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		slogHumanPath       string
 		slogJSONPath        string
 		slogStackdriverPath string
+		socketPath          string
 	)
 	cmd := &clibase.Cmd{
 		Use:   "agent",
 		Short: `Starts the Coder workspace agent.`,
 		// This command isn't useful to manually execute.
 		Hidden: true,
+		Children: []*clibase.Cmd{
+			r.agentStatus(&socketPath),
+			r.agentLog(&socketPath),
+			r.agentMetadata(&socketPath),
+			r.agentPeers(&socketPath),
+			r.agentPortForward(&socketPath),
+		},
 		Handler: func(inv *clibase.Invocation) error {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
@@ -167,6 +177,16 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				slog.F("auth", auth),
 				slog.F("version", version),
 			)
+			// The exec state is only set when the agent was started by an
+			// agent that updated itself.
+			execState, err := agentupdate.ReadExecState()
+			if err != nil {
+				logger.Warn(ctx, "read agent exec state", slog.Error(err))
+			}
+			if execState != nil {
+				logger.Info(ctx, "agent was updated", slog.F("previous_version", execState.PreviousVersion))
+			}
+
 			client := agentsdk.New(r.agentURL)
 			client.SDK.SetLogger(logger)
 			// Set a reasonable timeout so requests can't hang forever!
@@ -253,6 +273,15 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				return xerrors.Errorf("add executable to $PATH: %w", err)
 			}
 
+			var updater agent.Updater
+			if runtime.GOOS != "windows" {
+				updater = agentupdate.New(agentupdate.Options{
+					Logger:         logger.Named("update"),
+					Client:         client.SDK,
+					ExecutablePath: executablePath,
+				})
+			}
+
 			prometheusRegistry := prometheus.NewRegistry()
 			subsystemsRaw := inv.Environ.Get(agent.EnvAgentSubsystem)
 			subsystems := []codersdk.AgentSubsystem{}
@@ -289,6 +318,9 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				IgnorePorts:   ignorePorts,
 				SSHMaxTimeout: sshMaxTimeout,
 				Subsystems:    subsystems,
+				SocketPath:    socketPath,
+				Updater:       updater,
+				ExecState:     execState,
 
 				PrometheusRegistry: prometheusRegistry,
 			})
@@ -362,6 +394,13 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Value:       clibase.StringOf(&debugAddress),
 			Description: "The bind address to serve a debug HTTP server.",
 		},
+		{
+			Flag:        "socket-path",
+			Default:     agentsocket.DefaultPath(),
+			Env:         agentsocket.EnvSocketPath,
+			Description: "The path of the Unix socket processes in the workspace use to talk to the agent. Set to an empty string to disable the socket.",
+			Value:       clibase.StringOf(&socketPath),
+		},
 		{
 			Name:        "Human Log Location",
 			Description: "Output human-readable logs to a given file.",
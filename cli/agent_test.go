@@ -1,7 +1,9 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,12 +16,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentsocket"
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestWorkspaceAgent(t *testing.T) {
@@ -240,6 +244,85 @@ func TestWorkspaceAgent(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Socket", func(t *testing.T) {
+		t.Parallel()
+
+		authToken := uuid.NewString()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		apply := echo.ProvisionApplyWithAgent(authToken)
+		apply[0].GetApply().Resources[0].Agents[0].Metadata = []*proto.Agent_Metadata{{
+			Key:         "branch",
+			DisplayName: "Branch",
+		}}
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: apply,
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		socketPath := filepath.Join(t.TempDir(), "agent.sock")
+		inv, _ := clitest.New(t,
+			"agent",
+			"--auth", "token",
+			"--agent-token", authToken,
+			"--agent-url", client.URL.String(),
+			"--log-dir", t.TempDir(),
+			"--socket-path", socketPath,
+		)
+		clitest.Start(t, inv)
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		agentID := resources[0].Agents[0].ID
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, _ = clitest.New(t, "agent", "status", "--output", "json")
+		inv.Environ.Set(agentsocket.EnvSocketPath, socketPath)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		var status agentsocket.Status
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &status))
+		require.Equal(t, agentID, status.AgentID)
+		require.Equal(t, workspace.Name, status.WorkspaceName)
+		require.Equal(t, coderdtest.FirstUserParams.Username, status.OwnerName)
+
+		inv, _ = clitest.New(t, "agent", "log", "--socket-path", socketPath, "--level", "warn")
+		inv.Stdin = strings.NewReader("first\nsecond\n")
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		logs, closer, err := client.WorkspaceAgentLogsAfter(ctx, agentID, 0, false)
+		require.NoError(t, err)
+		defer closer.Close()
+		got := <-logs
+		require.Len(t, got, 2)
+		require.Equal(t, "first", got[0].Output)
+		require.Equal(t, codersdk.LogLevelWarn, got[1].Level)
+
+		inv, _ = clitest.New(t, "agent", "metadata", "set", "--socket-path", socketPath, "branch", "main")
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		metadata, errs := client.WatchWorkspaceAgentMetadata(ctx, agentID)
+		require.Eventually(t, func() bool {
+			select {
+			case md := <-metadata:
+				return len(md) == 1 && md[0].Result.Value == "main"
+			case err := <-errs:
+				require.NoError(t, err)
+				return false
+			}
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		inv, _ = clitest.New(t, "agent", "metadata", "set", "--socket-path", socketPath, "unknown", "main")
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "not defined in the template")
+	})
+
 	t.Run("PostStartup", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentsocket"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+type agentPeerRow struct {
+	agentsdk.Peer `table:"-"`
+
+	Name      string `json:"-" table:"name,default_sort"`
+	Workspace string `json:"-" table:"workspace"`
+	Agent     string `json:"-" table:"agent"`
+	Owner     string `json:"-" table:"owner"`
+	Status    string `json:"-" table:"status"`
+}
+
+func (*RootCmd) agentPeers(socketPath *string) *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(
+			cliui.TableFormat([]agentPeerRow{}, []string{"name", "status"}),
+			func(data any) (any, error) {
+				peers, ok := data.([]agentsdk.Peer)
+				if !ok {
+					return nil, xerrors.Errorf("expected type %T, got %T", peers, data)
+				}
+				rows := make([]agentPeerRow, 0, len(peers))
+				for _, peer := range peers {
+					rows = append(rows, agentPeerRow{
+						Peer:      peer,
+						Name:      fmt.Sprintf("%s/%s.%s", peer.OwnerName, peer.WorkspaceName, peer.AgentName),
+						Workspace: peer.WorkspaceName,
+						Agent:     peer.AgentName,
+						Owner:     peer.OwnerName,
+						Status:    string(peer.Status),
+					})
+				}
+				return rows, nil
+			},
+		),
+		cliui.JSONFormat(),
+	)
+	cmd := &clibase.Cmd{
+		Use:        "peers",
+		Short:      "List the workspace agents this agent can connect to",
+		Long:       "Peers are only available if the deployment enables workspace networking.",
+		Middleware: clibase.RequireNArgs(0),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			peers, err := agentsocket.NewClient(*socketPath).Peers(ctx)
+			if err != nil {
+				return xerrors.Errorf("list peers: %w", err)
+			}
+			out, err := formatter.Format(ctx, peers)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (*RootCmd) agentPortForward(socketPath *string) *clibase.Cmd {
+	var tcpForwards []string
+	cmd := &clibase.Cmd{
+		Use:   "port-forward <peer>",
+		Short: "Forward ports of another workspace agent to this workspace",
+		Long: "The peer is named [owner/]workspace[.agent], see \"coder agent peers\".\n" + formatExamples(
+			example{
+				Description: "Forward port 5432 of the \"db\" workspace to port 5432 of this workspace",
+				Command:     "coder agent port-forward db --tcp 5432",
+			},
+			example{
+				Description: "Forward port 8080 of the \"api\" agent of the \"backend\" workspace to port 3000",
+				Command:     "coder agent port-forward backend.api --tcp 3000:8080",
+			},
+		),
+		Middleware: clibase.RequireNArgs(1),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			specs, err := parsePortForwards(tcpForwards, nil)
+			if err != nil {
+				return xerrors.Errorf("parse port-forward specs: %w", err)
+			}
+			if len(specs) == 0 {
+				err = inv.Command.HelpHandler(inv)
+				if err != nil {
+					return xerrors.Errorf("generate help output: %w", err)
+				}
+				return xerrors.New("no port-forwards requested")
+			}
+
+			client := agentsocket.NewClient(*socketPath)
+			peer := inv.Args[0]
+			var (
+				wg                = new(sync.WaitGroup)
+				listeners         = make([]net.Listener, 0, len(specs))
+				closeAllListeners = func() {
+					for _, l := range listeners {
+						_ = l.Close()
+					}
+				}
+			)
+			defer closeAllListeners()
+
+			for _, spec := range specs {
+				dialAddress, err := netip.ParseAddrPort(spec.dialAddress)
+				if err != nil {
+					return xerrors.Errorf("parse %q: %w", spec.dialAddress, err)
+				}
+				l, err := net.Listen(spec.listenNetwork, spec.listenAddress)
+				if err != nil {
+					return xerrors.Errorf("listen '%v://%v': %w", spec.listenNetwork, spec.listenAddress, err)
+				}
+				listeners = append(listeners, l)
+				_, _ = fmt.Fprintf(inv.Stderr, "Forwarding '%v://%v' locally to port %d of %s\n", spec.listenNetwork, spec.listenAddress, dialAddress.Port(), peer)
+
+				wg.Add(1)
+				go func(l net.Listener, port uint16) {
+					defer wg.Done()
+					for {
+						netConn, err := l.Accept()
+						if err != nil {
+							if !xerrors.Is(err, net.ErrClosed) {
+								_, _ = fmt.Fprintf(inv.Stderr, "Error accepting connection from '%v': %v\n", l.Addr(), err)
+							}
+							return
+						}
+						go func() {
+							defer netConn.Close()
+							remoteConn, err := client.DialPeer(ctx, peer, port)
+							if err != nil {
+								_, _ = fmt.Fprintf(inv.Stderr, "Failed to dial port %d of %s: %s\n", port, peer, err)
+								return
+							}
+							defer remoteConn.Close()
+							agentssh.Bicopy(ctx, netConn, remoteConn)
+						}()
+					}
+				}(l, dialAddress.Port())
+			}
+
+			var closeErr error
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				sigs := make(chan os.Signal, 1)
+				signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+				select {
+				case <-ctx.Done():
+					closeErr = ctx.Err()
+				case <-sigs:
+					_, _ = fmt.Fprintln(inv.Stderr, "\nReceived signal, closing all listeners and active connections")
+				}
+
+				cancel()
+				closeAllListeners()
+			}()
+
+			_, _ = fmt.Fprintln(inv.Stderr, "Ready!")
+			wg.Wait()
+			return closeErr
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "tcp",
+			FlagShorthand: "p",
+			Description:   "Forward TCP port(s) of the peer to this workspace.",
+			Value:         clibase.StringArrayOf(&tcpForwards),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentsocket"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// The agent helpers talk to the agent running the workspace over its socket.
+// They are children of the agent command so they share its --socket-path
+// option, which the agent sets in workspace sessions.
+
+type agentStatusRow struct {
+	agentsocket.Status `table:"-"`
+
+	Workspace string `json:"-" table:"workspace,default_sort"`
+	Agent     string `json:"-" table:"agent"`
+	Owner     string `json:"-" table:"owner"`
+	Lifecycle string `json:"-" table:"lifecycle"`
+	Version   string `json:"-" table:"version"`
+	Directory string `json:"-" table:"directory"`
+}
+
+func (*RootCmd) agentStatus(socketPath *string) *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(
+			cliui.TableFormat([]agentStatusRow{}, []string{"workspace", "agent", "owner", "lifecycle", "version"}),
+			func(data any) (any, error) {
+				status, ok := data.(agentsocket.Status)
+				if !ok {
+					return nil, xerrors.Errorf("expected type %T, got %T", status, data)
+				}
+				return []agentStatusRow{{
+					Status:    status,
+					Workspace: status.WorkspaceName,
+					Agent:     status.AgentName,
+					Owner:     status.OwnerName,
+					Lifecycle: string(status.Lifecycle),
+					Version:   status.Version,
+					Directory: status.Directory,
+				}}, nil
+			},
+		),
+		cliui.JSONFormat(),
+	)
+	cmd := &clibase.Cmd{
+		Use:        "status",
+		Short:      "Show the status of the agent running this workspace",
+		Middleware: clibase.RequireNArgs(0),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			status, err := agentsocket.NewClient(*socketPath).Status(ctx)
+			if err != nil {
+				return xerrors.Errorf("get agent status: %w", err)
+			}
+			out, err := formatter.Format(ctx, status)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (*RootCmd) agentLog(socketPath *string) *clibase.Cmd {
+	var level string
+	cmd := &clibase.Cmd{
+		Use:   "log [message]",
+		Short: "Append a line to the workspace agent logs",
+		Long: "Lines are read from stdin if no message is given. They show up in the \"External\" log source of the agent.\n" + formatExamples(
+			example{
+				Description: "Log a message",
+				Command:     `coder agent log "Dependencies installed"`,
+			},
+			example{
+				Description: "Log the output of a command as errors",
+				Command:     "make 2>&1 | coder agent log --level error",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			client := agentsocket.NewClient(*socketPath)
+			send := func(lines []string) error {
+				logs := make([]agentsdk.Log, 0, len(lines))
+				for _, line := range lines {
+					logs = append(logs, agentsdk.Log{
+						CreatedAt: time.Now(),
+						Output:    line,
+						Level:     codersdk.LogLevel(level),
+					})
+				}
+				err := client.PostLogs(ctx, agentsocket.PostLogsRequest{Logs: logs})
+				if err != nil {
+					return xerrors.Errorf("send logs: %w", err)
+				}
+				return nil
+			}
+			if len(inv.Args) > 0 {
+				return send([]string{strings.Join(inv.Args, " ")})
+			}
+
+			// Batch lines to avoid a request per line of a long output.
+			const batchSize = 100
+			lines := make([]string, 0, batchSize)
+			scanner := bufio.NewScanner(inv.Stdin)
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+				if len(lines) == batchSize {
+					if err := send(lines); err != nil {
+						return err
+					}
+					lines = lines[:0]
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return xerrors.Errorf("read stdin: %w", err)
+			}
+			if len(lines) == 0 {
+				return nil
+			}
+			return send(lines)
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "level",
+			Description: "The level of the log lines.",
+			Default:     string(codersdk.LogLevelInfo),
+			Value: clibase.EnumOf(&level,
+				string(codersdk.LogLevelTrace),
+				string(codersdk.LogLevelDebug),
+				string(codersdk.LogLevelInfo),
+				string(codersdk.LogLevelWarn),
+				string(codersdk.LogLevelError),
+			),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) agentMetadata(socketPath *string) *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "metadata",
+		Short: "Report workspace agent metadata",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.agentMetadataSet(socketPath),
+		},
+	}
+}
+
+func (*RootCmd) agentMetadataSet(socketPath *string) *clibase.Cmd {
+	var errorMessage string
+	cmd := &clibase.Cmd{
+		Use:   "set <key> [value]",
+		Short: "Set the value of a metadata item defined in the template",
+		Long: "The value shows up in the dashboard and replaces the last value collected by the script of the item, if any.\n" + formatExamples(
+			example{
+				Description: "Report the branch that is checked out",
+				Command:     `coder agent metadata set branch "$(git branch --show-current)"`,
+			},
+		),
+		Middleware: clibase.RequireRangeArgs(1, 2),
+		Handler: func(inv *clibase.Invocation) error {
+			req := agentsocket.PostMetadataRequest{
+				Error: errorMessage,
+			}
+			if len(inv.Args) > 1 {
+				req.Value = inv.Args[1]
+			}
+			err := agentsocket.NewClient(*socketPath).PostMetadata(inv.Context(), inv.Args[0], req)
+			if err != nil {
+				return xerrors.Errorf("set metadata: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "error",
+			Description: "Report an error instead of a value.",
+			Value:       clibase.StringOf(&errorMessage),
+		},
+	}
+	return cmd
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/agent/agentsocket"
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/config"
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -142,6 +143,9 @@ func NormalizeGoldenFile(t *testing.T, byt []byte) []byte {
 
 	byt = bytes.ReplaceAll(byt, []byte(codersdk.DefaultCacheDir()), []byte("[cache dir]"))
 
+	// The agent socket is in a directory of the user running the tests.
+	byt = bytes.ReplaceAll(byt, []byte(agentsocket.DefaultPath()), []byte("[agent socket]"))
+
 	// The home directory changes depending on the test environment.
 	byt = bytes.ReplaceAll(byt, []byte(homeDir), []byte("~"))
 	for _, r := range []struct {
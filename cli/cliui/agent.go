@@ -2,6 +2,7 @@ package cliui
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
@@ -196,6 +197,9 @@ func Agent(ctx context.Context, writer io.Writer, agentID uuid.UUID, opts AgentO
 				sw.Fail(stage, agent.ReadyAt.Sub(*agent.StartedAt))
 				// Use zero time (omitted) to separate these from the startup logs.
 				sw.Log(time.Time{}, codersdk.LogLevelWarn, "Warning: The startup script exited with an error and your workspace may be incomplete.")
+				for _, line := range scriptFailures(agent) {
+					sw.Log(time.Time{}, codersdk.LogLevelWarn, line)
+				}
 				sw.Log(time.Time{}, codersdk.LogLevelWarn, troubleshootingMessage(agent, "https://coder.com/docs/v2/latest/templates#startup-script-exited-with-an-error"))
 			default:
 				switch {
@@ -235,6 +239,32 @@ func Agent(ctx context.Context, writer io.Writer, agentID uuid.UUID, opts AgentO
 	}
 }
 
+// scriptFailures describes the startup scripts of the agent that did not
+// complete successfully, in the order they are declared.
+func scriptFailures(agent codersdk.WorkspaceAgent) []string {
+	names := make(map[uuid.UUID]string, len(agent.LogSources))
+	for _, source := range agent.LogSources {
+		names[source.ID] = source.DisplayName
+	}
+	var lines []string
+	for _, script := range agent.Scripts {
+		if !script.RunOnStart {
+			continue
+		}
+		name, ok := names[script.LogSourceID]
+		if !ok {
+			name = script.LogSourceID.String()
+		}
+		switch script.Status {
+		case codersdk.WorkspaceAgentScriptStatusExitFailure:
+			lines = append(lines, fmt.Sprintf("  %s: exited with code %d", name, script.ExitCode))
+		case codersdk.WorkspaceAgentScriptStatusTimedOut:
+			lines = append(lines, fmt.Sprintf("  %s: timed out after %s", name, script.Timeout))
+		}
+	}
+	return lines
+}
+
 func troubleshootingMessage(agent codersdk.WorkspaceAgent, url string) string {
 	m := "For more information and troubleshooting, see " + url
 	if agent.TroubleshootingURL != "" {
@@ -188,6 +188,43 @@ func TestAgent(t *testing.T) {
 				"For more information and troubleshooting, see",
 			},
 		},
+		{
+			name: "Startup script failures are listed",
+			opts: cliui.AgentOptions{
+				FetchInterval: time.Millisecond,
+				Wait:          true,
+			},
+			iter: []func(context.Context, *codersdk.WorkspaceAgent, chan []codersdk.WorkspaceAgentLog) error{
+				func(_ context.Context, agent *codersdk.WorkspaceAgent, logs chan []codersdk.WorkspaceAgentLog) error {
+					sources := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+					agent.Status = codersdk.WorkspaceAgentConnected
+					agent.FirstConnectedAt = ptr.Ref(time.Now())
+					agent.StartedAt = ptr.Ref(time.Now())
+					agent.LifecycleState = codersdk.WorkspaceAgentLifecycleStartError
+					agent.ReadyAt = ptr.Ref(time.Now())
+					agent.LogSources = []codersdk.WorkspaceAgentLogSource{
+						{ID: sources[0], DisplayName: "Dotfiles"},
+						{ID: sources[1], DisplayName: "Install"},
+						{ID: sources[2], DisplayName: "Seed"},
+					}
+					agent.Scripts = []codersdk.WorkspaceAgentScript{
+						{LogSourceID: sources[0], RunOnStart: true, Status: codersdk.WorkspaceAgentScriptStatusOK},
+						{LogSourceID: sources[1], RunOnStart: true, Status: codersdk.WorkspaceAgentScriptStatusExitFailure, ExitCode: 2},
+						{LogSourceID: sources[2], RunOnStart: true, Status: codersdk.WorkspaceAgentScriptStatusTimedOut, Timeout: time.Minute},
+					}
+					close(logs)
+					return nil
+				},
+			},
+			want: []string{
+				"⧗ Running workspace agent startup script",
+				"✘ Running workspace agent startup script",
+				"Warning: The startup script exited with an error and your workspace may be incomplete.",
+				"Install: exited with code 2",
+				"Seed: timed out after 1m0s",
+				"For more information and troubleshooting, see",
+			},
+		},
 		{
 			name: "Error when shutting down",
 			opts: cliui.AgentOptions{
@@ -5,6 +5,7 @@ import (
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"golang.org/x/mod/semver"
@@ -136,7 +137,15 @@ func renderAgentStatus(agent codersdk.WorkspaceAgent) string {
 			DefaultStyles.Placeholder.Render("["+strconv.Itoa(int(since.Seconds()))+"s]"),
 		)
 	case codersdk.WorkspaceAgentConnected:
-		return DefaultStyles.Keyword.Render("⦿ connected")
+		status := DefaultStyles.Keyword.Render("⦿ connected")
+		if len(agent.AutostopInhibitors) > 0 {
+			reasons := make([]string, 0, len(agent.AutostopInhibitors))
+			for _, inhibitor := range agent.AutostopInhibitors {
+				reasons = append(reasons, inhibitor.Reason)
+			}
+			status += " " + DefaultStyles.Placeholder.Render("[autostop inhibited: "+strings.Join(reasons, ", ")+"]")
+		}
+		return status
 	default:
 		return DefaultStyles.Warn.Render("○ unknown")
 	}
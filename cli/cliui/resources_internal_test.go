@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/coder/coder/v2/codersdk"
 )
 
 func TestRenderAgentVersion(t *testing.T) {
@@ -48,3 +50,15 @@ func TestRenderAgentVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderAgentStatus_AutostopInhibitors(t *testing.T) {
+	t.Parallel()
+	actual := renderAgentStatus(codersdk.WorkspaceAgent{
+		Status: codersdk.WorkspaceAgentConnected,
+		AutostopInhibitors: []codersdk.WorkspaceAgentAutostopInhibitor{
+			{Reason: "make all"},
+			{Reason: "python train.py"},
+		},
+	})
+	assert.Contains(t, actual, "autostop inhibited: make all, python train.py")
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// connectionLogPageSize is the number of connections fetched per request.
+const connectionLogPageSize = 100
+
+type connectionLogRow struct {
+	// For JSON format:
+	codersdk.ConnectionLog `table:"-"`
+
+	// For table format:
+	ConnectTime    string `json:"-" table:"connect time,default_sort"`
+	Type           string `json:"-" table:"type"`
+	Workspace      string `json:"-" table:"workspace"`
+	Agent          string `json:"-" table:"agent"`
+	User           string `json:"-" table:"user"`
+	IP             string `json:"-" table:"ip"`
+	SlugOrPort     string `json:"-" table:"slug or port"`
+	Code           string `json:"-" table:"code"`
+	DisconnectTime string `json:"-" table:"disconnect time"`
+	BytesSent      int64  `json:"-" table:"bytes sent"`
+	BytesReceived  int64  `json:"-" table:"bytes received"`
+}
+
+func connectionLogRowFromLog(log codersdk.ConnectionLog) connectionLogRow {
+	row := connectionLogRow{
+		ConnectionLog: log,
+		ConnectTime:   log.ConnectTime.Format(time.RFC3339),
+		Type:          string(log.Type),
+		Workspace:     log.WorkspaceOwnerUsername + "/" + log.WorkspaceName,
+		Agent:         log.AgentName,
+		User:          log.Username,
+		SlugOrPort:    log.SlugOrPort,
+		BytesSent:     log.BytesSent,
+		BytesReceived: log.BytesReceived,
+	}
+	if log.IP.IsValid() {
+		row.IP = log.IP.String()
+	}
+	if log.Code != nil {
+		row.Code = strconv.Itoa(int(*log.Code))
+	}
+	if log.DisconnectTime != nil {
+		row.DisconnectTime = log.DisconnectTime.Format(time.RFC3339)
+	}
+	return row
+}
+
+func (r *RootCmd) connectionLog() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]connectionLogRow{}, []string{"connect time", "type", "workspace", "user", "ip", "slug or port", "code", "disconnect time"}),
+		cliui.JSONFormat(),
+	)
+
+	var (
+		search string
+		limit  int64
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "connection-log",
+		Short: "List connections to workspaces",
+		Long: "SSH sessions, reconnecting PTYs and port forwards are reported by workspace agents. Workspace app and port accesses are logged once per user, app, client IP and hour.\n" + formatExamples(
+			example{
+				Description: "List the SSH sessions to a workspace",
+				Command:     "coder connection-log --search \"type:ssh workspace:my-workspace\"",
+			},
+			example{
+				Description: "Export all connections of a day as JSON",
+				Command:     "coder connection-log --search \"date_from:2023-10-01 date_to:2023-10-01\" --limit 0 -o json",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			var logs []codersdk.ConnectionLog
+			for limit == 0 || int64(len(logs)) < limit {
+				pageSize := connectionLogPageSize
+				if limit > 0 && limit-int64(len(logs)) < int64(pageSize) {
+					pageSize = int(limit - int64(len(logs)))
+				}
+				res, err := client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{
+					SearchQuery: search,
+					Pagination: codersdk.Pagination{
+						Offset: len(logs),
+						Limit:  pageSize,
+					},
+				})
+				if err != nil {
+					return xerrors.Errorf("get connection logs: %w", err)
+				}
+				logs = append(logs, res.ConnectionLogs...)
+				if len(res.ConnectionLogs) < pageSize {
+					break
+				}
+			}
+			if len(logs) == 0 {
+				cliui.Infof(inv.Stderr, "No connections found.\n")
+			}
+
+			rows := make([]connectionLogRow, 0, len(logs))
+			for _, log := range logs {
+				rows = append(rows, connectionLogRowFromLog(log))
+			}
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "search",
+			FlagShorthand: "s",
+			Description:   "Search for connections with a query, e.g. \"type:ssh owner:me\". Filters are type, owner, username, workspace, workspace_id, date_from and date_to.",
+			Value:         clibase.StringOf(&search),
+		},
+		{
+			Flag:        "limit",
+			Description: "Maximum number of connections to list. Set to 0 to list all of them.",
+			Default:     "100",
+			Value:       clibase.Int64Of(&limit),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
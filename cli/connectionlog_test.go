@@ -0,0 +1,83 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestConnectionLog(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	ids := make([]uuid.UUID, 3)
+	for i := range ids {
+		ids[i] = uuid.New()
+		err := agentClient.PostConnection(ctx, agentsdk.PostConnectionRequest{
+			ID:          ids[i],
+			Type:        codersdk.ConnectionTypeSSH,
+			ConnectTime: time.Now().Add(time.Duration(i) * time.Second),
+			IP:          "fd7a:115c:a1e0::1",
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "connection-log", "--search", "workspace:"+workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), workspace.Name)
+		require.Contains(t, stdout.String(), "fd7a:115c:a1e0::1")
+	})
+
+	t.Run("JSONAll", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "connection-log", "--limit", "0", "-o", "json")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var logs []codersdk.ConnectionLog
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &logs))
+		require.Len(t, logs, 3)
+		// Newest first.
+		require.Equal(t, ids[2], logs[0].ID)
+		require.Equal(t, ids[0], logs[2].ID)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "connection-log", "--limit", "2", "-o", "json")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var logs []codersdk.ConnectionLog
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &logs))
+		require.Len(t, logs, 2)
+	})
+}
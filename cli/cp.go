@@ -0,0 +1,593 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// cpPartialSuffix is appended to destination files while they are being
+// written. An interrupted transfer leaves the partial file behind so the
+// next invocation can resume from where it stopped.
+const cpPartialSuffix = ".coder-cp-partial"
+
+// cpPartialPath returns the path of the partial file of dst. The size and
+// modification time of the source are part of the name, so a partial file is
+// only resumed if the source hasn't changed since.
+func cpPartialPath(dst string, info fs.FileInfo) string {
+	return fmt.Sprintf("%s.%d-%d%s", dst, info.Size(), info.ModTime().Unix(), cpPartialSuffix)
+}
+
+const cpDescriptionLong = `Copies files between your machine and a workspace over the workspace connection.
+Either the source or the destination must be a workspace path: <workspace>[.<agent>]:<path>.
+  * Relative workspace paths are resolved from the home directory of the workspace user.
+  * File modes and modification times are preserved.
+  * Files that are already up to date are skipped.
+  * Interrupted transfers resume from where they stopped when the same copy is run again and the source is unchanged.
+`
+
+func (r *RootCmd) cp() *clibase.Cmd {
+	var recursive bool
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "cp <source> <destination>",
+		Short:       "Copy files between your machine and a workspace",
+		Long: cpDescriptionLong + "\n" + formatExamples(
+			example{
+				Description: "Copy a file to the home directory of a workspace",
+				Command:     "coder cp ./notes.txt my-workspace:",
+			},
+			example{
+				Description: "Copy a directory from a workspace to your machine",
+				Command:     "coder cp -r my-workspace:projects/api ./api",
+			},
+			example{
+				Description: "Copy a file to a specific agent of a workspace",
+				Command:     "coder cp ./build.tar.gz my-workspace.dev:/tmp/build.tar.gz",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			src, dst := parseCpPath(inv.Args[0]), parseCpPath(inv.Args[1])
+			if src.remote() == dst.remote() {
+				return xerrors.New("exactly one of the source and destination must be a workspace path (<workspace>:<path>)")
+			}
+			workspaceName := src.workspace
+			if dst.remote() {
+				workspaceName = dst.workspace
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, workspaceName)
+			if err != nil {
+				return err
+			}
+
+			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+				Fetch: client.WorkspaceAgent,
+				Wait:  false,
+			})
+			if err != nil {
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			logger, ok := LoggerFromContext(ctx)
+			if !ok {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr))
+			}
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			if r.disableDirect {
+				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger:         logger,
+				BlockEndpoints: r.disableDirect,
+			})
+			if err != nil {
+				return xerrors.Errorf("dial agent: %w", err)
+			}
+			defer conn.Close()
+			conn.AwaitReachable(ctx)
+
+			sshClient, err := conn.SSHClient(ctx)
+			if err != nil {
+				return xerrors.Errorf("ssh client: %w", err)
+			}
+			defer sshClient.Close()
+
+			sftpClient, err := sftp.NewClient(sshClient)
+			if err != nil {
+				return xerrors.Errorf("sftp client: %w", err)
+			}
+			defer sftpClient.Close()
+
+			// The SFTP client does not accept a context, so close it
+			// to interrupt any in-flight requests when canceled.
+			go func() {
+				<-ctx.Done()
+				_ = sftpClient.Close()
+			}()
+
+			c := &copier{
+				progress: newCpProgress(inv.Stderr, isTTYErr(inv)),
+			}
+			if src.remote() {
+				c.src, c.dst = sftpFS{sftpClient}, localFS{}
+			} else {
+				c.src, c.dst = localFS{}, sftpFS{sftpClient}
+			}
+
+			start := time.Now()
+			err = c.copy(ctx, src.path, dst.path, recursive)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return err
+			}
+			c.progress.summary(time.Since(start))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "recursive",
+			FlagShorthand: "r",
+			Description:   "Copy directories recursively.",
+			Value:         clibase.BoolOf(&recursive),
+		},
+	}
+	return cmd
+}
+
+type cpPath struct {
+	workspace string
+	path      string
+}
+
+func (p cpPath) remote() bool {
+	return p.workspace != ""
+}
+
+// parseCpPath parses a "<workspace>[.<agent>]:<path>" argument. Arguments
+// without a workspace prefix are local paths. Windows drive letters and
+// paths containing a separator before the colon are also treated as local.
+func parseCpPath(s string) cpPath {
+	prefix, p, ok := strings.Cut(s, ":")
+	if !ok || prefix == "" || strings.ContainsAny(prefix, `/\`) {
+		return cpPath{path: s}
+	}
+	if runtime.GOOS == "windows" && len(prefix) == 1 {
+		return cpPath{path: s}
+	}
+	if p == "" {
+		p = "."
+	}
+	return cpPath{workspace: prefix, path: p}
+}
+
+// cpFS is the subset of filesystem operations needed to copy files. It
+// is implemented for the local filesystem and for a workspace over SFTP.
+type cpFS interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.FileInfo, error)
+	Open(name string) (io.ReadSeekCloser, error)
+	// OpenFile opens a file for writing, creating it if necessary.
+	OpenFile(name string, truncate bool) (io.WriteSeeker, io.Closer, error)
+	MkdirAll(name string) error
+	Remove(name string) error
+	Chmod(name string, mode fs.FileMode) error
+	Chtimes(name string, mtime time.Time) error
+	Rename(oldname, newname string) error
+	Join(elem ...string) string
+	Base(name string) string
+	Dir(name string) string
+}
+
+type localFS struct{}
+
+func (localFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+func (localFS) ReadDir(name string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (localFS) Open(name string) (io.ReadSeekCloser, error) { return os.Open(name) }
+
+func (localFS) OpenFile(name string, truncate bool) (io.WriteSeeker, io.Closer, error) {
+	flag := os.O_WRONLY | os.O_CREATE
+	if truncate {
+		flag |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(name, flag, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f, nil
+}
+
+func (localFS) MkdirAll(name string) error { return os.MkdirAll(name, 0o700) }
+
+func (localFS) Remove(name string) error { return os.Remove(name) }
+
+func (localFS) Chmod(name string, mode fs.FileMode) error { return os.Chmod(name, mode) }
+
+func (localFS) Chtimes(name string, mtime time.Time) error { return os.Chtimes(name, mtime, mtime) }
+
+func (localFS) Rename(oldname, newname string) error { return os.Rename(oldname, newname) }
+
+func (localFS) Join(elem ...string) string { return filepath.Join(elem...) }
+
+func (localFS) Base(name string) string { return filepath.Base(name) }
+
+func (localFS) Dir(name string) string { return filepath.Dir(name) }
+
+type sftpFS struct {
+	client *sftp.Client
+}
+
+func (s sftpFS) Stat(name string) (fs.FileInfo, error) { return s.client.Stat(name) }
+
+func (s sftpFS) ReadDir(name string) ([]fs.FileInfo, error) { return s.client.ReadDir(name) }
+
+func (s sftpFS) Open(name string) (io.ReadSeekCloser, error) { return s.client.Open(name) }
+
+func (s sftpFS) OpenFile(name string, truncate bool) (io.WriteSeeker, io.Closer, error) {
+	flag := os.O_WRONLY | os.O_CREATE
+	if truncate {
+		flag |= os.O_TRUNC
+	}
+	f, err := s.client.OpenFile(name, flag)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f, nil
+}
+
+func (s sftpFS) MkdirAll(name string) error { return s.client.MkdirAll(name) }
+
+func (s sftpFS) Remove(name string) error { return s.client.Remove(name) }
+
+func (s sftpFS) Chmod(name string, mode fs.FileMode) error { return s.client.Chmod(name, mode) }
+
+func (s sftpFS) Chtimes(name string, mtime time.Time) error {
+	return s.client.Chtimes(name, mtime, mtime)
+}
+
+// Rename uses the POSIX rename extension because the plain SFTP rename
+// fails when the destination already exists.
+func (s sftpFS) Rename(oldname, newname string) error { return s.client.PosixRename(oldname, newname) }
+
+func (s sftpFS) Join(elem ...string) string { return s.client.Join(elem...) }
+
+func (sftpFS) Base(name string) string { return path.Base(name) }
+
+func (sftpFS) Dir(name string) string { return path.Dir(name) }
+
+type copier struct {
+	src      cpFS
+	dst      cpFS
+	progress *cpProgress
+}
+
+func (c *copier) copy(ctx context.Context, src, dst string, recursive bool) error {
+	info, err := c.src.Stat(src)
+	if err != nil {
+		return xerrors.Errorf("stat source: %w", err)
+	}
+	if info.IsDir() && !recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy it", src)
+	}
+	// Like cp, copying into an existing directory places the source
+	// inside of it.
+	if dstInfo, err := c.dst.Stat(dst); err == nil && dstInfo.IsDir() {
+		dst = c.dst.Join(dst, c.src.Base(src))
+	}
+	if info.IsDir() {
+		return c.copyDir(ctx, src, dst, info)
+	}
+	if !info.Mode().IsRegular() {
+		return xerrors.Errorf("%q is not a regular file", src)
+	}
+	err = c.copyFile(ctx, src, dst, info)
+	if err != nil {
+		return err
+	}
+	return c.removeStalePartials(c.dst.Dir(dst), []string{c.dst.Base(dst)})
+}
+
+func (c *copier) copyDir(ctx context.Context, src, dst string, info fs.FileInfo) error {
+	err := c.dst.MkdirAll(dst)
+	if err != nil {
+		return xerrors.Errorf("create directory %q: %w", dst, err)
+	}
+	entries, err := c.src.ReadDir(src)
+	if err != nil {
+		return xerrors.Errorf("read directory %q: %w", src, err)
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		srcPath := c.src.Join(src, entry.Name())
+		dstPath := c.dst.Join(dst, entry.Name())
+		switch {
+		case entry.IsDir():
+			err = c.copyDir(ctx, srcPath, dstPath, entry)
+		case entry.Mode().IsRegular():
+			err = c.copyFile(ctx, srcPath, dstPath, entry)
+			files = append(files, entry.Name())
+		default:
+			// Symlinks and special files are not followed to avoid
+			// copying files outside of the tree or looping forever.
+			c.progress.skip(srcPath, "not a regular file")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	err = c.removeStalePartials(dst, files)
+	if err != nil {
+		return err
+	}
+	// The mode is applied last in case the directory is not writable.
+	err = c.dst.Chmod(dst, info.Mode().Perm())
+	if err != nil {
+		return xerrors.Errorf("chmod %q: %w", dst, err)
+	}
+	return nil
+}
+
+func (c *copier) copyFile(ctx context.Context, src, dst string, info fs.FileInfo) error {
+	// Modification times are preserved, so a destination with the same
+	// size and time is the result of a previous copy. SFTP only has
+	// second precision.
+	if dstInfo, err := c.dst.Stat(dst); err == nil && dstInfo.Mode().IsRegular() &&
+		dstInfo.Size() == info.Size() && dstInfo.ModTime().Unix() == info.ModTime().Unix() {
+		c.progress.upToDate()
+		return nil
+	}
+
+	partial := cpPartialPath(dst, info)
+	var offset int64
+	if partialInfo, err := c.dst.Stat(partial); err == nil && partialInfo.Mode().IsRegular() && partialInfo.Size() <= info.Size() {
+		offset = partialInfo.Size()
+	}
+
+	reader, err := c.src.Open(src)
+	if err != nil {
+		return xerrors.Errorf("open %q: %w", src, err)
+	}
+	defer reader.Close()
+	writer, closer, err := c.dst.OpenFile(partial, offset == 0)
+	if err != nil {
+		return xerrors.Errorf("create %q: %w", partial, err)
+	}
+	defer closer.Close()
+	if offset > 0 {
+		_, err = reader.Seek(offset, io.SeekStart)
+		if err != nil {
+			return xerrors.Errorf("seek %q: %w", src, err)
+		}
+		_, err = writer.Seek(offset, io.SeekStart)
+		if err != nil {
+			return xerrors.Errorf("seek %q: %w", partial, err)
+		}
+	}
+
+	file := c.progress.start(dst, offset, info.Size())
+	_, err = io.Copy(writer, &cpReader{ctx: ctx, r: reader, progress: file})
+	if err != nil {
+		return xerrors.Errorf("copy %q: %w", src, err)
+	}
+	err = closer.Close()
+	if err != nil {
+		return xerrors.Errorf("close %q: %w", partial, err)
+	}
+	err = c.dst.Chmod(partial, info.Mode().Perm())
+	if err != nil {
+		return xerrors.Errorf("chmod %q: %w", partial, err)
+	}
+	err = c.dst.Chtimes(partial, info.ModTime())
+	if err != nil {
+		return xerrors.Errorf("chtimes %q: %w", partial, err)
+	}
+	err = c.dst.Rename(partial, dst)
+	if err != nil {
+		return xerrors.Errorf("rename %q: %w", partial, err)
+	}
+	file.done()
+	return nil
+}
+
+// removeStalePartials removes the partial files of the given files in dir.
+// They are left behind by interrupted copies of a source that has changed
+// since, as the partial files of completed copies are renamed.
+func (c *copier) removeStalePartials(dir string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	entries, err := c.dst.ReadDir(dir)
+	if err != nil {
+		return xerrors.Errorf("read directory %q: %w", dir, err)
+	}
+	copied := make(map[string]bool, len(files))
+	for _, file := range files {
+		copied[file] = true
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), cpPartialSuffix)
+		if !ok {
+			continue
+		}
+		// Strip the size and modification time of the source.
+		i := strings.LastIndex(name, ".")
+		if i < 0 || !copied[name[:i]] {
+			continue
+		}
+		partial := c.dst.Join(dir, entry.Name())
+		err = c.dst.Remove(partial)
+		if err != nil {
+			return xerrors.Errorf("remove %q: %w", partial, err)
+		}
+	}
+	return nil
+}
+
+// cpReader stops reading once the context is canceled and reports the
+// number of bytes read.
+type cpReader struct {
+	ctx      context.Context
+	r        io.Reader
+	progress *cpFileProgress
+}
+
+func (r *cpReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.r.Read(p)
+	r.progress.add(int64(n))
+	return n, err
+}
+
+// cpProgress writes the progress of a copy. On terminals the line of the
+// file being copied is updated in place.
+type cpProgress struct {
+	w       io.Writer
+	tty     bool
+	files   int
+	skipped int
+	bytes   int64
+}
+
+func newCpProgress(w io.Writer, tty bool) *cpProgress {
+	return &cpProgress{w: w, tty: tty}
+}
+
+func (p *cpProgress) start(name string, offset, size int64) *cpFileProgress {
+	f := &cpFileProgress{
+		progress: p,
+		name:     name,
+		offset:   offset,
+		copied:   offset,
+		size:     size,
+	}
+	f.render()
+	return f
+}
+
+func (p *cpProgress) upToDate() {
+	p.skipped++
+}
+
+func (p *cpProgress) skip(name string, reason string) {
+	_, _ = fmt.Fprintf(p.w, "Skipping %s: %s\n", name, reason)
+}
+
+func (p *cpProgress) summary(took time.Duration) {
+	msg := fmt.Sprintf("Copied %d file(s) (%s) in %s", p.files, formatCpBytes(p.bytes), took.Round(time.Millisecond))
+	if p.skipped > 0 {
+		msg += fmt.Sprintf(", %d file(s) already up to date", p.skipped)
+	}
+	_, _ = fmt.Fprintln(p.w, msg)
+}
+
+type cpFileProgress struct {
+	progress   *cpProgress
+	name       string
+	offset     int64
+	copied     int64
+	size       int64
+	lastRender time.Time
+}
+
+func (f *cpFileProgress) add(n int64) {
+	f.copied += n
+	f.progress.bytes += n
+	if f.progress.tty && time.Since(f.lastRender) > 100*time.Millisecond {
+		f.render()
+	}
+}
+
+func (f *cpFileProgress) render() {
+	if !f.progress.tty {
+		return
+	}
+	f.lastRender = time.Now()
+	_, _ = fmt.Fprintf(f.progress.w, "\r\033[K%s", f.line())
+}
+
+func (f *cpFileProgress) done() {
+	f.progress.files++
+	if f.progress.tty {
+		_, _ = fmt.Fprintf(f.progress.w, "\r\033[K%s\n", f.line())
+		return
+	}
+	_, _ = fmt.Fprintln(f.progress.w, f.line())
+}
+
+func (f *cpFileProgress) line() string {
+	percent := 100
+	if f.size > 0 {
+		percent = int(f.copied * 100 / f.size)
+	}
+	line := fmt.Sprintf("%s  %3d%%  %s/%s", f.name, percent, formatCpBytes(f.copied), formatCpBytes(f.size))
+	if f.offset > 0 {
+		line += fmt.Sprintf("  (resumed at %s)", formatCpBytes(f.offset))
+	}
+	return line
+}
+
+func formatCpBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
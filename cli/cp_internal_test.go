@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCpPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want cpPath
+	}{
+		{in: "file.txt", want: cpPath{path: "file.txt"}},
+		{in: "/tmp/file.txt", want: cpPath{path: "/tmp/file.txt"}},
+		{in: "./dir:with:colons", want: cpPath{path: "./dir:with:colons"}},
+		{in: ":file.txt", want: cpPath{path: ":file.txt"}},
+		{in: "ws:", want: cpPath{workspace: "ws", path: "."}},
+		{in: "ws:file.txt", want: cpPath{workspace: "ws", path: "file.txt"}},
+		{in: "ws.agent:/tmp/file.txt", want: cpPath{workspace: "ws.agent", path: "/tmp/file.txt"}},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests, struct {
+			in   string
+			want cpPath
+		}{in: `C:\Users\file.txt`, want: cpPath{path: `C:\Users\file.txt`}})
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, parseCpPath(tt.in))
+		})
+	}
+}
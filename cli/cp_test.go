@@ -0,0 +1,174 @@
+package cli_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+
+	// The agent runs on this machine, so absolute paths on the
+	// "workspace" side are just paths in another temporary directory.
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Workspace) {
+		t.Helper()
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		t.Cleanup(func() {
+			_ = agentCloser.Close()
+		})
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		return client, workspace
+	}
+	// partialPath returns the path of the partial file left behind by an
+	// interrupted copy of src to dst.
+	partialPath := func(t *testing.T, src, dst string) string {
+		t.Helper()
+		info, err := os.Stat(src)
+		require.NoError(t, err)
+		return fmt.Sprintf("%s.%d-%d.coder-cp-partial", dst, info.Size(), info.ModTime().Unix())
+	}
+	run := func(t *testing.T, client *codersdk.Client, args ...string) error {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, append([]string{"cp"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		return inv.WithContext(ctx).Run()
+	}
+
+	t.Run("Upload", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		src := filepath.Join(t.TempDir(), "hello.sh")
+		require.NoError(t, os.WriteFile(src, []byte("echo hello"), 0o750))
+		dstDir := t.TempDir()
+
+		err := run(t, client, src, workspace.Name+":"+dstDir)
+		require.NoError(t, err)
+
+		dst := filepath.Join(dstDir, "hello.sh")
+		data, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "echo hello", string(data))
+		info, err := os.Stat(dst)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+		_, err = os.Stat(partialPath(t, src, dst))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("DownloadRecursive", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		src := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "a", "b"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "a", "one.txt"), []byte("one"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "a", "b", "two.txt"), []byte("two"), 0o600))
+		dst := filepath.Join(t.TempDir(), "copy")
+
+		err := run(t, client, workspace.Name+":"+src, dst)
+		require.Error(t, err, "directories require --recursive")
+
+		err = run(t, client, "-r", workspace.Name+":"+src, dst)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dst, "a", "one.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "one", string(data))
+		data, err = os.ReadFile(filepath.Join(dst, "a", "b", "two.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "two", string(data))
+		info, err := os.Stat(filepath.Join(dst, "a", "b", "two.txt"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		src := filepath.Join(t.TempDir(), "data")
+		require.NoError(t, os.WriteFile(src, []byte("0123456789"), 0o644))
+		dst := filepath.Join(t.TempDir(), "data")
+		// Simulate an interrupted transfer. The partial content differs
+		// from the source to prove only the remainder is copied.
+		require.NoError(t, os.WriteFile(partialPath(t, src, dst), []byte("abcd"), 0o600))
+
+		err := run(t, client, src, workspace.Name+":"+dst)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "abcd456789", string(data))
+
+		// The destination is now up to date, so it is not copied again.
+		past := time.Now().Add(-time.Hour)
+		require.NoError(t, os.WriteFile(dst, []byte("ABCDEFGHIJ"), 0o644))
+		require.NoError(t, os.Chtimes(src, past, past))
+		require.NoError(t, os.Chtimes(dst, past, past))
+		err = run(t, client, src, workspace.Name+":"+dst)
+		require.NoError(t, err)
+		data, err = os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "ABCDEFGHIJ", string(data))
+	})
+
+	t.Run("SourceChanged", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		src := filepath.Join(t.TempDir(), "data")
+		require.NoError(t, os.WriteFile(src, []byte("0123456789"), 0o644))
+		past := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(src, past, past))
+		dst := filepath.Join(t.TempDir(), "data")
+		// Simulate an interrupted transfer of the old source.
+		stale := partialPath(t, src, dst)
+		require.NoError(t, os.WriteFile(stale, []byte("0123"), 0o600))
+
+		// The source changes before the copy is run again, without
+		// changing its size.
+		require.NoError(t, os.WriteFile(src, []byte("9876543210"), 0o644))
+		require.NoError(t, os.Chtimes(src, time.Now(), time.Now()))
+
+		err := run(t, client, src, workspace.Name+":"+dst)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "9876543210", string(data))
+		_, err = os.Stat(stale)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("NoWorkspacePath", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		err := run(t, client, "./a", "./b")
+		require.ErrorContains(t, err, "must be a workspace path")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +24,8 @@ func (r *RootCmd) create() *clibase.Cmd {
 		startAt       string
 		stopAfter     time.Duration
 		workspaceName string
+		autoUpdates   string
+		presetName    string
 
 		parameterFlags workspaceParameterFlags
 	)
@@ -143,6 +146,7 @@ func (r *RootCmd) create() *clibase.Cmd {
 
 				RichParameterFile: parameterFlags.richParameterFile,
 				RichParameters:    cliRichParameters,
+				Preset:            presetName,
 			})
 			if err != nil {
 				return xerrors.Errorf("prepare build: %w", err)
@@ -167,6 +171,7 @@ func (r *RootCmd) create() *clibase.Cmd {
 				AutostartSchedule:   schedSpec,
 				TTLMillis:           ttlMillis,
 				RichParameterValues: richParameters,
+				AutomaticUpdates:    codersdk.AutomaticUpdates(autoUpdates),
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace: %w", err)
@@ -201,6 +206,19 @@ func (r *RootCmd) create() *clibase.Cmd {
 			Description: "Specify a duration after which the workspace should shut down (e.g. 8h).",
 			Value:       clibase.DurationOf(&stopAfter),
 		},
+		clibase.Option{
+			Flag:        "preset",
+			Env:         "CODER_WORKSPACE_PRESET",
+			Description: "Specify the name of a parameter preset of the template. Parameter values given with other flags take precedence over the preset.",
+			Value:       clibase.StringOf(&presetName),
+		},
+		clibase.Option{
+			Flag:        "automatic-updates",
+			Env:         "CODER_WORKSPACE_AUTOMATIC_UPDATES",
+			Description: "Specify automatic updates setting for the workspace (accepts 'always' or 'never').",
+			Default:     string(codersdk.AutomaticUpdatesNever),
+			Value:       clibase.EnumOf(&autoUpdates, string(codersdk.AutomaticUpdatesAlways), string(codersdk.AutomaticUpdatesNever)),
+		},
 		cliui.SkipPromptOption(),
 	)
 	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
@@ -221,6 +239,9 @@ type prepWorkspaceBuildArgs struct {
 	PromptRichParameters bool
 	RichParameters       []codersdk.WorkspaceBuildParameter
 	RichParameterFile    string
+
+	// Preset is the name of a parameter preset of the template version.
+	Preset string
 }
 
 // prepWorkspaceBuild will ensure a workspace build will succeed on the latest template version.
@@ -246,8 +267,31 @@ func prepWorkspaceBuild(inv *clibase.Invocation, client *codersdk.Client, args p
 		}
 	}
 
+	var presetParameters []codersdk.WorkspaceBuildParameter
+	if args.Preset != "" {
+		presets, err := client.TemplateVersionPresets(ctx, templateVersion.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template version presets: %w", err)
+		}
+		presetNames := make([]string, 0, len(presets))
+		for _, preset := range presets {
+			if preset.Name == args.Preset {
+				presetParameters = preset.Parameters
+				break
+			}
+			presetNames = append(presetNames, preset.Name)
+		}
+		if presetParameters == nil {
+			if len(presetNames) == 0 {
+				return nil, xerrors.Errorf("template %q has no presets", args.Template.Name)
+			}
+			return nil, xerrors.Errorf("preset %q not found, available presets: %s", args.Preset, strings.Join(presetNames, ", "))
+		}
+	}
+
 	resolver := new(ParameterResolver).
 		WithLastBuildParameters(args.LastBuildParameters).
+		WithPresetParameters(presetParameters).
 		WithPromptBuildOptions(args.PromptBuildOptions).
 		WithBuildOptions(args.BuildOptions).
 		WithPromptRichParameters(args.PromptRichParameters).
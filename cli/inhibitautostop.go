@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentsocket"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/retry"
+)
+
+func (*RootCmd) inhibitAutostop() *clibase.Cmd {
+	var (
+		socketPath string
+		reason     string
+	)
+	cmd := &clibase.Cmd{
+		Use:   "inhibit-autostop -- <command> [args...]",
+		Short: "Keep the workspace running while a command runs",
+		Long: "Must be run inside a workspace. The deadline of the workspace is extended while the command runs, up to the maximum lifetime set in the template.\n" + formatExamples(
+			example{
+				Description: "Keep the workspace running during a long build",
+				Command:     "coder inhibit-autostop -- make all",
+			},
+			example{
+				Description: "Set the reason shown on the workspace",
+				Command:     `coder inhibit-autostop --reason "Training the model" -- python train.py`,
+			},
+		),
+		Middleware: clibase.RequireRangeArgs(1, -1),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+			if reason == "" {
+				reason = strings.Join(inv.Args, " ")
+			}
+			client := agentsocket.NewClient(socketPath)
+			req := agentsocket.InhibitAutostopRequest{
+				Reason: reason,
+			}
+			_, inhibitor, err := client.InhibitAutostop(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("inhibit autostop: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stderr, "Autostop is inhibited until the command exits.")
+
+			// The agent releases the inhibitor when the connection to it is
+			// lost, for example when the agent restarts, so hold it again
+			// until the command exits.
+			held := make(chan struct{})
+			go func() {
+				defer close(held)
+				for {
+					_, _ = io.Copy(io.Discard, inhibitor)
+					_ = inhibitor.Close()
+					if ctx.Err() != nil {
+						return
+					}
+					_, _ = fmt.Fprintln(inv.Stderr, "Lost the connection to the agent, autostop is not inhibited until it is back.")
+					for r := retry.New(time.Second, 10*time.Second); r.Wait(ctx); {
+						_, next, err := client.InhibitAutostop(ctx, req)
+						if err == nil {
+							inhibitor = next
+							break
+						}
+					}
+					if ctx.Err() != nil {
+						return
+					}
+					_, _ = fmt.Fprintln(inv.Stderr, "Autostop is inhibited again.")
+				}
+			}()
+			defer func() {
+				// Canceling the context closes the connection.
+				cancel()
+				<-held
+			}()
+
+			c := exec.CommandContext(ctx, inv.Args[0], inv.Args[1:]...)
+			c.Stdin = inv.Stdin
+			c.Stdout = inv.Stdout
+			c.Stderr = inv.Stderr
+			err = c.Run()
+			if err != nil {
+				return xerrors.Errorf("run command: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "reason",
+			Description: "The reason shown on the workspace while the command runs. Defaults to the command.",
+			Value:       clibase.StringOf(&reason),
+		},
+		{
+			Flag:        "socket-path",
+			Default:     agentsocket.DefaultPath(),
+			Env:         agentsocket.EnvSocketPath,
+			Description: "The path of the Unix socket of the workspace agent.",
+			Value:       clibase.StringOf(&socketPath),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,73 @@
+package cli_test
+
+import (
+	"io"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestInhibitAutostop(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The agent socket only works on Linux and macOS.")
+	}
+
+	authToken := uuid.NewString()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	inv, _ := clitest.New(t,
+		"agent",
+		"--auth", "token",
+		"--agent-token", authToken,
+		"--agent-url", client.URL.String(),
+		"--log-dir", t.TempDir(),
+		"--socket-path", socketPath,
+	)
+	clitest.Start(t, inv)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// The command runs until its stdin is closed.
+	stdin, stdinWriter := io.Pipe()
+	inv, _ = clitest.New(t, "inhibit-autostop", "--socket-path", socketPath, "--reason", "make all", "--", "cat")
+	inv.Stdin = stdin
+	waiter := clitest.StartWithWaiter(t, inv.WithContext(ctx))
+
+	require.Eventually(t, func() bool {
+		agent, err := client.WorkspaceAgent(ctx, agentID)
+		require.NoError(t, err)
+		return len(agent.AutostopInhibitors) == 1 && agent.AutostopInhibitors[0].Reason == "make all"
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	require.NoError(t, stdinWriter.Close())
+	waiter.RequireSuccess()
+
+	// The inhibitor is released when the command exits.
+	require.Eventually(t, func() bool {
+		agent, err := client.WorkspaceAgent(ctx, agentID)
+		require.NoError(t, err)
+		return len(agent.AutostopInhibitors) == 0
+	}, testutil.WaitLong, testutil.IntervalFast)
+}
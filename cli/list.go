@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
@@ -148,7 +150,24 @@ func (r *RootCmd) list() *clibase.Cmd {
 			}
 
 			_, err = fmt.Fprintln(inv.Stdout, out)
-			return err
+			if err != nil {
+				return err
+			}
+
+			// Warn once per deprecated template, so users know to move
+			// their workspaces to another template.
+			deprecated := map[string]string{}
+			for _, workspace := range res.Workspaces {
+				if workspace.TemplateDeprecationMessage != "" {
+					deprecated[workspace.TemplateName] = workspace.TemplateDeprecationMessage
+				}
+			}
+			templateNames := maps.Keys(deprecated)
+			slices.Sort(templateNames)
+			for _, name := range templateNames {
+				warnDeprecatedTemplate(inv.Stderr, name, deprecated[name])
+			}
+			return nil
 		},
 	}
 	cmd.Options = clibase.OptionSet{
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) logs() *clibase.Cmd {
+	var (
+		follow  bool
+		sources []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "logs <workspace>[.<agent>]",
+		Short: "Show the logs of a workspace agent",
+		Long: formatExamples(
+			example{
+				Description: "Show the logs of all sources, like the startup script",
+				Command:     "coder logs my-workspace",
+			},
+			example{
+				Description: "Follow the logs of a single source",
+				Command:     `coder logs my-workspace --source "App" --follow`,
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspaceName, agentName, _ := strings.Cut(inv.Args[0], ".")
+			workspace, err := namedWorkspace(ctx, client, workspaceName)
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			agent, err := workspaceAgentByName(workspace, agentName)
+			if err != nil {
+				return err
+			}
+
+			sourceNames := make(map[uuid.UUID]string, len(agent.LogSources))
+			for _, source := range agent.LogSources {
+				sourceNames[source.ID] = source.DisplayName
+			}
+			filter, err := logSourceFilter(agent.LogSources, sources)
+			if err != nil {
+				return err
+			}
+
+			logs, closer, err := client.WorkspaceAgentLogsAfter(ctx, agent.ID, 0, follow)
+			if err != nil {
+				return xerrors.Errorf("get logs: %w", err)
+			}
+			defer closer.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case batch, ok := <-logs:
+					if !ok {
+						return nil
+					}
+					for _, log := range batch {
+						if filter != nil {
+							if _, ok := filter[log.SourceID]; !ok {
+								continue
+							}
+						}
+						_, _ = fmt.Fprintf(inv.Stdout, "%s [%s] %s\n",
+							cliui.DefaultStyles.DateTimeStamp.Render(log.CreatedAt.Local().Format(time.RFC3339)),
+							sourceNames[log.SourceID],
+							log.Output,
+						)
+					}
+				}
+			}
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "follow",
+			FlagShorthand: "f",
+			Description:   "Keep streaming new logs until the command is interrupted.",
+			Value:         clibase.BoolOf(&follow),
+		},
+		{
+			Flag:        "source",
+			Description: "Only show the logs of these sources, by display name. Defaults to all sources.",
+			Value:       clibase.StringArrayOf(&sources),
+		},
+	}
+	return cmd
+}
+
+// workspaceAgentByName returns the agent of the latest build of the workspace
+// with the given name. The name can be omitted when the workspace has a single
+// agent.
+func workspaceAgentByName(workspace codersdk.Workspace, name string) (codersdk.WorkspaceAgent, error) {
+	var agents []codersdk.WorkspaceAgent
+	for _, resource := range workspace.LatestBuild.Resources {
+		agents = append(agents, resource.Agents...)
+	}
+	if len(agents) == 0 {
+		return codersdk.WorkspaceAgent{}, xerrors.Errorf("workspace %q has no agents", workspace.Name)
+	}
+	if name == "" {
+		if len(agents) > 1 {
+			return codersdk.WorkspaceAgent{}, xerrors.Errorf("workspace %q has multiple agents, specify one with %s.<agent>", workspace.Name, workspace.Name)
+		}
+		return agents[0], nil
+	}
+	for _, agent := range agents {
+		if agent.Name == name {
+			return agent, nil
+		}
+	}
+	return codersdk.WorkspaceAgent{}, xerrors.Errorf("agent not found by name %q", name)
+}
+
+// logSourceFilter returns the IDs of the log sources with the given display
+// names, or nil if no names are given.
+func logSourceFilter(logSources []codersdk.WorkspaceAgentLogSource, names []string) (map[uuid.UUID]struct{}, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	filter := make(map[uuid.UUID]struct{}, len(names))
+	for _, name := range names {
+		found := false
+		for _, source := range logSources {
+			if strings.EqualFold(source.DisplayName, name) {
+				filter[source.ID] = struct{}{}
+				found = true
+			}
+		}
+		if !found {
+			available := make([]string, 0, len(logSources))
+			for _, source := range logSources {
+				available = append(available, fmt.Sprintf("%q", source.DisplayName))
+			}
+			sort.Strings(available)
+			return nil, xerrors.Errorf("log source %q not found, available sources: %s", name, strings.Join(available, ", "))
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,84 @@
+package cli_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestLogs(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].LogFiles = []*proto.LogFile{{
+			DisplayName: "App",
+			Path:        "/var/log/app.log",
+		}}
+		return agents
+	})
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+	require.Len(t, agent.LogSources, 1)
+	appSource := agent.LogSources[0]
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	scriptSource, err := agentClient.PostLogSource(ctx, agentsdk.PostLogSource{
+		ID:          uuid.New(),
+		DisplayName: "Startup Script",
+	})
+	require.NoError(t, err)
+	for _, log := range []struct {
+		source uuid.UUID
+		output string
+	}{
+		{appSource.ID, "listening on :8080"},
+		{scriptSource.ID, "installing dependencies"},
+	} {
+		err := agentClient.PatchLogs(ctx, agentsdk.PatchLogs{
+			LogSourceID: log.source,
+			Logs: []agentsdk.Log{{
+				CreatedAt: time.Now(),
+				Output:    log.output,
+				Level:     codersdk.LogLevelInfo,
+			}},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("All", func(t *testing.T) {
+		t.Parallel()
+		inv, root := clitest.New(t, "logs", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+		pty.ExpectMatch("[App] listening on :8080")
+		pty.ExpectMatch("[Startup Script] installing dependencies")
+	})
+
+	t.Run("Source", func(t *testing.T) {
+		t.Parallel()
+		inv, root := clitest.New(t, "logs", workspace.Name+"."+agent.Name, "--source", "startup script")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+		pty.ExpectMatch("[Startup Script] installing dependencies")
+	})
+
+	t.Run("UnknownSource", func(t *testing.T) {
+		t.Parallel()
+		inv, root := clitest.New(t, "logs", workspace.Name, "--source", "IDE")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, `log source "IDE" not found, available sources: "App", "Startup Script"`)
+	})
+}
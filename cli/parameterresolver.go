@@ -21,6 +21,7 @@ const (
 
 type ParameterResolver struct {
 	lastBuildParameters []codersdk.WorkspaceBuildParameter
+	presetParameters    []codersdk.WorkspaceBuildParameter
 
 	richParameters     []codersdk.WorkspaceBuildParameter
 	richParametersFile map[string]string
@@ -35,6 +36,13 @@ func (pr *ParameterResolver) WithLastBuildParameters(params []codersdk.Workspace
 	return pr
 }
 
+// WithPresetParameters sets the values of a template preset. Values given in
+// a parameter file or on the command line take precedence over them.
+func (pr *ParameterResolver) WithPresetParameters(params []codersdk.WorkspaceBuildParameter) *ParameterResolver {
+	pr.presetParameters = params
+	return pr
+}
+
 func (pr *ParameterResolver) WithRichParameters(params []codersdk.WorkspaceBuildParameter) *ParameterResolver {
 	pr.richParameters = params
 	return pr
@@ -64,6 +72,7 @@ func (pr *ParameterResolver) Resolve(inv *clibase.Invocation, action WorkspaceCL
 	var staged []codersdk.WorkspaceBuildParameter
 	var err error
 
+	staged = pr.resolveWithPreset(staged)
 	staged = pr.resolveWithParametersMapFile(staged)
 	staged = pr.resolveWithCommandLineOrEnv(staged)
 	staged = pr.resolveWithLastBuildParameters(staged, templateVersionParameters)
@@ -76,6 +85,21 @@ func (pr *ParameterResolver) Resolve(inv *clibase.Invocation, action WorkspaceCL
 	return staged, nil
 }
 
+func (pr *ParameterResolver) resolveWithPreset(resolved []codersdk.WorkspaceBuildParameter) []codersdk.WorkspaceBuildParameter {
+next:
+	for _, presetParameter := range pr.presetParameters {
+		for i, r := range resolved {
+			if r.Name == presetParameter.Name {
+				resolved[i].Value = presetParameter.Value
+				continue next
+			}
+		}
+
+		resolved = append(resolved, presetParameter)
+	}
+	return resolved
+}
+
 func (pr *ParameterResolver) resolveWithParametersMapFile(resolved []codersdk.WorkspaceBuildParameter) []codersdk.WorkspaceBuildParameter {
 next:
 	for name, value := range pr.richParametersFile {
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) port() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "port",
+		Short:       "Share ports of workspaces with other users",
+		Long: "Ports of a workspace are only accessible by its owner until they are shared. The template of the workspace limits how widely ports can be shared.\n" + formatExamples(
+			example{
+				Description: "Share port 3000 with all authenticated users",
+				Command:     "coder port share my-workspace 3000",
+			},
+			example{
+				Description: "Share port 8080 of the agent named \"main\" publicly",
+				Command:     "coder port share my-workspace.main 8080 --level public",
+			},
+			example{
+				Description: "Stop sharing port 3000",
+				Command:     "coder port unshare my-workspace 3000",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.portShare(),
+			r.portUnshare(),
+			r.portList(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) portShare() *clibase.Cmd {
+	var level string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "share <workspace>[.<agent>] <port>",
+		Short: "Share a port of a workspace with other users",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, agentName, err := portShareWorkspaceAgent(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			port, err := parsePortShareNumber(inv.Args[1])
+			if err != nil {
+				return err
+			}
+
+			share, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+				AgentName:  agentName,
+				Port:       port,
+				ShareLevel: codersdk.WorkspaceAgentPortShareLevel(level),
+			})
+			if err != nil {
+				return xerrors.Errorf("share port: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Port %s of %s is now shared with %s users.\n",
+				cliui.DefaultStyles.Keyword.Render(strconv.Itoa(int(share.Port))),
+				cliui.DefaultStyles.Keyword.Render(workspace.Name+"."+share.AgentName),
+				cliui.DefaultStyles.Keyword.Render(string(share.ShareLevel)),
+			)
+			// The URL is only available if the deployment has a wildcard
+			// access URL, since ports are served as subdomain apps.
+			appHost, err := client.AppHost(ctx)
+			if err == nil && appHost.Host != "" {
+				subdomain := fmt.Sprintf("%d--%s--%s--%s", share.Port, share.AgentName, workspace.Name, workspace.OwnerName)
+				_, _ = fmt.Fprintf(inv.Stdout, "%s://%s\n", client.URL.Scheme, strings.Replace(appHost.Host, "*", subdomain, 1))
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "level",
+			Description: "Who can access the port: all authenticated users, or anyone including unauthenticated users.",
+			Default:     string(codersdk.WorkspaceAgentPortShareLevelAuthenticated),
+			Value: clibase.EnumOf(&level,
+				string(codersdk.WorkspaceAgentPortShareLevelAuthenticated),
+				string(codersdk.WorkspaceAgentPortShareLevelPublic),
+			),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) portUnshare() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "unshare <workspace>[.<agent>] <port>",
+		Short: "Stop sharing a port of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, agentName, err := portShareWorkspaceAgent(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			port, err := parsePortShareNumber(inv.Args[1])
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.DeleteWorkspaceAgentPortShareRequest{
+				AgentName: agentName,
+				Port:      port,
+			})
+			if err != nil {
+				return xerrors.Errorf("unshare port: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Port %s of %s is no longer shared.\n",
+				cliui.DefaultStyles.Keyword.Render(strconv.Itoa(int(port))),
+				cliui.DefaultStyles.Keyword.Render(workspace.Name+"."+agentName),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// portShareListRow is the type provided to the OutputFormatter.
+type portShareListRow struct {
+	// For JSON format:
+	codersdk.WorkspaceAgentPortShare `table:"-"`
+
+	// For table format:
+	Agent string `json:"-" table:"agent,default_sort"`
+	Port  int32  `json:"-" table:"port"`
+	Level string `json:"-" table:"level"`
+}
+
+func (r *RootCmd) portList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]portShareListRow{}, []string{"agent", "port", "level"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the shared ports of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			shares, err := client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list shared ports: %w", err)
+			}
+			if len(shares.Shares) == 0 {
+				cliui.Infof(inv.Stdout, "No ports of %s are shared.\n", workspace.Name)
+				return nil
+			}
+
+			rows := make([]portShareListRow, 0, len(shares.Shares))
+			for _, share := range shares.Shares {
+				rows = append(rows, portShareListRow{
+					WorkspaceAgentPortShare: share,
+					Agent:                   share.AgentName,
+					Port:                    share.Port,
+					Level:                   string(share.ShareLevel),
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// portShareWorkspaceAgent resolves a "<workspace>[.<agent>]" argument. The
+// agent may only be omitted if the workspace has a single agent.
+func portShareWorkspaceAgent(ctx context.Context, client *codersdk.Client, in string) (codersdk.Workspace, string, error) {
+	workspaceName, agentName, _ := strings.Cut(in, ".")
+	workspace, err := namedWorkspace(ctx, client, workspaceName)
+	if err != nil {
+		return codersdk.Workspace{}, "", err
+	}
+	if agentName != "" {
+		return workspace, agentName, nil
+	}
+
+	var agents []string
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			agents = append(agents, agent.Name)
+		}
+	}
+	switch len(agents) {
+	case 0:
+		return codersdk.Workspace{}, "", xerrors.Errorf("workspace %q has no agents, is it running?", workspace.Name)
+	case 1:
+		return workspace, agents[0], nil
+	default:
+		return codersdk.Workspace{}, "", xerrors.Errorf("workspace %q has multiple agents, specify one of %s with %s", workspace.Name, strings.Join(agents, ", "), cliui.DefaultStyles.Code.Render(workspace.Name+".<agent>"))
+	}
+}
+
+func parsePortShareNumber(in string) (int32, error) {
+	port, err := strconv.ParseUint(in, 10, 16)
+	if err != nil || port == 0 {
+		return 0, xerrors.Errorf("invalid port %q, must be between 1 and 65535", in)
+	}
+	return int32(port), nil
+}
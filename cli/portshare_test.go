@@ -0,0 +1,90 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+//nolint:tparallel,paralleltest // Subtests share the same workspace and run in order.
+func TestPort(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelPublic),
+	})
+	require.NoError(t, err)
+
+	t.Run("Share", func(t *testing.T) {
+		inv, root := clitest.New(t, "port", "share", workspace.Name, "3000", "--level", "public")
+		clitest.SetupConfig(t, member, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "now shared with public users")
+
+		shares, err := member.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceAgentPortShare{{
+			WorkspaceID: workspace.ID,
+			AgentName:   "example",
+			Port:        3000,
+			ShareLevel:  codersdk.WorkspaceAgentPortShareLevelPublic,
+		}}, shares.Shares)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		inv, root := clitest.New(t, "port", "list", workspace.Name)
+		clitest.SetupConfig(t, member, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Regexp(t, `example\s+3000\s+public`, stdout.String())
+	})
+
+	t.Run("InvalidPort", func(t *testing.T) {
+		inv, root := clitest.New(t, "port", "share", workspace.Name+".example", "70000")
+		clitest.SetupConfig(t, member, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "invalid port")
+	})
+
+	t.Run("Unshare", func(t *testing.T) {
+		inv, root := clitest.New(t, "port", "unshare", workspace.Name+".example", "3000")
+		clitest.SetupConfig(t, member, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "no longer shared")
+
+		shares, err := member.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares.Shares)
+	})
+}
@@ -0,0 +1,566 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet"
+)
+
+func (r *RootCmd) proxy() *clibase.Cmd {
+	var listenAddress string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "proxy",
+		Short: "Start a local SOCKS5 and HTTP proxy to the ports of your workspaces",
+		Long: "Hosts are named workspace, agent.workspace or agent.workspace.owner." + tailnet.CoderDNSSuffix + ". " +
+			"Workspaces are connected to the first time one of their ports is dialed.\n" + formatExamples(
+			example{
+				Description: "Start the proxy on port 1080",
+				Command:     "coder proxy --listen 127.0.0.1:1080",
+			},
+			example{
+				Description: "Reach port 8080 of the \"dev\" workspace",
+				Command:     "curl --proxy socks5h://127.0.0.1:1080 http://dev:8080",
+			},
+			example{
+				Description: "Reach port 3000 of the \"web\" agent of a workspace owned by alice",
+				Command:     "curl --proxy http://127.0.0.1:1080 http://web.dev.alice.coder:3000",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			logger := slog.Make()
+			if r.verbose {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			if r.disableDirect {
+				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+			}
+
+			l, err := net.Listen("tcp", listenAddress)
+			if err != nil {
+				return xerrors.Errorf("listen %q: %w", listenAddress, err)
+			}
+			defer l.Close()
+
+			p := &workspaceProxy{
+				ctx:           ctx,
+				client:        client,
+				logger:        logger,
+				disableDirect: r.disableDirect,
+				conns:         map[uuid.UUID]*proxyAgentConn{},
+			}
+			defer p.close()
+
+			var (
+				wg       sync.WaitGroup
+				closeErr error
+			)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				sigs := make(chan os.Signal, 1)
+				signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+				select {
+				case <-ctx.Done():
+					closeErr = ctx.Err()
+				case <-sigs:
+					_, _ = fmt.Fprintln(inv.Stderr, "\nReceived signal, closing the proxy and active connections")
+				}
+
+				cancel()
+				_ = l.Close()
+			}()
+
+			_, _ = fmt.Fprintf(inv.Stderr, "SOCKS5 and HTTP proxy listening on %s\n", l.Addr())
+			for {
+				netConn, err := l.Accept()
+				if err != nil {
+					if !xerrors.Is(err, net.ErrClosed) {
+						_, _ = fmt.Fprintf(inv.Stderr, "Error accepting connection from %q: %v\n", l.Addr(), err)
+					}
+					break
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer netConn.Close()
+					err := p.serve(ctx, netConn)
+					if err != nil && ctx.Err() == nil {
+						_, _ = fmt.Fprintf(inv.Stderr, "Proxy connection from %s failed: %v\n", netConn.RemoteAddr(), err)
+					}
+				}()
+			}
+			cancel()
+			wg.Wait()
+			return closeErr
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "listen",
+			Env:         "CODER_PROXY_LISTEN",
+			Description: "The address the proxy listens on. It speaks both SOCKS5 and HTTP.",
+			Default:     "127.0.0.1:1080",
+			Value:       clibase.StringOf(&listenAddress),
+		},
+	}
+	return cmd
+}
+
+// workspaceProxy dials the ports of workspaces for SOCKS5 and HTTP clients.
+// Connections to agents are made on demand and reused for later dials.
+type workspaceProxy struct {
+	// ctx is canceled when the proxy closes. Connections to agents are
+	// dialed with it, since they outlive the requests that dial them.
+	ctx           context.Context
+	client        *codersdk.Client
+	logger        slog.Logger
+	disableDirect bool
+
+	dials  singleflight.Group
+	mu     sync.Mutex
+	closed bool
+	conns  map[uuid.UUID]*proxyAgentConn
+}
+
+// proxyAgentConn is a cached connection to an agent.
+type proxyAgentConn struct {
+	*codersdk.WorkspaceAgentConn
+	// cancel cancels the context the connection was dialed with.
+	cancel context.CancelFunc
+}
+
+func (c *proxyAgentConn) close() {
+	_ = c.Close()
+	c.cancel()
+}
+
+// serve handles a single client connection. SOCKS5 clients are told apart
+// from HTTP clients by the version byte they send first.
+func (p *workspaceProxy) serve(ctx context.Context, netConn net.Conn) error {
+	br := bufio.NewReader(netConn)
+	version, err := br.Peek(1)
+	if err != nil {
+		return xerrors.Errorf("read: %w", err)
+	}
+	conn := &bufferedConn{Conn: netConn, r: br}
+	if version[0] == socks5Version {
+		return p.serveSOCKS5(ctx, conn)
+	}
+	return p.serveHTTP(ctx, conn)
+}
+
+const (
+	socks5Version        = 0x05
+	socks5AuthNone       = 0x00
+	socks5AuthNoMethods  = 0xff
+	socks5CommandConnect = 0x01
+	socks5AddrIPv4       = 0x01
+	socks5AddrDomain     = 0x03
+	socks5AddrIPv6       = 0x04
+
+	socks5ReplySucceeded               = 0x00
+	socks5ReplyGeneralFailure          = 0x01
+	socks5ReplyHostUnreachable         = 0x04
+	socks5ReplyCommandNotSupported     = 0x07
+	socks5ReplyAddressTypeNotSupported = 0x08
+)
+
+// serveSOCKS5 implements the CONNECT command of SOCKS5 without
+// authentication, see RFC 1928.
+func (p *workspaceProxy) serveSOCKS5(ctx context.Context, conn *bufferedConn) error {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(conn, header)
+	if err != nil {
+		return xerrors.Errorf("read greeting: %w", err)
+	}
+	methods := make([]byte, header[1])
+	_, err = io.ReadFull(conn, methods)
+	if err != nil {
+		return xerrors.Errorf("read auth methods: %w", err)
+	}
+	method := byte(socks5AuthNoMethods)
+	for _, m := range methods {
+		if m == socks5AuthNone {
+			method = socks5AuthNone
+			break
+		}
+	}
+	_, err = conn.Write([]byte{socks5Version, method})
+	if err != nil {
+		return xerrors.Errorf("write auth method: %w", err)
+	}
+	if method == socks5AuthNoMethods {
+		return xerrors.New("client doesn't support connecting without authentication")
+	}
+
+	request := make([]byte, 4)
+	_, err = io.ReadFull(conn, request)
+	if err != nil {
+		return xerrors.Errorf("read request: %w", err)
+	}
+	if request[1] != socks5CommandConnect {
+		_ = writeSOCKS5Reply(conn, socks5ReplyCommandNotSupported)
+		return xerrors.Errorf("unsupported command %d", request[1])
+	}
+	var host string
+	switch request[3] {
+	case socks5AddrIPv4, socks5AddrIPv6:
+		// Workspaces are only reachable by name.
+		_ = writeSOCKS5Reply(conn, socks5ReplyAddressTypeNotSupported)
+		return xerrors.New("workspaces must be dialed by name")
+	case socks5AddrDomain:
+		length := make([]byte, 1)
+		_, err = io.ReadFull(conn, length)
+		if err != nil {
+			return xerrors.Errorf("read host length: %w", err)
+		}
+		rawHost := make([]byte, length[0])
+		_, err = io.ReadFull(conn, rawHost)
+		if err != nil {
+			return xerrors.Errorf("read host: %w", err)
+		}
+		host = string(rawHost)
+	default:
+		_ = writeSOCKS5Reply(conn, socks5ReplyAddressTypeNotSupported)
+		return xerrors.Errorf("unsupported address type %d", request[3])
+	}
+	rawPort := make([]byte, 2)
+	_, err = io.ReadFull(conn, rawPort)
+	if err != nil {
+		return xerrors.Errorf("read port: %w", err)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(rawPort))))
+
+	remoteConn, err := p.dial(ctx, address)
+	if err != nil {
+		reply := byte(socks5ReplyGeneralFailure)
+		if xerrors.As(err, new(*proxyHostError)) {
+			reply = socks5ReplyHostUnreachable
+		}
+		_ = writeSOCKS5Reply(conn, reply)
+		return err
+	}
+	defer remoteConn.Close()
+	err = writeSOCKS5Reply(conn, socks5ReplySucceeded)
+	if err != nil {
+		return xerrors.Errorf("write reply: %w", err)
+	}
+	agentssh.Bicopy(ctx, conn, remoteConn)
+	return nil
+}
+
+func writeSOCKS5Reply(w io.Writer, reply byte) error {
+	// The bound address is of no use to clients, so it's always empty.
+	_, err := w.Write([]byte{socks5Version, reply, 0x00, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+// serveHTTP tunnels CONNECT requests, and forwards requests with an absolute
+// URL to the workspace named by their host.
+func (p *workspaceProxy) serveHTTP(ctx context.Context, conn *bufferedConn) error {
+	req, err := http.ReadRequest(conn.r)
+	if err != nil {
+		return xerrors.Errorf("read request: %w", err)
+	}
+	address := req.Host
+	if req.Method != http.MethodConnect {
+		if req.URL.Scheme != "http" {
+			_ = writeHTTPProxyError(conn, http.StatusBadRequest, "Only http URLs can be proxied without CONNECT.")
+			return xerrors.Errorf("unsupported request %s %s", req.Method, req.RequestURI)
+		}
+		address = req.URL.Host
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "80")
+	}
+
+	remoteConn, err := p.dial(ctx, address)
+	if err != nil {
+		status := http.StatusBadGateway
+		if xerrors.As(err, new(*proxyHostError)) {
+			status = http.StatusNotFound
+		}
+		_ = writeHTTPProxyError(conn, status, err.Error())
+		return err
+	}
+	defer remoteConn.Close()
+
+	if req.Method == http.MethodConnect {
+		_, err = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		if err != nil {
+			return xerrors.Errorf("write response: %w", err)
+		}
+	} else {
+		// Later requests of the client may be for other hosts, so the
+		// connection is only used for this one.
+		req.RequestURI = ""
+		req.Close = true
+		req.Header.Del("Proxy-Connection")
+		req.Header.Del("Proxy-Authorization")
+		err = req.Write(remoteConn)
+		if err != nil {
+			return xerrors.Errorf("write request: %w", err)
+		}
+	}
+	agentssh.Bicopy(ctx, conn, remoteConn)
+	return nil
+}
+
+func writeHTTPProxyError(w io.Writer, status int, message string) error {
+	res := &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}},
+		ContentLength: int64(len(message)),
+		Body:          io.NopCloser(strings.NewReader(message)),
+		Close:         true,
+	}
+	return res.Write(w)
+}
+
+// proxyHostError is returned for hosts that don't name a reachable
+// workspace agent.
+type proxyHostError struct {
+	host string
+	err  error
+}
+
+func (e *proxyHostError) Error() string {
+	return fmt.Sprintf("resolve %q: %s", e.host, e.err)
+}
+
+func (e *proxyHostError) Unwrap() error {
+	return e.err
+}
+
+// dial connects to the port of the workspace agent named by the host of
+// address.
+func (p *workspaceProxy) dial(ctx context.Context, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, xerrors.Errorf("split host port %q: %w", address, err)
+	}
+	agentID, err := p.resolve(ctx, host)
+	if err != nil {
+		return nil, &proxyHostError{host: host, err: err}
+	}
+	conn, err := p.agentConn(ctx, agentID)
+	if err != nil {
+		return nil, xerrors.Errorf("dial workspace agent: %w", err)
+	}
+	remoteConn, err := conn.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", port))
+	if err != nil {
+		p.evict(ctx, agentID, conn)
+		return nil, xerrors.Errorf("dial port %s of %q: %w", port, host, err)
+	}
+	return remoteConn, nil
+}
+
+// resolve returns the ID of the agent named by host, which is workspace,
+// agent.workspace or agent.workspace.owner.coder. The owner defaults to the
+// user, the agent may be omitted if the workspace has only one.
+func (p *workspaceProxy) resolve(ctx context.Context, host string) (uuid.UUID, error) {
+	owner, workspaceName, agentName, err := parseProxyHost(host)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	workspace, err := p.client.WorkspaceByOwnerAndName(ctx, owner, workspaceName, codersdk.WorkspaceOptions{})
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get workspace: %w", err)
+	}
+	if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart || workspace.LatestBuild.Job.CompletedAt == nil {
+		return uuid.Nil, xerrors.Errorf("workspace %q is not running", workspace.Name)
+	}
+
+	var agents []codersdk.WorkspaceAgent
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			if agentName == "" || strings.EqualFold(agent.Name, agentName) {
+				agents = append(agents, agent)
+			}
+		}
+	}
+	switch len(agents) {
+	case 0:
+		if agentName != "" {
+			return uuid.Nil, xerrors.Errorf("workspace %q has no agent named %q", workspace.Name, agentName)
+		}
+		return uuid.Nil, xerrors.Errorf("workspace %q has no agents", workspace.Name)
+	case 1:
+		return agents[0].ID, nil
+	default:
+		agentNames := make([]string, 0, len(agents))
+		for _, agent := range agents {
+			agentNames = append(agentNames, agent.Name)
+		}
+		return uuid.Nil, xerrors.Errorf("workspace %q has multiple agents, specify one of: %s", workspace.Name, strings.Join(agentNames, ", "))
+	}
+}
+
+// parseProxyHost splits a host into the owner, workspace and agent it names.
+func parseProxyHost(host string) (owner, workspace, agent string, err error) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if tailnet.IsCoderDNSName(host) {
+		labels := strings.Split(strings.TrimSuffix(host, "."+tailnet.CoderDNSSuffix), ".")
+		switch len(labels) {
+		case 2:
+			return labels[1], labels[0], "", nil
+		case 3:
+			return labels[2], labels[1], labels[0], nil
+		}
+		return "", "", "", xerrors.Errorf("expected workspace.owner.%[1]s or agent.workspace.owner.%[1]s", tailnet.CoderDNSSuffix)
+	}
+	labels := strings.Split(host, ".")
+	switch len(labels) {
+	case 1:
+		return codersdk.Me, labels[0], "", nil
+	case 2:
+		return codersdk.Me, labels[1], labels[0], nil
+	}
+	return "", "", "", xerrors.New("not the name of a workspace")
+}
+
+const (
+	// agentDialTimeout bounds how long dialing an agent takes, including
+	// waiting for it to become reachable.
+	agentDialTimeout = 30 * time.Second
+	// agentReachableTimeout is how long a cached connection gets to reach
+	// its agent after a dial through it failed, before it's evicted.
+	agentReachableTimeout = 5 * time.Second
+)
+
+// agentConn returns a connection to the agent, dialing it if there isn't
+// one yet. Concurrent dials of the same agent share a single connection.
+func (p *workspaceProxy) agentConn(ctx context.Context, agentID uuid.UUID) (*proxyAgentConn, error) {
+	p.mu.Lock()
+	conn, ok := p.conns[agentID]
+	p.mu.Unlock()
+	if ok {
+		return conn, nil
+	}
+
+	// The dial isn't done under the lock so connections to other agents
+	// aren't held up while the agent is dialed. Requests that give up
+	// waiting don't cancel the dial for the others.
+	dial := p.dials.DoChan(agentID.String(), func() (interface{}, error) {
+		return p.dialAgent(agentID)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-dial:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*proxyAgentConn), nil
+	}
+}
+
+// dialAgent dials the agent and caches the connection.
+func (p *workspaceProxy) dialAgent(agentID uuid.UUID) (*proxyAgentConn, error) {
+	// The connection is closed when the context it's dialed with is
+	// canceled, so the dial is bounded by canceling the context instead of
+	// a deadline.
+	ctx, cancel := context.WithCancel(p.ctx)
+	timer := time.AfterFunc(agentDialTimeout, cancel)
+	agentConn, err := p.client.DialWorkspaceAgent(ctx, agentID, &codersdk.DialWorkspaceAgentOptions{
+		Logger:         p.logger.With(slog.F("agent_id", agentID)),
+		BlockEndpoints: p.disableDirect,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	conn := &proxyAgentConn{WorkspaceAgentConn: agentConn, cancel: cancel}
+	if !timer.Stop() {
+		// The dial timed out as it completed.
+		conn.close()
+		return nil, xerrors.Errorf("timed out dialing agent after %s", agentDialTimeout)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		conn.close()
+		return nil, xerrors.New("proxy is closed")
+	}
+	p.conns[agentID] = conn
+	return conn, nil
+}
+
+// evict closes the connection to the agent and removes it from the cache, so
+// the next dial connects to the agent again. Connections that are still
+// reachable are kept, since dials also fail when nothing listens on a port.
+func (p *workspaceProxy) evict(ctx context.Context, agentID uuid.UUID, conn *proxyAgentConn) {
+	ctx, cancel := context.WithTimeout(ctx, agentReachableTimeout)
+	defer cancel()
+	if conn.AwaitReachable(ctx) {
+		return
+	}
+
+	p.mu.Lock()
+	if p.conns[agentID] != conn {
+		// Another dial evicted it already.
+		p.mu.Unlock()
+		return
+	}
+	delete(p.conns, agentID)
+	p.mu.Unlock()
+	conn.close()
+	p.logger.Debug(ctx, "evicted unreachable agent connection", slog.F("agent_id", agentID))
+}
+
+func (p *workspaceProxy) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for id, conn := range p.conns {
+		conn.close()
+		delete(p.conns, id)
+	}
+}
+
+// bufferedConn reads through the reader that was used to detect the
+// protocol of the client, so no bytes are lost.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
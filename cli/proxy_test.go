@@ -0,0 +1,95 @@
+package cli_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+//nolint:tparallel,paralleltest // Subtests share the proxy and the workspace.
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	workspace := runAgent(t, client, user.UserID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	workspace, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	agentName := workspace.LatestBuild.Resources[0].Agents[0].Name
+
+	// Reserve a port for the proxy.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	proxyAddress := l.Addr().String()
+	_ = l.Close()
+
+	inv, root := clitest.New(t, "proxy", "--listen", proxyAddress)
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t).Attach(inv)
+	errC := make(chan error)
+	go func() {
+		errC <- inv.WithContext(ctx).Run()
+	}()
+	pty.ExpectMatchContext(ctx, "proxy listening on")
+
+	t.Run("SOCKS5", func(t *testing.T) {
+		remote, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := setupTestListener(t, remote)
+
+		dialer, err := proxy.SOCKS5("tcp", proxyAddress, nil, proxy.Direct)
+		require.NoError(t, err)
+		conn, err := dialer.Dial("tcp", net.JoinHostPort(workspace.Name, port))
+		require.NoError(t, err)
+		defer conn.Close()
+		testDial(t, conn)
+
+		_, err = dialer.Dial("tcp", net.JoinHostPort("missing", port))
+		require.Error(t, err)
+	})
+
+	t.Run("CONNECT", func(t *testing.T) {
+		remote, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := setupTestListener(t, remote)
+
+		connect := func(host string) (net.Conn, *http.Response) {
+			conn, err := net.Dial("tcp", proxyAddress)
+			require.NoError(t, err)
+			address := net.JoinHostPort(host, port)
+			_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", address, address)
+			require.NoError(t, err)
+			res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			return conn, res
+		}
+
+		conn, res := connect(fmt.Sprintf("%s.%s.%s.coder", agentName, workspace.Name, coderdtest.FirstUserParams.Username))
+		defer conn.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		testDial(t, conn)
+
+		conn, res = connect("missing")
+		defer conn.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	cancel()
+	err = <-errC
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) roles() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "roles",
+		Short: "Manage custom roles that are assigned to users like the built-in roles",
+		Long: "Permissions are written as <resource>:<action>, a permission prefixed with ! denies the action.\n" + formatExamples(
+			example{
+				Description: "Create a site role that can read the deployment config",
+				Command:     "coder roles create config-reader --site-permissions deployment_config:read",
+			},
+			example{
+				Description: "Create an organization role that can edit templates",
+				Command:     "coder roles create template-editor --organization-role --organization-permissions template:read,template:update",
+			},
+		),
+		Aliases: []string{"role"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.createRole(),
+			r.listRoles(),
+			r.editRole(),
+			r.deleteRole(),
+		},
+	}
+	return cmd
+}
+
+// parsePermissions parses permissions written as <resource>:<action>, or
+// !<resource>:<action> to deny the action.
+func parsePermissions(perms []string) ([]codersdk.Permission, error) {
+	parsed := make([]codersdk.Permission, 0, len(perms))
+	for _, perm := range perms {
+		negate := strings.HasPrefix(perm, "!")
+		resource, action, ok := strings.Cut(strings.TrimPrefix(perm, "!"), ":")
+		if !ok || resource == "" || action == "" {
+			return nil, xerrors.Errorf("invalid permission %q, must be <resource>:<action>", perm)
+		}
+		parsed = append(parsed, codersdk.Permission{
+			Negate:       negate,
+			ResourceType: codersdk.RBACResource(resource),
+			Action:       action,
+		})
+	}
+	return parsed, nil
+}
+
+func formatPermissions(perms []codersdk.Permission) string {
+	formatted := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		prefix := ""
+		if perm.Negate {
+			prefix = "!"
+		}
+		formatted = append(formatted, prefix+string(perm.ResourceType)+":"+perm.Action)
+	}
+	return strings.Join(formatted, ",")
+}
+
+// roleByName returns the custom role with the given name. Organization roles
+// can also be referred to by the name they are assigned with.
+func roleByName(inv *clibase.Invocation, client *codersdk.Client, name string) (codersdk.CustomRole, error) {
+	roles, err := client.CustomRoles(inv.Context())
+	if err != nil {
+		return codersdk.CustomRole{}, xerrors.Errorf("get roles: %w", err)
+	}
+	var found []codersdk.CustomRole
+	for _, role := range roles {
+		if strings.EqualFold(role.AssignedName(), name) {
+			return role, nil
+		}
+		if strings.EqualFold(role.Name, name) {
+			found = append(found, role)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return codersdk.CustomRole{}, xerrors.Errorf("role %q not found", name)
+	case 1:
+		return found[0], nil
+	default:
+		return codersdk.CustomRole{}, xerrors.Errorf("role %q exists in several organizations, use the name it is assigned with", name)
+	}
+}
+
+func permissionOptions(site, org, user *[]string, verb string) clibase.OptionSet {
+	return clibase.OptionSet{
+		{
+			Flag:        "site-permissions",
+			Description: verb + " the permissions on all resources of the deployment. Only site roles can have site permissions.",
+			Value:       clibase.StringArrayOf(site),
+		},
+		{
+			Flag:        "organization-permissions",
+			Description: verb + " the permissions on the resources of the organization of the role. Only organization roles can have organization permissions.",
+			Value:       clibase.StringArrayOf(org),
+		},
+		{
+			Flag:        "user-permissions",
+			Description: verb + " the permissions on the resources owned by the users with the role.",
+			Value:       clibase.StringArrayOf(user),
+		},
+	}
+}
+
+func (r *RootCmd) createRole() *clibase.Cmd {
+	var (
+		displayName      string
+		organizationRole bool
+		sitePerms        []string
+		orgPerms         []string
+		userPerms        []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a custom role",
+		Long:  "A role can only grant permissions that you have yourself.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			req := codersdk.CreateCustomRoleRequest{
+				Name:        inv.Args[0],
+				DisplayName: displayName,
+			}
+			var err error
+			if req.SitePermissions, err = parsePermissions(sitePerms); err != nil {
+				return err
+			}
+			if req.OrganizationPermissions, err = parsePermissions(orgPerms); err != nil {
+				return err
+			}
+			if req.UserPermissions, err = parsePermissions(userPerms); err != nil {
+				return err
+			}
+			if organizationRole {
+				org, err := CurrentOrganization(inv, client)
+				if err != nil {
+					return xerrors.Errorf("get current organization: %w", err)
+				}
+				req.OrganizationID = &org.ID
+			}
+
+			role, err := client.CreateCustomRole(inv.Context(), req)
+			if err != nil {
+				return xerrors.Errorf("create role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Created role %s! Assign it as %s.\n",
+				cliui.DefaultStyles.Keyword.Render(role.DisplayName),
+				cliui.DefaultStyles.Code.Render(role.AssignedName()),
+			)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "The name of the role shown in the UI. Defaults to the name of the role.",
+			Value:       clibase.StringOf(&displayName),
+		},
+		{
+			Flag:        "organization-role",
+			Description: "Create the role in the current organization. Organization roles are assigned to members of the organization.",
+			Value:       clibase.BoolOf(&organizationRole),
+		},
+	}
+	cmd.Options = append(cmd.Options, permissionOptions(&sitePerms, &orgPerms, &userPerms, "Grant")...)
+	return cmd
+}
+
+type roleRow struct {
+	// For JSON format:
+	codersdk.CustomRole `table:"-"`
+
+	// For table format:
+	Name                    string `json:"-" table:"name,default_sort"`
+	DisplayName             string `json:"-" table:"display name"`
+	SitePermissions         string `json:"-" table:"site permissions"`
+	OrganizationPermissions string `json:"-" table:"organization permissions"`
+	UserPermissions         string `json:"-" table:"user permissions"`
+	ID                      string `json:"-" table:"id"`
+}
+
+func roleRowFromRole(role codersdk.CustomRole) roleRow {
+	return roleRow{
+		CustomRole:              role,
+		Name:                    role.AssignedName(),
+		DisplayName:             role.DisplayName,
+		SitePermissions:         formatPermissions(role.SitePermissions),
+		OrganizationPermissions: formatPermissions(role.OrganizationPermissions),
+		UserPermissions:         formatPermissions(role.UserPermissions),
+		ID:                      role.ID.String(),
+	}
+}
+
+func (r *RootCmd) listRoles() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]roleRow{}, []string{"name", "display name", "site permissions", "organization permissions", "user permissions"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List custom roles",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			roles, err := client.CustomRoles(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("get roles: %w", err)
+			}
+
+			if len(roles) == 0 {
+				cliui.Infof(
+					inv.Stderr,
+					"No custom roles found.\n",
+				)
+			}
+
+			rows := make([]roleRow, 0, len(roles))
+			for _, role := range roles {
+				rows = append(rows, roleRowFromRole(role))
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) editRole() *clibase.Cmd {
+	var (
+		displayName string
+		sitePerms   []string
+		orgPerms    []string
+		userPerms   []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "edit <name>",
+		Short: "Edit a custom role",
+		Long:  "The permissions that are set replace the existing permissions of the role, the permissions that are not set are kept.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			role, err := roleByName(inv, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			var req codersdk.UpdateCustomRoleRequest
+			flags := inv.ParsedFlags()
+			if flags.Lookup("display-name").Changed {
+				req.DisplayName = &displayName
+			}
+			if flags.Lookup("site-permissions").Changed {
+				if req.SitePermissions, err = parsePermissions(sitePerms); err != nil {
+					return err
+				}
+			}
+			if flags.Lookup("organization-permissions").Changed {
+				if req.OrganizationPermissions, err = parsePermissions(orgPerms); err != nil {
+					return err
+				}
+			}
+			if flags.Lookup("user-permissions").Changed {
+				if req.UserPermissions, err = parsePermissions(userPerms); err != nil {
+					return err
+				}
+			}
+
+			role, err = client.UpdateCustomRole(inv.Context(), role.ID, req)
+			if err != nil {
+				return xerrors.Errorf("update role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated role %s!\n", cliui.DefaultStyles.Keyword.Render(role.DisplayName))
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "The name of the role shown in the UI.",
+			Value:       clibase.StringOf(&displayName),
+		},
+	}
+	cmd.Options = append(cmd.Options, permissionOptions(&sitePerms, &orgPerms, &userPerms, "Replace")...)
+	return cmd
+}
+
+func (r *RootCmd) deleteRole() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name>",
+		Short: "Delete a custom role and remove it from every user",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			role, err := roleByName(inv, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteCustomRole(inv.Context(), role.ID)
+			if err != nil {
+				return xerrors.Errorf("delete role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted role %s!\n", cliui.DefaultStyles.Keyword.Render(role.DisplayName))
+			return nil
+		},
+	}
+	return cmd
+}
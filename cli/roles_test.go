@@ -0,0 +1,78 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "roles", "create", "config-reader",
+		"--site-permissions", "deployment_config:read,!deployment_stats:read",
+	)
+	clitest.SetupConfig(t, client, root)
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "roles", "create", "template-editor",
+		"--organization-role",
+		"--organization-permissions", "template:update",
+	)
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "roles", "edit", "config-reader", "--display-name", "Config Reader", "--user-permissions", "workspace:read")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "roles", "list", "-o", "json")
+	clitest.SetupConfig(t, client, root)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var roles []codersdk.CustomRole
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &roles))
+	require.Len(t, roles, 2)
+	require.Equal(t, "config-reader", roles[0].Name)
+	require.Equal(t, "Config Reader", roles[0].DisplayName)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceDeploymentValues, Action: codersdk.ActionRead},
+		{Negate: true, ResourceType: codersdk.ResourceDeploymentStats, Action: codersdk.ActionRead},
+	}, roles[0].SitePermissions)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceWorkspace, Action: codersdk.ActionRead},
+	}, roles[0].UserPermissions)
+	require.Equal(t, "template-editor", roles[1].Name)
+	require.Equal(t, &owner.OrganizationID, roles[1].OrganizationID)
+
+	inv, root = clitest.New(t, "roles", "delete", "template-editor")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "roles", "delete", "template-editor")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "not found")
+
+	inv, root = clitest.New(t, "roles", "create", "invalid", "--site-permissions", "deployment_config")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "invalid permission")
+}
@@ -79,6 +79,7 @@ var errUnauthenticated = xerrors.New(notLoggedInMessage)
 func (r *RootCmd) Core() []*clibase.Cmd {
 	// Please re-sort this list alphabetically if you change it!
 	return []*clibase.Cmd{
+		r.connectionLog(),
 		r.dotfiles(),
 		r.login(),
 		r.logout(),
@@ -86,20 +87,29 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
+		r.roles(),
 		r.state(),
 		r.templates(),
 		r.tokens(),
 		r.users(),
 		r.version(defaultVersionInfo),
+		r.webhooks(),
 
 		// Workspace Commands
+		r.autoupdate(),
 		r.configSSH(),
+		r.cp(),
 		r.create(),
 		r.deleteWorkspace(),
+		r.inhibitAutostop(),
 		r.list(),
+		r.logs(),
 		r.ping(),
+		r.port(),
+		r.proxy(),
 		r.rename(),
 		r.schedules(),
+		r.sessions(),
 		r.show(),
 		r.speedtest(),
 		r.ssh(),
@@ -20,6 +20,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"net/mail"
 	"net/url"
 	"os"
 	"os/signal"
@@ -74,7 +75,9 @@ import (
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauthpki"
+	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
@@ -247,6 +250,46 @@ func createOIDCConfig(ctx context.Context, vals *codersdk.DeploymentValues) (*co
 	}, nil
 }
 
+func createLDAPConfig(vals *codersdk.DeploymentValues) (*coderd.LDAPConfig, error) {
+	if vals.LDAP.UserSearchBaseDN == "" {
+		return nil, xerrors.Errorf("LDAP user search base DN must be set!")
+	}
+	if vals.LDAP.BindDN != "" && vals.LDAP.BindPassword == "" {
+		return nil, xerrors.Errorf("LDAP bind password must be set if a bind DN is set!")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if vals.LDAP.CAFile != "" {
+		data, err := os.ReadFile(vals.LDAP.CAFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", vals.LDAP.CAFile.String(), err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return nil, xerrors.Errorf("failed to parse CA certificate in ldap-ca-file")
+		}
+		tlsConfig.RootCAs = caPool
+	}
+	return &coderd.LDAPConfig{
+		URL:                 vals.LDAP.URL.String(),
+		TLSConfig:           tlsConfig,
+		StartTLS:            vals.LDAP.StartTLS.Value(),
+		BindDN:              vals.LDAP.BindDN.String(),
+		BindPassword:        vals.LDAP.BindPassword.String(),
+		UserSearchBaseDN:    vals.LDAP.UserSearchBaseDN.String(),
+		UserSearchFilter:    vals.LDAP.UserSearchFilter.String(),
+		UsernameAttribute:   vals.LDAP.UsernameAttribute.String(),
+		EmailAttribute:      vals.LDAP.EmailAttribute.String(),
+		AllowSignups:        vals.LDAP.AllowSignups.Value(),
+		GroupAttribute:      vals.LDAP.GroupAttribute.String(),
+		CreateMissingGroups: vals.LDAP.GroupAutoCreate.Value(),
+		GroupFilter:         vals.LDAP.GroupRegexFilter.Value(),
+		GroupMapping:        vals.LDAP.GroupMapping.Value,
+	}, nil
+}
+
 func afterCtx(ctx context.Context, fn func()) {
 	go func() {
 		<-ctx.Done()
@@ -275,6 +318,12 @@ func enablePrometheus(
 	}
 	afterCtx(ctx, closeWorkspacesFunc)
 
+	closePrebuildsFunc, err := prometheusmetrics.Prebuilds(ctx, options.PrometheusRegistry, options.Database, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("register prebuilds prometheus metric: %w", err)
+	}
+	afterCtx(ctx, closePrebuildsFunc)
+
 	if vals.Prometheus.CollectAgentStats {
 		closeAgentStatsFunc, err := prometheusmetrics.AgentStats(ctx, logger, options.PrometheusRegistry, options.Database, time.Now(), 0)
 		if err != nil {
@@ -361,6 +410,20 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				return xerrors.Errorf("access-url must include a scheme (e.g. 'http://' or 'https://)")
 			}
 
+			if !codersdk.WorkspaceNetworking(vals.WorkspaceNetworking.String()).Valid() {
+				return xerrors.Errorf("workspace-networking must be one of %q, %q or %q",
+					codersdk.WorkspaceNetworkingDisabled, codersdk.WorkspaceNetworkingOwner, codersdk.WorkspaceNetworkingGroup)
+			}
+
+			if vals.Notifications.Email.Smarthost.String() != "" {
+				if _, _, err := net.SplitHostPort(vals.Notifications.Email.Smarthost.String()); err != nil {
+					return xerrors.Errorf("notifications-email-smarthost must be a host:port: %w", err)
+				}
+				if _, err := mail.ParseAddress(vals.Notifications.Email.From.String()); err != nil {
+					return xerrors.Errorf("notifications-email-from must be a valid email address if notifications-email-smarthost is set: %w", err)
+				}
+			}
+
 			// Disable rate limits if the `--dangerous-disable-rate-limits` flag
 			// was specified.
 			loginRateLimit := 60
@@ -686,6 +749,14 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.OIDCConfig = oc
 			}
 
+			if vals.LDAP.URL != "" {
+				lc, err := createLDAPConfig(vals)
+				if err != nil {
+					return xerrors.Errorf("create ldap config: %w", err)
+				}
+				options.LDAPConfig = lc
+			}
+
 			if vals.InMemoryDatabase {
 				// This is only used for testing.
 				options.Database = dbfake.New()
@@ -711,6 +782,8 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.Database = dbmetrics.New(options.Database, options.PrometheusRegistry)
 			}
 
+			options.NotificationsEnqueuer = notifications.NewEnqueuer(options.Database, options.Pubsub, vals.Notifications.Email.Smarthost.String() != "")
+
 			var deploymentID string
 			err = options.Database.InTx(func(tx database.Store) error {
 				// This will block until the lock is acquired, and will be
@@ -944,7 +1017,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger, options.Database)
+			purger := dbpurge.New(ctx, logger, options.Database, vals.ConnectionLogRetention.Value())
 			defer purger.Close()
 
 			// Wrap the server in middleware that redirects to the access URL if
@@ -1010,7 +1083,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 			autobuildTicker := time.NewTicker(vals.AutobuildPollInterval.Value())
 			defer autobuildTicker.Stop()
-			autobuildExecutor := autobuild.NewExecutor(ctx, options.Database, coderAPI.TemplateScheduleStore, logger, autobuildTicker.C)
+			autobuildExecutor := autobuild.NewExecutor(ctx, options.Database, options.NotificationsEnqueuer, coderAPI.TemplateScheduleStore, logger, autobuildTicker.C)
 			autobuildExecutor.Run()
 
 			hangDetectorTicker := time.NewTicker(vals.JobHangDetectorInterval.Value())
@@ -1019,6 +1092,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			hangDetector.Start()
 			defer hangDetector.Close()
 
+			prebuildsTicker := time.NewTicker(prebuilds.ReconcileInterval)
+			defer prebuildsTicker.Stop()
+			prebuildsReconciler := prebuilds.New(ctx, options.Database, logger, prebuildsTicker.C)
+			prebuildsReconciler.Start()
+			defer prebuildsReconciler.Close()
+
 			// Currently there is no way to ask the server to shut
 			// itself down, so any exit signal will result in a non-zero
 			// exit of the server.
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) sessions() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "sessions",
+		Short: "List and replay recorded sessions",
+		Long: "Interactive sessions are recorded if session recording is enabled for the deployment or the template of the workspace.\n" + formatExamples(
+			example{
+				Description: "List the recorded sessions of a workspace",
+				Command:     "coder sessions list my-workspace",
+			},
+			example{
+				Description: "Replay a session at twice the speed",
+				Command:     "coder sessions replay 8f7a2a0c-6b7d-4d0b-9d6e-2c1f0b6a3e41 --speed 2",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.listSessions(),
+			r.replaySession(),
+		},
+	}
+	return cmd
+}
+
+type sessionRecordingRow struct {
+	// For JSON format:
+	codersdk.WorkspaceSessionRecording `table:"-"`
+
+	// For table format:
+	ID        string `json:"-" table:"id"`
+	Type      string `json:"-" table:"type"`
+	Command   string `json:"-" table:"command"`
+	CreatedAt string `json:"-" table:"created at,default_sort"`
+	EndedAt   string `json:"-" table:"ended at"`
+	Size      int64  `json:"-" table:"size"`
+	Input     bool   `json:"-" table:"input"`
+}
+
+func sessionRecordingRowFromRecording(recording codersdk.WorkspaceSessionRecording) sessionRecordingRow {
+	endedAt := "Recording"
+	if recording.EndedAt != nil {
+		endedAt = recording.EndedAt.Format(time.RFC3339)
+	}
+	return sessionRecordingRow{
+		WorkspaceSessionRecording: recording,
+		ID:                        recording.ID.String(),
+		Type:                      string(recording.Type),
+		Command:                   recording.Command,
+		CreatedAt:                 recording.CreatedAt.Format(time.RFC3339),
+		EndedAt:                   endedAt,
+		Size:                      recording.Size,
+		Input:                     recording.IncludesInput,
+	}
+}
+
+func (r *RootCmd) listSessions() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]sessionRecordingRow{}, []string{"id", "type", "command", "created at", "ended at", "size"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the recorded sessions of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			recordings, err := client.WorkspaceSessionRecordings(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list session recordings: %w", err)
+			}
+			if len(recordings) == 0 {
+				cliui.Infof(inv.Stdout, "No sessions have been recorded.\n")
+				return nil
+			}
+
+			rows := make([]sessionRecordingRow, 0, len(recordings))
+			for _, recording := range recordings {
+				rows = append(rows, sessionRecordingRowFromRecording(recording))
+			}
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) replaySession() *clibase.Cmd {
+	var (
+		speed  int64
+		output string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "replay <recording-id>",
+		Short: "Replay a recorded session in the terminal",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			id, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("parse recording ID: %w", err)
+			}
+			if speed < 1 {
+				return xerrors.New("speed must be at least 1")
+			}
+
+			cast, err := client.WorkspaceSessionRecordingCast(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("get session recording: %w", err)
+			}
+			defer cast.Close()
+
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return xerrors.Errorf("create output file: %w", err)
+				}
+				defer f.Close()
+				_, err = io.Copy(f, cast)
+				if err != nil {
+					return xerrors.Errorf("write output file: %w", err)
+				}
+				return f.Close()
+			}
+
+			return replayCast(ctx, inv.Stdout, cast, speed)
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "speed",
+			Description: "Replay the session this many times faster than it was recorded.",
+			Default:     "1",
+			Value:       clibase.Int64Of(&speed),
+		},
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "Save the recording to a file in asciicast v2 format instead of replaying it.",
+			Value:         clibase.StringOf(&output),
+		},
+	}
+	return cmd
+}
+
+// replayCast writes the output events of an asciicast v2 file to w with the
+// recorded timing.
+func replayCast(ctx context.Context, w io.Writer, cast io.Reader, speed int64) error {
+	scanner := bufio.NewScanner(cast)
+	// Events hold up to a chunk of output.
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return xerrors.Errorf("read header: %w", err)
+		}
+		return xerrors.New("the recording is empty")
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return xerrors.Errorf("parse header: %w", err)
+	}
+	if header.Version != 2 {
+		return xerrors.Errorf("unsupported asciicast version %d", header.Version)
+	}
+
+	start := time.Now()
+	for scanner.Scan() {
+		var (
+			event   []json.RawMessage
+			elapsed float64
+			code    string
+			data    string
+		)
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			return xerrors.Errorf("parse event %q: %w", scanner.Text(), err)
+		}
+		if err := json.Unmarshal(event[0], &elapsed); err != nil {
+			return xerrors.Errorf("parse event time: %w", err)
+		}
+		if err := json.Unmarshal(event[1], &code); err != nil {
+			return xerrors.Errorf("parse event code: %w", err)
+		}
+		if code != "o" {
+			continue
+		}
+		if err := json.Unmarshal(event[2], &data); err != nil {
+			return xerrors.Errorf("parse event data: %w", err)
+		}
+
+		at := start.Add(time.Duration(elapsed * float64(time.Second) / float64(speed)))
+		if wait := time.Until(at); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
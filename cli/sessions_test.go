@@ -0,0 +1,117 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+//nolint:tparallel,paralleltest // Subtests share the same recording.
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, err := client.UpdateTemplateMeta(ctx, workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		RecordSessions: ptr.Ref(true),
+	})
+	require.NoError(t, err)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer agentCloser.Close()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	// Record a short session.
+	conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+	require.NoError(t, err)
+	stdin, err := session.StdinPipe()
+	require.NoError(t, err)
+	err = session.Shell()
+	require.NoError(t, err)
+	_, err = stdin.Write([]byte("echo $((40 + 2))\nexit\n"))
+	require.NoError(t, err)
+	_ = session.Wait()
+	_ = session.Close()
+
+	var recordings []codersdk.WorkspaceSessionRecording
+	require.Eventually(t, func() bool {
+		recordings, err = client.WorkspaceSessionRecordings(ctx, workspace.ID)
+		return err == nil && len(recordings) == 1 && recordings[0].EndedAt != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+	recording := recordings[0]
+
+	t.Run("List", func(t *testing.T) {
+		inv, root := clitest.New(t, "sessions", "list", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Regexp(t, recording.ID.String()+`\s+ssh`, stdout.String())
+	})
+
+	t.Run("ListJSON", func(t *testing.T) {
+		inv, root := clitest.New(t, "sessions", "list", workspace.Name, "--output=json")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var got []codersdk.WorkspaceSessionRecording
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+		require.Len(t, got, 1)
+		require.Equal(t, recording.ID, got[0].ID)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		inv, root := clitest.New(t, "sessions", "replay", recording.ID.String(), "--speed", "1000")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "42\r\n")
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "session.cast")
+		inv, root := clitest.New(t, "sessions", "replay", recording.ID.String(), "--output", output)
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		require.EqualValues(t, recording.Size, len(data))
+	})
+}
@@ -26,6 +26,9 @@ func (r *RootCmd) show() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("get workspace: %w", err)
 			}
+			if workspace.TemplateDeprecationMessage != "" {
+				warnDeprecatedTemplate(inv.Stderr, workspace.TemplateName, workspace.TemplateDeprecationMessage)
+			}
 			return cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
 				WorkspaceName: workspace.Name,
 				ServerVersion: buildInfo.Version,
@@ -51,6 +51,9 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		noWait         bool
 		logDirPath     string
 		remoteForward  string
+		listSessions   bool
+		attachSession  string
+		readOnly       bool
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -58,13 +61,37 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		Use:         "ssh <workspace>",
 		Short:       "Start a shell into a workspace",
 		Middleware: clibase.Chain(
-			clibase.RequireNArgs(1),
+			clibase.RequireRangeArgs(0, 1),
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) (retErr error) {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
+			if attachSession != "" {
+				sessionID, err := uuid.Parse(attachSession)
+				if err != nil {
+					return xerrors.Errorf("invalid session ID %q: %w", attachSession, err)
+				}
+				// Users invited to a session can't read the workspace, so
+				// the workspace is optional and looked up from the invite.
+				var agentID uuid.UUID
+				if len(inv.Args) == 0 {
+					agentID, err = invitedReconnectingPTYSessionAgent(ctx, client, sessionID)
+				} else {
+					var workspaceAgent codersdk.WorkspaceAgent
+					_, workspaceAgent, err = getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+					agentID = workspaceAgent.ID
+				}
+				if err != nil {
+					return err
+				}
+				return attachReconnectingPTY(ctx, inv, client, agentID, sessionID, readOnly)
+			}
+			if len(inv.Args) != 1 {
+				return xerrors.Errorf("wanted 1 args but got %v %v", len(inv.Args), inv.Args)
+			}
+
 			logger := slog.Make() // empty logger
 			defer func() {
 				if retErr != nil {
@@ -134,6 +161,9 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			if err != nil {
 				return err
 			}
+			if listSessions {
+				return listReconnectingPTYSessions(ctx, inv, client, workspaceAgent.ID)
+			}
 
 			// Select the startup script behavior based on template configuration or flags.
 			var wait bool
@@ -200,6 +230,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
 				Logger:         logger,
 				BlockEndpoints: r.disableDirect,
+				EnableDNS:      true,
 			})
 			if err != nil {
 				return xerrors.Errorf("dial agent: %w", err)
@@ -453,6 +484,21 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			FlagShorthand: "R",
 			Value:         clibase.StringOf(&remoteForward),
 		},
+		{
+			Flag:        "list-sessions",
+			Description: "List the reconnecting terminal sessions running in the workspace, such as those opened from the dashboard, instead of starting a shell.",
+			Value:       clibase.BoolOf(&listSessions),
+		},
+		{
+			Flag:        "attach",
+			Description: "Attach to the reconnecting terminal session with the given ID instead of starting a shell. The workspace may be omitted when attaching to a session you were invited to. Press Ctrl-] to detach.",
+			Value:       clibase.StringOf(&attachSession),
+		},
+		{
+			Flag:        "read-only",
+			Description: "Attach to the session without being able to write to it.",
+			Value:       clibase.BoolOf(&readOnly),
+		},
 	}
 	return cmd
 }
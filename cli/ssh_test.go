@@ -493,6 +493,59 @@ func TestSSH(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, ents, 1, "expected one file in logdir %s", logDir)
 	})
+	t.Run("Sessions", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("ConPTY appears to be inconsistent on Windows.")
+		}
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		defer func() {
+			_ = agentCloser.Close()
+		}()
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		sessionID := uuid.New()
+		session, err := client.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+			AgentID:   resources[0].Agents[0].ID,
+			Reconnect: sessionID,
+			Width:     80,
+			Height:    80,
+			Command:   "bash",
+		})
+		require.NoError(t, err)
+		defer session.Close()
+		require.Eventually(t, func() bool {
+			sessions, err := client.WorkspaceAgentReconnectingPTYSessions(ctx, resources[0].Agents[0].ID)
+			return err == nil && len(sessions.Sessions) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		inv, root := clitest.New(t, "ssh", workspace.Name, "--list-sessions")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Regexp(t, sessionID.String()+`\s+bash`, stdout.String())
+
+		inv, root = clitest.New(t, "ssh", workspace.Name, "--attach", sessionID.String())
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		w := clitest.StartWithWaiter(t, inv.WithContext(ctx))
+		pty.ExpectMatch("Attached to session")
+
+		pty.WriteLine("echo $((40 + 2))")
+		pty.ExpectMatch("42")
+		pty.Write(0x1d) // Ctrl-]
+		w.RequireSuccess()
+	})
 }
 
 //nolint:paralleltest // This test uses t.Setenv, parent test MUST NOT be parallel.
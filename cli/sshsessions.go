@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// reconnectingPTYDetachKey is Ctrl-], the same escape telnet uses. It is
+// never forwarded to the session.
+const reconnectingPTYDetachKey = 0x1d
+
+type reconnectingPTYSessionRow struct {
+	ID          uuid.UUID `table:"id"`
+	Command     string    `table:"command"`
+	CreatedAt   string    `table:"created at,default_sort"`
+	Connections int64     `table:"connections"`
+}
+
+// listReconnectingPTYSessions prints the live reconnecting PTY sessions of a
+// workspace agent.
+func listReconnectingPTYSessions(ctx context.Context, inv *clibase.Invocation, client *codersdk.Client, agentID uuid.UUID) error {
+	sessions, err := client.WorkspaceAgentReconnectingPTYSessions(ctx, agentID)
+	if err != nil {
+		return xerrors.Errorf("list sessions: %w", err)
+	}
+	if len(sessions.Sessions) == 0 {
+		cliui.Infof(inv.Stdout, "No sessions are running.\n")
+		return nil
+	}
+
+	rows := make([]reconnectingPTYSessionRow, 0, len(sessions.Sessions))
+	for _, session := range sessions.Sessions {
+		rows = append(rows, reconnectingPTYSessionRow{
+			ID:          session.ID,
+			Command:     session.Command,
+			CreatedAt:   session.CreatedAt.Format(time.RFC3339),
+			Connections: session.Connections,
+		})
+	}
+	out, err := cliui.DisplayTable(rows, "", nil)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(inv.Stdout, out)
+	return err
+}
+
+// invitedReconnectingPTYSessionAgent finds the agent of a session the user
+// has been invited to.
+func invitedReconnectingPTYSessionAgent(ctx context.Context, client *codersdk.Client, sessionID uuid.UUID) (uuid.UUID, error) {
+	invites, err := client.UserWorkspaceAgentPTYInvites(ctx, codersdk.Me)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("list invites: %w", err)
+	}
+	for _, invite := range invites {
+		if invite.SessionID == sessionID {
+			return invite.AgentID, nil
+		}
+	}
+	return uuid.Nil, xerrors.Errorf("you have not been invited to session %s, specify the workspace if it is yours", sessionID)
+}
+
+// attachReconnectingPTY attaches the terminal to a reconnecting PTY session
+// until the session ends or the user presses the detach key.
+func attachReconnectingPTY(ctx context.Context, inv *clibase.Invocation, client *codersdk.Client, agentID, sessionID uuid.UUID, readOnly bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		width  uint16 = 80
+		height uint16 = 24
+	)
+	stdoutFile, validOut := inv.Stdout.(*os.File)
+	stdinFile, validIn := inv.Stdin.(*os.File)
+	isTTY := validOut && validIn && isatty.IsTerminal(stdoutFile.Fd())
+	if isTTY {
+		w, h, err := term.GetSize(int(stdoutFile.Fd()))
+		if err == nil {
+			width, height = uint16(w), uint16(h)
+		}
+	}
+
+	conn, err := client.AttachWorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYAttachOpts{
+		AgentID:   agentID,
+		SessionID: sessionID,
+		Width:     width,
+		Height:    height,
+		ReadOnly:  readOnly,
+	})
+	if err != nil {
+		return xerrors.Errorf("attach to session: %w", err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	send := func(req codersdk.ReconnectingPTYRequest) error {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		_, err = conn.Write(data)
+		return err
+	}
+
+	if isTTY {
+		state, err := term.MakeRaw(int(stdinFile.Fd()))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = term.Restore(int(stdinFile.Fd()), state)
+		}()
+
+		if !readOnly {
+			windowChange := listenWindowSize(ctx)
+			go func() {
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-windowChange:
+					}
+					w, h, err := term.GetSize(int(stdoutFile.Fd()))
+					if err != nil {
+						continue
+					}
+					_ = send(codersdk.ReconnectingPTYRequest{Width: uint16(w), Height: uint16(h)})
+				}
+			}()
+		}
+	}
+	_, _ = fmt.Fprintf(inv.Stderr, "Attached to session %s. Press Ctrl-] to detach.\r\n", sessionID)
+
+	go func() {
+		defer cancel()
+		buf := make([]byte, 1024)
+		for {
+			n, err := inv.Stdin.Read(buf)
+			if n > 0 {
+				data := buf[:n]
+				detach := bytes.IndexByte(data, reconnectingPTYDetachKey)
+				if detach >= 0 {
+					data = data[:detach]
+				}
+				if len(data) > 0 && !readOnly {
+					if err := send(codersdk.ReconnectingPTYRequest{Data: string(data)}); err != nil {
+						return
+					}
+				}
+				if detach >= 0 {
+					return
+				}
+			}
+			if err != nil {
+				// Read-only viewers keep watching after stdin closes.
+				if readOnly {
+					<-ctx.Done()
+				}
+				return
+			}
+		}
+	}()
+
+	_, err = io.Copy(inv.Stdout, conn)
+	if err != nil && ctx.Err() == nil {
+		return xerrors.Errorf("copy output: %w", err)
+	}
+	return nil
+}
@@ -39,6 +39,19 @@ func (r *RootCmd) start() *clibase.Cmd {
 			if err != nil {
 				return err
 			}
+			if template.Deprecated {
+				warnDeprecatedTemplate(inv.Stderr, template.Name, template.DeprecationMessage)
+			}
+			if workspace.Outdated && (template.RequireActiveVersion || workspace.AutomaticUpdates == codersdk.AutomaticUpdatesAlways) {
+				me, err := client.User(inv.Context(), codersdk.Me)
+				if err != nil {
+					return xerrors.Errorf("get current user: %w", err)
+				}
+				// Workspaces are only updated when their owner starts them.
+				if me.ID == workspace.OwnerID {
+					cliui.Info(inv.Stdout, "The workspace will be updated to the active template version.")
+				}
+			}
 
 			buildOptions, err := asWorkspaceBuildParameters(parameterFlags.buildOptions)
 			if err != nil {
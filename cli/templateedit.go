@@ -28,6 +28,8 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		allowUserCancelWorkspaceJobs  bool
 		allowUserAutostart            bool
 		allowUserAutostop             bool
+		deprecationMessage            string
+		requireActiveVersion          bool
 	)
 	client := new(codersdk.Client)
 
@@ -109,6 +111,12 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				AllowUserAutostart:           allowUserAutostart,
 				AllowUserAutostop:            allowUserAutostop,
 			}
+			if inv.ParsedFlags().Changed("deprecated") {
+				req.DeprecationMessage = &deprecationMessage
+			}
+			if inv.ParsedFlags().Changed("require-active-version") {
+				req.RequireActiveVersion = &requireActiveVersion
+			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
 			if err != nil {
@@ -204,6 +212,16 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Default:     "true",
 			Value:       clibase.BoolOf(&allowUserAutostop),
 		},
+		{
+			Flag:        "deprecated",
+			Description: "Deprecate the template with a message that tells users what to use instead. Deprecated templates can't be used to create new workspaces. Pass an empty message to undeprecate the template.",
+			Value:       clibase.StringOf(&deprecationMessage),
+		},
+		{
+			Flag:        "require-active-version",
+			Description: "Require workspaces to use the active template version. Workspaces are updated to the active version when their owner starts them.",
+			Value:       clibase.BoolOf(&requireActiveVersion),
+		},
 		cliui.SkipPromptOption(),
 	}
 
@@ -40,6 +40,12 @@ func (r *RootCmd) templateList() *clibase.Cmd {
 				return nil
 			}
 
+			for _, template := range templates {
+				if template.Deprecated {
+					warnDeprecatedTemplate(inv.Stderr, template.Name, template.DeprecationMessage)
+				}
+			}
+
 			rows := templatesToRows(templates...)
 			out, err := formatter.Format(inv.Context(), rows)
 			if err != nil {
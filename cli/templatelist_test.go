@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"sort"
 	"testing"
 
@@ -106,4 +107,34 @@ func TestTemplateList(t *testing.T) {
 		pty.ExpectMatch(coderdtest.FirstUserParams.Username)
 		pty.ExpectMatch("Create one:")
 	})
+	t.Run("Deprecated", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--deprecated", "Use the kubernetes template.", "-y")
+		clitest.SetupConfig(t, client, root)
+
+		ctx, cancelFunc := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancelFunc()
+
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		inv, root = clitest.New(t, "templates", "list", "--column", "name,deprecated")
+		clitest.SetupConfig(t, client, root)
+		stdout := bytes.NewBuffer(nil)
+		stderr := bytes.NewBuffer(nil)
+		inv.Stdout = stdout
+		inv.Stderr = stderr
+
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "true")
+		require.Contains(t, stderr.String(), fmt.Sprintf("Template %q is deprecated", template.Name))
+		require.Contains(t, stderr.String(), "Use the kubernetes template.")
+	})
 }
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -60,6 +62,7 @@ type templateTableRow struct {
 	ActiveVersionID uuid.UUID                `json:"-" table:"active version id"`
 	UsedBy          string                   `json:"-" table:"used by"`
 	DefaultTTL      time.Duration            `json:"-" table:"default ttl"`
+	Deprecated      bool                     `json:"-" table:"deprecated"`
 }
 
 // templateToRows converts a list of templates to a list of templateTableRow for
@@ -77,8 +80,15 @@ func templatesToRows(templates ...codersdk.Template) []templateTableRow {
 			ActiveVersionID: template.ActiveVersionID,
 			UsedBy:          cliui.DefaultStyles.Fuchsia.Render(formatActiveDevelopers(template.ActiveUserCount)),
 			DefaultTTL:      (time.Duration(template.DefaultTTLMillis) * time.Millisecond),
+			Deprecated:      template.Deprecated,
 		}
 	}
 
 	return rows
 }
+
+// warnDeprecatedTemplate tells users that a template is deprecated, and what
+// to use instead.
+func warnDeprecatedTemplate(w io.Writer, name, message string) {
+	cliui.Warn(w, fmt.Sprintf("Template %q is deprecated", name), message)
+}
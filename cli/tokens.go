@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"golang.org/x/exp/slices"
@@ -22,6 +23,14 @@ func (r *RootCmd) tokens() *clibase.Cmd {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			example{
+				Description: "Create a token that can only read workspaces and their templates",
+				Command:     "coder tokens create --scope workspace:read,template:read,user:read",
+			},
+			example{
+				Description: "Create a token for a service account",
+				Command:     "coder tokens create --user ci-bot --lifetime 8760h",
+			},
 			example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -48,6 +57,8 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 	var (
 		tokenLifetime time.Duration
 		name          string
+		scope         []string
+		user          string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -58,10 +69,17 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			res, err := client.CreateToken(inv.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+			req := codersdk.CreateTokenRequest{
 				Lifetime:  tokenLifetime,
 				TokenName: name,
-			})
+			}
+			var err error
+			req.Scope, req.ScopeRules, err = codersdk.ParseAPIKeyScope(scope)
+			if err != nil {
+				return err
+			}
+
+			res, err := client.CreateToken(inv.Context(), user, req)
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
 			}
@@ -87,11 +105,39 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			Description:   "Specify a human-readable name.",
 			Value:         clibase.StringOf(&name),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Restrict the token to \"all\", \"application_connect\", or to rules written as <resource>:<action>[:<id>]. A rule with an ID only allows the listed resources of its type. The token can never do more than your roles allow.",
+			Default:     string(codersdk.APIKeyScopeAll),
+			Value:       clibase.StringArrayOf(&scope),
+		},
+		{
+			Flag:        "user",
+			Description: "Create the token for another user, such as a service account. Requires permission to manage the API keys of the user.",
+			Default:     codersdk.Me,
+			Value:       clibase.StringOf(&user),
+		},
 	}
 
 	return cmd
 }
 
+func formatTokenScope(key codersdk.APIKey) string {
+	if key.Scope != codersdk.APIKeyScopeCustom {
+		return string(key.Scope)
+	}
+	formatted := make([]string, 0, len(key.ScopeRules))
+	for _, rule := range key.ScopeRules {
+		value := string(rule.ResourceType) + ":" + rule.Action
+		if rule.ResourceID != nil {
+			value += ":" + rule.ResourceID.String()
+		}
+		formatted = append(formatted, value)
+	}
+	return strings.Join(formatted, ",")
+}
+
 // tokenListRow is the type provided to the OutputFormatter.
 type tokenListRow struct {
 	// For JSON format:
@@ -100,6 +146,7 @@ type tokenListRow struct {
 	// For table format:
 	ID        string    `json:"-" table:"id,default_sort"`
 	TokenName string    `json:"token_name" table:"name"`
+	Scope     string    `json:"-" table:"scope"`
 	LastUsed  time.Time `json:"-" table:"last used"`
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
@@ -111,6 +158,7 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		APIKey:    token.APIKey,
 		ID:        token.ID,
 		TokenName: token.TokenName,
+		Scope:     formatTokenScope(token.APIKey),
 		LastUsed:  token.LastUsed,
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
@@ -120,7 +168,7 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 
 func (r *RootCmd) listTokens() *clibase.Cmd {
 	// we only display the 'owner' column if the --all argument is passed in
-	defaultCols := []string{"id", "name", "last used", "expires at", "created at"}
+	defaultCols := []string{"id", "name", "scope", "last used", "expires at", "created at"}
 	if slices.Contains(os.Args, "-a") || slices.Contains(os.Args, "--all") {
 		defaultCols = append(defaultCols, "owner")
 	}
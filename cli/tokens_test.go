@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
@@ -51,6 +53,7 @@ func TestTokens(t *testing.T) {
 	res = buf.String()
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "ID")
+	require.Contains(t, res, "SCOPE")
 	require.Contains(t, res, "EXPIRES AT")
 	require.Contains(t, res, "CREATED AT")
 	require.Contains(t, res, "LAST USED")
@@ -68,6 +71,26 @@ func TestTokens(t *testing.T) {
 	require.Len(t, tokens, 1)
 	require.Equal(t, id, tokens[0].ID)
 
+	templateID := uuid.New()
+	scope := "workspace:read,template:read:" + templateID.String()
+	inv, root = clitest.New(t, "tokens", "create", "--name", "token-two", "--scope", scope)
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "tokens", "ls")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), scope)
+
+	inv, root = clitest.New(t, "tokens", "create", "--name", "token-three", "--scope", "workspace")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "invalid scope rule")
+
 	inv, root = clitest.New(t, "tokens", "rm", "token-one")
 	clitest.SetupConfig(t, client, root)
 	buf = new(bytes.Buffer)
@@ -78,3 +101,33 @@ func TestTokens(t *testing.T) {
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "deleted")
 }
+
+func TestTokensCreateForUser(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	first := coderdtest.CreateFirstUser(t, client)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancelFunc()
+
+	serviceAccount, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+		OrganizationID: first.OrganizationID,
+		Email:          "ci@coder.com",
+		Username:       "ci-bot",
+		ServiceAccount: true,
+	})
+	require.NoError(t, err)
+
+	inv, root := clitest.New(t, "tokens", "create", "--user", serviceAccount.Username, "--lifetime", "8760h")
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	serviceClient := codersdk.New(client.URL)
+	serviceClient.SetSessionToken(strings.TrimSpace(buf.String()))
+	user, err := serviceClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, serviceAccount.ID, user.ID)
+}
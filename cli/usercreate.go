@@ -15,11 +15,12 @@ import (
 
 func (r *RootCmd) userCreate() *clibase.Cmd {
 	var (
-		email        string
-		username     string
-		password     string
-		disableLogin bool
-		loginType    string
+		email          string
+		username       string
+		password       string
+		disableLogin   bool
+		loginType      string
+		serviceAccount bool
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -56,6 +57,27 @@ func (r *RootCmd) userCreate() *clibase.Cmd {
 					return err
 				}
 			}
+			if serviceAccount {
+				if password != "" || disableLogin || loginType != "" {
+					return xerrors.New("Service accounts cannot log in, so you cannot specify --password, --disable-login or --login-type with --service-account")
+				}
+				_, err = client.CreateUser(inv.Context(), codersdk.CreateUserRequest{
+					Email:          email,
+					Username:       username,
+					OrganizationID: organization.ID,
+					ServiceAccount: true,
+				})
+				if err != nil {
+					return err
+				}
+
+				_, _ = fmt.Fprintln(inv.Stderr, `A new service account has been created!
+Create a token for it to authenticate:
+
+`+cliui.DefaultStyles.Code.Render("coder tokens create --user "+username))
+				return nil
+			}
+
 			userLoginType := codersdk.LoginTypePassword
 			if disableLogin && loginType != "" {
 				return xerrors.New("You cannot specify both --disable-login and --login-type")
@@ -95,6 +117,8 @@ func (r *RootCmd) userCreate() *clibase.Cmd {
 				authenticationMethod = `Login is authenticated through GitHub.`
 			case codersdk.LoginTypeOIDC:
 				authenticationMethod = `Login is authenticated through the configured OIDC provider.`
+			case codersdk.LoginTypeLDAP:
+				authenticationMethod = `Login is authenticated through the configured LDAP directory.`
 			}
 
 			_, _ = fmt.Fprintln(inv.Stderr, `A new user has been created!
@@ -143,11 +167,16 @@ Create a workspace  `+cliui.DefaultStyles.Code.Render("coder create")+`!`)
 			Description: fmt.Sprintf("Optionally specify the login type for the user. Valid values are: %s. "+
 				"Using 'none' prevents the user from authenticating and requires an API key/token to be generated by an admin.",
 				strings.Join([]string{
-					string(codersdk.LoginTypePassword), string(codersdk.LoginTypeNone), string(codersdk.LoginTypeGithub), string(codersdk.LoginTypeOIDC),
+					string(codersdk.LoginTypePassword), string(codersdk.LoginTypeNone), string(codersdk.LoginTypeGithub), string(codersdk.LoginTypeOIDC), string(codersdk.LoginTypeLDAP),
 				}, ", ",
 				)),
 			Value: clibase.StringOf(&loginType),
 		},
+		{
+			Flag:        "service-account",
+			Description: "Create a service account for automation. Service accounts cannot log in and authenticate with tokens created by an admin. They don't take license seats.",
+			Value:       clibase.BoolOf(&serviceAccount),
+		},
 	}
 	return cmd
 }
@@ -1,13 +1,17 @@
 package cli_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestUserCreate(t *testing.T) {
@@ -37,4 +41,36 @@ func TestUserCreate(t *testing.T) {
 		}
 		<-doneChan
 	})
+
+	t.Run("ServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		inv, root := clitest.New(t, "users", "create", "--service-account", "--username", "ci-bot", "--email", "ci@coder.com")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		pty.ExpectMatch("coder tokens create --user ci-bot")
+
+		user, err := client.User(ctx, "ci-bot")
+		require.NoError(t, err)
+		require.True(t, user.IsServiceAccount)
+		require.Equal(t, codersdk.LoginTypeNone, user.LoginType)
+	})
+
+	t.Run("ServiceAccountPassword", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		inv, root := clitest.New(t, "users", "create", "--service-account", "--username", "ci-bot", "--email", "ci@coder.com", "--password", "SomeSecurePassword!")
+		clitest.SetupConfig(t, client, root)
+
+		err := inv.Run()
+		require.ErrorContains(t, err, "Service accounts cannot log in")
+	})
 }
@@ -0,0 +1,416 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) webhooks() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "webhooks",
+		Short: "Manage webhooks that receive workspace, template and user events",
+		Long: "Webhooks receive a JSON payload for every event they subscribe to, signed with the secret of the webhook.\n" + formatExamples(
+			example{
+				Description: "Send the workspace builds that failed to a webhook",
+				Command:     "coder webhooks create alerts --endpoint https://example.com/hook --events workspace_build_failed",
+			},
+			example{
+				Description: "List the recent deliveries of a webhook",
+				Command:     "coder webhooks deliveries alerts",
+			},
+		),
+		Aliases: []string{"webhook"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.createWebhook(),
+			r.listWebhooks(),
+			r.editWebhook(),
+			r.deleteWebhook(),
+			r.webhookDeliveries(),
+		},
+	}
+	return cmd
+}
+
+func webhookEventsDescription() string {
+	events := make([]string, 0, len(codersdk.WebhookEvents))
+	for _, event := range codersdk.WebhookEvents {
+		events = append(events, string(event))
+	}
+	return strings.Join(events, ", ")
+}
+
+func convertWebhookEvents(events []string) []codersdk.WebhookEvent {
+	converted := make([]codersdk.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, codersdk.WebhookEvent(event))
+	}
+	return converted
+}
+
+// webhookByName returns the webhook with the given name.
+func webhookByName(inv *clibase.Invocation, client *codersdk.Client, name string) (codersdk.Webhook, error) {
+	webhooks, err := client.Webhooks(inv.Context())
+	if err != nil {
+		return codersdk.Webhook{}, xerrors.Errorf("get webhooks: %w", err)
+	}
+	for _, webhook := range webhooks {
+		if strings.EqualFold(webhook.Name, name) {
+			return webhook, nil
+		}
+	}
+	return codersdk.Webhook{}, xerrors.Errorf("webhook %q not found", name)
+}
+
+func (r *RootCmd) createWebhook() *clibase.Cmd {
+	var (
+		url    string
+		events []string
+		secret string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a webhook",
+		Long:  "The secret of the webhook is printed once. It signs the payloads sent to the webhook.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			res, err := client.CreateWebhook(inv.Context(), codersdk.CreateWebhookRequest{
+				Name:   inv.Args[0],
+				URL:    url,
+				Events: convertWebhookEvents(events),
+				Secret: secret,
+			})
+			if err != nil {
+				return xerrors.Errorf("create webhook: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Created webhook %s! Its secret is:\n", cliui.DefaultStyles.Keyword.Render(res.Webhook.Name))
+			_, _ = fmt.Fprintln(inv.Stdout, res.Secret)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "endpoint",
+			Description: "The URL the payloads are posted to.",
+			Value:       clibase.StringOf(&url),
+		},
+		{
+			Flag:        "events",
+			Description: "The events the webhook subscribes to, any of: " + webhookEventsDescription() + ".",
+			Value:       clibase.StringArrayOf(&events),
+		},
+		{
+			Flag:        "secret",
+			Description: "The secret that signs the payloads. A random secret is generated if it is empty.",
+			Value:       clibase.StringOf(&secret),
+		},
+	}
+	return cmd
+}
+
+type webhookRow struct {
+	// For JSON format:
+	codersdk.Webhook `table:"-"`
+
+	// For table format:
+	Name    string `json:"-" table:"name,default_sort"`
+	URL     string `json:"-" table:"url"`
+	Events  string `json:"-" table:"events"`
+	Enabled bool   `json:"-" table:"enabled"`
+	ID      string `json:"-" table:"id"`
+}
+
+func webhookRowFromWebhook(webhook codersdk.Webhook) webhookRow {
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+	return webhookRow{
+		Webhook: webhook,
+		Name:    webhook.Name,
+		URL:     webhook.URL,
+		Events:  strings.Join(events, ","),
+		Enabled: webhook.Enabled,
+		ID:      webhook.ID.String(),
+	}
+}
+
+func (r *RootCmd) listWebhooks() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]webhookRow{}, []string{"name", "url", "events", "enabled"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List webhooks",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			webhooks, err := client.Webhooks(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("get webhooks: %w", err)
+			}
+
+			if len(webhooks) == 0 {
+				cliui.Infof(
+					inv.Stderr,
+					"No webhooks found.\n",
+				)
+			}
+
+			rows := make([]webhookRow, 0, len(webhooks))
+			for _, webhook := range webhooks {
+				rows = append(rows, webhookRowFromWebhook(webhook))
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) editWebhook() *clibase.Cmd {
+	var (
+		name    string
+		url     string
+		events  []string
+		enabled bool
+		secret  string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "edit <name>",
+		Short: "Edit a webhook",
+		Long: formatExamples(
+			example{
+				Description: "Pause the deliveries to a webhook",
+				Command:     "coder webhooks edit alerts --enabled=false",
+			},
+			example{
+				Description: "Rotate the secret of a webhook",
+				Command:     "coder webhooks edit alerts --secret \"$(openssl rand -hex 32)\"",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			webhook, err := webhookByName(inv, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			var req codersdk.UpdateWebhookRequest
+			flags := inv.ParsedFlags()
+			if flags.Lookup("name").Changed {
+				req.Name = &name
+			}
+			if flags.Lookup("endpoint").Changed {
+				req.URL = &url
+			}
+			if flags.Lookup("events").Changed {
+				req.Events = convertWebhookEvents(events)
+			}
+			if flags.Lookup("enabled").Changed {
+				req.Enabled = &enabled
+			}
+			if flags.Lookup("secret").Changed {
+				req.Secret = &secret
+			}
+
+			webhook, err = client.UpdateWebhook(inv.Context(), webhook.ID, req)
+			if err != nil {
+				return xerrors.Errorf("update webhook: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated webhook %s!\n", cliui.DefaultStyles.Keyword.Render(webhook.Name))
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "name",
+			Description: "Rename the webhook.",
+			Value:       clibase.StringOf(&name),
+		},
+		{
+			Flag:        "endpoint",
+			Description: "The URL the payloads are posted to.",
+			Value:       clibase.StringOf(&url),
+		},
+		{
+			Flag:        "events",
+			Description: "Replace the events the webhook subscribes to, any of: " + webhookEventsDescription() + ".",
+			Value:       clibase.StringArrayOf(&events),
+		},
+		{
+			Flag:        "enabled",
+			Description: "Whether events are delivered to the webhook.",
+			Value:       clibase.BoolOf(&enabled),
+		},
+		{
+			Flag:        "secret",
+			Description: "Replace the secret that signs the payloads.",
+			Value:       clibase.StringOf(&secret),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) deleteWebhook() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name>",
+		Short: "Delete a webhook and its deliveries",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			webhook, err := webhookByName(inv, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteWebhook(inv.Context(), webhook.ID)
+			if err != nil {
+				return xerrors.Errorf("delete webhook: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted webhook %s!\n", cliui.DefaultStyles.Keyword.Render(webhook.Name))
+			return nil
+		},
+	}
+	return cmd
+}
+
+type webhookDeliveryRow struct {
+	// For JSON format:
+	codersdk.WebhookDelivery `table:"-"`
+
+	// For table format:
+	CreatedAt   string `json:"-" table:"created at,default_sort"`
+	Event       string `json:"-" table:"event"`
+	Attempts    int32  `json:"-" table:"attempts"`
+	StatusCode  string `json:"-" table:"status code"`
+	DeliveredAt string `json:"-" table:"delivered at"`
+	NextAttempt string `json:"-" table:"next attempt"`
+	Error       string `json:"-" table:"error"`
+	ID          string `json:"-" table:"id"`
+}
+
+func webhookDeliveryRowFromDelivery(delivery codersdk.WebhookDelivery) webhookDeliveryRow {
+	row := webhookDeliveryRow{
+		WebhookDelivery: delivery,
+		CreatedAt:       delivery.CreatedAt.Format(time.RFC3339),
+		Event:           string(delivery.Event),
+		Attempts:        delivery.Attempts,
+		Error:           delivery.Error,
+		ID:              delivery.ID.String(),
+	}
+	if delivery.StatusCode != nil {
+		row.StatusCode = strconv.Itoa(int(*delivery.StatusCode))
+	}
+	if delivery.DeliveredAt != nil {
+		row.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+	if delivery.NextAttemptAt != nil {
+		row.NextAttempt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+	return row
+}
+
+func (r *RootCmd) webhookDeliveries() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]webhookDeliveryRow{}, []string{"created at", "event", "attempts", "status code", "delivered at", "next attempt", "error"}),
+		cliui.JSONFormat(),
+	)
+
+	var limit int64
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "deliveries <name>",
+		Short: "List the recent deliveries of a webhook",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			webhook, err := webhookByName(inv, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			deliveries, err := client.WebhookDeliveries(inv.Context(), webhook.ID, codersdk.WebhookDeliveriesRequest{
+				Pagination: codersdk.Pagination{
+					Limit: int(limit),
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("get webhook deliveries: %w", err)
+			}
+
+			if len(deliveries) == 0 {
+				cliui.Infof(
+					inv.Stderr,
+					"No deliveries found.\n",
+				)
+			}
+
+			rows := make([]webhookDeliveryRow, 0, len(deliveries))
+			for _, delivery := range deliveries {
+				rows = append(rows, webhookDeliveryRowFromDelivery(delivery))
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "limit",
+			Description: "The number of deliveries to list, newest first.",
+			Default:     "25",
+			Value:       clibase.Int64Of(&limit),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
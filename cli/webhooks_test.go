@@ -0,0 +1,73 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "webhooks", "create", "alerts",
+		"--endpoint", "https://example.com/hook",
+		"--events", "workspace_build_failed,user_created",
+	)
+	clitest.SetupConfig(t, client, root)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.NotEmpty(t, strings.TrimSpace(stdout.String()), "the secret is printed")
+
+	inv, root = clitest.New(t, "webhooks", "edit", "alerts", "--enabled=false", "--events", "workspace_created")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "webhooks", "list", "-o", "json")
+	clitest.SetupConfig(t, client, root)
+	stdout.Reset()
+	inv.Stdout = &stdout
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var webhooks []codersdk.Webhook
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &webhooks))
+	require.Len(t, webhooks, 1)
+	require.Equal(t, "alerts", webhooks[0].Name)
+	require.Equal(t, "https://example.com/hook", webhooks[0].URL)
+	require.Equal(t, []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceCreated}, webhooks[0].Events)
+	require.False(t, webhooks[0].Enabled)
+
+	inv, root = clitest.New(t, "webhooks", "deliveries", "alerts")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "webhooks", "delete", "alerts")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	_, err = client.Webhook(ctx, webhooks[0].ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	inv, root = clitest.New(t, "webhooks", "delete", "alerts")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "not found")
+}
@@ -2,6 +2,8 @@ package coderd_test
 
 import (
 	"context"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentsocket"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
@@ -29,7 +32,7 @@ func TestWorkspaceActivityBump(t *testing.T) {
 	// deadline allows you to forcibly set a max_deadline on the build. This
 	// doesn't use template autostop requirements and instead edits the
 	// max_deadline on the build directly in the database.
-	setupActivityTest := func(t *testing.T, deadline ...time.Duration) (client *codersdk.Client, workspace codersdk.Workspace, assertBumped func(want bool)) {
+	setupActivityTest := func(t *testing.T, deadline ...time.Duration) (client *codersdk.Client, workspace codersdk.Workspace, assertBumped func(want bool), socket *agentsocket.Client) {
 		const ttl = time.Minute
 		maxTTL := time.Duration(0)
 		if len(deadline) > 0 {
@@ -88,9 +91,11 @@ func TestWorkspaceActivityBump(t *testing.T) {
 
 		agentClient := agentsdk.New(client.URL)
 		agentClient.SetSessionToken(agentToken)
+		socketPath := filepath.Join(t.TempDir(), "agent.sock")
 		agentCloser := agent.New(agent.Options{
-			Client: agentClient,
-			Logger: slogtest.Make(t, nil).Named("agent"),
+			Client:     agentClient,
+			Logger:     slogtest.Make(t, nil).Named("agent"),
+			SocketPath: socketPath,
 		})
 		t.Cleanup(func() {
 			_ = agentCloser.Close()
@@ -119,6 +124,7 @@ func TestWorkspaceActivityBump(t *testing.T) {
 
 		_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
 
+		socket = agentsocket.NewClient(socketPath)
 		return client, workspace, func(want bool) {
 			if !want {
 				// It is difficult to test the absence of a call in a non-racey
@@ -152,13 +158,13 @@ func TestWorkspaceActivityBump(t *testing.T) {
 				return
 			}
 			require.WithinDuration(t, dbtime.Now().Add(ttl), workspace.LatestBuild.Deadline.Time, 3*time.Second)
-		}
+		}, socket
 	}
 
 	t.Run("Dial", func(t *testing.T) {
 		t.Parallel()
 
-		client, workspace, assertBumped := setupActivityTest(t)
+		client, workspace, assertBumped, _ := setupActivityTest(t)
 
 		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
 		conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, &codersdk.DialWorkspaceAgentOptions{
@@ -176,10 +182,36 @@ func TestWorkspaceActivityBump(t *testing.T) {
 		assertBumped(true)
 	})
 
+	t.Run("AutostopInhibitor", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("The agent socket only works on Linux and macOS.")
+		}
+
+		client, workspace, assertBumped, socket := setupActivityTest(t)
+
+		// Must hold the inhibitor for a few seconds to surpass bump threshold.
+		time.Sleep(time.Second * 3)
+		_, release, err := socket.InhibitAutostop(ctx, agentsocket.InhibitAutostopRequest{
+			Reason: "make all",
+		})
+		require.NoError(t, err)
+		defer release.Close()
+
+		assertBumped(true)
+
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		require.Eventually(t, func() bool {
+			agent, err := client.WorkspaceAgent(ctx, resources[0].Agents[0].ID)
+			require.NoError(t, err)
+			return len(agent.AutostopInhibitors) == 1 && agent.AutostopInhibitors[0].Reason == "make all"
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+
 	t.Run("NoBump", func(t *testing.T) {
 		t.Parallel()
 
-		client, workspace, assertBumped := setupActivityTest(t)
+		client, workspace, assertBumped, _ := setupActivityTest(t)
 
 		// Benign operations like retrieving workspace must not
 		// bump the deadline.
@@ -194,7 +226,7 @@ func TestWorkspaceActivityBump(t *testing.T) {
 
 		// Set the max deadline to be in 61 seconds. We bump by 1 minute, so we
 		// should expect the deadline to match the max deadline exactly.
-		client, workspace, assertBumped := setupActivityTest(t, 61*time.Second)
+		client, workspace, assertBumped, _ := setupActivityTest(t, 61*time.Second)
 
 		// Bump by dialing the workspace and sending traffic.
 		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
@@ -347,6 +347,51 @@ const docTemplate = `{
                 }
             }
         },
+        "/connectionlog": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Connection Log"
+                ],
+                "summary": "Get connection logs",
+                "operationId": "get-connection-logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ConnectionLogResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/csp/reports": {
             "post": {
                 "security": [
@@ -1081,81 +1126,81 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations": {
-            "post": {
+        "/oauth2-provider/apps": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "OAuth2"
                 ],
-                "summary": "Create organization",
-                "operationId": "create-organization",
+                "summary": "Get OAuth2 apps",
+                "operationId": "get-oauth2-apps",
                 "parameters": [
                     {
-                        "description": "Create organization request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateOrganizationRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filter by the apps the user has authorized",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "OAuth2"
                 ],
-                "summary": "Get organization by ID",
-                "operationId": "get-organization-by-id",
+                "summary": "Create OAuth2 app",
+                "operationId": "create-oauth2-app",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
+                        "description": "The OAuth2 app to create.",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PostOAuth2ProviderAppRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/groups": {
+        "/oauth2-provider/apps/{app}": {
             "get": {
                 "security": [
                     {
@@ -1166,16 +1211,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "OAuth2"
                 ],
-                "summary": "Get groups by organization",
-                "operationId": "get-groups-by-organization",
+                "summary": "Get OAuth2 app",
+                "operationId": "get-oauth2-app",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
@@ -1184,15 +1229,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Group"
-                            }
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1205,81 +1247,67 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "OAuth2"
                 ],
-                "summary": "Create group for organization",
-                "operationId": "create-group-for-organization",
+                "summary": "Update OAuth2 app",
+                "operationId": "update-oauth2-app",
                 "parameters": [
                     {
-                        "description": "Create group request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update an OAuth2 app.",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateGroupRequest"
+                            "$ref": "#/definitions/codersdk.PutOAuth2ProviderAppRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Group"
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/groups/{groupName}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "OAuth2"
                 ],
-                "summary": "Get group by organization and group name",
-                "operationId": "get-group-by-organization-and-group-name",
+                "summary": "Delete OAuth2 app",
+                "operationId": "delete-oauth2-app",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Group name",
-                        "name": "groupName",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Group"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/organizations/{organization}/members/roles": {
+        "/oauth2-provider/apps/{app}/secrets": {
             "get": {
                 "security": [
                     {
@@ -1290,16 +1318,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "OAuth2"
                 ],
-                "summary": "Get member roles by organization",
-                "operationId": "get-member-roles-by-organization",
+                "summary": "Get OAuth2 app secrets",
+                "operationId": "get-oauth2-app-secrets",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
@@ -1310,170 +1338,166 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecret"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/members/{user}/roles": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "OAuth2"
                 ],
-                "summary": "Assign role to organization member",
-                "operationId": "assign-role-to-organization-member",
+                "summary": "Create OAuth2 app secret",
+                "operationId": "create-oauth2-app-secret",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update roles request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.OrganizationMember"
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecretFull"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/members/{user}/workspaces": {
-            "post": {
+        "/oauth2-provider/apps/{app}/secrets/{secretID}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Workspaces"
+                    "OAuth2"
                 ],
-                "summary": "Create user workspace by organization",
-                "operationId": "create-user-workspace-by-organization",
+                "summary": "Delete OAuth2 app secret",
+                "operationId": "delete-oauth2-app-secret",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Username, UUID, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Secret ID",
+                        "name": "secretID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Create workspace request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/organizations/{organization}/provisionerdaemons": {
-            "get": {
+        "/oauth2-provider/apps/{app}/tokens": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "OAuth2"
                 ],
-                "summary": "Get provisioner daemons",
-                "operationId": "get-provisioner-daemons",
+                "summary": "Revoke OAuth2 app tokens",
+                "operationId": "revoke-oauth2-app-tokens",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/organizations/{organization}/provisionerdaemons/serve": {
+        "/organizations": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Create organization",
+                "operationId": "create-organization",
+                "parameters": [
+                    {
+                        "description": "Create organization request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Organization"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Enterprise"
+                    "Organizations"
                 ],
-                "summary": "Serve provisioner daemon",
-                "operationId": "serve-provisioner-daemon",
+                "summary": "Get organization by ID",
+                "operationId": "get-organization-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -1485,13 +1509,16 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Organization"
+                        }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates": {
+        "/organizations/{organization}/groups": {
             "get": {
                 "security": [
                     {
@@ -1502,10 +1529,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get templates by organization",
-                "operationId": "get-templates-by-organization",
+                "summary": "Get groups by organization",
+                "operationId": "get-groups-by-organization",
                 "parameters": [
                     {
                         "type": "string",
@@ -1522,7 +1549,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Template"
+                                "$ref": "#/definitions/codersdk.Group"
                             }
                         }
                     }
@@ -1541,18 +1568,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template by organization",
-                "operationId": "create-template-by-organization",
+                "summary": "Create group for organization",
+                "operationId": "create-group-for-organization",
                 "parameters": [
                     {
-                        "description": "Request body",
+                        "description": "Create group request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
+                            "$ref": "#/definitions/codersdk.CreateGroupRequest"
                         }
                     },
                     {
@@ -1564,16 +1591,16 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/examples": {
+        "/organizations/{organization}/groups/{groupName}": {
             "get": {
                 "security": [
                     {
@@ -1584,10 +1611,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template examples by organization",
-                "operationId": "get-template-examples-by-organization",
+                "summary": "Get group by organization and group name",
+                "operationId": "get-group-by-organization-and-group-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -1596,22 +1623,26 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group name",
+                        "name": "groupName",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateExample"
-                            }
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}": {
+        "/organizations/{organization}/members/roles": {
             "get": {
                 "security": [
                     {
@@ -1622,10 +1653,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get templates by organization and template name",
-                "operationId": "get-templates-by-organization-and-template-name",
+                "summary": "Get member roles by organization",
+                "operationId": "get-member-roles-by-organization",
                 "parameters": [
                     {
                         "type": "string",
@@ -1634,44 +1665,42 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
-            "get": {
+        "/organizations/{organization}/members/{user}/roles": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get template version by organization, template, and name",
-                "operationId": "get-template-version-by-organization-template-and-name",
+                "summary": "Assign role to organization member",
+                "operationId": "assign-role-to-organization-member",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
@@ -1679,44 +1708,49 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Update roles request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.OrganizationMember"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
-            "get": {
+        "/organizations/{organization}/members/{user}/workspaces": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Workspaces"
                 ],
-                "summary": "Get previous template version by organization, template, and name",
-                "operationId": "get-previous-template-version-by-organization-template-and-name",
+                "summary": "Create user workspace by organization",
+                "operationId": "create-user-workspace-by-organization",
                 "parameters": [
                     {
                         "type": "string",
@@ -1728,47 +1762,46 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
+                        "description": "Username, UUID, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Create workspace request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templateversions": {
-            "post": {
+        "/organizations/{organization}/provisionerdaemons": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template version by organization",
-                "operationId": "create-template-version-by-organization",
+                "summary": "Get provisioner daemons",
+                "operationId": "get-provisioner-daemons",
                 "parameters": [
                     {
                         "type": "string",
@@ -1777,53 +1810,51 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Create template version request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
+                            }
                         }
                     }
                 }
             }
         },
-        "/regions": {
+        "/organizations/{organization}/provisionerdaemons/serve": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "WorkspaceProxies"
+                    "Enterprise"
+                ],
+                "summary": "Serve provisioner daemon",
+                "operationId": "serve-provisioner-daemon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get site-wide regions for workspace connections",
-                "operationId": "get-site-wide-regions-for-workspace-connections",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/replicas": {
+        "/organizations/{organization}/templates": {
             "get": {
                 "security": [
                     {
@@ -1834,16 +1865,354 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get active replicas",
-                "operationId": "get-active-replicas",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
+                "summary": "Get templates by organization",
+                "operationId": "get-templates-by-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Template"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create template by organization",
+                "operationId": "create-template-by-organization",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Template"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/templates/examples": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template examples by organization",
+                "operationId": "get-template-examples-by-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateExample"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/templates/{templatename}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get templates by organization and template name",
+                "operationId": "get-templates-by-organization-and-template-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Template"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version by organization, template, and name",
+                "operationId": "get-template-version-by-organization-template-and-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get previous template version by organization, template, and name",
+                "operationId": "get-previous-template-version-by-organization-template-and-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/templateversions": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create template version by organization",
+                "operationId": "create-template-version-by-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create template version request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WorkspaceProxies"
+                ],
+                "summary": "Get site-wide regions for workspace connections",
+                "operationId": "get-site-wide-regions-for-workspace-connections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
+                        }
+                    }
+                }
+            }
+        },
+        "/replicas": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get active replicas",
+                "operationId": "get-active-replicas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
                                 "$ref": "#/definitions/codersdk.Replica"
                             }
                         }
@@ -1851,6 +2220,184 @@ const docTemplate = `{
                 }
             }
         },
+        "/roles": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get custom roles",
+                "operationId": "get-custom-roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.CustomRole"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Create custom role",
+                "operationId": "create-custom-role",
+                "parameters": [
+                    {
+                        "description": "Create custom role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateCustomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{customrole}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get custom role by ID",
+                "operationId": "get-custom-role-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Custom role ID",
+                        "name": "customrole",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Delete custom role",
+                "operationId": "delete-custom-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Custom role ID",
+                        "name": "customrole",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Update custom role",
+                "operationId": "update-custom-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Custom role ID",
+                        "name": "customrole",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update custom role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateCustomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
+                    }
+                }
+            }
+        },
         "/scim/v2/Users": {
             "get": {
                 "security": [
@@ -1981,6 +2528,70 @@ const docTemplate = `{
                 }
             }
         },
+        "/session-recordings/{recording}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get session recording",
+                "operationId": "get-session-recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Recording ID",
+                        "name": "recording",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceSessionRecording"
+                        }
+                    }
+                }
+            }
+        },
+        "/session-recordings/{recording}/cast": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get session recording in asciicast format",
+                "operationId": "get-session-recording-in-asciicast-format",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Recording ID",
+                        "name": "recording",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
         "/templates/{template}": {
             "get": {
                 "security": [
@@ -2216,8 +2827,179 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template DAUs by ID",
-                "operationId": "get-template-daus-by-id",
+                "summary": "Get template DAUs by ID",
+                "operationId": "get-template-daus-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{template}/prebuilds": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get prebuild pools by template",
+                "operationId": "get-prebuild-pools-by-template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspacePrebuildPool"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create prebuild pool for template",
+                "operationId": "create-prebuild-pool-for-template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create prebuild pool request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspacePrebuildPoolRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspacePrebuildPool"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{template}/prebuilds/{pool}": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Update prebuild pool",
+                "operationId": "update-prebuild-pool",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Prebuild pool ID",
+                        "name": "pool",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update prebuild pool request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspacePrebuildPoolRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspacePrebuildPool"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Delete prebuild pool",
+                "operationId": "delete-prebuild-pool",
                 "parameters": [
                     {
                         "type": "string",
@@ -2226,13 +3008,21 @@ const docTemplate = `{
                         "name": "template",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Prebuild pool ID",
+                        "name": "pool",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
@@ -2866,6 +3656,44 @@ const docTemplate = `{
                 }
             }
         },
+        "/templateversions/{templateversion}/presets": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get presets by template version",
+                "operationId": "get-presets-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionPreset"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/templateversions/{templateversion}/resources": {
             "get": {
                 "security": [
@@ -3205,6 +4033,40 @@ const docTemplate = `{
                 }
             }
         },
+        "/users/ldap/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Log in user with LDAP",
+                "operationId": "log-in-user-with-ldap",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithLDAPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/users/login": {
             "post": {
                 "consumes": [
@@ -3735,22 +4597,228 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/codersdk.APIKey"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete API key",
+                "operationId": "delete-api-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/login-type": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user login type",
+                "operationId": "get-user-login-type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UserLoginType"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get user notifications",
+                "operationId": "get-user-notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return unread notifications",
+                        "name": "unread_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.NotificationsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get user notification preferences",
+                "operationId": "get-user-notification-preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.NotificationPreference"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Update user notification preferences",
+                "operationId": "update-user-notification-preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update notification preferences request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateNotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.NotificationPreference"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{user}/notifications/read": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "tags": [
-                    "Users"
+                    "Notifications"
                 ],
-                "summary": "Delete API key",
-                "operationId": "delete-api-key",
+                "summary": "Mark all user notifications as read",
+                "operationId": "mark-all-user-notifications-as-read",
                 "parameters": [
                     {
                         "type": "string",
@@ -3758,14 +4826,6 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -3775,8 +4835,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user}/login-type": {
-            "get": {
+        "/users/{user}/notifications/{notification}/read": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3786,10 +4846,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Notifications"
                 ],
-                "summary": "Get user login type",
-                "operationId": "get-user-login-type",
+                "summary": "Mark user notification as read",
+                "operationId": "mark-user-notification-as-read",
                 "parameters": [
                     {
                         "type": "string",
@@ -3797,13 +4857,21 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Notification ID",
+                        "name": "notification",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UserLoginType"
+                            "$ref": "#/definitions/codersdk.Notification"
                         }
                     }
                 }
@@ -3973,6 +5041,43 @@ const docTemplate = `{
                 }
             }
         },
+        "/users/{user}/pty-invites": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get reconnecting PTY session invites of user",
+                "operationId": "get-reconnecting-pty-session-invites-of-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentPTYInvite"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/users/{user}/quiet-hours": {
             "get": {
                 "security": [
@@ -4295,7 +5400,235 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhooks",
+                "operationId": "get-webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Webhook"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create webhook",
+                "operationId": "create-webhook",
+                "parameters": [
+                    {
+                        "description": "Create webhook request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWebhookResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook by ID",
+                "operationId": "get-webhook-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Webhook"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete webhook",
+                "operationId": "delete-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Update webhook",
+                "operationId": "update-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update webhook request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Webhook"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook deliveries",
+                "operationId": "get-webhook-deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WebhookDelivery"
+                            }
                         }
                     }
                 }
@@ -4516,6 +5849,78 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaceagents/me/autostop-inhibitors": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent autostop inhibitors",
+                "operationId": "submit-workspace-agent-autostop-inhibitors",
+                "parameters": [
+                    {
+                        "description": "Workspace agent autostop inhibitors request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostAutostopInhibitorsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/connections": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Post workspace agent connection",
+                "operationId": "post-workspace-agent-connection",
+                "parameters": [
+                    {
+                        "description": "Connection request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostConnectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
         "/workspaceagents/me/coordinate": {
             "get": {
                 "security": [
@@ -4602,6 +6007,45 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaceagents/me/log-source": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Post workspace agent log source",
+                "operationId": "post-workspace-agent-log-source",
+                "parameters": [
+                    {
+                        "description": "Log source request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostLogSource"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentLogSource"
+                        }
+                    }
+                }
+            }
+        },
         "/workspaceagents/me/logs": {
             "patch": {
                 "security": [
@@ -4654,19 +6098,198 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get authorized workspace agent manifest",
-                "operationId": "get-authorized-workspace-agent-manifest",
+                "summary": "Get authorized workspace agent manifest",
+                "operationId": "get-authorized-workspace-agent-manifest",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Manifest"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/metadata/{key}": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent metadata",
+                "operationId": "submit-workspace-agent-metadata",
+                "parameters": [
+                    {
+                        "description": "Workspace agent metadata request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "metadata key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/peers": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent peers",
+                "operationId": "get-workspace-agent-peers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/agentsdk.Peer"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/peers/{workspaceagent}/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent peer",
+                "operationId": "coordinate-workspace-agent-peer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-lifecycle": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent lifecycle state",
+                "operationId": "submit-workspace-agent-lifecycle-state",
+                "parameters": [
+                    {
+                        "description": "Workspace agent lifecycle request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-stats": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent stats",
+                "operationId": "submit-workspace-agent-stats",
+                "parameters": [
+                    {
+                        "description": "Stats request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Stats"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Manifest"
+                            "$ref": "#/definitions/agentsdk.StatsResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/metadata/{key}": {
+        "/workspaceagents/me/script-status": {
             "post": {
                 "security": [
                     {
@@ -4679,25 +6302,17 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent metadata",
-                "operationId": "submit-workspace-agent-metadata",
+                "summary": "Submit workspace agent script status",
+                "operationId": "submit-workspace-agent-script-status",
                 "parameters": [
                     {
-                        "description": "Workspace agent metadata request",
+                        "description": "Workspace agent script status request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
+                            "$ref": "#/definitions/agentsdk.PostScriptStatusRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "format": "string",
-                        "description": "metadata key",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -4710,7 +6325,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaceagents/me/report-lifecycle": {
+        "/workspaceagents/me/session-recordings": {
             "post": {
                 "security": [
                     {
@@ -4723,22 +6338,22 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent lifecycle state",
-                "operationId": "submit-workspace-agent-lifecycle-state",
+                "summary": "Post workspace agent session recording",
+                "operationId": "post-workspace-agent-session-recording",
                 "parameters": [
                     {
-                        "description": "Workspace agent lifecycle request",
+                        "description": "Session recording request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                            "$ref": "#/definitions/agentsdk.PostSessionRecordingRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Success"
+                    "201": {
+                        "description": "Created"
                     }
                 },
                 "x-apidocgen": {
@@ -4746,7 +6361,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaceagents/me/report-stats": {
+        "/workspaceagents/me/session-recordings/{recording}/chunks": {
             "post": {
                 "security": [
                     {
@@ -4756,32 +6371,37 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent stats",
-                "operationId": "submit-workspace-agent-stats",
+                "summary": "Post workspace agent session recording chunk",
+                "operationId": "post-workspace-agent-session-recording-chunk",
                 "parameters": [
                     {
-                        "description": "Stats request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Recording ID",
+                        "name": "recording",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Session recording chunk",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Stats"
+                            "$ref": "#/definitions/agentsdk.PostSessionRecordingChunkRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.StatsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
@@ -5107,8 +6727,226 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Open PTY to workspace agent",
-                "operationId": "open-pty-to-workspace-agent",
+                "summary": "Open PTY to workspace agent",
+                "operationId": "open-pty-to-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/pty-sessions": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get reconnecting PTY sessions of workspace agent",
+                "operationId": "get-reconnecting-pty-sessions-of-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentReconnectingPTYSessionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/pty-sessions/{session}/attach": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Attach to reconnecting PTY session",
+                "operationId": "attach-to-reconnecting-pty-session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Reconnecting PTY session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Terminal height",
+                        "name": "height",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Terminal width",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Attach without being able to write to the session",
+                        "name": "read_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/pty-sessions/{session}/invites": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get reconnecting PTY session invites",
+                "operationId": "get-reconnecting-pty-session-invites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Reconnecting PTY session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentPTYInvite"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Invite user to reconnecting PTY session",
+                "operationId": "invite-user-to-reconnecting-pty-session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Reconnecting PTY session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invite request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceAgentPTYInviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPTYInvite"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/pty-sessions/{session}/invites/{user}": {
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Delete reconnecting PTY session invite",
+                "operationId": "delete-reconnecting-pty-session-invite",
                 "parameters": [
                     {
                         "type": "string",
@@ -5117,11 +6955,27 @@ const docTemplate = `{
                         "name": "workspaceagent",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Reconnecting PTY session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
@@ -5973,6 +7827,47 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaces/{workspace}/autoupdates": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace automatic updates by ID",
+                "operationId": "update-workspace-automatic-updates-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Automatic updates request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutomaticUpdatesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
         "/workspaces/{workspace}/builds": {
             "get": {
                 "security": [
@@ -5996,42 +7891,216 @@ const docTemplate = `{
                         "name": "workspace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date-time",
-                        "description": "Since timestamp",
-                        "name": "since",
-                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Since timestamp",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Create workspace build",
+                "operationId": "create-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace build request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/dormant": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace dormancy status by id.",
+                "operationId": "update-workspace-dormancy-status-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Make a workspace dormant or active",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceDormancy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Workspace"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/extend": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Extend workspace deadline by ID",
+                "operationId": "extend-workspace-deadline-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Extend deadline update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/port-share": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace agent port shares",
+                "operationId": "get-workspace-agent-port-shares",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
-                            }
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShares"
                         }
                     }
                 }
@@ -6049,10 +8118,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Workspaces"
                 ],
-                "summary": "Create workspace build",
-                "operationId": "create-workspace-build",
+                "summary": "Upsert workspace agent port share",
+                "operationId": "upsert-workspace-agent-port-share",
                 "parameters": [
                     {
                         "type": "string",
@@ -6063,12 +8132,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create workspace build request",
+                        "description": "Upsert port sharing level request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                            "$ref": "#/definitions/codersdk.UpsertWorkspaceAgentPortShareRequest"
                         }
                     }
                 ],
@@ -6076,14 +8145,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShare"
                         }
                     }
                 }
-            }
-        },
-        "/workspaces/{workspace}/dormant": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6092,14 +8159,11 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Update workspace dormancy status by id.",
-                "operationId": "update-workspace-dormancy-status-by-id",
+                "summary": "Delete workspace agent port share",
+                "operationId": "delete-workspace-agent-port-share",
                 "parameters": [
                     {
                         "type": "string",
@@ -6110,43 +8174,37 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Make a workspace dormant or active",
+                        "description": "Delete port sharing level request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceDormancy"
+                            "$ref": "#/definitions/codersdk.DeleteWorkspaceAgentPortShareRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/extend": {
-            "put": {
+        "/workspaces/{workspace}/session-recordings": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Extend workspace deadline by ID",
-                "operationId": "extend-workspace-deadline-by-id",
+                "summary": "Get workspace session recordings",
+                "operationId": "get-workspace-session-recordings",
                 "parameters": [
                     {
                         "type": "string",
@@ -6155,22 +8213,16 @@ const docTemplate = `{
                         "name": "workspace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Extend deadline update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceSessionRecording"
+                            }
                         }
                     }
                 }
@@ -6398,9 +8450,6 @@ const docTemplate = `{
                 },
                 "output": {
                     "type": "string"
-                },
-                "source": {
-                    "$ref": "#/definitions/codersdk.WorkspaceAgentLogSource"
                 }
             }
         },
@@ -6410,6 +8459,9 @@ const docTemplate = `{
                 "agent_id": {
                     "type": "string"
                 },
+                "agent_name": {
+                    "type": "string"
+                },
                 "apps": {
                     "type": "array",
                     "items": {
@@ -6438,6 +8490,13 @@ const docTemplate = `{
                     "description": "GitAuthConfigs stores the number of Git configurations\nthe Coder deployment has. If this number is \u003e0, we\nset up special configuration in the workspace.",
                     "type": "integer"
                 },
+                "log_files": {
+                    "description": "LogFiles are files the agent tails into the workspace agent logs.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentLogFile"
+                    }
+                },
                 "metadata": {
                     "type": "array",
                     "items": {
@@ -6447,26 +8506,40 @@ const docTemplate = `{
                 "motd_file": {
                     "type": "string"
                 },
-                "shutdown_script": {
+                "owner_name": {
                     "type": "string"
                 },
-                "shutdown_script_timeout": {
-                    "type": "integer"
+                "record_session_input": {
+                    "type": "boolean"
                 },
-                "startup_script": {
-                    "type": "string"
+                "record_sessions": {
+                    "description": "RecordSessions is true if interactive sessions must be recorded and\nuploaded with PostSessionRecording.",
+                    "type": "boolean"
                 },
-                "startup_script_timeout": {
-                    "type": "integer"
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
+                    }
                 },
                 "vscode_port_proxy_uri": {
                     "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                },
+                "workspace_name": {
+                    "type": "string"
                 }
             }
         },
         "agentsdk.PatchLogs": {
             "type": "object",
             "properties": {
+                "log_source_id": {
+                    "description": "LogSourceID is the log source the logs belong to. If unset, the\nlogs are attributed to ExternalLogSourceID.",
+                    "type": "string"
+                },
                 "logs": {
                     "type": "array",
                     "items": {
@@ -6475,6 +8548,31 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.Peer": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "owner_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentStatus"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
+                    "type": "string"
+                }
+            }
+        },
         "agentsdk.PostAppHealthsRequest": {
             "type": "object",
             "properties": {
@@ -6487,6 +8585,58 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.PostAutostopInhibitorsRequest": {
+            "type": "object",
+            "properties": {
+                "inhibitors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentAutostopInhibitor"
+                    }
+                }
+            }
+        },
+        "agentsdk.PostConnectionRequest": {
+            "type": "object",
+            "properties": {
+                "bytes_received": {
+                    "type": "integer"
+                },
+                "bytes_sent": {
+                    "type": "integer"
+                },
+                "connect_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "disconnect_reason": {
+                    "type": "string"
+                },
+                "disconnect_time": {
+                    "description": "DisconnectTime is set once the connection is closed, along with the\nfields below.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "description": "IP is the address of the client on the tailnet.",
+                    "type": "string"
+                },
+                "slug_or_port": {
+                    "description": "SlugOrPort is the port of a port forward.",
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/codersdk.ConnectionType"
+                }
+            }
+        },
         "agentsdk.PostLifecycleRequest": {
             "type": "object",
             "properties": {
@@ -6498,6 +8648,21 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.PostLogSource": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is a unique identifier for the log source.\nIt is scoped to a workspace agent, and can be statically\ndefined inside code to prevent duplicate sources from being\ncreated for the same agent.",
+                    "type": "string"
+                }
+            }
+        },
         "agentsdk.PostMetadataRequest": {
             "type": "object",
             "properties": {
@@ -6517,6 +8682,73 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.PostScriptStatusRequest": {
+            "type": "object",
+            "properties": {
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "script_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentScriptStatus"
+                }
+            }
+        },
+        "agentsdk.PostSessionRecordingChunkRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data is a part of the asciicast v2 file.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "final": {
+                    "description": "Final is set on the last chunk of a recording.",
+                    "type": "boolean"
+                },
+                "index": {
+                    "description": "Index orders the chunks of a recording. Uploading the same index twice\nis a no-op, so failed uploads can be retried.",
+                    "type": "integer"
+                }
+            }
+        },
+        "agentsdk.PostSessionRecordingRequest": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is generated by the agent so chunks can be uploaded as soon as the\nsession starts.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "includes_input": {
+                    "type": "boolean"
+                },
+                "reconnect_id": {
+                    "description": "ReconnectID is only set for reconnecting PTY sessions.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "type": {
+                    "$ref": "#/definitions/codersdk.WorkspaceSessionRecordingType"
+                }
+            }
+        },
         "agentsdk.PostStartupRequest": {
             "type": "object",
             "properties": {
@@ -6925,6 +9157,7 @@ const docTemplate = `{
                         "password",
                         "github",
                         "oidc",
+                        "ldap",
                         "token"
                     ],
                     "allOf": [
@@ -6936,7 +9169,8 @@ const docTemplate = `{
                 "scope": {
                     "enum": [
                         "all",
-                        "application_connect"
+                        "application_connect",
+                        "custom"
                     ],
                     "allOf": [
                         {
@@ -6944,6 +9178,13 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scope_rules": {
+                    "description": "ScopeRules are the rules of keys with the custom scope.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.APIKeyScopeRule"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 },
@@ -6951,23 +9192,49 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
-                "user_id": {
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.APIKeyScope": {
+            "type": "string",
+            "enum": [
+                "all",
+                "application_connect",
+                "custom"
+            ],
+            "x-enum-varnames": [
+                "APIKeyScopeAll",
+                "APIKeyScopeApplicationConnect",
+                "APIKeyScopeCustom"
+            ]
+        },
+        "codersdk.APIKeyScopeRule": {
+            "description": "APIKeyScopeRule allows an action on a resource type. If ResourceID is set, the rule only allows the action on that resource.",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is one of the RBAC actions, or \"*\" for all actions.",
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "read",
+                        "update",
+                        "delete",
+                        "*"
+                    ]
+                },
+                "resource_id": {
                     "type": "string",
                     "format": "uuid"
+                },
+                "resource_type": {
+                    "$ref": "#/definitions/codersdk.RBACResource"
                 }
             }
         },
-        "codersdk.APIKeyScope": {
-            "type": "string",
-            "enum": [
-                "all",
-                "application_connect"
-            ],
-            "x-enum-varnames": [
-                "APIKeyScopeAll",
-                "APIKeyScopeApplicationConnect"
-            ]
-        },
         "codersdk.AddLicenseRequest": {
             "type": "object",
             "required": [
@@ -7042,7 +9309,8 @@ const docTemplate = `{
                 "stop",
                 "login",
                 "logout",
-                "register"
+                "register",
+                "connect"
             ],
             "x-enum-varnames": [
                 "AuditActionCreate",
@@ -7052,7 +9320,8 @@ const docTemplate = `{
                 "AuditActionStop",
                 "AuditActionLogin",
                 "AuditActionLogout",
-                "AuditActionRegister"
+                "AuditActionRegister",
+                "AuditActionConnect"
             ]
         },
         "codersdk.AuditDiff": {
@@ -7167,6 +9436,9 @@ const docTemplate = `{
                 "github": {
                     "$ref": "#/definitions/codersdk.AuthMethod"
                 },
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.AuthMethod"
+                },
                 "oidc": {
                     "$ref": "#/definitions/codersdk.OIDCAuthMethod"
                 },
@@ -7242,6 +9514,17 @@ const docTemplate = `{
                 "type": "boolean"
             }
         },
+        "codersdk.AutomaticUpdates": {
+            "type": "string",
+            "enum": [
+                "always",
+                "never"
+            ],
+            "x-enum-varnames": [
+                "AutomaticUpdatesAlways",
+                "AutomaticUpdatesNever"
+            ]
+        },
         "codersdk.BuildInfoResponse": {
             "type": "object",
             "properties": {
@@ -7288,6 +9571,130 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ConnectionLog": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "bytes_received": {
+                    "description": "BytesReceived is the number of bytes the workspace received from the\nclient.",
+                    "type": "integer"
+                },
+                "bytes_sent": {
+                    "description": "BytesSent is the number of bytes sent from the workspace to the client.",
+                    "type": "integer"
+                },
+                "code": {
+                    "description": "Code is the exit code of SSH and reconnecting PTY sessions, or the HTTP\nstatus code of workspace app accesses.",
+                    "type": "integer"
+                },
+                "connect_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "disconnect_reason": {
+                    "type": "string"
+                },
+                "disconnect_time": {
+                    "description": "DisconnectTime is unset while the connection is open, and for workspace\napp accesses.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "slug_or_port": {
+                    "description": "SlugOrPort is the slug of the workspace app, or the port that was\nforwarded to.",
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "ssh",
+                        "vscode",
+                        "jetbrains",
+                        "reconnecting_pty",
+                        "port_forwarding",
+                        "workspace_app"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ConnectionType"
+                        }
+                    ]
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is the user that connected. It is only known for workspace app\naccesses.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
+                    "type": "string"
+                },
+                "workspace_owner_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_owner_username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ConnectionLogResponse": {
+            "type": "object",
+            "properties": {
+                "connection_logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ConnectionLog"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.ConnectionType": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "vscode",
+                "jetbrains",
+                "reconnecting_pty",
+                "port_forwarding",
+                "workspace_app"
+            ],
+            "x-enum-varnames": [
+                "ConnectionTypeSSH",
+                "ConnectionTypeVSCode",
+                "ConnectionTypeJetBrains",
+                "ConnectionTypeReconnectingPTY",
+                "ConnectionTypePortForwarding",
+                "ConnectionTypeWorkspaceApp"
+            ]
+        },
         "codersdk.ConvertLoginRequest": {
             "type": "object",
             "required": [
@@ -7308,6 +9715,43 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateCustomRoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "OrganizationID makes the role an organization role. Organization roles\ncannot have site permissions, site roles cannot have organization\npermissions.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
         "codersdk.CreateFirstUserRequest": {
             "type": "object",
             "required": [
@@ -7591,7 +10035,8 @@ const docTemplate = `{
                 "scope": {
                     "enum": [
                         "all",
-                        "application_connect"
+                        "application_connect",
+                        "custom"
                     ],
                     "allOf": [
                         {
@@ -7599,6 +10044,13 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scope_rules": {
+                    "description": "ScopeRules are required for, and only allowed with, the custom scope.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.APIKeyScopeRule"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 }
@@ -7634,11 +10086,68 @@ const docTemplate = `{
                 "password": {
                     "type": "string"
                 },
+                "service_account": {
+                    "description": "ServiceAccount creates a non-human user for automation. Service\naccounts cannot log in, so they must not have a password or another\nlogin type. They authenticate with tokens and don't take license seats.",
+                    "type": "boolean"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
+        "codersdk.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "name",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret signs the payloads sent to the webhook. A random secret is\ngenerated if it is empty.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.CreateWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "Secret is returned only once, when the webhook is created.",
+                    "type": "string"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/codersdk.Webhook"
+                }
+            }
+        },
+        "codersdk.CreateWorkspaceAgentPTYInviteRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "read_only": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.CreateWorkspaceBuildRequest": {
             "type": "object",
             "required": [
@@ -7695,6 +10204,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateWorkspacePrebuildPoolRequest": {
+            "type": "object",
+            "required": [
+                "template_version_id"
+            ],
+            "properties": {
+                "desired_instances": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
+                },
+                "template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.CreateWorkspaceProxyRequest": {
             "type": "object",
             "required": [
@@ -7718,6 +10249,14 @@ const docTemplate = `{
                 "name"
             ],
             "properties": {
+                "automatic_updates": {
+                    "description": "AutomaticUpdates defaults to \"never\".",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                },
                 "autostart_schedule": {
                     "type": "string"
                 },
@@ -7728,21 +10267,67 @@ const docTemplate = `{
                     "description": "RichParameterValues allows for additional parameters to be provided\nduring the initial provision.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
+                },
+                "template_id": {
+                    "description": "TemplateID specifies which template should be used for creating the workspace.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_version_id": {
+                    "description": "TemplateVersionID can be used to specify a specific version of a template for creating the workspace.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ttl_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.CustomRole": {
+            "description": "CustomRole is a role defined by administrators in addition to the built-in roles. Roles with an organization are assigned to members of that organization as \"name:organization_id\".",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
                     }
                 },
-                "template_id": {
-                    "description": "TemplateID specifies which template should be used for creating the workspace.",
-                    "type": "string",
-                    "format": "uuid"
-                },
-                "template_version_id": {
-                    "description": "TemplateVersionID can be used to specify a specific version of a template for creating the workspace.",
+                "updated_at": {
                     "type": "string",
-                    "format": "uuid"
+                    "format": "date-time"
                 },
-                "ttl_ms": {
-                    "type": "integer"
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
                 }
             }
         },
@@ -7851,6 +10436,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DeleteWorkspaceAgentPortShareRequest": {
+            "type": "object",
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.DeploymentConfig": {
             "type": "object",
             "properties": {
@@ -7926,12 +10522,18 @@ const docTemplate = `{
                 "config_ssh": {
                     "$ref": "#/definitions/codersdk.SSHConfig"
                 },
+                "connection_log_retention": {
+                    "type": "integer"
+                },
                 "dangerous": {
                     "$ref": "#/definitions/codersdk.DangerousConfig"
                 },
                 "derp": {
                     "$ref": "#/definitions/codersdk.DERP"
                 },
+                "disable_agent_auto_update": {
+                    "type": "boolean"
+                },
                 "disable_owner_workspace_exec": {
                     "type": "boolean"
                 },
@@ -7969,9 +10571,15 @@ const docTemplate = `{
                 "job_hang_detector_interval": {
                     "type": "integer"
                 },
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.LDAPConfig"
+                },
                 "logging": {
                     "$ref": "#/definitions/codersdk.LoggingConfig"
                 },
+                "max_service_account_token_lifetime": {
+                    "type": "integer"
+                },
                 "max_session_expiry": {
                     "type": "integer"
                 },
@@ -7981,6 +10589,9 @@ const docTemplate = `{
                 "metrics_cache_refresh_interval": {
                     "type": "integer"
                 },
+                "notifications": {
+                    "$ref": "#/definitions/codersdk.NotificationsConfig"
+                },
                 "oauth2": {
                     "$ref": "#/definitions/codersdk.OAuth2Config"
                 },
@@ -8026,6 +10637,9 @@ const docTemplate = `{
                 "secure_auth_cookie": {
                     "type": "boolean"
                 },
+                "session_recording": {
+                    "$ref": "#/definitions/codersdk.SessionRecordingConfig"
+                },
                 "ssh_keygen_algorithm": {
                     "type": "string"
                 },
@@ -8068,6 +10682,9 @@ const docTemplate = `{
                 "wildcard_access_url": {
                     "$ref": "#/definitions/clibase.URL"
                 },
+                "workspace_networking": {
+                    "type": "string"
+                },
                 "write_config": {
                     "type": "boolean"
                 }
@@ -8415,10 +11032,18 @@ const docTemplate = `{
         "codersdk.Healthcheck": {
             "type": "object",
             "properties": {
+                "command": {
+                    "description": "Command specifies a command to run instead of making an HTTP request.\nThe app is healthy if the command exits with a zero exit code.",
+                    "type": "string"
+                },
                 "interval": {
                     "description": "Interval specifies the seconds between each health check.",
                     "type": "integer"
                 },
+                "tcp": {
+                    "description": "TCP specifies an address to connect to over TCP instead of making an\nHTTP request, e.g. \"localhost:5432\".",
+                    "type": "string"
+                },
                 "threshold": {
                     "description": "Threshold specifies the number of consecutive failed health checks before returning \"unhealthy\".",
                     "type": "integer"
@@ -8472,6 +11097,53 @@ const docTemplate = `{
                 "RequiredTemplateVariables"
             ]
         },
+        "codersdk.LDAPConfig": {
+            "type": "object",
+            "properties": {
+                "allow_signups": {
+                    "type": "boolean"
+                },
+                "bind_dn": {
+                    "type": "string"
+                },
+                "bind_password": {
+                    "type": "string"
+                },
+                "ca_file": {
+                    "type": "string"
+                },
+                "email_attribute": {
+                    "type": "string"
+                },
+                "group_attribute": {
+                    "type": "string"
+                },
+                "group_auto_create": {
+                    "type": "boolean"
+                },
+                "group_mapping": {
+                    "type": "object"
+                },
+                "group_regex_filter": {
+                    "$ref": "#/definitions/clibase.Regexp"
+                },
+                "start_tls": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_search_base_dn": {
+                    "type": "string"
+                },
+                "user_search_filter": {
+                    "type": "string"
+                },
+                "username_attribute": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.License": {
             "type": "object",
             "properties": {
@@ -8562,6 +11234,7 @@ const docTemplate = `{
                 "password",
                 "github",
                 "oidc",
+                "ldap",
                 "token",
                 "none"
             ],
@@ -8570,10 +11243,26 @@ const docTemplate = `{
                 "LoginTypePassword",
                 "LoginTypeGithub",
                 "LoginTypeOIDC",
+                "LoginTypeLDAP",
                 "LoginTypeToken",
                 "LoginTypeNone"
             ]
         },
+        "codersdk.LoginWithLDAPRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.LoginWithPasswordRequest": {
             "type": "object",
             "required": [
@@ -8621,6 +11310,117 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.Notification": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "kind": {
+                    "$ref": "#/definitions/codersdk.NotificationKind"
+                },
+                "read_at": {
+                    "description": "ReadAt is unset until the user reads the notification.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.NotificationKind": {
+            "type": "string",
+            "enum": [
+                "workspace_autostop",
+                "workspace_dormant",
+                "workspace_marked_for_deletion",
+                "workspace_build_failed",
+                "account_dormant"
+            ],
+            "x-enum-varnames": [
+                "NotificationKindWorkspaceAutostop",
+                "NotificationKindWorkspaceDormant",
+                "NotificationKindWorkspaceMarkedForDeletion",
+                "NotificationKindWorkspaceBuildFailed",
+                "NotificationKindAccountDormant"
+            ]
+        },
+        "codersdk.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "$ref": "#/definitions/codersdk.NotificationKind"
+                }
+            }
+        },
+        "codersdk.NotificationsConfig": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "$ref": "#/definitions/codersdk.NotificationsEmailConfig"
+                }
+            }
+        },
+        "codersdk.NotificationsEmailConfig": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "hello": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "smarthost": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.NotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Notification"
+                    }
+                },
+                "unread_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.OAuth2AppEndpoints": {
+            "type": "object",
+            "properties": {
+                "authorization": {
+                    "type": "string"
+                },
+                "revocation": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.OAuth2Config": {
             "type": "object",
             "properties": {
@@ -8661,6 +11461,63 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.OAuth2ProviderApp": {
+            "type": "object",
+            "properties": {
+                "endpoints": {
+                    "description": "Endpoints are included in the app response for easier discovery. The\nclient ID is the ID of the app.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.OAuth2AppEndpoints"
+                        }
+                    ]
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirect_uris": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecret": {
+            "type": "object",
+            "properties": {
+                "client_secret_truncated": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecretFull": {
+            "type": "object",
+            "properties": {
+                "client_secret_full": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.OAuthConversionResponse": {
             "type": "object",
             "properties": {
@@ -8863,35 +11720,79 @@ const docTemplate = `{
                 "message": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.PatchWorkspaceProxy": {
+            "type": "object",
+            "required": [
+                "display_name",
+                "icon",
+                "id",
+                "name"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "regenerate_token": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.Permission": {
+            "description": "Permission allows an action on a resource type, or denies it if Negate is set.",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "read",
+                        "update",
+                        "delete"
+                    ]
+                },
+                "negate": {
+                    "type": "boolean"
+                },
+                "resource_type": {
+                    "$ref": "#/definitions/codersdk.RBACResource"
                 }
             }
         },
-        "codersdk.PatchWorkspaceProxy": {
+        "codersdk.PostOAuth2ProviderAppRequest": {
             "type": "object",
             "required": [
-                "display_name",
-                "icon",
-                "id",
-                "name"
+                "name",
+                "redirect_uris"
             ],
             "properties": {
-                "display_name": {
-                    "type": "string"
-                },
                 "icon": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string",
-                    "format": "uuid"
-                },
                 "name": {
                     "type": "string"
                 },
-                "regenerate_token": {
-                    "type": "boolean"
+                "redirect_uris": {
+                    "description": "RedirectURIs are the URIs the user may be redirected to after\nauthorizing the app. The redirect_uri of an authorization request must\nmatch one of them exactly.",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -9173,6 +12074,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PutOAuth2ProviderAppRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "redirect_uris"
+            ],
+            "properties": {
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirect_uris": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.RBACResource": {
             "type": "string",
             "enum": [
@@ -9181,6 +12104,12 @@ const docTemplate = `{
                 "workspace_execution",
                 "application_connect",
                 "audit_log",
+                "connection_log",
+                "session_recording",
+                "webhook",
+                "oauth2_app",
+                "oauth2_app_secret",
+                "oauth2_app_code_token",
                 "template",
                 "group",
                 "file",
@@ -9205,6 +12134,12 @@ const docTemplate = `{
                 "ResourceWorkspaceExecution",
                 "ResourceWorkspaceApplicationConnect",
                 "ResourceAuditLog",
+                "ResourceConnectionLog",
+                "ResourceSessionRecording",
+                "ResourceWebhook",
+                "ResourceOAuth2ProviderApp",
+                "ResourceOAuth2ProviderAppSecret",
+                "ResourceOAuth2ProviderAppCodeToken",
                 "ResourceTemplate",
                 "ResourceGroup",
                 "ResourceFile",
@@ -9335,7 +12270,10 @@ const docTemplate = `{
                 "license",
                 "convert_login",
                 "workspace_proxy",
-                "organization"
+                "organization",
+                "custom_role",
+                "oauth2_provider_app",
+                "oauth2_provider_app_secret"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -9349,7 +12287,10 @@ const docTemplate = `{
                 "ResourceTypeLicense",
                 "ResourceTypeConvertLogin",
                 "ResourceTypeWorkspaceProxy",
-                "ResourceTypeOrganization"
+                "ResourceTypeOrganization",
+                "ResourceTypeCustomRole",
+                "ResourceTypeOAuth2ProviderApp",
+                "ResourceTypeOAuth2ProviderAppSecret"
             ]
         },
         "codersdk.Response": {
@@ -9444,6 +12385,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.SessionRecordingConfig": {
+            "type": "object",
+            "properties": {
+                "enable": {
+                    "type": "boolean"
+                },
+                "record_input": {
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.SupportConfig": {
             "type": "object",
             "properties": {
@@ -9561,6 +12513,17 @@ const docTemplate = `{
                 "default_ttl_ms": {
                     "type": "integer"
                 },
+                "deprecated": {
+                    "description": "Deprecated templates can't be used to create new workspaces. Existing\nworkspaces keep working.",
+                    "type": "boolean"
+                },
+                "deprecated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "deprecation_message": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -9578,6 +12541,19 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "max_port_share_level": {
+                    "description": "MaxPortShareLevel is the most permissive level workspace owners can\nshare ports of workspaces created from this template with.",
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShareLevel"
+                        }
+                    ]
+                },
                 "max_ttl_ms": {
                     "description": "TODO(@dean): remove max_ttl once autostop_requirement is matured",
                     "type": "integer"
@@ -9595,6 +12571,14 @@ const docTemplate = `{
                         "terraform"
                     ]
                 },
+                "record_sessions": {
+                    "description": "RecordSessions records interactive sessions in workspaces created from\nthis template, even if session recording is disabled for the\ndeployment.",
+                    "type": "boolean"
+                },
+                "require_active_version": {
+                    "description": "RequireActiveVersion updates workspaces to the active version when\ntheir owner starts them, and prevents them from using other versions.",
+                    "type": "boolean"
+                },
                 "time_til_dormant_autodelete_ms": {
                     "type": "integer"
                 },
@@ -9876,6 +12860,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "is_service_account": {
+                    "description": "IsServiceAccount is set for non-human users used for automation.",
+                    "type": "boolean"
+                },
                 "last_seen_at": {
                     "type": "string",
                     "format": "date-time"
@@ -10076,6 +13064,24 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionPreset": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
+                }
+            }
+        },
         "codersdk.TemplateVersionVariable": {
             "type": "object",
             "properties": {
@@ -10194,6 +13200,47 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateCustomRoleRequest": {
+            "description": "UpdateCustomRoleRequest updates the display name and permissions of a role. Permissions that are null are left unchanged. Roles cannot be renamed.",
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
+        "codersdk.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.NotificationPreference"
+                    }
+                }
+            }
+        },
         "codersdk.UpdateRoles": {
             "type": "object",
             "properties": {
@@ -10269,6 +13316,37 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UpdateWorkspaceAutomaticUpdatesRequest": {
+            "type": "object",
+            "properties": {
+                "automatic_updates": {
+                    "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceAutostartRequest": {
             "type": "object",
             "properties": {
@@ -10285,6 +13363,15 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspacePrebuildPoolRequest": {
+            "type": "object",
+            "properties": {
+                "desired_instances": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceRequest": {
             "type": "object",
             "properties": {
@@ -10310,6 +13397,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpsertWorkspaceAgentPortShareRequest": {
+            "type": "object",
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "share_level": {
+                    "enum": [
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShareLevel"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.User": {
             "type": "object",
             "required": [
@@ -10335,6 +13444,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "is_service_account": {
+                    "description": "IsServiceAccount is set for non-human users used for automation.",
+                    "type": "boolean"
+                },
                 "last_seen_at": {
                     "type": "string",
                     "format": "date-time"
@@ -10495,36 +13608,152 @@ const docTemplate = `{
                 "detail": {
                     "type": "string"
                 },
-                "field": {
-                    "type": "string"
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ValidationMonotonicOrder": {
+            "type": "string",
+            "enum": [
+                "increasing",
+                "decreasing"
+            ],
+            "x-enum-varnames": [
+                "MonotonicOrderIncreasing",
+                "MonotonicOrderDecreasing"
+            ]
+        },
+        "codersdk.VariableValue": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "description": "Error is the error of the last attempt.",
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/codersdk.WebhookEvent"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_attempt_at": {
+                    "description": "LastAttemptAt is unset until the delivery is attempted.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt is unset once the delivery succeeded, or failed too many\ntimes.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status_code": {
+                    "description": "StatusCode is the HTTP status code of the last attempt.",
+                    "type": "integer"
+                },
+                "webhook_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.ValidationMonotonicOrder": {
+        "codersdk.WebhookEvent": {
             "type": "string",
             "enum": [
-                "increasing",
-                "decreasing"
+                "workspace_created",
+                "workspace_started",
+                "workspace_stopped",
+                "workspace_deleted",
+                "workspace_build_failed",
+                "template_version_promoted",
+                "user_created"
             ],
             "x-enum-varnames": [
-                "MonotonicOrderIncreasing",
-                "MonotonicOrderDecreasing"
+                "WebhookEventWorkspaceCreated",
+                "WebhookEventWorkspaceStarted",
+                "WebhookEventWorkspaceStopped",
+                "WebhookEventWorkspaceDeleted",
+                "WebhookEventWorkspaceBuildFailed",
+                "WebhookEventTemplateVersionPromoted",
+                "WebhookEventUserCreated"
             ]
         },
-        "codersdk.VariableValue": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
-                }
-            }
-        },
         "codersdk.Workspace": {
             "type": "object",
             "properties": {
+                "automatic_updates": {
+                    "description": "AutomaticUpdates is whether the workspace is updated to the active\nversion of its template when it is started.",
+                    "enum": [
+                        "always",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                },
                 "autostart_schedule": {
                     "type": "string"
                 },
@@ -10585,6 +13814,9 @@ const docTemplate = `{
                 "template_allow_user_cancel_workspace_jobs": {
                     "type": "boolean"
                 },
+                "template_deprecation_message": {
+                    "type": "string"
+                },
                 "template_display_name": {
                     "type": "string"
                 },
@@ -10619,6 +13851,13 @@ const docTemplate = `{
                 "architecture": {
                     "type": "string"
                 },
+                "autostop_inhibitors": {
+                    "description": "AutostopInhibitors are held by processes in the workspace to keep it\nrunning past its deadline. They are only reported while the agent is\nconnected.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentAutostopInhibitor"
+                    }
+                },
                 "connection_timeout_seconds": {
                     "type": "integer"
                 },
@@ -10681,6 +13920,12 @@ const docTemplate = `{
                 "lifecycle_state": {
                     "$ref": "#/definitions/codersdk.WorkspaceAgentLifecycle"
                 },
+                "log_sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentLogSource"
+                    }
+                },
                 "login_before_ready": {
                     "description": "Deprecated: Use StartupScriptBehavior instead.",
                     "type": "boolean"
@@ -10705,6 +13950,12 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
+                    }
+                },
                 "shutdown_script": {
                     "type": "string"
                 },
@@ -10734,157 +13985,395 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.AgentSubsystem"
                     }
                 },
-                "troubleshooting_url": {
-                    "type": "string"
+                "troubleshooting_url": {
+                    "type": "string"
+                },
+                "update_error": {
+                    "description": "UpdateError is the reason the last self-update failed.",
+                    "type": "string"
+                },
+                "update_state": {
+                    "description": "UpdateState is the state of the self-update of the agent binary.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentUpdateState"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentAutostopInhibitor": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentConnectionInfo": {
+            "type": "object",
+            "properties": {
+                "derp_force_websockets": {
+                    "type": "boolean"
+                },
+                "derp_map": {
+                    "$ref": "#/definitions/tailcfg.DERPMap"
+                },
+                "disable_direct_connections": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentHealth": {
+            "type": "object",
+            "properties": {
+                "healthy": {
+                    "description": "Healthy is true if the agent is healthy.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "reason": {
+                    "description": "Reason is a human-readable explanation of the agent's health. It is empty if Healthy is true.",
+                    "type": "string",
+                    "example": "agent has lost connection"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentLifecycle": {
+            "type": "string",
+            "enum": [
+                "created",
+                "starting",
+                "start_timeout",
+                "start_error",
+                "ready",
+                "shutting_down",
+                "shutdown_timeout",
+                "shutdown_error",
+                "off"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentLifecycleCreated",
+                "WorkspaceAgentLifecycleStarting",
+                "WorkspaceAgentLifecycleStartTimeout",
+                "WorkspaceAgentLifecycleStartError",
+                "WorkspaceAgentLifecycleReady",
+                "WorkspaceAgentLifecycleShuttingDown",
+                "WorkspaceAgentLifecycleShutdownTimeout",
+                "WorkspaceAgentLifecycleShutdownError",
+                "WorkspaceAgentLifecycleOff"
+            ]
+        },
+        "codersdk.WorkspaceAgentListeningPort": {
+            "type": "object",
+            "properties": {
+                "network": {
+                    "description": "only \"tcp\" at the moment",
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "process_name": {
+                    "description": "may be empty",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentListeningPortsResponse": {
+            "type": "object",
+            "properties": {
+                "ports": {
+                    "description": "If there are no ports in the list, nothing should be displayed in the UI.\nThere must not be a \"no ports available\" message or anything similar, as\nthere will always be no ports displayed on platforms where our port\ndetection logic is unsupported.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPort"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentLog": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "$ref": "#/definitions/codersdk.LogLevel"
+                },
+                "output": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentLogFile": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "log_source_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentLogSource": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentMetadataDescription": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "script": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentPTYInvite": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "read_only": {
+                    "description": "ReadOnly invites can watch the session but not write to it.",
+                    "type": "boolean"
+                },
+                "session_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "updated_at": {
+                "user_id": {
                     "type": "string",
-                    "format": "date-time"
+                    "format": "uuid"
                 },
-                "version": {
-                    "type": "string"
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.WorkspaceAgentConnectionInfo": {
+        "codersdk.WorkspaceAgentPortShare": {
             "type": "object",
             "properties": {
-                "derp_force_websockets": {
-                    "type": "boolean"
+                "agent_name": {
+                    "type": "string"
                 },
-                "derp_map": {
-                    "$ref": "#/definitions/tailcfg.DERPMap"
+                "port": {
+                    "type": "integer"
                 },
-                "disable_direct_connections": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "codersdk.WorkspaceAgentHealth": {
-            "type": "object",
-            "properties": {
-                "healthy": {
-                    "description": "Healthy is true if the agent is healthy.",
-                    "type": "boolean",
-                    "example": false
+                "share_level": {
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShareLevel"
+                        }
+                    ]
                 },
-                "reason": {
-                    "description": "Reason is a human-readable explanation of the agent's health. It is empty if Healthy is true.",
+                "workspace_id": {
                     "type": "string",
-                    "example": "agent has lost connection"
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.WorkspaceAgentLifecycle": {
+        "codersdk.WorkspaceAgentPortShareLevel": {
             "type": "string",
             "enum": [
-                "created",
-                "starting",
-                "start_timeout",
-                "start_error",
-                "ready",
-                "shutting_down",
-                "shutdown_timeout",
-                "shutdown_error",
-                "off"
+                "owner",
+                "authenticated",
+                "public"
             ],
             "x-enum-varnames": [
-                "WorkspaceAgentLifecycleCreated",
-                "WorkspaceAgentLifecycleStarting",
-                "WorkspaceAgentLifecycleStartTimeout",
-                "WorkspaceAgentLifecycleStartError",
-                "WorkspaceAgentLifecycleReady",
-                "WorkspaceAgentLifecycleShuttingDown",
-                "WorkspaceAgentLifecycleShutdownTimeout",
-                "WorkspaceAgentLifecycleShutdownError",
-                "WorkspaceAgentLifecycleOff"
+                "WorkspaceAgentPortShareLevelOwner",
+                "WorkspaceAgentPortShareLevelAuthenticated",
+                "WorkspaceAgentPortShareLevelPublic"
             ]
         },
-        "codersdk.WorkspaceAgentListeningPort": {
+        "codersdk.WorkspaceAgentPortShares": {
             "type": "object",
             "properties": {
-                "network": {
-                    "description": "only \"tcp\" at the moment",
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentPortShare"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentReconnectingPTYSession": {
+            "type": "object",
+            "properties": {
+                "command": {
                     "type": "string"
                 },
-                "port": {
+                "connections": {
+                    "description": "Connections is the number of connections currently attached.",
                     "type": "integer"
                 },
-                "process_name": {
-                    "description": "may be empty",
-                    "type": "string"
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "description": "ID is the reconnect ID used to attach to the session.",
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.WorkspaceAgentListeningPortsResponse": {
+        "codersdk.WorkspaceAgentReconnectingPTYSessionsResponse": {
             "type": "object",
             "properties": {
-                "ports": {
-                    "description": "If there are no ports in the list, nothing should be displayed in the UI.\nThere must not be a \"no ports available\" message or anything similar, as\nthere will always be no ports displayed on platforms where our port\ndetection logic is unsupported.",
+                "sessions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPort"
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentReconnectingPTYSession"
                     }
                 }
             }
         },
-        "codersdk.WorkspaceAgentLog": {
+        "codersdk.WorkspaceAgentScript": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "cron": {
+                    "type": "string"
+                },
+                "ended_at": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "id": {
+                "exit_code": {
                     "type": "integer"
                 },
-                "level": {
-                    "$ref": "#/definitions/codersdk.LogLevel"
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "output": {
-                    "type": "string"
-                }
-            }
-        },
-        "codersdk.WorkspaceAgentLogSource": {
-            "type": "string",
-            "enum": [
-                "startup_script",
-                "shutdown_script",
-                "kubernetes",
-                "envbox",
-                "envbuilder",
-                "external"
-            ],
-            "x-enum-varnames": [
-                "WorkspaceAgentLogSourceStartupScript",
-                "WorkspaceAgentLogSourceShutdownScript",
-                "WorkspaceAgentLogSourceKubernetes",
-                "WorkspaceAgentLogSourceEnvbox",
-                "WorkspaceAgentLogSourceEnvbuilder",
-                "WorkspaceAgentLogSourceExternal"
-            ]
-        },
-        "codersdk.WorkspaceAgentMetadataDescription": {
-            "type": "object",
-            "properties": {
-                "display_name": {
+                "log_path": {
                     "type": "string"
                 },
-                "interval": {
-                    "type": "integer"
+                "log_source_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "key": {
-                    "type": "string"
+                "run_on_start": {
+                    "type": "boolean"
+                },
+                "run_on_stop": {
+                    "type": "boolean"
                 },
                 "script": {
                     "type": "string"
                 },
+                "start_blocks_login": {
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "description": "Status, ExitCode, StartedAt and EndedAt describe the most recent\nexecution of the script, as reported by the agent.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentScriptStatus"
+                        }
+                    ]
+                },
                 "timeout": {
                     "type": "integer"
                 }
             }
         },
+        "codersdk.WorkspaceAgentScriptStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "ok",
+                "exit_failure",
+                "timed_out"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentScriptStatusPending",
+                "WorkspaceAgentScriptStatusRunning",
+                "WorkspaceAgentScriptStatusOK",
+                "WorkspaceAgentScriptStatusExitFailure",
+                "WorkspaceAgentScriptStatusTimedOut"
+            ]
+        },
         "codersdk.WorkspaceAgentStartupScriptBehavior": {
             "type": "string",
             "enum": [
@@ -10911,6 +14400,25 @@ const docTemplate = `{
                 "WorkspaceAgentTimeout"
             ]
         },
+        "codersdk.WorkspaceAgentUpdateState": {
+            "type": "string",
+            "enum": [
+                "unknown",
+                "up_to_date",
+                "disabled",
+                "updating",
+                "updated",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentUpdateStateUnknown",
+                "WorkspaceAgentUpdateStateUpToDate",
+                "WorkspaceAgentUpdateStateDisabled",
+                "WorkspaceAgentUpdateStateUpdating",
+                "WorkspaceAgentUpdateStateUpdated",
+                "WorkspaceAgentUpdateStateFailed"
+            ]
+        },
         "codersdk.WorkspaceApp": {
             "type": "object",
             "properties": {
@@ -11178,6 +14686,52 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspacePrebuildPool": {
+            "type": "object",
+            "properties": {
+                "building_instances": {
+                    "description": "BuildingInstances are being built.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "desired_instances": {
+                    "type": "integer"
+                },
+                "failed_instances": {
+                    "description": "FailedInstances failed to build, or were stopped, and will be deleted.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
+                },
+                "ready_instances": {
+                    "description": "ReadyInstances are built and can be claimed.",
+                    "type": "integer"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.WorkspaceProxy": {
             "type": "object",
             "properties": {
@@ -11333,6 +14887,74 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceSessionRecording": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "command": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "ended_at": {
+                    "description": "EndedAt is unset while the session is still being recorded.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "includes_input": {
+                    "description": "IncludesInput is true if what the user typed was recorded too.",
+                    "type": "boolean"
+                },
+                "reconnect_id": {
+                    "description": "ReconnectID is the ID of the reconnecting PTY session that was\nrecorded.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "size": {
+                    "description": "Size is the size of the recording in bytes.",
+                    "type": "integer"
+                },
+                "type": {
+                    "enum": [
+                        "ssh",
+                        "reconnecting_pty"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceSessionRecordingType"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceSessionRecordingType": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "reconnecting_pty"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceSessionRecordingTypeSSH",
+                "WorkspaceSessionRecordingTypeReconnectingPTY"
+            ]
+        },
         "codersdk.WorkspaceStatus": {
             "type": "string",
             "enum": [
@@ -11870,6 +15492,10 @@ const docTemplate = `{
                 "app_request": {
                     "$ref": "#/definitions/workspaceapps.Request"
                 },
+                "client_ip": {
+                    "description": "ClientIP and UserAgent describe the user's request for the connection\nlog, as the request that issues the token may come from a proxy.",
+                    "type": "string"
+                },
                 "path_app_base_url": {
                     "description": "PathAppBaseURL is required.",
                     "type": "string"
@@ -11877,6 +15503,9 @@ const docTemplate = `{
                 "session_token": {
                     "description": "SessionToken is the session token provided by the user.",
                     "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
                 }
             }
         },
@@ -2,6 +2,7 @@ package coderd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/apikey"
@@ -56,10 +58,25 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	scope := database.APIKeyScopeAll
-	if scope != "" {
+	if createToken.Scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
 
+	scopeRules, err := convertCreateScopeRules(createToken.ScopeRules)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid scope rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if (scope == database.APIKeyScopeCustom) != (len(scopeRules) > 0) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Scope rules are required for, and only allowed with, the %q scope.", database.APIKeyScopeCustom),
+		})
+		return
+	}
+
 	// default lifetime is 30 days
 	lifeTime := 30 * 24 * time.Hour
 	if createToken.Lifetime != 0 {
@@ -72,7 +89,7 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		tokenName = createToken.TokenName
 	}
 
-	err := api.validateAPIKeyLifetime(lifeTime)
+	err = api.validateAPIKeyLifetime(user, lifeTime)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Failed to validate create API key request.",
@@ -87,6 +104,7 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		DeploymentValues: api.DeploymentValues,
 		ExpiresAt:        dbtime.Now().Add(lifeTime),
 		Scope:            scope,
+		ScopeRules:       scopeRules,
 		LifetimeSeconds:  int64(lifeTime.Seconds()),
 		TokenName:        tokenName,
 	})
@@ -111,6 +129,56 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: cookie.Value})
 }
 
+// convertCreateScopeRules validates the scope rules of a create token request
+// and converts them to RBAC scope rules.
+func convertCreateScopeRules(rules []codersdk.APIKeyScopeRule) ([]rbac.ScopeRule, error) {
+	converted := make([]rbac.ScopeRule, 0, len(rules))
+	for _, rule := range rules {
+		if !slices.Contains(codersdk.AllRBACResources, rule.ResourceType) {
+			return nil, xerrors.Errorf("invalid resource type %q", rule.ResourceType)
+		}
+		if rule.ResourceType == codersdk.ResourceAPIKey {
+			// Otherwise the token could create tokens without its scope.
+			return nil, xerrors.Errorf("scope rules cannot allow actions on %q", codersdk.ResourceAPIKey)
+		}
+		if rule.Action != rbac.WildcardSymbol && !slices.Contains(codersdk.AllRBACActions, rule.Action) {
+			return nil, xerrors.Errorf("invalid action %q, actions must be one of %q or %q", rule.Action, codersdk.AllRBACActions, rbac.WildcardSymbol)
+		}
+		scopeRule := rbac.ScopeRule{
+			ResourceType: string(rule.ResourceType),
+			Action:       rbac.Action(rule.Action),
+		}
+		if rule.ResourceID != nil {
+			scopeRule.ResourceID = rule.ResourceID.String()
+		}
+		converted = append(converted, scopeRule)
+	}
+	return converted, nil
+}
+
+// convertScopeRules converts the scope rules stored with an API key.
+func convertScopeRules(raw []byte) []codersdk.APIKeyScopeRule {
+	var rules []rbac.ScopeRule
+	// The rules are always written by apikey.Generate, so they can only fail
+	// to parse if the database was edited by hand.
+	_ = json.Unmarshal(raw, &rules)
+	if len(rules) == 0 {
+		return nil
+	}
+	converted := make([]codersdk.APIKeyScopeRule, 0, len(rules))
+	for _, rule := range rules {
+		scopeRule := codersdk.APIKeyScopeRule{
+			ResourceType: codersdk.RBACResource(rule.ResourceType),
+			Action:       string(rule.Action),
+		}
+		if id, err := uuid.Parse(rule.ResourceID); err == nil {
+			scopeRule.ResourceID = &id
+		}
+		converted = append(converted, scopeRule)
+	}
+	return converted
+}
+
 // Creates a new session key, used for logging in via the CLI.
 //
 // @Summary Create new session key
@@ -320,7 +388,7 @@ func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 	aReq.Old = key
 	defer commitAudit()
 
-	err = api.Database.DeleteAPIKeyByID(ctx, keyID)
+	_, err = api.Database.DeleteAPIKeyByID(ctx, keyID)
 	if httpapi.Is404Error(err) {
 		httpapi.ResourceNotFound(rw)
 		return
@@ -345,29 +413,35 @@ func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} codersdk.TokenConfig
 // @Router /users/{user}/keys/tokens/tokenconfig [get]
 func (api *API) tokenConfig(rw http.ResponseWriter, r *http.Request) {
-	values, err := api.DeploymentValues.WithoutSecrets()
-	if err != nil {
-		httpapi.InternalServerError(rw, err)
-		return
-	}
+	user := httpmw.UserParam(r)
 
 	httpapi.Write(
 		r.Context(), rw, http.StatusOK,
 		codersdk.TokenConfig{
-			MaxTokenLifetime: values.MaxTokenLifetime.Value(),
+			MaxTokenLifetime: api.maxTokenLifetime(user),
 		},
 	)
 }
 
-func (api *API) validateAPIKeyLifetime(lifetime time.Duration) error {
+// maxTokenLifetime returns the longest lifetime a token owned by the user
+// may have. Service accounts have their own limit.
+func (api *API) maxTokenLifetime(user database.User) time.Duration {
+	if user.IsServiceAccount {
+		return api.DeploymentValues.MaxServiceAccountTokenLifetime.Value()
+	}
+	return api.DeploymentValues.MaxTokenLifetime.Value()
+}
+
+func (api *API) validateAPIKeyLifetime(user database.User, lifetime time.Duration) error {
 	if lifetime <= 0 {
 		return xerrors.New("lifetime must be positive number greater than 0")
 	}
 
-	if lifetime > api.DeploymentValues.MaxTokenLifetime.Value() {
+	maxLifetime := api.maxTokenLifetime(user)
+	if lifetime > maxLifetime {
 		return xerrors.Errorf(
 			"lifetime must be less than %v",
-			api.DeploymentValues.MaxTokenLifetime,
+			maxLifetime,
 		)
 	}
 
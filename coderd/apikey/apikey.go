@@ -2,6 +2,7 @@ package apikey
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"net"
 	"time"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 )
@@ -25,8 +27,10 @@ type CreateParams struct {
 	ExpiresAt       time.Time
 	LifetimeSeconds int64
 	Scope           database.APIKeyScope
-	TokenName       string
-	RemoteAddr      string
+	// ScopeRules are required for, and only allowed with, the custom scope.
+	ScopeRules []rbac.ScopeRule
+	TokenName  string
+	RemoteAddr string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -67,10 +71,26 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 	}
 	switch scope {
 	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+		if len(params.ScopeRules) > 0 {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("scope rules are only allowed with the %q scope", database.APIKeyScopeCustom)
+		}
+	case database.APIKeyScopeCustom:
+		if len(params.ScopeRules) == 0 {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("the %q scope requires at least one scope rule", database.APIKeyScopeCustom)
+		}
 	default:
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
 
+	scopeRules := params.ScopeRules
+	if scopeRules == nil {
+		scopeRules = []rbac.ScopeRule{}
+	}
+	rawScopeRules, err := json.Marshal(scopeRules)
+	if err != nil {
+		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("marshal scope rules: %w", err)
+	}
+
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
 	return database.InsertAPIKeyParams{
@@ -91,6 +111,7 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		HashedSecret: hashed[:],
 		LoginType:    params.LoginType,
 		Scope:        scope,
+		ScopeRules:   rawScopeRules,
 		TokenName:    params.TokenName,
 	}, token, nil
 }
@@ -85,6 +85,117 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenCustomScope(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	other := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, other.LatestBuild.ID)
+
+	scopedClient := func(ctx context.Context, t *testing.T, rules ...codersdk.APIKeyScopeRule) *codersdk.Client {
+		t.Helper()
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:      codersdk.APIKeyScopeCustom,
+			ScopeRules: rules,
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+		return scoped
+	}
+	// Reading a workspace also reads its template and owner.
+	readRules := []codersdk.APIKeyScopeRule{
+		{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionRead},
+		{ResourceType: codersdk.ResourceUser, Action: codersdk.ActionRead},
+	}
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scoped := scopedClient(ctx, t, append([]codersdk.APIKeyScopeRule{
+			{ResourceType: codersdk.ResourceWorkspace, Action: codersdk.ActionRead},
+		}, readRules...)...)
+
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 2)
+
+		err = scoped.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{Name: "renamed"})
+		require.Error(t, err)
+
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("SingleWorkspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scoped := scopedClient(ctx, t, append([]codersdk.APIKeyScopeRule{
+			{ResourceType: codersdk.ResourceWorkspace, Action: "*", ResourceID: &workspace.ID},
+		}, readRules...)...)
+
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+		require.Equal(t, workspace.ID, workspaces.Workspaces[0].ID)
+
+		_, err = scoped.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		_, err = scoped.Workspace(ctx, other.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_ = scopedClient(ctx, t, codersdk.APIKeyScopeRule{
+			ResourceType: codersdk.ResourceWorkspace,
+			Action:       codersdk.ActionRead,
+			ResourceID:   &workspace.ID,
+		})
+
+		keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		found := false
+		for _, key := range keys {
+			if len(key.ScopeRules) != 1 {
+				continue
+			}
+			found = true
+			require.Equal(t, codersdk.APIKeyScopeCustom, key.Scope)
+			require.Equal(t, []codersdk.APIKeyScopeRule{{
+				ResourceType: codersdk.ResourceWorkspace,
+				Action:       codersdk.ActionRead,
+				ResourceID:   &workspace.ID,
+			}}, key.ScopeRules)
+		}
+		require.True(t, found, "custom scoped token not listed")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		for _, req := range []codersdk.CreateTokenRequest{
+			{Scope: codersdk.APIKeyScopeCustom},
+			{Scope: codersdk.APIKeyScopeAll, ScopeRules: readRules},
+			{Scope: codersdk.APIKeyScopeCustom, ScopeRules: []codersdk.APIKeyScopeRule{{ResourceType: "invalid", Action: codersdk.ActionRead}}},
+			{Scope: codersdk.APIKeyScopeCustom, ScopeRules: []codersdk.APIKeyScopeRule{{ResourceType: codersdk.ResourceWorkspace, Action: "invalid"}}},
+			{Scope: codersdk.APIKeyScopeCustom, ScopeRules: []codersdk.APIKeyScopeRule{{ResourceType: codersdk.ResourceAPIKey, Action: codersdk.ActionCreate}}},
+		} {
+			_, err := client.CreateToken(ctx, codersdk.Me, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
@@ -144,6 +255,49 @@ func TestTokenUserSetMaxLifetime(t *testing.T) {
 	require.ErrorContains(t, err, "lifetime must be less")
 }
 
+func TestTokenServiceAccountMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	dc := coderdtest.DeploymentValues(t)
+	dc.MaxTokenLifetime = clibase.Duration(time.Hour * 24 * 7)
+	dc.MaxServiceAccountTokenLifetime = clibase.Duration(time.Hour * 24 * 365)
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: dc,
+	})
+	first := coderdtest.CreateFirstUser(t, client)
+
+	serviceAccount, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+		OrganizationID: first.OrganizationID,
+		Email:          "ci@coder.com",
+		Username:       "ci-bot",
+		ServiceAccount: true,
+	})
+	require.NoError(t, err)
+
+	config, err := client.GetTokenConfig(ctx, serviceAccount.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, dc.MaxServiceAccountTokenLifetime.Value(), config.MaxTokenLifetime)
+
+	// Service accounts are not limited by the max token lifetime of users.
+	_, err = client.CreateToken(ctx, serviceAccount.ID.String(), codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 300,
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateToken(ctx, serviceAccount.ID.String(), codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 400,
+	})
+	require.ErrorContains(t, err, "lifetime must be less")
+
+	// Users are still limited.
+	_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 300,
+	})
+	require.ErrorContains(t, err, "lifetime must be less")
+}
+
 func TestSessionExpiry(t *testing.T) {
 	t.Parallel()
 
@@ -3,6 +3,7 @@ package autobuild
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,16 +17,22 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 )
 
+// autostopNotice is how long before the deadline of a workspace its owner is
+// notified that it will stop.
+const autostopNotice = 30 * time.Minute
+
 // Executor automatically starts or stops workspaces.
 type Executor struct {
 	ctx                   context.Context
 	db                    database.Store
+	notifications         *notifications.Enqueuer
 	templateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
 	log                   slog.Logger
 	tick                  <-chan time.Time
@@ -40,11 +47,12 @@ type Stats struct {
 }
 
 // New returns a new wsactions executor.
-func NewExecutor(ctx context.Context, db database.Store, tss *atomic.Pointer[schedule.TemplateScheduleStore], log slog.Logger, tick <-chan time.Time) *Executor {
+func NewExecutor(ctx context.Context, db database.Store, enq *notifications.Enqueuer, tss *atomic.Pointer[schedule.TemplateScheduleStore], log slog.Logger, tick <-chan time.Time) *Executor {
 	le := &Executor{
 		//nolint:gocritic // Autostart has a limited set of permissions.
 		ctx:                   dbauthz.AsAutostart(ctx),
 		db:                    db,
+		notifications:         enq,
 		templateScheduleStore: tss,
 		tick:                  tick,
 		log:                   log.Named("autobuild"),
@@ -118,6 +126,8 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		return stats
 	}
 
+	e.notifyAutostop(t)
+
 	// We only use errgroup here for convenience of API, not for early
 	// cancellation. This means we only return nil errors in th eg.Go.
 	eg := errgroup.Group{}
@@ -129,7 +139,11 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		log := e.log.With(slog.F("workspace_id", wsID))
 
 		eg.Go(func() error {
+			// Notifications are only sent once the transaction committed.
+			var notifs []notifications.Notification
 			err := e.db.InTx(func(tx database.Store) error {
+				notifs = nil
+
 				// Re-check eligibility since the first check was outside the
 				// transaction and the workspace settings may have changed.
 				ws, err := tx.GetWorkspaceByID(e.ctx, wsID)
@@ -200,6 +214,27 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						slog.F("time_til_dormant", templateSchedule.TimeTilDormant),
 						slog.F("since_last_used_at", time.Since(ws.LastUsedAt)),
 					)
+
+					notifs = append(notifs, notifications.Notification{
+						UserID: ws.OwnerID,
+						Kind:   database.NotificationKindWorkspaceDormant,
+						Labels: map[string]string{
+							"workspace":    ws.Name,
+							"last_used_at": notifications.FormatTime(ws.LastUsedAt),
+						},
+						DedupeKey: fmt.Sprintf("%s:%s:%d", database.NotificationKindWorkspaceDormant, ws.ID, ws.DormantAt.Time.Unix()),
+					})
+					if ws.DeletingAt.Valid {
+						notifs = append(notifs, notifications.Notification{
+							UserID: ws.OwnerID,
+							Kind:   database.NotificationKindWorkspaceMarkedForDeletion,
+							Labels: map[string]string{
+								"workspace":   ws.Name,
+								"deleting_at": notifications.FormatTime(ws.DeletingAt.Time),
+							},
+							DedupeKey: fmt.Sprintf("%s:%s:%d", database.NotificationKindWorkspaceMarkedForDeletion, ws.ID, ws.DeletingAt.Time.Unix()),
+						})
+					}
 				}
 
 				if reason == database.BuildReasonAutodelete {
@@ -229,6 +264,10 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
 			if err != nil {
 				log.Error(e.ctx, "workspace scheduling failed", slog.Error(err))
+				return nil
+			}
+			for _, n := range notifs {
+				e.notify(n)
 			}
 			return nil
 		})
@@ -243,6 +282,45 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	return stats
 }
 
+// notifyAutostop notifies the owners of the workspaces that will stop within
+// autostopNotice. Owners are notified once per build and deadline, so they are
+// notified again when the deadline is extended.
+func (e *Executor) notifyAutostop(t time.Time) {
+	workspaces, err := e.db.GetWorkspacesApproachingAutostop(e.ctx, database.GetWorkspacesApproachingAutostopParams{
+		Now:        t,
+		StopBefore: t.Add(autostopNotice),
+	})
+	if err != nil {
+		e.log.Error(e.ctx, "get workspaces approaching autostop", slog.Error(err))
+		return
+	}
+	for _, ws := range workspaces {
+		e.notify(notifications.Notification{
+			UserID: ws.OwnerID,
+			Kind:   database.NotificationKindWorkspaceAutostop,
+			Labels: map[string]string{
+				"workspace": ws.Name,
+				"deadline":  notifications.FormatTime(ws.Deadline),
+			},
+			DedupeKey: fmt.Sprintf("%s:%s:%d", database.NotificationKindWorkspaceAutostop, ws.BuildID, ws.Deadline.Unix()),
+		})
+	}
+}
+
+func (e *Executor) notify(n notifications.Notification) {
+	if e.notifications == nil {
+		return
+	}
+	err := e.notifications.Enqueue(e.ctx, n)
+	if err != nil {
+		e.log.Warn(e.ctx, "enqueue notification",
+			slog.F("user_id", n.UserID),
+			slog.F("kind", n.Kind),
+			slog.Error(err),
+		)
+	}
+}
+
 // getNextTransition returns the next eligible transition for the workspace
 // as well as the reason for why it is transitioning. It is possible
 // for this function to return a nil error as well as an empty transition.
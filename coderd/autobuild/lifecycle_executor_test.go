@@ -22,6 +22,7 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestExecutorAutostartOK(t *testing.T) {
@@ -430,6 +431,45 @@ func TestExecutorWorkspaceAutostopBeforeDeadline(t *testing.T) {
 	assert.Len(t, stats.Transitions, 0)
 }
 
+func TestExecutorAutostopNotification(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan autobuild.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+		})
+		// Given: we have a user with a workspace
+		workspace = mustProvisionWorkspace(t, client)
+		ctx       = testutil.Context(t, testutil.WaitLong)
+	)
+	require.NotZero(t, workspace.LatestBuild.Deadline)
+
+	// When: the autobuild executor ticks twice shortly before the deadline
+	go func() {
+		tickCh <- workspace.LatestBuild.Deadline.Time.Add(-10 * time.Minute)
+		tickCh <- workspace.LatestBuild.Deadline.Time.Add(-9 * time.Minute)
+		close(tickCh)
+	}()
+
+	// Then: the workspace keeps running
+	for i := 0; i < 2; i++ {
+		stats := <-statsCh
+		assert.NoError(t, stats.Error)
+		assert.Len(t, stats.Transitions, 0)
+	}
+
+	// And: the owner is notified once
+	res, err := client.Notifications(ctx, codersdk.Me, codersdk.NotificationsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Notifications, 1)
+	require.Equal(t, codersdk.NotificationKindWorkspaceAutostop, res.Notifications[0].Kind)
+	require.Contains(t, res.Notifications[0].Title, workspace.Name)
+}
+
 func TestExecutorWorkspaceAutostopNoWaitChangedMyMind(t *testing.T) {
 	t.Parallel()
 
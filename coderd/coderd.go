@@ -54,6 +54,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/metricscache"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
@@ -61,6 +62,7 @@ import (
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wsconncache"
 	"github.com/coder/coder/v2/codersdk"
@@ -110,6 +112,7 @@ type Options struct {
 	GoogleTokenValidator           *idtoken.Validator
 	GithubOAuth2Config             *GithubOAuth2Config
 	OIDCConfig                     *OIDCConfig
+	LDAPConfig                     *LDAPConfig
 	PrometheusRegistry             *prometheus.Registry
 	SecureAuthCookie               bool
 	StrictTransportSecurityCfg     httpmw.HSTSConfig
@@ -163,6 +166,9 @@ type Options struct {
 
 	UpdateAgentMetrics func(ctx context.Context, username, workspaceName, agentName string, metrics []agentsdk.AgentMetric)
 	StatsBatcher       *batchstats.Batcher
+	// NotificationsEnqueuer stores the notifications of users. It's created
+	// from the deployment values if nil.
+	NotificationsEnqueuer *notifications.Enqueuer
 
 	WorkspaceAppsStatsCollectorOptions workspaceapps.StatsCollectorOptions
 }
@@ -296,6 +302,13 @@ func New(options *Options) *API {
 	if options.StatsBatcher == nil {
 		panic("developer error: options.StatsBatcher is nil")
 	}
+	if options.NotificationsEnqueuer == nil {
+		options.NotificationsEnqueuer = notifications.NewEnqueuer(
+			options.Database,
+			options.Pubsub,
+			options.DeploymentValues.Notifications.Email.Smarthost.String() != "",
+		)
+	}
 
 	siteCacheDir := options.CacheDir
 	if siteCacheDir != "" {
@@ -397,6 +410,33 @@ func New(options *Options) *API {
 		oidcAuthURLParams = options.OIDCConfig.AuthURLParams
 	}
 
+	api.webhookTicker = time.NewTicker(webhooks.PollInterval)
+	api.webhookDispatcher = webhooks.New(api.ctx,
+		options.Database,
+		options.Pubsub,
+		options.Logger.Named("webhooks"),
+		nil,
+		api.webhookTicker.C,
+	)
+	err = api.webhookDispatcher.Start()
+	if err != nil {
+		panic(xerrors.Errorf("start webhook dispatcher: %w", err))
+	}
+	if options.DeploymentValues.Notifications.Email.Smarthost.String() != "" {
+		api.notificationTicker = time.NewTicker(notifications.PollInterval)
+		api.notificationSender = notifications.NewSender(api.ctx,
+			options.Database,
+			options.Pubsub,
+			options.Logger.Named("notifications"),
+			options.DeploymentValues.Notifications.Email,
+			api.notificationTicker.C,
+		)
+		err = api.notificationSender.Start()
+		if err != nil {
+			panic(xerrors.Errorf("start notification sender: %w", err))
+		}
+	}
+
 	api.Auditor.Store(&options.Auditor)
 	api.TailnetCoordinator.Store(&options.TailnetCoordinator)
 	if api.Experiments.Enabled(codersdk.ExperimentSingleTailnet) {
@@ -553,6 +593,21 @@ func New(options *Options) *API {
 		}
 	})
 
+	// Coder as an OAuth2 authorization server for third-party apps.
+	r.Route("/oauth2", func(r chi.Router) {
+		r.Use(apiRateLimiter)
+		r.Route("/authorize", func(r chi.Router) {
+			r.Use(
+				oauth2ProviderAuthorizeHeaders,
+				apiKeyMiddlewareRedirect,
+			)
+			r.Get("/", api.getOAuth2ProviderAppAuthorize)
+			r.Post("/", api.postOAuth2ProviderAppAuthorize)
+		})
+		r.Post("/tokens", api.postOAuth2ProviderAppToken)
+		r.Post("/revoke", api.postOAuth2ProviderAppRevoke)
+	})
+
 	r.Route("/api/v2", func(r chi.Router) {
 		api.APIHandler = r
 
@@ -596,6 +651,10 @@ func New(options *Options) *API {
 			r.Get("/", api.auditLogs)
 			r.Post("/testgenerate", api.generateFakeAuditLog)
 		})
+		r.Route("/connectionlog", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.connectionLogs)
+		})
 		r.Route("/files", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -658,6 +717,12 @@ func New(options *Options) *API {
 			r.Get("/", api.template)
 			r.Delete("/", api.deleteTemplate)
 			r.Patch("/", api.patchTemplateMeta)
+			r.Route("/prebuilds", func(r chi.Router) {
+				r.Get("/", api.workspacePrebuildPools)
+				r.Post("/", api.postWorkspacePrebuildPool)
+				r.Put("/{pool}", api.putWorkspacePrebuildPool)
+				r.Delete("/{pool}", api.deleteWorkspacePrebuildPool)
+			})
 			r.Route("/versions", func(r chi.Router) {
 				r.Get("/", api.templateVersionsByTemplate)
 				r.Patch("/", api.patchActiveTemplateVersion)
@@ -677,6 +742,7 @@ func New(options *Options) *API {
 			r.Get("/schema", templateVersionSchemaDeprecated)
 			r.Get("/parameters", templateVersionParametersDeprecated)
 			r.Get("/rich-parameters", api.templateVersionRichParameters)
+			r.Get("/presets", api.templateVersionPresets)
 			r.Get("/gitauth", api.templateVersionGitAuth)
 			r.Get("/variables", api.templateVersionVariables)
 			r.Get("/resources", api.templateVersionResources)
@@ -716,6 +782,7 @@ func New(options *Options) *API {
 					)
 					r.Get("/", api.userOIDC)
 				})
+				r.Post("/ldap/login", api.postLoginLDAP)
 			})
 			r.Group(func(r chi.Router) {
 				r.Use(
@@ -772,9 +839,62 @@ func New(options *Options) *API {
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Route("/notifications", func(r chi.Router) {
+						r.Get("/", api.notifications)
+						r.Put("/read", api.putNotificationsRead)
+						r.Get("/preferences", api.notificationPreferences)
+						r.Put("/preferences", api.putNotificationPreferences)
+						r.Put("/{notification}/read", api.putNotificationRead)
+					})
+					r.Get("/pty-invites", api.userWorkspaceAgentPTYInvites)
 				})
 			})
 		})
+		r.Route("/roles", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.customRoles)
+			r.Post("/", api.postCustomRole)
+			r.Route("/{customrole}", func(r chi.Router) {
+				r.Use(httpmw.ExtractCustomRoleParam(options.Database))
+				r.Get("/", api.customRole)
+				r.Patch("/", api.patchCustomRole)
+				r.Delete("/", api.deleteCustomRole)
+			})
+		})
+		r.Route("/oauth2-provider", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Route("/apps", func(r chi.Router) {
+				r.Get("/", api.oAuth2ProviderApps)
+				r.Post("/", api.postOAuth2ProviderApp)
+				r.Route("/{app}", func(r chi.Router) {
+					r.Use(httpmw.ExtractOAuth2ProviderApp(options.Database))
+					r.Get("/", api.oAuth2ProviderApp)
+					r.Put("/", api.putOAuth2ProviderApp)
+					r.Delete("/", api.deleteOAuth2ProviderApp)
+					r.Route("/secrets", func(r chi.Router) {
+						r.Get("/", api.oAuth2ProviderAppSecrets)
+						r.Post("/", api.postOAuth2ProviderAppSecret)
+						r.Route("/{secretID}", func(r chi.Router) {
+							r.Use(httpmw.ExtractOAuth2ProviderAppSecret(options.Database))
+							r.Delete("/", api.deleteOAuth2ProviderAppSecret)
+						})
+					})
+					r.Delete("/tokens", api.deleteOAuth2ProviderAppTokens)
+				})
+			})
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.webhooks)
+			r.Post("/", api.postWebhook)
+			r.Route("/{webhook}", func(r chi.Router) {
+				r.Use(httpmw.ExtractWebhookParam(options.Database))
+				r.Get("/", api.webhook)
+				r.Patch("/", api.patchWebhook)
+				r.Delete("/", api.deleteWebhook)
+				r.Get("/deliveries", api.webhookDeliveries)
+			})
+		})
 		r.Route("/workspaceagents", func(r chi.Router) {
 			r.Post("/azure-instance-identity", api.postWorkspaceAuthAzureInstanceIdentity)
 			r.Post("/aws-instance-identity", api.postWorkspaceAuthAWSInstanceIdentity)
@@ -799,13 +919,44 @@ func New(options *Options) *API {
 				r.Post("/startup", api.postWorkspaceAgentStartup)
 				r.Patch("/startup-logs", api.patchWorkspaceAgentLogsDeprecated)
 				r.Patch("/logs", api.patchWorkspaceAgentLogs)
+				r.Post("/log-source", api.workspaceAgentPostLogSource)
+				r.Post("/script-status", api.workspaceAgentPostScriptStatus)
 				r.Post("/app-health", api.postWorkspaceAppHealth)
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
 				r.Get("/gitsshkey", api.agentGitSSHKey)
 				r.Get("/coordinate", api.workspaceAgentCoordinate)
+				r.Route("/peers", func(r chi.Router) {
+					r.Get("/", api.workspaceAgentPeers)
+					r.Get("/{workspaceagent}/coordinate", api.workspaceAgentPeerCoordinate)
+				})
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
+				r.Post("/autostop-inhibitors", api.workspaceAgentPostAutostopInhibitors)
+				r.Post("/update-state", api.workspaceAgentPostUpdateState)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadata)
+				r.Post("/connections", api.workspaceAgentPostConnection)
+				r.Route("/session-recordings", func(r chi.Router) {
+					r.Post("/", api.workspaceAgentPostSessionRecording)
+					r.Post("/{recording}/chunks", api.workspaceAgentPostSessionRecordingChunk)
+				})
+			})
+			r.Route("/{workspaceagent}/pty-sessions", func(r chi.Router) {
+				r.Use(apiKeyMiddleware)
+				// Users invited to a session can't read the workspace, so the
+				// attach handler fetches the agent and checks access itself.
+				r.Get("/{session}/attach", api.workspaceAgentPTYSessionAttach)
+				r.Group(func(r chi.Router) {
+					r.Use(
+						httpmw.ExtractWorkspaceAgentParam(options.Database),
+						httpmw.ExtractWorkspaceParam(options.Database),
+					)
+					r.Get("/", api.workspaceAgentPTYSessions)
+					r.Route("/{session}/invites", func(r chi.Router) {
+						r.Get("/", api.workspaceAgentPTYInvites)
+						r.Post("/", api.postWorkspaceAgentPTYInvite)
+						r.Delete("/{user}", api.deleteWorkspaceAgentPTYInvite)
+					})
+				})
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
 				r.Use(
@@ -853,10 +1004,22 @@ func New(options *Options) *API {
 					r.Put("/", api.putWorkspaceTTL)
 				})
 				r.Get("/watch", api.watchWorkspace)
+				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
 				r.Put("/extend", api.putExtendWorkspace)
 				r.Put("/dormant", api.putWorkspaceDormant)
+				r.Route("/port-share", func(r chi.Router) {
+					r.Get("/", api.workspaceAgentPortShares)
+					r.Post("/", api.postWorkspaceAgentPortShare)
+					r.Delete("/", api.deleteWorkspaceAgentPortShare)
+				})
+				r.Get("/session-recordings", api.workspaceSessionRecordings)
 			})
 		})
+		r.Route("/session-recordings/{recording}", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.workspaceSessionRecording)
+			r.Get("/cast", api.workspaceSessionRecordingCast)
+		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1005,6 +1168,10 @@ type API struct {
 
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
+	webhookDispatcher     *webhooks.Dispatcher
+	webhookTicker         *time.Ticker
+	notificationSender    *notifications.Sender
+	notificationTicker    *time.Ticker
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
 	workspaceAppServer    *workspaceapps.Server
 	agentProvider         workspaceapps.AgentProvider
@@ -1029,6 +1196,12 @@ func (api *API) Close() error {
 	api.WebsocketWaitMutex.Unlock()
 
 	api.metricsCache.Close()
+	api.webhookTicker.Stop()
+	api.webhookDispatcher.Close()
+	if api.notificationSender != nil {
+		api.notificationTicker.Stop()
+		api.notificationSender.Close()
+	}
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
@@ -1118,6 +1291,7 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 		provisionerdserver.Options{
 			OIDCConfig:     api.OIDCConfig,
 			GitAuthConfigs: api.GitAuthConfigs,
+			Notifications:  api.NotificationsEnqueuer,
 		},
 	)
 	if err != nil {
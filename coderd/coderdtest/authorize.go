@@ -57,12 +57,15 @@ func AssertRBAC(t *testing.T, api *coderd.API, client *codersdk.Client) RBACAsse
 	roles, err := api.Database.GetAuthorizationUserRoles(ctx, key.UserID)
 	require.NoError(t, err, "fetch user roles")
 
+	scope, err := key.RBACScope()
+	require.NoError(t, err, "read api key scope")
+
 	return RBACAsserter{
 		Subject: rbac.Subject{
 			ID:     key.UserID.String(),
 			Roles:  rbac.RoleNames(roles.Roles),
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  scope,
 		},
 		Recorder: recorder,
 	}
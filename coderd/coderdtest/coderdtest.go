@@ -64,6 +64,8 @@ import (
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
@@ -99,10 +101,13 @@ type Options struct {
 	GithubOAuth2Config    *coderd.GithubOAuth2Config
 	RealIPConfig          *httpmw.RealIPConfig
 	OIDCConfig            *coderd.OIDCConfig
+	LDAPConfig            *coderd.LDAPConfig
 	GoogleTokenValidator  *idtoken.Validator
 	SSHKeygenAlgorithm    gitsshkey.Algorithm
 	AutobuildTicker       <-chan time.Time
 	AutobuildStats        chan<- autobuild.Stats
+	PrebuildsTicker       <-chan time.Time
+	PrebuildsStats        chan<- prebuilds.Stats
 	Auditor               audit.Auditor
 	TLSCertificates       []tls.Certificate
 	GitAuthConfigs        []*gitauth.Config
@@ -199,6 +204,16 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			close(options.AutobuildStats)
 		})
 	}
+	if options.PrebuildsTicker == nil {
+		ticker := make(chan time.Time)
+		options.PrebuildsTicker = ticker
+		t.Cleanup(func() { close(ticker) })
+	}
+	if options.PrebuildsStats != nil {
+		t.Cleanup(func() {
+			close(options.PrebuildsStats)
+		})
+	}
 
 	if options.Authorizer == nil {
 		defAuth := rbac.NewCachingAuthorizer(prometheus.NewRegistry())
@@ -262,10 +277,17 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 	}
 	templateScheduleStore.Store(&options.TemplateScheduleStore)
 
+	notificationsEnqueuer := notifications.NewEnqueuer(
+		options.Database,
+		options.Pubsub,
+		options.DeploymentValues.Notifications.Email.Smarthost.String() != "",
+	)
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	lifecycleExecutor := autobuild.NewExecutor(
 		ctx,
 		options.Database,
+		notificationsEnqueuer,
 		&templateScheduleStore,
 		slogtest.Make(t, nil).Named("autobuild.executor").Leveled(slog.LevelDebug),
 		options.AutobuildTicker,
@@ -278,6 +300,15 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 	hangDetector.Start()
 	t.Cleanup(hangDetector.Close)
 
+	prebuildsReconciler := prebuilds.New(
+		ctx,
+		options.Database,
+		slogtest.Make(t, nil).Named("prebuilds.reconciler").Leveled(slog.LevelDebug),
+		options.PrebuildsTicker,
+	).WithStatsChannel(options.PrebuildsStats)
+	prebuildsReconciler.Start()
+	t.Cleanup(prebuildsReconciler.Close)
+
 	var mutex sync.RWMutex
 	var handler http.Handler
 	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -399,6 +430,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			GithubOAuth2Config:                 options.GithubOAuth2Config,
 			RealIPConfig:                       options.RealIPConfig,
 			OIDCConfig:                         options.OIDCConfig,
+			LDAPConfig:                         options.LDAPConfig,
 			GoogleTokenValidator:               options.GoogleTokenValidator,
 			SSHKeygenAlgorithm:                 options.SSHKeygenAlgorithm,
 			DERPServer:                         derpServer,
@@ -424,6 +456,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			HealthcheckTimeout:                 options.HealthcheckTimeout,
 			HealthcheckRefresh:                 options.HealthcheckRefresh,
 			StatsBatcher:                       options.StatsBatcher,
+			NotificationsEnqueuer:              notificationsEnqueuer,
 			WorkspaceAppsStatsCollectorOptions: options.WorkspaceAppsStatsCollectorOptions,
 		}
 }
@@ -0,0 +1,409 @@
+// Package ldaptest provides an in-process LDAP server for testing code that
+// authenticates users against a directory.
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+// startTLSOID is the name of the StartTLS extended operation.
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// Entry is an entry of the directory.
+type Entry struct {
+	DN string
+	// Password is the password the entry binds with. Entries without a
+	// password cannot bind.
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server is a minimal LDAP server. It supports simple binds, searches of its
+// entries and, if enabled, StartTLS. Attribute names and values are matched
+// case insensitively. Like real servers, it accepts binds with an empty
+// password as unauthenticated binds.
+type Server struct {
+	// URL is the ldap:// URL the server listens on.
+	URL string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu        sync.Mutex
+	entries   []Entry
+	tlsConfig *tls.Config
+	conns     map[net.Conn]struct{}
+	closed    bool
+}
+
+// New starts a server with the entries that is closed when the test ends.
+func New(t testing.TB, entries ...Entry) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &Server{
+		URL:      "ldap://" + listener.Addr().String(),
+		listener: listener,
+		entries:  entries,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.accept()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// EnableStartTLS makes the server accept StartTLS with the certificate.
+func (s *Server) EnableStartTLS(cert tls.Certificate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tlsConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// Put adds an entry, or replaces the entry with the same DN.
+func (s *Server) Put(entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.entries {
+		if strings.EqualFold(existing.DN, entry.DN) {
+			s.entries[i] = entry
+			return
+		}
+	}
+	s.entries = append(s.entries, entry)
+}
+
+// Close stops the server and closes open connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	_ = s.listener.Close()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				_ = conn.Close()
+			}()
+			s.serve(conn)
+		}()
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := ber.ReadPacket(reader)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, ok := msg.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+		reply := func(op *ber.Packet) bool {
+			envelope := ber.NewSequence("LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+			envelope.AppendChild(op)
+			_, err := conn.Write(envelope.Bytes())
+			return err == nil
+		}
+
+		op := msg.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			if !reply(s.bind(op)) {
+				return
+			}
+		case ldap.ApplicationSearchRequest:
+			for _, resp := range s.search(op) {
+				if !reply(resp) {
+					return
+				}
+			}
+		case ldap.ApplicationExtendedRequest:
+			s.mu.Lock()
+			tlsConfig := s.tlsConfig
+			s.mu.Unlock()
+			if len(op.Children) == 0 || str(op.Children[0]) != startTLSOID || tlsConfig == nil {
+				if !reply(result(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError, "unsupported extended operation")) {
+					return
+				}
+				continue
+			}
+			if !reply(result(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess, "")) {
+				return
+			}
+			tlsConn := tls.Server(conn, tlsConfig)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(tlsConn)
+		default:
+			// Unbind requests and unsupported operations.
+			return
+		}
+	}
+}
+
+func (s *Server) bind(op *ber.Packet) *ber.Packet {
+	if len(op.Children) < 3 {
+		return result(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, "invalid bind request")
+	}
+	dn, password := str(op.Children[1]), str(op.Children[2])
+	if password == "" {
+		// An unauthenticated bind, see RFC 4513 section 5.1.2.
+		return result(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password != "" && entry.Password == password {
+			return result(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+		}
+	}
+	return result(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *Server) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{result(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "invalid search request")}
+	}
+	baseDN := str(op.Children[0])
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attributes []string
+	for _, attribute := range op.Children[7].Children {
+		attributes = append(attributes, str(attribute))
+	}
+
+	s.mu.Lock()
+	entries := append([]Entry(nil), s.entries...)
+	s.mu.Unlock()
+
+	var resps []*ber.Packet
+	for _, entry := range entries {
+		if !inScope(entry.DN, baseDN, int(scope)) {
+			continue
+		}
+		ok, err := match(filter, entry)
+		if err != nil {
+			return []*ber.Packet{result(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, err.Error())}
+		}
+		if !ok {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(resps)) >= sizeLimit {
+			return append(resps, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSizeLimitExceeded, "size limit exceeded"))
+		}
+		resps = append(resps, searchResultEntry(entry, attributes))
+	}
+	return append(resps, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+}
+
+func inScope(dn, baseDN string, scope int) bool {
+	dn, baseDN = strings.ToLower(dn), strings.ToLower(baseDN)
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == baseDN
+	case ldap.ScopeSingleLevel:
+		_, parent, _ := strings.Cut(dn, ",")
+		return parent == baseDN
+	default:
+		return baseDN == "" || dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+	}
+}
+
+func match(filter *ber.Packet, entry Entry) (bool, error) {
+	if filter.ClassType != ber.ClassContext {
+		return false, xerrors.Errorf("invalid filter class %d", filter.ClassType)
+	}
+	switch filter.Tag {
+	case ldap.FilterAnd, ldap.FilterOr:
+		for _, child := range filter.Children {
+			ok, err := match(child, entry)
+			if err != nil {
+				return false, err
+			}
+			if filter.Tag == ldap.FilterAnd && !ok {
+				return false, nil
+			}
+			if filter.Tag == ldap.FilterOr && ok {
+				return true, nil
+			}
+		}
+		return filter.Tag == ldap.FilterAnd, nil
+	case ldap.FilterNot:
+		if len(filter.Children) != 1 {
+			return false, xerrors.New("invalid not filter")
+		}
+		ok, err := match(filter.Children[0], entry)
+		return !ok, err
+	case ldap.FilterPresent:
+		return len(values(entry, str(filter))) > 0, nil
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch, ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual:
+		if len(filter.Children) < 2 {
+			return false, xerrors.New("invalid attribute value assertion")
+		}
+		want := strings.ToLower(str(filter.Children[1]))
+		for _, value := range values(entry, str(filter.Children[0])) {
+			value = strings.ToLower(value)
+			switch {
+			case filter.Tag == ldap.FilterGreaterOrEqual && value >= want,
+				filter.Tag == ldap.FilterLessOrEqual && value <= want,
+				(filter.Tag == ldap.FilterEqualityMatch || filter.Tag == ldap.FilterApproxMatch) && value == want:
+				return true, nil
+			}
+		}
+		return false, nil
+	case ldap.FilterSubstrings:
+		if len(filter.Children) < 2 {
+			return false, xerrors.New("invalid substrings filter")
+		}
+		for _, value := range values(entry, str(filter.Children[0])) {
+			if matchSubstrings(strings.ToLower(value), filter.Children[1].Children) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, xerrors.Errorf("unsupported filter %d", filter.Tag)
+	}
+}
+
+func matchSubstrings(value string, substrings []*ber.Packet) bool {
+	for _, substring := range substrings {
+		part := strings.ToLower(str(substring))
+		switch substring.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, part) {
+				return false
+			}
+			value = value[len(part):]
+		case ldap.FilterSubstringsAny:
+			i := strings.Index(value, part)
+			if i < 0 {
+				return false
+			}
+			value = value[i+len(part):]
+		case ldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(value, part) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func values(entry Entry, attribute string) []string {
+	for name, values := range entry.Attributes {
+		if strings.EqualFold(name, attribute) {
+			return values
+		}
+	}
+	return nil
+}
+
+func searchResultEntry(entry Entry, attributes []string) *ber.Packet {
+	names := make([]string, 0, len(entry.Attributes))
+	for name := range entry.Attributes {
+		if len(attributes) == 0 || containsFold(attributes, name) || containsFold(attributes, "*") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	partialAttributes := ber.NewSequence("Attributes")
+	for _, name := range names {
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range entry.Attributes[name] {
+			vals.AppendChild(octetString(value))
+		}
+		attribute := ber.NewSequence("Attribute")
+		attribute.AppendChild(octetString(name))
+		attribute.AppendChild(vals)
+		partialAttributes.AppendChild(attribute)
+	}
+	resp := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	resp.AppendChild(octetString(entry.DN))
+	resp.AppendChild(partialAttributes)
+	return resp
+}
+
+func result(tag ber.Tag, code int64, message string) *ber.Packet {
+	resp := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	resp.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	resp.AppendChild(octetString(""))
+	resp.AppendChild(octetString(message))
+	return resp
+}
+
+func octetString(s string) *ber.Packet {
+	return ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, s, "")
+}
+
+// str returns the contents of a primitive packet as a string. Only packets
+// of the universal class have a decoded value.
+func str(p *ber.Packet) string {
+	return p.Data.String()
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
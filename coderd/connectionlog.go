@@ -0,0 +1,230 @@
+package coderd
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/searchquery"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Post workspace agent connection
+// @ID post-workspace-agent-connection
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostConnectionRequest true "Connection request"
+// @Success 204
+// @Router /workspaceagents/me/connections [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostConnection(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostConnectionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.ID == uuid.Nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A connection ID is required.",
+		})
+		return
+	}
+	connectionType := database.ConnectionType(req.Type)
+	if !connectionType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid connection type.",
+			Detail:  fmt.Sprintf("Invalid type %q, must be one of %q.", req.Type, database.AllConnectionTypeValues()),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The agent only knows the tailnet address of the client, so the user is
+	// looked up by the coordinator from the addresses that clients announce.
+	var userID uuid.NullUUID
+	if ip, err := netip.ParseAddr(req.IP); err == nil {
+		userID.UUID, userID.Valid, err = (*api.TailnetCoordinator.Load()).ClientUser(ctx, workspaceAgent.ID, ip.Unmap())
+		if err != nil {
+			api.Logger.Warn(ctx, "look up the user of a connection", slog.F("agent_id", workspaceAgent.ID), slog.Error(err))
+		}
+	}
+
+	params := database.UpsertConnectionLogParams{
+		ID:               req.ID,
+		ConnectTime:      req.ConnectTime,
+		OrganizationID:   workspace.OrganizationID,
+		WorkspaceOwnerID: workspace.OwnerID,
+		WorkspaceID:      workspace.ID,
+		WorkspaceName:    workspace.Name,
+		AgentID:          workspaceAgent.ID,
+		AgentName:        workspaceAgent.Name,
+		Type:             connectionType,
+		UserID:           userID,
+		Ip:               parseInet(req.IP),
+		SlugOrPort:       req.SlugOrPort,
+		BytesSent:        req.BytesSent,
+		BytesReceived:    req.BytesReceived,
+		DisconnectReason: req.DisconnectReason,
+	}
+	if req.ExitCode != nil {
+		params.Code = sql.NullInt32{Int32: *req.ExitCode, Valid: true}
+	}
+	if req.DisconnectTime != nil {
+		params.DisconnectTime = sql.NullTime{Time: *req.DisconnectTime, Valid: true}
+	}
+
+	// nolint:gocritic // Connections can only be logged by agents.
+	_, err = api.Database.UpsertConnectionLog(dbauthz.AsSystemRestricted(ctx), params)
+	if httpapi.Is404Error(err) {
+		// The ID is already used by a connection of another agent.
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Connection %q does not belong to this agent.", req.ID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error logging connection.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get connection logs
+// @ID get-connection-logs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Connection Log
+// @Param q query string false "Search query"
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} codersdk.ConnectionLogResponse
+// @Router /connectionlog [get]
+func (api *API) connectionLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	queryStr := r.URL.Query().Get("q")
+	filter, errs := searchquery.ConnectionLogs(queryStr)
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid connection log search query.",
+			Validations: errs,
+		})
+		return
+	}
+	filter.OffsetOpt = int32(page.Offset)
+	filter.LimitOpt = int32(page.Limit)
+
+	if filter.Username == "me" {
+		filter.UserID = apiKey.UserID
+		filter.Username = ""
+	}
+	if filter.WorkspaceOwner == "me" {
+		filter.WorkspaceOwnerID = apiKey.UserID
+		filter.WorkspaceOwner = ""
+	}
+
+	dblogs, err := api.Database.GetConnectionLogsOffset(ctx, filter)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	// GetConnectionLogsOffset uses a window function to get the count, so
+	// an empty page has no count.
+	if len(dblogs) == 0 {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.ConnectionLogResponse{
+			ConnectionLogs: []codersdk.ConnectionLog{},
+			Count:          0,
+		})
+		return
+	}
+
+	logs := make([]codersdk.ConnectionLog, 0, len(dblogs))
+	for _, dblog := range dblogs {
+		logs = append(logs, convertConnectionLog(dblog))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ConnectionLogResponse{
+		ConnectionLogs: logs,
+		Count:          dblogs[0].Count,
+	})
+}
+
+func convertConnectionLog(dblog database.GetConnectionLogsOffsetRow) codersdk.ConnectionLog {
+	ip, _ := netip.AddrFromSlice(dblog.Ip.IPNet.IP)
+	log := codersdk.ConnectionLog{
+		ID:                     dblog.ID,
+		ConnectTime:            dblog.ConnectTime,
+		OrganizationID:         dblog.OrganizationID,
+		WorkspaceOwnerID:       dblog.WorkspaceOwnerID,
+		WorkspaceOwnerUsername: dblog.WorkspaceOwnerUsername.String,
+		WorkspaceID:            dblog.WorkspaceID,
+		WorkspaceName:          dblog.WorkspaceName,
+		AgentID:                dblog.AgentID,
+		AgentName:              dblog.AgentName,
+		Type:                   codersdk.ConnectionType(dblog.Type),
+		Username:               dblog.UserUsername.String,
+		IP:                     ip.Unmap(),
+		UserAgent:              dblog.UserAgent.String,
+		SlugOrPort:             dblog.SlugOrPort,
+		BytesSent:              dblog.BytesSent,
+		BytesReceived:          dblog.BytesReceived,
+		DisconnectReason:       dblog.DisconnectReason,
+	}
+	if dblog.DisconnectTime.Valid {
+		log.DisconnectTime = &dblog.DisconnectTime.Time
+	}
+	if dblog.UserID.Valid {
+		log.UserID = &dblog.UserID.UUID
+	}
+	if dblog.Code.Valid {
+		log.Code = &dblog.Code.Int32
+	}
+	return log
+}
+
+// parseInet parses an IP address reported by an agent. Invalid addresses are
+// stored as NULL.
+func parseInet(ipStr string) pqtype.Inet {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return pqtype.Inet{}
+	}
+	return pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+		},
+		Valid: true,
+	}
+}
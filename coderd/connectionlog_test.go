@@ -0,0 +1,227 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestConnectionLogs(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// The agent reports a connection when it opens and when it closes.
+	connectTime := time.Now().Add(-time.Minute).UTC().Truncate(time.Millisecond)
+	disconnectTime := connectTime.Add(30 * time.Second)
+	exitCode := int32(3)
+	sshConn := agentsdk.PostConnectionRequest{
+		ID:          uuid.New(),
+		Type:        codersdk.ConnectionTypeSSH,
+		ConnectTime: connectTime,
+		IP:          "fd7a:115c:a1e0::1",
+	}
+	err := agentClient.PostConnection(ctx, sshConn)
+	require.NoError(t, err)
+	sshConn.DisconnectTime = &disconnectTime
+	sshConn.ExitCode = &exitCode
+	sshConn.BytesSent = 100
+	sshConn.BytesReceived = 10
+	err = agentClient.PostConnection(ctx, sshConn)
+	require.NoError(t, err)
+	// Retries of the first report don't undo the second.
+	err = agentClient.PostConnection(ctx, agentsdk.PostConnectionRequest{
+		ID:          sshConn.ID,
+		Type:        codersdk.ConnectionTypeSSH,
+		ConnectTime: connectTime,
+		IP:          "fd7a:115c:a1e0::1",
+	})
+	require.NoError(t, err)
+
+	err = agentClient.PostConnection(ctx, agentsdk.PostConnectionRequest{
+		ID:          uuid.New(),
+		Type:        codersdk.ConnectionTypePortForwarding,
+		ConnectTime: time.Now(),
+		IP:          "fd7a:115c:a1e0::2",
+		SlugOrPort:  "8080",
+	})
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, res.Count)
+		require.Len(t, res.ConnectionLogs, 2)
+		// Newest first.
+		require.Equal(t, codersdk.ConnectionTypePortForwarding, res.ConnectionLogs[0].Type)
+		require.Equal(t, "8080", res.ConnectionLogs[0].SlugOrPort)
+
+		log := res.ConnectionLogs[1]
+		require.Equal(t, sshConn.ID, log.ID)
+		require.Equal(t, workspace.ID, log.WorkspaceID)
+		require.Equal(t, workspace.Name, log.WorkspaceName)
+		require.Equal(t, user.UserID, log.WorkspaceOwnerID)
+		require.Equal(t, workspace.OwnerName, log.WorkspaceOwnerUsername)
+		require.Equal(t, "fd7a:115c:a1e0::1", log.IP.String())
+		require.NotNil(t, log.DisconnectTime)
+		require.True(t, disconnectTime.Equal(*log.DisconnectTime))
+		require.NotNil(t, log.Code)
+		require.Equal(t, exitCode, *log.Code)
+		require.EqualValues(t, 100, log.BytesSent)
+		require.EqualValues(t, 10, log.BytesReceived)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{
+			SearchQuery: "type:ssh owner:me workspace:" + workspace.Name,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.ConnectionLogs, 1)
+		require.Equal(t, sshConn.ID, res.ConnectionLogs[0].ID)
+
+		res, err = client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{
+			SearchQuery: "workspace:doesnotexist",
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.ConnectionLogs)
+		require.Zero(t, res.Count)
+
+		_, err = client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{
+			SearchQuery: "type:carrier-pigeon",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := member.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("OtherAgent", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Agents can't overwrite connections of other agents.
+		otherToken := uuid.NewString()
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(otherToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, otherVersion.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+		otherWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, otherTemplate.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, otherWorkspace.LatestBuild.ID)
+
+		otherAgent := agentsdk.New(client.URL)
+		otherAgent.SetSessionToken(otherToken)
+		err := otherAgent.PostConnection(ctx, agentsdk.PostConnectionRequest{
+			ID:          sshConn.ID,
+			Type:        codersdk.ConnectionTypeSSH,
+			ConnectTime: time.Now(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestConnectionLogs_SSHUser(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Logger: slogtest.Make(t, nil).Named("agent"),
+		Client: agentClient,
+	})
+	defer agentCloser.Close()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// The owner connects to the workspace of the member.
+	conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, &codersdk.DialWorkspaceAgentOptions{
+		Logger: slogtest.Make(t, nil).Named("client"),
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	err = session.Run("exit 0")
+	require.NoError(t, err)
+
+	var log codersdk.ConnectionLog
+	require.Eventually(t, func() bool {
+		res, err := client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{
+			SearchQuery: "type:ssh",
+		})
+		if !assert.NoError(t, err) || len(res.ConnectionLogs) == 0 {
+			return false
+		}
+		log = res.ConnectionLogs[0]
+		return true
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.NotNil(t, log.UserID, "the connection is attributed to a user")
+	require.Equal(t, owner.UserID, *log.UserID)
+	require.Equal(t, workspace.OwnerID, log.WorkspaceOwnerID)
+	require.NotEqual(t, owner.UserID, log.WorkspaceOwnerID)
+}
@@ -98,26 +98,36 @@ func ProvisionerJobStatus(provisionerJob database.ProvisionerJob) codersdk.Provi
 
 func User(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 	convertedUser := codersdk.User{
-		ID:              user.ID,
-		Email:           user.Email,
-		CreatedAt:       user.CreatedAt,
-		LastSeenAt:      user.LastSeenAt,
-		Username:        user.Username,
-		Status:          codersdk.UserStatus(user.Status),
-		OrganizationIDs: organizationIDs,
-		Roles:           make([]codersdk.Role, 0, len(user.RBACRoles)),
-		AvatarURL:       user.AvatarURL.String,
-		LoginType:       codersdk.LoginType(user.LoginType),
+		ID:               user.ID,
+		Email:            user.Email,
+		CreatedAt:        user.CreatedAt,
+		LastSeenAt:       user.LastSeenAt,
+		Username:         user.Username,
+		Status:           codersdk.UserStatus(user.Status),
+		OrganizationIDs:  organizationIDs,
+		Roles:            make([]codersdk.Role, 0, len(user.RBACRoles)),
+		AvatarURL:        user.AvatarURL.String,
+		LoginType:        codersdk.LoginType(user.LoginType),
+		IsServiceAccount: user.IsServiceAccount,
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedUser.Roles = append(convertedUser.Roles, Role(rbacRole))
+		convertedUser.Roles = append(convertedUser.Roles, RoleByName(roleName))
 	}
 
 	return convertedUser
 }
 
+// RoleByName converts an assigned role name. Custom roles are stored in the
+// database, so only their name is known here.
+func RoleByName(name string) codersdk.Role {
+	role, err := rbac.RoleByName(name)
+	if err != nil {
+		return codersdk.Role{Name: name, DisplayName: name}
+	}
+	return Role(role)
+}
+
 func Role(role rbac.Role) codersdk.Role {
 	return codersdk.Role{
 		DisplayName: role.DisplayName,
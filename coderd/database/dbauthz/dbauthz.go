@@ -207,6 +207,27 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
+	// See prebuilds package.
+	subjectPrebuilds = rbac.Subject{
+		ID: uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Name:        "prebuilds",
+				DisplayName: "Prebuilt Workspaces Daemon",
+				Site: rbac.Permissions(map[string][]rbac.Action{
+					rbac.ResourceSystem.Type:         {rbac.WildcardSymbol},
+					rbac.ResourceTemplate.Type:       {rbac.ActionRead},
+					rbac.ResourceUser.Type:           {rbac.ActionRead},
+					rbac.ResourceWorkspace.Type:      {rbac.ActionCreate, rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWorkspaceBuild.Type: {rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
 	subjectSystemRestricted = rbac.Subject{
 		ID: uuid.Nil.String(),
 		Roles: rbac.Roles([]rbac.Role{
@@ -214,20 +235,22 @@ var (
 				Name:        "system",
 				DisplayName: "Coder",
 				Site: rbac.Permissions(map[string][]rbac.Action{
-					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
-					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate, rbac.ActionDelete},
-					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
-					rbac.ResourceOrganization.Type:       {rbac.ActionCreate},
-					rbac.ResourceOrganizationMember.Type: {rbac.ActionCreate},
-					rbac.ResourceOrgRoleAssignment.Type:  {rbac.ActionCreate},
-					rbac.ResourceUser.Type:               {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceUserData.Type:           {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceWorkspace.Type:          {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceBuild.Type:     {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceExecution.Type: {rbac.ActionCreate},
-					rbac.ResourceWorkspaceProxy.Type:     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWildcard.Type:                   {rbac.ActionRead},
+					rbac.ResourceAPIKey.Type:                     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceGroup.Type:                      {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceOAuth2ProviderAppCodeToken.Type: {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceOAuth2ProviderAppSecret.Type:    {rbac.ActionUpdate},
+					rbac.ResourceRoleAssignment.Type:             {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceSystem.Type:                     {rbac.WildcardSymbol},
+					rbac.ResourceOrganization.Type:               {rbac.ActionCreate},
+					rbac.ResourceOrganizationMember.Type:         {rbac.ActionCreate},
+					rbac.ResourceOrgRoleAssignment.Type:          {rbac.ActionCreate},
+					rbac.ResourceUser.Type:                       {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceUserData.Type:                   {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceWorkspace.Type:                  {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceBuild.Type:             {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceExecution.Type:         {rbac.ActionCreate},
+					rbac.ResourceWorkspaceProxy.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -255,6 +278,12 @@ func AsHangDetector(ctx context.Context) context.Context {
 	return context.WithValue(ctx, authContextKey{}, subjectHangDetector)
 }
 
+// AsPrebuilds returns a context with an actor that has permissions required
+// for prebuilds.Reconciler to function and to claim prebuilt workspaces.
+func AsPrebuilds(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey{}, subjectPrebuilds)
+}
+
 // AsSystemRestricted returns a context with an actor that has permissions
 // required for various system operations (login, logout, metrics cache).
 func AsSystemRestricted(ctx context.Context) context.Context {
@@ -534,6 +563,16 @@ func (q *querier) authorizeUpdateFileTemplate(ctx context.Context, file database
 	}
 }
 
+// authorizeOAuth2ProviderAppToken authorizes an action on the OAuth2 token
+// backed by the given API key. Tokens belong to the owner of the key.
+func (q *querier) authorizeOAuth2ProviderAppToken(ctx context.Context, action rbac.Action, apiKeyID string) error {
+	key, err := q.db.GetAPIKeyByID(ctx, apiKeyID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, action, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String()))
+}
+
 func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, removed []string) error {
 	actor, ok := ActorFromContext(ctx)
 	if !ok {
@@ -548,6 +587,7 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	}
 
 	grantedRoles := append(added, removed...)
+	var customRoles []string
 	// Validate that the roles being assigned are valid.
 	for _, r := range grantedRoles {
 		_, isOrgRole := rbac.IsOrgRole(r)
@@ -558,12 +598,32 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 			return xerrors.Errorf("Must only update site wide roles")
 		}
 
+		if !rbac.IsBuiltInRole(r) {
+			customRoles = append(customRoles, r)
+			continue
+		}
 		// All roles should be valid roles
 		if _, err := rbac.RoleByName(r); err != nil {
 			return xerrors.Errorf("%q is not a supported role", r)
 		}
 	}
 
+	if len(customRoles) > 0 {
+		// Role definitions are not secret, any actor that can assign roles
+		// can see them.
+		found, err := q.db.GetCustomRolesByName(ctx, customRoles)
+		if err != nil {
+			return xerrors.Errorf("get custom roles: %w", err)
+		}
+		for _, r := range customRoles {
+			if !slices.ContainsFunc(found, func(role database.CustomRole) bool {
+				return role.Name == r || role.Name+":"+role.OrganizationID.UUID.String() == r
+			}) {
+				return xerrors.Errorf("%q is not a supported role", r)
+			}
+		}
+	}
+
 	if len(added) > 0 {
 		if err := q.authorizeContext(ctx, rbac.ActionCreate, roleAssign); err != nil {
 			return err
@@ -585,6 +645,46 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	return nil
 }
 
+// customRoleEscalationCheck ensures the actor has every permission a custom
+// role grants, so roles cannot be used to escalate privileges. Negated
+// permissions only take away, so they are always allowed.
+func (q *querier) customRoleEscalationCheck(ctx context.Context, organizationID uuid.NullUUID, site, org, user json.RawMessage) error {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return NoActorError
+	}
+
+	check := func(raw json.RawMessage, object func(resourceType string) rbac.Object) error {
+		var perms []rbac.Permission
+		if err := json.Unmarshal(raw, &perms); err != nil {
+			return xerrors.Errorf("unmarshal permissions: %w", err)
+		}
+		for _, perm := range perms {
+			if perm.Negate {
+				continue
+			}
+			if err := q.authorizeContext(ctx, perm.Action, object(perm.ResourceType)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := check(site, func(resourceType string) rbac.Object {
+		return rbac.Object{Type: resourceType}
+	}); err != nil {
+		return err
+	}
+	if err := check(org, func(resourceType string) rbac.Object {
+		return rbac.Object{Type: resourceType}.InOrg(organizationID.UUID)
+	}); err != nil {
+		return err
+	}
+	return check(user, func(resourceType string) rbac.Object {
+		return rbac.Object{Type: resourceType}.WithOwner(actor.ID)
+	})
+}
+
 func (q *querier) SoftDeleteTemplateByID(ctx context.Context, id uuid.UUID) error {
 	deleteF := func(ctx context.Context, id uuid.UUID) error {
 		return q.db.UpdateTemplateDeletedByID(ctx, database.UpdateTemplateDeletedByIDParams{
@@ -649,6 +749,13 @@ func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
 
+func (q *querier) AcquireNotificationMessageEmail(ctx context.Context, arg database.AcquireNotificationMessageEmailParams) (database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.NotificationMessage{}, err
+	}
+	return q.db.AcquireNotificationMessageEmail(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -657,6 +764,30 @@ func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.Acquir
 	return q.db.AcquireProvisionerJob(ctx, arg)
 }
 
+func (q *querier) AcquireWebhookDelivery(ctx context.Context, arg database.AcquireWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.db.AcquireWebhookDelivery(ctx, arg)
+}
+
+func (q *querier) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.Workspace, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+	if err != nil {
+		return database.Workspace{}, err
+	}
+
+	// Claiming a workspace updates the prebuilt workspace and creates a
+	// workspace for the new owner.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.Workspace{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(workspace.OrganizationID)); err != nil {
+		return database.Workspace{}, err
+	}
+	return q.db.ClaimPrebuiltWorkspace(ctx, arg)
+}
+
 func (q *querier) CleanTailnetCoordinators(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -664,8 +795,22 @@ func (q *querier) CleanTailnetCoordinators(ctx context.Context) error {
 	return q.db.CleanTailnetCoordinators(ctx)
 }
 
-func (q *querier) DeleteAPIKeyByID(ctx context.Context, id string) error {
-	return deleteQ(q.log, q.auth, q.db.GetAPIKeyByID, q.db.DeleteAPIKeyByID)(ctx, id)
+func (q *querier) CountUnreadNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String())); err != nil {
+		return 0, err
+	}
+	return q.db.CountUnreadNotificationMessagesByUserID(ctx, userID)
+}
+
+func (q *querier) DeleteAPIKeyByID(ctx context.Context, id string) (int64, error) {
+	key, err := q.db.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, key); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteAPIKeyByID(ctx, id)
 }
 
 func (q *querier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
@@ -695,6 +840,10 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
+func (q *querier) DeleteCustomRoleByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetCustomRoleByID, q.db.DeleteCustomRoleByID)(ctx, id)
+}
+
 func (q *querier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
 	return deleteQ(q.log, q.auth, q.db.GetGitSSHKey, q.db.DeleteGitSSHKey)(ctx, userID)
 }
@@ -732,6 +881,60 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppByID, q.db.DeleteOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (int64, error) {
+	code, err := q.db.GetOAuth2ProviderAppCodeByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, code); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID, q.db.DeleteOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
+}
+
+func (q *querier) DeleteOldConnectionLogs(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldConnectionLogs(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldNotificationMessages(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNotificationMessages(ctx)
+}
+
+func (q *querier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWebhookDeliveries(ctx)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -739,6 +942,13 @@ func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	return q.db.DeleteOldWorkspaceAgentLogs(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceAgentLogsBySourceID(ctx context.Context, arg database.DeleteOldWorkspaceAgentLogsBySourceIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentLogsBySourceID(ctx, arg)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -767,6 +977,75 @@ func (q *querier) DeleteTailnetClient(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetClient(ctx, arg)
 }
 
+func (q *querier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetWebhookByID, q.db.DeleteWebhookByID)(ctx, id)
+}
+
+func (q *querier) DeleteWorkspaceAgentPTYInvite(ctx context.Context, arg database.DeleteWorkspaceAgentPTYInviteParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	// Managing who may attach to a terminal requires being able to open one.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, workspace.ExecutionRBAC()); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceAgentPTYInvite(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	// Unsharing a port is updating the workspace.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceAgentPortShare(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
+}
+
+func (q *querier) DeleteWorkspacePrebuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspacePrebuild, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return database.WorkspacePrebuild{}, err
+	}
+	return q.db.DeleteWorkspacePrebuildByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspacePrebuildPoolByID(ctx context.Context, id uuid.UUID) error {
+	pool, err := q.db.GetWorkspacePrebuildPoolByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	template, err := q.db.GetTemplateByID(ctx, pool.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	// Pools are part of the template.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspacePrebuildPoolByID(ctx, id)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -838,6 +1117,72 @@ func (q *querier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUI
 	return q.db.GetAuthorizationUserRoles(ctx, userID)
 }
 
+func (q *querier) GetConnectionLogsOffset(ctx context.Context, arg database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	// Like audit logs, connection logs are only checked against the global
+	// permission once, as there can be a large number of them.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceConnectionLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetConnectionLogsOffset(ctx, arg)
+}
+
+func (q *querier) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (database.CustomRole, error) {
+	return fetch(q.log, q.auth, q.db.GetCustomRoleByID)(ctx, id)
+}
+
+func (q *querier) GetCustomRoles(ctx context.Context) ([]database.CustomRole, error) {
+	roles, err := q.db.GetCustomRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return q.filterCustomRoles(ctx, roles)
+}
+
+func (q *querier) GetCustomRolesByName(ctx context.Context, lookupRoles []string) ([]database.CustomRole, error) {
+	roles, err := q.db.GetCustomRolesByName(ctx, lookupRoles)
+	if err != nil {
+		return nil, err
+	}
+	return q.filterCustomRoles(ctx, roles)
+}
+
+// filterCustomRoles returns the roles the actor can read. Site and
+// organization roles are different types of objects, and rbac.Filter only
+// filters objects of a single type.
+func (q *querier) filterCustomRoles(ctx context.Context, roles []database.CustomRole) ([]database.CustomRole, error) {
+	act, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, NoActorError
+	}
+	var siteRoles, orgRoles []database.CustomRole
+	for _, role := range roles {
+		if role.OrganizationID.Valid {
+			orgRoles = append(orgRoles, role)
+		} else {
+			siteRoles = append(siteRoles, role)
+		}
+	}
+	siteRoles, err := rbac.Filter(ctx, q.auth, act, rbac.ActionRead, siteRoles)
+	if err != nil {
+		return nil, err
+	}
+	orgRoles, err = rbac.Filter(ctx, q.auth, act, rbac.ActionRead, orgRoles)
+	if err != nil {
+		return nil, err
+	}
+	readable := make(map[uuid.UUID]struct{}, len(siteRoles)+len(orgRoles))
+	for _, role := range append(siteRoles, orgRoles...) {
+		readable[role.ID] = struct{}{}
+	}
+	filtered := make([]database.CustomRole, 0, len(readable))
+	for _, role := range roles {
+		if _, ok := readable[role.ID]; ok {
+			filtered = append(filtered, role)
+		}
+	}
+	return filtered, nil
+}
+
 func (q *querier) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -1009,6 +1354,88 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetNotificationMessageByID(ctx context.Context, id uuid.UUID) (database.NotificationMessage, error) {
+	return fetch(q.log, q.auth, q.db.GetNotificationMessageByID)(ctx, id)
+}
+
+func (q *querier) GetNotificationMessagesByUserID(ctx context.Context, arg database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(arg.UserID.String())); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationMessagesByUserID(ctx, arg)
+}
+
+func (q *querier) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationPreferencesByUserID(ctx, userID)
+}
+
+func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByHashedSecret)(ctx, hashedSecret)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByHashedSecret)(ctx, hashedSecret)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	// Listing secrets is an error rather than an empty list for users who
+	// cannot read them.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppSecret); err != nil {
+		return nil, err
+	}
+	return q.db.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
+}
+
+func (q *querier) GetOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	token, err := q.db.GetOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	if err := q.authorizeOAuth2ProviderAppToken(ctx, rbac.ActionRead, token.APIKeyID); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return token, nil
+}
+
+func (q *querier) GetOAuth2ProviderAppTokenByRefreshHash(ctx context.Context, refreshHash []byte) (database.OAuth2ProviderAppToken, error) {
+	token, err := q.db.GetOAuth2ProviderAppTokenByRefreshHash(ctx, refreshHash)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	if err := q.authorizeOAuth2ProviderAppToken(ctx, rbac.ActionRead, token.APIKeyID); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return token, nil
+}
+
+func (q *querier) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderApps(ctx)
+	})(ctx, nil)
+}
+
+func (q *querier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return fetchWithPostFilter(q.auth, q.db.GetOAuth2ProviderAppsByUserID)(ctx, userID)
+}
+
 func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1372,6 +1799,30 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	// An actor can read template version presets if they can read the related template.
+	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var object rbac.Objecter
+	template, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		object = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
+	} else {
+		object = tv.RBACObject(template)
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, object); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionPresets(ctx, templateVersionID)
+}
+
 func (q *querier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
 	if err != nil {
@@ -1524,6 +1975,24 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	return fetch(q.log, q.auth, q.db.GetWebhookByID)(ctx, id)
+}
+
+func (q *querier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	// Deliveries are part of the webhook, so reading the webhook is enough.
+	if _, err := q.GetWebhookByID(ctx, arg.WebhookID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhookDeliveriesByWebhookID(ctx, arg)
+}
+
+func (q *querier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.Webhook, error) {
+		return q.db.GetWebhooks(ctx)
+	})(ctx, nil)
+}
+
 func (q *querier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	// This is a system function
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
@@ -1563,6 +2032,20 @@ func (q *querier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uu
 	return q.db.GetWorkspaceAgentLifecycleStateByID(ctx, id)
 }
 
+func (q *querier) GetWorkspaceAgentLogFilesByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentLogFile, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentLogFilesByAgentID(ctx, workspaceAgentID)
+}
+
+func (q *querier) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentLogsAfterParams) ([]database.WorkspaceAgentLog, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, arg.AgentID)
 	if err != nil {
@@ -1585,6 +2068,77 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentI
 	return q.db.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
 }
 
+func (q *querier) GetWorkspaceAgentPTYInvite(ctx context.Context, arg database.GetWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceAgentPTYInvite{}, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return database.WorkspaceAgentPTYInvite{}, err
+	}
+
+	return q.db.GetWorkspaceAgentPTYInvite(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentPTYInvitesBySessionID(ctx context.Context, arg database.GetWorkspaceAgentPTYInvitesBySessionIDParams) ([]database.WorkspaceAgentPTYInvite, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentPTYInvitesBySessionID(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentPTYInvitesByUserID(ctx context.Context, userID uuid.UUID) ([]database.WorkspaceAgentPTYInvite, error) {
+	// Invited users can't read the workspaces they are invited to, so the
+	// invites are checked against the user instead.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID)); err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentPTYInvitesByUserID(ctx, userID)
+}
+
+func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	// Reading a port share is reading the workspace.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	return q.db.GetWorkspaceAgentPortShare(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentScriptByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgentScript, error) {
+	script, err := q.db.GetWorkspaceAgentScriptByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	// This verifies the caller can read the agent the script belongs to.
+	_, err = q.GetWorkspaceAgentByID(ctx, script.WorkspaceAgentID)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+	return script, nil
+}
+
+func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentStats(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	return q.db.GetWorkspaceAgentStats(ctx, createdAfter)
 }
@@ -1725,6 +2279,48 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspacePrebuildPoolByID(ctx context.Context, id uuid.UUID) (database.WorkspacePrebuildPool, error) {
+	pool, err := q.db.GetWorkspacePrebuildPoolByID(ctx, id)
+	if err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, pool.TemplateID)
+	if err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, template); err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+	return pool, nil
+}
+
+func (q *querier) GetWorkspacePrebuildPools(ctx context.Context) ([]database.WorkspacePrebuildPool, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePrebuildPools(ctx)
+}
+
+func (q *querier) GetWorkspacePrebuildPoolsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspacePrebuildPool, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePrebuildPoolsByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetWorkspacePrebuilds(ctx context.Context) ([]database.WorkspacePrebuild, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePrebuilds(ctx)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -1839,6 +2435,46 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	recording, err := q.db.GetWorkspaceSessionRecordingByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	workspace, err := q.db.GetWorkspaceByID(ctx, recording.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace.SessionRecordingRBAC()); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	return recording, nil
+}
+
+func (q *querier) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	// Reading the chunks is reading the recording.
+	if _, err := q.GetWorkspaceSessionRecordingByID(ctx, recordingID); err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceSessionRecordingChunks(ctx, recordingID)
+}
+
+func (q *querier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace.SessionRecordingRBAC()); err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	prep, err := prepareSQLFilter(ctx, q.auth, rbac.ActionRead, rbac.ResourceWorkspace.Type)
 	if err != nil {
@@ -1847,6 +2483,13 @@ func (q *querier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesP
 	return q.db.GetAuthorizedWorkspaces(ctx, arg, prep)
 }
 
+func (q *querier) GetWorkspacesApproachingAutostop(ctx context.Context, arg database.GetWorkspacesApproachingAutostopParams) ([]database.GetWorkspacesApproachingAutostopRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacesApproachingAutostop(ctx, arg)
+}
+
 func (q *querier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	return q.db.GetWorkspacesEligibleForTransition(ctx, now)
 }
@@ -1866,6 +2509,20 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	obj := rbac.ResourceRoleAssignment
+	if arg.OrganizationID.Valid {
+		obj = rbac.ResourceOrgRoleAssignment.InOrg(arg.OrganizationID.UUID)
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, obj); err != nil {
+		return database.CustomRole{}, err
+	}
+	if err := q.customRoleEscalationCheck(ctx, arg.OrganizationID, arg.SitePermissions, arg.OrgPermissions, arg.UserPermissions); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.InsertCustomRole(ctx, arg)
+}
+
 func (q *querier) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -1924,6 +2581,33 @@ func (q *querier) InsertMissingGroups(ctx context.Context, arg database.InsertMi
 	return q.db.InsertMissingGroups(ctx, arg)
 }
 
+func (q *querier) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	// Notifications are sent by the system, users only read them.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.NotificationMessage{}, err
+	}
+	return q.db.InsertNotificationMessage(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderApp, q.db.InsertOAuth2ProviderApp)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String()), q.db.InsertOAuth2ProviderAppCode)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppSecret, q.db.InsertOAuth2ProviderAppSecret)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	if err := q.authorizeOAuth2ProviderAppToken(ctx, rbac.ActionCreate, arg.APIKeyID); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return q.db.InsertOAuth2ProviderAppToken(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -2009,6 +2693,13 @@ func (q *querier) InsertTemplateVersionParameter(ctx context.Context, arg databa
 	return q.db.InsertTemplateVersionParameter(ctx, arg)
 }
 
+func (q *querier) InsertTemplateVersionPreset(ctx context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+	return q.db.InsertTemplateVersionPreset(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -2045,6 +2736,17 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	return q.db.InsertUserLink(ctx, arg)
 }
 
+func (q *querier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	return insert(q.log, q.auth, rbac.ResourceWebhook, q.db.InsertWebhook)(ctx, arg)
+}
+
+func (q *querier) InsertWebhookDeliveries(ctx context.Context, arg database.InsertWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertWebhookDeliveries(ctx, arg)
+}
+
 func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID)
 	return insert(q.log, q.auth, obj, q.db.InsertWorkspace)(ctx, arg)
@@ -2059,6 +2761,24 @@ func (q *querier) InsertWorkspaceAgent(ctx context.Context, arg database.InsertW
 	return q.db.InsertWorkspaceAgent(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentLogFiles(ctx context.Context, arg database.InsertWorkspaceAgentLogFilesParams) ([]database.WorkspaceAgentLogFile, error) {
+	// We don't check for workspace ownership here since the log files may be
+	// associated with an orphaned agent used by a dry run build.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertWorkspaceAgentLogFiles(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	// We don't check for workspace ownership here since the log sources may
+	// be associated with an orphaned agent used by a dry run build.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertWorkspaceAgentLogSources(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentLogs(ctx context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	return q.db.InsertWorkspaceAgentLogs(ctx, arg)
 }
@@ -2073,6 +2793,15 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	// We don't check for workspace ownership here since the scripts may be
+	// associated with an orphaned agent used by a dry run build.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertWorkspaceAgentScripts(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentStat(ctx context.Context, arg database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	// TODO: This is a workspace agent operation. Should users be able to query this?
 	// Not really sure what this is for.
@@ -2147,6 +2876,26 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspacePrebuild(ctx context.Context, arg database.InsertWorkspacePrebuildParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspacePrebuild(ctx, arg)
+}
+
+func (q *querier) InsertWorkspacePrebuildPool(ctx context.Context, arg database.InsertWorkspacePrebuildPoolParams) (database.WorkspacePrebuildPool, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+
+	// Pools are part of the template.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+	return q.db.InsertWorkspacePrebuildPool(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	return insert(q.log, q.auth, rbac.ResourceWorkspaceProxy, q.db.InsertWorkspaceProxy)(ctx, arg)
 }
@@ -2165,6 +2914,48 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+// Recordings are only ever written by agents.
+func (q *querier) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	return q.db.InsertWorkspaceSessionRecording(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+}
+
+func (q *querier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Listing port shares is reading the workspace.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+
+	return q.db.ListWorkspaceAgentPortShares(ctx, workspaceID)
+}
+
+func (q *querier) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return err
+	}
+
+	return q.db.ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx, templateID)
+}
+
 func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2190,6 +2981,20 @@ func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKe
 	return update(q.log, q.auth, fetch, q.db.UpdateAPIKeyByID)(ctx, arg)
 }
 
+func (q *querier) UpdateCustomRoleByID(ctx context.Context, arg database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+	role, err := q.db.GetCustomRoleByID(ctx, arg.ID)
+	if err != nil {
+		return database.CustomRole{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, role); err != nil {
+		return database.CustomRole{}, err
+	}
+	if err := q.customRoleEscalationCheck(ctx, role.OrganizationID, arg.SitePermissions, arg.OrgPermissions, arg.UserPermissions); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.UpdateCustomRoleByID(ctx, arg)
+}
+
 func (q *querier) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 		return q.db.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -2239,6 +3044,41 @@ func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemb
 	return q.db.UpdateMemberRoles(ctx, arg)
 }
 
+func (q *querier) UpdateNotificationMessageEmailByID(ctx context.Context, arg database.UpdateNotificationMessageEmailByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateNotificationMessageEmailByID(ctx, arg)
+}
+
+func (q *querier) UpdateNotificationMessageReadAtByID(ctx context.Context, arg database.UpdateNotificationMessageReadAtByIDParams) (database.NotificationMessage, error) {
+	fetch := func(ctx context.Context, arg database.UpdateNotificationMessageReadAtByIDParams) (database.NotificationMessage, error) {
+		return q.db.GetNotificationMessageByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateNotificationMessageReadAtByID)(ctx, arg)
+}
+
+func (q *querier) UpdateNotificationMessagesReadAtByUserID(ctx context.Context, arg database.UpdateNotificationMessagesReadAtByUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.UpdateNotificationMessagesReadAtByUserID(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderAppByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppByID)(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+		return q.db.GetOAuth2ProviderAppSecretByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppSecretByID)(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -2547,6 +3387,20 @@ func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserS
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserStatus)(ctx, arg)
 }
 
+func (q *querier) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+		return q.db.GetWebhookByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWebhookByID)(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWebhookDeliveryByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -2554,6 +3408,19 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentAutostopInhibitorsByID(ctx context.Context, arg database.UpdateWorkspaceAgentAutostopInhibitorsByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentAutostopInhibitorsByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2606,6 +3473,25 @@ func (q *querier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentScriptStatusByID(ctx context.Context, arg database.UpdateWorkspaceAgentScriptStatusByIDParams) error {
+	script, err := q.db.GetWorkspaceAgentScriptByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, script.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentScriptStatusByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	agent, err := q.db.GetWorkspaceAgentByID(ctx, arg.ID)
 	if err != nil {
@@ -2624,6 +3510,19 @@ func (q *querier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg datab
 	return q.db.UpdateWorkspaceAgentStartupByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentUpdateStateByID(ctx context.Context, arg database.UpdateWorkspaceAgentUpdateStateByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentUpdateStateByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg database.UpdateWorkspaceAppHealthByIDParams) error {
 	// TODO: This is a workspace agent operation. Should users be able to query this?
 	workspace, err := q.db.GetWorkspaceByWorkspaceAppID(ctx, arg.ID)
@@ -2638,6 +3537,13 @@ func (q *querier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAppHealthByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceAutomaticUpdates)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAutostart(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -2695,6 +3601,22 @@ func (q *querier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.Up
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceLastUsedAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspacePrebuildPoolByID(ctx context.Context, arg database.UpdateWorkspacePrebuildPoolByIDParams) (database.WorkspacePrebuildPool, error) {
+	pool, err := q.db.GetWorkspacePrebuildPoolByID(ctx, arg.ID)
+	if err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, pool.TemplateID)
+	if err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+	return q.db.UpdateWorkspacePrebuildPoolByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2709,6 +3631,13 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.UpdateWorkspaceSessionRecordingByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -2729,6 +3658,13 @@ func (q *querier) UpsertAppSecurityKey(ctx context.Context, data string) error {
 	return q.db.UpsertAppSecurityKey(ctx, data)
 }
 
+func (q *querier) UpsertConnectionLog(ctx context.Context, arg database.UpsertConnectionLogParams) (database.ConnectionLog, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.ConnectionLog{}, err
+	}
+	return q.db.UpsertConnectionLog(ctx, arg)
+}
+
 func (q *querier) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2750,6 +3686,13 @@ func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	return q.db.UpsertLogoURL(ctx, value)
 }
 
+func (q *querier) UpsertNotificationPreference(ctx context.Context, arg database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String())); err != nil {
+		return database.NotificationPreference{}, err
+	}
+	return q.db.UpsertNotificationPreference(ctx, arg)
+}
+
 func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2785,6 +3728,34 @@ func (q *querier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (d
 	return q.db.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (q *querier) UpsertWorkspaceAgentPTYInvite(ctx context.Context, arg database.UpsertWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAgentPTYInvite{}, err
+	}
+
+	// Inviting users to a terminal requires being able to open one.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, workspace.ExecutionRBAC()); err != nil {
+		return database.WorkspaceAgentPTYInvite{}, err
+	}
+
+	return q.db.UpsertWorkspaceAgentPTYInvite(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	// Sharing a port is updating the workspace.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	return q.db.UpsertWorkspaceAgentPortShare(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
@@ -164,7 +164,7 @@ func must[T any](value T, err error) T {
 func (s *MethodTestSuite) TestAPIKey() {
 	s.Run("DeleteAPIKeyByID", s.Subtest(func(db database.Store, check *expects) {
 		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{})
-		check.Args(key.ID).Asserts(key, rbac.ActionDelete).Returns()
+		check.Args(key.ID).Asserts(key, rbac.ActionDelete).Returns(int64(1))
 	}))
 	s.Run("GetAPIKeyByID", s.Subtest(func(db database.Store, check *expects) {
 		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{})
@@ -240,6 +240,25 @@ func (s *MethodTestSuite) TestAuditLogs() {
 	}))
 }
 
+func (s *MethodTestSuite) TestConnectionLogs() {
+	s.Run("UpsertConnectionLog", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertConnectionLogParams{
+			ID:   uuid.New(),
+			Type: database.ConnectionTypeSSH,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetConnectionLogsOffset", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.ConnectionLog(s.T(), db, database.ConnectionLog{})
+		_ = dbgen.ConnectionLog(s.T(), db, database.ConnectionLog{})
+		check.Args(database.GetConnectionLogsOffsetParams{
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourceConnectionLog, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldConnectionLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+}
+
 func (s *MethodTestSuite) TestFile() {
 	s.Run("GetFileByHashAndCreator", s.Subtest(func(db database.Store, check *expects) {
 		f := dbgen.File(s.T(), db, database.File{})
@@ -591,6 +610,66 @@ func (s *MethodTestSuite) TestOrganization() {
 	}))
 }
 
+func (s *MethodTestSuite) TestCustomRoles() {
+	s.Run("InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertCustomRoleParams{
+			ID:              uuid.New(),
+			Name:            "support",
+			SitePermissions: json.RawMessage(`[{"negate":false,"resource_type":"workspace","action":"read"}]`),
+			OrgPermissions:  json.RawMessage("[]"),
+			UserPermissions: json.RawMessage("[]"),
+		}).Asserts(
+			rbac.ResourceRoleAssignment, rbac.ActionCreate,
+			// The actor must have every permission the role grants.
+			rbac.ResourceWorkspace, rbac.ActionRead,
+		)
+	}))
+	s.Run("Org/InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.InsertCustomRoleParams{
+			ID:              uuid.New(),
+			Name:            "template-author",
+			OrganizationID:  uuid.NullUUID{UUID: o.ID, Valid: true},
+			SitePermissions: json.RawMessage("[]"),
+			OrgPermissions:  json.RawMessage(`[{"negate":false,"resource_type":"template","action":"update"}]`),
+			UserPermissions: json.RawMessage("[]"),
+		}).Asserts(
+			rbac.ResourceOrgRoleAssignment.InOrg(o.ID), rbac.ActionCreate,
+			rbac.ResourceTemplate.InOrg(o.ID), rbac.ActionUpdate,
+		)
+	}))
+	s.Run("GetCustomRoleByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(r.ID).Asserts(r, rbac.ActionRead).Returns(r)
+	}))
+	s.Run("GetCustomRoles", s.Subtest(func(db database.Store, check *expects) {
+		r1 := dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "a"})
+		r2 := dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "b"})
+		check.Args().Asserts(r1, rbac.ActionRead, r2, rbac.ActionRead).Returns(slice.New(r1, r2))
+	}))
+	s.Run("GetCustomRolesByName", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args([]string{r.Name}).Asserts(r, rbac.ActionRead).Returns(slice.New(r))
+	}))
+	s.Run("UpdateCustomRoleByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(database.UpdateCustomRoleByIDParams{
+			ID: r.ID,
+			// Negated permissions never need to be checked.
+			SitePermissions: json.RawMessage(`[{"negate":true,"resource_type":"workspace","action":"read"},{"negate":false,"resource_type":"template","action":"read"}]`),
+			OrgPermissions:  json.RawMessage("[]"),
+			UserPermissions: json.RawMessage("[]"),
+		}).Asserts(
+			r, rbac.ActionUpdate,
+			rbac.ResourceTemplate, rbac.ActionRead,
+		)
+	}))
+	s.Run("DeleteCustomRoleByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(r.ID).Asserts(r, rbac.ActionDelete).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestWorkspaceProxy() {
 	s.Run("InsertWorkspaceProxy", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceProxyParams{
@@ -621,6 +700,313 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWebhook() {
+	s.Run("InsertWebhook", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebhookParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionCreate)
+	}))
+	s.Run("GetWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionRead).Returns(w)
+	}))
+	s.Run("GetWebhooks", s.Subtest(func(db database.Store, check *expects) {
+		w1 := dbgen.Webhook(s.T(), db, database.Webhook{Name: "a"})
+		w2 := dbgen.Webhook(s.T(), db, database.Webhook{Name: "b"})
+		check.Args().Asserts(w1, rbac.ActionRead, w2, rbac.ActionRead).Returns(slice.New(w1, w2))
+	}))
+	s.Run("UpdateWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.UpdateWebhookByIDParams{
+			ID:     w.ID,
+			Name:   w.Name,
+			Events: w.Events,
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionDelete).Returns()
+	}))
+	s.Run("GetWebhookDeliveriesByWebhookID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: w.ID,
+		}).Asserts(w, rbac.ActionRead).Returns([]database.WebhookDelivery{})
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderApps() {
+	s.Run("GetOAuth2ProviderApps", s.Subtest(func(db database.Store, check *expects) {
+		apps := []database.OAuth2ProviderApp{
+			dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "first"}),
+			dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "last"}),
+		}
+		check.Args().Asserts(apps[0], rbac.ActionRead, apps[1], rbac.ActionRead).Returns(apps)
+	}))
+	s.Run("GetOAuth2ProviderAppsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		check.Args(user.ID).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionRead, app, rbac.ActionRead).Returns(slice.New(app))
+	}))
+	s.Run("GetOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionRead).Returns(app)
+	}))
+	s.Run("InsertOAuth2ProviderApp", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertOAuth2ProviderAppParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		app.Name = "updated"
+		app.UpdatedAt = dbtime.Now()
+		check.Args(database.UpdateOAuth2ProviderAppByIDParams{
+			ID:           app.ID,
+			UpdatedAt:    app.UpdatedAt,
+			Name:         app.Name,
+			Icon:         app.Icon,
+			RedirectURIs: app.RedirectURIs,
+		}).Asserts(app, rbac.ActionUpdate).Returns(app)
+	}))
+	s.Run("DeleteOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppSecrets() {
+	s.Run("GetOAuth2ProviderAppSecretsByAppID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(app.ID).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionRead).Returns(slice.New(secret))
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.HashedSecret).Asserts(secret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("InsertOAuth2ProviderAppSecret", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppSecretParams{
+			ID:    uuid.New(),
+			AppID: app.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		secret.LastUsedAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+		check.Args(database.UpdateOAuth2ProviderAppSecretByIDParams{
+			ID:         secret.ID,
+			LastUsedAt: secret.LastUsedAt,
+		}).Asserts(secret, rbac.ActionUpdate).Returns(secret)
+	}))
+	s.Run("DeleteOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppCodes() {
+	s.Run("GetOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppCodeByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.HashedSecret).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("InsertOAuth2ProviderAppCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppCodeParams{
+			ID:     uuid.New(),
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionDelete).Returns(int64(1))
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodesByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		_ = dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
+	s.Run("InsertOAuth2ProviderAppToken", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppTokenParams{
+			ID:       uuid.New(),
+			AppID:    app.ID,
+			APIKeyID: key.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppTokenByAPIKeyID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		check.Args(key.ID).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionRead).Returns(token)
+	}))
+	s.Run("GetOAuth2ProviderAppTokenByRefreshHash", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		check.Args(token.RefreshHash).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionRead).Returns(token)
+	}))
+	s.Run("DeleteOAuth2ProviderAppTokensByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		check.Args(database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestNotifications() {
+	s.Run("GetNotificationMessageByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		m := dbgen.NotificationMessage(s.T(), db, database.NotificationMessage{UserID: u.ID})
+		check.Args(m.ID).Asserts(m, rbac.ActionRead).Returns(m)
+	}))
+	s.Run("GetNotificationMessagesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		m := dbgen.NotificationMessage(s.T(), db, database.NotificationMessage{UserID: u.ID})
+		check.Args(database.GetNotificationMessagesByUserIDParams{
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()), rbac.ActionRead).Returns(slice.New(m))
+	}))
+	s.Run("CountUnreadNotificationMessagesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.NotificationMessage(s.T(), db, database.NotificationMessage{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()), rbac.ActionRead).Returns(int64(1))
+	}))
+	s.Run("UpdateNotificationMessageReadAtByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		m := dbgen.NotificationMessage(s.T(), db, database.NotificationMessage{UserID: u.ID})
+		check.Args(database.UpdateNotificationMessageReadAtByIDParams{
+			ID:     m.ID,
+			ReadAt: dbtime.Now(),
+		}).Asserts(m, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateNotificationMessagesReadAtByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateNotificationMessagesReadAtByUserIDParams{
+			UserID: u.ID,
+			ReadAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()), rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetNotificationPreferencesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()), rbac.ActionRead).Returns([]database.NotificationPreference{})
+	}))
+	s.Run("UpsertNotificationPreference", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertNotificationPreferenceParams{
+			UserID:   u.ID,
+			Kind:     database.NotificationKindWorkspaceAutostop,
+			Disabled: true,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()), rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestPrebuilds() {
+	s.Run("InsertWorkspacePrebuildPool", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertWorkspacePrebuildPoolParams{
+			ID:         uuid.New(),
+			TemplateID: t1.ID,
+			Parameters: []byte("[]"),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("GetWorkspacePrebuildPoolByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.WorkspacePrebuildPool(s.T(), db, database.WorkspacePrebuildPool{TemplateID: t1.ID})
+		check.Args(p.ID).Asserts(t1, rbac.ActionRead).Returns(p)
+	}))
+	s.Run("GetWorkspacePrebuildPoolsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.WorkspacePrebuildPool(s.T(), db, database.WorkspacePrebuildPool{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionRead).Returns(slice.New(p))
+	}))
+	s.Run("GetWorkspacePrebuildPools", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.WorkspacePrebuildPool(s.T(), db, database.WorkspacePrebuildPool{TemplateID: t1.ID})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(slice.New(p))
+	}))
+	s.Run("UpdateWorkspacePrebuildPoolByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.WorkspacePrebuildPool(s.T(), db, database.WorkspacePrebuildPool{TemplateID: t1.ID})
+		check.Args(database.UpdateWorkspacePrebuildPoolByIDParams{
+			ID:               p.ID,
+			DesiredInstances: 3,
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspacePrebuildPoolByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.WorkspacePrebuildPool(s.T(), db, database.WorkspacePrebuildPool{TemplateID: t1.ID})
+		check.Args(p.ID).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspacePrebuilds", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		pb := dbgen.WorkspacePrebuild(s.T(), db, database.WorkspacePrebuild{WorkspaceID: ws.ID})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(slice.New(pb))
+	}))
+	s.Run("InsertWorkspacePrebuild", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspacePrebuildParams{
+			WorkspaceID: ws.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("DeleteWorkspacePrebuildByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		pb := dbgen.WorkspacePrebuild(s.T(), db, database.WorkspacePrebuild{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(rbac.ResourceSystem, rbac.ActionDelete).Returns(pb)
+	}))
+	s.Run("ClaimPrebuiltWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.ClaimPrebuiltWorkspaceParams{
+			ID:               ws.ID,
+			OwnerID:          u.ID,
+			Name:             "claimed",
+			AutomaticUpdates: database.AutomaticUpdatesNever,
+		}).Asserts(
+			ws, rbac.ActionUpdate,
+			rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(ws.OrganizationID), rbac.ActionCreate,
+		)
+	}))
+}
+
 func (s *MethodTestSuite) TestTemplate() {
 	s.Run("GetPreviousTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
 		tvid := uuid.New()
@@ -687,6 +1073,13 @@ func (s *MethodTestSuite) TestTemplate() {
 		})
 		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionParameter{})
 	}))
+	s.Run("GetTemplateVersionPresets", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionPreset{})
+	}))
 	s.Run("GetTemplateVersionVariables", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
@@ -798,9 +1191,24 @@ func (s *MethodTestSuite) TestTemplate() {
 	s.Run("UpdateTemplateMetaByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(database.UpdateTemplateMetaByIDParams{
-			ID: t1.ID,
+			ID:                  t1.ID,
+			MaxPortSharingLevel: "owner",
 		}).Asserts(t1, rbac.ActionUpdate)
 	}))
+	s.Run("DeleteWorkspaceAgentPortSharesByTemplate", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID, TemplateID: t1.ID})
+		_ = dbgen.WorkspaceAgentPortShare(s.T(), db, database.WorkspaceAgentPortShare{WorkspaceID: ws.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID, TemplateID: t1.ID})
+		_ = dbgen.WorkspaceAgentPortShare(s.T(), db, database.WorkspaceAgentPortShare{WorkspaceID: ws.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateTemplateVersionByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
@@ -1039,6 +1447,16 @@ func (s *MethodTestSuite) TestWorkspace() {
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
 		check.Args(agt.AuthInstanceID.String).Asserts(ws, rbac.ActionRead).Returns(agt)
 	}))
+	s.Run("UpdateWorkspaceAgentAutostopInhibitorsByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentAutostopInhibitorsByIDParams{
+			ID:                 agt.ID,
+			AutostopInhibitors: database.AutostopInhibitors{{ID: uuid.New(), Reason: "make"}},
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAgentLifecycleStateByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1049,6 +1467,51 @@ func (s *MethodTestSuite) TestWorkspace() {
 			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("GetWorkspaceAgentScriptByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		scripts, err := db.InsertWorkspaceAgentScripts(context.Background(), database.InsertWorkspaceAgentScriptsParams{
+			WorkspaceAgentID: agt.ID,
+			ID:               []uuid.UUID{uuid.New()},
+			LogSourceID:      []uuid.UUID{uuid.New()},
+			LogPath:          []string{""},
+			Script:           []string{"echo hello"},
+			Cron:             []string{""},
+			StartBlocksLogin: []bool{false},
+			RunOnStart:       []bool{true},
+			RunOnStop:        []bool{false},
+			TimeoutSeconds:   []int32{0},
+			DisplayOrder:     []int32{0},
+		})
+		require.NoError(s.T(), err)
+		check.Args(scripts[0].ID).Asserts(ws, rbac.ActionRead).Returns(scripts[0])
+	}))
+	s.Run("UpdateWorkspaceAgentScriptStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		scripts, err := db.InsertWorkspaceAgentScripts(context.Background(), database.InsertWorkspaceAgentScriptsParams{
+			WorkspaceAgentID: agt.ID,
+			ID:               []uuid.UUID{uuid.New()},
+			LogSourceID:      []uuid.UUID{uuid.New()},
+			LogPath:          []string{""},
+			Script:           []string{"echo hello"},
+			Cron:             []string{""},
+			StartBlocksLogin: []bool{false},
+			RunOnStart:       []bool{true},
+			RunOnStop:        []bool{false},
+			TimeoutSeconds:   []int32{0},
+			DisplayOrder:     []int32{0},
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceAgentScriptStatusByIDParams{
+			ID:     scripts[0].ID,
+			Status: database.WorkspaceAgentScriptStatusOk,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAgentLogOverflowByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1071,6 +1534,16 @@ func (s *MethodTestSuite) TestWorkspace() {
 			},
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAgentUpdateStateByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentUpdateStateByIDParams{
+			ID:          agt.ID,
+			UpdateState: database.WorkspaceAgentUpdateStateUpdating,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetWorkspaceAgentLogsAfter", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1171,9 +1644,10 @@ func (s *MethodTestSuite) TestWorkspace() {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		check.Args(database.InsertWorkspaceParams{
-			ID:             uuid.New(),
-			OwnerID:        u.ID,
-			OrganizationID: o.ID,
+			ID:               uuid.New(),
+			OwnerID:          u.ID,
+			OrganizationID:   o.ID,
+			AutomaticUpdates: database.AutomaticUpdatesNever,
 		}).Asserts(rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(o.ID), rbac.ActionCreate)
 	}))
 	s.Run("Start/InsertWorkspaceBuild", s.Subtest(func(db database.Store, check *expects) {
@@ -1226,6 +1700,13 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Health: database.WorkspaceAppHealthDisabled,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAutomaticUpdates", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceAutomaticUpdatesParams{
+			ID:               ws.ID,
+			AutomaticUpdates: database.AutomaticUpdatesAlways,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAutostart", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.UpdateWorkspaceAutostartParams{
@@ -1266,6 +1747,119 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: ws.ID,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("GetWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		ps := dbgen.WorkspaceAgentPortShare(s.T(), db, database.WorkspaceAgentPortShare{WorkspaceID: ws.ID})
+		check.Args(database.GetWorkspaceAgentPortShareParams{
+			WorkspaceID: ps.WorkspaceID,
+			AgentName:   ps.AgentName,
+			Port:        ps.Port,
+		}).Asserts(ws, rbac.ActionRead).Returns(ps)
+	}))
+	s.Run("ListWorkspaceAgentPortShares", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		ps := dbgen.WorkspaceAgentPortShare(s.T(), db, database.WorkspaceAgentPortShare{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentPortShare{ps})
+	}))
+	s.Run("UpsertWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		ps := dbgen.WorkspaceAgentPortShare(s.T(), db, database.WorkspaceAgentPortShare{WorkspaceID: ws.ID})
+		check.Args(database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID: ps.WorkspaceID,
+			AgentName:   ps.AgentName,
+			Port:        ps.Port,
+			ShareLevel:  ps.ShareLevel,
+		}).Asserts(ws, rbac.ActionUpdate).Returns(ps)
+	}))
+	s.Run("DeleteWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		ps := dbgen.WorkspaceAgentPortShare(s.T(), db, database.WorkspaceAgentPortShare{WorkspaceID: ws.ID})
+		check.Args(database.DeleteWorkspaceAgentPortShareParams{
+			WorkspaceID: ps.WorkspaceID,
+			AgentName:   ps.AgentName,
+			Port:        ps.Port,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceAgentPTYInvite", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		invite := dbgen.WorkspaceAgentPTYInvite(s.T(), db, database.WorkspaceAgentPTYInvite{WorkspaceID: ws.ID, AgentID: agt.ID})
+		check.Args(database.GetWorkspaceAgentPTYInviteParams{
+			AgentID:   invite.AgentID,
+			SessionID: invite.SessionID,
+			UserID:    invite.UserID,
+		}).Asserts(ws, rbac.ActionRead).Returns(invite)
+	}))
+	s.Run("GetWorkspaceAgentPTYInvitesBySessionID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		invite := dbgen.WorkspaceAgentPTYInvite(s.T(), db, database.WorkspaceAgentPTYInvite{WorkspaceID: ws.ID, AgentID: agt.ID})
+		check.Args(database.GetWorkspaceAgentPTYInvitesBySessionIDParams{
+			AgentID:   invite.AgentID,
+			SessionID: invite.SessionID,
+		}).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentPTYInvite{invite})
+	}))
+	s.Run("GetWorkspaceAgentPTYInvitesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		invite := dbgen.WorkspaceAgentPTYInvite(s.T(), db, database.WorkspaceAgentPTYInvite{UserID: u.ID})
+		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns([]database.WorkspaceAgentPTYInvite{invite})
+	}))
+	s.Run("UpsertWorkspaceAgentPTYInvite", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		invite := dbgen.WorkspaceAgentPTYInvite(s.T(), db, database.WorkspaceAgentPTYInvite{WorkspaceID: ws.ID, AgentID: agt.ID})
+		check.Args(database.UpsertWorkspaceAgentPTYInviteParams{
+			WorkspaceID: invite.WorkspaceID,
+			AgentID:     invite.AgentID,
+			SessionID:   invite.SessionID,
+			UserID:      invite.UserID,
+			ReadOnly:    invite.ReadOnly,
+			CreatedBy:   invite.CreatedBy,
+			CreatedAt:   invite.CreatedAt,
+		}).Asserts(ws.ExecutionRBAC(), rbac.ActionCreate).Returns(invite)
+	}))
+	s.Run("DeleteWorkspaceAgentPTYInvite", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		invite := dbgen.WorkspaceAgentPTYInvite(s.T(), db, database.WorkspaceAgentPTYInvite{WorkspaceID: ws.ID, AgentID: agt.ID})
+		check.Args(database.DeleteWorkspaceAgentPTYInviteParams{
+			AgentID:   invite.AgentID,
+			SessionID: invite.SessionID,
+			UserID:    invite.UserID,
+		}).Asserts(ws.ExecutionRBAC(), rbac.ActionCreate).Returns()
+	}))
+	s.Run("GetWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		check.Args(rec.ID).Asserts(ws.SessionRecordingRBAC(), rbac.ActionRead).Returns(rec)
+	}))
+	s.Run("GetWorkspaceSessionRecordingsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws.SessionRecordingRBAC(), rbac.ActionRead).Returns([]database.WorkspaceSessionRecording{rec})
+	}))
+	s.Run("GetWorkspaceSessionRecordingChunks", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{WorkspaceID: ws.ID})
+		chunk := dbgen.WorkspaceSessionRecordingChunk(s.T(), db, database.WorkspaceSessionRecordingChunk{RecordingID: rec.ID})
+		check.Args(rec.ID).Asserts(ws.SessionRecordingRBAC(), rbac.ActionRead).Returns([]database.WorkspaceSessionRecordingChunk{chunk})
+	}))
 	s.Run("GetWorkspaceByWorkspaceAppID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1304,6 +1898,26 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("UpsertDefaultProxy", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.UpsertDefaultProxyParams{}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("InsertWorkspaceSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceSessionRecordingParams{
+			ID:   uuid.New(),
+			Type: database.WorkspaceSessionRecordingTypeSSH,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("InsertWorkspaceSessionRecordingChunk", s.Subtest(func(db database.Store, check *expects) {
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{})
+		check.Args(database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: rec.ID,
+			Data:        []byte("[0.1,\"o\",\"hi\"]\n"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate).Returns(int64(1))
+	}))
+	s.Run("UpdateWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		rec := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{})
+		check.Args(database.UpdateWorkspaceSessionRecordingByIDParams{
+			ID:        rec.ID,
+			AddedSize: 10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns(int64(1))
+	}))
 	s.Run("GetUserLinkByLinkedID", s.Subtest(func(db database.Store, check *expects) {
 		l := dbgen.UserLink(s.T(), db, database.UserLink{})
 		check.Args(l.LinkedID).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(l)
@@ -1491,6 +2105,39 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			StartupScriptBehavior: database.StartupScriptBehaviorNonBlocking,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAgentLogSources", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentLogSourcesParams{
+			WorkspaceAgentID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("InsertWorkspaceAgentScripts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentScriptsParams{
+			WorkspaceAgentID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAgentLogSourcesByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentScriptsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("InsertWorkspaceAgentLogFiles", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentLogFilesParams{
+			WorkspaceAgentID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAgentLogFilesByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldWorkspaceAgentLogsBySourceID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldWorkspaceAgentLogsBySourceIDParams{
+			AgentID:     uuid.New(),
+			LogSourceID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("InsertWorkspaceApp", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAppParams{
 			ID:           uuid.New(),
@@ -1563,6 +2210,15 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			TemplateVersionID: v.ID,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertTemplateVersionPreset", s.Subtest(func(db database.Store, check *expects) {
+		v := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{})
+		check.Args(database.InsertTemplateVersionPresetParams{
+			ID:                uuid.New(),
+			TemplateVersionID: v.ID,
+			Name:              "small",
+			Parameters:        []byte("[]"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
 	s.Run("InsertWorkspaceResource", s.Subtest(func(db database.Store, check *expects) {
 		r := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{})
 		check.Args(database.InsertWorkspaceResourceParams{
@@ -1570,4 +2226,64 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Transition: database.WorkspaceTransitionStart,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebhookDeliveriesParams{
+			Event:   database.WebhookEventUserCreated,
+			Payload: json.RawMessage("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireWebhookDelivery", s.Subtest(func(db database.Store, check *expects) {
+		dbgen.Webhook(s.T(), db, database.Webhook{Enabled: true})
+		_, err := db.InsertWebhookDeliveries(context.Background(), database.InsertWebhookDeliveriesParams{
+			Event:     database.WebhookEventUserCreated,
+			Payload:   json.RawMessage("{}"),
+			CreatedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.AcquireWebhookDeliveryParams{
+			Now:            dbtime.Now(),
+			LeaseExpiresAt: dbtime.Now().Add(time.Minute),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWebhookDeliveryByID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateWebhookDeliveryByIDParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOldWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("InsertNotificationMessage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertNotificationMessageParams{
+			ID:     uuid.New(),
+			UserID: u.ID,
+			Kind:   database.NotificationKindWorkspaceDormant,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireNotificationMessageEmail", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.NotificationMessage(s.T(), db, database.NotificationMessage{
+			UserID:             u.ID,
+			EmailNextAttemptAt: sql.NullTime{Valid: true},
+		})
+		check.Args(database.AcquireNotificationMessageEmailParams{
+			Now:            dbtime.Now(),
+			LeaseExpiresAt: dbtime.Now().Add(time.Minute),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateNotificationMessageEmailByID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateNotificationMessageEmailByIDParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOldNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("GetWorkspacesApproachingAutostop", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspacesApproachingAutostopParams{
+			Now:        dbtime.Now(),
+			StopBefore: dbtime.Now().Add(time.Hour),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 }
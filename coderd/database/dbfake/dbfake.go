@@ -1,6 +1,7 @@
 package dbfake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -123,6 +124,8 @@ type data struct {
 	// New tables
 	workspaceAgentStats           []database.WorkspaceAgentStat
 	auditLogs                     []database.AuditLog
+	connectionLogs                []database.ConnectionLog
+	customRoles                   []database.CustomRole
 	dbcryptKeys                   []database.DBCryptKey
 	files                         []database.File
 	gitAuthLinks                  []database.GitAuthLink
@@ -130,6 +133,12 @@ type data struct {
 	groupMembers                  []database.GroupMember
 	groups                        []database.Group
 	licenses                      []database.License
+	notificationMessages          []database.NotificationMessage
+	notificationPreferences       []database.NotificationPreference
+	oauth2ProviderApps            []database.OAuth2ProviderApp
+	oauth2ProviderAppCodes        []database.OAuth2ProviderAppCode
+	oauth2ProviderAppSecrets      []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppTokens       []database.OAuth2ProviderAppToken
 	parameterSchemas              []database.ParameterSchema
 	provisionerDaemons            []database.ProvisionerDaemon
 	provisionerJobLogs            []database.ProvisionerJobLog
@@ -137,16 +146,28 @@ type data struct {
 	replicas                      []database.Replica
 	templateVersions              []database.TemplateVersionTable
 	templateVersionParameters     []database.TemplateVersionParameter
+	templateVersionPresets        []database.TemplateVersionPreset
 	templateVersionVariables      []database.TemplateVersionVariable
 	templates                     []database.TemplateTable
+	webhookDeliveries             []database.WebhookDelivery
+	webhooks                      []database.Webhook
 	workspaceAgents               []database.WorkspaceAgent
 	workspaceAgentMetadata        []database.WorkspaceAgentMetadatum
 	workspaceAgentLogs            []database.WorkspaceAgentLog
+	workspaceAgentLogFiles        []database.WorkspaceAgentLogFile
+	workspaceAgentLogSources      []database.WorkspaceAgentLogSource
+	workspaceAgentPortShares      []database.WorkspaceAgentPortShare
+	workspaceAgentPTYInvites      []database.WorkspaceAgentPTYInvite
+	workspaceAgentScripts         []database.WorkspaceAgentScript
+	workspaceSessionRecordings    []database.WorkspaceSessionRecording
+	workspaceSessionRecordingData []database.WorkspaceSessionRecordingChunk
 	workspaceApps                 []database.WorkspaceApp
 	workspaceAppStatsLastInsertID int64
 	workspaceAppStats             []database.WorkspaceAppStat
 	workspaceBuilds               []database.WorkspaceBuildTable
 	workspaceBuildParameters      []database.WorkspaceBuildParameter
+	workspacePrebuildPools        []database.WorkspacePrebuildPool
+	workspacePrebuilds            []database.WorkspacePrebuild
 	workspaceResourceMetadata     []database.WorkspaceResourceMetadatum
 	workspaceResources            []database.WorkspaceResource
 	workspaces                    []database.Workspace
@@ -277,23 +298,52 @@ func (q *FakeQuerier) getUserByIDNoLock(id uuid.UUID) (database.User, error) {
 	return database.User{}, sql.ErrNoRows
 }
 
+// isServiceAccountNoLock returns whether the user is a service account.
+func (q *FakeQuerier) isServiceAccountNoLock(id uuid.UUID) bool {
+	user, err := q.getUserByIDNoLock(id)
+	return err == nil && user.IsServiceAccount
+}
+
+// sortConnectionLogsNoLock keeps the connection logs sorted by connect time,
+// newest first.
+// deleteOrphanedOAuth2ProviderAppTokensNoLock deletes the OAuth2 tokens whose
+// API key was deleted, like the foreign key does in the database.
+func (q *FakeQuerier) deleteOrphanedOAuth2ProviderAppTokensNoLock() {
+	tokens := make([]database.OAuth2ProviderAppToken, 0, len(q.oauth2ProviderAppTokens))
+	for _, token := range q.oauth2ProviderAppTokens {
+		if slices.ContainsFunc(q.apiKeys, func(key database.APIKey) bool {
+			return key.ID == token.APIKeyID
+		}) {
+			tokens = append(tokens, token)
+		}
+	}
+	q.oauth2ProviderAppTokens = tokens
+}
+
+func (q *FakeQuerier) sortConnectionLogsNoLock() {
+	slices.SortStableFunc(q.connectionLogs, func(a, b database.ConnectionLog) int {
+		return b.ConnectTime.Compare(a.ConnectTime)
+	})
+}
+
 func convertUsers(users []database.User, count int64) []database.GetUsersRow {
 	rows := make([]database.GetUsersRow, len(users))
 	for i, u := range users {
 		rows[i] = database.GetUsersRow{
-			ID:             u.ID,
-			Email:          u.Email,
-			Username:       u.Username,
-			HashedPassword: u.HashedPassword,
-			CreatedAt:      u.CreatedAt,
-			UpdatedAt:      u.UpdatedAt,
-			Status:         u.Status,
-			RBACRoles:      u.RBACRoles,
-			LoginType:      u.LoginType,
-			AvatarURL:      u.AvatarURL,
-			Deleted:        u.Deleted,
-			LastSeenAt:     u.LastSeenAt,
-			Count:          count,
+			ID:               u.ID,
+			Email:            u.Email,
+			Username:         u.Username,
+			HashedPassword:   u.HashedPassword,
+			CreatedAt:        u.CreatedAt,
+			UpdatedAt:        u.UpdatedAt,
+			Status:           u.Status,
+			RBACRoles:        u.RBACRoles,
+			LoginType:        u.LoginType,
+			AvatarURL:        u.AvatarURL,
+			Deleted:          u.Deleted,
+			LastSeenAt:       u.LastSeenAt,
+			IsServiceAccount: u.IsServiceAccount,
+			Count:            count,
 		}
 	}
 
@@ -351,6 +401,7 @@ func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspac
 			LastUsedAt:        w.LastUsedAt,
 			DormantAt:         w.DormantAt,
 			DeletingAt:        w.DeletingAt,
+			AutomaticUpdates:  w.AutomaticUpdates,
 			Count:             count,
 		}
 
@@ -689,6 +740,34 @@ func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
 
+func (q *FakeQuerier) AcquireNotificationMessageEmail(_ context.Context, arg database.AcquireNotificationMessageEmailParams) (database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationMessage{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := -1
+	for i, message := range q.notificationMessages {
+		if !message.EmailNextAttemptAt.Valid || message.EmailNextAttemptAt.Time.After(arg.Now) {
+			continue
+		}
+		if index == -1 || message.EmailNextAttemptAt.Time.Before(q.notificationMessages[index].EmailNextAttemptAt.Time) {
+			index = i
+		}
+	}
+	if index == -1 {
+		return database.NotificationMessage{}, sql.ErrNoRows
+	}
+
+	message := q.notificationMessages[index]
+	message.EmailAttempts++
+	message.EmailNextAttemptAt = sql.NullTime{Time: arg.LeaseExpiresAt, Valid: true}
+	q.notificationMessages[index] = message
+	return message, nil
+}
+
 func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -744,11 +823,92 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) AcquireWebhookDelivery(_ context.Context, arg database.AcquireWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	enabled := make(map[uuid.UUID]bool, len(q.webhooks))
+	for _, webhook := range q.webhooks {
+		enabled[webhook.ID] = webhook.Enabled
+	}
+
+	index := -1
+	for i, delivery := range q.webhookDeliveries {
+		if !delivery.NextAttemptAt.Valid || delivery.NextAttemptAt.Time.After(arg.Now) {
+			continue
+		}
+		if !enabled[delivery.WebhookID] {
+			continue
+		}
+		if index == -1 || delivery.NextAttemptAt.Time.Before(q.webhookDeliveries[index].NextAttemptAt.Time) {
+			index = i
+		}
+	}
+	if index == -1 {
+		return database.WebhookDelivery{}, sql.ErrNoRows
+	}
+
+	delivery := q.webhookDeliveries[index]
+	delivery.Attempts++
+	delivery.LastAttemptAt = sql.NullTime{Time: arg.Now, Valid: true}
+	delivery.NextAttemptAt = sql.NullTime{Time: arg.LeaseExpiresAt, Valid: true}
+	q.webhookDeliveries[index] = delivery
+	return delivery, nil
+}
+
 func (*FakeQuerier) CleanTailnetCoordinators(_ context.Context) error {
 	return ErrUnimplemented
 }
 
-func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
+func (q *FakeQuerier) ClaimPrebuiltWorkspace(_ context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.Workspace, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.ID != arg.ID {
+			continue
+		}
+		for _, other := range q.workspaces {
+			if !other.Deleted && other.OwnerID == arg.OwnerID && strings.EqualFold(other.Name, arg.Name) {
+				return database.Workspace{}, errDuplicateKey
+			}
+		}
+
+		workspace.OwnerID = arg.OwnerID
+		workspace.Name = arg.Name
+		workspace.AutostartSchedule = arg.AutostartSchedule
+		workspace.Ttl = arg.Ttl
+		workspace.AutomaticUpdates = arg.AutomaticUpdates
+		workspace.UpdatedAt = arg.UpdatedAt
+		workspace.LastUsedAt = arg.UpdatedAt
+		q.workspaces[i] = workspace
+		return workspace, nil
+	}
+	return database.Workspace{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) CountUnreadNotificationMessagesByUserID(_ context.Context, userID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, message := range q.notificationMessages {
+		if message.UserID == userID && !message.ReadAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) (int64, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -758,9 +918,10 @@ func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 		}
 		q.apiKeys[index] = q.apiKeys[len(q.apiKeys)-1]
 		q.apiKeys = q.apiKeys[:len(q.apiKeys)-1]
-		return nil
+		q.deleteOrphanedOAuth2ProviderAppTokensNoLock()
+		return 1, nil
 	}
-	return sql.ErrNoRows
+	return 0, nil
 }
 
 func (q *FakeQuerier) DeleteAPIKeysByUserID(_ context.Context, userID uuid.UUID) error {
@@ -772,7 +933,7 @@ func (q *FakeQuerier) DeleteAPIKeysByUserID(_ context.Context, userID uuid.UUID)
 			q.apiKeys = append(q.apiKeys[:i], q.apiKeys[i+1:]...)
 		}
 	}
-
+	q.deleteOrphanedOAuth2ProviderAppTokensNoLock()
 	return nil
 }
 
@@ -785,7 +946,7 @@ func (q *FakeQuerier) DeleteApplicationConnectAPIKeysByUserID(_ context.Context,
 			q.apiKeys = append(q.apiKeys[:i], q.apiKeys[i+1:]...)
 		}
 	}
-
+	q.deleteOrphanedOAuth2ProviderAppTokensNoLock()
 	return nil
 }
 
@@ -793,6 +954,40 @@ func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteCustomRoleByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if role.ID != id {
+			continue
+		}
+		q.customRoles = append(q.customRoles[:i], q.customRoles[i+1:]...)
+
+		if !role.OrganizationID.Valid {
+			for j, user := range q.users {
+				user.RBACRoles = slices.DeleteFunc(user.RBACRoles, func(name string) bool {
+					return name == role.Name
+				})
+				q.users[j] = user
+			}
+			return nil
+		}
+		assigned := role.Name + ":" + role.OrganizationID.UUID.String()
+		for j, member := range q.organizationMembers {
+			if member.OrganizationID != role.OrganizationID.UUID {
+				continue
+			}
+			member.Roles = slices.DeleteFunc(member.Roles, func(name string) bool {
+				return name == assigned
+			})
+			q.organizationMembers[j] = member
+		}
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) DeleteGitSSHKey(_ context.Context, userID uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -878,11 +1073,204 @@ func (q *FakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, app := range q.oauth2ProviderApps {
+		if app.ID != id {
+			continue
+		}
+		q.oauth2ProviderApps = append(q.oauth2ProviderApps[:i], q.oauth2ProviderApps[i+1:]...)
+
+		secrets := make([]database.OAuth2ProviderAppSecret, 0, len(q.oauth2ProviderAppSecrets))
+		for _, secret := range q.oauth2ProviderAppSecrets {
+			if secret.AppID != id {
+				secrets = append(secrets, secret)
+			}
+		}
+		q.oauth2ProviderAppSecrets = secrets
+
+		codes := make([]database.OAuth2ProviderAppCode, 0, len(q.oauth2ProviderAppCodes))
+		for _, code := range q.oauth2ProviderAppCodes {
+			if code.AppID != id {
+				codes = append(codes, code)
+			}
+		}
+		q.oauth2ProviderAppCodes = codes
+
+		// Revoke the API keys of the app's tokens.
+		for _, token := range q.oauth2ProviderAppTokens {
+			if token.AppID != id {
+				continue
+			}
+			for j := len(q.apiKeys) - 1; j >= 0; j-- {
+				if q.apiKeys[j].ID == token.APIKeyID {
+					q.apiKeys = append(q.apiKeys[:j], q.apiKeys[j+1:]...)
+				}
+			}
+		}
+		q.deleteOrphanedOAuth2ProviderAppTokensNoLock()
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (int64, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes[:i], q.oauth2ProviderAppCodes[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(_ context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i := len(q.oauth2ProviderAppCodes) - 1; i >= 0; i-- {
+		code := q.oauth2ProviderAppCodes[i]
+		if code.AppID == arg.AppID && code.UserID == arg.UserID {
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes[:i], q.oauth2ProviderAppCodes[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets[:i], q.oauth2ProviderAppSecrets[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.AppID != arg.AppID {
+			continue
+		}
+		for i := len(q.apiKeys) - 1; i >= 0; i-- {
+			if q.apiKeys[i].ID == token.APIKeyID && q.apiKeys[i].UserID == arg.UserID {
+				q.apiKeys = append(q.apiKeys[:i], q.apiKeys[i+1:]...)
+			}
+		}
+	}
+	q.deleteOrphanedOAuth2ProviderAppTokensNoLock()
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldConnectionLogs(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logs := make([]database.ConnectionLog, 0, len(q.connectionLogs))
+	for _, log := range q.connectionLogs {
+		if log.ConnectTime.Before(beforeTime) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	q.connectionLogs = logs
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldNotificationMessages(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	threshold := dbtime.Now().Add(-90 * 24 * time.Hour)
+	messages := make([]database.NotificationMessage, 0, len(q.notificationMessages))
+	for _, message := range q.notificationMessages {
+		if message.CreatedAt.Before(threshold) {
+			continue
+		}
+		messages = append(messages, message)
+	}
+	q.notificationMessages = messages
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWebhookDeliveries(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	threshold := dbtime.Now().Add(-30 * 24 * time.Hour)
+	deliveries := make([]database.WebhookDelivery, 0, len(q.webhookDeliveries))
+	for _, delivery := range q.webhookDeliveries {
+		if !delivery.NextAttemptAt.Valid && delivery.CreatedAt.Before(threshold) {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	q.webhookDeliveries = deliveries
+	return nil
+}
+
 func (*FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context) error {
 	// noop
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldWorkspaceAgentLogsBySourceID(_ context.Context, arg database.DeleteOldWorkspaceAgentLogsBySourceIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	// Keep the newest logs of the source up to the length.
+	var newerLength int64
+	deleteBefore := int64(-1)
+	for i := len(q.workspaceAgentLogs) - 1; i >= 0; i-- {
+		log := q.workspaceAgentLogs[i]
+		if log.AgentID != arg.AgentID || log.LogSourceID != arg.LogSourceID {
+			continue
+		}
+		newerLength += int64(len(log.Output))
+		if newerLength > int64(arg.MaxLength) {
+			deleteBefore = log.ID
+			break
+		}
+	}
+	if deleteBefore == -1 {
+		return nil
+	}
+	logs := make([]database.WorkspaceAgentLog, 0, len(q.workspaceAgentLogs))
+	for _, log := range q.workspaceAgentLogs {
+		if log.AgentID == arg.AgentID && log.LogSourceID == arg.LogSourceID && log.ID <= deleteBefore {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	q.workspaceAgentLogs = logs
+	return nil
+}
+
 func (*FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 	// no-op
 	return nil
@@ -909,6 +1297,115 @@ func (*FakeQuerier) DeleteTailnetClient(context.Context, database.DeleteTailnetC
 	return database.DeleteTailnetClientRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteWebhookByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, webhook := range q.webhooks {
+		if webhook.ID != id {
+			continue
+		}
+		q.webhooks = append(q.webhooks[:i], q.webhooks[i+1:]...)
+
+		deliveries := make([]database.WebhookDelivery, 0, len(q.webhookDeliveries))
+		for _, delivery := range q.webhookDeliveries {
+			if delivery.WebhookID != id {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+		q.webhookDeliveries = deliveries
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceAgentPTYInvite(_ context.Context, arg database.DeleteWorkspaceAgentPTYInviteParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, invite := range q.workspaceAgentPTYInvites {
+		if invite.AgentID == arg.AgentID && invite.SessionID == arg.SessionID && invite.UserID == arg.UserID {
+			q.workspaceAgentPTYInvites = append(q.workspaceAgentPTYInvites[:i], q.workspaceAgentPTYInvites[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceAgentPortShare(_ context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			q.workspaceAgentPortShares = append(q.workspaceAgentPortShares[:i], q.workspaceAgentPortShares[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceAgentPortSharesByTemplate(_ context.Context, templateID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	shares := make([]database.WorkspaceAgentPortShare, 0, len(q.workspaceAgentPortShares))
+	for _, share := range q.workspaceAgentPortShares {
+		workspace, err := q.getWorkspaceByIDNoLock(context.Background(), share.WorkspaceID)
+		if err == nil && workspace.TemplateID == templateID {
+			continue
+		}
+		shares = append(shares, share)
+	}
+	q.workspaceAgentPortShares = shares
+
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspacePrebuildByWorkspaceID(_ context.Context, workspaceID uuid.UUID) (database.WorkspacePrebuild, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, prebuild := range q.workspacePrebuilds {
+		if prebuild.WorkspaceID == workspaceID {
+			q.workspacePrebuilds = append(q.workspacePrebuilds[:i], q.workspacePrebuilds[i+1:]...)
+			return prebuild, nil
+		}
+	}
+	return database.WorkspacePrebuild{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteWorkspacePrebuildPoolByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, pool := range q.workspacePrebuildPools {
+		if pool.ID != id {
+			continue
+		}
+		q.workspacePrebuildPools = append(q.workspacePrebuildPools[:i], q.workspacePrebuildPools[i+1:]...)
+
+		for j, prebuild := range q.workspacePrebuilds {
+			if prebuild.PoolID.Valid && prebuild.PoolID.UUID == id {
+				q.workspacePrebuilds[j].PoolID = uuid.NullUUID{}
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -985,7 +1482,7 @@ func (q *FakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 
 	active := int64(0)
 	for _, u := range q.users {
-		if u.Status == database.UserStatusActive && !u.Deleted {
+		if u.Status == database.UserStatusActive && !u.Deleted && !u.IsServiceAccount {
 			active++
 		}
 	}
@@ -1171,6 +1668,146 @@ func (q *FakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 	}, nil
 }
 
+func (q *FakeQuerier) GetConnectionLogsOffset(_ context.Context, arg database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	usernameMatches := func(filter string, userID uuid.UUID) bool {
+		user, err := q.getUserByIDNoLock(userID)
+		return err == nil && !user.Deleted && strings.EqualFold(filter, user.Username)
+	}
+
+	// q.connectionLogs are already sorted by connect time DESC.
+	logs := make([]database.GetConnectionLogsOffsetRow, 0)
+	for _, log := range q.connectionLogs {
+		if arg.Type != "" && string(log.Type) != arg.Type {
+			continue
+		}
+		if arg.WorkspaceOwnerID != uuid.Nil && log.WorkspaceOwnerID != arg.WorkspaceOwnerID {
+			continue
+		}
+		if arg.WorkspaceOwner != "" && !usernameMatches(arg.WorkspaceOwner, log.WorkspaceOwnerID) {
+			continue
+		}
+		if arg.WorkspaceID != uuid.Nil && log.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		if arg.WorkspaceName != "" && !strings.EqualFold(log.WorkspaceName, arg.WorkspaceName) {
+			continue
+		}
+		if arg.UserID != uuid.Nil && (!log.UserID.Valid || log.UserID.UUID != arg.UserID) {
+			continue
+		}
+		if arg.Username != "" && (!log.UserID.Valid || !usernameMatches(arg.Username, log.UserID.UUID)) {
+			continue
+		}
+		if !arg.DateFrom.IsZero() && log.ConnectTime.Before(arg.DateFrom) {
+			continue
+		}
+		if !arg.DateTo.IsZero() && log.ConnectTime.After(arg.DateTo) {
+			continue
+		}
+
+		row := database.GetConnectionLogsOffsetRow{
+			ID:               log.ID,
+			ConnectTime:      log.ConnectTime,
+			DisconnectTime:   log.DisconnectTime,
+			OrganizationID:   log.OrganizationID,
+			WorkspaceOwnerID: log.WorkspaceOwnerID,
+			WorkspaceID:      log.WorkspaceID,
+			WorkspaceName:    log.WorkspaceName,
+			AgentID:          log.AgentID,
+			AgentName:        log.AgentName,
+			Type:             log.Type,
+			UserID:           log.UserID,
+			Ip:               log.Ip,
+			UserAgent:        log.UserAgent,
+			SlugOrPort:       log.SlugOrPort,
+			Code:             log.Code,
+			BytesSent:        log.BytesSent,
+			BytesReceived:    log.BytesReceived,
+			DisconnectReason: log.DisconnectReason,
+		}
+		if owner, err := q.getUserByIDNoLock(log.WorkspaceOwnerID); err == nil {
+			row.WorkspaceOwnerUsername = sql.NullString{String: owner.Username, Valid: true}
+		}
+		if log.UserID.Valid {
+			if user, err := q.getUserByIDNoLock(log.UserID.UUID); err == nil {
+				row.UserUsername = sql.NullString{String: user.Username, Valid: true}
+			}
+		}
+		logs = append(logs, row)
+	}
+
+	count := int64(len(logs))
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(logs) {
+			return []database.GetConnectionLogsOffsetRow{}, nil
+		}
+		logs = logs[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(logs) {
+		logs = logs[:arg.LimitOpt]
+	}
+	for i := range logs {
+		logs[i].Count = count
+	}
+	return logs, nil
+}
+
+func (q *FakeQuerier) GetCustomRoleByID(_ context.Context, id uuid.UUID) (database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, role := range q.customRoles {
+		if role.ID == id {
+			return role, nil
+		}
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetCustomRoles(_ context.Context) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := slices.Clone(q.customRoles)
+	slices.SortFunc(roles, func(a, b database.CustomRole) int {
+		if a.OrganizationID.Valid != b.OrganizationID.Valid {
+			if !a.OrganizationID.Valid {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(a.OrganizationID.UUID.String(), b.OrganizationID.UUID.String()); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return roles, nil
+}
+
+func (q *FakeQuerier) GetCustomRolesByName(_ context.Context, lookupRoles []string) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		name := role.Name
+		if role.OrganizationID.Valid {
+			name += ":" + role.OrganizationID.UUID.String()
+		}
+		if slices.Contains(lookupRoles, name) {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
 func (q *FakeQuerier) GetDBCryptKeys(_ context.Context) ([]database.DBCryptKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1203,6 +1840,9 @@ func (q *FakeQuerier) GetDeploymentDAUs(_ context.Context, tzOffset int32) ([]da
 		if as.ConnectionCount == 0 {
 			continue
 		}
+		if q.isServiceAccountNoLock(as.UserID) {
+			continue
+		}
 		date := as.CreatedAt.UTC().Add(time.Duration(tzOffset) * -1 * time.Hour).Truncate(time.Hour * 24)
 
 		dateEntry := seens[date]
@@ -1590,42 +2230,242 @@ func (q *FakeQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(_ context.Context,
 			returnBuilds = append(returnBuilds, b)
 		}
 	}
-	if len(returnBuilds) == 0 {
-		return nil, sql.ErrNoRows
+	if len(returnBuilds) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return returnBuilds, nil
+}
+
+func (q *FakeQuerier) GetLicenseByID(_ context.Context, id int32) (database.License, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, license := range q.licenses {
+		if license.ID == id {
+			return license, nil
+		}
+	}
+	return database.License{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetLicenses(_ context.Context) ([]database.License, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	results := append([]database.License{}, q.licenses...)
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	return results, nil
+}
+
+func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.logoURL == "" {
+		return "", sql.ErrNoRows
+	}
+
+	return q.logoURL, nil
+}
+
+func (q *FakeQuerier) GetNotificationMessageByID(_ context.Context, id uuid.UUID) (database.NotificationMessage, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, message := range q.notificationMessages {
+		if message.ID == id {
+			return message, nil
+		}
+	}
+	return database.NotificationMessage{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetNotificationMessagesByUserID(_ context.Context, arg database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	messages := make([]database.NotificationMessage, 0)
+	for _, message := range q.notificationMessages {
+		if message.UserID != arg.UserID {
+			continue
+		}
+		if arg.UnreadOnly && message.ReadAt.Valid {
+			continue
+		}
+		messages = append(messages, message)
+	}
+	slices.SortStableFunc(messages, func(a, b database.NotificationMessage) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(messages) {
+			return []database.NotificationMessage{}, nil
+		}
+		messages = messages[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(messages) {
+		messages = messages[:arg.LimitOpt]
+	}
+	return messages, nil
+}
+
+func (q *FakeQuerier) GetNotificationPreferencesByUserID(_ context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	preferences := make([]database.NotificationPreference, 0)
+	for _, preference := range q.notificationPreferences {
+		if preference.UserID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	kinds := database.AllNotificationKindValues()
+	slices.SortFunc(preferences, func(a, b database.NotificationPreference) int {
+		// Enums are sorted in the order of their values.
+		return slices.Index(kinds, a.Kind) - slices.Index(kinds, b.Kind)
+	})
+	return preferences, nil
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == id {
+			return app, nil
+		}
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByHashedSecret(_ context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if bytes.Equal(code.HashedSecret, hashedSecret) {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppSecretByHashedSecret(_ context.Context, hashedSecret []byte) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if bytes.Equal(secret.HashedSecret, hashedSecret) {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppSecretsByAppID(_ context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := make([]database.OAuth2ProviderAppSecret, 0)
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID == appID {
+			secrets = append(secrets, secret)
+		}
+	}
+	slices.SortStableFunc(secrets, func(a, b database.OAuth2ProviderAppSecret) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return secrets, nil
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppTokenByAPIKeyID(_ context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.APIKeyID == apiKeyID {
+			return token, nil
+		}
 	}
-	return returnBuilds, nil
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) GetLicenseByID(_ context.Context, id int32) (database.License, error) {
+func (q *FakeQuerier) GetOAuth2ProviderAppTokenByRefreshHash(_ context.Context, refreshHash []byte) (database.OAuth2ProviderAppToken, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	for _, license := range q.licenses {
-		if license.ID == id {
-			return license, nil
+	for _, token := range q.oauth2ProviderAppTokens {
+		if bytes.Equal(token.RefreshHash, refreshHash) {
+			return token, nil
 		}
 	}
-	return database.License{}, sql.ErrNoRows
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) GetLicenses(_ context.Context) ([]database.License, error) {
+func (q *FakeQuerier) GetOAuth2ProviderApps(_ context.Context) ([]database.OAuth2ProviderApp, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	results := append([]database.License{}, q.licenses...)
-	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
-	return results, nil
+	apps := slices.Clone(q.oauth2ProviderApps)
+	slices.SortFunc(apps, func(a, b database.OAuth2ProviderApp) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return apps, nil
 }
 
-func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
+func (q *FakeQuerier) GetOAuth2ProviderAppsByUserID(_ context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	if q.logoURL == "" {
-		return "", sql.ErrNoRows
+	authorized := make(map[uuid.UUID]bool)
+	for _, token := range q.oauth2ProviderAppTokens {
+		for _, key := range q.apiKeys {
+			if key.ID == token.APIKeyID && key.UserID == userID {
+				authorized[token.AppID] = true
+			}
+		}
 	}
 
-	return q.logoURL, nil
+	apps := make([]database.OAuth2ProviderApp, 0)
+	for _, app := range q.oauth2ProviderApps {
+		if authorized[app.ID] {
+			apps = append(apps, app)
+		}
+	}
+	slices.SortFunc(apps, func(a, b database.OAuth2ProviderApp) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return apps, nil
 }
 
 func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
@@ -2053,6 +2893,9 @@ func (q *FakeQuerier) GetTemplateAppInsights(ctx context.Context, arg database.G
 			(s.SessionStartedAt.Before(arg.StartTime) && (s.SessionEndedAt.After(arg.EndTime) || s.SessionEndedAt.Equal(arg.EndTime)))) {
 			continue
 		}
+		if q.isServiceAccountNoLock(s.UserID) {
+			continue
+		}
 
 		w, err := q.getWorkspaceByIDNoLock(ctx, s.WorkspaceID)
 		if err != nil {
@@ -2242,6 +3085,9 @@ func (q *FakeQuerier) GetTemplateDAUs(_ context.Context, arg database.GetTemplat
 		if as.ConnectionCount == 0 {
 			continue
 		}
+		if q.isServiceAccountNoLock(as.UserID) {
+			continue
+		}
 
 		date := as.CreatedAt.UTC().Add(time.Duration(arg.TzOffset) * time.Hour * -1).Truncate(time.Hour * 24)
 
@@ -2304,6 +3150,9 @@ func (q *FakeQuerier) GetTemplateDailyInsights(ctx context.Context, arg database
 		if s.ConnectionCount == 0 {
 			continue
 		}
+		if q.isServiceAccountNoLock(s.UserID) {
+			continue
+		}
 
 		for _, ds := range dailyStats {
 			if s.CreatedAt.Before(ds.startTime) || s.CreatedAt.Equal(ds.endTime) || s.CreatedAt.After(ds.endTime) {
@@ -2315,6 +3164,9 @@ func (q *FakeQuerier) GetTemplateDailyInsights(ctx context.Context, arg database
 	}
 
 	for _, s := range q.workspaceAppStats {
+		if q.isServiceAccountNoLock(s.UserID) {
+			continue
+		}
 		w, err := q.getWorkspaceByIDNoLock(ctx, s.WorkspaceID)
 		if err != nil {
 			return nil, err
@@ -2364,6 +3216,9 @@ func (q *FakeQuerier) GetTemplateInsights(_ context.Context, arg database.GetTem
 		return database.GetTemplateInsightsRow{}, err
 	}
 
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
 	templateIDSet := make(map[uuid.UUID]struct{})
 	appUsageIntervalsByUser := make(map[uuid.UUID]map[time.Time]*database.GetTemplateInsightsRow)
 	for _, s := range q.workspaceAgentStats {
@@ -2376,6 +3231,9 @@ func (q *FakeQuerier) GetTemplateInsights(_ context.Context, arg database.GetTem
 		if s.ConnectionCount == 0 {
 			continue
 		}
+		if q.isServiceAccountNoLock(s.UserID) {
+			continue
+		}
 
 		templateIDSet[s.TemplateID] = struct{}{}
 		if appUsageIntervalsByUser[s.UserID] == nil {
@@ -2586,6 +3444,23 @@ func (q *FakeQuerier) GetTemplateVersionParameters(_ context.Context, templateVe
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetTemplateVersionPresets(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	presets := make([]database.TemplateVersionPreset, 0)
+	for _, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID != templateVersionID {
+			continue
+		}
+		presets = append(presets, preset)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return strings.ToLower(presets[i].Name) < strings.ToLower(presets[j].Name)
+	})
+	return presets, nil
+}
+
 func (q *FakeQuerier) GetTemplateVersionVariables(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3017,6 +3892,59 @@ func (q *FakeQuerier) GetUsersByIDs(_ context.Context, ids []uuid.UUID) ([]datab
 	return users, nil
 }
 
+func (q *FakeQuerier) GetWebhookByID(_ context.Context, id uuid.UUID) (database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebhookDeliveriesByWebhookID(_ context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	deliveries := make([]database.WebhookDelivery, 0)
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.WebhookID == arg.WebhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	slices.SortStableFunc(deliveries, func(a, b database.WebhookDelivery) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(deliveries) {
+			return []database.WebhookDelivery{}, nil
+		}
+		deliveries = deliveries[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(deliveries) {
+		deliveries = deliveries[:arg.LimitOpt]
+	}
+	return deliveries, nil
+}
+
+func (q *FakeQuerier) GetWebhooks(_ context.Context) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := slices.Clone(q.webhooks)
+	slices.SortFunc(webhooks, func(a, b database.Webhook) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return webhooks, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentAndOwnerByAuthToken(_ context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3121,6 +4049,38 @@ func (q *FakeQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, i
 	}, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentLogFilesByAgentID(_ context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentLogFile, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logFiles := make([]database.WorkspaceAgentLogFile, 0)
+	for _, logFile := range q.workspaceAgentLogFiles {
+		if logFile.WorkspaceAgentID == workspaceAgentID {
+			logFiles = append(logFiles, logFile)
+		}
+	}
+	sort.SliceStable(logFiles, func(i, j int) bool {
+		return logFiles[i].Path < logFiles[j].Path
+	})
+	return logFiles, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentLogSourcesByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logSources := make([]database.WorkspaceAgentLogSource, 0)
+	for _, logSource := range q.workspaceAgentLogSources {
+		for _, id := range ids {
+			if logSource.WorkspaceAgentID == id {
+				logSources = append(logSources, logSource)
+				break
+			}
+		}
+	}
+	return logSources, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentLogsAfter(_ context.Context, arg database.GetWorkspaceAgentLogsAfterParams) ([]database.WorkspaceAgentLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -3155,6 +4115,109 @@ func (q *FakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, workspaceAgen
 	return metadata, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentPTYInvite(_ context.Context, arg database.GetWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentPTYInvite{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, invite := range q.workspaceAgentPTYInvites {
+		if invite.AgentID == arg.AgentID && invite.SessionID == arg.SessionID && invite.UserID == arg.UserID {
+			return invite, nil
+		}
+	}
+	return database.WorkspaceAgentPTYInvite{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentPTYInvitesBySessionID(_ context.Context, arg database.GetWorkspaceAgentPTYInvitesBySessionIDParams) ([]database.WorkspaceAgentPTYInvite, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	invites := make([]database.WorkspaceAgentPTYInvite, 0)
+	for _, invite := range q.workspaceAgentPTYInvites {
+		if invite.AgentID == arg.AgentID && invite.SessionID == arg.SessionID {
+			invites = append(invites, invite)
+		}
+	}
+	slices.SortFunc(invites, func(a, b database.WorkspaceAgentPTYInvite) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return invites, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentPTYInvitesByUserID(_ context.Context, userID uuid.UUID) ([]database.WorkspaceAgentPTYInvite, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	invites := make([]database.WorkspaceAgentPTYInvite, 0)
+	for _, invite := range q.workspaceAgentPTYInvites {
+		if invite.UserID == userID {
+			invites = append(invites, invite)
+		}
+	}
+	slices.SortFunc(invites, func(a, b database.WorkspaceAgentPTYInvite) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return invites, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentPortShare(_ context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			return share, nil
+		}
+	}
+
+	return database.WorkspaceAgentPortShare{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentScriptByID(_ context.Context, id uuid.UUID) (database.WorkspaceAgentScript, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, script := range q.workspaceAgentScripts {
+		if script.ID == id {
+			return script, nil
+		}
+	}
+	return database.WorkspaceAgentScript{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentScriptsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	scripts := make([]database.WorkspaceAgentScript, 0)
+	for _, script := range q.workspaceAgentScripts {
+		for _, id := range ids {
+			if script.WorkspaceAgentID == id {
+				scripts = append(scripts, script)
+				break
+			}
+		}
+	}
+	sort.SliceStable(scripts, func(i, j int) bool {
+		return scripts[i].DisplayOrder < scripts[j].DisplayOrder
+	})
+	return scripts, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentStats(_ context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3602,6 +4665,63 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspacePrebuildPoolByID(_ context.Context, id uuid.UUID) (database.WorkspacePrebuildPool, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, pool := range q.workspacePrebuildPools {
+		if pool.ID == id {
+			return pool, nil
+		}
+	}
+	return database.WorkspacePrebuildPool{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspacePrebuildPools(_ context.Context) ([]database.WorkspacePrebuildPool, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	pools := slices.Clone(q.workspacePrebuildPools)
+	slices.SortFunc(pools, func(a, b database.WorkspacePrebuildPool) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return pools, nil
+}
+
+func (q *FakeQuerier) GetWorkspacePrebuildPoolsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.WorkspacePrebuildPool, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	pools := make([]database.WorkspacePrebuildPool, 0)
+	for _, pool := range q.workspacePrebuildPools {
+		if pool.TemplateID == templateID {
+			pools = append(pools, pool)
+		}
+	}
+	slices.SortFunc(pools, func(a, b database.WorkspacePrebuildPool) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return pools, nil
+}
+
+func (q *FakeQuerier) GetWorkspacePrebuilds(ctx context.Context) ([]database.WorkspacePrebuild, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	prebuilds := make([]database.WorkspacePrebuild, 0)
+	for _, prebuild := range q.workspacePrebuilds {
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, prebuild.WorkspaceID)
+		if err != nil || workspace.Deleted {
+			continue
+		}
+		prebuilds = append(prebuilds, prebuild)
+	}
+	slices.SortFunc(prebuilds, func(a, b database.WorkspacePrebuild) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return prebuilds, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceProxies(_ context.Context) ([]database.WorkspaceProxy, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3766,6 +4886,50 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceSessionRecordingByID(_ context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID == id {
+			return recording, nil
+		}
+	}
+	return database.WorkspaceSessionRecording{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingChunks(_ context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	chunks := make([]database.WorkspaceSessionRecordingChunk, 0)
+	for _, chunk := range q.workspaceSessionRecordingData {
+		if chunk.RecordingID == recordingID {
+			chunks = append(chunks, chunk)
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].ChunkIndex < chunks[j].ChunkIndex
+	})
+	return chunks, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	recordings := make([]database.WorkspaceSessionRecording, 0)
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.WorkspaceID == workspaceID {
+			recordings = append(recordings, recording)
+		}
+	}
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].CreatedAt.After(recordings[j].CreatedAt)
+	})
+	return recordings, nil
+}
+
 func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -3776,6 +4940,46 @@ func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspa
 	return workspaceRows, err
 }
 
+func (q *FakeQuerier) GetWorkspacesApproachingAutostop(ctx context.Context, arg database.GetWorkspacesApproachingAutostopParams) ([]database.GetWorkspacesApproachingAutostopRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := []database.GetWorkspacesApproachingAutostopRow{}
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.DormantAt.Valid {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil {
+			continue
+		}
+		if build.Transition != database.WorkspaceTransitionStart ||
+			!build.Deadline.After(arg.Now) ||
+			build.Deadline.After(arg.StopBefore) {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, xerrors.Errorf("get provisioner job by ID: %w", err)
+		}
+		if !job.CompletedAt.Valid || job.Error.String != "" {
+			continue
+		}
+		rows = append(rows, database.GetWorkspacesApproachingAutostopRow{
+			ID:       workspace.ID,
+			OwnerID:  workspace.OwnerID,
+			Name:     workspace.Name,
+			BuildID:  build.ID,
+			Deadline: build.Deadline,
+		})
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3859,6 +5063,7 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		LastUsed:        arg.LastUsed,
 		LoginType:       arg.LoginType,
 		Scope:           arg.Scope,
+		ScopeRules:      arg.ScopeRules,
 		TokenName:       arg.TokenName,
 	}
 	q.apiKeys = append(q.apiKeys, key)
@@ -3895,7 +5100,37 @@ func (q *FakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 		}
 	})
 
-	return alog, nil
+	return alog, nil
+}
+
+func (q *FakeQuerier) InsertCustomRole(_ context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, role := range q.customRoles {
+		if role.OrganizationID == arg.OrganizationID && strings.EqualFold(role.Name, arg.Name) {
+			return database.CustomRole{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	role := database.CustomRole{
+		ID:              arg.ID,
+		Name:            arg.Name,
+		DisplayName:     arg.DisplayName,
+		OrganizationID:  arg.OrganizationID,
+		SitePermissions: arg.SitePermissions,
+		OrgPermissions:  arg.OrgPermissions,
+		UserPermissions: arg.UserPermissions,
+		CreatedAt:       arg.CreatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
 }
 
 func (q *FakeQuerier) InsertDBCryptKey(_ context.Context, arg database.InsertDBCryptKeyParams) error {
@@ -4113,6 +5348,163 @@ func (q *FakeQuerier) InsertMissingGroups(_ context.Context, arg database.Insert
 	return newGroups, nil
 }
 
+func (q *FakeQuerier) InsertNotificationMessage(_ context.Context, arg database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationMessage{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, preference := range q.notificationPreferences {
+		if preference.UserID == arg.UserID && preference.Kind == arg.Kind && preference.Disabled {
+			return database.NotificationMessage{}, sql.ErrNoRows
+		}
+	}
+	if arg.DedupeKey != "" {
+		for _, message := range q.notificationMessages {
+			if message.UserID == arg.UserID && message.DedupeKey == arg.DedupeKey {
+				return database.NotificationMessage{}, sql.ErrNoRows
+			}
+		}
+	}
+
+	message := database.NotificationMessage{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		Kind:      arg.Kind,
+		Title:     arg.Title,
+		Content:   arg.Content,
+		DedupeKey: arg.DedupeKey,
+		CreatedAt: arg.CreatedAt,
+		EmailNextAttemptAt: sql.NullTime{
+			Time:  arg.CreatedAt,
+			Valid: arg.Email,
+		},
+	}
+	q.notificationMessages = append(q.notificationMessages, message)
+	return message, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if strings.EqualFold(app.Name, arg.Name) {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	app := database.OAuth2ProviderApp{
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		UpdatedAt:    arg.UpdatedAt,
+		Name:         arg.Name,
+		Icon:         arg.Icon,
+		RedirectURIs: arg.RedirectURIs,
+	}
+	q.oauth2ProviderApps = append(q.oauth2ProviderApps, app)
+	return app, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppCode(_ context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !slices.ContainsFunc(q.oauth2ProviderApps, func(app database.OAuth2ProviderApp) bool {
+		return app.ID == arg.AppID
+	}) {
+		return database.OAuth2ProviderAppCode{}, errForeignKeyConstraint
+	}
+
+	//nolint:gosimple
+	code := database.OAuth2ProviderAppCode{
+		ID:            arg.ID,
+		AppID:         arg.AppID,
+		UserID:        arg.UserID,
+		CreatedAt:     arg.CreatedAt,
+		ExpiresAt:     arg.ExpiresAt,
+		HashedSecret:  arg.HashedSecret,
+		RedirectURI:   arg.RedirectURI,
+		Scope:         arg.Scope,
+		CodeChallenge: arg.CodeChallenge,
+	}
+	q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+	return code, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppSecret(_ context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !slices.ContainsFunc(q.oauth2ProviderApps, func(app database.OAuth2ProviderApp) bool {
+		return app.ID == arg.AppID
+	}) {
+		return database.OAuth2ProviderAppSecret{}, errForeignKeyConstraint
+	}
+
+	//nolint:gosimple
+	secret := database.OAuth2ProviderAppSecret{
+		ID:            arg.ID,
+		AppID:         arg.AppID,
+		CreatedAt:     arg.CreatedAt,
+		HashedSecret:  arg.HashedSecret,
+		DisplaySecret: arg.DisplaySecret,
+	}
+	q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets, secret)
+	return secret, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !slices.ContainsFunc(q.oauth2ProviderApps, func(app database.OAuth2ProviderApp) bool {
+		return app.ID == arg.AppID
+	}) {
+		return database.OAuth2ProviderAppToken{}, errForeignKeyConstraint
+	}
+	if !slices.ContainsFunc(q.apiKeys, func(key database.APIKey) bool {
+		return key.ID == arg.APIKeyID
+	}) {
+		return database.OAuth2ProviderAppToken{}, errForeignKeyConstraint
+	}
+
+	//nolint:gosimple
+	token := database.OAuth2ProviderAppToken{
+		ID:          arg.ID,
+		AppID:       arg.AppID,
+		APIKeyID:    arg.APIKeyID,
+		CreatedAt:   arg.CreatedAt,
+		ExpiresAt:   arg.ExpiresAt,
+		RefreshHash: arg.RefreshHash,
+	}
+	q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
+	return token, nil
+}
+
 func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Organization{}, err
@@ -4274,6 +5666,7 @@ func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 		AllowUserCancelWorkspaceJobs: arg.AllowUserCancelWorkspaceJobs,
 		AllowUserAutostart:           true,
 		AllowUserAutostop:            true,
+		MaxPortSharingLevel:          database.AppSharingLevelOwner,
 	}
 	q.templates = append(q.templates, template)
 	return nil
@@ -4340,6 +5733,32 @@ func (q *FakeQuerier) InsertTemplateVersionParameter(_ context.Context, arg data
 	return param, nil
 }
 
+func (q *FakeQuerier) InsertTemplateVersionPreset(_ context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID == arg.TemplateVersionID && preset.Name == arg.Name {
+			return database.TemplateVersionPreset{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	preset := database.TemplateVersionPreset{
+		ID:                arg.ID,
+		TemplateVersionID: arg.TemplateVersionID,
+		Name:              arg.Name,
+		Parameters:        arg.Parameters,
+		CreatedAt:         arg.CreatedAt,
+	}
+	q.templateVersionPresets = append(q.templateVersionPresets, preset)
+	return preset, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -4393,15 +5812,16 @@ func (q *FakeQuerier) InsertUser(_ context.Context, arg database.InsertUserParam
 	}
 
 	user := database.User{
-		ID:             arg.ID,
-		Email:          arg.Email,
-		HashedPassword: arg.HashedPassword,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		Username:       arg.Username,
-		Status:         database.UserStatusDormant,
-		RBACRoles:      arg.RBACRoles,
-		LoginType:      arg.LoginType,
+		ID:               arg.ID,
+		Email:            arg.Email,
+		HashedPassword:   arg.HashedPassword,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		Username:         arg.Username,
+		Status:           database.UserStatusDormant,
+		RBACRoles:        arg.RBACRoles,
+		LoginType:        arg.LoginType,
+		IsServiceAccount: arg.IsServiceAccount,
 	}
 	q.users = append(q.users, user)
 	return user, nil
@@ -4451,6 +5871,62 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 	return link, nil
 }
 
+func (q *FakeQuerier) InsertWebhook(_ context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if strings.EqualFold(webhook.Name, arg.Name) {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	webhook := database.Webhook{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+		Url:       arg.Url,
+		Secret:    arg.Secret,
+		Events:    arg.Events,
+		Enabled:   arg.Enabled,
+	}
+	q.webhooks = append(q.webhooks, webhook)
+	return webhook, nil
+}
+
+func (q *FakeQuerier) InsertWebhookDeliveries(_ context.Context, arg database.InsertWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deliveries := make([]database.WebhookDelivery, 0)
+	for _, webhook := range q.webhooks {
+		if !webhook.Enabled || !slices.Contains(webhook.Events, arg.Event) {
+			continue
+		}
+		delivery := database.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     webhook.ID,
+			Event:         arg.Event,
+			Payload:       arg.Payload,
+			CreatedAt:     arg.CreatedAt,
+			NextAttemptAt: sql.NullTime{Time: arg.CreatedAt, Valid: true},
+		}
+		q.webhookDeliveries = append(q.webhookDeliveries, delivery)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
 func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -4471,6 +5947,7 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		AutostartSchedule: arg.AutostartSchedule,
 		Ttl:               arg.Ttl,
 		LastUsedAt:        arg.LastUsedAt,
+		AutomaticUpdates:  arg.AutomaticUpdates,
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -4506,12 +5983,65 @@ func (q *FakeQuerier) InsertWorkspaceAgent(_ context.Context, arg database.Inser
 		LifecycleState:           database.WorkspaceAgentLifecycleStateCreated,
 		ShutdownScript:           arg.ShutdownScript,
 		DisplayApps:              arg.DisplayApps,
+		UpdateState:              database.WorkspaceAgentUpdateStateUnknown,
 	}
 
 	q.workspaceAgents = append(q.workspaceAgents, agent)
 	return agent, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentLogFiles(_ context.Context, arg database.InsertWorkspaceAgentLogFilesParams) ([]database.WorkspaceAgentLogFile, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logFiles := make([]database.WorkspaceAgentLogFile, 0)
+	for index, id := range arg.ID {
+		logFiles = append(logFiles, database.WorkspaceAgentLogFile{
+			ID:               id,
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			LogSourceID:      arg.LogSourceID[index],
+			CreatedAt:        arg.CreatedAt,
+			Path:             arg.Path[index],
+		})
+	}
+	q.workspaceAgentLogFiles = append(q.workspaceAgentLogFiles, logFiles...)
+	return logFiles, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceAgentLogSources(_ context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logSources := make([]database.WorkspaceAgentLogSource, 0)
+	for index, source := range arg.ID {
+		for _, existing := range q.workspaceAgentLogSources {
+			if existing.WorkspaceAgentID == arg.WorkspaceAgentID && existing.ID == source {
+				return nil, errDuplicateKey
+			}
+		}
+		logSource := database.WorkspaceAgentLogSource{
+			ID:               source,
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			CreatedAt:        arg.CreatedAt,
+			DisplayName:      arg.DisplayName[index],
+			Icon:             arg.Icon[index],
+		}
+		logSources = append(logSources, logSource)
+	}
+	q.workspaceAgentLogSources = append(q.workspaceAgentLogSources, logSources...)
+	return logSources, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentLogs(_ context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -4525,31 +6055,29 @@ func (q *FakeQuerier) InsertWorkspaceAgentLogs(_ context.Context, arg database.I
 	if len(q.workspaceAgentLogs) > 0 {
 		id = q.workspaceAgentLogs[len(q.workspaceAgentLogs)-1].ID
 	}
-	outputLength := int32(0)
 	for index, output := range arg.Output {
 		id++
 		logs = append(logs, database.WorkspaceAgentLog{
-			ID:        id,
-			AgentID:   arg.AgentID,
-			CreatedAt: arg.CreatedAt[index],
-			Level:     arg.Level[index],
-			Source:    arg.Source[index],
-			Output:    output,
+			ID:          id,
+			AgentID:     arg.AgentID,
+			CreatedAt:   arg.CreatedAt[index],
+			Level:       arg.Level[index],
+			LogSourceID: arg.LogSourceID,
+			Output:      output,
 		})
-		outputLength += int32(len(output))
 	}
 	for index, agent := range q.workspaceAgents {
 		if agent.ID != arg.AgentID {
 			continue
 		}
 		// Greater than 1MB, same as the PostgreSQL constraint!
-		if agent.LogsLength+outputLength > (1 << 20) {
+		if agent.LogsLength+arg.OutputLength > (1 << 20) {
 			return nil, &pq.Error{
 				Constraint: "max_logs_length",
 				Table:      "workspace_agents",
 			}
 		}
-		agent.LogsLength += outputLength
+		agent.LogsLength += arg.OutputLength
 		q.workspaceAgents[index] = agent
 		break
 	}
@@ -4575,6 +6103,38 @@ func (q *FakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	scripts := make([]database.WorkspaceAgentScript, 0)
+	for index, id := range arg.ID {
+		script := database.WorkspaceAgentScript{
+			ID:               id,
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			CreatedAt:        arg.CreatedAt,
+			LogSourceID:      arg.LogSourceID[index],
+			LogPath:          arg.LogPath[index],
+			Script:           arg.Script[index],
+			Cron:             arg.Cron[index],
+			StartBlocksLogin: arg.StartBlocksLogin[index],
+			RunOnStart:       arg.RunOnStart[index],
+			RunOnStop:        arg.RunOnStop[index],
+			TimeoutSeconds:   arg.TimeoutSeconds[index],
+			DisplayOrder:     arg.DisplayOrder[index],
+			Status:           database.WorkspaceAgentScriptStatusPending,
+		}
+		scripts = append(scripts, script)
+	}
+	q.workspaceAgentScripts = append(q.workspaceAgentScripts, scripts...)
+	return scripts, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentStat(_ context.Context, p database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	if err := validateDatabaseType(p); err != nil {
 		return database.WorkspaceAgentStat{}, err
@@ -4677,6 +6237,8 @@ func (q *FakeQuerier) InsertWorkspaceApp(_ context.Context, arg database.InsertW
 		HealthcheckUrl:       arg.HealthcheckUrl,
 		HealthcheckInterval:  arg.HealthcheckInterval,
 		HealthcheckThreshold: arg.HealthcheckThreshold,
+		HealthcheckTCP:       arg.HealthcheckTCP,
+		HealthcheckCommand:   arg.HealthcheckCommand,
 		Health:               arg.Health,
 	}
 	q.workspaceApps = append(q.workspaceApps, workspaceApp)
@@ -4765,6 +6327,51 @@ func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg data
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspacePrebuild(_ context.Context, arg database.InsertWorkspacePrebuildParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, prebuild := range q.workspacePrebuilds {
+		if prebuild.WorkspaceID == arg.WorkspaceID {
+			return errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	q.workspacePrebuilds = append(q.workspacePrebuilds, database.WorkspacePrebuild{
+		WorkspaceID: arg.WorkspaceID,
+		PoolID:      arg.PoolID,
+		CreatedAt:   arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertWorkspacePrebuildPool(_ context.Context, arg database.InsertWorkspacePrebuildPoolParams) (database.WorkspacePrebuildPool, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	pool := database.WorkspacePrebuildPool{
+		ID:                arg.ID,
+		TemplateID:        arg.TemplateID,
+		TemplateVersionID: arg.TemplateVersionID,
+		Parameters:        arg.Parameters,
+		DesiredInstances:  arg.DesiredInstances,
+		CreatedAt:         arg.CreatedAt,
+		UpdatedAt:         arg.UpdatedAt,
+	}
+	q.workspacePrebuildPools = append(q.workspacePrebuildPools, pool)
+	return pool, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceProxy(_ context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4851,6 +6458,100 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceSessionRecording(_ context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID == arg.ID {
+			return database.WorkspaceSessionRecording{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	recording := database.WorkspaceSessionRecording{
+		ID:            arg.ID,
+		WorkspaceID:   arg.WorkspaceID,
+		AgentID:       arg.AgentID,
+		Type:          arg.Type,
+		ReconnectID:   arg.ReconnectID,
+		Command:       arg.Command,
+		IncludesInput: arg.IncludesInput,
+		CreatedAt:     arg.CreatedAt,
+		UpdatedAt:     arg.UpdatedAt,
+	}
+	q.workspaceSessionRecordings = append(q.workspaceSessionRecordings, recording)
+	return recording, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSessionRecordingChunk(_ context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, chunk := range q.workspaceSessionRecordingData {
+		if chunk.RecordingID == arg.RecordingID && chunk.ChunkIndex == arg.ChunkIndex {
+			return 0, nil
+		}
+	}
+
+	q.workspaceSessionRecordingData = append(q.workspaceSessionRecordingData, database.WorkspaceSessionRecordingChunk{
+		RecordingID: arg.RecordingID,
+		ChunkIndex:  arg.ChunkIndex,
+		Data:        arg.Data,
+		CreatedAt:   arg.CreatedAt,
+	})
+	return 1, nil
+}
+
+func (q *FakeQuerier) ListWorkspaceAgentPortShares(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	shares := make([]database.WorkspaceAgentPortShare, 0)
+	for _, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == workspaceID {
+			shares = append(shares, share)
+		}
+	}
+	slices.SortFunc(shares, func(a, b database.WorkspaceAgentPortShare) int {
+		if a.AgentName != b.AgentName {
+			return strings.Compare(a.AgentName, b.AgentName)
+		}
+		return int(a.Port - b.Port)
+	})
+
+	return shares, nil
+}
+
+func (q *FakeQuerier) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(_ context.Context, templateID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.workspaceAgentPortShares {
+		if share.ShareLevel != database.AppSharingLevelPublic {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(context.Background(), share.WorkspaceID)
+		if err != nil || workspace.TemplateID != templateID {
+			continue
+		}
+		share.ShareLevel = database.AppSharingLevelAuthenticated
+		q.workspaceAgentPortShares[i] = share
+	}
+
+	return nil
+}
+
 func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4931,7 +6632,30 @@ func (q *FakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPI
 		q.apiKeys[index] = apiKey
 		return nil
 	}
-	return sql.ErrNoRows
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateCustomRoleByID(_ context.Context, arg database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if role.ID != arg.ID {
+			continue
+		}
+		role.DisplayName = arg.DisplayName
+		role.SitePermissions = arg.SitePermissions
+		role.OrgPermissions = arg.OrgPermissions
+		role.UserPermissions = arg.UserPermissions
+		role.UpdatedAt = arg.UpdatedAt
+		q.customRoles[i] = role
+		return role, nil
+	}
+	return database.CustomRole{}, sql.ErrNoRows
 }
 
 func (q *FakeQuerier) UpdateGitAuthLink(_ context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
@@ -5056,6 +6780,113 @@ func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateNotificationMessageEmailByID(_ context.Context, arg database.UpdateNotificationMessageEmailByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, message := range q.notificationMessages {
+		if message.ID != arg.ID {
+			continue
+		}
+		message.EmailedAt = arg.EmailedAt
+		message.EmailError = arg.EmailError
+		message.EmailNextAttemptAt = arg.EmailNextAttemptAt
+		q.notificationMessages[i] = message
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateNotificationMessageReadAtByID(_ context.Context, arg database.UpdateNotificationMessageReadAtByIDParams) (database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationMessage{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, message := range q.notificationMessages {
+		if message.ID != arg.ID {
+			continue
+		}
+		if !message.ReadAt.Valid {
+			message.ReadAt = sql.NullTime{Time: arg.ReadAt, Valid: true}
+			q.notificationMessages[i] = message
+		}
+		return message, nil
+	}
+	return database.NotificationMessage{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateNotificationMessagesReadAtByUserID(_ context.Context, arg database.UpdateNotificationMessagesReadAtByUserIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, message := range q.notificationMessages {
+		if message.UserID == arg.UserID && !message.ReadAt.Valid {
+			q.notificationMessages[i].ReadAt = sql.NullTime{Time: arg.ReadAt, Valid: true}
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID != arg.ID && strings.EqualFold(app.Name, arg.Name) {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
+		}
+	}
+
+	for i, app := range q.oauth2ProviderApps {
+		if app.ID != arg.ID {
+			continue
+		}
+		app.UpdatedAt = arg.UpdatedAt
+		app.Name = arg.Name
+		app.Icon = arg.Icon
+		app.RedirectURIs = arg.RedirectURIs
+		q.oauth2ProviderApps[i] = app
+		return app, nil
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateOAuth2ProviderAppSecretByID(_ context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID != arg.ID {
+			continue
+		}
+		secret.LastUsedAt = arg.LastUsedAt
+		q.oauth2ProviderAppSecrets[i] = secret
+		return secret, nil
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5223,6 +7054,11 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.DisplayName = arg.DisplayName
 		tpl.Description = arg.Description
 		tpl.Icon = arg.Icon
+		tpl.MaxPortSharingLevel = arg.MaxPortSharingLevel
+		tpl.RecordSessions = arg.RecordSessions
+		tpl.DeprecationMessage = arg.DeprecationMessage
+		tpl.DeprecatedAt = arg.DeprecatedAt
+		tpl.RequireActiveVersion = arg.RequireActiveVersion
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -5572,6 +7408,58 @@ func (q *FakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUse
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWebhookByID(_ context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.ID != arg.ID && strings.EqualFold(webhook.Name, arg.Name) {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+
+	for i, webhook := range q.webhooks {
+		if webhook.ID != arg.ID {
+			continue
+		}
+		webhook.UpdatedAt = arg.UpdatedAt
+		webhook.Name = arg.Name
+		webhook.Url = arg.Url
+		webhook.Secret = arg.Secret
+		webhook.Events = arg.Events
+		webhook.Enabled = arg.Enabled
+		q.webhooks[i] = webhook
+		return webhook, nil
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWebhookDeliveryByID(_ context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, delivery := range q.webhookDeliveries {
+		if delivery.ID != arg.ID {
+			continue
+		}
+		delivery.StatusCode = arg.StatusCode
+		delivery.Error = arg.Error
+		delivery.DeliveredAt = arg.DeliveredAt
+		delivery.NextAttemptAt = arg.NextAttemptAt
+		q.webhookDeliveries[i] = delivery
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -5602,6 +7490,23 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceAgentAutostopInhibitorsByID(_ context.Context, arg database.UpdateWorkspaceAgentAutostopInhibitorsByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for i, agent := range q.workspaceAgents {
+		if agent.ID == arg.ID {
+			agent.AutostopInhibitors = arg.AutostopInhibitors
+			q.workspaceAgents[i] = agent
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5683,6 +7588,29 @@ func (q *FakeQuerier) UpdateWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) UpdateWorkspaceAgentScriptStatusByID(_ context.Context, arg database.UpdateWorkspaceAgentScriptStatusByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, script := range q.workspaceAgentScripts {
+		if script.ID != arg.ID {
+			continue
+		}
+		script.Status = arg.Status
+		script.ExitCode = arg.ExitCode
+		script.StartedAt = arg.StartedAt
+		script.EndedAt = arg.EndedAt
+		q.workspaceAgentScripts[index] = script
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentStartupByID(_ context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5722,6 +7650,24 @@ func (q *FakeQuerier) UpdateWorkspaceAgentStartupByID(_ context.Context, arg dat
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceAgentUpdateStateByID(_ context.Context, arg database.UpdateWorkspaceAgentUpdateStateByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for i, agent := range q.workspaceAgents {
+		if agent.ID == arg.ID {
+			agent.UpdateState = arg.UpdateState
+			agent.UpdateError = arg.UpdateError
+			q.workspaceAgents[i] = agent
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAppHealthByID(_ context.Context, arg database.UpdateWorkspaceAppHealthByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5741,6 +7687,26 @@ func (q *FakeQuerier) UpdateWorkspaceAppHealthByID(_ context.Context, arg databa
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceAutomaticUpdates(_ context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.AutomaticUpdates = arg.AutomaticUpdates
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAutostart(_ context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5880,6 +7846,26 @@ func (q *FakeQuerier) UpdateWorkspaceLastUsedAt(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspacePrebuildPoolByID(_ context.Context, arg database.UpdateWorkspacePrebuildPoolByIDParams) (database.WorkspacePrebuildPool, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspacePrebuildPool{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, pool := range q.workspacePrebuildPools {
+		if pool.ID != arg.ID {
+			continue
+		}
+		pool.DesiredInstances = arg.DesiredInstances
+		pool.UpdatedAt = arg.UpdatedAt
+		q.workspacePrebuildPools[i] = pool
+		return pool, nil
+	}
+	return database.WorkspacePrebuildPool{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5920,6 +7906,33 @@ func (q *FakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg databas
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceSessionRecordingByID(_ context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, recording := range q.workspaceSessionRecordings {
+		if recording.ID != arg.ID {
+			continue
+		}
+		if recording.EndedAt.Valid && arg.Appended {
+			return 0, nil
+		}
+		recording.UpdatedAt = arg.UpdatedAt
+		recording.Size += arg.AddedSize
+		if !recording.EndedAt.Valid {
+			recording.EndedAt = arg.EndedAt
+		}
+		q.workspaceSessionRecordings[i] = recording
+		return 1, nil
+	}
+	return 0, nil
+}
+
 func (q *FakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5986,6 +7999,73 @@ func (q *FakeQuerier) UpsertAppSecurityKey(_ context.Context, data string) error
 	return nil
 }
 
+func (q *FakeQuerier) UpsertConnectionLog(_ context.Context, arg database.UpsertConnectionLogParams) (database.ConnectionLog, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ConnectionLog{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, log := range q.connectionLogs {
+		if log.ID != arg.ID {
+			continue
+		}
+		if log.AgentID != arg.AgentID {
+			return database.ConnectionLog{}, sql.ErrNoRows
+		}
+		if arg.ConnectTime.Before(log.ConnectTime) {
+			log.ConnectTime = arg.ConnectTime
+		}
+		if !log.UserID.Valid {
+			log.UserID = arg.UserID
+		}
+		if arg.Code.Valid {
+			log.Code = arg.Code
+		}
+		if arg.BytesSent > log.BytesSent {
+			log.BytesSent = arg.BytesSent
+		}
+		if arg.BytesReceived > log.BytesReceived {
+			log.BytesReceived = arg.BytesReceived
+		}
+		if arg.DisconnectTime.Valid {
+			log.DisconnectTime = arg.DisconnectTime
+		}
+		if arg.DisconnectReason != "" {
+			log.DisconnectReason = arg.DisconnectReason
+		}
+		q.connectionLogs[i] = log
+		q.sortConnectionLogsNoLock()
+		return log, nil
+	}
+
+	//nolint:gosimple
+	log := database.ConnectionLog{
+		ID:               arg.ID,
+		ConnectTime:      arg.ConnectTime,
+		DisconnectTime:   arg.DisconnectTime,
+		OrganizationID:   arg.OrganizationID,
+		WorkspaceOwnerID: arg.WorkspaceOwnerID,
+		WorkspaceID:      arg.WorkspaceID,
+		WorkspaceName:    arg.WorkspaceName,
+		AgentID:          arg.AgentID,
+		AgentName:        arg.AgentName,
+		Type:             arg.Type,
+		UserID:           arg.UserID,
+		Ip:               arg.Ip,
+		UserAgent:        arg.UserAgent,
+		SlugOrPort:       arg.SlugOrPort,
+		Code:             arg.Code,
+		BytesSent:        arg.BytesSent,
+		BytesReceived:    arg.BytesReceived,
+		DisconnectReason: arg.DisconnectReason,
+	}
+	q.connectionLogs = append(q.connectionLogs, log)
+	q.sortConnectionLogsNoLock()
+	return log, nil
+}
+
 func (q *FakeQuerier) UpsertDefaultProxy(_ context.Context, arg database.UpsertDefaultProxyParams) error {
 	q.defaultProxyDisplayName = arg.DisplayName
 	q.defaultProxyIconURL = arg.IconUrl
@@ -6008,6 +8088,30 @@ func (q *FakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
 	return nil
 }
 
+func (q *FakeQuerier) UpsertNotificationPreference(_ context.Context, arg database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationPreference{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	preference := database.NotificationPreference{
+		UserID:    arg.UserID,
+		Kind:      arg.Kind,
+		Disabled:  arg.Disabled,
+		UpdatedAt: arg.UpdatedAt,
+	}
+	for i, existing := range q.notificationPreferences {
+		if existing.UserID == arg.UserID && existing.Kind == arg.Kind {
+			q.notificationPreferences[i] = preference
+			return preference, nil
+		}
+	}
+	q.notificationPreferences = append(q.notificationPreferences, preference)
+	return preference, nil
+}
+
 func (q *FakeQuerier) UpsertOAuthSigningKey(_ context.Context, value string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -6036,6 +8140,69 @@ func (*FakeQuerier) UpsertTailnetCoordinator(context.Context, uuid.UUID) (databa
 	return database.TailnetCoordinator{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) UpsertWorkspaceAgentPTYInvite(_ context.Context, arg database.UpsertWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentPTYInvite{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, invite := range q.workspaceAgentPTYInvites {
+		if invite.AgentID == arg.AgentID && invite.SessionID == arg.SessionID && invite.UserID == arg.UserID {
+			invite.ReadOnly = arg.ReadOnly
+			invite.CreatedBy = arg.CreatedBy
+			invite.CreatedAt = arg.CreatedAt
+			q.workspaceAgentPTYInvites[i] = invite
+			return invite, nil
+		}
+	}
+
+	//nolint:gosimple
+	invite := database.WorkspaceAgentPTYInvite{
+		WorkspaceID: arg.WorkspaceID,
+		AgentID:     arg.AgentID,
+		SessionID:   arg.SessionID,
+		UserID:      arg.UserID,
+		ReadOnly:    arg.ReadOnly,
+		CreatedBy:   arg.CreatedBy,
+		CreatedAt:   arg.CreatedAt,
+	}
+	q.workspaceAgentPTYInvites = append(q.workspaceAgentPTYInvites, invite)
+
+	return invite, nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			share.ShareLevel = arg.ShareLevel
+			q.workspaceAgentPortShares[i] = share
+			return share, nil
+		}
+	}
+
+	//nolint:gosimple
+	share := database.WorkspaceAgentPortShare{
+		WorkspaceID: arg.WorkspaceID,
+		AgentName:   arg.AgentName,
+		Port:        arg.Port,
+		ShareLevel:  arg.ShareLevel,
+	}
+	q.workspaceAgentPortShares = append(q.workspaceAgentPortShares, share)
+
+	return share, nil
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -6069,6 +8236,12 @@ func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.G
 		if arg.ExactName != "" && !strings.EqualFold(template.Name, arg.ExactName) {
 			continue
 		}
+		if arg.FuzzyName != "" && !strings.Contains(strings.ToLower(template.Name), strings.ToLower(arg.FuzzyName)) {
+			continue
+		}
+		if arg.Deprecated.Valid && arg.Deprecated.Bool != (template.DeprecationMessage != "") {
+			continue
+		}
 
 		if len(arg.IDs) > 0 {
 			match := false
@@ -62,6 +62,34 @@ func AuditLog(t testing.TB, db database.Store, seed database.AuditLog) database.
 	return log
 }
 
+func ConnectionLog(t testing.TB, db database.Store, seed database.ConnectionLog) database.ConnectionLog {
+	log, err := db.UpsertConnectionLog(genCtx, database.UpsertConnectionLogParams{
+		ID:               takeFirst(seed.ID, uuid.New()),
+		ConnectTime:      takeFirst(seed.ConnectTime, dbtime.Now()),
+		OrganizationID:   takeFirst(seed.OrganizationID, uuid.New()),
+		WorkspaceOwnerID: takeFirst(seed.WorkspaceOwnerID, uuid.New()),
+		WorkspaceID:      takeFirst(seed.WorkspaceID, uuid.New()),
+		WorkspaceName:    takeFirst(seed.WorkspaceName, namesgenerator.GetRandomName(1)),
+		AgentID:          takeFirst(seed.AgentID, uuid.New()),
+		AgentName:        takeFirst(seed.AgentName, "main"),
+		Type:             takeFirst(seed.Type, database.ConnectionTypeSSH),
+		UserID:           seed.UserID,
+		Ip: pqtype.Inet{
+			IPNet: takeFirstIP(seed.Ip.IPNet, net.IPNet{}),
+			Valid: takeFirst(seed.Ip.Valid, false),
+		},
+		UserAgent:        seed.UserAgent,
+		SlugOrPort:       seed.SlugOrPort,
+		Code:             seed.Code,
+		BytesSent:        seed.BytesSent,
+		BytesReceived:    seed.BytesReceived,
+		DisconnectTime:   seed.DisconnectTime,
+		DisconnectReason: seed.DisconnectReason,
+	})
+	require.NoError(t, err, "insert connection log")
+	return log
+}
+
 func Template(t testing.TB, db database.Store, seed database.Template) database.Template {
 	id := takeFirst(seed.ID, uuid.New())
 	err := db.InsertTemplate(genCtx, database.InsertTemplateParams{
@@ -116,6 +144,7 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 		UpdatedAt:       takeFirst(seed.UpdatedAt, dbtime.Now()),
 		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
 		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
+		ScopeRules:      takeFirstSlice(seed.ScopeRules, []byte("[]")),
 		TokenName:       takeFirst(seed.TokenName),
 	})
 	require.NoError(t, err, "insert api key")
@@ -175,6 +204,7 @@ func Workspace(t testing.TB, db database.Store, orig database.Workspace) databas
 		Name:              takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
 		AutostartSchedule: orig.AutostartSchedule,
 		Ttl:               orig.Ttl,
+		AutomaticUpdates:  takeFirst(orig.AutomaticUpdates, database.AutomaticUpdatesNever),
 	})
 	require.NoError(t, err, "insert workspace")
 	return workspace
@@ -214,14 +244,15 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 
 func User(t testing.TB, db database.Store, orig database.User) database.User {
 	user, err := db.InsertUser(genCtx, database.InsertUserParams{
-		ID:             takeFirst(orig.ID, uuid.New()),
-		Email:          takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
-		Username:       takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
-		HashedPassword: takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
-		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
-		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
-		RBACRoles:      takeFirstSlice(orig.RBACRoles, []string{}),
-		LoginType:      takeFirst(orig.LoginType, database.LoginTypePassword),
+		ID:               takeFirst(orig.ID, uuid.New()),
+		Email:            takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
+		Username:         takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
+		HashedPassword:   takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
+		CreatedAt:        takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:        takeFirst(orig.UpdatedAt, dbtime.Now()),
+		RBACRoles:        takeFirstSlice(orig.RBACRoles, []string{}),
+		LoginType:        takeFirst(orig.LoginType, database.LoginTypePassword),
+		IsServiceAccount: orig.IsServiceAccount,
 	})
 	require.NoError(t, err, "insert user")
 
@@ -279,6 +310,22 @@ func OrganizationMember(t testing.TB, db database.Store, orig database.Organizat
 	return mem
 }
 
+func CustomRole(t testing.TB, db database.Store, orig database.CustomRole) database.CustomRole {
+	role, err := db.InsertCustomRole(genCtx, database.InsertCustomRoleParams{
+		ID:              takeFirst(orig.ID, uuid.New()),
+		Name:            takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		DisplayName:     takeFirst(orig.DisplayName, namesgenerator.GetRandomName(1)),
+		OrganizationID:  orig.OrganizationID,
+		SitePermissions: takeFirstSlice(orig.SitePermissions, json.RawMessage("[]")),
+		OrgPermissions:  takeFirstSlice(orig.OrgPermissions, json.RawMessage("[]")),
+		UserPermissions: takeFirstSlice(orig.UserPermissions, json.RawMessage("[]")),
+		CreatedAt:       takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:       takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert custom role")
+	return role
+}
+
 func Group(t testing.TB, db database.Store, orig database.Group) database.Group {
 	name := takeFirst(orig.Name, namesgenerator.GetRandomName(1))
 	group, err := db.InsertGroup(genCtx, database.InsertGroupParams{
@@ -390,12 +437,67 @@ func WorkspaceApp(t testing.TB, db database.Store, orig database.WorkspaceApp) d
 		HealthcheckUrl:       takeFirst(orig.HealthcheckUrl, "https://localhost:8000"),
 		HealthcheckInterval:  takeFirst(orig.HealthcheckInterval, 60),
 		HealthcheckThreshold: takeFirst(orig.HealthcheckThreshold, 60),
+		HealthcheckTCP:       orig.HealthcheckTCP,
+		HealthcheckCommand:   orig.HealthcheckCommand,
 		Health:               takeFirst(orig.Health, database.WorkspaceAppHealthHealthy),
 	})
 	require.NoError(t, err, "insert app")
 	return resource
 }
 
+func WorkspaceAgentPortShare(t testing.TB, db database.Store, orig database.WorkspaceAgentPortShare) database.WorkspaceAgentPortShare {
+	ps, err := db.UpsertWorkspaceAgentPortShare(genCtx, database.UpsertWorkspaceAgentPortShareParams{
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentName:   takeFirst(orig.AgentName, namesgenerator.GetRandomName(1)),
+		Port:        takeFirst(orig.Port, 8080),
+		ShareLevel:  takeFirst(orig.ShareLevel, database.AppSharingLevelPublic),
+	})
+	require.NoError(t, err, "insert workspace agent port share")
+	return ps
+}
+
+func WorkspaceAgentPTYInvite(t testing.TB, db database.Store, orig database.WorkspaceAgentPTYInvite) database.WorkspaceAgentPTYInvite {
+	invite, err := db.UpsertWorkspaceAgentPTYInvite(genCtx, database.UpsertWorkspaceAgentPTYInviteParams{
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentID:     takeFirst(orig.AgentID, uuid.New()),
+		SessionID:   takeFirst(orig.SessionID, uuid.New()),
+		UserID:      takeFirst(orig.UserID, uuid.New()),
+		ReadOnly:    takeFirst(orig.ReadOnly, false),
+		CreatedBy:   takeFirst(orig.CreatedBy, uuid.New()),
+		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace agent pty invite")
+	return invite
+}
+
+func WorkspaceSessionRecording(t testing.TB, db database.Store, orig database.WorkspaceSessionRecording) database.WorkspaceSessionRecording {
+	recording, err := db.InsertWorkspaceSessionRecording(genCtx, database.InsertWorkspaceSessionRecordingParams{
+		ID:            takeFirst(orig.ID, uuid.New()),
+		WorkspaceID:   takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentID:       takeFirst(orig.AgentID, uuid.New()),
+		Type:          takeFirst(orig.Type, database.WorkspaceSessionRecordingTypeSSH),
+		ReconnectID:   orig.ReconnectID,
+		Command:       takeFirst(orig.Command, "bash"),
+		IncludesInput: takeFirst(orig.IncludesInput, false),
+		CreatedAt:     takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:     takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace session recording")
+	return recording
+}
+
+func WorkspaceSessionRecordingChunk(t testing.TB, db database.Store, orig database.WorkspaceSessionRecordingChunk) database.WorkspaceSessionRecordingChunk {
+	chunk := database.WorkspaceSessionRecordingChunk{
+		RecordingID: takeFirst(orig.RecordingID, uuid.New()),
+		ChunkIndex:  takeFirst(orig.ChunkIndex, 0),
+		Data:        takeFirstSlice(orig.Data, []byte(`{"version":2,"width":80,"height":24}`+"\n")),
+		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
+	}
+	_, err := db.InsertWorkspaceSessionRecordingChunk(genCtx, database.InsertWorkspaceSessionRecordingChunkParams(chunk))
+	require.NoError(t, err, "insert workspace session recording chunk")
+	return chunk
+}
+
 func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceResource) database.WorkspaceResource {
 	resource, err := db.InsertWorkspaceResource(genCtx, database.InsertWorkspaceResourceParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -455,6 +557,119 @@ func WorkspaceProxy(t testing.TB, db database.Store, orig database.WorkspaceProx
 	return proxy, secret
 }
 
+func Webhook(t testing.TB, db database.Store, orig database.Webhook) database.Webhook {
+	webhook, err := db.InsertWebhook(genCtx, database.InsertWebhookParams{
+		ID:        takeFirst(orig.ID, uuid.New()),
+		CreatedAt: takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt: takeFirst(orig.UpdatedAt, dbtime.Now()),
+		Name:      takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		Url:       takeFirst(orig.Url, "https://example.com/webhook"),
+		Secret:    takeFirst(orig.Secret, namesgenerator.GetRandomName(1)),
+		Events:    takeFirstSlice(orig.Events, database.AllWebhookEventValues()),
+		Enabled:   orig.Enabled,
+	})
+	require.NoError(t, err, "insert webhook")
+	return webhook
+}
+
+func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
+	app, err := db.InsertOAuth2ProviderApp(genCtx, database.InsertOAuth2ProviderAppParams{
+		ID:           takeFirst(seed.ID, uuid.New()),
+		CreatedAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:    takeFirst(seed.UpdatedAt, dbtime.Now()),
+		Name:         takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		Icon:         takeFirst(seed.Icon, ""),
+		RedirectURIs: takeFirstSlice(seed.RedirectURIs, []string{"https://example.com/callback"}),
+	})
+	require.NoError(t, err, "insert oauth2 app")
+	return app
+}
+
+func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAuth2ProviderAppSecret) database.OAuth2ProviderAppSecret {
+	secret, err := db.InsertOAuth2ProviderAppSecret(genCtx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		AppID:         seed.AppID,
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte(uuid.NewString())),
+		DisplaySecret: takeFirst(seed.DisplaySecret, "secret"),
+	})
+	require.NoError(t, err, "insert oauth2 app secret")
+	return secret
+}
+
+func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
+	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		AppID:         seed.AppID,
+		UserID:        seed.UserID,
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:     takeFirst(seed.ExpiresAt, dbtime.Now().Add(10*time.Minute)),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte(uuid.NewString())),
+		RedirectURI:   takeFirst(seed.RedirectURI, "https://example.com/callback"),
+		Scope:         takeFirst(seed.Scope, "all"),
+		CodeChallenge: takeFirst(seed.CodeChallenge, "challenge"),
+	})
+	require.NoError(t, err, "insert oauth2 app code")
+	return code
+}
+
+func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth2ProviderAppToken) database.OAuth2ProviderAppToken {
+	token, err := db.InsertOAuth2ProviderAppToken(genCtx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		AppID:       seed.AppID,
+		APIKeyID:    seed.APIKeyID,
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:   takeFirst(seed.ExpiresAt, dbtime.Now().Add(30*24*time.Hour)),
+		RefreshHash: takeFirstSlice(seed.RefreshHash, []byte(uuid.NewString())),
+	})
+	require.NoError(t, err, "insert oauth2 app token")
+	return token
+}
+
+func WorkspacePrebuildPool(t testing.TB, db database.Store, orig database.WorkspacePrebuildPool) database.WorkspacePrebuildPool {
+	pool, err := db.InsertWorkspacePrebuildPool(genCtx, database.InsertWorkspacePrebuildPoolParams{
+		ID:                takeFirst(orig.ID, uuid.New()),
+		TemplateID:        takeFirst(orig.TemplateID, uuid.New()),
+		TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
+		Parameters:        takeFirstSlice(orig.Parameters, json.RawMessage("[]")),
+		DesiredInstances:  takeFirst(orig.DesiredInstances, 1),
+		CreatedAt:         takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:         takeFirst(orig.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace prebuild pool")
+	return pool
+}
+
+func WorkspacePrebuild(t testing.TB, db database.Store, orig database.WorkspacePrebuild) database.WorkspacePrebuild {
+	prebuild := database.WorkspacePrebuild{
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		PoolID:      orig.PoolID,
+		CreatedAt:   takeFirst(orig.CreatedAt, dbtime.Now()),
+	}
+	err := db.InsertWorkspacePrebuild(genCtx, database.InsertWorkspacePrebuildParams{
+		WorkspaceID: prebuild.WorkspaceID,
+		PoolID:      prebuild.PoolID,
+		CreatedAt:   prebuild.CreatedAt,
+	})
+	require.NoError(t, err, "insert workspace prebuild")
+	return prebuild
+}
+
+func NotificationMessage(t testing.TB, db database.Store, orig database.NotificationMessage) database.NotificationMessage {
+	message, err := db.InsertNotificationMessage(genCtx, database.InsertNotificationMessageParams{
+		ID:        takeFirst(orig.ID, uuid.New()),
+		UserID:    takeFirst(orig.UserID, uuid.New()),
+		Kind:      takeFirst(orig.Kind, database.NotificationKindWorkspaceAutostop),
+		Title:     takeFirst(orig.Title, namesgenerator.GetRandomName(1)),
+		Content:   takeFirst(orig.Content, namesgenerator.GetRandomName(1)),
+		DedupeKey: orig.DedupeKey,
+		CreatedAt: takeFirst(orig.CreatedAt, dbtime.Now()),
+		Email:     orig.EmailNextAttemptAt.Valid,
+	})
+	require.NoError(t, err, "insert notification message")
+	return message
+}
+
 func File(t testing.TB, db database.Store, orig database.File) database.File {
 	file, err := db.InsertFile(genCtx, database.InsertFileParams{
 		ID:        takeFirst(orig.ID, uuid.New()),
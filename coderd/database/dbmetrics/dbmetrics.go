@@ -86,6 +86,13 @@ func (m metricsStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64)
 	return err
 }
 
+func (m metricsStore) AcquireNotificationMessageEmail(ctx context.Context, arg database.AcquireNotificationMessageEmailParams) (database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireNotificationMessageEmail(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireNotificationMessageEmail").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	provisionerJob, err := m.s.AcquireProvisionerJob(ctx, arg)
@@ -93,6 +100,20 @@ func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.Ac
 	return provisionerJob, err
 }
 
+func (m metricsStore) AcquireWebhookDelivery(ctx context.Context, arg database.AcquireWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireWebhookDelivery(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireWebhookDelivery").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.ClaimPrebuiltWorkspace(ctx, arg)
+	m.queryLatencies.WithLabelValues("ClaimPrebuiltWorkspace").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.CleanTailnetCoordinators(ctx)
@@ -100,11 +121,18 @@ func (m metricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	return err
 }
 
-func (m metricsStore) DeleteAPIKeyByID(ctx context.Context, id string) error {
+func (m metricsStore) CountUnreadNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	start := time.Now()
-	err := m.s.DeleteAPIKeyByID(ctx, id)
+	r0, r1 := m.s.CountUnreadNotificationMessagesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("CountUnreadNotificationMessagesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) DeleteAPIKeyByID(ctx context.Context, id string) (int64, error) {
+	start := time.Now()
+	rows, err := m.s.DeleteAPIKeyByID(ctx, id)
 	m.queryLatencies.WithLabelValues("DeleteAPIKeyByID").Observe(time.Since(start).Seconds())
-	return err
+	return rows, err
 }
 
 func (m metricsStore) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
@@ -127,6 +155,13 @@ func (m metricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error
 	return m.s.DeleteCoordinator(ctx, id)
 }
 
+func (m metricsStore) DeleteCustomRoleByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteCustomRoleByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteCustomRoleByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteGitSSHKey(ctx, userID)
@@ -162,6 +197,62 @@ func (m metricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error
 	return licenseID, err
 }
 
+func (m metricsStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (int64, error) {
+	start := time.Now()
+	rows, err := m.s.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return rows, err
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	start := time.Now()
+	err := m.s.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodesByAppAndUserID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	start := time.Now()
+	err := m.s.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppTokensByAppAndUserID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteOldConnectionLogs(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteOldConnectionLogs(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldConnectionLogs").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteOldNotificationMessages(ctx context.Context) error {
+	start := time.Now()
+	err := m.s.DeleteOldNotificationMessages(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldNotificationMessages").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	start := time.Now()
+	err := m.s.DeleteOldWebhookDeliveries(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldWorkspaceAgentLogs(ctx)
@@ -169,6 +260,13 @@ func (m metricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	return r0
 }
 
+func (m metricsStore) DeleteOldWorkspaceAgentLogsBySourceID(ctx context.Context, arg database.DeleteOldWorkspaceAgentLogsBySourceIDParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentLogsBySourceID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentLogsBySourceID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.DeleteOldWorkspaceAgentStats(ctx)
@@ -195,6 +293,48 @@ func (m metricsStore) DeleteTailnetClient(ctx context.Context, arg database.Dele
 	return m.s.DeleteTailnetClient(ctx, arg)
 }
 
+func (m metricsStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebhookByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteWorkspaceAgentPTYInvite(ctx context.Context, arg database.DeleteWorkspaceAgentPTYInviteParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceAgentPTYInvite(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentPTYInvite").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceAgentPortShare(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentPortSharesByTemplate").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspacePrebuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspacePrebuild, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteWorkspacePrebuildByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePrebuildByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) DeleteWorkspacePrebuildPoolByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteWorkspacePrebuildPoolByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePrebuildPoolByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
@@ -279,6 +419,34 @@ func (m metricsStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid
 	return row, err
 }
 
+func (m metricsStore) GetConnectionLogsOffset(ctx context.Context, arg database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetConnectionLogsOffset(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetConnectionLogsOffset").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoleByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetCustomRoleByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRoles(ctx context.Context) ([]database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoles(ctx)
+	m.queryLatencies.WithLabelValues("GetCustomRoles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRolesByName(ctx context.Context, lookupRoles []string) ([]database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRolesByName(ctx, lookupRoles)
+	m.queryLatencies.WithLabelValues("GetCustomRolesByName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDBCryptKeys(ctx)
@@ -454,6 +622,97 @@ func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m metricsStore) GetNotificationMessageByID(ctx context.Context, id uuid.UUID) (database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationMessageByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetNotificationMessageByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetNotificationMessagesByUserID(ctx context.Context, arg database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationMessagesByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetNotificationMessagesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationPreferencesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetNotificationPreferencesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByHashedSecret(ctx, hashedSecret)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByHashedSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretByHashedSecret(ctx, hashedSecret)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByHashedSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretsByAppID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKeyID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByAPIKeyID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppTokenByRefreshHash(ctx context.Context, refreshHash []byte) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppTokenByRefreshHash(ctx, refreshHash)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByRefreshHash").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderApps(ctx)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderApps").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuthSigningKey(ctx)
@@ -697,6 +956,13 @@ func (m metricsStore) GetTemplateVersionParameters(ctx context.Context, template
 	return parameters, err
 }
 
+func (m metricsStore) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPresets(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPresets").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variables, err := m.s.GetTemplateVersionVariables(ctx, templateVersionID)
@@ -809,6 +1075,27 @@ func (m metricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]dat
 	return users, err
 }
 
+func (m metricsStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveriesByWebhookID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveriesByWebhookID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhooks(ctx)
+	m.queryLatencies.WithLabelValues("GetWebhooks").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentAndOwnerByAuthToken(ctx, authToken)
@@ -837,6 +1124,20 @@ func (m metricsStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, i
 	return r0, r1
 }
 
+func (m metricsStore) GetWorkspaceAgentLogFilesByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentLogFile, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentLogFilesByAgentID(ctx, workspaceAgentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLogFilesByAgentID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLogSourcesByAgentIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentLogsAfterParams) ([]database.WorkspaceAgentLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLogsAfter(ctx, arg)
@@ -851,6 +1152,48 @@ func (m metricsStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAg
 	return metadata, err
 }
 
+func (m metricsStore) GetWorkspaceAgentPTYInvite(ctx context.Context, arg database.GetWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPTYInvite(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPTYInvite").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentPTYInvitesBySessionID(ctx context.Context, arg database.GetWorkspaceAgentPTYInvitesBySessionIDParams) ([]database.WorkspaceAgentPTYInvite, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPTYInvitesBySessionID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPTYInvitesBySessionID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentPTYInvitesByUserID(ctx context.Context, userID uuid.UUID) ([]database.WorkspaceAgentPTYInvite, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPTYInvitesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPTYInvitesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPortShare(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentScriptByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentScriptByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentScriptByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentScriptsByAgentIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	start := time.Now()
 	stats, err := m.s.GetWorkspaceAgentStats(ctx, createdAt)
@@ -984,6 +1327,34 @@ func (m metricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspac
 	return workspace, err
 }
 
+func (m metricsStore) GetWorkspacePrebuildPoolByID(ctx context.Context, id uuid.UUID) (database.WorkspacePrebuildPool, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePrebuildPoolByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspacePrebuildPoolByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspacePrebuildPools(ctx context.Context) ([]database.WorkspacePrebuildPool, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePrebuildPools(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspacePrebuildPools").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspacePrebuildPoolsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspacePrebuildPool, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePrebuildPoolsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetWorkspacePrebuildPoolsByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspacePrebuilds(ctx context.Context) ([]database.WorkspacePrebuild, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePrebuilds(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspacePrebuilds").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxies, err := m.s.GetWorkspaceProxies(ctx)
@@ -1054,6 +1425,27 @@ func (m metricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context, cre
 	return resources, err
 }
 
+func (m metricsStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingChunks(ctx, recordingID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingChunks").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingsByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspaces(ctx, arg)
@@ -1061,6 +1453,13 @@ func (m metricsStore) GetWorkspaces(ctx context.Context, arg database.GetWorkspa
 	return workspaces, err
 }
 
+func (m metricsStore) GetWorkspacesApproachingAutostop(ctx context.Context, arg database.GetWorkspacesApproachingAutostopParams) ([]database.GetWorkspacesApproachingAutostopRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacesApproachingAutostop(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspacesApproachingAutostop").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspacesEligibleForTransition(ctx, now)
@@ -1089,6 +1488,13 @@ func (m metricsStore) InsertAuditLog(ctx context.Context, arg database.InsertAud
 	return log, err
 }
 
+func (m metricsStore) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertCustomRole(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertCustomRole").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
 	start := time.Now()
 	r0 := m.s.InsertDBCryptKey(ctx, arg)
@@ -1159,6 +1565,41 @@ func (m metricsStore) InsertMissingGroups(ctx context.Context, arg database.Inse
 	return r0, r1
 }
 
+func (m metricsStore) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertNotificationMessage(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertNotificationMessage").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderApp(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderApp").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
@@ -1222,6 +1663,13 @@ func (m metricsStore) InsertTemplateVersionParameter(ctx context.Context, arg da
 	return parameter, err
 }
 
+func (m metricsStore) InsertTemplateVersionPreset(ctx context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateVersionPreset(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVersionPreset").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variable, err := m.s.InsertTemplateVersionVariable(ctx, arg)
@@ -1250,6 +1698,20 @@ func (m metricsStore) InsertUserLink(ctx context.Context, arg database.InsertUse
 	return link, err
 }
 
+func (m metricsStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhook").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWebhookDeliveries(ctx context.Context, arg database.InsertWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.InsertWorkspace(ctx, arg)
@@ -1264,6 +1726,20 @@ func (m metricsStore) InsertWorkspaceAgent(ctx context.Context, arg database.Ins
 	return agent, err
 }
 
+func (m metricsStore) InsertWorkspaceAgentLogFiles(ctx context.Context, arg database.InsertWorkspaceAgentLogFilesParams) ([]database.WorkspaceAgentLogFile, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentLogFiles(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentLogFiles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentLogSources(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentLogSources").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceAgentLogs(ctx context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentLogs(ctx, arg)
@@ -1278,6 +1754,13 @@ func (m metricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentScripts(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentScripts").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceAgentStat(ctx context.Context, arg database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	start := time.Now()
 	stat, err := m.s.InsertWorkspaceAgentStat(ctx, arg)
@@ -1320,6 +1803,20 @@ func (m metricsStore) InsertWorkspaceBuildParameters(ctx context.Context, arg da
 	return err
 }
 
+func (m metricsStore) InsertWorkspacePrebuild(ctx context.Context, arg database.InsertWorkspacePrebuildParams) error {
+	start := time.Now()
+	err := m.s.InsertWorkspacePrebuild(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspacePrebuild").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) InsertWorkspacePrebuildPool(ctx context.Context, arg database.InsertWorkspacePrebuildPoolParams) (database.WorkspacePrebuildPool, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspacePrebuildPool(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspacePrebuildPool").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.InsertWorkspaceProxy(ctx, arg)
@@ -1341,6 +1838,34 @@ func (m metricsStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg d
 	return metadata, err
 }
 
+func (m metricsStore) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSessionRecording(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSessionRecording").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	start := time.Now()
+	rows, err := m.s.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSessionRecordingChunk").Observe(time.Since(start).Seconds())
+	return rows, err
+}
+
+func (m metricsStore) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.ListWorkspaceAgentPortShares(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("ListWorkspaceAgentPortShares").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx, templateID)
+	m.queryLatencies.WithLabelValues("ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.RegisterWorkspaceProxy(ctx, arg)
@@ -1369,6 +1894,13 @@ func (m metricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateA
 	return err
 }
 
+func (m metricsStore) UpdateCustomRoleByID(ctx context.Context, arg database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateCustomRoleByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateCustomRoleByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateGitAuthLink(ctx, arg)
@@ -1404,6 +1936,41 @@ func (m metricsStore) UpdateMemberRoles(ctx context.Context, arg database.Update
 	return member, err
 }
 
+func (m metricsStore) UpdateNotificationMessageEmailByID(ctx context.Context, arg database.UpdateNotificationMessageEmailByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateNotificationMessageEmailByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateNotificationMessageEmailByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) UpdateNotificationMessageReadAtByID(ctx context.Context, arg database.UpdateNotificationMessageReadAtByIDParams) (database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateNotificationMessageReadAtByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateNotificationMessageReadAtByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateNotificationMessagesReadAtByUserID(ctx context.Context, arg database.UpdateNotificationMessagesReadAtByUserIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateNotificationMessagesReadAtByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateNotificationMessagesReadAtByUserID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobByID(ctx, arg)
@@ -1565,6 +2132,20 @@ func (m metricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateU
 	return user, err
 }
 
+func (m metricsStore) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWebhookByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateWebhookDeliveryByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookDeliveryByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.UpdateWorkspace(ctx, arg)
@@ -1572,6 +2153,13 @@ func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWo
 	return workspace, err
 }
 
+func (m metricsStore) UpdateWorkspaceAgentAutostopInhibitorsByID(ctx context.Context, arg database.UpdateWorkspaceAgentAutostopInhibitorsByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentAutostopInhibitorsByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentAutostopInhibitorsByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
@@ -1600,6 +2188,13 @@ func (m metricsStore) UpdateWorkspaceAgentMetadata(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceAgentScriptStatusByID(ctx context.Context, arg database.UpdateWorkspaceAgentScriptStatusByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentScriptStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentScriptStatusByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentStartupByID(ctx, arg)
@@ -1607,6 +2202,13 @@ func (m metricsStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg d
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceAgentUpdateStateByID(ctx context.Context, arg database.UpdateWorkspaceAgentUpdateStateByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentUpdateStateByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentUpdateStateByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceAppHealthByID(ctx context.Context, arg database.UpdateWorkspaceAppHealthByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAppHealthByID(ctx, arg)
@@ -1614,6 +2216,13 @@ func (m metricsStore) UpdateWorkspaceAppHealthByID(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	start := time.Now()
+	err := m.s.UpdateWorkspaceAutomaticUpdates(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAutomaticUpdates").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) UpdateWorkspaceAutostart(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAutostart(ctx, arg)
@@ -1656,6 +2265,13 @@ func (m metricsStore) UpdateWorkspaceLastUsedAt(ctx context.Context, arg databas
 	return err
 }
 
+func (m metricsStore) UpdateWorkspacePrebuildPoolByID(ctx context.Context, arg database.UpdateWorkspacePrebuildPoolByIDParams) (database.WorkspacePrebuildPool, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspacePrebuildPoolByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspacePrebuildPoolByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.UpdateWorkspaceProxy(ctx, arg)
@@ -1670,6 +2286,13 @@ func (m metricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg datab
 	return r0
 }
 
+func (m metricsStore) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingByIDParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceSessionRecordingByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceTTL(ctx, arg)
@@ -1691,6 +2314,13 @@ func (m metricsStore) UpsertAppSecurityKey(ctx context.Context, value string) er
 	return r0
 }
 
+func (m metricsStore) UpsertConnectionLog(ctx context.Context, arg database.UpsertConnectionLogParams) (database.ConnectionLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertConnectionLog(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertConnectionLog").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertDefaultProxy(ctx, arg)
@@ -1712,6 +2342,13 @@ func (m metricsStore) UpsertLogoURL(ctx context.Context, value string) error {
 	return r0
 }
 
+func (m metricsStore) UpsertNotificationPreference(ctx context.Context, arg database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertNotificationPreference(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertNotificationPreference").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpsertOAuthSigningKey(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertOAuthSigningKey(ctx, value)
@@ -1744,6 +2381,20 @@ func (m metricsStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID
 	return m.s.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (m metricsStore) UpsertWorkspaceAgentPTYInvite(ctx context.Context, arg database.UpsertWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceAgentPTYInvite(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentPTYInvite").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceAgentPortShare(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
@@ -53,6 +53,21 @@ func (mr *MockStoreMockRecorder) AcquireLock(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLock", reflect.TypeOf((*MockStore)(nil).AcquireLock), arg0, arg1)
 }
 
+// AcquireNotificationMessageEmail mocks base method.
+func (m *MockStore) AcquireNotificationMessageEmail(arg0 context.Context, arg1 database.AcquireNotificationMessageEmailParams) (database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireNotificationMessageEmail", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireNotificationMessageEmail indicates an expected call of AcquireNotificationMessageEmail.
+func (mr *MockStoreMockRecorder) AcquireNotificationMessageEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireNotificationMessageEmail", reflect.TypeOf((*MockStore)(nil).AcquireNotificationMessageEmail), arg0, arg1)
+}
+
 // AcquireProvisionerJob mocks base method.
 func (m *MockStore) AcquireProvisionerJob(arg0 context.Context, arg1 database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -68,6 +83,36 @@ func (mr *MockStoreMockRecorder) AcquireProvisionerJob(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireProvisionerJob", reflect.TypeOf((*MockStore)(nil).AcquireProvisionerJob), arg0, arg1)
 }
 
+// AcquireWebhookDelivery mocks base method.
+func (m *MockStore) AcquireWebhookDelivery(arg0 context.Context, arg1 database.AcquireWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireWebhookDelivery indicates an expected call of AcquireWebhookDelivery.
+func (mr *MockStoreMockRecorder) AcquireWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireWebhookDelivery", reflect.TypeOf((*MockStore)(nil).AcquireWebhookDelivery), arg0, arg1)
+}
+
+// ClaimPrebuiltWorkspace mocks base method.
+func (m *MockStore) ClaimPrebuiltWorkspace(arg0 context.Context, arg1 database.ClaimPrebuiltWorkspaceParams) (database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPrebuiltWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPrebuiltWorkspace indicates an expected call of ClaimPrebuiltWorkspace.
+func (mr *MockStoreMockRecorder) ClaimPrebuiltWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPrebuiltWorkspace", reflect.TypeOf((*MockStore)(nil).ClaimPrebuiltWorkspace), arg0, arg1)
+}
+
 // CleanTailnetCoordinators mocks base method.
 func (m *MockStore) CleanTailnetCoordinators(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -82,12 +127,28 @@ func (mr *MockStoreMockRecorder) CleanTailnetCoordinators(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanTailnetCoordinators", reflect.TypeOf((*MockStore)(nil).CleanTailnetCoordinators), arg0)
 }
 
+// CountUnreadNotificationMessagesByUserID mocks base method.
+func (m *MockStore) CountUnreadNotificationMessagesByUserID(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotificationMessagesByUserID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotificationMessagesByUserID indicates an expected call of CountUnreadNotificationMessagesByUserID.
+func (mr *MockStoreMockRecorder) CountUnreadNotificationMessagesByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotificationMessagesByUserID", reflect.TypeOf((*MockStore)(nil).CountUnreadNotificationMessagesByUserID), arg0, arg1)
+}
+
 // DeleteAPIKeyByID mocks base method.
-func (m *MockStore) DeleteAPIKeyByID(arg0 context.Context, arg1 string) error {
+func (m *MockStore) DeleteAPIKeyByID(arg0 context.Context, arg1 string) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteAPIKeyByID", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteAPIKeyByID indicates an expected call of DeleteAPIKeyByID.
@@ -138,6 +199,20 @@ func (mr *MockStoreMockRecorder) DeleteCoordinator(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoordinator", reflect.TypeOf((*MockStore)(nil).DeleteCoordinator), arg0, arg1)
 }
 
+// DeleteCustomRoleByID mocks base method.
+func (m *MockStore) DeleteCustomRoleByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCustomRoleByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCustomRoleByID indicates an expected call of DeleteCustomRoleByID.
+func (mr *MockStoreMockRecorder) DeleteCustomRoleByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomRoleByID", reflect.TypeOf((*MockStore)(nil).DeleteCustomRoleByID), arg0, arg1)
+}
+
 // DeleteGitSSHKey mocks base method.
 func (m *MockStore) DeleteGitSSHKey(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -209,6 +284,119 @@ func (mr *MockStoreMockRecorder) DeleteLicense(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLicense", reflect.TypeOf((*MockStore)(nil).DeleteLicense), arg0, arg1)
 }
 
+// DeleteOAuth2ProviderAppByID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2ProviderAppByID indicates an expected call of DeleteOAuth2ProviderAppByID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppByID), arg0, arg1)
+}
+
+// DeleteOAuth2ProviderAppCodeByID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppCodeByID(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppCodeByID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOAuth2ProviderAppCodeByID indicates an expected call of DeleteOAuth2ProviderAppCodeByID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppCodeByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppCodeByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppCodeByID), arg0, arg1)
+}
+
+// DeleteOAuth2ProviderAppCodesByAppAndUserID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppCodesByAppAndUserID(arg0 context.Context, arg1 database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppCodesByAppAndUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2ProviderAppCodesByAppAndUserID indicates an expected call of DeleteOAuth2ProviderAppCodesByAppAndUserID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppCodesByAppAndUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppCodesByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppCodesByAppAndUserID), arg0, arg1)
+}
+
+// DeleteOAuth2ProviderAppSecretByID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppSecretByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppSecretByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2ProviderAppSecretByID indicates an expected call of DeleteOAuth2ProviderAppSecretByID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppSecretByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppSecretByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppSecretByID), arg0, arg1)
+}
+
+// DeleteOAuth2ProviderAppTokensByAppAndUserID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(arg0 context.Context, arg1 database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppTokensByAppAndUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2ProviderAppTokensByAppAndUserID indicates an expected call of DeleteOAuth2ProviderAppTokensByAppAndUserID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppTokensByAppAndUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppTokensByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppTokensByAppAndUserID), arg0, arg1)
+}
+
+// DeleteOldConnectionLogs mocks base method.
+func (m *MockStore) DeleteOldConnectionLogs(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldConnectionLogs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldConnectionLogs indicates an expected call of DeleteOldConnectionLogs.
+func (mr *MockStoreMockRecorder) DeleteOldConnectionLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldConnectionLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldConnectionLogs), arg0, arg1)
+}
+
+// DeleteOldNotificationMessages mocks base method.
+func (m *MockStore) DeleteOldNotificationMessages(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldNotificationMessages", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldNotificationMessages indicates an expected call of DeleteOldNotificationMessages.
+func (mr *MockStoreMockRecorder) DeleteOldNotificationMessages(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldNotificationMessages", reflect.TypeOf((*MockStore)(nil).DeleteOldNotificationMessages), arg0)
+}
+
+// DeleteOldWebhookDeliveries mocks base method.
+func (m *MockStore) DeleteOldWebhookDeliveries(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWebhookDeliveries", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWebhookDeliveries indicates an expected call of DeleteOldWebhookDeliveries.
+func (mr *MockStoreMockRecorder) DeleteOldWebhookDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).DeleteOldWebhookDeliveries), arg0)
+}
+
 // DeleteOldWorkspaceAgentLogs mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentLogs(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -223,6 +411,20 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentLogs(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentLogs), arg0)
 }
 
+// DeleteOldWorkspaceAgentLogsBySourceID mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentLogsBySourceID(arg0 context.Context, arg1 database.DeleteOldWorkspaceAgentLogsBySourceIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentLogsBySourceID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentLogsBySourceID indicates an expected call of DeleteOldWorkspaceAgentLogsBySourceID.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentLogsBySourceID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentLogsBySourceID", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentLogsBySourceID), arg0, arg1)
+}
+
 // DeleteOldWorkspaceAgentStats mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentStats(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -281,6 +483,91 @@ func (mr *MockStoreMockRecorder) DeleteTailnetClient(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTailnetClient", reflect.TypeOf((*MockStore)(nil).DeleteTailnetClient), arg0, arg1)
 }
 
+// DeleteWebhookByID mocks base method.
+func (m *MockStore) DeleteWebhookByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookByID indicates an expected call of DeleteWebhookByID.
+func (mr *MockStoreMockRecorder) DeleteWebhookByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookByID", reflect.TypeOf((*MockStore)(nil).DeleteWebhookByID), arg0, arg1)
+}
+
+// DeleteWorkspaceAgentPTYInvite mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentPTYInvite(arg0 context.Context, arg1 database.DeleteWorkspaceAgentPTYInviteParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPTYInvite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentPTYInvite indicates an expected call of DeleteWorkspaceAgentPTYInvite.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPTYInvite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPTYInvite", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPTYInvite), arg0, arg1)
+}
+
+// DeleteWorkspaceAgentPortShare mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentPortShare(arg0 context.Context, arg1 database.DeleteWorkspaceAgentPortShareParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPortShare", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentPortShare indicates an expected call of DeleteWorkspaceAgentPortShare.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortShare(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortShare), arg0, arg1)
+}
+
+// DeleteWorkspaceAgentPortSharesByTemplate mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentPortSharesByTemplate(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPortSharesByTemplate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentPortSharesByTemplate indicates an expected call of DeleteWorkspaceAgentPortSharesByTemplate.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), arg0, arg1)
+}
+
+// DeleteWorkspacePrebuildByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspacePrebuildByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspacePrebuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePrebuildByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspacePrebuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWorkspacePrebuildByWorkspaceID indicates an expected call of DeleteWorkspacePrebuildByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePrebuildByWorkspaceID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePrebuildByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePrebuildByWorkspaceID), arg0, arg1)
+}
+
+// DeleteWorkspacePrebuildPoolByID mocks base method.
+func (m *MockStore) DeleteWorkspacePrebuildPoolByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePrebuildPoolByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePrebuildPoolByID indicates an expected call of DeleteWorkspacePrebuildPoolByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePrebuildPoolByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePrebuildPoolByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePrebuildPoolByID), arg0, arg1)
+}
+
 // GetAPIKeyByID mocks base method.
 func (m *MockStore) GetAPIKeyByID(arg0 context.Context, arg1 string) (database.APIKey, error) {
 	m.ctrl.T.Helper()
@@ -506,6 +793,66 @@ func (mr *MockStoreMockRecorder) GetAuthorizedWorkspaces(arg0, arg1, arg2 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizedWorkspaces", reflect.TypeOf((*MockStore)(nil).GetAuthorizedWorkspaces), arg0, arg1, arg2)
 }
 
+// GetConnectionLogsOffset mocks base method.
+func (m *MockStore) GetConnectionLogsOffset(arg0 context.Context, arg1 database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnectionLogsOffset", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetConnectionLogsOffsetRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConnectionLogsOffset indicates an expected call of GetConnectionLogsOffset.
+func (mr *MockStoreMockRecorder) GetConnectionLogsOffset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnectionLogsOffset", reflect.TypeOf((*MockStore)(nil).GetConnectionLogsOffset), arg0, arg1)
+}
+
+// GetCustomRoleByID mocks base method.
+func (m *MockStore) GetCustomRoleByID(arg0 context.Context, arg1 uuid.UUID) (database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRoleByID", arg0, arg1)
+	ret0, _ := ret[0].(database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRoleByID indicates an expected call of GetCustomRoleByID.
+func (mr *MockStoreMockRecorder) GetCustomRoleByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRoleByID", reflect.TypeOf((*MockStore)(nil).GetCustomRoleByID), arg0, arg1)
+}
+
+// GetCustomRoles mocks base method.
+func (m *MockStore) GetCustomRoles(arg0 context.Context) ([]database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRoles", arg0)
+	ret0, _ := ret[0].([]database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRoles indicates an expected call of GetCustomRoles.
+func (mr *MockStoreMockRecorder) GetCustomRoles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRoles", reflect.TypeOf((*MockStore)(nil).GetCustomRoles), arg0)
+}
+
+// GetCustomRolesByName mocks base method.
+func (m *MockStore) GetCustomRolesByName(arg0 context.Context, arg1 []string) ([]database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRolesByName", arg0, arg1)
+	ret0, _ := ret[0].([]database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRolesByName indicates an expected call of GetCustomRolesByName.
+func (mr *MockStoreMockRecorder) GetCustomRolesByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRolesByName", reflect.TypeOf((*MockStore)(nil).GetCustomRolesByName), arg0, arg1)
+}
+
 // GetDBCryptKeys mocks base method.
 func (m *MockStore) GetDBCryptKeys(arg0 context.Context) ([]database.DBCryptKey, error) {
 	m.ctrl.T.Helper()
@@ -809,76 +1156,271 @@ func (mr *MockStoreMockRecorder) GetLatestWorkspaceBuildByWorkspaceID(arg0, arg1
 // GetLatestWorkspaceBuilds mocks base method.
 func (m *MockStore) GetLatestWorkspaceBuilds(arg0 context.Context) ([]database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestWorkspaceBuilds", arg0)
-	ret0, _ := ret[0].([]database.WorkspaceBuild)
+	ret := m.ctrl.Call(m, "GetLatestWorkspaceBuilds", arg0)
+	ret0, _ := ret[0].([]database.WorkspaceBuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestWorkspaceBuilds indicates an expected call of GetLatestWorkspaceBuilds.
+func (mr *MockStoreMockRecorder) GetLatestWorkspaceBuilds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceBuilds", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceBuilds), arg0)
+}
+
+// GetLatestWorkspaceBuildsByWorkspaceIDs mocks base method.
+func (m *MockStore) GetLatestWorkspaceBuildsByWorkspaceIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceBuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestWorkspaceBuildsByWorkspaceIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceBuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestWorkspaceBuildsByWorkspaceIDs indicates an expected call of GetLatestWorkspaceBuildsByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetLatestWorkspaceBuildsByWorkspaceIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceBuildsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceBuildsByWorkspaceIDs), arg0, arg1)
+}
+
+// GetLicenseByID mocks base method.
+func (m *MockStore) GetLicenseByID(arg0 context.Context, arg1 int32) (database.License, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicenseByID", arg0, arg1)
+	ret0, _ := ret[0].(database.License)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLicenseByID indicates an expected call of GetLicenseByID.
+func (mr *MockStoreMockRecorder) GetLicenseByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseByID", reflect.TypeOf((*MockStore)(nil).GetLicenseByID), arg0, arg1)
+}
+
+// GetLicenses mocks base method.
+func (m *MockStore) GetLicenses(arg0 context.Context) ([]database.License, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicenses", arg0)
+	ret0, _ := ret[0].([]database.License)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLicenses indicates an expected call of GetLicenses.
+func (mr *MockStoreMockRecorder) GetLicenses(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenses", reflect.TypeOf((*MockStore)(nil).GetLicenses), arg0)
+}
+
+// GetLogoURL mocks base method.
+func (m *MockStore) GetLogoURL(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogoURL", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogoURL indicates an expected call of GetLogoURL.
+func (mr *MockStoreMockRecorder) GetLogoURL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), arg0)
+}
+
+// GetNotificationMessageByID mocks base method.
+func (m *MockStore) GetNotificationMessageByID(arg0 context.Context, arg1 uuid.UUID) (database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationMessageByID", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationMessageByID indicates an expected call of GetNotificationMessageByID.
+func (mr *MockStoreMockRecorder) GetNotificationMessageByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationMessageByID", reflect.TypeOf((*MockStore)(nil).GetNotificationMessageByID), arg0, arg1)
+}
+
+// GetNotificationMessagesByUserID mocks base method.
+func (m *MockStore) GetNotificationMessagesByUserID(arg0 context.Context, arg1 database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationMessagesByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationMessagesByUserID indicates an expected call of GetNotificationMessagesByUserID.
+func (mr *MockStoreMockRecorder) GetNotificationMessagesByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationMessagesByUserID", reflect.TypeOf((*MockStore)(nil).GetNotificationMessagesByUserID), arg0, arg1)
+}
+
+// GetNotificationPreferencesByUserID mocks base method.
+func (m *MockStore) GetNotificationPreferencesByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferencesByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferencesByUserID indicates an expected call of GetNotificationPreferencesByUserID.
+func (mr *MockStoreMockRecorder) GetNotificationPreferencesByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferencesByUserID", reflect.TypeOf((*MockStore)(nil).GetNotificationPreferencesByUserID), arg0, arg1)
+}
+
+// GetOAuth2ProviderAppByID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderApp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderApp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppByID indicates an expected call of GetOAuth2ProviderAppByID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppByID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppByID), arg0, arg1)
+}
+
+// GetOAuth2ProviderAppCodeByHashedSecret mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppCodeByHashedSecret(arg0 context.Context, arg1 []byte) (database.OAuth2ProviderAppCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppCodeByHashedSecret", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppCodeByHashedSecret indicates an expected call of GetOAuth2ProviderAppCodeByHashedSecret.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppCodeByHashedSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppCodeByHashedSecret", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppCodeByHashedSecret), arg0, arg1)
+}
+
+// GetOAuth2ProviderAppCodeByID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppCodeByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppCodeByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppCodeByID indicates an expected call of GetOAuth2ProviderAppCodeByID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppCodeByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppCodeByID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppCodeByID), arg0, arg1)
+}
+
+// GetOAuth2ProviderAppSecretByHashedSecret mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppSecretByHashedSecret(arg0 context.Context, arg1 []byte) (database.OAuth2ProviderAppSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppSecretByHashedSecret", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppSecretByHashedSecret indicates an expected call of GetOAuth2ProviderAppSecretByHashedSecret.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppSecretByHashedSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppSecretByHashedSecret", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppSecretByHashedSecret), arg0, arg1)
+}
+
+// GetOAuth2ProviderAppSecretByID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppSecretByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppSecretByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppSecretByID indicates an expected call of GetOAuth2ProviderAppSecretByID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppSecretByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppSecretByID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppSecretByID), arg0, arg1)
+}
+
+// GetOAuth2ProviderAppSecretsByAppID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppSecretsByAppID(arg0 context.Context, arg1 uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppSecretsByAppID", arg0, arg1)
+	ret0, _ := ret[0].([]database.OAuth2ProviderAppSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLatestWorkspaceBuilds indicates an expected call of GetLatestWorkspaceBuilds.
-func (mr *MockStoreMockRecorder) GetLatestWorkspaceBuilds(arg0 interface{}) *gomock.Call {
+// GetOAuth2ProviderAppSecretsByAppID indicates an expected call of GetOAuth2ProviderAppSecretsByAppID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppSecretsByAppID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceBuilds", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceBuilds), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppSecretsByAppID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppSecretsByAppID), arg0, arg1)
 }
 
-// GetLatestWorkspaceBuildsByWorkspaceIDs mocks base method.
-func (m *MockStore) GetLatestWorkspaceBuildsByWorkspaceIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceBuild, error) {
+// GetOAuth2ProviderAppTokenByAPIKeyID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppTokenByAPIKeyID(arg0 context.Context, arg1 string) (database.OAuth2ProviderAppToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestWorkspaceBuildsByWorkspaceIDs", arg0, arg1)
-	ret0, _ := ret[0].([]database.WorkspaceBuild)
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppTokenByAPIKeyID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLatestWorkspaceBuildsByWorkspaceIDs indicates an expected call of GetLatestWorkspaceBuildsByWorkspaceIDs.
-func (mr *MockStoreMockRecorder) GetLatestWorkspaceBuildsByWorkspaceIDs(arg0, arg1 interface{}) *gomock.Call {
+// GetOAuth2ProviderAppTokenByAPIKeyID indicates an expected call of GetOAuth2ProviderAppTokenByAPIKeyID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppTokenByAPIKeyID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceBuildsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceBuildsByWorkspaceIDs), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppTokenByAPIKeyID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppTokenByAPIKeyID), arg0, arg1)
 }
 
-// GetLicenseByID mocks base method.
-func (m *MockStore) GetLicenseByID(arg0 context.Context, arg1 int32) (database.License, error) {
+// GetOAuth2ProviderAppTokenByRefreshHash mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppTokenByRefreshHash(arg0 context.Context, arg1 []byte) (database.OAuth2ProviderAppToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLicenseByID", arg0, arg1)
-	ret0, _ := ret[0].(database.License)
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppTokenByRefreshHash", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLicenseByID indicates an expected call of GetLicenseByID.
-func (mr *MockStoreMockRecorder) GetLicenseByID(arg0, arg1 interface{}) *gomock.Call {
+// GetOAuth2ProviderAppTokenByRefreshHash indicates an expected call of GetOAuth2ProviderAppTokenByRefreshHash.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppTokenByRefreshHash(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseByID", reflect.TypeOf((*MockStore)(nil).GetLicenseByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppTokenByRefreshHash", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppTokenByRefreshHash), arg0, arg1)
 }
 
-// GetLicenses mocks base method.
-func (m *MockStore) GetLicenses(arg0 context.Context) ([]database.License, error) {
+// GetOAuth2ProviderApps mocks base method.
+func (m *MockStore) GetOAuth2ProviderApps(arg0 context.Context) ([]database.OAuth2ProviderApp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLicenses", arg0)
-	ret0, _ := ret[0].([]database.License)
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderApps", arg0)
+	ret0, _ := ret[0].([]database.OAuth2ProviderApp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLicenses indicates an expected call of GetLicenses.
-func (mr *MockStoreMockRecorder) GetLicenses(arg0 interface{}) *gomock.Call {
+// GetOAuth2ProviderApps indicates an expected call of GetOAuth2ProviderApps.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderApps(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenses", reflect.TypeOf((*MockStore)(nil).GetLicenses), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderApps", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderApps), arg0)
 }
 
-// GetLogoURL mocks base method.
-func (m *MockStore) GetLogoURL(arg0 context.Context) (string, error) {
+// GetOAuth2ProviderAppsByUserID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.OAuth2ProviderApp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLogoURL", arg0)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.OAuth2ProviderApp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLogoURL indicates an expected call of GetLogoURL.
-func (mr *MockStoreMockRecorder) GetLogoURL(arg0 interface{}) *gomock.Call {
+// GetOAuth2ProviderAppsByUserID indicates an expected call of GetOAuth2ProviderAppsByUserID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppsByUserID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppsByUserID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppsByUserID), arg0, arg1)
 }
 
 // GetOAuthSigningKey mocks base method.
@@ -1436,6 +1978,21 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionParameters(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionParameters), arg0, arg1)
 }
 
+// GetTemplateVersionPresets mocks base method.
+func (m *MockStore) GetTemplateVersionPresets(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPresets", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPresets indicates an expected call of GetTemplateVersionPresets.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPresets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPresets", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPresets), arg0, arg1)
+}
+
 // GetTemplateVersionVariables mocks base method.
 func (m *MockStore) GetTemplateVersionVariables(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -1676,6 +2233,51 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), arg0, arg1)
 }
 
+// GetWebhookByID mocks base method.
+func (m *MockStore) GetWebhookByID(arg0 context.Context, arg1 uuid.UUID) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockStoreMockRecorder) GetWebhookByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockStore)(nil).GetWebhookByID), arg0, arg1)
+}
+
+// GetWebhookDeliveriesByWebhookID mocks base method.
+func (m *MockStore) GetWebhookDeliveriesByWebhookID(arg0 context.Context, arg1 database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveriesByWebhookID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveriesByWebhookID indicates an expected call of GetWebhookDeliveriesByWebhookID.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveriesByWebhookID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesByWebhookID", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveriesByWebhookID), arg0, arg1)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockStore) GetWebhooks(arg0 context.Context) ([]database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", arg0)
+	ret0, _ := ret[0].([]database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockStoreMockRecorder) GetWebhooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockStore)(nil).GetWebhooks), arg0)
+}
+
 // GetWorkspaceAgentAndOwnerByAuthToken mocks base method.
 func (m *MockStore) GetWorkspaceAgentAndOwnerByAuthToken(arg0 context.Context, arg1 uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	m.ctrl.T.Helper()
@@ -1736,6 +2338,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentLifecycleStateByID(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentLifecycleStateByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentLifecycleStateByID), arg0, arg1)
 }
 
+// GetWorkspaceAgentLogFilesByAgentID mocks base method.
+func (m *MockStore) GetWorkspaceAgentLogFilesByAgentID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceAgentLogFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentLogFilesByAgentID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentLogFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentLogFilesByAgentID indicates an expected call of GetWorkspaceAgentLogFilesByAgentID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentLogFilesByAgentID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentLogFilesByAgentID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentLogFilesByAgentID), arg0, arg1)
+}
+
+// GetWorkspaceAgentLogSourcesByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentLogSourcesByAgentIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentLogSourcesByAgentIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentLogSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentLogSourcesByAgentIDs indicates an expected call of GetWorkspaceAgentLogSourcesByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentLogSourcesByAgentIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentLogSourcesByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentLogSourcesByAgentIDs), arg0, arg1)
+}
+
 // GetWorkspaceAgentLogsAfter mocks base method.
 func (m *MockStore) GetWorkspaceAgentLogsAfter(arg0 context.Context, arg1 database.GetWorkspaceAgentLogsAfterParams) ([]database.WorkspaceAgentLog, error) {
 	m.ctrl.T.Helper()
@@ -1766,6 +2398,96 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadata(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadata), arg0, arg1)
 }
 
+// GetWorkspaceAgentPTYInvite mocks base method.
+func (m *MockStore) GetWorkspaceAgentPTYInvite(arg0 context.Context, arg1 database.GetWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPTYInvite", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentPTYInvite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPTYInvite indicates an expected call of GetWorkspaceAgentPTYInvite.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPTYInvite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPTYInvite", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPTYInvite), arg0, arg1)
+}
+
+// GetWorkspaceAgentPTYInvitesBySessionID mocks base method.
+func (m *MockStore) GetWorkspaceAgentPTYInvitesBySessionID(arg0 context.Context, arg1 database.GetWorkspaceAgentPTYInvitesBySessionIDParams) ([]database.WorkspaceAgentPTYInvite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPTYInvitesBySessionID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentPTYInvite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPTYInvitesBySessionID indicates an expected call of GetWorkspaceAgentPTYInvitesBySessionID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPTYInvitesBySessionID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPTYInvitesBySessionID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPTYInvitesBySessionID), arg0, arg1)
+}
+
+// GetWorkspaceAgentPTYInvitesByUserID mocks base method.
+func (m *MockStore) GetWorkspaceAgentPTYInvitesByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceAgentPTYInvite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPTYInvitesByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentPTYInvite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPTYInvitesByUserID indicates an expected call of GetWorkspaceAgentPTYInvitesByUserID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPTYInvitesByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPTYInvitesByUserID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPTYInvitesByUserID), arg0, arg1)
+}
+
+// GetWorkspaceAgentPortShare mocks base method.
+func (m *MockStore) GetWorkspaceAgentPortShare(arg0 context.Context, arg1 database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPortShare", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentPortShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPortShare indicates an expected call of GetWorkspaceAgentPortShare.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortShare(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortShare), arg0, arg1)
+}
+
+// GetWorkspaceAgentScriptByID mocks base method.
+func (m *MockStore) GetWorkspaceAgentScriptByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceAgentScript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentScriptByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentScript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentScriptByID indicates an expected call of GetWorkspaceAgentScriptByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentScriptByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentScriptByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentScriptByID), arg0, arg1)
+}
+
+// GetWorkspaceAgentScriptsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentScriptsByAgentIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentScriptsByAgentIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentScript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentScriptsByAgentIDs indicates an expected call of GetWorkspaceAgentScriptsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentScriptsByAgentIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentScriptsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentScriptsByAgentIDs), arg0, arg1)
+}
+
 // GetWorkspaceAgentStats mocks base method.
 func (m *MockStore) GetWorkspaceAgentStats(arg0 context.Context, arg1 time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -2051,6 +2773,66 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), arg0, arg1)
 }
 
+// GetWorkspacePrebuildPoolByID mocks base method.
+func (m *MockStore) GetWorkspacePrebuildPoolByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspacePrebuildPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePrebuildPoolByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspacePrebuildPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePrebuildPoolByID indicates an expected call of GetWorkspacePrebuildPoolByID.
+func (mr *MockStoreMockRecorder) GetWorkspacePrebuildPoolByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePrebuildPoolByID", reflect.TypeOf((*MockStore)(nil).GetWorkspacePrebuildPoolByID), arg0, arg1)
+}
+
+// GetWorkspacePrebuildPools mocks base method.
+func (m *MockStore) GetWorkspacePrebuildPools(arg0 context.Context) ([]database.WorkspacePrebuildPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePrebuildPools", arg0)
+	ret0, _ := ret[0].([]database.WorkspacePrebuildPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePrebuildPools indicates an expected call of GetWorkspacePrebuildPools.
+func (mr *MockStoreMockRecorder) GetWorkspacePrebuildPools(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePrebuildPools", reflect.TypeOf((*MockStore)(nil).GetWorkspacePrebuildPools), arg0)
+}
+
+// GetWorkspacePrebuildPoolsByTemplateID mocks base method.
+func (m *MockStore) GetWorkspacePrebuildPoolsByTemplateID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspacePrebuildPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePrebuildPoolsByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspacePrebuildPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePrebuildPoolsByTemplateID indicates an expected call of GetWorkspacePrebuildPoolsByTemplateID.
+func (mr *MockStoreMockRecorder) GetWorkspacePrebuildPoolsByTemplateID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePrebuildPoolsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetWorkspacePrebuildPoolsByTemplateID), arg0, arg1)
+}
+
+// GetWorkspacePrebuilds mocks base method.
+func (m *MockStore) GetWorkspacePrebuilds(arg0 context.Context) ([]database.WorkspacePrebuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePrebuilds", arg0)
+	ret0, _ := ret[0].([]database.WorkspacePrebuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePrebuilds indicates an expected call of GetWorkspacePrebuilds.
+func (mr *MockStoreMockRecorder) GetWorkspacePrebuilds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePrebuilds", reflect.TypeOf((*MockStore)(nil).GetWorkspacePrebuilds), arg0)
+}
+
 // GetWorkspaceProxies mocks base method.
 func (m *MockStore) GetWorkspaceProxies(arg0 context.Context) ([]database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -2201,6 +2983,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), arg0, arg1)
 }
 
+// GetWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingByID indicates an expected call of GetWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingByID), arg0, arg1)
+}
+
+// GetWorkspaceSessionRecordingChunks mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingChunks(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceSessionRecordingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingChunks", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecordingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingChunks indicates an expected call of GetWorkspaceSessionRecordingChunks.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingChunks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingChunks", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingChunks), arg0, arg1)
+}
+
+// GetWorkspaceSessionRecordingsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingsByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingsByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingsByWorkspaceID indicates an expected call of GetWorkspaceSessionRecordingsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingsByWorkspaceID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingsByWorkspaceID), arg0, arg1)
+}
+
 // GetWorkspaces mocks base method.
 func (m *MockStore) GetWorkspaces(arg0 context.Context, arg1 database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	m.ctrl.T.Helper()
@@ -2216,6 +3043,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaces(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaces", reflect.TypeOf((*MockStore)(nil).GetWorkspaces), arg0, arg1)
 }
 
+// GetWorkspacesApproachingAutostop mocks base method.
+func (m *MockStore) GetWorkspacesApproachingAutostop(arg0 context.Context, arg1 database.GetWorkspacesApproachingAutostopParams) ([]database.GetWorkspacesApproachingAutostopRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacesApproachingAutostop", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspacesApproachingAutostopRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacesApproachingAutostop indicates an expected call of GetWorkspacesApproachingAutostop.
+func (mr *MockStoreMockRecorder) GetWorkspacesApproachingAutostop(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacesApproachingAutostop", reflect.TypeOf((*MockStore)(nil).GetWorkspacesApproachingAutostop), arg0, arg1)
+}
+
 // GetWorkspacesEligibleForTransition mocks base method.
 func (m *MockStore) GetWorkspacesEligibleForTransition(arg0 context.Context, arg1 time.Time) ([]database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -2290,6 +3132,21 @@ func (mr *MockStoreMockRecorder) InsertAuditLog(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLog", reflect.TypeOf((*MockStore)(nil).InsertAuditLog), arg0, arg1)
 }
 
+// InsertCustomRole mocks base method.
+func (m *MockStore) InsertCustomRole(arg0 context.Context, arg1 database.InsertCustomRoleParams) (database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCustomRole", arg0, arg1)
+	ret0, _ := ret[0].(database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCustomRole indicates an expected call of InsertCustomRole.
+func (mr *MockStoreMockRecorder) InsertCustomRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCustomRole", reflect.TypeOf((*MockStore)(nil).InsertCustomRole), arg0, arg1)
+}
+
 // InsertDBCryptKey mocks base method.
 func (m *MockStore) InsertDBCryptKey(arg0 context.Context, arg1 database.InsertDBCryptKeyParams) error {
 	m.ctrl.T.Helper()
@@ -2406,34 +3263,109 @@ func (mr *MockStoreMockRecorder) InsertGroupMember(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupMember", reflect.TypeOf((*MockStore)(nil).InsertGroupMember), arg0, arg1)
 }
 
-// InsertLicense mocks base method.
-func (m *MockStore) InsertLicense(arg0 context.Context, arg1 database.InsertLicenseParams) (database.License, error) {
+// InsertLicense mocks base method.
+func (m *MockStore) InsertLicense(arg0 context.Context, arg1 database.InsertLicenseParams) (database.License, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLicense", arg0, arg1)
+	ret0, _ := ret[0].(database.License)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertLicense indicates an expected call of InsertLicense.
+func (mr *MockStoreMockRecorder) InsertLicense(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLicense", reflect.TypeOf((*MockStore)(nil).InsertLicense), arg0, arg1)
+}
+
+// InsertMissingGroups mocks base method.
+func (m *MockStore) InsertMissingGroups(arg0 context.Context, arg1 database.InsertMissingGroupsParams) ([]database.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMissingGroups", arg0, arg1)
+	ret0, _ := ret[0].([]database.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMissingGroups indicates an expected call of InsertMissingGroups.
+func (mr *MockStoreMockRecorder) InsertMissingGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingGroups", reflect.TypeOf((*MockStore)(nil).InsertMissingGroups), arg0, arg1)
+}
+
+// InsertNotificationMessage mocks base method.
+func (m *MockStore) InsertNotificationMessage(arg0 context.Context, arg1 database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNotificationMessage", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertNotificationMessage indicates an expected call of InsertNotificationMessage.
+func (mr *MockStoreMockRecorder) InsertNotificationMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotificationMessage", reflect.TypeOf((*MockStore)(nil).InsertNotificationMessage), arg0, arg1)
+}
+
+// InsertOAuth2ProviderApp mocks base method.
+func (m *MockStore) InsertOAuth2ProviderApp(arg0 context.Context, arg1 database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderApp", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderApp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuth2ProviderApp indicates an expected call of InsertOAuth2ProviderApp.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderApp(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderApp", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderApp), arg0, arg1)
+}
+
+// InsertOAuth2ProviderAppCode mocks base method.
+func (m *MockStore) InsertOAuth2ProviderAppCode(arg0 context.Context, arg1 database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderAppCode", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuth2ProviderAppCode indicates an expected call of InsertOAuth2ProviderAppCode.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppCode", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppCode), arg0, arg1)
+}
+
+// InsertOAuth2ProviderAppSecret mocks base method.
+func (m *MockStore) InsertOAuth2ProviderAppSecret(arg0 context.Context, arg1 database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertLicense", arg0, arg1)
-	ret0, _ := ret[0].(database.License)
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderAppSecret", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertLicense indicates an expected call of InsertLicense.
-func (mr *MockStoreMockRecorder) InsertLicense(arg0, arg1 interface{}) *gomock.Call {
+// InsertOAuth2ProviderAppSecret indicates an expected call of InsertOAuth2ProviderAppSecret.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLicense", reflect.TypeOf((*MockStore)(nil).InsertLicense), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppSecret", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppSecret), arg0, arg1)
 }
 
-// InsertMissingGroups mocks base method.
-func (m *MockStore) InsertMissingGroups(arg0 context.Context, arg1 database.InsertMissingGroupsParams) ([]database.Group, error) {
+// InsertOAuth2ProviderAppToken mocks base method.
+func (m *MockStore) InsertOAuth2ProviderAppToken(arg0 context.Context, arg1 database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertMissingGroups", arg0, arg1)
-	ret0, _ := ret[0].([]database.Group)
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderAppToken", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertMissingGroups indicates an expected call of InsertMissingGroups.
-func (mr *MockStoreMockRecorder) InsertMissingGroups(arg0, arg1 interface{}) *gomock.Call {
+// InsertOAuth2ProviderAppToken indicates an expected call of InsertOAuth2ProviderAppToken.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppToken(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingGroups", reflect.TypeOf((*MockStore)(nil).InsertMissingGroups), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppToken", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppToken), arg0, arg1)
 }
 
 // InsertOrganization mocks base method.
@@ -2569,6 +3501,21 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersionParameter(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionParameter", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionParameter), arg0, arg1)
 }
 
+// InsertTemplateVersionPreset mocks base method.
+func (m *MockStore) InsertTemplateVersionPreset(arg0 context.Context, arg1 database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVersionPreset", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateVersionPreset indicates an expected call of InsertTemplateVersionPreset.
+func (mr *MockStoreMockRecorder) InsertTemplateVersionPreset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionPreset", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionPreset), arg0, arg1)
+}
+
 // InsertTemplateVersionVariable mocks base method.
 func (m *MockStore) InsertTemplateVersionVariable(arg0 context.Context, arg1 database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -2628,6 +3575,36 @@ func (mr *MockStoreMockRecorder) InsertUserLink(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockStore)(nil).InsertUserLink), arg0, arg1)
 }
 
+// InsertWebhook mocks base method.
+func (m *MockStore) InsertWebhook(arg0 context.Context, arg1 database.InsertWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockStoreMockRecorder) InsertWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockStore)(nil).InsertWebhook), arg0, arg1)
+}
+
+// InsertWebhookDeliveries mocks base method.
+func (m *MockStore) InsertWebhookDeliveries(arg0 context.Context, arg1 database.InsertWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookDeliveries indicates an expected call of InsertWebhookDeliveries.
+func (mr *MockStoreMockRecorder) InsertWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).InsertWebhookDeliveries), arg0, arg1)
+}
+
 // InsertWorkspace mocks base method.
 func (m *MockStore) InsertWorkspace(arg0 context.Context, arg1 database.InsertWorkspaceParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -2658,6 +3635,36 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgent(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgent), arg0, arg1)
 }
 
+// InsertWorkspaceAgentLogFiles mocks base method.
+func (m *MockStore) InsertWorkspaceAgentLogFiles(arg0 context.Context, arg1 database.InsertWorkspaceAgentLogFilesParams) ([]database.WorkspaceAgentLogFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentLogFiles", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentLogFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentLogFiles indicates an expected call of InsertWorkspaceAgentLogFiles.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentLogFiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentLogFiles", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentLogFiles), arg0, arg1)
+}
+
+// InsertWorkspaceAgentLogSources mocks base method.
+func (m *MockStore) InsertWorkspaceAgentLogSources(arg0 context.Context, arg1 database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentLogSources", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentLogSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentLogSources indicates an expected call of InsertWorkspaceAgentLogSources.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentLogSources(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentLogSources", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentLogSources), arg0, arg1)
+}
+
 // InsertWorkspaceAgentLogs mocks base method.
 func (m *MockStore) InsertWorkspaceAgentLogs(arg0 context.Context, arg1 database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	m.ctrl.T.Helper()
@@ -2687,6 +3694,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadata(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceAgentScripts mocks base method.
+func (m *MockStore) InsertWorkspaceAgentScripts(arg0 context.Context, arg1 database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentScripts", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentScript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentScripts indicates an expected call of InsertWorkspaceAgentScripts.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentScripts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentScripts", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentScripts), arg0, arg1)
+}
+
 // InsertWorkspaceAgentStat mocks base method.
 func (m *MockStore) InsertWorkspaceAgentStat(arg0 context.Context, arg1 database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	m.ctrl.T.Helper()
@@ -2773,6 +3795,35 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), arg0, arg1)
 }
 
+// InsertWorkspacePrebuild mocks base method.
+func (m *MockStore) InsertWorkspacePrebuild(arg0 context.Context, arg1 database.InsertWorkspacePrebuildParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspacePrebuild", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspacePrebuild indicates an expected call of InsertWorkspacePrebuild.
+func (mr *MockStoreMockRecorder) InsertWorkspacePrebuild(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspacePrebuild", reflect.TypeOf((*MockStore)(nil).InsertWorkspacePrebuild), arg0, arg1)
+}
+
+// InsertWorkspacePrebuildPool mocks base method.
+func (m *MockStore) InsertWorkspacePrebuildPool(arg0 context.Context, arg1 database.InsertWorkspacePrebuildPoolParams) (database.WorkspacePrebuildPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspacePrebuildPool", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspacePrebuildPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspacePrebuildPool indicates an expected call of InsertWorkspacePrebuildPool.
+func (mr *MockStoreMockRecorder) InsertWorkspacePrebuildPool(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspacePrebuildPool", reflect.TypeOf((*MockStore)(nil).InsertWorkspacePrebuildPool), arg0, arg1)
+}
+
 // InsertWorkspaceProxy mocks base method.
 func (m *MockStore) InsertWorkspaceProxy(arg0 context.Context, arg1 database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -2818,6 +3869,51 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceSessionRecording mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecording(arg0 context.Context, arg1 database.InsertWorkspaceSessionRecordingParams) (database.WorkspaceSessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecording", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecording indicates an expected call of InsertWorkspaceSessionRecording.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecording(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecording", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecording), arg0, arg1)
+}
+
+// InsertWorkspaceSessionRecordingChunk mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecordingChunk(arg0 context.Context, arg1 database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecordingChunk", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecordingChunk indicates an expected call of InsertWorkspaceSessionRecordingChunk.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecordingChunk(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecordingChunk", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecordingChunk), arg0, arg1)
+}
+
+// ListWorkspaceAgentPortShares mocks base method.
+func (m *MockStore) ListWorkspaceAgentPortShares(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaceAgentPortShares", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentPortShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaceAgentPortShares indicates an expected call of ListWorkspaceAgentPortShares.
+func (mr *MockStoreMockRecorder) ListWorkspaceAgentPortShares(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceAgentPortShares", reflect.TypeOf((*MockStore)(nil).ListWorkspaceAgentPortShares), arg0, arg1)
+}
+
 // Ping mocks base method.
 func (m *MockStore) Ping(arg0 context.Context) (time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -2833,6 +3929,20 @@ func (mr *MockStoreMockRecorder) Ping(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), arg0)
 }
 
+// ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate mocks base method.
+func (m *MockStore) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate indicates an expected call of ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate.
+func (mr *MockStoreMockRecorder) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate", reflect.TypeOf((*MockStore)(nil).ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate), arg0, arg1)
+}
+
 // RegisterWorkspaceProxy mocks base method.
 func (m *MockStore) RegisterWorkspaceProxy(arg0 context.Context, arg1 database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -2891,6 +4001,21 @@ func (mr *MockStoreMockRecorder) UpdateAPIKeyByID(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeyByID", reflect.TypeOf((*MockStore)(nil).UpdateAPIKeyByID), arg0, arg1)
 }
 
+// UpdateCustomRoleByID mocks base method.
+func (m *MockStore) UpdateCustomRoleByID(arg0 context.Context, arg1 database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomRoleByID", arg0, arg1)
+	ret0, _ := ret[0].(database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCustomRoleByID indicates an expected call of UpdateCustomRoleByID.
+func (mr *MockStoreMockRecorder) UpdateCustomRoleByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomRoleByID", reflect.TypeOf((*MockStore)(nil).UpdateCustomRoleByID), arg0, arg1)
+}
+
 // UpdateGitAuthLink mocks base method.
 func (m *MockStore) UpdateGitAuthLink(arg0 context.Context, arg1 database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -2966,6 +4091,79 @@ func (mr *MockStoreMockRecorder) UpdateMemberRoles(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRoles", reflect.TypeOf((*MockStore)(nil).UpdateMemberRoles), arg0, arg1)
 }
 
+// UpdateNotificationMessageEmailByID mocks base method.
+func (m *MockStore) UpdateNotificationMessageEmailByID(arg0 context.Context, arg1 database.UpdateNotificationMessageEmailByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationMessageEmailByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationMessageEmailByID indicates an expected call of UpdateNotificationMessageEmailByID.
+func (mr *MockStoreMockRecorder) UpdateNotificationMessageEmailByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationMessageEmailByID", reflect.TypeOf((*MockStore)(nil).UpdateNotificationMessageEmailByID), arg0, arg1)
+}
+
+// UpdateNotificationMessageReadAtByID mocks base method.
+func (m *MockStore) UpdateNotificationMessageReadAtByID(arg0 context.Context, arg1 database.UpdateNotificationMessageReadAtByIDParams) (database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationMessageReadAtByID", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNotificationMessageReadAtByID indicates an expected call of UpdateNotificationMessageReadAtByID.
+func (mr *MockStoreMockRecorder) UpdateNotificationMessageReadAtByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationMessageReadAtByID", reflect.TypeOf((*MockStore)(nil).UpdateNotificationMessageReadAtByID), arg0, arg1)
+}
+
+// UpdateNotificationMessagesReadAtByUserID mocks base method.
+func (m *MockStore) UpdateNotificationMessagesReadAtByUserID(arg0 context.Context, arg1 database.UpdateNotificationMessagesReadAtByUserIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationMessagesReadAtByUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationMessagesReadAtByUserID indicates an expected call of UpdateNotificationMessagesReadAtByUserID.
+func (mr *MockStoreMockRecorder) UpdateNotificationMessagesReadAtByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationMessagesReadAtByUserID", reflect.TypeOf((*MockStore)(nil).UpdateNotificationMessagesReadAtByUserID), arg0, arg1)
+}
+
+// UpdateOAuth2ProviderAppByID mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderAppByID(arg0 context.Context, arg1 database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderAppByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderApp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOAuth2ProviderAppByID indicates an expected call of UpdateOAuth2ProviderAppByID.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppByID), arg0, arg1)
+}
+
+// UpdateOAuth2ProviderAppSecretByID mocks base method.
+func (m *MockStore) UpdateOAuth2ProviderAppSecretByID(arg0 context.Context, arg1 database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2ProviderAppSecretByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOAuth2ProviderAppSecretByID indicates an expected call of UpdateOAuth2ProviderAppSecretByID.
+func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppSecretByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppSecretByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppSecretByID), arg0, arg1)
+}
+
 // UpdateProvisionerJobByID mocks base method.
 func (m *MockStore) UpdateProvisionerJobByID(arg0 context.Context, arg1 database.UpdateProvisionerJobByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3297,6 +4495,35 @@ func (mr *MockStoreMockRecorder) UpdateUserStatus(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockStore)(nil).UpdateUserStatus), arg0, arg1)
 }
 
+// UpdateWebhookByID mocks base method.
+func (m *MockStore) UpdateWebhookByID(arg0 context.Context, arg1 database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhookByID indicates an expected call of UpdateWebhookByID.
+func (mr *MockStoreMockRecorder) UpdateWebhookByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookByID", reflect.TypeOf((*MockStore)(nil).UpdateWebhookByID), arg0, arg1)
+}
+
+// UpdateWebhookDeliveryByID mocks base method.
+func (m *MockStore) UpdateWebhookDeliveryByID(arg0 context.Context, arg1 database.UpdateWebhookDeliveryByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDeliveryByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDeliveryByID indicates an expected call of UpdateWebhookDeliveryByID.
+func (mr *MockStoreMockRecorder) UpdateWebhookDeliveryByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDeliveryByID", reflect.TypeOf((*MockStore)(nil).UpdateWebhookDeliveryByID), arg0, arg1)
+}
+
 // UpdateWorkspace mocks base method.
 func (m *MockStore) UpdateWorkspace(arg0 context.Context, arg1 database.UpdateWorkspaceParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -3312,6 +4539,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspace(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspace), arg0, arg1)
 }
 
+// UpdateWorkspaceAgentAutostopInhibitorsByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentAutostopInhibitorsByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentAutostopInhibitorsByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentAutostopInhibitorsByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentAutostopInhibitorsByID indicates an expected call of UpdateWorkspaceAgentAutostopInhibitorsByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentAutostopInhibitorsByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentAutostopInhibitorsByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentAutostopInhibitorsByID), arg0, arg1)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3368,6 +4609,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentMetadata(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentMetadata), arg0, arg1)
 }
 
+// UpdateWorkspaceAgentScriptStatusByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentScriptStatusByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentScriptStatusByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentScriptStatusByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentScriptStatusByID indicates an expected call of UpdateWorkspaceAgentScriptStatusByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentScriptStatusByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentScriptStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentScriptStatusByID), arg0, arg1)
+}
+
 // UpdateWorkspaceAgentStartupByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentStartupByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentStartupByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3382,6 +4637,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentStartupByID(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentStartupByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentStartupByID), arg0, arg1)
 }
 
+// UpdateWorkspaceAgentUpdateStateByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentUpdateStateByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentUpdateStateByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentUpdateStateByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentUpdateStateByID indicates an expected call of UpdateWorkspaceAgentUpdateStateByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentUpdateStateByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentUpdateStateByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentUpdateStateByID), arg0, arg1)
+}
+
 // UpdateWorkspaceAppHealthByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAppHealthByID(arg0 context.Context, arg1 database.UpdateWorkspaceAppHealthByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3396,6 +4665,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceAppHealthByID(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAppHealthByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAppHealthByID), arg0, arg1)
 }
 
+// UpdateWorkspaceAutomaticUpdates mocks base method.
+func (m *MockStore) UpdateWorkspaceAutomaticUpdates(arg0 context.Context, arg1 database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAutomaticUpdates", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAutomaticUpdates indicates an expected call of UpdateWorkspaceAutomaticUpdates.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAutomaticUpdates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAutomaticUpdates", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAutomaticUpdates), arg0, arg1)
+}
+
 // UpdateWorkspaceAutostart mocks base method.
 func (m *MockStore) UpdateWorkspaceAutostart(arg0 context.Context, arg1 database.UpdateWorkspaceAutostartParams) error {
 	m.ctrl.T.Helper()
@@ -3481,6 +4764,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceLastUsedAt(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceLastUsedAt), arg0, arg1)
 }
 
+// UpdateWorkspacePrebuildPoolByID mocks base method.
+func (m *MockStore) UpdateWorkspacePrebuildPoolByID(arg0 context.Context, arg1 database.UpdateWorkspacePrebuildPoolByIDParams) (database.WorkspacePrebuildPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspacePrebuildPoolByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspacePrebuildPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspacePrebuildPoolByID indicates an expected call of UpdateWorkspacePrebuildPoolByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspacePrebuildPoolByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspacePrebuildPoolByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspacePrebuildPoolByID), arg0, arg1)
+}
+
 // UpdateWorkspaceProxy mocks base method.
 func (m *MockStore) UpdateWorkspaceProxy(arg0 context.Context, arg1 database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -3510,6 +4808,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceProxyDeleted(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxyDeleted", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxyDeleted), arg0, arg1)
 }
 
+// UpdateWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) UpdateWorkspaceSessionRecordingByID(arg0 context.Context, arg1 database.UpdateWorkspaceSessionRecordingByIDParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceSessionRecordingByID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceSessionRecordingByID indicates an expected call of UpdateWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceSessionRecordingByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceSessionRecordingByID), arg0, arg1)
+}
+
 // UpdateWorkspaceTTL mocks base method.
 func (m *MockStore) UpdateWorkspaceTTL(arg0 context.Context, arg1 database.UpdateWorkspaceTTLParams) error {
 	m.ctrl.T.Helper()
@@ -3552,6 +4865,21 @@ func (mr *MockStoreMockRecorder) UpsertAppSecurityKey(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAppSecurityKey", reflect.TypeOf((*MockStore)(nil).UpsertAppSecurityKey), arg0, arg1)
 }
 
+// UpsertConnectionLog mocks base method.
+func (m *MockStore) UpsertConnectionLog(arg0 context.Context, arg1 database.UpsertConnectionLogParams) (database.ConnectionLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertConnectionLog", arg0, arg1)
+	ret0, _ := ret[0].(database.ConnectionLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertConnectionLog indicates an expected call of UpsertConnectionLog.
+func (mr *MockStoreMockRecorder) UpsertConnectionLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertConnectionLog", reflect.TypeOf((*MockStore)(nil).UpsertConnectionLog), arg0, arg1)
+}
+
 // UpsertDefaultProxy mocks base method.
 func (m *MockStore) UpsertDefaultProxy(arg0 context.Context, arg1 database.UpsertDefaultProxyParams) error {
 	m.ctrl.T.Helper()
@@ -3594,6 +4922,21 @@ func (mr *MockStoreMockRecorder) UpsertLogoURL(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLogoURL", reflect.TypeOf((*MockStore)(nil).UpsertLogoURL), arg0, arg1)
 }
 
+// UpsertNotificationPreference mocks base method.
+func (m *MockStore) UpsertNotificationPreference(arg0 context.Context, arg1 database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreference", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreference indicates an expected call of UpsertNotificationPreference.
+func (mr *MockStoreMockRecorder) UpsertNotificationPreference(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreference", reflect.TypeOf((*MockStore)(nil).UpsertNotificationPreference), arg0, arg1)
+}
+
 // UpsertOAuthSigningKey mocks base method.
 func (m *MockStore) UpsertOAuthSigningKey(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -3667,6 +5010,36 @@ func (mr *MockStoreMockRecorder) UpsertTailnetCoordinator(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTailnetCoordinator", reflect.TypeOf((*MockStore)(nil).UpsertTailnetCoordinator), arg0, arg1)
 }
 
+// UpsertWorkspaceAgentPTYInvite mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentPTYInvite(arg0 context.Context, arg1 database.UpsertWorkspaceAgentPTYInviteParams) (database.WorkspaceAgentPTYInvite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentPTYInvite", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentPTYInvite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceAgentPTYInvite indicates an expected call of UpsertWorkspaceAgentPTYInvite.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPTYInvite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPTYInvite", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPTYInvite), arg0, arg1)
+}
+
+// UpsertWorkspaceAgentPortShare mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentPortShare(arg0 context.Context, arg1 database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentPortShare", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentPortShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceAgentPortShare indicates an expected call of UpsertWorkspaceAgentPortShare.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPortShare(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPortShare), arg0, arg1)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
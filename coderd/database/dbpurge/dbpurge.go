@@ -11,6 +11,7 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 )
 
 const (
@@ -21,7 +22,9 @@ const (
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
+// Connection logs older than connectionLogRetention are deleted too, unless it
+// is zero.
+func New(ctx context.Context, logger slog.Logger, db database.Store, connectionLogRetention time.Duration) io.Closer {
 	closed := make(chan struct{})
 	ctx, cancelFunc := context.WithCancel(ctx)
 	//nolint:gocritic // The system purges old db records without user input.
@@ -45,6 +48,17 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 			eg.Go(func() error {
 				return db.DeleteOldWorkspaceAgentStats(ctx)
 			})
+			eg.Go(func() error {
+				return db.DeleteOldWebhookDeliveries(ctx)
+			})
+			eg.Go(func() error {
+				return db.DeleteOldNotificationMessages(ctx)
+			})
+			if connectionLogRetention > 0 {
+				eg.Go(func() error {
+					return db.DeleteOldConnectionLogs(ctx, dbtime.Now().Add(-connectionLogRetention))
+				})
+			}
 			err := eg.Wait()
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
@@ -20,7 +20,7 @@ func TestMain(m *testing.M) {
 // Ensures no goroutines leak.
 func TestPurge(t *testing.T) {
 	t.Parallel()
-	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbfake.New())
+	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbfake.New(), 0)
 	err := purger.Close()
 	require.NoError(t, err)
 }
@@ -2,7 +2,8 @@
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'custom'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -19,7 +20,13 @@ CREATE TYPE audit_action AS ENUM (
     'stop',
     'login',
     'logout',
-    'register'
+    'register',
+    'connect'
+);
+
+CREATE TYPE automatic_updates AS ENUM (
+    'always',
+    'never'
 );
 
 CREATE TYPE build_reason AS ENUM (
@@ -31,6 +38,15 @@ CREATE TYPE build_reason AS ENUM (
     'autodelete'
 );
 
+CREATE TYPE connection_type AS ENUM (
+    'ssh',
+    'vscode',
+    'jetbrains',
+    'reconnecting_pty',
+    'port_forwarding',
+    'workspace_app'
+);
+
 CREATE TYPE display_app AS ENUM (
     'vscode',
     'vscode_insiders',
@@ -62,11 +78,21 @@ CREATE TYPE login_type AS ENUM (
     'github',
     'oidc',
     'token',
-    'none'
+    'none',
+    'oauth2_provider_app',
+    'ldap'
 );
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
 
+CREATE TYPE notification_kind AS ENUM (
+    'workspace_autostop',
+    'workspace_dormant',
+    'workspace_marked_for_deletion',
+    'workspace_build_failed',
+    'account_dormant'
+);
+
 CREATE TYPE parameter_destination_scheme AS ENUM (
     'none',
     'environment_variable',
@@ -116,7 +142,10 @@ CREATE TYPE resource_type AS ENUM (
     'workspace_build',
     'license',
     'workspace_proxy',
-    'convert_login'
+    'convert_login',
+    'custom_role',
+    'oauth2_provider_app',
+    'oauth2_provider_app_secret'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -132,6 +161,16 @@ CREATE TYPE user_status AS ENUM (
 
 COMMENT ON TYPE user_status IS 'Defines the user status: active, dormant, or suspended.';
 
+CREATE TYPE webhook_event AS ENUM (
+    'workspace_created',
+    'workspace_started',
+    'workspace_stopped',
+    'workspace_deleted',
+    'workspace_build_failed',
+    'template_version_promoted',
+    'user_created'
+);
+
 CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'created',
     'starting',
@@ -144,13 +183,12 @@ CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'off'
 );
 
-CREATE TYPE workspace_agent_log_source AS ENUM (
-    'startup_script',
-    'shutdown_script',
-    'kubernetes_logs',
-    'envbox',
-    'envbuilder',
-    'external'
+CREATE TYPE workspace_agent_script_status AS ENUM (
+    'pending',
+    'running',
+    'ok',
+    'exit_failure',
+    'timed_out'
 );
 
 CREATE TYPE workspace_agent_subsystem AS ENUM (
@@ -160,6 +198,15 @@ CREATE TYPE workspace_agent_subsystem AS ENUM (
     'exectrace'
 );
 
+CREATE TYPE workspace_agent_update_state AS ENUM (
+    'unknown',
+    'up_to_date',
+    'disabled',
+    'updating',
+    'updated',
+    'failed'
+);
+
 CREATE TYPE workspace_app_health AS ENUM (
     'disabled',
     'initializing',
@@ -167,6 +214,11 @@ CREATE TYPE workspace_app_health AS ENUM (
     'unhealthy'
 );
 
+CREATE TYPE workspace_session_recording_type AS ENUM (
+    'ssh',
+    'reconnecting_pty'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
@@ -252,11 +304,14 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    scope_rules jsonb DEFAULT '[]'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.scope_rules IS 'The rules of keys with the custom scope. The key can only perform the actions the rules allow, on top of the roles of its user.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -275,6 +330,63 @@ CREATE TABLE audit_logs (
     resource_icon text NOT NULL
 );
 
+CREATE TABLE connection_logs (
+    id uuid NOT NULL,
+    connect_time timestamp with time zone NOT NULL,
+    disconnect_time timestamp with time zone,
+    organization_id uuid NOT NULL,
+    workspace_owner_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_name text NOT NULL,
+    agent_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    type connection_type NOT NULL,
+    user_id uuid,
+    ip inet,
+    user_agent text,
+    slug_or_port text DEFAULT ''::text NOT NULL,
+    code integer,
+    bytes_sent bigint DEFAULT 0 NOT NULL,
+    bytes_received bigint DEFAULT 0 NOT NULL,
+    disconnect_reason text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE connection_logs IS 'Connections to workspaces, as reported by workspace agents and the workspace app proxy.';
+
+COMMENT ON COLUMN connection_logs.disconnect_time IS 'Null while the connection is open. Workspace app accesses never have a disconnect time.';
+
+COMMENT ON COLUMN connection_logs.user_id IS 'The user that connected, if known. Agents don''t know who is connecting to them.';
+
+COMMENT ON COLUMN connection_logs.slug_or_port IS 'The slug of the workspace app, or the port of a port forward.';
+
+COMMENT ON COLUMN connection_logs.code IS 'The exit code of SSH and reconnecting PTY sessions, or the HTTP status code of workspace app accesses.';
+
+COMMENT ON COLUMN connection_logs.bytes_sent IS 'Bytes sent from the workspace to the client.';
+
+COMMENT ON COLUMN connection_logs.bytes_received IS 'Bytes received by the workspace from the client.';
+
+CREATE TABLE custom_roles (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    organization_id uuid,
+    site_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    org_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    user_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined by administrators in addition to the built-in roles.';
+
+COMMENT ON COLUMN custom_roles.organization_id IS 'Roles with an organization are assigned to organization members, roles without one are site wide.';
+
+COMMENT ON COLUMN custom_roles.site_permissions IS 'The site wide permissions of the role, as an array of rbac permissions.';
+
+COMMENT ON COLUMN custom_roles.org_permissions IS 'The permissions of the role in its organization, as an array of rbac permissions.';
+
+COMMENT ON COLUMN custom_roles.user_permissions IS 'The permissions of the role on resources owned by the user, as an array of rbac permissions.';
+
 CREATE TABLE dbcrypt_keys (
     number integer NOT NULL,
     active_key_digest text,
@@ -370,6 +482,93 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE notification_messages (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    kind notification_kind NOT NULL,
+    title text NOT NULL,
+    content text NOT NULL,
+    dedupe_key text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    read_at timestamp with time zone,
+    email_attempts integer DEFAULT 0 NOT NULL,
+    email_next_attempt_at timestamp with time zone,
+    emailed_at timestamp with time zone,
+    email_error text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE notification_messages IS 'Notifications shown in the inbox of users, and optionally emailed to them. Any replica may send the emails.';
+
+COMMENT ON COLUMN notification_messages.dedupe_key IS 'Identifies the event the notification is about, so it is only sent once. Empty if notifications are never deduplicated.';
+
+COMMENT ON COLUMN notification_messages.email_next_attempt_at IS 'When sending the email is attempted next. Null if no email is sent, once it was sent or it ran out of attempts.';
+
+COMMENT ON COLUMN notification_messages.email_error IS 'Why the last attempt to send the email failed.';
+
+CREATE TABLE notification_preferences (
+    user_id uuid NOT NULL,
+    kind notification_kind NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE notification_preferences IS 'The kinds of notifications users opted out of. Users receive all kinds of notifications without a preference.';
+
+CREATE TABLE oauth2_provider_app_codes (
+    id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    hashed_secret bytea NOT NULL,
+    redirect_uri text NOT NULL,
+    scope text NOT NULL,
+    code_challenge text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes issued to apps when users authorize them. A code is exchanged for a token once.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The PKCE S256 code challenge. The app has to send the matching verifier with the code.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect_uri of the authorization request, empty if it was left out. The token request must send the same one.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+    id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone,
+    hashed_secret bytea NOT NULL,
+    display_secret text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_secrets IS 'Client secrets apps authenticate to the token endpoint with.';
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The last characters of the secret, to tell secrets apart.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+    id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    api_key_id text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    refresh_hash bytea NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'Refresh tokens of the API keys issued to apps. Deleting the API key revokes the refresh token.';
+
+CREATE TABLE oauth2_provider_apps (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    icon text NOT NULL,
+    redirect_uris text[] NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Third-party apps that act on behalf of users through the OAuth2 authorization code flow, with Coder as the authorization server.';
+
+COMMENT ON COLUMN oauth2_provider_apps.redirect_uris IS 'Users are only redirected back to an app at one of these URIs.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -500,9 +699,12 @@ CREATE TABLE tailnet_clients (
     coordinator_id uuid NOT NULL,
     agent_id uuid NOT NULL,
     updated_at timestamp with time zone NOT NULL,
-    node jsonb NOT NULL
+    node jsonb NOT NULL,
+    user_id uuid
 );
 
+COMMENT ON COLUMN tailnet_clients.user_id IS 'The user that the client authenticated as. Null for clients that coordinate on behalf of others, such as workspace proxies.';
+
 CREATE TABLE tailnet_coordinators (
     id uuid NOT NULL,
     heartbeat_at timestamp with time zone NOT NULL
@@ -563,6 +765,18 @@ COMMENT ON COLUMN template_version_parameters.display_order IS 'Specifies the or
 
 COMMENT ON COLUMN template_version_parameters.ephemeral IS 'The value of an ephemeral parameter will not be preserved between consecutive workspace builds.';
 
+CREATE TABLE template_version_presets (
+    id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    name text NOT NULL,
+    parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_presets IS 'Named sets of rich parameter values declared by a template version.';
+
+COMMENT ON COLUMN template_version_presets.parameters IS 'The rich parameter values of the preset, as an array of name and value objects.';
+
 CREATE TABLE template_version_variables (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -619,11 +833,14 @@ CREATE TABLE users (
     avatar_url text,
     deleted boolean DEFAULT false NOT NULL,
     last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
-    quiet_hours_schedule text DEFAULT ''::text NOT NULL
+    quiet_hours_schedule text DEFAULT ''::text NOT NULL,
+    is_service_account boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON COLUMN users.quiet_hours_schedule IS 'Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user''s quiet hours. If empty, the default quiet hours on the instance is used instead.';
 
+COMMENT ON COLUMN users.is_service_account IS 'Service accounts are non-human users for automation. They cannot log in interactively and are excluded from license seat counts and DAUs.';
+
 CREATE VIEW visible_users AS
  SELECT users.id,
     users.username,
@@ -675,7 +892,12 @@ CREATE TABLE templates (
     time_til_dormant bigint DEFAULT 0 NOT NULL,
     time_til_dormant_autodelete bigint DEFAULT 0 NOT NULL,
     autostop_requirement_days_of_week smallint DEFAULT 0 NOT NULL,
-    autostop_requirement_weeks bigint DEFAULT 0 NOT NULL
+    autostop_requirement_weeks bigint DEFAULT 0 NOT NULL,
+    max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
+    record_sessions boolean DEFAULT false NOT NULL,
+    deprecation_message text DEFAULT ''::text NOT NULL,
+    deprecated_at timestamp with time zone,
+    require_active_version boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -692,6 +914,16 @@ COMMENT ON COLUMN templates.autostop_requirement_days_of_week IS 'A bitmap of da
 
 COMMENT ON COLUMN templates.autostop_requirement_weeks IS 'The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.';
 
+COMMENT ON COLUMN templates.max_port_sharing_level IS 'The maximum sharing level workspace owners can set on ports of workspaces created from this template.';
+
+COMMENT ON COLUMN templates.record_sessions IS 'Whether interactive sessions in workspaces created from this template are recorded.';
+
+COMMENT ON COLUMN templates.deprecation_message IS 'If set, the template is deprecated and can''t be used to create new workspaces. The message tells users what to use instead.';
+
+COMMENT ON COLUMN templates.deprecated_at IS 'When the template was deprecated. Null if the template isn''t deprecated.';
+
+COMMENT ON COLUMN templates.require_active_version IS 'Whether workspaces must be updated to the active version of the template when their owner starts them.';
+
 CREATE VIEW template_with_users AS
  SELECT templates.id,
     templates.created_at,
@@ -717,6 +949,11 @@ CREATE VIEW template_with_users AS
     templates.time_til_dormant_autodelete,
     templates.autostop_requirement_days_of_week,
     templates.autostop_requirement_weeks,
+    templates.max_port_sharing_level,
+    templates.record_sessions,
+    templates.deprecation_message,
+    templates.deprecated_at,
+    templates.require_active_version,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username
    FROM (public.templates
@@ -739,13 +976,68 @@ COMMENT ON COLUMN user_links.oauth_access_token_key_id IS 'The ID of the key use
 
 COMMENT ON COLUMN user_links.oauth_refresh_token_key_id IS 'The ID of the key used to encrypt the OAuth refresh token. If this is NULL, the refresh token is not encrypted';
 
+CREATE TABLE webhook_deliveries (
+    id uuid NOT NULL,
+    webhook_id uuid NOT NULL,
+    event webhook_event NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone,
+    last_attempt_at timestamp with time zone,
+    status_code integer,
+    error text DEFAULT ''::text NOT NULL,
+    delivered_at timestamp with time zone
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'Events queued for and delivered to webhooks. Any replica may deliver them.';
+
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the delivery is attempted next. Null once it was delivered or it ran out of attempts.';
+
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'The HTTP status code of the last attempt, if the endpoint responded.';
+
+COMMENT ON COLUMN webhook_deliveries.error IS 'Why the last attempt failed.';
+
+CREATE TABLE webhooks (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events webhook_event[] NOT NULL,
+    enabled boolean DEFAULT true NOT NULL
+);
+
+COMMENT ON TABLE webhooks IS 'Endpoints that receive events as signed JSON payloads.';
+
+COMMENT ON COLUMN webhooks.secret IS 'The key payloads are signed with using HMAC-SHA256.';
+
+CREATE TABLE workspace_agent_log_files (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    log_source_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    path text NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_log_files IS 'Files in the workspace the agent tails into the workspace agent logs, each with its own log source.';
+
+CREATE TABLE workspace_agent_log_sources (
+    workspace_agent_id uuid NOT NULL,
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    display_name character varying(127) NOT NULL,
+    icon text NOT NULL
+);
+
 CREATE TABLE workspace_agent_logs (
     agent_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
     output character varying(1024) NOT NULL,
     id bigint NOT NULL,
     level log_level DEFAULT 'info'::log_level NOT NULL,
-    source workspace_agent_log_source DEFAULT 'startup_script'::workspace_agent_log_source NOT NULL
+    log_source_id uuid DEFAULT '00000000-0000-0000-0000-000000000000'::uuid NOT NULL
 );
 
 CREATE UNLOGGED TABLE workspace_agent_metadata (
@@ -760,6 +1052,54 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
     collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_agent_port_share (
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    port integer NOT NULL,
+    share_level app_sharing_level NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_port_share IS 'Ports on workspace agents that the workspace owner has shared with other users. Agents are referenced by name so shares survive workspace rebuilds.';
+
+CREATE TABLE workspace_agent_pty_invites (
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    session_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    read_only boolean NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_pty_invites IS 'Users other than the workspace owner that may attach to a reconnecting PTY session on a workspace agent.';
+
+COMMENT ON COLUMN workspace_agent_pty_invites.session_id IS 'The reconnect ID of the reconnecting PTY session on the agent.';
+
+CREATE TABLE workspace_agent_scripts (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    log_source_id uuid NOT NULL,
+    log_path text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    script text NOT NULL,
+    cron text NOT NULL,
+    start_blocks_login boolean NOT NULL,
+    run_on_start boolean NOT NULL,
+    run_on_stop boolean NOT NULL,
+    timeout_seconds integer NOT NULL,
+    display_order integer NOT NULL,
+    status workspace_agent_script_status DEFAULT 'pending'::workspace_agent_script_status NOT NULL,
+    exit_code integer DEFAULT 0 NOT NULL,
+    started_at timestamp with time zone,
+    ended_at timestamp with time zone
+);
+
+COMMENT ON COLUMN workspace_agent_scripts.display_order IS 'Specifies the order in which scripts are displayed. Scripts run concurrently regardless of their order.';
+
+COMMENT ON COLUMN workspace_agent_scripts.status IS 'The status of the most recent execution of the script.';
+
+COMMENT ON COLUMN workspace_agent_scripts.exit_code IS 'The exit code of the most recent execution of the script.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -824,6 +1164,9 @@ CREATE TABLE workspace_agents (
     ready_at timestamp with time zone,
     subsystems workspace_agent_subsystem[] DEFAULT '{}'::workspace_agent_subsystem[],
     display_apps display_app[] DEFAULT '{vscode,vscode_insiders,web_terminal,ssh_helper,port_forwarding_helper}'::display_app[],
+    autostop_inhibitors jsonb DEFAULT '[]'::jsonb NOT NULL,
+    update_state workspace_agent_update_state DEFAULT 'unknown'::workspace_agent_update_state NOT NULL,
+    update_error text DEFAULT ''::text NOT NULL,
     CONSTRAINT max_logs_length CHECK ((logs_length <= 1048576)),
     CONSTRAINT subsystems_not_none CHECK ((NOT ('none'::workspace_agent_subsystem = ANY (subsystems))))
 );
@@ -856,6 +1199,12 @@ COMMENT ON COLUMN workspace_agents.started_at IS 'The time the agent entered the
 
 COMMENT ON COLUMN workspace_agents.ready_at IS 'The time the agent entered the ready or start_error lifecycle state';
 
+COMMENT ON COLUMN workspace_agents.autostop_inhibitors IS 'The autostop inhibitors held in the workspace, as reported by the workspace agent. The deadline of the workspace is extended while the agent holds one.';
+
+COMMENT ON COLUMN workspace_agents.update_state IS 'The state of the agent replacing itself with the agent binary served by coderd, as reported by the workspace agent.';
+
+COMMENT ON COLUMN workspace_agents.update_error IS 'The error of the last failed agent update.';
+
 CREATE TABLE workspace_app_stats (
     id bigint NOT NULL,
     user_id uuid NOT NULL,
@@ -915,9 +1264,15 @@ CREATE TABLE workspace_apps (
     subdomain boolean DEFAULT false NOT NULL,
     sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
     slug text NOT NULL,
-    external boolean DEFAULT false NOT NULL
+    external boolean DEFAULT false NOT NULL,
+    healthcheck_tcp text DEFAULT ''::text NOT NULL,
+    healthcheck_command text DEFAULT ''::text NOT NULL
 );
 
+COMMENT ON COLUMN workspace_apps.healthcheck_tcp IS 'Address the agent connects to over TCP to check the health of the app, used instead of healthcheck_url.';
+
+COMMENT ON COLUMN workspace_apps.healthcheck_command IS 'Command the agent runs to check the health of the app, used instead of healthcheck_url. A zero exit code means the app is healthy.';
+
 CREATE TABLE workspace_build_parameters (
     workspace_build_id uuid NOT NULL,
     name text NOT NULL,
@@ -967,6 +1322,33 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_prebuild_pools (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+    desired_instances integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT desired_instances_not_negative CHECK ((desired_instances >= 0))
+);
+
+COMMENT ON TABLE workspace_prebuild_pools IS 'Pools of prebuilt workspaces. Creating a workspace with the template version and parameters of a pool claims one of its workspaces.';
+
+COMMENT ON COLUMN workspace_prebuild_pools.parameters IS 'The rich parameter values the workspaces of the pool are built with, as an array of name and value objects.';
+
+COMMENT ON COLUMN workspace_prebuild_pools.desired_instances IS 'The number of ready workspaces the pool is kept at.';
+
+CREATE TABLE workspace_prebuilds (
+    workspace_id uuid NOT NULL,
+    pool_id uuid,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_prebuilds IS 'Prebuilt workspaces that were not claimed yet. They are owned by the prebuilds system user.';
+
+COMMENT ON COLUMN workspace_prebuilds.pool_id IS 'Null once the pool was deleted, in which case the workspace is deleted too.';
+
 CREATE TABLE workspace_proxies (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -1035,6 +1417,33 @@ CREATE TABLE workspace_resources (
     daily_cost integer DEFAULT 0 NOT NULL
 );
 
+CREATE TABLE workspace_session_recording_chunks (
+    recording_id uuid NOT NULL,
+    chunk_index integer NOT NULL,
+    data bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE workspace_session_recordings (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    type workspace_session_recording_type NOT NULL,
+    reconnect_id uuid,
+    command text NOT NULL,
+    includes_input boolean NOT NULL,
+    size bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of interactive sessions on workspace agents in asciicast v2 format. The recording itself is stored in workspace_session_recording_chunks.';
+
+COMMENT ON COLUMN workspace_session_recordings.reconnect_id IS 'The reconnect ID of the reconnecting PTY session, if the recording is of one.';
+
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'Null while the session is still being recorded.';
+
 CREATE TABLE workspaces (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1048,9 +1457,12 @@ CREATE TABLE workspaces (
     ttl bigint,
     last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
     dormant_at timestamp with time zone,
-    deleting_at timestamp with time zone
+    deleting_at timestamp with time zone,
+    automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL
 );
 
+COMMENT ON COLUMN workspaces.automatic_updates IS 'Whether the workspace is updated to the active version of its template when it is started.';
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('licenses_id_seq'::regclass);
 
 ALTER TABLE ONLY provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
@@ -1072,6 +1484,12 @@ ALTER TABLE ONLY api_keys
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY connection_logs
+    ADD CONSTRAINT connection_logs_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY dbcrypt_keys
     ADD CONSTRAINT dbcrypt_keys_active_key_digest_key UNIQUE (active_key_digest);
 
@@ -1108,6 +1526,24 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, kind);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -1153,6 +1589,12 @@ ALTER TABLE ONLY tailnet_coordinators
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_template_version_id_name_key UNIQUE (template_version_id, name);
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -1171,9 +1613,30 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_log_files
+    ADD CONSTRAINT workspace_agent_log_files_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_log_sources
+    ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_port_share
+    ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
+
+ALTER TABLE ONLY workspace_agent_pty_invites
+    ADD CONSTRAINT workspace_agent_pty_invites_pkey PRIMARY KEY (agent_id, session_id, user_id);
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -1204,6 +1667,12 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_prebuild_pools
+    ADD CONSTRAINT workspace_prebuild_pools_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_prebuilds
+    ADD CONSTRAINT workspace_prebuilds_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
@@ -1219,9 +1688,17 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_pkey PRIMARY KEY (recording_id, chunk_index);
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
+CREATE UNIQUE INDEX custom_roles_lower_name_organization_id_idx ON custom_roles USING btree (lower(name), COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
 CREATE INDEX idx_agent_stats_created_at ON workspace_agent_stats USING btree (created_at);
 
 CREATE INDEX idx_agent_stats_user_id ON workspace_agent_stats USING btree (user_id);
@@ -1256,6 +1733,28 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX connection_logs_connect_time_idx ON connection_logs USING btree (connect_time DESC);
+
+CREATE INDEX connection_logs_workspace_id_idx ON connection_logs USING btree (workspace_id);
+
+CREATE INDEX connection_logs_workspace_owner_id_idx ON connection_logs USING btree (workspace_owner_id);
+
+CREATE INDEX notification_messages_email_next_attempt_at_idx ON notification_messages USING btree (email_next_attempt_at) WHERE (email_next_attempt_at IS NOT NULL);
+
+CREATE INDEX notification_messages_user_id_created_at_idx ON notification_messages USING btree (user_id, created_at DESC);
+
+CREATE UNIQUE INDEX notification_messages_user_id_dedupe_key_idx ON notification_messages USING btree (user_id, dedupe_key) WHERE (dedupe_key <> ''::text);
+
+CREATE UNIQUE INDEX oauth2_provider_app_codes_hashed_secret_idx ON oauth2_provider_app_codes USING btree (hashed_secret);
+
+CREATE UNIQUE INDEX oauth2_provider_app_secrets_hashed_secret_idx ON oauth2_provider_app_secrets USING btree (hashed_secret);
+
+CREATE UNIQUE INDEX oauth2_provider_app_tokens_api_key_id_idx ON oauth2_provider_app_tokens USING btree (api_key_id);
+
+CREATE UNIQUE INDEX oauth2_provider_app_tokens_refresh_hash_idx ON oauth2_provider_app_tokens USING btree (refresh_hash);
+
+CREATE UNIQUE INDEX oauth2_provider_apps_lower_name_idx ON oauth2_provider_apps USING btree (lower(name));
+
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
@@ -1266,6 +1765,18 @@ CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WH
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE (next_attempt_at IS NOT NULL);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
+CREATE UNIQUE INDEX webhooks_lower_name_idx ON webhooks USING btree (lower(name));
+
+CREATE INDEX workspace_agent_pty_invites_user_id_idx ON workspace_agent_pty_invites USING btree (user_id);
+
+CREATE INDEX workspace_agent_log_files_workspace_agent_id_idx ON workspace_agent_log_files USING btree (workspace_agent_id);
+
+CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts USING btree (workspace_agent_id);
+
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_logs USING btree (agent_id, id);
 
 CREATE INDEX workspace_agents_auth_token_idx ON workspace_agents USING btree (auth_token);
@@ -1274,10 +1785,16 @@ CREATE INDEX workspace_agents_resource_id_idx ON workspace_agents USING btree (r
 
 CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING btree (workspace_id);
 
+CREATE INDEX workspace_prebuild_pools_template_id_idx ON workspace_prebuild_pools USING btree (template_id);
+
+CREATE INDEX workspace_prebuilds_pool_id_idx ON workspace_prebuilds USING btree (pool_id);
+
 CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
+CREATE INDEX workspace_session_recordings_workspace_id_idx ON workspace_session_recordings USING btree (workspace_id);
+
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
 
 CREATE TRIGGER tailnet_notify_agent_change AFTER INSERT OR DELETE OR UPDATE ON tailnet_agents FOR EACH ROW EXECUTE FUNCTION tailnet_notify_agent_change();
@@ -1293,6 +1810,9 @@ CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY git_auth_links
     ADD CONSTRAINT git_auth_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 
@@ -1311,6 +1831,27 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -1335,6 +1876,9 @@ ALTER TABLE ONLY tailnet_clients
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -1362,9 +1906,36 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_log_files
+    ADD CONSTRAINT workspace_agent_log_files_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_log_sources
+    ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_port_share
+    ADD CONSTRAINT workspace_agent_port_share_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_pty_invites
+    ADD CONSTRAINT workspace_agent_pty_invites_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_pty_invites
+    ADD CONSTRAINT workspace_agent_pty_invites_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_pty_invites
+    ADD CONSTRAINT workspace_agent_pty_invites_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_pty_invites
+    ADD CONSTRAINT workspace_agent_pty_invites_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -1395,12 +1966,33 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_prebuild_pools
+    ADD CONSTRAINT workspace_prebuild_pools_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuild_pools
+    ADD CONSTRAINT workspace_prebuild_pools_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuilds
+    ADD CONSTRAINT workspace_prebuilds_pool_id_fkey FOREIGN KEY (pool_id) REFERENCES workspace_prebuild_pools(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_prebuilds
+    ADD CONSTRAINT workspace_prebuilds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_recording_id_fkey FOREIGN KEY (recording_id) REFERENCES workspace_session_recordings(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
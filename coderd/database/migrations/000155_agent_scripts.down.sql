@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TYPE workspace_agent_log_source AS ENUM (
+	'startup_script',
+	'shutdown_script',
+	'kubernetes_logs',
+	'envbox',
+	'envbuilder',
+	'external'
+);
+
+ALTER TABLE workspace_agent_logs ADD COLUMN source workspace_agent_log_source NOT NULL DEFAULT 'startup_script';
+ALTER TABLE workspace_agent_logs DROP COLUMN log_source_id;
+
+DROP TABLE workspace_agent_scripts;
+
+DROP TYPE workspace_agent_script_status;
+
+DROP TABLE workspace_agent_log_sources;
+
+COMMIT;
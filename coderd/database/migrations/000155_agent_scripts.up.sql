@@ -0,0 +1,51 @@
+BEGIN;
+
+CREATE TABLE workspace_agent_log_sources (
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	id uuid NOT NULL,
+	created_at timestamptz NOT NULL,
+	display_name varchar(127) NOT NULL,
+	icon text NOT NULL,
+	PRIMARY KEY (workspace_agent_id, id)
+);
+
+CREATE TYPE workspace_agent_script_status AS ENUM (
+	'pending',
+	'running',
+	'ok',
+	'exit_failure',
+	'timed_out'
+);
+
+CREATE TABLE workspace_agent_scripts (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	log_source_id uuid NOT NULL,
+	log_path text NOT NULL,
+	created_at timestamptz NOT NULL,
+	script text NOT NULL,
+	cron text NOT NULL,
+	start_blocks_login boolean NOT NULL,
+	run_on_start boolean NOT NULL,
+	run_on_stop boolean NOT NULL,
+	timeout_seconds integer NOT NULL,
+	display_order integer NOT NULL,
+	status workspace_agent_script_status NOT NULL DEFAULT 'pending',
+	exit_code integer NOT NULL DEFAULT 0,
+	started_at timestamptz,
+	ended_at timestamptz
+);
+
+CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts (workspace_agent_id);
+
+COMMENT ON COLUMN workspace_agent_scripts.status IS 'The status of the most recent execution of the script.';
+COMMENT ON COLUMN workspace_agent_scripts.exit_code IS 'The exit code of the most recent execution of the script.';
+COMMENT ON COLUMN workspace_agent_scripts.display_order IS 'Specifies the order in which scripts are displayed. Scripts run concurrently regardless of their order.';
+
+-- Logs are now attributed to a log source instead of a fixed set of enum
+-- values. Existing logs are assigned to the zero UUID.
+ALTER TABLE workspace_agent_logs ADD COLUMN log_source_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE workspace_agent_logs DROP COLUMN source;
+DROP TYPE workspace_agent_log_source;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE workspace_agent_port_share (
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	port integer NOT NULL,
+	share_level app_sharing_level NOT NULL,
+	PRIMARY KEY (workspace_id, agent_name, port)
+);
+
+COMMENT ON TABLE workspace_agent_port_share IS 'Ports on workspace agents that the workspace owner has shared with other users. Agents are referenced by name so shares survive workspace rebuilds.';
+
+DROP VIEW template_with_users;
+
+ALTER TABLE templates ADD COLUMN max_port_sharing_level app_sharing_level NOT NULL DEFAULT 'owner'::app_sharing_level;
+
+COMMENT ON COLUMN templates.max_port_sharing_level IS 'The maximum sharing level workspace owners can set on ports of workspaces created from this template.';
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
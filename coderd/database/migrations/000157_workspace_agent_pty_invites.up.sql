@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'connect';
+
+CREATE TABLE workspace_agent_pty_invites (
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	session_id uuid NOT NULL,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	read_only boolean NOT NULL,
+	created_by uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (agent_id, session_id, user_id)
+);
+
+COMMENT ON TABLE workspace_agent_pty_invites IS 'Users other than the workspace owner that may attach to a reconnecting PTY session on a workspace agent.';
+COMMENT ON COLUMN workspace_agent_pty_invites.session_id IS 'The reconnect ID of the reconnecting PTY session on the agent.';
+
+CREATE INDEX workspace_agent_pty_invites_user_id_idx ON workspace_agent_pty_invites USING btree (user_id);
+
+COMMIT;
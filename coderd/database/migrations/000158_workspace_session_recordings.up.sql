@@ -0,0 +1,58 @@
+BEGIN;
+
+CREATE TYPE workspace_session_recording_type AS ENUM (
+	'ssh',
+	'reconnecting_pty'
+);
+
+CREATE TABLE workspace_session_recordings (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	type workspace_session_recording_type NOT NULL,
+	reconnect_id uuid,
+	command text NOT NULL,
+	includes_input boolean NOT NULL,
+	size bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of interactive sessions on workspace agents in asciicast v2 format. The recording itself is stored in workspace_session_recording_chunks.';
+COMMENT ON COLUMN workspace_session_recordings.reconnect_id IS 'The reconnect ID of the reconnecting PTY session, if the recording is of one.';
+COMMENT ON COLUMN workspace_session_recordings.ended_at IS 'Null while the session is still being recorded.';
+
+CREATE INDEX workspace_session_recordings_workspace_id_idx ON workspace_session_recordings USING btree (workspace_id);
+
+CREATE TABLE workspace_session_recording_chunks (
+	recording_id uuid NOT NULL REFERENCES workspace_session_recordings(id) ON DELETE CASCADE,
+	chunk_index integer NOT NULL,
+	data bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (recording_id, chunk_index)
+);
+
+DROP VIEW template_with_users;
+
+ALTER TABLE templates ADD COLUMN record_sessions boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.record_sessions IS 'Whether interactive sessions in workspaces created from this template are recorded.';
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
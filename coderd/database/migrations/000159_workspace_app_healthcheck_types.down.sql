@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE workspace_apps
+	DROP COLUMN healthcheck_tcp,
+	DROP COLUMN healthcheck_command;
+
+COMMIT;
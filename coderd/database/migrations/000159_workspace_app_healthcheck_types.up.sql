@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE workspace_apps
+	ADD COLUMN healthcheck_tcp text DEFAULT ''::text NOT NULL,
+	ADD COLUMN healthcheck_command text DEFAULT ''::text NOT NULL;
+
+COMMENT ON COLUMN workspace_apps.healthcheck_tcp IS 'Address the agent connects to over TCP to check the health of the app, used instead of healthcheck_url.';
+COMMENT ON COLUMN workspace_apps.healthcheck_command IS 'Command the agent runs to check the health of the app, used instead of healthcheck_url. A zero exit code means the app is healthy.';
+
+COMMIT;
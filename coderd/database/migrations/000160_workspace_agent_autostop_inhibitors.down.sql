@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE workspace_agents
+	DROP COLUMN autostop_inhibitors;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE workspace_agents
+	ADD COLUMN autostop_inhibitors jsonb DEFAULT '[]'::jsonb NOT NULL;
+
+COMMENT ON COLUMN workspace_agents.autostop_inhibitors IS 'The autostop inhibitors held in the workspace, as reported by the workspace agent. The deadline of the workspace is extended while the agent holds one.';
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE workspace_agent_log_files (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	log_source_id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	path text NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_log_files IS 'Files in the workspace the agent tails into the workspace agent logs, each with its own log source.';
+
+CREATE INDEX workspace_agent_log_files_workspace_agent_id_idx ON workspace_agent_log_files USING btree (workspace_agent_id);
+
+COMMIT;
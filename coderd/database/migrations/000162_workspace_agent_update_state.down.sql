@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE workspace_agents
+	DROP COLUMN update_state,
+	DROP COLUMN update_error;
+
+DROP TYPE workspace_agent_update_state;
+
+COMMIT;
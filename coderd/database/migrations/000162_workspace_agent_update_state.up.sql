@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TYPE workspace_agent_update_state AS ENUM (
+	'unknown',
+	'up_to_date',
+	'disabled',
+	'updating',
+	'updated',
+	'failed'
+);
+
+ALTER TABLE workspace_agents
+	ADD COLUMN update_state workspace_agent_update_state DEFAULT 'unknown' NOT NULL,
+	ADD COLUMN update_error text DEFAULT '' NOT NULL;
+
+COMMENT ON COLUMN workspace_agents.update_state IS 'The state of the agent replacing itself with the agent binary served by coderd, as reported by the workspace agent.';
+
+COMMENT ON COLUMN workspace_agents.update_error IS 'The error of the last failed agent update.';
+
+COMMIT;
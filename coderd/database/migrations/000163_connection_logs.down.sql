@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE connection_logs;
+
+DROP TYPE connection_type;
+
+COMMIT;
@@ -0,0 +1,48 @@
+BEGIN;
+
+CREATE TYPE connection_type AS ENUM (
+	'ssh',
+	'vscode',
+	'jetbrains',
+	'reconnecting_pty',
+	'port_forwarding',
+	'workspace_app'
+);
+
+-- Connection logs are kept for compliance, so they deliberately don't
+-- reference the workspace, agent or users they describe. Rows are deleted by
+-- dbpurge once they are older than the configured retention.
+CREATE TABLE connection_logs (
+	id uuid NOT NULL PRIMARY KEY,
+	connect_time timestamp with time zone NOT NULL,
+	disconnect_time timestamp with time zone,
+	organization_id uuid NOT NULL,
+	workspace_owner_id uuid NOT NULL,
+	workspace_id uuid NOT NULL,
+	workspace_name text NOT NULL,
+	agent_id uuid NOT NULL,
+	agent_name text NOT NULL,
+	type connection_type NOT NULL,
+	user_id uuid,
+	ip inet,
+	user_agent text,
+	slug_or_port text DEFAULT '' NOT NULL,
+	code integer,
+	bytes_sent bigint DEFAULT 0 NOT NULL,
+	bytes_received bigint DEFAULT 0 NOT NULL,
+	disconnect_reason text DEFAULT '' NOT NULL
+);
+
+COMMENT ON TABLE connection_logs IS 'Connections to workspaces, as reported by workspace agents and the workspace app proxy.';
+COMMENT ON COLUMN connection_logs.disconnect_time IS 'Null while the connection is open. Workspace app accesses never have a disconnect time.';
+COMMENT ON COLUMN connection_logs.user_id IS 'The user that connected, if known. Agents don''t know who is connecting to them.';
+COMMENT ON COLUMN connection_logs.slug_or_port IS 'The slug of the workspace app, or the port of a port forward.';
+COMMENT ON COLUMN connection_logs.code IS 'The exit code of SSH and reconnecting PTY sessions, or the HTTP status code of workspace app accesses.';
+COMMENT ON COLUMN connection_logs.bytes_sent IS 'Bytes sent from the workspace to the client.';
+COMMENT ON COLUMN connection_logs.bytes_received IS 'Bytes received by the workspace from the client.';
+
+CREATE INDEX connection_logs_connect_time_idx ON connection_logs USING btree (connect_time DESC);
+CREATE INDEX connection_logs_workspace_id_idx ON connection_logs USING btree (workspace_id);
+CREATE INDEX connection_logs_workspace_owner_id_idx ON connection_logs USING btree (workspace_owner_id);
+
+COMMIT;
@@ -0,0 +1,51 @@
+BEGIN;
+
+CREATE TYPE webhook_event AS ENUM (
+	'workspace_created',
+	'workspace_started',
+	'workspace_stopped',
+	'workspace_deleted',
+	'workspace_build_failed',
+	'template_version_promoted',
+	'user_created'
+);
+
+CREATE TABLE webhooks (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name text NOT NULL,
+	url text NOT NULL,
+	secret text NOT NULL,
+	events webhook_event[] NOT NULL,
+	enabled boolean DEFAULT true NOT NULL
+);
+
+COMMENT ON TABLE webhooks IS 'Endpoints that receive events as signed JSON payloads.';
+COMMENT ON COLUMN webhooks.secret IS 'The key payloads are signed with using HMAC-SHA256.';
+
+CREATE UNIQUE INDEX webhooks_lower_name_idx ON webhooks USING btree (lower(name));
+
+CREATE TABLE webhook_deliveries (
+	id uuid NOT NULL PRIMARY KEY,
+	webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	event webhook_event NOT NULL,
+	payload jsonb NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	attempts integer DEFAULT 0 NOT NULL,
+	next_attempt_at timestamp with time zone,
+	last_attempt_at timestamp with time zone,
+	status_code integer,
+	error text DEFAULT '' NOT NULL,
+	delivered_at timestamp with time zone
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'Events queued for and delivered to webhooks. Any replica may deliver them.';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the delivery is attempted next. Null once it was delivered or it ran out of attempts.';
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'The HTTP status code of the last attempt, if the endpoint responded.';
+COMMENT ON COLUMN webhook_deliveries.error IS 'Why the last attempt failed.';
+
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE (next_attempt_at IS NOT NULL);
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
+COMMIT;
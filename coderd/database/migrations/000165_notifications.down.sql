@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE notification_preferences;
+
+DROP TABLE notification_messages;
+
+DROP TYPE notification_kind;
+
+COMMIT;
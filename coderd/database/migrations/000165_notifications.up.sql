@@ -0,0 +1,45 @@
+BEGIN;
+
+CREATE TYPE notification_kind AS ENUM (
+	'workspace_autostop',
+	'workspace_dormant',
+	'workspace_marked_for_deletion',
+	'workspace_build_failed',
+	'account_dormant'
+);
+
+CREATE TABLE notification_messages (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	kind notification_kind NOT NULL,
+	title text NOT NULL,
+	content text NOT NULL,
+	dedupe_key text DEFAULT '' NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	read_at timestamp with time zone,
+	email_attempts integer DEFAULT 0 NOT NULL,
+	email_next_attempt_at timestamp with time zone,
+	emailed_at timestamp with time zone,
+	email_error text DEFAULT '' NOT NULL
+);
+
+COMMENT ON TABLE notification_messages IS 'Notifications shown in the inbox of users, and optionally emailed to them. Any replica may send the emails.';
+COMMENT ON COLUMN notification_messages.dedupe_key IS 'Identifies the event the notification is about, so it is only sent once. Empty if notifications are never deduplicated.';
+COMMENT ON COLUMN notification_messages.email_next_attempt_at IS 'When sending the email is attempted next. Null if no email is sent, once it was sent or it ran out of attempts.';
+COMMENT ON COLUMN notification_messages.email_error IS 'Why the last attempt to send the email failed.';
+
+CREATE UNIQUE INDEX notification_messages_user_id_dedupe_key_idx ON notification_messages USING btree (user_id, dedupe_key) WHERE (dedupe_key <> ''::text);
+CREATE INDEX notification_messages_user_id_created_at_idx ON notification_messages USING btree (user_id, created_at DESC);
+CREATE INDEX notification_messages_email_next_attempt_at_idx ON notification_messages USING btree (email_next_attempt_at) WHERE (email_next_attempt_at IS NOT NULL);
+
+CREATE TABLE notification_preferences (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	kind notification_kind NOT NULL,
+	disabled boolean DEFAULT false NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, kind)
+);
+
+COMMENT ON TABLE notification_preferences IS 'The kinds of notifications users opted out of. Users receive all kinds of notifications without a preference.';
+
+COMMIT;
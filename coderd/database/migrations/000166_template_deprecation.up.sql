@@ -0,0 +1,27 @@
+BEGIN;
+
+DROP VIEW template_with_users;
+
+ALTER TABLE templates ADD COLUMN deprecation_message text NOT NULL DEFAULT '';
+ALTER TABLE templates ADD COLUMN deprecated_at timestamp with time zone;
+
+COMMENT ON COLUMN templates.deprecation_message IS 'If set, the template is deprecated and can''t be used to create new workspaces. The message tells users what to use instead.';
+COMMENT ON COLUMN templates.deprecated_at IS 'When the template was deprecated. Null if the template isn''t deprecated.';
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
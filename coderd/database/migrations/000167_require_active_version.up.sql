@@ -0,0 +1,34 @@
+BEGIN;
+
+DROP VIEW template_with_users;
+
+ALTER TABLE templates ADD COLUMN require_active_version boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.require_active_version IS 'Whether workspaces must be updated to the active version of the template when their owner starts them.';
+
+CREATE TYPE automatic_updates AS ENUM (
+	'always',
+	'never'
+);
+
+ALTER TABLE workspaces ADD COLUMN automatic_updates automatic_updates NOT NULL DEFAULT 'never';
+
+COMMENT ON COLUMN workspaces.automatic_updates IS 'Whether the workspace is updated to the active version of its template when it is started.';
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE workspace_prebuilds;
+
+DROP TABLE workspace_prebuild_pools;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+CREATE TABLE workspace_prebuild_pools (
+	id uuid NOT NULL PRIMARY KEY,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+	desired_instances integer NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	CONSTRAINT desired_instances_not_negative CHECK (desired_instances >= 0)
+);
+
+COMMENT ON TABLE workspace_prebuild_pools IS 'Pools of prebuilt workspaces. Creating a workspace with the template version and parameters of a pool claims one of its workspaces.';
+COMMENT ON COLUMN workspace_prebuild_pools.parameters IS 'The rich parameter values the workspaces of the pool are built with, as an array of name and value objects.';
+COMMENT ON COLUMN workspace_prebuild_pools.desired_instances IS 'The number of ready workspaces the pool is kept at.';
+
+CREATE INDEX workspace_prebuild_pools_template_id_idx ON workspace_prebuild_pools USING btree (template_id);
+
+CREATE TABLE workspace_prebuilds (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	pool_id uuid REFERENCES workspace_prebuild_pools (id) ON DELETE SET NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_prebuilds IS 'Prebuilt workspaces that were not claimed yet. They are owned by the prebuilds system user.';
+COMMENT ON COLUMN workspace_prebuilds.pool_id IS 'Null once the pool was deleted, in which case the workspace is deleted too.';
+
+CREATE INDEX workspace_prebuilds_pool_id_idx ON workspace_prebuilds USING btree (pool_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE template_version_presets;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE template_version_presets (
+	id uuid NOT NULL PRIMARY KEY,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	UNIQUE (template_version_id, name)
+);
+
+COMMENT ON TABLE template_version_presets IS 'Named sets of rich parameter values declared by a template version.';
+COMMENT ON COLUMN template_version_presets.parameters IS 'The rich parameter values of the preset, as an array of name and value objects.';
+
+COMMIT;
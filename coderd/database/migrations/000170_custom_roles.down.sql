@@ -0,0 +1,3 @@
+-- The custom_role resource type is left in place, enum values cannot be
+-- removed.
+DROP TABLE IF EXISTS custom_roles;
@@ -0,0 +1,22 @@
+-- This has to be outside a transaction
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'custom_role';
+
+CREATE TABLE custom_roles (
+	id uuid NOT NULL PRIMARY KEY,
+	name text NOT NULL,
+	display_name text NOT NULL,
+	organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+	site_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+	org_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+	user_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined by administrators in addition to the built-in roles.';
+COMMENT ON COLUMN custom_roles.organization_id IS 'Roles with an organization are assigned to organization members, roles without one are site wide.';
+COMMENT ON COLUMN custom_roles.site_permissions IS 'The site wide permissions of the role, as an array of rbac permissions.';
+COMMENT ON COLUMN custom_roles.org_permissions IS 'The permissions of the role in its organization, as an array of rbac permissions.';
+COMMENT ON COLUMN custom_roles.user_permissions IS 'The permissions of the role on resources owned by the user, as an array of rbac permissions.';
+
+CREATE UNIQUE INDEX custom_roles_lower_name_organization_id_idx ON custom_roles USING btree (lower(name), COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
@@ -0,0 +1,6 @@
+-- The custom scope is left in place, enum values cannot be removed. Keys with
+-- the custom scope are deleted so they don't gain the permissions of their
+-- user.
+DELETE FROM api_keys WHERE scope = 'custom'::api_key_scope;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scope_rules;
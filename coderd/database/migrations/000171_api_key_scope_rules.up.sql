@@ -0,0 +1,6 @@
+-- This has to be outside a transaction
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'custom';
+
+ALTER TABLE api_keys ADD COLUMN scope_rules jsonb NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN api_keys.scope_rules IS 'The rules of keys with the custom scope. The key can only perform the actions the rules allow, on top of the roles of its user.';
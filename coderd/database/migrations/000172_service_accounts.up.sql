@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN is_service_account boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN users.is_service_account IS 'Service accounts are non-human users for automation. They cannot log in interactively and are excluded from license seat counts and DAUs.';
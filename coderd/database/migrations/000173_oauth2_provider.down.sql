@@ -0,0 +1,6 @@
+-- The oauth2_provider_app login type and resource types are left in place,
+-- enum values cannot be removed.
+DROP TABLE IF EXISTS oauth2_provider_app_tokens;
+DROP TABLE IF EXISTS oauth2_provider_app_codes;
+DROP TABLE IF EXISTS oauth2_provider_app_secrets;
+DROP TABLE IF EXISTS oauth2_provider_apps;
@@ -0,0 +1,64 @@
+-- This has to be outside a transaction
+ALTER TYPE login_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app';
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app';
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app_secret';
+
+CREATE TABLE oauth2_provider_apps (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name text NOT NULL,
+	icon text NOT NULL,
+	redirect_uris text[] NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Third-party apps that act on behalf of users through the OAuth2 authorization code flow, with Coder as the authorization server.';
+COMMENT ON COLUMN oauth2_provider_apps.redirect_uris IS 'Users are only redirected back to an app at one of these URIs.';
+
+CREATE UNIQUE INDEX oauth2_provider_apps_lower_name_idx ON oauth2_provider_apps USING btree (lower(name));
+
+CREATE TABLE oauth2_provider_app_secrets (
+	id uuid NOT NULL PRIMARY KEY,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone,
+	hashed_secret bytea NOT NULL,
+	display_secret text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_secrets IS 'Client secrets apps authenticate to the token endpoint with.';
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The last characters of the secret, to tell secrets apart.';
+
+CREATE UNIQUE INDEX oauth2_provider_app_secrets_hashed_secret_idx ON oauth2_provider_app_secrets USING btree (hashed_secret);
+
+CREATE TABLE oauth2_provider_app_codes (
+	id uuid NOT NULL PRIMARY KEY,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	hashed_secret bytea NOT NULL,
+	redirect_uri text NOT NULL,
+	scope text NOT NULL,
+	code_challenge text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes issued to apps when users authorize them. A code is exchanged for a token once.';
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect_uri of the authorization request, empty if it was left out. The token request must send the same one.';
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The PKCE S256 code challenge. The app has to send the matching verifier with the code.';
+
+CREATE UNIQUE INDEX oauth2_provider_app_codes_hashed_secret_idx ON oauth2_provider_app_codes USING btree (hashed_secret);
+
+CREATE TABLE oauth2_provider_app_tokens (
+	id uuid NOT NULL PRIMARY KEY,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	api_key_id text NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	refresh_hash bytea NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'Refresh tokens of the API keys issued to apps. Deleting the API key revokes the refresh token.';
+
+CREATE UNIQUE INDEX oauth2_provider_app_tokens_refresh_hash_idx ON oauth2_provider_app_tokens USING btree (refresh_hash);
+CREATE UNIQUE INDEX oauth2_provider_app_tokens_api_key_id_idx ON oauth2_provider_app_tokens USING btree (api_key_id);
@@ -0,0 +1 @@
+ALTER TABLE tailnet_clients DROP COLUMN user_id;
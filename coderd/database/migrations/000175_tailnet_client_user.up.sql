@@ -0,0 +1,3 @@
+ALTER TABLE tailnet_clients ADD COLUMN user_id uuid;
+
+COMMENT ON COLUMN tailnet_clients.user_id IS 'The user that the client authenticated as. Null for clients that coordinate on behalf of others, such as workspace proxies.';
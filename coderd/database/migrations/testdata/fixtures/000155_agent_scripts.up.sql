@@ -0,0 +1,51 @@
+INSERT INTO workspace_agent_log_sources (
+	workspace_agent_id,
+	id,
+	created_at,
+	display_name,
+	icon
+)
+VALUES (
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'0ff953c0-92a6-4fe6-a415-eb0139a36ad1',
+	'2023-08-01 00:00:00+00',
+	'Startup Script',
+	'/emojis/25b6.png'
+);
+
+INSERT INTO workspace_agent_scripts (
+	id,
+	workspace_agent_id,
+	log_source_id,
+	log_path,
+	created_at,
+	script,
+	cron,
+	start_blocks_login,
+	run_on_start,
+	run_on_stop,
+	timeout_seconds,
+	display_order,
+	status,
+	exit_code,
+	started_at,
+	ended_at
+)
+VALUES (
+	'ee6fe5ac-cbc3-4e0b-8c3a-2c1d1a1ff3b7',
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'0ff953c0-92a6-4fe6-a415-eb0139a36ad1',
+	'coder-startup-script.log',
+	'2023-08-01 00:00:00+00',
+	'echo "Hello, world!"',
+	'',
+	true,
+	true,
+	false,
+	300,
+	0,
+	'ok',
+	0,
+	'2023-08-01 00:00:01+00',
+	'2023-08-01 00:00:02+00'
+);
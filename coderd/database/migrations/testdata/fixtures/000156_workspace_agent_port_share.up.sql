@@ -0,0 +1,12 @@
+INSERT INTO workspace_agent_port_share (
+	workspace_id,
+	agent_name,
+	port,
+	share_level
+)
+VALUES (
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'main',
+	3000,
+	'authenticated'
+);
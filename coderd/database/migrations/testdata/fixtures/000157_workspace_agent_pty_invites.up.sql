@@ -0,0 +1,18 @@
+INSERT INTO workspace_agent_pty_invites (
+	workspace_id,
+	agent_id,
+	session_id,
+	user_id,
+	read_only,
+	created_by,
+	created_at
+)
+VALUES (
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'c5a6a6bb-59c0-4e6b-bd41-1a4f1d4e0d23',
+	'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+	true,
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'2023-09-20 12:00:00+00'
+);
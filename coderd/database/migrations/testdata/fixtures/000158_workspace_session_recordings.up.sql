@@ -0,0 +1,39 @@
+INSERT INTO workspace_session_recordings (
+	id,
+	workspace_id,
+	agent_id,
+	type,
+	reconnect_id,
+	command,
+	includes_input,
+	size,
+	created_at,
+	updated_at,
+	ended_at
+)
+VALUES (
+	'8f7a2a0c-6b7d-4d0b-9d6e-2c1f0b6a3e41',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'ssh',
+	NULL,
+	'',
+	false,
+	75,
+	'2023-09-20 12:00:00+00',
+	'2023-09-20 12:05:00+00',
+	'2023-09-20 12:05:00+00'
+);
+
+INSERT INTO workspace_session_recording_chunks (
+	recording_id,
+	chunk_index,
+	data,
+	created_at
+)
+VALUES (
+	'8f7a2a0c-6b7d-4d0b-9d6e-2c1f0b6a3e41',
+	0,
+	convert_to(E'{"version":2,"width":80,"height":24,"timestamp":1695211200}\n[0.5,"o","$ "]\n', 'UTF8'),
+	'2023-09-20 12:05:00+00'
+);
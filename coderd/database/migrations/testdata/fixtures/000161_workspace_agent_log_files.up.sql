@@ -0,0 +1,29 @@
+INSERT INTO workspace_agent_log_sources (
+	workspace_agent_id,
+	id,
+	created_at,
+	display_name,
+	icon
+)
+VALUES (
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'5b1b0c8e-7a4d-4a2f-9f5e-3c6e8d2a1b47',
+	'2023-10-01 12:00:00+00',
+	'App',
+	''
+);
+
+INSERT INTO workspace_agent_log_files (
+	id,
+	workspace_agent_id,
+	log_source_id,
+	created_at,
+	path
+)
+VALUES (
+	'd3c7e2a1-4f6b-4e8d-9a2c-7b1e5f3d9c60',
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'5b1b0c8e-7a4d-4a2f-9f5e-3c6e8d2a1b47',
+	'2023-10-01 12:00:00+00',
+	'/var/log/app.log'
+);
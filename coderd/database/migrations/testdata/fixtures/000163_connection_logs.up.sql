@@ -0,0 +1,40 @@
+INSERT INTO connection_logs (
+	id,
+	connect_time,
+	disconnect_time,
+	organization_id,
+	workspace_owner_id,
+	workspace_id,
+	workspace_name,
+	agent_id,
+	agent_name,
+	type,
+	user_id,
+	ip,
+	user_agent,
+	slug_or_port,
+	code,
+	bytes_sent,
+	bytes_received,
+	disconnect_reason
+)
+VALUES (
+	'c1a4e2b6-0f3d-4a8e-9b7c-5d2e1f0a9b8c',
+	'2023-09-20 12:00:00+00',
+	'2023-09-20 12:05:00+00',
+	'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'workspace',
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'main',
+	'ssh',
+	NULL,
+	'fd7a:115c:a1e0:49d6:b259:b7ac:b1b2:48f3',
+	NULL,
+	'',
+	0,
+	1024,
+	128,
+	''
+);
@@ -0,0 +1,47 @@
+INSERT INTO webhooks (
+	id,
+	created_at,
+	updated_at,
+	name,
+	url,
+	secret,
+	events,
+	enabled
+)
+VALUES (
+	'8f4b3c2a-6d1e-4f7a-9b0c-2e5d8a1f3b6c',
+	'2023-09-20 12:00:00+00',
+	'2023-09-20 12:00:00+00',
+	'cmdb',
+	'https://cmdb.example.com/hooks/coder',
+	'secret',
+	'{workspace_created,workspace_deleted}',
+	true
+);
+
+INSERT INTO webhook_deliveries (
+	id,
+	webhook_id,
+	event,
+	payload,
+	created_at,
+	attempts,
+	next_attempt_at,
+	last_attempt_at,
+	status_code,
+	error,
+	delivered_at
+)
+VALUES (
+	'4d7e1a9b-3c5f-4b2e-8a6d-0f1c9e7b5a3d',
+	'8f4b3c2a-6d1e-4f7a-9b0c-2e5d8a1f3b6c',
+	'workspace_created',
+	'{"event": "workspace_created"}',
+	'2023-09-20 12:05:00+00',
+	1,
+	NULL,
+	'2023-09-20 12:05:00+00',
+	200,
+	'',
+	'2023-09-20 12:05:01+00'
+);
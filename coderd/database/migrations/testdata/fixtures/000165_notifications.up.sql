@@ -0,0 +1,41 @@
+INSERT INTO notification_messages (
+	id,
+	user_id,
+	kind,
+	title,
+	content,
+	dedupe_key,
+	created_at,
+	read_at,
+	email_attempts,
+	email_next_attempt_at,
+	emailed_at,
+	email_error
+)
+VALUES (
+	'6e2f9a4c-1b7d-4c3e-8f5a-9d0b2c7e4a1f',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'workspace_dormant',
+	'Workspace "workspace" is dormant',
+	'Your workspace "workspace" was marked dormant because it was not used since 2023-09-13 12:00 UTC.',
+	'workspace_dormant:3a9a1feb-e89d-457c-9d53-ac751b198ebe:2023-09-20T12:00:00Z',
+	'2023-09-20 12:00:00+00',
+	NULL,
+	1,
+	NULL,
+	'2023-09-20 12:00:05+00',
+	''
+);
+
+INSERT INTO notification_preferences (
+	user_id,
+	kind,
+	disabled,
+	updated_at
+)
+VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'workspace_autostop',
+	true,
+	'2023-09-20 12:00:00+00'
+);
@@ -0,0 +1,29 @@
+INSERT INTO workspace_prebuild_pools (
+	id,
+	template_id,
+	template_version_id,
+	parameters,
+	desired_instances,
+	created_at,
+	updated_at
+)
+VALUES (
+	'5c1e8b7a-2f4d-4a9e-b3c6-7d0e9f1a2b4c',
+	'4cc1f466-f326-477e-8762-9d0c6781fc56',
+	'920baba5-4c64-4686-8b7d-d1bef5683eae',
+	'[{"name": "region", "value": "eu"}]',
+	2,
+	'2023-09-20 12:00:00+00',
+	'2023-09-20 12:00:00+00'
+);
+
+INSERT INTO workspace_prebuilds (
+	workspace_id,
+	pool_id,
+	created_at
+)
+VALUES (
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'5c1e8b7a-2f4d-4a9e-b3c6-7d0e9f1a2b4c',
+	'2023-09-20 12:00:00+00'
+);
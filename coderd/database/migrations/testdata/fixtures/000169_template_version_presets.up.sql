@@ -0,0 +1,14 @@
+INSERT INTO template_version_presets (
+	id,
+	template_version_id,
+	name,
+	parameters,
+	created_at
+)
+VALUES (
+	'8d2b6f3e-9c1a-4e7b-a5d0-3f6c2e8b1a9d',
+	'920baba5-4c64-4686-8b7d-d1bef5683eae',
+	'small',
+	'[{"name": "region", "value": "eu"}]',
+	'2023-09-20 12:00:00+00'
+);
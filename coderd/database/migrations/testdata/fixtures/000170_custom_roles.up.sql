@@ -0,0 +1,22 @@
+INSERT INTO custom_roles (
+	id,
+	name,
+	display_name,
+	organization_id,
+	site_permissions,
+	org_permissions,
+	user_permissions,
+	created_at,
+	updated_at
+)
+VALUES (
+	'5a1c3e7b-2d4f-4b8a-9e6c-0f3d2b1a8c7e',
+	'support',
+	'Support',
+	NULL,
+	'[{"negate": false, "resource_type": "workspace", "action": "read"}]',
+	'[]',
+	'[]',
+	'2023-09-20 12:00:00+00',
+	'2023-09-20 12:00:00+00'
+);
@@ -0,0 +1,73 @@
+INSERT INTO oauth2_provider_apps (
+	id,
+	created_at,
+	updated_at,
+	name,
+	icon,
+	redirect_uris
+)
+VALUES (
+	'4c7a2e91-6b3d-4f8e-a1c5-9d2b7e0f3a64',
+	'2023-09-20 12:00:00+00',
+	'2023-09-20 12:00:00+00',
+	'dashboard',
+	'/icon/code.svg',
+	'{https://dashboard.example.com/callback}'
+);
+
+INSERT INTO oauth2_provider_app_secrets (
+	id,
+	app_id,
+	created_at,
+	last_used_at,
+	hashed_secret,
+	display_secret
+)
+VALUES (
+	'b1e3d5f7-2a4c-4e6b-8d0f-1a3c5e7b9d2f',
+	'4c7a2e91-6b3d-4f8e-a1c5-9d2b7e0f3a64',
+	'2023-09-20 12:00:00+00',
+	NULL,
+	'\x9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08',
+	'a3f9c1'
+);
+
+INSERT INTO oauth2_provider_app_codes (
+	id,
+	app_id,
+	user_id,
+	created_at,
+	expires_at,
+	hashed_secret,
+	redirect_uri,
+	scope,
+	code_challenge
+)
+VALUES (
+	'7d2f4b6e-8a1c-4e3d-9f5b-2c4e6a8d0b1f',
+	'4c7a2e91-6b3d-4f8e-a1c5-9d2b7e0f3a64',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'2023-09-20 12:00:00+00',
+	'2023-09-20 12:10:00+00',
+	'\x60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752',
+	'https://dashboard.example.com/callback',
+	'all',
+	'E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM'
+);
+
+INSERT INTO oauth2_provider_app_tokens (
+	id,
+	app_id,
+	api_key_id,
+	created_at,
+	expires_at,
+	refresh_hash
+)
+VALUES (
+	'e5a7c9b1-3d5f-4a7c-9e1b-3d5f7a9c1e3b',
+	'4c7a2e91-6b3d-4f8e-a1c5-9d2b7e0f3a64',
+	'WEG2T4MNno',
+	'2023-09-20 12:00:00+00',
+	'2023-10-20 12:00:00+00',
+	'\xfcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9'
+);
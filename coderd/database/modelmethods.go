@@ -1,11 +1,13 @@
 package database
 
 import (
+	"encoding/json"
 	"sort"
 	"strconv"
 	"time"
 
 	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -98,6 +100,20 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 	}
 }
 
+// RBACScope returns the scope of the key. Keys with the custom scope can only
+// perform the actions allowed by their scope rules.
+func (k APIKey) RBACScope() (rbac.ExpandableScope, error) {
+	if k.Scope != APIKeyScopeCustom {
+		return rbac.ScopeName(k.Scope), nil
+	}
+	var rules []rbac.ScopeRule
+	err := json.Unmarshal(k.ScopeRules, &rules)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal scope rules: %w", err)
+	}
+	return rbac.CustomScope(rules), nil
+}
+
 func (k APIKey) RBACObject() rbac.Object {
 	return rbac.ResourceAPIKey.WithIDString(k.ID).
 		WithOwner(k.UserID.String())
@@ -185,6 +201,15 @@ func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Objec
 		WithOwner(w.OwnerID.String())
 }
 
+// SessionRecordingRBAC returns the object for recordings of sessions in the
+// workspace. Recordings are not owned by the workspace owner, so they can't
+// read or remove them.
+func (w Workspace) SessionRecordingRBAC() rbac.Object {
+	return rbac.ResourceSessionRecording.
+		WithID(w.ID).
+		InOrg(w.OrganizationID)
+}
+
 func (w Workspace) DormantRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceDormant.
 		WithID(w.ID).
@@ -225,6 +250,38 @@ func (w WorkspaceProxy) IsPrimary() bool {
 	return w.Name == "primary"
 }
 
+func (w Webhook) RBACObject() rbac.Object {
+	return rbac.ResourceWebhook.
+		WithID(w.ID)
+}
+
+func (a OAuth2ProviderApp) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderApp.
+		WithID(a.ID)
+}
+
+func (s OAuth2ProviderAppSecret) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppSecret.
+		WithID(s.ID)
+}
+
+func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppCodeToken.
+		WithID(c.ID).
+		WithOwner(c.UserID.String())
+}
+
+// RBACObject returns the role assignment object of the organization for
+// organization roles, and the site wide one otherwise.
+func (r CustomRole) RBACObject() rbac.Object {
+	if r.OrganizationID.Valid {
+		return rbac.ResourceOrgRoleAssignment.WithID(r.ID).
+			InOrg(r.OrganizationID.UUID)
+	}
+	return rbac.ResourceRoleAssignment.
+		WithID(r.ID)
+}
+
 func (f File) RBACObject() rbac.Object {
 	return rbac.ResourceFile.
 		WithID(f.ID).
@@ -260,6 +317,10 @@ func (u UserLink) RBACObject() rbac.Object {
 	return rbac.ResourceUserData.WithOwner(u.UserID.String()).WithID(u.UserID)
 }
 
+func (m NotificationMessage) RBACObject() rbac.Object {
+	return rbac.ResourceUserData.WithID(m.ID).WithOwner(m.UserID.String())
+}
+
 func (l License) RBACObject() rbac.Object {
 	return rbac.ResourceLicense.WithIDString(strconv.FormatInt(int64(l.ID), 10))
 }
@@ -323,18 +384,19 @@ func ConvertUserRows(rows []GetUsersRow) []User {
 	users := make([]User, len(rows))
 	for i, r := range rows {
 		users[i] = User{
-			ID:             r.ID,
-			Email:          r.Email,
-			Username:       r.Username,
-			HashedPassword: r.HashedPassword,
-			CreatedAt:      r.CreatedAt,
-			UpdatedAt:      r.UpdatedAt,
-			Status:         r.Status,
-			RBACRoles:      r.RBACRoles,
-			LoginType:      r.LoginType,
-			AvatarURL:      r.AvatarURL,
-			Deleted:        r.Deleted,
-			LastSeenAt:     r.LastSeenAt,
+			ID:               r.ID,
+			Email:            r.Email,
+			Username:         r.Username,
+			HashedPassword:   r.HashedPassword,
+			CreatedAt:        r.CreatedAt,
+			UpdatedAt:        r.UpdatedAt,
+			Status:           r.Status,
+			RBACRoles:        r.RBACRoles,
+			LoginType:        r.LoginType,
+			AvatarURL:        r.AvatarURL,
+			Deleted:          r.Deleted,
+			LastSeenAt:       r.LastSeenAt,
+			IsServiceAccount: r.IsServiceAccount,
 		}
 	}
 
@@ -358,6 +420,7 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			LastUsedAt:        r.LastUsedAt,
 			DormantAt:         r.DormantAt,
 			DeletingAt:        r.DeletingAt,
+			AutomaticUpdates:  r.AutomaticUpdates,
 		}
 	}
 
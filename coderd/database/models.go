@@ -21,6 +21,7 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeCustom             APIKeyScope = "custom"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +62,8 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom:
 		return true
 	}
 	return false
@@ -71,6 +73,7 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom,
 	}
 }
 
@@ -146,6 +149,7 @@ const (
 	AuditActionLogin    AuditAction = "login"
 	AuditActionLogout   AuditAction = "logout"
 	AuditActionRegister AuditAction = "register"
+	AuditActionConnect  AuditAction = "connect"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -192,7 +196,8 @@ func (e AuditAction) Valid() bool {
 		AuditActionStop,
 		AuditActionLogin,
 		AuditActionLogout,
-		AuditActionRegister:
+		AuditActionRegister,
+		AuditActionConnect:
 		return true
 	}
 	return false
@@ -208,6 +213,65 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionLogin,
 		AuditActionLogout,
 		AuditActionRegister,
+		AuditActionConnect,
+	}
+}
+
+type AutomaticUpdates string
+
+const (
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	AutomaticUpdatesNever  AutomaticUpdates = "never"
+)
+
+func (e *AutomaticUpdates) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AutomaticUpdates(s)
+	case string:
+		*e = AutomaticUpdates(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AutomaticUpdates: %T", src)
+	}
+	return nil
+}
+
+type NullAutomaticUpdates struct {
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates"`
+	Valid            bool             `json:"valid"` // Valid is true if AutomaticUpdates is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAutomaticUpdates) Scan(value interface{}) error {
+	if value == nil {
+		ns.AutomaticUpdates, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AutomaticUpdates.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAutomaticUpdates) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AutomaticUpdates), nil
+}
+
+func (e AutomaticUpdates) Valid() bool {
+	switch e {
+	case AutomaticUpdatesAlways,
+		AutomaticUpdatesNever:
+		return true
+	}
+	return false
+}
+
+func AllAutomaticUpdatesValues() []AutomaticUpdates {
+	return []AutomaticUpdates{
+		AutomaticUpdatesAlways,
+		AutomaticUpdatesNever,
 	}
 }
 
@@ -281,6 +345,76 @@ func AllBuildReasonValues() []BuildReason {
 	}
 }
 
+type ConnectionType string
+
+const (
+	ConnectionTypeSSH             ConnectionType = "ssh"
+	ConnectionTypeVSCode          ConnectionType = "vscode"
+	ConnectionTypeJetBrains       ConnectionType = "jetbrains"
+	ConnectionTypeReconnectingPTY ConnectionType = "reconnecting_pty"
+	ConnectionTypePortForwarding  ConnectionType = "port_forwarding"
+	ConnectionTypeWorkspaceApp    ConnectionType = "workspace_app"
+)
+
+func (e *ConnectionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ConnectionType(s)
+	case string:
+		*e = ConnectionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ConnectionType: %T", src)
+	}
+	return nil
+}
+
+type NullConnectionType struct {
+	ConnectionType ConnectionType `json:"connection_type"`
+	Valid          bool           `json:"valid"` // Valid is true if ConnectionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullConnectionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ConnectionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ConnectionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullConnectionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ConnectionType), nil
+}
+
+func (e ConnectionType) Valid() bool {
+	switch e {
+	case ConnectionTypeSSH,
+		ConnectionTypeVSCode,
+		ConnectionTypeJetBrains,
+		ConnectionTypeReconnectingPTY,
+		ConnectionTypePortForwarding,
+		ConnectionTypeWorkspaceApp:
+		return true
+	}
+	return false
+}
+
+func AllConnectionTypeValues() []ConnectionType {
+	return []ConnectionType{
+		ConnectionTypeSSH,
+		ConnectionTypeVSCode,
+		ConnectionTypeJetBrains,
+		ConnectionTypeReconnectingPTY,
+		ConnectionTypePortForwarding,
+		ConnectionTypeWorkspaceApp,
+	}
+}
+
 type DisplayApp string
 
 const (
@@ -535,11 +669,13 @@ func AllLogSourceValues() []LogSource {
 type LoginType string
 
 const (
-	LoginTypePassword LoginType = "password"
-	LoginTypeGithub   LoginType = "github"
-	LoginTypeOIDC     LoginType = "oidc"
-	LoginTypeToken    LoginType = "token"
-	LoginTypeNone     LoginType = "none"
+	LoginTypePassword          LoginType = "password"
+	LoginTypeGithub            LoginType = "github"
+	LoginTypeOIDC              LoginType = "oidc"
+	LoginTypeToken             LoginType = "token"
+	LoginTypeNone              LoginType = "none"
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
+	LoginTypeLDAP              LoginType = "ldap"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -583,7 +719,9 @@ func (e LoginType) Valid() bool {
 		LoginTypeGithub,
 		LoginTypeOIDC,
 		LoginTypeToken,
-		LoginTypeNone:
+		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP:
 		return true
 	}
 	return false
@@ -596,6 +734,75 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeOIDC,
 		LoginTypeToken,
 		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP,
+	}
+}
+
+type NotificationKind string
+
+const (
+	NotificationKindWorkspaceAutostop          NotificationKind = "workspace_autostop"
+	NotificationKindWorkspaceDormant           NotificationKind = "workspace_dormant"
+	NotificationKindWorkspaceMarkedForDeletion NotificationKind = "workspace_marked_for_deletion"
+	NotificationKindWorkspaceBuildFailed       NotificationKind = "workspace_build_failed"
+	NotificationKindAccountDormant             NotificationKind = "account_dormant"
+)
+
+func (e *NotificationKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationKind(s)
+	case string:
+		*e = NotificationKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationKind: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationKind struct {
+	NotificationKind NotificationKind `json:"notification_kind"`
+	Valid            bool             `json:"valid"` // Valid is true if NotificationKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationKind), nil
+}
+
+func (e NotificationKind) Valid() bool {
+	switch e {
+	case NotificationKindWorkspaceAutostop,
+		NotificationKindWorkspaceDormant,
+		NotificationKindWorkspaceMarkedForDeletion,
+		NotificationKindWorkspaceBuildFailed,
+		NotificationKindAccountDormant:
+		return true
+	}
+	return false
+}
+
+func AllNotificationKindValues() []NotificationKind {
+	return []NotificationKind{
+		NotificationKindWorkspaceAutostop,
+		NotificationKindWorkspaceDormant,
+		NotificationKindWorkspaceMarkedForDeletion,
+		NotificationKindWorkspaceBuildFailed,
+		NotificationKindAccountDormant,
 	}
 }
 
@@ -1014,18 +1221,21 @@ func AllProvisionerTypeValues() []ProvisionerType {
 type ResourceType string
 
 const (
-	ResourceTypeOrganization    ResourceType = "organization"
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeGitSshKey       ResourceType = "git_ssh_key"
-	ResourceTypeApiKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
+	ResourceTypeOrganization            ResourceType = "organization"
+	ResourceTypeTemplate                ResourceType = "template"
+	ResourceTypeTemplateVersion         ResourceType = "template_version"
+	ResourceTypeUser                    ResourceType = "user"
+	ResourceTypeWorkspace               ResourceType = "workspace"
+	ResourceTypeGitSshKey               ResourceType = "git_ssh_key"
+	ResourceTypeApiKey                  ResourceType = "api_key"
+	ResourceTypeGroup                   ResourceType = "group"
+	ResourceTypeWorkspaceBuild          ResourceType = "workspace_build"
+	ResourceTypeLicense                 ResourceType = "license"
+	ResourceTypeWorkspaceProxy          ResourceType = "workspace_proxy"
+	ResourceTypeConvertLogin            ResourceType = "convert_login"
+	ResourceTypeCustomRole              ResourceType = "custom_role"
+	ResourceTypeOAuth2ProviderApp       ResourceType = "oauth2_provider_app"
+	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1076,7 +1286,10 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
-		ResourceTypeConvertLogin:
+		ResourceTypeConvertLogin,
+		ResourceTypeCustomRole,
+		ResourceTypeOAuth2ProviderApp,
+		ResourceTypeOAuth2ProviderAppSecret:
 		return true
 	}
 	return false
@@ -1096,6 +1309,9 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
+		ResourceTypeCustomRole,
+		ResourceTypeOAuth2ProviderApp,
+		ResourceTypeOAuth2ProviderAppSecret,
 	}
 }
 
@@ -1219,6 +1435,79 @@ func AllUserStatusValues() []UserStatus {
 	}
 }
 
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceCreated        WebhookEvent = "workspace_created"
+	WebhookEventWorkspaceStarted        WebhookEvent = "workspace_started"
+	WebhookEventWorkspaceStopped        WebhookEvent = "workspace_stopped"
+	WebhookEventWorkspaceDeleted        WebhookEvent = "workspace_deleted"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build_failed"
+	WebhookEventTemplateVersionPromoted WebhookEvent = "template_version_promoted"
+	WebhookEventUserCreated             WebhookEvent = "user_created"
+)
+
+func (e *WebhookEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookEvent(s)
+	case string:
+		*e = WebhookEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookEvent: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookEvent struct {
+	WebhookEvent WebhookEvent `json:"webhook_event"`
+	Valid        bool         `json:"valid"` // Valid is true if WebhookEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookEvent), nil
+}
+
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case WebhookEventWorkspaceCreated,
+		WebhookEventWorkspaceStarted,
+		WebhookEventWorkspaceStopped,
+		WebhookEventWorkspaceDeleted,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventTemplateVersionPromoted,
+		WebhookEventUserCreated:
+		return true
+	}
+	return false
+}
+
+func AllWebhookEventValues() []WebhookEvent {
+	return []WebhookEvent{
+		WebhookEventWorkspaceCreated,
+		WebhookEventWorkspaceStarted,
+		WebhookEventWorkspaceStopped,
+		WebhookEventWorkspaceDeleted,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventTemplateVersionPromoted,
+		WebhookEventUserCreated,
+	}
+}
+
 type WorkspaceAgentLifecycleState string
 
 const (
@@ -1298,73 +1587,70 @@ func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
 	}
 }
 
-type WorkspaceAgentLogSource string
+type WorkspaceAgentScriptStatus string
 
 const (
-	WorkspaceAgentLogSourceStartupScript  WorkspaceAgentLogSource = "startup_script"
-	WorkspaceAgentLogSourceShutdownScript WorkspaceAgentLogSource = "shutdown_script"
-	WorkspaceAgentLogSourceKubernetesLogs WorkspaceAgentLogSource = "kubernetes_logs"
-	WorkspaceAgentLogSourceEnvbox         WorkspaceAgentLogSource = "envbox"
-	WorkspaceAgentLogSourceEnvbuilder     WorkspaceAgentLogSource = "envbuilder"
-	WorkspaceAgentLogSourceExternal       WorkspaceAgentLogSource = "external"
+	WorkspaceAgentScriptStatusPending     WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning     WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusOk          WorkspaceAgentScriptStatus = "ok"
+	WorkspaceAgentScriptStatusExitFailure WorkspaceAgentScriptStatus = "exit_failure"
+	WorkspaceAgentScriptStatusTimedOut    WorkspaceAgentScriptStatus = "timed_out"
 )
 
-func (e *WorkspaceAgentLogSource) Scan(src interface{}) error {
+func (e *WorkspaceAgentScriptStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = WorkspaceAgentLogSource(s)
+		*e = WorkspaceAgentScriptStatus(s)
 	case string:
-		*e = WorkspaceAgentLogSource(s)
+		*e = WorkspaceAgentScriptStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for WorkspaceAgentLogSource: %T", src)
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptStatus: %T", src)
 	}
 	return nil
 }
 
-type NullWorkspaceAgentLogSource struct {
-	WorkspaceAgentLogSource WorkspaceAgentLogSource `json:"workspace_agent_log_source"`
-	Valid                   bool                    `json:"valid"` // Valid is true if WorkspaceAgentLogSource is not NULL
+type NullWorkspaceAgentScriptStatus struct {
+	WorkspaceAgentScriptStatus WorkspaceAgentScriptStatus `json:"workspace_agent_script_status"`
+	Valid                      bool                       `json:"valid"` // Valid is true if WorkspaceAgentScriptStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullWorkspaceAgentLogSource) Scan(value interface{}) error {
+func (ns *NullWorkspaceAgentScriptStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.WorkspaceAgentLogSource, ns.Valid = "", false
+		ns.WorkspaceAgentScriptStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.WorkspaceAgentLogSource.Scan(value)
+	return ns.WorkspaceAgentScriptStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullWorkspaceAgentLogSource) Value() (driver.Value, error) {
+func (ns NullWorkspaceAgentScriptStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.WorkspaceAgentLogSource), nil
+	return string(ns.WorkspaceAgentScriptStatus), nil
 }
 
-func (e WorkspaceAgentLogSource) Valid() bool {
+func (e WorkspaceAgentScriptStatus) Valid() bool {
 	switch e {
-	case WorkspaceAgentLogSourceStartupScript,
-		WorkspaceAgentLogSourceShutdownScript,
-		WorkspaceAgentLogSourceKubernetesLogs,
-		WorkspaceAgentLogSourceEnvbox,
-		WorkspaceAgentLogSourceEnvbuilder,
-		WorkspaceAgentLogSourceExternal:
+	case WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusOk,
+		WorkspaceAgentScriptStatusExitFailure,
+		WorkspaceAgentScriptStatusTimedOut:
 		return true
 	}
 	return false
 }
 
-func AllWorkspaceAgentLogSourceValues() []WorkspaceAgentLogSource {
-	return []WorkspaceAgentLogSource{
-		WorkspaceAgentLogSourceStartupScript,
-		WorkspaceAgentLogSourceShutdownScript,
-		WorkspaceAgentLogSourceKubernetesLogs,
-		WorkspaceAgentLogSourceEnvbox,
-		WorkspaceAgentLogSourceEnvbuilder,
-		WorkspaceAgentLogSourceExternal,
+func AllWorkspaceAgentScriptStatusValues() []WorkspaceAgentScriptStatus {
+	return []WorkspaceAgentScriptStatus{
+		WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusOk,
+		WorkspaceAgentScriptStatusExitFailure,
+		WorkspaceAgentScriptStatusTimedOut,
 	}
 }
 
@@ -1432,6 +1718,76 @@ func AllWorkspaceAgentSubsystemValues() []WorkspaceAgentSubsystem {
 	}
 }
 
+type WorkspaceAgentUpdateState string
+
+const (
+	WorkspaceAgentUpdateStateUnknown  WorkspaceAgentUpdateState = "unknown"
+	WorkspaceAgentUpdateStateUpToDate WorkspaceAgentUpdateState = "up_to_date"
+	WorkspaceAgentUpdateStateDisabled WorkspaceAgentUpdateState = "disabled"
+	WorkspaceAgentUpdateStateUpdating WorkspaceAgentUpdateState = "updating"
+	WorkspaceAgentUpdateStateUpdated  WorkspaceAgentUpdateState = "updated"
+	WorkspaceAgentUpdateStateFailed   WorkspaceAgentUpdateState = "failed"
+)
+
+func (e *WorkspaceAgentUpdateState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentUpdateState(s)
+	case string:
+		*e = WorkspaceAgentUpdateState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentUpdateState: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentUpdateState struct {
+	WorkspaceAgentUpdateState WorkspaceAgentUpdateState `json:"workspace_agent_update_state"`
+	Valid                     bool                      `json:"valid"` // Valid is true if WorkspaceAgentUpdateState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentUpdateState) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentUpdateState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentUpdateState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentUpdateState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentUpdateState), nil
+}
+
+func (e WorkspaceAgentUpdateState) Valid() bool {
+	switch e {
+	case WorkspaceAgentUpdateStateUnknown,
+		WorkspaceAgentUpdateStateUpToDate,
+		WorkspaceAgentUpdateStateDisabled,
+		WorkspaceAgentUpdateStateUpdating,
+		WorkspaceAgentUpdateStateUpdated,
+		WorkspaceAgentUpdateStateFailed:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentUpdateStateValues() []WorkspaceAgentUpdateState {
+	return []WorkspaceAgentUpdateState{
+		WorkspaceAgentUpdateStateUnknown,
+		WorkspaceAgentUpdateStateUpToDate,
+		WorkspaceAgentUpdateStateDisabled,
+		WorkspaceAgentUpdateStateUpdating,
+		WorkspaceAgentUpdateStateUpdated,
+		WorkspaceAgentUpdateStateFailed,
+	}
+}
+
 type WorkspaceAppHealth string
 
 const (
@@ -1496,6 +1852,64 @@ func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
 	}
 }
 
+type WorkspaceSessionRecordingType string
+
+const (
+	WorkspaceSessionRecordingTypeSSH             WorkspaceSessionRecordingType = "ssh"
+	WorkspaceSessionRecordingTypeReconnectingPTY WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+func (e *WorkspaceSessionRecordingType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceSessionRecordingType(s)
+	case string:
+		*e = WorkspaceSessionRecordingType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceSessionRecordingType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceSessionRecordingType struct {
+	WorkspaceSessionRecordingType WorkspaceSessionRecordingType `json:"workspace_session_recording_type"`
+	Valid                         bool                          `json:"valid"` // Valid is true if WorkspaceSessionRecordingType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceSessionRecordingType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceSessionRecordingType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceSessionRecordingType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceSessionRecordingType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceSessionRecordingType), nil
+}
+
+func (e WorkspaceSessionRecordingType) Valid() bool {
+	switch e {
+	case WorkspaceSessionRecordingTypeSSH,
+		WorkspaceSessionRecordingTypeReconnectingPTY:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceSessionRecordingTypeValues() []WorkspaceSessionRecordingType {
+	return []WorkspaceSessionRecordingType{
+		WorkspaceSessionRecordingTypeSSH,
+		WorkspaceSessionRecordingTypeReconnectingPTY,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -1571,6 +1985,8 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// The rules of keys with the custom scope. The key can only perform the actions the rules allow, on top of the roles of its user.
+	ScopeRules json.RawMessage `db:"scope_rules" json:"scope_rules"`
 }
 
 type AuditLog struct {
@@ -1591,6 +2007,51 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Connections to workspaces, as reported by workspace agents and the workspace app proxy.
+type ConnectionLog struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	ConnectTime time.Time `db:"connect_time" json:"connect_time"`
+	// Null while the connection is open. Workspace app accesses never have a disconnect time.
+	DisconnectTime   sql.NullTime   `db:"disconnect_time" json:"disconnect_time"`
+	OrganizationID   uuid.UUID      `db:"organization_id" json:"organization_id"`
+	WorkspaceOwnerID uuid.UUID      `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceID      uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string         `db:"workspace_name" json:"workspace_name"`
+	AgentID          uuid.UUID      `db:"agent_id" json:"agent_id"`
+	AgentName        string         `db:"agent_name" json:"agent_name"`
+	Type             ConnectionType `db:"type" json:"type"`
+	// The user that connected, if known. Agents don't know who is connecting to them.
+	UserID    uuid.NullUUID  `db:"user_id" json:"user_id"`
+	Ip        pqtype.Inet    `db:"ip" json:"ip"`
+	UserAgent sql.NullString `db:"user_agent" json:"user_agent"`
+	// The slug of the workspace app, or the port of a port forward.
+	SlugOrPort string `db:"slug_or_port" json:"slug_or_port"`
+	// The exit code of SSH and reconnecting PTY sessions, or the HTTP status code of workspace app accesses.
+	Code sql.NullInt32 `db:"code" json:"code"`
+	// Bytes sent from the workspace to the client.
+	BytesSent int64 `db:"bytes_sent" json:"bytes_sent"`
+	// Bytes received by the workspace from the client.
+	BytesReceived    int64  `db:"bytes_received" json:"bytes_received"`
+	DisconnectReason string `db:"disconnect_reason" json:"disconnect_reason"`
+}
+
+// Roles defined by administrators in addition to the built-in roles.
+type CustomRole struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	// Roles with an organization are assigned to organization members, roles without one are site wide.
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	// The site wide permissions of the role, as an array of rbac permissions.
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
+	// The permissions of the role in its organization, as an array of rbac permissions.
+	OrgPermissions json.RawMessage `db:"org_permissions" json:"org_permissions"`
+	// The permissions of the role on resources owned by the user, as an array of rbac permissions.
+	UserPermissions json.RawMessage `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
 // A table used to store the keys used to encrypt the database.
 type DBCryptKey struct {
 	// An integer used to identify the key.
@@ -1672,6 +2133,80 @@ type Organization struct {
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
 
+// Notifications shown in the inbox of users, and optionally emailed to them. Any replica may send the emails.
+type NotificationMessage struct {
+	ID      uuid.UUID        `db:"id" json:"id"`
+	UserID  uuid.UUID        `db:"user_id" json:"user_id"`
+	Kind    NotificationKind `db:"kind" json:"kind"`
+	Title   string           `db:"title" json:"title"`
+	Content string           `db:"content" json:"content"`
+	// Identifies the event the notification is about, so it is only sent once. Empty if notifications are never deduplicated.
+	DedupeKey     string       `db:"dedupe_key" json:"dedupe_key"`
+	CreatedAt     time.Time    `db:"created_at" json:"created_at"`
+	ReadAt        sql.NullTime `db:"read_at" json:"read_at"`
+	EmailAttempts int32        `db:"email_attempts" json:"email_attempts"`
+	// When sending the email is attempted next. Null if no email is sent, once it was sent or it ran out of attempts.
+	EmailNextAttemptAt sql.NullTime `db:"email_next_attempt_at" json:"email_next_attempt_at"`
+	EmailedAt          sql.NullTime `db:"emailed_at" json:"emailed_at"`
+	// Why the last attempt to send the email failed.
+	EmailError string `db:"email_error" json:"email_error"`
+}
+
+// The kinds of notifications users opted out of. Users receive all kinds of notifications without a preference.
+type NotificationPreference struct {
+	UserID    uuid.UUID        `db:"user_id" json:"user_id"`
+	Kind      NotificationKind `db:"kind" json:"kind"`
+	Disabled  bool             `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// Third-party apps that act on behalf of users through the OAuth2 authorization code flow, with Coder as the authorization server.
+type OAuth2ProviderApp struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
+	Icon      string    `db:"icon" json:"icon"`
+	// Users are only redirected back to an app at one of these URIs.
+	RedirectURIs []string `db:"redirect_uris" json:"redirect_uris"`
+}
+
+// Authorization codes issued to apps when users authorize them. A code is exchanged for a token once.
+type OAuth2ProviderAppCode struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	// The redirect_uri of the authorization request, empty if it was left out. The token request must send the same one.
+	RedirectURI string `db:"redirect_uri" json:"redirect_uri"`
+	Scope       string `db:"scope" json:"scope"`
+	// The PKCE S256 code challenge. The app has to send the matching verifier with the code.
+	CodeChallenge string `db:"code_challenge" json:"code_challenge"`
+}
+
+// Client secrets apps authenticate to the token endpoint with.
+type OAuth2ProviderAppSecret struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	AppID        uuid.UUID    `db:"app_id" json:"app_id"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt   sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	HashedSecret []byte       `db:"hashed_secret" json:"hashed_secret"`
+	// The last characters of the secret, to tell secrets apart.
+	DisplaySecret string `db:"display_secret" json:"display_secret"`
+}
+
+// Refresh tokens of the API keys issued to apps. Deleting the API key revokes the refresh token.
+type OAuth2ProviderAppToken struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	AppID       uuid.UUID `db:"app_id" json:"app_id"`
+	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
+}
+
 type OrganizationMember struct {
 	UserID         uuid.UUID `db:"user_id" json:"user_id"`
 	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
@@ -1786,6 +2321,8 @@ type TailnetClient struct {
 	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
 	UpdatedAt     time.Time       `db:"updated_at" json:"updated_at"`
 	Node          json.RawMessage `db:"node" json:"node"`
+	// The user that the client authenticated as. Null for clients that coordinate on behalf of others, such as workspace proxies.
+	UserID uuid.NullUUID `db:"user_id" json:"user_id"`
 }
 
 // We keep this separate from replicas in case we need to break the coordinator out into its own service
@@ -1820,6 +2357,11 @@ type Template struct {
 	TimeTilDormantAutoDelete      int64           `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
 	AutostopRequirementDaysOfWeek int16           `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
 	AutostopRequirementWeeks      int64           `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	RecordSessions                bool            `db:"record_sessions" json:"record_sessions"`
+	DeprecationMessage            string          `db:"deprecation_message" json:"deprecation_message"`
+	DeprecatedAt                  sql.NullTime    `db:"deprecated_at" json:"deprecated_at"`
+	RequireActiveVersion          bool            `db:"require_active_version" json:"require_active_version"`
 	CreatedByAvatarURL            sql.NullString  `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 }
@@ -1856,6 +2398,16 @@ type TemplateTable struct {
 	AutostopRequirementDaysOfWeek int16 `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
 	// The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.
 	AutostopRequirementWeeks int64 `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	// The maximum sharing level workspace owners can set on ports of workspaces created from this template.
+	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	// Whether interactive sessions in workspaces created from this template are recorded.
+	RecordSessions bool `db:"record_sessions" json:"record_sessions"`
+	// If set, the template is deprecated and can't be used to create new workspaces. The message tells users what to use instead.
+	DeprecationMessage string `db:"deprecation_message" json:"deprecation_message"`
+	// When the template was deprecated. Null if the template isn't deprecated.
+	DeprecatedAt sql.NullTime `db:"deprecated_at" json:"deprecated_at"`
+	// Whether workspaces must be updated to the active version of the template when their owner starts them.
+	RequireActiveVersion bool `db:"require_active_version" json:"require_active_version"`
 }
 
 // Joins in the username + avatar url of the created by user.
@@ -1911,6 +2463,16 @@ type TemplateVersionParameter struct {
 	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
 }
 
+// Named sets of rich parameter values declared by a template version.
+type TemplateVersionPreset struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
+	// The rich parameter values of the preset, as an array of name and value objects.
+	Parameters json.RawMessage `db:"parameters" json:"parameters"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
+
 type TemplateVersionTable struct {
 	ID             uuid.UUID     `db:"id" json:"id"`
 	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
@@ -1960,6 +2522,8 @@ type User struct {
 	LastSeenAt     time.Time      `db:"last_seen_at" json:"last_seen_at"`
 	// Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user's quiet hours. If empty, the default quiet hours on the instance is used instead.
 	QuietHoursSchedule string `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	// Service accounts are non-human users for automation. They cannot log in interactively and are excluded from license seat counts and DAUs.
+	IsServiceAccount bool `db:"is_service_account" json:"is_service_account"`
 }
 
 type UserLink struct {
@@ -1996,6 +2560,39 @@ type Workspace struct {
 	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
 	DormantAt         sql.NullTime   `db:"dormant_at" json:"dormant_at"`
 	DeletingAt        sql.NullTime   `db:"deleting_at" json:"deleting_at"`
+	// Whether the workspace is updated to the active version of its template when it is started.
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+}
+
+// Endpoints that receive events as signed JSON payloads.
+type Webhook struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
+	Url       string    `db:"url" json:"url"`
+	// The key payloads are signed with using HMAC-SHA256.
+	Secret  string         `db:"secret" json:"secret"`
+	Events  []WebhookEvent `db:"events" json:"events"`
+	Enabled bool           `db:"enabled" json:"enabled"`
+}
+
+// Events queued for and delivered to webhooks. Any replica may deliver them.
+type WebhookDelivery struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	WebhookID uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	Event     WebhookEvent    `db:"event" json:"event"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	Attempts  int32           `db:"attempts" json:"attempts"`
+	// When the delivery is attempted next. Null once it was delivered or it ran out of attempts.
+	NextAttemptAt sql.NullTime `db:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt sql.NullTime `db:"last_attempt_at" json:"last_attempt_at"`
+	// The HTTP status code of the last attempt, if the endpoint responded.
+	StatusCode sql.NullInt32 `db:"status_code" json:"status_code"`
+	// Why the last attempt failed.
+	Error       string       `db:"error" json:"error"`
+	DeliveredAt sql.NullTime `db:"delivered_at" json:"delivered_at"`
 }
 
 type WorkspaceAgent struct {
@@ -2047,15 +2644,38 @@ type WorkspaceAgent struct {
 	ReadyAt     sql.NullTime              `db:"ready_at" json:"ready_at"`
 	Subsystems  []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
 	DisplayApps []DisplayApp              `db:"display_apps" json:"display_apps"`
+	// The autostop inhibitors held in the workspace, as reported by the workspace agent. The deadline of the workspace is extended while the agent holds one.
+	AutostopInhibitors AutostopInhibitors `db:"autostop_inhibitors" json:"autostop_inhibitors"`
+	// The state of the agent replacing itself with the agent binary served by coderd, as reported by the workspace agent.
+	UpdateState WorkspaceAgentUpdateState `db:"update_state" json:"update_state"`
+	// The error of the last failed agent update.
+	UpdateError string `db:"update_error" json:"update_error"`
 }
 
 type WorkspaceAgentLog struct {
-	AgentID   uuid.UUID               `db:"agent_id" json:"agent_id"`
-	CreatedAt time.Time               `db:"created_at" json:"created_at"`
-	Output    string                  `db:"output" json:"output"`
-	ID        int64                   `db:"id" json:"id"`
-	Level     LogLevel                `db:"level" json:"level"`
-	Source    WorkspaceAgentLogSource `db:"source" json:"source"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	Output      string    `db:"output" json:"output"`
+	ID          int64     `db:"id" json:"id"`
+	Level       LogLevel  `db:"level" json:"level"`
+	LogSourceID uuid.UUID `db:"log_source_id" json:"log_source_id"`
+}
+
+// Files in the workspace the agent tails into the workspace agent logs, each with its own log source.
+type WorkspaceAgentLogFile struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	LogSourceID      uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	Path             string    `db:"path" json:"path"`
+}
+
+type WorkspaceAgentLogSource struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	ID               uuid.UUID `db:"id" json:"id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Icon             string    `db:"icon" json:"icon"`
 }
 
 type WorkspaceAgentMetadatum struct {
@@ -2070,6 +2690,48 @@ type WorkspaceAgentMetadatum struct {
 	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
 }
 
+// Users other than the workspace owner that may attach to a reconnecting PTY session on a workspace agent.
+type WorkspaceAgentPTYInvite struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	// The reconnect ID of the reconnecting PTY session on the agent.
+	SessionID uuid.UUID `db:"session_id" json:"session_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	ReadOnly  bool      `db:"read_only" json:"read_only"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Ports on workspace agents that the workspace owner has shared with other users. Agents are referenced by name so shares survive workspace rebuilds.
+type WorkspaceAgentPortShare struct {
+	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	AgentName   string          `db:"agent_name" json:"agent_name"`
+	Port        int32           `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel `db:"share_level" json:"share_level"`
+}
+
+type WorkspaceAgentScript struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	LogSourceID      uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	LogPath          string    `db:"log_path" json:"log_path"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	Script           string    `db:"script" json:"script"`
+	Cron             string    `db:"cron" json:"cron"`
+	StartBlocksLogin bool      `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart       bool      `db:"run_on_start" json:"run_on_start"`
+	RunOnStop        bool      `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds   int32     `db:"timeout_seconds" json:"timeout_seconds"`
+	// Specifies the order in which scripts are displayed. Scripts run concurrently regardless of their order.
+	DisplayOrder int32 `db:"display_order" json:"display_order"`
+	// The status of the most recent execution of the script.
+	Status WorkspaceAgentScriptStatus `db:"status" json:"status"`
+	// The exit code of the most recent execution of the script.
+	ExitCode  int32        `db:"exit_code" json:"exit_code"`
+	StartedAt sql.NullTime `db:"started_at" json:"started_at"`
+	EndedAt   sql.NullTime `db:"ended_at" json:"ended_at"`
+}
+
 type WorkspaceAgentStat struct {
 	ID                          uuid.UUID       `db:"id" json:"id"`
 	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
@@ -2106,6 +2768,10 @@ type WorkspaceApp struct {
 	SharingLevel         AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
 	Slug                 string             `db:"slug" json:"slug"`
 	External             bool               `db:"external" json:"external"`
+	// Address the agent connects to over TCP to check the health of the app, used instead of healthcheck_url.
+	HealthcheckTCP string `db:"healthcheck_tcp" json:"healthcheck_tcp"`
+	// Command the agent runs to check the health of the app, used instead of healthcheck_url. A zero exit code means the app is healthy.
+	HealthcheckCommand string `db:"healthcheck_command" json:"healthcheck_command"`
 }
 
 // A record of workspace app usage statistics
@@ -2177,6 +2843,27 @@ type WorkspaceBuildTable struct {
 	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
 }
 
+// Pools of prebuilt workspaces. Creating a workspace with the template version and parameters of a pool claims one of its workspaces.
+type WorkspacePrebuildPool struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	// The rich parameter values the workspaces of the pool are built with, as an array of name and value objects.
+	Parameters json.RawMessage `db:"parameters" json:"parameters"`
+	// The number of ready workspaces the pool is kept at.
+	DesiredInstances int32     `db:"desired_instances" json:"desired_instances"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Prebuilt workspaces that were not claimed yet. They are owned by the prebuilds system user.
+type WorkspacePrebuild struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// Null once the pool was deleted, in which case the workspace is deleted too.
+	PoolID    uuid.NullUUID `db:"pool_id" json:"pool_id"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
 type WorkspaceProxy struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -2219,3 +2906,27 @@ type WorkspaceResourceMetadatum struct {
 	Sensitive           bool           `db:"sensitive" json:"sensitive"`
 	ID                  int64          `db:"id" json:"id"`
 }
+
+// Recordings of interactive sessions on workspace agents in asciicast v2 format. The recording itself is stored in workspace_session_recording_chunks.
+type WorkspaceSessionRecording struct {
+	ID          uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceID uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Type        WorkspaceSessionRecordingType `db:"type" json:"type"`
+	// The reconnect ID of the reconnecting PTY session, if the recording is of one.
+	ReconnectID   uuid.NullUUID `db:"reconnect_id" json:"reconnect_id"`
+	Command       string        `db:"command" json:"command"`
+	IncludesInput bool          `db:"includes_input" json:"includes_input"`
+	Size          int64         `db:"size" json:"size"`
+	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time     `db:"updated_at" json:"updated_at"`
+	// Null while the session is still being recorded.
+	EndedAt sql.NullTime `db:"ended_at" json:"ended_at"`
+}
+
+type WorkspaceSessionRecordingChunk struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	ChunkIndex  int32     `db:"chunk_index" json:"chunk_index"`
+	Data        []byte    `db:"data" json:"data"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
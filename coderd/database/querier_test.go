@@ -7,10 +7,13 @@ import (
 	"database/sql"
 	"encoding/json"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/database"
@@ -113,11 +116,11 @@ func TestInsertWorkspaceAgentLogs(t *testing.T) {
 		ResourceID: resource.ID,
 	})
 	logs, err := db.InsertWorkspaceAgentLogs(ctx, database.InsertWorkspaceAgentLogsParams{
-		AgentID:   agent.ID,
-		CreatedAt: []time.Time{dbtime.Now()},
-		Output:    []string{"first"},
-		Level:     []database.LogLevel{database.LogLevelInfo},
-		Source:    []database.WorkspaceAgentLogSource{database.WorkspaceAgentLogSourceExternal},
+		AgentID:     agent.ID,
+		CreatedAt:   []time.Time{dbtime.Now()},
+		Output:      []string{"first"},
+		Level:       []database.LogLevel{database.LogLevelInfo},
+		LogSourceID: uuid.New(),
 		// 1 MB is the max
 		OutputLength: 1 << 20,
 	})
@@ -129,7 +132,7 @@ func TestInsertWorkspaceAgentLogs(t *testing.T) {
 		CreatedAt:    []time.Time{dbtime.Now()},
 		Output:       []string{"second"},
 		Level:        []database.LogLevel{database.LogLevelInfo},
-		Source:       []database.WorkspaceAgentLogSource{database.WorkspaceAgentLogSourceExternal},
+		LogSourceID:  uuid.New(),
 		OutputLength: 1,
 	})
 	require.True(t, database.IsWorkspaceAgentLogsLimitError(err))
@@ -491,6 +494,81 @@ func TestUserChangeLoginType(t *testing.T) {
 	require.Equal(t, bobExpPass, bob.HashedPassword, "hashed password should not change")
 }
 
+func TestOAuth2ProviderGrantsSingleUse(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	sqlDB := testSQLDB(t)
+	err := migrations.Up(sqlDB)
+	require.NoError(t, err)
+	db := database.New(sqlDB)
+
+	user := dbgen.User(t, db, database.User{})
+	app := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+
+	// concurrently runs delete in several transactions at once and returns the
+	// total number of rows they deleted.
+	concurrently := func(t *testing.T, del func(tx database.Store) (int64, error)) int64 {
+		t.Helper()
+
+		var (
+			deleted atomic.Int64
+			wg      sync.WaitGroup
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := db.InTx(func(tx database.Store) error {
+					rows, err := del(tx)
+					if err != nil {
+						return err
+					}
+					deleted.Add(rows)
+					return nil
+				}, nil)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		return deleted.Load()
+	}
+
+	t.Run("Code", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		code := dbgen.OAuth2ProviderAppCode(t, db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		deleted := concurrently(t, func(tx database.Store) (int64, error) {
+			return tx.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+		})
+		require.EqualValues(t, 1, deleted)
+
+		// Replaying the code afterwards deletes nothing either.
+		rows, err := db.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+		require.NoError(t, err)
+		require.Zero(t, rows)
+	})
+
+	t.Run("RefreshToken", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		key, _ := dbgen.APIKey(t, db, database.APIKey{UserID: user.ID, LoginType: database.LoginTypeOAuth2ProviderApp})
+		token := dbgen.OAuth2ProviderAppToken(t, db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		deleted := concurrently(t, func(tx database.Store) (int64, error) {
+			return tx.DeleteAPIKeyByID(ctx, key.ID)
+		})
+		require.EqualValues(t, 1, deleted)
+
+		// The refresh token went away with its API key.
+		_, err := db.GetOAuth2ProviderAppTokenByRefreshHash(ctx, token.RefreshHash)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
 func requireUsersMatch(t testing.TB, expected []database.User, found []database.GetUsersRow, msg string) {
 	t.Helper()
 	require.ElementsMatch(t, expected, database.ConvertUserRows(found), msg)
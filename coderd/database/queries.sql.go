@@ -22,9 +22,12 @@ WHERE
 	id = $1
 `
 
-func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteAPIKeyByID, id)
-	return err
+func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAPIKeyByID, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteAPIKeysByUserID = `-- name: DeleteAPIKeysByUserID :exec
@@ -54,7 +57,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_rules
 FROM
 	api_keys
 WHERE
@@ -79,13 +82,14 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopeRules,
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_rules
 FROM
 	api_keys
 WHERE
@@ -118,12 +122,13 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopeRules,
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_rules FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -148,6 +153,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopeRules,
 		); err != nil {
 			return nil, err
 		}
@@ -163,7 +169,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_rules FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -193,6 +199,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopeRules,
 		); err != nil {
 			return nil, err
 		}
@@ -208,7 +215,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_rules FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -233,6 +240,7 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopeRules,
 		); err != nil {
 			return nil, err
 		}
@@ -261,7 +269,8 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scope_rules
 	)
 VALUES
 	($1,
@@ -270,22 +279,23 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_rules
 `
 
 type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+	ID              string          `db:"id" json:"id"`
+	LifetimeSeconds int64           `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret    []byte          `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress       pqtype.Inet     `db:"ip_address" json:"ip_address"`
+	UserID          uuid.UUID       `db:"user_id" json:"user_id"`
+	LastUsed        time.Time       `db:"last_used" json:"last_used"`
+	ExpiresAt       time.Time       `db:"expires_at" json:"expires_at"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+	LoginType       LoginType       `db:"login_type" json:"login_type"`
+	Scope           APIKeyScope     `db:"scope" json:"scope"`
+	TokenName       string          `db:"token_name" json:"token_name"`
+	ScopeRules      json.RawMessage `db:"scope_rules" json:"scope_rules"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -302,6 +312,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		arg.ScopeRules,
 	)
 	var i APIKey
 	err := row.Scan(
@@ -317,6 +328,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopeRules,
 	)
 	return i, err
 }
@@ -636,26 +648,170 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
-const getDBCryptKeys = `-- name: GetDBCryptKeys :many
-SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
+const deleteOldConnectionLogs = `-- name: DeleteOldConnectionLogs :exec
+DELETE FROM connection_logs WHERE connect_time < $1
 `
 
-func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
-	rows, err := q.db.QueryContext(ctx, getDBCryptKeys)
+func (q *sqlQuerier) DeleteOldConnectionLogs(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldConnectionLogs, beforeTime)
+	return err
+}
+
+const getConnectionLogsOffset = `-- name: GetConnectionLogsOffset :many
+SELECT
+	connection_logs.id, connection_logs.connect_time, connection_logs.disconnect_time, connection_logs.organization_id, connection_logs.workspace_owner_id, connection_logs.workspace_id, connection_logs.workspace_name, connection_logs.agent_id, connection_logs.agent_name, connection_logs.type, connection_logs.user_id, connection_logs.ip, connection_logs.user_agent, connection_logs.slug_or_port, connection_logs.code, connection_logs.bytes_sent, connection_logs.bytes_received, connection_logs.disconnect_reason,
+	workspace_owner.username AS workspace_owner_username,
+	users.username AS user_username,
+	COUNT(connection_logs.*) OVER () AS count
+FROM
+	connection_logs
+	LEFT JOIN users AS workspace_owner ON connection_logs.workspace_owner_id = workspace_owner.id
+	LEFT JOIN users ON connection_logs.user_id = users.id
+WHERE
+	-- Filter by type
+	CASE
+		WHEN $1 :: text != '' THEN
+			type = $1 :: connection_type
+		ELSE true
+	END
+	-- Filter by workspace_owner_id
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_owner_id = $2
+		ELSE true
+	END
+	-- Filter by workspace_owner
+	AND CASE
+		WHEN $3 :: text != '' THEN
+			workspace_owner_id = (SELECT id FROM users WHERE lower(username) = lower($3) AND deleted = false)
+		ELSE true
+	END
+	-- Filter by workspace_id
+	AND CASE
+		WHEN $4 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_id = $4
+		ELSE true
+	END
+	-- Filter by workspace_name
+	AND CASE
+		WHEN $5 :: text != '' THEN
+			lower(workspace_name) = lower($5)
+		ELSE true
+	END
+	-- Filter by user_id
+	AND CASE
+		WHEN $6 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = $6
+		ELSE true
+	END
+	-- Filter by username
+	AND CASE
+		WHEN $7 :: text != '' THEN
+			user_id = (SELECT id FROM users WHERE lower(username) = lower($7) AND deleted = false)
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connect_time >= $8
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN $9 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connect_time <= $9
+		ELSE true
+	END
+ORDER BY
+	connect_time DESC
+OFFSET
+	$10
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($11 :: int, 0)
+`
+
+type GetConnectionLogsOffsetParams struct {
+	Type             string    `db:"type" json:"type"`
+	WorkspaceOwnerID uuid.UUID `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceOwner   string    `db:"workspace_owner" json:"workspace_owner"`
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string    `db:"workspace_name" json:"workspace_name"`
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	Username         string    `db:"username" json:"username"`
+	DateFrom         time.Time `db:"date_from" json:"date_from"`
+	DateTo           time.Time `db:"date_to" json:"date_to"`
+	OffsetOpt        int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt         int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetConnectionLogsOffsetRow struct {
+	ID                     uuid.UUID      `db:"id" json:"id"`
+	ConnectTime            time.Time      `db:"connect_time" json:"connect_time"`
+	DisconnectTime         sql.NullTime   `db:"disconnect_time" json:"disconnect_time"`
+	OrganizationID         uuid.UUID      `db:"organization_id" json:"organization_id"`
+	WorkspaceOwnerID       uuid.UUID      `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceID            uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName          string         `db:"workspace_name" json:"workspace_name"`
+	AgentID                uuid.UUID      `db:"agent_id" json:"agent_id"`
+	AgentName              string         `db:"agent_name" json:"agent_name"`
+	Type                   ConnectionType `db:"type" json:"type"`
+	UserID                 uuid.NullUUID  `db:"user_id" json:"user_id"`
+	Ip                     pqtype.Inet    `db:"ip" json:"ip"`
+	UserAgent              sql.NullString `db:"user_agent" json:"user_agent"`
+	SlugOrPort             string         `db:"slug_or_port" json:"slug_or_port"`
+	Code                   sql.NullInt32  `db:"code" json:"code"`
+	BytesSent              int64          `db:"bytes_sent" json:"bytes_sent"`
+	BytesReceived          int64          `db:"bytes_received" json:"bytes_received"`
+	DisconnectReason       string         `db:"disconnect_reason" json:"disconnect_reason"`
+	WorkspaceOwnerUsername sql.NullString `db:"workspace_owner_username" json:"workspace_owner_username"`
+	UserUsername           sql.NullString `db:"user_username" json:"user_username"`
+	Count                  int64          `db:"count" json:"count"`
+}
+
+func (q *sqlQuerier) GetConnectionLogsOffset(ctx context.Context, arg GetConnectionLogsOffsetParams) ([]GetConnectionLogsOffsetRow, error) {
+	rows, err := q.db.QueryContext(ctx, getConnectionLogsOffset,
+		arg.Type,
+		arg.WorkspaceOwnerID,
+		arg.WorkspaceOwner,
+		arg.WorkspaceID,
+		arg.WorkspaceName,
+		arg.UserID,
+		arg.Username,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []DBCryptKey
+	var items []GetConnectionLogsOffsetRow
 	for rows.Next() {
-		var i DBCryptKey
+		var i GetConnectionLogsOffsetRow
 		if err := rows.Scan(
-			&i.Number,
-			&i.ActiveKeyDigest,
-			&i.RevokedKeyDigest,
-			&i.CreatedAt,
-			&i.RevokedAt,
-			&i.Test,
+			&i.ID,
+			&i.ConnectTime,
+			&i.DisconnectTime,
+			&i.OrganizationID,
+			&i.WorkspaceOwnerID,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.AgentID,
+			&i.AgentName,
+			&i.Type,
+			&i.UserID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.SlugOrPort,
+			&i.Code,
+			&i.BytesSent,
+			&i.BytesReceived,
+			&i.DisconnectReason,
+			&i.WorkspaceOwnerUsername,
+			&i.UserUsername,
+			&i.Count,
 		); err != nil {
 			return nil, err
 		}
@@ -670,152 +826,195 @@ func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
 	return items, nil
 }
 
-const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
-INSERT INTO dbcrypt_keys
-	(number, active_key_digest, created_at, test)
-VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
-`
-
-type InsertDBCryptKeyParams struct {
-	Number          int32  `db:"number" json:"number"`
-	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
-	Test            string `db:"test" json:"test"`
-}
-
-func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
-	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
-	return err
+const upsertConnectionLog = `-- name: UpsertConnectionLog :one
+INSERT INTO
+	connection_logs (
+		id,
+		connect_time,
+		organization_id,
+		workspace_owner_id,
+		workspace_id,
+		workspace_name,
+		agent_id,
+		agent_name,
+		type,
+		user_id,
+		ip,
+		user_agent,
+		slug_or_port,
+		code,
+		bytes_sent,
+		bytes_received,
+		disconnect_time,
+		disconnect_reason
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+ON CONFLICT (id) DO UPDATE SET
+	connect_time = LEAST(connection_logs.connect_time, EXCLUDED.connect_time),
+	user_id = COALESCE(connection_logs.user_id, EXCLUDED.user_id),
+	code = COALESCE(EXCLUDED.code, connection_logs.code),
+	bytes_sent = GREATEST(connection_logs.bytes_sent, EXCLUDED.bytes_sent),
+	bytes_received = GREATEST(connection_logs.bytes_received, EXCLUDED.bytes_received),
+	disconnect_time = COALESCE(EXCLUDED.disconnect_time, connection_logs.disconnect_time),
+	disconnect_reason = CASE
+		WHEN EXCLUDED.disconnect_reason != '' THEN EXCLUDED.disconnect_reason
+		ELSE connection_logs.disconnect_reason
+	END
+WHERE
+	-- Connections can't be taken over by another agent.
+	connection_logs.agent_id = EXCLUDED.agent_id
+RETURNING id, connect_time, disconnect_time, organization_id, workspace_owner_id, workspace_id, workspace_name, agent_id, agent_name, type, user_id, ip, user_agent, slug_or_port, code, bytes_sent, bytes_received, disconnect_reason
+`
+
+type UpsertConnectionLogParams struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	ConnectTime      time.Time      `db:"connect_time" json:"connect_time"`
+	OrganizationID   uuid.UUID      `db:"organization_id" json:"organization_id"`
+	WorkspaceOwnerID uuid.UUID      `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceID      uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string         `db:"workspace_name" json:"workspace_name"`
+	AgentID          uuid.UUID      `db:"agent_id" json:"agent_id"`
+	AgentName        string         `db:"agent_name" json:"agent_name"`
+	Type             ConnectionType `db:"type" json:"type"`
+	UserID           uuid.NullUUID  `db:"user_id" json:"user_id"`
+	Ip               pqtype.Inet    `db:"ip" json:"ip"`
+	UserAgent        sql.NullString `db:"user_agent" json:"user_agent"`
+	SlugOrPort       string         `db:"slug_or_port" json:"slug_or_port"`
+	Code             sql.NullInt32  `db:"code" json:"code"`
+	BytesSent        int64          `db:"bytes_sent" json:"bytes_sent"`
+	BytesReceived    int64          `db:"bytes_received" json:"bytes_received"`
+	DisconnectTime   sql.NullTime   `db:"disconnect_time" json:"disconnect_time"`
+	DisconnectReason string         `db:"disconnect_reason" json:"disconnect_reason"`
+}
+
+// UpsertConnectionLog records a connection, or updates it if it was recorded
+// before. Agents report a connection when it opens and again when it closes,
+// and either report may be retried or arrive out of order.
+func (q *sqlQuerier) UpsertConnectionLog(ctx context.Context, arg UpsertConnectionLogParams) (ConnectionLog, error) {
+	row := q.db.QueryRowContext(ctx, upsertConnectionLog,
+		arg.ID,
+		arg.ConnectTime,
+		arg.OrganizationID,
+		arg.WorkspaceOwnerID,
+		arg.WorkspaceID,
+		arg.WorkspaceName,
+		arg.AgentID,
+		arg.AgentName,
+		arg.Type,
+		arg.UserID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.SlugOrPort,
+		arg.Code,
+		arg.BytesSent,
+		arg.BytesReceived,
+		arg.DisconnectTime,
+		arg.DisconnectReason,
+	)
+	var i ConnectionLog
+	err := row.Scan(
+		&i.ID,
+		&i.ConnectTime,
+		&i.DisconnectTime,
+		&i.OrganizationID,
+		&i.WorkspaceOwnerID,
+		&i.WorkspaceID,
+		&i.WorkspaceName,
+		&i.AgentID,
+		&i.AgentName,
+		&i.Type,
+		&i.UserID,
+		&i.Ip,
+		&i.UserAgent,
+		&i.SlugOrPort,
+		&i.Code,
+		&i.BytesSent,
+		&i.BytesReceived,
+		&i.DisconnectReason,
+	)
+	return i, err
 }
 
-const revokeDBCryptKey = `-- name: RevokeDBCryptKey :exec
-UPDATE dbcrypt_keys
+const deleteCustomRoleByID = `-- name: DeleteCustomRoleByID :exec
+WITH deleted AS (
+	DELETE FROM custom_roles WHERE id = $1 RETURNING name, organization_id
+), unassigned_users AS (
+	UPDATE
+		users
+	SET
+		rbac_roles = array_remove(users.rbac_roles, deleted.name)
+	FROM
+		deleted
+	WHERE
+		deleted.organization_id IS NULL
+)
+UPDATE
+	organization_members
 SET
-	revoked_key_digest = active_key_digest,
-	active_key_digest = revoked_key_digest,
-	revoked_at = CURRENT_TIMESTAMP
+	roles = array_remove(organization_members.roles, deleted.name || ':' || deleted.organization_id :: text)
+FROM
+	deleted
 WHERE
-	active_key_digest = $1::text
-AND
-	revoked_key_digest IS NULL
+	organization_members.organization_id = deleted.organization_id
 `
 
-func (q *sqlQuerier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
-	_, err := q.db.ExecContext(ctx, revokeDBCryptKey, activeKeyDigest)
+// DeleteCustomRoleByID deletes the role and removes it from every user and
+// organization member it is assigned to.
+func (q *sqlQuerier) DeleteCustomRoleByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteCustomRoleByID, id)
 	return err
 }
 
-const getFileByHashAndCreator = `-- name: GetFileByHashAndCreator :one
-SELECT
-	hash, created_at, created_by, mimetype, data, id
-FROM
-	files
-WHERE
-	hash = $1
-AND
-	created_by = $2
-LIMIT
-	1
+const getCustomRoleByID = `-- name: GetCustomRoleByID :one
+SELECT id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at FROM custom_roles WHERE id = $1
 `
 
-type GetFileByHashAndCreatorParams struct {
-	Hash      string    `db:"hash" json:"hash"`
-	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
-}
-
-func (q *sqlQuerier) GetFileByHashAndCreator(ctx context.Context, arg GetFileByHashAndCreatorParams) (File, error) {
-	row := q.db.QueryRowContext(ctx, getFileByHashAndCreator, arg.Hash, arg.CreatedBy)
-	var i File
+func (q *sqlQuerier) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, getCustomRoleByID, id)
+	var i CustomRole
 	err := row.Scan(
-		&i.Hash,
-		&i.CreatedAt,
-		&i.CreatedBy,
-		&i.Mimetype,
-		&i.Data,
 		&i.ID,
-	)
-	return i, err
-}
-
-const getFileByID = `-- name: GetFileByID :one
-SELECT
-	hash, created_at, created_by, mimetype, data, id
-FROM
-	files
-WHERE
-	id = $1
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetFileByID(ctx context.Context, id uuid.UUID) (File, error) {
-	row := q.db.QueryRowContext(ctx, getFileByID, id)
-	var i File
-	err := row.Scan(
-		&i.Hash,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
 		&i.CreatedAt,
-		&i.CreatedBy,
-		&i.Mimetype,
-		&i.Data,
-		&i.ID,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getFileTemplates = `-- name: GetFileTemplates :many
+const getCustomRoles = `-- name: GetCustomRoles :many
 SELECT
-	files.id AS file_id,
-	files.created_by AS file_created_by,
-	templates.id AS template_id,
-	templates.organization_id AS template_organization_id,
-	templates.created_by AS template_created_by,
-	templates.user_acl,
-	templates.group_acl
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
 FROM
-	templates
-INNER JOIN
-	template_versions
-	ON templates.id = template_versions.template_id
-INNER JOIN
-	provisioner_jobs
-	ON job_id = provisioner_jobs.id
-INNER JOIN
-	files
-	ON files.id = provisioner_jobs.file_id
-WHERE
-    -- Only fetch template version associated files.
-	storage_method = 'file'
-	AND provisioner_jobs.type = 'template_version_import'
-	AND file_id = $1
+	custom_roles
+ORDER BY
+	organization_id NULLS FIRST, lower(name) ASC
 `
 
-type GetFileTemplatesRow struct {
-	FileID                 uuid.UUID   `db:"file_id" json:"file_id"`
-	FileCreatedBy          uuid.UUID   `db:"file_created_by" json:"file_created_by"`
-	TemplateID             uuid.UUID   `db:"template_id" json:"template_id"`
-	TemplateOrganizationID uuid.UUID   `db:"template_organization_id" json:"template_organization_id"`
-	TemplateCreatedBy      uuid.UUID   `db:"template_created_by" json:"template_created_by"`
-	UserACL                TemplateACL `db:"user_acl" json:"user_acl"`
-	GroupACL               TemplateACL `db:"group_acl" json:"group_acl"`
-}
-
-// Get all templates that use a file.
-func (q *sqlQuerier) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]GetFileTemplatesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFileTemplates, fileID)
+func (q *sqlQuerier) GetCustomRoles(ctx context.Context) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomRoles)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFileTemplatesRow
+	var items []CustomRole
 	for rows.Next() {
-		var i GetFileTemplatesRow
+		var i CustomRole
 		if err := rows.Scan(
-			&i.FileID,
-			&i.FileCreatedBy,
-			&i.TemplateID,
-			&i.TemplateOrganizationID,
-			&i.TemplateCreatedBy,
-			&i.UserACL,
-			&i.GroupACL,
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -830,92 +1029,39 @@ func (q *sqlQuerier) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]
 	return items, nil
 }
 
-const insertFile = `-- name: InsertFile :one
-INSERT INTO
-	files (id, hash, created_at, created_by, mimetype, "data")
-VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING hash, created_at, created_by, mimetype, data, id
-`
-
-type InsertFileParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Hash      string    `db:"hash" json:"hash"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
-	Mimetype  string    `db:"mimetype" json:"mimetype"`
-	Data      []byte    `db:"data" json:"data"`
-}
-
-func (q *sqlQuerier) InsertFile(ctx context.Context, arg InsertFileParams) (File, error) {
-	row := q.db.QueryRowContext(ctx, insertFile,
-		arg.ID,
-		arg.Hash,
-		arg.CreatedAt,
-		arg.CreatedBy,
-		arg.Mimetype,
-		arg.Data,
-	)
-	var i File
-	err := row.Scan(
-		&i.Hash,
-		&i.CreatedAt,
-		&i.CreatedBy,
-		&i.Mimetype,
-		&i.Data,
-		&i.ID,
-	)
-	return i, err
-}
-
-const getGitAuthLink = `-- name: GetGitAuthLink :one
-SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id FROM git_auth_links WHERE provider_id = $1 AND user_id = $2
-`
-
-type GetGitAuthLinkParams struct {
-	ProviderID string    `db:"provider_id" json:"provider_id"`
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
-}
-
-func (q *sqlQuerier) GetGitAuthLink(ctx context.Context, arg GetGitAuthLinkParams) (GitAuthLink, error) {
-	row := q.db.QueryRowContext(ctx, getGitAuthLink, arg.ProviderID, arg.UserID)
-	var i GitAuthLink
-	err := row.Scan(
-		&i.ProviderID,
-		&i.UserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-	)
-	return i, err
-}
-
-const getGitAuthLinksByUserID = `-- name: GetGitAuthLinksByUserID :many
-SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id FROM git_auth_links WHERE user_id = $1
+const getCustomRolesByName = `-- name: GetCustomRolesByName :many
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	CASE
+		WHEN organization_id IS NULL THEN name
+		ELSE name || ':' || organization_id :: text
+	END = ANY($1 :: text[])
 `
 
-func (q *sqlQuerier) GetGitAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]GitAuthLink, error) {
-	rows, err := q.db.QueryContext(ctx, getGitAuthLinksByUserID, userID)
+// GetCustomRolesByName looks up roles by the names they are assigned with.
+// Organization roles are assigned as "name:organization_id".
+func (q *sqlQuerier) GetCustomRolesByName(ctx context.Context, lookupRoles []string) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomRolesByName, pq.Array(lookupRoles))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GitAuthLink
+	var items []CustomRole
 	for rows.Next() {
-		var i GitAuthLink
+		var i CustomRole
 		if err := rows.Scan(
-			&i.ProviderID,
-			&i.UserID,
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.OAuthAccessToken,
-			&i.OAuthRefreshToken,
-			&i.OAuthExpiry,
-			&i.OAuthAccessTokenKeyID,
-			&i.OAuthRefreshTokenKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -930,316 +1076,289 @@ func (q *sqlQuerier) GetGitAuthLinksByUserID(ctx context.Context, userID uuid.UU
 	return items, nil
 }
 
-const insertGitAuthLink = `-- name: InsertGitAuthLink :one
-INSERT INTO git_auth_links (
-    provider_id,
-    user_id,
-    created_at,
-    updated_at,
-    oauth_access_token,
-    oauth_access_token_key_id,
-    oauth_refresh_token,
-    oauth_refresh_token_key_id,
-    oauth_expiry
-) VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7,
-    $8,
-    $9
-) RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+const insertCustomRole = `-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
 `
 
-type InsertGitAuthLinkParams struct {
-	ProviderID             string         `db:"provider_id" json:"provider_id"`
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	CreatedAt              time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt              time.Time      `db:"updated_at" json:"updated_at"`
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+type InsertCustomRoleParams struct {
+	ID              uuid.UUID       `db:"id" json:"id"`
+	Name            string          `db:"name" json:"name"`
+	DisplayName     string          `db:"display_name" json:"display_name"`
+	OrganizationID  uuid.NullUUID   `db:"organization_id" json:"organization_id"`
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  json.RawMessage `db:"org_permissions" json:"org_permissions"`
+	UserPermissions json.RawMessage `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertGitAuthLink(ctx context.Context, arg InsertGitAuthLinkParams) (GitAuthLink, error) {
-	row := q.db.QueryRowContext(ctx, insertGitAuthLink,
-		arg.ProviderID,
-		arg.UserID,
+func (q *sqlQuerier) InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, insertCustomRole,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
 	)
-	var i GitAuthLink
+	var i CustomRole
 	err := row.Scan(
-		&i.ProviderID,
-		&i.UserID,
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
 	)
 	return i, err
 }
 
-const updateGitAuthLink = `-- name: UpdateGitAuthLink :one
-UPDATE git_auth_links SET
-    updated_at = $3,
-    oauth_access_token = $4,
-    oauth_access_token_key_id = $5,
-    oauth_refresh_token = $6,
-    oauth_refresh_token_key_id = $7,
-    oauth_expiry = $8
-WHERE provider_id = $1 AND user_id = $2 RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+const updateCustomRoleByID = `-- name: UpdateCustomRoleByID :one
+UPDATE
+	custom_roles
+SET
+	display_name = $2,
+	site_permissions = $3,
+	org_permissions = $4,
+	user_permissions = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
 `
 
-type UpdateGitAuthLinkParams struct {
-	ProviderID             string         `db:"provider_id" json:"provider_id"`
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	UpdatedAt              time.Time      `db:"updated_at" json:"updated_at"`
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+type UpdateCustomRoleByIDParams struct {
+	ID              uuid.UUID       `db:"id" json:"id"`
+	DisplayName     string          `db:"display_name" json:"display_name"`
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  json.RawMessage `db:"org_permissions" json:"org_permissions"`
+	UserPermissions json.RawMessage `db:"user_permissions" json:"user_permissions"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateGitAuthLink(ctx context.Context, arg UpdateGitAuthLinkParams) (GitAuthLink, error) {
-	row := q.db.QueryRowContext(ctx, updateGitAuthLink,
-		arg.ProviderID,
-		arg.UserID,
+func (q *sqlQuerier) UpdateCustomRoleByID(ctx context.Context, arg UpdateCustomRoleByIDParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, updateCustomRoleByID,
+		arg.ID,
+		arg.DisplayName,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
 		arg.UpdatedAt,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
 	)
-	var i GitAuthLink
+	var i CustomRole
 	err := row.Scan(
-		&i.ProviderID,
-		&i.UserID,
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
 	)
 	return i, err
 }
 
-const deleteGitSSHKey = `-- name: DeleteGitSSHKey :exec
-DELETE FROM
-	gitsshkeys
-WHERE
-	user_id = $1
+const getDBCryptKeys = `-- name: GetDBCryptKeys :many
+SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
 `
 
-func (q *sqlQuerier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteGitSSHKey, userID)
-	return err
+func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
+	rows, err := q.db.QueryContext(ctx, getDBCryptKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DBCryptKey
+	for rows.Next() {
+		var i DBCryptKey
+		if err := rows.Scan(
+			&i.Number,
+			&i.ActiveKeyDigest,
+			&i.RevokedKeyDigest,
+			&i.CreatedAt,
+			&i.RevokedAt,
+			&i.Test,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getGitSSHKey = `-- name: GetGitSSHKey :one
-SELECT
-	user_id, created_at, updated_at, private_key, public_key
-FROM
-	gitsshkeys
-WHERE
-	user_id = $1
+const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
+INSERT INTO dbcrypt_keys
+	(number, active_key_digest, created_at, test)
+VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
 `
 
-func (q *sqlQuerier) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSHKey, error) {
-	row := q.db.QueryRowContext(ctx, getGitSSHKey, userID)
-	var i GitSSHKey
-	err := row.Scan(
-		&i.UserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.PrivateKey,
-		&i.PublicKey,
-	)
-	return i, err
+type InsertDBCryptKeyParams struct {
+	Number          int32  `db:"number" json:"number"`
+	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
+	Test            string `db:"test" json:"test"`
 }
 
-const insertGitSSHKey = `-- name: InsertGitSSHKey :one
-INSERT INTO
-	gitsshkeys (
-		user_id,
-		created_at,
-		updated_at,
-		private_key,
-		public_key
-	)
-VALUES
-	($1, $2, $3, $4, $5) RETURNING user_id, created_at, updated_at, private_key, public_key
-`
-
-type InsertGitSSHKeyParams struct {
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-	PrivateKey string    `db:"private_key" json:"private_key"`
-	PublicKey  string    `db:"public_key" json:"public_key"`
-}
-
-func (q *sqlQuerier) InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error) {
-	row := q.db.QueryRowContext(ctx, insertGitSSHKey,
-		arg.UserID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.PrivateKey,
-		arg.PublicKey,
-	)
-	var i GitSSHKey
-	err := row.Scan(
-		&i.UserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.PrivateKey,
-		&i.PublicKey,
-	)
-	return i, err
+func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
+	return err
 }
 
-const updateGitSSHKey = `-- name: UpdateGitSSHKey :one
-UPDATE
-	gitsshkeys
+const revokeDBCryptKey = `-- name: RevokeDBCryptKey :exec
+UPDATE dbcrypt_keys
 SET
-	updated_at = $2,
-	private_key = $3,
-	public_key = $4
+	revoked_key_digest = active_key_digest,
+	active_key_digest = revoked_key_digest,
+	revoked_at = CURRENT_TIMESTAMP
 WHERE
-	user_id = $1
-RETURNING
-	user_id, created_at, updated_at, private_key, public_key
+	active_key_digest = $1::text
+AND
+	revoked_key_digest IS NULL
 `
 
-type UpdateGitSSHKeyParams struct {
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-	PrivateKey string    `db:"private_key" json:"private_key"`
-	PublicKey  string    `db:"public_key" json:"public_key"`
-}
-
-func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error) {
-	row := q.db.QueryRowContext(ctx, updateGitSSHKey,
-		arg.UserID,
-		arg.UpdatedAt,
-		arg.PrivateKey,
-		arg.PublicKey,
-	)
-	var i GitSSHKey
-	err := row.Scan(
-		&i.UserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.PrivateKey,
-		&i.PublicKey,
-	)
-	return i, err
+func (q *sqlQuerier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
+	_, err := q.db.ExecContext(ctx, revokeDBCryptKey, activeKeyDigest)
+	return err
 }
 
-const deleteGroupMemberFromGroup = `-- name: DeleteGroupMemberFromGroup :exec
-DELETE FROM
-	group_members
+const getFileByHashAndCreator = `-- name: GetFileByHashAndCreator :one
+SELECT
+	hash, created_at, created_by, mimetype, data, id
+FROM
+	files
 WHERE
-	user_id = $1 AND
-	group_id = $2
+	hash = $1
+AND
+	created_by = $2
+LIMIT
+	1
 `
 
-type DeleteGroupMemberFromGroupParams struct {
-	UserID  uuid.UUID `db:"user_id" json:"user_id"`
-	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+type GetFileByHashAndCreatorParams struct {
+	Hash      string    `db:"hash" json:"hash"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
 }
 
-func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupMemberFromGroup, arg.UserID, arg.GroupID)
-	return err
+func (q *sqlQuerier) GetFileByHashAndCreator(ctx context.Context, arg GetFileByHashAndCreatorParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, getFileByHashAndCreator, arg.Hash, arg.CreatedBy)
+	var i File
+	err := row.Scan(
+		&i.Hash,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Mimetype,
+		&i.Data,
+		&i.ID,
+	)
+	return i, err
 }
 
-const deleteGroupMembersByOrgAndUser = `-- name: DeleteGroupMembersByOrgAndUser :exec
-DELETE FROM
-	group_members
+const getFileByID = `-- name: GetFileByID :one
+SELECT
+	hash, created_at, created_by, mimetype, data, id
+FROM
+	files
 WHERE
-	group_members.user_id = $1
-	AND group_id = ANY(SELECT id FROM groups WHERE organization_id = $2)
+	id = $1
+LIMIT
+	1
 `
 
-type DeleteGroupMembersByOrgAndUserParams struct {
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-}
-
-func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupMembersByOrgAndUser, arg.UserID, arg.OrganizationID)
-	return err
+func (q *sqlQuerier) GetFileByID(ctx context.Context, id uuid.UUID) (File, error) {
+	row := q.db.QueryRowContext(ctx, getFileByID, id)
+	var i File
+	err := row.Scan(
+		&i.Hash,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Mimetype,
+		&i.Data,
+		&i.ID,
+	)
+	return i, err
 }
 
-const getGroupMembers = `-- name: GetGroupMembers :many
+const getFileTemplates = `-- name: GetFileTemplates :many
 SELECT
-	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule
+	files.id AS file_id,
+	files.created_by AS file_created_by,
+	templates.id AS template_id,
+	templates.organization_id AS template_organization_id,
+	templates.created_by AS template_created_by,
+	templates.user_acl,
+	templates.group_acl
 FROM
-	users
-LEFT JOIN
-	group_members
-ON
-	group_members.user_id = users.id AND
-	group_members.group_id = $1
-LEFT JOIN
-	organization_members
-ON
-	organization_members.user_id = users.id AND
-	organization_members.organization_id = $1
+	templates
+INNER JOIN
+	template_versions
+	ON templates.id = template_versions.template_id
+INNER JOIN
+	provisioner_jobs
+	ON job_id = provisioner_jobs.id
+INNER JOIN
+	files
+	ON files.id = provisioner_jobs.file_id
 WHERE
-	-- In either case, the group_id will only match an org or a group.
-    (group_members.group_id = $1
-         OR
-     organization_members.organization_id = $1)
-AND
-	users.status = 'active'
-AND
-	users.deleted = 'false'
+    -- Only fetch template version associated files.
+	storage_method = 'file'
+	AND provisioner_jobs.type = 'template_version_import'
+	AND file_id = $1
 `
 
-// If the group is a user made group, then we need to check the group_members table.
-// If it is the "Everyone" group, then we need to check the organization_members table.
-func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
+type GetFileTemplatesRow struct {
+	FileID                 uuid.UUID   `db:"file_id" json:"file_id"`
+	FileCreatedBy          uuid.UUID   `db:"file_created_by" json:"file_created_by"`
+	TemplateID             uuid.UUID   `db:"template_id" json:"template_id"`
+	TemplateOrganizationID uuid.UUID   `db:"template_organization_id" json:"template_organization_id"`
+	TemplateCreatedBy      uuid.UUID   `db:"template_created_by" json:"template_created_by"`
+	UserACL                TemplateACL `db:"user_acl" json:"user_acl"`
+	GroupACL               TemplateACL `db:"group_acl" json:"group_acl"`
+}
+
+// Get all templates that use a file.
+func (q *sqlQuerier) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]GetFileTemplatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFileTemplates, fileID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []GetFileTemplatesRow
 	for rows.Next() {
-		var i User
+		var i GetFileTemplatesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
+			&i.FileID,
+			&i.FileCreatedBy,
+			&i.TemplateID,
+			&i.TemplateOrganizationID,
+			&i.TemplateCreatedBy,
+			&i.UserACL,
+			&i.GroupACL,
 		); err != nil {
 			return nil, err
 		}
@@ -1254,151 +1373,92 @@ func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]
 	return items, nil
 }
 
-const insertGroupMember = `-- name: InsertGroupMember :exec
+const insertFile = `-- name: InsertFile :one
 INSERT INTO
-    group_members (user_id, group_id)
+	files (id, hash, created_at, created_by, mimetype, "data")
 VALUES
-    ($1, $2)
+	($1, $2, $3, $4, $5, $6) RETURNING hash, created_at, created_by, mimetype, data, id
 `
 
-type InsertGroupMemberParams struct {
-	UserID  uuid.UUID `db:"user_id" json:"user_id"`
-	GroupID uuid.UUID `db:"group_id" json:"group_id"`
-}
-
-func (q *sqlQuerier) InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error {
-	_, err := q.db.ExecContext(ctx, insertGroupMember, arg.UserID, arg.GroupID)
-	return err
+type InsertFileParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Hash      string    `db:"hash" json:"hash"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+	Mimetype  string    `db:"mimetype" json:"mimetype"`
+	Data      []byte    `db:"data" json:"data"`
 }
 
-const insertUserGroupsByName = `-- name: InsertUserGroupsByName :exec
-WITH groups AS (
-    SELECT
-        id
-    FROM
-        groups
-    WHERE
-        groups.organization_id = $2 AND
-        groups.name = ANY($3 :: text [])
-)
-INSERT INTO
-    group_members (user_id, group_id)
-SELECT
-    $1,
-    groups.id
-FROM
-    groups
-`
-
-type InsertUserGroupsByNameParams struct {
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	GroupNames     []string  `db:"group_names" json:"group_names"`
-}
-
-// InsertUserGroupsByName adds a user to all provided groups, if they exist.
-func (q *sqlQuerier) InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error {
-	_, err := q.db.ExecContext(ctx, insertUserGroupsByName, arg.UserID, arg.OrganizationID, pq.Array(arg.GroupNames))
-	return err
-}
-
-const deleteGroupByID = `-- name: DeleteGroupByID :exec
-DELETE FROM
-	groups
-WHERE
-	id = $1
-`
-
-func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupByID, id)
-	return err
-}
-
-const getGroupByID = `-- name: GetGroupByID :one
-SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
-FROM
-	groups
-WHERE
-	id = $1
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error) {
-	row := q.db.QueryRowContext(ctx, getGroupByID, id)
-	var i Group
+func (q *sqlQuerier) InsertFile(ctx context.Context, arg InsertFileParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, insertFile,
+		arg.ID,
+		arg.Hash,
+		arg.CreatedAt,
+		arg.CreatedBy,
+		arg.Mimetype,
+		arg.Data,
+	)
+	var i File
 	err := row.Scan(
+		&i.Hash,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Mimetype,
+		&i.Data,
 		&i.ID,
-		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
 	)
 	return i, err
 }
 
-const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
-SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
-FROM
-	groups
-WHERE
-	organization_id = $1
-AND
-	name = $2
-LIMIT
-	1
+const getGitAuthLink = `-- name: GetGitAuthLink :one
+SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id FROM git_auth_links WHERE provider_id = $1 AND user_id = $2
 `
 
-type GetGroupByOrgAndNameParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	Name           string    `db:"name" json:"name"`
+type GetGitAuthLinkParams struct {
+	ProviderID string    `db:"provider_id" json:"provider_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrgAndNameParams) (Group, error) {
-	row := q.db.QueryRowContext(ctx, getGroupByOrgAndName, arg.OrganizationID, arg.Name)
-	var i Group
+func (q *sqlQuerier) GetGitAuthLink(ctx context.Context, arg GetGitAuthLinkParams) (GitAuthLink, error) {
+	row := q.db.QueryRowContext(ctx, getGitAuthLink, arg.ProviderID, arg.UserID)
+	var i GitAuthLink
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.ProviderID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
 	)
 	return i, err
 }
 
-const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
-SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
-FROM
-	groups
-WHERE
-	organization_id = $1
+const getGitAuthLinksByUserID = `-- name: GetGitAuthLinksByUserID :many
+SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id FROM git_auth_links WHERE user_id = $1
 `
 
-func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error) {
-	rows, err := q.db.QueryContext(ctx, getGroupsByOrganizationID, organizationID)
+func (q *sqlQuerier) GetGitAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]GitAuthLink, error) {
+	rows, err := q.db.QueryContext(ctx, getGitAuthLinksByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Group
+	var items []GitAuthLink
 	for rows.Next() {
-		var i Group
+		var i GitAuthLink
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.OrganizationID,
-			&i.AvatarURL,
-			&i.QuotaAllowance,
-			&i.DisplayName,
-			&i.Source,
+			&i.ProviderID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.OAuthAccessTokenKeyID,
+			&i.OAuthRefreshTokenKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -1413,271 +1473,317 @@ func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organization
 	return items, nil
 }
 
-const insertAllUsersGroup = `-- name: InsertAllUsersGroup :one
-INSERT INTO groups (
-	id,
-	name,
-	organization_id
-)
-VALUES
-	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+const insertGitAuthLink = `-- name: InsertGitAuthLink :one
+INSERT INTO git_auth_links (
+    provider_id,
+    user_id,
+    created_at,
+    updated_at,
+    oauth_access_token,
+    oauth_access_token_key_id,
+    oauth_refresh_token,
+    oauth_refresh_token_key_id,
+    oauth_expiry
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+) RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
 `
 
-// We use the organization_id as the id
-// for simplicity since all users is
-// every member of the org.
-func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error) {
-	row := q.db.QueryRowContext(ctx, insertAllUsersGroup, organizationID)
-	var i Group
+type InsertGitAuthLinkParams struct {
+	ProviderID             string         `db:"provider_id" json:"provider_id"`
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	CreatedAt              time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt              time.Time      `db:"updated_at" json:"updated_at"`
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+}
+
+func (q *sqlQuerier) InsertGitAuthLink(ctx context.Context, arg InsertGitAuthLinkParams) (GitAuthLink, error) {
+	row := q.db.QueryRowContext(ctx, insertGitAuthLink,
+		arg.ProviderID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+	)
+	var i GitAuthLink
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.ProviderID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
 	)
 	return i, err
 }
 
-const insertGroup = `-- name: InsertGroup :one
-INSERT INTO groups (
-	id,
-	name,
-	display_name,
-	organization_id,
-	avatar_url,
-	quota_allowance
-)
-VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+const updateGitAuthLink = `-- name: UpdateGitAuthLink :one
+UPDATE git_auth_links SET
+    updated_at = $3,
+    oauth_access_token = $4,
+    oauth_access_token_key_id = $5,
+    oauth_refresh_token = $6,
+    oauth_refresh_token_key_id = $7,
+    oauth_expiry = $8
+WHERE provider_id = $1 AND user_id = $2 RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
 `
 
-type InsertGroupParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
-}
+type UpdateGitAuthLinkParams struct {
+	ProviderID             string         `db:"provider_id" json:"provider_id"`
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	UpdatedAt              time.Time      `db:"updated_at" json:"updated_at"`
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+}
 
-func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
-	row := q.db.QueryRowContext(ctx, insertGroup,
-		arg.ID,
-		arg.Name,
-		arg.DisplayName,
-		arg.OrganizationID,
-		arg.AvatarURL,
-		arg.QuotaAllowance,
+func (q *sqlQuerier) UpdateGitAuthLink(ctx context.Context, arg UpdateGitAuthLinkParams) (GitAuthLink, error) {
+	row := q.db.QueryRowContext(ctx, updateGitAuthLink,
+		arg.ProviderID,
+		arg.UserID,
+		arg.UpdatedAt,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
 	)
-	var i Group
+	var i GitAuthLink
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.ProviderID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
 	)
 	return i, err
 }
 
-const insertMissingGroups = `-- name: InsertMissingGroups :many
-INSERT INTO groups (
-	id,
-	name,
-	organization_id,
-    source
-)
+const deleteGitSSHKey = `-- name: DeleteGitSSHKey :exec
+DELETE FROM
+	gitsshkeys
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGitSSHKey, userID)
+	return err
+}
+
+const getGitSSHKey = `-- name: GetGitSSHKey :one
 SELECT
-    gen_random_uuid(),
-    group_name,
-    $1,
-    $2
+	user_id, created_at, updated_at, private_key, public_key
 FROM
-    UNNEST($3 :: text[]) AS group_name
-ON CONFLICT DO NOTHING
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	gitsshkeys
+WHERE
+	user_id = $1
 `
 
-type InsertMissingGroupsParams struct {
-	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
-	Source         GroupSource `db:"source" json:"source"`
-	GroupNames     []string    `db:"group_names" json:"group_names"`
+func (q *sqlQuerier) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSHKey, error) {
+	row := q.db.QueryRowContext(ctx, getGitSSHKey, userID)
+	var i GitSSHKey
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PrivateKey,
+		&i.PublicKey,
+	)
+	return i, err
 }
 
-// Inserts any group by name that does not exist. All new groups are given
-// a random uuid, are inserted into the same organization. They have the default
-// values for avatar, display name, and quota allowance (all zero values).
-// If the name conflicts, do nothing.
-func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error) {
-	rows, err := q.db.QueryContext(ctx, insertMissingGroups, arg.OrganizationID, arg.Source, pq.Array(arg.GroupNames))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Group
-	for rows.Next() {
-		var i Group
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.OrganizationID,
-			&i.AvatarURL,
-			&i.QuotaAllowance,
-			&i.DisplayName,
-			&i.Source,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const insertGitSSHKey = `-- name: InsertGitSSHKey :one
+INSERT INTO
+	gitsshkeys (
+		user_id,
+		created_at,
+		updated_at,
+		private_key,
+		public_key
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING user_id, created_at, updated_at, private_key, public_key
+`
+
+type InsertGitSSHKeyParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	PrivateKey string    `db:"private_key" json:"private_key"`
+	PublicKey  string    `db:"public_key" json:"public_key"`
 }
 
-const updateGroupByID = `-- name: UpdateGroupByID :one
+func (q *sqlQuerier) InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error) {
+	row := q.db.QueryRowContext(ctx, insertGitSSHKey,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.PrivateKey,
+		arg.PublicKey,
+	)
+	var i GitSSHKey
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PrivateKey,
+		&i.PublicKey,
+	)
+	return i, err
+}
+
+const updateGitSSHKey = `-- name: UpdateGitSSHKey :one
 UPDATE
-	groups
+	gitsshkeys
 SET
-	name = $1,
-	display_name = $2,
-	avatar_url = $3,
-	quota_allowance = $4
+	updated_at = $2,
+	private_key = $3,
+	public_key = $4
 WHERE
-	id = $5
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	user_id = $1
+RETURNING
+	user_id, created_at, updated_at, private_key, public_key
 `
 
-type UpdateGroupByIDParams struct {
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
-	ID             uuid.UUID `db:"id" json:"id"`
+type UpdateGitSSHKeyParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	PrivateKey string    `db:"private_key" json:"private_key"`
+	PublicKey  string    `db:"public_key" json:"public_key"`
 }
 
-func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
-	row := q.db.QueryRowContext(ctx, updateGroupByID,
-		arg.Name,
-		arg.DisplayName,
-		arg.AvatarURL,
-		arg.QuotaAllowance,
-		arg.ID,
+func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error) {
+	row := q.db.QueryRowContext(ctx, updateGitSSHKey,
+		arg.UserID,
+		arg.UpdatedAt,
+		arg.PrivateKey,
+		arg.PublicKey,
 	)
-	var i Group
+	var i GitSSHKey
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PrivateKey,
+		&i.PublicKey,
 	)
 	return i, err
 }
 
-const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
-WITH app_stats_by_user_and_agent AS (
-	SELECT
-		s.start_time,
-		60 as seconds,
-		w.template_id,
-		was.user_id,
-		was.agent_id,
-		was.access_method,
-		was.slug_or_port,
-		wa.display_name,
-		wa.icon,
-		(wa.slug IS NOT NULL)::boolean AS is_app
-	FROM workspace_app_stats was
-	JOIN workspaces w ON (
-		w.id = was.workspace_id
-		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN w.template_id = ANY($1::uuid[]) ELSE TRUE END
-	)
-	-- We do a left join here because we want to include user IDs that have used
-	-- e.g. ports when counting active users.
-	LEFT JOIN workspace_apps wa ON (
-		wa.agent_id = was.agent_id
-		AND wa.slug = was.slug_or_port
-	)
-	-- This table contains both 1 minute entries and >1 minute entries,
-	-- to calculate this with our uniqueness constraints, we generate series
-	-- for the longer intervals.
-	CROSS JOIN LATERAL generate_series(
-		date_trunc('minute', was.session_started_at),
-		-- Subtract 1 microsecond to avoid creating an extra series.
-		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
-		'1 minute'::interval
-	) s(start_time)
-	WHERE
-		s.start_time >= $2::timestamptz
-		-- Subtract one minute because the series only contains the start time.
-		AND s.start_time < ($3::timestamptz) - '1 minute'::interval
-	GROUP BY s.start_time, w.template_id, was.user_id, was.agent_id, was.access_method, was.slug_or_port, wa.display_name, wa.icon, wa.slug
-)
+const deleteGroupMemberFromGroup = `-- name: DeleteGroupMemberFromGroup :exec
+DELETE FROM
+	group_members
+WHERE
+	user_id = $1 AND
+	group_id = $2
+`
 
-SELECT
-	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
-	-- Return IDs so we can combine this with GetTemplateInsights.
-	array_agg(DISTINCT user_id)::uuid[] AS active_user_ids,
-	access_method,
-	slug_or_port,
-	display_name,
-	icon,
-	is_app,
-	SUM(seconds) AS usage_seconds
-FROM app_stats_by_user_and_agent
-GROUP BY access_method, slug_or_port, display_name, icon, is_app
+type DeleteGroupMemberFromGroupParams struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
+func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMemberFromGroup, arg.UserID, arg.GroupID)
+	return err
+}
+
+const deleteGroupMembersByOrgAndUser = `-- name: DeleteGroupMembersByOrgAndUser :exec
+DELETE FROM
+	group_members
+WHERE
+	group_members.user_id = $1
+	AND group_id = ANY(SELECT id FROM groups WHERE organization_id = $2)
 `
 
-type GetTemplateAppInsightsParams struct {
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
+type DeleteGroupMembersByOrgAndUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
 }
 
-type GetTemplateAppInsightsRow struct {
-	TemplateIDs   []uuid.UUID    `db:"template_ids" json:"template_ids"`
-	ActiveUserIDs []uuid.UUID    `db:"active_user_ids" json:"active_user_ids"`
-	AccessMethod  string         `db:"access_method" json:"access_method"`
-	SlugOrPort    string         `db:"slug_or_port" json:"slug_or_port"`
-	DisplayName   sql.NullString `db:"display_name" json:"display_name"`
-	Icon          sql.NullString `db:"icon" json:"icon"`
-	IsApp         bool           `db:"is_app" json:"is_app"`
-	UsageSeconds  int64          `db:"usage_seconds" json:"usage_seconds"`
+func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMembersByOrgAndUser, arg.UserID, arg.OrganizationID)
+	return err
 }
 
-// GetTemplateAppInsights returns the aggregate usage of each app in a given
-// timeframe. The result can be filtered on template_ids, meaning only user data
-// from workspaces based on those templates will be included.
-func (q *sqlQuerier) GetTemplateAppInsights(ctx context.Context, arg GetTemplateAppInsightsParams) ([]GetTemplateAppInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateAppInsights, pq.Array(arg.TemplateIDs), arg.StartTime, arg.EndTime)
+const getGroupMembers = `-- name: GetGroupMembers :many
+SELECT
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule, users.is_service_account
+FROM
+	users
+LEFT JOIN
+	group_members
+ON
+	group_members.user_id = users.id AND
+	group_members.group_id = $1
+LEFT JOIN
+	organization_members
+ON
+	organization_members.user_id = users.id AND
+	organization_members.organization_id = $1
+WHERE
+	-- In either case, the group_id will only match an org or a group.
+    (group_members.group_id = $1
+         OR
+     organization_members.organization_id = $1)
+AND
+	users.status = 'active'
+AND
+	users.deleted = 'false'
+`
+
+// If the group is a user made group, then we need to check the group_members table.
+// If it is the "Everyone" group, then we need to check the organization_members table.
+func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateAppInsightsRow
+	var items []User
 	for rows.Next() {
-		var i GetTemplateAppInsightsRow
+		var i User
 		if err := rows.Scan(
-			pq.Array(&i.TemplateIDs),
-			pq.Array(&i.ActiveUserIDs),
-			&i.AccessMethod,
-			&i.SlugOrPort,
-			&i.DisplayName,
-			&i.Icon,
-			&i.IsApp,
-			&i.UsageSeconds,
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.IsServiceAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -1692,270 +1798,151 @@ func (q *sqlQuerier) GetTemplateAppInsights(ctx context.Context, arg GetTemplate
 	return items, nil
 }
 
-const getTemplateDailyInsights = `-- name: GetTemplateDailyInsights :many
-WITH ts AS (
-	SELECT
-		d::timestamptz AS from_,
-		CASE
-			WHEN (d::timestamptz + '1 day'::interval) <= $1::timestamptz
-			THEN (d::timestamptz + '1 day'::interval)
-			ELSE $1::timestamptz
-		END AS to_
-	FROM
-		-- Subtract 1 second from end_time to avoid including the next interval in the results.
-		generate_series($2::timestamptz, ($1::timestamptz) - '1 second'::interval, '1 day'::interval) AS d
-), unflattened_usage_by_day AS (
-	-- We select data from both workspace agent stats and workspace app stats to
-	-- get a complete picture of usage. This matches how usage is calculated by
-	-- the combination of GetTemplateInsights and GetTemplateAppInsights. We use
-	-- a union all to avoid a costly distinct operation.
-	--
-	-- Note that one query must perform a left join so that all intervals are
-	-- present at least once.
-	SELECT
-		ts.from_, ts.to_,
-		was.template_id,
-		was.user_id
-	FROM ts
-	LEFT JOIN workspace_agent_stats was ON (
-		was.created_at >= ts.from_
-		AND was.created_at < ts.to_
-		AND was.connection_count > 0
-		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN was.template_id = ANY($3::uuid[]) ELSE TRUE END
-	)
-	GROUP BY ts.from_, ts.to_, was.template_id, was.user_id
+const insertGroupMember = `-- name: InsertGroupMember :exec
+INSERT INTO
+    group_members (user_id, group_id)
+VALUES
+    ($1, $2)
+`
 
-	UNION ALL
+type InsertGroupMemberParams struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
 
-	SELECT
-		ts.from_, ts.to_,
-		w.template_id,
-		was.user_id
-	FROM ts
-	JOIN workspace_app_stats was ON (
-		(was.session_started_at >= ts.from_ AND was.session_started_at < ts.to_)
-		OR (was.session_ended_at > ts.from_ AND was.session_ended_at < ts.to_)
-		OR (was.session_started_at < ts.from_ AND was.session_ended_at >= ts.to_)
-	)
-	JOIN workspaces w ON (
-		w.id = was.workspace_id
-		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN w.template_id = ANY($3::uuid[]) ELSE TRUE END
-	)
-	GROUP BY ts.from_, ts.to_, w.template_id, was.user_id
-)
+func (q *sqlQuerier) InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error {
+	_, err := q.db.ExecContext(ctx, insertGroupMember, arg.UserID, arg.GroupID)
+	return err
+}
 
+const insertUserGroupsByName = `-- name: InsertUserGroupsByName :exec
+WITH groups AS (
+    SELECT
+        id
+    FROM
+        groups
+    WHERE
+        groups.organization_id = $2 AND
+        groups.name = ANY($3 :: text [])
+)
+INSERT INTO
+    group_members (user_id, group_id)
 SELECT
-	from_ AS start_time,
-	to_ AS end_time,
-	array_remove(array_agg(DISTINCT template_id), NULL)::uuid[] AS template_ids,
-	COUNT(DISTINCT user_id) AS active_users
-FROM unflattened_usage_by_day
-GROUP BY from_, to_
+    $1,
+    groups.id
+FROM
+    groups
 `
 
-type GetTemplateDailyInsightsParams struct {
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+type InsertUserGroupsByNameParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	GroupNames     []string  `db:"group_names" json:"group_names"`
 }
 
-type GetTemplateDailyInsightsRow struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-	ActiveUsers int64       `db:"active_users" json:"active_users"`
+// InsertUserGroupsByName adds a user to all provided groups, if they exist.
+func (q *sqlQuerier) InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserGroupsByName, arg.UserID, arg.OrganizationID, pq.Array(arg.GroupNames))
+	return err
 }
 
-// GetTemplateDailyInsights returns all daily intervals between start and end
-// time, if end time is a partial day, it will be included in the results and
-// that interval will be less than 24 hours. If there is no data for a selected
-// interval/template, it will be included in the results with 0 active users.
-func (q *sqlQuerier) GetTemplateDailyInsights(ctx context.Context, arg GetTemplateDailyInsightsParams) ([]GetTemplateDailyInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateDailyInsights, arg.EndTime, arg.StartTime, pq.Array(arg.TemplateIDs))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetTemplateDailyInsightsRow
-	for rows.Next() {
-		var i GetTemplateDailyInsightsRow
-		if err := rows.Scan(
-			&i.StartTime,
-			&i.EndTime,
-			pq.Array(&i.TemplateIDs),
-			&i.ActiveUsers,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const deleteGroupByID = `-- name: DeleteGroupByID :exec
+DELETE FROM
+	groups
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupByID, id)
+	return err
 }
 
-const getTemplateInsights = `-- name: GetTemplateInsights :one
-WITH agent_stats_by_interval_and_user AS (
-	SELECT
-		date_trunc('minute', was.created_at),
-		was.user_id,
-		array_agg(was.template_id) AS template_ids,
-		CASE WHEN SUM(was.session_count_vscode) > 0 THEN 60 ELSE 0 END AS usage_vscode_seconds,
-		CASE WHEN SUM(was.session_count_jetbrains) > 0 THEN 60 ELSE 0 END AS usage_jetbrains_seconds,
-		CASE WHEN SUM(was.session_count_reconnecting_pty) > 0 THEN 60 ELSE 0 END AS usage_reconnecting_pty_seconds,
-		CASE WHEN SUM(was.session_count_ssh) > 0 THEN 60 ELSE 0 END AS usage_ssh_seconds
-	FROM workspace_agent_stats was
-	WHERE
-		was.created_at >= $1::timestamptz
-		AND was.created_at < $2::timestamptz
-		AND was.connection_count > 0
-		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN was.template_id = ANY($3::uuid[]) ELSE TRUE END
-	GROUP BY date_trunc('minute', was.created_at), was.user_id
-), template_ids AS (
-	SELECT array_agg(DISTINCT template_id) AS ids
-	FROM agent_stats_by_interval_and_user, unnest(template_ids) template_id
-	WHERE template_id IS NOT NULL
-)
-
+const getGroupByID = `-- name: GetGroupByID :one
 SELECT
-	COALESCE((SELECT ids FROM template_ids), '{}')::uuid[] AS template_ids,
-	-- Return IDs so we can combine this with GetTemplateAppInsights.
-	COALESCE(array_agg(DISTINCT user_id), '{}')::uuid[] AS active_user_ids,
-	COALESCE(SUM(usage_vscode_seconds), 0)::bigint AS usage_vscode_seconds,
-	COALESCE(SUM(usage_jetbrains_seconds), 0)::bigint AS usage_jetbrains_seconds,
-	COALESCE(SUM(usage_reconnecting_pty_seconds), 0)::bigint AS usage_reconnecting_pty_seconds,
-	COALESCE(SUM(usage_ssh_seconds), 0)::bigint AS usage_ssh_seconds
-FROM agent_stats_by_interval_and_user
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+FROM
+	groups
+WHERE
+	id = $1
+LIMIT
+	1
 `
 
-type GetTemplateInsightsParams struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-}
-
-type GetTemplateInsightsRow struct {
-	TemplateIDs                 []uuid.UUID `db:"template_ids" json:"template_ids"`
-	ActiveUserIDs               []uuid.UUID `db:"active_user_ids" json:"active_user_ids"`
-	UsageVscodeSeconds          int64       `db:"usage_vscode_seconds" json:"usage_vscode_seconds"`
-	UsageJetbrainsSeconds       int64       `db:"usage_jetbrains_seconds" json:"usage_jetbrains_seconds"`
-	UsageReconnectingPtySeconds int64       `db:"usage_reconnecting_pty_seconds" json:"usage_reconnecting_pty_seconds"`
-	UsageSshSeconds             int64       `db:"usage_ssh_seconds" json:"usage_ssh_seconds"`
-}
-
-// GetTemplateInsights has a granularity of 5 minutes where if a session/app was
-// in use during a minute, we will add 5 minutes to the total usage for that
-// session/app (per user).
-func (q *sqlQuerier) GetTemplateInsights(ctx context.Context, arg GetTemplateInsightsParams) (GetTemplateInsightsRow, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
-	var i GetTemplateInsightsRow
+func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByID, id)
+	var i Group
 	err := row.Scan(
-		pq.Array(&i.TemplateIDs),
-		pq.Array(&i.ActiveUserIDs),
-		&i.UsageVscodeSeconds,
-		&i.UsageJetbrainsSeconds,
-		&i.UsageReconnectingPtySeconds,
-		&i.UsageSshSeconds,
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
 	)
 	return i, err
 }
 
-const getTemplateParameterInsights = `-- name: GetTemplateParameterInsights :many
-WITH latest_workspace_builds AS (
-	SELECT
-		wb.id,
-		wbmax.template_id,
-		wb.template_version_id
-	FROM (
-		SELECT
-			tv.template_id, wbmax.workspace_id, MAX(wbmax.build_number) as max_build_number
-		FROM workspace_builds wbmax
-		JOIN template_versions tv ON (tv.id = wbmax.template_version_id)
-		WHERE
-			wbmax.created_at >= $1::timestamptz
-			AND wbmax.created_at < $2::timestamptz
-			AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN tv.template_id = ANY($3::uuid[]) ELSE TRUE END
-		GROUP BY tv.template_id, wbmax.workspace_id
-	) wbmax
-	JOIN workspace_builds wb ON (
-		wb.workspace_id = wbmax.workspace_id
-		AND wb.build_number = wbmax.max_build_number
-	)
-), unique_template_params AS (
-	SELECT
-		ROW_NUMBER() OVER () AS num,
-		array_agg(DISTINCT wb.template_id)::uuid[] AS template_ids,
-		array_agg(wb.id)::uuid[] AS workspace_build_ids,
-		tvp.name,
-		tvp.type,
-		tvp.display_name,
-		tvp.description,
-		tvp.options
-	FROM latest_workspace_builds wb
-	JOIN template_version_parameters tvp ON (tvp.template_version_id = wb.template_version_id)
-	GROUP BY tvp.name, tvp.type, tvp.display_name, tvp.description, tvp.options
-)
-
+const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
 SELECT
-	utp.num,
-	utp.template_ids,
-	utp.name,
-	utp.type,
-	utp.display_name,
-	utp.description,
-	utp.options,
-	wbp.value,
-	COUNT(wbp.value) AS count
-FROM unique_template_params utp
-JOIN workspace_build_parameters wbp ON (utp.workspace_build_ids @> ARRAY[wbp.workspace_build_id] AND utp.name = wbp.name)
-GROUP BY utp.num, utp.template_ids, utp.name, utp.type, utp.display_name, utp.description, utp.options, wbp.value
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+FROM
+	groups
+WHERE
+	organization_id = $1
+AND
+	name = $2
+LIMIT
+	1
 `
 
-type GetTemplateParameterInsightsParams struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+type GetGroupByOrgAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
 }
 
-type GetTemplateParameterInsightsRow struct {
-	Num         int64           `db:"num" json:"num"`
-	TemplateIDs []uuid.UUID     `db:"template_ids" json:"template_ids"`
-	Name        string          `db:"name" json:"name"`
-	Type        string          `db:"type" json:"type"`
-	DisplayName string          `db:"display_name" json:"display_name"`
-	Description string          `db:"description" json:"description"`
-	Options     json.RawMessage `db:"options" json:"options"`
-	Value       string          `db:"value" json:"value"`
-	Count       int64           `db:"count" json:"count"`
+func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrgAndNameParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByOrgAndName, arg.OrganizationID, arg.Name)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
 }
 
-// GetTemplateParameterInsights does for each template in a given timeframe,
-// look for the latest workspace build (for every workspace) that has been
-// created in the timeframe and return the aggregate usage counts of parameter
-// values.
-func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateParameterInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
+SELECT
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+FROM
+	groups
+WHERE
+	organization_id = $1
+`
+
+func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupsByOrganizationID, organizationID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateParameterInsightsRow
+	var items []Group
 	for rows.Next() {
-		var i GetTemplateParameterInsightsRow
+		var i Group
 		if err := rows.Scan(
-			&i.Num,
-			pq.Array(&i.TemplateIDs),
+			&i.ID,
 			&i.Name,
-			&i.Type,
+			&i.OrganizationID,
+			&i.AvatarURL,
+			&i.QuotaAllowance,
 			&i.DisplayName,
-			&i.Description,
-			&i.Options,
-			&i.Value,
-			&i.Count,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -1970,61 +1957,123 @@ func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTe
 	return items, nil
 }
 
-const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
-SELECT
-	workspace_agent_stats.user_id,
-	users.username,
-	users.avatar_url,
-	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
-	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
-	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
-FROM workspace_agent_stats
-JOIN users ON (users.id = workspace_agent_stats.user_id)
-WHERE
-	workspace_agent_stats.created_at >= $1
-	AND workspace_agent_stats.created_at < $2
-	AND workspace_agent_stats.connection_median_latency_ms > 0
-	AND workspace_agent_stats.connection_count > 0
-	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN template_id = ANY($3::uuid[]) ELSE TRUE END
-GROUP BY workspace_agent_stats.user_id, users.username, users.avatar_url
-ORDER BY user_id ASC
+const insertAllUsersGroup = `-- name: InsertAllUsersGroup :one
+INSERT INTO groups (
+	id,
+	name,
+	organization_id
+)
+VALUES
+	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
 `
 
-type GetUserLatencyInsightsParams struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-}
-
-type GetUserLatencyInsightsRow struct {
-	UserID                       uuid.UUID      `db:"user_id" json:"user_id"`
-	Username                     string         `db:"username" json:"username"`
-	AvatarURL                    sql.NullString `db:"avatar_url" json:"avatar_url"`
-	TemplateIDs                  []uuid.UUID    `db:"template_ids" json:"template_ids"`
-	WorkspaceConnectionLatency50 float64        `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64        `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+// We use the organization_id as the id
+// for simplicity since all users is
+// every member of the org.
+func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error) {
+	row := q.db.QueryRowContext(ctx, insertAllUsersGroup, organizationID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
 }
 
-// GetUserLatencyInsights returns the median and 95th percentile connection
-// latency that users have experienced. The result can be filtered on
-// template_ids, meaning only user data from workspaces based on those templates
-// will be included.
-func (q *sqlQuerier) GetUserLatencyInsights(ctx context.Context, arg GetUserLatencyInsightsParams) ([]GetUserLatencyInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUserLatencyInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+const insertGroup = `-- name: InsertGroup :one
+INSERT INTO groups (
+	id,
+	name,
+	display_name,
+	organization_id,
+	avatar_url,
+	quota_allowance
+)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+`
+
+type InsertGroupParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	DisplayName    string    `db:"display_name" json:"display_name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+}
+
+func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, insertGroup,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.AvatarURL,
+		arg.QuotaAllowance,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
+}
+
+const insertMissingGroups = `-- name: InsertMissingGroups :many
+INSERT INTO groups (
+	id,
+	name,
+	organization_id,
+    source
+)
+SELECT
+    gen_random_uuid(),
+    group_name,
+    $1,
+    $2
+FROM
+    UNNEST($3 :: text[]) AS group_name
+ON CONFLICT DO NOTHING
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+`
+
+type InsertMissingGroupsParams struct {
+	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
+	Source         GroupSource `db:"source" json:"source"`
+	GroupNames     []string    `db:"group_names" json:"group_names"`
+}
+
+// Inserts any group by name that does not exist. All new groups are given
+// a random uuid, are inserted into the same organization. They have the default
+// values for avatar, display name, and quota allowance (all zero values).
+// If the name conflicts, do nothing.
+func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, insertMissingGroups, arg.OrganizationID, arg.Source, pq.Array(arg.GroupNames))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserLatencyInsightsRow
+	var items []Group
 	for rows.Next() {
-		var i GetUserLatencyInsightsRow
+		var i Group
 		if err := rows.Scan(
-			&i.UserID,
-			&i.Username,
+			&i.ID,
+			&i.Name,
+			&i.OrganizationID,
 			&i.AvatarURL,
-			pq.Array(&i.TemplateIDs),
-			&i.WorkspaceConnectionLatency50,
-			&i.WorkspaceConnectionLatency95,
+			&i.QuotaAllowance,
+			&i.DisplayName,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -2039,64 +2088,142 @@ func (q *sqlQuerier) GetUserLatencyInsights(ctx context.Context, arg GetUserLate
 	return items, nil
 }
 
-const deleteLicense = `-- name: DeleteLicense :one
-DELETE
-FROM licenses
-WHERE id = $1
-RETURNING id
+const updateGroupByID = `-- name: UpdateGroupByID :one
+UPDATE
+	groups
+SET
+	name = $1,
+	display_name = $2,
+	avatar_url = $3,
+	quota_allowance = $4
+WHERE
+	id = $5
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
 `
 
-func (q *sqlQuerier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
-	row := q.db.QueryRowContext(ctx, deleteLicense, id)
-	err := row.Scan(&id)
-	return id, err
+type UpdateGroupByIDParams struct {
+	Name           string    `db:"name" json:"name"`
+	DisplayName    string    `db:"display_name" json:"display_name"`
+	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	ID             uuid.UUID `db:"id" json:"id"`
 }
 
-const getLicenseByID = `-- name: GetLicenseByID :one
-SELECT
-	id, uploaded_at, jwt, exp, uuid
-FROM
-	licenses
-WHERE
-	id = $1
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetLicenseByID(ctx context.Context, id int32) (License, error) {
-	row := q.db.QueryRowContext(ctx, getLicenseByID, id)
-	var i License
+func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, updateGroupByID,
+		arg.Name,
+		arg.DisplayName,
+		arg.AvatarURL,
+		arg.QuotaAllowance,
+		arg.ID,
+	)
+	var i Group
 	err := row.Scan(
 		&i.ID,
-		&i.UploadedAt,
-		&i.JWT,
-		&i.Exp,
-		&i.UUID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
 	)
 	return i, err
 }
 
-const getLicenses = `-- name: GetLicenses :many
-SELECT id, uploaded_at, jwt, exp, uuid
-FROM licenses
-ORDER BY (id)
+const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
+WITH app_stats_by_user_and_agent AS (
+	SELECT
+		s.start_time,
+		60 as seconds,
+		w.template_id,
+		was.user_id,
+		was.agent_id,
+		was.access_method,
+		was.slug_or_port,
+		wa.display_name,
+		wa.icon,
+		(wa.slug IS NOT NULL)::boolean AS is_app
+	FROM workspace_app_stats was
+	JOIN workspaces w ON (
+		w.id = was.workspace_id
+		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN w.template_id = ANY($1::uuid[]) ELSE TRUE END
+	)
+	-- We do a left join here because we want to include user IDs that have used
+	-- e.g. ports when counting active users.
+	LEFT JOIN workspace_apps wa ON (
+		wa.agent_id = was.agent_id
+		AND wa.slug = was.slug_or_port
+	)
+	-- This table contains both 1 minute entries and >1 minute entries,
+	-- to calculate this with our uniqueness constraints, we generate series
+	-- for the longer intervals.
+	CROSS JOIN LATERAL generate_series(
+		date_trunc('minute', was.session_started_at),
+		-- Subtract 1 microsecond to avoid creating an extra series.
+		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
+		'1 minute'::interval
+	) s(start_time)
+	WHERE
+		s.start_time >= $2::timestamptz
+		-- Subtract one minute because the series only contains the start time.
+		AND s.start_time < ($3::timestamptz) - '1 minute'::interval
+		-- Service accounts are not counted as active users.
+		AND was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
+	GROUP BY s.start_time, w.template_id, was.user_id, was.agent_id, was.access_method, was.slug_or_port, wa.display_name, wa.icon, wa.slug
+)
+
+SELECT
+	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
+	-- Return IDs so we can combine this with GetTemplateInsights.
+	array_agg(DISTINCT user_id)::uuid[] AS active_user_ids,
+	access_method,
+	slug_or_port,
+	display_name,
+	icon,
+	is_app,
+	SUM(seconds) AS usage_seconds
+FROM app_stats_by_user_and_agent
+GROUP BY access_method, slug_or_port, display_name, icon, is_app
 `
 
-func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
-	rows, err := q.db.QueryContext(ctx, getLicenses)
+type GetTemplateAppInsightsParams struct {
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+}
+
+type GetTemplateAppInsightsRow struct {
+	TemplateIDs   []uuid.UUID    `db:"template_ids" json:"template_ids"`
+	ActiveUserIDs []uuid.UUID    `db:"active_user_ids" json:"active_user_ids"`
+	AccessMethod  string         `db:"access_method" json:"access_method"`
+	SlugOrPort    string         `db:"slug_or_port" json:"slug_or_port"`
+	DisplayName   sql.NullString `db:"display_name" json:"display_name"`
+	Icon          sql.NullString `db:"icon" json:"icon"`
+	IsApp         bool           `db:"is_app" json:"is_app"`
+	UsageSeconds  int64          `db:"usage_seconds" json:"usage_seconds"`
+}
+
+// GetTemplateAppInsights returns the aggregate usage of each app in a given
+// timeframe. The result can be filtered on template_ids, meaning only user data
+// from workspaces based on those templates will be included.
+func (q *sqlQuerier) GetTemplateAppInsights(ctx context.Context, arg GetTemplateAppInsightsParams) ([]GetTemplateAppInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAppInsights, pq.Array(arg.TemplateIDs), arg.StartTime, arg.EndTime)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []License
+	var items []GetTemplateAppInsightsRow
 	for rows.Next() {
-		var i License
+		var i GetTemplateAppInsightsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UploadedAt,
-			&i.JWT,
-			&i.Exp,
-			&i.UUID,
+			pq.Array(&i.TemplateIDs),
+			pq.Array(&i.ActiveUserIDs),
+			&i.AccessMethod,
+			&i.SlugOrPort,
+			&i.DisplayName,
+			&i.Icon,
+			&i.IsApp,
+			&i.UsageSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -2111,133 +2238,103 @@ func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
 	return items, nil
 }
 
-const getUnexpiredLicenses = `-- name: GetUnexpiredLicenses :many
-SELECT id, uploaded_at, jwt, exp, uuid
-FROM licenses
-WHERE exp > NOW()
-ORDER BY (id)
-`
-
-func (q *sqlQuerier) GetUnexpiredLicenses(ctx context.Context) ([]License, error) {
-	rows, err := q.db.QueryContext(ctx, getUnexpiredLicenses)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []License
-	for rows.Next() {
-		var i License
-		if err := rows.Scan(
-			&i.ID,
-			&i.UploadedAt,
-			&i.JWT,
-			&i.Exp,
-			&i.UUID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const insertLicense = `-- name: InsertLicense :one
-INSERT INTO
-	licenses (
-	uploaded_at,
-	jwt,
-	exp,
-	uuid
-)
-VALUES
-	($1, $2, $3, $4) RETURNING id, uploaded_at, jwt, exp, uuid
-`
+const getTemplateDailyInsights = `-- name: GetTemplateDailyInsights :many
+WITH ts AS (
+	SELECT
+		d::timestamptz AS from_,
+		CASE
+			WHEN (d::timestamptz + '1 day'::interval) <= $1::timestamptz
+			THEN (d::timestamptz + '1 day'::interval)
+			ELSE $1::timestamptz
+		END AS to_
+	FROM
+		-- Subtract 1 second from end_time to avoid including the next interval in the results.
+		generate_series($2::timestamptz, ($1::timestamptz) - '1 second'::interval, '1 day'::interval) AS d
+), unflattened_usage_by_day AS (
+	-- We select data from both workspace agent stats and workspace app stats to
+	-- get a complete picture of usage. This matches how usage is calculated by
+	-- the combination of GetTemplateInsights and GetTemplateAppInsights. We use
+	-- a union all to avoid a costly distinct operation.
+	--
+	-- Note that one query must perform a left join so that all intervals are
+	-- present at least once.
+	SELECT
+		ts.from_, ts.to_,
+		was.template_id,
+		was.user_id
+	FROM ts
+	LEFT JOIN workspace_agent_stats was ON (
+		was.created_at >= ts.from_
+		AND was.created_at < ts.to_
+		AND was.connection_count > 0
+		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN was.template_id = ANY($3::uuid[]) ELSE TRUE END
+		-- Service accounts are not counted as active users.
+		AND was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
+	)
+	GROUP BY ts.from_, ts.to_, was.template_id, was.user_id
 
-type InsertLicenseParams struct {
-	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
-	JWT        string    `db:"jwt" json:"jwt"`
-	Exp        time.Time `db:"exp" json:"exp"`
-	UUID       uuid.UUID `db:"uuid" json:"uuid"`
-}
+	UNION ALL
 
-func (q *sqlQuerier) InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error) {
-	row := q.db.QueryRowContext(ctx, insertLicense,
-		arg.UploadedAt,
-		arg.JWT,
-		arg.Exp,
-		arg.UUID,
+	SELECT
+		ts.from_, ts.to_,
+		w.template_id,
+		was.user_id
+	FROM ts
+	JOIN workspace_app_stats was ON (
+		(was.session_started_at >= ts.from_ AND was.session_started_at < ts.to_)
+		OR (was.session_ended_at > ts.from_ AND was.session_ended_at < ts.to_)
+		OR (was.session_started_at < ts.from_ AND was.session_ended_at >= ts.to_)
 	)
-	var i License
-	err := row.Scan(
-		&i.ID,
-		&i.UploadedAt,
-		&i.JWT,
-		&i.Exp,
-		&i.UUID,
+	JOIN workspaces w ON (
+		w.id = was.workspace_id
+		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN w.template_id = ANY($3::uuid[]) ELSE TRUE END
 	)
-	return i, err
-}
-
-const acquireLock = `-- name: AcquireLock :exec
-SELECT pg_advisory_xact_lock($1)
-`
-
-// Blocks until the lock is acquired.
-//
-// This must be called from within a transaction. The lock will be automatically
-// released when the transaction ends.
-func (q *sqlQuerier) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
-	_, err := q.db.ExecContext(ctx, acquireLock, pgAdvisoryXactLock)
-	return err
-}
+	-- Service accounts are not counted as active users.
+	WHERE was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
+	GROUP BY ts.from_, ts.to_, w.template_id, was.user_id
+)
 
-const tryAcquireLock = `-- name: TryAcquireLock :one
-SELECT pg_try_advisory_xact_lock($1)
+SELECT
+	from_ AS start_time,
+	to_ AS end_time,
+	array_remove(array_agg(DISTINCT template_id), NULL)::uuid[] AS template_ids,
+	COUNT(DISTINCT user_id) AS active_users
+FROM unflattened_usage_by_day
+GROUP BY from_, to_
 `
 
-// Non blocking lock. Returns true if the lock was acquired, false otherwise.
-//
-// This must be called from within a transaction. The lock will be automatically
-// released when the transaction ends.
-func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
-	row := q.db.QueryRowContext(ctx, tryAcquireLock, pgTryAdvisoryXactLock)
-	var pg_try_advisory_xact_lock bool
-	err := row.Scan(&pg_try_advisory_xact_lock)
-	return pg_try_advisory_xact_lock, err
+type GetTemplateDailyInsightsParams struct {
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
 }
 
-const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
-SELECT
-    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
-FROM
-    organization_members
-WHERE
-    user_id = ANY($1 :: uuid [ ])
-GROUP BY
-    user_id
-`
-
-type GetOrganizationIDsByMemberIDsRow struct {
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+type GetTemplateDailyInsightsRow struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	ActiveUsers int64       `db:"active_users" json:"active_users"`
 }
 
-func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+// GetTemplateDailyInsights returns all daily intervals between start and end
+// time, if end time is a partial day, it will be included in the results and
+// that interval will be less than 24 hours. If there is no data for a selected
+// interval/template, it will be included in the results with 0 active users.
+func (q *sqlQuerier) GetTemplateDailyInsights(ctx context.Context, arg GetTemplateDailyInsightsParams) ([]GetTemplateDailyInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateDailyInsights, arg.EndTime, arg.StartTime, pq.Array(arg.TemplateIDs))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetOrganizationIDsByMemberIDsRow
+	var items []GetTemplateDailyInsightsRow
 	for rows.Next() {
-		var i GetOrganizationIDsByMemberIDsRow
-		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
+		var i GetTemplateDailyInsightsRow
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			pq.Array(&i.TemplateIDs),
+			&i.ActiveUsers,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -2251,60 +2348,166 @@ func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uu
 	return items, nil
 }
 
-const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
+const getTemplateInsights = `-- name: GetTemplateInsights :one
+WITH agent_stats_by_interval_and_user AS (
+	SELECT
+		date_trunc('minute', was.created_at),
+		was.user_id,
+		array_agg(was.template_id) AS template_ids,
+		CASE WHEN SUM(was.session_count_vscode) > 0 THEN 60 ELSE 0 END AS usage_vscode_seconds,
+		CASE WHEN SUM(was.session_count_jetbrains) > 0 THEN 60 ELSE 0 END AS usage_jetbrains_seconds,
+		CASE WHEN SUM(was.session_count_reconnecting_pty) > 0 THEN 60 ELSE 0 END AS usage_reconnecting_pty_seconds,
+		CASE WHEN SUM(was.session_count_ssh) > 0 THEN 60 ELSE 0 END AS usage_ssh_seconds
+	FROM workspace_agent_stats was
+	WHERE
+		was.created_at >= $1::timestamptz
+		AND was.created_at < $2::timestamptz
+		AND was.connection_count > 0
+		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN was.template_id = ANY($3::uuid[]) ELSE TRUE END
+		-- Service accounts are not counted as active users.
+		AND was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
+	GROUP BY date_trunc('minute', was.created_at), was.user_id
+), template_ids AS (
+	SELECT array_agg(DISTINCT template_id) AS ids
+	FROM agent_stats_by_interval_and_user, unnest(template_ids) template_id
+	WHERE template_id IS NOT NULL
+)
+
 SELECT
-	user_id, organization_id, created_at, updated_at, roles
-FROM
-	organization_members
-WHERE
-	organization_id = $1
-	AND user_id = $2
-LIMIT
-	1
+	COALESCE((SELECT ids FROM template_ids), '{}')::uuid[] AS template_ids,
+	-- Return IDs so we can combine this with GetTemplateAppInsights.
+	COALESCE(array_agg(DISTINCT user_id), '{}')::uuid[] AS active_user_ids,
+	COALESCE(SUM(usage_vscode_seconds), 0)::bigint AS usage_vscode_seconds,
+	COALESCE(SUM(usage_jetbrains_seconds), 0)::bigint AS usage_jetbrains_seconds,
+	COALESCE(SUM(usage_reconnecting_pty_seconds), 0)::bigint AS usage_reconnecting_pty_seconds,
+	COALESCE(SUM(usage_ssh_seconds), 0)::bigint AS usage_ssh_seconds
+FROM agent_stats_by_interval_and_user
 `
 
-type GetOrganizationMemberByUserIDParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+type GetTemplateInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
 }
 
-func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
-	var i OrganizationMember
+type GetTemplateInsightsRow struct {
+	TemplateIDs                 []uuid.UUID `db:"template_ids" json:"template_ids"`
+	ActiveUserIDs               []uuid.UUID `db:"active_user_ids" json:"active_user_ids"`
+	UsageVscodeSeconds          int64       `db:"usage_vscode_seconds" json:"usage_vscode_seconds"`
+	UsageJetbrainsSeconds       int64       `db:"usage_jetbrains_seconds" json:"usage_jetbrains_seconds"`
+	UsageReconnectingPtySeconds int64       `db:"usage_reconnecting_pty_seconds" json:"usage_reconnecting_pty_seconds"`
+	UsageSshSeconds             int64       `db:"usage_ssh_seconds" json:"usage_ssh_seconds"`
+}
+
+// GetTemplateInsights has a granularity of 5 minutes where if a session/app was
+// in use during a minute, we will add 5 minutes to the total usage for that
+// session/app (per user).
+func (q *sqlQuerier) GetTemplateInsights(ctx context.Context, arg GetTemplateInsightsParams) (GetTemplateInsightsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	var i GetTemplateInsightsRow
 	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		pq.Array(&i.Roles),
+		pq.Array(&i.TemplateIDs),
+		pq.Array(&i.ActiveUserIDs),
+		&i.UsageVscodeSeconds,
+		&i.UsageJetbrainsSeconds,
+		&i.UsageReconnectingPtySeconds,
+		&i.UsageSshSeconds,
 	)
 	return i, err
 }
 
-const getOrganizationMembershipsByUserID = `-- name: GetOrganizationMembershipsByUserID :many
-SELECT
-	user_id, organization_id, created_at, updated_at, roles
-FROM
-	organization_members
-WHERE
-  user_id = $1
+const getTemplateParameterInsights = `-- name: GetTemplateParameterInsights :many
+WITH latest_workspace_builds AS (
+	SELECT
+		wb.id,
+		wbmax.template_id,
+		wb.template_version_id
+	FROM (
+		SELECT
+			tv.template_id, wbmax.workspace_id, MAX(wbmax.build_number) as max_build_number
+		FROM workspace_builds wbmax
+		JOIN template_versions tv ON (tv.id = wbmax.template_version_id)
+		WHERE
+			wbmax.created_at >= $1::timestamptz
+			AND wbmax.created_at < $2::timestamptz
+			AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN tv.template_id = ANY($3::uuid[]) ELSE TRUE END
+		GROUP BY tv.template_id, wbmax.workspace_id
+	) wbmax
+	JOIN workspace_builds wb ON (
+		wb.workspace_id = wbmax.workspace_id
+		AND wb.build_number = wbmax.max_build_number
+	)
+), unique_template_params AS (
+	SELECT
+		ROW_NUMBER() OVER () AS num,
+		array_agg(DISTINCT wb.template_id)::uuid[] AS template_ids,
+		array_agg(wb.id)::uuid[] AS workspace_build_ids,
+		tvp.name,
+		tvp.type,
+		tvp.display_name,
+		tvp.description,
+		tvp.options
+	FROM latest_workspace_builds wb
+	JOIN template_version_parameters tvp ON (tvp.template_version_id = wb.template_version_id)
+	GROUP BY tvp.name, tvp.type, tvp.display_name, tvp.description, tvp.options
+)
+
+SELECT
+	utp.num,
+	utp.template_ids,
+	utp.name,
+	utp.type,
+	utp.display_name,
+	utp.description,
+	utp.options,
+	wbp.value,
+	COUNT(wbp.value) AS count
+FROM unique_template_params utp
+JOIN workspace_build_parameters wbp ON (utp.workspace_build_ids @> ARRAY[wbp.workspace_build_id] AND utp.name = wbp.name)
+GROUP BY utp.num, utp.template_ids, utp.name, utp.type, utp.display_name, utp.description, utp.options, wbp.value
 `
 
-func (q *sqlQuerier) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]OrganizationMember, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationMembershipsByUserID, userID)
+type GetTemplateParameterInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateParameterInsightsRow struct {
+	Num         int64           `db:"num" json:"num"`
+	TemplateIDs []uuid.UUID     `db:"template_ids" json:"template_ids"`
+	Name        string          `db:"name" json:"name"`
+	Type        string          `db:"type" json:"type"`
+	DisplayName string          `db:"display_name" json:"display_name"`
+	Description string          `db:"description" json:"description"`
+	Options     json.RawMessage `db:"options" json:"options"`
+	Value       string          `db:"value" json:"value"`
+	Count       int64           `db:"count" json:"count"`
+}
+
+// GetTemplateParameterInsights does for each template in a given timeframe,
+// look for the latest workspace build (for every workspace) that has been
+// created in the timeframe and return the aggregate usage counts of parameter
+// values.
+func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateParameterInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []OrganizationMember
+	var items []GetTemplateParameterInsightsRow
 	for rows.Next() {
-		var i OrganizationMember
+		var i GetTemplateParameterInsightsRow
 		if err := rows.Scan(
-			&i.UserID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			pq.Array(&i.Roles),
+			&i.Num,
+			pq.Array(&i.TemplateIDs),
+			&i.Name,
+			&i.Type,
+			&i.DisplayName,
+			&i.Description,
+			&i.Options,
+			&i.Value,
+			&i.Count,
 		); err != nil {
 			return nil, err
 		}
@@ -2319,145 +2522,133 @@ func (q *sqlQuerier) GetOrganizationMembershipsByUserID(ctx context.Context, use
 	return items, nil
 }
 
-const insertOrganizationMember = `-- name: InsertOrganizationMember :one
-INSERT INTO
-	organization_members (
-		organization_id,
-		user_id,
-		created_at,
-		updated_at,
-		roles
-	)
-VALUES
-	($1, $2, $3, $4, $5) RETURNING user_id, organization_id, created_at, updated_at, roles
+const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
+SELECT
+	workspace_agent_stats.user_id,
+	users.username,
+	users.avatar_url,
+	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
+FROM workspace_agent_stats
+JOIN users ON (users.id = workspace_agent_stats.user_id)
+WHERE
+	workspace_agent_stats.created_at >= $1
+	AND workspace_agent_stats.created_at < $2
+	AND workspace_agent_stats.connection_median_latency_ms > 0
+	AND workspace_agent_stats.connection_count > 0
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN template_id = ANY($3::uuid[]) ELSE TRUE END
+GROUP BY workspace_agent_stats.user_id, users.username, users.avatar_url
+ORDER BY user_id ASC
 `
 
-type InsertOrganizationMemberParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
-	CreatedAt      time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
-	Roles          []string  `db:"roles" json:"roles"`
+type GetUserLatencyInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
 }
 
-func (q *sqlQuerier) InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, insertOrganizationMember,
-		arg.OrganizationID,
-		arg.UserID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		pq.Array(arg.Roles),
-	)
-	var i OrganizationMember
-	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		pq.Array(&i.Roles),
-	)
-	return i, err
+type GetUserLatencyInsightsRow struct {
+	UserID                       uuid.UUID      `db:"user_id" json:"user_id"`
+	Username                     string         `db:"username" json:"username"`
+	AvatarURL                    sql.NullString `db:"avatar_url" json:"avatar_url"`
+	TemplateIDs                  []uuid.UUID    `db:"template_ids" json:"template_ids"`
+	WorkspaceConnectionLatency50 float64        `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64        `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
 }
 
-const updateMemberRoles = `-- name: UpdateMemberRoles :one
-UPDATE
-	organization_members
-SET
-	-- Remove all duplicates from the roles.
-	roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
-WHERE
-	user_id = $2
-	AND organization_id = $3
-RETURNING user_id, organization_id, created_at, updated_at, roles
-`
-
-type UpdateMemberRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	OrgID        uuid.UUID `db:"org_id" json:"org_id"`
+// GetUserLatencyInsights returns the median and 95th percentile connection
+// latency that users have experienced. The result can be filtered on
+// template_ids, meaning only user data from workspaces based on those templates
+// will be included.
+func (q *sqlQuerier) GetUserLatencyInsights(ctx context.Context, arg GetUserLatencyInsightsParams) ([]GetUserLatencyInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLatencyInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserLatencyInsightsRow
+	for rows.Next() {
+		var i GetUserLatencyInsightsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.AvatarURL,
+			pq.Array(&i.TemplateIDs),
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency95,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, updateMemberRoles, pq.Array(arg.GrantedRoles), arg.UserID, arg.OrgID)
-	var i OrganizationMember
-	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		pq.Array(&i.Roles),
-	)
-	return i, err
+const deleteLicense = `-- name: DeleteLicense :one
+DELETE
+FROM licenses
+WHERE id = $1
+RETURNING id
+`
+
+func (q *sqlQuerier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, deleteLicense, id)
+	err := row.Scan(&id)
+	return id, err
 }
 
-const getOrganizationByID = `-- name: GetOrganizationByID :one
+const getLicenseByID = `-- name: GetLicenseByID :one
 SELECT
-	id, name, description, created_at, updated_at
+	id, uploaded_at, jwt, exp, uuid
 FROM
-	organizations
+	licenses
 WHERE
 	id = $1
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationByID, id)
-	var i Organization
+func (q *sqlQuerier) GetLicenseByID(ctx context.Context, id int32) (License, error) {
+	row := q.db.QueryRowContext(ctx, getLicenseByID, id)
+	var i License
 	err := row.Scan(
 		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.UploadedAt,
+		&i.JWT,
+		&i.Exp,
+		&i.UUID,
 	)
 	return i, err
 }
 
-const getOrganizationByName = `-- name: GetOrganizationByName :one
-SELECT
-	id, name, description, created_at, updated_at
-FROM
-	organizations
-WHERE
-	LOWER("name") = LOWER($1)
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, name string) (Organization, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationByName, name)
-	var i Organization
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
-}
-
-const getOrganizations = `-- name: GetOrganizations :many
-SELECT
-	id, name, description, created_at, updated_at
-FROM
-	organizations
+const getLicenses = `-- name: GetLicenses :many
+SELECT id, uploaded_at, jwt, exp, uuid
+FROM licenses
+ORDER BY (id)
 `
 
-func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizations)
+func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
+	rows, err := q.db.QueryContext(ctx, getLicenses)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Organization
+	var items []License
 	for rows.Next() {
-		var i Organization
+		var i License
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.UploadedAt,
+			&i.JWT,
+			&i.Exp,
+			&i.UUID,
 		); err != nil {
 			return nil, err
 		}
@@ -2472,37 +2663,28 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, erro
 	return items, nil
 }
 
-const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
-SELECT
-	id, name, description, created_at, updated_at
-FROM
-	organizations
-WHERE
-	id = (
-		SELECT
-			organization_id
-		FROM
-			organization_members
-		WHERE
-			user_id = $1
-	)
+const getUnexpiredLicenses = `-- name: GetUnexpiredLicenses :many
+SELECT id, uploaded_at, jwt, exp, uuid
+FROM licenses
+WHERE exp > NOW()
+ORDER BY (id)
 `
 
-func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationsByUserID, userID)
+func (q *sqlQuerier) GetUnexpiredLicenses(ctx context.Context) ([]License, error) {
+	rows, err := q.db.QueryContext(ctx, getUnexpiredLicenses)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Organization
+	var items []License
 	for rows.Next() {
-		var i Organization
+		var i License
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.UploadedAt,
+			&i.JWT,
+			&i.Exp,
+			&i.UUID,
 		); err != nil {
 			return nil, err
 		}
@@ -2517,78 +2699,226 @@ func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.U
 	return items, nil
 }
 
-const insertOrganization = `-- name: InsertOrganization :one
+const insertLicense = `-- name: InsertLicense :one
 INSERT INTO
-	organizations (id, "name", description, created_at, updated_at)
+	licenses (
+	uploaded_at,
+	jwt,
+	exp,
+	uuid
+)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at
+	($1, $2, $3, $4) RETURNING id, uploaded_at, jwt, exp, uuid
 `
 
-type InsertOrganizationParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+type InsertLicenseParams struct {
+	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
+	JWT        string    `db:"jwt" json:"jwt"`
+	Exp        time.Time `db:"exp" json:"exp"`
+	UUID       uuid.UUID `db:"uuid" json:"uuid"`
 }
 
-func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error) {
-	row := q.db.QueryRowContext(ctx, insertOrganization,
-		arg.ID,
-		arg.Name,
-		arg.Description,
-		arg.CreatedAt,
-		arg.UpdatedAt,
+func (q *sqlQuerier) InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error) {
+	row := q.db.QueryRowContext(ctx, insertLicense,
+		arg.UploadedAt,
+		arg.JWT,
+		arg.Exp,
+		arg.UUID,
 	)
-	var i Organization
+	var i License
 	err := row.Scan(
 		&i.ID,
-		&i.Name,
-		&i.Description,
+		&i.UploadedAt,
+		&i.JWT,
+		&i.Exp,
+		&i.UUID,
+	)
+	return i, err
+}
+
+const acquireLock = `-- name: AcquireLock :exec
+SELECT pg_advisory_xact_lock($1)
+`
+
+// Blocks until the lock is acquired.
+//
+// This must be called from within a transaction. The lock will be automatically
+// released when the transaction ends.
+func (q *sqlQuerier) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
+	_, err := q.db.ExecContext(ctx, acquireLock, pgAdvisoryXactLock)
+	return err
+}
+
+const tryAcquireLock = `-- name: TryAcquireLock :one
+SELECT pg_try_advisory_xact_lock($1)
+`
+
+// Non blocking lock. Returns true if the lock was acquired, false otherwise.
+//
+// This must be called from within a transaction. The lock will be automatically
+// released when the transaction ends.
+func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, tryAcquireLock, pgTryAdvisoryXactLock)
+	var pg_try_advisory_xact_lock bool
+	err := row.Scan(&pg_try_advisory_xact_lock)
+	return pg_try_advisory_xact_lock, err
+}
+
+const acquireNotificationMessageEmail = `-- name: AcquireNotificationMessageEmail :one
+UPDATE
+	notification_messages
+SET
+	email_attempts = email_attempts + 1,
+	email_next_attempt_at = $1 :: timestamptz
+WHERE
+	id = (
+		SELECT
+			nested.id
+		FROM
+			notification_messages AS nested
+		WHERE
+			nested.email_next_attempt_at <= $2 :: timestamptz
+		ORDER BY
+			nested.email_next_attempt_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			1
+	)
+RETURNING id, user_id, kind, title, content, dedupe_key, created_at, read_at, email_attempts, email_next_attempt_at, emailed_at, email_error
+`
+
+type AcquireNotificationMessageEmailParams struct {
+	LeaseExpiresAt time.Time `db:"lease_expires_at" json:"lease_expires_at"`
+	Now            time.Time `db:"now" json:"now"`
+}
+
+// AcquireNotificationMessageEmail claims the notification whose email has
+// been due the longest. The notification is leased until @lease_expires_at,
+// when another replica may retry the email if the attempt didn't finish.
+func (q *sqlQuerier) AcquireNotificationMessageEmail(ctx context.Context, arg AcquireNotificationMessageEmailParams) (NotificationMessage, error) {
+	row := q.db.QueryRowContext(ctx, acquireNotificationMessageEmail, arg.LeaseExpiresAt, arg.Now)
+	var i NotificationMessage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Title,
+		&i.Content,
+		&i.DedupeKey,
 		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.ReadAt,
+		&i.EmailAttempts,
+		&i.EmailNextAttemptAt,
+		&i.EmailedAt,
+		&i.EmailError,
 	)
 	return i, err
 }
 
-const getParameterSchemasByJobID = `-- name: GetParameterSchemasByJobID :many
+const countUnreadNotificationMessagesByUserID = `-- name: CountUnreadNotificationMessagesByUserID :one
 SELECT
-	id, created_at, job_id, name, description, default_source_scheme, default_source_value, allow_override_source, default_destination_scheme, allow_override_destination, default_refresh, redisplay_value, validation_error, validation_condition, validation_type_system, validation_value_type, index
+	COUNT(*)
 FROM
-	parameter_schemas
+	notification_messages
 WHERE
-	job_id = $1
+	user_id = $1
+	AND read_at IS NULL
+`
+
+func (q *sqlQuerier) CountUnreadNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUnreadNotificationMessagesByUserID, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteOldNotificationMessages = `-- name: DeleteOldNotificationMessages :exec
+DELETE FROM
+	notification_messages
+WHERE
+	created_at < NOW() - INTERVAL '90 days'
+`
+
+// Notifications are kept for 90 days.
+func (q *sqlQuerier) DeleteOldNotificationMessages(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNotificationMessages)
+	return err
+}
+
+const getNotificationMessageByID = `-- name: GetNotificationMessageByID :one
+SELECT id, user_id, kind, title, content, dedupe_key, created_at, read_at, email_attempts, email_next_attempt_at, emailed_at, email_error FROM notification_messages WHERE id = $1
+`
+
+func (q *sqlQuerier) GetNotificationMessageByID(ctx context.Context, id uuid.UUID) (NotificationMessage, error) {
+	row := q.db.QueryRowContext(ctx, getNotificationMessageByID, id)
+	var i NotificationMessage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Title,
+		&i.Content,
+		&i.DedupeKey,
+		&i.CreatedAt,
+		&i.ReadAt,
+		&i.EmailAttempts,
+		&i.EmailNextAttemptAt,
+		&i.EmailedAt,
+		&i.EmailError,
+	)
+	return i, err
+}
+
+const getNotificationMessagesByUserID = `-- name: GetNotificationMessagesByUserID :many
+SELECT
+	id, user_id, kind, title, content, dedupe_key, created_at, read_at, email_attempts, email_next_attempt_at, emailed_at, email_error
+FROM
+	notification_messages
+WHERE
+	user_id = $1
+	AND CASE
+		WHEN $2 :: boolean THEN read_at IS NULL
+		ELSE true
+	END
 ORDER BY
-	index
+	created_at DESC
+OFFSET
+	$3
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($4 :: int, 0)
 `
 
-func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error) {
-	rows, err := q.db.QueryContext(ctx, getParameterSchemasByJobID, jobID)
+type GetNotificationMessagesByUserIDParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	UnreadOnly bool      `db:"unread_only" json:"unread_only"`
+	OffsetOpt  int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetNotificationMessagesByUserID(ctx context.Context, arg GetNotificationMessagesByUserIDParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationMessagesByUserID, arg.UserID, arg.UnreadOnly, arg.OffsetOpt, arg.LimitOpt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ParameterSchema
+	var items []NotificationMessage
 	for rows.Next() {
-		var i ParameterSchema
+		var i NotificationMessage
 		if err := rows.Scan(
 			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Title,
+			&i.Content,
+			&i.DedupeKey,
 			&i.CreatedAt,
-			&i.JobID,
-			&i.Name,
-			&i.Description,
-			&i.DefaultSourceScheme,
-			&i.DefaultSourceValue,
-			&i.AllowOverrideSource,
-			&i.DefaultDestinationScheme,
-			&i.AllowOverrideDestination,
-			&i.DefaultRefresh,
-			&i.RedisplayValue,
-			&i.ValidationError,
-			&i.ValidationCondition,
-			&i.ValidationTypeSystem,
-			&i.ValidationValueType,
-			&i.Index,
+			&i.ReadAt,
+			&i.EmailAttempts,
+			&i.EmailNextAttemptAt,
+			&i.EmailedAt,
+			&i.EmailError,
 		); err != nil {
 			return nil, err
 		}
@@ -2603,30 +2933,24 @@ func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.
 	return items, nil
 }
 
-const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
-SELECT
-	id, created_at, updated_at, name, provisioners, replica_id, tags
-FROM
-	provisioner_daemons
+const getNotificationPreferencesByUserID = `-- name: GetNotificationPreferencesByUserID :many
+SELECT user_id, kind, disabled, updated_at FROM notification_preferences WHERE user_id = $1 ORDER BY kind
 `
 
-func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerDaemons)
+func (q *sqlQuerier) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationPreferencesByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerDaemon
+	var items []NotificationPreference
 	for rows.Next() {
-		var i ProvisionerDaemon
+		var i NotificationPreference
 		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
+			&i.UserID,
+			&i.Kind,
+			&i.Disabled,
 			&i.UpdatedAt,
-			&i.Name,
-			pq.Array(&i.Provisioners),
-			&i.ReplicaID,
-			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -2641,82 +2965,391 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 	return items, nil
 }
 
-const insertProvisionerDaemon = `-- name: InsertProvisionerDaemon :one
+const insertNotificationMessage = `-- name: InsertNotificationMessage :one
 INSERT INTO
-	provisioner_daemons (
+	notification_messages (
 		id,
+		user_id,
+		kind,
+		title,
+		content,
+		dedupe_key,
 		created_at,
-		"name",
-		provisioners,
-		tags
+		email_next_attempt_at
+	)
+SELECT
+	$1 :: uuid,
+	$2 :: uuid,
+	$3 :: notification_kind,
+	$4 :: text,
+	$5 :: text,
+	$6 :: text,
+	$7 :: timestamptz,
+	CASE WHEN $8 :: boolean THEN $7 :: timestamptz ELSE NULL END
+WHERE
+	NOT EXISTS (
+		SELECT
+			1
+		FROM
+			notification_preferences
+		WHERE
+			notification_preferences.user_id = $2 :: uuid
+			AND notification_preferences.kind = $3 :: notification_kind
+			AND notification_preferences.disabled
+	)
+ON CONFLICT (user_id, dedupe_key) WHERE dedupe_key != '' DO NOTHING
+RETURNING id, user_id, kind, title, content, dedupe_key, created_at, read_at, email_attempts, email_next_attempt_at, emailed_at, email_error
+`
+
+type InsertNotificationMessageParams struct {
+	ID        uuid.UUID        `db:"id" json:"id"`
+	UserID    uuid.UUID        `db:"user_id" json:"user_id"`
+	Kind      NotificationKind `db:"kind" json:"kind"`
+	Title     string           `db:"title" json:"title"`
+	Content   string           `db:"content" json:"content"`
+	DedupeKey string           `db:"dedupe_key" json:"dedupe_key"`
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+	Email     bool             `db:"email" json:"email"`
+}
+
+// InsertNotificationMessage creates a notification unless the user opted out
+// of its kind, or already received a notification with the same dedupe key.
+// No rows are returned if the notification wasn't created.
+func (q *sqlQuerier) InsertNotificationMessage(ctx context.Context, arg InsertNotificationMessageParams) (NotificationMessage, error) {
+	row := q.db.QueryRowContext(ctx, insertNotificationMessage, arg.ID, arg.UserID, arg.Kind, arg.Title, arg.Content, arg.DedupeKey, arg.CreatedAt, arg.Email)
+	var i NotificationMessage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Title,
+		&i.Content,
+		&i.DedupeKey,
+		&i.CreatedAt,
+		&i.ReadAt,
+		&i.EmailAttempts,
+		&i.EmailNextAttemptAt,
+		&i.EmailedAt,
+		&i.EmailError,
+	)
+	return i, err
+}
+
+const updateNotificationMessageEmailByID = `-- name: UpdateNotificationMessageEmailByID :exec
+UPDATE
+	notification_messages
+SET
+	emailed_at = $2,
+	email_error = $3,
+	email_next_attempt_at = $4
+WHERE
+	id = $1
+`
+
+type UpdateNotificationMessageEmailByIDParams struct {
+	ID                 uuid.UUID    `db:"id" json:"id"`
+	EmailedAt          sql.NullTime `db:"emailed_at" json:"emailed_at"`
+	EmailError         string       `db:"email_error" json:"email_error"`
+	EmailNextAttemptAt sql.NullTime `db:"email_next_attempt_at" json:"email_next_attempt_at"`
+}
+
+func (q *sqlQuerier) UpdateNotificationMessageEmailByID(ctx context.Context, arg UpdateNotificationMessageEmailByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateNotificationMessageEmailByID, arg.ID, arg.EmailedAt, arg.EmailError, arg.EmailNextAttemptAt)
+	return err
+}
+
+const updateNotificationMessageReadAtByID = `-- name: UpdateNotificationMessageReadAtByID :one
+UPDATE
+	notification_messages
+SET
+	read_at = COALESCE(read_at, $1 :: timestamptz)
+WHERE
+	id = $2
+RETURNING id, user_id, kind, title, content, dedupe_key, created_at, read_at, email_attempts, email_next_attempt_at, emailed_at, email_error
+`
+
+type UpdateNotificationMessageReadAtByIDParams struct {
+	ReadAt time.Time `db:"read_at" json:"read_at"`
+	ID     uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateNotificationMessageReadAtByID(ctx context.Context, arg UpdateNotificationMessageReadAtByIDParams) (NotificationMessage, error) {
+	row := q.db.QueryRowContext(ctx, updateNotificationMessageReadAtByID, arg.ReadAt, arg.ID)
+	var i NotificationMessage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Title,
+		&i.Content,
+		&i.DedupeKey,
+		&i.CreatedAt,
+		&i.ReadAt,
+		&i.EmailAttempts,
+		&i.EmailNextAttemptAt,
+		&i.EmailedAt,
+		&i.EmailError,
+	)
+	return i, err
+}
+
+const updateNotificationMessagesReadAtByUserID = `-- name: UpdateNotificationMessagesReadAtByUserID :exec
+UPDATE
+	notification_messages
+SET
+	read_at = $1 :: timestamptz
+WHERE
+	user_id = $2
+	AND read_at IS NULL
+`
+
+type UpdateNotificationMessagesReadAtByUserIDParams struct {
+	ReadAt time.Time `db:"read_at" json:"read_at"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) UpdateNotificationMessagesReadAtByUserID(ctx context.Context, arg UpdateNotificationMessagesReadAtByUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateNotificationMessagesReadAtByUserID, arg.ReadAt, arg.UserID)
+	return err
+}
+
+const upsertNotificationPreference = `-- name: UpsertNotificationPreference :one
+INSERT INTO
+	notification_preferences (
+		user_id,
+		kind,
+		disabled,
+		updated_at
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags
+	($1, $2, $3, $4)
+ON CONFLICT (user_id, kind) DO UPDATE SET
+	disabled = $3,
+	updated_at = $4
+RETURNING user_id, kind, disabled, updated_at
 `
 
-type InsertProvisionerDaemonParams struct {
-	ID           uuid.UUID         `db:"id" json:"id"`
-	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
-	Name         string            `db:"name" json:"name"`
-	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
-	Tags         StringMap         `db:"tags" json:"tags"`
+type UpsertNotificationPreferenceParams struct {
+	UserID    uuid.UUID        `db:"user_id" json:"user_id"`
+	Kind      NotificationKind `db:"kind" json:"kind"`
+	Disabled  bool             `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time        `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProvisionerDaemonParams) (ProvisionerDaemon, error) {
-	row := q.db.QueryRowContext(ctx, insertProvisionerDaemon,
-		arg.ID,
-		arg.CreatedAt,
-		arg.Name,
-		pq.Array(arg.Provisioners),
-		arg.Tags,
+func (q *sqlQuerier) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error) {
+	row := q.db.QueryRowContext(ctx, upsertNotificationPreference, arg.UserID, arg.Kind, arg.Disabled, arg.UpdatedAt)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Kind,
+		&i.Disabled,
+		&i.UpdatedAt,
 	)
-	var i ProvisionerDaemon
+	return i, err
+}
+
+const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
+WITH deleted_api_keys AS (
+	DELETE FROM
+		api_keys
+	WHERE
+		id IN (
+			SELECT
+				api_key_id
+			FROM
+				oauth2_provider_app_tokens
+			WHERE
+				app_id = $1
+		)
+)
+DELETE FROM oauth2_provider_apps WHERE id = $1
+`
+
+// DeleteOAuth2ProviderAppByID deletes the app and revokes the API keys of its
+// tokens.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppCodeByID = `-- name: DeleteOAuth2ProviderAppCodeByID :exec
+DELETE FROM oauth2_provider_app_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppCodeByID, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOAuth2ProviderAppCodesByAppAndUserID = `-- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
+DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2
+`
+
+type DeleteOAuth2ProviderAppCodesByAppAndUserIDParams struct {
+	AppID  uuid.UUID `db:"app_id" json:"app_id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppCodesByAppAndUserID, arg.AppID, arg.UserID)
+	return err
+}
+
+const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppTokensByAppAndUserID = `-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $2
+	AND id IN (
+		SELECT
+			api_key_id
+		FROM
+			oauth2_provider_app_tokens
+		WHERE
+			app_id = $1
+	)
+`
+
+type DeleteOAuth2ProviderAppTokensByAppAndUserIDParams struct {
+	AppID  uuid.UUID `db:"app_id" json:"app_id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// DeleteOAuth2ProviderAppTokensByAppAndUserID revokes the tokens the app holds
+// for the user. Deleting the API keys deletes their refresh tokens.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppTokensByAppAndUserID, arg.AppID, arg.UserID)
+	return err
+}
+
+const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
+SELECT id, created_at, updated_at, name, icon, redirect_uris FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
+	var i OAuth2ProviderApp
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
-		pq.Array(&i.Provisioners),
-		&i.ReplicaID,
-		&i.Tags,
+		&i.Icon,
+		pq.Array(&i.RedirectURIs),
 	)
 	return i, err
 }
 
-const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
-SELECT
-	job_id, created_at, source, level, stage, output, id
-FROM
-	provisioner_job_logs
-WHERE
-	job_id = $1
-	AND (
-		id > $2
-	) ORDER BY id ASC
+const getOAuth2ProviderAppCodeByHashedSecret = `-- name: GetOAuth2ProviderAppCodeByHashedSecret :one
+SELECT id, app_id, created_at, last_used_at, hashed_secret, display_secret FROM oauth2_provider_app_codes WHERE hashed_secret = $1
 `
 
-type GetProvisionerLogsAfterIDParams struct {
-	JobID        uuid.UUID `db:"job_id" json:"job_id"`
-	CreatedAfter int64     `db:"created_after" json:"created_after"`
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByHashedSecret, hashedSecret)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedSecret,
+		&i.RedirectURI,
+		&i.Scope,
+		&i.CodeChallenge,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerLogsAfterID, arg.JobID, arg.CreatedAfter)
+const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT id, app_id, user_id, created_at, expires_at, hashed_secret, redirect_uri, scope, code_challenge FROM oauth2_provider_app_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedSecret,
+		&i.RedirectURI,
+		&i.Scope,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByHashedSecret = `-- name: GetOAuth2ProviderAppSecretByHashedSecret :one
+SELECT id, app_id, created_at, last_used_at, hashed_secret, display_secret FROM oauth2_provider_app_secrets WHERE hashed_secret = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByHashedSecret(ctx context.Context, hashedSecret []byte) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByHashedSecret, hashedSecret)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT id, app_id, created_at, last_used_at, hashed_secret, display_secret FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretsByAppID = `-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT id, app_id, created_at, last_used_at, hashed_secret, display_secret FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY created_at ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppSecretsByAppID, appID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerJobLog
+	var items []OAuth2ProviderAppSecret
 	for rows.Next() {
-		var i ProvisionerJobLog
+		var i OAuth2ProviderAppSecret
 		if err := rows.Scan(
-			&i.JobID,
-			&i.CreatedAt,
-			&i.Source,
-			&i.Level,
-			&i.Stage,
-			&i.Output,
 			&i.ID,
+			&i.AppID,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.HashedSecret,
+			&i.DisplaySecret,
 		); err != nil {
 			return nil, err
 		}
@@ -2731,51 +3364,62 @@ func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvi
 	return items, nil
 }
 
-const insertProvisionerJobLogs = `-- name: InsertProvisionerJobLogs :many
-INSERT INTO
-	provisioner_job_logs
-SELECT
-	$1 :: uuid AS job_id,
-	unnest($2 :: timestamptz [ ]) AS created_at,
-	unnest($3 :: log_source [ ]) AS source,
-	unnest($4 :: log_level [ ]) AS LEVEL,
-	unnest($5 :: VARCHAR(128) [ ]) AS stage,
-	unnest($6 :: VARCHAR(1024) [ ]) AS output RETURNING job_id, created_at, source, level, stage, output, id
+const getOAuth2ProviderAppTokenByAPIKeyID = `-- name: GetOAuth2ProviderAppTokenByAPIKeyID :one
+SELECT id, app_id, api_key_id, created_at, expires_at, refresh_hash FROM oauth2_provider_app_tokens WHERE api_key_id = $1
 `
 
-type InsertProvisionerJobLogsParams struct {
-	JobID     uuid.UUID   `db:"job_id" json:"job_id"`
-	CreatedAt []time.Time `db:"created_at" json:"created_at"`
-	Source    []LogSource `db:"source" json:"source"`
-	Level     []LogLevel  `db:"level" json:"level"`
-	Stage     []string    `db:"stage" json:"stage"`
-	Output    []string    `db:"output" json:"output"`
+func (q *sqlQuerier) GetOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppTokenByAPIKeyID, apiKeyID)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.APIKeyID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RefreshHash,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error) {
-	rows, err := q.db.QueryContext(ctx, insertProvisionerJobLogs,
-		arg.JobID,
-		pq.Array(arg.CreatedAt),
-		pq.Array(arg.Source),
-		pq.Array(arg.Level),
-		pq.Array(arg.Stage),
-		pq.Array(arg.Output),
+const getOAuth2ProviderAppTokenByRefreshHash = `-- name: GetOAuth2ProviderAppTokenByRefreshHash :one
+SELECT id, app_id, api_key_id, created_at, expires_at, refresh_hash FROM oauth2_provider_app_tokens WHERE refresh_hash = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppTokenByRefreshHash(ctx context.Context, refreshHash []byte) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppTokenByRefreshHash, refreshHash)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.APIKeyID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RefreshHash,
 	)
+	return i, err
+}
+
+const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
+SELECT id, created_at, updated_at, name, icon, redirect_uris FROM oauth2_provider_apps ORDER BY lower(name) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderApps)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerJobLog
+	var items []OAuth2ProviderApp
 	for rows.Next() {
-		var i ProvisionerJobLog
+		var i OAuth2ProviderApp
 		if err := rows.Scan(
-			&i.JobID,
-			&i.CreatedAt,
-			&i.Source,
-			&i.Level,
-			&i.Stage,
-			&i.Output,
 			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Icon,
+			pq.Array(&i.RedirectURIs),
 		); err != nil {
 			return nil, err
 		}
@@ -2790,117 +3434,44 @@ func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertPro
 	return items, nil
 }
 
-const acquireProvisionerJob = `-- name: AcquireProvisionerJob :one
-UPDATE
-	provisioner_jobs
-SET
-	started_at = $1,
-	updated_at = $1,
-	worker_id = $2
+const getOAuth2ProviderAppsByUserID = `-- name: GetOAuth2ProviderAppsByUserID :many
+SELECT
+	oauth2_provider_apps.id, oauth2_provider_apps.created_at, oauth2_provider_apps.updated_at, oauth2_provider_apps.name, oauth2_provider_apps.icon, oauth2_provider_apps.redirect_uris
+FROM
+	oauth2_provider_apps
 WHERE
-	id = (
+	id IN (
 		SELECT
-			id
+			oauth2_provider_app_tokens.app_id
 		FROM
-			provisioner_jobs AS nested
+			oauth2_provider_app_tokens
+		INNER JOIN
+			api_keys ON api_keys.id = oauth2_provider_app_tokens.api_key_id
 		WHERE
-			nested.started_at IS NULL
-			-- Ensure the caller has the correct provisioner.
-			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
-			-- Ensure the caller satisfies all job tags.
-			AND nested.tags <@ $4 :: jsonb
-		ORDER BY
-			nested.created_at
-		FOR UPDATE
-		SKIP LOCKED
-		LIMIT
-			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
-`
-
-type AcquireProvisionerJobParams struct {
-	StartedAt sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID  uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	Types     []ProvisionerType `db:"types" json:"types"`
-	Tags      json.RawMessage   `db:"tags" json:"tags"`
-}
-
-// Acquires the lock for a single job that isn't started, completed,
-// canceled, and that matches an array of provisioner types.
-//
-// SKIP LOCKED is used to jump over locked rows. This prevents
-// multiple provisioners from acquiring the same jobs. See:
-// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
-func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error) {
-	row := q.db.QueryRowContext(ctx, acquireProvisionerJob,
-		arg.StartedAt,
-		arg.WorkerID,
-		pq.Array(arg.Types),
-		arg.Tags,
-	)
-	var i ProvisionerJob
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.StartedAt,
-		&i.CanceledAt,
-		&i.CompletedAt,
-		&i.Error,
-		&i.OrganizationID,
-		&i.InitiatorID,
-		&i.Provisioner,
-		&i.StorageMethod,
-		&i.Type,
-		&i.Input,
-		&i.WorkerID,
-		&i.FileID,
-		&i.Tags,
-		&i.ErrorCode,
-		&i.TraceMetadata,
+			api_keys.user_id = $1
 	)
-	return i, err
-}
-
-const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
-SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
-FROM
-	provisioner_jobs
-WHERE
-	updated_at < $1
-	AND started_at IS NOT NULL
-	AND completed_at IS NULL
+ORDER BY
+	lower(name) ASC
 `
 
-func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.Time) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getHungProvisionerJobs, updatedAt)
+// GetOAuth2ProviderAppsByUserID returns the apps the user has authorized and
+// that still hold tokens of the user.
+func (q *sqlQuerier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppsByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerJob
+	var items []OAuth2ProviderApp
 	for rows.Next() {
-		var i ProvisionerJob
+		var i OAuth2ProviderApp
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.StartedAt,
-			&i.CanceledAt,
-			&i.CompletedAt,
-			&i.Error,
-			&i.OrganizationID,
-			&i.InitiatorID,
-			&i.Provisioner,
-			&i.StorageMethod,
-			&i.Type,
-			&i.Input,
-			&i.WorkerID,
-			&i.FileID,
-			&i.Tags,
-			&i.ErrorCode,
-			&i.TraceMetadata,
+			&i.Name,
+			&i.Icon,
+			pq.Array(&i.RedirectURIs),
 		); err != nil {
 			return nil, err
 		}
@@ -2915,162 +3486,287 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 	return items, nil
 }
 
-const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
-SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
-FROM
-	provisioner_jobs
-WHERE
-	id = $1
+const insertOAuth2ProviderApp = `-- name: InsertOAuth2ProviderApp :one
+INSERT INTO
+	oauth2_provider_apps (
+		id,
+		created_at,
+		updated_at,
+		name,
+		icon,
+		redirect_uris
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at, name, icon, redirect_uris
 `
 
-func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error) {
-	row := q.db.QueryRowContext(ctx, getProvisionerJobByID, id)
-	var i ProvisionerJob
+type InsertOAuth2ProviderAppParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	Name         string    `db:"name" json:"name"`
+	Icon         string    `db:"icon" json:"icon"`
+	RedirectURIs []string  `db:"redirect_uris" json:"redirect_uris"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderApp,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		pq.Array(arg.RedirectURIs),
+	)
+	var i OAuth2ProviderApp
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.StartedAt,
-		&i.CanceledAt,
-		&i.CompletedAt,
-		&i.Error,
-		&i.OrganizationID,
-		&i.InitiatorID,
-		&i.Provisioner,
-		&i.StorageMethod,
-		&i.Type,
-		&i.Input,
-		&i.WorkerID,
-		&i.FileID,
-		&i.Tags,
-		&i.ErrorCode,
-		&i.TraceMetadata,
+		&i.Name,
+		&i.Icon,
+		pq.Array(&i.RedirectURIs),
 	)
 	return i, err
 }
 
-const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
-SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
-FROM
-	provisioner_jobs
-WHERE
-	id = ANY($1 :: uuid [ ])
+const insertOAuth2ProviderAppCode = `-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO
+	oauth2_provider_app_codes (
+		id,
+		app_id,
+		user_id,
+		created_at,
+		expires_at,
+		hashed_secret,
+		redirect_uri,
+		scope,
+		code_challenge
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, app_id, user_id, created_at, expires_at, hashed_secret, redirect_uri, scope, code_challenge
 `
 
-func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDs, pq.Array(ids))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ProvisionerJob
-	for rows.Next() {
-		var i ProvisionerJob
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.StartedAt,
-			&i.CanceledAt,
-			&i.CompletedAt,
-			&i.Error,
-			&i.OrganizationID,
-			&i.InitiatorID,
-			&i.Provisioner,
-			&i.StorageMethod,
-			&i.Type,
-			&i.Input,
-			&i.WorkerID,
-			&i.FileID,
-			&i.Tags,
-			&i.ErrorCode,
-			&i.TraceMetadata,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type InsertOAuth2ProviderAppCodeParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt     time.Time `db:"expires_at" json:"expires_at"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	RedirectURI   string    `db:"redirect_uri" json:"redirect_uri"`
+	Scope         string    `db:"scope" json:"scope"`
+	CodeChallenge string    `db:"code_challenge" json:"code_challenge"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppCode,
+		arg.ID,
+		arg.AppID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.HashedSecret,
+		arg.RedirectURI,
+		arg.Scope,
+		arg.CodeChallenge,
+	)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedSecret,
+		&i.RedirectURI,
+		&i.Scope,
+		&i.CodeChallenge,
+	)
+	return i, err
 }
 
-const getProvisionerJobsByIDsWithQueuePosition = `-- name: GetProvisionerJobsByIDsWithQueuePosition :many
-WITH unstarted_jobs AS (
-    SELECT
-        id, created_at
-    FROM
-        provisioner_jobs
-    WHERE
-        started_at IS NULL
-),
-queue_position AS (
-    SELECT
-        id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
-    FROM
-        unstarted_jobs
-),
-queue_size AS (
-	SELECT COUNT(*) as count FROM unstarted_jobs
-)
-SELECT
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata,
-    COALESCE(qp.queue_position, 0) AS queue_position,
-    COALESCE(qs.count, 0) AS queue_size
-FROM
-	provisioner_jobs pj
-LEFT JOIN
-	queue_position qp ON qp.id = pj.id
-LEFT JOIN
-	queue_size qs ON TRUE
-WHERE
-	pj.id = ANY($1 :: uuid [ ])
+const insertOAuth2ProviderAppSecret = `-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO
+	oauth2_provider_app_secrets (
+		id,
+		app_id,
+		created_at,
+		hashed_secret,
+		display_secret
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, app_id, created_at, last_used_at, hashed_secret, display_secret
 `
 
-type GetProvisionerJobsByIDsWithQueuePositionRow struct {
-	ProvisionerJob ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
-	QueuePosition  int64          `db:"queue_position" json:"queue_position"`
-	QueueSize      int64          `db:"queue_size" json:"queue_size"`
+type InsertOAuth2ProviderAppSecretParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
 }
 
-func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDsWithQueuePosition, pq.Array(ids))
+func (q *sqlQuerier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppSecret,
+		arg.ID,
+		arg.AppID,
+		arg.CreatedAt,
+		arg.HashedSecret,
+		arg.DisplaySecret,
+	)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppToken = `-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO
+	oauth2_provider_app_tokens (
+		id,
+		app_id,
+		api_key_id,
+		created_at,
+		expires_at,
+		refresh_hash
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, app_id, api_key_id, created_at, expires_at, refresh_hash
+`
+
+type InsertOAuth2ProviderAppTokenParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	AppID       uuid.UUID `db:"app_id" json:"app_id"`
+	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppToken,
+		arg.ID,
+		arg.AppID,
+		arg.APIKeyID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.RefreshHash,
+	)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.APIKeyID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RefreshHash,
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppByID = `-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE
+	oauth2_provider_apps
+SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	redirect_uris = $5
+WHERE
+	id = $1
+RETURNING id, created_at, updated_at, name, icon, redirect_uris
+`
+
+type UpdateOAuth2ProviderAppByIDParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	Name         string    `db:"name" json:"name"`
+	Icon         string    `db:"icon" json:"icon"`
+	RedirectURIs []string  `db:"redirect_uris" json:"redirect_uris"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		pq.Array(arg.RedirectURIs),
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		pq.Array(&i.RedirectURIs),
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppSecretByID = `-- name: UpdateOAuth2ProviderAppSecretByID :one
+UPDATE
+	oauth2_provider_app_secrets
+SET
+	last_used_at = $2
+WHERE
+	id = $1
+RETURNING id, app_id, created_at, last_used_at, hashed_secret, display_secret
+`
+
+type UpdateOAuth2ProviderAppSecretByIDParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppSecretByID, arg.ID, arg.LastUsedAt)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
+const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
+SELECT
+    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
+FROM
+    organization_members
+WHERE
+    user_id = ANY($1 :: uuid [ ])
+GROUP BY
+    user_id
+`
+
+type GetOrganizationIDsByMemberIDsRow struct {
+	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
+	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+}
+
+func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetProvisionerJobsByIDsWithQueuePositionRow
+	var items []GetOrganizationIDsByMemberIDsRow
 	for rows.Next() {
-		var i GetProvisionerJobsByIDsWithQueuePositionRow
-		if err := rows.Scan(
-			&i.ProvisionerJob.ID,
-			&i.ProvisionerJob.CreatedAt,
-			&i.ProvisionerJob.UpdatedAt,
-			&i.ProvisionerJob.StartedAt,
-			&i.ProvisionerJob.CanceledAt,
-			&i.ProvisionerJob.CompletedAt,
-			&i.ProvisionerJob.Error,
-			&i.ProvisionerJob.OrganizationID,
-			&i.ProvisionerJob.InitiatorID,
-			&i.ProvisionerJob.Provisioner,
-			&i.ProvisionerJob.StorageMethod,
-			&i.ProvisionerJob.Type,
-			&i.ProvisionerJob.Input,
-			&i.ProvisionerJob.WorkerID,
-			&i.ProvisionerJob.FileID,
-			&i.ProvisionerJob.Tags,
-			&i.ProvisionerJob.ErrorCode,
-			&i.ProvisionerJob.TraceMetadata,
-			&i.QueuePosition,
-			&i.QueueSize,
-		); err != nil {
+		var i GetOrganizationIDsByMemberIDsRow
+		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -3084,38 +3780,60 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 	return items, nil
 }
 
-const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata FROM provisioner_jobs WHERE created_at > $1
+const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
+SELECT
+	user_id, organization_id, created_at, updated_at, roles
+FROM
+	organization_members
+WHERE
+	organization_id = $1
+	AND user_id = $2
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsCreatedAfter, createdAt)
+type GetOrganizationMemberByUserIDParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.UserID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Roles),
+	)
+	return i, err
+}
+
+const getOrganizationMembershipsByUserID = `-- name: GetOrganizationMembershipsByUserID :many
+SELECT
+	user_id, organization_id, created_at, updated_at, roles
+FROM
+	organization_members
+WHERE
+  user_id = $1
+`
+
+func (q *sqlQuerier) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]OrganizationMember, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationMembershipsByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerJob
+	var items []OrganizationMember
 	for rows.Next() {
-		var i ProvisionerJob
+		var i OrganizationMember
 		if err := rows.Scan(
-			&i.ID,
+			&i.UserID,
+			&i.OrganizationID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.StartedAt,
-			&i.CanceledAt,
-			&i.CompletedAt,
-			&i.Error,
-			&i.OrganizationID,
-			&i.InitiatorID,
-			&i.Provisioner,
-			&i.StorageMethod,
-			&i.Type,
-			&i.Input,
-			&i.WorkerID,
-			&i.FileID,
-			&i.Tags,
-			&i.ErrorCode,
-			&i.TraceMetadata,
+			pq.Array(&i.Roles),
 		); err != nil {
 			return nil, err
 		}
@@ -3130,183 +3848,145 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 	return items, nil
 }
 
-const insertProvisionerJob = `-- name: InsertProvisionerJob :one
+const insertOrganizationMember = `-- name: InsertOrganizationMember :one
 INSERT INTO
-	provisioner_jobs (
-		id,
+	organization_members (
+		organization_id,
+		user_id,
 		created_at,
 		updated_at,
-		organization_id,
-		initiator_id,
-		provisioner,
-		storage_method,
-		file_id,
-		"type",
-		"input",
-		tags,
-		trace_metadata
+		roles
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+	($1, $2, $3, $4, $5) RETURNING user_id, organization_id, created_at, updated_at, roles
 `
 
-type InsertProvisionerJobParams struct {
-	ID             uuid.UUID                `db:"id" json:"id"`
-	CreatedAt      time.Time                `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time                `db:"updated_at" json:"updated_at"`
-	OrganizationID uuid.UUID                `db:"organization_id" json:"organization_id"`
-	InitiatorID    uuid.UUID                `db:"initiator_id" json:"initiator_id"`
-	Provisioner    ProvisionerType          `db:"provisioner" json:"provisioner"`
-	StorageMethod  ProvisionerStorageMethod `db:"storage_method" json:"storage_method"`
-	FileID         uuid.UUID                `db:"file_id" json:"file_id"`
-	Type           ProvisionerJobType       `db:"type" json:"type"`
-	Input          json.RawMessage          `db:"input" json:"input"`
-	Tags           StringMap                `db:"tags" json:"tags"`
-	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
+type InsertOrganizationMemberParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	Roles          []string  `db:"roles" json:"roles"`
 }
 
-func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
-	row := q.db.QueryRowContext(ctx, insertProvisionerJob,
-		arg.ID,
+func (q *sqlQuerier) InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, insertOrganizationMember,
+		arg.OrganizationID,
+		arg.UserID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.OrganizationID,
-		arg.InitiatorID,
-		arg.Provisioner,
-		arg.StorageMethod,
-		arg.FileID,
-		arg.Type,
-		arg.Input,
-		arg.Tags,
-		arg.TraceMetadata,
+		pq.Array(arg.Roles),
 	)
-	var i ProvisionerJob
+	var i OrganizationMember
 	err := row.Scan(
-		&i.ID,
+		&i.UserID,
+		&i.OrganizationID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.StartedAt,
-		&i.CanceledAt,
-		&i.CompletedAt,
-		&i.Error,
-		&i.OrganizationID,
-		&i.InitiatorID,
-		&i.Provisioner,
-		&i.StorageMethod,
-		&i.Type,
-		&i.Input,
-		&i.WorkerID,
-		&i.FileID,
-		&i.Tags,
-		&i.ErrorCode,
-		&i.TraceMetadata,
+		pq.Array(&i.Roles),
 	)
 	return i, err
 }
 
-const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
+const updateMemberRoles = `-- name: UpdateMemberRoles :one
 UPDATE
-	provisioner_jobs
+	organization_members
 SET
-	updated_at = $2
+	-- Remove all duplicates from the roles.
+	roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
-	id = $1
+	user_id = $2
+	AND organization_id = $3
+RETURNING user_id, organization_id, created_at, updated_at, roles
 `
 
-type UpdateProvisionerJobByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+type UpdateMemberRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	OrgID        uuid.UUID `db:"org_id" json:"org_id"`
 }
 
-func (q *sqlQuerier) UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobByID, arg.ID, arg.UpdatedAt)
-	return err
+func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, updateMemberRoles, pq.Array(arg.GrantedRoles), arg.UserID, arg.OrgID)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.UserID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Roles),
+	)
+	return i, err
 }
 
-const updateProvisionerJobWithCancelByID = `-- name: UpdateProvisionerJobWithCancelByID :exec
-UPDATE
-	provisioner_jobs
-SET
-	canceled_at = $2,
-	completed_at = $3
+const getOrganizationByID = `-- name: GetOrganizationByID :one
+SELECT
+	id, name, description, created_at, updated_at
+FROM
+	organizations
 WHERE
 	id = $1
 `
 
-type UpdateProvisionerJobWithCancelByIDParams struct {
-	ID          uuid.UUID    `db:"id" json:"id"`
-	CanceledAt  sql.NullTime `db:"canceled_at" json:"canceled_at"`
-	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
-}
-
-func (q *sqlQuerier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCancelByID, arg.ID, arg.CanceledAt, arg.CompletedAt)
-	return err
+func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationByID, id)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const updateProvisionerJobWithCompleteByID = `-- name: UpdateProvisionerJobWithCompleteByID :exec
-UPDATE
-	provisioner_jobs
-SET
-	updated_at = $2,
-	completed_at = $3,
-	error = $4,
-	error_code = $5
+const getOrganizationByName = `-- name: GetOrganizationByName :one
+SELECT
+	id, name, description, created_at, updated_at
+FROM
+	organizations
 WHERE
-	id = $1
+	LOWER("name") = LOWER($1)
+LIMIT
+	1
 `
 
-type UpdateProvisionerJobWithCompleteByIDParams struct {
-	ID          uuid.UUID      `db:"id" json:"id"`
-	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
-	CompletedAt sql.NullTime   `db:"completed_at" json:"completed_at"`
-	Error       sql.NullString `db:"error" json:"error"`
-	ErrorCode   sql.NullString `db:"error_code" json:"error_code"`
-}
-
-func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCompleteByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.CompletedAt,
-		arg.Error,
-		arg.ErrorCode,
+func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, name string) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationByName, name)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
-	return err
+	return i, err
 }
 
-const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
+const getOrganizations = `-- name: GetOrganizations :many
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	id, name, description, created_at, updated_at
 FROM
-	workspace_proxies
-WHERE
-	deleted = false
+	organizations
 `
 
-func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
+func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizations)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceProxy
+	var items []Organization
 	for rows.Next() {
-		var i WorkspaceProxy
+		var i Organization
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Url,
-			&i.WildcardHostname,
+			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Deleted,
-			&i.TokenHashedSecret,
-			&i.RegionID,
-			&i.DerpEnabled,
-			&i.DerpOnly,
 		); err != nil {
 			return nil, err
 		}
@@ -3321,433 +4001,310 @@ func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy,
 	return items, nil
 }
 
-const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
+const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	id, name, description, created_at, updated_at
 FROM
-	workspace_proxies
+	organizations
 WHERE
-	-- Validate that the @hostname has been sanitized and is not empty. This
-	-- doesn't prevent SQL injection (already prevented by using prepared
-	-- queries), but it does prevent carefully crafted hostnames from matching
-	-- when they shouldn't.
-	--
-	-- Periods don't need to be escaped because they're not special characters
-	-- in SQL matches unlike regular expressions.
-	$1 :: text SIMILAR TO '[a-zA-Z0-9._-]+' AND
-	deleted = false AND
-
-	-- Validate that the hostname matches either the wildcard hostname or the
-	-- access URL (ignoring scheme, port and path).
-	(
-		(
-			$2 :: bool = true AND
-			url SIMILAR TO '[^:]*://' || $1 :: text || '([:/]?%)*'
-		) OR
-		(
-			$3 :: bool = true AND
-			$1 :: text LIKE replace(wildcard_hostname, '*', '%')
-		)
+	id = (
+		SELECT
+			organization_id
+		FROM
+			organization_members
+		WHERE
+			user_id = $1
 	)
-LIMIT
-	1
 `
 
-type GetWorkspaceProxyByHostnameParams struct {
-	Hostname              string `db:"hostname" json:"hostname"`
-	AllowAccessUrl        bool   `db:"allow_access_url" json:"allow_access_url"`
-	AllowWildcardHostname bool   `db:"allow_wildcard_hostname" json:"allow_wildcard_hostname"`
+func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Organization
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-// Finds a workspace proxy that has an access URL or app hostname that matches
-// the provided hostname. This is to check if a hostname matches any workspace
-// proxy.
-//
-// The hostname must be sanitized to only contain [a-zA-Z0-9.-] before calling
-// this query. The scheme, port and path should be stripped.
-func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByHostname, arg.Hostname, arg.AllowAccessUrl, arg.AllowWildcardHostname)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-	)
-	return i, err
-}
+const insertOrganization = `-- name: InsertOrganization :one
+INSERT INTO
+	organizations (id, "name", description, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at
+`
 
-const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
-SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
-FROM
-	workspace_proxies
-WHERE
-	id = $1
-LIMIT
-	1
-`
+type InsertOrganizationParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
 
-func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByID, id)
-	var i WorkspaceProxy
+func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, insertOrganization,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Organization
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
+		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
 	)
 	return i, err
 }
 
-const getWorkspaceProxyByName = `-- name: GetWorkspaceProxyByName :one
+const getParameterSchemasByJobID = `-- name: GetParameterSchemasByJobID :many
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	id, created_at, job_id, name, description, default_source_scheme, default_source_value, allow_override_source, default_destination_scheme, allow_override_destination, default_refresh, redisplay_value, validation_error, validation_condition, validation_type_system, validation_value_type, index
 FROM
-	workspace_proxies
+	parameter_schemas
 WHERE
-	name = $1
-	AND deleted = false
-LIMIT
-	1
+	job_id = $1
+ORDER BY
+	index
 `
 
-func (q *sqlQuerier) GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByName, name)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-	)
-	return i, err
+func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error) {
+	rows, err := q.db.QueryContext(ctx, getParameterSchemasByJobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ParameterSchema
+	for rows.Next() {
+		var i ParameterSchema
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.JobID,
+			&i.Name,
+			&i.Description,
+			&i.DefaultSourceScheme,
+			&i.DefaultSourceValue,
+			&i.AllowOverrideSource,
+			&i.DefaultDestinationScheme,
+			&i.AllowOverrideDestination,
+			&i.DefaultRefresh,
+			&i.RedisplayValue,
+			&i.ValidationError,
+			&i.ValidationCondition,
+			&i.ValidationTypeSystem,
+			&i.ValidationValueType,
+			&i.Index,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const insertWorkspaceProxy = `-- name: InsertWorkspaceProxy :one
+const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
+SELECT
+	id, created_at, updated_at, name, provisioners, replica_id, tags
+FROM
+	provisioner_daemons
+`
+
+func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerDaemons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerDaemon
+	for rows.Next() {
+		var i ProvisionerDaemon
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			pq.Array(&i.Provisioners),
+			&i.ReplicaID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertProvisionerDaemon = `-- name: InsertProvisionerDaemon :one
 INSERT INTO
-	workspace_proxies (
+	provisioner_daemons (
 		id,
-		url,
-		wildcard_hostname,
-		name,
-		display_name,
-		icon,
-		derp_enabled,
-		derp_only,
-		token_hashed_secret,
 		created_at,
-		updated_at,
-		deleted
+		"name",
+		provisioners,
+		tags
 	)
 VALUES
-	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags
 `
 
-type InsertWorkspaceProxyParams struct {
-	ID                uuid.UUID `db:"id" json:"id"`
-	Name              string    `db:"name" json:"name"`
-	DisplayName       string    `db:"display_name" json:"display_name"`
-	Icon              string    `db:"icon" json:"icon"`
-	DerpEnabled       bool      `db:"derp_enabled" json:"derp_enabled"`
-	DerpOnly          bool      `db:"derp_only" json:"derp_only"`
-	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
-	CreatedAt         time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+type InsertProvisionerDaemonParams struct {
+	ID           uuid.UUID         `db:"id" json:"id"`
+	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
+	Name         string            `db:"name" json:"name"`
+	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
+	Tags         StringMap         `db:"tags" json:"tags"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceProxy,
+func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProvisionerDaemonParams) (ProvisionerDaemon, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerDaemon,
 		arg.ID,
-		arg.Name,
-		arg.DisplayName,
-		arg.Icon,
-		arg.DerpEnabled,
-		arg.DerpOnly,
-		arg.TokenHashedSecret,
 		arg.CreatedAt,
-		arg.UpdatedAt,
+		arg.Name,
+		pq.Array(arg.Provisioners),
+		arg.Tags,
 	)
-	var i WorkspaceProxy
+	var i ProvisionerDaemon
 	err := row.Scan(
 		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
+		&i.Name,
+		pq.Array(&i.Provisioners),
+		&i.ReplicaID,
+		&i.Tags,
 	)
 	return i, err
 }
 
-const registerWorkspaceProxy = `-- name: RegisterWorkspaceProxy :one
-UPDATE
-	workspace_proxies
-SET
-	url = $1 :: text,
-	wildcard_hostname = $2 :: text,
-	derp_enabled = $3 :: boolean,
-	derp_only = $4 :: boolean,
-	updated_at = Now()
+const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
+SELECT
+	job_id, created_at, source, level, stage, output, id
+FROM
+	provisioner_job_logs
 WHERE
-	id = $5
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	job_id = $1
+	AND (
+		id > $2
+	) ORDER BY id ASC
 `
 
-type RegisterWorkspaceProxyParams struct {
-	Url              string    `db:"url" json:"url"`
-	WildcardHostname string    `db:"wildcard_hostname" json:"wildcard_hostname"`
-	DerpEnabled      bool      `db:"derp_enabled" json:"derp_enabled"`
-	DerpOnly         bool      `db:"derp_only" json:"derp_only"`
-	ID               uuid.UUID `db:"id" json:"id"`
+type GetProvisionerLogsAfterIDParams struct {
+	JobID        uuid.UUID `db:"job_id" json:"job_id"`
+	CreatedAfter int64     `db:"created_after" json:"created_after"`
 }
 
-func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, registerWorkspaceProxy,
-		arg.Url,
-		arg.WildcardHostname,
-		arg.DerpEnabled,
-		arg.DerpOnly,
-		arg.ID,
-	)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-	)
-	return i, err
-}
-
-const updateWorkspaceProxy = `-- name: UpdateWorkspaceProxy :one
-UPDATE
-	workspace_proxies
-SET
-	-- These values should always be provided.
-	name = $1,
-	display_name = $2,
-	icon = $3,
-	-- Only update the token if a new one is provided.
-	-- So this is an optional field.
-	token_hashed_secret = CASE
-		WHEN length($4 :: bytea) > 0  THEN $4 :: bytea
-		ELSE  workspace_proxies.token_hashed_secret
-	END,
-	-- Always update this timestamp.
-	updated_at = Now()
-WHERE
-	id = $5
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
-`
-
-type UpdateWorkspaceProxyParams struct {
-	Name              string    `db:"name" json:"name"`
-	DisplayName       string    `db:"display_name" json:"display_name"`
-	Icon              string    `db:"icon" json:"icon"`
-	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
-	ID                uuid.UUID `db:"id" json:"id"`
-}
-
-// This allows editing the properties of a workspace proxy.
-func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, updateWorkspaceProxy,
-		arg.Name,
-		arg.DisplayName,
-		arg.Icon,
-		arg.TokenHashedSecret,
-		arg.ID,
-	)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-	)
-	return i, err
-}
-
-const updateWorkspaceProxyDeleted = `-- name: UpdateWorkspaceProxyDeleted :exec
-UPDATE
-	workspace_proxies
-SET
-	updated_at = Now(),
-	deleted = $1
-WHERE
-	id = $2
-`
-
-type UpdateWorkspaceProxyDeletedParams struct {
-	Deleted bool      `db:"deleted" json:"deleted"`
-	ID      uuid.UUID `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceProxyDeleted, arg.Deleted, arg.ID)
-	return err
-}
-
-const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
-SELECT
-	coalesce(SUM(quota_allowance), 0)::BIGINT
-FROM
-	groups g
-LEFT JOIN group_members gm ON
-	g.id = gm.group_id
-WHERE
-	user_id = $1
-OR
-    g.id = g.organization_id
-`
-
-func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, userID)
-	var column_1 int64
-	err := row.Scan(&column_1)
-	return column_1, err
+func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerLogsAfterID, arg.JobID, arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobLog
+	for rows.Next() {
+		var i ProvisionerJobLog
+		if err := rows.Scan(
+			&i.JobID,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Level,
+			&i.Stage,
+			&i.Output,
+			&i.ID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
-WITH latest_builds AS (
-SELECT
-	DISTINCT ON
-	(workspace_id) id,
-	workspace_id,
-	daily_cost
-FROM
-	workspace_builds wb
-ORDER BY
-	workspace_id,
-	created_at DESC
-)
+const insertProvisionerJobLogs = `-- name: InsertProvisionerJobLogs :many
+INSERT INTO
+	provisioner_job_logs
 SELECT
-	coalesce(SUM(daily_cost), 0)::BIGINT
-FROM
-	workspaces
-JOIN latest_builds ON
-	latest_builds.workspace_id = workspaces.id
-WHERE NOT deleted AND workspaces.owner_id = $1
-`
-
-func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
-	var column_1 int64
-	err := row.Scan(&column_1)
-	return column_1, err
-}
-
-const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
-DELETE FROM replicas WHERE updated_at < $1
+	$1 :: uuid AS job_id,
+	unnest($2 :: timestamptz [ ]) AS created_at,
+	unnest($3 :: log_source [ ]) AS source,
+	unnest($4 :: log_level [ ]) AS LEVEL,
+	unnest($5 :: VARCHAR(128) [ ]) AS stage,
+	unnest($6 :: VARCHAR(1024) [ ]) AS output RETURNING job_id, created_at, source, level, stage, output, id
 `
 
-func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
-	return err
+type InsertProvisionerJobLogsParams struct {
+	JobID     uuid.UUID   `db:"job_id" json:"job_id"`
+	CreatedAt []time.Time `db:"created_at" json:"created_at"`
+	Source    []LogSource `db:"source" json:"source"`
+	Level     []LogLevel  `db:"level" json:"level"`
+	Stage     []string    `db:"stage" json:"stage"`
+	Output    []string    `db:"output" json:"output"`
 }
 
-const getReplicaByID = `-- name: GetReplicaByID :one
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
-`
-
-func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
-	var i Replica
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
-		&i.UpdatedAt,
-		&i.Hostname,
-		&i.RegionID,
-		&i.RelayAddress,
-		&i.DatabaseLatency,
-		&i.Version,
-		&i.Error,
-		&i.Primary,
+func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error) {
+	rows, err := q.db.QueryContext(ctx, insertProvisionerJobLogs,
+		arg.JobID,
+		pq.Array(arg.CreatedAt),
+		pq.Array(arg.Source),
+		pq.Array(arg.Level),
+		pq.Array(arg.Stage),
+		pq.Array(arg.Output),
 	)
-	return i, err
-}
-
-const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
-`
-
-func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
-	rows, err := q.db.QueryContext(ctx, getReplicasUpdatedAfter, updatedAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Replica
+	var items []ProvisionerJobLog
 	for rows.Next() {
-		var i Replica
+		var i ProvisionerJobLog
 		if err := rows.Scan(
-			&i.ID,
+			&i.JobID,
 			&i.CreatedAt,
-			&i.StartedAt,
-			&i.StoppedAt,
-			&i.UpdatedAt,
-			&i.Hostname,
-			&i.RegionID,
-			&i.RelayAddress,
-			&i.DatabaseLatency,
-			&i.Version,
-			&i.Error,
-			&i.Primary,
+			&i.Source,
+			&i.Level,
+			&i.Stage,
+			&i.Output,
+			&i.ID,
 		); err != nil {
 			return nil, err
 		}
@@ -3762,401 +4319,203 @@ func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time
 	return items, nil
 }
 
-const insertReplica = `-- name: InsertReplica :one
-INSERT INTO replicas (
-    id,
-    created_at,
-    started_at,
-    updated_at,
-    hostname,
-    region_id,
-    relay_address,
-    version,
-    database_latency,
-	"primary"
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+const acquireProvisionerJob = `-- name: AcquireProvisionerJob :one
+UPDATE
+	provisioner_jobs
+SET
+	started_at = $1,
+	updated_at = $1,
+	worker_id = $2
+WHERE
+	id = (
+		SELECT
+			id
+		FROM
+			provisioner_jobs AS nested
+		WHERE
+			nested.started_at IS NULL
+			-- Ensure the caller has the correct provisioner.
+			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
+			-- Ensure the caller satisfies all job tags.
+			AND nested.tags <@ $4 :: jsonb
+		ORDER BY
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			1
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
 `
 
-type InsertReplicaParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	StartedAt       time.Time `db:"started_at" json:"started_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-	Hostname        string    `db:"hostname" json:"hostname"`
-	RegionID        int32     `db:"region_id" json:"region_id"`
-	RelayAddress    string    `db:"relay_address" json:"relay_address"`
-	Version         string    `db:"version" json:"version"`
-	DatabaseLatency int32     `db:"database_latency" json:"database_latency"`
-	Primary         bool      `db:"primary" json:"primary"`
+type AcquireProvisionerJobParams struct {
+	StartedAt sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID  uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	Types     []ProvisionerType `db:"types" json:"types"`
+	Tags      json.RawMessage   `db:"tags" json:"tags"`
 }
 
-func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, insertReplica,
-		arg.ID,
-		arg.CreatedAt,
+// Acquires the lock for a single job that isn't started, completed,
+// canceled, and that matches an array of provisioner types.
+//
+// SKIP LOCKED is used to jump over locked rows. This prevents
+// multiple provisioners from acquiring the same jobs. See:
+// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
+func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error) {
+	row := q.db.QueryRowContext(ctx, acquireProvisionerJob,
 		arg.StartedAt,
-		arg.UpdatedAt,
-		arg.Hostname,
-		arg.RegionID,
-		arg.RelayAddress,
-		arg.Version,
-		arg.DatabaseLatency,
-		arg.Primary,
+		arg.WorkerID,
+		pq.Array(arg.Types),
+		arg.Tags,
 	)
-	var i Replica
+	var i ProvisionerJob
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
 		&i.UpdatedAt,
-		&i.Hostname,
-		&i.RegionID,
-		&i.RelayAddress,
-		&i.DatabaseLatency,
-		&i.Version,
+		&i.StartedAt,
+		&i.CanceledAt,
+		&i.CompletedAt,
 		&i.Error,
-		&i.Primary,
+		&i.OrganizationID,
+		&i.InitiatorID,
+		&i.Provisioner,
+		&i.StorageMethod,
+		&i.Type,
+		&i.Input,
+		&i.WorkerID,
+		&i.FileID,
+		&i.Tags,
+		&i.ErrorCode,
+		&i.TraceMetadata,
 	)
 	return i, err
 }
 
-const updateReplica = `-- name: UpdateReplica :one
-UPDATE replicas SET
-    updated_at = $2,
-    started_at = $3,
-    stopped_at = $4,
-    relay_address = $5,
-    region_id = $6,
-    hostname = $7,
-    version = $8,
-    error = $9,
-    database_latency = $10,
-	"primary" = $11
-WHERE id = $1 RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+FROM
+	provisioner_jobs
+WHERE
+	updated_at < $1
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL
 `
 
-type UpdateReplicaParams struct {
-	ID              uuid.UUID    `db:"id" json:"id"`
-	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
-	StartedAt       time.Time    `db:"started_at" json:"started_at"`
-	StoppedAt       sql.NullTime `db:"stopped_at" json:"stopped_at"`
-	RelayAddress    string       `db:"relay_address" json:"relay_address"`
-	RegionID        int32        `db:"region_id" json:"region_id"`
-	Hostname        string       `db:"hostname" json:"hostname"`
-	Version         string       `db:"version" json:"version"`
-	Error           string       `db:"error" json:"error"`
-	DatabaseLatency int32        `db:"database_latency" json:"database_latency"`
-	Primary         bool         `db:"primary" json:"primary"`
+func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getHungProvisionerJobs, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJob
+	for rows.Next() {
+		var i ProvisionerJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, updateReplica,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.StartedAt,
-		arg.StoppedAt,
-		arg.RelayAddress,
-		arg.RegionID,
-		arg.Hostname,
-		arg.Version,
-		arg.Error,
-		arg.DatabaseLatency,
-		arg.Primary,
-	)
-	var i Replica
+const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+FROM
+	provisioner_jobs
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerJobByID, id)
+	var i ProvisionerJob
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
 		&i.UpdatedAt,
-		&i.Hostname,
-		&i.RegionID,
-		&i.RelayAddress,
-		&i.DatabaseLatency,
-		&i.Version,
+		&i.StartedAt,
+		&i.CanceledAt,
+		&i.CompletedAt,
 		&i.Error,
-		&i.Primary,
+		&i.OrganizationID,
+		&i.InitiatorID,
+		&i.Provisioner,
+		&i.StorageMethod,
+		&i.Type,
+		&i.Input,
+		&i.WorkerID,
+		&i.FileID,
+		&i.Tags,
+		&i.ErrorCode,
+		&i.TraceMetadata,
 	)
 	return i, err
 }
 
-const getAppSecurityKey = `-- name: GetAppSecurityKey :one
-SELECT value FROM site_configs WHERE key = 'app_signing_key'
-`
-
-func (q *sqlQuerier) GetAppSecurityKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getAppSecurityKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getDERPMeshKey = `-- name: GetDERPMeshKey :one
-SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
-`
-
-func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getDERPMeshKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getDefaultProxyConfig = `-- name: GetDefaultProxyConfig :one
+const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
 SELECT
-	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_display_name'), 'Default') :: text AS display_name,
-	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_icon_url'), '/emojis/1f3e1.png') :: text AS icon_url
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+FROM
+	provisioner_jobs
+WHERE
+	id = ANY($1 :: uuid [ ])
 `
 
-type GetDefaultProxyConfigRow struct {
-	DisplayName string `db:"display_name" json:"display_name"`
-	IconUrl     string `db:"icon_url" json:"icon_url"`
-}
-
-func (q *sqlQuerier) GetDefaultProxyConfig(ctx context.Context) (GetDefaultProxyConfigRow, error) {
-	row := q.db.QueryRowContext(ctx, getDefaultProxyConfig)
-	var i GetDefaultProxyConfigRow
-	err := row.Scan(&i.DisplayName, &i.IconUrl)
-	return i, err
-}
-
-const getDeploymentID = `-- name: GetDeploymentID :one
-SELECT value FROM site_configs WHERE key = 'deployment_id'
-`
-
-func (q *sqlQuerier) GetDeploymentID(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getDeploymentID)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getLastUpdateCheck = `-- name: GetLastUpdateCheck :one
-SELECT value FROM site_configs WHERE key = 'last_update_check'
-`
-
-func (q *sqlQuerier) GetLastUpdateCheck(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getLastUpdateCheck)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getLogoURL = `-- name: GetLogoURL :one
-SELECT value FROM site_configs WHERE key = 'logo_url'
-`
-
-func (q *sqlQuerier) GetLogoURL(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getLogoURL)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getOAuthSigningKey = `-- name: GetOAuthSigningKey :one
-SELECT value FROM site_configs WHERE key = 'oauth_signing_key'
-`
-
-func (q *sqlQuerier) GetOAuthSigningKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getOAuthSigningKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getServiceBanner = `-- name: GetServiceBanner :one
-SELECT value FROM site_configs WHERE key = 'service_banner'
-`
-
-func (q *sqlQuerier) GetServiceBanner(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getServiceBanner)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const insertDERPMeshKey = `-- name: InsertDERPMeshKey :exec
-INSERT INTO site_configs (key, value) VALUES ('derp_mesh_key', $1)
-`
-
-func (q *sqlQuerier) InsertDERPMeshKey(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, insertDERPMeshKey, value)
-	return err
-}
-
-const insertDeploymentID = `-- name: InsertDeploymentID :exec
-INSERT INTO site_configs (key, value) VALUES ('deployment_id', $1)
-`
-
-func (q *sqlQuerier) InsertDeploymentID(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, insertDeploymentID, value)
-	return err
-}
-
-const upsertAppSecurityKey = `-- name: UpsertAppSecurityKey :exec
-INSERT INTO site_configs (key, value) VALUES ('app_signing_key', $1)
-ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'app_signing_key'
-`
-
-func (q *sqlQuerier) UpsertAppSecurityKey(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertAppSecurityKey, value)
-	return err
-}
-
-const upsertDefaultProxy = `-- name: UpsertDefaultProxy :exec
-INSERT INTO site_configs (key, value)
-VALUES
-    ('default_proxy_display_name', $1 :: text),
-    ('default_proxy_icon_url', $2 :: text)
-ON CONFLICT
-    (key)
-DO UPDATE SET value = EXCLUDED.value WHERE site_configs.key = EXCLUDED.key
-`
-
-type UpsertDefaultProxyParams struct {
-	DisplayName string `db:"display_name" json:"display_name"`
-	IconUrl     string `db:"icon_url" json:"icon_url"`
-}
-
-// The default proxy is implied and not actually stored in the database.
-// So we need to store it's configuration here for display purposes.
-// The functional values are immutable and controlled implicitly.
-func (q *sqlQuerier) UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error {
-	_, err := q.db.ExecContext(ctx, upsertDefaultProxy, arg.DisplayName, arg.IconUrl)
-	return err
-}
-
-const upsertLastUpdateCheck = `-- name: UpsertLastUpdateCheck :exec
-INSERT INTO site_configs (key, value) VALUES ('last_update_check', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update_check'
-`
-
-func (q *sqlQuerier) UpsertLastUpdateCheck(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertLastUpdateCheck, value)
-	return err
-}
-
-const upsertLogoURL = `-- name: UpsertLogoURL :exec
-INSERT INTO site_configs (key, value) VALUES ('logo_url', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'logo_url'
-`
-
-func (q *sqlQuerier) UpsertLogoURL(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertLogoURL, value)
-	return err
-}
-
-const upsertOAuthSigningKey = `-- name: UpsertOAuthSigningKey :exec
-INSERT INTO site_configs (key, value) VALUES ('oauth_signing_key', $1)
-ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'oauth_signing_key'
-`
-
-func (q *sqlQuerier) UpsertOAuthSigningKey(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertOAuthSigningKey, value)
-	return err
-}
-
-const upsertServiceBanner = `-- name: UpsertServiceBanner :exec
-INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
-`
-
-func (q *sqlQuerier) UpsertServiceBanner(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertServiceBanner, value)
-	return err
-}
-
-const cleanTailnetCoordinators = `-- name: CleanTailnetCoordinators :exec
-DELETE
-FROM tailnet_coordinators
-WHERE heartbeat_at < now() - INTERVAL '24 HOURS'
-`
-
-func (q *sqlQuerier) CleanTailnetCoordinators(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, cleanTailnetCoordinators)
-	return err
-}
-
-const deleteCoordinator = `-- name: DeleteCoordinator :exec
-DELETE
-FROM tailnet_coordinators
-WHERE id = $1
-`
-
-func (q *sqlQuerier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteCoordinator, id)
-	return err
-}
-
-const deleteTailnetAgent = `-- name: DeleteTailnetAgent :one
-DELETE
-FROM tailnet_agents
-WHERE id = $1 and coordinator_id = $2
-RETURNING id, coordinator_id
-`
-
-type DeleteTailnetAgentParams struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-}
-
-type DeleteTailnetAgentRow struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-}
-
-func (q *sqlQuerier) DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error) {
-	row := q.db.QueryRowContext(ctx, deleteTailnetAgent, arg.ID, arg.CoordinatorID)
-	var i DeleteTailnetAgentRow
-	err := row.Scan(&i.ID, &i.CoordinatorID)
-	return i, err
-}
-
-const deleteTailnetClient = `-- name: DeleteTailnetClient :one
-DELETE
-FROM tailnet_clients
-WHERE id = $1 and coordinator_id = $2
-RETURNING id, coordinator_id
-`
-
-type DeleteTailnetClientParams struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-}
-
-type DeleteTailnetClientRow struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-}
-
-func (q *sqlQuerier) DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error) {
-	row := q.db.QueryRowContext(ctx, deleteTailnetClient, arg.ID, arg.CoordinatorID)
-	var i DeleteTailnetClientRow
-	err := row.Scan(&i.ID, &i.CoordinatorID)
-	return i, err
-}
-
-const getAllTailnetAgents = `-- name: GetAllTailnetAgents :many
-SELECT id, coordinator_id, updated_at, node
-FROM tailnet_agents
-`
-
-func (q *sqlQuerier) GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getAllTailnetAgents)
+func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TailnetAgent
+	var items []ProvisionerJob
 	for rows.Next() {
-		var i TailnetAgent
+		var i ProvisionerJob
 		if err := rows.Scan(
 			&i.ID,
-			&i.CoordinatorID,
+			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Node,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
 		); err != nil {
 			return nil, err
 		}
@@ -4171,61 +4530,75 @@ func (q *sqlQuerier) GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, e
 	return items, nil
 }
 
-const getAllTailnetClients = `-- name: GetAllTailnetClients :many
-SELECT id, coordinator_id, agent_id, updated_at, node
-FROM tailnet_clients
-ORDER BY agent_id
-`
-
-func (q *sqlQuerier) GetAllTailnetClients(ctx context.Context) ([]TailnetClient, error) {
-	rows, err := q.db.QueryContext(ctx, getAllTailnetClients)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TailnetClient
-	for rows.Next() {
-		var i TailnetClient
-		if err := rows.Scan(
-			&i.ID,
-			&i.CoordinatorID,
-			&i.AgentID,
-			&i.UpdatedAt,
-			&i.Node,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getTailnetAgents = `-- name: GetTailnetAgents :many
-SELECT id, coordinator_id, updated_at, node
-FROM tailnet_agents
-WHERE id = $1
+const getProvisionerJobsByIDsWithQueuePosition = `-- name: GetProvisionerJobsByIDsWithQueuePosition :many
+WITH unstarted_jobs AS (
+    SELECT
+        id, created_at
+    FROM
+        provisioner_jobs
+    WHERE
+        started_at IS NULL
+),
+queue_position AS (
+    SELECT
+        id,
+        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+    FROM
+        unstarted_jobs
+),
+queue_size AS (
+	SELECT COUNT(*) as count FROM unstarted_jobs
+)
+SELECT
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata,
+    COALESCE(qp.queue_position, 0) AS queue_position,
+    COALESCE(qs.count, 0) AS queue_size
+FROM
+	provisioner_jobs pj
+LEFT JOIN
+	queue_position qp ON qp.id = pj.id
+LEFT JOIN
+	queue_size qs ON TRUE
+WHERE
+	pj.id = ANY($1 :: uuid [ ])
 `
 
-func (q *sqlQuerier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getTailnetAgents, id)
+type GetProvisionerJobsByIDsWithQueuePositionRow struct {
+	ProvisionerJob ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
+	QueuePosition  int64          `db:"queue_position" json:"queue_position"`
+	QueueSize      int64          `db:"queue_size" json:"queue_size"`
+}
+
+func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDsWithQueuePosition, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TailnetAgent
+	var items []GetProvisionerJobsByIDsWithQueuePositionRow
 	for rows.Next() {
-		var i TailnetAgent
+		var i GetProvisionerJobsByIDsWithQueuePositionRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CoordinatorID,
-			&i.UpdatedAt,
-			&i.Node,
+			&i.ProvisionerJob.ID,
+			&i.ProvisionerJob.CreatedAt,
+			&i.ProvisionerJob.UpdatedAt,
+			&i.ProvisionerJob.StartedAt,
+			&i.ProvisionerJob.CanceledAt,
+			&i.ProvisionerJob.CompletedAt,
+			&i.ProvisionerJob.Error,
+			&i.ProvisionerJob.OrganizationID,
+			&i.ProvisionerJob.InitiatorID,
+			&i.ProvisionerJob.Provisioner,
+			&i.ProvisionerJob.StorageMethod,
+			&i.ProvisionerJob.Type,
+			&i.ProvisionerJob.Input,
+			&i.ProvisionerJob.WorkerID,
+			&i.ProvisionerJob.FileID,
+			&i.ProvisionerJob.Tags,
+			&i.ProvisionerJob.ErrorCode,
+			&i.ProvisionerJob.TraceMetadata,
+			&i.QueuePosition,
+			&i.QueueSize,
 		); err != nil {
 			return nil, err
 		}
@@ -4240,27 +4613,38 @@ func (q *sqlQuerier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]Tail
 	return items, nil
 }
 
-const getTailnetClientsForAgent = `-- name: GetTailnetClientsForAgent :many
-SELECT id, coordinator_id, agent_id, updated_at, node
-FROM tailnet_clients
-WHERE agent_id = $1
+const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata FROM provisioner_jobs WHERE created_at > $1
 `
 
-func (q *sqlQuerier) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error) {
-	rows, err := q.db.QueryContext(ctx, getTailnetClientsForAgent, agentID)
+func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsCreatedAfter, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TailnetClient
+	var items []ProvisionerJob
 	for rows.Next() {
-		var i TailnetClient
+		var i ProvisionerJob
 		if err := rows.Scan(
 			&i.ID,
-			&i.CoordinatorID,
-			&i.AgentID,
+			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Node,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
 		); err != nil {
 			return nil, err
 		}
@@ -4275,310 +4659,183 @@ func (q *sqlQuerier) GetTailnetClientsForAgent(ctx context.Context, agentID uuid
 	return items, nil
 }
 
-const upsertTailnetAgent = `-- name: UpsertTailnetAgent :one
+const insertProvisionerJob = `-- name: InsertProvisionerJob :one
 INSERT INTO
-	tailnet_agents (
-	id,
-	coordinator_id,
-	node,
-	updated_at
-)
+	provisioner_jobs (
+		id,
+		created_at,
+		updated_at,
+		organization_id,
+		initiator_id,
+		provisioner,
+		storage_method,
+		file_id,
+		"type",
+		"input",
+		tags,
+		trace_metadata
+	)
 VALUES
-	($1, $2, $3, now() at time zone 'utc')
-ON CONFLICT (id, coordinator_id)
-DO UPDATE SET
-	id = $1,
-	coordinator_id = $2,
-	node = $3,
-	updated_at = now() at time zone 'utc'
-RETURNING id, coordinator_id, updated_at, node
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
 `
 
-type UpsertTailnetAgentParams struct {
-	ID            uuid.UUID       `db:"id" json:"id"`
-	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
-	Node          json.RawMessage `db:"node" json:"node"`
+type InsertProvisionerJobParams struct {
+	ID             uuid.UUID                `db:"id" json:"id"`
+	CreatedAt      time.Time                `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                `db:"updated_at" json:"updated_at"`
+	OrganizationID uuid.UUID                `db:"organization_id" json:"organization_id"`
+	InitiatorID    uuid.UUID                `db:"initiator_id" json:"initiator_id"`
+	Provisioner    ProvisionerType          `db:"provisioner" json:"provisioner"`
+	StorageMethod  ProvisionerStorageMethod `db:"storage_method" json:"storage_method"`
+	FileID         uuid.UUID                `db:"file_id" json:"file_id"`
+	Type           ProvisionerJobType       `db:"type" json:"type"`
+	Input          json.RawMessage          `db:"input" json:"input"`
+	Tags           StringMap                `db:"tags" json:"tags"`
+	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
 }
 
-func (q *sqlQuerier) UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetAgent, arg.ID, arg.CoordinatorID, arg.Node)
-	var i TailnetAgent
+func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerJob,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.OrganizationID,
+		arg.InitiatorID,
+		arg.Provisioner,
+		arg.StorageMethod,
+		arg.FileID,
+		arg.Type,
+		arg.Input,
+		arg.Tags,
+		arg.TraceMetadata,
+	)
+	var i ProvisionerJob
 	err := row.Scan(
 		&i.ID,
-		&i.CoordinatorID,
+		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Node,
+		&i.StartedAt,
+		&i.CanceledAt,
+		&i.CompletedAt,
+		&i.Error,
+		&i.OrganizationID,
+		&i.InitiatorID,
+		&i.Provisioner,
+		&i.StorageMethod,
+		&i.Type,
+		&i.Input,
+		&i.WorkerID,
+		&i.FileID,
+		&i.Tags,
+		&i.ErrorCode,
+		&i.TraceMetadata,
 	)
 	return i, err
 }
 
-const upsertTailnetClient = `-- name: UpsertTailnetClient :one
-INSERT INTO
-	tailnet_clients (
-	id,
-	coordinator_id,
-	agent_id,
-	node,
-	updated_at
-)
-VALUES
-	($1, $2, $3, $4, now() at time zone 'utc')
-ON CONFLICT (id, coordinator_id)
-DO UPDATE SET
-	id = $1,
-	coordinator_id = $2,
-	agent_id = $3,
-	node = $4,
-	updated_at = now() at time zone 'utc'
-RETURNING id, coordinator_id, agent_id, updated_at, node
+const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2
+WHERE
+	id = $1
 `
 
-type UpsertTailnetClientParams struct {
-	ID            uuid.UUID       `db:"id" json:"id"`
-	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
-	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
-	Node          json.RawMessage `db:"node" json:"node"`
+type UpdateProvisionerJobByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetClient,
-		arg.ID,
-		arg.CoordinatorID,
-		arg.AgentID,
-		arg.Node,
-	)
-	var i TailnetClient
-	err := row.Scan(
-		&i.ID,
-		&i.CoordinatorID,
-		&i.AgentID,
-		&i.UpdatedAt,
-		&i.Node,
-	)
-	return i, err
-}
-
-const upsertTailnetCoordinator = `-- name: UpsertTailnetCoordinator :one
-INSERT INTO
-	tailnet_coordinators (
-	id,
-	heartbeat_at
-)
-VALUES
-	($1, now() at time zone 'utc')
-ON CONFLICT (id)
-DO UPDATE SET
-  id = $1,
-  heartbeat_at = now() at time zone 'utc'
-RETURNING id, heartbeat_at
-`
-
-func (q *sqlQuerier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetCoordinator, id)
-	var i TailnetCoordinator
-	err := row.Scan(&i.ID, &i.HeartbeatAt)
-	return i, err
+func (q *sqlQuerier) UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobByID, arg.ID, arg.UpdatedAt)
+	return err
 }
 
-const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
-WITH build_times AS (
-SELECT
-	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
-	workspace_builds.transition
-FROM
-	workspace_builds
-JOIN template_versions ON
-	workspace_builds.template_version_id = template_versions.id
-JOIN provisioner_jobs pj ON
-	workspace_builds.job_id = pj.id
+const updateProvisionerJobWithCancelByID = `-- name: UpdateProvisionerJobWithCancelByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	canceled_at = $2,
+	completed_at = $3
 WHERE
-	template_versions.template_id = $1 AND
-		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
-		(pj.started_at > $2) AND
-		(pj.canceled_at IS NULL) AND
-		((pj.error IS NULL) OR (pj.error = ''))
-ORDER BY
-	workspace_builds.created_at DESC
-)
-SELECT
-	-- Postgres offers no clear way to DRY this short of a function or other
-	-- complexities.
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
-FROM build_times
+	id = $1
 `
 
-type GetTemplateAverageBuildTimeParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
-}
-
-type GetTemplateAverageBuildTimeRow struct {
-	Start50  float64 `db:"start_50" json:"start_50"`
-	Stop50   float64 `db:"stop_50" json:"stop_50"`
-	Delete50 float64 `db:"delete_50" json:"delete_50"`
-	Start95  float64 `db:"start_95" json:"start_95"`
-	Stop95   float64 `db:"stop_95" json:"stop_95"`
-	Delete95 float64 `db:"delete_95" json:"delete_95"`
+type UpdateProvisionerJobWithCancelByIDParams struct {
+	ID          uuid.UUID    `db:"id" json:"id"`
+	CanceledAt  sql.NullTime `db:"canceled_at" json:"canceled_at"`
+	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
 }
 
-func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
-	var i GetTemplateAverageBuildTimeRow
-	err := row.Scan(
-		&i.Start50,
-		&i.Stop50,
-		&i.Delete50,
-		&i.Start95,
-		&i.Stop95,
-		&i.Delete95,
-	)
-	return i, err
+func (q *sqlQuerier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCancelByID, arg.ID, arg.CanceledAt, arg.CompletedAt)
+	return err
 }
 
-const getTemplateByID = `-- name: GetTemplateByID :one
-SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username
-FROM
-	template_with_users
+const updateProvisionerJobWithCompleteByID = `-- name: UpdateProvisionerJobWithCompleteByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	completed_at = $3,
+	error = $4,
+	error_code = $5
 WHERE
 	id = $1
-LIMIT
-	1
 `
 
-func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
-	var i Template
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
-		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
-		&i.Description,
-		&i.DefaultTTL,
-		&i.CreatedBy,
-		&i.Icon,
-		&i.UserACL,
-		&i.GroupACL,
-		&i.DisplayName,
-		&i.AllowUserCancelWorkspaceJobs,
-		&i.MaxTTL,
-		&i.AllowUserAutostart,
-		&i.AllowUserAutostop,
-		&i.FailureTTL,
-		&i.TimeTilDormant,
-		&i.TimeTilDormantAutoDelete,
-		&i.AutostopRequirementDaysOfWeek,
-		&i.AutostopRequirementWeeks,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
+type UpdateProvisionerJobWithCompleteByIDParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	CompletedAt sql.NullTime   `db:"completed_at" json:"completed_at"`
+	Error       sql.NullString `db:"error" json:"error"`
+	ErrorCode   sql.NullString `db:"error_code" json:"error_code"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCompleteByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.CompletedAt,
+		arg.Error,
+		arg.ErrorCode,
 	)
-	return i, err
+	return err
 }
 
-const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
+const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
 FROM
-	template_with_users AS templates
+	workspace_proxies
 WHERE
-	organization_id = $1
-	AND deleted = $2
-	AND LOWER("name") = LOWER($3)
-LIMIT
-	1
-`
-
-type GetTemplateByOrganizationAndNameParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	Deleted        bool      `db:"deleted" json:"deleted"`
-	Name           string    `db:"name" json:"name"`
-}
-
-func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByOrganizationAndName, arg.OrganizationID, arg.Deleted, arg.Name)
-	var i Template
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
-		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
-		&i.Description,
-		&i.DefaultTTL,
-		&i.CreatedBy,
-		&i.Icon,
-		&i.UserACL,
-		&i.GroupACL,
-		&i.DisplayName,
-		&i.AllowUserCancelWorkspaceJobs,
-		&i.MaxTTL,
-		&i.AllowUserAutostart,
-		&i.AllowUserAutostop,
-		&i.FailureTTL,
-		&i.TimeTilDormant,
-		&i.TimeTilDormantAutoDelete,
-		&i.AutostopRequirementDaysOfWeek,
-		&i.AutostopRequirementWeeks,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-	)
-	return i, err
-}
-
-const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username FROM template_with_users AS templates
-ORDER BY (name, id) ASC
+	deleted = false
 `
 
-func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplates)
+func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Template
+	var items []WorkspaceProxy
 	for rows.Next() {
-		var i Template
+		var i WorkspaceProxy
 		if err := rows.Scan(
 			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Url,
+			&i.WildcardHostname,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.OrganizationID,
 			&i.Deleted,
-			&i.Name,
-			&i.Provisioner,
-			&i.ActiveVersionID,
-			&i.Description,
-			&i.DefaultTTL,
-			&i.CreatedBy,
-			&i.Icon,
-			&i.UserACL,
-			&i.GroupACL,
-			&i.DisplayName,
-			&i.AllowUserCancelWorkspaceJobs,
-			&i.MaxTTL,
-			&i.AllowUserAutostart,
-			&i.AllowUserAutostop,
-			&i.FailureTTL,
-			&i.TimeTilDormant,
-			&i.TimeTilDormantAutoDelete,
-			&i.AutostopRequirementDaysOfWeek,
-			&i.AutostopRequirementWeeks,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
+			&i.TokenHashedSecret,
+			&i.RegionID,
+			&i.DerpEnabled,
+			&i.DerpOnly,
 		); err != nil {
 			return nil, err
 		}
@@ -4593,338 +4850,433 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 	return items, nil
 }
 
-const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
+const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
 FROM
-	template_with_users AS templates
+	workspace_proxies
 WHERE
-	-- Optionally include deleted templates
-	templates.deleted = $1
-	-- Filter by organization_id
-	AND CASE
-		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			organization_id = $2
-		ELSE true
-	END
-	-- Filter by exact name
-	AND CASE
-		WHEN $3 :: text != '' THEN
-			LOWER("name") = LOWER($3)
-		ELSE true
-	END
-	-- Filter by ids
-	AND CASE
-		WHEN array_length($4 :: uuid[], 1) > 0 THEN
-			id = ANY($4)
-		ELSE true
-	END
-  -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
-  -- @authorize_filter
-ORDER BY (name, id) ASC
+	-- Validate that the @hostname has been sanitized and is not empty. This
+	-- doesn't prevent SQL injection (already prevented by using prepared
+	-- queries), but it does prevent carefully crafted hostnames from matching
+	-- when they shouldn't.
+	--
+	-- Periods don't need to be escaped because they're not special characters
+	-- in SQL matches unlike regular expressions.
+	$1 :: text SIMILAR TO '[a-zA-Z0-9._-]+' AND
+	deleted = false AND
+
+	-- Validate that the hostname matches either the wildcard hostname or the
+	-- access URL (ignoring scheme, port and path).
+	(
+		(
+			$2 :: bool = true AND
+			url SIMILAR TO '[^:]*://' || $1 :: text || '([:/]?%)*'
+		) OR
+		(
+			$3 :: bool = true AND
+			$1 :: text LIKE replace(wildcard_hostname, '*', '%')
+		)
+	)
+LIMIT
+	1
 `
 
-type GetTemplatesWithFilterParams struct {
-	Deleted        bool        `db:"deleted" json:"deleted"`
-	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
-	ExactName      string      `db:"exact_name" json:"exact_name"`
-	IDs            []uuid.UUID `db:"ids" json:"ids"`
+type GetWorkspaceProxyByHostnameParams struct {
+	Hostname              string `db:"hostname" json:"hostname"`
+	AllowAccessUrl        bool   `db:"allow_access_url" json:"allow_access_url"`
+	AllowWildcardHostname bool   `db:"allow_wildcard_hostname" json:"allow_wildcard_hostname"`
 }
 
-func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplatesWithFilter,
-		arg.Deleted,
-		arg.OrganizationID,
-		arg.ExactName,
-		pq.Array(arg.IDs),
+// Finds a workspace proxy that has an access URL or app hostname that matches
+// the provided hostname. This is to check if a hostname matches any workspace
+// proxy.
+//
+// The hostname must be sanitized to only contain [a-zA-Z0-9.-] before calling
+// this query. The scheme, port and path should be stripped.
+func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByHostname, arg.Hostname, arg.AllowAccessUrl, arg.AllowWildcardHostname)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Template
-	for rows.Next() {
-		var i Template
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.OrganizationID,
-			&i.Deleted,
-			&i.Name,
-			&i.Provisioner,
-			&i.ActiveVersionID,
-			&i.Description,
-			&i.DefaultTTL,
-			&i.CreatedBy,
-			&i.Icon,
-			&i.UserACL,
-			&i.GroupACL,
-			&i.DisplayName,
-			&i.AllowUserCancelWorkspaceJobs,
-			&i.MaxTTL,
-			&i.AllowUserAutostart,
-			&i.AllowUserAutostop,
-			&i.FailureTTL,
-			&i.TimeTilDormant,
-			&i.TimeTilDormantAutoDelete,
-			&i.AutostopRequirementDaysOfWeek,
-			&i.AutostopRequirementWeeks,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return i, err
 }
 
-const insertTemplate = `-- name: InsertTemplate :exec
+const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+FROM
+	workspace_proxies
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByID, id)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+	)
+	return i, err
+}
+
+const getWorkspaceProxyByName = `-- name: GetWorkspaceProxyByName :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+FROM
+	workspace_proxies
+WHERE
+	name = $1
+	AND deleted = false
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByName, name)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+	)
+	return i, err
+}
+
+const insertWorkspaceProxy = `-- name: InsertWorkspaceProxy :one
 INSERT INTO
-	templates (
+	workspace_proxies (
 		id,
+		url,
+		wildcard_hostname,
+		name,
+		display_name,
+		icon,
+		derp_enabled,
+		derp_only,
+		token_hashed_secret,
 		created_at,
 		updated_at,
-		organization_id,
-		"name",
-		provisioner,
-		active_version_id,
-		description,
-		created_by,
-		icon,
-		user_acl,
-		group_acl,
-		display_name,
-		allow_user_cancel_workspace_jobs
+		deleted
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
 `
 
-type InsertTemplateParams struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                    time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID               uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Name                         string          `db:"name" json:"name"`
-	Provisioner                  ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID              uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                  string          `db:"description" json:"description"`
-	CreatedBy                    uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                         string          `db:"icon" json:"icon"`
-	UserACL                      TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+type InsertWorkspaceProxyParams struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	Name              string    `db:"name" json:"name"`
+	DisplayName       string    `db:"display_name" json:"display_name"`
+	Icon              string    `db:"icon" json:"icon"`
+	DerpEnabled       bool      `db:"derp_enabled" json:"derp_enabled"`
+	DerpOnly          bool      `db:"derp_only" json:"derp_only"`
+	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplate,
+func (q *sqlQuerier) InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceProxy,
 		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.Icon,
+		arg.DerpEnabled,
+		arg.DerpOnly,
+		arg.TokenHashedSecret,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.OrganizationID,
-		arg.Name,
-		arg.Provisioner,
-		arg.ActiveVersionID,
-		arg.Description,
-		arg.CreatedBy,
-		arg.Icon,
-		arg.UserACL,
-		arg.GroupACL,
-		arg.DisplayName,
-		arg.AllowUserCancelWorkspaceJobs,
 	)
-	return err
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+	)
+	return i, err
 }
 
-const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
+const registerWorkspaceProxy = `-- name: RegisterWorkspaceProxy :one
 UPDATE
-	templates
+	workspace_proxies
 SET
-	group_acl = $1,
-	user_acl = $2
+	url = $1 :: text,
+	wildcard_hostname = $2 :: text,
+	derp_enabled = $3 :: boolean,
+	derp_only = $4 :: boolean,
+	updated_at = Now()
 WHERE
-	id = $3
+	id = $5
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
 `
 
-type UpdateTemplateACLByIDParams struct {
-	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
-	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
-	ID       uuid.UUID   `db:"id" json:"id"`
+type RegisterWorkspaceProxyParams struct {
+	Url              string    `db:"url" json:"url"`
+	WildcardHostname string    `db:"wildcard_hostname" json:"wildcard_hostname"`
+	DerpEnabled      bool      `db:"derp_enabled" json:"derp_enabled"`
+	DerpOnly         bool      `db:"derp_only" json:"derp_only"`
+	ID               uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
-	return err
+func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, registerWorkspaceProxy,
+		arg.Url,
+		arg.WildcardHostname,
+		arg.DerpEnabled,
+		arg.DerpOnly,
+		arg.ID,
+	)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+	)
+	return i, err
 }
 
-const updateTemplateActiveVersionByID = `-- name: UpdateTemplateActiveVersionByID :exec
+const updateWorkspaceProxy = `-- name: UpdateWorkspaceProxy :one
 UPDATE
-	templates
+	workspace_proxies
 SET
-	active_version_id = $2,
-	updated_at = $3
+	-- These values should always be provided.
+	name = $1,
+	display_name = $2,
+	icon = $3,
+	-- Only update the token if a new one is provided.
+	-- So this is an optional field.
+	token_hashed_secret = CASE
+		WHEN length($4 :: bytea) > 0  THEN $4 :: bytea
+		ELSE  workspace_proxies.token_hashed_secret
+	END,
+	-- Always update this timestamp.
+	updated_at = Now()
 WHERE
-	id = $1
+	id = $5
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
 `
 
-type UpdateTemplateActiveVersionByIDParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+type UpdateWorkspaceProxyParams struct {
+	Name              string    `db:"name" json:"name"`
+	DisplayName       string    `db:"display_name" json:"display_name"`
+	Icon              string    `db:"icon" json:"icon"`
+	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
+	ID                uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateActiveVersionByID, arg.ID, arg.ActiveVersionID, arg.UpdatedAt)
-	return err
+// This allows editing the properties of a workspace proxy.
+func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceProxy,
+		arg.Name,
+		arg.DisplayName,
+		arg.Icon,
+		arg.TokenHashedSecret,
+		arg.ID,
+	)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+	)
+	return i, err
 }
 
-const updateTemplateDeletedByID = `-- name: UpdateTemplateDeletedByID :exec
+const updateWorkspaceProxyDeleted = `-- name: UpdateWorkspaceProxyDeleted :exec
 UPDATE
-	templates
+	workspace_proxies
 SET
-	deleted = $2,
-	updated_at = $3
+	updated_at = Now(),
+	deleted = $1
 WHERE
-	id = $1
+	id = $2
 `
 
-type UpdateTemplateDeletedByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Deleted   bool      `db:"deleted" json:"deleted"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+type UpdateWorkspaceProxyDeletedParams struct {
+	Deleted bool      `db:"deleted" json:"deleted"`
+	ID      uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateDeletedByID, arg.ID, arg.Deleted, arg.UpdatedAt)
+func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceProxyDeleted, arg.Deleted, arg.ID)
 	return err
 }
 
-const updateTemplateMetaByID = `-- name: UpdateTemplateMetaByID :exec
-UPDATE
-	templates
-SET
-	updated_at = $2,
-	description = $3,
-	name = $4,
-	icon = $5,
-	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
+SELECT
+	coalesce(SUM(quota_allowance), 0)::BIGINT
+FROM
+	groups g
+LEFT JOIN group_members gm ON
+	g.id = gm.group_id
 WHERE
-	id = $1
+	user_id = $1
+OR
+    g.id = g.organization_id
 `
 
-type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                    time.Time `db:"updated_at" json:"updated_at"`
-	Description                  string    `db:"description" json:"description"`
-	Name                         string    `db:"name" json:"name"`
-	Icon                         string    `db:"icon" json:"icon"`
-	DisplayName                  string    `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool      `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, userID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
 }
 
-func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateMetaByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.Description,
-		arg.Name,
-		arg.Icon,
-		arg.DisplayName,
-		arg.AllowUserCancelWorkspaceJobs,
-	)
+const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.owner_id = $1
+`
+
+func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
+DELETE FROM replicas WHERE updated_at < $1
+`
+
+func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
 	return err
 }
 
-const updateTemplateScheduleByID = `-- name: UpdateTemplateScheduleByID :exec
-UPDATE
-	templates
-SET
-	updated_at = $2,
-	allow_user_autostart = $3,
-	allow_user_autostop = $4,
-	default_ttl = $5,
-	max_ttl = $6,
-	autostop_requirement_days_of_week = $7,
-	autostop_requirement_weeks = $8,
-	failure_ttl = $9,
-	time_til_dormant = $10,
-	time_til_dormant_autodelete = $11
-WHERE
-	id = $1
+const getReplicaByID = `-- name: GetReplicaByID :one
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
 `
 
-type UpdateTemplateScheduleByIDParams struct {
-	ID                            uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                     time.Time `db:"updated_at" json:"updated_at"`
-	AllowUserAutostart            bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
-	DefaultTTL                    int64     `db:"default_ttl" json:"default_ttl"`
-	MaxTTL                        int64     `db:"max_ttl" json:"max_ttl"`
-	AutostopRequirementDaysOfWeek int16     `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64     `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-}
-
-func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateScheduleByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.AllowUserAutostart,
-		arg.AllowUserAutostop,
-		arg.DefaultTTL,
-		arg.MaxTTL,
-		arg.AutostopRequirementDaysOfWeek,
-		arg.AutostopRequirementWeeks,
-		arg.FailureTTL,
-		arg.TimeTilDormant,
-		arg.TimeTilDormantAutoDelete,
+func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+		&i.Primary,
 	)
-	return err
+	return i, err
 }
 
-const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
-SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
 `
 
-func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
+func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
+	rows, err := q.db.QueryContext(ctx, getReplicasUpdatedAfter, updatedAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionParameter
+	var items []Replica
 	for rows.Next() {
-		var i TemplateVersionParameter
+		var i Replica
 		if err := rows.Scan(
-			&i.TemplateVersionID,
-			&i.Name,
-			&i.Description,
-			&i.Type,
-			&i.Mutable,
-			&i.DefaultValue,
-			&i.Icon,
-			&i.Options,
-			&i.ValidationRegex,
-			&i.ValidationMin,
-			&i.ValidationMax,
-			&i.ValidationError,
-			&i.ValidationMonotonic,
-			&i.Required,
-			&i.DisplayName,
-			&i.DisplayOrder,
-			&i.Ephemeral,
+			&i.ID,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.UpdatedAt,
+			&i.Hostname,
+			&i.RegionID,
+			&i.RelayAddress,
+			&i.DatabaseLatency,
+			&i.Version,
+			&i.Error,
+			&i.Primary,
 		); err != nil {
 			return nil, err
 		}
@@ -4939,368 +5291,401 @@ func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateV
 	return items, nil
 }
 
-const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
-INSERT INTO
-    template_version_parameters (
-        template_version_id,
-        name,
-        description,
-        type,
-        mutable,
-        default_value,
-        icon,
-        options,
-        validation_regex,
-        validation_min,
-        validation_max,
-        validation_error,
-        validation_monotonic,
-        required,
-        display_name,
-        display_order,
-        ephemeral
-    )
-VALUES
-    (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8,
-        $9,
-        $10,
-        $11,
-        $12,
-        $13,
-        $14,
-        $15,
-        $16,
-        $17
-    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral
+const insertReplica = `-- name: InsertReplica :one
+INSERT INTO replicas (
+    id,
+    created_at,
+    started_at,
+    updated_at,
+    hostname,
+    region_id,
+    relay_address,
+    version,
+    database_latency,
+	"primary"
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
 `
 
-type InsertTemplateVersionParameterParams struct {
-	TemplateVersionID   uuid.UUID       `db:"template_version_id" json:"template_version_id"`
-	Name                string          `db:"name" json:"name"`
-	Description         string          `db:"description" json:"description"`
-	Type                string          `db:"type" json:"type"`
-	Mutable             bool            `db:"mutable" json:"mutable"`
-	DefaultValue        string          `db:"default_value" json:"default_value"`
-	Icon                string          `db:"icon" json:"icon"`
-	Options             json.RawMessage `db:"options" json:"options"`
-	ValidationRegex     string          `db:"validation_regex" json:"validation_regex"`
-	ValidationMin       sql.NullInt32   `db:"validation_min" json:"validation_min"`
-	ValidationMax       sql.NullInt32   `db:"validation_max" json:"validation_max"`
-	ValidationError     string          `db:"validation_error" json:"validation_error"`
-	ValidationMonotonic string          `db:"validation_monotonic" json:"validation_monotonic"`
-	Required            bool            `db:"required" json:"required"`
-	DisplayName         string          `db:"display_name" json:"display_name"`
-	DisplayOrder        int32           `db:"display_order" json:"display_order"`
-	Ephemeral           bool            `db:"ephemeral" json:"ephemeral"`
+type InsertReplicaParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	StartedAt       time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Hostname        string    `db:"hostname" json:"hostname"`
+	RegionID        int32     `db:"region_id" json:"region_id"`
+	RelayAddress    string    `db:"relay_address" json:"relay_address"`
+	Version         string    `db:"version" json:"version"`
+	DatabaseLatency int32     `db:"database_latency" json:"database_latency"`
+	Primary         bool      `db:"primary" json:"primary"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
-		arg.TemplateVersionID,
-		arg.Name,
-		arg.Description,
-		arg.Type,
-		arg.Mutable,
-		arg.DefaultValue,
-		arg.Icon,
-		arg.Options,
-		arg.ValidationRegex,
-		arg.ValidationMin,
-		arg.ValidationMax,
-		arg.ValidationError,
-		arg.ValidationMonotonic,
-		arg.Required,
-		arg.DisplayName,
-		arg.DisplayOrder,
-		arg.Ephemeral,
+func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, insertReplica,
+		arg.ID,
+		arg.CreatedAt,
+		arg.StartedAt,
+		arg.UpdatedAt,
+		arg.Hostname,
+		arg.RegionID,
+		arg.RelayAddress,
+		arg.Version,
+		arg.DatabaseLatency,
+		arg.Primary,
 	)
-	var i TemplateVersionParameter
+	var i Replica
 	err := row.Scan(
-		&i.TemplateVersionID,
-		&i.Name,
-		&i.Description,
-		&i.Type,
-		&i.Mutable,
-		&i.DefaultValue,
-		&i.Icon,
-		&i.Options,
-		&i.ValidationRegex,
-		&i.ValidationMin,
-		&i.ValidationMax,
-		&i.ValidationError,
-		&i.ValidationMonotonic,
-		&i.Required,
-		&i.DisplayName,
-		&i.DisplayOrder,
-		&i.Ephemeral,
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+		&i.Primary,
 	)
 	return i, err
 }
 
-const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	created_at < (
-		SELECT created_at
-		FROM template_version_with_user AS tv
-		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
-	)
-	AND organization_id = $1
-	AND template_id = $3
-ORDER BY created_at DESC
-LIMIT 1
+const updateReplica = `-- name: UpdateReplica :one
+UPDATE replicas SET
+    updated_at = $2,
+    started_at = $3,
+    stopped_at = $4,
+    relay_address = $5,
+    region_id = $6,
+    hostname = $7,
+    version = $8,
+    error = $9,
+    database_latency = $10,
+	"primary" = $11
+WHERE id = $1 RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
 `
 
-type GetPreviousTemplateVersionParams struct {
-	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
-	Name           string        `db:"name" json:"name"`
-	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+type UpdateReplicaParams struct {
+	ID              uuid.UUID    `db:"id" json:"id"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
+	StartedAt       time.Time    `db:"started_at" json:"started_at"`
+	StoppedAt       sql.NullTime `db:"stopped_at" json:"stopped_at"`
+	RelayAddress    string       `db:"relay_address" json:"relay_address"`
+	RegionID        int32        `db:"region_id" json:"region_id"`
+	Hostname        string       `db:"hostname" json:"hostname"`
+	Version         string       `db:"version" json:"version"`
+	Error           string       `db:"error" json:"error"`
+	DatabaseLatency int32        `db:"database_latency" json:"database_latency"`
+	Primary         bool         `db:"primary" json:"primary"`
 }
 
-func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
-	var i TemplateVersion
+func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, updateReplica,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.StartedAt,
+		arg.StoppedAt,
+		arg.RelayAddress,
+		arg.RegionID,
+		arg.Hostname,
+		arg.Version,
+		arg.Error,
+		arg.DatabaseLatency,
+		arg.Primary,
+	)
+	var i Replica
 	err := row.Scan(
 		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
 		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
 		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.GitAuthProviders),
-		&i.Message,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+		&i.Primary,
 	)
 	return i, err
 }
 
-const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	id = $1
+const getAppSecurityKey = `-- name: GetAppSecurityKey :one
+SELECT value FROM site_configs WHERE key = 'app_signing_key'
 `
 
-func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
-	var i TemplateVersion
-	err := row.Scan(
-		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.GitAuthProviders),
-		&i.Message,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-	)
-	return i, err
+func (q *sqlQuerier) GetAppSecurityKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAppSecurityKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
 }
 
-const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	job_id = $1
+const getDERPMeshKey = `-- name: GetDERPMeshKey :one
+SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
 `
 
-func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
-	var i TemplateVersion
-	err := row.Scan(
-		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.GitAuthProviders),
-		&i.Message,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-	)
-	return i, err
+func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDERPMeshKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
 }
 
-const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
+const getDefaultProxyConfig = `-- name: GetDefaultProxyConfig :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	template_id = $1
-	AND "name" = $2
+	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_display_name'), 'Default') :: text AS display_name,
+	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_icon_url'), '/emojis/1f3e1.png') :: text AS icon_url
 `
 
-type GetTemplateVersionByTemplateIDAndNameParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	Name       string        `db:"name" json:"name"`
+type GetDefaultProxyConfigRow struct {
+	DisplayName string `db:"display_name" json:"display_name"`
+	IconUrl     string `db:"icon_url" json:"icon_url"`
 }
 
-func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
-	var i TemplateVersion
-	err := row.Scan(
-		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.GitAuthProviders),
-		&i.Message,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
-	)
+func (q *sqlQuerier) GetDefaultProxyConfig(ctx context.Context) (GetDefaultProxyConfigRow, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultProxyConfig)
+	var i GetDefaultProxyConfigRow
+	err := row.Scan(&i.DisplayName, &i.IconUrl)
 	return i, err
 }
 
-const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	id = ANY($1 :: uuid [ ])
+const getDeploymentID = `-- name: GetDeploymentID :one
+SELECT value FROM site_configs WHERE key = 'deployment_id'
 `
 
-func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TemplateVersion
-	for rows.Next() {
-		var i TemplateVersion
-		if err := rows.Scan(
-			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			pq.Array(&i.GitAuthProviders),
-			&i.Message,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) GetDeploymentID(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDeploymentID)
+	var value string
+	err := row.Scan(&value)
+	return value, err
 }
 
-const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
-WHERE
-	template_id = $1 :: uuid
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the created_at field, so select all
-			-- rows after the cursor.
-			(created_at, id) > (
-				SELECT
-					created_at, id
-				FROM
-					template_versions
-				WHERE
-					id = $2
-			)
-		)
-		ELSE true
-	END
-ORDER BY
-    -- Deterministic and consistent ordering of all rows, even if they share
-    -- a timestamp. This is to ensure consistent pagination.
-	(created_at, id) ASC OFFSET $3
-LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($4 :: int, 0)
+const getLastUpdateCheck = `-- name: GetLastUpdateCheck :one
+SELECT value FROM site_configs WHERE key = 'last_update_check'
 `
 
-type GetTemplateVersionsByTemplateIDParams struct {
-	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
-	AfterID    uuid.UUID `db:"after_id" json:"after_id"`
-	OffsetOpt  int32     `db:"offset_opt" json:"offset_opt"`
-	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+func (q *sqlQuerier) GetLastUpdateCheck(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLastUpdateCheck)
+	var value string
+	err := row.Scan(&value)
+	return value, err
 }
 
-func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
-		arg.TemplateID,
-		arg.AfterID,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
-	if err != nil {
+const getLogoURL = `-- name: GetLogoURL :one
+SELECT value FROM site_configs WHERE key = 'logo_url'
+`
+
+func (q *sqlQuerier) GetLogoURL(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLogoURL)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getOAuthSigningKey = `-- name: GetOAuthSigningKey :one
+SELECT value FROM site_configs WHERE key = 'oauth_signing_key'
+`
+
+func (q *sqlQuerier) GetOAuthSigningKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getOAuthSigningKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getServiceBanner = `-- name: GetServiceBanner :one
+SELECT value FROM site_configs WHERE key = 'service_banner'
+`
+
+func (q *sqlQuerier) GetServiceBanner(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getServiceBanner)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const insertDERPMeshKey = `-- name: InsertDERPMeshKey :exec
+INSERT INTO site_configs (key, value) VALUES ('derp_mesh_key', $1)
+`
+
+func (q *sqlQuerier) InsertDERPMeshKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, insertDERPMeshKey, value)
+	return err
+}
+
+const insertDeploymentID = `-- name: InsertDeploymentID :exec
+INSERT INTO site_configs (key, value) VALUES ('deployment_id', $1)
+`
+
+func (q *sqlQuerier) InsertDeploymentID(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, insertDeploymentID, value)
+	return err
+}
+
+const upsertAppSecurityKey = `-- name: UpsertAppSecurityKey :exec
+INSERT INTO site_configs (key, value) VALUES ('app_signing_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'app_signing_key'
+`
+
+func (q *sqlQuerier) UpsertAppSecurityKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertAppSecurityKey, value)
+	return err
+}
+
+const upsertDefaultProxy = `-- name: UpsertDefaultProxy :exec
+INSERT INTO site_configs (key, value)
+VALUES
+    ('default_proxy_display_name', $1 :: text),
+    ('default_proxy_icon_url', $2 :: text)
+ON CONFLICT
+    (key)
+DO UPDATE SET value = EXCLUDED.value WHERE site_configs.key = EXCLUDED.key
+`
+
+type UpsertDefaultProxyParams struct {
+	DisplayName string `db:"display_name" json:"display_name"`
+	IconUrl     string `db:"icon_url" json:"icon_url"`
+}
+
+// The default proxy is implied and not actually stored in the database.
+// So we need to store it's configuration here for display purposes.
+// The functional values are immutable and controlled implicitly.
+func (q *sqlQuerier) UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error {
+	_, err := q.db.ExecContext(ctx, upsertDefaultProxy, arg.DisplayName, arg.IconUrl)
+	return err
+}
+
+const upsertLastUpdateCheck = `-- name: UpsertLastUpdateCheck :exec
+INSERT INTO site_configs (key, value) VALUES ('last_update_check', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update_check'
+`
+
+func (q *sqlQuerier) UpsertLastUpdateCheck(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertLastUpdateCheck, value)
+	return err
+}
+
+const upsertLogoURL = `-- name: UpsertLogoURL :exec
+INSERT INTO site_configs (key, value) VALUES ('logo_url', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'logo_url'
+`
+
+func (q *sqlQuerier) UpsertLogoURL(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertLogoURL, value)
+	return err
+}
+
+const upsertOAuthSigningKey = `-- name: UpsertOAuthSigningKey :exec
+INSERT INTO site_configs (key, value) VALUES ('oauth_signing_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'oauth_signing_key'
+`
+
+func (q *sqlQuerier) UpsertOAuthSigningKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertOAuthSigningKey, value)
+	return err
+}
+
+const upsertServiceBanner = `-- name: UpsertServiceBanner :exec
+INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
+`
+
+func (q *sqlQuerier) UpsertServiceBanner(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertServiceBanner, value)
+	return err
+}
+
+const cleanTailnetCoordinators = `-- name: CleanTailnetCoordinators :exec
+DELETE
+FROM tailnet_coordinators
+WHERE heartbeat_at < now() - INTERVAL '24 HOURS'
+`
+
+func (q *sqlQuerier) CleanTailnetCoordinators(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, cleanTailnetCoordinators)
+	return err
+}
+
+const deleteCoordinator = `-- name: DeleteCoordinator :exec
+DELETE
+FROM tailnet_coordinators
+WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteCoordinator, id)
+	return err
+}
+
+const deleteTailnetAgent = `-- name: DeleteTailnetAgent :one
+DELETE
+FROM tailnet_agents
+WHERE id = $1 and coordinator_id = $2
+RETURNING id, coordinator_id
+`
+
+type DeleteTailnetAgentParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+type DeleteTailnetAgentRow struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error) {
+	row := q.db.QueryRowContext(ctx, deleteTailnetAgent, arg.ID, arg.CoordinatorID)
+	var i DeleteTailnetAgentRow
+	err := row.Scan(&i.ID, &i.CoordinatorID)
+	return i, err
+}
+
+const deleteTailnetClient = `-- name: DeleteTailnetClient :one
+DELETE
+FROM tailnet_clients
+WHERE id = $1 and coordinator_id = $2
+RETURNING id, coordinator_id
+`
+
+type DeleteTailnetClientParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+type DeleteTailnetClientRow struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error) {
+	row := q.db.QueryRowContext(ctx, deleteTailnetClient, arg.ID, arg.CoordinatorID)
+	var i DeleteTailnetClientRow
+	err := row.Scan(&i.ID, &i.CoordinatorID)
+	return i, err
+}
+
+const getAllTailnetAgents = `-- name: GetAllTailnetAgents :many
+SELECT id, coordinator_id, updated_at, node
+FROM tailnet_agents
+`
+
+func (q *sqlQuerier) GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTailnetAgents)
+	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []TailnetAgent
 	for rows.Next() {
-		var i TemplateVersion
+		var i TailnetAgent
 		if err := rows.Scan(
 			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
+			&i.CoordinatorID,
 			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			pq.Array(&i.GitAuthProviders),
-			&i.Message,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
+			&i.Node,
 		); err != nil {
 			return nil, err
 		}
@@ -5315,33 +5700,28 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 	return items, nil
 }
 
-const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
-SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username FROM template_version_with_user AS template_versions WHERE created_at > $1
+const getAllTailnetClients = `-- name: GetAllTailnetClients :many
+SELECT id, coordinator_id, agent_id, updated_at, node, user_id
+FROM tailnet_clients
+ORDER BY agent_id
 `
 
-func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
+func (q *sqlQuerier) GetAllTailnetClients(ctx context.Context) ([]TailnetClient, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTailnetClients)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []TailnetClient
 	for rows.Next() {
-		var i TemplateVersion
+		var i TailnetClient
 		if err := rows.Scan(
 			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
+			&i.CoordinatorID,
+			&i.AgentID,
 			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			pq.Array(&i.GitAuthProviders),
-			&i.Message,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
+			&i.Node,
+			&i.UserID,
 		); err != nil {
 			return nil, err
 		}
@@ -5356,148 +5736,2075 @@ func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, create
 	return items, nil
 }
 
-const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
-INSERT INTO
-	template_versions (
-		id,
-		template_id,
-		organization_id,
-		created_at,
-		updated_at,
-		"name",
-		message,
-		readme,
-		job_id,
-		created_by
+const getTailnetAgents = `-- name: GetTailnetAgents :many
+SELECT id, coordinator_id, updated_at, node
+FROM tailnet_agents
+WHERE id = $1
+`
+
+func (q *sqlQuerier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetAgents, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetAgent
+	for rows.Next() {
+		var i TailnetAgent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.UpdatedAt,
+			&i.Node,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTailnetClientsForAgent = `-- name: GetTailnetClientsForAgent :many
+SELECT id, coordinator_id, agent_id, updated_at, node, user_id
+FROM tailnet_clients
+WHERE agent_id = $1
+`
+
+func (q *sqlQuerier) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetClientsForAgent, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetClient
+	for rows.Next() {
+		var i TailnetClient
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.AgentID,
+			&i.UpdatedAt,
+			&i.Node,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTailnetAgent = `-- name: UpsertTailnetAgent :one
+INSERT INTO
+	tailnet_agents (
+	id,
+	coordinator_id,
+	node,
+	updated_at
+)
+VALUES
+	($1, $2, $3, now() at time zone 'utc')
+ON CONFLICT (id, coordinator_id)
+DO UPDATE SET
+	id = $1,
+	coordinator_id = $2,
+	node = $3,
+	updated_at = now() at time zone 'utc'
+RETURNING id, coordinator_id, updated_at, node
+`
+
+type UpsertTailnetAgentParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
+	Node          json.RawMessage `db:"node" json:"node"`
+}
+
+func (q *sqlQuerier) UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetAgent, arg.ID, arg.CoordinatorID, arg.Node)
+	var i TailnetAgent
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.UpdatedAt,
+		&i.Node,
 	)
+	return i, err
+}
+
+const upsertTailnetClient = `-- name: UpsertTailnetClient :one
+INSERT INTO
+	tailnet_clients (
+	id,
+	coordinator_id,
+	agent_id,
+	node,
+	user_id,
+	updated_at
+)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	($1, $2, $3, $4, $5, now() at time zone 'utc')
+ON CONFLICT (id, coordinator_id)
+DO UPDATE SET
+	id = $1,
+	coordinator_id = $2,
+	agent_id = $3,
+	node = $4,
+	user_id = $5,
+	updated_at = now() at time zone 'utc'
+RETURNING id, coordinator_id, agent_id, updated_at, node, user_id
 `
 
-type InsertTemplateVersionParams struct {
-	ID             uuid.UUID     `db:"id" json:"id"`
-	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
-	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
-	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
-	Name           string        `db:"name" json:"name"`
-	Message        string        `db:"message" json:"message"`
-	Readme         string        `db:"readme" json:"readme"`
-	JobID          uuid.UUID     `db:"job_id" json:"job_id"`
-	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
+type UpsertTailnetClientParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
+	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
+	Node          json.RawMessage `db:"node" json:"node"`
+	UserID        uuid.NullUUID   `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
+func (q *sqlQuerier) UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetClient,
 		arg.ID,
-		arg.TemplateID,
-		arg.OrganizationID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.Name,
-		arg.Message,
-		arg.Readme,
-		arg.JobID,
-		arg.CreatedBy,
+		arg.CoordinatorID,
+		arg.AgentID,
+		arg.Node,
+		arg.UserID,
 	)
-	return err
+	var i TailnetClient
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.AgentID,
+		&i.UpdatedAt,
+		&i.Node,
+		&i.UserID,
+	)
+	return i, err
 }
 
-const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
-UPDATE
-	template_versions
-SET
-	template_id = $2,
-	updated_at = $3,
-	name = $4,
-	message = $5
+const upsertTailnetCoordinator = `-- name: UpsertTailnetCoordinator :one
+INSERT INTO
+	tailnet_coordinators (
+	id,
+	heartbeat_at
+)
+VALUES
+	($1, now() at time zone 'utc')
+ON CONFLICT (id)
+DO UPDATE SET
+  id = $1,
+  heartbeat_at = now() at time zone 'utc'
+RETURNING id, heartbeat_at
+`
+
+func (q *sqlQuerier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetCoordinator, id)
+	var i TailnetCoordinator
+	err := row.Scan(&i.ID, &i.HeartbeatAt)
+	return i, err
+}
+
+const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
+WITH build_times AS (
+SELECT
+	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
+	workspace_builds.transition
+FROM
+	workspace_builds
+JOIN template_versions ON
+	workspace_builds.template_version_id = template_versions.id
+JOIN provisioner_jobs pj ON
+	workspace_builds.job_id = pj.id
 WHERE
-	id = $1
+	template_versions.template_id = $1 AND
+		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
+		(pj.started_at > $2) AND
+		(pj.canceled_at IS NULL) AND
+		((pj.error IS NULL) OR (pj.error = ''))
+ORDER BY
+	workspace_builds.created_at DESC
+)
+SELECT
+	-- Postgres offers no clear way to DRY this short of a function or other
+	-- complexities.
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
+FROM build_times
 `
 
-type UpdateTemplateVersionByIDParams struct {
-	ID         uuid.UUID     `db:"id" json:"id"`
+type GetTemplateAverageBuildTimeParams struct {
 	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
-	Name       string        `db:"name" json:"name"`
-	Message    string        `db:"message" json:"message"`
+	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
-		arg.ID,
-		arg.TemplateID,
-		arg.UpdatedAt,
-		arg.Name,
-		arg.Message,
-	)
-	return err
+type GetTemplateAverageBuildTimeRow struct {
+	Start50  float64 `db:"start_50" json:"start_50"`
+	Stop50   float64 `db:"stop_50" json:"stop_50"`
+	Delete50 float64 `db:"delete_50" json:"delete_50"`
+	Start95  float64 `db:"start_95" json:"start_95"`
+	Stop95   float64 `db:"stop_95" json:"stop_95"`
+	Delete95 float64 `db:"delete_95" json:"delete_95"`
 }
 
-const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
-UPDATE
-	template_versions
+func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
+	var i GetTemplateAverageBuildTimeRow
+	err := row.Scan(
+		&i.Start50,
+		&i.Stop50,
+		&i.Delete50,
+		&i.Start95,
+		&i.Stop95,
+		&i.Delete95,
+	)
+	return i, err
+}
+
+const getTemplateByID = `-- name: GetTemplateByID :one
+SELECT
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, max_port_sharing_level, record_sessions, deprecation_message, deprecated_at, require_active_version, created_by_avatar_url, created_by_username
+FROM
+	template_with_users
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
+		&i.CreatedBy,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.MaxTTL,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
+		&i.TimeTilDormant,
+		&i.TimeTilDormantAutoDelete,
+		&i.AutostopRequirementDaysOfWeek,
+		&i.AutostopRequirementWeeks,
+		&i.MaxPortSharingLevel,
+		&i.RecordSessions,
+		&i.DeprecationMessage,
+		&i.DeprecatedAt,
+		&i.RequireActiveVersion,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
+	return i, err
+}
+
+const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
+SELECT
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, max_port_sharing_level, record_sessions, deprecation_message, deprecated_at, require_active_version, created_by_avatar_url, created_by_username
+FROM
+	template_with_users AS templates
+WHERE
+	organization_id = $1
+	AND deleted = $2
+	AND LOWER("name") = LOWER($3)
+LIMIT
+	1
+`
+
+type GetTemplateByOrganizationAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Deleted        bool      `db:"deleted" json:"deleted"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByOrganizationAndName, arg.OrganizationID, arg.Deleted, arg.Name)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
+		&i.CreatedBy,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.MaxTTL,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
+		&i.TimeTilDormant,
+		&i.TimeTilDormantAutoDelete,
+		&i.AutostopRequirementDaysOfWeek,
+		&i.AutostopRequirementWeeks,
+		&i.MaxPortSharingLevel,
+		&i.RecordSessions,
+		&i.DeprecationMessage,
+		&i.DeprecatedAt,
+		&i.RequireActiveVersion,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
+	return i, err
+}
+
+const getTemplates = `-- name: GetTemplates :many
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, max_port_sharing_level, record_sessions, deprecation_message, deprecated_at, require_active_version, created_by_avatar_url, created_by_username FROM template_with_users AS templates
+ORDER BY (name, id) ASC
+`
+
+func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Template
+	for rows.Next() {
+		var i Template
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.Deleted,
+			&i.Name,
+			&i.Provisioner,
+			&i.ActiveVersionID,
+			&i.Description,
+			&i.DefaultTTL,
+			&i.CreatedBy,
+			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.DisplayName,
+			&i.AllowUserCancelWorkspaceJobs,
+			&i.MaxTTL,
+			&i.AllowUserAutostart,
+			&i.AllowUserAutostop,
+			&i.FailureTTL,
+			&i.TimeTilDormant,
+			&i.TimeTilDormantAutoDelete,
+			&i.AutostopRequirementDaysOfWeek,
+			&i.AutostopRequirementWeeks,
+			&i.MaxPortSharingLevel,
+			&i.RecordSessions,
+			&i.DeprecationMessage,
+			&i.DeprecatedAt,
+			&i.RequireActiveVersion,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
+SELECT
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, max_port_sharing_level, record_sessions, deprecation_message, deprecated_at, require_active_version, created_by_avatar_url, created_by_username
+FROM
+	template_with_users AS templates
+WHERE
+	-- Optionally include deleted templates
+	templates.deleted = $1
+	-- Filter by organization_id
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			organization_id = $2
+		ELSE true
+	END
+	-- Filter by exact name
+	AND CASE
+		WHEN $3 :: text != '' THEN
+			LOWER("name") = LOWER($3)
+		ELSE true
+	END
+	-- Filter by name, matching on substring
+	AND CASE
+		WHEN $4 :: text != '' THEN
+			LOWER("name") LIKE '%' || LOWER($4) || '%'
+		ELSE true
+	END
+	-- Filter by ids
+	AND CASE
+		WHEN array_length($5 :: uuid[], 1) > 0 THEN
+			id = ANY($5)
+		ELSE true
+	END
+	-- Filter by deprecation
+	AND CASE
+		WHEN $6 :: boolean IS NOT NULL THEN
+			(deprecation_message != '') = $6 :: boolean
+		ELSE true
+	END
+  -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
+  -- @authorize_filter
+ORDER BY (name, id) ASC
+`
+
+type GetTemplatesWithFilterParams struct {
+	Deleted        bool         `db:"deleted" json:"deleted"`
+	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
+	ExactName      string       `db:"exact_name" json:"exact_name"`
+	FuzzyName      string       `db:"fuzzy_name" json:"fuzzy_name"`
+	IDs            []uuid.UUID  `db:"ids" json:"ids"`
+	Deprecated     sql.NullBool `db:"deprecated" json:"deprecated"`
+}
+
+func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplatesWithFilter,
+		arg.Deleted,
+		arg.OrganizationID,
+		arg.ExactName,
+		arg.FuzzyName,
+		pq.Array(arg.IDs),
+		arg.Deprecated,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Template
+	for rows.Next() {
+		var i Template
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.Deleted,
+			&i.Name,
+			&i.Provisioner,
+			&i.ActiveVersionID,
+			&i.Description,
+			&i.DefaultTTL,
+			&i.CreatedBy,
+			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.DisplayName,
+			&i.AllowUserCancelWorkspaceJobs,
+			&i.MaxTTL,
+			&i.AllowUserAutostart,
+			&i.AllowUserAutostop,
+			&i.FailureTTL,
+			&i.TimeTilDormant,
+			&i.TimeTilDormantAutoDelete,
+			&i.AutostopRequirementDaysOfWeek,
+			&i.AutostopRequirementWeeks,
+			&i.MaxPortSharingLevel,
+			&i.RecordSessions,
+			&i.DeprecationMessage,
+			&i.DeprecatedAt,
+			&i.RequireActiveVersion,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplate = `-- name: InsertTemplate :exec
+INSERT INTO
+	templates (
+		id,
+		created_at,
+		updated_at,
+		organization_id,
+		"name",
+		provisioner,
+		active_version_id,
+		description,
+		created_by,
+		icon,
+		user_acl,
+		group_acl,
+		display_name,
+		allow_user_cancel_workspace_jobs
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+`
+
+type InsertTemplateParams struct {
+	ID                           uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                    time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
+	OrganizationID               uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Name                         string          `db:"name" json:"name"`
+	Provisioner                  ProvisionerType `db:"provisioner" json:"provisioner"`
+	ActiveVersionID              uuid.UUID       `db:"active_version_id" json:"active_version_id"`
+	Description                  string          `db:"description" json:"description"`
+	CreatedBy                    uuid.UUID       `db:"created_by" json:"created_by"`
+	Icon                         string          `db:"icon" json:"icon"`
+	UserACL                      TemplateACL     `db:"user_acl" json:"user_acl"`
+	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
+	DisplayName                  string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+}
+
+func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplate,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.OrganizationID,
+		arg.Name,
+		arg.Provisioner,
+		arg.ActiveVersionID,
+		arg.Description,
+		arg.CreatedBy,
+		arg.Icon,
+		arg.UserACL,
+		arg.GroupACL,
+		arg.DisplayName,
+		arg.AllowUserCancelWorkspaceJobs,
+	)
+	return err
+}
+
+const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
+UPDATE
+	templates
+SET
+	group_acl = $1,
+	user_acl = $2
+WHERE
+	id = $3
+`
+
+type UpdateTemplateACLByIDParams struct {
+	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
+	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
+	ID       uuid.UUID   `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
+	return err
+}
+
+const updateTemplateActiveVersionByID = `-- name: UpdateTemplateActiveVersionByID :exec
+UPDATE
+	templates
+SET
+	active_version_id = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateTemplateActiveVersionByIDParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateActiveVersionByID, arg.ID, arg.ActiveVersionID, arg.UpdatedAt)
+	return err
+}
+
+const updateTemplateDeletedByID = `-- name: UpdateTemplateDeletedByID :exec
+UPDATE
+	templates
+SET
+	deleted = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateTemplateDeletedByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Deleted   bool      `db:"deleted" json:"deleted"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateDeletedByID, arg.ID, arg.Deleted, arg.UpdatedAt)
+	return err
+}
+
+const updateTemplateMetaByID = `-- name: UpdateTemplateMetaByID :exec
+UPDATE
+	templates
+SET
+	updated_at = $2,
+	description = $3,
+	name = $4,
+	icon = $5,
+	display_name = $6,
+	allow_user_cancel_workspace_jobs = $7,
+	max_port_sharing_level = $8,
+	record_sessions = $9,
+	deprecation_message = $10,
+	deprecated_at = $11,
+	require_active_version = $12
+WHERE
+	id = $1
+`
+
+type UpdateTemplateMetaByIDParams struct {
+	ID                           uuid.UUID       `db:"id" json:"id"`
+	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
+	Description                  string          `db:"description" json:"description"`
+	Name                         string          `db:"name" json:"name"`
+	Icon                         string          `db:"icon" json:"icon"`
+	DisplayName                  string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	RecordSessions               bool            `db:"record_sessions" json:"record_sessions"`
+	DeprecationMessage           string          `db:"deprecation_message" json:"deprecation_message"`
+	DeprecatedAt                 sql.NullTime    `db:"deprecated_at" json:"deprecated_at"`
+	RequireActiveVersion         bool            `db:"require_active_version" json:"require_active_version"`
+}
+
+func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateMetaByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Description,
+		arg.Name,
+		arg.Icon,
+		arg.DisplayName,
+		arg.AllowUserCancelWorkspaceJobs,
+		arg.MaxPortSharingLevel,
+		arg.RecordSessions,
+		arg.DeprecationMessage,
+		arg.DeprecatedAt,
+		arg.RequireActiveVersion,
+	)
+	return err
+}
+
+const updateTemplateScheduleByID = `-- name: UpdateTemplateScheduleByID :exec
+UPDATE
+	templates
+SET
+	updated_at = $2,
+	allow_user_autostart = $3,
+	allow_user_autostop = $4,
+	default_ttl = $5,
+	max_ttl = $6,
+	autostop_requirement_days_of_week = $7,
+	autostop_requirement_weeks = $8,
+	failure_ttl = $9,
+	time_til_dormant = $10,
+	time_til_dormant_autodelete = $11
+WHERE
+	id = $1
+`
+
+type UpdateTemplateScheduleByIDParams struct {
+	ID                            uuid.UUID `db:"id" json:"id"`
+	UpdatedAt                     time.Time `db:"updated_at" json:"updated_at"`
+	AllowUserAutostart            bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop             bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
+	DefaultTTL                    int64     `db:"default_ttl" json:"default_ttl"`
+	MaxTTL                        int64     `db:"max_ttl" json:"max_ttl"`
+	AutostopRequirementDaysOfWeek int16     `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks      int64     `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
+}
+
+func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateScheduleByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.AllowUserAutostart,
+		arg.AllowUserAutostop,
+		arg.DefaultTTL,
+		arg.MaxTTL,
+		arg.AutostopRequirementDaysOfWeek,
+		arg.AutostopRequirementWeeks,
+		arg.FailureTTL,
+		arg.TimeTilDormant,
+		arg.TimeTilDormantAutoDelete,
+	)
+	return err
+}
+
+const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
+SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+`
+
+func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionParameter
+	for rows.Next() {
+		var i TemplateVersionParameter
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Mutable,
+			&i.DefaultValue,
+			&i.Icon,
+			&i.Options,
+			&i.ValidationRegex,
+			&i.ValidationMin,
+			&i.ValidationMax,
+			&i.ValidationError,
+			&i.ValidationMonotonic,
+			&i.Required,
+			&i.DisplayName,
+			&i.DisplayOrder,
+			&i.Ephemeral,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
+INSERT INTO
+    template_version_parameters (
+        template_version_id,
+        name,
+        description,
+        type,
+        mutable,
+        default_value,
+        icon,
+        options,
+        validation_regex,
+        validation_min,
+        validation_max,
+        validation_error,
+        validation_monotonic,
+        required,
+        display_name,
+        display_order,
+        ephemeral
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14,
+        $15,
+        $16,
+        $17
+    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral
+`
+
+type InsertTemplateVersionParameterParams struct {
+	TemplateVersionID   uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	Name                string          `db:"name" json:"name"`
+	Description         string          `db:"description" json:"description"`
+	Type                string          `db:"type" json:"type"`
+	Mutable             bool            `db:"mutable" json:"mutable"`
+	DefaultValue        string          `db:"default_value" json:"default_value"`
+	Icon                string          `db:"icon" json:"icon"`
+	Options             json.RawMessage `db:"options" json:"options"`
+	ValidationRegex     string          `db:"validation_regex" json:"validation_regex"`
+	ValidationMin       sql.NullInt32   `db:"validation_min" json:"validation_min"`
+	ValidationMax       sql.NullInt32   `db:"validation_max" json:"validation_max"`
+	ValidationError     string          `db:"validation_error" json:"validation_error"`
+	ValidationMonotonic string          `db:"validation_monotonic" json:"validation_monotonic"`
+	Required            bool            `db:"required" json:"required"`
+	DisplayName         string          `db:"display_name" json:"display_name"`
+	DisplayOrder        int32           `db:"display_order" json:"display_order"`
+	Ephemeral           bool            `db:"ephemeral" json:"ephemeral"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.Mutable,
+		arg.DefaultValue,
+		arg.Icon,
+		arg.Options,
+		arg.ValidationRegex,
+		arg.ValidationMin,
+		arg.ValidationMax,
+		arg.ValidationError,
+		arg.ValidationMonotonic,
+		arg.Required,
+		arg.DisplayName,
+		arg.DisplayOrder,
+		arg.Ephemeral,
+	)
+	var i TemplateVersionParameter
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Mutable,
+		&i.DefaultValue,
+		&i.Icon,
+		&i.Options,
+		&i.ValidationRegex,
+		&i.ValidationMin,
+		&i.ValidationMax,
+		&i.ValidationError,
+		&i.ValidationMonotonic,
+		&i.Required,
+		&i.DisplayName,
+		&i.DisplayOrder,
+		&i.Ephemeral,
+	)
+	return i, err
+}
+
+const getTemplateVersionPresets = `-- name: GetTemplateVersionPresets :many
+SELECT id, template_version_id, name, parameters, created_at FROM template_version_presets WHERE template_version_id = $1 ORDER BY LOWER(name) ASC
+`
+
+func (q *sqlQuerier) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPreset, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionPresets, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionPreset
+	for rows.Next() {
+		var i TemplateVersionPreset
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Parameters,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionPreset = `-- name: InsertTemplateVersionPreset :one
+INSERT INTO
+	template_version_presets (
+		id,
+		template_version_id,
+		name,
+		parameters,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, template_version_id, name, parameters, created_at
+`
+
+type InsertTemplateVersionPresetParams struct {
+	ID                uuid.UUID       `db:"id" json:"id"`
+	TemplateVersionID uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	Name              string          `db:"name" json:"name"`
+	Parameters        json.RawMessage `db:"parameters" json:"parameters"`
+	CreatedAt         time.Time       `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionPreset(ctx context.Context, arg InsertTemplateVersionPresetParams) (TemplateVersionPreset, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionPreset,
+		arg.ID,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Parameters,
+		arg.CreatedAt,
+	)
+	var i TemplateVersionPreset
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Parameters,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	created_at < (
+		SELECT created_at
+		FROM template_version_with_user AS tv
+		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
+	)
+	AND organization_id = $1
+	AND template_id = $3
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetPreviousTemplateVersionParams struct {
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	Name           string        `db:"name" json:"name"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+}
+
+func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		pq.Array(&i.GitAuthProviders),
+		&i.Message,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
+	return i, err
+}
+
+const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		pq.Array(&i.GitAuthProviders),
+		&i.Message,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
+	return i, err
+}
+
+const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	job_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		pq.Array(&i.GitAuthProviders),
+		&i.Message,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
+	return i, err
+}
+
+const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	template_id = $1
+	AND "name" = $2
+`
+
+type GetTemplateVersionByTemplateIDAndNameParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	Name       string        `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		pq.Array(&i.GitAuthProviders),
+		&i.Message,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
+	return i, err
+}
+
+const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersion
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			pq.Array(&i.GitAuthProviders),
+			&i.Message,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	template_id = $1 :: uuid
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the created_at field, so select all
+			-- rows after the cursor.
+			(created_at, id) > (
+				SELECT
+					created_at, id
+				FROM
+					template_versions
+				WHERE
+					id = $2
+			)
+		)
+		ELSE true
+	END
+ORDER BY
+    -- Deterministic and consistent ordering of all rows, even if they share
+    -- a timestamp. This is to ensure consistent pagination.
+	(created_at, id) ASC OFFSET $3
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($4 :: int, 0)
+`
+
+type GetTemplateVersionsByTemplateIDParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	AfterID    uuid.UUID `db:"after_id" json:"after_id"`
+	OffsetOpt  int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
+		arg.TemplateID,
+		arg.AfterID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersion
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			pq.Array(&i.GitAuthProviders),
+			&i.Message,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
+SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers, message, created_by_avatar_url, created_by_username FROM template_version_with_user AS template_versions WHERE created_at > $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersion
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			pq.Array(&i.GitAuthProviders),
+			&i.Message,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
+INSERT INTO
+	template_versions (
+		id,
+		template_id,
+		organization_id,
+		created_at,
+		updated_at,
+		"name",
+		message,
+		readme,
+		job_id,
+		created_by
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+type InsertTemplateVersionParams struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
+	Name           string        `db:"name" json:"name"`
+	Message        string        `db:"message" json:"message"`
+	Readme         string        `db:"readme" json:"readme"`
+	JobID          uuid.UUID     `db:"job_id" json:"job_id"`
+	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
+		arg.ID,
+		arg.TemplateID,
+		arg.OrganizationID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Message,
+		arg.Readme,
+		arg.JobID,
+		arg.CreatedBy,
+	)
+	return err
+}
+
+const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
+UPDATE
+	template_versions
+SET
+	template_id = $2,
+	updated_at = $3,
+	name = $4,
+	message = $5
+WHERE
+	id = $1
+`
+
+type UpdateTemplateVersionByIDParams struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+	Name       string        `db:"name" json:"name"`
+	Message    string        `db:"message" json:"message"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
+		arg.ID,
+		arg.TemplateID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Message,
+	)
+	return err
+}
+
+const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
+UPDATE
+	template_versions
+SET
+	readme = $2,
+	updated_at = $3
+WHERE
+	job_id = $1
+`
+
+type UpdateTemplateVersionDescriptionByJobIDParams struct {
+	JobID     uuid.UUID `db:"job_id" json:"job_id"`
+	Readme    string    `db:"readme" json:"readme"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
+	return err
+}
+
+const updateTemplateVersionGitAuthProvidersByJobID = `-- name: UpdateTemplateVersionGitAuthProvidersByJobID :exec
+UPDATE
+	template_versions
+SET
+	git_auth_providers = $2,
+	updated_at = $3
+WHERE
+	job_id = $1
+`
+
+type UpdateTemplateVersionGitAuthProvidersByJobIDParams struct {
+	JobID            uuid.UUID `db:"job_id" json:"job_id"`
+	GitAuthProviders []string  `db:"git_auth_providers" json:"git_auth_providers"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionGitAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionGitAuthProvidersByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionGitAuthProvidersByJobID, arg.JobID, pq.Array(arg.GitAuthProviders), arg.UpdatedAt)
+	return err
+}
+
+const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionVariable
+	for rows.Next() {
+		var i TemplateVersionVariable
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Value,
+			&i.DefaultValue,
+			&i.Required,
+			&i.Sensitive,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
+INSERT INTO
+    template_version_variables (
+        template_version_id,
+        name,
+        description,
+        type,
+        value,
+        default_value,
+        required,
+        sensitive
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8
+    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+`
+
+type InsertTemplateVersionVariableParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
+	Description       string    `db:"description" json:"description"`
+	Type              string    `db:"type" json:"type"`
+	Value             string    `db:"value" json:"value"`
+	DefaultValue      string    `db:"default_value" json:"default_value"`
+	Required          bool      `db:"required" json:"required"`
+	Sensitive         bool      `db:"sensitive" json:"sensitive"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.Value,
+		arg.DefaultValue,
+		arg.Required,
+		arg.Sensitive,
+	)
+	var i TemplateVersionVariable
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Value,
+		&i.DefaultValue,
+		&i.Required,
+		&i.Sensitive,
+	)
+	return i, err
+}
+
+const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
+SELECT
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+FROM
+	user_links
+WHERE
+	linked_id = $1
+`
+
+func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+	)
+	return i, err
+}
+
+const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
+SELECT
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+FROM
+	user_links
+WHERE
+	user_id = $1 AND login_type = $2
+`
+
+type GetUserLinkByUserIDLoginTypeParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+	)
+	return i, err
+}
+
+const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
+SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id FROM user_links WHERE user_id = $1
+`
+
+func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserLink
+	for rows.Next() {
+		var i UserLink
+		if err := rows.Scan(
+			&i.UserID,
+			&i.LoginType,
+			&i.LinkedID,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.OAuthAccessTokenKeyID,
+			&i.OAuthRefreshTokenKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserLink = `-- name: InsertUserLink :one
+INSERT INTO
+	user_links (
+		user_id,
+		login_type,
+		linked_id,
+		oauth_access_token,
+		oauth_access_token_key_id,
+		oauth_refresh_token,
+		oauth_refresh_token_key_id,
+		oauth_expiry
+	)
+VALUES
+	( $1, $2, $3, $4, $5, $6, $7, $8 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+`
+
+type InsertUserLinkParams struct {
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	LoginType              LoginType      `db:"login_type" json:"login_type"`
+	LinkedID               string         `db:"linked_id" json:"linked_id"`
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+}
+
+func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, insertUserLink,
+		arg.UserID,
+		arg.LoginType,
+		arg.LinkedID,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+	)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+	)
+	return i, err
+}
+
+const updateUserLink = `-- name: UpdateUserLink :one
+UPDATE
+	user_links
+SET
+	oauth_access_token = $1,
+	oauth_access_token_key_id = $2,
+	oauth_refresh_token = $3,
+	oauth_refresh_token_key_id = $4,
+	oauth_expiry = $5
+WHERE
+	user_id = $6 AND login_type = $7 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+`
+
+type UpdateUserLinkParams struct {
+	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	LoginType              LoginType      `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLink,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.UserID,
+		arg.LoginType,
+	)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+	)
+	return i, err
+}
+
+const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
+UPDATE
+	user_links
 SET
-	readme = $2,
-	updated_at = $3
+	linked_id = $1
 WHERE
-	job_id = $1
+	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+`
+
+type UpdateUserLinkedIDParams struct {
+	LinkedID  string    `db:"linked_id" json:"linked_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+	)
+	return i, err
+}
+
+const getActiveUserCount = `-- name: GetActiveUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false AND is_service_account = false
+`
+
+// Service accounts don't take license seats.
+func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
+SELECT
+	-- username is returned just to help for logging purposes
+	-- status is used to enforce 'suspended' users, as all roles are ignored
+	--	when suspended.
+	id, username, status,
+	-- All user roles, including their org roles.
+	array_cat(
+		-- All users are members
+		array_append(users.rbac_roles, 'member'),
+		(
+			SELECT
+				array_agg(org_roles)
+			FROM
+				organization_members,
+				-- All org_members get the org-member role for their orgs
+				unnest(
+					array_append(roles, 'organization-member:' || organization_members.organization_id::text)
+				) AS org_roles
+			WHERE
+				user_id = users.id
+		)
+	) :: text[] AS roles,
+	-- All groups the user is in.
+	(
+		SELECT
+			array_agg(
+				group_members.group_id :: text
+			)
+		FROM
+			group_members
+		WHERE
+			user_id = users.id
+	) :: text[] AS groups
+FROM
+	users
+WHERE
+	id = $1
+`
+
+type GetAuthorizationUserRolesRow struct {
+	ID       uuid.UUID  `db:"id" json:"id"`
+	Username string     `db:"username" json:"username"`
+	Status   UserStatus `db:"status" json:"status"`
+	Roles    []string   `db:"roles" json:"roles"`
+	Groups   []string   `db:"groups" json:"groups"`
+}
+
+// This function returns roles for authorization purposes. Implied member roles
+// are included.
+func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
+	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
+	var i GetAuthorizationUserRolesRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Status,
+		pq.Array(&i.Roles),
+		pq.Array(&i.Groups),
+	)
+	return i, err
+}
+
+const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
+FROM
+	users
+WHERE
+	(LOWER(username) = LOWER($1) OR LOWER(email) = LOWER($2)) AND
+	deleted = false
+LIMIT
+	1
+`
+
+type GetUserByEmailOrUsernameParams struct {
+	Username string `db:"username" json:"username"`
+	Email    string `db:"email" json:"email"`
+}
+
+func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
+FROM
+	users
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
+	)
+	return i, err
+}
+
+const getUserCount = `-- name: GetUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	deleted = false
+`
+
+func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account, COUNT(*) OVER() AS count
+FROM
+	users
+WHERE
+	users.deleted = false
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the username field, so select all
+			-- rows after the cursor.
+			(LOWER(username)) > (
+				SELECT
+					LOWER(username)
+				FROM
+					users
+				WHERE
+					id = $1
+			)
+		)
+		ELSE true
+	END
+	-- Start filters
+	-- Filter by name, email or username
+	AND CASE
+		WHEN $2 :: text != '' THEN (
+			email ILIKE concat('%', $2, '%')
+			OR username ILIKE concat('%', $2, '%')
+		)
+		ELSE true
+	END
+	-- Filter by status
+	AND CASE
+		-- @status needs to be a text because it can be empty, If it was
+		-- user_status enum, it would not.
+		WHEN cardinality($3 :: user_status[]) > 0 THEN
+			status = ANY($3 :: user_status[])
+		ELSE true
+	END
+	-- Filter by rbac_roles
+	AND CASE
+		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
+		-- everyone is a member.
+		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
+			rbac_roles && $4 :: text[]
+		ELSE true
+	END
+	-- Filter by last_seen
+	AND CASE
+		WHEN $5 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at <= $5
+		ELSE true
+	END
+	AND CASE
+		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at >= $6
+		ELSE true
+	END
+	-- End of filters
+
+	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
+	-- @authorize_filter
+ORDER BY
+	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
+	LOWER(username) ASC OFFSET $7
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($8 :: int, 0)
 `
 
-type UpdateTemplateVersionDescriptionByJobIDParams struct {
-	JobID     uuid.UUID `db:"job_id" json:"job_id"`
-	Readme    string    `db:"readme" json:"readme"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
-}
-
-func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
-	return err
+type GetUsersParams struct {
+	AfterID        uuid.UUID    `db:"after_id" json:"after_id"`
+	Search         string       `db:"search" json:"search"`
+	Status         []UserStatus `db:"status" json:"status"`
+	RbacRole       []string     `db:"rbac_role" json:"rbac_role"`
+	LastSeenBefore time.Time    `db:"last_seen_before" json:"last_seen_before"`
+	LastSeenAfter  time.Time    `db:"last_seen_after" json:"last_seen_after"`
+	OffsetOpt      int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt       int32        `db:"limit_opt" json:"limit_opt"`
 }
 
-const updateTemplateVersionGitAuthProvidersByJobID = `-- name: UpdateTemplateVersionGitAuthProvidersByJobID :exec
-UPDATE
-	template_versions
-SET
-	git_auth_providers = $2,
-	updated_at = $3
-WHERE
-	job_id = $1
-`
-
-type UpdateTemplateVersionGitAuthProvidersByJobIDParams struct {
-	JobID            uuid.UUID `db:"job_id" json:"job_id"`
-	GitAuthProviders []string  `db:"git_auth_providers" json:"git_auth_providers"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+type GetUsersRow struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	Email              string         `db:"email" json:"email"`
+	Username           string         `db:"username" json:"username"`
+	HashedPassword     []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+	Status             UserStatus     `db:"status" json:"status"`
+	RBACRoles          pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType          LoginType      `db:"login_type" json:"login_type"`
+	AvatarURL          sql.NullString `db:"avatar_url" json:"avatar_url"`
+	Deleted            bool           `db:"deleted" json:"deleted"`
+	LastSeenAt         time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	QuietHoursSchedule string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	IsServiceAccount   bool           `db:"is_service_account" json:"is_service_account"`
+	Count              int64          `db:"count" json:"count"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionGitAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionGitAuthProvidersByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionGitAuthProvidersByJobID, arg.JobID, pq.Array(arg.GitAuthProviders), arg.UpdatedAt)
-	return err
+// This will never return deleted users.
+func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers,
+		arg.AfterID,
+		arg.Search,
+		pq.Array(arg.Status),
+		pq.Array(arg.RbacRole),
+		arg.LastSeenBefore,
+		arg.LastSeenAfter,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.IsServiceAccount,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
-SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account FROM users WHERE id = ANY($1 :: uuid [ ])
 `
 
-func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
+// This shouldn't check for deleted, because it's frequently used
+// to look up references to actions. eg. a user could build a workspace
+// for another user, then be deleted... we still want them to appear!
+func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionVariable
+	var items []User
 	for rows.Next() {
-		var i TemplateVersionVariable
+		var i User
 		if err := rows.Scan(
-			&i.TemplateVersionID,
-			&i.Name,
-			&i.Description,
-			&i.Type,
-			&i.Value,
-			&i.DefaultValue,
-			&i.Required,
-			&i.Sensitive,
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.IsServiceAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -5512,145 +7819,100 @@ func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVe
 	return items, nil
 }
 
-const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
+const insertUser = `-- name: InsertUser :one
 INSERT INTO
-    template_version_variables (
-        template_version_id,
-        name,
-        description,
-        type,
-        value,
-        default_value,
-        required,
-        sensitive
-    )
+	users (
+		id,
+		email,
+		username,
+		hashed_password,
+		created_at,
+		updated_at,
+		rbac_roles,
+		login_type,
+		is_service_account
+	)
 VALUES
-    (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8
-    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
 `
 
-type InsertTemplateVersionVariableParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Name              string    `db:"name" json:"name"`
-	Description       string    `db:"description" json:"description"`
-	Type              string    `db:"type" json:"type"`
-	Value             string    `db:"value" json:"value"`
-	DefaultValue      string    `db:"default_value" json:"default_value"`
-	Required          bool      `db:"required" json:"required"`
-	Sensitive         bool      `db:"sensitive" json:"sensitive"`
-}
-
-func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
-		arg.TemplateVersionID,
-		arg.Name,
-		arg.Description,
-		arg.Type,
-		arg.Value,
-		arg.DefaultValue,
-		arg.Required,
-		arg.Sensitive,
-	)
-	var i TemplateVersionVariable
-	err := row.Scan(
-		&i.TemplateVersionID,
-		&i.Name,
-		&i.Description,
-		&i.Type,
-		&i.Value,
-		&i.DefaultValue,
-		&i.Required,
-		&i.Sensitive,
-	)
-	return i, err
+type InsertUserParams struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	Email            string         `db:"email" json:"email"`
+	Username         string         `db:"username" json:"username"`
+	HashedPassword   []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+	RBACRoles        pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType        LoginType      `db:"login_type" json:"login_type"`
+	IsServiceAccount bool           `db:"is_service_account" json:"is_service_account"`
 }
 
-const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
-SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
-FROM
-	user_links
-WHERE
-	linked_id = $1
-`
-
-func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
+func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, insertUser,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.HashedPassword,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.RBACRoles,
+		arg.LoginType,
+		arg.IsServiceAccount,
 	)
-	return i, err
-}
-
-const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
-SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
-FROM
-	user_links
-WHERE
-	user_id = $1 AND login_type = $2
-`
-
-type GetUserLinkByUserIDLoginTypeParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
-}
-
-func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
-	var i UserLink
+	var i User
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
 		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
-const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
-SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id FROM user_links WHERE user_id = $1
+const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
+UPDATE
+    users
+SET
+    status = 'dormant'::user_status,
+	updated_at = $1
+WHERE
+    last_seen_at < $2 :: timestamp
+    AND status = 'active'::user_status
+RETURNING id, email, last_seen_at
 `
 
-func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
-	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+type UpdateInactiveUsersToDormantParams struct {
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
+}
+
+type UpdateInactiveUsersToDormantRow struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	Email      string    `db:"email" json:"email"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
+	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UserLink
+	var items []UpdateInactiveUsersToDormantRow
 	for rows.Next() {
-		var i UserLink
-		if err := rows.Scan(
-			&i.UserID,
-			&i.LoginType,
-			&i.LinkedID,
-			&i.OAuthAccessToken,
-			&i.OAuthRefreshToken,
-			&i.OAuthExpiry,
-			&i.OAuthAccessTokenKeyID,
-			&i.OAuthRefreshTokenKeyID,
-		); err != nil {
+		var i UpdateInactiveUsersToDormantRow
+		if err := rows.Scan(&i.ID, &i.Email, &i.LastSeenAt); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -5664,234 +7926,228 @@ func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID)
 	return items, nil
 }
 
-const insertUserLink = `-- name: InsertUserLink :one
-INSERT INTO
-	user_links (
-		user_id,
-		login_type,
-		linked_id,
-		oauth_access_token,
-		oauth_access_token_key_id,
-		oauth_refresh_token,
-		oauth_refresh_token_key_id,
-		oauth_expiry
-	)
-VALUES
-	( $1, $2, $3, $4, $5, $6, $7, $8 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
+UPDATE
+	users
+SET
+	deleted = $2
+WHERE
+	id = $1
 `
 
-type InsertUserLinkParams struct {
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	LoginType              LoginType      `db:"login_type" json:"login_type"`
-	LinkedID               string         `db:"linked_id" json:"linked_id"`
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
+type UpdateUserDeletedByIDParams struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	Deleted bool      `db:"deleted" json:"deleted"`
 }
 
-func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, insertUserLink,
-		arg.UserID,
-		arg.LoginType,
-		arg.LinkedID,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-	)
-	var i UserLink
+func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, arg.ID, arg.Deleted)
+	return err
+}
+
+const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
+UPDATE
+	users
+SET
+	hashed_password = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserHashedPasswordParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
+UPDATE
+	users
+SET
+	last_seen_at = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
+`
+
+type UpdateUserLastSeenAtParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
+	var i User
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
 		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
-const updateUserLink = `-- name: UpdateUserLink :one
+const updateUserLoginType = `-- name: UpdateUserLoginType :one
 UPDATE
-	user_links
+	users
 SET
-	oauth_access_token = $1,
-	oauth_access_token_key_id = $2,
-	oauth_refresh_token = $3,
-	oauth_refresh_token_key_id = $4,
-	oauth_expiry = $5
+	login_type = $1,
+	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
+		users.hashed_password
+	ELSE
+		-- If the login type is not password, then the password should be
+        -- cleared.
+		'':: bytea
+	END
 WHERE
-	user_id = $6 AND login_type = $7 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
 `
 
-type UpdateUserLinkParams struct {
-	OAuthAccessToken       string         `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string         `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time      `db:"oauth_expiry" json:"oauth_expiry"`
-	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
-	LoginType              LoginType      `db:"login_type" json:"login_type"`
+type UpdateUserLoginTypeParams struct {
+	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLink,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.UserID,
-		arg.LoginType,
-	)
-	var i UserLink
+func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
+	var i User
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
 		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
-const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
+const updateUserProfile = `-- name: UpdateUserProfile :one
 UPDATE
-	user_links
+	users
 SET
-	linked_id = $1
+	email = $2,
+	username = $3,
+	avatar_url = $4,
+	updated_at = $5
 WHERE
-	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
 `
 
-type UpdateUserLinkedIDParams struct {
-	LinkedID  string    `db:"linked_id" json:"linked_id"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+type UpdateUserProfileParams struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	Email     string         `db:"email" json:"email"`
+	Username  string         `db:"username" json:"username"`
+	AvatarURL sql.NullString `db:"avatar_url" json:"avatar_url"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
-	var i UserLink
+func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.AvatarURL,
+		arg.UpdatedAt,
+	)
+	var i User
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
 		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
-const getActiveUserCount = `-- name: GetActiveUserCount :one
-SELECT
-	COUNT(*)
-FROM
-	users
-WHERE
-	status = 'active'::user_status AND deleted = false
-`
-
-func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getActiveUserCount)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
-SELECT
-	-- username is returned just to help for logging purposes
-	-- status is used to enforce 'suspended' users, as all roles are ignored
-	--	when suspended.
-	id, username, status,
-	-- All user roles, including their org roles.
-	array_cat(
-		-- All users are members
-		array_append(users.rbac_roles, 'member'),
-		(
-			SELECT
-				array_agg(org_roles)
-			FROM
-				organization_members,
-				-- All org_members get the org-member role for their orgs
-				unnest(
-					array_append(roles, 'organization-member:' || organization_members.organization_id::text)
-				) AS org_roles
-			WHERE
-				user_id = users.id
-		)
-	) :: text[] AS roles,
-	-- All groups the user is in.
-	(
-		SELECT
-			array_agg(
-				group_members.group_id :: text
-			)
-		FROM
-			group_members
-		WHERE
-			user_id = users.id
-	) :: text[] AS groups
-FROM
+const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
+UPDATE
 	users
+SET
+	quiet_hours_schedule = $2
 WHERE
 	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
 `
 
-type GetAuthorizationUserRolesRow struct {
-	ID       uuid.UUID  `db:"id" json:"id"`
-	Username string     `db:"username" json:"username"`
-	Status   UserStatus `db:"status" json:"status"`
-	Roles    []string   `db:"roles" json:"roles"`
-	Groups   []string   `db:"groups" json:"groups"`
+type UpdateUserQuietHoursScheduleParams struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
 }
 
-// This function returns roles for authorization purposes. Implied member roles
-// are included.
-func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
-	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
-	var i GetAuthorizationUserRolesRow
+func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
+	var i User
 	err := row.Scan(
 		&i.ID,
+		&i.Email,
 		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 		&i.Status,
-		pq.Array(&i.Roles),
-		pq.Array(&i.Groups),
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
-const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
-FROM
+const updateUserRoles = `-- name: UpdateUserRoles :one
+UPDATE
 	users
+SET
+	-- Remove all duplicates from the roles.
+	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
-	(LOWER(username) = LOWER($1) OR LOWER(email) = LOWER($2)) AND
-	deleted = false
-LIMIT
-	1
+	id = $2
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
 `
 
-type GetUserByEmailOrUsernameParams struct {
-	Username string `db:"username" json:"username"`
-	Email    string `db:"email" json:"email"`
+type UpdateUserRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	ID           uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
+func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -5907,23 +8163,29 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
-FROM
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
 	users
+SET
+	status = $2,
+	updated_at = $3
 WHERE
-	id = $1
-LIMIT
-	1
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, is_service_account
 `
 
-func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -5939,162 +8201,153 @@ func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
-const getUserCount = `-- name: GetUserCount :one
-SELECT
-	COUNT(*)
-FROM
-	users
+const acquireWebhookDelivery = `-- name: AcquireWebhookDelivery :one
+UPDATE
+	webhook_deliveries
+SET
+	attempts = attempts + 1,
+	last_attempt_at = $1 :: timestamptz,
+	next_attempt_at = $2 :: timestamptz
 WHERE
-	deleted = false
+	id = (
+		SELECT
+			nested.id
+		FROM
+			webhook_deliveries AS nested
+			JOIN webhooks ON webhooks.id = nested.webhook_id
+		WHERE
+			nested.next_attempt_at <= $1 :: timestamptz
+			AND webhooks.enabled
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE OF nested
+		SKIP LOCKED
+		LIMIT
+			1
+	)
+RETURNING id, webhook_id, event, payload, created_at, attempts, next_attempt_at, last_attempt_at, status_code, error, delivered_at
 `
 
-func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getUserCount)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type AcquireWebhookDeliveryParams struct {
+	Now            time.Time `db:"now" json:"now"`
+	LeaseExpiresAt time.Time `db:"lease_expires_at" json:"lease_expires_at"`
 }
 
-const getUsers = `-- name: GetUsers :many
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, COUNT(*) OVER() AS count
-FROM
-	users
+// AcquireWebhookDelivery claims the delivery that has been due the longest.
+// The delivery is leased until @lease_expires_at, when another replica may
+// retry it if the attempt didn't finish. Deliveries to disabled webhooks wait
+// until the webhook is enabled again.
+func (q *sqlQuerier) AcquireWebhookDelivery(ctx context.Context, arg AcquireWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, acquireWebhookDelivery, arg.Now, arg.LeaseExpiresAt)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.CreatedAt,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.StatusCode,
+		&i.Error,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const deleteOldWebhookDeliveries = `-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM
+	webhook_deliveries
 WHERE
-	users.deleted = false
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the username field, so select all
-			-- rows after the cursor.
-			(LOWER(username)) > (
-				SELECT
-					LOWER(username)
-				FROM
-					users
-				WHERE
-					id = $1
-			)
-		)
-		ELSE true
-	END
-	-- Start filters
-	-- Filter by name, email or username
-	AND CASE
-		WHEN $2 :: text != '' THEN (
-			email ILIKE concat('%', $2, '%')
-			OR username ILIKE concat('%', $2, '%')
-		)
-		ELSE true
-	END
-	-- Filter by status
-	AND CASE
-		-- @status needs to be a text because it can be empty, If it was
-		-- user_status enum, it would not.
-		WHEN cardinality($3 :: user_status[]) > 0 THEN
-			status = ANY($3 :: user_status[])
-		ELSE true
-	END
-	-- Filter by rbac_roles
-	AND CASE
-		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
-		-- everyone is a member.
-		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
-			rbac_roles && $4 :: text[]
-		ELSE true
-	END
-	-- Filter by last_seen
-	AND CASE
-		WHEN $5 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at <= $5
-		ELSE true
-	END
-	AND CASE
-		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at >= $6
-		ELSE true
-	END
-	-- End of filters
+	next_attempt_at IS NULL
+	AND created_at < NOW() - INTERVAL '30 days'
+`
+
+// Deliveries that are done are kept for 30 days, so admins can review
+// failures.
+func (q *sqlQuerier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWebhookDeliveries)
+	return err
+}
+
+const deleteWebhookByID = `-- name: DeleteWebhookByID :exec
+DELETE FROM webhooks WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhookByID, id)
+	return err
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT id, created_at, updated_at, name, url, secret, events, enabled FROM webhooks WHERE id = $1
+`
+
+func (q *sqlQuerier) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Enabled,
+	)
+	return i, err
+}
 
-	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
-	-- @authorize_filter
+const getWebhookDeliveriesByWebhookID = `-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	id, webhook_id, event, payload, created_at, attempts, next_attempt_at, last_attempt_at, status_code, error, delivered_at
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = $1
 ORDER BY
-	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
-	LOWER(username) ASC OFFSET $7
+	created_at DESC
+OFFSET
+	$2
 LIMIT
 	-- A null limit means "no limit", so 0 means return all
-	NULLIF($8 :: int, 0)
+	NULLIF($3 :: int, 0)
 `
 
-type GetUsersParams struct {
-	AfterID        uuid.UUID    `db:"after_id" json:"after_id"`
-	Search         string       `db:"search" json:"search"`
-	Status         []UserStatus `db:"status" json:"status"`
-	RbacRole       []string     `db:"rbac_role" json:"rbac_role"`
-	LastSeenBefore time.Time    `db:"last_seen_before" json:"last_seen_before"`
-	LastSeenAfter  time.Time    `db:"last_seen_after" json:"last_seen_after"`
-	OffsetOpt      int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt       int32        `db:"limit_opt" json:"limit_opt"`
-}
-
-type GetUsersRow struct {
-	ID                 uuid.UUID      `db:"id" json:"id"`
-	Email              string         `db:"email" json:"email"`
-	Username           string         `db:"username" json:"username"`
-	HashedPassword     []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
-	Status             UserStatus     `db:"status" json:"status"`
-	RBACRoles          pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType          LoginType      `db:"login_type" json:"login_type"`
-	AvatarURL          sql.NullString `db:"avatar_url" json:"avatar_url"`
-	Deleted            bool           `db:"deleted" json:"deleted"`
-	LastSeenAt         time.Time      `db:"last_seen_at" json:"last_seen_at"`
-	QuietHoursSchedule string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
-	Count              int64          `db:"count" json:"count"`
+type GetWebhookDeliveriesByWebhookIDParams struct {
+	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	OffsetOpt int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
 }
 
-// This will never return deleted users.
-func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUsers,
-		arg.AfterID,
-		arg.Search,
-		pq.Array(arg.Status),
-		pq.Array(arg.RbacRole),
-		arg.LastSeenBefore,
-		arg.LastSeenAfter,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+func (q *sqlQuerier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveriesByWebhookID, arg.WebhookID, arg.OffsetOpt, arg.LimitOpt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUsersRow
+	var items []WebhookDelivery
 	for rows.Next() {
-		var i GetUsersRow
+		var i WebhookDelivery
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.Count,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.StatusCode,
+			&i.Error,
+			&i.DeliveredAt,
 		); err != nil {
 			return nil, err
 		}
@@ -6109,36 +8362,28 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 	return items, nil
 }
 
-const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule FROM users WHERE id = ANY($1 :: uuid [ ])
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT id, created_at, updated_at, name, url, secret, events, enabled FROM webhooks ORDER BY lower(name) ASC
 `
 
-// This shouldn't check for deleted, because it's frequently used
-// to look up references to actions. eg. a user could build a workspace
-// for another user, then be deleted... we still want them to appear!
-func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+func (q *sqlQuerier) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []Webhook
 	for rows.Next() {
-		var i User
+		var i Webhook
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
+			&i.Name,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.Enabled,
 		); err != nil {
 			return nil, err
 		}
@@ -6153,96 +8398,304 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 	return items, nil
 }
 
-const insertUser = `-- name: InsertUser :one
+const insertWebhook = `-- name: InsertWebhook :one
 INSERT INTO
-	users (
+	webhooks (
 		id,
-		email,
-		username,
-		hashed_password,
 		created_at,
 		updated_at,
-		rbac_roles,
-		login_type
+		name,
+		url,
+		secret,
+		events,
+		enabled
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at, name, url, secret, events, enabled
 `
 
-type InsertUserParams struct {
-	ID             uuid.UUID      `db:"id" json:"id"`
-	Email          string         `db:"email" json:"email"`
-	Username       string         `db:"username" json:"username"`
-	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
-	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType      LoginType      `db:"login_type" json:"login_type"`
+type InsertWebhookParams struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+	Name      string         `db:"name" json:"name"`
+	Url       string         `db:"url" json:"url"`
+	Secret    string         `db:"secret" json:"secret"`
+	Events    []WebhookEvent `db:"events" json:"events"`
+	Enabled   bool           `db:"enabled" json:"enabled"`
 }
 
-func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, insertUser,
+func (q *sqlQuerier) InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhook,
 		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.HashedPassword,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.RBACRoles,
-		arg.LoginType,
+		arg.Name,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.Enabled,
 	)
-	var i User
+	var i Webhook
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Enabled,
 	)
 	return i, err
 }
 
-const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
+const insertWebhookDeliveries = `-- name: InsertWebhookDeliveries :many
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		event,
+		payload,
+		created_at,
+		next_attempt_at
+	)
+SELECT
+	gen_random_uuid(),
+	webhooks.id,
+	$1 :: webhook_event,
+	$2 :: jsonb,
+	$3 :: timestamptz,
+	$3 :: timestamptz
+FROM
+	webhooks
+WHERE
+	webhooks.enabled
+	AND $1 :: webhook_event = ANY(webhooks.events)
+RETURNING id, webhook_id, event, payload, created_at, attempts, next_attempt_at, last_attempt_at, status_code, error, delivered_at
+`
+
+type InsertWebhookDeliveriesParams struct {
+	Event     WebhookEvent    `db:"event" json:"event"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// InsertWebhookDeliveries queues an event for every enabled webhook that
+// subscribed to it.
+func (q *sqlQuerier) InsertWebhookDeliveries(ctx context.Context, arg InsertWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, insertWebhookDeliveries, arg.Event, arg.Payload, arg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.StatusCode,
+			&i.Error,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWebhookByID = `-- name: UpdateWebhookByID :one
 UPDATE
-    users
+	webhooks
 SET
-    status = 'dormant'::user_status,
-	updated_at = $1
+	updated_at = $2,
+	name = $3,
+	url = $4,
+	secret = $5,
+	events = $6,
+	enabled = $7
 WHERE
-    last_seen_at < $2 :: timestamp
-    AND status = 'active'::user_status
-RETURNING id, email, last_seen_at
+	id = $1
+RETURNING id, created_at, updated_at, name, url, secret, events, enabled
 `
 
-type UpdateInactiveUsersToDormantParams struct {
-	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
-	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
+type UpdateWebhookByIDParams struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+	Name      string         `db:"name" json:"name"`
+	Url       string         `db:"url" json:"url"`
+	Secret    string         `db:"secret" json:"secret"`
+	Events    []WebhookEvent `db:"events" json:"events"`
+	Enabled   bool           `db:"enabled" json:"enabled"`
 }
 
-type UpdateInactiveUsersToDormantRow struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	Email      string    `db:"email" json:"email"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+func (q *sqlQuerier) UpdateWebhookByID(ctx context.Context, arg UpdateWebhookByIDParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhookByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.Enabled,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Enabled,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
-	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
+const updateWebhookDeliveryByID = `-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	status_code = $2,
+	error = $3,
+	delivered_at = $4,
+	next_attempt_at = $5
+WHERE
+	id = $1
+`
+
+type UpdateWebhookDeliveryByIDParams struct {
+	ID            uuid.UUID     `db:"id" json:"id"`
+	StatusCode    sql.NullInt32 `db:"status_code" json:"status_code"`
+	Error         string        `db:"error" json:"error"`
+	DeliveredAt   sql.NullTime  `db:"delivered_at" json:"delivered_at"`
+	NextAttemptAt sql.NullTime  `db:"next_attempt_at" json:"next_attempt_at"`
+}
+
+func (q *sqlQuerier) UpdateWebhookDeliveryByID(ctx context.Context, arg UpdateWebhookDeliveryByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookDeliveryByID,
+		arg.ID,
+		arg.StatusCode,
+		arg.Error,
+		arg.DeliveredAt,
+		arg.NextAttemptAt,
+	)
+	return err
+}
+
+const deleteOldWorkspaceAgentLogsBySourceID = `-- name: DeleteOldWorkspaceAgentLogsBySourceID :exec
+DELETE FROM workspace_agent_logs WHERE agent_id = $1 AND log_source_id = $2 AND id <= (
+	SELECT id FROM (
+		SELECT id, SUM(octet_length(output)) OVER (ORDER BY id DESC) AS newer_length
+		FROM workspace_agent_logs
+		WHERE agent_id = $1 AND log_source_id = $2
+	) AS newer WHERE newer_length > $3 :: integer ORDER BY id DESC LIMIT 1
+)
+`
+
+type DeleteOldWorkspaceAgentLogsBySourceIDParams struct {
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	LogSourceID uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	MaxLength   int32     `db:"max_length" json:"max_length"`
+}
+
+// Logs of the files an agent tails don't count toward the logs limit of the
+// agent. Instead, only the newest logs of each file are kept.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentLogsBySourceID(ctx context.Context, arg DeleteOldWorkspaceAgentLogsBySourceIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentLogsBySourceID, arg.AgentID, arg.LogSourceID, arg.MaxLength)
+	return err
+}
+
+const getWorkspaceAgentLogFilesByAgentID = `-- name: GetWorkspaceAgentLogFilesByAgentID :many
+SELECT id, workspace_agent_id, log_source_id, created_at, path FROM workspace_agent_log_files WHERE workspace_agent_id = $1 ORDER BY path
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentLogFilesByAgentID(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentLogFile, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentLogFilesByAgentID, workspaceAgentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UpdateInactiveUsersToDormantRow
+	var items []WorkspaceAgentLogFile
 	for rows.Next() {
-		var i UpdateInactiveUsersToDormantRow
-		if err := rows.Scan(&i.ID, &i.Email, &i.LastSeenAt); err != nil {
+		var i WorkspaceAgentLogFile
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.CreatedAt,
+			&i.Path,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentLogFiles = `-- name: InsertWorkspaceAgentLogFiles :many
+INSERT INTO
+	workspace_agent_log_files (id, workspace_agent_id, created_at, log_source_id, path)
+SELECT
+	unnest($1 :: uuid [ ]) AS id,
+	$2 :: uuid AS workspace_agent_id,
+	$3 :: timestamptz AS created_at,
+	unnest($4 :: uuid [ ]) AS log_source_id,
+	unnest($5 :: text [ ]) AS path
+RETURNING workspace_agent_log_files.id, workspace_agent_log_files.workspace_agent_id, workspace_agent_log_files.log_source_id, workspace_agent_log_files.created_at, workspace_agent_log_files.path
+`
+
+type InsertWorkspaceAgentLogFilesParams struct {
+	ID               []uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	LogSourceID      []uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	Path             []string    `db:"path" json:"path"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentLogFiles(ctx context.Context, arg InsertWorkspaceAgentLogFilesParams) ([]WorkspaceAgentLogFile, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentLogFiles,
+		pq.Array(arg.ID),
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.LogSourceID),
+		pq.Array(arg.Path),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentLogFile
+	for rows.Next() {
+		var i WorkspaceAgentLogFile
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.CreatedAt,
+			&i.Path,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -6256,276 +8709,354 @@ func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg Updat
 	return items, nil
 }
 
-const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
-UPDATE
-	users
-SET
-	deleted = $2
+const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_share
 WHERE
-	id = $1
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
 `
 
-type UpdateUserDeletedByIDParams struct {
-	ID      uuid.UUID `db:"id" json:"id"`
-	Deleted bool      `db:"deleted" json:"deleted"`
+type DeleteWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
 }
 
-func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, arg.ID, arg.Deleted)
+func (q *sqlQuerier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
 	return err
 }
 
-const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
-UPDATE
-	users
-SET
-	hashed_password = $2
+const deleteWorkspaceAgentPortSharesByTemplate = `-- name: DeleteWorkspaceAgentPortSharesByTemplate :exec
+DELETE FROM
+	workspace_agent_port_share
 WHERE
-	id = $1
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = $1
+	)
 `
 
-type UpdateUserHashedPasswordParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
-}
-
-func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+func (q *sqlQuerier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortSharesByTemplate, templateID)
 	return err
 }
 
-const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
-UPDATE
-	users
-SET
-	last_seen_at = $2,
-	updated_at = $3
+const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
+SELECT
+	workspace_id, agent_name, port, share_level
+FROM
+	workspace_agent_port_share
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
 `
 
-type UpdateUserLastSeenAtParams struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+type GetWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
 }
 
-func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	var i WorkspaceAgentPortShare
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
 	)
 	return i, err
 }
 
-const updateUserLoginType = `-- name: UpdateUserLoginType :one
+const listWorkspaceAgentPortShares = `-- name: ListWorkspaceAgentPortShares :many
+SELECT
+	workspace_id, agent_name, port, share_level
+FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name, port
+`
+
+func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkspaceAgentPortShares, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentPortShare
+	for rows.Next() {
+		var i WorkspaceAgentPortShare
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.ShareLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reduceWorkspaceAgentShareLevelToAuthenticatedByTemplate = `-- name: ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate :exec
 UPDATE
-	users
+	workspace_agent_port_share
 SET
-	login_type = $1,
-	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
-		users.hashed_password
-	ELSE
-		-- If the login type is not password, then the password should be
-        -- cleared.
-		'':: bytea
-	END
+	share_level = 'authenticated'
 WHERE
-	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	share_level = 'public'
+	AND workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = $1
+	)
 `
 
-type UpdateUserLoginTypeParams struct {
-	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+func (q *sqlQuerier) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, reduceWorkspaceAgentShareLevelToAuthenticatedByTemplate, templateID)
+	return err
 }
 
-func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
-	var i User
+const upsertWorkspaceAgentPortShare = `-- name: UpsertWorkspaceAgentPortShare :one
+INSERT INTO
+	workspace_agent_port_share (workspace_id, agent_name, port, share_level)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, agent_name, port)
+DO UPDATE SET
+	share_level = $4
+RETURNING workspace_id, agent_name, port, share_level
+`
+
+type UpsertWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	AgentName   string          `db:"agent_name" json:"agent_name"`
+	Port        int32           `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel `db:"share_level" json:"share_level"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentPortShare,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.ShareLevel,
+	)
+	var i WorkspaceAgentPortShare
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
 	)
 	return i, err
 }
 
-const updateUserProfile = `-- name: UpdateUserProfile :one
-UPDATE
-	users
-SET
-	email = $2,
-	username = $3,
-	avatar_url = $4,
-	updated_at = $5
+const deleteWorkspaceAgentPTYInvite = `-- name: DeleteWorkspaceAgentPTYInvite :exec
+DELETE FROM
+	workspace_agent_pty_invites
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	agent_id = $1
+	AND session_id = $2
+	AND user_id = $3
 `
 
-type UpdateUserProfileParams struct {
-	ID        uuid.UUID      `db:"id" json:"id"`
-	Email     string         `db:"email" json:"email"`
-	Username  string         `db:"username" json:"username"`
-	AvatarURL sql.NullString `db:"avatar_url" json:"avatar_url"`
-	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+type DeleteWorkspaceAgentPTYInviteParams struct {
+	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
+	SessionID uuid.UUID `db:"session_id" json:"session_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserProfile,
-		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.AvatarURL,
-		arg.UpdatedAt,
-	)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-	)
-	return i, err
+func (q *sqlQuerier) DeleteWorkspaceAgentPTYInvite(ctx context.Context, arg DeleteWorkspaceAgentPTYInviteParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPTYInvite, arg.AgentID, arg.SessionID, arg.UserID)
+	return err
 }
 
-const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
-UPDATE
-	users
-SET
-	quiet_hours_schedule = $2
+const getWorkspaceAgentPTYInvite = `-- name: GetWorkspaceAgentPTYInvite :one
+SELECT
+	workspace_id, agent_id, session_id, user_id, read_only, created_by, created_at
+FROM
+	workspace_agent_pty_invites
 WHERE
-	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	agent_id = $1
+	AND session_id = $2
+	AND user_id = $3
 `
 
-type UpdateUserQuietHoursScheduleParams struct {
-	ID                 uuid.UUID `db:"id" json:"id"`
-	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+type GetWorkspaceAgentPTYInviteParams struct {
+	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
+	SessionID uuid.UUID `db:"session_id" json:"session_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
-	var i User
+func (q *sqlQuerier) GetWorkspaceAgentPTYInvite(ctx context.Context, arg GetWorkspaceAgentPTYInviteParams) (WorkspaceAgentPTYInvite, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentPTYInvite, arg.AgentID, arg.SessionID, arg.UserID)
+	var i WorkspaceAgentPTYInvite
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.SessionID,
+		&i.UserID,
+		&i.ReadOnly,
+		&i.CreatedBy,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
 	)
 	return i, err
 }
 
-const updateUserRoles = `-- name: UpdateUserRoles :one
-UPDATE
-	users
-SET
-	-- Remove all duplicates from the roles.
-	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+const getWorkspaceAgentPTYInvitesBySessionID = `-- name: GetWorkspaceAgentPTYInvitesBySessionID :many
+SELECT
+	workspace_id, agent_id, session_id, user_id, read_only, created_by, created_at
+FROM
+	workspace_agent_pty_invites
 WHERE
-	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	agent_id = $1
+	AND session_id = $2
+ORDER BY
+	created_at
 `
 
-type UpdateUserRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	ID           uuid.UUID `db:"id" json:"id"`
+type GetWorkspaceAgentPTYInvitesBySessionIDParams struct {
+	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
+	SessionID uuid.UUID `db:"session_id" json:"session_id"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentPTYInvitesBySessionID(ctx context.Context, arg GetWorkspaceAgentPTYInvitesBySessionIDParams) ([]WorkspaceAgentPTYInvite, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentPTYInvitesBySessionID, arg.AgentID, arg.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentPTYInvite
+	for rows.Next() {
+		var i WorkspaceAgentPTYInvite
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.SessionID,
+			&i.UserID,
+			&i.ReadOnly,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-	)
-	return i, err
+const getWorkspaceAgentPTYInvitesByUserID = `-- name: GetWorkspaceAgentPTYInvitesByUserID :many
+SELECT
+	workspace_id, agent_id, session_id, user_id, read_only, created_by, created_at
+FROM
+	workspace_agent_pty_invites
+WHERE
+	user_id = $1
+ORDER BY
+	created_at
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentPTYInvitesByUserID(ctx context.Context, userID uuid.UUID) ([]WorkspaceAgentPTYInvite, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentPTYInvitesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentPTYInvite
+	for rows.Next() {
+		var i WorkspaceAgentPTYInvite
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.SessionID,
+			&i.UserID,
+			&i.ReadOnly,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateUserStatus = `-- name: UpdateUserStatus :one
-UPDATE
-	users
-SET
-	status = $2,
-	updated_at = $3
-WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+const upsertWorkspaceAgentPTYInvite = `-- name: UpsertWorkspaceAgentPTYInvite :one
+INSERT INTO
+	workspace_agent_pty_invites (workspace_id, agent_id, session_id, user_id, read_only, created_by, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (agent_id, session_id, user_id)
+DO UPDATE SET
+	read_only = $5,
+	created_by = $6,
+	created_at = $7
+RETURNING workspace_id, agent_id, session_id, user_id, read_only, created_by, created_at
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+type UpsertWorkspaceAgentPTYInviteParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	SessionID   uuid.UUID `db:"session_id" json:"session_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	ReadOnly    bool      `db:"read_only" json:"read_only"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) UpsertWorkspaceAgentPTYInvite(ctx context.Context, arg UpsertWorkspaceAgentPTYInviteParams) (WorkspaceAgentPTYInvite, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentPTYInvite,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.SessionID,
+		arg.UserID,
+		arg.ReadOnly,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i WorkspaceAgentPTYInvite
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.SessionID,
+		&i.UserID,
+		&i.ReadOnly,
+		&i.CreatedBy,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
 	)
 	return i, err
 }
@@ -6545,7 +9076,7 @@ func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 
 const getWorkspaceAgentAndOwnerByAuthToken = `-- name: GetWorkspaceAgentAndOwnerByAuthToken :one
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps,
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.autostop_inhibitors, workspace_agents.update_state, workspace_agents.update_error,
 	workspaces.id AS workspace_id,
 	users.id AS owner_id,
 	users.username AS owner_name,
@@ -6645,6 +9176,9 @@ func (q *sqlQuerier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, a
 		&i.WorkspaceAgent.ReadyAt,
 		pq.Array(&i.WorkspaceAgent.Subsystems),
 		pq.Array(&i.WorkspaceAgent.DisplayApps),
+		&i.WorkspaceAgent.AutostopInhibitors,
+		&i.WorkspaceAgent.UpdateState,
+		&i.WorkspaceAgent.UpdateError,
 		&i.WorkspaceID,
 		&i.OwnerID,
 		&i.OwnerName,
@@ -6657,7 +9191,7 @@ func (q *sqlQuerier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, a
 
 const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps, autostop_inhibitors, update_state, update_error
 FROM
 	workspace_agents
 WHERE
@@ -6702,13 +9236,16 @@ func (q *sqlQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (W
 		&i.ReadyAt,
 		pq.Array(&i.Subsystems),
 		pq.Array(&i.DisplayApps),
+		&i.AutostopInhibitors,
+		&i.UpdateState,
+		&i.UpdateError,
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByInstanceID = `-- name: GetWorkspaceAgentByInstanceID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps, autostop_inhibitors, update_state, update_error
 FROM
 	workspace_agents
 WHERE
@@ -6755,6 +9292,9 @@ func (q *sqlQuerier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInst
 		&i.ReadyAt,
 		pq.Array(&i.Subsystems),
 		pq.Array(&i.DisplayApps),
+		&i.AutostopInhibitors,
+		&i.UpdateState,
+		&i.UpdateError,
 	)
 	return i, err
 }
@@ -6783,9 +9323,42 @@ func (q *sqlQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id
 	return i, err
 }
 
+const getWorkspaceAgentLogSourcesByAgentIDs = `-- name: GetWorkspaceAgentLogSourcesByAgentIDs :many
+SELECT workspace_agent_id, id, created_at, display_name, icon FROM workspace_agent_log_sources WHERE workspace_agent_id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentLogSourcesByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentLogSource
+	for rows.Next() {
+		var i WorkspaceAgentLogSource
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.DisplayName,
+			&i.Icon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentLogsAfter = `-- name: GetWorkspaceAgentLogsAfter :many
 SELECT
-	agent_id, created_at, output, id, level, source
+	agent_id, created_at, output, id, level, log_source_id
 FROM
 	workspace_agent_logs
 WHERE
@@ -6815,7 +9388,7 @@ func (q *sqlQuerier) GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWork
 			&i.Output,
 			&i.ID,
 			&i.Level,
-			&i.Source,
+			&i.LogSourceID,
 		); err != nil {
 			return nil, err
 		}
@@ -6874,7 +9447,7 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAge
 
 const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps, autostop_inhibitors, update_state, update_error
 FROM
 	workspace_agents
 WHERE
@@ -6925,6 +9498,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 			&i.ReadyAt,
 			pq.Array(&i.Subsystems),
 			pq.Array(&i.DisplayApps),
+			&i.AutostopInhibitors,
+			&i.UpdateState,
+			&i.UpdateError,
 		); err != nil {
 			return nil, err
 		}
@@ -6940,7 +9516,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 }
 
 const getWorkspaceAgentsCreatedAfter = `-- name: GetWorkspaceAgentsCreatedAfter :many
-SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps FROM workspace_agents WHERE created_at > $1
+SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps, autostop_inhibitors, update_state, update_error FROM workspace_agents WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error) {
@@ -6987,6 +9563,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 			&i.ReadyAt,
 			pq.Array(&i.Subsystems),
 			pq.Array(&i.DisplayApps),
+			&i.AutostopInhibitors,
+			&i.UpdateState,
+			&i.UpdateError,
 		); err != nil {
 			return nil, err
 		}
@@ -7003,7 +9582,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 
 const getWorkspaceAgentsInLatestBuildByWorkspaceID = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceID :many
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.autostop_inhibitors, workspace_agents.update_state, workspace_agents.update_error
 FROM
 	workspace_agents
 JOIN
@@ -7066,6 +9645,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Co
 			&i.ReadyAt,
 			pq.Array(&i.Subsystems),
 			pq.Array(&i.DisplayApps),
+			&i.AutostopInhibitors,
+			&i.UpdateState,
+			&i.UpdateError,
 		); err != nil {
 			return nil, err
 		}
@@ -7107,7 +9689,7 @@ INSERT INTO
 		display_apps
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_apps, autostop_inhibitors, update_state, update_error
 `
 
 type InsertWorkspaceAgentParams struct {
@@ -7196,33 +9778,91 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		&i.ReadyAt,
 		pq.Array(&i.Subsystems),
 		pq.Array(&i.DisplayApps),
+		&i.AutostopInhibitors,
+		&i.UpdateState,
+		&i.UpdateError,
 	)
 	return i, err
 }
 
+const insertWorkspaceAgentLogSources = `-- name: InsertWorkspaceAgentLogSources :many
+INSERT INTO
+		workspace_agent_log_sources (workspace_agent_id, created_at, id, display_name, icon)
+	SELECT
+		$1 :: uuid AS workspace_agent_id,
+		$2 :: timestamptz AS created_at,
+		unnest($3 :: uuid [ ]) AS id,
+		unnest($4 :: VARCHAR(127) [ ]) AS display_name,
+		unnest($5 :: text [ ]) AS icon
+	RETURNING workspace_agent_log_sources.workspace_agent_id, workspace_agent_log_sources.id, workspace_agent_log_sources.created_at, workspace_agent_log_sources.display_name, workspace_agent_log_sources.icon
+`
+
+type InsertWorkspaceAgentLogSourcesParams struct {
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	ID               []uuid.UUID `db:"id" json:"id"`
+	DisplayName      []string    `db:"display_name" json:"display_name"`
+	Icon             []string    `db:"icon" json:"icon"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentLogSources,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.ID),
+		pq.Array(arg.DisplayName),
+		pq.Array(arg.Icon),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentLogSource
+	for rows.Next() {
+		var i WorkspaceAgentLogSource
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.DisplayName,
+			&i.Icon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertWorkspaceAgentLogs = `-- name: InsertWorkspaceAgentLogs :many
 WITH new_length AS (
 	UPDATE workspace_agents SET
 	logs_length = logs_length + $6 WHERE workspace_agents.id = $1
 )
 INSERT INTO
-		workspace_agent_logs (agent_id, created_at, output, level, source)
+		workspace_agent_logs (agent_id, created_at, output, level, log_source_id)
 	SELECT
 		$1 :: uuid AS agent_id,
 		unnest($2 :: timestamptz [ ]) AS created_at,
 		unnest($3 :: VARCHAR(1024) [ ]) AS output,
 		unnest($4 :: log_level [ ]) AS level,
-		unnest($5 :: workspace_agent_log_source [ ]) AS source
-	RETURNING workspace_agent_logs.agent_id, workspace_agent_logs.created_at, workspace_agent_logs.output, workspace_agent_logs.id, workspace_agent_logs.level, workspace_agent_logs.source
+		$5 :: uuid AS log_source_id
+	RETURNING workspace_agent_logs.agent_id, workspace_agent_logs.created_at, workspace_agent_logs.output, workspace_agent_logs.id, workspace_agent_logs.level, workspace_agent_logs.log_source_id
 `
 
 type InsertWorkspaceAgentLogsParams struct {
-	AgentID      uuid.UUID                 `db:"agent_id" json:"agent_id"`
-	CreatedAt    []time.Time               `db:"created_at" json:"created_at"`
-	Output       []string                  `db:"output" json:"output"`
-	Level        []LogLevel                `db:"level" json:"level"`
-	Source       []WorkspaceAgentLogSource `db:"source" json:"source"`
-	OutputLength int32                     `db:"output_length" json:"output_length"`
+	AgentID      uuid.UUID   `db:"agent_id" json:"agent_id"`
+	CreatedAt    []time.Time `db:"created_at" json:"created_at"`
+	Output       []string    `db:"output" json:"output"`
+	Level        []LogLevel  `db:"level" json:"level"`
+	LogSourceID  uuid.UUID   `db:"log_source_id" json:"log_source_id"`
+	OutputLength int32       `db:"output_length" json:"output_length"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error) {
@@ -7231,7 +9871,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWor
 		pq.Array(arg.CreatedAt),
 		pq.Array(arg.Output),
 		pq.Array(arg.Level),
-		pq.Array(arg.Source),
+		arg.LogSourceID,
 		arg.OutputLength,
 	)
 	if err != nil {
@@ -7247,7 +9887,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWor
 			&i.Output,
 			&i.ID,
 			&i.Level,
-			&i.Source,
+			&i.LogSourceID,
 		); err != nil {
 			return nil, err
 		}
@@ -7297,6 +9937,25 @@ func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg Inser
 	return err
 }
 
+const updateWorkspaceAgentAutostopInhibitorsByID = `-- name: UpdateWorkspaceAgentAutostopInhibitorsByID :exec
+UPDATE
+	workspace_agents
+SET
+	autostop_inhibitors = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentAutostopInhibitorsByIDParams struct {
+	ID                 uuid.UUID          `db:"id" json:"id"`
+	AutostopInhibitors AutostopInhibitors `db:"autostop_inhibitors" json:"autostop_inhibitors"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentAutostopInhibitorsByID(ctx context.Context, arg UpdateWorkspaceAgentAutostopInhibitorsByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentAutostopInhibitorsByID, arg.ID, arg.AutostopInhibitors)
+	return err
+}
+
 const updateWorkspaceAgentConnectionByID = `-- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
 	workspace_agents
@@ -7409,30 +10068,241 @@ func (q *sqlQuerier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg Updat
 	return err
 }
 
-const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
+const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
+UPDATE
+	workspace_agents
+SET
+	version = $2,
+	expanded_directory = $3,
+	subsystems = $4
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentStartupByIDParams struct {
+	ID                uuid.UUID                 `db:"id" json:"id"`
+	Version           string                    `db:"version" json:"version"`
+	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
+	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
+		arg.ID,
+		arg.Version,
+		arg.ExpandedDirectory,
+		pq.Array(arg.Subsystems),
+	)
+	return err
+}
+
+const updateWorkspaceAgentUpdateStateByID = `-- name: UpdateWorkspaceAgentUpdateStateByID :exec
+UPDATE
+	workspace_agents
+SET
+	update_state = $2,
+	update_error = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentUpdateStateByIDParams struct {
+	ID          uuid.UUID                 `db:"id" json:"id"`
+	UpdateState WorkspaceAgentUpdateState `db:"update_state" json:"update_state"`
+	UpdateError string                    `db:"update_error" json:"update_error"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentUpdateStateByID(ctx context.Context, arg UpdateWorkspaceAgentUpdateStateByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentUpdateStateByID, arg.ID, arg.UpdateState, arg.UpdateError)
+	return err
+}
+
+const getWorkspaceAgentScriptByID = `-- name: GetWorkspaceAgentScriptByID :one
+SELECT id, workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_order, status, exit_code, started_at, ended_at FROM workspace_agent_scripts WHERE id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentScriptByID(ctx context.Context, id uuid.UUID) (WorkspaceAgentScript, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentScriptByID, id)
+	var i WorkspaceAgentScript
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceAgentID,
+		&i.LogSourceID,
+		&i.LogPath,
+		&i.CreatedAt,
+		&i.Script,
+		&i.Cron,
+		&i.StartBlocksLogin,
+		&i.RunOnStart,
+		&i.RunOnStop,
+		&i.TimeoutSeconds,
+		&i.DisplayOrder,
+		&i.Status,
+		&i.ExitCode,
+		&i.StartedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
+SELECT id, workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_order, status, exit_code, started_at, ended_at FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ]) ORDER BY display_order
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentScript
+	for rows.Next() {
+		var i WorkspaceAgentScript
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.LogPath,
+			&i.CreatedAt,
+			&i.Script,
+			&i.Cron,
+			&i.StartBlocksLogin,
+			&i.RunOnStart,
+			&i.RunOnStop,
+			&i.TimeoutSeconds,
+			&i.DisplayOrder,
+			&i.Status,
+			&i.ExitCode,
+			&i.StartedAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentScripts = `-- name: InsertWorkspaceAgentScripts :many
+INSERT INTO
+	workspace_agent_scripts (id, workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_order)
+SELECT
+	unnest($1 :: uuid [ ]) AS id,
+	$2 :: uuid AS workspace_agent_id,
+	$3 :: timestamptz AS created_at,
+	unnest($4 :: uuid [ ]) AS log_source_id,
+	unnest($5 :: text [ ]) AS log_path,
+	unnest($6 :: text [ ]) AS script,
+	unnest($7 :: text [ ]) AS cron,
+	unnest($8 :: boolean [ ]) AS start_blocks_login,
+	unnest($9 :: boolean [ ]) AS run_on_start,
+	unnest($10 :: boolean [ ]) AS run_on_stop,
+	unnest($11 :: integer [ ]) AS timeout_seconds,
+	unnest($12 :: integer [ ]) AS display_order
+RETURNING workspace_agent_scripts.id, workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds, workspace_agent_scripts.display_order, workspace_agent_scripts.status, workspace_agent_scripts.exit_code, workspace_agent_scripts.started_at, workspace_agent_scripts.ended_at
+`
+
+type InsertWorkspaceAgentScriptsParams struct {
+	ID               []uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	LogSourceID      []uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	LogPath          []string    `db:"log_path" json:"log_path"`
+	Script           []string    `db:"script" json:"script"`
+	Cron             []string    `db:"cron" json:"cron"`
+	StartBlocksLogin []bool      `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart       []bool      `db:"run_on_start" json:"run_on_start"`
+	RunOnStop        []bool      `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds   []int32     `db:"timeout_seconds" json:"timeout_seconds"`
+	DisplayOrder     []int32     `db:"display_order" json:"display_order"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentScripts,
+		pq.Array(arg.ID),
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.LogSourceID),
+		pq.Array(arg.LogPath),
+		pq.Array(arg.Script),
+		pq.Array(arg.Cron),
+		pq.Array(arg.StartBlocksLogin),
+		pq.Array(arg.RunOnStart),
+		pq.Array(arg.RunOnStop),
+		pq.Array(arg.TimeoutSeconds),
+		pq.Array(arg.DisplayOrder),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentScript
+	for rows.Next() {
+		var i WorkspaceAgentScript
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.LogPath,
+			&i.CreatedAt,
+			&i.Script,
+			&i.Cron,
+			&i.StartBlocksLogin,
+			&i.RunOnStart,
+			&i.RunOnStop,
+			&i.TimeoutSeconds,
+			&i.DisplayOrder,
+			&i.Status,
+			&i.ExitCode,
+			&i.StartedAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWorkspaceAgentScriptStatusByID = `-- name: UpdateWorkspaceAgentScriptStatusByID :exec
 UPDATE
-	workspace_agents
+	workspace_agent_scripts
 SET
-	version = $2,
-	expanded_directory = $3,
-	subsystems = $4
+	status = $2,
+	exit_code = $3,
+	started_at = $4,
+	ended_at = $5
 WHERE
 	id = $1
 `
 
-type UpdateWorkspaceAgentStartupByIDParams struct {
-	ID                uuid.UUID                 `db:"id" json:"id"`
-	Version           string                    `db:"version" json:"version"`
-	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
-	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+type UpdateWorkspaceAgentScriptStatusByIDParams struct {
+	ID        uuid.UUID                  `db:"id" json:"id"`
+	Status    WorkspaceAgentScriptStatus `db:"status" json:"status"`
+	ExitCode  int32                      `db:"exit_code" json:"exit_code"`
+	StartedAt sql.NullTime               `db:"started_at" json:"started_at"`
+	EndedAt   sql.NullTime               `db:"ended_at" json:"ended_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
+func (q *sqlQuerier) UpdateWorkspaceAgentScriptStatusByID(ctx context.Context, arg UpdateWorkspaceAgentScriptStatusByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentScriptStatusByID,
 		arg.ID,
-		arg.Version,
-		arg.ExpandedDirectory,
-		pq.Array(arg.Subsystems),
+		arg.Status,
+		arg.ExitCode,
+		arg.StartedAt,
+		arg.EndedAt,
 	)
 	return err
 }
@@ -7453,7 +10323,9 @@ SELECT
 FROM
 	workspace_agent_stats
 WHERE
-	connection_count > 0
+	connection_count > 0 AND
+	-- Service accounts are not counted as active users.
+	user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 GROUP BY
 	date, user_id
 ORDER BY
@@ -7547,7 +10419,9 @@ FROM
 	workspace_agent_stats
 WHERE
 	template_id = $1 AND
-	connection_count > 0
+	connection_count > 0 AND
+	-- Service accounts are not counted as active users.
+	user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 GROUP BY
 	date, user_id
 ORDER BY
@@ -7947,7 +10821,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWo
 }
 
 const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 AND slug = $2
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_tcp, healthcheck_command FROM workspace_apps WHERE agent_id = $1 AND slug = $2
 `
 
 type GetWorkspaceAppByAgentIDAndSlugParams struct {
@@ -7974,12 +10848,14 @@ func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg Ge
 		&i.SharingLevel,
 		&i.Slug,
 		&i.External,
+		&i.HealthcheckTCP,
+		&i.HealthcheckCommand,
 	)
 	return i, err
 }
 
 const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_tcp, healthcheck_command FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
@@ -8007,6 +10883,8 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.HealthcheckTCP,
+			&i.HealthcheckCommand,
 		); err != nil {
 			return nil, err
 		}
@@ -8022,7 +10900,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 }
 
 const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_tcp, healthcheck_command FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
@@ -8050,6 +10928,8 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.HealthcheckTCP,
+			&i.HealthcheckCommand,
 		); err != nil {
 			return nil, err
 		}
@@ -8065,7 +10945,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 }
 
 const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_tcp, healthcheck_command FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
@@ -8093,6 +10973,8 @@ func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.HealthcheckTCP,
+			&i.HealthcheckCommand,
 		); err != nil {
 			return nil, err
 		}
@@ -8124,10 +11006,12 @@ INSERT INTO
         healthcheck_url,
         healthcheck_interval,
         healthcheck_threshold,
+        healthcheck_tcp,
+        healthcheck_command,
         health
     )
 VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, healthcheck_tcp, healthcheck_command
 `
 
 type InsertWorkspaceAppParams struct {
@@ -8145,6 +11029,8 @@ type InsertWorkspaceAppParams struct {
 	HealthcheckUrl       string             `db:"healthcheck_url" json:"healthcheck_url"`
 	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
 	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
+	HealthcheckTCP       string             `db:"healthcheck_tcp" json:"healthcheck_tcp"`
+	HealthcheckCommand   string             `db:"healthcheck_command" json:"healthcheck_command"`
 	Health               WorkspaceAppHealth `db:"health" json:"health"`
 }
 
@@ -8164,6 +11050,8 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		arg.HealthcheckUrl,
 		arg.HealthcheckInterval,
 		arg.HealthcheckThreshold,
+		arg.HealthcheckTCP,
+		arg.HealthcheckCommand,
 		arg.Health,
 	)
 	var i WorkspaceApp
@@ -8183,6 +11071,8 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		&i.SharingLevel,
 		&i.Slug,
 		&i.External,
+		&i.HealthcheckTCP,
+		&i.HealthcheckCommand,
 	)
 	return i, err
 }
@@ -8834,57 +11724,376 @@ func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspa
 		arg.MaxDeadline,
 		arg.Reason,
 	)
-	return err
-}
-
-const updateWorkspaceBuildByID = `-- name: UpdateWorkspaceBuildByID :exec
-UPDATE
-	workspace_builds
-SET
-	updated_at = $2,
-	provisioner_state = $3,
-	deadline = $4,
-	max_deadline = $5
-WHERE
-	id = $1
+	return err
+}
+
+const updateWorkspaceBuildByID = `-- name: UpdateWorkspaceBuildByID :exec
+UPDATE
+	workspace_builds
+SET
+	updated_at = $2,
+	provisioner_state = $3,
+	deadline = $4,
+	max_deadline = $5
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceBuildByIDParams struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
+	Deadline         time.Time `db:"deadline" json:"deadline"`
+	MaxDeadline      time.Time `db:"max_deadline" json:"max_deadline"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWorkspaceBuildByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.ProvisionerState,
+		arg.Deadline,
+		arg.MaxDeadline,
+	)
+	return err
+}
+
+const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
+UPDATE
+	workspace_builds
+SET
+	daily_cost = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceBuildCostByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+	return err
+}
+
+const claimPrebuiltWorkspace = `-- name: ClaimPrebuiltWorkspace :one
+UPDATE
+	workspaces
+SET
+	owner_id = $2,
+	name = $3,
+	autostart_schedule = $4,
+	ttl = $5,
+	automatic_updates = $6,
+	updated_at = $7,
+	last_used_at = $7
+WHERE
+	id = $1
+	AND deleted = false
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+`
+
+type ClaimPrebuiltWorkspaceParams struct {
+	ID                uuid.UUID        `db:"id" json:"id"`
+	OwnerID           uuid.UUID        `db:"owner_id" json:"owner_id"`
+	Name              string           `db:"name" json:"name"`
+	AutostartSchedule sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl               sql.NullInt64    `db:"ttl" json:"ttl"`
+	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// ClaimPrebuiltWorkspace transfers a prebuilt workspace to the user that
+// claimed it.
+func (q *sqlQuerier) ClaimPrebuiltWorkspace(ctx context.Context, arg ClaimPrebuiltWorkspaceParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, claimPrebuiltWorkspace,
+		arg.ID,
+		arg.OwnerID,
+		arg.Name,
+		arg.AutostartSchedule,
+		arg.Ttl,
+		arg.AutomaticUpdates,
+		arg.UpdatedAt,
+	)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+	)
+	return i, err
+}
+
+const deleteWorkspacePrebuildByWorkspaceID = `-- name: DeleteWorkspacePrebuildByWorkspaceID :one
+DELETE FROM workspace_prebuilds WHERE workspace_id = $1 RETURNING workspace_id, pool_id, created_at
+`
+
+// DeleteWorkspacePrebuildByWorkspaceID returns no rows if the workspace isn't
+// a prebuilt workspace anymore, for example because another user claimed it
+// concurrently.
+func (q *sqlQuerier) DeleteWorkspacePrebuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspacePrebuild, error) {
+	row := q.db.QueryRowContext(ctx, deleteWorkspacePrebuildByWorkspaceID, workspaceID)
+	var i WorkspacePrebuild
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.PoolID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWorkspacePrebuildPoolByID = `-- name: DeleteWorkspacePrebuildPoolByID :exec
+DELETE FROM workspace_prebuild_pools WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspacePrebuildPoolByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePrebuildPoolByID, id)
+	return err
+}
+
+const getWorkspacePrebuildPoolByID = `-- name: GetWorkspacePrebuildPoolByID :one
+SELECT id, template_id, template_version_id, parameters, desired_instances, created_at, updated_at FROM workspace_prebuild_pools WHERE id = $1
+`
+
+func (q *sqlQuerier) GetWorkspacePrebuildPoolByID(ctx context.Context, id uuid.UUID) (WorkspacePrebuildPool, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspacePrebuildPoolByID, id)
+	var i WorkspacePrebuildPool
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.Parameters,
+		&i.DesiredInstances,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWorkspacePrebuildPools = `-- name: GetWorkspacePrebuildPools :many
+SELECT id, template_id, template_version_id, parameters, desired_instances, created_at, updated_at FROM workspace_prebuild_pools ORDER BY created_at ASC
+`
+
+func (q *sqlQuerier) GetWorkspacePrebuildPools(ctx context.Context) ([]WorkspacePrebuildPool, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePrebuildPools)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePrebuildPool
+	for rows.Next() {
+		var i WorkspacePrebuildPool
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.TemplateVersionID,
+			&i.Parameters,
+			&i.DesiredInstances,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspacePrebuildPoolsByTemplateID = `-- name: GetWorkspacePrebuildPoolsByTemplateID :many
+SELECT id, template_id, template_version_id, parameters, desired_instances, created_at, updated_at FROM workspace_prebuild_pools WHERE template_id = $1 ORDER BY created_at ASC
+`
+
+func (q *sqlQuerier) GetWorkspacePrebuildPoolsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspacePrebuildPool, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePrebuildPoolsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePrebuildPool
+	for rows.Next() {
+		var i WorkspacePrebuildPool
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.TemplateVersionID,
+			&i.Parameters,
+			&i.DesiredInstances,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspacePrebuilds = `-- name: GetWorkspacePrebuilds :many
+SELECT
+	workspace_prebuilds.workspace_id, workspace_prebuilds.pool_id, workspace_prebuilds.created_at
+FROM
+	workspace_prebuilds
+JOIN
+	workspaces ON workspaces.id = workspace_prebuilds.workspace_id
+WHERE
+	workspaces.deleted = false
+ORDER BY
+	workspace_prebuilds.created_at ASC
+`
+
+// GetWorkspacePrebuilds returns the prebuilt workspaces that were not claimed
+// or deleted yet.
+func (q *sqlQuerier) GetWorkspacePrebuilds(ctx context.Context) ([]WorkspacePrebuild, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePrebuilds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePrebuild
+	for rows.Next() {
+		var i WorkspacePrebuild
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.PoolID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspacePrebuild = `-- name: InsertWorkspacePrebuild :exec
+INSERT INTO
+	workspace_prebuilds (
+		workspace_id,
+		pool_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3)
+`
+
+type InsertWorkspacePrebuildParams struct {
+	WorkspaceID uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	PoolID      uuid.NullUUID `db:"pool_id" json:"pool_id"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspacePrebuild(ctx context.Context, arg InsertWorkspacePrebuildParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspacePrebuild, arg.WorkspaceID, arg.PoolID, arg.CreatedAt)
+	return err
+}
+
+const insertWorkspacePrebuildPool = `-- name: InsertWorkspacePrebuildPool :one
+INSERT INTO
+	workspace_prebuild_pools (
+		id,
+		template_id,
+		template_version_id,
+		parameters,
+		desired_instances,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, template_id, template_version_id, parameters, desired_instances, created_at, updated_at
 `
 
-type UpdateWorkspaceBuildByIDParams struct {
-	ID               uuid.UUID `db:"id" json:"id"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
-	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
-	Deadline         time.Time `db:"deadline" json:"deadline"`
-	MaxDeadline      time.Time `db:"max_deadline" json:"max_deadline"`
+type InsertWorkspacePrebuildPoolParams struct {
+	ID                uuid.UUID       `db:"id" json:"id"`
+	TemplateID        uuid.UUID       `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	Parameters        json.RawMessage `db:"parameters" json:"parameters"`
+	DesiredInstances  int32           `db:"desired_instances" json:"desired_instances"`
+	CreatedAt         time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time       `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWorkspaceBuildByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildByID,
+func (q *sqlQuerier) InsertWorkspacePrebuildPool(ctx context.Context, arg InsertWorkspacePrebuildPoolParams) (WorkspacePrebuildPool, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspacePrebuildPool,
 		arg.ID,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.Parameters,
+		arg.DesiredInstances,
+		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.ProvisionerState,
-		arg.Deadline,
-		arg.MaxDeadline,
 	)
-	return err
+	var i WorkspacePrebuildPool
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.Parameters,
+		&i.DesiredInstances,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
+const updateWorkspacePrebuildPoolByID = `-- name: UpdateWorkspacePrebuildPoolByID :one
 UPDATE
-	workspace_builds
+	workspace_prebuild_pools
 SET
-	daily_cost = $2
+	desired_instances = $2,
+	updated_at = $3
 WHERE
 	id = $1
+RETURNING id, template_id, template_version_id, parameters, desired_instances, created_at, updated_at
 `
 
-type UpdateWorkspaceBuildCostByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+type UpdateWorkspacePrebuildPoolByIDParams struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	DesiredInstances int32     `db:"desired_instances" json:"desired_instances"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
-	return err
+func (q *sqlQuerier) UpdateWorkspacePrebuildPoolByID(ctx context.Context, arg UpdateWorkspacePrebuildPoolByIDParams) (WorkspacePrebuildPool, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspacePrebuildPoolByID, arg.ID, arg.DesiredInstances, arg.UpdatedAt)
+	var i WorkspacePrebuildPool
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.Parameters,
+		&i.DesiredInstances,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
@@ -9297,7 +12506,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9342,13 +12551,14 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.LastUsedAt,
 		&i.DormantAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9374,13 +12584,14 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.LastUsedAt,
 		&i.DormantAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9413,13 +12624,14 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.LastUsedAt,
 		&i.DormantAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9471,13 +12683,14 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.LastUsedAt,
 		&i.DormantAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates,
 	COALESCE(template_name.template_name, 'unknown') as template_name,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
@@ -9716,23 +12929,24 @@ type GetWorkspacesParams struct {
 }
 
 type GetWorkspacesRow struct {
-	ID                  uuid.UUID      `db:"id" json:"id"`
-	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt           time.Time      `db:"updated_at" json:"updated_at"`
-	OwnerID             uuid.UUID      `db:"owner_id" json:"owner_id"`
-	OrganizationID      uuid.UUID      `db:"organization_id" json:"organization_id"`
-	TemplateID          uuid.UUID      `db:"template_id" json:"template_id"`
-	Deleted             bool           `db:"deleted" json:"deleted"`
-	Name                string         `db:"name" json:"name"`
-	AutostartSchedule   sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
-	Ttl                 sql.NullInt64  `db:"ttl" json:"ttl"`
-	LastUsedAt          time.Time      `db:"last_used_at" json:"last_used_at"`
-	DormantAt           sql.NullTime   `db:"dormant_at" json:"dormant_at"`
-	DeletingAt          sql.NullTime   `db:"deleting_at" json:"deleting_at"`
-	TemplateName        string         `db:"template_name" json:"template_name"`
-	TemplateVersionID   uuid.UUID      `db:"template_version_id" json:"template_version_id"`
-	TemplateVersionName sql.NullString `db:"template_version_name" json:"template_version_name"`
-	Count               int64          `db:"count" json:"count"`
+	ID                  uuid.UUID        `db:"id" json:"id"`
+	CreatedAt           time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time        `db:"updated_at" json:"updated_at"`
+	OwnerID             uuid.UUID        `db:"owner_id" json:"owner_id"`
+	OrganizationID      uuid.UUID        `db:"organization_id" json:"organization_id"`
+	TemplateID          uuid.UUID        `db:"template_id" json:"template_id"`
+	Deleted             bool             `db:"deleted" json:"deleted"`
+	Name                string           `db:"name" json:"name"`
+	AutostartSchedule   sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl                 sql.NullInt64    `db:"ttl" json:"ttl"`
+	LastUsedAt          time.Time        `db:"last_used_at" json:"last_used_at"`
+	DormantAt           sql.NullTime     `db:"dormant_at" json:"dormant_at"`
+	DeletingAt          sql.NullTime     `db:"deleting_at" json:"deleting_at"`
+	AutomaticUpdates    AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	TemplateName        string           `db:"template_name" json:"template_name"`
+	TemplateVersionID   uuid.UUID        `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionName sql.NullString   `db:"template_version_name" json:"template_version_name"`
+	Count               int64            `db:"count" json:"count"`
 }
 
 func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams) ([]GetWorkspacesRow, error) {
@@ -9773,6 +12987,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.LastUsedAt,
 			&i.DormantAt,
 			&i.DeletingAt,
+			&i.AutomaticUpdates,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
@@ -9791,9 +13006,85 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 	return items, nil
 }
 
+const getWorkspacesApproachingAutostop = `-- name: GetWorkspacesApproachingAutostop :many
+SELECT
+	workspaces.id,
+	workspaces.owner_id,
+	workspaces.name,
+	workspace_builds.id AS build_id,
+	workspace_builds.deadline
+FROM
+	workspaces
+INNER JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+	) AND
+	workspace_builds.transition = 'start'::workspace_transition AND
+	provisioner_jobs.completed_at IS NOT NULL AND
+	COALESCE(provisioner_jobs.error, '') = '' AND
+	workspace_builds.deadline > $1 :: timestamptz AND
+	workspace_builds.deadline <= $2 :: timestamptz AND
+	workspaces.dormant_at IS NULL AND
+	workspaces.deleted = 'false'
+`
+
+type GetWorkspacesApproachingAutostopParams struct {
+	Now        time.Time `db:"now" json:"now"`
+	StopBefore time.Time `db:"stop_before" json:"stop_before"`
+}
+
+type GetWorkspacesApproachingAutostopRow struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	OwnerID  uuid.UUID `db:"owner_id" json:"owner_id"`
+	Name     string    `db:"name" json:"name"`
+	BuildID  uuid.UUID `db:"build_id" json:"build_id"`
+	Deadline time.Time `db:"deadline" json:"deadline"`
+}
+
+// GetWorkspacesApproachingAutostop returns the running workspaces that are
+// stopped automatically after @now and until @stop_before, so their owners can
+// be warned.
+func (q *sqlQuerier) GetWorkspacesApproachingAutostop(ctx context.Context, arg GetWorkspacesApproachingAutostopParams) ([]GetWorkspacesApproachingAutostopRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacesApproachingAutostop, arg.Now, arg.StopBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspacesApproachingAutostopRow
+	for rows.Next() {
+		var i GetWorkspacesApproachingAutostopRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Name,
+			&i.BuildID,
+			&i.Deadline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspacesEligibleForTransition = `-- name: GetWorkspacesEligibleForTransition :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates
 FROM
 	workspaces
 LEFT JOIN
@@ -9880,6 +13171,7 @@ func (q *sqlQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now
 			&i.LastUsedAt,
 			&i.DormantAt,
 			&i.DeletingAt,
+			&i.AutomaticUpdates,
 		); err != nil {
 			return nil, err
 		}
@@ -9906,23 +13198,25 @@ INSERT INTO
 		name,
 		autostart_schedule,
 		ttl,
-		last_used_at
+		last_used_at,
+		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
 `
 
 type InsertWorkspaceParams struct {
-	ID                uuid.UUID      `db:"id" json:"id"`
-	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
-	OwnerID           uuid.UUID      `db:"owner_id" json:"owner_id"`
-	OrganizationID    uuid.UUID      `db:"organization_id" json:"organization_id"`
-	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
-	Name              string         `db:"name" json:"name"`
-	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
-	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
-	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
+	ID                uuid.UUID        `db:"id" json:"id"`
+	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+	OwnerID           uuid.UUID        `db:"owner_id" json:"owner_id"`
+	OrganizationID    uuid.UUID        `db:"organization_id" json:"organization_id"`
+	TemplateID        uuid.UUID        `db:"template_id" json:"template_id"`
+	Name              string           `db:"name" json:"name"`
+	AutostartSchedule sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl               sql.NullInt64    `db:"ttl" json:"ttl"`
+	LastUsedAt        time.Time        `db:"last_used_at" json:"last_used_at"`
+	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 }
 
 func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error) {
@@ -9937,6 +13231,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		arg.AutostartSchedule,
 		arg.Ttl,
 		arg.LastUsedAt,
+		arg.AutomaticUpdates,
 	)
 	var i Workspace
 	err := row.Scan(
@@ -9953,6 +13248,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.LastUsedAt,
 		&i.DormantAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
@@ -9983,7 +13279,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
 `
 
 type UpdateWorkspaceParams struct {
@@ -10008,10 +13304,30 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.LastUsedAt,
 		&i.DormantAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
+const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
+UPDATE
+	workspaces
+SET
+	automatic_updates = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAutomaticUpdatesParams struct {
+	ID               uuid.UUID        `db:"id" json:"id"`
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg UpdateWorkspaceAutomaticUpdatesParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAutomaticUpdates, arg.ID, arg.AutomaticUpdates)
+	return err
+}
+
 const updateWorkspaceAutostart = `-- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -10067,7 +13383,7 @@ WHERE
 	workspaces.template_id = templates.id
 AND
 	workspaces.id = $1
-RETURNING workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at
+RETURNING workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates
 `
 
 type UpdateWorkspaceDormantDeletingAtParams struct {
@@ -10092,6 +13408,7 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.LastUsedAt,
 		&i.DormantAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
@@ -10159,3 +13476,230 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 	_, err := q.db.ExecContext(ctx, updateWorkspacesDormantDeletingAtByTemplateID, arg.TimeTilDormantAutodeleteMs, arg.DormantAt, arg.TemplateID)
 	return err
 }
+
+const getWorkspaceSessionRecordingByID = `-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	id, workspace_id, agent_id, type, reconnect_id, command, includes_input, size, created_at, updated_at, ended_at
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSessionRecordingByID, id)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.Type,
+		&i.ReconnectID,
+		&i.Command,
+		&i.IncludesInput,
+		&i.Size,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceSessionRecordingChunks = `-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	recording_id, chunk_index, data, created_at
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = $1
+ORDER BY
+	chunk_index
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingChunks(ctx context.Context, recordingID uuid.UUID) ([]WorkspaceSessionRecordingChunk, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingChunks, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecordingChunk
+	for rows.Next() {
+		var i WorkspaceSessionRecordingChunk
+		if err := rows.Scan(
+			&i.RecordingID,
+			&i.ChunkIndex,
+			&i.Data,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSessionRecordingsByWorkspaceID = `-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+SELECT
+	id, workspace_id, agent_id, type, reconnect_id, command, includes_input, size, created_at, updated_at, ended_at
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecording
+	for rows.Next() {
+		var i WorkspaceSessionRecording
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.Type,
+			&i.ReconnectID,
+			&i.Command,
+			&i.IncludesInput,
+			&i.Size,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSessionRecording = `-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (id, workspace_id, agent_id, type, reconnect_id, command, includes_input, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, workspace_id, agent_id, type, reconnect_id, command, includes_input, size, created_at, updated_at, ended_at
+`
+
+type InsertWorkspaceSessionRecordingParams struct {
+	ID            uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceID   uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	AgentID       uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Type          WorkspaceSessionRecordingType `db:"type" json:"type"`
+	ReconnectID   uuid.NullUUID                 `db:"reconnect_id" json:"reconnect_id"`
+	Command       string                        `db:"command" json:"command"`
+	IncludesInput bool                          `db:"includes_input" json:"includes_input"`
+	CreatedAt     time.Time                     `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time                     `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecording(ctx context.Context, arg InsertWorkspaceSessionRecordingParams) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSessionRecording,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.Type,
+		arg.ReconnectID,
+		arg.Command,
+		arg.IncludesInput,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.Type,
+		&i.ReconnectID,
+		&i.Command,
+		&i.IncludesInput,
+		&i.Size,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const insertWorkspaceSessionRecordingChunk = `-- name: InsertWorkspaceSessionRecordingChunk :execrows
+INSERT INTO
+	workspace_session_recording_chunks (recording_id, chunk_index, data, created_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (recording_id, chunk_index)
+DO NOTHING
+`
+
+type InsertWorkspaceSessionRecordingChunkParams struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	ChunkIndex  int32     `db:"chunk_index" json:"chunk_index"`
+	Data        []byte    `db:"data" json:"data"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertWorkspaceSessionRecordingChunk,
+		arg.RecordingID,
+		arg.ChunkIndex,
+		arg.Data,
+		arg.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateWorkspaceSessionRecordingByID = `-- name: UpdateWorkspaceSessionRecordingByID :execrows
+UPDATE
+	workspace_session_recordings
+SET
+	updated_at = $1,
+	size = size + $2 :: bigint,
+	ended_at = COALESCE(ended_at, $3)
+WHERE
+	id = $4
+	AND (ended_at IS NULL OR NOT $5 :: boolean)
+`
+
+type UpdateWorkspaceSessionRecordingByIDParams struct {
+	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+	AddedSize int64        `db:"added_size" json:"added_size"`
+	EndedAt   sql.NullTime `db:"ended_at" json:"ended_at"`
+	ID        uuid.UUID    `db:"id" json:"id"`
+	Appended  bool         `db:"appended" json:"appended"`
+}
+
+// Ended recordings keep their end time, and no chunks can be appended to them:
+// no rows are updated when a chunk is appended to an ended recording.
+func (q *sqlQuerier) UpdateWorkspaceSessionRecordingByID(ctx context.Context, arg UpdateWorkspaceSessionRecordingByIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateWorkspaceSessionRecordingByID,
+		arg.UpdatedAt,
+		arg.AddedSize,
+		arg.EndedAt,
+		arg.ID,
+		arg.Appended,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -44,7 +44,8 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scope_rules
 	)
 VALUES
 	(@id,
@@ -53,7 +54,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name, @scope_rules) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -65,7 +66,7 @@ SET
 WHERE
 	id = $1;
 
--- name: DeleteAPIKeyByID :exec
+-- name: DeleteAPIKeyByID :execrows
 DELETE FROM
 	api_keys
 WHERE
@@ -0,0 +1,118 @@
+-- UpsertConnectionLog records a connection, or updates it if it was recorded
+-- before. Agents report a connection when it opens and again when it closes,
+-- and either report may be retried or arrive out of order.
+-- name: UpsertConnectionLog :one
+INSERT INTO
+	connection_logs (
+		id,
+		connect_time,
+		organization_id,
+		workspace_owner_id,
+		workspace_id,
+		workspace_name,
+		agent_id,
+		agent_name,
+		type,
+		user_id,
+		ip,
+		user_agent,
+		slug_or_port,
+		code,
+		bytes_sent,
+		bytes_received,
+		disconnect_time,
+		disconnect_reason
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+ON CONFLICT (id) DO UPDATE SET
+	connect_time = LEAST(connection_logs.connect_time, EXCLUDED.connect_time),
+	user_id = COALESCE(connection_logs.user_id, EXCLUDED.user_id),
+	code = COALESCE(EXCLUDED.code, connection_logs.code),
+	bytes_sent = GREATEST(connection_logs.bytes_sent, EXCLUDED.bytes_sent),
+	bytes_received = GREATEST(connection_logs.bytes_received, EXCLUDED.bytes_received),
+	disconnect_time = COALESCE(EXCLUDED.disconnect_time, connection_logs.disconnect_time),
+	disconnect_reason = CASE
+		WHEN EXCLUDED.disconnect_reason != '' THEN EXCLUDED.disconnect_reason
+		ELSE connection_logs.disconnect_reason
+	END
+WHERE
+	-- Connections can't be taken over by another agent.
+	connection_logs.agent_id = EXCLUDED.agent_id
+RETURNING *;
+
+-- name: GetConnectionLogsOffset :many
+SELECT
+	connection_logs.*,
+	workspace_owner.username AS workspace_owner_username,
+	users.username AS user_username,
+	COUNT(connection_logs.*) OVER () AS count
+FROM
+	connection_logs
+	LEFT JOIN users AS workspace_owner ON connection_logs.workspace_owner_id = workspace_owner.id
+	LEFT JOIN users ON connection_logs.user_id = users.id
+WHERE
+	-- Filter by type
+	CASE
+		WHEN @type :: text != '' THEN
+			type = @type :: connection_type
+		ELSE true
+	END
+	-- Filter by workspace_owner_id
+	AND CASE
+		WHEN @workspace_owner_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_owner_id = @workspace_owner_id
+		ELSE true
+	END
+	-- Filter by workspace_owner
+	AND CASE
+		WHEN @workspace_owner :: text != '' THEN
+			workspace_owner_id = (SELECT id FROM users WHERE lower(username) = lower(@workspace_owner) AND deleted = false)
+		ELSE true
+	END
+	-- Filter by workspace_id
+	AND CASE
+		WHEN @workspace_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_id = @workspace_id
+		ELSE true
+	END
+	-- Filter by workspace_name
+	AND CASE
+		WHEN @workspace_name :: text != '' THEN
+			lower(workspace_name) = lower(@workspace_name)
+		ELSE true
+	END
+	-- Filter by user_id
+	AND CASE
+		WHEN @user_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = @user_id
+		ELSE true
+	END
+	-- Filter by username
+	AND CASE
+		WHEN @username :: text != '' THEN
+			user_id = (SELECT id FROM users WHERE lower(username) = lower(@username) AND deleted = false)
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN @date_from :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connect_time >= @date_from
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN @date_to :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connect_time <= @date_to
+		ELSE true
+	END
+ORDER BY
+	connect_time DESC
+OFFSET
+	@offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: DeleteOldConnectionLogs :exec
+DELETE FROM connection_logs WHERE connect_time < @before_time;
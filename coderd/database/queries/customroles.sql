@@ -0,0 +1,76 @@
+-- name: GetCustomRoles :many
+SELECT
+	*
+FROM
+	custom_roles
+ORDER BY
+	organization_id NULLS FIRST, lower(name) ASC;
+
+-- name: GetCustomRoleByID :one
+SELECT * FROM custom_roles WHERE id = $1;
+
+-- GetCustomRolesByName looks up roles by the names they are assigned with.
+-- Organization roles are assigned as "name:organization_id".
+-- name: GetCustomRolesByName :many
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	CASE
+		WHEN organization_id IS NULL THEN name
+		ELSE name || ':' || organization_id :: text
+	END = ANY(@lookup_roles :: text[]);
+
+-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: UpdateCustomRoleByID :one
+UPDATE
+	custom_roles
+SET
+	display_name = $2,
+	site_permissions = $3,
+	org_permissions = $4,
+	user_permissions = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING *;
+
+-- DeleteCustomRoleByID deletes the role and removes it from every user and
+-- organization member it is assigned to.
+-- name: DeleteCustomRoleByID :exec
+WITH deleted AS (
+	DELETE FROM custom_roles WHERE id = $1 RETURNING name, organization_id
+), unassigned_users AS (
+	UPDATE
+		users
+	SET
+		rbac_roles = array_remove(users.rbac_roles, deleted.name)
+	FROM
+		deleted
+	WHERE
+		deleted.organization_id IS NULL
+)
+UPDATE
+	organization_members
+SET
+	roles = array_remove(organization_members.roles, deleted.name || ':' || deleted.organization_id :: text)
+FROM
+	deleted
+WHERE
+	organization_members.organization_id = deleted.organization_id;
@@ -40,6 +40,8 @@ WITH agent_stats_by_interval_and_user AS (
 		AND was.created_at < @end_time::timestamptz
 		AND was.connection_count > 0
 		AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN was.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+		-- Service accounts are not counted as active users.
+		AND was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 	GROUP BY date_trunc('minute', was.created_at), was.user_id
 ), template_ids AS (
 	SELECT array_agg(DISTINCT template_id) AS ids
@@ -97,6 +99,8 @@ WITH app_stats_by_user_and_agent AS (
 		s.start_time >= @start_time::timestamptz
 		-- Subtract one minute because the series only contains the start time.
 		AND s.start_time < (@end_time::timestamptz) - '1 minute'::interval
+		-- Service accounts are not counted as active users.
+		AND was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 	GROUP BY s.start_time, w.template_id, was.user_id, was.agent_id, was.access_method, was.slug_or_port, wa.display_name, wa.icon, wa.slug
 )
 
@@ -147,6 +151,8 @@ WITH ts AS (
 		AND was.created_at < ts.to_
 		AND was.connection_count > 0
 		AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN was.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+		-- Service accounts are not counted as active users.
+		AND was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 	)
 	GROUP BY ts.from_, ts.to_, was.template_id, was.user_id
 
@@ -166,6 +172,8 @@ WITH ts AS (
 		w.id = was.workspace_id
 		AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN w.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
 	)
+	-- Service accounts are not counted as active users.
+	WHERE was.user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 	GROUP BY ts.from_, ts.to_, w.template_id, was.user_id
 )
 
@@ -0,0 +1,147 @@
+-- InsertNotificationMessage creates a notification unless the user opted out
+-- of its kind, or already received a notification with the same dedupe key.
+-- No rows are returned if the notification wasn't created.
+-- name: InsertNotificationMessage :one
+INSERT INTO
+	notification_messages (
+		id,
+		user_id,
+		kind,
+		title,
+		content,
+		dedupe_key,
+		created_at,
+		email_next_attempt_at
+	)
+SELECT
+	@id :: uuid,
+	@user_id :: uuid,
+	@kind :: notification_kind,
+	@title :: text,
+	@content :: text,
+	@dedupe_key :: text,
+	@created_at :: timestamptz,
+	CASE WHEN @email :: boolean THEN @created_at :: timestamptz ELSE NULL END
+WHERE
+	NOT EXISTS (
+		SELECT
+			1
+		FROM
+			notification_preferences
+		WHERE
+			notification_preferences.user_id = @user_id :: uuid
+			AND notification_preferences.kind = @kind :: notification_kind
+			AND notification_preferences.disabled
+	)
+ON CONFLICT (user_id, dedupe_key) WHERE dedupe_key != '' DO NOTHING
+RETURNING *;
+
+-- name: GetNotificationMessageByID :one
+SELECT * FROM notification_messages WHERE id = $1;
+
+-- name: GetNotificationMessagesByUserID :many
+SELECT
+	*
+FROM
+	notification_messages
+WHERE
+	user_id = @user_id
+	AND CASE
+		WHEN @unread_only :: boolean THEN read_at IS NULL
+		ELSE true
+	END
+ORDER BY
+	created_at DESC
+OFFSET
+	@offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: CountUnreadNotificationMessagesByUserID :one
+SELECT
+	COUNT(*)
+FROM
+	notification_messages
+WHERE
+	user_id = $1
+	AND read_at IS NULL;
+
+-- name: UpdateNotificationMessageReadAtByID :one
+UPDATE
+	notification_messages
+SET
+	read_at = COALESCE(read_at, @read_at :: timestamptz)
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: UpdateNotificationMessagesReadAtByUserID :exec
+UPDATE
+	notification_messages
+SET
+	read_at = @read_at :: timestamptz
+WHERE
+	user_id = @user_id
+	AND read_at IS NULL;
+
+-- AcquireNotificationMessageEmail claims the notification whose email has
+-- been due the longest. The notification is leased until @lease_expires_at,
+-- when another replica may retry the email if the attempt didn't finish.
+-- name: AcquireNotificationMessageEmail :one
+UPDATE
+	notification_messages
+SET
+	email_attempts = email_attempts + 1,
+	email_next_attempt_at = @lease_expires_at :: timestamptz
+WHERE
+	id = (
+		SELECT
+			nested.id
+		FROM
+			notification_messages AS nested
+		WHERE
+			nested.email_next_attempt_at <= @now :: timestamptz
+		ORDER BY
+			nested.email_next_attempt_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			1
+	)
+RETURNING *;
+
+-- name: UpdateNotificationMessageEmailByID :exec
+UPDATE
+	notification_messages
+SET
+	emailed_at = $2,
+	email_error = $3,
+	email_next_attempt_at = $4
+WHERE
+	id = $1;
+
+-- Notifications are kept for 90 days.
+-- name: DeleteOldNotificationMessages :exec
+DELETE FROM
+	notification_messages
+WHERE
+	created_at < NOW() - INTERVAL '90 days';
+
+-- name: GetNotificationPreferencesByUserID :many
+SELECT * FROM notification_preferences WHERE user_id = $1 ORDER BY kind;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO
+	notification_preferences (
+		user_id,
+		kind,
+		disabled,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id, kind) DO UPDATE SET
+	disabled = $3,
+	updated_at = $4
+RETURNING *;
@@ -0,0 +1,165 @@
+-- name: GetOAuth2ProviderApps :many
+SELECT * FROM oauth2_provider_apps ORDER BY lower(name) ASC;
+
+-- name: GetOAuth2ProviderAppByID :one
+SELECT * FROM oauth2_provider_apps WHERE id = $1;
+
+-- GetOAuth2ProviderAppsByUserID returns the apps the user has authorized and
+-- that still hold tokens of the user.
+-- name: GetOAuth2ProviderAppsByUserID :many
+SELECT
+	oauth2_provider_apps.*
+FROM
+	oauth2_provider_apps
+WHERE
+	id IN (
+		SELECT
+			oauth2_provider_app_tokens.app_id
+		FROM
+			oauth2_provider_app_tokens
+		INNER JOIN
+			api_keys ON api_keys.id = oauth2_provider_app_tokens.api_key_id
+		WHERE
+			api_keys.user_id = $1
+	)
+ORDER BY
+	lower(name) ASC;
+
+-- name: InsertOAuth2ProviderApp :one
+INSERT INTO
+	oauth2_provider_apps (
+		id,
+		created_at,
+		updated_at,
+		name,
+		icon,
+		redirect_uris
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE
+	oauth2_provider_apps
+SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	redirect_uris = $5
+WHERE
+	id = $1
+RETURNING *;
+
+-- DeleteOAuth2ProviderAppByID deletes the app and revokes the API keys of its
+-- tokens.
+-- name: DeleteOAuth2ProviderAppByID :exec
+WITH deleted_api_keys AS (
+	DELETE FROM
+		api_keys
+	WHERE
+		id IN (
+			SELECT
+				api_key_id
+			FROM
+				oauth2_provider_app_tokens
+			WHERE
+				app_id = $1
+		)
+)
+DELETE FROM oauth2_provider_apps WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT * FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY created_at ASC;
+
+-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT * FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppSecretByHashedSecret :one
+SELECT * FROM oauth2_provider_app_secrets WHERE hashed_secret = $1;
+
+-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO
+	oauth2_provider_app_secrets (
+		id,
+		app_id,
+		created_at,
+		hashed_secret,
+		display_secret
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppSecretByID :one
+UPDATE
+	oauth2_provider_app_secrets
+SET
+	last_used_at = $2
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT * FROM oauth2_provider_app_codes WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByHashedSecret :one
+SELECT * FROM oauth2_provider_app_codes WHERE hashed_secret = $1;
+
+-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO
+	oauth2_provider_app_codes (
+		id,
+		app_id,
+		user_id,
+		created_at,
+		expires_at,
+		hashed_secret,
+		redirect_uri,
+		scope,
+		code_challenge
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppCodeByID :execrows
+DELETE FROM oauth2_provider_app_codes WHERE id = $1;
+
+-- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
+DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2;
+
+-- name: GetOAuth2ProviderAppTokenByRefreshHash :one
+SELECT * FROM oauth2_provider_app_tokens WHERE refresh_hash = $1;
+
+-- name: GetOAuth2ProviderAppTokenByAPIKeyID :one
+SELECT * FROM oauth2_provider_app_tokens WHERE api_key_id = $1;
+
+-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO
+	oauth2_provider_app_tokens (
+		id,
+		app_id,
+		api_key_id,
+		created_at,
+		expires_at,
+		refresh_hash
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- DeleteOAuth2ProviderAppTokensByAppAndUserID revokes the tokens the app holds
+-- for the user. Deleting the API keys deletes their refresh tokens.
+-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $2
+	AND id IN (
+		SELECT
+			api_key_id
+		FROM
+			oauth2_provider_app_tokens
+		WHERE
+			app_id = $1
+	);
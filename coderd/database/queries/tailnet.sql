@@ -5,16 +5,18 @@ INSERT INTO
 	coordinator_id,
 	agent_id,
 	node,
+	user_id,
 	updated_at
 )
 VALUES
-	($1, $2, $3, $4, now() at time zone 'utc')
+	($1, $2, $3, $4, $5, now() at time zone 'utc')
 ON CONFLICT (id, coordinator_id)
 DO UPDATE SET
 	id = $1,
 	coordinator_id = $2,
 	agent_id = $3,
 	node = $4,
+	user_id = $5,
 	updated_at = now() at time zone 'utc'
 RETURNING *;
 
@@ -28,12 +28,24 @@ WHERE
 			LOWER("name") = LOWER(@exact_name)
 		ELSE true
 	END
+	-- Filter by name, matching on substring
+	AND CASE
+		WHEN @fuzzy_name :: text != '' THEN
+			LOWER("name") LIKE '%' || LOWER(@fuzzy_name) || '%'
+		ELSE true
+	END
 	-- Filter by ids
 	AND CASE
 		WHEN array_length(@ids :: uuid[], 1) > 0 THEN
 			id = ANY(@ids)
 		ELSE true
 	END
+	-- Filter by deprecation
+	AND CASE
+		WHEN sqlc.narg('deprecated') :: boolean IS NOT NULL THEN
+			(deprecation_message != '') = sqlc.narg('deprecated') :: boolean
+		ELSE true
+	END
   -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
   -- @authorize_filter
 ORDER BY (name, id) ASC
@@ -104,7 +116,12 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	max_port_sharing_level = $8,
+	record_sessions = $9,
+	deprecation_message = $10,
+	deprecated_at = $11,
+	require_active_version = $12
 WHERE
 	id = $1
 ;
@@ -0,0 +1,14 @@
+-- name: InsertTemplateVersionPreset :one
+INSERT INTO
+	template_version_presets (
+		id,
+		template_version_id,
+		name,
+		parameters,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: GetTemplateVersionPresets :many
+SELECT * FROM template_version_presets WHERE template_version_id = $1 ORDER BY LOWER(name) ASC;
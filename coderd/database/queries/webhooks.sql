@@ -0,0 +1,128 @@
+-- name: GetWebhooks :many
+SELECT * FROM webhooks ORDER BY lower(name) ASC;
+
+-- name: GetWebhookByID :one
+SELECT * FROM webhooks WHERE id = $1;
+
+-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (
+		id,
+		created_at,
+		updated_at,
+		name,
+		url,
+		secret,
+		events,
+		enabled
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: UpdateWebhookByID :one
+UPDATE
+	webhooks
+SET
+	updated_at = $2,
+	name = $3,
+	url = $4,
+	secret = $5,
+	events = $6,
+	enabled = $7
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM webhooks WHERE id = $1;
+
+-- InsertWebhookDeliveries queues an event for every enabled webhook that
+-- subscribed to it.
+-- name: InsertWebhookDeliveries :many
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		event,
+		payload,
+		created_at,
+		next_attempt_at
+	)
+SELECT
+	gen_random_uuid(),
+	webhooks.id,
+	@event :: webhook_event,
+	@payload :: jsonb,
+	@created_at :: timestamptz,
+	@created_at :: timestamptz
+FROM
+	webhooks
+WHERE
+	webhooks.enabled
+	AND @event :: webhook_event = ANY(webhooks.events)
+RETURNING *;
+
+-- AcquireWebhookDelivery claims the delivery that has been due the longest.
+-- The delivery is leased until @lease_expires_at, when another replica may
+-- retry it if the attempt didn't finish. Deliveries to disabled webhooks wait
+-- until the webhook is enabled again.
+-- name: AcquireWebhookDelivery :one
+UPDATE
+	webhook_deliveries
+SET
+	attempts = attempts + 1,
+	last_attempt_at = @now :: timestamptz,
+	next_attempt_at = @lease_expires_at :: timestamptz
+WHERE
+	id = (
+		SELECT
+			nested.id
+		FROM
+			webhook_deliveries AS nested
+			JOIN webhooks ON webhooks.id = nested.webhook_id
+		WHERE
+			nested.next_attempt_at <= @now :: timestamptz
+			AND webhooks.enabled
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE OF nested
+		SKIP LOCKED
+		LIMIT
+			1
+	)
+RETURNING *;
+
+-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	status_code = $2,
+	error = $3,
+	delivered_at = $4,
+	next_attempt_at = $5
+WHERE
+	id = $1;
+
+-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = @webhook_id
+ORDER BY
+	created_at DESC
+OFFSET
+	@offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- Deliveries that are done are kept for 30 days, so admins can review
+-- failures.
+-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM
+	webhook_deliveries
+WHERE
+	next_attempt_at IS NULL
+	AND created_at < NOW() - INTERVAL '30 days';
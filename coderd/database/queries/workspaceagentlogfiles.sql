@@ -0,0 +1,24 @@
+-- name: InsertWorkspaceAgentLogFiles :many
+INSERT INTO
+	workspace_agent_log_files (id, workspace_agent_id, created_at, log_source_id, path)
+SELECT
+	unnest(@id :: uuid [ ]) AS id,
+	@workspace_agent_id :: uuid AS workspace_agent_id,
+	@created_at :: timestamptz AS created_at,
+	unnest(@log_source_id :: uuid [ ]) AS log_source_id,
+	unnest(@path :: text [ ]) AS path
+RETURNING workspace_agent_log_files.*;
+
+-- name: GetWorkspaceAgentLogFilesByAgentID :many
+SELECT * FROM workspace_agent_log_files WHERE workspace_agent_id = $1 ORDER BY path;
+
+-- Logs of the files an agent tails don't count toward the logs limit of the
+-- agent. Instead, only the newest logs of each file are kept.
+-- name: DeleteOldWorkspaceAgentLogsBySourceID :exec
+DELETE FROM workspace_agent_logs WHERE agent_id = @agent_id AND log_source_id = @log_source_id AND id <= (
+	SELECT id FROM (
+		SELECT id, SUM(octet_length(output)) OVER (ORDER BY id DESC) AS newer_length
+		FROM workspace_agent_logs
+		WHERE agent_id = @agent_id AND log_source_id = @log_source_id
+	) AS newer WHERE newer_length > @max_length :: integer ORDER BY id DESC LIMIT 1
+);
@@ -0,0 +1,66 @@
+-- name: GetWorkspaceAgentPortShare :one
+SELECT
+	*
+FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
+
+-- name: ListWorkspaceAgentPortShares :many
+SELECT
+	*
+FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name, port;
+
+-- name: UpsertWorkspaceAgentPortShare :one
+INSERT INTO
+	workspace_agent_port_share (workspace_id, agent_name, port, share_level)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, agent_name, port)
+DO UPDATE SET
+	share_level = $4
+RETURNING *;
+
+-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
+
+-- name: DeleteWorkspaceAgentPortSharesByTemplate :exec
+DELETE FROM
+	workspace_agent_port_share
+WHERE
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = @template_id
+	);
+
+-- name: ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate :exec
+UPDATE
+	workspace_agent_port_share
+SET
+	share_level = 'authenticated'
+WHERE
+	share_level = 'public'
+	AND workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			template_id = @template_id
+	);
@@ -0,0 +1,50 @@
+-- name: GetWorkspaceAgentPTYInvite :one
+SELECT
+	*
+FROM
+	workspace_agent_pty_invites
+WHERE
+	agent_id = $1
+	AND session_id = $2
+	AND user_id = $3;
+
+-- name: GetWorkspaceAgentPTYInvitesBySessionID :many
+SELECT
+	*
+FROM
+	workspace_agent_pty_invites
+WHERE
+	agent_id = $1
+	AND session_id = $2
+ORDER BY
+	created_at;
+
+-- name: GetWorkspaceAgentPTYInvitesByUserID :many
+SELECT
+	*
+FROM
+	workspace_agent_pty_invites
+WHERE
+	user_id = $1
+ORDER BY
+	created_at;
+
+-- name: UpsertWorkspaceAgentPTYInvite :one
+INSERT INTO
+	workspace_agent_pty_invites (workspace_id, agent_id, session_id, user_id, read_only, created_by, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (agent_id, session_id, user_id)
+DO UPDATE SET
+	read_only = $5,
+	created_by = $6,
+	created_at = $7
+RETURNING *;
+
+-- name: DeleteWorkspaceAgentPTYInvite :exec
+DELETE FROM
+	workspace_agent_pty_invites
+WHERE
+	agent_id = $1
+	AND session_id = $2
+	AND user_id = $3;
@@ -99,6 +99,23 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceAgentAutostopInhibitorsByID :exec
+UPDATE
+	workspace_agents
+SET
+	autostop_inhibitors = $2
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceAgentUpdateStateByID :exec
+UPDATE
+	workspace_agents
+SET
+	update_state = $2,
+	update_error = $3
+WHERE
+	id = $1;
+
 -- name: InsertWorkspaceAgentMetadata :exec
 INSERT INTO
 	workspace_agent_metadata (
@@ -156,15 +173,29 @@ WITH new_length AS (
 	logs_length = logs_length + @output_length WHERE workspace_agents.id = @agent_id
 )
 INSERT INTO
-		workspace_agent_logs (agent_id, created_at, output, level, source)
+		workspace_agent_logs (agent_id, created_at, output, level, log_source_id)
 	SELECT
 		@agent_id :: uuid AS agent_id,
 		unnest(@created_at :: timestamptz [ ]) AS created_at,
 		unnest(@output :: VARCHAR(1024) [ ]) AS output,
 		unnest(@level :: log_level [ ]) AS level,
-		unnest(@source :: workspace_agent_log_source [ ]) AS source
+		@log_source_id :: uuid AS log_source_id
 	RETURNING workspace_agent_logs.*;
 
+-- name: InsertWorkspaceAgentLogSources :many
+INSERT INTO
+		workspace_agent_log_sources (workspace_agent_id, created_at, id, display_name, icon)
+	SELECT
+		@workspace_agent_id :: uuid AS workspace_agent_id,
+		@created_at :: timestamptz AS created_at,
+		unnest(@id :: uuid [ ]) AS id,
+		unnest(@display_name :: VARCHAR(127) [ ]) AS display_name,
+		unnest(@icon :: text [ ]) AS icon
+	RETURNING workspace_agent_log_sources.*;
+
+-- name: GetWorkspaceAgentLogSourcesByAgentIDs :many
+SELECT * FROM workspace_agent_log_sources WHERE workspace_agent_id = ANY(@ids :: uuid [ ]);
+
 -- If an agent hasn't connected in the last 7 days, we purge it's logs.
 -- Logs can take up a lot of space, so it's important we clean up frequently.
 -- name: DeleteOldWorkspaceAgentLogs :exec
@@ -70,7 +70,9 @@ FROM
 	workspace_agent_stats
 WHERE
 	template_id = $1 AND
-	connection_count > 0
+	connection_count > 0 AND
+	-- Service accounts are not counted as active users.
+	user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 GROUP BY
 	date, user_id
 ORDER BY
@@ -83,7 +85,9 @@ SELECT
 FROM
 	workspace_agent_stats
 WHERE
-	connection_count > 0
+	connection_count > 0 AND
+	-- Service accounts are not counted as active users.
+	user_id NOT IN (SELECT id FROM users WHERE is_service_account)
 GROUP BY
 	date, user_id
 ORDER BY
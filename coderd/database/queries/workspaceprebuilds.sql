@@ -0,0 +1,83 @@
+-- name: GetWorkspacePrebuildPools :many
+SELECT * FROM workspace_prebuild_pools ORDER BY created_at ASC;
+
+-- name: GetWorkspacePrebuildPoolsByTemplateID :many
+SELECT * FROM workspace_prebuild_pools WHERE template_id = $1 ORDER BY created_at ASC;
+
+-- name: GetWorkspacePrebuildPoolByID :one
+SELECT * FROM workspace_prebuild_pools WHERE id = $1;
+
+-- name: InsertWorkspacePrebuildPool :one
+INSERT INTO
+	workspace_prebuild_pools (
+		id,
+		template_id,
+		template_version_id,
+		parameters,
+		desired_instances,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: UpdateWorkspacePrebuildPoolByID :one
+UPDATE
+	workspace_prebuild_pools
+SET
+	desired_instances = $2,
+	updated_at = $3
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteWorkspacePrebuildPoolByID :exec
+DELETE FROM workspace_prebuild_pools WHERE id = $1;
+
+-- GetWorkspacePrebuilds returns the prebuilt workspaces that were not claimed
+-- or deleted yet.
+-- name: GetWorkspacePrebuilds :many
+SELECT
+	workspace_prebuilds.*
+FROM
+	workspace_prebuilds
+JOIN
+	workspaces ON workspaces.id = workspace_prebuilds.workspace_id
+WHERE
+	workspaces.deleted = false
+ORDER BY
+	workspace_prebuilds.created_at ASC;
+
+-- name: InsertWorkspacePrebuild :exec
+INSERT INTO
+	workspace_prebuilds (
+		workspace_id,
+		pool_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3);
+
+-- DeleteWorkspacePrebuildByWorkspaceID returns no rows if the workspace isn't
+-- a prebuilt workspace anymore, for example because another user claimed it
+-- concurrently.
+-- name: DeleteWorkspacePrebuildByWorkspaceID :one
+DELETE FROM workspace_prebuilds WHERE workspace_id = $1 RETURNING *;
+
+-- ClaimPrebuiltWorkspace transfers a prebuilt workspace to the user that
+-- claimed it.
+-- name: ClaimPrebuiltWorkspace :one
+UPDATE
+	workspaces
+SET
+	owner_id = $2,
+	name = $3,
+	autostart_schedule = $4,
+	ttl = $5,
+	automatic_updates = $6,
+	updated_at = $7,
+	last_used_at = $7
+WHERE
+	id = $1
+	AND deleted = false
+RETURNING *;
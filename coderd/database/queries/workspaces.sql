@@ -319,10 +319,11 @@ INSERT INTO
 		name,
 		autostart_schedule,
 		ttl,
-		last_used_at
+		last_used_at,
+		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
 
 -- name: UpdateWorkspaceDeletedByID :exec
 UPDATE
@@ -358,6 +359,14 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceAutomaticUpdates :exec
+UPDATE
+	workspaces
+SET
+	automatic_updates = $2
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceLastUsedAt :exec
 UPDATE
 	workspaces
@@ -493,6 +502,39 @@ WHERE
 		)
 	) AND workspaces.deleted = 'false';
 
+-- GetWorkspacesApproachingAutostop returns the running workspaces that are
+-- stopped automatically after @now and until @stop_before, so their owners can
+-- be warned.
+-- name: GetWorkspacesApproachingAutostop :many
+SELECT
+	workspaces.id,
+	workspaces.owner_id,
+	workspaces.name,
+	workspace_builds.id AS build_id,
+	workspace_builds.deadline
+FROM
+	workspaces
+INNER JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+	) AND
+	workspace_builds.transition = 'start'::workspace_transition AND
+	provisioner_jobs.completed_at IS NOT NULL AND
+	COALESCE(provisioner_jobs.error, '') = '' AND
+	workspace_builds.deadline > @now :: timestamptz AND
+	workspace_builds.deadline <= @stop_before :: timestamptz AND
+	workspaces.dormant_at IS NULL AND
+	workspaces.deleted = 'false';
+
 -- name: UpdateWorkspaceDormantDeletingAt :one
 UPDATE
 	workspaces
@@ -0,0 +1,55 @@
+-- name: InsertWorkspaceSessionRecording :one
+INSERT INTO
+	workspace_session_recordings (id, workspace_id, agent_id, type, reconnect_id, command, includes_input, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: InsertWorkspaceSessionRecordingChunk :execrows
+INSERT INTO
+	workspace_session_recording_chunks (recording_id, chunk_index, data, created_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (recording_id, chunk_index)
+DO NOTHING;
+
+-- name: UpdateWorkspaceSessionRecordingByID :execrows
+-- Ended recordings keep their end time, and no chunks can be appended to them:
+-- no rows are updated when a chunk is appended to an ended recording.
+UPDATE
+	workspace_session_recordings
+SET
+	updated_at = @updated_at,
+	size = size + @added_size :: bigint,
+	ended_at = COALESCE(ended_at, @ended_at)
+WHERE
+	id = @id
+	AND (ended_at IS NULL OR NOT @appended :: boolean);
+
+-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC;
+
+-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	*
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = $1
+ORDER BY
+	chunk_index;
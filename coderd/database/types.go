@@ -79,3 +79,31 @@ func (m *StringMap) Scan(src interface{}) error {
 func (m StringMap) Value() (driver.Value, error) {
 	return json.Marshal(m)
 }
+
+// AutostopInhibitor is held by a process in a workspace to keep the workspace
+// from being stopped on its deadline.
+type AutostopInhibitor struct {
+	ID        uuid.UUID `json:"id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AutostopInhibitors []AutostopInhibitor
+
+func (a *AutostopInhibitors) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &a)
+	case []byte:
+		return json.Unmarshal(v, &a)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (a AutostopInhibitors) Value() (driver.Value, error) {
+	if a == nil {
+		// Store an empty array rather than null.
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a)
+}
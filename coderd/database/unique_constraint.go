@@ -18,6 +18,7 @@ const (
 	UniqueProvisionerDaemonsNameKey                         UniqueConstraint = "provisioner_daemons_name_key"                             // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_name_key UNIQUE (name);
 	UniqueSiteConfigsKeyKey                                 UniqueConstraint = "site_configs_key_key"                                     // ALTER TABLE ONLY site_configs ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 	UniqueTemplateVersionParametersTemplateVersionIDNameKey UniqueConstraint = "template_version_parameters_template_version_id_name_key" // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
+	UniqueTemplateVersionPresetsTemplateVersionIDNameKey    UniqueConstraint = "template_version_presets_template_version_id_name_key"    // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_template_version_id_name_key UNIQUE (template_version_id, name);
 	UniqueTemplateVersionVariablesTemplateVersionIDNameKey  UniqueConstraint = "template_version_variables_template_version_id_name_key"  // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 	UniqueTemplateVersionsTemplateIDNameKey                 UniqueConstraint = "template_versions_template_id_name_key"                   // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_name_key UNIQUE (template_id, name);
 	UniqueWorkspaceAppStatsUserIDAgentIDSessionIDKey        UniqueConstraint = "workspace_app_stats_user_id_agent_id_session_id_key"      // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_user_id_agent_id_session_id_key UNIQUE (user_id, agent_id, session_id);
@@ -27,14 +28,22 @@ const (
 	UniqueWorkspaceBuildsWorkspaceIDBuildNumberKey          UniqueConstraint = "workspace_builds_workspace_id_build_number_key"           // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 	UniqueWorkspaceProxiesRegionIDUnique                    UniqueConstraint = "workspace_proxies_region_id_unique"                       // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
 	UniqueWorkspaceResourceMetadataName                     UniqueConstraint = "workspace_resource_metadata_name"                         // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
+	UniqueCustomRolesLowerNameOrganizationIDIndex           UniqueConstraint = "custom_roles_lower_name_organization_id_idx"              // CREATE UNIQUE INDEX custom_roles_lower_name_organization_id_idx ON custom_roles USING btree (lower(name), COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
 	UniqueIndexApiKeyName                                   UniqueConstraint = "idx_api_key_name"                                         // CREATE UNIQUE INDEX idx_api_key_name ON api_keys USING btree (user_id, token_name) WHERE (login_type = 'token'::login_type);
 	UniqueIndexOrganizationName                             UniqueConstraint = "idx_organization_name"                                    // CREATE UNIQUE INDEX idx_organization_name ON organizations USING btree (name);
 	UniqueIndexOrganizationNameLower                        UniqueConstraint = "idx_organization_name_lower"                              // CREATE UNIQUE INDEX idx_organization_name_lower ON organizations USING btree (lower(name));
 	UniqueIndexUsersEmail                                   UniqueConstraint = "idx_users_email"                                          // CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted = false);
 	UniqueIndexUsersUsername                                UniqueConstraint = "idx_users_username"                                       // CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
+	UniqueNotificationMessagesUserIDDedupeKeyIndex          UniqueConstraint = "notification_messages_user_id_dedupe_key_idx"             // CREATE UNIQUE INDEX notification_messages_user_id_dedupe_key_idx ON notification_messages USING btree (user_id, dedupe_key) WHERE (dedupe_key <> ''::text);
+	UniqueOauth2ProviderAppCodesHashedSecretIndex           UniqueConstraint = "oauth2_provider_app_codes_hashed_secret_idx"              // CREATE UNIQUE INDEX oauth2_provider_app_codes_hashed_secret_idx ON oauth2_provider_app_codes USING btree (hashed_secret);
+	UniqueOauth2ProviderAppSecretsHashedSecretIndex         UniqueConstraint = "oauth2_provider_app_secrets_hashed_secret_idx"            // CREATE UNIQUE INDEX oauth2_provider_app_secrets_hashed_secret_idx ON oauth2_provider_app_secrets USING btree (hashed_secret);
+	UniqueOauth2ProviderAppTokensApiKeyIDIndex              UniqueConstraint = "oauth2_provider_app_tokens_api_key_id_idx"                // CREATE UNIQUE INDEX oauth2_provider_app_tokens_api_key_id_idx ON oauth2_provider_app_tokens USING btree (api_key_id);
+	UniqueOauth2ProviderAppTokensRefreshHashIndex           UniqueConstraint = "oauth2_provider_app_tokens_refresh_hash_idx"              // CREATE UNIQUE INDEX oauth2_provider_app_tokens_refresh_hash_idx ON oauth2_provider_app_tokens USING btree (refresh_hash);
+	UniqueOauth2ProviderAppsLowerNameIndex                  UniqueConstraint = "oauth2_provider_apps_lower_name_idx"                      // CREATE UNIQUE INDEX oauth2_provider_apps_lower_name_idx ON oauth2_provider_apps USING btree (lower(name));
 	UniqueTemplatesOrganizationIDNameIndex                  UniqueConstraint = "templates_organization_id_name_idx"                       // CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 	UniqueUsersEmailLowerIndex                              UniqueConstraint = "users_email_lower_idx"                                    // CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 	UniqueUsersUsernameLowerIndex                           UniqueConstraint = "users_username_lower_idx"                                 // CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
+	UniqueWebhooksLowerNameIndex                            UniqueConstraint = "webhooks_lower_name_idx"                                  // CREATE UNIQUE INDEX webhooks_lower_name_idx ON webhooks USING btree (lower(name));
 	UniqueWorkspaceProxiesLowerNameIndex                    UniqueConstraint = "workspace_proxies_lower_name_idx"                         // CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 	UniqueWorkspacesOwnerIDLowerIndex                       UniqueConstraint = "workspaces_owner_id_lower_idx"                            // CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
 )
@@ -0,0 +1,166 @@
+// Package deliveryqueue sends the items of a queue that is stored in the
+// database.
+//
+// Items are claimed with a lease, so any replica can send them and an item
+// whose replica went away during the attempt is retried once the lease
+// expired. A Queue sends the due items when it is woken through the pubsub,
+// and on every tick to pick up the items that are due for a retry.
+package deliveryqueue
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+)
+
+// PollInterval is how often queues look for items that are due for a retry.
+const PollInterval = 10 * time.Second
+
+// Backoff returns the delay before the next attempt of an item that failed
+// after the given number of attempts. The delay starts at retryInterval and
+// doubles with every attempt.
+func Backoff(retryInterval time.Duration, attempts int32) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return retryInterval << (attempts - 1)
+}
+
+// Options configure a Queue.
+type Options[T any] struct {
+	Pubsub pubsub.Pubsub
+	// Channel is the pubsub channel that wakes the queue when items are
+	// queued.
+	Channel string
+	// Tick wakes the queue to retry failed items. The queue stops when it is
+	// closed.
+	Tick <-chan time.Time
+	// LeaseDuration is how long an item is claimed by the queue that
+	// attempts it. Send must return before the lease expires.
+	LeaseDuration time.Duration
+	// Concurrency is the number of items that are sent at the same time.
+	// Defaults to 1.
+	Concurrency int
+	// Acquire claims the next due item until leaseExpiresAt. It returns
+	// sql.ErrNoRows when no item is due.
+	Acquire func(ctx context.Context, now, leaseExpiresAt time.Time) (T, error)
+	// Send attempts the item and records the outcome. If ctx is canceled, the
+	// queue is closing and the item should be left to the lease.
+	Send func(ctx context.Context, item T)
+}
+
+// Queue claims the due items and sends them.
+type Queue[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	log  slog.Logger
+	opts Options[T]
+	wake chan struct{}
+}
+
+// New returns a queue that sends items with the given options. The context is
+// passed to Acquire and Send.
+func New[T any](ctx context.Context, log slog.Logger, opts Options[T]) *Queue[T] {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &Queue[T]{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		log:    log,
+		opts:   opts,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Start sends the items that are due, and keeps doing so until the queue is
+// closed.
+//
+// Start should only be called once.
+func (q *Queue[T]) Start() error {
+	cancelSub, err := q.opts.Pubsub.Subscribe(q.opts.Channel, func(_ context.Context, _ []byte) {
+		q.notify()
+	})
+	if err != nil {
+		q.cancel()
+		close(q.done)
+		return xerrors.Errorf("subscribe to %q: %w", q.opts.Channel, err)
+	}
+
+	go func() {
+		defer close(q.done)
+		defer cancelSub()
+		defer q.cancel()
+
+		// Send the items that were queued while no queue ran.
+		q.notify()
+		for {
+			select {
+			case <-q.ctx.Done():
+				return
+			case _, ok := <-q.opts.Tick:
+				if !ok {
+					return
+				}
+			case <-q.wake:
+			}
+			q.sendDue()
+		}
+	}()
+	return nil
+}
+
+// Close stops the queue and waits for the items in flight.
+func (q *Queue[T]) Close() {
+	q.cancel()
+	<-q.done
+}
+
+func (q *Queue[T]) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sendDue sends items until none are due.
+func (q *Queue[T]) sendDue() {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	sem := make(chan struct{}, q.opts.Concurrency)
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case sem <- struct{}{}:
+		}
+
+		now := dbtime.Now()
+		item, err := q.opts.Acquire(q.ctx, now, now.Add(q.opts.LeaseDuration))
+		if err != nil {
+			if !xerrors.Is(err, sql.ErrNoRows) && q.ctx.Err() == nil {
+				q.log.Warn(q.ctx, "acquire queued item", slog.F("channel", q.opts.Channel), slog.Error(err))
+			}
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			q.opts.Send(q.ctx, item)
+		}()
+	}
+}
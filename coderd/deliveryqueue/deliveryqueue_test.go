@@ -0,0 +1,151 @@
+package deliveryqueue_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/deliveryqueue"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// items is a queue of due items that records the items that were sent.
+type items struct {
+	mu      sync.Mutex
+	due     []int
+	sent    []int
+	active  int
+	maxSeen int
+}
+
+func (i *items) push(item ...int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.due = append(i.due, item...)
+}
+
+func (i *items) acquire(_ context.Context, _, _ time.Time) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.due) == 0 {
+		return 0, sql.ErrNoRows
+	}
+	item := i.due[0]
+	i.due = i.due[1:]
+	return item, nil
+}
+
+func (i *items) send(_ context.Context, item int) {
+	i.mu.Lock()
+	i.active++
+	if i.active > i.maxSeen {
+		i.maxSeen = i.active
+	}
+	i.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.active--
+	i.sent = append(i.sent, item)
+}
+
+func (i *items) sentCount() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.sent)
+}
+
+func TestQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Wake", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ps := pubsub.NewInMemory()
+		tick := make(chan time.Time)
+
+		queued := &items{}
+		// Items queued before the start are sent right away.
+		queued.push(1, 2)
+		q := deliveryqueue.New(ctx, slogtest.Make(t, nil), deliveryqueue.Options[int]{
+			Pubsub:        ps,
+			Channel:       "test",
+			Tick:          tick,
+			LeaseDuration: time.Minute,
+			Acquire:       queued.acquire,
+			Send:          queued.send,
+		})
+		require.NoError(t, q.Start())
+		defer q.Close()
+		require.Eventually(t, func() bool {
+			return queued.sentCount() == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Publishing wakes the queue.
+		queued.push(3)
+		require.NoError(t, ps.Publish("test", nil))
+		require.Eventually(t, func() bool {
+			return queued.sentCount() == 3
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// So does a tick.
+		queued.push(4)
+		tick <- time.Now()
+		require.Eventually(t, func() bool {
+			return queued.sentCount() == 4
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		queued.mu.Lock()
+		defer queued.mu.Unlock()
+		require.Equal(t, []int{1, 2, 3, 4}, queued.sent)
+		require.Equal(t, 1, queued.maxSeen)
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		queued := &items{}
+		queued.push(1, 2, 3, 4, 5, 6, 7, 8)
+		q := deliveryqueue.New(ctx, slogtest.Make(t, nil), deliveryqueue.Options[int]{
+			Pubsub:        pubsub.NewInMemory(),
+			Channel:       "test",
+			Tick:          make(chan time.Time),
+			LeaseDuration: time.Minute,
+			Concurrency:   3,
+			Acquire:       queued.acquire,
+			Send:          queued.send,
+		})
+		require.NoError(t, q.Start())
+		defer q.Close()
+		require.Eventually(t, func() bool {
+			return queued.sentCount() == 8
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		queued.mu.Lock()
+		defer queued.mu.Unlock()
+		require.LessOrEqual(t, queued.maxSeen, 3)
+		require.Greater(t, queued.maxSeen, 1)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, time.Second, deliveryqueue.Backoff(time.Second, 0))
+	require.Equal(t, time.Second, deliveryqueue.Backoff(time.Second, 1))
+	require.Equal(t, 4*time.Second, deliveryqueue.Backoff(time.Second, 3))
+}
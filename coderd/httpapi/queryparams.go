@@ -1,6 +1,7 @@
 package httpapi
 
 import (
+	"database/sql"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -79,6 +80,36 @@ func (p *QueryParamParser) Int(vals url.Values, def int, queryParam string) int
 	return v
 }
 
+func (p *QueryParamParser) Boolean(vals url.Values, def bool, queryParam string) bool {
+	v, err := parseQueryParam(p, vals, strconv.ParseBool, def, queryParam)
+	if err != nil {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  queryParam,
+			Detail: fmt.Sprintf("Query param %q must be a valid boolean (%s)", queryParam, err.Error()),
+		})
+	}
+	return v
+}
+
+// NullableBoolean is like Boolean, but the result is only valid if the query
+// param is set.
+func (p *QueryParamParser) NullableBoolean(vals url.Values, def sql.NullBool, queryParam string) sql.NullBool {
+	v, err := parseQueryParam(p, vals, func(v string) (sql.NullBool, error) {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return sql.NullBool{}, err
+		}
+		return sql.NullBool{Bool: b, Valid: true}, nil
+	}, def, queryParam)
+	if err != nil {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  queryParam,
+			Detail: fmt.Sprintf("Query param %q must be a valid boolean (%s)", queryParam, err.Error()),
+		})
+	}
+	return v
+}
+
 func (p *QueryParamParser) Required(queryParam string) *QueryParamParser {
 	p.RequiredParams[queryParam] = true
 	return p
@@ -1,6 +1,7 @@
 package httpapi_test
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -194,6 +195,63 @@ func TestParseQueryParams(t *testing.T) {
 		testQueryParams(t, expParams, parser, parser.Int)
 	})
 
+	t.Run("Boolean", func(t *testing.T) {
+		t.Parallel()
+		expParams := []queryParamTestCase[bool]{
+			{
+				QueryParam: "valid_true",
+				Value:      "true",
+				Expected:   true,
+			},
+			{
+				QueryParam: "valid_false",
+				Value:      "false",
+				Default:    true,
+				Expected:   false,
+			},
+			{
+				QueryParam: "no_value",
+				NoSet:      true,
+				Default:    true,
+				Expected:   true,
+			},
+			{
+				QueryParam:            "invalid_boolean",
+				Value:                 "bogus",
+				Expected:              false,
+				ExpectedErrorContains: "must be a valid boolean",
+			},
+		}
+
+		parser := httpapi.NewQueryParamParser()
+		testQueryParams(t, expParams, parser, parser.Boolean)
+	})
+
+	t.Run("NullableBoolean", func(t *testing.T) {
+		t.Parallel()
+		expParams := []queryParamTestCase[sql.NullBool]{
+			{
+				QueryParam: "valid_false",
+				Value:      "false",
+				Expected:   sql.NullBool{Bool: false, Valid: true},
+			},
+			{
+				QueryParam: "no_value",
+				NoSet:      true,
+				Expected:   sql.NullBool{},
+			},
+			{
+				QueryParam:            "invalid_boolean",
+				Value:                 "bogus",
+				Expected:              sql.NullBool{},
+				ExpectedErrorContains: "must be a valid boolean",
+			},
+		}
+
+		parser := httpapi.NewQueryParamParser()
+		testQueryParams(t, expParams, parser, parser.NullableBoolean)
+	})
+
 	t.Run("UInt", func(t *testing.T) {
 		t.Parallel()
 		expParams := []queryParamTestCase[uint64]{
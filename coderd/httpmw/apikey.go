@@ -23,6 +23,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -150,6 +151,10 @@ func APIKeyFromRequest(ctx context.Context, db database.Store, sessionTokenFunc
 	}
 
 	token := tokenFunc(r)
+	if token == "" && sessionTokenFunc == nil {
+		// OAuth2 apps send their access tokens as bearer tokens.
+		token = bearerTokenFromRequest(r)
+	}
 	if token == "" {
 		return nil, codersdk.Response{
 			Message: SignedOutErrorMessage,
@@ -431,14 +436,30 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	actorRoles, err := rolestore.Expand(ctx, cfg.DB, roles.Roles)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error expanding user's roles. %s", err.Error()),
+		})
+	}
+
+	scope, err := key.RBACScope()
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error reading API key scope. %s", err.Error()),
+		})
+	}
+
 	// Actor is the user's authorization context.
 	authz := Authorization{
 		ActorName: roles.Username,
 		Actor: rbac.Subject{
 			ID:     key.UserID.String(),
-			Roles:  rbac.RoleNames(roles.Roles),
+			Roles:  actorRoles,
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  scope,
 		}.WithCachedASTValue(),
 	}
 
@@ -471,6 +492,16 @@ func APITokenFromRequest(r *http.Request) string {
 	return ""
 }
 
+// bearerTokenFromRequest returns the token of an "Authorization: Bearer"
+// header.
+func bearerTokenFromRequest(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
 // SplitAPIToken verifies the format of an API key and returns the split ID and
 // secret.
 //
@@ -0,0 +1,52 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type customRoleParamContextKey struct{}
+
+// CustomRoleParam returns the custom role extracted via the ExtractCustomRoleParam
+// middleware.
+func CustomRoleParam(r *http.Request) database.CustomRole {
+	role, ok := r.Context().Value(customRoleParamContextKey{}).(database.CustomRole)
+	if !ok {
+		panic("developer error: custom role param middleware not provided")
+	}
+	return role
+}
+
+// ExtractCustomRoleParam grabs a custom role from the "customrole" URL parameter.
+func ExtractCustomRoleParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			roleID, parsed := ParseUUIDParam(rw, r, "customrole")
+			if !parsed {
+				return
+			}
+
+			role, err := db.GetCustomRoleByID(ctx, roleID)
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching custom role.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, customRoleParamContextKey{}, role)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
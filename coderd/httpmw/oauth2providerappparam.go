@@ -0,0 +1,103 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type (
+	oauth2ProviderAppParamContextKey       struct{}
+	oauth2ProviderAppSecretParamContextKey struct{}
+)
+
+// OAuth2ProviderApp returns the OAuth2 app extracted via the
+// ExtractOAuth2ProviderApp middleware.
+func OAuth2ProviderApp(r *http.Request) database.OAuth2ProviderApp {
+	app, ok := r.Context().Value(oauth2ProviderAppParamContextKey{}).(database.OAuth2ProviderApp)
+	if !ok {
+		panic("developer error: oauth2 app param middleware not provided")
+	}
+	return app
+}
+
+// ExtractOAuth2ProviderApp grabs an OAuth2 app from the "app" URL parameter.
+func ExtractOAuth2ProviderApp(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			appID, parsed := ParseUUIDParam(rw, r, "app")
+			if !parsed {
+				return
+			}
+
+			app, err := db.GetOAuth2ProviderAppByID(ctx, appID)
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching OAuth2 app.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, oauth2ProviderAppParamContextKey{}, app)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
+
+// OAuth2ProviderAppSecret returns the OAuth2 app secret extracted via the
+// ExtractOAuth2ProviderAppSecret middleware.
+func OAuth2ProviderAppSecret(r *http.Request) database.OAuth2ProviderAppSecret {
+	secret, ok := r.Context().Value(oauth2ProviderAppSecretParamContextKey{}).(database.OAuth2ProviderAppSecret)
+	if !ok {
+		panic("developer error: oauth2 app secret param middleware not provided")
+	}
+	return secret
+}
+
+// ExtractOAuth2ProviderAppSecret grabs an OAuth2 app secret from the
+// "secretID" URL parameter. It must be used after ExtractOAuth2ProviderApp,
+// since the secret has to belong to that app.
+func ExtractOAuth2ProviderAppSecret(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			app := OAuth2ProviderApp(r)
+
+			secretID, parsed := ParseUUIDParam(rw, r, "secretID")
+			if !parsed {
+				return
+			}
+
+			secret, err := db.GetOAuth2ProviderAppSecretByID(ctx, secretID)
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching OAuth2 app secret.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			// Hide secrets of other apps.
+			if secret.AppID != app.ID {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+
+			ctx = context.WithValue(ctx, oauth2ProviderAppSecretParamContextKey{}, secret)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/httpmw"
+)
+
+func TestOAuth2ProviderAppParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func(db database.Store, appID string, secretID string) *http.Response {
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+
+		router := chi.NewRouter()
+		router.Use(
+			httpmw.ExtractOAuth2ProviderApp(db),
+			httpmw.ExtractOAuth2ProviderAppSecret(db),
+		)
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			_ = httpmw.OAuth2ProviderApp(r)
+			_ = httpmw.OAuth2ProviderAppSecret(r)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("app", appID)
+		rctx.URLParams.Add("secretID", secretID)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		router.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbfake.New()
+		app := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(t, db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+
+		res := setup(db, app.ID.String(), secret.ID.String())
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("AppNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbfake.New()
+		app := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(t, db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+
+		res := setup(db, uuid.NewString(), secret.ID.String())
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("SecretOfOtherApp", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbfake.New()
+		app := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+		other := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(t, db, database.OAuth2ProviderAppSecret{AppID: other.ID})
+
+		res := setup(db, app.ID.String(), secret.ID.String())
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
@@ -1085,6 +1086,83 @@ func TestTemplateInsights_Golden(t *testing.T) {
 	}
 }
 
+func TestTemplateInsights_ServiceAccount(t *testing.T) {
+	t.Parallel()
+
+	db, pubsub := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database:                 db,
+		Pubsub:                   pubsub,
+		IncludeProvisionerDaemon: true,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	agentID := build.Resources[0].Agents[0].ID
+
+	serviceAccount := dbgen.User(t, db, database.User{IsServiceAccount: true})
+
+	// Both the owner and the service account use the workspace.
+	ctx := testutil.Context(t, testutil.WaitLong)
+	now := dbtime.Now()
+	var stats []workspaceapps.StatsReport
+	for _, userID := range []uuid.UUID{owner.UserID, serviceAccount.ID} {
+		dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+			CreatedAt:       now.Add(-5 * time.Minute),
+			UserID:          userID,
+			TemplateID:      template.ID,
+			WorkspaceID:     workspace.ID,
+			AgentID:         agentID,
+			ConnectionCount: 1,
+			SessionCountSSH: 1,
+		})
+		stats = append(stats, workspaceapps.StatsReport{
+			UserID:           userID,
+			WorkspaceID:      workspace.ID,
+			AgentID:          agentID,
+			AccessMethod:     workspaceapps.AccessMethodPath,
+			SlugOrPort:       "8080",
+			SessionID:        uuid.New(),
+			SessionStartedAt: now.Add(-10 * time.Minute),
+			SessionEndedAt:   now.Add(-5 * time.Minute),
+			Requests:         1,
+		})
+	}
+	reporter := workspaceapps.NewStatsDBReporter(db, workspaceapps.DefaultStatsDBReporterBatchSize)
+	//nolint:gocritic // This is a test.
+	err := reporter.Report(dbauthz.AsSystemRestricted(ctx), stats)
+	require.NoError(t, err, "want no error inserting app stats")
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	resp, err := client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		TemplateIDs: []uuid.UUID{template.ID},
+		StartTime:   today.AddDate(0, 0, -1),
+		EndTime:     now.UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+		Interval:    codersdk.InsightsReportIntervalDay,
+	})
+	require.NoError(t, err)
+
+	// The service account is not counted as an active user.
+	require.Equal(t, int64(1), resp.Report.ActiveUsers)
+	var activeUsers int64
+	for _, interval := range resp.IntervalReports {
+		activeUsers += interval.ActiveUsers
+	}
+	require.Equal(t, int64(1), activeUsers)
+	for _, app := range resp.Report.AppsUsage {
+		if app.Slug == "8080" {
+			require.Equal(t, int64(5*60), app.Seconds, "only the owner's port usage is counted")
+		}
+	}
+}
+
 func TestTemplateInsights_BadRequest(t *testing.T) {
 	t.Parallel()
 
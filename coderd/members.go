@@ -10,6 +10,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -81,10 +82,9 @@ func (api *API) updateOrganizationMemberRoles(ctx context.Context, args database
 		if roleOrg != args.OrgID {
 			return database.OrganizationMember{}, xerrors.Errorf("Must only pass roles for org %q", args.OrgID.String())
 		}
-
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.OrganizationMember{}, xerrors.Errorf("%q is not a supported role", r)
-		}
+	}
+	if err := rolestore.Validate(ctx, api.Database, args.GrantedRoles); err != nil {
+		return database.OrganizationMember{}, err
 	}
 
 	updatedUser, err := api.Database.UpdateMemberRoles(ctx, args)
@@ -104,8 +104,7 @@ func convertOrganizationMember(mem database.OrganizationMember) codersdk.Organiz
 	}
 
 	for _, roleName := range mem.Roles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedMember.Roles = append(convertedMember.Roles, db2sdk.Role(rbacRole))
+		convertedMember.Roles = append(convertedMember.Roles, db2sdk.RoleByName(roleName))
 	}
 	return convertedMember
 }
@@ -0,0 +1,252 @@
+package coderd
+
+import (
+	"net/http"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get user notifications
+// @ID get-user-notifications
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Param unread_only query bool false "Only return unread notifications"
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} codersdk.NotificationsResponse
+// @Router /users/{user}/notifications [get]
+func (api *API) notifications(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+	parser := httpapi.NewQueryParamParser()
+	unreadOnly := parser.Boolean(r.URL.Query(), false, "unread_only")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	messages, err := api.Database.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+		UserID:     user.ID,
+		UnreadOnly: unreadOnly,
+		OffsetOpt:  int32(page.Offset),
+		LimitOpt:   int32(page.Limit),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	unread, err := api.Database.CountUnreadNotificationMessagesByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := codersdk.NotificationsResponse{
+		Notifications: make([]codersdk.Notification, 0, len(messages)),
+		UnreadCount:   int(unread),
+	}
+	for _, message := range messages {
+		resp.Notifications = append(resp.Notifications, convertNotification(message))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Mark user notification as read
+// @ID mark-user-notification-as-read
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Param notification path string true "Notification ID" format(uuid)
+// @Success 200 {object} codersdk.Notification
+// @Router /users/{user}/notifications/{notification}/read [put]
+func (api *API) putNotificationRead(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	notificationID, ok := httpmw.ParseUUIDParam(rw, r, "notification")
+	if !ok {
+		return
+	}
+	message, err := api.Database.GetNotificationMessageByID(ctx, notificationID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if message.UserID != user.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	message, err = api.Database.UpdateNotificationMessageReadAtByID(ctx, database.UpdateNotificationMessageReadAtByIDParams{
+		ID:     message.ID,
+		ReadAt: dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotification(message))
+}
+
+// @Summary Mark all user notifications as read
+// @ID mark-all-user-notifications-as-read
+// @Security CoderSessionToken
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/notifications/read [put]
+func (api *API) putNotificationsRead(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	err := api.Database.UpdateNotificationMessagesReadAtByUserID(ctx, database.UpdateNotificationMessagesReadAtByUserIDParams{
+		UserID: user.ID,
+		ReadAt: dbtime.Now(),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get user notification preferences
+// @ID get-user-notification-preferences
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [get]
+func (api *API) notificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	preferences, err := api.Database.GetNotificationPreferencesByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(preferences))
+}
+
+// @Summary Update user notification preferences
+// @ID update-user-notification-preferences
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Notifications
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateNotificationPreferencesRequest true "Update notification preferences request"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [put]
+func (api *API) putNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	var req codersdk.UpdateNotificationPreferencesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	for _, preference := range req.Preferences {
+		if !slices.Contains(codersdk.NotificationKinds, preference.Kind) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid notification preferences.",
+				Validations: []codersdk.ValidationError{
+					{Field: "preferences", Detail: "unknown notification kind " + string(preference.Kind)},
+				},
+			})
+			return
+		}
+	}
+
+	var preferences []database.NotificationPreference
+	err := api.Database.InTx(func(tx database.Store) error {
+		for _, preference := range req.Preferences {
+			_, err := tx.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+				UserID:    user.ID,
+				Kind:      database.NotificationKind(preference.Kind),
+				Disabled:  preference.Disabled,
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		preferences, err = tx.GetNotificationPreferencesByUserID(ctx, user.ID)
+		return err
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(preferences))
+}
+
+func convertNotification(message database.NotificationMessage) codersdk.Notification {
+	notification := codersdk.Notification{
+		ID:        message.ID,
+		Kind:      codersdk.NotificationKind(message.Kind),
+		Title:     message.Title,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+	}
+	if message.ReadAt.Valid {
+		notification.ReadAt = &message.ReadAt.Time
+	}
+	return notification
+}
+
+// convertNotificationPreferences returns the preferences for every kind of
+// notification. Kinds the user has no preference for are enabled.
+func convertNotificationPreferences(preferences []database.NotificationPreference) []codersdk.NotificationPreference {
+	converted := make([]codersdk.NotificationPreference, 0, len(codersdk.NotificationKinds))
+	for _, kind := range codersdk.NotificationKinds {
+		preference := codersdk.NotificationPreference{Kind: kind}
+		for _, p := range preferences {
+			if codersdk.NotificationKind(p.Kind) == kind {
+				preference.Disabled = p.Disabled
+			}
+		}
+		converted = append(converted, preference)
+	}
+	return converted
+}
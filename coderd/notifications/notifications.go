@@ -0,0 +1,97 @@
+// Package notifications tells users about events that concern them, such as
+// their workspace stopping soon or failing to build.
+//
+// Notifications are stored in the database, where users read them in their
+// inbox. When an SMTP server is configured, they are emailed as well: every
+// replica runs a Sender that claims the emails that are due, sends them and
+// schedules a retry with exponential backoff when they fail.
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+)
+
+// EmailChannel is the pubsub channel that wakes the senders of all replicas
+// when notifications are queued to be emailed.
+const EmailChannel = "notification_emails"
+
+// Notification is an event a user is notified about.
+type Notification struct {
+	UserID uuid.UUID
+	Kind   database.NotificationKind
+	// Labels are the values the title and content templates of the kind are
+	// rendered with.
+	Labels map[string]string
+	// DedupeKey identifies the event, so the user is only notified about it
+	// once. Notifications without a key are never deduplicated.
+	DedupeKey string
+}
+
+// Enqueuer stores the notifications of users.
+type Enqueuer struct {
+	db     database.Store
+	pubsub pubsub.Pubsub
+	email  bool
+}
+
+// NewEnqueuer returns an Enqueuer. If email is true, the notifications are also
+// queued to be emailed by a Sender.
+func NewEnqueuer(db database.Store, ps pubsub.Pubsub, email bool) *Enqueuer {
+	return &Enqueuer{
+		db:     db,
+		pubsub: ps,
+		email:  email,
+	}
+}
+
+// Enqueue renders the notification and stores it, unless the user opted out of
+// its kind or was already notified about the event.
+func (e *Enqueuer) Enqueue(ctx context.Context, n Notification) error {
+	title, content, err := Render(n.Kind, n.Labels)
+	if err != nil {
+		return xerrors.Errorf("render %s notification: %w", n.Kind, err)
+	}
+
+	//nolint:gocritic // Notifications are sent by the system, not by users.
+	_, err = e.db.InsertNotificationMessage(dbauthz.AsSystemRestricted(ctx), database.InsertNotificationMessageParams{
+		ID:        uuid.New(),
+		UserID:    n.UserID,
+		Kind:      n.Kind,
+		Title:     title,
+		Content:   content,
+		DedupeKey: n.DedupeKey,
+		CreatedAt: dbtime.Now(),
+		Email:     e.email,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// The user opted out, or was already notified.
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("insert notification message: %w", err)
+	}
+	if !e.email {
+		return nil
+	}
+
+	err = e.pubsub.Publish(EmailChannel, nil)
+	if err != nil {
+		return xerrors.Errorf("publish notification email: %w", err)
+	}
+	return nil
+}
+
+// FormatTime formats times in the labels of notifications.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
@@ -0,0 +1,274 @@
+package notifications_test
+
+import (
+	"io"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/smtptest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestEnqueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Rendered", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitShort)
+			db, pubsub = dbtestutil.NewDB(t)
+			user       = dbgen.User(t, db, database.User{})
+		)
+		enqueuer := notifications.NewEnqueuer(db, pubsub, false)
+
+		err := enqueuer.Enqueue(ctx, notifications.Notification{
+			UserID: user.ID,
+			Kind:   database.NotificationKindWorkspaceBuildFailed,
+			Labels: map[string]string{
+				"workspace":    "dev",
+				"transition":   "start",
+				"build_number": "3",
+				"error":        "exit status 1",
+			},
+		})
+		require.NoError(t, err)
+
+		messages, err := db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.Equal(t, `Workspace "dev" failed to start`, messages[0].Title)
+		require.Equal(t, `Build #3 of your workspace "dev" failed: exit status 1`, messages[0].Content)
+		require.False(t, messages[0].ReadAt.Valid)
+		require.False(t, messages[0].EmailNextAttemptAt.Valid, "emails are disabled")
+	})
+
+	t.Run("MissingLabel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitShort)
+			db, pubsub = dbtestutil.NewDB(t)
+			user       = dbgen.User(t, db, database.User{})
+		)
+		enqueuer := notifications.NewEnqueuer(db, pubsub, false)
+
+		err := enqueuer.Enqueue(ctx, notifications.Notification{
+			UserID: user.ID,
+			Kind:   database.NotificationKindWorkspaceDormant,
+			Labels: map[string]string{"workspace": "dev"},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Deduplicated", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitShort)
+			db, pubsub = dbtestutil.NewDB(t)
+			user       = dbgen.User(t, db, database.User{})
+		)
+		enqueuer := notifications.NewEnqueuer(db, pubsub, false)
+
+		notification := notifications.Notification{
+			UserID: user.ID,
+			Kind:   database.NotificationKindWorkspaceAutostop,
+			Labels: map[string]string{
+				"workspace": "dev",
+				"deadline":  notifications.FormatTime(time.Now()),
+			},
+			DedupeKey: "build",
+		}
+		require.NoError(t, enqueuer.Enqueue(ctx, notification))
+		require.NoError(t, enqueuer.Enqueue(ctx, notification))
+
+		count, err := db.CountUnreadNotificationMessagesByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, count)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitShort)
+			db, pubsub = dbtestutil.NewDB(t)
+			user       = dbgen.User(t, db, database.User{})
+		)
+		_, err := db.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+			UserID:   user.ID,
+			Kind:     database.NotificationKindWorkspaceDormant,
+			Disabled: true,
+		})
+		require.NoError(t, err)
+		enqueuer := notifications.NewEnqueuer(db, pubsub, false)
+
+		err = enqueuer.Enqueue(ctx, notifications.Notification{
+			UserID: user.ID,
+			Kind:   database.NotificationKindWorkspaceDormant,
+			Labels: map[string]string{
+				"workspace":    "dev",
+				"last_used_at": notifications.FormatTime(time.Now()),
+			},
+		})
+		require.NoError(t, err)
+
+		count, err := db.CountUnreadNotificationMessagesByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{
+		"workspace":    "dev",
+		"email":        "dev@example.com",
+		"last_seen_at": "2023-07-01 12:00 UTC",
+		"deadline":     "2023-10-01 12:00 UTC",
+		"last_used_at": "2023-09-01 12:00 UTC",
+		"deleting_at":  "2023-10-08 12:00 UTC",
+		"transition":   "stop",
+		"build_number": "2",
+		"error":        "timeout",
+	}
+	for _, kind := range database.AllNotificationKindValues() {
+		title, content, err := notifications.Render(kind, labels)
+		require.NoError(t, err, kind)
+		require.Contains(t, title, "dev", kind)
+		require.NotEmpty(t, content, kind)
+	}
+}
+
+func TestSender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sent", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitLong)
+			db, pubsub = dbtestutil.NewDB(t)
+			log        = slogtest.Make(t, nil)
+			server     = smtptest.New(t)
+			user       = dbgen.User(t, db, database.User{Email: "alice@example.com"})
+		)
+		sender := notifications.NewSender(ctx, db, pubsub, log, codersdk.NotificationsEmailConfig{
+			From:      clibase.String("Coder <coder@example.com>"),
+			Smarthost: clibase.String(server.Addr),
+			Hello:     "localhost",
+		}, make(chan time.Time))
+		require.NoError(t, sender.Start())
+		defer sender.Close()
+
+		err := notifications.NewEnqueuer(db, pubsub, true).Enqueue(ctx, notifications.Notification{
+			UserID: user.ID,
+			Kind:   database.NotificationKindWorkspaceMarkedForDeletion,
+			Labels: map[string]string{
+				"workspace":   "dev",
+				"deleting_at": "2023-10-08 12:00 UTC",
+			},
+		})
+		require.NoError(t, err)
+
+		var msg smtptest.Message
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the email")
+		case msg = <-server.Messages():
+		}
+		require.Equal(t, "coder@example.com", msg.From)
+		require.Equal(t, []string{"alice@example.com"}, msg.To)
+		require.Contains(t, msg.Data, `Subject: Workspace "dev" will be deleted`)
+		parsed, err := mail.ReadMessage(strings.NewReader(msg.Data))
+		require.NoError(t, err)
+		body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "2023-10-08 12:00 UTC")
+
+		var messages []database.NotificationMessage
+		require.Eventually(t, func() bool {
+			messages, err = db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+				UserID: user.ID,
+			})
+			return assert.NoError(t, err) && len(messages) == 1 && messages[0].EmailedAt.Valid
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.EqualValues(t, 1, messages[0].EmailAttempts)
+		require.False(t, messages[0].EmailNextAttemptAt.Valid)
+		require.Empty(t, messages[0].EmailError)
+	})
+
+	t.Run("Retried", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitLong)
+			db, pubsub = dbtestutil.NewDB(t)
+			log        = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+			server     = smtptest.New(t)
+			user       = dbgen.User(t, db, database.User{Email: "alice@example.com"})
+		)
+		server.Fail(1)
+		sender := notifications.NewSender(ctx, db, pubsub, log, codersdk.NotificationsEmailConfig{
+			From:      "coder@example.com",
+			Smarthost: clibase.String(server.Addr),
+		}, make(chan time.Time))
+		require.NoError(t, sender.Start())
+		defer sender.Close()
+
+		before := time.Now()
+		err := notifications.NewEnqueuer(db, pubsub, true).Enqueue(ctx, notifications.Notification{
+			UserID: user.ID,
+			Kind:   database.NotificationKindWorkspaceDormant,
+			Labels: map[string]string{
+				"workspace":    "dev",
+				"last_used_at": "2023-09-01 12:00 UTC",
+			},
+		})
+		require.NoError(t, err)
+
+		var messages []database.NotificationMessage
+		require.Eventually(t, func() bool {
+			messages, err = db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+				UserID: user.ID,
+			})
+			return assert.NoError(t, err) && len(messages) == 1 && messages[0].EmailError != ""
+		}, testutil.WaitShort, testutil.IntervalFast)
+		message := messages[0]
+		require.EqualValues(t, 1, message.EmailAttempts)
+		require.Contains(t, message.EmailError, "451")
+		require.False(t, message.EmailedAt.Valid)
+		require.True(t, message.EmailNextAttemptAt.Valid)
+		require.WithinDuration(t, before.Add(notifications.RetryInterval), message.EmailNextAttemptAt.Time, testutil.WaitShort)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, notifications.RetryInterval, notifications.Backoff(1))
+	require.Equal(t, 2*notifications.RetryInterval, notifications.Backoff(2))
+	require.Equal(t, 8*notifications.RetryInterval, notifications.Backoff(notifications.MaxAttempts-1))
+}
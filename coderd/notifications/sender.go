@@ -0,0 +1,238 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/deliveryqueue"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// MaxAttempts is the number of times an email is attempted before it is
+	// given up.
+	MaxAttempts = 5
+	// RetryInterval is the delay before the first retry of a failed email. It
+	// doubles with every attempt.
+	RetryInterval = time.Minute
+	// PollInterval is how often senders look for emails that are due for a
+	// retry.
+	PollInterval = deliveryqueue.PollInterval
+
+	// leaseDuration is how long an email is claimed by the sender that
+	// attempts it. If the replica goes away during the attempt, another
+	// replica retries the email once the lease expired.
+	leaseDuration = time.Minute
+	// sendTimeout must be shorter than leaseDuration.
+	sendTimeout = 30 * time.Second
+)
+
+// Backoff returns the delay before the next attempt of an email that failed
+// after the given number of attempts.
+func Backoff(attempts int32) time.Duration {
+	return deliveryqueue.Backoff(RetryInterval, attempts)
+}
+
+// Sender emails the queued notifications through an SMTP server.
+type Sender struct {
+	*deliveryqueue.Queue[database.NotificationMessage]
+
+	db  database.Store
+	log slog.Logger
+	cfg codersdk.NotificationsEmailConfig
+}
+
+// NewSender returns a sender that emails notifications when it is woken
+// through the pubsub, and on every tick to retry failed emails. Emails are
+// sent one at a time to go easy on the SMTP server.
+func NewSender(ctx context.Context, db database.Store, ps pubsub.Pubsub, log slog.Logger, cfg codersdk.NotificationsEmailConfig, tick <-chan time.Time) *Sender {
+	s := &Sender{
+		db:  db,
+		log: log,
+		cfg: cfg,
+	}
+	//nolint:gocritic // The sender reads the notifications and emails of all users.
+	s.Queue = deliveryqueue.New(dbauthz.AsSystemRestricted(ctx), log, deliveryqueue.Options[database.NotificationMessage]{
+		Pubsub:        ps,
+		Channel:       EmailChannel,
+		Tick:          tick,
+		LeaseDuration: leaseDuration,
+		Acquire: func(ctx context.Context, now, leaseExpiresAt time.Time) (database.NotificationMessage, error) {
+			return db.AcquireNotificationMessageEmail(ctx, database.AcquireNotificationMessageEmailParams{
+				Now:            now,
+				LeaseExpiresAt: leaseExpiresAt,
+			})
+		},
+		Send: s.send,
+	})
+	return s
+}
+
+func (s *Sender) send(ctx context.Context, message database.NotificationMessage) {
+	log := s.log.With(
+		slog.F("notification_id", message.ID),
+		slog.F("user_id", message.UserID),
+		slog.F("kind", message.Kind),
+		slog.F("attempt", message.EmailAttempts),
+	)
+
+	user, err := s.db.GetUserByID(ctx, message.UserID)
+	if err != nil {
+		// The lease expires and the email is retried.
+		log.Warn(ctx, "get notification recipient", slog.Error(err))
+		return
+	}
+
+	err = s.email(ctx, user, message)
+	if ctx.Err() != nil {
+		// The sender is closing. The lease expires and the email is retried.
+		return
+	}
+	update := database.UpdateNotificationMessageEmailByIDParams{
+		ID: message.ID,
+	}
+	switch {
+	case err == nil:
+		update.EmailedAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+	case message.EmailAttempts >= MaxAttempts:
+		update.EmailError = err.Error()
+		log.Warn(ctx, "notification email failed, giving up", slog.Error(err))
+	default:
+		update.EmailError = err.Error()
+		update.EmailNextAttemptAt = sql.NullTime{Time: dbtime.Now().Add(Backoff(message.EmailAttempts)), Valid: true}
+		log.Debug(ctx, "notification email failed, retrying",
+			slog.F("next_attempt_at", update.EmailNextAttemptAt.Time), slog.Error(err))
+	}
+
+	err = s.db.UpdateNotificationMessageEmailByID(ctx, update)
+	if err != nil {
+		log.Error(ctx, "update notification email", slog.Error(err))
+	}
+}
+
+// email sends the notification to the email address of the user.
+func (s *Sender) email(ctx context.Context, user database.User, message database.NotificationMessage) error {
+	if user.Deleted {
+		return xerrors.New("the user was deleted")
+	}
+	if user.Email == "" {
+		return xerrors.New("the user has no email address")
+	}
+	from, err := mail.ParseAddress(s.cfg.From.String())
+	if err != nil {
+		return xerrors.Errorf("parse from address: %w", err)
+	}
+	to := mail.Address{Address: user.Email}
+
+	var body bytes.Buffer
+	qp := quotedprintable.NewWriter(&body)
+	_, _ = qp.Write([]byte(message.Content))
+	_ = qp.Close()
+
+	var msg bytes.Buffer
+	_, _ = fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	_, _ = fmt.Fprintf(&msg, "To: %s\r\n", to.String())
+	_, _ = fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Title))
+	_, _ = fmt.Fprintf(&msg, "Date: %s\r\n", message.CreatedAt.Format(time.RFC1123Z))
+	_, _ = fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", message.ID, s.cfg.Hello.String())
+	_, _ = fmt.Fprint(&msg, "MIME-Version: 1.0\r\n")
+	_, _ = fmt.Fprint(&msg, "Content-Type: text/plain; charset=UTF-8\r\n")
+	_, _ = fmt.Fprint(&msg, "Content-Transfer-Encoding: quoted-printable\r\n")
+	_, _ = fmt.Fprint(&msg, "\r\n")
+	_, _ = msg.Write(body.Bytes())
+
+	return s.sendMail(ctx, from.Address, to.Address, msg.Bytes())
+}
+
+// sendMail delivers the message to the SMTP server. net/smtp.SendMail isn't
+// used because it can't be canceled.
+func (s *Sender) sendMail(ctx context.Context, from, to string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	smarthost := s.cfg.Smarthost.String()
+	host, _, err := net.SplitHostPort(smarthost)
+	if err != nil {
+		return xerrors.Errorf("parse smarthost: %w", err)
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", smarthost)
+	if err != nil {
+		return xerrors.Errorf("dial smarthost: %w", err)
+	}
+	defer conn.Close()
+	// Interrupt the conversation when the sender is closed or it times out.
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return xerrors.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if hello := s.cfg.Hello.String(); hello != "" {
+		err = client.Hello(hello)
+		if err != nil {
+			return xerrors.Errorf("smtp hello: %w", err)
+		}
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		})
+		if err != nil {
+			return xerrors.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if username := s.cfg.Username.String(); username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return xerrors.New("the smtp server doesn't support authentication")
+		}
+		err = client.Auth(smtp.PlainAuth("", username, s.cfg.Password.String(), host))
+		if err != nil {
+			return xerrors.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	err = client.Mail(from)
+	if err != nil {
+		return xerrors.Errorf("smtp mail: %w", err)
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return xerrors.Errorf("smtp rcpt: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return xerrors.Errorf("smtp data: %w", err)
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return xerrors.Errorf("write message: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return xerrors.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,199 @@
+// Package smtptest provides an SMTP server that records the emails it receives,
+// for testing code that sends emails.
+package smtptest
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Message is an email received by the server.
+type Message struct {
+	From string
+	To   []string
+	// Data is the message including its headers, with CRLF line endings
+	// converted to LF.
+	Data string
+}
+
+// Server is a minimal SMTP server. It doesn't support TLS or authentication.
+type Server struct {
+	// Addr is the host:port the server listens on.
+	Addr string
+
+	listener net.Listener
+	messages chan Message
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	failures int
+	closed   bool
+}
+
+// New starts a server that is closed when the test ends.
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		listener: listener,
+		messages: make(chan Message, 100),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.accept()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Messages returns the channel the received messages are sent to.
+func (s *Server) Messages() <-chan Message {
+	return s.messages
+}
+
+// Fail makes the server reject the next n messages with a temporary error.
+func (s *Server) Fail(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// Close stops the server and closes open connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	_ = s.listener.Close()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				_ = conn.Close()
+			}()
+			s.serve(textproto.NewConn(conn))
+		}()
+	}
+}
+
+// fail returns true if the current message should be rejected.
+func (s *Server) fail() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures <= 0 {
+		return false
+	}
+	s.failures--
+	return true
+}
+
+func (s *Server) serve(conn *textproto.Conn) {
+	reply := func(format string, args ...interface{}) bool {
+		return conn.PrintfLine(format, args...) == nil
+	}
+	if !reply("220 localhost smtptest") {
+		return
+	}
+
+	var msg Message
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		var ok bool
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = reply("250-localhost") && reply("250 8BITMIME")
+		case "HELO":
+			ok = reply("250 localhost")
+		case "MAIL":
+			if s.fail() {
+				ok = reply("451 Temporary failure, try again later")
+				break
+			}
+			msg = Message{From: address(arg)}
+			ok = reply("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			ok = reply("250 OK")
+		case "DATA":
+			if !reply("354 End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+			select {
+			case s.messages <- msg:
+				ok = reply("250 OK")
+			default:
+				ok = reply("452 Too many messages")
+			}
+			msg = Message{}
+		case "RSET":
+			msg = Message{}
+			ok = reply("250 OK")
+		case "NOOP":
+			ok = reply("250 OK")
+		case "QUIT":
+			_ = reply("221 Bye")
+			return
+		default:
+			ok = reply("502 Command not implemented")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// address returns the address in a path like "FROM:<user@example.com>".
+func address(arg string) string {
+	start := strings.Index(arg, "<")
+	end := strings.Index(arg, ">")
+	if start == -1 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
@@ -0,0 +1,74 @@
+package notifications
+
+import (
+	"strings"
+	"text/template"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+type messageTemplate struct {
+	title   *template.Template
+	content *template.Template
+}
+
+func newMessageTemplate(kind database.NotificationKind, title, content string) messageTemplate {
+	return messageTemplate{
+		title:   template.Must(template.New(string(kind)).Option("missingkey=error").Parse(title)),
+		content: template.Must(template.New(string(kind)).Option("missingkey=error").Parse(content)),
+	}
+}
+
+// templates are the title and content of every kind of notification. They are
+// rendered when the notification is queued, so changes only apply to new
+// notifications.
+var templates = map[database.NotificationKind]messageTemplate{
+	database.NotificationKindWorkspaceAutostop: newMessageTemplate(database.NotificationKindWorkspaceAutostop,
+		`Workspace "{{.workspace}}" will stop soon`,
+		`Your workspace "{{.workspace}}" will stop automatically at {{.deadline}}. `+
+			`Using the workspace, or extending its deadline, keeps it running.`,
+	),
+	database.NotificationKindWorkspaceDormant: newMessageTemplate(database.NotificationKindWorkspaceDormant,
+		`Workspace "{{.workspace}}" is dormant`,
+		`Your workspace "{{.workspace}}" was marked dormant because it was not used since {{.last_used_at}}. `+
+			`Dormant workspaces can't be started until they are activated again.`,
+	),
+	database.NotificationKindWorkspaceMarkedForDeletion: newMessageTemplate(database.NotificationKindWorkspaceMarkedForDeletion,
+		`Workspace "{{.workspace}}" will be deleted`,
+		`Your dormant workspace "{{.workspace}}" will be deleted automatically at {{.deleting_at}}. `+
+			`Activate it before then to keep it.`,
+	),
+	database.NotificationKindWorkspaceBuildFailed: newMessageTemplate(database.NotificationKindWorkspaceBuildFailed,
+		`Workspace "{{.workspace}}" failed to {{.transition}}`,
+		`Build #{{.build_number}} of your workspace "{{.workspace}}" failed: {{.error}}`,
+	),
+	database.NotificationKindAccountDormant: newMessageTemplate(database.NotificationKindAccountDormant,
+		`Your account "{{.email}}" is dormant`,
+		`Your account "{{.email}}" was marked dormant because it was not used since {{.last_seen_at}}. `+
+			`Dormant accounts don't count towards the user limit of the license. Log in to activate your account again.`,
+	),
+}
+
+// Render returns the title and content of a notification of the kind.
+func Render(kind database.NotificationKind, labels map[string]string) (title string, content string, err error) {
+	tmpl, ok := templates[kind]
+	if !ok {
+		return "", "", xerrors.Errorf("unknown notification kind %q", kind)
+	}
+
+	var b strings.Builder
+	err = tmpl.title.Execute(&b, labels)
+	if err != nil {
+		return "", "", xerrors.Errorf("render title: %w", err)
+	}
+	title = b.String()
+
+	b.Reset()
+	err = tmpl.content.Execute(&b, labels)
+	if err != nil {
+		return "", "", xerrors.Errorf("render content: %w", err)
+	}
+	return title, b.String(), nil
+}
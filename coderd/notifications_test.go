@@ -0,0 +1,126 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNotifications(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Inbox", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, api := coderdtest.NewWithAPI(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		first := dbgen.NotificationMessage(t, api.Database, database.NotificationMessage{UserID: member.ID})
+		second := dbgen.NotificationMessage(t, api.Database, database.NotificationMessage{
+			UserID:    member.ID,
+			CreatedAt: first.CreatedAt.Add(time.Second),
+		})
+		// Notifications of other users aren't returned.
+		other := dbgen.NotificationMessage(t, api.Database, database.NotificationMessage{UserID: owner.UserID})
+
+		res, err := memberClient.Notifications(ctx, codersdk.Me, codersdk.NotificationsRequest{})
+		require.NoError(t, err)
+		require.Equal(t, 2, res.UnreadCount)
+		require.Len(t, res.Notifications, 2)
+		require.Equal(t, second.ID, res.Notifications[0].ID)
+		require.Equal(t, first.ID, res.Notifications[1].ID)
+		require.Equal(t, first.Title, res.Notifications[1].Title)
+		require.Nil(t, res.Notifications[1].ReadAt)
+
+		read, err := memberClient.MarkNotificationRead(ctx, codersdk.Me, first.ID)
+		require.NoError(t, err)
+		require.NotNil(t, read.ReadAt)
+
+		res, err = memberClient.Notifications(ctx, codersdk.Me, codersdk.NotificationsRequest{UnreadOnly: true})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.UnreadCount)
+		require.Len(t, res.Notifications, 1)
+		require.Equal(t, second.ID, res.Notifications[0].ID)
+
+		// Users can't mark the notifications of other users as read.
+		_, err = memberClient.MarkNotificationRead(ctx, codersdk.Me, other.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		err = memberClient.MarkNotificationsRead(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		res, err = memberClient.Notifications(ctx, codersdk.Me, codersdk.NotificationsRequest{UnreadOnly: true})
+		require.NoError(t, err)
+		require.Zero(t, res.UnreadCount)
+		require.Empty(t, res.Notifications)
+
+		res, err = client.Notifications(ctx, codersdk.Me, codersdk.NotificationsRequest{})
+		require.NoError(t, err)
+		require.Equal(t, 1, res.UnreadCount)
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := memberClient.Notifications(ctx, owner.UserID.String(), codersdk.NotificationsRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Preferences", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		preferences, err := memberClient.NotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, preferences, len(codersdk.NotificationKinds))
+		for _, preference := range preferences {
+			require.False(t, preference.Disabled)
+		}
+
+		preferences, err = memberClient.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{
+				{Kind: codersdk.NotificationKindWorkspaceAutostop, Disabled: true},
+			},
+		})
+		require.NoError(t, err)
+		for _, preference := range preferences {
+			require.Equal(t, preference.Kind == codersdk.NotificationKindWorkspaceAutostop, preference.Disabled, preference.Kind)
+		}
+
+		preferences, err = memberClient.NotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Contains(t, preferences, codersdk.NotificationPreference{Kind: codersdk.NotificationKindWorkspaceAutostop, Disabled: true})
+
+		_, err = memberClient.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{
+				{Kind: "unknown", Disabled: true},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
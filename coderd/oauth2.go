@@ -0,0 +1,405 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get OAuth2 apps
+// @ID get-oauth2-apps
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param user_id query string false "Filter by the apps the user has authorized" format(uuid)
+// @Success 200 {array} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [get]
+func (api *API) oAuth2ProviderApps(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var (
+		dbapps []database.OAuth2ProviderApp
+		err    error
+	)
+	if rawUserID := r.URL.Query().Get("user_id"); rawUserID != "" {
+		userID, parseErr := uuid.Parse(rawUserID)
+		if parseErr != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid user ID.",
+				Detail:  parseErr.Error(),
+			})
+			return
+		}
+		dbapps, err = api.Database.GetOAuth2ProviderAppsByUserID(ctx, userID)
+	} else {
+		dbapps, err = api.Database.GetOAuth2ProviderApps(ctx)
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	apps := make([]codersdk.OAuth2ProviderApp, 0, len(dbapps))
+	for _, app := range dbapps {
+		apps = append(apps, api.convertOAuth2ProviderApp(app))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apps)
+}
+
+// @Summary Get OAuth2 app
+// @ID get-oauth2-app
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [get]
+func (api *API) oAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	app := httpmw.OAuth2ProviderApp(r)
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Create OAuth2 app
+// @ID create-oauth2-app
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param request body codersdk.PostOAuth2ProviderAppRequest true "The OAuth2 app to create."
+// @Success 201 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [post]
+func (api *API) postOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.PostOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	now := dbtime.Now()
+	app, err := api.Database.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
+		ID:           uuid.New(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Name:         req.Name,
+		Icon:         req.Icon,
+		RedirectURIs: req.RedirectURIs,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = app
+
+	httpapi.Write(ctx, rw, http.StatusCreated, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Update OAuth2 app
+// @ID update-oauth2-app
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Param request body codersdk.PutOAuth2ProviderAppRequest true "Update an OAuth2 app."
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [put]
+func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = app
+
+	var req codersdk.PutOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	app, err := api.Database.UpdateOAuth2ProviderAppByID(ctx, database.UpdateOAuth2ProviderAppByIDParams{
+		ID:           app.ID,
+		UpdatedAt:    dbtime.Now(),
+		Name:         req.Name,
+		Icon:         req.Icon,
+		RedirectURIs: req.RedirectURIs,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = app
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Delete OAuth2 app
+// @ID delete-oauth2-app
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 204
+// @Router /oauth2-provider/apps/{app} [delete]
+func (api *API) deleteOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = app
+
+	err := api.Database.DeleteOAuth2ProviderAppByID(ctx, app.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get OAuth2 app secrets
+// @ID get-oauth2-app-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {array} codersdk.OAuth2ProviderAppSecret
+// @Router /oauth2-provider/apps/{app}/secrets [get]
+func (api *API) oAuth2ProviderAppSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	app := httpmw.OAuth2ProviderApp(r)
+
+	dbsecrets, err := api.Database.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	secrets := make([]codersdk.OAuth2ProviderAppSecret, 0, len(dbsecrets))
+	for _, secret := range dbsecrets {
+		secrets = append(secrets, codersdk.OAuth2ProviderAppSecret{
+			ID:                    secret.ID,
+			LastUsedAt:            codersdk.NullTime{NullTime: secret.LastUsedAt},
+			ClientSecretTruncated: secret.DisplaySecret,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, secrets)
+}
+
+// @Summary Create OAuth2 app secret
+// @ID create-oauth2-app-secret
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 201 {object} codersdk.OAuth2ProviderAppSecretFull
+// @Router /oauth2-provider/apps/{app}/secrets [post]
+func (api *API) postOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	secret, hashed, err := generateOAuth2ProviderSecret()
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	dbsecret, err := api.Database.InsertOAuth2ProviderAppSecret(ctx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:           uuid.New(),
+		AppID:        app.ID,
+		CreatedAt:    dbtime.Now(),
+		HashedSecret: hashed,
+		// Only the last characters are kept in plain text, so users can tell
+		// their secrets apart.
+		DisplaySecret: secret[len(secret)-6:],
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = dbsecret
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.OAuth2ProviderAppSecretFull{
+		ID:               dbsecret.ID,
+		ClientSecretFull: secret,
+	})
+}
+
+// @Summary Delete OAuth2 app secret
+// @ID delete-oauth2-app-secret
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Param secretID path string true "Secret ID" format(uuid)
+// @Success 204
+// @Router /oauth2-provider/apps/{app}/secrets/{secretID} [delete]
+func (api *API) deleteOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		secret            = httpmw.OAuth2ProviderAppSecret(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = secret
+
+	err := api.Database.DeleteOAuth2ProviderAppSecretByID(ctx, secret.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Revoke OAuth2 app tokens
+// @ID revoke-oauth2-app-tokens
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 204
+// @Router /oauth2-provider/apps/{app}/tokens [delete]
+func (api *API) deleteOAuth2ProviderAppTokens(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		app    = httpmw.OAuth2ProviderApp(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		// Codes that were not exchanged yet would otherwise still grant the
+		// app new tokens.
+		err := tx.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: apiKey.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: apiKey.UserID,
+		})
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (api *API) convertOAuth2ProviderApp(app database.OAuth2ProviderApp) codersdk.OAuth2ProviderApp {
+	return codersdk.OAuth2ProviderApp{
+		ID:           app.ID,
+		Name:         app.Name,
+		Icon:         app.Icon,
+		RedirectURIs: app.RedirectURIs,
+		Endpoints: codersdk.OAuth2AppEndpoints{
+			Authorization: api.AccessURL.JoinPath("/oauth2/authorize").String(),
+			Token:         api.AccessURL.JoinPath("/oauth2/tokens").String(),
+			Revocation:    api.AccessURL.JoinPath("/oauth2/revoke").String(),
+		},
+	}
+}
@@ -0,0 +1,600 @@
+package coderd_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"html"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOAuth2ProviderApps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:         "dashboard",
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, client.URL.String()+"/oauth2/authorize", app.Endpoints.Authorization)
+		require.Equal(t, client.URL.String()+"/oauth2/tokens", app.Endpoints.Token)
+
+		apps, err := client.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.OAuth2ProviderApp{app}, apps)
+
+		updated, err := client.PutOAuth2ProviderApp(ctx, app.ID, codersdk.PutOAuth2ProviderAppRequest{
+			Name:         "dashboard",
+			Icon:         "/icon/code.svg",
+			RedirectURIs: []string{"http://localhost:3000/callback", "http://localhost:3001/callback"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "/icon/code.svg", updated.Icon)
+		require.Len(t, updated.RedirectURIs, 2)
+
+		fetched, err := client.OAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, fetched)
+
+		err = client.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		_, err = client.OAuth2ProviderApp(ctx, app.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		logs := auditor.AuditLogs()
+		require.GreaterOrEqual(t, len(logs), 3)
+		for i, action := range []database.AuditAction{database.AuditActionCreate, database.AuditActionWrite, database.AuditActionDelete} {
+			auditLog := logs[len(logs)-3+i]
+			require.Equal(t, database.ResourceTypeOAuth2ProviderApp, auditLog.ResourceType)
+			require.Equal(t, action, auditLog.Action)
+			require.Equal(t, app.ID, auditLog.ResourceID)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		for _, req := range []codersdk.PostOAuth2ProviderAppRequest{
+			{Name: "no-uris"},
+			{Name: "bad-uri", RedirectURIs: []string{"not a url"}},
+			{RedirectURIs: []string{"http://localhost:3000/callback"}},
+		} {
+			_, err := client.PostOAuth2ProviderApp(ctx, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, req.Name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), req.Name)
+		}
+
+		req := codersdk.PostOAuth2ProviderAppRequest{
+			Name:         "duplicate",
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+		}
+		_, err := client.PostOAuth2ProviderApp(ctx, req)
+		require.NoError(t, err)
+		_, err = client.PostOAuth2ProviderApp(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCanOnlyRead", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:         "dashboard",
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+		})
+		require.NoError(t, err)
+
+		_, err = member.OAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+
+		_, err = member.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:         "other",
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+		})
+		require.Error(t, err)
+		err = member.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.Error(t, err)
+		_, err = member.OAuth2ProviderAppSecrets(ctx, app.ID)
+		require.Error(t, err)
+	})
+}
+
+func TestOAuth2ProviderAppSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "dashboard",
+		RedirectURIs: []string{"http://localhost:3000/callback"},
+	})
+	require.NoError(t, err)
+
+	secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, secret.ClientSecretFull)
+
+	secrets, err := client.OAuth2ProviderAppSecrets(ctx, app.ID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	require.Equal(t, secret.ID, secrets[0].ID)
+	require.True(t, strings.HasSuffix(secret.ClientSecretFull, secrets[0].ClientSecretTruncated))
+	require.Less(t, len(secrets[0].ClientSecretTruncated), len(secret.ClientSecretFull))
+	require.False(t, secrets[0].LastUsedAt.Valid)
+
+	// Secrets of other apps are not found.
+	other, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "other",
+		RedirectURIs: []string{"http://localhost:3000/callback"},
+	})
+	require.NoError(t, err)
+	err = client.DeleteOAuth2ProviderAppSecret(ctx, other.ID, secret.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	err = client.DeleteOAuth2ProviderAppSecret(ctx, app.ID, secret.ID)
+	require.NoError(t, err)
+	secrets, err = client.OAuth2ProviderAppSecrets(ctx, app.ID)
+	require.NoError(t, err)
+	require.Empty(t, secrets)
+}
+
+func TestOAuth2ProviderFlow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Exchange", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, config := setupOAuth2ProviderApp(ctx, t, client)
+
+		token, err := exchangeOAuth2Token(ctx, t, client, config)
+		require.NoError(t, err)
+		require.NotEmpty(t, token.AccessToken)
+		require.NotEmpty(t, token.RefreshToken)
+		require.Equal(t, "Bearer", token.TokenType)
+		require.True(t, token.Expiry.After(time.Now()))
+
+		// Apps send the access token as a bearer token.
+		res, err := config.Client(ctx, token).Get(client.URL.String() + "/api/v2/users/me")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		// The access token also works as a session token.
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		user, err := appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, owner.UserID, user.ID)
+
+		// The access token is not listed with the tokens of the user.
+		tokens, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, config := setupOAuth2ProviderApp(ctx, t, client)
+
+		token, err := exchangeOAuth2Token(ctx, t, client, config)
+		require.NoError(t, err)
+
+		// Force a refresh by expiring the token.
+		expired := *token
+		expired.Expiry = time.Now().Add(-1)
+		refreshed, err := config.TokenSource(ctx, &expired).Token()
+		require.NoError(t, err)
+		require.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+		require.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(refreshed.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		// The old tokens are revoked.
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		_, err = config.TokenSource(ctx, &expired).Token()
+		require.Error(t, err)
+	})
+
+	t.Run("Scope", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, config := setupOAuth2ProviderApp(ctx, t, client)
+		config.Scopes = []string{"user:read"}
+
+		token, err := exchangeOAuth2Token(ctx, t, client, config)
+		require.NoError(t, err)
+
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = appClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+
+		// The scope is kept when the token is refreshed.
+		expired := *token
+		expired.Expiry = time.Now().Add(-1)
+		refreshed, err := config.TokenSource(ctx, &expired).Token()
+		require.NoError(t, err)
+		appClient.SetSessionToken(refreshed.AccessToken)
+		_, err = appClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidExchange", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, config := setupOAuth2ProviderApp(ctx, t, client)
+
+		verifier := uuid.NewString() + uuid.NewString()
+		code := authorizeOAuth2ProviderApp(ctx, t, client, config, verifier)
+
+		wrongSecret := *config
+		wrongSecret.ClientSecret = "wrong"
+		_, err := wrongSecret.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		requireOAuth2Error(t, err, "invalid_client")
+
+		_, err = config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", uuid.NewString()+uuid.NewString()))
+		requireOAuth2Error(t, err, "invalid_grant")
+
+		wrongRedirect := *config
+		wrongRedirect.RedirectURL = "http://localhost:3000/other"
+		_, err = wrongRedirect.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		requireOAuth2Error(t, err, "invalid_grant")
+
+		_, err = config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.NoError(t, err)
+
+		// Codes can only be used once.
+		_, err = config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		requireOAuth2Error(t, err, "invalid_grant")
+	})
+
+	t.Run("OmittedRedirectURI", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:         "app-" + uuid.NewString()[:8],
+			RedirectURIs: []string{"http://localhost:3000/callback"},
+		})
+		require.NoError(t, err)
+		secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+		config := &oauth2.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  app.Endpoints.Authorization,
+				TokenURL: app.Endpoints.Token,
+			},
+		}
+
+		// An app with one redirect URI can leave it out of both requests.
+		token, err := exchangeOAuth2Token(ctx, t, client, config)
+		require.NoError(t, err)
+		require.NotEmpty(t, token.AccessToken)
+	})
+
+	t.Run("AllowPage", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+
+		res := requestOAuth2Authorize(ctx, t, client, http.MethodGet, config.AuthCodeURL("state",
+			oauth2.SetAuthURLParam("code_challenge", "challenge"),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		))
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(body), app.Name)
+
+		// Users are not redirected to unknown redirect URIs.
+		wrongRedirect := *config
+		wrongRedirect.RedirectURL = "http://localhost:3000/unknown"
+		res = requestOAuth2Authorize(ctx, t, client, http.MethodGet, wrongRedirect.AuthCodeURL("state",
+			oauth2.SetAuthURLParam("code_challenge", "challenge"),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		))
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		// Other errors are sent to the app.
+		res = requestOAuth2Authorize(ctx, t, client, http.MethodGet, config.AuthCodeURL("state"))
+		defer res.Body.Close()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "invalid_request", location.Query().Get("error"))
+		require.Equal(t, "state", location.Query().Get("state"))
+	})
+
+	t.Run("AllowPageCSRF", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, config := setupOAuth2ProviderApp(ctx, t, client)
+		authURL := config.AuthCodeURL("state",
+			oauth2.SetAuthURLParam("code_challenge", "challenge"),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		)
+
+		// The browser authenticates with the session cookie.
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		jar.SetCookies(client.URL, []*http.Cookie{{Name: codersdk.SessionTokenCookie, Value: client.SessionToken()}})
+		httpClient := &http.Client{
+			Jar: jar,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		postAllow := func(origin, csrfToken string) *http.Response {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, strings.NewReader(url.Values{"csrf_token": {csrfToken}}.Encode()))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("Origin", origin)
+			res, err := httpClient.Do(req)
+			require.NoError(t, err)
+			return res
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, authURL, nil)
+		require.NoError(t, err)
+		res, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		// The page cannot be framed.
+		require.Equal(t, "DENY", res.Header.Get("X-Frame-Options"))
+		require.Equal(t, "frame-ancestors 'none'", res.Header.Get("Content-Security-Policy"))
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		match := regexp.MustCompile(`name="csrf_token" value="([^"]+)"`).FindStringSubmatch(string(body))
+		require.Len(t, match, 2)
+		csrfToken := html.UnescapeString(match[1])
+
+		// Other pages cannot post the form without the token.
+		res = postAllow(client.URL.String(), "")
+		defer res.Body.Close()
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		// Or from another origin, even with the token.
+		res = postAllow("http://app.example.com", csrfToken)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		res = postAllow(client.URL.String(), csrfToken)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		require.NotEmpty(t, location.Query().Get("code"))
+	})
+}
+
+func TestOAuth2ProviderRevoke(t *testing.T) {
+	t.Parallel()
+
+	t.Run("User", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+
+		token, err := exchangeOAuth2Token(ctx, t, client, config)
+		require.NoError(t, err)
+
+		apps, err := client.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{UserID: owner.UserID})
+		require.NoError(t, err)
+		require.Len(t, apps, 1)
+		require.Equal(t, app.ID, apps[0].ID)
+
+		err = client.RevokeOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+
+		apps, err = client.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{UserID: owner.UserID})
+		require.NoError(t, err)
+		require.Empty(t, apps)
+
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		expired := *token
+		expired.Expiry = time.Now().Add(-1)
+		_, err = config.TokenSource(ctx, &expired).Token()
+		require.Error(t, err)
+	})
+
+	t.Run("App", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+
+		token, err := exchangeOAuth2Token(ctx, t, client, config)
+		require.NoError(t, err)
+
+		// Apps revoke their tokens with the refresh token.
+		res := postOAuth2Revoke(ctx, t, app, config, token.RefreshToken)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+	})
+
+	t.Run("DeleteApp", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+
+		token, err := exchangeOAuth2Token(ctx, t, client, config)
+		require.NoError(t, err)
+
+		err = client.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+	})
+}
+
+// setupOAuth2ProviderApp creates an app with a client secret and returns the
+// config an app would use to authenticate users.
+func setupOAuth2ProviderApp(ctx context.Context, t *testing.T, client *codersdk.Client) (codersdk.OAuth2ProviderApp, *oauth2.Config) {
+	t.Helper()
+
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "app-" + uuid.NewString()[:8],
+		RedirectURIs: []string{"http://localhost:3000/callback", "http://localhost:3000/other"},
+	})
+	require.NoError(t, err)
+	secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+
+	return app, &oauth2.Config{
+		ClientID:     app.ID.String(),
+		ClientSecret: secret.ClientSecretFull,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  app.Endpoints.Authorization,
+			TokenURL: app.Endpoints.Token,
+		},
+		RedirectURL: "http://localhost:3000/callback",
+	}
+}
+
+// authorizeOAuth2ProviderApp allows the app as the user of the client and
+// returns the code the app receives.
+func authorizeOAuth2ProviderApp(ctx context.Context, t *testing.T, client *codersdk.Client, config *oauth2.Config, verifier string) string {
+	t.Helper()
+
+	hashed := sha256.Sum256([]byte(verifier))
+	res := requestOAuth2Authorize(ctx, t, client, http.MethodPost, config.AuthCodeURL("state",
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(hashed[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	))
+	defer res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "state", location.Query().Get("state"))
+	require.NotEmpty(t, location.Query().Get("code"))
+	return location.Query().Get("code")
+}
+
+func exchangeOAuth2Token(ctx context.Context, t *testing.T, client *codersdk.Client, config *oauth2.Config) (*oauth2.Token, error) {
+	t.Helper()
+
+	verifier := uuid.NewString() + uuid.NewString()
+	code := authorizeOAuth2ProviderApp(ctx, t, client, config, verifier)
+	return config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+}
+
+// requestOAuth2Authorize requests the authorization endpoint as the user of
+// the client, without following redirects.
+func requestOAuth2Authorize(ctx context.Context, t *testing.T, client *codersdk.Client, method string, authURL string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, method, authURL, nil)
+	require.NoError(t, err)
+	req.Header.Set(codersdk.SessionTokenHeader, client.SessionToken())
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := httpClient.Do(req)
+	require.NoError(t, err)
+	return res
+}
+
+func postOAuth2Revoke(ctx context.Context, t *testing.T, app codersdk.OAuth2ProviderApp, config *oauth2.Config, token string) *http.Response {
+	t.Helper()
+
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, app.Endpoints.Revocation, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(config.ClientID, config.ClientSecret)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return res
+}
+
+func requireOAuth2Error(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var retrieveErr *oauth2.RetrieveError
+	require.ErrorAs(t, err, &retrieveErr)
+	require.Contains(t, string(retrieveErr.Body), code)
+}
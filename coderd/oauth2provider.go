@@ -0,0 +1,617 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/justinas/nosurf"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/site"
+)
+
+const (
+	// oauth2ProviderCodeLifetime is how long an app has to exchange an
+	// authorization code for a token.
+	oauth2ProviderCodeLifetime = 10 * time.Minute
+	// oauth2ProviderRefreshLifetime is how long an app may use a refresh token.
+	// The access tokens themselves expire after the session duration.
+	oauth2ProviderRefreshLifetime = 30 * 24 * time.Hour
+)
+
+// oauth2Error is an error response of the authorization server, as defined
+// by RFC 6749 section 5.2.
+type oauth2Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e oauth2Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// oauth2TokenResponse is a successful response of the token endpoint, as
+// defined by RFC 6749 section 5.1.
+type oauth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// oauth2AuthorizeParams are the parameters of an authorization request.
+type oauth2AuthorizeParams struct {
+	app           database.OAuth2ProviderApp
+	redirectURI   *url.URL
+	state         string
+	scope         string
+	codeChallenge string
+	// requestRedirectURI is the redirect_uri parameter of the request. It is
+	// empty when the app's only redirect URI was used instead.
+	requestRedirectURI string
+}
+
+// getOAuth2ProviderAppAuthorize shows the user the page to allow the app to
+// access Coder on their behalf.
+func (api *API) getOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	params, ok := api.parseOAuth2AuthorizeParams(rw, r)
+	if !ok {
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusInternalServerError, "Failed to fetch the user.")
+		return
+	}
+
+	scope := params.scope
+	if scope == "" {
+		scope = string(codersdk.APIKeyScopeAll)
+	}
+	site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+		AppIcon:   params.app.Icon,
+		AppName:   params.app.Name,
+		CancelURI: oauth2RedirectURI(params.redirectURI, params.state, url.Values{"error": {"access_denied"}}),
+		Username:  user.Username,
+		Scope:     scope,
+	})
+}
+
+// postOAuth2ProviderAppAuthorize is submitted by the allow page. It issues an
+// authorization code and sends the user back to the app with it.
+func (api *API) postOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	if !api.verifyOAuth2AuthorizeOrigin(r) {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusForbidden, "The request to allow the app did not come from the allow page. Please try again.")
+		return
+	}
+
+	params, ok := api.parseOAuth2AuthorizeParams(rw, r)
+	if !ok {
+		return
+	}
+
+	code, hashed, err := generateOAuth2ProviderSecret()
+	if err != nil {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusInternalServerError, "Failed to generate an authorization code.")
+		return
+	}
+	now := dbtime.Now()
+	_, err = api.Database.InsertOAuth2ProviderAppCode(ctx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            uuid.New(),
+		AppID:         params.app.ID,
+		UserID:        apiKey.UserID,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(oauth2ProviderCodeLifetime),
+		HashedSecret:  hashed,
+		RedirectURI:   params.requestRedirectURI,
+		Scope:         params.scope,
+		CodeChallenge: params.codeChallenge,
+	})
+	if err != nil {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusInternalServerError, "Failed to create an authorization code.")
+		return
+	}
+
+	http.Redirect(rw, r, oauth2RedirectURI(params.redirectURI, params.state, url.Values{"code": {code}}), http.StatusFound)
+}
+
+// verifyOAuth2AuthorizeOrigin checks that an allow request was submitted by
+// the allow page. The CSRF middleware does not enforce tokens, so a page on
+// any same-site origin (such as a workspace app) could otherwise post the
+// form with the session cookie of the user.
+func (api *API) verifyOAuth2AuthorizeOrigin(r *http.Request) bool {
+	// Only requests authenticated by the session cookie can be forged, since
+	// other pages cannot set headers or query parameters with the token.
+	sessCookie, err := r.Cookie(codersdk.SessionTokenCookie)
+	if xerrors.Is(err, http.ErrNoCookie) {
+		return true
+	}
+	if token := r.Header.Get(codersdk.SessionTokenHeader); token != "" && token == sessCookie.Value {
+		return true
+	}
+
+	// Browsers send the origin with every form submission. It also stops
+	// sibling subdomains that overwrite the CSRF cookie.
+	if origin := r.Header.Get("Origin"); origin != "" {
+		originURL, err := url.Parse(origin)
+		if err != nil || (originURL.Host != r.Host && originURL.Host != api.AccessURL.Host) {
+			return false
+		}
+	}
+	return nosurf.VerifyToken(nosurf.Token(r), r.PostFormValue(nosurf.FormFieldName))
+}
+
+// oauth2ProviderAuthorizeHeaders stops the allow page from being framed, so
+// the user cannot be tricked into clicking it on another site.
+func oauth2ProviderAuthorizeHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("X-Frame-Options", "DENY")
+		rw.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// parseOAuth2AuthorizeParams validates an authorization request. An unknown
+// app or redirect URI is shown to the user, since they must not be redirected
+// to a URI that was not registered. Any other error is sent back to the app.
+func (api *API) parseOAuth2AuthorizeParams(rw http.ResponseWriter, r *http.Request) (oauth2AuthorizeParams, bool) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	appID, err := uuid.Parse(query.Get("client_id"))
+	if err != nil {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusBadRequest, "The client_id parameter must be the ID of an OAuth2 app.")
+		return oauth2AuthorizeParams{}, false
+	}
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appID)
+	if httpapi.Is404Error(err) {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusBadRequest, "The OAuth2 app does not exist.")
+		return oauth2AuthorizeParams{}, false
+	}
+	if err != nil {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusInternalServerError, "Failed to fetch the OAuth2 app.")
+		return oauth2AuthorizeParams{}, false
+	}
+
+	requestRedirectURI := query.Get("redirect_uri")
+	rawRedirectURI := requestRedirectURI
+	if rawRedirectURI == "" && len(app.RedirectURIs) == 1 {
+		rawRedirectURI = app.RedirectURIs[0]
+	}
+	if !slices.Contains(app.RedirectURIs, rawRedirectURI) {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusBadRequest, "The redirect_uri parameter must match a redirect URI of the OAuth2 app.")
+		return oauth2AuthorizeParams{}, false
+	}
+	redirectURI, err := url.Parse(rawRedirectURI)
+	if err != nil {
+		api.renderOAuth2AuthorizeError(rw, r, http.StatusBadRequest, "The redirect URI of the OAuth2 app is invalid.")
+		return oauth2AuthorizeParams{}, false
+	}
+
+	params := oauth2AuthorizeParams{
+		app:                app,
+		redirectURI:        redirectURI,
+		requestRedirectURI: requestRedirectURI,
+		state:              query.Get("state"),
+		scope:              strings.Join(strings.Fields(query.Get("scope")), " "),
+		codeChallenge:      query.Get("code_challenge"),
+	}
+	redirectError := func(code, description string) (oauth2AuthorizeParams, bool) {
+		http.Redirect(rw, r, oauth2RedirectURI(redirectURI, params.state, url.Values{
+			"error":             {code},
+			"error_description": {description},
+		}), http.StatusFound)
+		return oauth2AuthorizeParams{}, false
+	}
+
+	if query.Get("response_type") != string(codersdk.OAuth2ProviderResponseTypeCode) {
+		return redirectError("unsupported_response_type", fmt.Sprintf("The response_type must be %q.", codersdk.OAuth2ProviderResponseTypeCode))
+	}
+	// PKCE is required, since many apps cannot keep their client secret
+	// confidential.
+	if params.codeChallenge == "" || query.Get("code_challenge_method") != "S256" {
+		return redirectError("invalid_request", "A code_challenge with the S256 code_challenge_method is required.")
+	}
+	if _, _, err := oauth2ProviderScope(params.scope); err != nil {
+		return redirectError("invalid_scope", err.Error())
+	}
+
+	return params, true
+}
+
+func (api *API) renderOAuth2AuthorizeError(rw http.ResponseWriter, r *http.Request, status int, description string) {
+	site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+		Status:       status,
+		Title:        "Invalid OAuth2 Request",
+		Description:  description,
+		RetryEnabled: false,
+		DashboardURL: api.AccessURL.String(),
+	})
+}
+
+// postOAuth2ProviderAppToken exchanges an authorization code or a refresh
+// token for a new access token.
+func (api *API) postOAuth2ProviderAppToken(rw http.ResponseWriter, r *http.Request) {
+	// Apps are not users, so the endpoint acts as the system.
+	//nolint:gocritic // The app is authenticated with its client secret.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	// Tokens must never be cached.
+	rw.Header().Set("Cache-Control", "no-store")
+
+	app, err := api.authenticateOAuth2ProviderApp(ctx, r)
+	if err != nil {
+		writeOAuth2Error(ctx, rw, err)
+		return
+	}
+
+	var token oauth2TokenResponse
+	switch codersdk.OAuth2ProviderGrantType(r.PostFormValue("grant_type")) {
+	case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
+		token, err = api.oauth2ProviderTokenFromCode(ctx, r, app)
+	case codersdk.OAuth2ProviderGrantTypeRefreshToken:
+		token, err = api.oauth2ProviderTokenFromRefresh(ctx, r, app)
+	default:
+		err = oauth2Error{
+			Code:        "unsupported_grant_type",
+			Description: fmt.Sprintf("The grant_type must be %q or %q.", codersdk.OAuth2ProviderGrantTypeAuthorizationCode, codersdk.OAuth2ProviderGrantTypeRefreshToken),
+		}
+	}
+	if err != nil {
+		writeOAuth2Error(ctx, rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, token)
+}
+
+func (api *API) oauth2ProviderTokenFromCode(ctx context.Context, r *http.Request, app database.OAuth2ProviderApp) (oauth2TokenResponse, error) {
+	invalidGrant := oauth2Error{Code: "invalid_grant", Description: "The authorization code is invalid or expired."}
+
+	code, err := api.Database.GetOAuth2ProviderAppCodeByHashedSecret(ctx, hashOAuth2ProviderSecret(r.PostFormValue("code")))
+	if httpapi.Is404Error(err) {
+		return oauth2TokenResponse{}, invalidGrant
+	}
+	if err != nil {
+		return oauth2TokenResponse{}, xerrors.Errorf("get authorization code: %w", err)
+	}
+	if code.AppID != app.ID || code.ExpiresAt.Before(dbtime.Now()) {
+		return oauth2TokenResponse{}, invalidGrant
+	}
+	// The redirect_uri is only required if it was part of the authorization
+	// request (RFC 6749 section 4.1.3).
+	if code.RedirectURI != "" && r.PostFormValue("redirect_uri") != code.RedirectURI {
+		return oauth2TokenResponse{}, oauth2Error{Code: "invalid_grant", Description: "The redirect_uri does not match the authorization request."}
+	}
+	if !verifyOAuth2CodeChallenge(code.CodeChallenge, r.PostFormValue("code_verifier")) {
+		return oauth2TokenResponse{}, oauth2Error{Code: "invalid_grant", Description: "The code_verifier does not match the code_challenge."}
+	}
+
+	scope, scopeRules, err := oauth2ProviderScope(code.Scope)
+	if err != nil {
+		return oauth2TokenResponse{}, oauth2Error{Code: "invalid_scope", Description: err.Error()}
+	}
+
+	var token oauth2TokenResponse
+	err = api.Database.InTx(func(tx database.Store) error {
+		// Codes can only be used once. The delete blocks on a concurrent
+		// request using the same code, and affects no rows once it commits.
+		rows, err := tx.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+		if err != nil {
+			return xerrors.Errorf("delete authorization code: %w", err)
+		}
+		if rows == 0 {
+			return invalidGrant
+		}
+		token, err = api.insertOAuth2ProviderAppToken(ctx, tx, app, code.UserID, scope, scopeRules)
+		return err
+	}, nil)
+	if httpapi.Is404Error(err) {
+		// Another request used the code first.
+		return oauth2TokenResponse{}, invalidGrant
+	}
+	return token, err
+}
+
+func (api *API) oauth2ProviderTokenFromRefresh(ctx context.Context, r *http.Request, app database.OAuth2ProviderApp) (oauth2TokenResponse, error) {
+	invalidGrant := oauth2Error{Code: "invalid_grant", Description: "The refresh token is invalid or expired."}
+
+	dbtoken, err := api.Database.GetOAuth2ProviderAppTokenByRefreshHash(ctx, hashOAuth2ProviderSecret(r.PostFormValue("refresh_token")))
+	if httpapi.Is404Error(err) {
+		return oauth2TokenResponse{}, invalidGrant
+	}
+	if err != nil {
+		return oauth2TokenResponse{}, xerrors.Errorf("get refresh token: %w", err)
+	}
+	if dbtoken.AppID != app.ID || dbtoken.ExpiresAt.Before(dbtime.Now()) {
+		return oauth2TokenResponse{}, invalidGrant
+	}
+
+	key, err := api.Database.GetAPIKeyByID(ctx, dbtoken.APIKeyID)
+	if err != nil {
+		return oauth2TokenResponse{}, xerrors.Errorf("get api key: %w", err)
+	}
+	// The new token has the scope the user allowed in the first place.
+	var scopeRules []rbac.ScopeRule
+	if len(key.ScopeRules) > 0 {
+		err = json.Unmarshal(key.ScopeRules, &scopeRules)
+		if err != nil {
+			return oauth2TokenResponse{}, xerrors.Errorf("unmarshal scope rules: %w", err)
+		}
+	}
+
+	var token oauth2TokenResponse
+	err = api.Database.InTx(func(tx database.Store) error {
+		// Deleting the API key deletes the refresh token as well, so both can
+		// only be used once.
+		rows, err := tx.DeleteAPIKeyByID(ctx, key.ID)
+		if err != nil {
+			return xerrors.Errorf("delete api key: %w", err)
+		}
+		if rows == 0 {
+			return invalidGrant
+		}
+		token, err = api.insertOAuth2ProviderAppToken(ctx, tx, app, key.UserID, key.Scope, scopeRules)
+		return err
+	}, nil)
+	if httpapi.Is404Error(err) {
+		return oauth2TokenResponse{}, invalidGrant
+	}
+	return token, err
+}
+
+// insertOAuth2ProviderAppToken creates the API key of an access token along
+// with its refresh token.
+func (api *API) insertOAuth2ProviderAppToken(ctx context.Context, tx database.Store, app database.OAuth2ProviderApp, userID uuid.UUID, scope database.APIKeyScope, scopeRules []rbac.ScopeRule) (oauth2TokenResponse, error) {
+	params, accessToken, err := apikey.Generate(apikey.CreateParams{
+		UserID:           userID,
+		LoginType:        database.LoginTypeOAuth2ProviderApp,
+		DeploymentValues: api.DeploymentValues,
+		Scope:            scope,
+		ScopeRules:       scopeRules,
+	})
+	if err != nil {
+		return oauth2TokenResponse{}, xerrors.Errorf("generate api key: %w", err)
+	}
+	key, err := tx.InsertAPIKey(ctx, params)
+	if err != nil {
+		return oauth2TokenResponse{}, xerrors.Errorf("insert api key: %w", err)
+	}
+
+	refreshToken, hashed, err := generateOAuth2ProviderSecret()
+	if err != nil {
+		return oauth2TokenResponse{}, xerrors.Errorf("generate refresh token: %w", err)
+	}
+	now := dbtime.Now()
+	_, err = tx.InsertOAuth2ProviderAppToken(ctx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:          uuid.New(),
+		AppID:       app.ID,
+		APIKeyID:    key.ID,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(oauth2ProviderRefreshLifetime),
+		RefreshHash: hashed,
+	})
+	if err != nil {
+		return oauth2TokenResponse{}, xerrors.Errorf("insert refresh token: %w", err)
+	}
+
+	return oauth2TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		RefreshToken: refreshToken,
+		ExpiresIn:    key.LifetimeSeconds,
+	}, nil
+}
+
+// postOAuth2ProviderAppRevoke revokes an access or refresh token of the app,
+// as defined by RFC 7009.
+func (api *API) postOAuth2ProviderAppRevoke(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // The app is authenticated with its client secret.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	app, err := api.authenticateOAuth2ProviderApp(ctx, r)
+	if err != nil {
+		writeOAuth2Error(ctx, rw, err)
+		return
+	}
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		writeOAuth2Error(ctx, rw, oauth2Error{Code: "invalid_request", Description: "The token parameter is required."})
+		return
+	}
+
+	var dbtoken database.OAuth2ProviderAppToken
+	if keyID, keySecret, splitErr := httpmw.SplitAPIToken(token); splitErr == nil {
+		var key database.APIKey
+		key, err = api.Database.GetAPIKeyByID(ctx, keyID)
+		if err == nil {
+			hashed := sha256.Sum256([]byte(keySecret))
+			if subtle.ConstantTimeCompare(key.HashedSecret, hashed[:]) != 1 {
+				err = sql.ErrNoRows
+			}
+		}
+		if err == nil {
+			dbtoken, err = api.Database.GetOAuth2ProviderAppTokenByAPIKeyID(ctx, key.ID)
+		}
+	} else {
+		dbtoken, err = api.Database.GetOAuth2ProviderAppTokenByRefreshHash(ctx, hashOAuth2ProviderSecret(token))
+	}
+	// Unknown tokens and tokens of other apps are not an error, since they
+	// cannot be used by the app either way.
+	if httpapi.Is404Error(err) || (err == nil && dbtoken.AppID != app.ID) {
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+	if err != nil {
+		writeOAuth2Error(ctx, rw, err)
+		return
+	}
+
+	_, err = api.Database.DeleteAPIKeyByID(ctx, dbtoken.APIKeyID)
+	if err != nil && !httpapi.Is404Error(err) {
+		writeOAuth2Error(ctx, rw, err)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+// authenticateOAuth2ProviderApp authenticates the app of a token or revocation
+// request. The client ID and secret are sent with HTTP basic auth or in the
+// form.
+func (api *API) authenticateOAuth2ProviderApp(ctx context.Context, r *http.Request) (database.OAuth2ProviderApp, error) {
+	invalidClient := oauth2Error{Code: "invalid_client", Description: "The client ID or client secret is invalid."}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		// The credentials are form encoded before they are put in the header.
+		var err error
+		clientID, err = url.QueryUnescape(clientID)
+		if err != nil {
+			return database.OAuth2ProviderApp{}, invalidClient
+		}
+		clientSecret, err = url.QueryUnescape(clientSecret)
+		if err != nil {
+			return database.OAuth2ProviderApp{}, invalidClient
+		}
+	} else {
+		clientID = r.PostFormValue("client_id")
+		clientSecret = r.PostFormValue("client_secret")
+	}
+
+	appID, err := uuid.Parse(clientID)
+	if err != nil || clientSecret == "" {
+		return database.OAuth2ProviderApp{}, invalidClient
+	}
+	secret, err := api.Database.GetOAuth2ProviderAppSecretByHashedSecret(ctx, hashOAuth2ProviderSecret(clientSecret))
+	if httpapi.Is404Error(err) {
+		return database.OAuth2ProviderApp{}, invalidClient
+	}
+	if err != nil {
+		return database.OAuth2ProviderApp{}, xerrors.Errorf("get client secret: %w", err)
+	}
+	if secret.AppID != appID {
+		return database.OAuth2ProviderApp{}, invalidClient
+	}
+
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appID)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, xerrors.Errorf("get app: %w", err)
+	}
+	_, err = api.Database.UpdateOAuth2ProviderAppSecretByID(ctx, database.UpdateOAuth2ProviderAppSecretByIDParams{
+		ID: secret.ID,
+		LastUsedAt: sql.NullTime{
+			Time:  dbtime.Now(),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return database.OAuth2ProviderApp{}, xerrors.Errorf("update client secret: %w", err)
+	}
+	return app, nil
+}
+
+// writeOAuth2Error writes an error of the token or revocation endpoint. Errors
+// that are not an oauth2Error are server errors.
+func writeOAuth2Error(ctx context.Context, rw http.ResponseWriter, err error) {
+	var oauthErr oauth2Error
+	if !xerrors.As(err, &oauthErr) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, oauth2Error{
+			Code:        "server_error",
+			Description: err.Error(),
+		})
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == "invalid_client" {
+		status = http.StatusUnauthorized
+	}
+	httpapi.Write(ctx, rw, status, oauthErr)
+}
+
+// oauth2RedirectURI returns the redirect URI of the app with the state and
+// the given values added to its query.
+func oauth2RedirectURI(redirectURI *url.URL, state string, values url.Values) string {
+	u := *redirectURI
+	query := u.Query()
+	for key, value := range values {
+		query[key] = value
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// oauth2ProviderScope converts the space separated scope of an authorization
+// request to the scope of the API keys issued for it. An empty scope is the
+// "all" scope.
+func oauth2ProviderScope(raw string) (database.APIKeyScope, []rbac.ScopeRule, error) {
+	values := strings.Fields(raw)
+	if len(values) == 0 {
+		return database.APIKeyScopeAll, nil, nil
+	}
+	scope, rules, err := codersdk.ParseAPIKeyScope(values)
+	if err != nil {
+		return "", nil, err
+	}
+	scopeRules, err := convertCreateScopeRules(rules)
+	if err != nil {
+		return "", nil, err
+	}
+	return database.APIKeyScope(scope), scopeRules, nil
+}
+
+// verifyOAuth2CodeChallenge checks a PKCE code verifier against the S256
+// code challenge of the authorization request, as defined by RFC 7636.
+func verifyOAuth2CodeChallenge(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	hashed := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(hashed[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// generateOAuth2ProviderSecret generates a client secret, authorization code
+// or refresh token. Only the hash is stored.
+func generateOAuth2ProviderSecret() (string, []byte, error) {
+	secret, err := cryptorand.String(40)
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, hashOAuth2ProviderSecret(secret), nil
+}
+
+func hashOAuth2ProviderSecret(secret string) []byte {
+	hashed := sha256.Sum256([]byte(secret))
+	return hashed[:]
+}
@@ -0,0 +1,311 @@
+package coderd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/prebuilds"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get prebuild pools by template
+// @ID get-prebuild-pools-by-template
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspacePrebuildPool
+// @Router /templates/{template}/prebuilds [get]
+func (api *API) workspacePrebuildPools(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	pools, err := api.Database.GetWorkspacePrebuildPoolsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	// Prebuilt workspaces are owned by the prebuilds user, which the
+	// requester usually can't read.
+	// nolint:gocritic
+	instances, err := prebuilds.Instances(dbauthz.AsSystemRestricted(ctx), api.Database)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	apiPools := make([]codersdk.WorkspacePrebuildPool, 0, len(pools))
+	for _, pool := range pools {
+		apiPool, err := convertWorkspacePrebuildPool(pool, instances)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		apiPools = append(apiPools, apiPool)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiPools)
+}
+
+// @Summary Create prebuild pool for template
+// @ID create-prebuild-pool-for-template
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateWorkspacePrebuildPoolRequest true "Create prebuild pool request"
+// @Success 201 {object} codersdk.WorkspacePrebuildPool
+// @Router /templates/{template}/prebuilds [post]
+func (api *API) postWorkspacePrebuildPool(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateWorkspacePrebuildPoolRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, req.TemplateVersionID)
+	if httpapi.Is404Error(err) || (err == nil && version.TemplateID.UUID != template.ID) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template version %q doesn't belong to the template.", req.TemplateVersionID),
+			Validations: []codersdk.ValidationError{{
+				Field:  "template_version_id",
+				Detail: "template version not found",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	versionParameters, err := api.Database.GetTemplateVersionParameters(ctx, version.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	var validErrs []codersdk.ValidationError
+	for _, parameter := range req.Parameters {
+		found := false
+		for _, versionParameter := range versionParameters {
+			if versionParameter.Name == parameter.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "parameters",
+				Detail: fmt.Sprintf("Parameter %q does not exist in the template version.", parameter.Name),
+			})
+		}
+	}
+	for _, versionParameter := range versionParameters {
+		if !versionParameter.Required {
+			continue
+		}
+		found := false
+		for _, parameter := range req.Parameters {
+			if parameter.Name == versionParameter.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "parameters",
+				Detail: fmt.Sprintf("Parameter %q is required.", versionParameter.Name),
+			})
+		}
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid prebuild pool parameters.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	if req.Parameters == nil {
+		req.Parameters = []codersdk.WorkspaceBuildParameter{}
+	}
+	parameters, err := json.Marshal(req.Parameters)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	now := dbtime.Now()
+	pool, err := api.Database.InsertWorkspacePrebuildPool(ctx, database.InsertWorkspacePrebuildPoolParams{
+		ID:                uuid.New(),
+		TemplateID:        template.ID,
+		TemplateVersionID: version.ID,
+		Parameters:        parameters,
+		DesiredInstances:  req.DesiredInstances,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	apiPool, err := convertWorkspacePrebuildPool(pool, nil)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, apiPool)
+}
+
+// @Summary Update prebuild pool
+// @ID update-prebuild-pool
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param pool path string true "Prebuild pool ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspacePrebuildPoolRequest true "Update prebuild pool request"
+// @Success 200 {object} codersdk.WorkspacePrebuildPool
+// @Router /templates/{template}/prebuilds/{pool} [put]
+func (api *API) putWorkspacePrebuildPool(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	pool, ok := api.workspacePrebuildPoolParam(rw, r, template)
+	if !ok {
+		return
+	}
+
+	var req codersdk.UpdateWorkspacePrebuildPoolRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	pool, err := api.Database.UpdateWorkspacePrebuildPoolByID(ctx, database.UpdateWorkspacePrebuildPoolByIDParams{
+		ID:               pool.ID,
+		DesiredInstances: req.DesiredInstances,
+		UpdatedAt:        dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	// nolint:gocritic // See workspacePrebuildPools.
+	instances, err := prebuilds.Instances(dbauthz.AsSystemRestricted(ctx), api.Database)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	apiPool, err := convertWorkspacePrebuildPool(pool, instances)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiPool)
+}
+
+// @Summary Delete prebuild pool
+// @ID delete-prebuild-pool
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param pool path string true "Prebuild pool ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /templates/{template}/prebuilds/{pool} [delete]
+func (api *API) deleteWorkspacePrebuildPool(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	pool, ok := api.workspacePrebuildPoolParam(rw, r, template)
+	if !ok {
+		return
+	}
+
+	// The reconciler deletes the prebuilt workspaces of the pool.
+	err := api.Database.DeleteWorkspacePrebuildPoolByID(ctx, pool.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Prebuild pool has been deleted!",
+	})
+}
+
+func (api *API) workspacePrebuildPoolParam(rw http.ResponseWriter, r *http.Request, template database.Template) (database.WorkspacePrebuildPool, bool) {
+	poolID, ok := httpmw.ParseUUIDParam(rw, r, "pool")
+	if !ok {
+		return database.WorkspacePrebuildPool{}, false
+	}
+	pool, err := api.Database.GetWorkspacePrebuildPoolByID(r.Context(), poolID)
+	if httpapi.Is404Error(err) || (err == nil && pool.TemplateID != template.ID) {
+		httpapi.ResourceNotFound(rw)
+		return database.WorkspacePrebuildPool{}, false
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return database.WorkspacePrebuildPool{}, false
+	}
+	return pool, true
+}
+
+func convertWorkspacePrebuildPool(pool database.WorkspacePrebuildPool, instances []prebuilds.Instance) (codersdk.WorkspacePrebuildPool, error) {
+	parameters, err := prebuilds.Parameters(pool)
+	if err != nil {
+		return codersdk.WorkspacePrebuildPool{}, err
+	}
+
+	apiPool := codersdk.WorkspacePrebuildPool{
+		ID:                pool.ID,
+		TemplateID:        pool.TemplateID,
+		TemplateVersionID: pool.TemplateVersionID,
+		Parameters:        parameters,
+		DesiredInstances:  pool.DesiredInstances,
+		CreatedAt:         pool.CreatedAt,
+		UpdatedAt:         pool.UpdatedAt,
+	}
+	for _, instance := range instances {
+		if !instance.PoolID.Valid || instance.PoolID.UUID != pool.ID {
+			continue
+		}
+		switch instance.State {
+		case prebuilds.StateReady:
+			apiPool.ReadyInstances++
+		case prebuilds.StateBuilding:
+			apiPool.BuildingInstances++
+		default:
+			apiPool.FailedInstances++
+		}
+	}
+	return apiPool, nil
+}
@@ -0,0 +1,201 @@
+// Package prebuilds maintains pools of prebuilt workspaces that are ready to be
+// claimed by users, which cuts the time to a running workspace down to the
+// time it takes to transfer ownership.
+package prebuilds
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+var (
+	// OwnerID is the ID of the system user that owns prebuilt workspaces until
+	// they are claimed.
+	OwnerID = uuid.MustParse("c42fdf75-3097-471c-8c33-fb52454d81c0")
+	// OwnerUsername is the username of the system user that owns prebuilt
+	// workspaces until they are claimed.
+	OwnerUsername = "prebuilds"
+)
+
+// State is the state of a prebuilt workspace, derived from its latest build.
+type State string
+
+const (
+	StateBuilding State = "building"
+	StateReady    State = "ready"
+	StateFailed   State = "failed"
+	StateStopped  State = "stopped"
+)
+
+// Instance is a prebuilt workspace that has not been claimed yet.
+type Instance struct {
+	Workspace database.Workspace
+	// PoolID is null if the pool the workspace was built for has been deleted.
+	PoolID uuid.NullUUID
+	State  State
+}
+
+// Instances returns all prebuilt workspaces that have not been claimed yet,
+// oldest first.
+func Instances(ctx context.Context, db database.Store) ([]Instance, error) {
+	prebuilds, err := db.GetWorkspacePrebuilds(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace prebuilds: %w", err)
+	}
+	if len(prebuilds) == 0 {
+		return []Instance{}, nil
+	}
+
+	workspaceIDs := make([]uuid.UUID, 0, len(prebuilds))
+	for _, prebuild := range prebuilds {
+		workspaceIDs = append(workspaceIDs, prebuild.WorkspaceID)
+	}
+	builds, err := db.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, workspaceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get latest workspace builds: %w", err)
+	}
+	buildByWorkspaceID := make(map[uuid.UUID]database.WorkspaceBuild, len(builds))
+	jobIDs := make([]uuid.UUID, 0, len(builds))
+	for _, build := range builds {
+		buildByWorkspaceID[build.WorkspaceID] = build
+		jobIDs = append(jobIDs, build.JobID)
+	}
+	jobs, err := db.GetProvisionerJobsByIDs(ctx, jobIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get provisioner jobs: %w", err)
+	}
+	jobByID := make(map[uuid.UUID]database.ProvisionerJob, len(jobs))
+	for _, job := range jobs {
+		jobByID[job.ID] = job
+	}
+
+	instances := make([]Instance, 0, len(prebuilds))
+	for _, prebuild := range prebuilds {
+		workspace, err := db.GetWorkspaceByID(ctx, prebuild.WorkspaceID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace %s: %w", prebuild.WorkspaceID, err)
+		}
+		build, ok := buildByWorkspaceID[prebuild.WorkspaceID]
+		if !ok {
+			continue
+		}
+		job, ok := jobByID[build.JobID]
+		if !ok {
+			continue
+		}
+		instances = append(instances, Instance{
+			Workspace: workspace,
+			PoolID:    prebuild.PoolID,
+			State:     state(build, job),
+		})
+	}
+	return instances, nil
+}
+
+func state(build database.WorkspaceBuild, job database.ProvisionerJob) State {
+	switch db2sdk.ProvisionerJobStatus(job) {
+	case codersdk.ProvisionerJobPending, codersdk.ProvisionerJobRunning:
+		return StateBuilding
+	case codersdk.ProvisionerJobSucceeded:
+		if build.Transition == database.WorkspaceTransitionStart {
+			return StateReady
+		}
+		return StateStopped
+	default:
+		return StateFailed
+	}
+}
+
+// Parameters returns the build parameters prebuilt workspaces of the pool are
+// created with.
+func Parameters(pool database.WorkspacePrebuildPool) ([]codersdk.WorkspaceBuildParameter, error) {
+	var parameters []codersdk.WorkspaceBuildParameter
+	err := json.Unmarshal(pool.Parameters, &parameters)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal pool parameters: %w", err)
+	}
+	return parameters, nil
+}
+
+// Matches returns true if a workspace created with the given parameter values
+// would be identical to a prebuilt workspace of the pool. Parameters that are
+// not set on either side fall back to their default value.
+func Matches(pool database.WorkspacePrebuildPool, templateVersionParameters []database.TemplateVersionParameter, values []codersdk.WorkspaceBuildParameter) (bool, error) {
+	poolValues, err := Parameters(pool)
+	if err != nil {
+		return false, err
+	}
+
+	valueOf := func(values []codersdk.WorkspaceBuildParameter, parameter database.TemplateVersionParameter) string {
+		for _, value := range values {
+			if value.Name == parameter.Name {
+				return value.Value
+			}
+		}
+		return parameter.DefaultValue
+	}
+	for _, parameter := range templateVersionParameters {
+		if valueOf(poolValues, parameter) != valueOf(values, parameter) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Claim transfers a ready prebuilt workspace from one of the given pools to
+// a new owner. The workspace is renamed and receives the owner's schedule as
+// part of the transfer. build is called in the same transaction with the
+// claimed workspace to start it as the new owner, so the claim is rolled back
+// if the build can't be created. It returns false if none of the pools had a
+// ready prebuilt workspace.
+func Claim(ctx context.Context, db database.Store, poolIDs []uuid.UUID, arg database.ClaimPrebuiltWorkspaceParams, build func(tx database.Store, workspace database.Workspace) error) (database.Workspace, bool, error) {
+	instances, err := Instances(ctx, db)
+	if err != nil {
+		return database.Workspace{}, false, err
+	}
+
+	for _, instance := range instances {
+		if instance.State != StateReady || !instance.PoolID.Valid || !slices.Contains(poolIDs, instance.PoolID.UUID) {
+			continue
+		}
+
+		var workspace database.Workspace
+		err := db.InTx(func(tx database.Store) error {
+			// Deleting the prebuild row locks it, so only a single request
+			// can claim the workspace.
+			_, err := tx.DeleteWorkspacePrebuildByWorkspaceID(ctx, instance.Workspace.ID)
+			if err != nil {
+				return err
+			}
+
+			arg.ID = instance.Workspace.ID
+			workspace, err = tx.ClaimPrebuiltWorkspace(ctx, arg)
+			if err != nil {
+				return err
+			}
+			return build(tx, workspace)
+		}, nil)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Claimed by someone else or deleted in the meantime.
+			continue
+		}
+		if err != nil {
+			return database.Workspace{}, false, xerrors.Errorf("claim prebuilt workspace %s: %w", instance.Workspace.ID, err)
+		}
+		return workspace, true, nil
+	}
+	return database.Workspace{}, false, nil
+}
@@ -0,0 +1,482 @@
+package prebuilds
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+// ReconcileInterval is how often the reconciler compares the prebuilt
+// workspaces against the desired pool sizes.
+const ReconcileInterval = 15 * time.Second
+
+const (
+	// BuildBackoff is how long the reconciler waits before building prebuilt
+	// workspaces of a pool again after a build failed. It doubles with every
+	// consecutive failure, up to MaxBuildBackoff.
+	BuildBackoff = time.Minute
+	// MaxBuildBackoff is the longest the reconciler waits between builds of
+	// a pool whose builds keep failing.
+	MaxBuildBackoff = time.Hour
+)
+
+// acquireLockError is returned when the reconciler fails to acquire the lock
+// of a pool because another replica is reconciling it.
+type acquireLockError struct{}
+
+// Error implements error.
+func (acquireLockError) Error() string {
+	return "lock is held by another client"
+}
+
+// Reconciler keeps the number of prebuilt workspaces of every pool at its
+// desired size. Failed, stopped and surplus prebuilt workspaces are deleted,
+// and missing ones are built.
+type Reconciler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db    database.Store
+	log   slog.Logger
+	tick  <-chan time.Time
+	stats chan<- Stats
+
+	// backoffs are only accessed by the goroutine of Start.
+	backoffs map[uuid.UUID]poolBackoff
+}
+
+// poolBackoff delays the builds of a pool after builds of its template
+// version failed, so a broken template version doesn't keep the
+// provisioners busy.
+type poolBackoff struct {
+	templateVersionID uuid.UUID
+	failures          int
+	lastFailure       time.Time
+	until             time.Time
+}
+
+// observe updates the backoff with the instances of the pool. A ready
+// workspace created after the last failure shows the version builds again.
+func (b *poolBackoff) observe(ready []Instance, failed int, now time.Time) {
+	for _, instance := range ready {
+		if instance.Workspace.CreatedAt.After(b.lastFailure) {
+			b.failures = 0
+			b.until = time.Time{}
+			break
+		}
+	}
+	if failed == 0 {
+		return
+	}
+	b.failures += failed
+	b.lastFailure = now
+	backoff := MaxBuildBackoff
+	// Avoid overflowing the shift, the maximum is reached long before.
+	if b.failures <= 16 {
+		backoff = BuildBackoff << (b.failures - 1)
+	}
+	if backoff > MaxBuildBackoff {
+		backoff = MaxBuildBackoff
+	}
+	b.until = now.Add(backoff)
+}
+
+// Stats contains statistics about the last run of the reconciler.
+type Stats struct {
+	// CreatedWorkspaceIDs contains the IDs of all prebuilt workspaces that
+	// were created.
+	CreatedWorkspaceIDs []uuid.UUID
+	// DeletedWorkspaceIDs contains the IDs of all prebuilt workspaces that
+	// were scheduled for deletion.
+	DeletedWorkspaceIDs []uuid.UUID
+	// Error is the fatal error that occurred during the last run of the
+	// reconciler, if any.
+	Error error
+}
+
+// New returns a new prebuilt workspace reconciler.
+func New(ctx context.Context, db database.Store, log slog.Logger, tick <-chan time.Time) *Reconciler {
+	//nolint:gocritic // The reconciler has a limited set of permissions.
+	ctx, cancel := context.WithCancel(dbauthz.AsPrebuilds(ctx))
+	return &Reconciler{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		db:     db,
+		log:    log,
+		tick:   tick,
+		stats:  nil,
+
+		backoffs: map[uuid.UUID]poolBackoff{},
+	}
+}
+
+// WithStatsChannel will cause the reconciler to push a Stats to ch after
+// every tick. This push is blocking, so if ch is not read, the reconciler will
+// hang. This should only be used in tests.
+func (r *Reconciler) WithStatsChannel(ch chan<- Stats) *Reconciler {
+	r.stats = ch
+	return r
+}
+
+// Start will cause the reconciler to reconcile all pools on every tick from
+// its channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (r *Reconciler) Start() {
+	go func() {
+		defer close(r.done)
+		defer r.cancel()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case _, ok := <-r.tick:
+				if !ok {
+					return
+				}
+				stats := r.run()
+				if stats.Error != nil {
+					r.log.Warn(r.ctx, "error reconciling prebuilt workspaces", slog.Error(stats.Error))
+				}
+				if r.stats != nil {
+					select {
+					case <-r.ctx.Done():
+						return
+					case r.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Wait will block until the reconciler is stopped.
+func (r *Reconciler) Wait() {
+	<-r.done
+}
+
+// Close will stop the reconciler.
+func (r *Reconciler) Close() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Reconciler) run() Stats {
+	ctx, cancel := context.WithTimeout(r.ctx, 5*time.Minute)
+	defer cancel()
+
+	stats := Stats{
+		CreatedWorkspaceIDs: []uuid.UUID{},
+		DeletedWorkspaceIDs: []uuid.UUID{},
+		Error:               nil,
+	}
+
+	pools, err := r.db.GetWorkspacePrebuildPools(ctx)
+	if err != nil {
+		stats.Error = xerrors.Errorf("get workspace prebuild pools: %w", err)
+		return stats
+	}
+	instances, err := Instances(ctx, r.db)
+	if err != nil {
+		stats.Error = xerrors.Errorf("get prebuilt workspaces: %w", err)
+		return stats
+	}
+
+	// Prebuilt workspaces whose pool was deleted are no longer needed.
+	poolIDs := make(map[uuid.UUID]struct{}, len(pools))
+	for _, pool := range pools {
+		poolIDs[pool.ID] = struct{}{}
+	}
+	for _, instance := range instances {
+		if instance.PoolID.Valid {
+			if _, ok := poolIDs[instance.PoolID.UUID]; ok {
+				continue
+			}
+		}
+		if instance.State == StateBuilding {
+			// Wait for the build to finish, a workspace can only have a
+			// single active build.
+			continue
+		}
+		deleted, err := deleteInstance(ctx, r.db, instance)
+		if err != nil {
+			r.log.Error(ctx, "delete orphaned prebuilt workspace", slog.F("workspace_id", instance.Workspace.ID), slog.Error(err))
+			continue
+		}
+		if deleted {
+			stats.DeletedWorkspaceIDs = append(stats.DeletedWorkspaceIDs, instance.Workspace.ID)
+		}
+	}
+
+	// Forget the backoffs of pools that were deleted.
+	for poolID := range r.backoffs {
+		if _, ok := poolIDs[poolID]; !ok {
+			delete(r.backoffs, poolID)
+		}
+	}
+
+	for _, pool := range pools {
+		log := r.log.With(slog.F("pool_id", pool.ID), slog.F("template_id", pool.TemplateID))
+
+		// Failures of a previous version of the pool don't count.
+		backoff, ok := r.backoffs[pool.ID]
+		if !ok || backoff.templateVersionID != pool.TemplateVersionID {
+			backoff = poolBackoff{templateVersionID: pool.TemplateVersionID}
+		}
+
+		var created, deleted []uuid.UUID
+		var nextBackoff poolBackoff
+		err := r.db.InTx(func(tx database.Store) error {
+			locked, err := tx.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("prebuilds-reconciler:%s", pool.ID)))
+			if err != nil {
+				return xerrors.Errorf("acquire lock: %w", err)
+			}
+			if !locked {
+				// This error is ignored.
+				return acquireLockError{}
+			}
+
+			// Refetch the prebuilt workspaces while we hold the lock.
+			instances, err := Instances(ctx, tx)
+			if err != nil {
+				return xerrors.Errorf("get prebuilt workspaces: %w", err)
+			}
+
+			// The closure may be retried, so the backoff is only stored once
+			// the transaction succeeded.
+			nextBackoff = backoff
+			created, deleted, err = reconcilePool(ctx, tx, log, pool, instances, &nextBackoff, dbtime.Now())
+			return err
+		}, nil)
+		if err != nil {
+			if !xerrors.As(err, &acquireLockError{}) {
+				log.Error(ctx, "reconcile prebuild pool", slog.Error(err))
+			}
+			continue
+		}
+		r.backoffs[pool.ID] = nextBackoff
+		stats.CreatedWorkspaceIDs = append(stats.CreatedWorkspaceIDs, created...)
+		stats.DeletedWorkspaceIDs = append(stats.DeletedWorkspaceIDs, deleted...)
+	}
+
+	return stats
+}
+
+// reconcilePool deletes failed, stopped and surplus prebuilt workspaces of the
+// pool and builds the missing ones, unless builds of the pool are backing off
+// after failures.
+func reconcilePool(ctx context.Context, db database.Store, log slog.Logger, pool database.WorkspacePrebuildPool, instances []Instance, backoff *poolBackoff, now time.Time) (created, deleted []uuid.UUID, err error) {
+	template, err := db.GetTemplateByID(ctx, pool.TemplateID)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("get template: %w", err)
+	}
+
+	desired := int(pool.DesiredInstances)
+	// Nobody can create workspaces from deprecated templates, nor from old
+	// versions of templates that require the active version.
+	if template.Deleted || template.DeprecationMessage != "" ||
+		(template.RequireActiveVersion && template.ActiveVersionID != pool.TemplateVersionID) {
+		desired = 0
+	}
+
+	var ready, building []Instance
+	failed := 0
+	for _, instance := range instances {
+		if !instance.PoolID.Valid || instance.PoolID.UUID != pool.ID {
+			continue
+		}
+		switch instance.State {
+		case StateReady:
+			ready = append(ready, instance)
+		case StateBuilding:
+			building = append(building, instance)
+		default:
+			ok, err := deleteInstance(ctx, db, instance)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("delete %s prebuilt workspace: %w", instance.State, err)
+			}
+			if ok {
+				log.Info(ctx, "deleting prebuilt workspace", slog.F("workspace_id", instance.Workspace.ID), slog.F("state", instance.State))
+				deleted = append(deleted, instance.Workspace.ID)
+				if instance.State == StateFailed {
+					failed++
+				}
+			}
+		}
+	}
+	backoff.observe(ready, failed, now)
+
+	// Delete the newest ready workspaces if the pool has shrunk. Builds in
+	// progress are left alone until they complete.
+	for surplus := len(ready) + len(building) - desired; surplus > 0 && len(ready) > 0; surplus-- {
+		instance := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+
+		ok, err := deleteInstance(ctx, db, instance)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("delete surplus prebuilt workspace: %w", err)
+		}
+		if ok {
+			log.Info(ctx, "deleting surplus prebuilt workspace", slog.F("workspace_id", instance.Workspace.ID))
+			deleted = append(deleted, instance.Workspace.ID)
+		}
+	}
+
+	missing := desired - len(ready) - len(building)
+	if missing <= 0 {
+		return nil, deleted, nil
+	}
+	if now.Before(backoff.until) {
+		log.Debug(ctx, "backing off building prebuilt workspaces after failures",
+			slog.F("failures", backoff.failures), slog.F("until", backoff.until))
+		return nil, deleted, nil
+	}
+
+	parameters, err := Parameters(pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = ensureOwner(ctx, db, template.OrganizationID)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("ensure prebuilds owner: %w", err)
+	}
+
+	for i := 0; i < missing; i++ {
+		id := uuid.New()
+		now := dbtime.Now()
+		workspace, err := db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
+			ID:                id,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+			OwnerID:           OwnerID,
+			OrganizationID:    template.OrganizationID,
+			TemplateID:        template.ID,
+			Name:              "prebuild-" + hex.EncodeToString(id[:4]),
+			AutostartSchedule: sql.NullString{},
+			Ttl:               sql.NullInt64{},
+			LastUsedAt:        now,
+			AutomaticUpdates:  database.AutomaticUpdatesNever,
+		})
+		if err != nil {
+			return nil, nil, xerrors.Errorf("insert workspace: %w", err)
+		}
+
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+			Reason(database.BuildReasonInitiator).
+			Initiator(OwnerID).
+			VersionID(pool.TemplateVersionID).
+			RichParameterValues(parameters)
+		_, _, err = builder.Build(ctx, db, nil)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("build prebuilt workspace: %w", err)
+		}
+
+		err = db.InsertWorkspacePrebuild(ctx, database.InsertWorkspacePrebuildParams{
+			WorkspaceID: workspace.ID,
+			PoolID:      uuid.NullUUID{UUID: pool.ID, Valid: true},
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return nil, nil, xerrors.Errorf("insert workspace prebuild: %w", err)
+		}
+
+		log.Info(ctx, "building prebuilt workspace", slog.F("workspace_id", workspace.ID))
+		created = append(created, workspace.ID)
+	}
+
+	return created, deleted, nil
+}
+
+// deleteInstance removes the workspace from the pool so it can't be claimed
+// anymore, and schedules its deletion. It returns false if the workspace was
+// claimed in the meantime.
+func deleteInstance(ctx context.Context, db database.Store, instance Instance) (bool, error) {
+	var deleted bool
+	err := db.InTx(func(tx database.Store) error {
+		_, err := tx.DeleteWorkspacePrebuildByWorkspaceID(ctx, instance.Workspace.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("delete workspace prebuild: %w", err)
+		}
+
+		builder := wsbuilder.New(instance.Workspace, database.WorkspaceTransitionDelete).
+			Reason(database.BuildReasonInitiator).
+			Initiator(OwnerID)
+		_, _, err = builder.Build(ctx, tx, nil)
+		if err != nil {
+			return xerrors.Errorf("build workspace deletion: %w", err)
+		}
+		deleted = true
+		return nil
+	}, nil)
+	return deleted, err
+}
+
+// ensureOwner creates the system user that owns prebuilt workspaces, and adds
+// it to the organization. The user is created lazily so it doesn't interfere
+// with the setup of the first user.
+func ensureOwner(ctx context.Context, db database.Store, organizationID uuid.UUID) error {
+	//nolint:gocritic // Assigning the member roles requires system permissions.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	_, err := db.GetUserByID(ctx, OwnerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		now := dbtime.Now()
+		_, err = db.InsertUser(ctx, database.InsertUserParams{
+			ID:             OwnerID,
+			Email:          OwnerUsername + "@coder.internal",
+			Username:       OwnerUsername,
+			HashedPassword: []byte{},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			RBACRoles:      []string{},
+			LoginType:      database.LoginTypeNone,
+		})
+		if err != nil && !database.IsUniqueViolation(err) {
+			return xerrors.Errorf("insert user: %w", err)
+		}
+	} else if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+
+	_, err = db.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: organizationID,
+		UserID:         OwnerID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		now := dbtime.Now()
+		_, err = db.InsertOrganizationMember(ctx, database.InsertOrganizationMemberParams{
+			OrganizationID: organizationID,
+			UserID:         OwnerID,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			Roles:          []string{},
+		})
+		if err != nil && !database.IsUniqueViolation(err) {
+			return xerrors.Errorf("insert organization member: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("get organization member: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package prebuilds_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/prebuilds"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestReconciler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FillsPool", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan prebuilds.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				PrebuildsTicker:          tickCh,
+				PrebuildsStats:           statsCh,
+			})
+			owner    = coderdtest.CreateFirstUser(t, client)
+			version  = coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+			_        = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+			template = coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+			ctx      = testutil.Context(t, testutil.WaitLong)
+		)
+		defer close(tickCh)
+
+		pool, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			DesiredInstances:  2,
+		})
+		require.NoError(t, err)
+
+		// The first run builds the missing workspaces.
+		tickCh <- time.Now()
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.CreatedWorkspaceIDs, 2)
+		for _, id := range stats.CreatedWorkspaceIDs {
+			workspace := coderdtest.MustWorkspace(t, client, id)
+			assert.Equal(t, prebuilds.OwnerID, workspace.OwnerID)
+			assert.Equal(t, version.ID, workspace.LatestBuild.TemplateVersionID)
+			coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		}
+
+		// The pool is full now.
+		tickCh <- time.Now()
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.CreatedWorkspaceIDs)
+		require.Empty(t, stats.DeletedWorkspaceIDs)
+
+		pools, err := client.WorkspacePrebuildPools(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, pools, 1)
+		require.Equal(t, pool.ID, pools[0].ID)
+		require.EqualValues(t, 2, pools[0].ReadyInstances)
+		require.EqualValues(t, 0, pools[0].BuildingInstances)
+	})
+
+	t.Run("Claim", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan prebuilds.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				PrebuildsTicker:          tickCh,
+				PrebuildsStats:           statsCh,
+			})
+			owner        = coderdtest.CreateFirstUser(t, client)
+			member, user = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+			version      = coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+			_            = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+			template     = coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+			ctx          = testutil.Context(t, testutil.WaitLong)
+		)
+		defer close(tickCh)
+
+		_, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			DesiredInstances:  1,
+		})
+		require.NoError(t, err)
+
+		tickCh <- time.Now()
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.CreatedWorkspaceIDs, 1)
+		prebuilt := coderdtest.MustWorkspace(t, client, stats.CreatedWorkspaceIDs[0])
+		coderdtest.AwaitWorkspaceBuildJob(t, client, prebuilt.LatestBuild.ID)
+
+		// Creating a workspace claims the prebuilt workspace and starts it as
+		// the new owner.
+		workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.Name = "claimed"
+		})
+		require.Equal(t, prebuilt.ID, workspace.ID)
+		require.Equal(t, "claimed", workspace.Name)
+		require.Equal(t, user.ID, workspace.OwnerID)
+		require.NotNil(t, workspace.AutostartSchedule)
+		require.NotEqual(t, prebuilt.LatestBuild.ID, workspace.LatestBuild.ID)
+		require.Equal(t, user.ID, workspace.LatestBuild.InitiatorID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		// The pool is refilled.
+		tickCh <- time.Now()
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.CreatedWorkspaceIDs, 1)
+		require.NotEqual(t, prebuilt.ID, stats.CreatedWorkspaceIDs[0])
+
+		// Without a ready prebuilt workspace a new one is built.
+		other := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+		require.NotContains(t, stats.CreatedWorkspaceIDs, other.ID)
+		require.NotEqual(t, prebuilt.ID, other.ID)
+	})
+
+	t.Run("ParametersMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan prebuilds.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				PrebuildsTicker:          tickCh,
+				PrebuildsStats:           statsCh,
+			})
+			owner    = coderdtest.CreateFirstUser(t, client)
+			version  = coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, parameterResponses())
+			_        = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+			template = coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+			ctx      = testutil.Context(t, testutil.WaitLong)
+		)
+		defer close(tickCh)
+
+		_, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			Parameters:        []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}},
+			DesiredInstances:  1,
+		})
+		require.NoError(t, err)
+
+		tickCh <- time.Now()
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.CreatedWorkspaceIDs, 1)
+		prebuilt := coderdtest.MustWorkspace(t, client, stats.CreatedWorkspaceIDs[0])
+		coderdtest.AwaitWorkspaceBuildJob(t, client, prebuilt.LatestBuild.ID)
+
+		// The default value doesn't match the pool.
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		require.NotEqual(t, prebuilt.ID, workspace.ID)
+
+		workspace = coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}
+		})
+		require.Equal(t, prebuilt.ID, workspace.ID)
+	})
+
+	t.Run("ShrinksPool", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan prebuilds.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				PrebuildsTicker:          tickCh,
+				PrebuildsStats:           statsCh,
+			})
+			owner    = coderdtest.CreateFirstUser(t, client)
+			version  = coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+			_        = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+			template = coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+			ctx      = testutil.Context(t, testutil.WaitLong)
+		)
+		defer close(tickCh)
+
+		pool, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			DesiredInstances:  2,
+		})
+		require.NoError(t, err)
+
+		tickCh <- time.Now()
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.CreatedWorkspaceIDs, 2)
+		for _, id := range stats.CreatedWorkspaceIDs {
+			coderdtest.AwaitWorkspaceBuildJob(t, client, coderdtest.MustWorkspace(t, client, id).LatestBuild.ID)
+		}
+
+		_, err = client.UpdateWorkspacePrebuildPool(ctx, template.ID, pool.ID, codersdk.UpdateWorkspacePrebuildPoolRequest{
+			DesiredInstances: 1,
+		})
+		require.NoError(t, err)
+
+		tickCh <- time.Now()
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.CreatedWorkspaceIDs)
+		require.Len(t, stats.DeletedWorkspaceIDs, 1)
+
+		// Deleting the pool deletes the remaining prebuilt workspace.
+		err = client.DeleteWorkspacePrebuildPool(ctx, template.ID, pool.ID)
+		require.NoError(t, err)
+
+		tickCh <- time.Now()
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Len(t, stats.DeletedWorkspaceIDs, 1)
+	})
+}
+
+func TestReconcilerBackoff(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan prebuilds.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			PrebuildsTicker:          tickCh,
+			PrebuildsStats:           statsCh,
+		})
+		owner   = coderdtest.CreateFirstUser(t, client)
+		version = coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ApplyFailed,
+		})
+		_        = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template = coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		ctx      = testutil.Context(t, testutil.WaitLong)
+	)
+	defer close(tickCh)
+
+	_, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+		TemplateVersionID: version.ID,
+		DesiredInstances:  1,
+	})
+	require.NoError(t, err)
+
+	tickCh <- time.Now()
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.CreatedWorkspaceIDs, 1)
+	failed := coderdtest.MustWorkspace(t, client, stats.CreatedWorkspaceIDs[0])
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, failed.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+
+	// The failed workspace is deleted, but no new one is built until the
+	// backoff has passed.
+	tickCh <- time.Now()
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{failed.ID}, stats.DeletedWorkspaceIDs)
+	require.Empty(t, stats.CreatedWorkspaceIDs)
+
+	for i := 0; i < 3; i++ {
+		tickCh <- time.Now()
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.CreatedWorkspaceIDs)
+	}
+}
+
+func parameterResponses() *echo.Responses {
+	return &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{{
+						Name:         "region",
+						Type:         "string",
+						DefaultValue: "us",
+						Mutable:      true,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	}
+}
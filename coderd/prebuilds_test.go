@@ -0,0 +1,173 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspacePrebuildPools(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		pool, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			DesiredInstances:  3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, pool.TemplateID)
+		require.Equal(t, version.ID, pool.TemplateVersionID)
+		require.EqualValues(t, 3, pool.DesiredInstances)
+		require.Empty(t, pool.Parameters)
+
+		pool, err = client.UpdateWorkspacePrebuildPool(ctx, template.ID, pool.ID, codersdk.UpdateWorkspacePrebuildPoolRequest{
+			DesiredInstances: 1,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, pool.DesiredInstances)
+
+		pools, err := client.WorkspacePrebuildPools(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, pools, 1)
+		require.Equal(t, pool.ID, pools[0].ID)
+		require.EqualValues(t, 1, pools[0].DesiredInstances)
+
+		err = client.DeleteWorkspacePrebuildPool(ctx, template.ID, pool.ID)
+		require.NoError(t, err)
+
+		pools, err = client.WorkspacePrebuildPools(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, pools)
+	})
+
+	t.Run("InvalidParameters", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Parameters: []*proto.RichParameter{{
+							Name:     "region",
+							Type:     "string",
+							Required: true,
+						}},
+					},
+				},
+			}},
+			ProvisionApply: echo.ApplyComplete,
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			DesiredInstances:  1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Validations[0].Detail, `"region" is required`)
+
+		_, err = client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			Parameters: []codersdk.WorkspaceBuildParameter{
+				{Name: "region", Value: "eu"},
+				{Name: "zone", Value: "a"},
+			},
+			DesiredInstances: 1,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Validations[0].Detail, `"zone" does not exist`)
+
+		pool, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			Parameters:        []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}},
+			DesiredInstances:  1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}, pool.Parameters)
+	})
+
+	t.Run("OtherTemplateVersion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, otherVersion.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: otherVersion.ID,
+			DesiredInstances:  1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Pools can only be managed through their own template.
+		pool, err := client.CreateWorkspacePrebuildPool(ctx, otherTemplate.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: otherVersion.ID,
+			DesiredInstances:  1,
+		})
+		require.NoError(t, err)
+		err = client.DeleteWorkspacePrebuildPool(ctx, template.ID, pool.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		err = client.DeleteWorkspacePrebuildPool(ctx, otherTemplate.ID, uuid.New())
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			DesiredInstances:  1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Members can see the pools of templates they can use.
+		pools, err := member.WorkspacePrebuildPools(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, pools)
+	})
+}
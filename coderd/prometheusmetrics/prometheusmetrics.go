@@ -19,11 +19,14 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/tailnet"
 )
 
 const (
 	agentNameLabel     = "agent_name"
+	poolIDLabel        = "pool_id"
+	templateNameLabel  = "template_name"
 	usernameLabel      = "username"
 	workspaceNameLabel = "workspace_name"
 )
@@ -142,6 +145,105 @@ func Workspaces(ctx context.Context, registerer prometheus.Registerer, db databa
 	}, nil
 }
 
+// Prebuilds tracks the desired and actual number of prebuilt workspaces of
+// every prebuild pool.
+func Prebuilds(ctx context.Context, registerer prometheus.Registerer, db database.Store, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = 1 * time.Minute
+	}
+
+	desiredGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "prebuilds",
+		Name:      "desired_instances",
+		Help:      "The number of prebuilt workspaces a pool should have.",
+	}, []string{templateNameLabel, poolIDLabel})
+	err := registerer.Register(desiredGauge)
+	if err != nil {
+		return nil, err
+	}
+	instancesGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "prebuilds",
+		Name:      "instances",
+		Help:      "The number of unclaimed prebuilt workspaces of a pool by state.",
+	}, []string{templateNameLabel, poolIDLabel, "state"})
+	err = registerer.Register(instancesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(duration)
+
+		pools, err := db.GetWorkspacePrebuildPools(ctx)
+		if err != nil {
+			return
+		}
+		instances, err := prebuilds.Instances(ctx, db)
+		if err != nil {
+			return
+		}
+
+		templateNames := map[uuid.UUID]string{}
+		poolTemplateNames := map[uuid.UUID]string{}
+		for _, pool := range pools {
+			name, ok := templateNames[pool.TemplateID]
+			if !ok {
+				template, err := db.GetTemplateByID(ctx, pool.TemplateID)
+				if err != nil {
+					return
+				}
+				name = template.Name
+				templateNames[pool.TemplateID] = name
+			}
+			poolTemplateNames[pool.ID] = name
+		}
+
+		desiredGauge.Reset()
+		instancesGauge.Reset()
+		for _, pool := range pools {
+			desiredGauge.WithLabelValues(poolTemplateNames[pool.ID], pool.ID.String()).Set(float64(pool.DesiredInstances))
+			for _, state := range []prebuilds.State{prebuilds.StateReady, prebuilds.StateBuilding, prebuilds.StateFailed} {
+				instancesGauge.WithLabelValues(poolTemplateNames[pool.ID], pool.ID.String(), string(state)).Set(0)
+			}
+		}
+		for _, instance := range instances {
+			if !instance.PoolID.Valid {
+				continue
+			}
+			name, ok := poolTemplateNames[instance.PoolID.UUID]
+			if !ok {
+				continue
+			}
+			instancesGauge.WithLabelValues(name, instance.PoolID.UUID.String(), string(instance.State)).Add(1)
+		}
+	}
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				doTick()
+			}
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
+
 // Agents tracks the total number of workspaces with labels on status.
 func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, coordinator *atomic.Pointer[tailnet.Coordinator], derpMapFn func() *tailcfg.DERPMap, agentInactiveDisconnectTimeout, duration time.Duration) (func(), error) {
 	if duration == 0 {
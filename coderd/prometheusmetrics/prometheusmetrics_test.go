@@ -259,6 +259,60 @@ func TestWorkspaces(t *testing.T) {
 	}
 }
 
+func TestPrebuilds(t *testing.T) {
+	t.Parallel()
+
+	db := dbfake.New()
+	template := dbgen.Template(t, db, database.Template{Name: "docker"})
+	pool := dbgen.WorkspacePrebuildPool(t, db, database.WorkspacePrebuildPool{
+		TemplateID:       template.ID,
+		DesiredInstances: 3,
+	})
+	insertPrebuild := func(job database.ProvisionerJob) {
+		workspace := dbgen.Workspace(t, db, database.Workspace{TemplateID: template.ID})
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			JobID:       job.ID,
+			Transition:  database.WorkspaceTransitionStart,
+		})
+		dbgen.WorkspacePrebuild(t, db, database.WorkspacePrebuild{
+			WorkspaceID: workspace.ID,
+			PoolID:      uuid.NullUUID{UUID: pool.ID, Valid: true},
+		})
+	}
+	completed := sql.NullTime{Time: dbtime.Now(), Valid: true}
+	insertPrebuild(dbgen.ProvisionerJob(t, db, database.ProvisionerJob{StartedAt: completed, CompletedAt: completed}))
+	insertPrebuild(dbgen.ProvisionerJob(t, db, database.ProvisionerJob{StartedAt: completed, CompletedAt: completed}))
+	insertPrebuild(dbgen.ProvisionerJob(t, db, database.ProvisionerJob{}))
+
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.Prebuilds(context.Background(), registry, db, time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+
+		values := map[string]float64{}
+		for _, family := range metrics {
+			for _, metric := range family.Metric {
+				name := family.GetName()
+				for _, label := range metric.Label {
+					if label.GetName() == "state" {
+						name += ":" + label.GetValue()
+					}
+				}
+				values[name] = metric.Gauge.GetValue()
+			}
+		}
+		return values["coderd_prebuilds_desired_instances"] == 3 &&
+			values["coderd_prebuilds_instances:ready"] == 2 &&
+			values["coderd_prebuilds_instances:building"] == 1 &&
+			values["coderd_prebuilds_instances:failed"] == 0
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestAgents(t *testing.T) {
 	t.Parallel()
 
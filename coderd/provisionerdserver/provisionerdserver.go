@@ -29,14 +29,17 @@ import (
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/gitauth"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner"
 	"github.com/coder/coder/v2/provisionerd/proto"
@@ -52,6 +55,9 @@ var (
 type Options struct {
 	OIDCConfig     httpmw.OAuth2Config
 	GitAuthConfigs []*gitauth.Config
+	// Notifications notifies the owners of workspaces that failed to build.
+	// Owners aren't notified if it's nil.
+	Notifications *notifications.Enqueuer
 	// TimeNowFn is only used in tests
 	TimeNowFn func() time.Time
 }
@@ -75,6 +81,7 @@ type server struct {
 
 	AcquireJobDebounce time.Duration
 	OIDCConfig         httpmw.OAuth2Config
+	Notifications      *notifications.Enqueuer
 
 	TimeNowFn func() time.Time
 }
@@ -131,6 +138,7 @@ func NewServer(
 		DeploymentValues:            deploymentValues,
 		AcquireJobDebounce:          acquireJobDebounce,
 		OIDCConfig:                  options.OIDCConfig,
+		Notifications:               options.Notifications,
 		TimeNowFn:                   options.TimeNowFn,
 	}, nil
 }
@@ -744,6 +752,16 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
+
+		if !input.DryRun {
+			workspace, err := s.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+			if err != nil {
+				s.Logger.Warn(ctx, "webhook - get workspace", slog.Error(err))
+			} else {
+				s.enqueueWorkspaceWebhook(ctx, codersdk.WebhookEventWorkspaceBuildFailed, workspace, build, failJob.Error)
+				s.notifyWorkspaceBuildFailed(ctx, workspace, build, failJob.Error)
+			}
+		}
 	case *proto.FailedJob_TemplateImport_:
 	}
 
@@ -858,6 +876,7 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			}
 		}
 
+		parameters := make([]database.TemplateVersionParameter, 0, len(jobType.TemplateImport.RichParameters))
 		for _, richParameter := range jobType.TemplateImport.RichParameters {
 			s.Logger.Info(ctx, "inserting template import job parameter",
 				slog.F("job_id", job.ID.String()),
@@ -884,7 +903,7 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				}
 			}
 
-			_, err = s.Database.InsertTemplateVersionParameter(ctx, database.InsertTemplateVersionParameterParams{
+			parameter, err := s.Database.InsertTemplateVersionParameter(ctx, database.InsertTemplateVersionParameterParams{
 				TemplateVersionID:   input.TemplateVersionID,
 				Name:                richParameter.Name,
 				DisplayName:         richParameter.DisplayName,
@@ -906,10 +925,47 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			if err != nil {
 				return nil, xerrors.Errorf("insert parameter: %w", err)
 			}
+			parameters = append(parameters, parameter)
 		}
 
 		var completedError sql.NullString
 
+		err = validatePresets(jobType.TemplateImport.Presets, parameters)
+		if err != nil {
+			completedError = sql.NullString{
+				String: err.Error(),
+				Valid:  true,
+			}
+		} else {
+			for _, preset := range jobType.TemplateImport.Presets {
+				s.Logger.Info(ctx, "inserting template import job preset",
+					slog.F("job_id", job.ID.String()),
+					slog.F("preset_name", preset.Name),
+				)
+				presetParameters := make([]codersdk.WorkspaceBuildParameter, 0, len(preset.Parameters))
+				for _, presetParameter := range preset.Parameters {
+					presetParameters = append(presetParameters, codersdk.WorkspaceBuildParameter{
+						Name:  presetParameter.Name,
+						Value: presetParameter.Value,
+					})
+				}
+				rawParameters, err := json.Marshal(presetParameters)
+				if err != nil {
+					return nil, xerrors.Errorf("marshal preset parameters: %w", err)
+				}
+				_, err = s.Database.InsertTemplateVersionPreset(ctx, database.InsertTemplateVersionPresetParams{
+					ID:                uuid.New(),
+					TemplateVersionID: input.TemplateVersionID,
+					Name:              preset.Name,
+					Parameters:        rawParameters,
+					CreatedAt:         dbtime.Now(),
+				})
+				if err != nil {
+					return nil, xerrors.Errorf("insert preset: %w", err)
+				}
+			}
+		}
+
 		for _, gitAuthProvider := range jobType.TemplateImport.GitAuthProviders {
 			contains := false
 			for _, configuredProvider := range s.GitAuthConfigs {
@@ -1125,6 +1181,17 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			})
 		}
 
+		if getWorkspaceError == nil && !input.DryRun {
+			event := codersdk.WebhookEventWorkspaceStarted
+			switch workspaceBuild.Transition {
+			case database.WorkspaceTransitionStop:
+				event = codersdk.WebhookEventWorkspaceStopped
+			case database.WorkspaceTransitionDelete:
+				event = codersdk.WebhookEventWorkspaceDeleted
+			}
+			s.enqueueWorkspaceWebhook(ctx, event, workspace, workspaceBuild, "")
+		}
+
 		err = s.Pubsub.Publish(codersdk.WorkspaceNotifyChannel(workspaceBuild.WorkspaceID), []byte{})
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
@@ -1180,6 +1247,54 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 	return &proto.Empty{}, nil
 }
 
+// enqueueWorkspaceWebhook queues a webhook event about a workspace build.
+// Failing to queue the event doesn't fail the job.
+func (s *server) enqueueWorkspaceWebhook(ctx context.Context, event codersdk.WebhookEvent, workspace database.Workspace, build database.WorkspaceBuild, buildError string) {
+	//nolint:gocritic // Provisionerd can't read the workspace owner.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	owner, err := s.Database.GetUserByID(sysCtx, workspace.OwnerID)
+	if err != nil {
+		s.Logger.Warn(ctx, "webhook - get workspace owner", slog.Error(err))
+		return
+	}
+	template, err := s.Database.GetTemplateByID(sysCtx, workspace.TemplateID)
+	if err != nil {
+		s.Logger.Warn(ctx, "webhook - get template", slog.Error(err))
+		return
+	}
+
+	payload := webhooks.WorkspaceBuildPayload(event, workspace, owner, template, build, buildError)
+	err = webhooks.Enqueue(ctx, s.Database, s.Pubsub, payload)
+	if err != nil {
+		s.Logger.Warn(ctx, "enqueue webhook event", slog.F("event", event), slog.Error(err))
+	}
+}
+
+// notifyWorkspaceBuildFailed notifies the owner of the workspace about the
+// failed build, unless they started the build themselves and saw it fail.
+func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, buildError string) {
+	if s.Notifications == nil {
+		return
+	}
+	if build.Reason == database.BuildReasonInitiator && build.InitiatorID == workspace.OwnerID {
+		return
+	}
+	err := s.Notifications.Enqueue(ctx, notifications.Notification{
+		UserID: workspace.OwnerID,
+		Kind:   database.NotificationKindWorkspaceBuildFailed,
+		Labels: map[string]string{
+			"workspace":    workspace.Name,
+			"transition":   string(build.Transition),
+			"build_number": strconv.FormatInt(int64(build.BuildNumber), 10),
+			"error":        buildError,
+		},
+		DedupeKey: fmt.Sprintf("%s:%s", database.NotificationKindWorkspaceBuildFailed, build.ID),
+	})
+	if err != nil {
+		s.Logger.Warn(ctx, "enqueue build failed notification", slog.Error(err))
+	}
+}
+
 func (s *server) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	return s.Tracer.Start(ctx, name, append(opts, trace.WithAttributes(
 		semconv.ServiceNameKey.String("coderd.provisionerd"),
@@ -1247,6 +1362,14 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		if prAgent.GetStartupScriptBehavior() == "" {
 			prAgent.StartupScriptBehavior = string(codersdk.WorkspaceAgentStartupScriptBehaviorNonBlocking)
 		}
+		// Scripts that block login must be waited on by clients, the
+		// same as a blocking startup script.
+		for _, script := range prAgent.Scripts {
+			if script.StartBlocksLogin {
+				prAgent.StartupScriptBehavior = string(codersdk.WorkspaceAgentStartupScriptBehaviorBlocking)
+				break
+			}
+		}
 
 		agentID := uuid.New()
 		dbAgent, err := db.InsertWorkspaceAgent(ctx, database.InsertWorkspaceAgentParams{
@@ -1297,6 +1420,16 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			}
 		}
 
+		err = insertAgentScripts(ctx, db, dbAgent.ID, agentScripts(prAgent))
+		if err != nil {
+			return err
+		}
+
+		err = insertAgentLogFiles(ctx, db, dbAgent.ID, prAgent.LogFiles)
+		if err != nil {
+			return err
+		}
+
 		for _, app := range prAgent.Apps {
 			slug := app.Slug
 			if slug == "" {
@@ -1314,7 +1447,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			if app.Healthcheck == nil {
 				app.Healthcheck = &sdkproto.Healthcheck{}
 			}
-			if app.Healthcheck.Url != "" {
+			if app.Healthcheck.Url != "" || app.Healthcheck.Tcp != "" || app.Healthcheck.Command != "" {
 				health = database.WorkspaceAppHealthInitializing
 			}
 
@@ -1347,6 +1480,8 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 				HealthcheckUrl:       app.Healthcheck.Url,
 				HealthcheckInterval:  app.Healthcheck.Interval,
 				HealthcheckThreshold: app.Healthcheck.Threshold,
+				HealthcheckTCP:       app.Healthcheck.Tcp,
+				HealthcheckCommand:   app.Healthcheck.Command,
 				Health:               health,
 			})
 			if err != nil {
@@ -1420,7 +1555,7 @@ func deleteSessionToken(ctx context.Context, db database.Store, workspace databa
 			TokenName: workspaceSessionTokenName(workspace),
 		})
 		if err == nil {
-			err = tx.DeleteAPIKeyByID(ctx, key.ID)
+			_, err = tx.DeleteAPIKeyByID(ctx, key.ID)
 		}
 
 		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
@@ -1509,6 +1644,33 @@ func convertLogSource(logSource proto.LogSource) (database.LogSource, error) {
 	}
 }
 
+// validatePresets checks that every parameter value of the presets belongs
+// to a parameter of the template version and satisfies its validation rules.
+func validatePresets(presets []*sdkproto.Preset, parameters []database.TemplateVersionParameter) error {
+	for _, preset := range presets {
+		for _, presetParameter := range preset.Parameters {
+			idx := slices.IndexFunc(parameters, func(parameter database.TemplateVersionParameter) bool {
+				return parameter.Name == presetParameter.Name
+			})
+			if idx == -1 {
+				return xerrors.Errorf("preset %q sets parameter %q which does not exist", preset.Name, presetParameter.Name)
+			}
+			richParameter, err := db2sdk.TemplateVersionParameter(parameters[idx])
+			if err != nil {
+				return xerrors.Errorf("convert parameter %q: %w", presetParameter.Name, err)
+			}
+			err = codersdk.ValidateWorkspaceBuildParameter(richParameter, &codersdk.WorkspaceBuildParameter{
+				Name:  presetParameter.Name,
+				Value: presetParameter.Value,
+			}, nil)
+			if err != nil {
+				return xerrors.Errorf("preset %q: %w", preset.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
 func convertRichParameterValues(workspaceBuildParameters []database.WorkspaceBuildParameter) []*sdkproto.RichParameterValue {
 	protoParameters := make([]*sdkproto.RichParameterValue, len(workspaceBuildParameters))
 	for i, buildParameter := range workspaceBuildParameters {
@@ -1638,3 +1800,144 @@ func convertDisplayApps(apps *sdkproto.DisplayApps) []database.DisplayApp {
 	}
 	return dapps
 }
+
+// agentScripts returns the scripts the agent should run. The legacy
+// startup and shutdown scripts are converted to scripts so agents only
+// have to handle a single representation.
+func agentScripts(prAgent *sdkproto.Agent) []*sdkproto.Script {
+	scripts := make([]*sdkproto.Script, 0, len(prAgent.Scripts)+2)
+	if prAgent.StartupScript != "" {
+		scripts = append(scripts, &sdkproto.Script{
+			DisplayName:      "Startup Script",
+			Icon:             "/emojis/25b6.png",
+			Script:           prAgent.StartupScript,
+			RunOnStart:       true,
+			StartBlocksLogin: prAgent.StartupScriptBehavior == string(codersdk.WorkspaceAgentStartupScriptBehaviorBlocking),
+			TimeoutSeconds:   prAgent.StartupScriptTimeoutSeconds,
+			LogPath:          "coder-startup-script.log",
+		})
+	}
+	scripts = append(scripts, prAgent.Scripts...)
+	if prAgent.ShutdownScript != "" {
+		scripts = append(scripts, &sdkproto.Script{
+			DisplayName:    "Shutdown Script",
+			Icon:           "/emojis/25c0.png",
+			Script:         prAgent.ShutdownScript,
+			RunOnStop:      true,
+			TimeoutSeconds: prAgent.ShutdownScriptTimeoutSeconds,
+			LogPath:        "coder-shutdown-script.log",
+		})
+	}
+	return scripts
+}
+
+// insertAgentScripts inserts the scripts for an agent, each with its own
+// log source.
+func insertAgentScripts(ctx context.Context, db database.Store, agentID uuid.UUID, scripts []*sdkproto.Script) error {
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	logSourceIDs := make([]uuid.UUID, 0, len(scripts))
+	logSourceDisplayNames := make([]string, 0, len(scripts))
+	logSourceIcons := make([]string, 0, len(scripts))
+	scriptIDs := make([]uuid.UUID, 0, len(scripts))
+	scriptLogPaths := make([]string, 0, len(scripts))
+	scriptSources := make([]string, 0, len(scripts))
+	scriptCron := make([]string, 0, len(scripts))
+	scriptTimeout := make([]int32, 0, len(scripts))
+	scriptDisplayOrder := make([]int32, 0, len(scripts))
+	scriptStartBlocksLogin := make([]bool, 0, len(scripts))
+	scriptRunOnStart := make([]bool, 0, len(scripts))
+	scriptRunOnStop := make([]bool, 0, len(scripts))
+
+	for index, script := range scripts {
+		logSourceIDs = append(logSourceIDs, uuid.New())
+		logSourceDisplayNames = append(logSourceDisplayNames, script.DisplayName)
+		logSourceIcons = append(logSourceIcons, script.Icon)
+		scriptIDs = append(scriptIDs, uuid.New())
+		scriptLogPaths = append(scriptLogPaths, script.LogPath)
+		scriptSources = append(scriptSources, script.Script)
+		scriptCron = append(scriptCron, script.Cron)
+		scriptTimeout = append(scriptTimeout, script.TimeoutSeconds)
+		scriptDisplayOrder = append(scriptDisplayOrder, int32(index))
+		scriptStartBlocksLogin = append(scriptStartBlocksLogin, script.StartBlocksLogin)
+		scriptRunOnStart = append(scriptRunOnStart, script.RunOnStart)
+		scriptRunOnStop = append(scriptRunOnStop, script.RunOnStop)
+	}
+
+	_, err := db.InsertWorkspaceAgentLogSources(ctx, database.InsertWorkspaceAgentLogSourcesParams{
+		WorkspaceAgentID: agentID,
+		CreatedAt:        dbtime.Now(),
+		ID:               logSourceIDs,
+		DisplayName:      logSourceDisplayNames,
+		Icon:             logSourceIcons,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert agent log sources: %w", err)
+	}
+
+	_, err = db.InsertWorkspaceAgentScripts(ctx, database.InsertWorkspaceAgentScriptsParams{
+		WorkspaceAgentID: agentID,
+		CreatedAt:        dbtime.Now(),
+		ID:               scriptIDs,
+		LogSourceID:      logSourceIDs,
+		LogPath:          scriptLogPaths,
+		Script:           scriptSources,
+		Cron:             scriptCron,
+		TimeoutSeconds:   scriptTimeout,
+		DisplayOrder:     scriptDisplayOrder,
+		StartBlocksLogin: scriptStartBlocksLogin,
+		RunOnStart:       scriptRunOnStart,
+		RunOnStop:        scriptRunOnStop,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert agent scripts: %w", err)
+	}
+	return nil
+}
+
+// insertAgentLogFiles inserts the files the agent tails, each with its own
+// log source.
+func insertAgentLogFiles(ctx context.Context, db database.Store, agentID uuid.UUID, logFiles []*sdkproto.LogFile) error {
+	if len(logFiles) == 0 {
+		return nil
+	}
+
+	logSourceIDs := make([]uuid.UUID, 0, len(logFiles))
+	logSourceDisplayNames := make([]string, 0, len(logFiles))
+	logSourceIcons := make([]string, 0, len(logFiles))
+	logFileIDs := make([]uuid.UUID, 0, len(logFiles))
+	logFilePaths := make([]string, 0, len(logFiles))
+
+	for _, logFile := range logFiles {
+		logSourceIDs = append(logSourceIDs, uuid.New())
+		logSourceDisplayNames = append(logSourceDisplayNames, logFile.DisplayName)
+		logSourceIcons = append(logSourceIcons, logFile.Icon)
+		logFileIDs = append(logFileIDs, uuid.New())
+		logFilePaths = append(logFilePaths, logFile.Path)
+	}
+
+	_, err := db.InsertWorkspaceAgentLogSources(ctx, database.InsertWorkspaceAgentLogSourcesParams{
+		WorkspaceAgentID: agentID,
+		CreatedAt:        dbtime.Now(),
+		ID:               logSourceIDs,
+		DisplayName:      logSourceDisplayNames,
+		Icon:             logSourceIcons,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert agent log sources: %w", err)
+	}
+
+	_, err = db.InsertWorkspaceAgentLogFiles(ctx, database.InsertWorkspaceAgentLogFilesParams{
+		WorkspaceAgentID: agentID,
+		CreatedAt:        dbtime.Now(),
+		ID:               logFileIDs,
+		LogSourceID:      logSourceIDs,
+		Path:             logFilePaths,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert agent log files: %w", err)
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
@@ -764,7 +765,8 @@ func TestFailJob(t *testing.T) {
 		srvID := uuid.New()
 		srv, db, ps := setup(t, ignoreLogErrors, &overrides{id: &srvID})
 		workspace, err := db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
-			ID: uuid.New(),
+			ID:               uuid.New(),
+			AutomaticUpdates: database.AutomaticUpdatesNever,
 		})
 		require.NoError(t, err)
 		buildID := uuid.New()
@@ -825,6 +827,61 @@ func TestFailJob(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "some state", string(build.ProvisionerState))
 	})
+	t.Run("WorkspaceBuildNotification", func(t *testing.T) {
+		t.Parallel()
+		srvID := uuid.New()
+		srv, db, _ := setup(t, true, &overrides{id: &srvID})
+		owner := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{
+			OwnerID: owner.ID,
+			Name:    "dev",
+		})
+		buildID := uuid.New()
+		input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+			WorkspaceBuildID: buildID,
+		})
+		require.NoError(t, err)
+		job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			Input:       input,
+			Provisioner: database.ProvisionerTypeEcho,
+			Type:        database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			ID:          buildID,
+			WorkspaceID: workspace.ID,
+			JobID:       job.ID,
+			BuildNumber: 2,
+			Transition:  database.WorkspaceTransitionStart,
+			Reason:      database.BuildReasonAutostart,
+		})
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srvID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.FailJob(ctx, &proto.FailedJob{
+			JobId: job.ID.String(),
+			Error: "exit status 1",
+			Type: &proto.FailedJob_WorkspaceBuild_{
+				WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{},
+			},
+		})
+		require.NoError(t, err)
+
+		// The owner didn't start the build, so they're notified about the
+		// failure.
+		messages, err := db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+			UserID: owner.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.Equal(t, database.NotificationKindWorkspaceBuildFailed, messages[0].Kind)
+		require.Equal(t, `Build #2 of your workspace "dev" failed: exit status 1`, messages[0].Content)
+	})
 }
 
 func TestCompleteJob(t *testing.T) {
@@ -917,6 +974,64 @@ func TestCompleteJob(t *testing.T) {
 		require.Contains(t, job.Error.String, `git auth provider "github" is not configured`)
 	})
 
+	t.Run("TemplateImport_InvalidPreset", func(t *testing.T) {
+		t.Parallel()
+		srvID := uuid.New()
+		srv, db, _ := setup(t, false, &overrides{id: &srvID})
+		jobID := uuid.New()
+		versionID := uuid.New()
+		err := db.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+			ID:    versionID,
+			JobID: jobID,
+		})
+		require.NoError(t, err)
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:            jobID,
+			Provisioner:   database.ProvisionerTypeEcho,
+			Input:         []byte(`{"template_version_id": "` + versionID.String() + `"}`),
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srvID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_TemplateImport_{
+				TemplateImport: &proto.CompletedJob_TemplateImport{
+					StartResources: []*sdkproto.Resource{},
+					StopResources:  []*sdkproto.Resource{},
+					RichParameters: []*sdkproto.RichParameter{{
+						Name:         "size",
+						Type:         "string",
+						DefaultValue: "small",
+						Options: []*sdkproto.RichParameterOption{
+							{Name: "Small", Value: "small"},
+							{Name: "Large", Value: "large"},
+						},
+					}},
+					Presets: []*sdkproto.Preset{{
+						Name:       "huge",
+						Parameters: []*sdkproto.PresetParameter{{Name: "size", Value: "huge"}},
+					}},
+				},
+			},
+		})
+		require.NoError(t, err)
+		job, err = db.GetProvisionerJobByID(ctx, job.ID)
+		require.NoError(t, err)
+		require.Contains(t, job.Error.String, `preset "huge"`)
+		presets, err := db.GetTemplateVersionPresets(ctx, versionID)
+		require.NoError(t, err)
+		require.Empty(t, presets)
+	})
+
 	t.Run("TemplateImport_WithGitAuth", func(t *testing.T) {
 		t.Parallel()
 		srvID := uuid.New()
@@ -1601,6 +1716,108 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		}, agent.DisplayApps)
 	})
 
+	t.Run("Scripts", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				StartupScript:               "startup",
+				StartupScriptTimeoutSeconds: 60,
+				Scripts: []*sdkproto.Script{{
+					DisplayName:      "Dotfiles",
+					Icon:             "/icon/dotfiles.svg",
+					Script:           "dotfiles",
+					RunOnStart:       true,
+					StartBlocksLogin: true,
+					TimeoutSeconds:   30,
+				}, {
+					DisplayName: "Backup",
+					Script:      "backup",
+					Cron:        "0 0 * * * *",
+				}},
+				ShutdownScript: "shutdown",
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		agent := agents[0]
+		// A script blocking login makes the agent blocking.
+		require.Equal(t, database.StartupScriptBehaviorBlocking, agent.StartupScriptBehavior)
+
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(ctx, []uuid.UUID{agent.ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 4)
+		sources, err := db.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, []uuid.UUID{agent.ID})
+		require.NoError(t, err)
+		require.Len(t, sources, 4)
+		names := map[uuid.UUID]string{}
+		for _, source := range sources {
+			names[source.ID] = source.DisplayName
+		}
+
+		// The legacy startup script runs first and the legacy shutdown
+		// script last.
+		require.Equal(t, "startup", scripts[0].Script)
+		require.Equal(t, "Startup Script", names[scripts[0].LogSourceID])
+		require.True(t, scripts[0].RunOnStart)
+		require.EqualValues(t, 60, scripts[0].TimeoutSeconds)
+		require.Equal(t, "dotfiles", scripts[1].Script)
+		require.Equal(t, "Dotfiles", names[scripts[1].LogSourceID])
+		require.True(t, scripts[1].StartBlocksLogin)
+		require.EqualValues(t, 30, scripts[1].TimeoutSeconds)
+		require.Equal(t, "backup", scripts[2].Script)
+		require.Equal(t, "0 0 * * * *", scripts[2].Cron)
+		require.Equal(t, "shutdown", scripts[3].Script)
+		require.Equal(t, "Shutdown Script", names[scripts[3].LogSourceID])
+		require.True(t, scripts[3].RunOnStop)
+		for _, script := range scripts {
+			require.Equal(t, database.WorkspaceAgentScriptStatusPending, script.Status)
+		}
+	})
+
+	t.Run("LogFiles", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				LogFiles: []*sdkproto.LogFile{{
+					DisplayName: "App",
+					Icon:        "/icon/database.svg",
+					Path:        "/var/log/app.log",
+				}},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+
+		logFiles, err := db.GetWorkspaceAgentLogFilesByAgentID(ctx, agents[0].ID)
+		require.NoError(t, err)
+		require.Len(t, logFiles, 1)
+		require.Equal(t, "/var/log/app.log", logFiles[0].Path)
+		sources, err := db.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, sources, 1)
+		require.Equal(t, logFiles[0].LogSourceID, sources[0].ID)
+		require.Equal(t, "App", sources[0].DisplayName)
+		require.Equal(t, "/icon/database.svg", sources[0].Icon)
+	})
+
 	t.Run("AllDisplayApps", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
@@ -1725,6 +1942,7 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 		-time.Minute,
 		provisionerdserver.Options{
 			GitAuthConfigs: gitAuthConfigs,
+			Notifications:  notifications.NewEnqueuer(db, ps, false),
 			TimeNowFn:      timeNowFn,
 			OIDCConfig:     &oauth2.Config{},
 		},
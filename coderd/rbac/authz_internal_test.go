@@ -1021,6 +1021,70 @@ func TestAuthorizeScope(t *testing.T) {
 			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), actions: []Action{ActionCreate}, allow: false},
 		},
 	)
+
+	// This scope limits an owner to reading workspaces and a single template
+	templateID := uuid.NewString()
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(RoleMember())),
+		},
+		Scope: CustomScope([]ScopeRule{
+			{ResourceType: ResourceWorkspace.Type, Action: ActionRead},
+			{ResourceType: ResourceTemplate.Type, Action: ActionRead, ResourceID: templateID},
+		}),
+	}
+
+	testAuthorize(t, "CustomScope", user,
+		// Actions not allowed by the rules.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionCreate, ActionUpdate, ActionDelete}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceTemplate.WithID(uuid.MustParse(templateID)).InOrg(defOrg)},
+		}),
+		[]authTestCase{
+			// Any workspace.
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner("not-me"), actions: []Action{ActionRead}, allow: true},
+			// Only the listed template.
+			{resource: ResourceTemplate.WithID(uuid.MustParse(templateID)).InOrg(defOrg), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceTemplate.WithID(uuid.New()).InOrg(defOrg), actions: []Action{ActionRead}, allow: false},
+			// Types without rules.
+			{resource: ResourceUser.WithID(uuid.New()), actions: []Action{ActionRead}, allow: false},
+		},
+	)
+
+	// The resource ID of a rule only applies to the action of the rule, not to
+	// the other actions allowed on the type.
+	deletableID := uuid.New()
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(RoleMember())),
+		},
+		Scope: CustomScope([]ScopeRule{
+			{ResourceType: ResourceWorkspace.Type, Action: ActionRead},
+			{ResourceType: ResourceWorkspace.Type, Action: ActionDelete, ResourceID: deletableID.String()},
+		}),
+	}
+
+	testAuthorize(t, "CustomScopeMixedRules", user,
+		[]authTestCase{
+			// Any workspace can be read.
+			{resource: ResourceWorkspace.WithID(deletableID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			// Only the listed workspace can be deleted.
+			{resource: ResourceWorkspace.WithID(deletableID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionDelete}, allow: true},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionDelete}, allow: false},
+			// Not even the listed workspace can be updated.
+			{resource: ResourceWorkspace.WithID(deletableID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionCreate, ActionUpdate}, allow: false},
+		},
+	)
 }
 
 // cases applies a given function to all test cases. This makes generalities easier to create.
@@ -75,6 +75,49 @@ var (
 		Type: "audit_log",
 	}
 
+	// ResourceConnectionLog
+	// read = access connection log
+	ResourceConnectionLog = Object{
+		Type: "connection_log",
+	}
+
+	// ResourceSessionRecording is a recording of an interactive session on a
+	// workspace agent. Org owner only.
+	//	read = list and replay recordings
+	ResourceSessionRecording = Object{
+		Type: "session_recording",
+	}
+
+	// ResourceWebhook is an outgoing webhook and its delivery log. Site wide,
+	// owner only.
+	//	create/delete = register or remove a webhook
+	//	read = view webhooks and their deliveries
+	//	update = edit a webhook
+	ResourceWebhook = Object{
+		Type: "webhook",
+	}
+
+	// ResourceOAuth2ProviderApp is an app registered to authenticate users
+	// with Coder as an OAuth2 provider. Site wide.
+	//	create/delete = register or remove an app
+	//	read = view the registered apps
+	//	update = edit an app
+	ResourceOAuth2ProviderApp = Object{
+		Type: "oauth2_app",
+	}
+
+	// ResourceOAuth2ProviderAppSecret is a client secret of an OAuth2 app.
+	// Site wide, owner only.
+	ResourceOAuth2ProviderAppSecret = Object{
+		Type: "oauth2_app_secret",
+	}
+
+	// ResourceOAuth2ProviderAppCodeToken is an authorization code or a token
+	// an OAuth2 app holds for a user. Owned by the user.
+	ResourceOAuth2ProviderAppCodeToken = Object{
+		Type: "oauth2_app_code_token",
+	}
+
 	// ResourceTemplate CRUD. Org owner only.
 	//	create/delete = Make or delete a new template
 	//	update = Update the template, make new template versions
@@ -5,23 +5,29 @@ func AllResources() []Object {
 	return []Object{
 		ResourceAPIKey,
 		ResourceAuditLog,
+		ResourceConnectionLog,
 		ResourceDebugInfo,
 		ResourceDeploymentStats,
 		ResourceDeploymentValues,
 		ResourceFile,
 		ResourceGroup,
 		ResourceLicense,
+		ResourceOAuth2ProviderApp,
+		ResourceOAuth2ProviderAppCodeToken,
+		ResourceOAuth2ProviderAppSecret,
 		ResourceOrgRoleAssignment,
 		ResourceOrganization,
 		ResourceOrganizationMember,
 		ResourceProvisionerDaemon,
 		ResourceReplicas,
 		ResourceRoleAssignment,
+		ResourceSessionRecording,
 		ResourceSystem,
 		ResourceTailnetCoordinator,
 		ResourceTemplate,
 		ResourceUser,
 		ResourceUserData,
+		ResourceWebhook,
 		ResourceWildcard,
 		ResourceWorkspace,
 		ResourceWorkspaceApplicationConnect,
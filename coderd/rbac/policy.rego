@@ -161,6 +161,32 @@ scope_allow_list {
 	input.object.id in input.subject.scope.allow_list
 }
 
+# scope_action_ids are the resource IDs of the "<type>:<action>:<id>" entries
+# in the allow_list that match the object type and action. The entries tie an
+# ID to an action, where a plain ID allows every action of the scope. Only known
+# fields are used, so the comprehension is fine for partial compilations.
+scope_action_ids := [ id |
+	entry := input.subject.scope.allow_list[_]
+	parts := split(entry, ":")
+	count(parts) == 3
+	parts[0] == input.object.type
+	parts[1] in [input.action, "*"]
+	id := parts[2]
+]
+
+scope_allow_list {
+	# A "<type>:<action>:*" entry allows the action on all resources of the
+	# type, so the object.id is not needed either.
+	not "*" in input.subject.scope.allow_list
+	"*" in scope_action_ids
+}
+
+scope_allow_list {
+	not "*" in input.subject.scope.allow_list
+	not "*" in scope_action_ids
+	input.object.id in scope_action_ids
+}
+
 # The allow block is quite simple. Any set with `-1` cascades down in levels.
 # Authorization looks for any `allow` statement that is true. Multiple can be true!
 # Note that the absence of `allow` means "unauthorized".
@@ -19,6 +19,12 @@ const (
 
 	orgAdmin  string = "organization-admin"
 	orgMember string = "organization-member"
+
+	// customSiteRole and customOrganizationRole stand in for all custom roles
+	// in assignRoles. Custom roles are stored in the database, so they cannot
+	// be listed there by name.
+	customSiteRole         string = "custom-site-role"
+	customOrganizationRole string = "custom-organization-role"
 )
 
 func init() {
@@ -148,6 +154,8 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 			ResourceRoleAssignment.Type: {ActionRead},
 			// All users can see the provisioner daemons.
 			ResourceProvisionerDaemon.Type: {ActionRead},
+			// All users can see the OAuth2 apps they may authorize.
+			ResourceOAuth2ProviderApp.Type: {ActionRead},
 		}),
 		Org: map[string][]Permission{},
 		User: append(allPermsExcept(ResourceWorkspaceDormant, ResourceUser, ResourceOrganizationMember),
@@ -165,10 +173,12 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 		Site: Permissions(map[string][]Action{
 			// Should be able to read all template details, even in orgs they
 			// are not in.
-			ResourceTemplate.Type: {ActionRead},
-			ResourceAuditLog.Type: {ActionRead},
-			ResourceUser.Type:     {ActionRead},
-			ResourceGroup.Type:    {ActionRead},
+			ResourceTemplate.Type:         {ActionRead},
+			ResourceAuditLog.Type:         {ActionRead},
+			ResourceConnectionLog.Type:    {ActionRead},
+			ResourceSessionRecording.Type: {ActionRead},
+			ResourceUser.Type:             {ActionRead},
+			ResourceGroup.Type:            {ActionRead},
 			// Org roles are not really used yet, so grant the perm at the site level.
 			ResourceOrganizationMember.Type: {ActionRead},
 		}),
@@ -291,30 +301,35 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 //	map[actor_role][assign_role]<can_assign>
 var assignRoles = map[string]map[string]bool{
 	"system": {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:                  true,
+		auditor:                true,
+		member:                 true,
+		orgAdmin:               true,
+		orgMember:              true,
+		templateAdmin:          true,
+		userAdmin:              true,
+		customSiteRole:         true,
+		customOrganizationRole: true,
 	},
 	owner: {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:                  true,
+		auditor:                true,
+		member:                 true,
+		orgAdmin:               true,
+		orgMember:              true,
+		templateAdmin:          true,
+		userAdmin:              true,
+		customSiteRole:         true,
+		customOrganizationRole: true,
 	},
 	userAdmin: {
 		member:    true,
 		orgMember: true,
 	},
 	orgAdmin: {
-		orgAdmin:  true,
-		orgMember: true,
+		orgAdmin:               true,
+		orgMember:              true,
+		customOrganizationRole: true,
 	},
 }
 
@@ -374,7 +389,7 @@ func (roles Roles) Expand() ([]Role, error) {
 func (roles Roles) Names() []string {
 	names := make([]string, 0, len(roles))
 	for _, r := range roles {
-		return append(names, r.Name)
+		names = append(names, r.Name)
 	}
 	return names
 }
@@ -390,6 +405,14 @@ func CanAssignRole(expandable ExpandableRoles, assignedRole string) bool {
 	if err != nil {
 		return false
 	}
+	if _, ok := builtInRoles[assigned]; !ok {
+		// Any name that is not built in refers to a custom role. Whether it
+		// exists is up to the caller to check.
+		assigned = customSiteRole
+		if assignedOrg != "" {
+			assigned = customOrganizationRole
+		}
+	}
 
 	for _, longRole := range roles {
 		role, orgID, err := roleSplit(longRole)
@@ -442,6 +465,36 @@ func RoleByName(name string) (Role, error) {
 	return role, nil
 }
 
+// IsBuiltInRole returns true if the role name, with or without an
+// organization, is one of the roles defined in this package. Custom roles
+// cannot reuse these names.
+func IsBuiltInRole(name string) bool {
+	roleName, _, err := roleSplit(name)
+	if err != nil {
+		return false
+	}
+	_, ok := builtInRoles[roleName]
+	return ok
+}
+
+// CustomRole builds the role for a custom role stored in the database. Custom
+// organization roles only grant their organization permissions in the
+// organization they belong to, and are named like the built-in ones.
+func CustomRole(name, displayName string, organizationID uuid.UUID, site, org, user []Permission) Role {
+	role := Role{
+		Name:        name,
+		DisplayName: displayName,
+		Site:        site,
+		Org:         map[string][]Permission{},
+		User:        user,
+	}
+	if organizationID != uuid.Nil {
+		role.Name = roleName(name, organizationID.String())
+		role.Org[organizationID.String()] = org
+	}
+	return role
+}
+
 func rolesByNames(roleNames []string) ([]Role, error) {
 	roles := make([]Role, 0, len(roleNames))
 	for _, n := range roleNames {
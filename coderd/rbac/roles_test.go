@@ -336,6 +336,69 @@ func TestRolePermissions(t *testing.T) {
 				false: {userAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, memberMe},
 			},
 		},
+		{
+			Name:     "SessionRecording",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceSessionRecording.WithID(uuid.New()).InOrg(orgID),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin},
+				false: {memberMe, orgMemberMe, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "ConnectionLog",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceConnectionLog,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "Webhook",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceWebhook.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "OAuth2AppRead",
+			Actions:  []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceOAuth2ProviderApp.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+				false: {},
+			},
+		},
+		{
+			Name:     "OAuth2AppCreateUpdateDelete",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+			Resource: rbac.ResourceOAuth2ProviderApp.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "OAuth2AppSecret",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceOAuth2ProviderAppSecret.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "OAuth2AppCodeToken",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceOAuth2ProviderAppCodeToken.WithID(uuid.New()).WithOwner(currentUser.String()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, memberMe, orgMemberMe},
+				false: {orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
 	}
 
 	for _, c := range testCases {
@@ -510,3 +573,42 @@ func TestChangeSet(t *testing.T) {
 		})
 	}
 }
+
+func TestCanAssignCustomRole(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	otherOrgID := uuid.New()
+	custom := rbac.CustomRole("support", "Support", uuid.Nil, []rbac.Permission{
+		{ResourceType: rbac.ResourceUser.Type, Action: rbac.ActionRead},
+	}, nil, nil)
+	customOrg := rbac.CustomRole("support", "Support", orgID, nil, nil, nil)
+
+	require.False(t, rbac.IsBuiltInRole(custom.Name))
+	require.False(t, rbac.IsBuiltInRole(customOrg.Name))
+	require.True(t, rbac.IsBuiltInRole(rbac.RoleOrgAdmin(orgID)))
+	require.Equal(t, "support:"+orgID.String(), customOrg.Name)
+
+	testCases := []struct {
+		Name     string
+		Actor    rbac.ExpandableRoles
+		Assigned string
+		Expected bool
+	}{
+		{Name: "OwnerSite", Actor: rbac.RoleNames{rbac.RoleOwner()}, Assigned: custom.Name, Expected: true},
+		{Name: "OwnerOrg", Actor: rbac.RoleNames{rbac.RoleOwner()}, Assigned: customOrg.Name, Expected: true},
+		{Name: "UserAdminSite", Actor: rbac.RoleNames{rbac.RoleUserAdmin()}, Assigned: custom.Name, Expected: false},
+		{Name: "OrgAdminSite", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, Assigned: custom.Name, Expected: false},
+		{Name: "OrgAdminOrg", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, Assigned: customOrg.Name, Expected: true},
+		{Name: "OtherOrgAdminOrg", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(otherOrgID)}, Assigned: customOrg.Name, Expected: false},
+		{Name: "CustomRoleActor", Actor: rbac.Roles{custom}, Assigned: custom.Name, Expected: false},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.Expected, rbac.CanAssignRole(c.Actor, c.Assigned))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package rolestore expands role names into roles. Built-in roles are
+// defined by the rbac package, custom roles are stored in the database.
+package rolestore
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// Expand returns the roles of a subject with the given role names. Subjects
+// with only built-in roles keep their role names, which the rbac package
+// expands on its own.
+func Expand(ctx context.Context, db database.Store, names []string) (rbac.ExpandableRoles, error) {
+	if !hasCustomRoles(names) {
+		return rbac.RoleNames(names), nil
+	}
+	roles, err := rolesByNames(ctx, db, names)
+	if err != nil {
+		return nil, err
+	}
+	return rbac.Roles(roles), nil
+}
+
+// Validate returns an error if any of the role names is neither a built-in
+// role nor an existing custom role.
+func Validate(ctx context.Context, db database.Store, names []string) error {
+	_, err := rolesByNames(ctx, db, names)
+	return err
+}
+
+// ConvertDBRole converts a custom role stored in the database into the role
+// passed to the authorizer.
+func ConvertDBRole(dbRole database.CustomRole) (rbac.Role, error) {
+	var site, org, user []rbac.Permission
+	if err := json.Unmarshal(dbRole.SitePermissions, &site); err != nil {
+		return rbac.Role{}, xerrors.Errorf("unmarshal site permissions: %w", err)
+	}
+	if err := json.Unmarshal(dbRole.OrgPermissions, &org); err != nil {
+		return rbac.Role{}, xerrors.Errorf("unmarshal organization permissions: %w", err)
+	}
+	if err := json.Unmarshal(dbRole.UserPermissions, &user); err != nil {
+		return rbac.Role{}, xerrors.Errorf("unmarshal user permissions: %w", err)
+	}
+	return rbac.CustomRole(dbRole.Name, dbRole.DisplayName, dbRole.OrganizationID.UUID, site, org, user), nil
+}
+
+func hasCustomRoles(names []string) bool {
+	for _, name := range names {
+		if !rbac.IsBuiltInRole(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func rolesByNames(ctx context.Context, db database.Store, names []string) ([]rbac.Role, error) {
+	roles := make([]rbac.Role, 0, len(names))
+	var lookup []string
+	for _, name := range names {
+		if !rbac.IsBuiltInRole(name) {
+			lookup = append(lookup, name)
+			continue
+		}
+		role, err := rbac.RoleByName(name)
+		if err != nil {
+			return nil, xerrors.Errorf("%q is not a supported role", name)
+		}
+		roles = append(roles, role)
+	}
+	if len(lookup) == 0 {
+		return roles, nil
+	}
+
+	// Roles are expanded before the actor is known, and the role definitions
+	// are not secret.
+	//nolint:gocritic
+	dbRoles, err := db.GetCustomRolesByName(dbauthz.AsSystemRestricted(ctx), lookup)
+	if err != nil {
+		return nil, xerrors.Errorf("get custom roles: %w", err)
+	}
+	found := make(map[string]rbac.Role, len(dbRoles))
+	for _, dbRole := range dbRoles {
+		role, err := ConvertDBRole(dbRole)
+		if err != nil {
+			return nil, xerrors.Errorf("convert role %q: %w", dbRole.Name, err)
+		}
+		found[role.Name] = role
+	}
+	for _, name := range lookup {
+		role, ok := found[name]
+		if !ok {
+			return nil, xerrors.Errorf("%q is not a supported role", name)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
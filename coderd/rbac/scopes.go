@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 
 	"golang.org/x/xerrors"
 )
@@ -31,9 +32,54 @@ func WorkspaceAgentScope(workspaceID, ownerID uuid.UUID) Scope {
 	}
 }
 
+// ScopeRule allows an action on a resource type. If ResourceID is set, the
+// rule only allows the action on that resource.
+type ScopeRule struct {
+	ResourceType string `json:"resource_type"`
+	Action       Action `json:"action"`
+	ResourceID   string `json:"resource_id,omitempty"`
+}
+
+// CustomScope returns a scope that only allows the actions of the rules. Like
+// any scope, it is intersected with the roles of the subject, so it can never
+// grant more than the roles do.
+func CustomScope(rules []ScopeRule) Scope {
+	perms := map[string][]Action{}
+	// Every rule gets its own "<type>:<action>:<id>" entry, so a resource ID
+	// only allows the action of its rule and not the others of the type.
+	allowList := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if !slices.Contains(perms[rule.ResourceType], rule.Action) {
+			perms[rule.ResourceType] = append(perms[rule.ResourceType], rule.Action)
+		}
+		resourceID := rule.ResourceID
+		if resourceID == "" {
+			resourceID = WildcardSymbol
+		}
+		entry := fmt.Sprintf("%s:%s:%s", rule.ResourceType, rule.Action, resourceID)
+		if !slices.Contains(allowList, entry) {
+			allowList = append(allowList, entry)
+		}
+	}
+
+	return Scope{
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", ScopeCustom),
+			DisplayName: "Custom",
+			Site:        Permissions(perms),
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		},
+		AllowIDList: allowList,
+	}
+}
+
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
+	// ScopeCustom is the name of scopes built from rules with CustomScope. It
+	// cannot be expanded by name.
+	ScopeCustom ScopeName = "custom"
 )
 
 // TODO: Support passing in scopeID list for allowlisting resources.
@@ -89,6 +135,8 @@ func (name ScopeName) Name() string {
 // reject any resource that is not in the AllowIDList.
 // To not use an AllowIDList to reject authorization, use a wildcard for the
 // AllowIDList. Eg: 'AllowIDList: []string{WildcardSymbol}'
+// An "<type>:<action>:<id>" entry only allows the action on the resource, and
+// "<type>:<action>:*" the action on all resources of the type.
 type Scope struct {
 	Role
 	AllowIDList []string `json:"allow_list"`
@@ -1,13 +1,26 @@
 package coderd
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
 
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 )
 
 // assignableSiteRoles returns all site wide roles that can be assigned.
@@ -28,6 +41,11 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.SiteRoles()
+	custom, ok := api.assignableCustomRoles(rw, r, uuid.NullUUID{})
+	if !ok {
+		return
+	}
+	roles = append(roles, custom...)
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
@@ -52,9 +70,39 @@ func (api *API) assignableOrgRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.OrganizationRoles(organization.ID)
+	custom, ok := api.assignableCustomRoles(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+	if !ok {
+		return
+	}
+	roles = append(roles, custom...)
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
+// assignableCustomRoles returns the custom roles of the site, or of an
+// organization if organizationID is set.
+func (api *API) assignableCustomRoles(rw http.ResponseWriter, r *http.Request, organizationID uuid.NullUUID) ([]rbac.Role, bool) {
+	ctx := r.Context()
+	dbroles, err := api.Database.GetCustomRoles(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return nil, false
+	}
+
+	roles := make([]rbac.Role, 0, len(dbroles))
+	for _, dbrole := range dbroles {
+		if dbrole.OrganizationID != organizationID {
+			continue
+		}
+		role, err := rolestore.ConvertDBRole(dbrole)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return nil, false
+		}
+		roles = append(roles, role)
+	}
+	return roles, true
+}
+
 func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []codersdk.AssignableRoles {
 	assignable := make([]codersdk.AssignableRoles, 0)
 	for _, role := range roles {
@@ -71,3 +119,346 @@ func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []coder
 	}
 	return assignable
 }
+
+// @Summary Get custom roles
+// @ID get-custom-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Success 200 {array} codersdk.CustomRole
+// @Router /roles [get]
+func (api *API) customRoles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	dbroles, err := api.Database.GetCustomRoles(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	roles := make([]codersdk.CustomRole, 0, len(dbroles))
+	for _, dbrole := range dbroles {
+		role, err := convertCustomRole(dbrole)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		roles = append(roles, role)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, roles)
+}
+
+// @Summary Get custom role by ID
+// @ID get-custom-role-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param customrole path string true "Custom role ID" format(uuid)
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{customrole} [get]
+func (api *API) customRole(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	dbrole := httpmw.CustomRoleParam(r)
+
+	role, err := convertCustomRole(dbrole)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, role)
+}
+
+// @Summary Create custom role
+// @ID create-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /roles [post]
+func (api *API) postCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if rbac.IsBuiltInRole(req.Name) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is the name of a built-in role.", req.Name),
+		})
+		return
+	}
+
+	organizationID := uuid.NullUUID{}
+	if req.OrganizationID != nil {
+		_, err := api.Database.GetOrganizationByID(ctx, *req.OrganizationID)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Organization %q does not exist.", req.OrganizationID.String()),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		organizationID = uuid.NullUUID{UUID: *req.OrganizationID, Valid: true}
+	}
+	if !validateCustomRolePermissions(ctx, rw, organizationID.Valid, req.SitePermissions, req.OrganizationPermissions, req.UserPermissions) {
+		return
+	}
+
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = req.Name
+	}
+	now := dbtime.Now()
+	dbrole, err := api.Database.InsertCustomRole(ctx, database.InsertCustomRoleParams{
+		ID:              uuid.New(),
+		Name:            req.Name,
+		DisplayName:     displayName,
+		OrganizationID:  organizationID,
+		SitePermissions: convertPermissions(req.SitePermissions),
+		OrgPermissions:  convertPermissions(req.OrganizationPermissions),
+		UserPermissions: convertPermissions(req.UserPermissions),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Role with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to create this role.",
+			Detail:  "Roles can only grant permissions you have yourself.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = dbrole
+
+	role, err := convertCustomRole(dbrole)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, role)
+}
+
+// @Summary Update custom role
+// @ID update-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param customrole path string true "Custom role ID" format(uuid)
+// @Param request body codersdk.UpdateCustomRoleRequest true "Update custom role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{customrole} [patch]
+func (api *API) patchCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		dbrole            = httpmw.CustomRoleParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = dbrole
+
+	var req codersdk.UpdateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	current, err := convertCustomRole(dbrole)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	params := database.UpdateCustomRoleByIDParams{
+		ID:          dbrole.ID,
+		DisplayName: dbrole.DisplayName,
+		UpdatedAt:   dbtime.Now(),
+	}
+	if req.DisplayName != nil && *req.DisplayName != "" {
+		params.DisplayName = *req.DisplayName
+	}
+	if req.SitePermissions != nil {
+		current.SitePermissions = req.SitePermissions
+	}
+	if req.OrganizationPermissions != nil {
+		current.OrganizationPermissions = req.OrganizationPermissions
+	}
+	if req.UserPermissions != nil {
+		current.UserPermissions = req.UserPermissions
+	}
+	if !validateCustomRolePermissions(ctx, rw, dbrole.OrganizationID.Valid, current.SitePermissions, current.OrganizationPermissions, current.UserPermissions) {
+		return
+	}
+	params.SitePermissions = convertPermissions(current.SitePermissions)
+	params.OrgPermissions = convertPermissions(current.OrganizationPermissions)
+	params.UserPermissions = convertPermissions(current.UserPermissions)
+
+	updated, err := api.Database.UpdateCustomRoleByID(ctx, params)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to update this role.",
+			Detail:  "Roles can only grant permissions you have yourself.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = updated
+
+	role, err := convertCustomRole(updated)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, role)
+}
+
+// @Summary Delete custom role
+// @ID delete-custom-role
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param customrole path string true "Custom role ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /roles/{customrole} [delete]
+func (api *API) deleteCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		dbrole            = httpmw.CustomRoleParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = dbrole
+
+	err := api.Database.DeleteCustomRoleByID(ctx, dbrole.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Role has been deleted!",
+	})
+}
+
+// validateCustomRolePermissions writes an error response if the permissions
+// of a custom role are invalid.
+func validateCustomRolePermissions(ctx context.Context, rw http.ResponseWriter, organizationRole bool, site, org, user []codersdk.Permission) bool {
+	if organizationRole && len(site) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Organization roles cannot have site permissions.",
+		})
+		return false
+	}
+	if !organizationRole && len(org) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Site roles cannot have organization permissions.",
+		})
+		return false
+	}
+
+	for _, perms := range [][]codersdk.Permission{site, org, user} {
+		for _, perm := range perms {
+			if !slices.Contains(codersdk.AllRBACResources, perm.ResourceType) {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: fmt.Sprintf("Invalid resource type %q.", perm.ResourceType),
+				})
+				return false
+			}
+			if !slices.Contains(codersdk.AllRBACActions, perm.Action) {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: fmt.Sprintf("Invalid action %q.", perm.Action),
+					Detail:  fmt.Sprintf("Actions must be one of %q.", codersdk.AllRBACActions),
+				})
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func convertPermissions(perms []codersdk.Permission) json.RawMessage {
+	converted := make([]rbac.Permission, 0, len(perms))
+	for _, perm := range perms {
+		converted = append(converted, rbac.Permission{
+			Negate:       perm.Negate,
+			ResourceType: string(perm.ResourceType),
+			Action:       rbac.Action(perm.Action),
+		})
+	}
+	// Marshaling a slice of plain structs cannot fail.
+	raw, _ := json.Marshal(converted)
+	return raw
+}
+
+func convertCustomRole(dbrole database.CustomRole) (codersdk.CustomRole, error) {
+	role := codersdk.CustomRole{
+		ID:          dbrole.ID,
+		Name:        dbrole.Name,
+		DisplayName: dbrole.DisplayName,
+		CreatedAt:   dbrole.CreatedAt,
+		UpdatedAt:   dbrole.UpdatedAt,
+	}
+	if dbrole.OrganizationID.Valid {
+		role.OrganizationID = &dbrole.OrganizationID.UUID
+	}
+	for _, field := range []struct {
+		raw  json.RawMessage
+		dest *[]codersdk.Permission
+	}{
+		{dbrole.SitePermissions, &role.SitePermissions},
+		{dbrole.OrgPermissions, &role.OrganizationPermissions},
+		{dbrole.UserPermissions, &role.UserPermissions},
+	} {
+		*field.dest = []codersdk.Permission{}
+		if err := json.Unmarshal(field.raw, field.dest); err != nil {
+			return codersdk.CustomRole{}, xerrors.Errorf("unmarshal permissions: %w", err)
+		}
+	}
+	return role, nil
+}
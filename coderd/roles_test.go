@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -143,6 +144,205 @@ func TestListRoles(t *testing.T) {
 	}
 }
 
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name: "support",
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceUser, Action: codersdk.ActionRead},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "support", role.DisplayName)
+		require.Nil(t, role.OrganizationID)
+		require.Len(t, role.SitePermissions, 1)
+
+		// Site and organization roles are listed together.
+		orgRole, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:           "template-editor",
+			OrganizationID: &owner.OrganizationID,
+		})
+		require.NoError(t, err)
+
+		roles, err := client.CustomRoles(ctx)
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+		require.ElementsMatch(t, []uuid.UUID{role.ID, orgRole.ID}, []uuid.UUID{roles[0].ID, roles[1].ID})
+
+		displayName := "Support"
+		updated, err := client.UpdateCustomRole(ctx, role.ID, codersdk.UpdateCustomRoleRequest{
+			DisplayName: &displayName,
+		})
+		require.NoError(t, err)
+		require.Equal(t, displayName, updated.DisplayName)
+		require.Equal(t, role.SitePermissions, updated.SitePermissions)
+
+		fetched, err := client.CustomRole(ctx, role.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, fetched)
+
+		err = client.DeleteCustomRole(ctx, role.ID)
+		require.NoError(t, err)
+
+		_, err = client.CustomRole(ctx, role.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, req := range []codersdk.CreateCustomRoleRequest{
+			{Name: "owner"},
+			{Name: "bad", SitePermissions: []codersdk.Permission{{ResourceType: "unknown", Action: codersdk.ActionRead}}},
+			{Name: "bad", SitePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceUser, Action: "unknown"}}},
+			{Name: "bad", OrganizationPermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionRead}}},
+			{Name: "bad", OrganizationID: &owner.OrganizationID, SitePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionRead}}},
+		} {
+			_, err := client.CreateCustomRole(ctx, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+
+		_, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "support"})
+		require.NoError(t, err)
+		_, err = client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "support"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("AssignSiteRole", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.DeploymentConfig(ctx)
+		require.Error(t, err)
+
+		role, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:        "config-reader",
+			DisplayName: "Config Reader",
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceDeploymentValues, Action: codersdk.ActionRead},
+			},
+		})
+		require.NoError(t, err)
+
+		roles, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, roles, codersdk.AssignableRoles{
+			Role:       codersdk.Role{Name: role.Name, DisplayName: role.DisplayName},
+			Assignable: true,
+		})
+
+		updated, err := client.UpdateUserRoles(ctx, user.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.AssignedName()},
+		})
+		require.NoError(t, err)
+		require.Contains(t, updated.Roles, codersdk.Role{Name: role.Name, DisplayName: role.Name})
+
+		_, err = member.DeploymentConfig(ctx)
+		require.NoError(t, err)
+
+		// Deleting the role removes it from the user.
+		err = client.DeleteCustomRole(ctx, role.ID)
+		require.NoError(t, err)
+		updatedUser, err := client.User(ctx, user.ID.String())
+		require.NoError(t, err)
+		require.Empty(t, updatedUser.Roles)
+		_, err = member.DeploymentConfig(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("AssignOrganizationRole", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:           "template-editor",
+			OrganizationID: &owner.OrganizationID,
+			OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: codersdk.ActionUpdate},
+			},
+		})
+		require.NoError(t, err)
+
+		roles, err := client.ListOrganizationRoles(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Contains(t, roles, codersdk.AssignableRoles{
+			Role:       codersdk.Role{Name: role.AssignedName(), DisplayName: role.DisplayName},
+			Assignable: true,
+		})
+
+		member, err := client.UpdateOrganizationMemberRoles(ctx, owner.OrganizationID, user.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.AssignedName()},
+		})
+		require.NoError(t, err)
+		require.Len(t, member.Roles, 1)
+		require.Equal(t, role.AssignedName(), member.Roles[0].Name)
+
+		// Site roles cannot be assigned as organization roles.
+		_, err = client.UpdateOrganizationMemberRoles(ctx, owner.OrganizationID, user.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.Name},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Escalation", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := userAdmin.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name: "user-reader",
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceUser, Action: codersdk.ActionRead},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = userAdmin.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name: "config-reader",
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceDeploymentValues, Action: codersdk.ActionRead},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
 func convertRole(roleName string) codersdk.Role {
 	role, _ := rbac.RoleByName(roleName)
 	return codersdk.Role{
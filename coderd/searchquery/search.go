@@ -1,6 +1,7 @@
 package searchquery
 
 import (
+	"database/sql"
 	"fmt"
 	"net/url"
 	"strings"
@@ -47,6 +48,35 @@ func AuditLogs(query string) (database.GetAuditLogsOffsetParams, []codersdk.Vali
 	return filter, parser.Errors
 }
 
+func ConnectionLogs(query string) (database.GetConnectionLogsOffsetParams, []codersdk.ValidationError) {
+	// Always lowercase for all searches.
+	query = strings.ToLower(query)
+	values, errors := searchTerms(query, func(term string, values url.Values) error {
+		values.Add("workspace", term)
+		return nil
+	})
+	if len(errors) > 0 {
+		return database.GetConnectionLogsOffsetParams{}, errors
+	}
+
+	const dateLayout = "2006-01-02"
+	parser := httpapi.NewQueryParamParser()
+	filter := database.GetConnectionLogsOffsetParams{
+		Type:           string(httpapi.ParseCustom(parser, values, "", "type", httpapi.ParseEnum[database.ConnectionType])),
+		WorkspaceOwner: parser.String(values, "", "owner"),
+		WorkspaceID:    parser.UUID(values, uuid.Nil, "workspace_id"),
+		WorkspaceName:  parser.String(values, "", "workspace"),
+		Username:       parser.String(values, "", "username"),
+		DateFrom:       parser.Time(values, time.Time{}, "date_from", dateLayout),
+		DateTo:         parser.Time(values, time.Time{}, "date_to", dateLayout),
+	}
+	if !filter.DateTo.IsZero() {
+		filter.DateTo = filter.DateTo.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	}
+	parser.ErrorExcessParams(values)
+	return filter, parser.Errors
+}
+
 func Users(query string) (database.GetUsersParams, []codersdk.ValidationError) {
 	// Always lowercase for all searches.
 	query = strings.ToLower(query)
@@ -70,6 +100,26 @@ func Users(query string) (database.GetUsersParams, []codersdk.ValidationError) {
 	return filter, parser.Errors
 }
 
+func Templates(query string) (database.GetTemplatesWithFilterParams, []codersdk.ValidationError) {
+	// Always lowercase for all searches.
+	query = strings.ToLower(query)
+	values, errors := searchTerms(query, func(term string, values url.Values) error {
+		values.Add("name", term)
+		return nil
+	})
+	if len(errors) > 0 {
+		return database.GetTemplatesWithFilterParams{}, errors
+	}
+
+	parser := httpapi.NewQueryParamParser()
+	filter := database.GetTemplatesWithFilterParams{
+		FuzzyName:  parser.String(values, "", "name"),
+		Deprecated: parser.NullableBoolean(values, sql.NullBool{}, "deprecated"),
+	}
+	parser.ErrorExcessParams(values)
+	return filter, parser.Errors
+}
+
 type PostFilter struct {
 	DeletingBy *time.Time `json:"deleting_by" format:"date-time"`
 }
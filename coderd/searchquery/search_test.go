@@ -1,6 +1,7 @@
 package searchquery_test
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 	"testing"
@@ -275,6 +276,74 @@ func TestSearchAudit(t *testing.T) {
 	}
 }
 
+func TestSearchConnectionLogs(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		Name                  string
+		Query                 string
+		Expected              database.GetConnectionLogsOffsetParams
+		ExpectedErrorContains string
+	}{
+		{
+			Name:     "Empty",
+			Query:    "",
+			Expected: database.GetConnectionLogsOffsetParams{},
+		},
+		{
+			Name:  "Workspace",
+			Query: "My-Workspace",
+			Expected: database.GetConnectionLogsOffsetParams{
+				WorkspaceName: "my-workspace",
+			},
+		},
+		{
+			Name:  "OwnerAndType",
+			Query: "owner:alice type:ssh",
+			Expected: database.GetConnectionLogsOffsetParams{
+				WorkspaceOwner: "alice",
+				Type:           string(database.ConnectionTypeSSH),
+			},
+		},
+		{
+			Name:  "DateTo",
+			Query: "date_to:2023-09-20",
+			Expected: database.GetConnectionLogsOffsetParams{
+				DateTo: time.Date(2023, 9, 20, 23, 59, 59, 0, time.UTC),
+			},
+		},
+		// Failures
+		{
+			Name:                  "InvalidType",
+			Query:                 "type:telnet",
+			ExpectedErrorContains: "not a valid value",
+		},
+		{
+			Name:                  "ExtraKeys",
+			Query:                 `foo:bar`,
+			ExpectedErrorContains: `"foo" is not a valid query param`,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			values, errs := searchquery.ConnectionLogs(c.Query)
+			if c.ExpectedErrorContains != "" {
+				require.True(t, len(errs) > 0, "expect some errors")
+				var s strings.Builder
+				for _, err := range errs {
+					_, _ = s.WriteString(fmt.Sprintf("%s: %s\n", err.Field, err.Detail))
+				}
+				require.Contains(t, s.String(), c.ExpectedErrorContains)
+			} else {
+				require.Len(t, errs, 0, "expected no error")
+				require.Equal(t, c.Expected, values, "expected values")
+			}
+		})
+	}
+}
+
 func TestSearchUsers(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -393,3 +462,64 @@ func TestSearchUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchTemplates(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		Name                  string
+		Query                 string
+		Expected              database.GetTemplatesWithFilterParams
+		ExpectedErrorContains string
+	}{
+		{
+			Name:     "Empty",
+			Query:    "",
+			Expected: database.GetTemplatesWithFilterParams{},
+		},
+		{
+			Name:  "Name",
+			Query: "Docker",
+			Expected: database.GetTemplatesWithFilterParams{
+				FuzzyName: "docker",
+			},
+		},
+		{
+			Name:  "Deprecated",
+			Query: "name:docker deprecated:false",
+			Expected: database.GetTemplatesWithFilterParams{
+				FuzzyName:  "docker",
+				Deprecated: sql.NullBool{Bool: false, Valid: true},
+			},
+		},
+		// Failures
+		{
+			Name:                  "InvalidDeprecated",
+			Query:                 "deprecated:maybe",
+			ExpectedErrorContains: "must be a valid boolean",
+		},
+		{
+			Name:                  "ExtraKeys",
+			Query:                 `foo:bar`,
+			ExpectedErrorContains: `"foo" is not a valid query param`,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			values, errs := searchquery.Templates(c.Query)
+			if c.ExpectedErrorContains != "" {
+				require.True(t, len(errs) > 0, "expect some errors")
+				var s strings.Builder
+				for _, err := range errs {
+					_, _ = s.WriteString(fmt.Sprintf("%s: %s\n", err.Field, err.Detail))
+				}
+				require.Contains(t, s.String(), c.ExpectedErrorContains)
+			} else {
+				require.Len(t, errs, 0, "expected no error")
+				require.Equal(t, c.Expected, values, "expected values")
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -20,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
@@ -396,6 +398,7 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 // @Produce json
 // @Tags Templates
 // @Param organization path string true "Organization ID" format(uuid)
+// @Param q query string false "Search query"
 // @Success 200 {array} codersdk.Template
 // @Router /organizations/{organization}/templates [get]
 func (api *API) templatesByOrganization(rw http.ResponseWriter, r *http.Request) {
@@ -411,10 +414,18 @@ func (api *API) templatesByOrganization(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	filter, errs := searchquery.Templates(r.URL.Query().Get("q"))
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid template search query.",
+			Validations: errs,
+		})
+		return
+	}
+	filter.OrganizationID = organization.ID
+
 	// Filter templates based on rbac permissions
-	templates, err := api.Database.GetAuthorizedTemplates(ctx, database.GetTemplatesWithFilterParams{
-		OrganizationID: organization.ID,
-	}, prepared)
+	templates, err := api.Database.GetAuthorizedTemplates(ctx, filter, prepared)
 	if errors.Is(err, sql.ErrNoRows) {
 		err = nil
 	}
@@ -549,6 +560,34 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.TimeTilDormantAutoDeleteMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "locked_ttl_ms", Detail: "Must be a positive integer."})
 	}
+	maxPortShareLevel := template.MaxPortSharingLevel
+	if req.MaxPortShareLevel != nil {
+		if !req.MaxPortShareLevel.ValidMaxLevel() {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_port_share_level", Detail: fmt.Sprintf("Must be one of %q, %q or %q.", codersdk.WorkspaceAgentPortShareLevelOwner, codersdk.WorkspaceAgentPortShareLevelAuthenticated, codersdk.WorkspaceAgentPortShareLevelPublic)})
+		}
+		maxPortShareLevel = database.AppSharingLevel(*req.MaxPortShareLevel)
+	}
+	recordSessions := template.RecordSessions
+	if req.RecordSessions != nil {
+		recordSessions = *req.RecordSessions
+	}
+	requireActiveVersion := template.RequireActiveVersion
+	if req.RequireActiveVersion != nil {
+		requireActiveVersion = *req.RequireActiveVersion
+	}
+	deprecationMessage := template.DeprecationMessage
+	if req.DeprecationMessage != nil {
+		deprecationMessage = strings.TrimSpace(*req.DeprecationMessage)
+	}
+	// Keep when the template was first deprecated if only the message
+	// changes.
+	deprecatedAt := template.DeprecatedAt
+	switch {
+	case deprecationMessage == "":
+		deprecatedAt = sql.NullTime{}
+	case !deprecatedAt.Valid:
+		deprecatedAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -573,7 +612,11 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.AutostopRequirement.Weeks == scheduleOpts.AutostopRequirement.Weeks &&
 			req.FailureTTLMillis == time.Duration(template.FailureTTL).Milliseconds() &&
 			req.TimeTilDormantMillis == time.Duration(template.TimeTilDormant).Milliseconds() &&
-			req.TimeTilDormantAutoDeleteMillis == time.Duration(template.TimeTilDormantAutoDelete).Milliseconds() {
+			req.TimeTilDormantAutoDeleteMillis == time.Duration(template.TimeTilDormantAutoDelete).Milliseconds() &&
+			maxPortShareLevel == template.MaxPortSharingLevel &&
+			recordSessions == template.RecordSessions &&
+			requireActiveVersion == template.RequireActiveVersion &&
+			deprecationMessage == template.DeprecationMessage {
 			return nil
 		}
 
@@ -592,11 +635,32 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			Description:                  req.Description,
 			Icon:                         req.Icon,
 			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
+			MaxPortSharingLevel:          maxPortShareLevel,
+			RecordSessions:               recordSessions,
+			DeprecationMessage:           deprecationMessage,
+			DeprecatedAt:                 deprecatedAt,
+			RequireActiveVersion:         requireActiveVersion,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
 		}
 
+		// Ports that are already shared must not stay more accessible than
+		// the new maximum allows.
+		switch {
+		case maxPortShareLevel == template.MaxPortSharingLevel:
+		case maxPortShareLevel == database.AppSharingLevelOwner:
+			err = tx.DeleteWorkspaceAgentPortSharesByTemplate(ctx, template.ID)
+			if err != nil {
+				return xerrors.Errorf("delete workspace agent port shares: %w", err)
+			}
+		case maxPortShareLevel == database.AppSharingLevelAuthenticated:
+			err = tx.ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx, template.ID)
+			if err != nil {
+				return xerrors.Errorf("reduce workspace agent port share levels: %w", err)
+			}
+		}
+
 		updated, err = tx.GetTemplateByID(ctx, template.ID)
 		if err != nil {
 			return xerrors.Errorf("fetch updated template metadata: %w", err)
@@ -749,6 +813,11 @@ func (api *API) convertTemplate(
 		autostopRequirementWeeks = 1
 	}
 
+	var deprecatedAt *time.Time
+	if template.DeprecatedAt.Valid {
+		deprecatedAt = &template.DeprecatedAt.Time
+	}
+
 	return codersdk.Template{
 		ID:                             template.ID,
 		CreatedAt:                      template.CreatedAt,
@@ -776,5 +845,11 @@ func (api *API) convertTemplate(
 			DaysOfWeek: codersdk.BitmapToWeekdays(uint8(template.AutostopRequirementDaysOfWeek)),
 			Weeks:      autostopRequirementWeeks,
 		},
+		MaxPortShareLevel:    codersdk.WorkspaceAgentPortShareLevel(template.MaxPortSharingLevel),
+		RecordSessions:       template.RecordSessions,
+		Deprecated:           template.DeprecationMessage != "",
+		DeprecationMessage:   template.DeprecationMessage,
+		DeprecatedAt:         deprecatedAt,
+		RequireActiveVersion: template.RequireActiveVersion,
 	}
 }
@@ -431,6 +431,56 @@ func TestTemplatesByOrganization(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, templates, 2)
 	})
+
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		docker := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.Name = "docker"
+		})
+		version2 := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		kubernetes := coderdtest.CreateTemplate(t, client, user.OrganizationID, version2.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.Name = "kubernetes"
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpdateTemplateMeta(ctx, docker.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Use kubernetes instead."),
+		})
+		require.NoError(t, err)
+
+		templates, err := client.TemplatesByOrganizationWithFilter(ctx, user.OrganizationID, codersdk.TemplateFilter{
+			SearchQuery: "deprecated:true",
+		})
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, docker.ID, templates[0].ID)
+
+		templates, err = client.TemplatesByOrganizationWithFilter(ctx, user.OrganizationID, codersdk.TemplateFilter{
+			SearchQuery: "deprecated:false",
+		})
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, kubernetes.ID, templates[0].ID)
+
+		templates, err = client.TemplatesByOrganizationWithFilter(ctx, user.OrganizationID, codersdk.TemplateFilter{
+			SearchQuery: "kube",
+		})
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, kubernetes.ID, templates[0].ID)
+
+		_, err = client.TemplatesByOrganizationWithFilter(ctx, user.OrganizationID, codersdk.TemplateFilter{
+			SearchQuery: "deprecated:maybe",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
 }
 
 func TestTemplateByOrganizationAndName(t *testing.T) {
@@ -965,6 +1015,52 @@ func TestPatchTemplateMeta(t *testing.T) {
 		assert.Equal(t, updated.Icon, "")
 	})
 
+	t.Run("Deprecated", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.False(t, template.Deprecated)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Use the kubernetes template instead."),
+		})
+		require.NoError(t, err)
+		require.True(t, updated.Deprecated)
+		require.Equal(t, "Use the kubernetes template instead.", updated.DeprecationMessage)
+		require.NotNil(t, updated.DeprecatedAt)
+		deprecatedAt := *updated.DeprecatedAt
+
+		// Changing the message keeps when the template was deprecated.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Use the docker template instead."),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Use the docker template instead.", updated.DeprecationMessage)
+		require.NotNil(t, updated.DeprecatedAt)
+		require.True(t, deprecatedAt.Equal(*updated.DeprecatedAt))
+
+		// The template stays deprecated if the message isn't set.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "deprecated",
+		})
+		require.NoError(t, err)
+		require.True(t, updated.Deprecated)
+
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref(""),
+		})
+		require.NoError(t, err)
+		require.False(t, updated.Deprecated)
+		require.Empty(t, updated.DeprecationMessage)
+		require.Nil(t, updated.DeprecatedAt)
+	})
+
 	t.Run("AutostopRequirement", func(t *testing.T) {
 		t.Parallel()
 
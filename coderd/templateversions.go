@@ -28,6 +28,7 @@ import (
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
 	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
@@ -272,6 +273,47 @@ func (api *API) templateVersionRichParameters(rw http.ResponseWriter, r *http.Re
 	httpapi.Write(ctx, rw, http.StatusOK, templateVersionParameters)
 }
 
+// @Summary Get presets by template version
+// @ID get-presets-by-template-version
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateVersionPreset
+// @Router /templateversions/{templateversion}/presets [get]
+func (api *API) templateVersionPresets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	dbPresets, err := api.Database.GetTemplateVersionPresets(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version presets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	presets := make([]codersdk.TemplateVersionPreset, 0, len(dbPresets))
+	for _, dbPreset := range dbPresets {
+		var parameters []codersdk.WorkspaceBuildParameter
+		err = json.Unmarshal(dbPreset.Parameters, &parameters)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting template version preset.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		presets = append(presets, codersdk.TemplateVersionPreset{
+			ID:         dbPreset.ID,
+			Name:       dbPreset.Name,
+			Parameters: parameters,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, presets)
+}
+
 // @Summary Get git auth by template version
 // @ID get-git-auth-by-template-version
 // @Security CoderSessionToken
@@ -1057,6 +1099,11 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 
 	api.publishTemplateUpdate(ctx, template.ID)
 
+	err = webhooks.Enqueue(ctx, api.Database, api.Pubsub, webhooks.TemplateVersionPayload(codersdk.WebhookEventTemplateVersionPromoted, template, version))
+	if err != nil {
+		api.Logger.Warn(ctx, "enqueue webhook event", slog.F("event", codersdk.WebhookEventTemplateVersionPromoted), slog.Error(err))
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Updated the active template version!",
 	})
@@ -1467,3 +1467,101 @@ func TestTemplateVersionParameters_Order(t *testing.T) {
 	require.Equal(t, secondParameterName, templateRichParameters[3].Name)
 	require.Equal(t, thirdParameterName, templateRichParameters[4].Name)
 }
+
+func TestTemplateVersionPresets(t *testing.T) {
+	t.Parallel()
+
+	presetResponses := func(presets ...*proto.Preset) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Parameters: []*proto.RichParameter{
+							{
+								Name:         "size",
+								Type:         "string",
+								DefaultValue: "small",
+								Options: []*proto.RichParameterOption{
+									{Name: "Small", Value: "small"},
+									{Name: "Large", Value: "large"},
+								},
+							},
+							{
+								Name:     "region",
+								Type:     "string",
+								Required: true,
+							},
+						},
+						Presets: presets,
+					},
+				},
+			}},
+			ProvisionApply: echo.ApplyComplete,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, presetResponses(
+			&proto.Preset{
+				Name: "Large EU",
+				Parameters: []*proto.PresetParameter{
+					{Name: "region", Value: "eu"},
+					{Name: "size", Value: "large"},
+				},
+			},
+			&proto.Preset{
+				Name:       "frontend",
+				Parameters: []*proto.PresetParameter{{Name: "region", Value: "us"}},
+			},
+		))
+		version = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, version.Job.Status)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		presets, err := client.TemplateVersionPresets(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, presets, 2)
+		require.Equal(t, "frontend", presets[0].Name)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "us"}}, presets[0].Parameters)
+		require.Equal(t, "Large EU", presets[1].Name)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{
+			{Name: "region", Value: "eu"},
+			{Name: "size", Value: "large"},
+		}, presets[1].Parameters)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, presetResponses(
+			&proto.Preset{
+				Name:       "huge",
+				Parameters: []*proto.PresetParameter{{Name: "size", Value: "huge"}},
+			},
+		))
+		version = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		require.Equal(t, codersdk.ProvisionerJobFailed, version.Job.Status)
+		require.Contains(t, version.Job.Error, `preset "huge"`)
+	})
+
+	t.Run("UnknownParameter", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, presetResponses(
+			&proto.Preset{
+				Name:       "gpu",
+				Parameters: []*proto.PresetParameter{{Name: "gpus", Value: "1"}},
+			},
+		))
+		version = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		require.Equal(t, codersdk.ProvisionerJobFailed, version.Job.Status)
+		require.Contains(t, version.Job.Error, `preset "gpu" sets parameter "gpus" which does not exist`)
+	})
+}
@@ -2,11 +2,14 @@ package coderd
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/mail"
+	"net/url"
 	"regexp"
 	"sort"
 	"strconv"
@@ -15,6 +18,7 @@ import (
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-ldap/ldap/v3"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
@@ -31,6 +35,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
@@ -41,6 +46,9 @@ const (
 	userAuthLoggerName      = "userauth"
 	OAuthConvertCookieValue = "coder_oauth_convert_jwt"
 	mergeStateStringPrefix  = "convert-"
+	// ldapTimeout limits how long authenticating a user against the LDAP
+	// directory may take.
+	ldapTimeout = 10 * time.Second
 )
 
 type OAuthConvertStateClaims struct {
@@ -88,7 +96,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	switch req.ToType {
 	case codersdk.LoginTypeGithub, codersdk.LoginTypeOIDC:
 		// Allowed!
-	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken:
+	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken, codersdk.LoginTypeLDAP:
 		// These login types are not allowed to be converted to at this time.
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Cannot convert to login type %q.", req.ToType),
@@ -236,9 +244,18 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userRoles, err := rolestore.Expand(ctx, api.Database, roles.Roles)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error expanding user roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	userSubj := rbac.Subject{
 		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
+		Roles:  userRoles,
 		Groups: roles.Groups,
 		Scope:  rbac.ScopeAll,
 	}
@@ -398,7 +415,7 @@ func (api *API) postLogout(rw http.ResponseWriter, r *http.Request) {
 
 	logger := api.Logger.Named(userAuthLoggerName)
 
-	err := api.Database.DeleteAPIKeyByID(ctx, apiKey.ID)
+	_, err := api.Database.DeleteAPIKeyByID(ctx, apiKey.ID)
 	if err != nil {
 		logger.Error(ctx, "unable to delete API key", slog.F("api_key", apiKey.ID), slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -477,6 +494,7 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		LDAP: codersdk.AuthMethod{Enabled: api.LDAPConfig != nil},
 	})
 }
 
@@ -1123,6 +1141,346 @@ func mergeClaims(a, b map[string]interface{}) map[string]interface{} {
 	return c
 }
 
+type LDAPConfig struct {
+	// URL is the ldap:// or ldaps:// URL of the directory server.
+	URL string
+	// TLSConfig is used for ldaps:// URLs and StartTLS.
+	TLSConfig *tls.Config
+	// StartTLS upgrades ldap:// connections to TLS before binding.
+	StartTLS bool
+	// BindDN and BindPassword authenticate the search for the user that logs
+	// in. If BindDN is empty, the search is anonymous.
+	BindDN       string
+	BindPassword string
+	// UserSearchBaseDN is the DN the search for the user starts at.
+	UserSearchBaseDN string
+	// UserSearchFilter finds the entry of the user that logs in. Every
+	// "{username}" in it is replaced with the escaped username.
+	UserSearchFilter string
+	// UsernameAttribute selects the attribute to be used as the created
+	// user's username.
+	UsernameAttribute string
+	// EmailAttribute selects the attribute to be used as the created user's
+	// email.
+	EmailAttribute string
+	AllowSignups   bool
+	// GroupAttribute selects the attribute to be used as the user's groups,
+	// e.g. memberOf. If the group attribute is the empty string, then no
+	// group updates will ever come from LDAP.
+	GroupAttribute string
+	// CreateMissingGroups controls whether groups returned by LDAP are
+	// automatically created in Coder if they are missing.
+	CreateMissingGroups bool
+	// GroupFilter is a regular expression that filters the groups returned by
+	// LDAP. Any group not matched by this regex will be ignored. If the group
+	// filter is nil, then no group filtering will occur.
+	GroupFilter *regexp.Regexp
+	// GroupMapping controls how groups returned by LDAP get mapped to groups
+	// within Coder.
+	// map[ldapGroupName]coderGroupName
+	GroupMapping map[string]string
+}
+
+// errLDAPInvalidCredentials is returned if the user doesn't exist in the
+// directory or the password is wrong. Both are reported the same way, so
+// logins can't be used to find out which users exist.
+var errLDAPInvalidCredentials = xerrors.New("invalid credentials")
+
+// authenticate searches the entry of the user and binds as the user to verify
+// the password.
+func (cfg *LDAPConfig) authenticate(ctx context.Context, username, password string) (*ldap.Entry, error) {
+	// Directories accept a bind with an empty password as an unauthenticated
+	// bind, so it must never count as a login.
+	if username == "" || password == "" {
+		return nil, errLDAPInvalidCredentials
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ldapTimeout)
+	defer cancel()
+
+	conn, err := cfg.dial(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("connect to %q: %w", cfg.URL, err)
+	}
+	defer conn.Close()
+	// Requests don't take a context, so closing the connection is the only
+	// way to abort them.
+	conn.SetTimeout(ldapTimeout)
+	closed := make(chan struct{})
+	defer close(closed)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-closed:
+		}
+	}()
+
+	if cfg.BindDN != "" {
+		err = conn.Bind(cfg.BindDN, cfg.BindPassword)
+		if err != nil {
+			return nil, xerrors.Errorf("bind as %q: %w", cfg.BindDN, err)
+		}
+	}
+
+	attributes := []string{cfg.UsernameAttribute, cfg.EmailAttribute}
+	if cfg.GroupAttribute != "" {
+		attributes = append(attributes, cfg.GroupAttribute)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		cfg.UserSearchBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		// Two entries are enough to tell that the filter is ambiguous.
+		2,
+		// No time limit, the connection timeout is used instead.
+		0,
+		false,
+		strings.ReplaceAll(cfg.UserSearchFilter, "{username}", ldap.EscapeFilter(username)),
+		attributes,
+		nil,
+	))
+	if result != nil && len(result.Entries) > 1 {
+		return nil, xerrors.Errorf("the user search filter matches more than one entry for %q", username)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("search user: %w", err)
+	}
+	if len(result.Entries) == 0 {
+		return nil, errLDAPInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	err = conn.Bind(entry.DN, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil, errLDAPInvalidCredentials
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("bind as %q: %w", entry.DN, err)
+	}
+	return entry, nil
+}
+
+// dial connects to the directory server, and upgrades the connection to TLS
+// if StartTLS is enabled.
+func (cfg *LDAPConfig) dial(ctx context.Context) (*ldap.Conn, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	var port string
+	switch u.Scheme {
+	case "ldap":
+		port = ldap.DefaultLdapPort
+	case "ldaps":
+		port = ldap.DefaultLdapsPort
+		if cfg.StartTLS {
+			return nil, xerrors.New("StartTLS cannot be used with ldaps:// URLs")
+		}
+	default:
+		return nil, xerrors.Errorf("unsupported scheme %q, must be ldap or ldaps", u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+
+	tlsConfig := cfg.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	tlsConfig = tlsConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return nil, xerrors.Errorf("dial: %w", err)
+	}
+	if u.Scheme == "ldaps" {
+		tlsConn := tls.Client(netConn, tlsConfig)
+		err = tlsConn.HandshakeContext(ctx)
+		if err != nil {
+			_ = netConn.Close()
+			return nil, xerrors.Errorf("tls handshake: %w", err)
+		}
+		netConn = tlsConn
+	}
+
+	conn := ldap.NewConn(netConn, u.Scheme == "ldaps")
+	conn.Start()
+	if cfg.StartTLS {
+		// StartTLS doesn't take a context either.
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetTimeout(time.Until(deadline))
+		}
+		err = conn.StartTLS(tlsConfig)
+		if err != nil {
+			_ = conn.Close()
+			return nil, xerrors.Errorf("start tls: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// Authenticates the user with the username and password of their LDAP
+// directory.
+//
+// @Summary Log in user with LDAP
+// @ID log-in-user-with-ldap
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithLDAPRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/ldap/login [post]
+func (api *API) postLoginLDAP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// postLoginLDAP is a system function.
+		//nolint:gocritic
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if api.LDAPConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "LDAP authentication is not configured!",
+		})
+		return
+	}
+
+	var req codersdk.LoginWithLDAPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	entry, err := api.LDAPConfig.authenticate(ctx, req.Username, req.Password)
+	if errors.Is(err, errLDAPInvalidCredentials) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Incorrect username or password.",
+		})
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to authenticate user", slog.F("username", req.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to authenticate with LDAP.",
+		})
+		return
+	}
+
+	email := entry.GetEqualFoldAttributeValue(api.LDAPConfig.EmailAttribute)
+	if email == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("No email found in the %q attribute of your LDAP entry!", api.LDAPConfig.EmailAttribute),
+		})
+		return
+	}
+
+	// The username is a required property in Coder. Fall back to the
+	// username the user logged in with if the entry has none.
+	username := entry.GetEqualFoldAttributeValue(api.LDAPConfig.UsernameAttribute)
+	if username == "" {
+		username = req.Username
+	}
+	if httpapi.NameValid(username) != nil {
+		username = httpapi.UsernameFrom(username)
+	}
+
+	var usingGroups bool
+	var groups []string
+	// If the GroupAttribute is the empty string, then groups from LDAP are
+	// not used. This is so we can support manual group assignment.
+	if api.LDAPConfig.GroupAttribute != "" {
+		usingGroups = true
+		for _, group := range entry.GetEqualFoldAttributeValues(api.LDAPConfig.GroupAttribute) {
+			if mappedGroup, ok := api.LDAPConfig.GroupMapping[group]; ok {
+				group = mappedGroup
+			}
+			groups = append(groups, group)
+		}
+		logger.Debug(ctx, "groups returned in ldap entry",
+			slog.F("len", len(groups)),
+			slog.F("groups", groups),
+		)
+	}
+
+	user, link, err := findLinkedUser(ctx, api.Database, ldapLinkedID(entry), email)
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to find linked user", slog.F("email", email), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// If a new user is authenticating for the first time
+	// the audit action is 'register', not 'login'
+	if user.ID == uuid.Nil {
+		aReq.Action = database.AuditActionRegister
+	}
+
+	params := (&oauthLoginParams{
+		User:      user,
+		Link:      link,
+		LinkedID:  ldapLinkedID(entry),
+		LoginType: database.LoginTypeLDAP,
+		// LDAP logins have no OAuth2 tokens to store.
+		State:               httpmw.OAuth2State{Token: &oauth2.Token{}},
+		AllowSignups:        api.LDAPConfig.AllowSignups,
+		Email:               email,
+		Username:            username,
+		UsingGroups:         usingGroups,
+		Groups:              groups,
+		CreateMissingGroups: api.LDAPConfig.CreateMissingGroups,
+		GroupFilter:         api.LDAPConfig.GroupFilter,
+	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+		return audit.InitRequest[database.User](rw, params)
+	})
+	cookies, key, err := api.oauthLogin(r, params)
+	defer params.CommitAuditLogs()
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		// LDAP logins are API requests, not browser redirects.
+		httpErr.renderStaticPage = false
+		httpErr.Write(rw, r)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "ldap: login failed", slog.F("user", user.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process LDAP login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	var sessionToken string
+	for _, cookie := range cookies {
+		if cookie.Name == codersdk.SessionTokenCookie {
+			sessionToken = cookie.Value
+		}
+		http.SetCookie(rw, cookie)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: sessionToken,
+	})
+}
+
 type oauthLoginParams struct {
 	User      database.User
 	Link      database.UserLink
@@ -1212,12 +1570,16 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 		logger  = api.Logger.Named(userAuthLoggerName)
 	)
 
-	var isConvertLoginType bool
+	var (
+		isConvertLoginType bool
+		isNewUser          bool
+	)
 	err := api.Database.InTx(func(tx database.Store) error {
 		var (
 			link database.UserLink
 			err  error
 		)
+		isNewUser = false
 
 		user = params.User
 		link = params.Link
@@ -1310,6 +1672,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			if err != nil {
 				return xerrors.Errorf("create user: %w", err)
 			}
+			isNewUser = true
 		}
 
 		// Activate dormant user on sigin
@@ -1450,13 +1813,16 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 	if err != nil {
 		return nil, database.APIKey{}, xerrors.Errorf("in tx: %w", err)
 	}
+	if isNewUser {
+		api.WakeWebhooks(ctx)
+	}
 
 	var key database.APIKey
 	oldKey, _, ok := httpmw.APIKeyFromRequest(ctx, api.Database, nil, r)
 	if ok && oldKey != nil && isConvertLoginType {
 		// If this is a convert login type, and it succeeds, then delete the old
 		// session. Force the user to log back in.
-		err := api.Database.DeleteAPIKeyByID(r.Context(), oldKey.ID)
+		_, err := api.Database.DeleteAPIKeyByID(r.Context(), oldKey.ID)
 		if err != nil {
 			// Do not block this login if we fail to delete the old API key.
 			// Just delete the cookie and continue.
@@ -1605,6 +1971,11 @@ func oidcLinkedID(tok *oidc.IDToken) string {
 	return strings.Join([]string{tok.Issuer, tok.Subject}, "||")
 }
 
+// ldapLinkedID returns the unique ID for an LDAP user.
+func ldapLinkedID(entry *ldap.Entry) string {
+	return strings.Join([]string{"ldap", strings.ToLower(entry.DN)}, "||")
+}
+
 // findLinkedUser tries to find a user by their unique OAuth-linked ID.
 // If it doesn't not find it, it returns the user by their email.
 func findLinkedUser(ctx context.Context, db database.Store, linkedID string, emails ...string) (database.User, database.UserLink, error) {
@@ -1675,7 +2046,8 @@ func clearOAuthConvertCookie() *http.Cookie {
 
 func wrongLoginTypeHTTPError(user database.LoginType, params database.LoginType) httpError {
 	addedMsg := ""
-	if user == database.LoginTypePassword {
+	// Accounts cannot be converted to LDAP, see postConvertLoginType.
+	if user == database.LoginTypePassword && params != database.LoginTypeLDAP {
 		addedMsg = " You can convert your account to use this login type by visiting your account settings."
 	}
 	return httpError{
@@ -3,6 +3,8 @@ package coderd_test
 import (
 	"context"
 	"crypto"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"net/http/cookiejar"
@@ -21,6 +23,7 @@ import (
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/ldaptest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
@@ -160,6 +163,20 @@ func TestUserAuthMethods(t *testing.T) {
 		require.True(t, methods.Password.Enabled)
 		require.True(t, methods.Github.Enabled)
 	})
+	t.Run("LDAP", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.True(t, methods.Password.Enabled)
+		require.True(t, methods.LDAP.Enabled)
+	})
 }
 
 // nolint:bodyclose
@@ -1004,6 +1021,275 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+// nolint:bodyclose
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+
+	alice := ldaptest.Entry{
+		DN:       "uid=alice,ou=people,dc=coder,dc=com",
+		Password: "hunter2",
+		Attributes: map[string][]string{
+			"uid":  {"alice"},
+			"mail": {"alice@coder.com"},
+		},
+	}
+	ldapConfig := func(srv *ldaptest.Server) *coderd.LDAPConfig {
+		return &coderd.LDAPConfig{
+			URL:               srv.URL,
+			UserSearchBaseDN:  "ou=people,dc=coder,dc=com",
+			UserSearchFilter:  "(uid={username})",
+			UsernameAttribute: "uid",
+			EmailAttribute:    "mail",
+			AllowSignups:      true,
+		}
+	}
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Signup", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		srv := ldaptest.New(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor:    auditor,
+			LDAPConfig: ldapConfig(srv),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		numLogs := len(auditor.AuditLogs())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		resp, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		require.NoError(t, err)
+		numLogs++ // add an audit log for login
+
+		ldapClient := codersdk.New(client.URL)
+		ldapClient.SetSessionToken(resp.SessionToken)
+		user, err := ldapClient.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, "alice@coder.com", user.Email)
+		require.Equal(t, codersdk.LoginTypeLDAP, user.LoginType)
+
+		require.Len(t, auditor.AuditLogs(), numLogs)
+		require.Equal(t, database.AuditActionRegister, auditor.AuditLogs()[numLogs-1].Action)
+	})
+
+	t.Run("UpdatesUserOnLogin", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: ldapConfig(srv),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		resp, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		require.NoError(t, err)
+		ldapClient := codersdk.New(client.URL)
+		ldapClient.SetSessionToken(resp.SessionToken)
+		first, err := ldapClient.User(ctx, "me")
+		require.NoError(t, err)
+
+		// The user is linked by their DN, so a changed email in the
+		// directory updates the existing user.
+		srv.Put(ldaptest.Entry{
+			DN:       alice.DN,
+			Password: alice.Password,
+			Attributes: map[string][]string{
+				"uid":  {"alice"},
+				"mail": {"alice@example.com"},
+			},
+		})
+		resp, err = client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		require.NoError(t, err)
+		ldapClient.SetSessionToken(resp.SessionToken)
+		second, err := ldapClient.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, first.ID, second.ID)
+		require.Equal(t, "alice@example.com", second.Email)
+	})
+
+	t.Run("SignupsDisabled", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, alice)
+		cfg := ldapConfig(srv)
+		cfg.AllowSignups = false
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: cfg,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("IncorrectCredentials", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: ldapConfig(srv),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		for _, req := range []codersdk.LoginWithLDAPRequest{
+			{Username: "alice", Password: "wrong"},
+			{Username: "bob", Password: "hunter2"},
+			// The username is escaped, so it cannot change the filter.
+			{Username: "*", Password: "hunter2"},
+		} {
+			_, err := client.LoginWithLDAP(ctx, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode(), req.Username)
+		}
+	})
+
+	t.Run("ServiceAccountBind", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, alice, ldaptest.Entry{
+			DN:       "cn=coder,dc=coder,dc=com",
+			Password: "secret",
+		})
+		cfg := ldapConfig(srv)
+		cfg.BindDN = "cn=coder,dc=coder,dc=com"
+		cfg.BindPassword = "secret"
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: cfg,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		require.NoError(t, err)
+
+		// A wrong service account password is a configuration error, not
+		// a wrong password of the user.
+		cfg.BindPassword = "wrong"
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		client = coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: cfg,
+			Logger:     &logger,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, err = client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusInternalServerError, apiErr.StatusCode())
+	})
+
+	t.Run("StartTLS", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, alice)
+		cert := testutil.GenerateTLSCertificate(t, "localhost")
+		srv.EnableStartTLS(cert)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		roots := x509.NewCertPool()
+		roots.AddCert(leaf)
+
+		cfg := ldapConfig(srv)
+		cfg.StartTLS = true
+		cfg.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    roots,
+			ServerName: "localhost",
+		}
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: cfg,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err = client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("NoEmail", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, ldaptest.Entry{
+			DN:       alice.DN,
+			Password: alice.Password,
+			Attributes: map[string][]string{
+				"uid": {"alice"},
+			},
+		})
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: ldapConfig(srv),
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("PasswordUser", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: ldapConfig(srv),
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, _ = coderdtest.CreateAnotherUserMutators(t, client, owner.OrganizationID, nil, func(r *codersdk.CreateUserRequest) {
+			r.Email = "alice@coder.com"
+			r.Username = "alice"
+		})
+
+		// A password user with the same email must not be taken over.
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "hunter2",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
 func TestUserLogout(t *testing.T) {
 	t.Parallel()
 
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
@@ -21,10 +22,12 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -140,6 +143,7 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.WakeWebhooks(ctx)
 
 	telemetryUser := telemetry.ConvertUser(user)
 	// Send the initial users email address!
@@ -288,6 +292,16 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ServiceAccount {
+		// Service accounts authenticate with tokens only.
+		if req.UserLoginType != "" && req.UserLoginType != codersdk.LoginTypeNone {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Service accounts cannot use the %q login type.", req.UserLoginType),
+			})
+			return
+		}
+		req.UserLoginType = codersdk.LoginTypeNone
+	}
 	if req.UserLoginType == "" && req.DisableLogin {
 		// Handle the deprecated field
 		req.UserLoginType = codersdk.LoginTypeNone
@@ -391,6 +405,8 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		loginType = database.LoginTypeOIDC
 	case codersdk.LoginTypeGithub:
 		loginType = database.LoginTypeGithub
+	case codersdk.LoginTypeLDAP:
+		loginType = database.LoginTypeLDAP
 	default:
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Unsupported login type %q for manually creating new users.", req.UserLoginType),
@@ -414,6 +430,7 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.WakeWebhooks(ctx)
 
 	aReq.New = user
 
@@ -970,10 +987,9 @@ func UpdateSiteUserRoles(ctx context.Context, db database.Store, args database.U
 		if _, ok := rbac.IsOrgRole(r); ok {
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
-
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
-		}
+	}
+	if err := rolestore.Validate(ctx, db, args.GrantedRoles); err != nil {
+		return database.User{}, err
 	}
 
 	updatedUser, err := db.UpdateUserRoles(ctx, args)
@@ -1062,6 +1078,9 @@ type CreateUserRequest struct {
 	LoginType          database.LoginType
 }
 
+// CreateUser creates the user in a transaction of the store, with the
+// user_created webhook event. Callers wake the webhook dispatchers once the
+// transaction committed, since the store may be a transaction of the caller.
 func (api *API) CreateUser(ctx context.Context, store database.Store, req CreateUserRequest) (database.User, uuid.UUID, error) {
 	// Ensure the username is valid. It's the caller's responsibility to ensure
 	// the username is valid and unique.
@@ -1070,7 +1089,7 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 	}
 
 	var user database.User
-	return user, req.OrganizationID, store.InTx(func(tx database.Store) error {
+	err := store.InTx(func(tx database.Store) error {
 		orgRoles := make([]string, 0)
 		// If no organization is provided, create a new one for the user.
 		if req.OrganizationID == uuid.Nil {
@@ -1107,8 +1126,9 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 			CreatedAt: dbtime.Now(),
 			UpdatedAt: dbtime.Now(),
 			// All new users are defaulted to members of the site.
-			RBACRoles: []string{},
-			LoginType: req.LoginType,
+			RBACRoles:        []string{},
+			LoginType:        req.LoginType,
+			IsServiceAccount: req.ServiceAccount,
 		}
 		// If a user signs up with OAuth, they can have no password!
 		if req.Password != "" {
@@ -1150,8 +1170,22 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 		if err != nil {
 			return xerrors.Errorf("create organization member: %w", err)
 		}
+		_, err = webhooks.Insert(ctx, tx, webhooks.UserPayload(codersdk.WebhookEventUserCreated, user))
+		if err != nil {
+			return xerrors.Errorf("queue webhook event: %w", err)
+		}
 		return nil
 	}, nil)
+	return user, req.OrganizationID, err
+}
+
+// WakeWebhooks wakes the webhook dispatchers once the transaction that queued
+// deliveries committed.
+func (api *API) WakeWebhooks(ctx context.Context) {
+	err := webhooks.Wake(api.Pubsub)
+	if err != nil {
+		api.Logger.Warn(ctx, "wake webhook dispatchers", slog.Error(err))
+	}
 }
 
 func convertUsers(users []database.User, organizationIDsByUserID map[uuid.UUID][]uuid.UUID) []codersdk.User {
@@ -1196,5 +1230,6 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		Scope:           codersdk.APIKeyScope(k.Scope),
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
+		ScopeRules:      convertScopeRules(k.ScopeRules),
 	}
 }
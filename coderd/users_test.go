@@ -631,6 +631,65 @@ func TestPostUsers(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, found.LoginType, codersdk.LoginTypeOIDC)
 	})
+
+	t.Run("CreateServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		user, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: first.OrganizationID,
+			Email:          "ci@coder.com",
+			Username:       "ci-bot",
+			ServiceAccount: true,
+		})
+		require.NoError(t, err)
+		require.True(t, user.IsServiceAccount)
+		require.Equal(t, codersdk.LoginTypeNone, user.LoginType)
+
+		// Service accounts authenticate with tokens created for them.
+		token, err := client.CreateToken(ctx, user.ID.String(), codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+		serviceClient := codersdk.New(client.URL)
+		serviceClient.SetSessionToken(token.Key)
+		found, err := serviceClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, found.ID)
+		require.True(t, found.IsServiceAccount)
+	})
+
+	t.Run("ServiceAccountLogin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: first.OrganizationID,
+			Email:          "ci@coder.com",
+			Username:       "ci-bot",
+			Password:       "SomeSecurePassword!",
+			ServiceAccount: true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: first.OrganizationID,
+			Email:          "ci@coder.com",
+			Username:       "ci-bot",
+			UserLoginType:  codersdk.LoginTypeOIDC,
+			ServiceAccount: true,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
 }
 
 func TestUpdateUserProfile(t *testing.T) {
@@ -0,0 +1,356 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// @Summary Get webhooks
+// @ID get-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Success 200 {array} codersdk.Webhook
+// @Router /webhooks [get]
+func (api *API) webhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	dbwebhooks, err := api.Database.GetWebhooks(ctx)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	webhooks := make([]codersdk.Webhook, 0, len(dbwebhooks))
+	for _, webhook := range dbwebhooks {
+		webhooks = append(webhooks, convertWebhook(webhook))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, webhooks)
+}
+
+// @Summary Get webhook by ID
+// @ID get-webhook-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [get]
+func (api *API) webhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook := httpmw.WebhookParam(r)
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhook(webhook))
+}
+
+// @Summary Create webhook
+// @ID create-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param request body codersdk.CreateWebhookRequest true "Create webhook request"
+// @Success 201 {object} codersdk.CreateWebhookResponse
+// @Router /webhooks [post]
+func (api *API) postWebhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req codersdk.CreateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !validateWebhook(ctx, rw, req.URL, req.Events) {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		secret, err = cryptorand.HexString(32)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	now := dbtime.Now()
+	webhook, err := api.Database.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      req.Name,
+		Url:       req.URL,
+		Secret:    secret,
+		Events:    convertWebhookEvents(req.Events),
+		Enabled:   true,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateWebhookResponse{
+		Webhook: convertWebhook(webhook),
+		Secret:  secret,
+	})
+}
+
+// @Summary Update webhook
+// @ID update-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param request body codersdk.UpdateWebhookRequest true "Update webhook request"
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [patch]
+func (api *API) patchWebhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook := httpmw.WebhookParam(r)
+
+	var req codersdk.UpdateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	params := database.UpdateWebhookByIDParams{
+		ID:        webhook.ID,
+		UpdatedAt: dbtime.Now(),
+		Name:      webhook.Name,
+		Url:       webhook.Url,
+		Secret:    webhook.Secret,
+		Events:    webhook.Events,
+		Enabled:   webhook.Enabled,
+	}
+	if req.Name != nil {
+		if err := httpapi.NameValid(*req.Name); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid webhook name.",
+				Validations: []codersdk.ValidationError{
+					{Field: "name", Detail: err.Error()},
+				},
+			})
+			return
+		}
+		params.Name = *req.Name
+	}
+	events := convertWebhookEventsFromDatabase(webhook.Events)
+	if req.Events != nil {
+		events = req.Events
+	}
+	if req.URL != nil {
+		params.Url = *req.URL
+	}
+	if !validateWebhook(ctx, rw, params.Url, events) {
+		return
+	}
+	params.Events = convertWebhookEvents(events)
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "The secret of a webhook can't be empty.",
+			})
+			return
+		}
+		params.Secret = *req.Secret
+	}
+	if req.Enabled != nil {
+		params.Enabled = *req.Enabled
+	}
+
+	updated, err := api.Database.UpdateWebhookByID(ctx, params)
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", params.Name),
+		})
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhook(updated))
+}
+
+// @Summary Delete webhook
+// @ID delete-webhook
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /webhooks/{webhook} [delete]
+func (api *API) deleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook := httpmw.WebhookParam(r)
+
+	err := api.Database.DeleteWebhookByID(ctx, webhook.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Webhook has been deleted!",
+	})
+}
+
+// @Summary Get webhook deliveries
+// @ID get-webhook-deliveries
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries [get]
+func (api *API) webhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook := httpmw.WebhookParam(r)
+
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	dbdeliveries, err := api.Database.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhook.ID,
+		OffsetOpt: int32(page.Offset),
+		LimitOpt:  int32(page.Limit),
+	})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	deliveries := make([]codersdk.WebhookDelivery, 0, len(dbdeliveries))
+	for _, delivery := range dbdeliveries {
+		deliveries = append(deliveries, convertWebhookDelivery(delivery))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, deliveries)
+}
+
+// validateWebhook writes an error response if the URL or events of a webhook
+// are invalid.
+func validateWebhook(ctx context.Context, rw http.ResponseWriter, rawURL string, events []codersdk.WebhookEvent) bool {
+	u, err := url.Parse(rawURL)
+	if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+		err = xerrors.New("must be an http or https URL")
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid webhook URL.",
+			Validations: []codersdk.ValidationError{
+				{Field: "url", Detail: err.Error()},
+			},
+		})
+		return false
+	}
+
+	if len(events) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A webhook must subscribe to at least one event.",
+		})
+		return false
+	}
+	for _, event := range events {
+		if !database.WebhookEvent(event).Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid webhook event %q.", event),
+				Detail:  fmt.Sprintf("Events must be one of %q.", codersdk.WebhookEvents),
+			})
+			return false
+		}
+	}
+	return true
+}
+
+func convertWebhookEvents(events []codersdk.WebhookEvent) []database.WebhookEvent {
+	converted := make([]database.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(converted, database.WebhookEvent(event)) {
+			converted = append(converted, database.WebhookEvent(event))
+		}
+	}
+	return converted
+}
+
+func convertWebhookEventsFromDatabase(events []database.WebhookEvent) []codersdk.WebhookEvent {
+	converted := make([]codersdk.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, codersdk.WebhookEvent(event))
+	}
+	return converted
+}
+
+func convertWebhook(webhook database.Webhook) codersdk.Webhook {
+	return codersdk.Webhook{
+		ID:        webhook.ID,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+		Name:      webhook.Name,
+		URL:       webhook.Url,
+		Events:    convertWebhookEventsFromDatabase(webhook.Events),
+		Enabled:   webhook.Enabled,
+	}
+}
+
+func convertWebhookDelivery(delivery database.WebhookDelivery) codersdk.WebhookDelivery {
+	converted := codersdk.WebhookDelivery{
+		ID:        delivery.ID,
+		WebhookID: delivery.WebhookID,
+		Event:     codersdk.WebhookEvent(delivery.Event),
+		Payload:   delivery.Payload,
+		CreatedAt: delivery.CreatedAt,
+		Attempts:  delivery.Attempts,
+		Error:     delivery.Error,
+	}
+	if delivery.LastAttemptAt.Valid {
+		converted.LastAttemptAt = &delivery.LastAttemptAt.Time
+	}
+	if delivery.NextAttemptAt.Valid {
+		converted.NextAttemptAt = &delivery.NextAttemptAt.Time
+	}
+	if delivery.DeliveredAt.Valid {
+		converted.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	if delivery.StatusCode.Valid {
+		converted.StatusCode = &delivery.StatusCode.Int32
+	}
+	return converted
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// WorkspaceBuildPayload returns the payload of an event about a workspace
+// build. The build error is only set for failed builds.
+func WorkspaceBuildPayload(event codersdk.WebhookEvent, workspace database.Workspace, owner database.User, template database.Template, build database.WorkspaceBuild, buildError string) codersdk.WebhookPayload {
+	return codersdk.WebhookPayload{
+		Event: event,
+		Workspace: &codersdk.WebhookPayloadWorkspace{
+			ID:             workspace.ID,
+			Name:           workspace.Name,
+			OwnerID:        owner.ID,
+			OwnerName:      owner.Username,
+			OrganizationID: workspace.OrganizationID,
+			TemplateID:     template.ID,
+			TemplateName:   template.Name,
+			BuildID:        build.ID,
+			BuildNumber:    build.BuildNumber,
+			Transition:     codersdk.WorkspaceTransition(build.Transition),
+			Error:          buildError,
+		},
+	}
+}
+
+// TemplateVersionPayload returns the payload of an event about a template
+// version.
+func TemplateVersionPayload(event codersdk.WebhookEvent, template database.Template, version database.TemplateVersion) codersdk.WebhookPayload {
+	return codersdk.WebhookPayload{
+		Event: event,
+		TemplateVersion: &codersdk.WebhookPayloadTemplateVersion{
+			ID:             version.ID,
+			Name:           version.Name,
+			TemplateID:     template.ID,
+			TemplateName:   template.Name,
+			OrganizationID: template.OrganizationID,
+		},
+	}
+}
+
+// UserPayload returns the payload of an event about a user.
+func UserPayload(event codersdk.WebhookEvent, user database.User) codersdk.WebhookPayload {
+	return codersdk.WebhookPayload{
+		Event: event,
+		User: &codersdk.WebhookPayloadUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+		},
+	}
+}
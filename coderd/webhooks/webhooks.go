@@ -0,0 +1,224 @@
+// Package webhooks delivers events to the webhooks registered by admins.
+//
+// Events are queued in the database as a delivery per subscribed webhook, so
+// they survive restarts and any replica can send them. Every replica runs a
+// Dispatcher that claims due deliveries, sends them and schedules a retry with
+// exponential backoff when they fail.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/deliveryqueue"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// EventChannel is the pubsub channel that wakes the dispatchers of all
+	// replicas when deliveries are queued.
+	EventChannel = "webhook_deliveries"
+
+	// MaxAttempts is the number of times a delivery is attempted before it
+	// is given up.
+	MaxAttempts = 8
+	// RetryInterval is the delay before the first retry of a failed
+	// delivery. It doubles with every attempt.
+	RetryInterval = 30 * time.Second
+	// PollInterval is how often dispatchers look for deliveries that are due
+	// for a retry.
+	PollInterval = deliveryqueue.PollInterval
+
+	// leaseDuration is how long a delivery is claimed by the dispatcher that
+	// attempts it. If the replica goes away during the attempt, another
+	// replica retries the delivery once the lease expired.
+	leaseDuration = time.Minute
+	// requestTimeout must be shorter than leaseDuration.
+	requestTimeout = 30 * time.Second
+	// maxConcurrentDeliveries is the number of deliveries a dispatcher sends
+	// at the same time.
+	maxConcurrentDeliveries = 8
+)
+
+// Enqueue queues the payload for every enabled webhook that subscribed to its
+// event, and wakes the dispatchers. The ID and timestamp of the payload are
+// set if they are empty.
+func Enqueue(ctx context.Context, db database.Store, ps pubsub.Pubsub, payload codersdk.WebhookPayload) error {
+	queued, err := Insert(ctx, db, payload)
+	if err != nil || !queued {
+		return err
+	}
+	return Wake(ps)
+}
+
+// Insert queues the payload like Enqueue, without waking the dispatchers, and
+// reports whether any delivery was queued. It is used inside transactions:
+// the dispatchers can't see the deliveries until the transaction committed,
+// so callers Wake them afterwards.
+func Insert(ctx context.Context, db database.Store, payload codersdk.WebhookPayload) (bool, error) {
+	if payload.ID == uuid.Nil {
+		payload.ID = uuid.New()
+	}
+	if payload.Timestamp.IsZero() {
+		payload.Timestamp = dbtime.Now()
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return false, xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	// Events are delivered to all webhooks, regardless of who caused them.
+	//nolint:gocritic // Users can't read webhooks, but their actions emit events.
+	deliveries, err := db.InsertWebhookDeliveries(dbauthz.AsSystemRestricted(ctx), database.InsertWebhookDeliveriesParams{
+		Event:     database.WebhookEvent(payload.Event),
+		Payload:   data,
+		CreatedAt: payload.Timestamp,
+	})
+	if err != nil {
+		return false, xerrors.Errorf("insert webhook deliveries: %w", err)
+	}
+	return len(deliveries) > 0, nil
+}
+
+// Wake wakes the dispatchers of all replicas to send the queued deliveries.
+func Wake(ps pubsub.Pubsub) error {
+	err := ps.Publish(EventChannel, nil)
+	if err != nil {
+		return xerrors.Errorf("publish webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Backoff returns the delay before the next attempt of a delivery that failed
+// after the given number of attempts.
+func Backoff(attempts int32) time.Duration {
+	return deliveryqueue.Backoff(RetryInterval, attempts)
+}
+
+// Dispatcher sends the queued deliveries to their webhooks.
+type Dispatcher struct {
+	*deliveryqueue.Queue[database.WebhookDelivery]
+
+	db     database.Store
+	log    slog.Logger
+	client *http.Client
+}
+
+// New returns a dispatcher that sends deliveries when it is woken through the
+// pubsub, and on every tick to retry failed deliveries.
+func New(ctx context.Context, db database.Store, ps pubsub.Pubsub, log slog.Logger, client *http.Client, tick <-chan time.Time) *Dispatcher {
+	if client == nil {
+		client = &http.Client{}
+	}
+	d := &Dispatcher{
+		db:     db,
+		log:    log,
+		client: client,
+	}
+	//nolint:gocritic // The dispatcher reads the secrets of all webhooks.
+	d.Queue = deliveryqueue.New(dbauthz.AsSystemRestricted(ctx), log, deliveryqueue.Options[database.WebhookDelivery]{
+		Pubsub:        ps,
+		Channel:       EventChannel,
+		Tick:          tick,
+		LeaseDuration: leaseDuration,
+		Concurrency:   maxConcurrentDeliveries,
+		Acquire: func(ctx context.Context, now, leaseExpiresAt time.Time) (database.WebhookDelivery, error) {
+			return db.AcquireWebhookDelivery(ctx, database.AcquireWebhookDeliveryParams{
+				Now:            now,
+				LeaseExpiresAt: leaseExpiresAt,
+			})
+		},
+		Send: d.deliver,
+	})
+	return d
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery database.WebhookDelivery) {
+	log := d.log.With(
+		slog.F("webhook_id", delivery.WebhookID),
+		slog.F("delivery_id", delivery.ID),
+		slog.F("event", delivery.Event),
+		slog.F("attempt", delivery.Attempts),
+	)
+
+	webhook, err := d.db.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		// The lease expires and the delivery is retried.
+		log.Warn(ctx, "get webhook", slog.Error(err))
+		return
+	}
+
+	statusCode, err := d.send(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		// The dispatcher is closing. The lease expires and the delivery is
+		// retried, without counting this attempt as a failure of the webhook.
+		return
+	}
+	now := dbtime.Now()
+	update := database.UpdateWebhookDeliveryByIDParams{
+		ID: delivery.ID,
+		StatusCode: sql.NullInt32{
+			Int32: int32(statusCode),
+			Valid: statusCode != 0,
+		},
+	}
+	switch {
+	case err == nil:
+		update.DeliveredAt = sql.NullTime{Time: now, Valid: true}
+	case delivery.Attempts >= MaxAttempts:
+		update.Error = err.Error()
+		log.Warn(ctx, "webhook delivery failed, giving up", slog.Error(err))
+	default:
+		update.Error = err.Error()
+		update.NextAttemptAt = sql.NullTime{Time: now.Add(Backoff(delivery.Attempts)), Valid: true}
+		log.Debug(ctx, "webhook delivery failed, retrying",
+			slog.F("next_attempt_at", update.NextAttemptAt.Time), slog.Error(err))
+	}
+
+	err = d.db.UpdateWebhookDeliveryByID(ctx, update)
+	if err != nil {
+		log.Error(ctx, "update webhook delivery", slog.Error(err))
+	}
+}
+
+// send posts the payload to the webhook. It returns the status code of the
+// response, if any, and an error unless the webhook responded with a 2xx
+// status code.
+func (d *Dispatcher) send(ctx context.Context, webhook database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Coder/"+buildinfo.Version())
+	req.Header.Set(codersdk.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(codersdk.WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(codersdk.WebhookSignatureHeader, codersdk.WebhookSignature(webhook.Secret, delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,196 @@
+package webhooks_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookServer responds to every request with the status code, and sends
+// the requests to the returned channel.
+func webhookServer(t *testing.T, statusCode int) (string, <-chan request) {
+	t.Helper()
+
+	requests := make(chan request, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests <- request{header: r.Header.Clone(), body: body}
+		rw.WriteHeader(statusCode)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, requests
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delivered", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitLong)
+			db, pubsub = dbtestutil.NewDB(t)
+			log        = slogtest.Make(t, nil)
+		)
+		url, requests := webhookServer(t, http.StatusNoContent)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Secret:  "secret",
+			Events:  []database.WebhookEvent{database.WebhookEventWorkspaceCreated},
+			Enabled: true,
+		})
+		// This webhook didn't subscribe to the event.
+		other := dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Events:  []database.WebhookEvent{database.WebhookEventUserCreated},
+			Enabled: true,
+		})
+
+		dispatcher := webhooks.New(ctx, db, pubsub, log, nil, make(chan time.Time))
+		require.NoError(t, dispatcher.Start())
+		defer dispatcher.Close()
+
+		workspaceID := uuid.New()
+		err := webhooks.Enqueue(ctx, db, pubsub, codersdk.WebhookPayload{
+			Event: codersdk.WebhookEventWorkspaceCreated,
+			Workspace: &codersdk.WebhookPayloadWorkspace{
+				ID:   workspaceID,
+				Name: "dev",
+			},
+		})
+		require.NoError(t, err)
+
+		var req request
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the webhook request")
+		case req = <-requests:
+		}
+		require.Equal(t, string(codersdk.WebhookEventWorkspaceCreated), req.header.Get(codersdk.WebhookEventHeader))
+		require.Equal(t, codersdk.WebhookSignature("secret", req.body), req.header.Get(codersdk.WebhookSignatureHeader))
+		require.Equal(t, "application/json", req.header.Get("Content-Type"))
+
+		var deliveries []database.WebhookDelivery
+		require.Eventually(t, func() bool {
+			deliveries, err = db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhook.ID,
+			})
+			return assert.NoError(t, err) && len(deliveries) == 1 && deliveries[0].DeliveredAt.Valid
+		}, testutil.WaitShort, testutil.IntervalFast)
+		delivery := deliveries[0]
+		require.Equal(t, delivery.ID.String(), req.header.Get(codersdk.WebhookDeliveryHeader))
+		require.EqualValues(t, 1, delivery.Attempts)
+		require.EqualValues(t, http.StatusNoContent, delivery.StatusCode.Int32)
+		require.False(t, delivery.NextAttemptAt.Valid)
+		require.Empty(t, delivery.Error)
+		require.JSONEq(t, string(delivery.Payload), string(req.body))
+
+		deliveries, err = db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: other.ID,
+		})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+
+	t.Run("Retried", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitLong)
+			db, pubsub = dbtestutil.NewDB(t)
+			log        = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		)
+		url, requests := webhookServer(t, http.StatusInternalServerError)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Enabled: true,
+		})
+
+		dispatcher := webhooks.New(ctx, db, pubsub, log, nil, make(chan time.Time))
+		require.NoError(t, dispatcher.Start())
+		defer dispatcher.Close()
+
+		before := time.Now()
+		err := webhooks.Enqueue(ctx, db, pubsub, codersdk.WebhookPayload{
+			Event: codersdk.WebhookEventUserCreated,
+		})
+		require.NoError(t, err)
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the webhook request")
+		case <-requests:
+		}
+
+		var deliveries []database.WebhookDelivery
+		require.Eventually(t, func() bool {
+			deliveries, err = db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhook.ID,
+			})
+			return assert.NoError(t, err) && len(deliveries) == 1 && deliveries[0].StatusCode.Valid
+		}, testutil.WaitShort, testutil.IntervalFast)
+		delivery := deliveries[0]
+		require.EqualValues(t, 1, delivery.Attempts)
+		require.EqualValues(t, http.StatusInternalServerError, delivery.StatusCode.Int32)
+		require.NotEmpty(t, delivery.Error)
+		require.False(t, delivery.DeliveredAt.Valid)
+		require.True(t, delivery.NextAttemptAt.Valid)
+		require.WithinDuration(t, before.Add(webhooks.RetryInterval), delivery.NextAttemptAt.Time, testutil.WaitShort)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = testutil.Context(t, testutil.WaitLong)
+			db, pubsub = dbtestutil.NewDB(t)
+		)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Enabled: false,
+		})
+
+		err := webhooks.Enqueue(ctx, db, pubsub, codersdk.WebhookPayload{
+			Event: codersdk.WebhookEventUserCreated,
+		})
+		require.NoError(t, err)
+
+		deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+		})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, webhooks.RetryInterval, webhooks.Backoff(1))
+	require.Equal(t, 2*webhooks.RetryInterval, webhooks.Backoff(2))
+	require.Equal(t, 64*webhooks.RetryInterval, webhooks.Backoff(webhooks.MaxAttempts-1))
+}
@@ -0,0 +1,181 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		created, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "slack",
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceCreated},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Secret, "a secret is generated")
+		require.True(t, created.Webhook.Enabled)
+
+		webhooks, err := client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.Webhook{created.Webhook}, webhooks)
+
+		updated, err := client.UpdateWebhook(ctx, created.Webhook.ID, codersdk.UpdateWebhookRequest{
+			Events:  []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated, codersdk.WebhookEventWorkspaceDeleted},
+			Enabled: ptr.Ref(false),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "slack", updated.Name)
+		require.Equal(t, []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated, codersdk.WebhookEventWorkspaceDeleted}, updated.Events)
+		require.False(t, updated.Enabled)
+
+		webhook, err := client.Webhook(ctx, created.Webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, webhook)
+
+		err = client.DeleteWebhook(ctx, created.Webhook.ID)
+		require.NoError(t, err)
+		_, err = client.Webhook(ctx, created.Webhook.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		for _, req := range []codersdk.CreateWebhookRequest{
+			{Name: "scheme", URL: "ftp://example.com", Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated}},
+			{Name: "events", URL: "https://example.com"},
+			{Name: "event", URL: "https://example.com", Events: []codersdk.WebhookEvent{"workspace_renamed"}},
+		} {
+			_, err := client.CreateWebhook(ctx, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, req.Name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), req.Name)
+		}
+
+		req := codersdk.CreateWebhookRequest{
+			Name:   "duplicate",
+			URL:    "https://example.com",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		}
+		_, err := client.CreateWebhook(ctx, req)
+		require.NoError(t, err)
+		_, err = client.CreateWebhook(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := member.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "slack",
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		require.Error(t, err)
+
+		webhooks, err := member.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+	})
+
+	t.Run("Deliveries", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		const secret = "secret"
+		payloads := make(chan codersdk.WebhookPayload, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, codersdk.WebhookSignature(secret, body), r.Header.Get(codersdk.WebhookSignatureHeader))
+			var payload codersdk.WebhookPayload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			payloads <- payload
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		created, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "cmdb",
+			URL:    srv.URL,
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated, codersdk.WebhookEventWorkspaceStarted},
+			Secret: secret,
+		})
+		require.NoError(t, err)
+		require.Equal(t, secret, created.Secret)
+
+		_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		var payload codersdk.WebhookPayload
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the webhook payload")
+		case payload = <-payloads:
+		}
+		require.Equal(t, codersdk.WebhookEventUserCreated, payload.Event)
+		require.NotNil(t, payload.User)
+		require.Equal(t, user.ID, payload.User.ID)
+		require.Equal(t, user.Username, payload.User.Username)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ApplyComplete,
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the webhook payload")
+		case payload = <-payloads:
+		}
+		require.Equal(t, codersdk.WebhookEventWorkspaceStarted, payload.Event)
+		require.NotNil(t, payload.Workspace)
+		require.Equal(t, workspace.ID, payload.Workspace.ID)
+		require.Equal(t, template.Name, payload.Workspace.TemplateName)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, payload.Workspace.Transition)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := client.WebhookDeliveries(ctx, created.Webhook.ID, codersdk.WebhookDeliveriesRequest{})
+			return assert.NoError(t, err) && len(deliveries) == 2 &&
+				deliveries[0].DeliveredAt != nil && deliveries[1].DeliveredAt != nil
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+}
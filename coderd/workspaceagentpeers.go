@@ -0,0 +1,275 @@
+package coderd
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Get workspace agent peers
+// @ID get-workspace-agent-peers
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {array} agentsdk.Peer
+// @Router /workspaceagents/me/peers [get]
+func (api *API) workspaceAgentPeers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	if !api.workspaceNetworkingEnabled(ctx, rw) {
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	// The agent can only read its own workspace, so peers are looked up
+	// as the system after the owners that may be dialed are resolved.
+	//nolint:gocritic // Access is limited by workspaceAgentPeerOwners.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	owners, err := api.workspaceAgentPeerOwners(sysCtx, workspace)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	ownerIDs := make([]uuid.UUID, 0, len(owners))
+	for ownerID := range owners {
+		ownerIDs = append(ownerIDs, ownerID)
+	}
+	users, err := api.Database.GetUsersByIDs(sysCtx, ownerIDs)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	peers := make([]agentsdk.Peer, 0)
+	for _, user := range users {
+		workspaces, err := api.Database.GetWorkspaces(sysCtx, database.GetWorkspacesParams{
+			OwnerID: user.ID,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		for _, peerWorkspace := range workspaces {
+			if !workspaceAgentPeerAllowed(workspace, owners, peerWorkspace.OwnerID, peerWorkspace.OrganizationID) {
+				continue
+			}
+			agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(sysCtx, peerWorkspace.ID)
+			if err != nil {
+				httpapi.InternalServerError(rw, err)
+				return
+			}
+			for _, agent := range agents {
+				if agent.ID == workspaceAgent.ID {
+					continue
+				}
+				status := agent.Status(api.AgentInactiveDisconnectTimeout)
+				peers = append(peers, agentsdk.Peer{
+					AgentID:       agent.ID,
+					AgentName:     agent.Name,
+					WorkspaceID:   peerWorkspace.ID,
+					WorkspaceName: peerWorkspace.Name,
+					OwnerName:     user.Username,
+					Status:        codersdk.WorkspaceAgentStatus(status.Status),
+				})
+			}
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, peers)
+}
+
+// workspaceAgentPeerCoordinate accepts a WebSocket from a workspace agent
+// that dials another workspace agent, and serves it to the coordinator as a
+// client of that agent.
+//
+// @Summary Coordinate workspace agent peer
+// @ID coordinate-workspace-agent-peer
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 101
+// @Router /workspaceagents/me/peers/{workspaceagent}/coordinate [get]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPeerCoordinate(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	peerID, ok := httpmw.ParseUUIDParam(rw, r, "workspaceagent")
+	if !ok {
+		return
+	}
+	if !api.workspaceNetworkingEnabled(ctx, rw) {
+		return
+	}
+	if peerID == workspaceAgent.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspace agents cannot dial themselves.",
+		})
+		return
+	}
+
+	// Blocking non-browser connections applies to agents as well.
+	override := api.WorkspaceClientCoordinateOverride.Load()
+	if override != nil {
+		overrideFunc := *override
+		if overrideFunc != nil && overrideFunc(rw) {
+			return
+		}
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // Access is limited by workspaceAgentPeerOwners.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	owners, err := api.workspaceAgentPeerOwners(sysCtx, workspace)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	peerWorkspace, err := api.Database.GetWorkspaceByAgentID(sysCtx, peerID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	// Agents the dialing agent may not reach are reported as missing so
+	// their existence isn't leaked.
+	if peerWorkspace.Deleted || !workspaceAgentPeerAllowed(workspace, owners, peerWorkspace.OwnerID, peerWorkspace.OrganizationID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(sysCtx, peerWorkspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	inLatestBuild := false
+	for _, agent := range agents {
+		if agent.ID == peerID {
+			inLatestBuild = true
+			break
+		}
+	}
+	if !inLatestBuild {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	api.WebsocketWaitMutex.Lock()
+	api.WebsocketWaitGroup.Add(1)
+	api.WebsocketWaitMutex.Unlock()
+	defer api.WebsocketWaitGroup.Done()
+
+	conn, err := websocket.Accept(rw, r, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	ctx, wsNetConn := websocketNetConn(ctx, conn, websocket.MessageBinary)
+	defer wsNetConn.Close()
+
+	go httpapi.Heartbeat(ctx, conn)
+
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), peerID)
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, err.Error())
+		return
+	}
+}
+
+func (api *API) workspaceNetworkingEnabled(ctx context.Context, rw http.ResponseWriter) bool {
+	mode := codersdk.WorkspaceNetworking(api.DeploymentValues.WorkspaceNetworking.String())
+	if mode == codersdk.WorkspaceNetworkingOwner || mode == codersdk.WorkspaceNetworkingGroup {
+		return true
+	}
+	httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+		Message: "Workspace networking is disabled.",
+		Detail:  "Enable it with --workspace-networking.",
+	})
+	return false
+}
+
+// workspaceAgentPeerOwners returns the users whose workspace agents may be
+// dialed by the agents of the given workspace. The owner of the workspace is
+// always included; in group mode so are the members of every group the owner
+// shares in the workspace's organization.
+func (api *API) workspaceAgentPeerOwners(ctx context.Context, workspace database.Workspace) (map[uuid.UUID]struct{}, error) {
+	owners := map[uuid.UUID]struct{}{
+		workspace.OwnerID: {},
+	}
+	if codersdk.WorkspaceNetworking(api.DeploymentValues.WorkspaceNetworking.String()) != codersdk.WorkspaceNetworkingGroup {
+		return owners, nil
+	}
+
+	groups, err := api.Database.GetGroupsByOrganizationID(ctx, workspace.OrganizationID)
+	if err != nil {
+		return nil, xerrors.Errorf("get groups: %w", err)
+	}
+	for _, group := range groups {
+		// Everyone in the organization is a member of the "Everyone" group,
+		// which would make group mode the same as allowing the whole
+		// organization.
+		if group.ID == workspace.OrganizationID {
+			continue
+		}
+		members, err := api.Database.GetGroupMembers(ctx, group.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get group members: %w", err)
+		}
+		isMember := false
+		for _, member := range members {
+			if member.ID == workspace.OwnerID {
+				isMember = true
+				break
+			}
+		}
+		if !isMember {
+			continue
+		}
+		for _, member := range members {
+			owners[member.ID] = struct{}{}
+		}
+	}
+	return owners, nil
+}
+
+// workspaceAgentPeerAllowed reports whether agents of a workspace with the
+// given owner and organization may be dialed from the given workspace.
+// Workspaces of the same owner are always allowed, users added through a
+// shared group are only allowed within the same organization.
+func workspaceAgentPeerAllowed(workspace database.Workspace, owners map[uuid.UUID]struct{}, ownerID, organizationID uuid.UUID) bool {
+	if ownerID == workspace.OwnerID {
+		return true
+	}
+	if _, ok := owners[ownerID]; !ok {
+		return false
+	}
+	return organizationID == workspace.OrganizationID
+}
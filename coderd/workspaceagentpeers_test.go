@@ -0,0 +1,213 @@
+package coderd_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentsocket"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentPeers(t *testing.T) {
+	t.Parallel()
+
+	type peerWorkspace struct {
+		workspace  codersdk.Workspace
+		agentID    uuid.UUID
+		agentToken string
+	}
+	// createWorkspace creates a workspace for the user of userClient with a
+	// template of its own, so that every agent has a unique token.
+	createWorkspace := func(t *testing.T, client, userClient *codersdk.Client, orgID uuid.UUID) peerWorkspace {
+		t.Helper()
+		agentToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, orgID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(agentToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, orgID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, userClient, orgID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		workspace, err := client.Workspace(testutil.Context(t, testutil.WaitShort), workspace.ID)
+		require.NoError(t, err)
+		return peerWorkspace{
+			workspace:  workspace,
+			agentID:    workspace.LatestBuild.Resources[0].Agents[0].ID,
+			agentToken: agentToken,
+		}
+	}
+	agentClient := func(client *codersdk.Client, ws peerWorkspace) *agentsdk.Client {
+		c := agentsdk.New(client.URL)
+		c.SetSessionToken(ws.agentToken)
+		return c
+	}
+	newAPI := func(t *testing.T, mode codersdk.WorkspaceNetworking) (*codersdk.Client, database.Store, uuid.UUID) {
+		t.Helper()
+		dv := coderdtest.DeploymentValues(t)
+		err := dv.WorkspaceNetworking.Set(string(mode))
+		require.NoError(t, err)
+		client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues:         dv,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		return client, api.Database, user.OrganizationID
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client, _, orgID := newAPI(t, codersdk.WorkspaceNetworkingDisabled)
+		frontend := createWorkspace(t, client, client, orgID)
+		backend := createWorkspace(t, client, client, orgID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := agentClient(client, frontend).Peers(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = agentClient(client, frontend).ListenPeer(ctx, backend.agentID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		t.Parallel()
+		client, _, orgID := newAPI(t, codersdk.WorkspaceNetworkingOwner)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, orgID)
+		frontend := createWorkspace(t, client, client, orgID)
+		backend := createWorkspace(t, client, client, orgID)
+		other := createWorkspace(t, client, memberClient, orgID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		peers, err := agentClient(client, frontend).Peers(ctx)
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+		require.Equal(t, backend.agentID, peers[0].AgentID)
+		require.Equal(t, backend.workspace.Name, peers[0].WorkspaceName)
+		require.Equal(t, coderdtest.FirstUserParams.Username, peers[0].OwnerName)
+
+		conn, err := agentClient(client, frontend).ListenPeer(ctx, backend.agentID)
+		require.NoError(t, err)
+		_ = conn.Close()
+
+		// Agents of other users can't be dialed.
+		_, err = agentClient(client, frontend).ListenPeer(ctx, other.agentID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		_, err = agentClient(client, other).ListenPeer(ctx, frontend.agentID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		_, err = agentClient(client, frontend).ListenPeer(ctx, frontend.agentID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		t.Parallel()
+		client, db, orgID := newAPI(t, codersdk.WorkspaceNetworkingGroup)
+		firstUser, err := client.User(testutil.Context(t, testutil.WaitShort), codersdk.Me)
+		require.NoError(t, err)
+		teammateClient, teammate := coderdtest.CreateAnotherUser(t, client, orgID)
+		strangerClient, _ := coderdtest.CreateAnotherUser(t, client, orgID)
+		group := dbgen.Group(t, db, database.Group{OrganizationID: orgID})
+		dbgen.GroupMember(t, db, database.GroupMember{GroupID: group.ID, UserID: firstUser.ID})
+		dbgen.GroupMember(t, db, database.GroupMember{GroupID: group.ID, UserID: teammate.ID})
+
+		frontend := createWorkspace(t, client, client, orgID)
+		backend := createWorkspace(t, client, teammateClient, orgID)
+		stranger := createWorkspace(t, client, strangerClient, orgID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		peers, err := agentClient(client, frontend).Peers(ctx)
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+		require.Equal(t, backend.agentID, peers[0].AgentID)
+		require.Equal(t, teammate.Username, peers[0].OwnerName)
+
+		conn, err := agentClient(client, frontend).ListenPeer(ctx, backend.agentID)
+		require.NoError(t, err)
+		_ = conn.Close()
+
+		// Sharing the "Everyone" group isn't enough.
+		_, err = agentClient(client, frontend).ListenPeer(ctx, stranger.agentID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Dial", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("The agent socket only works on Linux and macOS.")
+		}
+		client, _, orgID := newAPI(t, codersdk.WorkspaceNetworkingOwner)
+		frontend := createWorkspace(t, client, client, orgID)
+		backend := createWorkspace(t, client, client, orgID)
+
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		socketPath := filepath.Join(t.TempDir(), "agent.sock")
+		for _, ws := range []peerWorkspace{frontend, backend} {
+			options := agent.Options{
+				Client: agentClient(client, ws),
+				Logger: logger.Named(ws.workspace.Name),
+			}
+			if ws.agentID == frontend.agentID {
+				options.SocketPath = socketPath
+			}
+			closer := agent.New(options)
+			t.Cleanup(func() {
+				_ = closer.Close()
+			})
+		}
+		coderdtest.AwaitWorkspaceAgents(t, client, frontend.workspace.ID)
+		coderdtest.AwaitWorkspaceAgents(t, client, backend.workspace.ID)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					_, _ = io.Copy(conn, conn)
+				}()
+			}
+		}()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		conn, err := agentsocket.NewClient(socketPath).DialPeer(ctx, backend.workspace.Name, uint16(listener.Addr().(*net.TCPAddr).Port))
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+		b := make([]byte, 4)
+		_, err = io.ReadFull(conn, b)
+		require.NoError(t, err)
+		require.Equal(t, "ping", string(b))
+	})
+}
@@ -0,0 +1,193 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace agent port shares
+// @ID get-workspace-agent-port-shares
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentPortShares
+// @Router /workspaces/{workspace}/port-share [get]
+func (api *API) workspaceAgentPortShares(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	shares, err := api.Database.ListWorkspaceAgentPortShares(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentPortShares{
+		Shares: convertPortShares(shares),
+	})
+}
+
+// @Summary Upsert workspace agent port share
+// @ID upsert-workspace-agent-port-share
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpsertWorkspaceAgentPortShareRequest true "Upsert port sharing level request"
+// @Success 200 {object} codersdk.WorkspaceAgentPortShare
+// @Router /workspaces/{workspace}/port-share [post]
+func (api *API) postWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.UpsertWorkspaceAgentPortShareRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if !req.ShareLevel.ValidPortShareLevel() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Port sharing level not allowed.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "share_level",
+				Detail: fmt.Sprintf("Must be %q or %q.", codersdk.WorkspaceAgentPortShareLevelAuthenticated, codersdk.WorkspaceAgentPortShareLevelPublic),
+			}},
+		})
+		return
+	}
+	if req.Port < 1 || req.Port > 65535 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid port.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "port",
+				Detail: "Must be between 1 and 65535.",
+			}},
+		})
+		return
+	}
+
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	maxLevel := codersdk.WorkspaceAgentPortShareLevel(template.MaxPortSharingLevel)
+	if req.ShareLevel.Exceeds(maxLevel) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Port sharing level not allowed.",
+			Detail:  fmt.Sprintf("The template %q only allows ports to be shared up to the %q level.", template.Name, maxLevel),
+		})
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	found := false
+	for _, agent := range agents {
+		if agent.Name == req.AgentName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent %q not found in the latest build of the workspace.", req.AgentName),
+		})
+		return
+	}
+
+	share, err := api.Database.UpsertWorkspaceAgentPortShare(ctx, database.UpsertWorkspaceAgentPortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+		ShareLevel:  database.AppSharingLevel(req.ShareLevel),
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertPortShare(share))
+}
+
+// @Summary Delete workspace agent port share
+// @ID delete-workspace-agent-port-share
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.DeleteWorkspaceAgentPortShareRequest true "Delete port sharing level request"
+// @Success 204
+// @Router /workspaces/{workspace}/port-share [delete]
+func (api *API) deleteWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.DeleteWorkspaceAgentPortShareRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	_, err := api.Database.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+				Message: fmt.Sprintf("Port %d of agent %q is not shared.", req.Port, req.AgentName),
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	err = api.Database.DeleteWorkspaceAgentPortShare(ctx, database.DeleteWorkspaceAgentPortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+func convertPortShares(shares []database.WorkspaceAgentPortShare) []codersdk.WorkspaceAgentPortShare {
+	converted := make([]codersdk.WorkspaceAgentPortShare, 0, len(shares))
+	for _, share := range shares {
+		converted = append(converted, convertPortShare(share))
+	}
+	return converted
+}
+
+func convertPortShare(share database.WorkspaceAgentPortShare) codersdk.WorkspaceAgentPortShare {
+	return codersdk.WorkspaceAgentPortShare{
+		WorkspaceID: share.WorkspaceID,
+		AgentName:   share.AgentName,
+		Port:        share.Port,
+		ShareLevel:  codersdk.WorkspaceAgentPortShareLevel(share.ShareLevel),
+	}
+}
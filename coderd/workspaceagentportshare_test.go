@@ -0,0 +1,206 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentPortShare(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Template, codersdk.Workspace) {
+		t.Helper()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		return client, template, workspace
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, template, workspace := setup(t)
+
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelPublic),
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceAgentPortShareLevelPublic, template.MaxPortShareLevel)
+
+		share, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  "example",
+			Port:       3000,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceAgentPortShare{
+			WorkspaceID: workspace.ID,
+			AgentName:   "example",
+			Port:        3000,
+			ShareLevel:  codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+		}, share)
+
+		// Sharing the same port again updates the level.
+		_, err = client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  "example",
+			Port:       3000,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+		})
+		require.NoError(t, err)
+
+		shares, err := client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares.Shares, 1)
+		require.Equal(t, codersdk.WorkspaceAgentPortShareLevelPublic, shares.Shares[0].ShareLevel)
+
+		err = client.DeleteWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.DeleteWorkspaceAgentPortShareRequest{
+			AgentName: "example",
+			Port:      3000,
+		})
+		require.NoError(t, err)
+
+		shares, err = client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares.Shares)
+
+		err = client.DeleteWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.DeleteWorkspaceAgentPortShareRequest{
+			AgentName: "example",
+			Port:      3000,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, template, workspace := setup(t)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelPublic),
+		})
+		require.NoError(t, err)
+
+		for _, req := range []codersdk.UpsertWorkspaceAgentPortShareRequest{
+			{AgentName: "example", Port: 3000, ShareLevel: codersdk.WorkspaceAgentPortShareLevelOwner},
+			{AgentName: "example", Port: 3000, ShareLevel: "everyone"},
+			{AgentName: "example", Port: 0, ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic},
+			{AgentName: "example", Port: 65536, ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic},
+			{AgentName: "doesnotexist", Port: 3000, ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic},
+		} {
+			_, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, "%+v", req)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), "%+v", req)
+		}
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevel("everyone")),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("TemplateMax", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, template, workspace := setup(t)
+
+		// Ports can only be shared with the owner by default.
+		require.Equal(t, codersdk.WorkspaceAgentPortShareLevelOwner, template.MaxPortShareLevel)
+		_, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  "example",
+			Port:       3000,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelPublic),
+		})
+		require.NoError(t, err)
+		for _, port := range []int32{3000, 3001} {
+			_, err = client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+				AgentName:  "example",
+				Port:       port,
+				ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+			})
+			require.NoError(t, err)
+		}
+
+		// Lowering the maximum reduces the level of existing shares.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelAuthenticated),
+		})
+		require.NoError(t, err)
+		shares, err := client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares.Shares, 2)
+		for _, share := range shares.Shares {
+			require.Equal(t, codersdk.WorkspaceAgentPortShareLevelAuthenticated, share.ShareLevel)
+		}
+
+		// Leaving the maximum out of the request keeps it as is.
+		template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "no port sharing changes",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceAgentPortShareLevelAuthenticated, template.MaxPortShareLevel)
+
+		// Lowering it to owner removes the shares entirely.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelOwner),
+		})
+		require.NoError(t, err)
+		shares, err = client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares.Shares)
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, template, workspace := setup(t)
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, template.OrganizationID)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelPublic),
+		})
+		require.NoError(t, err)
+
+		_, err = otherClient.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  "example",
+			Port:       3000,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,404 @@
+package coderd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"nhooyr.io/websocket"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get reconnecting PTY sessions of workspace agent
+// @ID get-reconnecting-pty-sessions-of-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentReconnectingPTYSessionsResponse
+// @Router /workspaceagents/{workspaceagent}/pty-sessions [get]
+func (api *API) workspaceAgentPTYSessions(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	// Sessions expose the commands run in the workspace, so only users that
+	// can open a terminal may list them.
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	agentConn, release, ok := api.dialConnectedWorkspaceAgent(ctx, rw, workspaceAgent)
+	if !ok {
+		return
+	}
+	defer release()
+
+	sessions, err := agentConn.ReconnectingPTYSessions(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching reconnecting PTY sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sessions)
+}
+
+// @Summary Get reconnecting PTY session invites
+// @ID get-reconnecting-pty-session-invites
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param session path string true "Reconnecting PTY session ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAgentPTYInvite
+// @Router /workspaceagents/{workspaceagent}/pty-sessions/{session}/invites [get]
+func (api *API) workspaceAgentPTYInvites(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	sessionID, ok := httpmw.ParseUUIDParam(rw, r, "session")
+	if !ok {
+		return
+	}
+
+	invites, err := api.Database.GetWorkspaceAgentPTYInvitesBySessionID(ctx, database.GetWorkspaceAgentPTYInvitesBySessionIDParams{
+		AgentID:   workspaceAgent.ID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertPTYInvites(invites))
+}
+
+// @Summary Invite user to reconnecting PTY session
+// @ID invite-user-to-reconnecting-pty-session
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param session path string true "Reconnecting PTY session ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceAgentPTYInviteRequest true "Invite request"
+// @Success 201 {object} codersdk.WorkspaceAgentPTYInvite
+// @Router /workspaceagents/{workspaceagent}/pty-sessions/{session}/invites [post]
+func (api *API) postWorkspaceAgentPTYInvite(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	sessionID, ok := httpmw.ParseUUIDParam(rw, r, "session")
+	if !ok {
+		return
+	}
+
+	var req codersdk.CreateWorkspaceAgentPTYInviteRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.UserID == workspace.OwnerID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The workspace owner does not need an invite.",
+		})
+		return
+	}
+	// The invited user is usually not visible to the workspace owner, so
+	// only check that the user exists.
+	//nolint:gocritic // Checking the user exists is not leaking any data.
+	_, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), req.UserID)
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("User %q does not exist.", req.UserID),
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	invite, err := api.Database.UpsertWorkspaceAgentPTYInvite(ctx, database.UpsertWorkspaceAgentPTYInviteParams{
+		WorkspaceID: workspace.ID,
+		AgentID:     workspaceAgent.ID,
+		SessionID:   sessionID,
+		UserID:      req.UserID,
+		ReadOnly:    req.ReadOnly,
+		CreatedBy:   apiKey.UserID,
+		CreatedAt:   dbtime.Now(),
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertPTYInvite(invite))
+}
+
+// @Summary Delete reconnecting PTY session invite
+// @ID delete-reconnecting-pty-session-invite
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param session path string true "Reconnecting PTY session ID" format(uuid)
+// @Param user path string true "User ID" format(uuid)
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/pty-sessions/{session}/invites/{user} [delete]
+func (api *API) deleteWorkspaceAgentPTYInvite(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	sessionID, ok := httpmw.ParseUUIDParam(rw, r, "session")
+	if !ok {
+		return
+	}
+	userID, ok := httpmw.ParseUUIDParam(rw, r, "user")
+	if !ok {
+		return
+	}
+
+	_, err := api.Database.GetWorkspaceAgentPTYInvite(ctx, database.GetWorkspaceAgentPTYInviteParams{
+		AgentID:   workspaceAgent.ID,
+		SessionID: sessionID,
+		UserID:    userID,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	err = api.Database.DeleteWorkspaceAgentPTYInvite(ctx, database.DeleteWorkspaceAgentPTYInviteParams{
+		AgentID:   workspaceAgent.ID,
+		SessionID: sessionID,
+		UserID:    userID,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get reconnecting PTY session invites of user
+// @ID get-reconnecting-pty-session-invites-of-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.WorkspaceAgentPTYInvite
+// @Router /users/{user}/pty-invites [get]
+func (api *API) userWorkspaceAgentPTYInvites(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	invites, err := api.Database.GetWorkspaceAgentPTYInvitesByUserID(ctx, user.ID)
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertPTYInvites(invites))
+}
+
+// workspaceAgentPTYSessionAttach attaches to an existing reconnecting PTY
+// session. Users other than the workspace owner need an invite, and every
+// attach is audited.
+//
+// @Summary Attach to reconnecting PTY session
+// @ID attach-to-reconnecting-pty-session
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param session path string true "Reconnecting PTY session ID" format(uuid)
+// @Param height query int false "Terminal height"
+// @Param width query int false "Terminal width"
+// @Param read_only query bool false "Attach without being able to write to the session"
+// @Success 101
+// @Router /workspaceagents/{workspaceagent}/pty-sessions/{session}/attach [get]
+func (api *API) workspaceAgentPTYSessionAttach(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	agentID, ok := httpmw.ParseUUIDParam(rw, r, "workspaceagent")
+	if !ok {
+		return
+	}
+	sessionID, ok := httpmw.ParseUUIDParam(rw, r, "session")
+	if !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	parser := httpapi.NewQueryParamParser()
+	height := parser.UInt(values, 80, "height")
+	width := parser.UInt(values, 80, "width")
+	readOnly := parser.Boolean(values, false, "read_only")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	// Invited users can't read the workspace, so the agent and workspace are
+	// fetched as the system and access is checked below.
+	//nolint:gocritic // Access is checked below.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	workspaceAgent, err := api.Database.GetWorkspaceAgentByID(sysCtx, agentID)
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	workspace, err := api.Database.GetWorkspaceByAgentID(sysCtx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		invite, err := api.Database.GetWorkspaceAgentPTYInvite(sysCtx, database.GetWorkspaceAgentPTYInviteParams{
+			AgentID:   workspaceAgent.ID,
+			SessionID: sessionID,
+			UserID:    apiKey.UserID,
+		})
+		if err != nil {
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		readOnly = readOnly || invite.ReadOnly
+	}
+
+	additionalFields, err := json.Marshal(ptySessionAuditFields{
+		AgentName: workspaceAgent.Name,
+		SessionID: sessionID,
+		ReadOnly:  readOnly,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	agentConn, release, ok := api.dialConnectedWorkspaceAgent(ctx, rw, workspaceAgent)
+	if !ok {
+		return
+	}
+	defer release()
+
+	opts := []codersdk.AgentReconnectingPTYInitOption{codersdk.AgentReconnectingPTYInitAttachOnly()}
+	if readOnly {
+		opts = append(opts, codersdk.AgentReconnectingPTYInitReadOnly())
+	}
+	ptNetConn, err := agentConn.ReconnectingPTY(ctx, sessionID, uint16(height), uint16(width), "", opts...)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing reconnecting PTY session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer ptNetConn.Close()
+
+	conn, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Record the attach now rather than when the session ends, which can be
+	// hours later.
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionConnect,
+		AdditionalFields: additionalFields,
+	})
+	aReq.Old = workspace
+	aReq.New = workspace
+	commitAudit()
+
+	ctx, wsNetConn := workspaceapps.WebsocketNetConn(ctx, conn, websocket.MessageBinary)
+	defer wsNetConn.Close() // Also closes conn.
+
+	go httpapi.Heartbeat(ctx, conn)
+
+	api.Logger.Debug(ctx, "attached to reconnecting pty session",
+		slog.F("agent_id", workspaceAgent.ID),
+		slog.F("session_id", sessionID),
+		slog.F("read_only", readOnly),
+	)
+	agentssh.Bicopy(ctx, wsNetConn, ptNetConn)
+}
+
+// ptySessionAuditFields are the additional fields of the audit log written
+// when attaching to a reconnecting PTY session.
+type ptySessionAuditFields struct {
+	AgentName string    `json:"agent_name"`
+	SessionID uuid.UUID `json:"session_id"`
+	ReadOnly  bool      `json:"read_only"`
+}
+
+func convertPTYInvites(invites []database.WorkspaceAgentPTYInvite) []codersdk.WorkspaceAgentPTYInvite {
+	converted := make([]codersdk.WorkspaceAgentPTYInvite, 0, len(invites))
+	for _, invite := range invites {
+		converted = append(converted, convertPTYInvite(invite))
+	}
+	return converted
+}
+
+func convertPTYInvite(invite database.WorkspaceAgentPTYInvite) codersdk.WorkspaceAgentPTYInvite {
+	return codersdk.WorkspaceAgentPTYInvite{
+		WorkspaceID: invite.WorkspaceID,
+		AgentID:     invite.AgentID,
+		SessionID:   invite.SessionID,
+		UserID:      invite.UserID,
+		ReadOnly:    invite.ReadOnly,
+		CreatedBy:   invite.CreatedBy,
+		CreatedAt:   invite.CreatedAt,
+	}
+}
@@ -0,0 +1,173 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentPTYSessions(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Auditor:                  auditor,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	guest, guestUser := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	stranger, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	sessionID := uuid.New()
+	owner, err := client.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+		AgentID:   agentID,
+		Reconnect: sessionID,
+		Width:     80,
+		Height:    80,
+		Command:   "bash",
+	})
+	require.NoError(t, err)
+	defer owner.Close()
+
+	require.Eventually(t, func() bool {
+		sessions, err := client.WorkspaceAgentReconnectingPTYSessions(ctx, agentID)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return len(sessions.Sessions) == 1 && sessions.Sessions[0].ID == sessionID
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// Other users can't list the sessions of the workspace.
+	_, err = guest.WorkspaceAgentReconnectingPTYSessions(ctx, agentID)
+	require.Error(t, err)
+
+	// Users need an invite to attach.
+	var apiErr *codersdk.Error
+	_, err = stranger.AttachWorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYAttachOpts{
+		AgentID:   agentID,
+		SessionID: sessionID,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	invite, err := client.CreateWorkspaceAgentPTYInvite(ctx, agentID, sessionID, codersdk.CreateWorkspaceAgentPTYInviteRequest{
+		UserID:   guestUser.ID,
+		ReadOnly: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, workspace.ID, invite.WorkspaceID)
+	require.True(t, invite.ReadOnly)
+
+	invites, err := guest.UserWorkspaceAgentPTYInvites(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceAgentPTYInvite{invite}, invites)
+
+	// The owner does not need an invite.
+	_, err = client.CreateWorkspaceAgentPTYInvite(ctx, agentID, sessionID, codersdk.CreateWorkspaceAgentPTYInviteRequest{
+		UserID: user.UserID,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// The invite is read-only, so asking for write access is ignored.
+	reader, err := guest.AttachWorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYAttachOpts{
+		AgentID:   agentID,
+		SessionID: sessionID,
+		Width:     80,
+		Height:    80,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	// Brief pause to reduce the likelihood that we send keystrokes while
+	// the shell is simultaneously sending a prompt.
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+		Data: "echo readonly\r\n",
+	})
+	require.NoError(t, err)
+	_, err = reader.Write(data)
+	require.NoError(t, err)
+	data, err = json.Marshal(codersdk.ReconnectingPTYRequest{
+		Data: "echo test\r\n",
+	})
+	require.NoError(t, err)
+	_, err = owner.Write(data)
+	require.NoError(t, err)
+
+	sawReadOnlyInput := false
+	matchEchoOutput := func(line string) bool {
+		if strings.Contains(line, "readonly") {
+			sawReadOnlyInput = true
+		}
+		return strings.Contains(line, "test") && !strings.Contains(line, "echo")
+	}
+	require.NoError(t, testutil.ReadUntil(ctx, t, reader, matchEchoOutput), "find echo output")
+	require.False(t, sawReadOnlyInput, "read-only input was written to the pty")
+
+	require.Eventually(t, func() bool {
+		for _, log := range auditor.AuditLogs() {
+			if log.Action == database.AuditActionConnect &&
+				log.UserID == guestUser.ID &&
+				log.ResourceID == workspace.ID {
+				return true
+			}
+		}
+		return false
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	err = client.DeleteWorkspaceAgentPTYInvite(ctx, agentID, sessionID, guestUser.ID)
+	require.NoError(t, err)
+	_, err = guest.AttachWorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYAttachOpts{
+		AgentID:   agentID,
+		SessionID: sessionID,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -64,8 +64,26 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	// nolint:gocritic // GetWorkspaceAgentScriptsByAgentIDs is a system function.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// nolint:gocritic // GetWorkspaceAgentLogSourcesByAgentIDs is a system function.
+	logSources, err := api.Database.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent log sources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout,
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), convertScripts(scripts), convertLogSources(logSources), api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -90,7 +108,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -118,6 +136,26 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// nolint:gocritic // GetWorkspaceAgentScriptsByAgentIDs is a system function.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// nolint:gocritic // GetWorkspaceAgentLogFilesByAgentID is a system function.
+	logFiles, err := api.Database.GetWorkspaceAgentLogFilesByAgentID(dbauthz.AsSystemRestricted(ctx), workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent log files.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -150,6 +188,17 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	// nolint:gocritic // The agent only needs to know whether the template
+	// records sessions, the workspace owner may not be able to read it.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	recordSessions := api.DeploymentValues.SessionRecording.Enable.Value() || template.RecordSessions
 
 	vscodeProxyURI := strings.ReplaceAll(api.AppHostname, "*",
 		fmt.Sprintf("%s://{{port}}--%s--%s--%s",
@@ -164,20 +213,25 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 
 	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.Manifest{
 		AgentID:                  apiAgent.ID,
+		AgentName:                workspaceAgent.Name,
+		OwnerName:                owner.Username,
+		WorkspaceID:              workspace.ID,
+		WorkspaceName:            workspace.Name,
 		Apps:                     convertApps(dbApps),
 		DERPMap:                  api.DERPMap(),
 		DERPForceWebSockets:      api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
 		GitAuthConfigs:           len(api.GitAuthConfigs),
 		EnvironmentVariables:     apiAgent.EnvironmentVariables,
-		StartupScript:            apiAgent.StartupScript,
 		Directory:                apiAgent.Directory,
 		VSCodePortProxyURI:       vscodeProxyURI,
 		MOTDFile:                 workspaceAgent.MOTDFile,
-		StartupScriptTimeout:     time.Duration(apiAgent.StartupScriptTimeoutSeconds) * time.Second,
-		ShutdownScript:           apiAgent.ShutdownScript,
-		ShutdownScriptTimeout:    time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		Metadata:                 convertWorkspaceAgentMetadataDesc(metadata),
+		Scripts:                  convertScripts(scripts),
+		LogFiles:                 convertLogFiles(logFiles),
+		RecordSessions:           recordSessions,
+		RecordSessionInput:       recordSessions && api.DeploymentValues.SessionRecording.RecordInput.Value(),
+		DisableAutoUpdate:        api.DeploymentValues.DisableAgentAutoUpdate.Value(),
 	})
 }
 
@@ -195,7 +249,7 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -263,6 +317,10 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 	httpapi.Write(ctx, rw, http.StatusOK, nil)
 }
 
+// maxLogFileLogsLength is the length of the newest lines kept of each file the
+// agent tails.
+const maxLogFileLogsLength = 256 << 10
+
 // @Summary Patch workspace agent logs
 // @ID patch-workspace-agent-logs
 // @Security CoderSessionToken
@@ -289,7 +347,6 @@ func (api *API) patchWorkspaceAgentLogs(rw http.ResponseWriter, r *http.Request)
 	createdAt := make([]time.Time, 0)
 	output := make([]string, 0)
 	level := make([]database.LogLevel, 0)
-	source := make([]database.WorkspaceAgentLogSource, 0)
 	outputLength := 0
 	for _, logEntry := range req.Logs {
 		createdAt = append(createdAt, logEntry.CreatedAt)
@@ -308,20 +365,51 @@ func (api *API) patchWorkspaceAgentLogs(rw http.ResponseWriter, r *http.Request)
 			return
 		}
 		level = append(level, parsedLevel)
+	}
 
-		if logEntry.Source == "" {
-			// Default to "startup_script" to support older agents that didn't have the source field.
-			logEntry.Source = codersdk.WorkspaceAgentLogSourceStartupScript
+	logSourceID := req.LogSourceID
+	if logSourceID == uuid.Nil {
+		// Older agents and external tools don't specify a log source, so
+		// their logs are attributed to the "External" log source. It's
+		// created on first use.
+		// nolint:gocritic // InsertWorkspaceAgentLogSources is a system function.
+		_, err := api.Database.InsertWorkspaceAgentLogSources(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceAgentLogSourcesParams{
+			WorkspaceAgentID: workspaceAgent.ID,
+			CreatedAt:        dbtime.Now(),
+			ID:               []uuid.UUID{agentsdk.ExternalLogSourceID},
+			DisplayName:      []string{"External"},
+			Icon:             []string{"/emojis/1f310.png"},
+		})
+		if err != nil && !database.IsUniqueViolation(err) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to create external log source.",
+				Detail:  err.Error(),
+			})
+			return
 		}
-		parsedSource := database.WorkspaceAgentLogSource(logEntry.Source)
-		if !parsedSource.Valid() {
-			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-				Message: "Invalid log source provided.",
-				Detail:  fmt.Sprintf("invalid log source: %q", logEntry.Source),
+		logSourceID = agentsdk.ExternalLogSourceID
+	}
+
+	// Lines of the files the agent tails don't count toward the logs limit,
+	// so a busy file can't keep the scripts from sending logs. Only the
+	// newest lines of each file are kept instead.
+	isLogFile := false
+	if logSourceID != agentsdk.ExternalLogSourceID {
+		// nolint:gocritic // GetWorkspaceAgentLogFilesByAgentID is a system function.
+		logFiles, err := api.Database.GetWorkspaceAgentLogFilesByAgentID(dbauthz.AsSystemRestricted(ctx), workspaceAgent.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace agent log files.",
+				Detail:  err.Error(),
 			})
 			return
 		}
-		source = append(source, parsedSource)
+		isLogFile = slices.ContainsFunc(logFiles, func(logFile database.WorkspaceAgentLogFile) bool {
+			return logFile.LogSourceID == logSourceID
+		})
+	}
+	if isLogFile {
+		outputLength = 0
 	}
 
 	logs, err := api.Database.InsertWorkspaceAgentLogs(ctx, database.InsertWorkspaceAgentLogsParams{
@@ -329,7 +417,7 @@ func (api *API) patchWorkspaceAgentLogs(rw http.ResponseWriter, r *http.Request)
 		CreatedAt:    createdAt,
 		Output:       output,
 		Level:        level,
-		Source:       source,
+		LogSourceID:  logSourceID,
 		OutputLength: int32(outputLength),
 	})
 	if err != nil {
@@ -384,6 +472,19 @@ func (api *API) patchWorkspaceAgentLogs(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if isLogFile {
+		// nolint:gocritic // DeleteOldWorkspaceAgentLogsBySourceID is a system function.
+		err = api.Database.DeleteOldWorkspaceAgentLogsBySourceID(dbauthz.AsSystemRestricted(ctx), database.DeleteOldWorkspaceAgentLogsBySourceIDParams{
+			AgentID:     workspaceAgent.ID,
+			LogSourceID: logSourceID,
+			MaxLength:   maxLogFileLogsLength,
+		})
+		if err != nil {
+			// The lines are trimmed again with the next lines of the file.
+			api.Logger.Warn(ctx, "failed to delete old workspace agent log file lines", slog.Error(err))
+		}
+	}
+
 	lowestLogID := logs[0].ID
 
 	// Publish by the lowest log ID inserted so the
@@ -392,7 +493,7 @@ func (api *API) patchWorkspaceAgentLogs(rw http.ResponseWriter, r *http.Request)
 		CreatedAfter: lowestLogID - 1,
 	})
 
-	if workspaceAgent.LogsLength == 0 {
+	if workspaceAgent.LogsLength == 0 && !isLogFile {
 		// If these are the first logs being appended, we publish a UI update
 		// to notify the UI that logs are now available.
 		resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
@@ -655,30 +756,8 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgentParam(r)
 
-	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
-		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
-	)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error reading workspace agent.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
-		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
-		})
-		return
-	}
-
-	agentConn, release, err := api.agentProvider.AgentConn(ctx, workspaceAgent.ID)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error dialing workspace agent.",
-			Detail:  err.Error(),
-		})
+	agentConn, release, ok := api.dialConnectedWorkspaceAgent(ctx, rw, workspaceAgent)
+	if !ok {
 		return
 	}
 	defer release()
@@ -748,6 +827,38 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, portsResponse)
 }
 
+// dialConnectedWorkspaceAgent dials the workspace agent, writing an error
+// response if it is not connected.
+func (api *API) dialConnectedWorkspaceAgent(ctx context.Context, rw http.ResponseWriter, workspaceAgent database.WorkspaceAgent) (*codersdk.WorkspaceAgentConn, func(), bool) {
+	apiAgent, err := convertWorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return nil, nil, false
+	}
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	return agentConn, release, true
+}
+
 // Deprecated: use api.tailnet.AgentConn instead.
 // See: https://github.com/coder/coder/issues/8218
 func (api *API) _dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
@@ -1272,8 +1383,16 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 
 	go httpapi.Heartbeat(ctx, conn)
 
+	// The coordinator remembers the user behind the client, so that the
+	// connections reported by the agent are attributed to them. Workspace
+	// proxies coordinate on behalf of many users, so they have no user.
+	var userID uuid.UUID
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok {
+		userID = apiKey.UserID
+	}
+
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+	err = (*api.TailnetCoordinator.Load()).ServeUserClient(wsNetConn, uuid.New(), workspaceAgent.ID, userID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
@@ -1295,6 +1414,8 @@ func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 			SharingLevel: codersdk.WorkspaceAppSharingLevel(dbApp.SharingLevel),
 			Healthcheck: codersdk.Healthcheck{
 				URL:       dbApp.HealthcheckUrl,
+				TCP:       dbApp.HealthcheckTCP,
+				Command:   dbApp.HealthcheckCommand,
 				Interval:  dbApp.HealthcheckInterval,
 				Threshold: dbApp.HealthcheckThreshold,
 			},
@@ -1318,7 +1439,60 @@ func convertWorkspaceAgentMetadataDesc(mds []database.WorkspaceAgentMetadatum) [
 	return metadata
 }
 
-func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
+func convertLogSources(dbLogSources []database.WorkspaceAgentLogSource) []codersdk.WorkspaceAgentLogSource {
+	logSources := make([]codersdk.WorkspaceAgentLogSource, 0)
+	for _, dbLogSource := range dbLogSources {
+		logSources = append(logSources, codersdk.WorkspaceAgentLogSource{
+			ID:               dbLogSource.ID,
+			DisplayName:      dbLogSource.DisplayName,
+			WorkspaceAgentID: dbLogSource.WorkspaceAgentID,
+			CreatedAt:        dbLogSource.CreatedAt,
+			Icon:             dbLogSource.Icon,
+		})
+	}
+	return logSources
+}
+
+func convertLogFiles(dbLogFiles []database.WorkspaceAgentLogFile) []codersdk.WorkspaceAgentLogFile {
+	logFiles := make([]codersdk.WorkspaceAgentLogFile, 0, len(dbLogFiles))
+	for _, dbLogFile := range dbLogFiles {
+		logFiles = append(logFiles, codersdk.WorkspaceAgentLogFile{
+			ID:          dbLogFile.ID,
+			LogSourceID: dbLogFile.LogSourceID,
+			Path:        dbLogFile.Path,
+		})
+	}
+	return logFiles
+}
+
+func convertScripts(dbScripts []database.WorkspaceAgentScript) []codersdk.WorkspaceAgentScript {
+	scripts := make([]codersdk.WorkspaceAgentScript, 0)
+	for _, dbScript := range dbScripts {
+		script := codersdk.WorkspaceAgentScript{
+			ID:               dbScript.ID,
+			LogPath:          dbScript.LogPath,
+			Script:           dbScript.Script,
+			Cron:             dbScript.Cron,
+			LogSourceID:      dbScript.LogSourceID,
+			RunOnStart:       dbScript.RunOnStart,
+			RunOnStop:        dbScript.RunOnStop,
+			StartBlocksLogin: dbScript.StartBlocksLogin,
+			Timeout:          time.Duration(dbScript.TimeoutSeconds) * time.Second,
+			Status:           codersdk.WorkspaceAgentScriptStatus(dbScript.Status),
+			ExitCode:         dbScript.ExitCode,
+		}
+		if dbScript.StartedAt.Valid {
+			script.StartedAt = &dbScript.StartedAt.Time
+		}
+		if dbScript.EndedAt.Valid {
+			script.EndedAt = &dbScript.EndedAt.Time
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts
+}
+
+func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, scripts []codersdk.WorkspaceAgentScript, logSources []codersdk.WorkspaceAgentLogSource, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
 	var envs map[string]string
 	if dbAgent.EnvironmentVariables.Valid {
 		err := json.Unmarshal(dbAgent.EnvironmentVariables.RawMessage, &envs)
@@ -1362,6 +1536,10 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		ShutdownScriptTimeoutSeconds: dbAgent.ShutdownScriptTimeoutSeconds,
 		Subsystems:                   subsystems,
 		DisplayApps:                  convertDisplayApps(dbAgent.DisplayApps),
+		Scripts:                      scripts,
+		LogSources:                   logSources,
+		UpdateState:                  codersdk.WorkspaceAgentUpdateState(dbAgent.UpdateState),
+		UpdateError:                  dbAgent.UpdateError,
 	}
 	node := coordinator.Node(dbAgent.ID)
 	if node != nil {
@@ -1395,6 +1573,18 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 	workspaceAgent.LastConnectedAt = status.LastConnectedAt
 	workspaceAgent.DisconnectedAt = status.DisconnectedAt
 
+	workspaceAgent.AutostopInhibitors = []codersdk.WorkspaceAgentAutostopInhibitor{}
+	// Inhibitors reported by an agent that is gone aren't held anymore.
+	if workspaceAgent.Status == codersdk.WorkspaceAgentConnected {
+		for _, inhibitor := range dbAgent.AutostopInhibitors {
+			workspaceAgent.AutostopInhibitors = append(workspaceAgent.AutostopInhibitors, codersdk.WorkspaceAgentAutostopInhibitor{
+				ID:        inhibitor.ID,
+				Reason:    inhibitor.Reason,
+				CreatedAt: inhibitor.CreatedAt,
+			})
+		}
+	}
+
 	if dbAgent.StartedAt.Valid {
 		workspaceAgent.StartedAt = &dbAgent.StartedAt.Time
 	}
@@ -1477,7 +1667,9 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		slog.F("payload", req),
 	)
 
-	if req.ConnectionCount > 0 {
+	// Held autostop inhibitors count as activity so the deadline keeps
+	// being extended while they are.
+	if req.ConnectionCount > 0 || len(workspaceAgent.AutostopInhibitors) > 0 {
 		activityBumpWorkspace(ctx, api.Logger.Named("activity_bump"), api.Database, workspace.ID)
 	}
 
@@ -1855,6 +2047,250 @@ func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Re
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// @Summary Submit workspace agent autostop inhibitors
+// @ID submit-workspace-agent-autostop-inhibitors
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostAutostopInhibitorsRequest true "Workspace agent autostop inhibitors request"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/autostop-inhibitors [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostAutostopInhibitors(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostAutostopInhibitorsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	inhibitors := make(database.AutostopInhibitors, 0, len(req.Inhibitors))
+	for _, inhibitor := range req.Inhibitors {
+		if inhibitor.ID == uuid.Nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Autostop inhibitors must have an ID.",
+			})
+			return
+		}
+		inhibitors = append(inhibitors, database.AutostopInhibitor{
+			ID:        inhibitor.ID,
+			Reason:    inhibitor.Reason,
+			CreatedAt: inhibitor.CreatedAt,
+		})
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.UpdateWorkspaceAgentAutostopInhibitorsByID(ctx, database.UpdateWorkspaceAgentAutostopInhibitorsByIDParams{
+		ID:                 workspaceAgent.ID,
+		AutostopInhibitors: inhibitors,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	// The deadline is bumped again on every stats report while an inhibitor
+	// is held, so the workspace stays up until it is released.
+	if len(inhibitors) > 0 {
+		activityBumpWorkspace(ctx, api.Logger.Named("activity_bump"), api.Database, workspace.ID)
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Submit workspace agent update state
+// @ID submit-workspace-agent-update-state
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostUpdateStateRequest true "Workspace agent update state request"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/update-state [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostUpdateState(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostUpdateStateRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	dbUpdateState := database.WorkspaceAgentUpdateState(req.State)
+	if !dbUpdateState.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid update state.",
+			Detail:  fmt.Sprintf("Invalid update state %q, must be be one of %q.", req.State, database.AllWorkspaceAgentUpdateStateValues()),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.UpdateWorkspaceAgentUpdateStateByID(ctx, database.UpdateWorkspaceAgentUpdateStateByIDParams{
+		ID:          workspaceAgent.ID,
+		UpdateState: dbUpdateState,
+		UpdateError: req.Error,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Submit workspace agent script status
+// @ID submit-workspace-agent-script-status
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostScriptStatusRequest true "Workspace agent script status request"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/script-status [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostScriptStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostScriptStatusRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	status := database.WorkspaceAgentScriptStatus(req.Status)
+	if !status.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid script status.",
+			Detail:  fmt.Sprintf("Invalid script status %q, must be one of %q.", req.Status, database.AllWorkspaceAgentScriptStatusValues()),
+		})
+		return
+	}
+
+	script, err := api.Database.GetWorkspaceAgentScriptByID(ctx, req.ScriptID)
+	if httpapi.Is404Error(err) || (err == nil && script.WorkspaceAgentID != workspaceAgent.ID) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Script %q does not belong to this agent.", req.ScriptID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent script.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	endedAt := sql.NullTime{}
+	if req.EndedAt != nil {
+		endedAt = sql.NullTime{Time: *req.EndedAt, Valid: true}
+	}
+	err = api.Database.UpdateWorkspaceAgentScriptStatusByID(ctx, database.UpdateWorkspaceAgentScriptStatusByIDParams{
+		ID:        script.ID,
+		Status:    status,
+		ExitCode:  req.ExitCode,
+		StartedAt: sql.NullTime{Time: req.StartedAt, Valid: !req.StartedAt.IsZero()},
+		EndedAt:   endedAt,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace agent script status.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Post workspace agent log source
+// @ID post-workspace-agent-log-source
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PostLogSource true "Log source request"
+// @Success 201 {object} codersdk.WorkspaceAgentLogSource
+// @Router /workspaceagents/me/log-source [post]
+func (api *API) workspaceAgentPostLogSource(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsdk.PostLogSource
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.ID == uuid.Nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A log source ID is required.",
+		})
+		return
+	}
+
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	// nolint:gocritic // InsertWorkspaceAgentLogSources is a system function.
+	sources, err := api.Database.InsertWorkspaceAgentLogSources(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceAgentLogSourcesParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		CreatedAt:        dbtime.Now(),
+		ID:               []uuid.UUID{req.ID},
+		DisplayName:      []string{req.DisplayName},
+		Icon:             []string{req.Icon},
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			httpapi.Write(ctx, rw, http.StatusCreated, codersdk.WorkspaceAgentLogSource{
+				WorkspaceAgentID: workspaceAgent.ID,
+				CreatedAt:        dbtime.Now(),
+				ID:               req.ID,
+				DisplayName:      req.DisplayName,
+				Icon:             req.Icon,
+			})
+			return
+		}
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	if len(sources) != 1 {
+		httpapi.InternalServerError(rw, xerrors.Errorf("database should've returned 1 row, got %d", len(sources)))
+		return
+	}
+
+	apiSource := convertLogSources(sources)[0]
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiSource)
+}
+
 // @Summary Submit workspace agent application health
 // @ID submit-workspace-agent-application-health
 // @Security CoderSessionToken
@@ -1907,7 +2343,7 @@ func (api *API) postWorkspaceAppHealth(rw http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		if old.HealthcheckUrl == "" {
+		if old.HealthcheckUrl == "" && old.HealthcheckTCP == "" && old.HealthcheckCommand == "" {
 			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
 				Message: "Error setting workspace app health",
 				Detail:  xerrors.Errorf("health checking is disabled for workspace app %s", id).Error(),
@@ -2228,6 +2664,7 @@ func convertWorkspaceAgentLog(logEntry database.WorkspaceAgentLog) codersdk.Work
 		CreatedAt: logEntry.CreatedAt,
 		Output:    logEntry.Output,
 		Level:     codersdk.LogLevel(logEntry.Level),
+		SourceID:  logEntry.LogSourceID,
 	}
 }
 
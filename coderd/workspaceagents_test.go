@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"runtime"
 	"strconv"
 	"strings"
@@ -28,6 +30,7 @@ import (
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/tailnet"
 	"github.com/coder/coder/v2/tailnet/tailnettest"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -481,6 +484,207 @@ func TestWorkspaceAgentStartupLogs(t *testing.T) {
 			}
 		}
 	})
+	t.Run("LogFilesDontOverflow", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:         echo.ParseComplete,
+			ProvisionPlan: echo.PlanComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{{
+							Name: "example",
+							Type: "aws_instance",
+							Agents: []*proto.Agent{{
+								Id: uuid.NewString(),
+								Auth: &proto.Agent_Token{
+									Token: authToken,
+								},
+								LogFiles: []*proto.LogFile{{
+									DisplayName: "App",
+									Path:        "/var/log/app.log",
+								}},
+							}},
+						}},
+					},
+				},
+			}},
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.Len(t, manifest.LogFiles, 1)
+		logSourceID := manifest.LogFiles[0].LogSourceID
+
+		// Send more lines of the file than the logs limit of the agent.
+		line := strings.Repeat("a", 1024)
+		for i := 0; i < 5; i++ {
+			lines := make([]agentsdk.Log, 256)
+			for j := range lines {
+				lines[j] = agentsdk.Log{CreatedAt: dbtime.Now(), Output: line}
+			}
+			err = agentClient.PatchLogs(ctx, agentsdk.PatchLogs{
+				LogSourceID: logSourceID,
+				Logs:        lines,
+			})
+			require.NoError(t, err)
+		}
+
+		// The scripts can still send logs.
+		err = agentClient.PatchLogs(ctx, agentsdk.PatchLogs{
+			Logs: []agentsdk.Log{{
+				CreatedAt: dbtime.Now(),
+				Output:    "script",
+			}},
+		})
+		require.NoError(t, err)
+
+		logs, closer, err := client.WorkspaceAgentLogsAfter(ctx, build.Resources[0].Agents[0].ID, 0, false)
+		require.NoError(t, err)
+		defer closer.Close()
+		var logFileLength int
+		var scriptLogs []string
+		for chunk := range logs {
+			for _, log := range chunk {
+				if log.SourceID == logSourceID {
+					logFileLength += len(log.Output)
+				} else {
+					scriptLogs = append(scriptLogs, log.Output)
+				}
+			}
+		}
+		// Only the newest lines of the file are kept.
+		require.Equal(t, 256<<10, logFileLength)
+		require.Equal(t, []string{"script"}, scriptLogs)
+
+		agent, err := client.WorkspaceAgent(ctx, build.Resources[0].Agents[0].ID)
+		require.NoError(t, err)
+		require.False(t, agent.LogsOverflowed)
+	})
+}
+
+func TestWorkspaceAgentScripts(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.PlanComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Scripts: []*proto.Script{{
+								DisplayName:      "Install",
+								Icon:             "/emojis/1f4e6.png",
+								Script:           "echo install",
+								RunOnStart:       true,
+								StartBlocksLogin: true,
+								TimeoutSeconds:   60,
+							}, {
+								DisplayName: "Cleanup",
+								Script:      "echo cleanup",
+								RunOnStop:   true,
+							}},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agent := build.Resources[0].Agents[0]
+	require.Equal(t, codersdk.WorkspaceAgentStartupScriptBehaviorBlocking, agent.StartupScriptBehavior)
+	require.Len(t, agent.Scripts, 2)
+	require.Len(t, agent.LogSources, 2)
+	install := agent.Scripts[0]
+	require.Equal(t, "echo install", install.Script)
+	require.Equal(t, time.Minute, install.Timeout)
+	require.True(t, install.StartBlocksLogin)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusPending, install.Status)
+	require.Equal(t, "echo cleanup", agent.Scripts[1].Script)
+	for _, source := range agent.LogSources {
+		if source.ID == install.LogSourceID {
+			require.Equal(t, "Install", source.DisplayName)
+			require.Equal(t, "/emojis/1f4e6.png", source.Icon)
+		}
+	}
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	manifest, err := agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.Len(t, manifest.Scripts, 2)
+	require.Equal(t, install.ID, manifest.Scripts[0].ID)
+
+	err = agentClient.PatchLogs(ctx, agentsdk.PatchLogs{
+		LogSourceID: install.LogSourceID,
+		Logs: []agentsdk.Log{{
+			CreatedAt: dbtime.Now(),
+			Output:    "installing",
+		}},
+	})
+	require.NoError(t, err)
+	logs, closer, err := client.WorkspaceAgentLogsAfter(ctx, agent.ID, 0, false)
+	require.NoError(t, err)
+	defer closer.Close()
+	logChunk := <-logs
+	require.Len(t, logChunk, 1)
+	require.Equal(t, install.LogSourceID, logChunk[0].SourceID)
+
+	startedAt := dbtime.Now()
+	endedAt := startedAt.Add(time.Second)
+	err = agentClient.PostScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID:  install.ID,
+		Status:    codersdk.WorkspaceAgentScriptStatusExitFailure,
+		ExitCode:  2,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+	})
+	require.NoError(t, err)
+
+	workspaceAgent, err := client.WorkspaceAgent(ctx, agent.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusExitFailure, workspaceAgent.Scripts[0].Status)
+	require.EqualValues(t, 2, workspaceAgent.Scripts[0].ExitCode)
+	require.NotNil(t, workspaceAgent.Scripts[0].EndedAt)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusPending, workspaceAgent.Scripts[1].Status)
+
+	err = agentClient.PostScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID:  uuid.New(),
+		Status:    codersdk.WorkspaceAgentScriptStatusOK,
+		StartedAt: startedAt,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
 }
 
 func TestWorkspaceAgentListen(t *testing.T) {
@@ -633,6 +837,108 @@ func TestWorkspaceAgentTailnet(t *testing.T) {
 	require.Equal(t, "test", strings.TrimSpace(string(output)))
 }
 
+func TestWorkspaceAgentTailnetDNS(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	createWorkspace := func(authToken string) codersdk.Workspace {
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		return workspace
+	}
+	authToken := uuid.NewString()
+	workspace := createWorkspace(authToken)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer agentCloser.Close()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	workspaceAgent := resources[0].Agents[0]
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	name := tailnet.AgentDNSName(workspaceAgent.Name, workspace.Name, coderdtest.FirstUserParams.Username)
+	conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+		Logger:             slogtest.Make(t, nil).Named("client").Leveled(slog.LevelDebug),
+		EnableDNS:          true,
+		DNSRefreshInterval: testutil.IntervalFast,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	addrs, ok := conn.LookupHost(name)
+	require.True(t, ok)
+	require.Equal(t, []netip.Addr{tailnet.IPFromUUID(workspaceAgent.ID)}, addrs)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				_, _ = io.Copy(c, c)
+			}()
+		}
+	}()
+	port := listener.Addr().(*net.TCPAddr).Port
+	netConn, err := conn.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", name, port))
+	require.NoError(t, err)
+	defer netConn.Close()
+	_, err = netConn.Write([]byte("ping"))
+	require.NoError(t, err)
+	b := make([]byte, 4)
+	_, err = io.ReadFull(netConn, b)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(b))
+
+	_, err = conn.DialContext(ctx, "tcp", fmt.Sprintf("missing.%s.%s.coder:%d", workspace.Name, coderdtest.FirstUserParams.Username, port))
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+
+	// The names of agents in workspaces created after the connection are
+	// picked up when the names are refreshed, and the connection reaches
+	// them the first time they're dialed.
+	otherToken := uuid.NewString()
+	other := createWorkspace(otherToken)
+	otherAgentClient := agentsdk.New(client.URL)
+	otherAgentClient.SetSessionToken(otherToken)
+	otherAgentCloser := agent.New(agent.Options{
+		Client: otherAgentClient,
+		Logger: slogtest.Make(t, nil).Named("other-agent").Leveled(slog.LevelDebug),
+	})
+	defer otherAgentCloser.Close()
+	otherAgent := coderdtest.AwaitWorkspaceAgents(t, client, other.ID)[0].Agents[0]
+	otherName := tailnet.AgentDNSName(otherAgent.Name, other.Name, coderdtest.FirstUserParams.Username)
+	require.Eventually(t, func() bool {
+		addrs, ok := conn.LookupHost(otherName)
+		return ok && len(addrs) == 1 && addrs[0] == tailnet.IPFromUUID(otherAgent.ID)
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	otherConn, err := conn.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", otherName, port))
+	require.NoError(t, err)
+	defer otherConn.Close()
+	_, err = otherConn.Write([]byte("pong"))
+	require.NoError(t, err)
+	_, err = io.ReadFull(otherConn, b)
+	require.NoError(t, err)
+	require.Equal(t, "pong", string(b))
+}
+
 func TestWorkspaceAgentTailnetDirectDisabled(t *testing.T) {
 	t.Parallel()
 
@@ -980,6 +1286,16 @@ func TestWorkspaceAgentAppHealth(t *testing.T) {
 				Threshold: 6,
 			},
 		},
+		{
+			Slug:        "postgres",
+			DisplayName: "postgres",
+			Url:         "http://localhost:5432",
+			Healthcheck: &proto.Healthcheck{
+				Tcp:       "localhost:5432",
+				Interval:  5,
+				Threshold: 6,
+			},
+		},
 	}
 	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
 		Parse: echo.ParseComplete,
@@ -1016,6 +1332,8 @@ func TestWorkspaceAgentAppHealth(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, codersdk.WorkspaceAppHealthDisabled, manifest.Apps[0].Health)
 	require.EqualValues(t, codersdk.WorkspaceAppHealthInitializing, manifest.Apps[1].Health)
+	require.EqualValues(t, codersdk.WorkspaceAppHealthInitializing, manifest.Apps[2].Health)
+	require.Equal(t, "localhost:5432", manifest.Apps[2].Healthcheck.TCP)
 	err = agentClient.PostAppHealth(ctx, agentsdk.PostAppHealthsRequest{})
 	require.Error(t, err)
 	// empty
@@ -1055,6 +1373,16 @@ func TestWorkspaceAgentAppHealth(t *testing.T) {
 	manifest, err = agentClient.Manifest(ctx)
 	require.NoError(t, err)
 	require.EqualValues(t, codersdk.WorkspaceAppHealthUnhealthy, manifest.Apps[1].Health)
+	// apps checked over TCP report health too
+	err = agentClient.PostAppHealth(ctx, agentsdk.PostAppHealthsRequest{
+		Healths: map[uuid.UUID]codersdk.WorkspaceAppHealth{
+			manifest.Apps[2].ID: codersdk.WorkspaceAppHealthHealthy,
+		},
+	})
+	require.NoError(t, err)
+	manifest, err = agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, codersdk.WorkspaceAppHealthHealthy, manifest.Apps[2].Health)
 }
 
 func TestWorkspaceAgentReportStats(t *testing.T) {
@@ -1181,6 +1509,50 @@ func TestWorkspaceAgent_LifecycleState(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgent_UpdateState(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	workspace, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAgentUpdateStateUnknown, workspace.LatestBuild.Resources[0].Agents[0].UpdateState)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	err = agentClient.PostUpdateState(ctx, agentsdk.PostUpdateStateRequest{
+		State: codersdk.WorkspaceAgentUpdateStateFailed,
+		Error: "download binary: unexpected status code 404",
+	})
+	require.NoError(t, err)
+
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+	require.Equal(t, codersdk.WorkspaceAgentUpdateStateFailed, agent.UpdateState)
+	require.Equal(t, "download binary: unexpected status code 404", agent.UpdateError)
+
+	err = agentClient.PostUpdateState(ctx, agentsdk.PostUpdateStateRequest{
+		State: codersdk.WorkspaceAgentUpdateState("nonexistent_state"),
+	})
+	require.Error(t, err)
+}
+
 func TestWorkspaceAgent_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -1252,6 +1624,9 @@ func TestWorkspaceAgent_Metadata(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify manifest API response.
+	require.Equal(t, workspace.ID, manifest.WorkspaceID)
+	require.Equal(t, workspace.Name, manifest.WorkspaceName)
+	require.Equal(t, workspace.OwnerName, manifest.OwnerName)
 	require.Equal(t, "First Meta", manifest.Metadata[0].DisplayName)
 	require.Equal(t, "foo1", manifest.Metadata[0].Key)
 	require.Equal(t, "echo hi", manifest.Metadata[0].Script)
@@ -0,0 +1,77 @@
+package workspaceapps
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// logConnection records an access to a workspace app or port in the
+// connection log. Terminal accesses are logged by the agent instead.
+//
+// A token is issued at least once per DefaultTokenExpiry while an app is in
+// use, so accesses by the same user from the same IP are merged into one
+// entry per app and hour.
+func (p *DBTokenProvider) logConnection(ctx context.Context, issueReq IssueTokenRequest, dbReq *databaseRequest, userID uuid.UUID, code int) {
+	if dbReq.AccessMethod == AccessMethodTerminal {
+		return
+	}
+
+	connectionType := database.ConnectionTypeWorkspaceApp
+	if _, err := strconv.ParseUint(dbReq.AppSlugOrPort, 10, 16); err == nil {
+		connectionType = database.ConnectionTypePortForwarding
+	}
+
+	ip := clientIP(issueReq.ClientIP)
+	now := dbtime.Now()
+	id := uuid.NewSHA1(dbReq.Agent.ID, []byte(fmt.Sprintf("%s/%s/%s/%s/%d", connectionType, userID, dbReq.AppSlugOrPort, ip, now.Unix()/3600)))
+
+	var ipNet pqtype.Inet
+	if ip != nil {
+		ipNet = pqtype.Inet{
+			IPNet: net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+			},
+			Valid: true,
+		}
+	}
+
+	_, err := p.Database.UpsertConnectionLog(ctx, database.UpsertConnectionLogParams{
+		ID:               id,
+		ConnectTime:      now,
+		OrganizationID:   dbReq.Workspace.OrganizationID,
+		WorkspaceOwnerID: dbReq.Workspace.OwnerID,
+		WorkspaceID:      dbReq.Workspace.ID,
+		WorkspaceName:    dbReq.Workspace.Name,
+		AgentID:          dbReq.Agent.ID,
+		AgentName:        dbReq.Agent.Name,
+		Type:             connectionType,
+		UserID:           uuid.NullUUID{UUID: userID, Valid: userID != uuid.Nil},
+		Ip:               ipNet,
+		UserAgent:        sql.NullString{String: issueReq.UserAgent, Valid: issueReq.UserAgent != ""},
+		SlugOrPort:       dbReq.AppSlugOrPort,
+		Code:             sql.NullInt32{Int32: int32(code), Valid: true},
+	})
+	if err != nil {
+		// Failing to log must not break access to the app.
+		p.Logger.Warn(ctx, "log workspace app connection", slog.F("workspace_id", dbReq.Workspace.ID), slog.Error(err))
+	}
+}
+
+// clientIP parses the remote address of a request, which may include a port.
+func clientIP(remoteAddr string) net.IP {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		remoteAddr = host
+	}
+	return net.ParseIP(remoteAddr)
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -122,6 +123,7 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 	if !authed {
 		if apiKey != nil {
 			// The request has a valid API key but insufficient permissions.
+			p.logConnection(dangerousSystemCtx, issueReq, dbReq, apiKey.UserID, http.StatusNotFound)
 			WriteWorkspaceApp404(p.Logger, p.DashboardURL, rw, r, &appReq, "insufficient permissions")
 			return nil, "", false
 		}
@@ -215,6 +217,12 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		return nil, "", false
 	}
 
+	var userID uuid.UUID
+	if apiKey != nil {
+		userID = apiKey.UserID
+	}
+	p.logConnection(dangerousSystemCtx, issueReq, dbReq, userID, http.StatusOK)
+
 	return &token, tokenStr, true
 }
 
@@ -236,6 +244,12 @@ func (p *DBTokenProvider) authorizeRequest(ctx context.Context, roles *httpmw.Au
 		sharingLevel = database.AppSharingLevelOwner
 	}
 
+	// Shared ports can't be more accessible than their template allows. The
+	// template's maximum may have been lowered after the port was shared.
+	if dbReq.MaxSharingLevel != "" && codersdk.WorkspaceAgentPortShareLevel(sharingLevel).Exceeds(codersdk.WorkspaceAgentPortShareLevel(dbReq.MaxSharingLevel)) {
+		sharingLevel = dbReq.MaxSharingLevel
+	}
+
 	// Short circuit if not authenticated.
 	if roles == nil {
 		// The user is not authenticated, so they can only access the app if it
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -630,6 +632,74 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Equal(t, "http://127.0.0.1:9090", token.AppURL)
 	})
 
+	t.Run("PortSharing", func(t *testing.T) {
+		t.Parallel()
+
+		resolve := func(t *testing.T, port int, sessionToken string) bool {
+			t.Helper()
+
+			req := (workspaceapps.Request{
+				AccessMethod:      workspaceapps.AccessMethodSubdomain,
+				BasePath:          "/",
+				UsernameOrID:      me.Username,
+				WorkspaceNameOrID: workspace.Name,
+				AgentNameOrID:     agentName,
+				AppSlugOrPort:     strconv.Itoa(port),
+			}).Normalize()
+
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			if sessionToken != "" {
+				r.Header.Set(codersdk.SessionTokenHeader, sessionToken)
+			}
+
+			_, ok := workspaceapps.ResolveRequest(rw, r, workspaceapps.ResolveRequestOptions{
+				Logger:              api.Logger,
+				SignedTokenProvider: api.WorkspaceAppsProvider,
+				DashboardURL:        api.AccessURL,
+				PathAppBaseURL:      api.AccessURL,
+				AppHostname:         api.AppHostname,
+				AppRequest:          req,
+			})
+			return ok
+		}
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelPublic),
+		})
+		require.NoError(t, err)
+
+		// Ports are only accessible by the owner until they are shared.
+		require.True(t, resolve(t, 9091, client.SessionToken()))
+		require.False(t, resolve(t, 9091, secondUserClient.SessionToken()))
+
+		_, err = client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  agentName,
+			Port:       9091,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+		})
+		require.NoError(t, err)
+		require.True(t, resolve(t, 9091, secondUserClient.SessionToken()))
+		require.False(t, resolve(t, 9091, ""))
+
+		_, err = client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  agentName,
+			Port:       9092,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+		})
+		require.NoError(t, err)
+		require.True(t, resolve(t, 9092, ""))
+
+		// Lowering the template maximum takes effect immediately.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxPortShareLevel: ptr.Ref(codersdk.WorkspaceAgentPortShareLevelAuthenticated),
+		})
+		require.NoError(t, err)
+		require.False(t, resolve(t, 9092, ""))
+		require.True(t, resolve(t, 9092, secondUserClient.SessionToken()))
+	})
+
 	t.Run("Terminal", func(t *testing.T) {
 		t.Parallel()
 
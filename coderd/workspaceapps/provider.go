@@ -60,6 +60,8 @@ func ResolveRequest(rw http.ResponseWriter, r *http.Request, opts ResolveRequest
 		SessionToken:   AppConnectSessionTokenFromRequest(r, appReq.AccessMethod),
 		AppPath:        opts.AppPath,
 		AppQuery:       opts.AppQuery,
+		ClientIP:       r.RemoteAddr,
+		UserAgent:      r.UserAgent(),
 	}
 
 	token, tokenStr, ok := opts.SignedTokenProvider.Issue(r.Context(), rw, r, issueReq)
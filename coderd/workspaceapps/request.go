@@ -39,6 +39,10 @@ type IssueTokenRequest struct {
 	AppQuery string `json:"app_query"`
 	// SessionToken is the session token provided by the user.
 	SessionToken string `json:"session_token"`
+	// ClientIP and UserAgent describe the user's request for the connection
+	// log, as the request that issues the token may come from a proxy.
+	ClientIP  string `json:"client_ip"`
+	UserAgent string `json:"user_agent"`
 }
 
 // AppBaseURL returns the base URL of this specific app request. An error is
@@ -184,6 +188,9 @@ type databaseRequest struct {
 	// AppSharingLevel is the sharing level of the app. This is forced to be set
 	// to AppSharingLevelOwner if the access method is terminal.
 	AppSharingLevel database.AppSharingLevel
+	// MaxSharingLevel caps AppSharingLevel if it is set. This is only set for
+	// port requests, where it is the template's maximum port sharing level.
+	MaxSharingLevel database.AppSharingLevel
 }
 
 // getDatabase does queries to get the owner user, workspace and agent
@@ -339,6 +346,28 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		}
 	}
 
+	// Ports are only accessible by the owner unless the owner shared them, in
+	// which case the template limits how far they can be shared.
+	var maxSharingLevel database.AppSharingLevel
+	if portUintErr == nil {
+		share, err := db.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
+			WorkspaceID: workspace.ID,
+			AgentName:   agent.Name,
+			Port:        int32(portUint),
+		})
+		if err == nil {
+			appSharingLevel = share.ShareLevel
+		} else if !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get workspace agent port share: %w", err)
+		}
+
+		template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template %q: %w", workspace.TemplateID, err)
+		}
+		maxSharingLevel = template.MaxPortSharingLevel
+	}
+
 	appURLParsed, err := url.Parse(appURL)
 	if err != nil {
 		return nil, xerrors.Errorf("parse app URL %q: %w", appURL, err)
@@ -352,6 +381,7 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		AppURL:          appURLParsed,
 		AppHealth:       appHealth,
 		AppSharingLevel: appSharingLevel,
+		MaxSharingLevel: maxSharingLevel,
 	}, nil
 }
 
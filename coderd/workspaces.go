@@ -21,11 +21,13 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -383,6 +385,13 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		})
 		return
 	}
+	if template.DeprecationMessage != "" {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Template %q has been deprecated, and cannot be used to create a new workspace.", template.Name),
+			Detail:  template.DeprecationMessage,
+		})
+		return
+	}
 
 	if organization.ID != template.OrganizationID {
 		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
@@ -450,6 +459,70 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	automaticUpdates := database.AutomaticUpdatesNever
+	if createWorkspace.AutomaticUpdates != "" {
+		automaticUpdates = database.AutomaticUpdates(createWorkspace.AutomaticUpdates)
+	}
+
+	// Claiming a prebuilt workspace is a lot faster than building a new
+	// one, so prefer it if a matching one is ready.
+	workspace, claimBuild, claimed, err := api.claimPrebuiltWorkspace(r, template, createWorkspace, database.ClaimPrebuiltWorkspaceParams{
+		OwnerID:           user.ID,
+		Name:              createWorkspace.Name,
+		AutostartSchedule: dbAutostartSchedule,
+		Ttl:               dbTTL,
+		AutomaticUpdates:  automaticUpdates,
+		UpdatedAt:         dbtime.Now(),
+	})
+	var claimBldErr wsbuilder.BuildError
+	if xerrors.As(err, &claimBldErr) {
+		// Building a new workspace would fail the same way.
+		httpapi.Write(ctx, rw, claimBldErr.Status, codersdk.Response{
+			Message: claimBldErr.Message,
+			Detail:  claimBldErr.Error(),
+		})
+		return
+	}
+	if err != nil {
+		// Fall back to building a new workspace.
+		api.Logger.Warn(ctx, "claim prebuilt workspace", slog.F("template_id", template.ID), slog.Error(err))
+	}
+	if claimed {
+		aReq.New = workspace
+
+		api.Telemetry.Report(&telemetry.Snapshot{
+			Workspaces:      []telemetry.Workspace{telemetry.ConvertWorkspace(workspace)},
+			WorkspaceBuilds: []telemetry.WorkspaceBuild{telemetry.ConvertWorkspaceBuild(*claimBuild)},
+		})
+
+		err = webhooks.Enqueue(ctx, api.Database, api.Pubsub, webhooks.WorkspaceBuildPayload(
+			codersdk.WebhookEventWorkspaceCreated, workspace, user, template, *claimBuild, ""))
+		if err != nil {
+			api.Logger.Warn(ctx, "enqueue webhook event", slog.F("event", codersdk.WebhookEventWorkspaceCreated), slog.Error(err))
+		}
+
+		data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace resources.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if len(data.builds) == 0 || len(data.templates) == 0 {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+
+		httpapi.Write(ctx, rw, http.StatusCreated, convertWorkspace(
+			workspace,
+			data.builds[0],
+			data.templates[0],
+			findUser(workspace.OwnerID, data.users),
+		))
+		return
+	}
+
 	var (
 		provisionerJob *database.ProvisionerJob
 		workspaceBuild *database.WorkspaceBuild
@@ -469,7 +542,8 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 			Ttl:               dbTTL,
 			// The workspaces page will sort by last used at, and it's useful to
 			// have the newly created workspace at the top of the list!
-			LastUsedAt: dbtime.Now(),
+			LastUsedAt:       dbtime.Now(),
+			AutomaticUpdates: automaticUpdates,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert workspace: %w", err)
@@ -521,6 +595,12 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		WorkspaceBuilds: []telemetry.WorkspaceBuild{telemetry.ConvertWorkspaceBuild(*workspaceBuild)},
 	})
 
+	err = webhooks.Enqueue(ctx, api.Database, api.Pubsub, webhooks.WorkspaceBuildPayload(
+		codersdk.WebhookEventWorkspaceCreated, workspace, user, template, *workspaceBuild, ""))
+	if err != nil {
+		api.Logger.Warn(ctx, "enqueue webhook event", slog.F("event", codersdk.WebhookEventWorkspaceCreated), slog.Error(err))
+	}
+
 	users := []database.User{user, initiator}
 	apiBuild, err := api.convertWorkspaceBuild(
 		*workspaceBuild,
@@ -534,6 +614,8 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		[]database.WorkspaceResourceMetadatum{},
 		[]database.WorkspaceAgent{},
 		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
+		[]database.WorkspaceAgentLogSource{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -552,6 +634,75 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	))
 }
 
+// claimPrebuiltWorkspace transfers a ready prebuilt workspace to the owner if
+// one of the template's prebuild pools matches the requested template version
+// and parameter values. The workspace is started as the new owner, so the
+// build goes through the same version and parameter checks as a new workspace,
+// and its cost is committed against the new owner's quota. It returns false
+// if no workspace was claimed.
+func (api *API) claimPrebuiltWorkspace(r *http.Request, template database.Template, req codersdk.CreateWorkspaceRequest, arg database.ClaimPrebuiltWorkspaceParams) (database.Workspace, *database.WorkspaceBuild, bool, error) {
+	ctx := r.Context()
+	versionID := req.TemplateVersionID
+	if versionID == uuid.Nil {
+		versionID = template.ActiveVersionID
+	}
+
+	pools, err := api.Database.GetWorkspacePrebuildPoolsByTemplateID(ctx, template.ID)
+	if err != nil {
+		return database.Workspace{}, nil, false, xerrors.Errorf("get prebuild pools: %w", err)
+	}
+	var (
+		poolIDs           []uuid.UUID
+		versionParameters []database.TemplateVersionParameter
+	)
+	for _, pool := range pools {
+		if pool.TemplateVersionID != versionID {
+			continue
+		}
+		if versionParameters == nil {
+			versionParameters, err = api.Database.GetTemplateVersionParameters(ctx, versionID)
+			if err != nil {
+				return database.Workspace{}, nil, false, xerrors.Errorf("get template version parameters: %w", err)
+			}
+		}
+		matches, err := prebuilds.Matches(pool, versionParameters, req.RichParameterValues)
+		if err != nil {
+			return database.Workspace{}, nil, false, err
+		}
+		if matches {
+			poolIDs = append(poolIDs, pool.ID)
+		}
+	}
+	if len(poolIDs) == 0 {
+		return database.Workspace{}, nil, false, nil
+	}
+
+	var workspaceBuild *database.WorkspaceBuild
+	// The requester can't read prebuilt workspaces before they own them.
+	// nolint:gocritic
+	workspace, claimed, err := prebuilds.Claim(dbauthz.AsPrebuilds(ctx), api.Database, poolIDs, arg, func(tx database.Store, workspace database.Workspace) error {
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+			Reason(database.BuildReasonInitiator).
+			Initiator(httpmw.APIKey(r).UserID).
+			ActiveVersion().
+			RichParameterValues(req.RichParameterValues)
+		if req.TemplateVersionID != uuid.Nil {
+			builder = builder.VersionID(req.TemplateVersionID)
+		}
+
+		var err error
+		// The build runs as the requester, who owns the workspace now.
+		workspaceBuild, _, err = builder.Build(ctx, tx, func(action rbac.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		})
+		return err
+	})
+	if err != nil || !claimed {
+		return database.Workspace{}, nil, false, err
+	}
+	return workspace, workspaceBuild, true, nil
+}
+
 // @Summary Update workspace metadata by ID
 // @ID update-workspace-metadata-by-id
 // @Security CoderSessionToken
@@ -795,6 +946,58 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Update workspace automatic updates by ID
+// @ID update-workspace-automatic-updates-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceAutomaticUpdatesRequest true "Automatic updates request"
+// @Success 204
+// @Router /workspaces/{workspace}/autoupdates [put]
+func (api *API) putWorkspaceAutoupdates(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspaceAutomaticUpdatesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	err := api.Database.UpdateWorkspaceAutomaticUpdates(ctx, database.UpdateWorkspaceAutomaticUpdatesParams{
+		ID:               workspace.ID,
+		AutomaticUpdates: database.AutomaticUpdates(req.AutomaticUpdates),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace automatic updates setting.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	newWorkspace := workspace
+	newWorkspace.AutomaticUpdates = database.AutomaticUpdates(req.AutomaticUpdates)
+	aReq.New = newWorkspace
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Update workspace dormancy status by id.
 // @ID update-workspace-dormancy-status-by-id
 // @Security CoderSessionToken
@@ -1118,6 +1321,8 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.scripts,
+		data.logSources,
 		data.templateVersions,
 	)
 	if err != nil {
@@ -1221,6 +1426,7 @@ func convertWorkspace(
 		TemplateDisplayName:                  template.DisplayName,
 		TemplateAllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
 		TemplateActiveVersionID:              template.ActiveVersionID,
+		TemplateDeprecationMessage:           template.DeprecationMessage,
 		Outdated:                             workspaceBuild.TemplateVersionID.String() != template.ActiveVersionID.String(),
 		Name:                                 workspace.Name,
 		AutostartSchedule:                    autostartSchedule,
@@ -1232,6 +1438,7 @@ func convertWorkspace(
 			Healthy:       len(failingAgents) == 0,
 			FailingAgents: failingAgents,
 		},
+		AutomaticUpdates: codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
 	}
 }
 
@@ -0,0 +1,323 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// maxSessionRecordingChunkSize limits the size of a single chunk uploaded by
+// an agent. Agents flush well below this.
+const maxSessionRecordingChunkSize = 4 << 20
+
+// errSessionRecordingEnded is returned when a chunk is appended to a recording
+// after its final chunk.
+var errSessionRecordingEnded = xerrors.New("session recording has ended")
+
+// @Summary Post workspace agent session recording
+// @ID post-workspace-agent-session-recording
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostSessionRecordingRequest true "Session recording request"
+// @Success 201 "Created"
+// @Router /workspaceagents/me/session-recordings [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostSessionRecordingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.ID == uuid.Nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A session recording ID is required.",
+		})
+		return
+	}
+	recordingType := database.WorkspaceSessionRecordingType(req.Type)
+	if !recordingType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid session recording type.",
+			Detail:  fmt.Sprintf("Invalid type %q, must be one of %q.", req.Type, database.AllWorkspaceSessionRecordingTypeValues()),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	// nolint:gocritic // Recordings can only be written by agents.
+	_, err = api.Database.InsertWorkspaceSessionRecording(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceSessionRecordingParams{
+		ID:            req.ID,
+		WorkspaceID:   workspace.ID,
+		AgentID:       workspaceAgent.ID,
+		Type:          recordingType,
+		ReconnectID:   uuid.NullUUID{UUID: req.ReconnectID, Valid: req.ReconnectID != uuid.Nil},
+		Command:       req.Command,
+		IncludesInput: req.IncludesInput,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	// The agent retries if the response is lost, so a duplicate is fine.
+	if err != nil && !database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, nil)
+}
+
+// @Summary Post workspace agent session recording chunk
+// @ID post-workspace-agent-session-recording-chunk
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param recording path string true "Recording ID" format(uuid)
+// @Param request body agentsdk.PostSessionRecordingChunkRequest true "Session recording chunk"
+// @Success 204
+// @Router /workspaceagents/me/session-recordings/{recording}/chunks [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostSessionRecordingChunk(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	recordingID, ok := httpmw.ParseUUIDParam(rw, r, "recording")
+	if !ok {
+		return
+	}
+	var req agentsdk.PostSessionRecordingChunkRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Index < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Chunk index must not be negative.",
+		})
+		return
+	}
+	if len(req.Data) > maxSessionRecordingChunkSize {
+		httpapi.Write(ctx, rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+			Message: fmt.Sprintf("Chunks must be at most %d bytes.", maxSessionRecordingChunkSize),
+		})
+		return
+	}
+
+	// nolint:gocritic // Agents can't read recordings, not even their own.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	recording, err := api.Database.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) || (err == nil && recording.AgentID != workspaceAgent.ID) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Session recording %q does not belong to this agent.", recordingID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		inserted, err := tx.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			ChunkIndex:  req.Index,
+			Data:        req.Data,
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert chunk: %w", err)
+		}
+		// Retried chunks are not counted twice.
+		var added int64
+		if inserted > 0 {
+			added = int64(len(req.Data))
+		}
+		updated, err := tx.UpdateWorkspaceSessionRecordingByID(ctx, database.UpdateWorkspaceSessionRecordingByIDParams{
+			ID:        recording.ID,
+			UpdatedAt: now,
+			AddedSize: added,
+			EndedAt:   sql.NullTime{Time: now, Valid: req.Final},
+			Appended:  inserted > 0,
+		})
+		if err != nil {
+			return xerrors.Errorf("update recording: %w", err)
+		}
+		if updated == 0 {
+			// Rolls back the chunk.
+			return errSessionRecordingEnded
+		}
+		return nil
+	}, nil)
+	if errors.Is(err, errSessionRecordingEnded) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Session recording %q has ended, no chunks can be added to it.", recordingID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error storing session recording chunk.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get workspace session recordings
+// @ID get-workspace-session-recordings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSessionRecording
+// @Router /workspaces/{workspace}/session-recordings [get]
+func (api *API) workspaceSessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	recordings, err := api.Database.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recordings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiRecordings := make([]codersdk.WorkspaceSessionRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		apiRecordings = append(apiRecordings, convertWorkspaceSessionRecording(recording))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiRecordings)
+}
+
+// @Summary Get session recording
+// @ID get-session-recording
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param recording path string true "Recording ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceSessionRecording
+// @Router /session-recordings/{recording} [get]
+func (api *API) workspaceSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	recording, ok := api.sessionRecordingParam(rw, r)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceSessionRecording(recording))
+}
+
+// @Summary Get session recording in asciicast format
+// @ID get-session-recording-in-asciicast-format
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param recording path string true "Recording ID" format(uuid)
+// @Success 200
+// @Router /session-recordings/{recording}/cast [get]
+func (api *API) workspaceSessionRecordingCast(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	recording, ok := api.sessionRecordingParam(rw, r)
+	if !ok {
+		return
+	}
+
+	chunks, err := api.Database.GetWorkspaceSessionRecordingChunks(ctx, recording.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recording chunks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-asciicast")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", recording.ID.String()+".cast"))
+	rw.WriteHeader(http.StatusOK)
+	for _, chunk := range chunks {
+		if _, err := rw.Write(chunk.Data); err != nil {
+			api.Logger.Debug(ctx, "write session recording", slog.F("recording_id", recording.ID), slog.Error(err))
+			return
+		}
+	}
+}
+
+// sessionRecordingParam fetches the recording in the URL. It writes a
+// response and returns false if the user can't read it.
+func (api *API) sessionRecordingParam(rw http.ResponseWriter, r *http.Request) (database.WorkspaceSessionRecording, bool) {
+	ctx := r.Context()
+	recordingID, ok := httpmw.ParseUUIDParam(rw, r, "recording")
+	if !ok {
+		return database.WorkspaceSessionRecording{}, false
+	}
+
+	recording, err := api.Database.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.WorkspaceSessionRecording{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recording.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceSessionRecording{}, false
+	}
+	return recording, true
+}
+
+func convertWorkspaceSessionRecording(recording database.WorkspaceSessionRecording) codersdk.WorkspaceSessionRecording {
+	apiRecording := codersdk.WorkspaceSessionRecording{
+		ID:            recording.ID,
+		WorkspaceID:   recording.WorkspaceID,
+		AgentID:       recording.AgentID,
+		Type:          codersdk.WorkspaceSessionRecordingType(recording.Type),
+		Command:       recording.Command,
+		IncludesInput: recording.IncludesInput,
+		Size:          recording.Size,
+		CreatedAt:     recording.CreatedAt,
+		UpdatedAt:     recording.UpdatedAt,
+	}
+	if recording.ReconnectID.Valid {
+		apiRecording.ReconnectID = &recording.ReconnectID.UUID
+	}
+	if recording.EndedAt.Valid {
+		apiRecording.EndedAt = &recording.EndedAt.Time
+	}
+	return apiRecording
+}
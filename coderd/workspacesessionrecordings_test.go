@@ -0,0 +1,147 @@
+package coderd_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.SessionRecording.Enable = true
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues:         dv,
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer agentCloser.Close()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, &codersdk.DialWorkspaceAgentOptions{
+		Logger: slogtest.Make(t, nil).Named("client").Leveled(slog.LevelDebug),
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+	require.NoError(t, err)
+	stdin, err := session.StdinPipe()
+	require.NoError(t, err)
+	err = session.Shell()
+	require.NoError(t, err)
+	_, err = stdin.Write([]byte("echo $((40 + 2))\nexit\n"))
+	require.NoError(t, err)
+	_ = session.Wait()
+	_ = session.Close()
+
+	var recordings []codersdk.WorkspaceSessionRecording
+	require.Eventually(t, func() bool {
+		recordings, err = client.WorkspaceSessionRecordings(ctx, workspace.ID)
+		return err == nil && len(recordings) == 1 && recordings[0].EndedAt != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+	recording := recordings[0]
+	require.Equal(t, codersdk.WorkspaceSessionRecordingTypeSSH, recording.Type)
+	require.Equal(t, workspace.ID, recording.WorkspaceID)
+	require.False(t, recording.IncludesInput)
+	require.Positive(t, recording.Size)
+
+	got, err := client.WorkspaceSessionRecording(ctx, recording.ID)
+	require.NoError(t, err)
+	require.Equal(t, recording.ID, got.ID)
+
+	cast, err := client.WorkspaceSessionRecordingCast(ctx, recording.ID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(cast)
+	_ = cast.Close()
+	require.NoError(t, err)
+	require.EqualValues(t, recording.Size, len(data))
+	require.Contains(t, string(data), `"version":2`)
+	require.Contains(t, string(data), `42\r\n`)
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := member.WorkspaceSessionRecording(ctx, recording.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Auditor", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		auditor, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, "auditor")
+		cast, err := auditor.WorkspaceSessionRecordingCast(ctx, recording.ID)
+		require.NoError(t, err)
+		_ = cast.Close()
+	})
+
+	t.Run("ChunkAfterFinal", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Retries of uploaded chunks are accepted.
+		err := agentClient.PostSessionRecordingChunk(ctx, recording.ID, agentsdk.PostSessionRecordingChunkRequest{
+			Index: 0,
+			Data:  []byte("retried"),
+		})
+		require.NoError(t, err)
+
+		// New chunks are not.
+		err = agentClient.PostSessionRecordingChunk(ctx, recording.ID, agentsdk.PostSessionRecordingChunkRequest{
+			Index: 1 << 20,
+			Data:  []byte("appended"),
+			Final: true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		got, err := client.WorkspaceSessionRecording(ctx, recording.ID)
+		require.NoError(t, err)
+		require.Equal(t, recording.Size, got.Size)
+		require.NotNil(t, got.EndedAt)
+		require.True(t, recording.EndedAt.Equal(*got.EndedAt), "the end time is kept")
+	})
+}
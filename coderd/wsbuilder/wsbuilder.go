@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
@@ -68,7 +69,8 @@ type Option func(Builder) Builder
 // versionTarget expresses how to determine the template version for the build.
 //
 // The zero value of this struct means to use the version from the last build.  If there is no last build,
-// the build will fail.
+// the build will fail.  Starts initiated by the owner use the active version instead if the workspace is
+// updated automatically (see updatesAutomatically).
 //
 // setting active: true means to use the active version from the template.
 //
@@ -431,7 +433,15 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 	if b.version.specific != nil {
 		return *b.version.specific, nil
 	}
-	if b.version.active {
+	active := b.version.active
+	if !active {
+		var err error
+		active, err = b.updatesAutomatically()
+		if err != nil {
+			return uuid.Nil, xerrors.Errorf("check automatic updates: %w", err)
+		}
+	}
+	if active {
 		t, err := b.getTemplate()
 		if err != nil {
 			return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
@@ -446,6 +456,26 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 	return bld.TemplateVersionID, nil
 }
 
+// updatesAutomatically returns true if the build updates the workspace to the active version of its template.  This
+// is the case for starts initiated by the owner, including those of the lifecycle executor, if the template requires
+// the active version or the owner opted in to automatic updates.
+func (b *Builder) updatesAutomatically() (bool, error) {
+	if b.trans != database.WorkspaceTransitionStart {
+		return false, nil
+	}
+	if b.initiator != uuid.Nil && b.initiator != b.workspace.OwnerID {
+		return false, nil
+	}
+	if b.workspace.AutomaticUpdates == database.AutomaticUpdatesAlways {
+		return true, nil
+	}
+	t, err := b.getTemplate()
+	if err != nil {
+		return false, xerrors.Errorf("get template: %w", err)
+	}
+	return t.RequireActiveVersion, nil
+}
+
 func (b *Builder) getLastBuild() (*database.WorkspaceBuild, error) {
 	if b.lastBuild != nil {
 		return b.lastBuild, nil
@@ -523,6 +553,13 @@ func (b *Builder) getParameters() (names, values []string, err error) {
 			b.findNewBuildParameterValue(templateVersionParameter.Name),
 		)
 		if err != nil {
+			// The workspace is being updated to the active version, which
+			// added a required parameter the owner has to provide a value for.
+			if b.version == (versionTarget{}) && tvp.Required && b.findNewBuildParameterValue(tvp.Name) == nil &&
+				!slices.ContainsFunc(lastBuildParameters, func(p database.WorkspaceBuildParameter) bool { return p.Name == tvp.Name }) {
+				msg := fmt.Sprintf("Parameter %q is required by the active template version. Provide a value for it to start the workspace.", tvp.Name)
+				return nil, nil, BuildError{http.StatusBadRequest, msg, err}
+			}
 			// At this point, we've queried all the data we need from the database,
 			// so the only errors are problems with the request (missing data, failed
 			// validation, immutable parameters, etc.)
@@ -661,6 +698,15 @@ func (b *Builder) authorize(authFunc func(action rbac.Action, object rbac.Object
 		}
 	}
 
+	// Only template managers may start workspaces with another version than
+	// the active one if the template requires it.
+	if b.trans == database.WorkspaceTransitionStart && template.RequireActiveVersion &&
+		b.version.specific != nil && *b.version.specific != template.ActiveVersionID &&
+		!authFunc(rbac.ActionUpdate, template.RBACObject()) {
+		msg := "The template requires workspaces to use its active version."
+		return BuildError{http.StatusForbidden, msg, xerrors.New(msg)}
+	}
+
 	if b.logLevel != "" && !authFunc(rbac.ActionRead, rbac.ResourceDeploymentValues) {
 		return BuildError{
 			http.StatusBadRequest,
@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
@@ -255,6 +256,14 @@ func (c *client) Listen(_ context.Context) (net.Conn, error) {
 	return clientConn, nil
 }
 
+func (*client) Peers(_ context.Context) ([]agentsdk.Peer, error) {
+	return nil, nil
+}
+
+func (*client) ListenPeer(_ context.Context, _ uuid.UUID) (net.Conn, error) {
+	return nil, xerrors.New("not implemented")
+}
+
 func (*client) ReportStats(_ context.Context, _ slog.Logger, _ <-chan *agentsdk.Stats, _ func(time.Duration)) (io.Closer, error) {
 	return io.NopCloser(strings.NewReader("")), nil
 }
@@ -263,6 +272,14 @@ func (*client) PostLifecycle(_ context.Context, _ agentsdk.PostLifecycleRequest)
 	return nil
 }
 
+func (*client) PostAutostopInhibitors(_ context.Context, _ agentsdk.PostAutostopInhibitorsRequest) error {
+	return nil
+}
+
+func (*client) PostUpdateState(_ context.Context, _ agentsdk.PostUpdateStateRequest) error {
+	return nil
+}
+
 func (*client) PostAppHealth(_ context.Context, _ agentsdk.PostAppHealthsRequest) error {
 	return nil
 }
@@ -279,6 +296,22 @@ func (*client) PatchLogs(_ context.Context, _ agentsdk.PatchLogs) error {
 	return nil
 }
 
+func (*client) PostScriptStatus(_ context.Context, _ agentsdk.PostScriptStatusRequest) error {
+	return nil
+}
+
 func (*client) GetServiceBanner(_ context.Context) (codersdk.ServiceBannerConfig, error) {
 	return codersdk.ServiceBannerConfig{}, nil
 }
+
+func (*client) PostSessionRecording(_ context.Context, _ agentsdk.PostSessionRecordingRequest) error {
+	return nil
+}
+
+func (*client) PostSessionRecordingChunk(_ context.Context, _ uuid.UUID, _ agentsdk.PostSessionRecordingChunkRequest) error {
+	return nil
+}
+
+func (*client) PostConnection(_ context.Context, _ agentsdk.PostConnectionRequest) error {
+	return nil
+}
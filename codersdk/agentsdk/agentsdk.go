@@ -81,7 +81,11 @@ func (c *Client) PostMetadata(ctx context.Context, key string, req PostMetadataR
 }
 
 type Manifest struct {
-	AgentID uuid.UUID `json:"agent_id"`
+	AgentID       uuid.UUID `json:"agent_id"`
+	AgentName     string    `json:"agent_name"`
+	OwnerName     string    `json:"owner_name"`
+	WorkspaceID   uuid.UUID `json:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
 	// GitAuthConfigs stores the number of Git configurations
 	// the Coder deployment has. If this number is >0, we
 	// set up special configuration in the workspace.
@@ -91,14 +95,20 @@ type Manifest struct {
 	DERPMap                  *tailcfg.DERPMap                             `json:"derpmap"`
 	DERPForceWebSockets      bool                                         `json:"derp_force_websockets"`
 	EnvironmentVariables     map[string]string                            `json:"environment_variables"`
-	StartupScript            string                                       `json:"startup_script"`
-	StartupScriptTimeout     time.Duration                                `json:"startup_script_timeout"`
 	Directory                string                                       `json:"directory"`
 	MOTDFile                 string                                       `json:"motd_file"`
-	ShutdownScript           string                                       `json:"shutdown_script"`
-	ShutdownScriptTimeout    time.Duration                                `json:"shutdown_script_timeout"`
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
+	// LogFiles are files the agent tails into the workspace agent logs.
+	LogFiles []codersdk.WorkspaceAgentLogFile `json:"log_files"`
+	// RecordSessions is true if interactive sessions must be recorded and
+	// uploaded with PostSessionRecording.
+	RecordSessions     bool `json:"record_sessions"`
+	RecordSessionInput bool `json:"record_session_input"`
+	// DisableAutoUpdate prevents the agent from replacing its binary when
+	// its version differs from coderd.
+	DisableAutoUpdate bool `json:"disable_auto_update"`
 }
 
 // Manifest fetches manifest for the currently authenticated workspace agent.
@@ -251,7 +261,42 @@ func (c *Client) DERPMapUpdates(ctx context.Context) (<-chan DERPMapUpdate, io.C
 // Listen connects to the workspace agent coordinate WebSocket
 // that handles connection negotiation.
 func (c *Client) Listen(ctx context.Context) (net.Conn, error) {
-	coordinateURL, err := c.SDK.URL.Parse("/api/v2/workspaceagents/me/coordinate")
+	return c.dialCoordinate(ctx, "/api/v2/workspaceagents/me/coordinate", "Listen closed")
+}
+
+// Peer is a workspace agent that the authenticated agent is allowed to
+// dial over the tailnet.
+type Peer struct {
+	AgentID       uuid.UUID                     `json:"agent_id" format:"uuid"`
+	AgentName     string                        `json:"agent_name"`
+	WorkspaceID   uuid.UUID                     `json:"workspace_id" format:"uuid"`
+	WorkspaceName string                        `json:"workspace_name"`
+	OwnerName     string                        `json:"owner_name"`
+	Status        codersdk.WorkspaceAgentStatus `json:"status"`
+}
+
+// Peers returns the workspace agents the agent is allowed to dial.
+func (c *Client) Peers(ctx context.Context) ([]Peer, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/peers", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	var peers []Peer
+	return peers, json.NewDecoder(res.Body).Decode(&peers)
+}
+
+// ListenPeer coordinates with the workspace agent of the given ID as a
+// client. Node updates of the peer are read from the returned conn.
+func (c *Client) ListenPeer(ctx context.Context, agentID uuid.UUID) (net.Conn, error) {
+	return c.dialCoordinate(ctx, fmt.Sprintf("/api/v2/workspaceagents/me/peers/%s/coordinate", agentID), "ListenPeer closed")
+}
+
+func (c *Client) dialCoordinate(ctx context.Context, path string, closeReason string) (net.Conn, error) {
+	coordinateURL, err := c.SDK.URL.Parse(path)
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
@@ -286,7 +331,7 @@ func (c *Client) Listen(ctx context.Context) (net.Conn, error) {
 		Conn: wsNetConn,
 		closeFunc: func() {
 			cancelFunc()
-			_ = conn.Close(websocket.StatusGoingAway, "Listen closed")
+			_ = conn.Close(websocket.StatusGoingAway, closeReason)
 			<-pingClosed
 		},
 	}, nil
@@ -612,6 +657,48 @@ func (c *Client) PostLifecycle(ctx context.Context, req PostLifecycleRequest) er
 	return nil
 }
 
+// PostAutostopInhibitorsRequest replaces the autostop inhibitors held in the
+// workspace.
+type PostAutostopInhibitorsRequest struct {
+	Inhibitors []codersdk.WorkspaceAgentAutostopInhibitor `json:"inhibitors"`
+}
+
+// PostAutostopInhibitors reports the autostop inhibitors held in the
+// workspace. The deadline of the workspace is extended while any is held.
+func (c *Client) PostAutostopInhibitors(ctx context.Context, req PostAutostopInhibitorsRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/autostop-inhibitors", req)
+	if err != nil {
+		return xerrors.Errorf("autostop inhibitors post request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+
+	return nil
+}
+
+// PostUpdateStateRequest reports the state of the self-update of the agent.
+type PostUpdateStateRequest struct {
+	State codersdk.WorkspaceAgentUpdateState `json:"state"`
+	// Error is the reason the update failed.
+	Error string `json:"error,omitempty"`
+}
+
+// PostUpdateState reports the state of the self-update of the agent.
+func (c *Client) PostUpdateState(ctx context.Context, req PostUpdateStateRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/update-state", req)
+	if err != nil {
+		return xerrors.Errorf("update state post request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+
+	return nil
+}
+
 type PostStartupRequest struct {
 	Version           string                    `json:"version"`
 	ExpandedDirectory string                    `json:"expanded_directory"`
@@ -631,14 +718,23 @@ func (c *Client) PostStartup(ctx context.Context, req PostStartupRequest) error
 }
 
 type Log struct {
-	CreatedAt time.Time                        `json:"created_at"`
-	Output    string                           `json:"output"`
-	Level     codersdk.LogLevel                `json:"level"`
-	Source    codersdk.WorkspaceAgentLogSource `json:"source"`
+	CreatedAt time.Time         `json:"created_at"`
+	Output    string            `json:"output"`
+	Level     codersdk.LogLevel `json:"level"`
 }
 
+// ExternalLogSourceID is the statically-defined ID of a log-source that
+// appears as "External" in the dashboard.
+//
+// This is to support legacy API-consumers that do not create their own
+// log-source. This should be removed in the future.
+var ExternalLogSourceID = uuid.MustParse("3b579bf4-1ed8-4b99-87a8-e9a1e3410410")
+
 type PatchLogs struct {
-	Logs []Log `json:"logs"`
+	// LogSourceID is the log source the logs belong to. If unset, the
+	// logs are attributed to ExternalLogSourceID.
+	LogSourceID uuid.UUID `json:"log_source_id"`
+	Logs        []Log     `json:"logs"`
 }
 
 // PatchLogs writes log messages to the agent startup script.
@@ -655,6 +751,131 @@ func (c *Client) PatchLogs(ctx context.Context, req PatchLogs) error {
 	return nil
 }
 
+type PostLogSource struct {
+	// ID is a unique identifier for the log source.
+	// It is scoped to a workspace agent, and can be statically
+	// defined inside code to prevent duplicate sources from being
+	// created for the same agent.
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"display_name"`
+	Icon        string    `json:"icon"`
+}
+
+// PostLogSource creates a log source for the agent. Logs sent with the
+// returned source ID are grouped under it in the dashboard.
+func (c *Client) PostLogSource(ctx context.Context, req PostLogSource) (codersdk.WorkspaceAgentLogSource, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/log-source", req)
+	if err != nil {
+		return codersdk.WorkspaceAgentLogSource{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return codersdk.WorkspaceAgentLogSource{}, codersdk.ReadBodyAsError(res)
+	}
+	var logSource codersdk.WorkspaceAgentLogSource
+	return logSource, json.NewDecoder(res.Body).Decode(&logSource)
+}
+
+type PostScriptStatusRequest struct {
+	ScriptID  uuid.UUID                           `json:"script_id" format:"uuid"`
+	Status    codersdk.WorkspaceAgentScriptStatus `json:"status"`
+	ExitCode  int32                               `json:"exit_code"`
+	StartedAt time.Time                           `json:"started_at" format:"date-time"`
+	EndedAt   *time.Time                          `json:"ended_at,omitempty" format:"date-time"`
+}
+
+// PostScriptStatus reports the status of a script execution.
+func (c *Client) PostScriptStatus(ctx context.Context, req PostScriptStatusRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/script-status", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+type PostSessionRecordingRequest struct {
+	// ID is generated by the agent so chunks can be uploaded as soon as the
+	// session starts.
+	ID   uuid.UUID                              `json:"id" format:"uuid"`
+	Type codersdk.WorkspaceSessionRecordingType `json:"type"`
+	// ReconnectID is only set for reconnecting PTY sessions.
+	ReconnectID   uuid.UUID `json:"reconnect_id,omitempty" format:"uuid"`
+	Command       string    `json:"command"`
+	IncludesInput bool      `json:"includes_input"`
+}
+
+// PostSessionRecording starts a recording of an interactive session.
+func (c *Client) PostSessionRecording(ctx context.Context, req PostSessionRecordingRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/session-recordings", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+type PostSessionRecordingChunkRequest struct {
+	// Index orders the chunks of a recording. Uploading the same index twice
+	// is a no-op, so failed uploads can be retried.
+	Index int32 `json:"index"`
+	// Data is a part of the asciicast v2 file.
+	Data []byte `json:"data"`
+	// Final is set on the last chunk of a recording.
+	Final bool `json:"final"`
+}
+
+// PostSessionRecordingChunk appends a chunk to a recording.
+func (c *Client) PostSessionRecordingChunk(ctx context.Context, id uuid.UUID, req PostSessionRecordingChunkRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/me/session-recordings/%s/chunks", id), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PostConnectionRequest reports a connection to the agent. The agent reports
+// each connection when it opens and again when it closes, with the same ID.
+type PostConnectionRequest struct {
+	ID          uuid.UUID               `json:"id" format:"uuid"`
+	Type        codersdk.ConnectionType `json:"type"`
+	ConnectTime time.Time               `json:"connect_time" format:"date-time"`
+	// IP is the address of the client on the tailnet.
+	IP string `json:"ip"`
+	// SlugOrPort is the port of a port forward.
+	SlugOrPort string `json:"slug_or_port,omitempty"`
+	// DisconnectTime is set once the connection is closed, along with the
+	// fields below.
+	DisconnectTime   *time.Time `json:"disconnect_time,omitempty" format:"date-time"`
+	ExitCode         *int32     `json:"exit_code,omitempty"`
+	BytesSent        int64      `json:"bytes_sent"`
+	BytesReceived    int64      `json:"bytes_received"`
+	DisconnectReason string     `json:"disconnect_reason,omitempty"`
+}
+
+// PostConnection reports a connection to the connection log.
+func (c *Client) PostConnection(ctx context.Context, req PostConnectionRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/connections", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // GetServiceBanner relays the service banner config.
 func (c *Client) GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerConfig, error) {
 	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/appearance", nil)
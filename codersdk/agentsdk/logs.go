@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -15,15 +16,14 @@ import (
 	"github.com/coder/retry"
 )
 
-type startupLogsWriter struct {
-	buf    bytes.Buffer // Buffer to track partial lines.
-	ctx    context.Context
-	send   func(ctx context.Context, log ...Log) error
-	level  codersdk.LogLevel
-	source codersdk.WorkspaceAgentLogSource
+type logsWriter struct {
+	buf   bytes.Buffer // Buffer to track partial lines.
+	ctx   context.Context
+	send  func(ctx context.Context, log ...Log) error
+	level codersdk.LogLevel
 }
 
-func (w *startupLogsWriter) Write(p []byte) (int, error) {
+func (w *logsWriter) Write(p []byte) (int, error) {
 	n := len(p)
 	for len(p) > 0 {
 		nl := bytes.IndexByte(p, '\n')
@@ -44,7 +44,6 @@ func (w *startupLogsWriter) Write(p []byte) (int, error) {
 			CreatedAt: time.Now().UTC(), // UTC, like dbtime.Now().
 			Level:     w.level,
 			Output:    string(partial) + string(p[:nl-cr]),
-			Source:    w.source,
 		})
 		if err != nil {
 			return n - len(p), err
@@ -60,45 +59,40 @@ func (w *startupLogsWriter) Write(p []byte) (int, error) {
 	return n, nil
 }
 
-func (w *startupLogsWriter) Close() error {
+func (w *logsWriter) Close() error {
 	if w.buf.Len() > 0 {
 		defer w.buf.Reset()
 		return w.send(w.ctx, Log{
 			CreatedAt: time.Now().UTC(), // UTC, like dbtime.Now().
 			Level:     w.level,
 			Output:    w.buf.String(),
-			Source:    w.source,
 		})
 	}
 	return nil
 }
 
-// StartupLogsWriter returns an io.WriteCloser that sends logs via the
+// LogsWriter returns an io.WriteCloser that sends logs via the
 // provided sender. The sender is expected to be non-blocking. Calling
 // Close flushes any remaining partially written log lines but is
-// otherwise no-op. If the context passed to StartupLogsWriter is
+// otherwise no-op. If the context passed to LogsWriter is
 // canceled, any remaining logs will be discarded.
 //
 // Neither Write nor Close is safe for concurrent use and must be used
 // by a single goroutine.
-func StartupLogsWriter(ctx context.Context, sender func(ctx context.Context, log ...Log) error, source codersdk.WorkspaceAgentLogSource, level codersdk.LogLevel) io.WriteCloser {
-	if source == "" {
-		source = codersdk.WorkspaceAgentLogSourceExternal
-	}
-	return &startupLogsWriter{
-		ctx:    ctx,
-		send:   sender,
-		level:  level,
-		source: source,
+func LogsWriter(ctx context.Context, sender func(ctx context.Context, log ...Log) error, level codersdk.LogLevel) io.WriteCloser {
+	return &logsWriter{
+		ctx:   ctx,
+		send:  sender,
+		level: level,
 	}
 }
 
-// LogsSender will send agent startup logs to the server. Calls to
-// sendLog are non-blocking and will return an error if flushAndClose
-// has been called. Calling sendLog concurrently is not supported. If
-// the context passed to flushAndClose is canceled, any remaining logs
-// will be discarded.
-func LogsSender(patchLogs func(ctx context.Context, req PatchLogs) error, logger slog.Logger) (sendLog func(ctx context.Context, log ...Log) error, flushAndClose func(context.Context) error) {
+// LogsSender will send agent logs for the given log source to the
+// server. Calls to sendLog are non-blocking and will return an error if
+// flushAndClose has been called. Calling sendLog concurrently is not
+// supported. If the context passed to flushAndClose is canceled, any
+// remaining logs will be discarded.
+func LogsSender(sourceID uuid.UUID, patchLogs func(ctx context.Context, req PatchLogs) error, logger slog.Logger) (sendLog func(ctx context.Context, log ...Log) error, flushAndClose func(context.Context) error) {
 	// The main context is used to close the sender goroutine and cancel
 	// any outbound requests to the API. The shutdown context is used to
 	// signal the sender goroutine to flush logs and then exit.
@@ -158,7 +152,8 @@ func LogsSender(patchLogs func(ctx context.Context, req PatchLogs) error, logger
 				// shutdown.
 				for r := retry.New(time.Second, 5*time.Second); r.Wait(ctx); {
 					err := patchLogs(ctx, PatchLogs{
-						Logs: backlog,
+						Logs:        backlog,
+						LogSourceID: sourceID,
 					})
 					if err == nil {
 						break
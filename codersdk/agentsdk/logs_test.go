@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
@@ -18,7 +19,7 @@ import (
 	"github.com/coder/coder/v2/testutil"
 )
 
-func TestStartupLogsWriter_Write(t *testing.T) {
+func TestLogsWriter_Write(t *testing.T) {
 	t.Parallel()
 
 	canceledCtx, cancel := context.WithCancel(context.Background())
@@ -28,7 +29,6 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 		name       string
 		ctx        context.Context
 		level      codersdk.LogLevel
-		source     codersdk.WorkspaceAgentLogSource
 		writes     []string
 		want       []agentsdk.Log
 		wantErr    bool
@@ -39,12 +39,10 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 			ctx:    context.Background(),
 			level:  codersdk.LogLevelInfo,
 			writes: []string{"hello world\n"},
-			source: codersdk.WorkspaceAgentLogSourceShutdownScript,
 			want: []agentsdk.Log{
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "hello world",
-					Source: codersdk.WorkspaceAgentLogSourceShutdownScript,
 				},
 			},
 		},
@@ -57,12 +55,10 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "hello world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "goodbye world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 			},
 		},
@@ -75,32 +71,26 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "hello world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "goodbye world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 			},
 		},
@@ -113,7 +103,6 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "hello world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 			},
 		},
@@ -127,12 +116,10 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "hello world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "goodbye world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 			},
 		},
@@ -145,12 +132,10 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "hello world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "goodbye world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 			},
 		},
@@ -163,17 +148,14 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "hello world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "\r",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 				{
 					Level:  codersdk.LogLevelInfo,
 					Output: "goodbye world",
-					Source: codersdk.WorkspaceAgentLogSourceExternal,
 				},
 			},
 		},
@@ -201,21 +183,21 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 				got = append(got, log...)
 				return nil
 			}
-			w := agentsdk.StartupLogsWriter(tt.ctx, send, tt.source, tt.level)
+			w := agentsdk.LogsWriter(tt.ctx, send, tt.level)
 			for _, s := range tt.writes {
 				_, err := w.Write([]byte(s))
 				if err != nil {
 					if tt.wantErr {
 						return
 					}
-					t.Errorf("startupLogsWriter.Write() error = %v, wantErr %v", err, tt.wantErr)
+					t.Errorf("logsWriter.Write() error = %v, wantErr %v", err, tt.wantErr)
 				}
 			}
 
 			if tt.closeFirst {
 				err := w.Close()
 				if err != nil {
-					t.Errorf("startupLogsWriter.Close() error = %v", err)
+					t.Errorf("logsWriter.Close() error = %v", err)
 					return
 				}
 			}
@@ -228,7 +210,7 @@ func TestStartupLogsWriter_Write(t *testing.T) {
 
 			err := w.Close()
 			if !tt.closeFirst && (err != nil) != tt.wantErr {
-				t.Errorf("startupLogsWriter.Close() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("logsWriter.Close() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 		})
@@ -245,7 +227,7 @@ func (s statusError) Error() string {
 	return fmt.Sprintf("status %d", s)
 }
 
-func TestStartupLogsSender(t *testing.T) {
+func TestLogsSender(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -279,8 +261,10 @@ func TestStartupLogsSender(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitMedium)
 			defer cancel()
 
+			sourceID := uuid.New()
 			got := []agentsdk.Log{}
 			patchLogs := func(_ context.Context, req agentsdk.PatchLogs) error {
+				assert.Equal(t, sourceID, req.LogSourceID)
 				if tt.patchResp != nil {
 					err := tt.patchResp(req)
 					if err != nil {
@@ -291,7 +275,7 @@ func TestStartupLogsSender(t *testing.T) {
 				return nil
 			}
 
-			sendLog, flushAndClose := agentsdk.LogsSender(patchLogs, slogtest.Make(t, nil).Leveled(slog.LevelDebug))
+			sendLog, flushAndClose := agentsdk.LogsSender(sourceID, patchLogs, slogtest.Make(t, nil).Leveled(slog.LevelDebug))
 			defer func() {
 				err := flushAndClose(ctx)
 				require.NoError(t, err)
@@ -330,7 +314,7 @@ func TestStartupLogsSender(t *testing.T) {
 			return nil
 		}
 
-		sendLog, flushAndClose := agentsdk.LogsSender(patchLogs, slogtest.Make(t, nil).Leveled(slog.LevelDebug))
+		sendLog, flushAndClose := agentsdk.LogsSender(uuid.New(), patchLogs, slogtest.Make(t, nil).Leveled(slog.LevelDebug))
 		defer func() {
 			_ = flushAndClose(ctx)
 		}()
@@ -361,7 +345,7 @@ func TestStartupLogsSender(t *testing.T) {
 			return nil
 		}
 
-		sendLog, flushAndClose := agentsdk.LogsSender(patchLogs, slogtest.Make(t, nil).Leveled(slog.LevelDebug))
+		sendLog, flushAndClose := agentsdk.LogsSender(uuid.New(), patchLogs, slogtest.Make(t, nil).Leveled(slog.LevelDebug))
 		defer func() {
 			_ = flushAndClose(ctx)
 		}()
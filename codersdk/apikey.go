@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
 // APIKey: do not ever return the HashedSecret
@@ -18,10 +20,12 @@ type APIKey struct {
 	ExpiresAt       time.Time   `json:"expires_at" validate:"required" format:"date-time"`
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
-	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,ldap,token"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,custom"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// ScopeRules are the rules of keys with the custom scope.
+	ScopeRules []APIKeyScopeRule `json:"scope_rules,omitempty"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -32,6 +36,7 @@ const (
 	LoginTypePassword LoginType = "password"
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
+	LoginTypeLDAP     LoginType = "ldap"
 	LoginTypeToken    LoginType = "token"
 	// LoginTypeNone is used if no login method is available for this user.
 	// If this is set, the user has no method of logging in.
@@ -48,12 +53,60 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeCustom is a scope that only allows the actions of its
+	// scope rules. The user's roles still apply, so the scope can never
+	// allow more than the user can do.
+	APIKeyScopeCustom APIKeyScope = "custom"
 )
 
+// APIKeyScopeRule allows an action on a resource type. If ResourceID is set,
+// the rule only allows the action on that resource.
+type APIKeyScopeRule struct {
+	ResourceType RBACResource `json:"resource_type"`
+	// Action is one of the RBAC actions, or "*" for all actions.
+	Action     string     `json:"action" enums:"create,read,update,delete,*"`
+	ResourceID *uuid.UUID `json:"resource_id,omitempty" format:"uuid"`
+}
+
+// ParseAPIKeyScope parses a scope given as a list of values. A single "all"
+// or "application_connect" value is that scope. Otherwise every value is a
+// rule of the custom scope, in the format <resource>:<action>[:<id>].
+func ParseAPIKeyScope(values []string) (APIKeyScope, []APIKeyScopeRule, error) {
+	if len(values) == 1 {
+		switch scope := APIKeyScope(values[0]); scope {
+		case APIKeyScopeAll, APIKeyScopeApplicationConnect:
+			return scope, nil, nil
+		}
+	}
+
+	rules := make([]APIKeyScopeRule, 0, len(values))
+	for _, value := range values {
+		parts := strings.Split(value, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return "", nil, xerrors.Errorf("invalid scope rule %q, must be <resource>:<action>[:<id>]", value)
+		}
+		rule := APIKeyScopeRule{
+			ResourceType: RBACResource(parts[0]),
+			Action:       parts[1],
+		}
+		if len(parts) == 3 {
+			id, err := uuid.Parse(parts[2])
+			if err != nil {
+				return "", nil, xerrors.Errorf("invalid resource ID in scope rule %q: %w", value, err)
+			}
+			rule.ResourceID = &id
+		}
+		rules = append(rules, rule)
+	}
+	return APIKeyScopeCustom, rules, nil
+}
+
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
+	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect,custom"`
 	TokenName string        `json:"token_name"`
+	// ScopeRules are required for, and only allowed with, the custom scope.
+	ScopeRules []APIKeyScopeRule `json:"scope_rules,omitempty"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
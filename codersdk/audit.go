@@ -14,18 +14,21 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeGitSSHKey       ResourceType = "git_ssh_key"
-	ResourceTypeAPIKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeOrganization    ResourceType = "organization"
+	ResourceTypeTemplate                ResourceType = "template"
+	ResourceTypeTemplateVersion         ResourceType = "template_version"
+	ResourceTypeUser                    ResourceType = "user"
+	ResourceTypeWorkspace               ResourceType = "workspace"
+	ResourceTypeWorkspaceBuild          ResourceType = "workspace_build"
+	ResourceTypeGitSSHKey               ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey                  ResourceType = "api_key"
+	ResourceTypeGroup                   ResourceType = "group"
+	ResourceTypeLicense                 ResourceType = "license"
+	ResourceTypeConvertLogin            ResourceType = "convert_login"
+	ResourceTypeWorkspaceProxy          ResourceType = "workspace_proxy"
+	ResourceTypeOrganization            ResourceType = "organization"
+	ResourceTypeCustomRole              ResourceType = "custom_role"
+	ResourceTypeOAuth2ProviderApp       ResourceType = "oauth2_provider_app"
+	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret" // nolint:gosec // This is not a secret.
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -56,6 +59,12 @@ func (r ResourceType) FriendlyString() string {
 		return "workspace proxy"
 	case ResourceTypeOrganization:
 		return "organization"
+	case ResourceTypeCustomRole:
+		return "custom role"
+	case ResourceTypeOAuth2ProviderApp:
+		return "OAuth2 app"
+	case ResourceTypeOAuth2ProviderAppSecret:
+		return "OAuth2 app secret"
 	default:
 		return "unknown"
 	}
@@ -72,6 +81,7 @@ const (
 	AuditActionLogin    AuditAction = "login"
 	AuditActionLogout   AuditAction = "logout"
 	AuditActionRegister AuditAction = "register"
+	AuditActionConnect  AuditAction = "connect"
 )
 
 func (a AuditAction) Friendly() string {
@@ -92,6 +102,8 @@ func (a AuditAction) Friendly() string {
 		return "logged out"
 	case AuditActionRegister:
 		return "registered"
+	case AuditActionConnect:
+		return "connected to"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,93 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ConnectionType string
+
+const (
+	ConnectionTypeSSH             ConnectionType = "ssh"
+	ConnectionTypeVSCode          ConnectionType = "vscode"
+	ConnectionTypeJetBrains       ConnectionType = "jetbrains"
+	ConnectionTypeReconnectingPTY ConnectionType = "reconnecting_pty"
+	ConnectionTypePortForwarding  ConnectionType = "port_forwarding"
+	ConnectionTypeWorkspaceApp    ConnectionType = "workspace_app"
+)
+
+// ConnectionLog is a connection to a workspace. SSH sessions, reconnecting
+// PTYs and SSH port forwards are reported by the workspace agent, workspace
+// app and port accesses by coderd.
+type ConnectionLog struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	ConnectTime time.Time `json:"connect_time" format:"date-time"`
+	// DisconnectTime is unset while the connection is open, and for workspace
+	// app accesses.
+	DisconnectTime         *time.Time     `json:"disconnect_time,omitempty" format:"date-time"`
+	OrganizationID         uuid.UUID      `json:"organization_id" format:"uuid"`
+	WorkspaceOwnerID       uuid.UUID      `json:"workspace_owner_id" format:"uuid"`
+	WorkspaceOwnerUsername string         `json:"workspace_owner_username"`
+	WorkspaceID            uuid.UUID      `json:"workspace_id" format:"uuid"`
+	WorkspaceName          string         `json:"workspace_name"`
+	AgentID                uuid.UUID      `json:"agent_id" format:"uuid"`
+	AgentName              string         `json:"agent_name"`
+	Type                   ConnectionType `json:"type" enums:"ssh,vscode,jetbrains,reconnecting_pty,port_forwarding,workspace_app"`
+	// UserID is the user that connected. It is only known for workspace app
+	// accesses.
+	UserID    *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	Username  string     `json:"username,omitempty"`
+	IP        netip.Addr `json:"ip"`
+	UserAgent string     `json:"user_agent,omitempty"`
+	// SlugOrPort is the slug of the workspace app, or the port that was
+	// forwarded to.
+	SlugOrPort string `json:"slug_or_port,omitempty"`
+	// Code is the exit code of SSH and reconnecting PTY sessions, or the HTTP
+	// status code of workspace app accesses.
+	Code *int32 `json:"code,omitempty"`
+	// BytesSent is the number of bytes sent from the workspace to the client.
+	BytesSent int64 `json:"bytes_sent"`
+	// BytesReceived is the number of bytes the workspace received from the
+	// client.
+	BytesReceived    int64  `json:"bytes_received"`
+	DisconnectReason string `json:"disconnect_reason,omitempty"`
+}
+
+type ConnectionLogsRequest struct {
+	SearchQuery string `json:"q,omitempty"`
+	Pagination
+}
+
+type ConnectionLogResponse struct {
+	ConnectionLogs []ConnectionLog `json:"connection_logs"`
+	Count          int64           `json:"count"`
+}
+
+// ConnectionLogs retrieves connection logs from the given page, newest first.
+func (c *Client) ConnectionLogs(ctx context.Context, req ConnectionLogsRequest) (ConnectionLogResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/connectionlog", nil, req.Pagination.asRequestOption(), func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("q", req.SearchQuery)
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return ConnectionLogResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ConnectionLogResponse{}, ReadBodyAsError(res)
+	}
+
+	var logRes ConnectionLogResponse
+	err = json.NewDecoder(res.Body).Decode(&logRes)
+	if err != nil {
+		return ConnectionLogResponse{}, err
+	}
+	return logRes, nil
+}
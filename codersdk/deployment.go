@@ -142,6 +142,7 @@ type DeploymentValues struct {
 	PostgresURL                     clibase.String                  `json:"pg_connection_url,omitempty" typescript:",notnull"`
 	OAuth2                          OAuth2Config                    `json:"oauth2,omitempty" typescript:",notnull"`
 	OIDC                            OIDCConfig                      `json:"oidc,omitempty" typescript:",notnull"`
+	LDAP                            LDAPConfig                      `json:"ldap,omitempty" typescript:",notnull"`
 	Telemetry                       TelemetryConfig                 `json:"telemetry,omitempty" typescript:",notnull"`
 	TLS                             TLSConfig                       `json:"tls,omitempty" typescript:",notnull"`
 	Trace                           TraceConfig                     `json:"trace,omitempty" typescript:",notnull"`
@@ -159,6 +160,7 @@ type DeploymentValues struct {
 	Experiments                     clibase.StringArray             `json:"experiments,omitempty" typescript:",notnull"`
 	UpdateCheck                     clibase.Bool                    `json:"update_check,omitempty" typescript:",notnull"`
 	MaxTokenLifetime                clibase.Duration                `json:"max_token_lifetime,omitempty" typescript:",notnull"`
+	MaxServiceAccountTokenLifetime  clibase.Duration                `json:"max_service_account_token_lifetime,omitempty" typescript:",notnull"`
 	Swagger                         SwaggerConfig                   `json:"swagger,omitempty" typescript:",notnull"`
 	Logging                         LoggingConfig                   `json:"logging,omitempty" typescript:",notnull"`
 	Dangerous                       DangerousConfig                 `json:"dangerous,omitempty" typescript:",notnull"`
@@ -171,9 +173,14 @@ type DeploymentValues struct {
 	SSHConfig                       SSHConfig                       `json:"config_ssh,omitempty" typescript:",notnull"`
 	WgtunnelHost                    clibase.String                  `json:"wgtunnel_host,omitempty" typescript:",notnull"`
 	DisableOwnerWorkspaceExec       clibase.Bool                    `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
+	DisableAgentAutoUpdate          clibase.Bool                    `json:"disable_agent_auto_update,omitempty" typescript:",notnull"`
+	WorkspaceNetworking             clibase.String                  `json:"workspace_networking,omitempty" typescript:",notnull"`
 	ProxyHealthStatusInterval       clibase.Duration                `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
 	EnableTerraformDebugMode        clibase.Bool                    `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
 	UserQuietHoursSchedule          UserQuietHoursScheduleConfig    `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
+	SessionRecording                SessionRecordingConfig          `json:"session_recording,omitempty" typescript:",notnull"`
+	ConnectionLogRetention          clibase.Duration                `json:"connection_log_retention,omitempty" typescript:",notnull"`
+	Notifications                   NotificationsConfig             `json:"notifications,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -289,6 +296,23 @@ type OIDCConfig struct {
 	IconURL             clibase.URL                         `json:"icon_url" typescript:",notnull"`
 }
 
+type LDAPConfig struct {
+	URL               clibase.String                    `json:"url" typescript:",notnull"`
+	StartTLS          clibase.Bool                      `json:"start_tls" typescript:",notnull"`
+	CAFile            clibase.String                    `json:"ca_file" typescript:",notnull"`
+	BindDN            clibase.String                    `json:"bind_dn" typescript:",notnull"`
+	BindPassword      clibase.String                    `json:"bind_password" typescript:",notnull"`
+	UserSearchBaseDN  clibase.String                    `json:"user_search_base_dn" typescript:",notnull"`
+	UserSearchFilter  clibase.String                    `json:"user_search_filter" typescript:",notnull"`
+	UsernameAttribute clibase.String                    `json:"username_attribute" typescript:",notnull"`
+	EmailAttribute    clibase.String                    `json:"email_attribute" typescript:",notnull"`
+	AllowSignups      clibase.Bool                      `json:"allow_signups" typescript:",notnull"`
+	GroupAttribute    clibase.String                    `json:"group_attribute" typescript:",notnull"`
+	GroupMapping      clibase.Struct[map[string]string] `json:"group_mapping" typescript:",notnull"`
+	GroupAutoCreate   clibase.Bool                      `json:"group_auto_create" typescript:",notnull"`
+	GroupRegexFilter  clibase.Regexp                    `json:"group_regex_filter" typescript:",notnull"`
+}
+
 type TelemetryConfig struct {
 	Enable clibase.Bool `json:"enable" typescript:",notnull"`
 	Trace  clibase.Bool `json:"trace" typescript:",notnull"`
@@ -370,6 +394,23 @@ type UserQuietHoursScheduleConfig struct {
 	// WindowDuration  clibase.Duration `json:"window_duration" typescript:",notnull"`
 }
 
+type SessionRecordingConfig struct {
+	Enable      clibase.Bool `json:"enable" typescript:",notnull"`
+	RecordInput clibase.Bool `json:"record_input" typescript:",notnull"`
+}
+
+type NotificationsConfig struct {
+	Email NotificationsEmailConfig `json:"email" typescript:",notnull"`
+}
+
+type NotificationsEmailConfig struct {
+	From      clibase.String `json:"from" typescript:",notnull"`
+	Smarthost clibase.String `json:"smarthost" typescript:",notnull"`
+	Hello     clibase.String `json:"hello" typescript:",notnull"`
+	Username  clibase.String `json:"username" typescript:",notnull"`
+	Password  clibase.String `json:"password" typescript:",notnull"`
+}
+
 const (
 	annotationEnterpriseKey = "enterprise"
 	annotationSecretKey     = "secret"
@@ -481,6 +522,10 @@ func (c *DeploymentValues) Options() clibase.OptionSet {
 			Name: "OIDC",
 			YAML: "oidc",
 		}
+		deploymentGroupLDAP = clibase.Group{
+			Name: "LDAP",
+			YAML: "ldap",
+		}
 		deploymentGroupTelemetry = clibase.Group{
 			Name: "Telemetry",
 			YAML: "telemetry",
@@ -498,6 +543,22 @@ when required by your organization's security policy.`,
 			Description: "Allow users to set quiet hours schedules each day for workspaces to avoid workspaces stopping during the day due to template max TTL.",
 			YAML:        "userQuietHoursSchedule",
 		}
+		deploymentGroupSessionRecording = clibase.Group{
+			Name:        "Session Recording",
+			Description: "Record interactive SSH and terminal sessions in workspaces so they can be replayed later.",
+			YAML:        "sessionRecording",
+		}
+		deploymentGroupNotifications = clibase.Group{
+			Name:        "Notifications",
+			Description: "Notify users when their workspaces are about to stop, become dormant, are scheduled for deletion or fail to build.",
+			YAML:        "notifications",
+		}
+		deploymentGroupNotificationsEmail = clibase.Group{
+			Parent:      &deploymentGroupNotifications,
+			Name:        "Email",
+			Description: "Send notifications by email, in addition to showing them in the inbox of users.",
+			YAML:        "email",
+		}
 		deploymentGroupDangerous = clibase.Group{
 			Name: "⚠️ Dangerous",
 			YAML: "dangerous",
@@ -1178,6 +1239,144 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "iconURL",
 		},
+		// LDAP settings.
+		{
+			Name:        "LDAP URL",
+			Description: "The ldap:// or ldaps:// URL of the directory server, e.g. ldaps://ldap.example.com. Login with LDAP is enabled when this is set.",
+			Flag:        "ldap-url",
+			Env:         "CODER_LDAP_URL",
+			Value:       &c.LDAP.URL,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "url",
+		},
+		{
+			Name:        "LDAP StartTLS",
+			Description: "Upgrade ldap:// connections to TLS with StartTLS before sending credentials.",
+			Flag:        "ldap-start-tls",
+			Env:         "CODER_LDAP_START_TLS",
+			Default:     "false",
+			Value:       &c.LDAP.StartTLS,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "startTLS",
+		},
+		{
+			Name:        "LDAP CA File",
+			Description: "Path to a PEM encoded file of the certificate authorities that the certificate of the directory server is verified with, instead of the system ones.",
+			Flag:        "ldap-ca-file",
+			Env:         "CODER_LDAP_CA_FILE",
+			Value:       &c.LDAP.CAFile,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "caFile",
+		},
+		{
+			Name:        "LDAP Bind DN",
+			Description: "The DN of the service account that searches for users. Users are searched for anonymously if this is not set.",
+			Flag:        "ldap-bind-dn",
+			Env:         "CODER_LDAP_BIND_DN",
+			Value:       &c.LDAP.BindDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "bindDN",
+		},
+		{
+			Name:        "LDAP Bind Password",
+			Description: "The password of the service account that searches for users.",
+			Flag:        "ldap-bind-password",
+			Env:         "CODER_LDAP_BIND_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.LDAP.BindPassword,
+			Group:       &deploymentGroupLDAP,
+		},
+		{
+			Name:        "LDAP User Search Base DN",
+			Description: "The DN the search for users starts at, e.g. ou=people,dc=example,dc=com.",
+			Flag:        "ldap-user-search-base-dn",
+			Env:         "CODER_LDAP_USER_SEARCH_BASE_DN",
+			Value:       &c.LDAP.UserSearchBaseDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userSearchBaseDN",
+		},
+		{
+			Name:        "LDAP User Search Filter",
+			Description: "The filter that finds the entry of the user logging in. {username} is replaced with the username. Use (sAMAccountName={username}) for Active Directory.",
+			Flag:        "ldap-user-search-filter",
+			Env:         "CODER_LDAP_USER_SEARCH_FILTER",
+			Default:     "(uid={username})",
+			Value:       &c.LDAP.UserSearchFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userSearchFilter",
+		},
+		{
+			Name:        "LDAP Username Attribute",
+			Description: "LDAP attribute to use as the username.",
+			Flag:        "ldap-username-attribute",
+			Env:         "CODER_LDAP_USERNAME_ATTRIBUTE",
+			Default:     "uid",
+			Value:       &c.LDAP.UsernameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "LDAP Email Attribute",
+			Description: "LDAP attribute to use as the email.",
+			Flag:        "ldap-email-attribute",
+			Env:         "CODER_LDAP_EMAIL_ATTRIBUTE",
+			Default:     "mail",
+			Value:       &c.LDAP.EmailAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "LDAP Allow Signups",
+			Description: "Whether new users can sign up with LDAP.",
+			Flag:        "ldap-allow-signups",
+			Env:         "CODER_LDAP_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.LDAP.AllowSignups,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "LDAP Group Attribute",
+			Description: "This field must be set if using the group sync feature. Set to the attribute to be used for groups, e.g. memberOf.",
+			Flag:        "ldap-group-attribute",
+			Env:         "CODER_LDAP_GROUP_ATTRIBUTE",
+			// This value is intentionally blank. If this is empty, then LDAP
+			// group behavior is disabled.
+			Default: "",
+			Value:   &c.LDAP.GroupAttribute,
+			Group:   &deploymentGroupLDAP,
+			YAML:    "groupAttribute",
+		},
+		{
+			Name:        "LDAP Group Mapping",
+			Description: "A map of LDAP groups and the group in Coder it should map to. This is useful because groups are returned as DNs, e.g. cn=devs,ou=groups,dc=example,dc=com.",
+			Flag:        "ldap-group-mapping",
+			Env:         "CODER_LDAP_GROUP_MAPPING",
+			Default:     "{}",
+			Value:       &c.LDAP.GroupMapping,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupMapping",
+		},
+		{
+			Name:        "Enable LDAP Group Auto Create",
+			Description: "Automatically creates missing groups from a user's group attribute.",
+			Flag:        "ldap-group-auto-create",
+			Env:         "CODER_LDAP_GROUP_AUTO_CREATE",
+			Default:     "false",
+			Value:       &c.LDAP.GroupAutoCreate,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "enableGroupAutoCreate",
+		},
+		{
+			Name:        "LDAP Regex Group Filter",
+			Description: "If provided any group name not matching the regex is ignored. This allows for filtering out groups that are not needed. This filter is applied after the group mapping.",
+			Flag:        "ldap-group-regex-filter",
+			Env:         "CODER_LDAP_GROUP_REGEX_FILTER",
+			Default:     ".*",
+			Value:       &c.LDAP.GroupRegexFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupRegexFilter",
+		},
 		// Telemetry settings
 		{
 			Name:        "Telemetry Enable",
@@ -1461,6 +1660,16 @@ when required by your organization's security policy.`,
 			Group:   &deploymentGroupNetworkingHTTP,
 			YAML:    "maxTokenLifetime",
 		},
+		{
+			Name:        "Max Service Account Token Lifetime",
+			Description: "The maximum lifetime duration that can be specified when creating an API token for a service account.",
+			Flag:        "max-service-account-token-lifetime",
+			Env:         "CODER_MAX_SERVICE_ACCOUNT_TOKEN_LIFETIME",
+			Default:     ((100 * 365 * time.Hour * 24) + (25 * time.Hour * 24)).String(),
+			Value:       &c.MaxServiceAccountTokenLifetime,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "maxServiceAccountTokenLifetime",
+		},
 		{
 			Name:        "Enable swagger endpoint",
 			Description: "Expose the swagger endpoint via /swagger.",
@@ -1626,6 +1835,25 @@ when required by your organization's security policy.`,
 			YAML:        "disableOwnerWorkspaceAccess",
 			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Disable Agent Auto Update",
+			Description: "Stop workspace agents from replacing themselves with the agent binary served by Coder when their versions differ.",
+			Flag:        "disable-agent-auto-update",
+			Env:         "CODER_DISABLE_AGENT_AUTO_UPDATE",
+
+			Value: &c.DisableAgentAutoUpdate,
+			YAML:  "disableAgentAutoUpdate",
+		},
+		{
+			Name:        "Workspace Networking",
+			Description: "Allow workspace agents to dial other workspace agents over the tailnet. \"owner\" permits agents of workspaces with the same owner, \"group\" additionally permits owners that share a group in the workspace's organization. One of \"disabled\", \"owner\" or \"group\".",
+			Flag:        "workspace-networking",
+			Env:         "CODER_WORKSPACE_NETWORKING",
+			Default:     string(WorkspaceNetworkingDisabled),
+			Value:       &c.WorkspaceNetworking,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "workspaceNetworking",
+		},
 		{
 			Name:        "Session Duration",
 			Description: "The token expiry duration for browser sessions. Sessions may last longer if they are actively making requests, but this functionality can be disabled via --disable-session-expiry-refresh.",
@@ -1748,6 +1976,81 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupUserQuietHoursSchedule,
 			YAML:        "defaultQuietHoursSchedule",
 		},
+		{
+			Name:        "Session Recording",
+			Description: "Record the output of interactive SSH and terminal sessions in all workspaces. Recording can also be enabled per template.",
+			Flag:        "session-recording",
+			Env:         "CODER_SESSION_RECORDING",
+			Default:     "false",
+			Value:       &c.SessionRecording.Enable,
+			Group:       &deploymentGroupSessionRecording,
+			YAML:        "enable",
+		},
+		{
+			Name:        "Session Recording Input",
+			Description: "Also record what users type in recorded sessions. Input may include secrets such as passwords typed at a prompt.",
+			Flag:        "session-recording-input",
+			Env:         "CODER_SESSION_RECORDING_INPUT",
+			Default:     "false",
+			Value:       &c.SessionRecording.RecordInput,
+			Group:       &deploymentGroupSessionRecording,
+			YAML:        "recordInput",
+		},
+		{
+			Name:        "Connection Log Retention",
+			Description: "How long to keep connection logs for. Connections older than this are deleted. Set to 0 to keep them forever.",
+			Flag:        "connection-log-retention",
+			Env:         "CODER_CONNECTION_LOG_RETENTION",
+			Default:     (90 * 24 * time.Hour).String(),
+			Value:       &c.ConnectionLogRetention,
+			YAML:        "connectionLogRetention",
+		},
+		{
+			Name:        "Notifications Email From",
+			Description: "The sender address of notification emails, e.g. coder@example.com.",
+			Flag:        "notifications-email-from",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FROM",
+			Value:       &c.Notifications.Email.From,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "from",
+		},
+		{
+			Name:        "Notifications Email Smarthost",
+			Description: "The SMTP server notification emails are sent through, as host:port. Emails are only sent when this is set.",
+			Flag:        "notifications-email-smarthost",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_SMARTHOST",
+			Value:       &c.Notifications.Email.Smarthost,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "smarthost",
+		},
+		{
+			Name:        "Notifications Email Hello",
+			Description: "The hostname sent to the SMTP server with the HELO command.",
+			Flag:        "notifications-email-hello",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_HELLO",
+			Default:     "localhost",
+			Value:       &c.Notifications.Email.Hello,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "hello",
+		},
+		{
+			Name:        "Notifications Email Username",
+			Description: "The username to authenticate to the SMTP server with. Authentication is only attempted when this is set.",
+			Flag:        "notifications-email-username",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_USERNAME",
+			Value:       &c.Notifications.Email.Username,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "username",
+		},
+		{
+			Name:        "Notifications Email Password",
+			Description: "The password to authenticate to the SMTP server with.",
+			Flag:        "notifications-email-password",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.Notifications.Email.Password,
+			Group:       &deploymentGroupNotificationsEmail,
+		},
 	}
 	return opts
 }
@@ -1923,6 +2226,29 @@ func (c *Client) BuildInfo(ctx context.Context) (BuildInfoResponse, error) {
 	return buildInfo, json.NewDecoder(res.Body).Decode(&buildInfo)
 }
 
+// WorkspaceNetworking controls which workspace agents an agent may dial
+// over the tailnet.
+type WorkspaceNetworking string
+
+const (
+	WorkspaceNetworkingDisabled WorkspaceNetworking = "disabled"
+	// WorkspaceNetworkingOwner allows agents to dial agents of other
+	// workspaces with the same owner.
+	WorkspaceNetworkingOwner WorkspaceNetworking = "owner"
+	// WorkspaceNetworkingGroup additionally allows agents to dial agents of
+	// workspaces whose owners share a group in the same organization.
+	WorkspaceNetworkingGroup WorkspaceNetworking = "group"
+)
+
+func (w WorkspaceNetworking) Valid() bool {
+	switch w {
+	case WorkspaceNetworkingDisabled, WorkspaceNetworkingOwner, WorkspaceNetworkingGroup:
+		return true
+	default:
+		return false
+	}
+}
+
 type Experiment string
 
 const (
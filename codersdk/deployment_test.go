@@ -57,6 +57,12 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"SCIM API Key": {
 			yaml: true,
 		},
+		"Notifications Email Password": {
+			yaml: true,
+		},
+		"LDAP Bind Password": {
+			yaml: true,
+		},
 		// These complex objects should be configured through YAML.
 		"Support Links": {
 			flag: true,
@@ -0,0 +1,144 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type NotificationKind string
+
+const (
+	NotificationKindWorkspaceAutostop          NotificationKind = "workspace_autostop"
+	NotificationKindWorkspaceDormant           NotificationKind = "workspace_dormant"
+	NotificationKindWorkspaceMarkedForDeletion NotificationKind = "workspace_marked_for_deletion"
+	NotificationKindWorkspaceBuildFailed       NotificationKind = "workspace_build_failed"
+	NotificationKindAccountDormant             NotificationKind = "account_dormant"
+)
+
+var NotificationKinds = []NotificationKind{
+	NotificationKindWorkspaceAutostop,
+	NotificationKindWorkspaceDormant,
+	NotificationKindWorkspaceMarkedForDeletion,
+	NotificationKindWorkspaceBuildFailed,
+	NotificationKindAccountDormant,
+}
+
+// Notification is a message in the inbox of a user.
+type Notification struct {
+	ID        uuid.UUID        `json:"id" format:"uuid"`
+	Kind      NotificationKind `json:"kind"`
+	Title     string           `json:"title"`
+	Content   string           `json:"content"`
+	CreatedAt time.Time        `json:"created_at" format:"date-time"`
+	// ReadAt is unset until the user reads the notification.
+	ReadAt *time.Time `json:"read_at,omitempty" format:"date-time"`
+}
+
+type NotificationsRequest struct {
+	UnreadOnly bool `json:"unread_only,omitempty"`
+	Pagination
+}
+
+type NotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unread_count"`
+}
+
+// NotificationPreference is whether a user is notified about a kind of
+// events. Users are notified about all kinds by default.
+type NotificationPreference struct {
+	Kind     NotificationKind `json:"kind"`
+	Disabled bool             `json:"disabled"`
+}
+
+// UpdateNotificationPreferencesRequest updates the preferences of the kinds
+// that are included.
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences" validate:"required"`
+}
+
+// Notifications returns the newest notifications of the user first.
+func (c *Client) Notifications(ctx context.Context, userIdent string, req NotificationsRequest) (NotificationsResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/users/%s/notifications", userIdent),
+		nil,
+		req.Pagination.asRequestOption(),
+		func(r *http.Request) {
+			if req.UnreadOnly {
+				q := r.URL.Query()
+				q.Set("unread_only", "true")
+				r.URL.RawQuery = q.Encode()
+			}
+		},
+	)
+	if err != nil {
+		return NotificationsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return NotificationsResponse{}, ReadBodyAsError(res)
+	}
+	var resp NotificationsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// MarkNotificationRead marks a notification of the user as read.
+func (c *Client) MarkNotificationRead(ctx context.Context, userIdent string, id uuid.UUID) (Notification, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/%s/read", userIdent, id.String()), nil)
+	if err != nil {
+		return Notification{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Notification{}, ReadBodyAsError(res)
+	}
+	var notification Notification
+	return notification, json.NewDecoder(res.Body).Decode(&notification)
+}
+
+// MarkNotificationsRead marks all notifications of the user as read.
+func (c *Client) MarkNotificationsRead(ctx context.Context, userIdent string) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/read", userIdent), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// NotificationPreferences returns the preferences of the user for every kind
+// of notification.
+func (c *Client) NotificationPreferences(ctx context.Context, userIdent string) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", userIdent), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var preferences []NotificationPreference
+	return preferences, json.NewDecoder(res.Body).Decode(&preferences)
+}
+
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, userIdent string, req UpdateNotificationPreferencesRequest) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", userIdent), req)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var preferences []NotificationPreference
+	return preferences, json.NewDecoder(res.Body).Decode(&preferences)
+}
@@ -0,0 +1,238 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// OAuth2ProviderGrantType is a grant type supported by the token endpoint of
+// the OAuth2 provider.
+type OAuth2ProviderGrantType string
+
+const (
+	OAuth2ProviderGrantTypeAuthorizationCode OAuth2ProviderGrantType = "authorization_code"
+	OAuth2ProviderGrantTypeRefreshToken      OAuth2ProviderGrantType = "refresh_token"
+)
+
+// OAuth2ProviderResponseType is a response type supported by the authorization
+// endpoint of the OAuth2 provider.
+type OAuth2ProviderResponseType string
+
+const (
+	OAuth2ProviderResponseTypeCode OAuth2ProviderResponseType = "code"
+)
+
+// OAuth2ProviderApp is an app that may act on behalf of Coder users after
+// they authorized it.
+type OAuth2ProviderApp struct {
+	ID           uuid.UUID `json:"id" format:"uuid"`
+	Name         string    `json:"name"`
+	Icon         string    `json:"icon"`
+	RedirectURIs []string  `json:"redirect_uris"`
+
+	// Endpoints are included in the app response for easier discovery. The
+	// client ID is the ID of the app.
+	Endpoints OAuth2AppEndpoints `json:"endpoints"`
+}
+
+type OAuth2AppEndpoints struct {
+	Authorization string `json:"authorization"`
+	Token         string `json:"token"`
+	Revocation    string `json:"revocation"`
+}
+
+// OAuth2ProviderAppFilter filters the apps that are returned.
+type OAuth2ProviderAppFilter struct {
+	// UserID only returns the apps that hold tokens of the user.
+	UserID uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+}
+
+type PostOAuth2ProviderAppRequest struct {
+	Name string `json:"name" validate:"required"`
+	// RedirectURIs are the URIs the user may be redirected to after
+	// authorizing the app. The redirect_uri of an authorization request must
+	// match one of them exactly.
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,dive,http_url"`
+	Icon         string   `json:"icon" validate:"omitempty"`
+}
+
+type PutOAuth2ProviderAppRequest struct {
+	Name         string   `json:"name" validate:"required"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,dive,http_url"`
+	Icon         string   `json:"icon" validate:"omitempty"`
+}
+
+// OAuth2ProviderAppSecret is a client secret of an app. Only its last
+// characters are returned.
+type OAuth2ProviderAppSecret struct {
+	ID                    uuid.UUID `json:"id" format:"uuid"`
+	LastUsedAt            NullTime  `json:"last_used_at" format:"date-time"`
+	ClientSecretTruncated string    `json:"client_secret_truncated"`
+}
+
+// OAuth2ProviderAppSecretFull is returned once, when the secret is created.
+type OAuth2ProviderAppSecretFull struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	ClientSecretFull string    `json:"client_secret_full"`
+}
+
+// OAuth2ProviderApps returns the registered apps.
+func (c *Client) OAuth2ProviderApps(ctx context.Context, filter OAuth2ProviderAppFilter) ([]OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/oauth2-provider/apps", nil,
+		func(r *http.Request) {
+			if filter.UserID != uuid.Nil {
+				q := r.URL.Query()
+				q.Set("user_id", filter.UserID.String())
+				r.URL.RawQuery = q.Encode()
+			}
+		})
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var apps []OAuth2ProviderApp
+	return apps, json.NewDecoder(res.Body).Decode(&apps)
+}
+
+func (c *Client) OAuth2ProviderApp(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id),
+		nil,
+	)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+func (c *Client) PostOAuth2ProviderApp(ctx context.Context, app PostOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/oauth2-provider/apps", app)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var resp OAuth2ProviderApp
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) PutOAuth2ProviderApp(ctx context.Context, id uuid.UUID, app PutOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id),
+		app,
+	)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var resp OAuth2ProviderApp
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteOAuth2ProviderApp deletes the app along with its secrets, and revokes
+// every token it holds.
+func (c *Client) DeleteOAuth2ProviderApp(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func (c *Client) OAuth2ProviderAppSecrets(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []OAuth2ProviderAppSecret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+func (c *Client) PostOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID) (OAuth2ProviderAppSecretFull, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID),
+		nil,
+	)
+	if err != nil {
+		return OAuth2ProviderAppSecretFull{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderAppSecretFull{}, ReadBodyAsError(res)
+	}
+	var secret OAuth2ProviderAppSecretFull
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+func (c *Client) DeleteOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID, secretID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets/%s", appID, secretID),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RevokeOAuth2ProviderApp revokes the tokens the app holds for the
+// authenticated user.
+func (c *Client) RevokeOAuth2ProviderApp(ctx context.Context, appID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/tokens", appID),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
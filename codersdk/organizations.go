@@ -136,6 +136,8 @@ type CreateWorkspaceRequest struct {
 	// RichParameterValues allows for additional parameters to be provided
 	// during the initial provision.
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
+	// AutomaticUpdates defaults to "never".
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates,omitempty" validate:"omitempty,oneof=always never"`
 }
 
 func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization, error) {
@@ -232,9 +234,27 @@ func (c *Client) CreateTemplate(ctx context.Context, organizationID uuid.UUID, r
 
 // TemplatesByOrganization lists all templates inside of an organization.
 func (c *Client) TemplatesByOrganization(ctx context.Context, organizationID uuid.UUID) ([]Template, error) {
+	return c.TemplatesByOrganizationWithFilter(ctx, organizationID, TemplateFilter{})
+}
+
+type TemplateFilter struct {
+	// SearchQuery filters templates, e.g. "name:docker deprecated:false".
+	SearchQuery string `json:"q,omitempty"`
+}
+
+// TemplatesByOrganizationWithFilter lists the templates inside of an
+// organization that match the filter.
+func (c *Client) TemplatesByOrganizationWithFilter(ctx context.Context, organizationID uuid.UUID, filter TemplateFilter) ([]Template, error) {
 	res, err := c.Request(ctx, http.MethodGet,
 		fmt.Sprintf("/api/v2/organizations/%s/templates", organizationID.String()),
 		nil,
+		func(r *http.Request) {
+			if filter.SearchQuery != "" {
+				q := r.URL.Query()
+				q.Set("q", filter.SearchQuery)
+				r.URL.RawQuery = q.Encode()
+			}
+		},
 	)
 	if err != nil {
 		return nil, xerrors.Errorf("execute request: %w", err)
@@ -0,0 +1,111 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WorkspacePrebuildPool keeps a number of prebuilt workspaces of a template
+// version ready. Creating a workspace with the same template version and
+// parameter values claims one of them instead of building a new workspace.
+type WorkspacePrebuildPool struct {
+	ID                uuid.UUID                 `json:"id" format:"uuid"`
+	TemplateID        uuid.UUID                 `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID                 `json:"template_version_id" format:"uuid"`
+	Parameters        []WorkspaceBuildParameter `json:"parameters"`
+	DesiredInstances  int32                     `json:"desired_instances"`
+	// ReadyInstances are built and can be claimed.
+	ReadyInstances int32 `json:"ready_instances"`
+	// BuildingInstances are being built.
+	BuildingInstances int32 `json:"building_instances"`
+	// FailedInstances failed to build, or were stopped, and will be deleted.
+	FailedInstances int32     `json:"failed_instances"`
+	CreatedAt       time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt       time.Time `json:"updated_at" format:"date-time"`
+}
+
+type CreateWorkspacePrebuildPoolRequest struct {
+	TemplateVersionID uuid.UUID                 `json:"template_version_id" validate:"required" format:"uuid"`
+	Parameters        []WorkspaceBuildParameter `json:"parameters,omitempty"`
+	DesiredInstances  int32                     `json:"desired_instances" validate:"min=0"`
+}
+
+type UpdateWorkspacePrebuildPoolRequest struct {
+	DesiredInstances int32 `json:"desired_instances" validate:"min=0"`
+}
+
+// WorkspacePrebuildPools returns the prebuild pools of a template.
+func (c *Client) WorkspacePrebuildPools(ctx context.Context, template uuid.UUID) ([]WorkspacePrebuildPool, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/templates/%s/prebuilds", template.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []WorkspacePrebuildPool
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) CreateWorkspacePrebuildPool(ctx context.Context, template uuid.UUID, req CreateWorkspacePrebuildPoolRequest) (WorkspacePrebuildPool, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/templates/%s/prebuilds", template.String()),
+		req,
+	)
+	if err != nil {
+		return WorkspacePrebuildPool{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return WorkspacePrebuildPool{}, ReadBodyAsError(res)
+	}
+	var resp WorkspacePrebuildPool
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) UpdateWorkspacePrebuildPool(ctx context.Context, template, pool uuid.UUID, req UpdateWorkspacePrebuildPoolRequest) (WorkspacePrebuildPool, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/templates/%s/prebuilds/%s", template.String(), pool.String()),
+		req,
+	)
+	if err != nil {
+		return WorkspacePrebuildPool{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return WorkspacePrebuildPool{}, ReadBodyAsError(res)
+	}
+	var resp WorkspacePrebuildPool
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteWorkspacePrebuildPool deletes a prebuild pool. Its prebuilt workspaces
+// that have not been claimed are deleted in the background.
+func (c *Client) DeleteWorkspacePrebuildPool(ctx context.Context, template, pool uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/templates/%s/prebuilds/%s", template.String(), pool.String()),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -8,6 +8,12 @@ const (
 	ResourceWorkspaceExecution          RBACResource = "workspace_execution"
 	ResourceWorkspaceApplicationConnect RBACResource = "application_connect"
 	ResourceAuditLog                    RBACResource = "audit_log"
+	ResourceConnectionLog               RBACResource = "connection_log"
+	ResourceSessionRecording            RBACResource = "session_recording"
+	ResourceWebhook                     RBACResource = "webhook"
+	ResourceOAuth2ProviderApp           RBACResource = "oauth2_app"
+	ResourceOAuth2ProviderAppSecret     RBACResource = "oauth2_app_secret"
+	ResourceOAuth2ProviderAppCodeToken  RBACResource = "oauth2_app_code_token"
 	ResourceTemplate                    RBACResource = "template"
 	ResourceGroup                       RBACResource = "group"
 	ResourceFile                        RBACResource = "file"
@@ -41,6 +47,12 @@ var (
 		ResourceWorkspaceExecution,
 		ResourceWorkspaceApplicationConnect,
 		ResourceAuditLog,
+		ResourceConnectionLog,
+		ResourceSessionRecording,
+		ResourceWebhook,
+		ResourceOAuth2ProviderApp,
+		ResourceOAuth2ProviderAppSecret,
+		ResourceOAuth2ProviderAppCodeToken,
 		ResourceTemplate,
 		ResourceGroup,
 		ResourceFile,
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
 type Role struct {
@@ -19,6 +21,58 @@ type AssignableRoles struct {
 	Assignable bool `json:"assignable"`
 }
 
+// Permission allows an action on a resource type, or denies it if Negate is
+// set.
+type Permission struct {
+	Negate       bool         `json:"negate"`
+	ResourceType RBACResource `json:"resource_type"`
+	Action       string       `json:"action" enums:"create,read,update,delete"`
+}
+
+// CustomRole is a role defined by administrators in addition to the built-in
+// roles. Roles with an organization are assigned to members of that
+// organization as "name:organization_id".
+type CustomRole struct {
+	ID                      uuid.UUID    `json:"id" format:"uuid"`
+	Name                    string       `json:"name"`
+	DisplayName             string       `json:"display_name"`
+	OrganizationID          *uuid.UUID   `json:"organization_id,omitempty" format:"uuid"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+	CreatedAt               time.Time    `json:"created_at" format:"date-time"`
+	UpdatedAt               time.Time    `json:"updated_at" format:"date-time"`
+}
+
+// AssignedName is the name the role is assigned with.
+func (r CustomRole) AssignedName() string {
+	if r.OrganizationID == nil {
+		return r.Name
+	}
+	return r.Name + ":" + r.OrganizationID.String()
+}
+
+type CreateCustomRoleRequest struct {
+	Name        string `json:"name" validate:"required,username"`
+	DisplayName string `json:"display_name"`
+	// OrganizationID makes the role an organization role. Organization roles
+	// cannot have site permissions, site roles cannot have organization
+	// permissions.
+	OrganizationID          *uuid.UUID   `json:"organization_id,omitempty" format:"uuid"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+}
+
+// UpdateCustomRoleRequest updates the display name and permissions of a role.
+// Permissions that are null are left unchanged. Roles cannot be renamed.
+type UpdateCustomRoleRequest struct {
+	DisplayName             *string      `json:"display_name,omitempty"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+}
+
 // ListSiteRoles lists all assignable site wide roles.
 func (c *Client) ListSiteRoles(ctx context.Context) ([]AssignableRoles, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/roles", nil)
@@ -46,3 +100,84 @@ func (c *Client) ListOrganizationRoles(ctx context.Context, org uuid.UUID) ([]As
 	var roles []AssignableRoles
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
+
+// CustomRoles lists the custom roles the user can see.
+func (c *Client) CustomRoles(ctx context.Context) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/roles", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []CustomRole
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) CustomRole(ctx context.Context, id uuid.UUID) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/roles/%s", id.String()),
+		nil,
+	)
+	if err != nil {
+		return CustomRole{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var resp CustomRole
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) CreateCustomRole(ctx context.Context, req CreateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/roles", req)
+	if err != nil {
+		return CustomRole{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var resp CustomRole
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) UpdateCustomRole(ctx context.Context, id uuid.UUID, req UpdateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPatch,
+		fmt.Sprintf("/api/v2/roles/%s", id.String()),
+		req,
+	)
+	if err != nil {
+		return CustomRole{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var resp CustomRole
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteCustomRole deletes a custom role. The role is removed from every user
+// it is assigned to.
+func (c *Client) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/roles/%s", id.String()),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
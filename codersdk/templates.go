@@ -50,6 +50,22 @@ type Template struct {
 	FailureTTLMillis               int64 `json:"failure_ttl_ms"`
 	TimeTilDormantMillis           int64 `json:"time_til_dormant_ms"`
 	TimeTilDormantAutoDeleteMillis int64 `json:"time_til_dormant_autodelete_ms"`
+
+	// MaxPortShareLevel is the most permissive level workspace owners can
+	// share ports of workspaces created from this template with.
+	MaxPortShareLevel WorkspaceAgentPortShareLevel `json:"max_port_share_level" enums:"owner,authenticated,public"`
+	// RecordSessions records interactive sessions in workspaces created from
+	// this template, even if session recording is disabled for the
+	// deployment.
+	RecordSessions bool `json:"record_sessions"`
+	// Deprecated templates can't be used to create new workspaces. Existing
+	// workspaces keep working.
+	Deprecated         bool       `json:"deprecated"`
+	DeprecationMessage string     `json:"deprecation_message"`
+	DeprecatedAt       *time.Time `json:"deprecated_at,omitempty" format:"date-time"`
+	// RequireActiveVersion updates workspaces to the active version when
+	// their owner starts them, and prevents them from using other versions.
+	RequireActiveVersion bool `json:"require_active_version"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -201,6 +217,16 @@ type UpdateTemplateMeta struct {
 	// from the template. This is useful for preventing dormant workspaces being immediately
 	// deleted when updating the dormant_ttl field to a new, shorter value.
 	UpdateWorkspaceDormantAt bool `json:"update_workspace_dormant_at"`
+	// MaxPortShareLevel is left unchanged if it is not set. Lowering it
+	// reduces the sharing level of ports that are already shared.
+	MaxPortShareLevel *WorkspaceAgentPortShareLevel `json:"max_port_share_level,omitempty" enums:"owner,authenticated,public"`
+	// RecordSessions is left unchanged if it is not set.
+	RecordSessions *bool `json:"record_sessions,omitempty"`
+	// DeprecationMessage deprecates the template if it is not empty, and
+	// undeprecates it if it is empty. It is left unchanged if it is not set.
+	DeprecationMessage *string `json:"deprecation_message,omitempty"`
+	// RequireActiveVersion is left unchanged if it is not set.
+	RequireActiveVersion *bool `json:"require_active_version,omitempty"`
 }
 
 type TemplateExample struct {
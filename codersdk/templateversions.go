@@ -75,6 +75,14 @@ type TemplateVersionParameterOption struct {
 	Icon        string `json:"icon"`
 }
 
+// TemplateVersionPreset is a named set of parameter values declared by a
+// template version.
+type TemplateVersionPreset struct {
+	ID         uuid.UUID                 `json:"id" format:"uuid"`
+	Name       string                    `json:"name"`
+	Parameters []WorkspaceBuildParameter `json:"parameters"`
+}
+
 // TemplateVersionVariable represents a managed template variable.
 type TemplateVersionVariable struct {
 	Name         string `json:"name"`
@@ -132,6 +140,20 @@ func (c *Client) TemplateVersionRichParameters(ctx context.Context, version uuid
 	return params, json.NewDecoder(res.Body).Decode(&params)
 }
 
+// TemplateVersionPresets returns the parameter presets a template version declares.
+func (c *Client) TemplateVersionPresets(ctx context.Context, version uuid.UUID) ([]TemplateVersionPreset, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/presets", version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var presets []TemplateVersionPreset
+	return presets, json.NewDecoder(res.Body).Decode(&presets)
+}
+
 // TemplateVersionGitAuth returns git authentication for the requested template version.
 func (c *Client) TemplateVersionGitAuth(ctx context.Context, version uuid.UUID) ([]TemplateVersionGitAuth, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/gitauth", version), nil)
@@ -55,6 +55,8 @@ type User struct {
 	Roles           []Role      `json:"roles"`
 	AvatarURL       string      `json:"avatar_url" format:"uri"`
 	LoginType       LoginType   `json:"login_type"`
+	// IsServiceAccount is set for non-human users used for automation.
+	IsServiceAccount bool `json:"is_service_account" table:"service account"`
 }
 
 type GetUsersResponse struct {
@@ -86,6 +88,10 @@ type CreateUserRequest struct {
 	// Deprecated: Set UserLoginType=LoginTypeDisabled instead.
 	DisableLogin   bool      `json:"disable_login"`
 	OrganizationID uuid.UUID `json:"organization_id" validate:"" format:"uuid"`
+	// ServiceAccount creates a non-human user for automation. Service
+	// accounts cannot log in, so they must not have a password or another
+	// login type. They authenticate with tokens and don't take license seats.
+	ServiceAccount bool `json:"service_account"`
 }
 
 type UpdateUserProfileRequest struct {
@@ -146,6 +152,13 @@ type LoginWithPasswordRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// LoginWithLDAPRequest enables callers to authenticate with the username and
+// password of their LDAP directory.
+type LoginWithLDAPRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
 type LoginWithPasswordResponse struct {
 	SessionToken string `json:"session_token" validate:"required"`
@@ -167,6 +180,7 @@ type AuthMethods struct {
 	Password AuthMethod     `json:"password"`
 	Github   AuthMethod     `json:"github"`
 	OIDC     OIDCAuthMethod `json:"oidc"`
+	LDAP     AuthMethod     `json:"ldap"`
 }
 
 type AuthMethod struct {
@@ -357,6 +371,26 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 	return resp, nil
 }
 
+// LoginWithLDAP creates a session token authenticating with the username and
+// password of an LDAP directory.
+// Call `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginWithLDAP(ctx context.Context, req LoginWithLDAPRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/ldap/login", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	return resp, nil
+}
+
 // ConvertLoginType will send a request to convert the user from password
 // based authentication to oauth based. The response has the oauth state code
 // to use in the oauth flow.
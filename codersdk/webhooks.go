@@ -0,0 +1,257 @@
+package codersdk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceCreated        WebhookEvent = "workspace_created"
+	WebhookEventWorkspaceStarted        WebhookEvent = "workspace_started"
+	WebhookEventWorkspaceStopped        WebhookEvent = "workspace_stopped"
+	WebhookEventWorkspaceDeleted        WebhookEvent = "workspace_deleted"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build_failed"
+	WebhookEventTemplateVersionPromoted WebhookEvent = "template_version_promoted"
+	WebhookEventUserCreated             WebhookEvent = "user_created"
+)
+
+var WebhookEvents = []WebhookEvent{
+	WebhookEventWorkspaceCreated,
+	WebhookEventWorkspaceStarted,
+	WebhookEventWorkspaceStopped,
+	WebhookEventWorkspaceDeleted,
+	WebhookEventWorkspaceBuildFailed,
+	WebhookEventTemplateVersionPromoted,
+	WebhookEventUserCreated,
+}
+
+const (
+	// WebhookEventHeader is the event of a webhook payload.
+	WebhookEventHeader = "X-Coder-Event"
+	// WebhookDeliveryHeader is the ID of a webhook delivery. Retries of a
+	// delivery have the same ID.
+	WebhookDeliveryHeader = "X-Coder-Delivery"
+	// WebhookSignatureHeader is the HMAC-SHA256 signature of a webhook payload,
+	// see WebhookSignature.
+	WebhookSignatureHeader = "X-Coder-Signature-256"
+)
+
+// WebhookSignature returns the signature of a webhook payload, in the format
+// of the WebhookSignatureHeader: "sha256=" and the hex encoded HMAC-SHA256 of
+// the body, keyed with the secret of the webhook.
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Webhook is an endpoint that receives events from Coder. The secret of a
+// webhook is never returned.
+type Webhook struct {
+	ID        uuid.UUID      `json:"id" format:"uuid"`
+	CreatedAt time.Time      `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time      `json:"updated_at" format:"date-time"`
+	Name      string         `json:"name"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	Enabled   bool           `json:"enabled"`
+}
+
+type CreateWebhookRequest struct {
+	Name   string         `json:"name" validate:"required,username"`
+	URL    string         `json:"url" validate:"required"`
+	Events []WebhookEvent `json:"events" validate:"required"`
+	// Secret signs the payloads sent to the webhook. A random secret is
+	// generated if it is empty.
+	Secret string `json:"secret,omitempty"`
+}
+
+type CreateWebhookResponse struct {
+	Webhook Webhook `json:"webhook"`
+	// Secret is returned only once, when the webhook is created.
+	Secret string `json:"secret"`
+}
+
+// UpdateWebhookRequest updates the fields that are set.
+type UpdateWebhookRequest struct {
+	Name    *string        `json:"name,omitempty"`
+	URL     *string        `json:"url,omitempty"`
+	Events  []WebhookEvent `json:"events,omitempty"`
+	Enabled *bool          `json:"enabled,omitempty"`
+	Secret  *string        `json:"secret,omitempty"`
+}
+
+// WebhookDelivery is a payload sent, or waiting to be sent, to a webhook.
+type WebhookDelivery struct {
+	ID        uuid.UUID       `json:"id" format:"uuid"`
+	WebhookID uuid.UUID       `json:"webhook_id" format:"uuid"`
+	Event     WebhookEvent    `json:"event"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at" format:"date-time"`
+	Attempts  int32           `json:"attempts"`
+	// LastAttemptAt is unset until the delivery is attempted.
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty" format:"date-time"`
+	// NextAttemptAt is unset once the delivery succeeded, or failed too many
+	// times.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" format:"date-time"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty" format:"date-time"`
+	// StatusCode is the HTTP status code of the last attempt.
+	StatusCode *int32 `json:"status_code,omitempty"`
+	// Error is the error of the last attempt.
+	Error string `json:"error,omitempty"`
+}
+
+type WebhookDeliveriesRequest struct {
+	Pagination
+}
+
+// WebhookPayload is the body of the requests sent to webhooks. Only the
+// object the event is about is set.
+type WebhookPayload struct {
+	// ID is the ID of the event. The deliveries of an event to different
+	// webhooks have the same ID.
+	ID              uuid.UUID                      `json:"id" format:"uuid"`
+	Event           WebhookEvent                   `json:"event"`
+	Timestamp       time.Time                      `json:"timestamp" format:"date-time"`
+	Workspace       *WebhookPayloadWorkspace       `json:"workspace,omitempty"`
+	TemplateVersion *WebhookPayloadTemplateVersion `json:"template_version,omitempty"`
+	User            *WebhookPayloadUser            `json:"user,omitempty"`
+}
+
+type WebhookPayloadWorkspace struct {
+	ID             uuid.UUID           `json:"id" format:"uuid"`
+	Name           string              `json:"name"`
+	OwnerID        uuid.UUID           `json:"owner_id" format:"uuid"`
+	OwnerName      string              `json:"owner_name"`
+	OrganizationID uuid.UUID           `json:"organization_id" format:"uuid"`
+	TemplateID     uuid.UUID           `json:"template_id" format:"uuid"`
+	TemplateName   string              `json:"template_name"`
+	BuildID        uuid.UUID           `json:"build_id" format:"uuid"`
+	BuildNumber    int32               `json:"build_number"`
+	Transition     WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	// Error is set for workspace_build_failed events.
+	Error string `json:"error,omitempty"`
+}
+
+type WebhookPayloadTemplateVersion struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	TemplateName   string    `json:"template_name"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+}
+
+type WebhookPayloadUser struct {
+	ID       uuid.UUID `json:"id" format:"uuid"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+}
+
+// Webhooks returns all webhooks.
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []Webhook
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) Webhook(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/webhooks/%s", id.String()),
+		nil,
+	)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var resp Webhook
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (CreateWebhookResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/webhooks", req)
+	if err != nil {
+		return CreateWebhookResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return CreateWebhookResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateWebhookResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, id uuid.UUID, req UpdateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPatch,
+		fmt.Sprintf("/api/v2/webhooks/%s", id.String()),
+		req,
+	)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var resp Webhook
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/webhooks/%s", id.String()),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebhookDeliveries returns the deliveries of a webhook, newest first.
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID, req WebhookDeliveriesRequest) ([]WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/webhooks/%s/deliveries", id.String()),
+		nil,
+		req.Pagination.asRequestOption(),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []WebhookDelivery
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -150,6 +150,10 @@ type WorkspaceAgentConnOptions struct {
 	AgentID   uuid.UUID
 	AgentIP   netip.Addr
 	CloseFunc func() error
+	// ReachAddr makes an address resolved from the name of another agent
+	// reachable over the connection before it's dialed. Names of other
+	// agents can't be dialed if it's nil.
+	ReachAddr func(ctx context.Context, ip netip.Addr) error
 }
 
 func (c *WorkspaceAgentConn) agentAddress() netip.Addr {
@@ -200,6 +204,31 @@ type WorkspaceAgentReconnectingPTYInit struct {
 	Height  uint16
 	Width   uint16
 	Command string
+	// ReadOnly discards all input from the connection, including resizes,
+	// so the connection can only watch the session.
+	ReadOnly bool
+	// AttachOnly fails the connection instead of spawning a new session if
+	// there is no session with the ID.
+	AttachOnly bool
+}
+
+// AgentReconnectingPTYInitOption is a functional option for
+// WorkspaceAgentReconnectingPTYInit.
+type AgentReconnectingPTYInitOption func(*WorkspaceAgentReconnectingPTYInit)
+
+// AgentReconnectingPTYInitReadOnly attaches to the session without being
+// able to write to it.
+func AgentReconnectingPTYInitReadOnly() AgentReconnectingPTYInitOption {
+	return func(init *WorkspaceAgentReconnectingPTYInit) {
+		init.ReadOnly = true
+	}
+}
+
+// AgentReconnectingPTYInitAttachOnly only attaches to an existing session.
+func AgentReconnectingPTYInitAttachOnly() AgentReconnectingPTYInitOption {
+	return func(init *WorkspaceAgentReconnectingPTYInit) {
+		init.AttachOnly = true
+	}
 }
 
 // ReconnectingPTYRequest is sent from the client to the server
@@ -214,7 +243,7 @@ type ReconnectingPTYRequest struct {
 // ReconnectingPTY spawns a new reconnecting terminal session.
 // `ReconnectingPTYRequest` should be JSON marshaled and written to the returned net.Conn.
 // Raw terminal output will be read from the returned net.Conn.
-func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string) (net.Conn, error) {
+func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string, initOpts ...AgentReconnectingPTYInitOption) (net.Conn, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
@@ -226,12 +255,16 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	data, err := json.Marshal(WorkspaceAgentReconnectingPTYInit{
+	init := WorkspaceAgentReconnectingPTYInit{
 		ID:      id,
 		Height:  height,
 		Width:   width,
 		Command: command,
-	})
+	}
+	for _, opt := range initOpts {
+		opt(&init)
+	}
+	data, err := json.Marshal(init)
 	if err != nil {
 		_ = conn.Close()
 		return nil, err
@@ -307,18 +340,40 @@ func (c *WorkspaceAgentConn) Speedtest(ctx context.Context, direction speedtest.
 }
 
 // DialContext dials the address provided in the workspace agent.
-// The network must be "tcp" or "udp".
+// The network must be "tcp" or "udp". Hosts under tailnet.CoderDNSSuffix are
+// resolved by the connection, any other host is the agent itself.
 func (c *WorkspaceAgentConn) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
-	if !c.AwaitReachable(ctx) {
-		return nil, xerrors.Errorf("workspace agent not reachable in time: %v", ctx.Err())
-	}
-
-	_, rawPort, _ := net.SplitHostPort(addr)
+	host, rawPort, _ := net.SplitHostPort(addr)
 	port, _ := strconv.ParseUint(rawPort, 10, 16)
 	ipp := netip.AddrPortFrom(c.agentAddress(), uint16(port))
+	if tailnet.IsCoderDNSName(host) {
+		addrs, ok := c.Conn.LookupHost(host)
+		if !ok || len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		// Older agents only listen on the legacy address, so the name of
+		// this agent is dialed the same way as any other host.
+		if addrs[0] != tailnet.IPFromUUID(c.opts.AgentID) {
+			ipp = netip.AddrPortFrom(addrs[0], uint16(port))
+		}
+	}
+
+	if ipp.Addr() == c.agentAddress() {
+		if !c.AwaitReachable(ctx) {
+			return nil, xerrors.Errorf("workspace agent not reachable in time: %v", ctx.Err())
+		}
+	} else {
+		if c.opts.ReachAddr == nil {
+			return nil, xerrors.Errorf("%s is not reachable over the connection", host)
+		}
+		err := c.opts.ReachAddr(ctx, ipp.Addr())
+		if err != nil {
+			return nil, xerrors.Errorf("reach %s: %w", host, err)
+		}
+	}
 
 	switch network {
 	case "tcp":
@@ -361,6 +416,39 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// WorkspaceAgentReconnectingPTYSessionsResponse lists the live reconnecting
+// PTY sessions on a workspace agent.
+type WorkspaceAgentReconnectingPTYSessionsResponse struct {
+	Sessions []WorkspaceAgentReconnectingPTYSession `json:"sessions"`
+}
+
+type WorkspaceAgentReconnectingPTYSession struct {
+	// ID is the reconnect ID used to attach to the session.
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	// Connections is the number of connections currently attached.
+	Connections int64 `json:"connections"`
+}
+
+// ReconnectingPTYSessions lists the live reconnecting PTY sessions on the
+// workspace agent.
+func (c *WorkspaceAgentConn) ReconnectingPTYSessions(ctx context.Context) (WorkspaceAgentReconnectingPTYSessionsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/reconnecting-ptys", nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYSessionsResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYSessionsResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentReconnectingPTYSessionsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -0,0 +1,119 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceAgentPortShareLevel string
+
+const (
+	WorkspaceAgentPortShareLevelOwner         WorkspaceAgentPortShareLevel = "owner"
+	WorkspaceAgentPortShareLevelAuthenticated WorkspaceAgentPortShareLevel = "authenticated"
+	WorkspaceAgentPortShareLevelPublic        WorkspaceAgentPortShareLevel = "public"
+)
+
+// ValidMaxLevel returns whether the level can be used as the maximum port
+// sharing level of a template.
+func (l WorkspaceAgentPortShareLevel) ValidMaxLevel() bool {
+	return l == WorkspaceAgentPortShareLevelOwner ||
+		l == WorkspaceAgentPortShareLevelAuthenticated ||
+		l == WorkspaceAgentPortShareLevelPublic
+}
+
+// ValidPortShareLevel returns whether the level can be set on a shared port.
+// Ports are only accessible by the owner unless they are shared, so "owner" is
+// not a valid level for a share.
+func (l WorkspaceAgentPortShareLevel) ValidPortShareLevel() bool {
+	return l == WorkspaceAgentPortShareLevelAuthenticated ||
+		l == WorkspaceAgentPortShareLevelPublic
+}
+
+// Exceeds returns whether the level grants access to more users than the
+// given level.
+func (l WorkspaceAgentPortShareLevel) Exceeds(other WorkspaceAgentPortShareLevel) bool {
+	return l.rank() > other.rank()
+}
+
+func (l WorkspaceAgentPortShareLevel) rank() int {
+	switch l {
+	case WorkspaceAgentPortShareLevelAuthenticated:
+		return 1
+	case WorkspaceAgentPortShareLevelPublic:
+		return 2
+	default:
+		return 0
+	}
+}
+
+type WorkspaceAgentPortShare struct {
+	WorkspaceID uuid.UUID                    `json:"workspace_id" format:"uuid"`
+	AgentName   string                       `json:"agent_name"`
+	Port        int32                        `json:"port"`
+	ShareLevel  WorkspaceAgentPortShareLevel `json:"share_level" enums:"owner,authenticated,public"`
+}
+
+type WorkspaceAgentPortShares struct {
+	Shares []WorkspaceAgentPortShare `json:"shares"`
+}
+
+type UpsertWorkspaceAgentPortShareRequest struct {
+	AgentName  string                       `json:"agent_name"`
+	Port       int32                        `json:"port"`
+	ShareLevel WorkspaceAgentPortShareLevel `json:"share_level" enums:"authenticated,public"`
+}
+
+type DeleteWorkspaceAgentPortShareRequest struct {
+	AgentName string `json:"agent_name"`
+	Port      int32  `json:"port"`
+}
+
+// GetWorkspaceAgentPortShares returns the ports shared by the owner of a
+// workspace.
+func (c *Client) GetWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) (WorkspaceAgentPortShares, error) {
+	var shares WorkspaceAgentPortShares
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), nil)
+	if err != nil {
+		return shares, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return shares, ReadBodyAsError(res)
+	}
+
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// UpsertWorkspaceAgentPortShare shares a port of a workspace agent, or updates
+// the sharing level of an already shared port.
+func (c *Client) UpsertWorkspaceAgentPortShare(ctx context.Context, workspaceID uuid.UUID, req UpsertWorkspaceAgentPortShareRequest) (WorkspaceAgentPortShare, error) {
+	var share WorkspaceAgentPortShare
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return share, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return share, ReadBodyAsError(res)
+	}
+
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// DeleteWorkspaceAgentPortShare stops sharing a port of a workspace agent.
+func (c *Client) DeleteWorkspaceAgentPortShare(ctx context.Context, workspaceID uuid.UUID, req DeleteWorkspaceAgentPortShareRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+)
+
+// WorkspaceAgentPTYInvite allows a user other than the workspace owner to
+// attach to a reconnecting PTY session.
+type WorkspaceAgentPTYInvite struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	AgentID     uuid.UUID `json:"agent_id" format:"uuid"`
+	SessionID   uuid.UUID `json:"session_id" format:"uuid"`
+	UserID      uuid.UUID `json:"user_id" format:"uuid"`
+	// ReadOnly invites can watch the session but not write to it.
+	ReadOnly  bool      `json:"read_only"`
+	CreatedBy uuid.UUID `json:"created_by" format:"uuid"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+type CreateWorkspaceAgentPTYInviteRequest struct {
+	UserID   uuid.UUID `json:"user_id" validate:"required" format:"uuid"`
+	ReadOnly bool      `json:"read_only"`
+}
+
+// WorkspaceAgentReconnectingPTYSessions lists the live reconnecting PTY
+// sessions on a workspace agent.
+func (c *Client) WorkspaceAgentReconnectingPTYSessions(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentReconnectingPTYSessionsResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/pty-sessions", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYSessionsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYSessionsResponse{}, ReadBodyAsError(res)
+	}
+	var sessions WorkspaceAgentReconnectingPTYSessionsResponse
+	return sessions, json.NewDecoder(res.Body).Decode(&sessions)
+}
+
+// CreateWorkspaceAgentPTYInvite invites a user to attach to a reconnecting
+// PTY session. Inviting a user again updates the invite.
+func (c *Client) CreateWorkspaceAgentPTYInvite(ctx context.Context, agentID, sessionID uuid.UUID, req CreateWorkspaceAgentPTYInviteRequest) (WorkspaceAgentPTYInvite, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/pty-sessions/%s/invites", agentID, sessionID), req)
+	if err != nil {
+		return WorkspaceAgentPTYInvite{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceAgentPTYInvite{}, ReadBodyAsError(res)
+	}
+	var invite WorkspaceAgentPTYInvite
+	return invite, json.NewDecoder(res.Body).Decode(&invite)
+}
+
+// WorkspaceAgentPTYInvites lists the users invited to a reconnecting PTY
+// session.
+func (c *Client) WorkspaceAgentPTYInvites(ctx context.Context, agentID, sessionID uuid.UUID) ([]WorkspaceAgentPTYInvite, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/pty-sessions/%s/invites", agentID, sessionID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var invites []WorkspaceAgentPTYInvite
+	return invites, json.NewDecoder(res.Body).Decode(&invites)
+}
+
+// DeleteWorkspaceAgentPTYInvite revokes the invite of a user to a
+// reconnecting PTY session. Connections that are already attached are not
+// closed.
+func (c *Client) DeleteWorkspaceAgentPTYInvite(ctx context.Context, agentID, sessionID, userID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaceagents/%s/pty-sessions/%s/invites/%s", agentID, sessionID, userID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UserWorkspaceAgentPTYInvites lists the reconnecting PTY sessions a user has
+// been invited to.
+func (c *Client) UserWorkspaceAgentPTYInvites(ctx context.Context, user string) ([]WorkspaceAgentPTYInvite, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/pty-invites", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var invites []WorkspaceAgentPTYInvite
+	return invites, json.NewDecoder(res.Body).Decode(&invites)
+}
+
+// @typescript-ignore:WorkspaceAgentReconnectingPTYAttachOpts
+type WorkspaceAgentReconnectingPTYAttachOpts struct {
+	AgentID   uuid.UUID
+	SessionID uuid.UUID
+	Width     uint16
+	Height    uint16
+	// ReadOnly attaches without being able to write to the session. Users
+	// with a read-only invite are always attached read-only.
+	ReadOnly bool
+}
+
+// AttachWorkspaceAgentReconnectingPTY attaches to an existing reconnecting
+// PTY session. Unlike WorkspaceAgentReconnectingPTY it never spawns a new
+// session, and users invited to the session by the workspace owner may use
+// it. It communicates using `ReconnectingPTYRequest` marshaled as JSON.
+func (c *Client) AttachWorkspaceAgentReconnectingPTY(ctx context.Context, opts WorkspaceAgentReconnectingPTYAttachOpts) (net.Conn, error) {
+	serverURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/pty-sessions/%s/attach", opts.AgentID, opts.SessionID))
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	q := serverURL.Query()
+	q.Set("width", strconv.Itoa(int(opts.Width)))
+	q.Set("height", strconv.Itoa(int(opts.Height)))
+	q.Set("read_only", strconv.FormatBool(opts.ReadOnly))
+	serverURL.RawQuery = q.Encode()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create cookie jar: %w", err)
+	}
+	jar.SetCookies(serverURL, []*http.Cookie{{
+		Name:  SessionTokenCookie,
+		Value: c.SessionToken(),
+	}})
+	httpClient := &http.Client{
+		Jar:       jar,
+		Transport: c.HTTPClient.Transport,
+	}
+	conn, res, err := websocket.Dial(ctx, serverURL.String(), &websocket.DialOptions{
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		if res == nil {
+			return nil, err
+		}
+		return nil, ReadBodyAsError(res)
+	}
+	return websocket.NetConn(context.Background(), conn, websocket.MessageBinary), nil
+}
@@ -12,6 +12,7 @@ import (
 	"net/netip"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -174,12 +175,45 @@ type WorkspaceAgent struct {
 	ConnectionTimeoutSeconds int32                 `json:"connection_timeout_seconds"`
 	TroubleshootingURL       string                `json:"troubleshooting_url"`
 	// Deprecated: Use StartupScriptBehavior instead.
-	LoginBeforeReady             bool                 `json:"login_before_ready"`
-	ShutdownScript               string               `json:"shutdown_script,omitempty"`
-	ShutdownScriptTimeoutSeconds int32                `json:"shutdown_script_timeout_seconds"`
-	Subsystems                   []AgentSubsystem     `json:"subsystems"`
-	Health                       WorkspaceAgentHealth `json:"health"` // Health reports the health of the agent.
-	DisplayApps                  []DisplayApp         `json:"display_apps"`
+	LoginBeforeReady             bool                      `json:"login_before_ready"`
+	ShutdownScript               string                    `json:"shutdown_script,omitempty"`
+	ShutdownScriptTimeoutSeconds int32                     `json:"shutdown_script_timeout_seconds"`
+	Subsystems                   []AgentSubsystem          `json:"subsystems"`
+	Health                       WorkspaceAgentHealth      `json:"health"` // Health reports the health of the agent.
+	DisplayApps                  []DisplayApp              `json:"display_apps"`
+	LogSources                   []WorkspaceAgentLogSource `json:"log_sources"`
+	Scripts                      []WorkspaceAgentScript    `json:"scripts"`
+	// AutostopInhibitors are held by processes in the workspace to keep it
+	// running past its deadline. They are only reported while the agent is
+	// connected.
+	AutostopInhibitors []WorkspaceAgentAutostopInhibitor `json:"autostop_inhibitors"`
+	// UpdateState is the state of the self-update of the agent binary.
+	UpdateState WorkspaceAgentUpdateState `json:"update_state"`
+	// UpdateError is the reason the last self-update failed.
+	UpdateError string `json:"update_error,omitempty"`
+}
+
+// WorkspaceAgentUpdateState is the state of the self-update of the agent,
+// which replaces its binary when its version differs from coderd.
+type WorkspaceAgentUpdateState string
+
+const (
+	// WorkspaceAgentUpdateStateUnknown is the state of agents that never
+	// reported one, like agents older than self-updates.
+	WorkspaceAgentUpdateStateUnknown  WorkspaceAgentUpdateState = "unknown"
+	WorkspaceAgentUpdateStateUpToDate WorkspaceAgentUpdateState = "up_to_date"
+	WorkspaceAgentUpdateStateDisabled WorkspaceAgentUpdateState = "disabled"
+	WorkspaceAgentUpdateStateUpdating WorkspaceAgentUpdateState = "updating"
+	WorkspaceAgentUpdateStateUpdated  WorkspaceAgentUpdateState = "updated"
+	WorkspaceAgentUpdateStateFailed   WorkspaceAgentUpdateState = "failed"
+)
+
+// WorkspaceAgentAutostopInhibitor extends the deadline of a workspace, up to
+// its max deadline, while it is held.
+type WorkspaceAgentAutostopInhibitor struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
 }
 
 type WorkspaceAgentHealth struct {
@@ -235,6 +269,14 @@ type DialWorkspaceAgentOptions struct {
 	// BlockEndpoints forced a direct connection through DERP. The Client may
 	// have DisableDirect set which will override this value.
 	BlockEndpoints bool
+	// EnableDNS makes the connection resolve the names of the agents of every
+	// workspace the user can read, see tailnet.AgentDNSName. The names are
+	// refreshed from coderd every DNSRefreshInterval. An agent other than the
+	// dialed one is coordinated with the connection the first time it's
+	// dialed by name.
+	EnableDNS bool
+	// DNSRefreshInterval defaults to 30 seconds.
+	DNSRefreshInterval time.Duration
 }
 
 func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, options *DialWorkspaceAgentOptions) (agentConn *WorkspaceAgentConn, err error) {
@@ -282,63 +324,27 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 	}
 	headers.Set(tokenHeader, c.SessionToken())
 	ctx, cancel := context.WithCancel(ctx)
+	coordinators := &agentCoordinators{
+		ctx:     ctx,
+		client:  c,
+		logger:  options.Logger,
+		conn:    conn,
+		headers: headers,
+		sends:   make(map[uuid.UUID]func(*tailnet.Node)),
+		agents:  make(map[uuid.UUID]*agentCoordination),
+	}
 	defer func() {
 		if err != nil {
 			cancel()
+			coordinators.close()
 		}
 	}()
+	conn.SetNodeCallback(coordinators.sendNode)
 
-	coordinateURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
+	err = coordinators.coordinate(ctx, agentID)
 	if err != nil {
-		return nil, xerrors.Errorf("parse url: %w", err)
+		return nil, err
 	}
-	closedCoordinator := make(chan struct{})
-	firstCoordinator := make(chan error)
-	go func() {
-		defer close(closedCoordinator)
-		isFirst := true
-		for retrier := retry.New(50*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
-			options.Logger.Debug(ctx, "connecting")
-			// nolint:bodyclose
-			ws, res, err := websocket.Dial(ctx, coordinateURL.String(), &websocket.DialOptions{
-				HTTPClient: c.HTTPClient,
-				HTTPHeader: headers,
-				// Need to disable compression to avoid a data-race.
-				CompressionMode: websocket.CompressionDisabled,
-			})
-			if isFirst {
-				if res != nil && res.StatusCode == http.StatusConflict {
-					firstCoordinator <- ReadBodyAsError(res)
-					return
-				}
-				isFirst = false
-				close(firstCoordinator)
-			}
-			if err != nil {
-				if errors.Is(err, context.Canceled) {
-					return
-				}
-				options.Logger.Debug(ctx, "failed to dial", slog.Error(err))
-				continue
-			}
-			sendNode, errChan := tailnet.ServeCoordinator(websocket.NetConn(ctx, ws, websocket.MessageBinary), func(nodes []*tailnet.Node) error {
-				return conn.UpdateNodes(nodes, false)
-			})
-			conn.SetNodeCallback(sendNode)
-			options.Logger.Debug(ctx, "serving coordinator")
-			err = <-errChan
-			if errors.Is(err, context.Canceled) {
-				_ = ws.Close(websocket.StatusGoingAway, "")
-				return
-			}
-			if err != nil {
-				options.Logger.Debug(ctx, "error serving coordinator", slog.Error(err))
-				_ = ws.Close(websocket.StatusGoingAway, "")
-				continue
-			}
-			_ = ws.Close(websocket.StatusGoingAway, "")
-		}
-	}()
 
 	derpMapURL, err := c.URL.Parse("/api/v2/derp-map")
 	if err != nil {
@@ -399,26 +405,55 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 		}
 	}()
 
-	err = <-firstCoordinator
-	if err != nil {
-		return nil, err
-	}
 	err = <-firstDerpMap
 	if err != nil {
 		return nil, err
 	}
 
+	closedDNS := make(chan struct{})
+	if options.EnableDNS {
+		err = coordinators.syncDNSHosts(ctx)
+		if err != nil {
+			close(closedDNS)
+			return nil, xerrors.Errorf("sync dns hosts: %w", err)
+		}
+		interval := options.DNSRefreshInterval
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		go func() {
+			defer close(closedDNS)
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				err := coordinators.syncDNSHosts(ctx)
+				if err != nil && ctx.Err() == nil {
+					options.Logger.Debug(ctx, "failed to sync dns hosts", slog.Error(err))
+				}
+			}
+		}()
+	} else {
+		close(closedDNS)
+	}
+
 	agentConn = NewWorkspaceAgentConn(conn, WorkspaceAgentConnOptions{
 		AgentID: agentID,
 		// Newer agents will listen on two IPs: WorkspaceAgentIP and an IP
 		// derived from the agents UUID. We need to use the legacy
 		// WorkspaceAgentIP here since we don't know if the agent is listening
 		// on the new IP.
-		AgentIP: WorkspaceAgentIP,
+		AgentIP:   WorkspaceAgentIP,
+		ReachAddr: coordinators.reach,
 		CloseFunc: func() error {
 			cancel()
-			<-closedCoordinator
+			coordinators.close()
 			<-closedDerpMap
+			<-closedDNS
 			return conn.Close()
 		},
 	})
@@ -431,6 +466,210 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 	return agentConn, nil
 }
 
+// agentCoordinators coordinates a connection with workspace agents. The node
+// of the connection is sent to the coordinator of every agent.
+type agentCoordinators struct {
+	ctx     context.Context
+	client  *Client
+	logger  slog.Logger
+	conn    *tailnet.Conn
+	headers http.Header
+	wg      sync.WaitGroup
+
+	nodeMu sync.Mutex
+	node   *tailnet.Node
+	sends  map[uuid.UUID]func(*tailnet.Node)
+
+	mu     sync.Mutex
+	closed bool
+	agents map[uuid.UUID]*agentCoordination
+	// dnsAgents are the agents resolved by the names set on the connection.
+	dnsAgents map[netip.Addr]uuid.UUID
+}
+
+type agentCoordination struct {
+	ready chan struct{}
+	err   error
+}
+
+// coordinate starts coordinating the connection with the agent if it isn't
+// yet, and waits until its coordinator has been dialed.
+func (a *agentCoordinators) coordinate(ctx context.Context, agentID uuid.UUID) error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return xerrors.New("connection closed")
+	}
+	co, ok := a.agents[agentID]
+	if !ok {
+		co = &agentCoordination{ready: make(chan struct{})}
+		a.agents[agentID] = co
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			a.serve(agentID, co)
+		}()
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-co.ready:
+		return co.err
+	}
+}
+
+// reach makes an address resolved from an agent name reachable over the
+// connection.
+func (a *agentCoordinators) reach(ctx context.Context, ip netip.Addr) error {
+	a.mu.Lock()
+	agentID, ok := a.dnsAgents[ip]
+	a.mu.Unlock()
+	if !ok {
+		return xerrors.Errorf("no agent has the address %s", ip)
+	}
+	err := a.coordinate(ctx, agentID)
+	if err != nil {
+		return xerrors.Errorf("coordinate agent %s: %w", agentID, err)
+	}
+	if !a.conn.AwaitReachable(ctx, ip) {
+		return xerrors.Errorf("workspace agent %s not reachable in time: %v", agentID, ctx.Err())
+	}
+	return nil
+}
+
+// serve serves the coordinator of the agent until the context of the
+// connection is done.
+func (a *agentCoordinators) serve(agentID uuid.UUID, co *agentCoordination) {
+	isFirst := true
+	ready := func(err error) {
+		isFirst = false
+		if err != nil {
+			// The agent is coordinated again the next time it's dialed.
+			a.mu.Lock()
+			delete(a.agents, agentID)
+			a.mu.Unlock()
+		}
+		co.err = err
+		close(co.ready)
+	}
+	defer func() {
+		if isFirst {
+			ready(xerrors.Errorf("coordinate: %w", a.ctx.Err()))
+		}
+	}()
+
+	coordinateURL, err := a.client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
+	if err != nil {
+		ready(xerrors.Errorf("parse url: %w", err))
+		return
+	}
+	for retrier := retry.New(50*time.Millisecond, 10*time.Second); retrier.Wait(a.ctx); {
+		a.logger.Debug(a.ctx, "connecting", slog.F("agent_id", agentID))
+		// nolint:bodyclose
+		ws, res, err := websocket.Dial(a.ctx, coordinateURL.String(), &websocket.DialOptions{
+			HTTPClient: a.client.HTTPClient,
+			HTTPHeader: a.headers,
+			// Need to disable compression to avoid a data-race.
+			CompressionMode: websocket.CompressionDisabled,
+		})
+		if isFirst {
+			if res != nil && res.StatusCode == http.StatusConflict {
+				ready(ReadBodyAsError(res))
+				return
+			}
+			ready(nil)
+		}
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			a.logger.Debug(a.ctx, "failed to dial", slog.F("agent_id", agentID), slog.Error(err))
+			continue
+		}
+		sendNode, errChan := tailnet.ServeCoordinator(websocket.NetConn(a.ctx, ws, websocket.MessageBinary), func(nodes []*tailnet.Node) error {
+			return a.conn.UpdateNodes(nodes, false)
+		})
+		a.setSendNode(agentID, sendNode)
+		a.logger.Debug(a.ctx, "serving coordinator", slog.F("agent_id", agentID))
+		err = <-errChan
+		a.setSendNode(agentID, nil)
+		if errors.Is(err, context.Canceled) {
+			_ = ws.Close(websocket.StatusGoingAway, "")
+			return
+		}
+		if err != nil {
+			a.logger.Debug(a.ctx, "error serving coordinator", slog.F("agent_id", agentID), slog.Error(err))
+			_ = ws.Close(websocket.StatusGoingAway, "")
+			continue
+		}
+		_ = ws.Close(websocket.StatusGoingAway, "")
+	}
+}
+
+// sendNode is the node callback of the connection.
+func (a *agentCoordinators) sendNode(node *tailnet.Node) {
+	a.nodeMu.Lock()
+	defer a.nodeMu.Unlock()
+	a.node = node
+	for _, send := range a.sends {
+		send(node)
+	}
+}
+
+// setSendNode sets how the node is sent to the coordinator of the agent, and
+// sends the last node to it. A nil send removes the coordinator.
+func (a *agentCoordinators) setSendNode(agentID uuid.UUID, send func(*tailnet.Node)) {
+	a.nodeMu.Lock()
+	defer a.nodeMu.Unlock()
+	if send == nil {
+		delete(a.sends, agentID)
+		return
+	}
+	a.sends[agentID] = send
+	if a.node != nil {
+		send(a.node)
+	}
+}
+
+// syncDNSHosts sets the names of the agents of every workspace the user can
+// read on the connection.
+func (a *agentCoordinators) syncDNSHosts(ctx context.Context) error {
+	res, err := a.client.Workspaces(ctx, WorkspaceFilter{})
+	if err != nil {
+		return xerrors.Errorf("list workspaces: %w", err)
+	}
+	hosts := make(map[string][]netip.Addr)
+	dnsAgents := make(map[netip.Addr]uuid.UUID)
+	for _, workspace := range res.Workspaces {
+		for _, resource := range workspace.LatestBuild.Resources {
+			for _, agent := range resource.Agents {
+				ip := tailnet.IPFromUUID(agent.ID)
+				hosts[tailnet.AgentDNSName(agent.Name, workspace.Name, workspace.OwnerName)] = []netip.Addr{ip}
+				dnsAgents[ip] = agent.ID
+			}
+		}
+	}
+	err = a.conn.SetDNSHosts(hosts)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.dnsAgents = dnsAgents
+	a.mu.Unlock()
+	return nil
+}
+
+// close waits for the coordinators to stop once the context of the
+// connection is done.
+func (a *agentCoordinators) close() {
+	a.mu.Lock()
+	a.closed = true
+	a.mu.Unlock()
+	a.wg.Wait()
+}
+
 // WatchWorkspaceAgentMetadata watches the metadata of a workspace agent.
 // The returned channel will be closed when the context is canceled. Exactly
 // one error will be sent on the error channel. The metadata channel is never closed.
@@ -762,6 +1001,7 @@ type WorkspaceAgentLog struct {
 	CreatedAt time.Time `json:"created_at" format:"date-time"`
 	Output    string    `json:"output"`
 	Level     LogLevel  `json:"level"`
+	SourceID  uuid.UUID `json:"source_id" format:"uuid"`
 }
 
 type AgentSubsystem string
@@ -781,13 +1021,63 @@ func (s AgentSubsystem) Valid() bool {
 	}
 }
 
-type WorkspaceAgentLogSource string
+// WorkspaceAgentLogSource identifies where a set of agent logs came from,
+// e.g. a script declared in the template or an external tool.
+type WorkspaceAgentLogSource struct {
+	WorkspaceAgentID uuid.UUID `json:"workspace_agent_id" format:"uuid"`
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	CreatedAt        time.Time `json:"created_at" format:"date-time"`
+	DisplayName      string    `json:"display_name"`
+	Icon             string    `json:"icon"`
+}
+
+// WorkspaceAgentLogFile is a file in the workspace the agent tails into the
+// workspace agent logs. Lines appended to it are attributed to its log
+// source.
+type WorkspaceAgentLogFile struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	LogSourceID uuid.UUID `json:"log_source_id" format:"uuid"`
+	Path        string    `json:"path"`
+}
+
+// WorkspaceAgentScriptStatus is the status of the most recent execution
+// of a workspace agent script.
+type WorkspaceAgentScriptStatus string
 
 const (
-	WorkspaceAgentLogSourceStartupScript  WorkspaceAgentLogSource = "startup_script"
-	WorkspaceAgentLogSourceShutdownScript WorkspaceAgentLogSource = "shutdown_script"
-	WorkspaceAgentLogSourceKubernetes     WorkspaceAgentLogSource = "kubernetes"
-	WorkspaceAgentLogSourceEnvbox         WorkspaceAgentLogSource = "envbox"
-	WorkspaceAgentLogSourceEnvbuilder     WorkspaceAgentLogSource = "envbuilder"
-	WorkspaceAgentLogSourceExternal       WorkspaceAgentLogSource = "external"
+	WorkspaceAgentScriptStatusPending     WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning     WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusOK          WorkspaceAgentScriptStatus = "ok"
+	WorkspaceAgentScriptStatusExitFailure WorkspaceAgentScriptStatus = "exit_failure"
+	WorkspaceAgentScriptStatusTimedOut    WorkspaceAgentScriptStatus = "timed_out"
 )
+
+// Finished returns true if the script is no longer running.
+func (s WorkspaceAgentScriptStatus) Finished() bool {
+	switch s {
+	case WorkspaceAgentScriptStatusOK, WorkspaceAgentScriptStatusExitFailure, WorkspaceAgentScriptStatusTimedOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// WorkspaceAgentScript is a script declared by the template that the
+// agent runs on start, on stop, or on a cron schedule.
+type WorkspaceAgentScript struct {
+	ID               uuid.UUID     `json:"id" format:"uuid"`
+	LogSourceID      uuid.UUID     `json:"log_source_id" format:"uuid"`
+	LogPath          string        `json:"log_path"`
+	Script           string        `json:"script"`
+	Cron             string        `json:"cron"`
+	RunOnStart       bool          `json:"run_on_start"`
+	RunOnStop        bool          `json:"run_on_stop"`
+	StartBlocksLogin bool          `json:"start_blocks_login"`
+	Timeout          time.Duration `json:"timeout"`
+	// Status, ExitCode, StartedAt and EndedAt describe the most recent
+	// execution of the script, as reported by the agent.
+	Status    WorkspaceAgentScriptStatus `json:"status"`
+	ExitCode  int32                      `json:"exit_code"`
+	StartedAt *time.Time                 `json:"started_at,omitempty" format:"date-time"`
+	EndedAt   *time.Time                 `json:"ended_at,omitempty" format:"date-time"`
+}
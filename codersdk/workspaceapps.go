@@ -51,6 +51,12 @@ type WorkspaceApp struct {
 type Healthcheck struct {
 	// URL specifies the endpoint to check for the app health.
 	URL string `json:"url"`
+	// TCP specifies an address to connect to over TCP instead of making an
+	// HTTP request, e.g. "localhost:5432".
+	TCP string `json:"tcp,omitempty"`
+	// Command specifies a command to run instead of making an HTTP request.
+	// The app is healthy if the command exits with a zero exit code.
+	Command string `json:"command,omitempty"`
 	// Interval specifies the seconds between each health check.
 	Interval int32 `json:"interval"`
 	// Threshold specifies the number of consecutive failed health checks before returning "unhealthy".
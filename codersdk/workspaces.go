@@ -29,6 +29,7 @@ type Workspace struct {
 	TemplateIcon                         string         `json:"template_icon"`
 	TemplateAllowUserCancelWorkspaceJobs bool           `json:"template_allow_user_cancel_workspace_jobs"`
 	TemplateActiveVersionID              uuid.UUID      `json:"template_active_version_id" format:"uuid"`
+	TemplateDeprecationMessage           string         `json:"template_deprecation_message"`
 	LatestBuild                          WorkspaceBuild `json:"latest_build"`
 	Outdated                             bool           `json:"outdated"`
 	Name                                 string         `json:"name"`
@@ -48,12 +49,22 @@ type Workspace struct {
 	// Health shows the health of the workspace and information about
 	// what is causing an unhealthy status.
 	Health WorkspaceHealth `json:"health"`
+	// AutomaticUpdates is whether the workspace is updated to the active
+	// version of its template when it is started.
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" enums:"always,never"`
 }
 
 func (w Workspace) FullName() string {
 	return fmt.Sprintf("%s/%s", w.OwnerName, w.Name)
 }
 
+type AutomaticUpdates string
+
+const (
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	AutomaticUpdatesNever  AutomaticUpdates = "never"
+)
+
 type WorkspaceHealth struct {
 	Healthy       bool        `json:"healthy" example:"false"`      // Healthy is true if the workspace is healthy.
 	FailingAgents []uuid.UUID `json:"failing_agents" format:"uuid"` // FailingAgents lists the IDs of the agents that are failing, if any.
@@ -275,6 +286,25 @@ func (c *Client) UpdateWorkspaceTTL(ctx context.Context, id uuid.UUID, req Updat
 	return nil
 }
 
+type UpdateWorkspaceAutomaticUpdatesRequest struct {
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" validate:"oneof=always never,required"`
+}
+
+// UpdateWorkspaceAutomaticUpdates sets whether the workspace is updated to
+// the active version of its template when it is started.
+func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UUID, req UpdateWorkspaceAutomaticUpdatesRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/autoupdates", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace automatic updates: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // PutExtendWorkspaceRequest is a request to extend the deadline of
 // the active workspace build.
 type PutExtendWorkspaceRequest struct {
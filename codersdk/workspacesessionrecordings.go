@@ -0,0 +1,84 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceSessionRecordingType string
+
+const (
+	WorkspaceSessionRecordingTypeSSH             WorkspaceSessionRecordingType = "ssh"
+	WorkspaceSessionRecordingTypeReconnectingPTY WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+// WorkspaceSessionRecording is a recording of an interactive session on a
+// workspace agent. The recording itself is served in asciicast v2 format by
+// WorkspaceSessionRecordingCast.
+type WorkspaceSessionRecording struct {
+	ID          uuid.UUID                     `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID                     `json:"workspace_id" format:"uuid"`
+	AgentID     uuid.UUID                     `json:"agent_id" format:"uuid"`
+	Type        WorkspaceSessionRecordingType `json:"type" enums:"ssh,reconnecting_pty"`
+	// ReconnectID is the ID of the reconnecting PTY session that was
+	// recorded.
+	ReconnectID *uuid.UUID `json:"reconnect_id,omitempty" format:"uuid"`
+	Command     string     `json:"command"`
+	// IncludesInput is true if what the user typed was recorded too.
+	IncludesInput bool `json:"includes_input"`
+	// Size is the size of the recording in bytes.
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+	// EndedAt is unset while the session is still being recorded.
+	EndedAt *time.Time `json:"ended_at,omitempty" format:"date-time"`
+}
+
+// WorkspaceSessionRecordings lists the session recordings of a workspace,
+// newest first.
+func (c *Client) WorkspaceSessionRecordings(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []WorkspaceSessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceSessionRecording returns a session recording.
+func (c *Client) WorkspaceSessionRecording(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/session-recordings/%s", id), nil)
+	if err != nil {
+		return WorkspaceSessionRecording{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceSessionRecording{}, ReadBodyAsError(res)
+	}
+	var recording WorkspaceSessionRecording
+	return recording, json.NewDecoder(res.Body).Decode(&recording)
+}
+
+// WorkspaceSessionRecordingCast returns the asciicast v2 file of a session
+// recording. The caller must close the returned reader.
+func (c *Client) WorkspaceSessionRecordingCast(ctx context.Context, id uuid.UUID) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/session-recordings/%s/cast", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
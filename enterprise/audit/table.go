@@ -16,15 +16,18 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"GitSSHKey":               {codersdk.AuditActionCreate},
+	"Template":                {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionConnect},
+	"WorkspaceBuild":          {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                   {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                  {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                 {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"CustomRole":              {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OAuth2ProviderApp":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OAuth2ProviderAppSecret": {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -73,6 +76,11 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"max_ttl":                           ActionTrack,
 		"autostop_requirement_days_of_week": ActionTrack,
 		"autostop_requirement_weeks":        ActionTrack,
+		"max_port_sharing_level":            ActionTrack,
+		"record_sessions":                   ActionTrack,
+		"deprecation_message":               ActionTrack,
+		"deprecated_at":                     ActionTrack,
+		"require_active_version":            ActionTrack,
 		"created_by":                        ActionTrack,
 		"created_by_username":               ActionIgnore,
 		"created_by_avatar_url":             ActionIgnore,
@@ -114,6 +122,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"last_seen_at":         ActionIgnore,
 		"deleted":              ActionTrack,
 		"quiet_hours_schedule": ActionTrack,
+		"is_service_account":   ActionTrack,
 	},
 	&database.Workspace{}: {
 		"id":                 ActionTrack,
@@ -129,6 +138,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"last_used_at":       ActionIgnore,
 		"dormant_at":         ActionTrack,
 		"deleting_at":        ActionTrack,
+		"automatic_updates":  ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                      ActionIgnore,
@@ -171,6 +181,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"ip_address":       ActionIgnore,
 		"scope":            ActionIgnore,
 		"token_name":       ActionIgnore,
+		"scope_rules":      ActionIgnore,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -179,6 +190,33 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"to_login_type":   ActionTrack,
 		"user_id":         ActionTrack,
 	},
+	&database.CustomRole{}: {
+		"id":               ActionTrack,
+		"name":             ActionTrack,
+		"display_name":     ActionTrack,
+		"organization_id":  ActionIgnore, // Never changes.
+		"site_permissions": ActionTrack,
+		"org_permissions":  ActionTrack,
+		"user_permissions": ActionTrack,
+		"created_at":       ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":       ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.OAuth2ProviderApp{}: {
+		"id":            ActionTrack,
+		"created_at":    ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":    ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"name":          ActionTrack,
+		"icon":          ActionTrack,
+		"redirect_uris": ActionTrack,
+	},
+	&database.OAuth2ProviderAppSecret{}: {
+		"id":             ActionIgnore,
+		"app_id":         ActionIgnore,
+		"created_at":     ActionIgnore,
+		"last_used_at":   ActionIgnore, // Changes whenever the secret is used.
+		"hashed_secret":  ActionIgnore,
+		"display_secret": ActionIgnore,
+	},
 	// TODO: track an ID here when the below ticket is completed:
 	// https://github.com/coder/coder/pull/6012
 	&database.License{}: {
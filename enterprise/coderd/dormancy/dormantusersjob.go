@@ -11,6 +11,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
 )
 
 const (
@@ -22,13 +23,13 @@ const (
 
 // CheckInactiveUsers function updates status of inactive users from active to dormant
 // using default parameters.
-func CheckInactiveUsers(ctx context.Context, logger slog.Logger, db database.Store) func() {
-	return CheckInactiveUsersWithOptions(ctx, logger, db, jobInterval, accountDormancyPeriod)
+func CheckInactiveUsers(ctx context.Context, logger slog.Logger, db database.Store, enq *notifications.Enqueuer) func() {
+	return CheckInactiveUsersWithOptions(ctx, logger, db, enq, jobInterval, accountDormancyPeriod)
 }
 
 // CheckInactiveUsersWithOptions function updates status of inactive users from active to dormant
-// using provided parameters.
-func CheckInactiveUsersWithOptions(ctx context.Context, logger slog.Logger, db database.Store, checkInterval, dormancyPeriod time.Duration) func() {
+// using provided parameters. Dormant users are notified if enq is not nil.
+func CheckInactiveUsersWithOptions(ctx context.Context, logger slog.Logger, db database.Store, enq *notifications.Enqueuer, checkInterval, dormancyPeriod time.Duration) func() {
 	logger = logger.Named("dormancy")
 
 	ctx, cancelFunc := context.WithCancel(ctx)
@@ -59,6 +60,20 @@ func CheckInactiveUsersWithOptions(ctx context.Context, logger slog.Logger, db d
 
 			for _, u := range updatedUsers {
 				logger.Info(ctx, "account has been marked as dormant", slog.F("email", u.Email), slog.F("last_seen_at", u.LastSeenAt))
+				if enq == nil {
+					continue
+				}
+				err = enq.Enqueue(ctx, notifications.Notification{
+					UserID: u.ID,
+					Kind:   database.NotificationKindAccountDormant,
+					Labels: map[string]string{
+						"email":        u.Email,
+						"last_seen_at": notifications.FormatTime(u.LastSeenAt),
+					},
+				})
+				if err != nil {
+					logger.Warn(ctx, "can't notify dormant user", slog.F("email", u.Email), slog.Error(err))
+				}
 			}
 			logger.Debug(ctx, "checking user accounts is done", slog.F("num_dormant_accounts", len(updatedUsers)), slog.F("execution_time", time.Since(startTime)))
 		}
@@ -12,6 +12,8 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/enterprise/coderd/dormancy"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -43,7 +45,8 @@ func TestCheckInactiveUsers(t *testing.T) {
 	suspendedUser3 := setupUser(ctx, t, db, "suspended-user-3@coder.com", database.UserStatusSuspended, time.Now().Add(-dormancyPeriod).Add(-6*time.Hour))
 
 	// Run the periodic job
-	closeFunc := dormancy.CheckInactiveUsersWithOptions(ctx, logger, db, interval, dormancyPeriod)
+	enqueuer := notifications.NewEnqueuer(db, pubsub.NewInMemory(), false)
+	closeFunc := dormancy.CheckInactiveUsersWithOptions(ctx, logger, db, enqueuer, interval, dormancyPeriod)
 	t.Cleanup(closeFunc)
 
 	var rows []database.GetUsersRow
@@ -81,6 +84,22 @@ func TestCheckInactiveUsers(t *testing.T) {
 		suspendedUser3,
 	}
 	require.ElementsMatch(t, allUsers, expectedUsers)
+
+	// Verify only dormant users were notified
+	for _, user := range []database.User{inactiveUser1, inactiveUser2, inactiveUser3} {
+		var messages []database.NotificationMessage
+		require.Eventually(t, func() bool {
+			messages, err = db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{UserID: user.ID})
+			return err == nil && len(messages) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, database.NotificationKindAccountDormant, messages[0].Kind)
+		require.Contains(t, messages[0].Title, user.Email)
+	}
+	for _, user := range []database.User{activeUser1, suspendedUser1} {
+		count, err := db.CountUnreadNotificationMessagesByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Zero(t, count)
+	}
 }
 
 func setupUser(ctx context.Context, t *testing.T, db database.Store, email string, status database.UserStatus, lastSeenAt time.Time) database.User {
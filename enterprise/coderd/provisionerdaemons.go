@@ -283,6 +283,7 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		provisionerdserver.Options{
 			GitAuthConfigs: api.GitAuthConfigs,
 			OIDCConfig:     api.OIDCConfig,
+			Notifications:  api.NotificationsEnqueuer,
 		},
 	)
 	if err != nil {
@@ -228,6 +228,7 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
+	api.AGPL.WakeWebhooks(ctx)
 
 	sUser.ID = dbUser.ID.String()
 	sUser.UserName = dbUser.Username
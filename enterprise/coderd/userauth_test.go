@@ -12,6 +12,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/ldaptest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -601,6 +602,146 @@ func TestGroupSync(t *testing.T) {
 
 // oidcTestRunner is just a helper to setup and run oidc tests.
 // An actual Coderd instance is used to run the tests.
+// nolint:bodyclose
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Groups", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			devsDN = "cn=devs,ou=groups,dc=coder,dc=com"
+			opsDN  = "cn=ops,ou=groups,dc=coder,dc=com"
+		)
+		setup := func(t *testing.T, mutate func(cfg *coderd.LDAPConfig)) (*codersdk.Client, *ldaptest.Server) {
+			t.Helper()
+
+			srv := ldaptest.New(t, ldaptest.Entry{
+				DN:       "uid=alice,ou=people,dc=coder,dc=com",
+				Password: "hunter2",
+				Attributes: map[string][]string{
+					"uid":      {"alice"},
+					"mail":     {"alice@coder.com"},
+					"memberOf": {devsDN, opsDN},
+				},
+			})
+			cfg := &coderd.LDAPConfig{
+				URL:               srv.URL,
+				UserSearchBaseDN:  "ou=people,dc=coder,dc=com",
+				UserSearchFilter:  "(uid={username})",
+				UsernameAttribute: "uid",
+				EmailAttribute:    "mail",
+				AllowSignups:      true,
+				GroupAttribute:    "memberOf",
+			}
+			if mutate != nil {
+				mutate(cfg)
+			}
+			owner, _ := coderdenttest.New(t, &coderdenttest.Options{
+				Options: &coderdtest.Options{
+					LDAPConfig: cfg,
+				},
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					Features: license.Features{
+						codersdk.FeatureTemplateRBAC: 1,
+					},
+				},
+			})
+			return owner, srv
+		}
+		login := func(t *testing.T, client *codersdk.Client) {
+			t.Helper()
+
+			ctx := testutil.Context(t, testutil.WaitMedium)
+			_, err := codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+				Username: "alice",
+				Password: "hunter2",
+			})
+			require.NoError(t, err)
+		}
+		assertGroups := func(t *testing.T, client *codersdk.Client, groups []string) {
+			t.Helper()
+
+			ctx := testutil.Context(t, testutil.WaitMedium)
+			user, err := client.User(ctx, "alice")
+			require.NoError(t, err)
+			allGroups, err := client.GroupsByOrganization(ctx, user.OrganizationIDs[0])
+			require.NoError(t, err)
+
+			userInGroups := []string{}
+			for _, g := range allGroups {
+				for _, mem := range g.Members {
+					if mem.ID == user.ID {
+						userInGroups = append(userInGroups, g.Name)
+					}
+				}
+			}
+			require.ElementsMatch(t, append(groups, database.EveryoneGroup), userInGroups, "expected groups")
+		}
+
+		t.Run("AssignsMapped", func(t *testing.T) {
+			t.Parallel()
+
+			owner, _ := setup(t, func(cfg *coderd.LDAPConfig) {
+				cfg.GroupMapping = map[string]string{devsDN: "devs"}
+			})
+			ctx := testutil.Context(t, testutil.WaitShort)
+			admin, err := owner.User(ctx, "me")
+			require.NoError(t, err)
+			_, err = owner.CreateGroup(ctx, admin.OrganizationIDs[0], codersdk.CreateGroupRequest{
+				Name: "devs",
+			})
+			require.NoError(t, err)
+
+			login(t, owner)
+			// The unmapped group does not exist, so it is ignored.
+			assertGroups(t, owner, []string{"devs"})
+		})
+
+		t.Run("AddThenRemoveOnReAuth", func(t *testing.T) {
+			t.Parallel()
+
+			owner, srv := setup(t, func(cfg *coderd.LDAPConfig) {
+				cfg.GroupMapping = map[string]string{devsDN: "devs"}
+			})
+			ctx := testutil.Context(t, testutil.WaitShort)
+			admin, err := owner.User(ctx, "me")
+			require.NoError(t, err)
+			_, err = owner.CreateGroup(ctx, admin.OrganizationIDs[0], codersdk.CreateGroupRequest{
+				Name: "devs",
+			})
+			require.NoError(t, err)
+
+			login(t, owner)
+			assertGroups(t, owner, []string{"devs"})
+
+			srv.Put(ldaptest.Entry{
+				DN:       "uid=alice,ou=people,dc=coder,dc=com",
+				Password: "hunter2",
+				Attributes: map[string][]string{
+					"uid":  {"alice"},
+					"mail": {"alice@coder.com"},
+				},
+			})
+			login(t, owner)
+			assertGroups(t, owner, []string{})
+		})
+
+		t.Run("AutoCreateFiltered", func(t *testing.T) {
+			t.Parallel()
+
+			owner, _ := setup(t, func(cfg *coderd.LDAPConfig) {
+				cfg.GroupMapping = map[string]string{devsDN: "devs", opsDN: "ops"}
+				cfg.CreateMissingGroups = true
+				cfg.GroupFilter = regexp.MustCompile("^devs$")
+			})
+
+			login(t, owner)
+			assertGroups(t, owner, []string{"devs"})
+		})
+	})
+}
+
 type oidcTestRunner struct {
 	AdminClient *codersdk.Client
 	AdminUser   codersdk.User
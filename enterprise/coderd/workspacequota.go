@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionerd/proto"
@@ -47,6 +48,17 @@ func (c *committer) CommitQuota(
 		permit   bool
 	)
 	err = c.Database.InTx(func(s database.Store) error {
+		// Prebuilt workspaces don't count against any quota until they are
+		// claimed. The start build of the claim commits the quota of the new
+		// owner.
+		if workspace.OwnerID == prebuilds.OwnerID {
+			permit = true
+			return s.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
+				ID:        nextBuild.ID,
+				DailyCost: request.DailyCost,
+			})
+		}
+
 		var err error
 		consumed, err = s.GetQuotaConsumedForUser(ctx, workspace.OwnerID)
 		if err != nil {
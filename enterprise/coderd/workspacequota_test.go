@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
@@ -233,6 +235,82 @@ func TestWorkspaceQuota(t *testing.T) {
 		verifyQuota(ctx, t, client, 4, 4)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
 	})
+
+	t.Run("ClaimPrebuild", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		tickCh := make(chan time.Time)
+		defer close(tickCh)
+		statsCh := make(chan prebuilds.Stats)
+		max := 1
+		client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				PrebuildsTicker: tickCh,
+				PrebuildsStats:  statsCh,
+			},
+			UserWorkspaceQuota: max,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		coderdtest.NewProvisionerDaemon(t, api.AGPL)
+
+		_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(2),
+		})
+		require.NoError(t, err)
+		verifyQuota(ctx, t, client, 0, 2)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  planWithCost(2),
+			ProvisionApply: applyWithCost(2),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		_, err = client.CreateWorkspacePrebuildPool(ctx, template.ID, codersdk.CreateWorkspacePrebuildPoolRequest{
+			TemplateVersionID: version.ID,
+			DesiredInstances:  1,
+		})
+		require.NoError(t, err)
+
+		prebuild := func() codersdk.Workspace {
+			tickCh <- time.Now()
+			stats := <-statsCh
+			require.NoError(t, stats.Error)
+			require.Len(t, stats.CreatedWorkspaceIDs, 1)
+			prebuilt := coderdtest.MustWorkspace(t, client, stats.CreatedWorkspaceIDs[0])
+			build := coderdtest.AwaitWorkspaceBuildJob(t, client, prebuilt.LatestBuild.ID)
+			require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+			return prebuilt
+		}
+
+		// Prebuilt workspaces don't count against any quota.
+		prebuilt := prebuild()
+		verifyQuota(ctx, t, client, 0, 2)
+
+		// Claiming starts the workspace as the new owner, which commits their
+		// quota.
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		require.Equal(t, prebuilt.ID, workspace.ID)
+		require.NotEqual(t, prebuilt.LatestBuild.ID, workspace.LatestBuild.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+		verifyQuota(ctx, t, client, 2, 2)
+
+		// The next claim exceeds the quota.
+		prebuilt = prebuild()
+		workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		require.Equal(t, prebuilt.ID, workspace.ID)
+		build = coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+		require.Contains(t, build.Job.Error, "quota")
+		verifyQuota(ctx, t, client, 2, 2)
+	})
 }
 
 func planWithCost(cost int32) []*proto.Response {
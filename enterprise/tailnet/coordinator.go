@@ -8,6 +8,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"sync"
 
 	"github.com/google/uuid"
@@ -42,6 +43,8 @@ func NewCoordinator(logger slog.Logger, ps pubsub.Pubsub) (agpl.Coordinator, err
 		agentNameCache:           nameCache,
 		clients:                  map[uuid.UUID]agpl.Queue{},
 		clientsToAgents:          map[uuid.UUID]map[uuid.UUID]agpl.Queue{},
+		clientUsers:              map[uuid.UUID]uuid.UUID{},
+		remoteClientUsers:        map[uuid.UUID]map[uuid.UUID]clientUserUpdate{},
 		legacyAgents:             map[uuid.UUID]struct{}{},
 	}
 
@@ -122,6 +125,12 @@ type haCoordinator struct {
 	clients map[uuid.UUID]agpl.Queue
 	// clientsToAgents is an index of clients to all of their subscribed agents.
 	clientsToAgents map[uuid.UUID]map[uuid.UUID]agpl.Queue
+	// clientUsers maps the IDs of local clients to the users they
+	// authenticated as.
+	clientUsers map[uuid.UUID]uuid.UUID
+	// remoteClientUsers maps agent IDs to the clients of other replicas that
+	// coordinate with them, and the users they authenticated as.
+	remoteClientUsers map[uuid.UUID]map[uuid.UUID]clientUserUpdate
 
 	// agentNameCache holds a cache of agent names. If one of them disappears,
 	// it's helpful to have a name cached for debugging.
@@ -153,6 +162,13 @@ func (c *haCoordinator) agentLogger(agent uuid.UUID) slog.Logger {
 // ServeClient accepts a WebSocket connection that wants to connect to an agent
 // with the specified ID.
 func (c *haCoordinator) ServeClient(conn net.Conn, id, agentID uuid.UUID) error {
+	return c.ServeUserClient(conn, id, agentID, uuid.Nil)
+}
+
+// ServeUserClient is ServeClient for a client that authenticated as the user.
+// The user is published to the other replicas along with the node of the
+// client.
+func (c *haCoordinator) ServeUserClient(conn net.Conn, id, agentID, userID uuid.UUID) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	logger := c.clientLogger(id, agentID)
@@ -162,6 +178,11 @@ func (c *haCoordinator) ServeClient(conn net.Conn, id, agentID uuid.UUID) error
 
 	c.addClient(id, tc)
 	defer c.clientDisconnected(id)
+	if userID != uuid.Nil {
+		c.mutex.Lock()
+		c.clientUsers[id] = userID
+		c.mutex.Unlock()
+	}
 
 	agentNode, err := c.clientSubscribeToAgent(tc, agentID)
 	if err != nil {
@@ -200,10 +221,43 @@ func (c *haCoordinator) initOrSetAgentConnectionSocketLocked(agentID uuid.UUID,
 	c.clientsToAgents[enq.UniqueID()][agentID] = c.agentSockets[agentID]
 }
 
+// ClientUser returns the user of the client that announced the address while
+// coordinating with the agent, through this replica or any other.
+func (c *haCoordinator) ClientUser(_ context.Context, agentID uuid.UUID, addr netip.Addr) (uuid.UUID, bool, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	for id := range c.agentToConnectionSockets[agentID] {
+		userID, ok := c.clientUsers[id]
+		if !ok {
+			continue
+		}
+		node, ok := c.nodes[id]
+		if ok && node.HasAddress(addr) {
+			return userID, true, nil
+		}
+	}
+	for _, update := range c.remoteClientUsers[agentID] {
+		if update.Node.HasAddress(addr) {
+			return update.UserID, true, nil
+		}
+	}
+	return uuid.Nil, false, nil
+}
+
 func (c *haCoordinator) clientDisconnected(id uuid.UUID) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if userID, ok := c.clientUsers[id]; ok {
+		for agentID := range c.clientsToAgents[id] {
+			err := c.publishClientUser(agentID, clientUserUpdate{ClientID: id, UserID: userID})
+			if err != nil {
+				c.log.Error(context.Background(), "publish client user", slog.Error(err), slog.F("agent_id", agentID))
+			}
+		}
+		delete(c.clientUsers, id)
+	}
+
 	for agentID := range c.clientsToAgents[id] {
 		// Clean all traces of this connection from the map.
 		delete(c.nodes, id)
@@ -255,6 +309,13 @@ func (c *haCoordinator) clientNodeUpdate(id uuid.UUID, node *agpl.Node) error {
 				c.log.Error(context.Background(), "enqueue node to agent", slog.Error(err), slog.F("agent_id", agentID))
 			}
 		}
+
+		if userID, ok := c.clientUsers[id]; ok {
+			err := c.publishClientUser(agentID, clientUserUpdate{ClientID: id, UserID: userID, Node: node})
+			if err != nil {
+				c.log.Error(context.Background(), "publish client user", slog.Error(err), slog.F("agent_id", agentID))
+			}
+		}
 	}
 
 	return nil
@@ -479,6 +540,29 @@ func (c *haCoordinator) publishNodesToAgent(recipient uuid.UUID, nodes []*agpl.N
 	return nil
 }
 
+// clientUserUpdate is published when a client that authenticated as a user
+// updates its node, so that every replica can look up the user of the client.
+type clientUserUpdate struct {
+	ClientID uuid.UUID `json:"client_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	// Node is nil once the client disconnected.
+	Node *agpl.Node `json:"node"`
+}
+
+func (c *haCoordinator) publishClientUser(agentID uuid.UUID, update clientUserUpdate) error {
+	msg, err := c.formatClientUser(agentID, update)
+	if err != nil {
+		return xerrors.Errorf("format publish message: %w", err)
+	}
+
+	err = c.pubsub.Publish("wireguard_peers", msg)
+	if err != nil {
+		return xerrors.Errorf("publish message: %w", err)
+	}
+
+	return nil
+}
+
 func (c *haCoordinator) publishAgentHello(id uuid.UUID) error {
 	msg, err := c.formatAgentHello(id)
 	if err != nil {
@@ -643,6 +727,35 @@ func (c *haCoordinator) handlePubsubMessage(ctx context.Context, message []byte)
 			c.log.Error(ctx, "handle agent update", slog.Error(err))
 			return
 		}
+	case "clientuser":
+		agentUUID, err := uuid.ParseBytes(agentID)
+		if err != nil {
+			c.log.Error(ctx, "invalid agent id", slog.F("id", string(agentID)))
+			return
+		}
+
+		var update clientUserUpdate
+		err = json.Unmarshal(nodeJSON, &update)
+		if err != nil {
+			c.log.Error(ctx, "invalid client user JSON", slog.F("id", agentID), slog.Error(err))
+			return
+		}
+
+		c.mutex.Lock()
+		clientUsers, ok := c.remoteClientUsers[agentUUID]
+		if update.Node != nil {
+			if !ok {
+				clientUsers = map[uuid.UUID]clientUserUpdate{}
+				c.remoteClientUsers[agentUUID] = clientUsers
+			}
+			clientUsers[update.ClientID] = update
+		} else if ok {
+			delete(clientUsers, update.ClientID)
+			if len(clientUsers) == 0 {
+				delete(c.remoteClientUsers, agentUUID)
+			}
+		}
+		c.mutex.Unlock()
 	default:
 		c.log.Error(ctx, "unknown peer event", slog.F("name", string(eventType)))
 	}
@@ -663,6 +776,21 @@ func (c *haCoordinator) formatCallMeMaybe(recipient uuid.UUID, nodes []*agpl.Nod
 	return buf.Bytes(), nil
 }
 
+// format: <coordinator id>|clientuser|<agent id>|<client user json>
+func (c *haCoordinator) formatClientUser(agentID uuid.UUID, update clientUserUpdate) ([]byte, error) {
+	buf := bytes.Buffer{}
+
+	_, _ = buf.WriteString(c.id.String() + "|")
+	_, _ = buf.WriteString("clientuser|")
+	_, _ = buf.WriteString(agentID.String() + "|")
+	err := json.NewEncoder(&buf).Encode(update)
+	if err != nil {
+		return nil, xerrors.Errorf("encode client user: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
 // format: <coordinator id>|agenthello|<node id>|
 func (c *haCoordinator) formatAgentHello(id uuid.UUID) ([]byte, error) {
 	buf := bytes.Buffer{}
@@ -2,6 +2,7 @@ package tailnet_test
 
 import (
 	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/google/uuid"
@@ -165,6 +166,55 @@ func TestCoordinatorSingle(t *testing.T) {
 func TestCoordinatorHA(t *testing.T) {
 	t.Parallel()
 
+	t.Run("ClientUser", func(t *testing.T) {
+		t.Parallel()
+
+		_, pubsub := dbtestutil.NewDB(t)
+
+		coordinator1, err := tailnet.NewCoordinator(slogtest.Make(t, nil), pubsub)
+		require.NoError(t, err)
+		defer coordinator1.Close()
+		coordinator2, err := tailnet.NewCoordinator(slogtest.Make(t, nil), pubsub)
+		require.NoError(t, err)
+		defer coordinator2.Close()
+
+		clientWS, clientServerWS := net.Pipe()
+		defer clientWS.Close()
+		defer clientServerWS.Close()
+		sendClientNode, clientErrChan := agpl.ServeCoordinator(clientWS, func(nodes []*agpl.Node) error {
+			return nil
+		})
+		agentID := uuid.New()
+		userID := uuid.New()
+		closeClientChan := make(chan struct{})
+		go func() {
+			err := coordinator2.ServeUserClient(clientServerWS, uuid.New(), agentID, userID)
+			assert.NoError(t, err)
+			close(closeClientChan)
+		}()
+		addr := agpl.IP()
+		sendClientNode(&agpl.Node{PreferredDERP: 1, Addresses: []netip.Prefix{netip.PrefixFrom(addr, 128)}})
+
+		// The user is looked up on both replicas.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		for _, coordinator := range []agpl.Coordinator{coordinator1, coordinator2} {
+			coordinator := coordinator
+			require.Eventually(t, func() bool {
+				user, ok, err := coordinator.ClientUser(ctx, agentID, addr)
+				return assert.NoError(t, err) && ok && user == userID
+			}, testutil.WaitShort, testutil.IntervalFast)
+		}
+
+		err = clientWS.Close()
+		require.NoError(t, err)
+		<-clientErrChan
+		<-closeClientChan
+		require.Eventually(t, func() bool {
+			_, ok, err := coordinator1.ClientUser(ctx, agentID, addr)
+			return assert.NoError(t, err) && !ok
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
 	t.Run("AgentWithClient", func(t *testing.T) {
 		t.Parallel()
 
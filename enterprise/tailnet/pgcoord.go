@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"strings"
 	"sync"
 	"time"
@@ -153,6 +154,12 @@ func (c *pgCoord) Node(id uuid.UUID) *agpl.Node {
 }
 
 func (c *pgCoord) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error {
+	return c.ServeUserClient(conn, id, agent, uuid.Nil)
+}
+
+// ServeUserClient is ServeClient for a client that authenticated as the user.
+// The user is stored with the node of the client.
+func (c *pgCoord) ServeUserClient(conn net.Conn, id uuid.UUID, agent uuid.UUID, user uuid.UUID) error {
 	defer func() {
 		err := conn.Close()
 		if err != nil {
@@ -162,7 +169,7 @@ func (c *pgCoord) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) erro
 				slog.Error(err))
 		}
 	}()
-	cIO := newConnIO(c.ctx, c.logger, c.bindings, conn, id, agent, id.String())
+	cIO := newConnIO(c.ctx, c.logger, c.bindings, conn, id, agent, user, id.String())
 	if err := sendCtx(c.ctx, c.newConnections, cIO); err != nil {
 		// can only be a context error, no need to log here.
 		return err
@@ -181,7 +188,7 @@ func (c *pgCoord) ServeAgent(conn net.Conn, id uuid.UUID, name string) error {
 		}
 	}()
 	logger := c.logger.With(slog.F("name", name))
-	cIO := newConnIO(c.ctx, logger, c.bindings, conn, uuid.Nil, id, name)
+	cIO := newConnIO(c.ctx, logger, c.bindings, conn, uuid.Nil, id, uuid.Nil, name)
 	if err := sendCtx(c.ctx, c.newConnections, cIO); err != nil {
 		// can only be a context error, no need to log here.
 		return err
@@ -190,6 +197,30 @@ func (c *pgCoord) ServeAgent(conn net.Conn, id uuid.UUID, name string) error {
 	return nil
 }
 
+// ClientUser returns the user of the client that announced the address while
+// coordinating with the agent, through any coordinator.
+func (c *pgCoord) ClientUser(ctx context.Context, agent uuid.UUID, addr netip.Addr) (uuid.UUID, bool, error) {
+	// nolint:gocritic // The user is looked up on behalf of the coordinator.
+	clients, err := c.store.GetTailnetClientsForAgent(dbauthz.As(ctx, pgCoordSubject), agent)
+	if err != nil {
+		return uuid.Nil, false, xerrors.Errorf("get tailnet clients: %w", err)
+	}
+	for _, client := range clients {
+		if !client.UserID.Valid {
+			continue
+		}
+		var node agpl.Node
+		err := json.Unmarshal(client.Node, &node)
+		if err != nil {
+			return uuid.Nil, false, xerrors.Errorf("unmarshal node: %w", err)
+		}
+		if node.HasAddress(addr) {
+			return client.UserID.UUID, true, nil
+		}
+	}
+	return uuid.Nil, false, nil
+}
+
 func (c *pgCoord) Close() error {
 	c.logger.Info(c.ctx, "closing coordinator")
 	c.cancel()
@@ -210,13 +241,15 @@ type connIO struct {
 	decoder  *json.Decoder
 	updates  *agpl.TrackedConn
 	bindings chan<- binding
+	// user is the user the client authenticated as, if any.
+	user uuid.UUID
 }
 
 func newConnIO(pCtx context.Context,
 	logger slog.Logger,
 	bindings chan<- binding,
 	conn net.Conn,
-	client, agent uuid.UUID,
+	client, agent, user uuid.UUID,
 	name string,
 ) *connIO {
 	ctx, cancel := context.WithCancel(pCtx)
@@ -236,6 +269,7 @@ func newConnIO(pCtx context.Context,
 		decoder:  json.NewDecoder(conn),
 		updates:  agpl.NewTrackedConn(ctx, cancel, conn, id, logger, name, 0),
 		bindings: bindings,
+		user:     user,
 	}
 	go c.recvLoop()
 	go c.updates.SendUpdates()
@@ -280,6 +314,7 @@ func (c *connIO) recvLoop() {
 				agent:  c.agent,
 			},
 			node: &node,
+			user: c.user,
 		}
 		if err := sendCtx(c.ctx, c.bindings, b); err != nil {
 			c.logger.Debug(c.ctx, "recvLoop ctx expired", slog.Error(err))
@@ -307,6 +342,8 @@ type bKey struct {
 type binding struct {
 	bKey
 	node *agpl.Node
+	// user is the user that the client authenticated as, if any.
+	user uuid.UUID
 }
 
 func (b *binding) isAgent() bool  { return b.client == uuid.Nil }
@@ -422,6 +459,7 @@ func (b *binder) writeOne(bnd binding) error {
 			CoordinatorID: b.coordinatorID,
 			AgentID:       bnd.agent,
 			Node:          nodeRaw,
+			UserID:        uuid.NullUUID{UUID: bnd.user, Valid: bnd.user != uuid.Nil},
 		})
 		b.logger.Debug(b.ctx, "upserted client binding",
 			slog.F("agent_id", bnd.agent), slog.F("client_id", bnd.client),
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"io"
 	"net"
+	"net/netip"
 	"sync"
 	"testing"
 	"time"
@@ -438,6 +439,75 @@ func TestPGCoordinatorDual_Mainline(t *testing.T) {
 	assertEventuallyNoClientsForAgent(ctx, t, store, agent2.id)
 }
 
+// TestPGCoordinatorDual_ClientUser tests that the user of a client is looked up
+// by a coordinator other than the one the client coordinates through.
+func TestPGCoordinatorDual_ClientUser(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("test only with postgres")
+	}
+	store, ps := dbtestutil.NewDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitSuperLong)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coord1, err := tailnet.NewPGCoord(ctx, logger.Named("coord1"), ps, store)
+	require.NoError(t, err)
+	defer coord1.Close()
+	coord2, err := tailnet.NewPGCoord(ctx, logger.Named("coord2"), ps, store)
+	require.NoError(t, err)
+	defer coord2.Close()
+
+	agent := newTestAgent(t, coord1, "agent")
+	defer agent.close()
+	agent.sendNode(&agpl.Node{PreferredDERP: 10})
+
+	userID := uuid.New()
+	client := newTestUserClient(t, coord2, agent.id, userID)
+	defer client.close()
+	agentNodes := client.recvNodes(ctx, t)
+	require.Len(t, agentNodes, 1)
+	addr := agpl.IP()
+	client.sendNode(&agpl.Node{PreferredDERP: 11, Addresses: []netip.Prefix{netip.PrefixFrom(addr, 128)}})
+	clientNodes := agent.recvNodes(ctx, t)
+	require.Len(t, clientNodes, 1)
+
+	require.Eventually(t, func() bool {
+		user, ok, err := coord1.ClientUser(ctx, agent.id, addr)
+		return assert.NoError(t, err) && ok && user == userID
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// Clients without a user, such as workspace proxies, aren't attributed.
+	proxy := newTestClient(t, coord2, agent.id)
+	defer proxy.close()
+	agentNodes = proxy.recvNodes(ctx, t)
+	require.Len(t, agentNodes, 1)
+	proxyAddr := agpl.IP()
+	proxy.sendNode(&agpl.Node{PreferredDERP: 12, Addresses: []netip.Prefix{netip.PrefixFrom(proxyAddr, 128)}})
+	assertEventuallyHasDERPs(ctx, t, agent, 11, 12)
+	_, ok, err := coord1.ClientUser(ctx, agent.id, proxyAddr)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	err = proxy.close()
+	require.NoError(t, err)
+	_ = proxy.recvErr(ctx, t)
+	proxy.waitForClose(ctx, t)
+	assertEventuallyHasDERPs(ctx, t, agent, 11)
+	err = client.close()
+	require.NoError(t, err)
+	_ = client.recvErr(ctx, t)
+	client.waitForClose(ctx, t)
+	err = agent.close()
+	require.NoError(t, err)
+	_ = agent.recvErr(ctx, t)
+	agent.waitForClose(ctx, t)
+
+	assertEventuallyNoClientsForAgent(ctx, t, store, agent.id)
+	_, ok, err = coord1.ClientUser(ctx, agent.id, addr)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 // TestPGCoordinator_MultiAgent tests when a single agent connects to multiple coordinators.
 // We use two agent connections, but they share the same AgentID.  This could happen due to a reconnection,
 // or an infrastructure problem where an old workspace is not fully cleaned up before a new one started.
@@ -675,6 +745,16 @@ func newTestClient(t *testing.T, coord agpl.Coordinator, agentID uuid.UUID, id .
 	return c
 }
 
+func newTestUserClient(t *testing.T, coord agpl.Coordinator, agentID, userID uuid.UUID) *testConn {
+	c := newTestConn(nil)
+	go func() {
+		err := coord.ServeUserClient(c.serverWS, c.id, agentID, userID)
+		assert.NoError(t, err)
+		close(c.closeChan)
+	}()
+	return c
+}
+
 func assertEventuallyHasDERPs(ctx context.Context, t *testing.T, c *testConn, expected ...int) {
 	t.Helper()
 	for {
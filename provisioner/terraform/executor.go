@@ -267,6 +267,7 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		Parameters:       state.Parameters,
 		Resources:        state.Resources,
 		GitAuthProviders: state.GitAuthProviders,
+		Presets:          state.Presets,
 	}, nil
 }
 
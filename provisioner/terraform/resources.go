@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/awalterschulze/gographviz"
@@ -76,9 +77,33 @@ type agentAppAttributes struct {
 	Healthcheck []appHealthcheckAttributes `mapstructure:"healthcheck"`
 }
 
+// A mapping of attributes on the "coder_script" resource.
+type agentScriptAttributes struct {
+	AgentID          string `mapstructure:"agent_id"`
+	DisplayName      string `mapstructure:"display_name"`
+	Icon             string `mapstructure:"icon"`
+	Script           string `mapstructure:"script"`
+	Cron             string `mapstructure:"cron"`
+	LogPath          string `mapstructure:"log_path"`
+	StartBlocksLogin bool   `mapstructure:"start_blocks_login"`
+	RunOnStart       bool   `mapstructure:"run_on_start"`
+	RunOnStop        bool   `mapstructure:"run_on_stop"`
+	TimeoutSeconds   int32  `mapstructure:"timeout"`
+}
+
+// A mapping of attributes on the "coder_log_file" resource.
+type agentLogFileAttributes struct {
+	AgentID     string `mapstructure:"agent_id"`
+	DisplayName string `mapstructure:"display_name"`
+	Icon        string `mapstructure:"icon"`
+	Path        string `mapstructure:"path"`
+}
+
 // A mapping of attributes on the "healthcheck" resource.
 type appHealthcheckAttributes struct {
 	URL       string `mapstructure:"url"`
+	TCP       string `mapstructure:"tcp"`
+	Command   string `mapstructure:"command"`
 	Interval  int32  `mapstructure:"interval"`
 	Threshold int32  `mapstructure:"threshold"`
 }
@@ -99,10 +124,17 @@ type resourceMetadataItem struct {
 	IsNull    bool   `mapstructure:"is_null"`
 }
 
+// A mapping of attributes on the "coder_workspace_preset" data source.
+type presetAttributes struct {
+	Name       string            `mapstructure:"name"`
+	Parameters map[string]string `mapstructure:"parameters"`
+}
+
 type State struct {
 	Resources        []*proto.Resource
 	Parameters       []*proto.RichParameter
 	GitAuthProviders []string
+	Presets          []*proto.Preset
 }
 
 // ConvertState consumes Terraform state and a GraphViz representation
@@ -126,6 +158,8 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 
 	// Extra array to preserve the order of rich parameters.
 	tfResourcesRichParameters := make([]*tfjson.StateResource, 0)
+	// Extra array to preserve the order of presets.
+	tfResourcesPresets := make([]*tfjson.StateResource, 0)
 
 	var findTerraformResources func(mod *tfjson.StateModule)
 	findTerraformResources = func(mod *tfjson.StateModule) {
@@ -136,6 +170,9 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 			if resource.Type == "coder_parameter" {
 				tfResourcesRichParameters = append(tfResourcesRichParameters, resource)
 			}
+			if resource.Type == "coder_workspace_preset" {
+				tfResourcesPresets = append(tfResourcesPresets, resource)
+			}
 
 			label := convertAddressToLabel(resource.Address)
 			if tfResourcesByLabel[label] == nil {
@@ -364,10 +401,22 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 
 			var healthcheck *proto.Healthcheck
 			if len(attrs.Healthcheck) != 0 {
+				check := attrs.Healthcheck[0]
+				kinds := 0
+				for _, value := range []string{check.URL, check.TCP, check.Command} {
+					if value != "" {
+						kinds++
+					}
+				}
+				if kinds > 1 {
+					return nil, xerrors.Errorf("healthcheck of app %q must set only one of url, tcp or command", attrs.Slug)
+				}
 				healthcheck = &proto.Healthcheck{
-					Url:       attrs.Healthcheck[0].URL,
-					Interval:  attrs.Healthcheck[0].Interval,
-					Threshold: attrs.Healthcheck[0].Threshold,
+					Url:       check.URL,
+					Tcp:       check.TCP,
+					Command:   check.Command,
+					Interval:  check.Interval,
+					Threshold: check.Threshold,
 				}
 			}
 
@@ -403,6 +452,95 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 		}
 	}
 
+	// Associate scripts with agents. Scripts are sorted by their address
+	// so the order they're presented in is stable between builds.
+	scriptResources := make([]*tfjson.StateResource, 0)
+	for _, resources := range tfResourcesByLabel {
+		for _, resource := range resources {
+			if resource.Type != "coder_script" {
+				continue
+			}
+			scriptResources = append(scriptResources, resource)
+		}
+	}
+	sort.Slice(scriptResources, func(i, j int) bool {
+		return scriptResources[i].Address < scriptResources[j].Address
+	})
+	for _, resource := range scriptResources {
+		var attrs agentScriptAttributes
+		err = mapstructure.Decode(resource.AttributeValues, &attrs)
+		if err != nil {
+			return nil, xerrors.Errorf("decode script attributes: %w", err)
+		}
+		if attrs.DisplayName == "" {
+			attrs.DisplayName = resource.Name
+		}
+		if !attrs.RunOnStart && !attrs.RunOnStop && attrs.Cron == "" {
+			return nil, xerrors.Errorf("script %q must run on start, on stop, or on a cron schedule", resource.Address)
+		}
+
+		for _, agents := range resourceAgents {
+			for _, agent := range agents {
+				// Find agents with the matching ID and associate them!
+				if agent.Id != attrs.AgentID {
+					continue
+				}
+				agent.Scripts = append(agent.Scripts, &proto.Script{
+					DisplayName:      attrs.DisplayName,
+					Icon:             attrs.Icon,
+					Script:           attrs.Script,
+					Cron:             attrs.Cron,
+					LogPath:          attrs.LogPath,
+					StartBlocksLogin: attrs.StartBlocksLogin,
+					RunOnStart:       attrs.RunOnStart,
+					RunOnStop:        attrs.RunOnStop,
+					TimeoutSeconds:   attrs.TimeoutSeconds,
+				})
+			}
+		}
+	}
+
+	// Associate log files with agents.
+	logFileResources := make([]*tfjson.StateResource, 0)
+	for _, resources := range tfResourcesByLabel {
+		for _, resource := range resources {
+			if resource.Type != "coder_log_file" {
+				continue
+			}
+			logFileResources = append(logFileResources, resource)
+		}
+	}
+	sort.Slice(logFileResources, func(i, j int) bool {
+		return logFileResources[i].Address < logFileResources[j].Address
+	})
+	for _, resource := range logFileResources {
+		var attrs agentLogFileAttributes
+		err = mapstructure.Decode(resource.AttributeValues, &attrs)
+		if err != nil {
+			return nil, xerrors.Errorf("decode log file attributes: %w", err)
+		}
+		if attrs.DisplayName == "" {
+			attrs.DisplayName = resource.Name
+		}
+		if attrs.Path == "" {
+			return nil, xerrors.Errorf("log file %q must have a path", resource.Address)
+		}
+
+		for _, agents := range resourceAgents {
+			for _, agent := range agents {
+				// Find agents with the matching ID and associate them!
+				if agent.Id != attrs.AgentID {
+					continue
+				}
+				agent.LogFiles = append(agent.LogFiles, &proto.LogFile{
+					DisplayName: attrs.DisplayName,
+					Icon:        attrs.Icon,
+					Path:        attrs.Path,
+				})
+			}
+		}
+	}
+
 	// Associate metadata blocks with resources.
 	resourceMetadata := map[string][]*proto.Resource_Metadata{}
 	resourceHidden := map[string]bool{}
@@ -482,7 +620,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 			if resource.Mode == tfjson.DataResourceMode {
 				continue
 			}
-			if resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" {
+			if resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_script" || resource.Type == "coder_metadata" {
 				continue
 			}
 			label := convertAddressToLabel(resource.Address)
@@ -595,6 +733,52 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 		)
 	}
 
+	var duplicatedPresetNames []string
+	presets := make([]*proto.Preset, 0)
+	for _, resource := range tfResourcesPresets {
+		var attrs presetAttributes
+		err = mapstructure.Decode(resource.AttributeValues, &attrs)
+		if err != nil {
+			return nil, xerrors.Errorf("decode map values for coder_workspace_preset.%s: %w", resource.Name, err)
+		}
+		protoPreset := &proto.Preset{
+			Name:       attrs.Name,
+			Parameters: make([]*proto.PresetParameter, 0, len(attrs.Parameters)),
+		}
+		for name, value := range attrs.Parameters {
+			protoPreset.Parameters = append(protoPreset.Parameters, &proto.PresetParameter{
+				Name:  name,
+				Value: value,
+			})
+		}
+		// Terraform maps are unordered.
+		sort.Slice(protoPreset.Parameters, func(i, j int) bool {
+			return protoPreset.Parameters[i].Name < protoPreset.Parameters[j].Name
+		})
+
+		formattedName := fmt.Sprintf("%q", protoPreset.Name)
+		if !slice.Contains(duplicatedPresetNames, formattedName) &&
+			slice.ContainsCompare(presets, protoPreset, func(a, b *proto.Preset) bool {
+				return a.Name == b.Name
+			}) {
+			duplicatedPresetNames = append(duplicatedPresetNames, formattedName)
+		}
+
+		presets = append(presets, protoPreset)
+	}
+
+	// Enforce that presets be uniquely named.
+	if len(duplicatedPresetNames) > 0 {
+		s := ""
+		if len(duplicatedPresetNames) == 1 {
+			s = "s"
+		}
+		return nil, xerrors.Errorf(
+			"coder_workspace_preset names must be unique but %s appear%s multiple times",
+			stringutil.JoinWithConjunction(duplicatedPresetNames), s,
+		)
+	}
+
 	// A map is used to ensure we don't have duplicates!
 	gitAuthProvidersMap := map[string]struct{}{}
 	for _, tfResources := range tfResourcesByLabel {
@@ -618,6 +802,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 		Resources:        resources,
 		Parameters:       parameters,
 		GitAuthProviders: gitAuthProviders,
+		Presets:          presets,
 	}, nil
 }
 
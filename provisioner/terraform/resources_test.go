@@ -205,6 +205,37 @@ func TestConvertResources(t *testing.T) {
 				}},
 			}},
 		},
+		"multiple-scripts": {
+			resources: []*proto.Resource{{
+				Name: "dev",
+				Type: "null_resource",
+				Agents: []*proto.Agent{{
+					Name:            "dev1",
+					OperatingSystem: "linux",
+					Architecture:    "amd64",
+					Scripts: []*proto.Script{{
+						DisplayName: "Foobar Script 1",
+						Script:      "echo foobar 1",
+						RunOnStart:  true,
+					}, {
+						DisplayName:      "Foobar Script 2",
+						Script:           "echo foobar 2",
+						RunOnStart:       true,
+						StartBlocksLogin: true,
+						TimeoutSeconds:   300,
+					}, {
+						DisplayName: "Foobar Script 3",
+						Script:      "echo foobar 3",
+						Cron:        "0 0 * * *",
+						LogPath:     "/tmp/foobar3.log",
+					}},
+					Auth:                     &proto.Agent_Token{},
+					StartupScriptBehavior:    "non-blocking",
+					ConnectionTimeoutSeconds: 120,
+					DisplayApps:              &displayApps,
+				}},
+			}},
+		},
 		"mapped-apps": {
 			resources: []*proto.Resource{{
 				Name: "dev",
@@ -702,6 +733,113 @@ func TestAppSlugValidation(t *testing.T) {
 	require.ErrorContains(t, err, "duplicate app slug")
 }
 
+func TestAppHealthcheckValidation(t *testing.T) {
+	t.Parallel()
+
+	// nolint:dogsled
+	_, filename, _, _ := runtime.Caller(0)
+
+	// Load the multiple-apps state file and edit it.
+	dir := filepath.Join(filepath.Dir(filename), "testdata", "multiple-apps")
+	tfPlanRaw, err := os.ReadFile(filepath.Join(dir, "multiple-apps.tfplan.json"))
+	require.NoError(t, err)
+	var tfPlan tfjson.Plan
+	err = json.Unmarshal(tfPlanRaw, &tfPlan)
+	require.NoError(t, err)
+	tfPlanGraph, err := os.ReadFile(filepath.Join(dir, "multiple-apps.tfplan.dot"))
+	require.NoError(t, err)
+
+	// Check app2 over TCP instead of HTTP.
+	var app2 *tfjson.StateResource
+	for _, resource := range tfPlan.PlannedValues.RootModule.Resources {
+		if resource.Address == "coder_app.app2" {
+			app2 = resource
+		}
+	}
+	require.NotNil(t, app2)
+	healthcheck := app2.AttributeValues["healthcheck"].([]interface{})[0].(map[string]interface{})
+	delete(healthcheck, "url")
+	healthcheck["tcp"] = "localhost:5432"
+
+	state, err := terraform.ConvertState([]*tfjson.StateModule{tfPlan.PlannedValues.RootModule}, string(tfPlanGraph))
+	require.NoError(t, err)
+	var found bool
+	for _, resource := range state.Resources {
+		for _, agent := range resource.Agents {
+			for _, app := range agent.Apps {
+				if app.Slug != "app2" {
+					continue
+				}
+				found = true
+				require.Equal(t, &proto.Healthcheck{
+					Tcp:       "localhost:5432",
+					Interval:  5,
+					Threshold: 6,
+				}, app.Healthcheck)
+			}
+		}
+	}
+	require.True(t, found)
+
+	// Only one kind of check may be set.
+	healthcheck["command"] = "pg_isready"
+	state, err = terraform.ConvertState([]*tfjson.StateModule{tfPlan.PlannedValues.RootModule}, string(tfPlanGraph))
+	require.Nil(t, state)
+	require.ErrorContains(t, err, "must set only one of url, tcp or command")
+}
+
+func TestLogFileResources(t *testing.T) {
+	t.Parallel()
+
+	// nolint:dogsled
+	_, filename, _, _ := runtime.Caller(0)
+
+	// Load the multiple-scripts state file and add a log file to it.
+	dir := filepath.Join(filepath.Dir(filename), "testdata", "multiple-scripts")
+	tfStateRaw, err := os.ReadFile(filepath.Join(dir, "multiple-scripts.tfstate.json"))
+	require.NoError(t, err)
+	var tfState tfjson.State
+	err = json.Unmarshal(tfStateRaw, &tfState)
+	require.NoError(t, err)
+	tfStateGraph, err := os.ReadFile(filepath.Join(dir, "multiple-scripts.tfstate.dot"))
+	require.NoError(t, err)
+
+	logFile := &tfjson.StateResource{
+		Address: "coder_log_file.app",
+		Mode:    tfjson.ManagedResourceMode,
+		Type:    "coder_log_file",
+		Name:    "app",
+		AttributeValues: map[string]interface{}{
+			"agent_id": "3d4ee1d5-6413-4dc7-baec-2fa9dbd870ba",
+			"icon":     "/icon/database.svg",
+			"path":     "/var/log/app.log",
+		},
+	}
+	tfState.Values.RootModule.Resources = append(tfState.Values.RootModule.Resources, logFile)
+
+	state, err := terraform.ConvertState([]*tfjson.StateModule{tfState.Values.RootModule}, string(tfStateGraph))
+	require.NoError(t, err)
+	var found bool
+	for _, resource := range state.Resources {
+		for _, agent := range resource.Agents {
+			found = true
+			require.Len(t, agent.LogFiles, 1)
+			// The display name defaults to the name of the resource.
+			require.Equal(t, &proto.LogFile{
+				DisplayName: "app",
+				Icon:        "/icon/database.svg",
+				Path:        "/var/log/app.log",
+			}, agent.LogFiles[0])
+		}
+	}
+	require.True(t, found)
+
+	delete(logFile.AttributeValues, "path")
+	state, err = terraform.ConvertState([]*tfjson.StateModule{tfState.Values.RootModule}, string(tfStateGraph))
+	require.Nil(t, state)
+	require.ErrorContains(t, err, "must have a path")
+}
+
 func TestMetadataResourceDuplicate(t *testing.T) {
 	t.Parallel()
 
@@ -784,6 +922,62 @@ func TestParameterValidation(t *testing.T) {
 	require.ErrorContains(t, err, "coder_parameter names must be unique but \"identical-0\", \"identical-1\" and \"identical-2\" appear multiple times")
 }
 
+func TestPresets(t *testing.T) {
+	t.Parallel()
+
+	// nolint:dogsled
+	_, filename, _, _ := runtime.Caller(0)
+
+	// Load the rich-parameters state file and add presets to it.
+	dir := filepath.Join(filepath.Dir(filename), "testdata", "rich-parameters")
+	tfPlanRaw, err := os.ReadFile(filepath.Join(dir, "rich-parameters.tfplan.json"))
+	require.NoError(t, err)
+	var tfPlan tfjson.Plan
+	err = json.Unmarshal(tfPlanRaw, &tfPlan)
+	require.NoError(t, err)
+	tfPlanGraph, err := os.ReadFile(filepath.Join(dir, "rich-parameters.tfplan.dot"))
+	require.NoError(t, err)
+
+	rootModule := tfPlan.PriorState.Values.RootModule
+	for _, name := range []string{"small", "large"} {
+		rootModule.Resources = append(rootModule.Resources, &tfjson.StateResource{
+			Address: "data.coder_workspace_preset." + name,
+			Mode:    tfjson.DataResourceMode,
+			Type:    "coder_workspace_preset",
+			Name:    name,
+			AttributeValues: map[string]interface{}{
+				"name": name,
+				"parameters": map[string]interface{}{
+					"Sample":  name,
+					"Example": "1",
+				},
+			},
+		})
+	}
+
+	state, err := terraform.ConvertState([]*tfjson.StateModule{rootModule}, string(tfPlanGraph))
+	require.NoError(t, err)
+	require.Len(t, state.Presets, 2)
+	require.Equal(t, "small", state.Presets[0].Name)
+	require.Equal(t, "large", state.Presets[1].Name)
+	// Parameters are sorted by name.
+	require.Len(t, state.Presets[0].Parameters, 2)
+	require.Equal(t, "Example", state.Presets[0].Parameters[0].Name)
+	require.Equal(t, "1", state.Presets[0].Parameters[0].Value)
+	require.Equal(t, "Sample", state.Presets[0].Parameters[1].Name)
+	require.Equal(t, "small", state.Presets[0].Parameters[1].Value)
+
+	// Names must be unique.
+	for _, resource := range rootModule.Resources {
+		if resource.Type == "coder_workspace_preset" {
+			resource.AttributeValues["name"] = "identical"
+		}
+	}
+	state, err = terraform.ConvertState([]*tfjson.StateModule{rootModule}, string(tfPlanGraph))
+	require.Nil(t, state)
+	require.ErrorContains(t, err, "coder_workspace_preset names must be unique but \"identical\" appears multiple times")
+}
+
 func TestInstanceTypeAssociation(t *testing.T) {
 	t.Parallel()
 	type tc struct {
@@ -0,0 +1,45 @@
+terraform {
+  required_providers {
+    coder = {
+      source  = "coder/coder"
+      version = "0.12.0"
+    }
+  }
+}
+
+resource "coder_agent" "dev1" {
+  os   = "linux"
+  arch = "amd64"
+}
+
+resource "coder_script" "script1" {
+  agent_id     = coder_agent.dev1.id
+  display_name = "Foobar Script 1"
+  script       = "echo foobar 1"
+  run_on_start = true
+}
+
+# script2 tests that a script can block login and have a timeout.
+resource "coder_script" "script2" {
+  agent_id           = coder_agent.dev1.id
+  display_name       = "Foobar Script 2"
+  script             = "echo foobar 2"
+  run_on_start       = true
+  start_blocks_login = true
+  timeout            = 300
+}
+
+# script3 tests that a script can run on a cron schedule.
+resource "coder_script" "script3" {
+  agent_id     = coder_agent.dev1.id
+  display_name = "Foobar Script 3"
+  script       = "echo foobar 3"
+  cron         = "0 0 * * *"
+  log_path     = "/tmp/foobar3.log"
+}
+
+resource "null_resource" "dev" {
+  depends_on = [
+    coder_agent.dev1
+  ]
+}
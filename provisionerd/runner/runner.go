@@ -572,6 +572,7 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 				StopResources:    stopProvision.Resources,
 				RichParameters:   startProvision.Parameters,
 				GitAuthProviders: startProvision.GitAuthProviders,
+				Presets:          startProvision.Presets,
 			},
 		},
 	}, nil
@@ -630,6 +631,7 @@ type templateImportProvision struct {
 	Resources        []*sdkproto.Resource
 	Parameters       []*sdkproto.RichParameter
 	GitAuthProviders []string
+	Presets          []*sdkproto.Preset
 }
 
 // Performs a dry-run provision when importing a template.
@@ -721,6 +723,7 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(
 				Resources:        c.Resources,
 				Parameters:       c.Parameters,
 				GitAuthProviders: c.GitAuthProviders,
+				Presets:          c.Presets,
 			}, nil
 		default:
 			return nil, xerrors.Errorf("invalid message type %q received from provisioner",
@@ -48,6 +48,10 @@ func prepareEnv() {
 	if err != nil {
 		panic(err)
 	}
+	err = os.Setenv("CLIDOCGEN_AGENT_SOCKET_PATH", "$XDG_RUNTIME_DIR/coder-agent/agent.sock")
+	if err != nil {
+		panic(err)
+	}
 }
 
 func deleteEmptyDirs(dir string) error {
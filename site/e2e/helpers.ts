@@ -435,6 +435,7 @@ const createTemplateVersionTar = async (
           resources: response.apply?.resources ?? [],
           parameters: response.apply?.parameters ?? [],
           gitAuthProviders: response.apply?.gitAuthProviders ?? [],
+          presets: [],
         },
       };
     });
@@ -480,10 +481,12 @@ const createTemplateVersionTar = async (
             directory: "",
             env: {},
             id: randomUUID(),
+            logFiles: [],
             metadata: [],
             motdFile: "",
             name: "dev",
             operatingSystem: "linux",
+            scripts: [],
             shutdownScript: "",
             shutdownScriptTimeoutSeconds: 0,
             startupScript: "",
@@ -531,6 +534,7 @@ const createTemplateVersionTar = async (
       resources: [],
       parameters: [],
       gitAuthProviders: [],
+      presets: [],
       ...response.plan,
     } as PlanComplete;
     response.plan.resources = response.plan.resources?.map(fillResource);
@@ -77,6 +77,18 @@ export interface RichParameterValue {
   value: string;
 }
 
+/** Preset represents a set of preset parameters for a template version. */
+export interface Preset {
+  name: string;
+  parameters: PresetParameter[];
+}
+
+/** PresetParameter holds the value of a parameter in a preset. */
+export interface PresetParameter {
+  name: string;
+  value: string;
+}
+
 /** VariableValue holds the key/value mapping of a Terraform variable. */
 export interface VariableValue {
   name: string;
@@ -121,6 +133,8 @@ export interface Agent {
   metadata: Agent_Metadata[];
   startupScriptBehavior: string;
   displayApps: DisplayApps | undefined;
+  scripts: Script[];
+  logFiles: LogFile[];
 }
 
 export interface Agent_Metadata {
@@ -144,6 +158,26 @@ export interface DisplayApps {
   portForwardingHelper: boolean;
 }
 
+/** Script represents a script to be run on the workspace. */
+export interface Script {
+  displayName: string;
+  icon: string;
+  script: string;
+  cron: string;
+  startBlocksLogin: boolean;
+  runOnStart: boolean;
+  runOnStop: boolean;
+  timeoutSeconds: number;
+  logPath: string;
+}
+
+/** LogFile is a file in the workspace the agent tails into its logs. */
+export interface LogFile {
+  displayName: string;
+  icon: string;
+  path: string;
+}
+
 /** App represents a dev-accessible application on the workspace. */
 export interface App {
   /**
@@ -166,6 +200,13 @@ export interface Healthcheck {
   url: string;
   interval: number;
   threshold: number;
+  /** tcp is an address to connect to instead of requesting url. */
+  tcp: string;
+  /**
+   * command is run instead of requesting url. A zero exit code means the
+   * app is healthy.
+   */
+  command: string;
 }
 
 /** Resource represents created infrastructure. */
@@ -235,6 +276,7 @@ export interface PlanComplete {
   resources: Resource[];
   parameters: RichParameter[];
   gitAuthProviders: string[];
+  presets: Preset[];
 }
 
 /**
@@ -398,6 +440,36 @@ export const RichParameterValue = {
   },
 };
 
+export const Preset = {
+  encode(
+    message: Preset,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    for (const v of message.parameters) {
+      PresetParameter.encode(v!, writer.uint32(18).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const PresetParameter = {
+  encode(
+    message: PresetParameter,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.value !== "") {
+      writer.uint32(18).string(message.value);
+    }
+    return writer;
+  },
+};
+
 export const VariableValue = {
   encode(
     message: VariableValue,
@@ -520,6 +592,12 @@ export const Agent = {
         writer.uint32(162).fork(),
       ).ldelim();
     }
+    for (const v of message.scripts) {
+      Script.encode(v!, writer.uint32(170).fork()).ldelim();
+    }
+    for (const v of message.logFiles) {
+      LogFile.encode(v!, writer.uint32(178).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -587,6 +665,60 @@ export const DisplayApps = {
   },
 };
 
+export const Script = {
+  encode(
+    message: Script,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.displayName !== "") {
+      writer.uint32(10).string(message.displayName);
+    }
+    if (message.icon !== "") {
+      writer.uint32(18).string(message.icon);
+    }
+    if (message.script !== "") {
+      writer.uint32(26).string(message.script);
+    }
+    if (message.cron !== "") {
+      writer.uint32(34).string(message.cron);
+    }
+    if (message.startBlocksLogin === true) {
+      writer.uint32(40).bool(message.startBlocksLogin);
+    }
+    if (message.runOnStart === true) {
+      writer.uint32(48).bool(message.runOnStart);
+    }
+    if (message.runOnStop === true) {
+      writer.uint32(56).bool(message.runOnStop);
+    }
+    if (message.timeoutSeconds !== 0) {
+      writer.uint32(64).int32(message.timeoutSeconds);
+    }
+    if (message.logPath !== "") {
+      writer.uint32(74).string(message.logPath);
+    }
+    return writer;
+  },
+};
+
+export const LogFile = {
+  encode(
+    message: LogFile,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.displayName !== "") {
+      writer.uint32(10).string(message.displayName);
+    }
+    if (message.icon !== "") {
+      writer.uint32(18).string(message.icon);
+    }
+    if (message.path !== "") {
+      writer.uint32(26).string(message.path);
+    }
+    return writer;
+  },
+};
+
 export const App = {
   encode(message: App, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
     if (message.slug !== "") {
@@ -637,6 +769,12 @@ export const Healthcheck = {
     if (message.threshold !== 0) {
       writer.uint32(24).int32(message.threshold);
     }
+    if (message.tcp !== "") {
+      writer.uint32(34).string(message.tcp);
+    }
+    if (message.command !== "") {
+      writer.uint32(42).string(message.command);
+    }
     return writer;
   },
 };
@@ -820,6 +958,9 @@ export const PlanComplete = {
     for (const v of message.gitAuthProviders) {
       writer.uint32(34).string(v!);
     }
+    for (const v of message.presets) {
+      Preset.encode(v!, writer.uint32(42).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -50,6 +50,11 @@ var (
 	errorHTML string
 
 	errorTemplate *htmltemplate.Template
+
+	//go:embed static/oauth2allow.html
+	oauthHTML string
+
+	oauthTemplate *htmltemplate.Template
 )
 
 func init() {
@@ -58,6 +63,11 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	oauthTemplate, err = htmltemplate.New("oauth2allow").Parse(oauthHTML)
+	if err != nil {
+		panic(err)
+	}
 }
 
 type Options struct {
@@ -777,6 +787,34 @@ func RenderStaticErrorPage(rw http.ResponseWriter, r *http.Request, data ErrorPa
 	}
 }
 
+type RenderOAuthAllowData struct {
+	AppIcon   string
+	AppName   string
+	CancelURI string
+	Username  string
+	// Scope is the scope the app asked for, as shown to the user.
+	Scope string
+	// CSRFToken is set by RenderOAuthAllowPage and must be submitted with
+	// the form.
+	CSRFToken string
+}
+
+// RenderOAuthAllowPage renders the page that asks the user to allow an OAuth2
+// app to access Coder on their behalf. Submitting the form posts to the same
+// URL.
+func RenderOAuthAllowPage(rw http.ResponseWriter, r *http.Request, data RenderOAuthAllowData) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	data.CSRFToken = nosurf.Token(r)
+	err := oauthTemplate.Execute(rw, data)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to render oauth page: " + err.Error(),
+		})
+		return
+	}
+}
+
 type binHashCache struct {
 	binFS http.FileSystem
 
@@ -108,6 +108,26 @@ export const login = async (
   return response.data;
 };
 
+export const loginWithLDAP = async (
+  username: string,
+  password: string,
+): Promise<TypesGen.LoginWithPasswordResponse> => {
+  const payload = JSON.stringify({
+    username,
+    password,
+  });
+
+  const response = await axios.post<TypesGen.LoginWithPasswordResponse>(
+    "/api/v2/users/ldap/login",
+    payload,
+    {
+      headers: { ...CONTENT_TYPE_JSON },
+    },
+  );
+
+  return response.data;
+};
+
 export const convertToOAUTH = async (request: TypesGen.ConvertLoginRequest) => {
   const response = await axios.post<TypesGen.OAuthConversionResponse>(
     "/api/v2/users/me/convert-login",
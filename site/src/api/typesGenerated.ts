@@ -20,6 +20,14 @@ export interface APIKey {
   readonly scope: APIKeyScope;
   readonly token_name: string;
   readonly lifetime_seconds: number;
+  readonly scope_rules?: APIKeyScopeRule[];
+}
+
+// From codersdk/apikey.go
+export interface APIKeyScopeRule {
+  readonly resource_type: RBACResource;
+  readonly action: string;
+  readonly resource_id?: string;
 }
 
 // From codersdk/apikey.go
@@ -115,6 +123,7 @@ export interface AuthMethods {
   readonly password: AuthMethod;
   readonly github: AuthMethod;
   readonly oidc: OIDCAuthMethod;
+  readonly ldap: AuthMethod;
 }
 
 // From codersdk/authorization.go
@@ -153,12 +162,59 @@ export interface ConnectionLatency {
   readonly p95: number;
 }
 
+// From codersdk/connectionlog.go
+export interface ConnectionLog {
+  readonly id: string;
+  readonly connect_time: string;
+  readonly disconnect_time?: string;
+  readonly organization_id: string;
+  readonly workspace_owner_id: string;
+  readonly workspace_owner_username: string;
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly agent_id: string;
+  readonly agent_name: string;
+  readonly type: ConnectionType;
+  readonly user_id?: string;
+  readonly username?: string;
+  // Named type "net/netip.Addr" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly ip: any;
+  readonly user_agent?: string;
+  readonly slug_or_port?: string;
+  readonly code?: number;
+  readonly bytes_sent: number;
+  readonly bytes_received: number;
+  readonly disconnect_reason?: string;
+}
+
+// From codersdk/connectionlog.go
+export interface ConnectionLogResponse {
+  readonly connection_logs: ConnectionLog[];
+  readonly count: number;
+}
+
+// From codersdk/connectionlog.go
+export interface ConnectionLogsRequest extends Pagination {
+  readonly q?: string;
+}
+
 // From codersdk/users.go
 export interface ConvertLoginRequest {
   readonly to_type: LoginType;
   readonly password: string;
 }
 
+// From codersdk/roles.go
+export interface CreateCustomRoleRequest {
+  readonly name: string;
+  readonly display_name: string;
+  readonly organization_id?: string;
+  readonly site_permissions: Permission[];
+  readonly organization_permissions: Permission[];
+  readonly user_permissions: Permission[];
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string;
@@ -240,6 +296,7 @@ export interface CreateTokenRequest {
   readonly lifetime: number;
   readonly scope: APIKeyScope;
   readonly token_name: string;
+  readonly scope_rules?: APIKeyScopeRule[];
 }
 
 // From codersdk/users.go
@@ -250,6 +307,27 @@ export interface CreateUserRequest {
   readonly login_type: LoginType;
   readonly disable_login: boolean;
   readonly organization_id: string;
+  readonly service_account: boolean;
+}
+
+// From codersdk/webhooks.go
+export interface CreateWebhookRequest {
+  readonly name: string;
+  readonly url: string;
+  readonly events: WebhookEvent[];
+  readonly secret?: string;
+}
+
+// From codersdk/webhooks.go
+export interface CreateWebhookResponse {
+  readonly webhook: Webhook;
+  readonly secret: string;
+}
+
+// From codersdk/workspaceagentptysessions.go
+export interface CreateWorkspaceAgentPTYInviteRequest {
+  readonly user_id: string;
+  readonly read_only: boolean;
 }
 
 // From codersdk/workspaces.go
@@ -263,6 +341,13 @@ export interface CreateWorkspaceBuildRequest {
   readonly log_level?: ProvisionerLogLevel;
 }
 
+// From codersdk/prebuilds.go
+export interface CreateWorkspacePrebuildPoolRequest {
+  readonly template_version_id: string;
+  readonly parameters?: WorkspaceBuildParameter[];
+  readonly desired_instances: number;
+}
+
 // From codersdk/workspaceproxy.go
 export interface CreateWorkspaceProxyRequest {
   readonly name: string;
@@ -278,6 +363,20 @@ export interface CreateWorkspaceRequest {
   readonly autostart_schedule?: string;
   readonly ttl_ms?: number;
   readonly rich_parameter_values?: WorkspaceBuildParameter[];
+  readonly automatic_updates?: AutomaticUpdates;
+}
+
+// From codersdk/roles.go
+export interface CustomRole {
+  readonly id: string;
+  readonly name: string;
+  readonly display_name: string;
+  readonly organization_id?: string;
+  readonly site_permissions: Permission[];
+  readonly organization_permissions: Permission[];
+  readonly user_permissions: Permission[];
+  readonly created_at: string;
+  readonly updated_at: string;
 }
 
 // From codersdk/deployment.go
@@ -335,6 +434,12 @@ export interface DangerousConfig {
   readonly allow_all_cors: boolean;
 }
 
+// From codersdk/workspaceagentportshare.go
+export interface DeleteWorkspaceAgentPortShareRequest {
+  readonly agent_name: string;
+  readonly port: number;
+}
+
 // From codersdk/deployment.go
 export interface DeploymentStats {
   readonly aggregated_from: string;
@@ -366,6 +471,7 @@ export interface DeploymentValues {
   readonly pg_connection_url?: string;
   readonly oauth2?: OAuth2Config;
   readonly oidc?: OIDCConfig;
+  readonly ldap?: LDAPConfig;
   readonly telemetry?: TelemetryConfig;
   readonly tls?: TLSConfig;
   readonly trace?: TraceConfig;
@@ -385,6 +491,7 @@ export interface DeploymentValues {
   readonly experiments?: string[];
   readonly update_check?: boolean;
   readonly max_token_lifetime?: number;
+  readonly max_service_account_token_lifetime?: number;
   readonly swagger?: SwaggerConfig;
   readonly logging?: LoggingConfig;
   readonly dangerous?: DangerousConfig;
@@ -399,9 +506,14 @@ export interface DeploymentValues {
   readonly config_ssh?: SSHConfig;
   readonly wgtunnel_host?: string;
   readonly disable_owner_workspace_exec?: boolean;
+  readonly disable_agent_auto_update?: boolean;
+  readonly workspace_networking?: string;
   readonly proxy_health_status_interval?: number;
   readonly enable_terraform_debug_mode?: boolean;
   readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig;
+  readonly session_recording?: SessionRecordingConfig;
+  readonly connection_log_retention?: number;
+  readonly notifications?: NotificationsConfig;
   // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.YAMLConfigPath")
   readonly config?: string;
   readonly write_config?: boolean;
@@ -523,6 +635,8 @@ export interface Group {
 // From codersdk/workspaceapps.go
 export interface Healthcheck {
   readonly url: string;
+  readonly tcp?: string;
+  readonly command?: string;
   readonly interval: number;
   readonly threshold: number;
 }
@@ -538,6 +652,28 @@ export interface IssueReconnectingPTYSignedTokenResponse {
   readonly signed_token: string;
 }
 
+// From codersdk/deployment.go
+export interface LDAPConfig {
+  readonly url: string;
+  readonly start_tls: boolean;
+  readonly ca_file: string;
+  readonly bind_dn: string;
+  readonly bind_password: string;
+  readonly user_search_base_dn: string;
+  readonly user_search_filter: string;
+  readonly username_attribute: string;
+  readonly email_attribute: string;
+  readonly allow_signups: boolean;
+  readonly group_attribute: string;
+  // Named type "github.com/coder/coder/v2/cli/clibase.Struct[map[string]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly group_mapping: any;
+  readonly group_auto_create: boolean;
+  // Named type "github.com/coder/coder/v2/cli/clibase.Regexp" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly group_regex_filter: any;
+}
+
 // From codersdk/licenses.go
 export interface License {
   readonly id: number;
@@ -564,6 +700,12 @@ export interface LoggingConfig {
   readonly stackdriver: string;
 }
 
+// From codersdk/users.go
+export interface LoginWithLDAPRequest {
+  readonly username: string;
+  readonly password: string;
+}
+
 // From codersdk/users.go
 export interface LoginWithPasswordRequest {
   readonly email: string;
@@ -582,6 +724,54 @@ export interface MinimalUser {
   readonly avatar_url: string;
 }
 
+// From codersdk/notifications.go
+export interface Notification {
+  readonly id: string;
+  readonly kind: NotificationKind;
+  readonly title: string;
+  readonly content: string;
+  readonly created_at: string;
+  readonly read_at?: string;
+}
+
+// From codersdk/notifications.go
+export interface NotificationPreference {
+  readonly kind: NotificationKind;
+  readonly disabled: boolean;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsConfig {
+  readonly email: NotificationsEmailConfig;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsEmailConfig {
+  readonly from: string;
+  readonly smarthost: string;
+  readonly hello: string;
+  readonly username: string;
+  readonly password: string;
+}
+
+// From codersdk/notifications.go
+export interface NotificationsRequest extends Pagination {
+  readonly unread_only?: boolean;
+}
+
+// From codersdk/notifications.go
+export interface NotificationsResponse {
+  readonly notifications: Notification[];
+  readonly unread_count: number;
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2AppEndpoints {
+  readonly authorization: string;
+  readonly token: string;
+  readonly revocation: string;
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig;
@@ -600,6 +790,33 @@ export interface OAuth2GithubConfig {
   readonly enterprise_base_url: string;
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2ProviderApp {
+  readonly id: string;
+  readonly name: string;
+  readonly icon: string;
+  readonly redirect_uris: string[];
+  readonly endpoints: OAuth2AppEndpoints;
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppFilter {
+  readonly user_id?: string;
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecret {
+  readonly id: string;
+  readonly last_used_at?: string;
+  readonly client_secret_truncated: string;
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecretFull {
+  readonly id: string;
+  readonly client_secret_full: string;
+}
+
 // From codersdk/users.go
 export interface OAuthConversionResponse {
   readonly state_string: string;
@@ -700,6 +917,20 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean;
 }
 
+// From codersdk/roles.go
+export interface Permission {
+  readonly negate: boolean;
+  readonly resource_type: RBACResource;
+  readonly action: string;
+}
+
+// From codersdk/oauth2.go
+export interface PostOAuth2ProviderAppRequest {
+  readonly name: string;
+  readonly redirect_uris: string[];
+  readonly icon: string;
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean;
@@ -776,6 +1007,13 @@ export interface PutExtendWorkspaceRequest {
   readonly deadline: string;
 }
 
+// From codersdk/oauth2.go
+export interface PutOAuth2ProviderAppRequest {
+  readonly name: string;
+  readonly redirect_uris: string[];
+  readonly icon: string;
+}
+
 // From codersdk/deployment.go
 export interface RateLimitConfig {
   readonly disable_all: boolean;
@@ -858,6 +1096,12 @@ export interface SessionCountDeploymentStats {
   readonly reconnecting_pty: number;
 }
 
+// From codersdk/deployment.go
+export interface SessionRecordingConfig {
+  readonly enable: boolean;
+  readonly record_input: boolean;
+}
+
 // From codersdk/deployment.go
 export interface SupportConfig {
   // Named type "github.com/coder/coder/v2/cli/clibase.Struct[[]github.com/coder/coder/v2/codersdk.LinkConfig]" unknown, using "any"
@@ -920,6 +1164,12 @@ export interface Template {
   readonly failure_ttl_ms: number;
   readonly time_til_dormant_ms: number;
   readonly time_til_dormant_autodelete_ms: number;
+  readonly max_port_share_level: WorkspaceAgentPortShareLevel;
+  readonly record_sessions: boolean;
+  readonly deprecated: boolean;
+  readonly deprecation_message: string;
+  readonly deprecated_at?: string;
+  readonly require_active_version: boolean;
 }
 
 // From codersdk/templates.go
@@ -961,6 +1211,11 @@ export interface TemplateExample {
   readonly markdown: string;
 }
 
+// From codersdk/organizations.go
+export interface TemplateFilter {
+  readonly q?: string;
+}
+
 // From codersdk/templates.go
 export interface TemplateGroup extends Group {
   readonly role: TemplateRole;
@@ -1072,6 +1327,13 @@ export interface TemplateVersionParameterOption {
   readonly icon: string;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionPreset {
+  readonly id: string;
+  readonly name: string;
+  readonly parameters: WorkspaceBuildParameter[];
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionVariable {
   readonly name: string;
@@ -1130,6 +1392,19 @@ export interface UpdateCheckResponse {
   readonly url: string;
 }
 
+// From codersdk/roles.go
+export interface UpdateCustomRoleRequest {
+  readonly display_name?: string;
+  readonly site_permissions: Permission[];
+  readonly organization_permissions: Permission[];
+  readonly user_permissions: Permission[];
+}
+
+// From codersdk/notifications.go
+export interface UpdateNotificationPreferencesRequest {
+  readonly preferences: NotificationPreference[];
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
   readonly roles: string[];
@@ -1158,6 +1433,10 @@ export interface UpdateTemplateMeta {
   readonly time_til_dormant_autodelete_ms?: number;
   readonly update_workspace_last_used_at: boolean;
   readonly update_workspace_dormant_at: boolean;
+  readonly max_port_share_level?: WorkspaceAgentPortShareLevel;
+  readonly record_sessions?: boolean;
+  readonly deprecation_message?: string;
+  readonly require_active_version?: boolean;
 }
 
 // From codersdk/users.go
@@ -1176,6 +1455,20 @@ export interface UpdateUserQuietHoursScheduleRequest {
   readonly schedule: string;
 }
 
+// From codersdk/webhooks.go
+export interface UpdateWebhookRequest {
+  readonly name?: string;
+  readonly url?: string;
+  readonly events?: WebhookEvent[];
+  readonly enabled?: boolean;
+  readonly secret?: string;
+}
+
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceAutomaticUpdatesRequest {
+  readonly automatic_updates: AutomaticUpdates;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string;
@@ -1192,6 +1485,11 @@ export interface UpdateWorkspaceProxyResponse {
   readonly proxy_token: string;
 }
 
+// From codersdk/prebuilds.go
+export interface UpdateWorkspacePrebuildPoolRequest {
+  readonly desired_instances: number;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceRequest {
   readonly name?: string;
@@ -1207,6 +1505,13 @@ export interface UploadResponse {
   readonly hash: string;
 }
 
+// From codersdk/workspaceagentportshare.go
+export interface UpsertWorkspaceAgentPortShareRequest {
+  readonly agent_name: string;
+  readonly port: number;
+  readonly share_level: WorkspaceAgentPortShareLevel;
+}
+
 // From codersdk/users.go
 export interface User {
   readonly id: string;
@@ -1219,6 +1524,7 @@ export interface User {
   readonly roles: Role[];
   readonly avatar_url: string;
   readonly login_type: LoginType;
+  readonly is_service_account: boolean;
 }
 
 // From codersdk/insights.go
@@ -1292,6 +1598,76 @@ export interface VariableValue {
   readonly value: string;
 }
 
+// From codersdk/webhooks.go
+export interface Webhook {
+  readonly id: string;
+  readonly created_at: string;
+  readonly updated_at: string;
+  readonly name: string;
+  readonly url: string;
+  readonly events: WebhookEvent[];
+  readonly enabled: boolean;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDeliveriesRequest extends Pagination {}
+
+// From codersdk/webhooks.go
+export interface WebhookDelivery {
+  readonly id: string;
+  readonly webhook_id: string;
+  readonly event: WebhookEvent;
+  readonly payload: Record<string, string>;
+  readonly created_at: string;
+  readonly attempts: number;
+  readonly last_attempt_at?: string;
+  readonly next_attempt_at?: string;
+  readonly delivered_at?: string;
+  readonly status_code?: number;
+  readonly error?: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayload {
+  readonly id: string;
+  readonly event: WebhookEvent;
+  readonly timestamp: string;
+  readonly workspace?: WebhookPayloadWorkspace;
+  readonly template_version?: WebhookPayloadTemplateVersion;
+  readonly user?: WebhookPayloadUser;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadTemplateVersion {
+  readonly id: string;
+  readonly name: string;
+  readonly template_id: string;
+  readonly template_name: string;
+  readonly organization_id: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadUser {
+  readonly id: string;
+  readonly username: string;
+  readonly email: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadWorkspace {
+  readonly id: string;
+  readonly name: string;
+  readonly owner_id: string;
+  readonly owner_name: string;
+  readonly organization_id: string;
+  readonly template_id: string;
+  readonly template_name: string;
+  readonly build_id: string;
+  readonly build_number: number;
+  readonly transition: WorkspaceTransition;
+  readonly error?: string;
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string;
@@ -1306,6 +1682,7 @@ export interface Workspace {
   readonly template_icon: string;
   readonly template_allow_user_cancel_workspace_jobs: boolean;
   readonly template_active_version_id: string;
+  readonly template_deprecation_message: string;
   readonly latest_build: WorkspaceBuild;
   readonly outdated: boolean;
   readonly name: string;
@@ -1315,6 +1692,7 @@ export interface Workspace {
   readonly deleting_at?: string;
   readonly dormant_at?: string;
   readonly health: WorkspaceHealth;
+  readonly automatic_updates: AutomaticUpdates;
 }
 
 // From codersdk/workspaceagents.go
@@ -1353,6 +1731,18 @@ export interface WorkspaceAgent {
   readonly subsystems: AgentSubsystem[];
   readonly health: WorkspaceAgentHealth;
   readonly display_apps: DisplayApp[];
+  readonly log_sources: WorkspaceAgentLogSource[];
+  readonly scripts: WorkspaceAgentScript[];
+  readonly autostop_inhibitors: WorkspaceAgentAutostopInhibitor[];
+  readonly update_state: WorkspaceAgentUpdateState;
+  readonly update_error?: string;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentAutostopInhibitor {
+  readonly id: string;
+  readonly reason: string;
+  readonly created_at: string;
 }
 
 // From codersdk/workspaceagents.go
@@ -1379,6 +1769,23 @@ export interface WorkspaceAgentLog {
   readonly created_at: string;
   readonly output: string;
   readonly level: LogLevel;
+  readonly source_id: string;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentLogFile {
+  readonly id: string;
+  readonly log_source_id: string;
+  readonly path: string;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentLogSource {
+  readonly workspace_agent_id: string;
+  readonly id: string;
+  readonly created_at: string;
+  readonly display_name: string;
+  readonly icon: string;
 }
 
 // From codersdk/workspaceagents.go
@@ -1404,6 +1811,60 @@ export interface WorkspaceAgentMetadataResult {
   readonly error: string;
 }
 
+// From codersdk/workspaceagentptysessions.go
+export interface WorkspaceAgentPTYInvite {
+  readonly workspace_id: string;
+  readonly agent_id: string;
+  readonly session_id: string;
+  readonly user_id: string;
+  readonly read_only: boolean;
+  readonly created_by: string;
+  readonly created_at: string;
+}
+
+// From codersdk/workspaceagentportshare.go
+export interface WorkspaceAgentPortShare {
+  readonly workspace_id: string;
+  readonly agent_name: string;
+  readonly port: number;
+  readonly share_level: WorkspaceAgentPortShareLevel;
+}
+
+// From codersdk/workspaceagentportshare.go
+export interface WorkspaceAgentPortShares {
+  readonly shares: WorkspaceAgentPortShare[];
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTYSession {
+  readonly id: string;
+  readonly command: string;
+  readonly created_at: string;
+  readonly connections: number;
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTYSessionsResponse {
+  readonly sessions: WorkspaceAgentReconnectingPTYSession[];
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentScript {
+  readonly id: string;
+  readonly log_source_id: string;
+  readonly log_path: string;
+  readonly script: string;
+  readonly cron: string;
+  readonly run_on_start: boolean;
+  readonly run_on_stop: boolean;
+  readonly start_blocks_login: boolean;
+  readonly timeout: number;
+  readonly status: WorkspaceAgentScriptStatus;
+  readonly exit_code: number;
+  readonly started_at?: string;
+  readonly ended_at?: string;
+}
+
 // From codersdk/workspaceapps.go
 export interface WorkspaceApp {
   readonly id: string;
@@ -1489,6 +1950,20 @@ export interface WorkspaceOptions {
   readonly include_deleted?: boolean;
 }
 
+// From codersdk/prebuilds.go
+export interface WorkspacePrebuildPool {
+  readonly id: string;
+  readonly template_id: string;
+  readonly template_version_id: string;
+  readonly parameters: WorkspaceBuildParameter[];
+  readonly desired_instances: number;
+  readonly ready_instances: number;
+  readonly building_instances: number;
+  readonly failed_instances: number;
+  readonly created_at: string;
+  readonly updated_at: string;
+}
+
 // From codersdk/workspaceproxy.go
 export interface WorkspaceProxy extends Region {
   readonly derp_enabled: boolean;
@@ -1540,6 +2015,21 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean;
 }
 
+// From codersdk/workspacesessionrecordings.go
+export interface WorkspaceSessionRecording {
+  readonly id: string;
+  readonly workspace_id: string;
+  readonly agent_id: string;
+  readonly type: WorkspaceSessionRecordingType;
+  readonly reconnect_id?: string;
+  readonly command: string;
+  readonly includes_input: boolean;
+  readonly size: number;
+  readonly created_at: string;
+  readonly updated_at: string;
+  readonly ended_at?: string;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
   readonly q?: string;
@@ -1552,8 +2042,12 @@ export interface WorkspacesResponse {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect";
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
+export type APIKeyScope = "all" | "application_connect" | "custom";
+export const APIKeyScopes: APIKeyScope[] = [
+  "all",
+  "application_connect",
+  "custom",
+];
 
 // From codersdk/workspaceagents.go
 export type AgentSubsystem = "envbox" | "envbuilder" | "exectrace";
@@ -1565,6 +2059,7 @@ export const AgentSubsystems: AgentSubsystem[] = [
 
 // From codersdk/audit.go
 export type AuditAction =
+  | "connect"
   | "create"
   | "delete"
   | "login"
@@ -1574,6 +2069,7 @@ export type AuditAction =
   | "stop"
   | "write";
 export const AuditActions: AuditAction[] = [
+  "connect",
   "create",
   "delete",
   "login",
@@ -1584,6 +2080,10 @@ export const AuditActions: AuditAction[] = [
   "write",
 ];
 
+// From codersdk/workspaces.go
+export type AutomaticUpdates = "always" | "never";
+export const AutomaticUpdateses: AutomaticUpdates[] = ["always", "never"];
+
 // From codersdk/workspacebuilds.go
 export type BuildReason = "autostart" | "autostop" | "initiator";
 export const BuildReasons: BuildReason[] = [
@@ -1592,6 +2092,23 @@ export const BuildReasons: BuildReason[] = [
   "initiator",
 ];
 
+// From codersdk/connectionlog.go
+export type ConnectionType =
+  | "jetbrains"
+  | "port_forwarding"
+  | "reconnecting_pty"
+  | "ssh"
+  | "vscode"
+  | "workspace_app";
+export const ConnectionTypes: ConnectionType[] = [
+  "jetbrains",
+  "port_forwarding",
+  "reconnecting_pty",
+  "ssh",
+  "vscode",
+  "workspace_app",
+];
+
 // From codersdk/workspaceagents.go
 export type DisplayApp =
   | "port_forwarding_helper"
@@ -1701,16 +2218,52 @@ export type LogSource = "provisioner" | "provisioner_daemon";
 export const LogSources: LogSource[] = ["provisioner", "provisioner_daemon"];
 
 // From codersdk/apikey.go
-export type LoginType = "" | "github" | "none" | "oidc" | "password" | "token";
+export type LoginType =
+  | ""
+  | "github"
+  | "ldap"
+  | "none"
+  | "oidc"
+  | "password"
+  | "token";
 export const LoginTypes: LoginType[] = [
   "",
   "github",
+  "ldap",
   "none",
   "oidc",
   "password",
   "token",
 ];
 
+// From codersdk/notifications.go
+export type NotificationKind =
+  | "account_dormant"
+  | "workspace_autostop"
+  | "workspace_build_failed"
+  | "workspace_dormant"
+  | "workspace_marked_for_deletion";
+export const NotificationKinds: NotificationKind[] = [
+  "account_dormant",
+  "workspace_autostop",
+  "workspace_build_failed",
+  "workspace_dormant",
+  "workspace_marked_for_deletion",
+];
+
+// From codersdk/oauth2.go
+export type OAuth2ProviderGrantType = "authorization_code" | "refresh_token";
+export const OAuth2ProviderGrantTypes: OAuth2ProviderGrantType[] = [
+  "authorization_code",
+  "refresh_token",
+];
+
+// From codersdk/oauth2.go
+export type OAuth2ProviderResponseType = "code";
+export const OAuth2ProviderResponseTypes: OAuth2ProviderResponseType[] = [
+  "code",
+];
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1760,20 +2313,26 @@ export type RBACResource =
   | "assign_org_role"
   | "assign_role"
   | "audit_log"
+  | "connection_log"
   | "debug_info"
   | "deployment_config"
   | "deployment_stats"
   | "file"
   | "group"
   | "license"
+  | "oauth2_app"
+  | "oauth2_app_code_token"
+  | "oauth2_app_secret"
   | "organization"
   | "organization_member"
   | "provisioner_daemon"
   | "replicas"
+  | "session_recording"
   | "system"
   | "template"
   | "user"
   | "user_data"
+  | "webhook"
   | "workspace"
   | "workspace_execution"
   | "workspace_proxy";
@@ -1783,20 +2342,26 @@ export const RBACResources: RBACResource[] = [
   "assign_org_role",
   "assign_role",
   "audit_log",
+  "connection_log",
   "debug_info",
   "deployment_config",
   "deployment_stats",
   "file",
   "group",
   "license",
+  "oauth2_app",
+  "oauth2_app_code_token",
+  "oauth2_app_secret",
   "organization",
   "organization_member",
   "provisioner_daemon",
   "replicas",
+  "session_recording",
   "system",
   "template",
   "user",
   "user_data",
+  "webhook",
   "workspace",
   "workspace_execution",
   "workspace_proxy",
@@ -1806,9 +2371,12 @@ export const RBACResources: RBACResource[] = [
 export type ResourceType =
   | "api_key"
   | "convert_login"
+  | "custom_role"
   | "git_ssh_key"
   | "group"
   | "license"
+  | "oauth2_provider_app"
+  | "oauth2_provider_app_secret"
   | "organization"
   | "template"
   | "template_version"
@@ -1819,9 +2387,12 @@ export type ResourceType =
 export const ResourceTypes: ResourceType[] = [
   "api_key",
   "convert_login",
+  "custom_role",
   "git_ssh_key",
   "group",
   "license",
+  "oauth2_provider_app",
+  "oauth2_provider_app_secret",
   "organization",
   "template",
   "template_version",
@@ -1864,6 +2435,25 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ];
 
+// From codersdk/webhooks.go
+export type WebhookEvent =
+  | "template_version_promoted"
+  | "user_created"
+  | "workspace_build_failed"
+  | "workspace_created"
+  | "workspace_deleted"
+  | "workspace_started"
+  | "workspace_stopped";
+export const WebhookEvents: WebhookEvent[] = [
+  "template_version_promoted",
+  "user_created",
+  "workspace_build_failed",
+  "workspace_created",
+  "workspace_deleted",
+  "workspace_started",
+  "workspace_stopped",
+];
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -1887,21 +2477,27 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
   "starting",
 ];
 
+// From codersdk/workspaceagentportshare.go
+export type WorkspaceAgentPortShareLevel = "authenticated" | "owner" | "public";
+export const WorkspaceAgentPortShareLevels: WorkspaceAgentPortShareLevel[] = [
+  "authenticated",
+  "owner",
+  "public",
+];
+
 // From codersdk/workspaceagents.go
-export type WorkspaceAgentLogSource =
-  | "envbox"
-  | "envbuilder"
-  | "external"
-  | "kubernetes"
-  | "shutdown_script"
-  | "startup_script";
-export const WorkspaceAgentLogSources: WorkspaceAgentLogSource[] = [
-  "envbox",
-  "envbuilder",
-  "external",
-  "kubernetes",
-  "shutdown_script",
-  "startup_script",
+export type WorkspaceAgentScriptStatus =
+  | "exit_failure"
+  | "ok"
+  | "pending"
+  | "running"
+  | "timed_out";
+export const WorkspaceAgentScriptStatuses: WorkspaceAgentScriptStatus[] = [
+  "exit_failure",
+  "ok",
+  "pending",
+  "running",
+  "timed_out",
 ];
 
 // From codersdk/workspaceagents.go
@@ -1922,6 +2518,23 @@ export const WorkspaceAgentStatuses: WorkspaceAgentStatus[] = [
   "timeout",
 ];
 
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentUpdateState =
+  | "disabled"
+  | "failed"
+  | "unknown"
+  | "up_to_date"
+  | "updated"
+  | "updating";
+export const WorkspaceAgentUpdateStates: WorkspaceAgentUpdateState[] = [
+  "disabled",
+  "failed",
+  "unknown",
+  "up_to_date",
+  "updated",
+  "updating",
+];
+
 // From codersdk/workspaceapps.go
 export type WorkspaceAppHealth =
   | "disabled"
@@ -1943,6 +2556,21 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ];
 
+// From codersdk/deployment.go
+export type WorkspaceNetworking = "disabled" | "group" | "owner";
+export const WorkspaceNetworkings: WorkspaceNetworking[] = [
+  "disabled",
+  "group",
+  "owner",
+];
+
+// From codersdk/workspacesessionrecordings.go
+export type WorkspaceSessionRecordingType = "reconnecting_pty" | "ssh";
+export const WorkspaceSessionRecordingTypes: WorkspaceSessionRecordingType[] = [
+  "reconnecting_pty",
+  "ssh",
+];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
@@ -42,6 +42,10 @@ export const authMethodLanguage = {
     displayName: "Github",
     description: "Use Github OAuth for authentication",
   },
+  ldap: {
+    displayName: "LDAP",
+    description: "Use the username and password of an LDAP directory to login",
+  },
   none: {
     displayName: "None",
     description: (
@@ -95,6 +99,7 @@ export const CreateUserForm: FC<
         organization_id: myOrgId,
         disable_login: false,
         login_type: "",
+        service_account: false,
       },
       validationSchema,
       onSubmit,
@@ -110,6 +115,7 @@ export const CreateUserForm: FC<
     authMethods?.password.enabled && "password",
     authMethods?.oidc.enabled && "oidc",
     authMethods?.github.enabled && "github",
+    authMethods?.ldap.enabled && "ldap",
     "none",
   ].filter(Boolean) as Array<keyof typeof authMethodLanguage>;
 
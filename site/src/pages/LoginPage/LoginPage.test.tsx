@@ -64,6 +64,7 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      ldap: { enabled: false },
     };
 
     // Given
@@ -115,6 +116,7 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      ldap: { enabled: false },
     };
 
     // Given
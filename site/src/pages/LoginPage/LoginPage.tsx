@@ -32,6 +32,9 @@ export const LoginPage: FC = () => {
           onSignIn={({ email, password }) => {
             authSend({ type: "SIGN_IN", email, password });
           }}
+          onSignInWithLDAP={({ username, password }) => {
+            authSend({ type: "SIGN_IN_WITH_LDAP", username, password });
+          }}
         />
       </>
     );
@@ -12,6 +12,10 @@ export interface LoginPageViewProps {
   isLoading: boolean;
   isSigningIn: boolean;
   onSignIn: (credentials: { email: string; password: string }) => void;
+  onSignInWithLDAP: (credentials: {
+    username: string;
+    password: string;
+  }) => void;
 }
 
 export const LoginPageView: FC<LoginPageViewProps> = ({
@@ -19,6 +23,7 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
   isLoading,
   isSigningIn,
   onSignIn,
+  onSignInWithLDAP,
 }) => {
   const location = useLocation();
   const redirectTo = retrieveRedirect(location.search);
@@ -42,6 +47,7 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
           error={error}
           info={info}
           onSubmit={onSignIn}
+          onSubmitLDAP={onSignInWithLDAP}
         />
         <footer className={styles.footer}>
           Copyright © {new Date().getFullYear()} Coder Technologies, Inc.
@@ -0,0 +1,68 @@
+import { Stack } from "../../../components/Stack/Stack";
+import TextField from "@mui/material/TextField";
+import { getFormHelpers, onChangeTrimmed } from "../../../utils/formUtils";
+import { LoadingButton } from "../../../components/LoadingButton/LoadingButton";
+import { Language } from "./SignInForm";
+import { FormikContextType, useFormik } from "formik";
+import * as Yup from "yup";
+import { FC } from "react";
+import { LDAPAuthFormValues } from "./SignInForm.types";
+
+type LDAPSignInFormProps = {
+  onSubmit: (credentials: { username: string; password: string }) => void;
+  isSigningIn: boolean;
+};
+
+export const LDAPSignInForm: FC<LDAPSignInFormProps> = ({
+  onSubmit,
+  isSigningIn,
+}) => {
+  const validationSchema = Yup.object({
+    username: Yup.string().trim().required(Language.usernameRequired),
+    password: Yup.string(),
+  });
+
+  const form: FormikContextType<LDAPAuthFormValues> =
+    useFormik<LDAPAuthFormValues>({
+      initialValues: {
+        username: "",
+        password: "",
+      },
+      validationSchema,
+      onSubmit,
+    });
+  const getFieldHelpers = getFormHelpers<LDAPAuthFormValues>(form);
+
+  return (
+    <form onSubmit={form.handleSubmit}>
+      <Stack spacing={2.5}>
+        <TextField
+          {...getFieldHelpers("username")}
+          onChange={onChangeTrimmed(form)}
+          autoFocus
+          autoComplete="username"
+          fullWidth
+          label={Language.usernameLabel}
+        />
+        <TextField
+          {...getFieldHelpers("password")}
+          autoComplete="current-password"
+          fullWidth
+          id="ldap-password"
+          label={Language.passwordLabel}
+          type="password"
+        />
+        <div>
+          <LoadingButton
+            size="large"
+            loading={isSigningIn}
+            fullWidth
+            type="submit"
+          >
+            {isSigningIn ? "" : Language.ldapSignIn}
+          </LoadingButton>
+        </div>
+      </Stack>
+    </form>
+  );
+};
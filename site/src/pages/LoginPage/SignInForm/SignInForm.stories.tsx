@@ -8,6 +8,7 @@ export default {
   argTypes: {
     isLoading: "boolean",
     onSubmit: { action: "Submit" },
+    onSubmitLDAP: { action: "Submit LDAP" },
   },
 };
 
@@ -31,6 +32,7 @@ SigningIn.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 };
 
@@ -58,6 +60,7 @@ WithGithub.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 };
 
@@ -68,6 +71,7 @@ WithOIDC.args = {
     password: { enabled: true },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 };
 
@@ -78,6 +82,7 @@ WithOIDCWithoutPassword.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 };
 
@@ -88,6 +93,7 @@ WithoutAny.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 };
 
@@ -98,5 +104,20 @@ WithGithubAndOIDC.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
+  },
+};
+
+export const WithLDAP = Template.bind({});
+WithLDAP.args = {
+  ...SignedOut.args,
+  onSubmitLDAP: () => {
+    return Promise.resolve();
+  },
+  authMethods: {
+    password: { enabled: true },
+    github: { enabled: false },
+    oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: true },
   },
 };
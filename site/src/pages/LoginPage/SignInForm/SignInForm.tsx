@@ -6,6 +6,7 @@ import { useTranslation } from "react-i18next";
 import { Maybe } from "../../../components/Conditionals/Maybe";
 import { PasswordSignInForm } from "./PasswordSignInForm";
 import { OAuthSignInForm } from "./OAuthSignInForm";
+import { LDAPSignInForm } from "./LDAPSignInForm";
 import { BuiltInAuthFormValues } from "./SignInForm.types";
 import Button from "@mui/material/Button";
 import EmailIcon from "@mui/icons-material/EmailOutlined";
@@ -17,9 +18,12 @@ export const Language = {
   passwordLabel: "Password",
   emailInvalid: "Please enter a valid email address.",
   emailRequired: "Please enter an email address.",
+  usernameLabel: "Username",
+  usernameRequired: "Please enter a username.",
   passwordSignIn: "Sign In",
   githubSignIn: "GitHub",
   oidcSignIn: "OpenID Connect",
+  ldapSignIn: "Sign In with LDAP",
 };
 
 const useStyles = makeStyles((theme) => ({
@@ -72,6 +76,7 @@ export interface SignInFormProps {
   info?: string;
   authMethods?: AuthMethods;
   onSubmit: (credentials: { email: string; password: string }) => void;
+  onSubmitLDAP?: (credentials: { username: string; password: string }) => void;
   // initialTouched is only used for testing the error state of the form.
   initialTouched?: FormikTouched<BuiltInAuthFormValues>;
 }
@@ -83,14 +88,18 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
   error,
   info,
   onSubmit,
+  onSubmitLDAP,
   initialTouched,
 }) => {
   const oAuthEnabled = Boolean(
     authMethods?.github.enabled || authMethods?.oidc.enabled,
   );
+  const ldapEnabled = Boolean(authMethods?.ldap?.enabled && onSubmitLDAP);
   const passwordEnabled = authMethods?.password.enabled ?? true;
-  // Hide password auth by default if any OAuth method is enabled
-  const [showPasswordAuth, setShowPasswordAuth] = useState(!oAuthEnabled);
+  // Hide password auth by default if any OAuth method or LDAP is enabled
+  const [showPasswordAuth, setShowPasswordAuth] = useState(
+    !oAuthEnabled && !ldapEnabled,
+  );
   const styles = useStyles();
   const commonTranslation = useTranslation("common");
   const loginPageTranslation = useTranslation("loginPage");
@@ -111,6 +120,24 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
           <Alert severity="info">{info}</Alert>
         </div>
       </Maybe>
+      <Maybe condition={ldapEnabled}>
+        <LDAPSignInForm
+          onSubmit={(credentials) => onSubmitLDAP?.(credentials)}
+          isSigningIn={isSigningIn}
+        />
+      </Maybe>
+      <Maybe
+        condition={
+          ldapEnabled &&
+          ((passwordEnabled && showPasswordAuth) || oAuthEnabled)
+        }
+      >
+        <div className={styles.divider}>
+          <div className={styles.dividerLine} />
+          <div className={styles.dividerLabel}>Or</div>
+          <div className={styles.dividerLine} />
+        </div>
+      </Maybe>
       <Maybe condition={passwordEnabled && showPasswordAuth}>
         <PasswordSignInForm
           onSubmit={onSubmit}
@@ -133,7 +160,7 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
         />
       </Maybe>
 
-      <Maybe condition={!passwordEnabled && !oAuthEnabled}>
+      <Maybe condition={!passwordEnabled && !oAuthEnabled && !ldapEnabled}>
         <Alert severity="error">No authentication methods configured!</Alert>
       </Maybe>
 
@@ -7,3 +7,12 @@ export interface BuiltInAuthFormValues {
   email: string;
   password: string;
 }
+
+/**
+ * LDAPAuthFormValues describes a form authenticating with the username and
+ * password of an LDAP directory. It is only present if LDAP is configured.
+ */
+export interface LDAPAuthFormValues {
+  username: string;
+  password: string;
+}
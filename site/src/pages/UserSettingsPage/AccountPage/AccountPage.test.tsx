@@ -39,6 +39,7 @@ describe("AccountPage", () => {
           avatar_url: "",
           last_seen_at: new Date().toString(),
           login_type: "password",
+          is_service_account: false,
           ...data,
         }),
       );
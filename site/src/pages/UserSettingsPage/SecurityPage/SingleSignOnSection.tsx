@@ -3,6 +3,7 @@ import { Section } from "../../../components/SettingsLayout/Section";
 import TextField from "@mui/material/TextField";
 import Box from "@mui/material/Box";
 import GitHubIcon from "@mui/icons-material/GitHub";
+import AccountTreeOutlined from "@mui/icons-material/AccountTreeOutlined";
 import KeyIcon from "@mui/icons-material/VpnKey";
 import Button from "@mui/material/Button";
 import Typography from "@mui/material/Typography";
@@ -164,17 +165,14 @@ export const SingleSignOnSection = ({
                 <span>
                   Authenticated with{" "}
                   <strong>
-                    {userLoginType.login_type === "github"
-                      ? "GitHub"
-                      : getOIDCLabel(authMethods)}
+                    {getLoginTypeLabel(userLoginType.login_type, authMethods)}
                   </strong>
                 </span>
                 <Box sx={{ ml: "auto", lineHeight: 1 }}>
-                  {userLoginType.login_type === "github" ? (
-                    <GitHubIcon sx={{ width: 16, height: 16 }} />
-                  ) : (
-                    <OIDCIcon authMethods={authMethods} />
-                  )}
+                  <LoginTypeIcon
+                    loginType={userLoginType.login_type}
+                    authMethods={authMethods}
+                  />
                 </Box>
               </Box>
             )
@@ -215,6 +213,34 @@ const getOIDCLabel = (authMethods: AuthMethods) => {
   return authMethods.oidc.signInText || "OpenID Connect";
 };
 
+const LoginTypeIcon = ({
+  loginType,
+  authMethods,
+}: {
+  loginType: LoginType;
+  authMethods: AuthMethods;
+}) => {
+  switch (loginType) {
+    case "github":
+      return <GitHubIcon sx={{ width: 16, height: 16 }} />;
+    case "ldap":
+      return <AccountTreeOutlined sx={{ width: 16, height: 16 }} />;
+    default:
+      return <OIDCIcon authMethods={authMethods} />;
+  }
+};
+
+const getLoginTypeLabel = (loginType: LoginType, authMethods: AuthMethods) => {
+  switch (loginType) {
+    case "github":
+      return "GitHub";
+    case "ldap":
+      return "LDAP";
+    default:
+      return getOIDCLabel(authMethods);
+  }
+};
+
 const ConfirmLoginTypeChangeModal = ({
   open,
   loading,
@@ -20,6 +20,7 @@ import { Stack } from "components/Stack/Stack";
 import { EnterpriseBadge } from "components/DeploySettingsLayout/Badges";
 import dayjs from "dayjs";
 import { SxProps, Theme } from "@mui/material/styles";
+import AccountTreeOutlined from "@mui/icons-material/AccountTreeOutlined";
 import HideSourceOutlined from "@mui/icons-material/HideSourceOutlined";
 import KeyOutlined from "@mui/icons-material/KeyOutlined";
 import GitHub from "@mui/icons-material/GitHub";
@@ -298,6 +299,9 @@ const LoginType = ({
   } else if (value === "github") {
     displayName = "GitHub";
     icon = <GitHub sx={iconStyles} />;
+  } else if (value === "ldap") {
+    displayName = "LDAP";
+    icon = <AccountTreeOutlined sx={iconStyles} />;
   } else if (value === "token") {
     displayName = "Token";
     icon = <KeyOutlined sx={iconStyles} />;
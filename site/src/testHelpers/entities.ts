@@ -277,6 +277,7 @@ export const MockUser: TypesGen.User = {
   avatar_url: "https://avatars.githubusercontent.com/u/95932066?s=200&v=4",
   last_seen_at: "",
   login_type: "password",
+  is_service_account: false,
 };
 
 export const MockUserAdmin: TypesGen.User = {
@@ -290,6 +291,7 @@ export const MockUserAdmin: TypesGen.User = {
   avatar_url: "",
   last_seen_at: "",
   login_type: "password",
+  is_service_account: false,
 };
 
 export const MockUser2: TypesGen.User = {
@@ -303,6 +305,7 @@ export const MockUser2: TypesGen.User = {
   avatar_url: "",
   last_seen_at: "2022-09-14T19:12:21Z",
   login_type: "oidc",
+  is_service_account: false,
 };
 
 export const SuspendedMockUser: TypesGen.User = {
@@ -316,6 +319,7 @@ export const SuspendedMockUser: TypesGen.User = {
   avatar_url: "",
   last_seen_at: "",
   login_type: "password",
+  is_service_account: false,
 };
 
 export const MockProvisioner: TypesGen.ProvisionerDaemon = {
@@ -449,6 +453,11 @@ export const MockTemplate: TypesGen.Template = {
   time_til_dormant_autodelete_ms: 0,
   allow_user_autostart: false,
   allow_user_autostop: false,
+  max_port_share_level: "owner",
+  record_sessions: false,
+  deprecated: false,
+  deprecation_message: "",
+  require_active_version: false,
 };
 
 export const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -536,6 +545,28 @@ export const MockWorkspaceApp: TypesGen.WorkspaceApp = {
   },
 };
 
+export const MockWorkspaceAgentLogSource: TypesGen.WorkspaceAgentLogSource = {
+  created_at: "2023-05-04T11:30:41.402072Z",
+  id: "dc790496-eaec-4f88-a53f-8ce1f61a1fff",
+  display_name: "Startup Script",
+  icon: "",
+  workspace_agent_id: "",
+};
+
+export const MockWorkspaceAgentScript: TypesGen.WorkspaceAgentScript = {
+  id: "b2f1a7a6-3c5e-4f2b-9d2e-6a8f3c1d0e11",
+  log_source_id: MockWorkspaceAgentLogSource.id,
+  cron: "",
+  log_path: "",
+  run_on_start: true,
+  run_on_stop: false,
+  script: "echo 'hello world'",
+  start_blocks_login: false,
+  timeout: 0,
+  status: "ok",
+  exit_code: 0,
+};
+
 export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
   apps: [MockWorkspaceApp],
   architecture: "amd64",
@@ -574,6 +605,10 @@ export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
     "vscode_insiders",
     "web_terminal",
   ],
+  log_sources: [MockWorkspaceAgentLogSource],
+  scripts: [MockWorkspaceAgentScript],
+  autostop_inhibitors: [],
+  update_state: "up_to_date",
 };
 
 export const MockWorkspaceAgentDisconnected: TypesGen.WorkspaceAgent = {
@@ -856,6 +891,7 @@ export const MockWorkspace: TypesGen.Workspace = {
   template_allow_user_cancel_workspace_jobs:
     MockTemplate.allow_user_cancel_workspace_jobs,
   template_active_version_id: MockTemplate.active_version_id,
+  template_deprecation_message: "",
   outdated: false,
   owner_id: MockUser.id,
   organization_id: MockOrganization.id,
@@ -868,6 +904,7 @@ export const MockWorkspace: TypesGen.Workspace = {
     healthy: true,
     failing_agents: [],
   },
+  automatic_updates: "never",
 };
 
 export const MockStoppedWorkspace: TypesGen.Workspace = {
@@ -1125,6 +1162,7 @@ export const MockAuthMethods: TypesGen.AuthMethods = {
   password: { enabled: true },
   github: { enabled: false },
   oidc: { enabled: false, signInText: "", iconUrl: "" },
+  ldap: { enabled: false },
 };
 
 export const MockAuthMethodsWithPasswordType: TypesGen.AuthMethods = {
@@ -2131,6 +2169,7 @@ export const MockWorkspaceAgentLogs: TypesGen.WorkspaceAgentLog[] = [
     created_at: "2023-05-04T11:30:41.402072Z",
     output: "+ curl -fsSL https://code-server.dev/install.sh",
     level: "info",
+    source_id: MockWorkspaceAgentLogSource.id,
   },
   {
     id: 166664,
@@ -2138,18 +2177,21 @@ export const MockWorkspaceAgentLogs: TypesGen.WorkspaceAgentLog[] = [
     output:
       "+ sh -s -- --method=standalone --prefix=/tmp/code-server --version 4.8.3",
     level: "info",
+    source_id: MockWorkspaceAgentLogSource.id,
   },
   {
     id: 166665,
     created_at: "2023-05-04T11:30:42.590731Z",
     output: "Ubuntu 22.04.2 LTS",
     level: "info",
+    source_id: MockWorkspaceAgentLogSource.id,
   },
   {
     id: 166666,
     created_at: "2023-05-04T11:30:42.593686Z",
     output: "Installing v4.8.3 of the amd64 release from GitHub.",
     level: "info",
+    source_id: MockWorkspaceAgentLogSource.id,
   },
 ];
 
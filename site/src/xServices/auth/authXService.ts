@@ -152,10 +152,15 @@ const loadInitialAuthData = async (): Promise<AuthData> => {
 };
 
 const signIn = async (
-  email: string,
-  password: string,
+  event:
+    | { type: "SIGN_IN"; email: string; password: string }
+    | { type: "SIGN_IN_WITH_LDAP"; username: string; password: string },
 ): Promise<AuthenticatedData> => {
-  await API.login(email, password);
+  if (event.type === "SIGN_IN_WITH_LDAP") {
+    await API.loginWithLDAP(event.username, event.password);
+  } else {
+    await API.login(event.email, event.password);
+  }
   const [user, permissions] = await Promise.all([
     API.getAuthenticatedUser(),
     API.checkAuthorization({
@@ -189,6 +194,7 @@ export interface AuthContext {
 export type AuthEvent =
   | { type: "SIGN_OUT" }
   | { type: "SIGN_IN"; email: string; password: string }
+  | { type: "SIGN_IN_WITH_LDAP"; username: string; password: string }
   | { type: "UPDATE_PROFILE"; data: TypesGen.UpdateUserProfileRequest };
 
 export const authMachine =
@@ -249,6 +255,9 @@ export const authMachine =
             SIGN_IN: {
               target: "signingIn",
             },
+            SIGN_IN_WITH_LDAP: {
+              target: "signingIn",
+            },
           },
         },
 
@@ -355,7 +364,7 @@ export const authMachine =
     {
       services: {
         loadInitialAuthData,
-        signIn: (_, { email, password }) => signIn(email, password),
+        signIn: (_, event) => signIn(event),
         signOut,
         updateProfile: async ({ data }, event) => {
           if (!data) {
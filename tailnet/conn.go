@@ -35,6 +35,7 @@ import (
 	tslogger "tailscale.com/types/logger"
 	"tailscale.com/types/netlogtype"
 	"tailscale.com/types/netmap"
+	"tailscale.com/util/dnsname"
 	"tailscale.com/wgengine"
 	"tailscale.com/wgengine/filter"
 	"tailscale.com/wgengine/magicsock"
@@ -99,6 +100,9 @@ type Options struct {
 	BlockEndpoints bool
 	Logger         slog.Logger
 	ListenPort     uint16
+	// NodePrivateKey is the private key of the node. A new key is generated
+	// if it is zero.
+	NodePrivateKey key.NodePrivate
 }
 
 // NodeID creates a Tailscale NodeID from the last 8 bytes of a UUID. It ensures
@@ -125,7 +129,10 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		return nil, xerrors.New("DERPMap must be provided")
 	}
 
-	nodePrivateKey := key.NewNode()
+	nodePrivateKey := options.NodePrivateKey
+	if nodePrivateKey.IsZero() {
+		nodePrivateKey = key.NewNode()
+	}
 	nodePublicKey := nodePrivateKey.Public()
 
 	netMap := &netmap.NetworkMap{
@@ -408,6 +415,7 @@ type Conn struct {
 	wireguardRouter  *router.Config
 	wireguardEngine  wgengine.Engine
 	listeners        map[listenKey]*listener
+	dnsHosts         map[dnsname.FQDN][]netip.Addr
 
 	lastMutex   sync.Mutex
 	nodeSending bool
@@ -635,7 +643,7 @@ func (c *Conn) reconfig() error {
 		return xerrors.Errorf("update wireguard config: %w", err)
 	}
 
-	err = c.wireguardEngine.Reconfig(cfg, c.wireguardRouter, &dns.Config{}, &tailcfg.Debug{})
+	err = c.wireguardEngine.Reconfig(cfg, c.wireguardRouter, &dns.Config{Hosts: c.dnsHosts}, &tailcfg.Debug{})
 	if err != nil {
 		if c.isClosed() {
 			return nil
@@ -854,6 +862,14 @@ func (c *Conn) Node() *Node {
 	return c.selfNode()
 }
 
+// NodePrivateKey returns the private key of the node. Passing it to a new
+// Conn with the same ID and addresses keeps the identity of the node.
+func (c *Conn) NodePrivateKey() key.NodePrivate {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.netMap.PrivateKey
+}
+
 func (c *Conn) selfNode() *Node {
 	endpoints := make([]string, 0, len(c.lastEndpoints))
 	for _, addr := range c.lastEndpoints {
@@ -2,12 +2,14 @@ package tailnet_test
 
 import (
 	"context"
+	"net"
 	"net/netip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"tailscale.com/types/key"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
@@ -35,6 +37,20 @@ func TestTailnet(t *testing.T) {
 		err = conn.Close()
 		require.NoError(t, err)
 	})
+	t.Run("NodePrivateKey", func(t *testing.T) {
+		t.Parallel()
+		nodeKey := key.NewNode()
+		conn, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:      []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+			Logger:         logger.Named("w1"),
+			DERPMap:        derpMap,
+			NodePrivateKey: nodeKey,
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+		require.True(t, nodeKey.Equal(conn.NodePrivateKey()))
+		require.Equal(t, nodeKey.Public(), conn.Node().Key)
+	})
 	t.Run("Connect", func(t *testing.T) {
 		t.Parallel()
 		w1IP := tailnet.IP()
@@ -196,6 +212,83 @@ func TestConn_PreferredDERP(t *testing.T) {
 	}
 }
 
+func TestConn_DNSHosts(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	derpMap, _ := tailnettest.RunDERPAndSTUN(t)
+
+	w1IP := tailnet.IP()
+	w1, err := tailnet.NewConn(&tailnet.Options{
+		Addresses: []netip.Prefix{netip.PrefixFrom(w1IP, 128)},
+		Logger:    logger.Named("w1"),
+		DERPMap:   derpMap,
+	})
+	require.NoError(t, err)
+	w2, err := tailnet.NewConn(&tailnet.Options{
+		Addresses: []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+		Logger:    logger.Named("w2"),
+		DERPMap:   derpMap,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = w1.Close()
+		_ = w2.Close()
+	})
+	w1.SetNodeCallback(func(node *tailnet.Node) {
+		err := w2.UpdateNodes([]*tailnet.Node{node}, false)
+		assert.NoError(t, err)
+	})
+	w2.SetNodeCallback(func(node *tailnet.Node) {
+		err := w1.UpdateNodes([]*tailnet.Node{node}, false)
+		assert.NoError(t, err)
+	})
+	require.True(t, w2.AwaitReachable(ctx, w1IP))
+
+	err = w2.SetDNSHosts(map[string][]netip.Addr{
+		"not-coder.example.com": {w1IP},
+	})
+	require.Error(t, err)
+
+	name := tailnet.AgentDNSName("main", "Dev", "alice")
+	require.Equal(t, "main.dev.alice.coder", name)
+	err = w2.SetDNSHosts(map[string][]netip.Addr{
+		name: {w1IP},
+	})
+	require.NoError(t, err)
+	addrs, ok := w2.LookupHost("MAIN.dev.alice.coder.")
+	require.True(t, ok)
+	require.Equal(t, []netip.Addr{w1IP}, addrs)
+
+	listener, err := w1.Listen("tcp", ":35566")
+	require.NoError(t, err)
+	defer listener.Close()
+	accepted := make(chan struct{})
+	go func() {
+		defer close(accepted)
+		nc, err := listener.Accept()
+		if !assert.NoError(t, err) {
+			return
+		}
+		_ = nc.Close()
+	}()
+	nc, err := w2.DialContext(ctx, "tcp", name+":35566")
+	require.NoError(t, err)
+	_ = nc.Close()
+	<-accepted
+
+	_, err = w2.DialContext(ctx, "tcp", "other.dev.alice.coder:35566")
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+	require.True(t, dnsErr.IsNotFound)
+
+	// Replacing the hosts removes names that are gone.
+	err = w2.SetDNSHosts(nil)
+	require.NoError(t, err)
+	_, ok = w2.LookupHost(name)
+	require.False(t, ok)
+}
+
 // TestConn_UpdateDERP tests that when update the DERP map we pick a new
 // preferred DERP server and new connections can be made from clients.
 func TestConn_UpdateDERP(t *testing.T) {
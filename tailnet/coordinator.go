@@ -38,6 +38,12 @@ type Coordinator interface {
 	// ServeClient accepts a WebSocket connection that wants to connect to an agent
 	// with the specified ID.
 	ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error
+	// ServeUserClient is ServeClient for a client that authenticated as the
+	// user, so that the user can be looked up with ClientUser.
+	ServeUserClient(conn net.Conn, id uuid.UUID, agent uuid.UUID, user uuid.UUID) error
+	// ClientUser returns the user of the client that announced the address
+	// while coordinating with the agent.
+	ClientUser(ctx context.Context, agent uuid.UUID, addr netip.Addr) (uuid.UUID, bool, error)
 	// ServeAgent accepts a WebSocket connection to an agent that listens to
 	// incoming connections and publishes node updates.
 	// Name is just used for debug information. It can be left blank.
@@ -79,6 +85,16 @@ type Node struct {
 	Endpoints []string `json:"endpoints"`
 }
 
+// HasAddress reports whether the node announced the address.
+func (n *Node) HasAddress(addr netip.Addr) bool {
+	for _, prefix := range n.Addresses {
+		if prefix.Addr().Unmap() == addr.Unmap() {
+			return true
+		}
+	}
+	return false
+}
+
 // ServeCoordinator matches the RW structure of a coordinator to exchange node messages.
 func ServeCoordinator(conn net.Conn, updateNodes func(node []*Node) error) (func(node *Node), <-chan error) {
 	errChan := make(chan error, 1)
@@ -179,6 +195,8 @@ type core struct {
 	clients map[uuid.UUID]Queue
 	// clientsToAgents is an index of clients to all of their subscribed agents.
 	clientsToAgents map[uuid.UUID]map[uuid.UUID]Queue
+	// clientUsers maps client IDs to the users they authenticated as.
+	clientUsers map[uuid.UUID]uuid.UUID
 
 	// agentNameCache holds a cache of agent names. If one of them disappears,
 	// it's helpful to have a name cached for debugging.
@@ -219,6 +237,7 @@ func newCore(logger slog.Logger) *core {
 		legacyAgents:             map[uuid.UUID]struct{}{},
 		clients:                  map[uuid.UUID]Queue{},
 		clientsToAgents:          map[uuid.UUID]map[uuid.UUID]Queue{},
+		clientUsers:              map[uuid.UUID]uuid.UUID{},
 	}
 }
 
@@ -259,6 +278,11 @@ func (c *core) agentCount() int {
 // ServeClient accepts a WebSocket connection that wants to connect to an agent
 // with the specified ID.
 func (c *coordinator) ServeClient(conn net.Conn, id, agentID uuid.UUID) error {
+	return c.ServeUserClient(conn, id, agentID, uuid.Nil)
+}
+
+// ServeUserClient is ServeClient for a client that authenticated as the user.
+func (c *coordinator) ServeUserClient(conn net.Conn, id, agentID, userID uuid.UUID) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	logger := c.core.clientLogger(id, agentID)
@@ -269,6 +293,9 @@ func (c *coordinator) ServeClient(conn net.Conn, id, agentID uuid.UUID) error {
 
 	c.core.addClient(id, tc)
 	defer c.core.clientDisconnected(id)
+	if userID != uuid.Nil {
+		c.core.setClientUser(id, userID)
+	}
 
 	agentNode, err := c.core.clientSubscribeToAgent(tc, agentID)
 	if err != nil {
@@ -299,6 +326,35 @@ func (c *coordinator) ServeClient(conn net.Conn, id, agentID uuid.UUID) error {
 	}
 }
 
+func (c *core) setClientUser(id, userID uuid.UUID) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.clientUsers[id] = userID
+}
+
+// ClientUser returns the user of the client that announced the address while
+// coordinating with the agent.
+func (c *coordinator) ClientUser(_ context.Context, agentID uuid.UUID, addr netip.Addr) (uuid.UUID, bool, error) {
+	userID, ok := c.core.clientUser(agentID, addr)
+	return userID, ok, nil
+}
+
+func (c *core) clientUser(agentID uuid.UUID, addr netip.Addr) (uuid.UUID, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for id := range c.agentToConnectionSockets[agentID] {
+		userID, ok := c.clientUsers[id]
+		if !ok {
+			continue
+		}
+		node, ok := c.nodes[id]
+		if ok && node.HasAddress(addr) {
+			return userID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
 func (c *core) clientLogger(id, agent uuid.UUID) slog.Logger {
 	return c.logger.With(slog.F("client_id", id), slog.F("agent_id", agent))
 }
@@ -338,6 +394,7 @@ func (c *core) clientDisconnected(id uuid.UUID) {
 
 	delete(c.clients, id)
 	delete(c.clientsToAgents, id)
+	delete(c.clientUsers, id)
 	logger.Debug(context.Background(), "deleted client agents")
 }
 
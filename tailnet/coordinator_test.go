@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -74,6 +75,44 @@ func TestCoordinator(t *testing.T) {
 		<-closeChan
 	})
 
+	t.Run("ClientUser", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		coordinator := tailnet.NewCoordinator(logger)
+		client, server := net.Pipe()
+		sendNode, errChan := tailnet.ServeCoordinator(client, func(node []*tailnet.Node) error {
+			return nil
+		})
+		agentID := uuid.New()
+		userID := uuid.New()
+		closeChan := make(chan struct{})
+		go func() {
+			err := coordinator.ServeUserClient(server, uuid.New(), agentID, userID)
+			assert.NoError(t, err)
+			close(closeChan)
+		}()
+		addr := tailnet.IP()
+		sendNode(&tailnet.Node{Addresses: []netip.Prefix{netip.PrefixFrom(addr, 128)}})
+		ctx := testutil.Context(t, testutil.WaitShort)
+		require.Eventually(t, func() bool {
+			user, ok, err := coordinator.ClientUser(ctx, agentID, addr)
+			return assert.NoError(t, err) && ok && user == userID
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// The client only coordinates with its agent.
+		_, ok, err := coordinator.ClientUser(ctx, uuid.New(), addr)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, client.Close())
+		require.NoError(t, server.Close())
+		<-errChan
+		<-closeChan
+		_, ok, err = coordinator.ClientUser(ctx, agentID, addr)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
 	t.Run("AgentWithClient", func(t *testing.T) {
 		t.Parallel()
 		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
@@ -0,0 +1,99 @@
+package tailnet
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"tailscale.com/util/dnsname"
+)
+
+// CoderDNSSuffix is the top-level domain of the names that resolve to
+// workspace agents, e.g. "main.dev.alice.coder".
+const CoderDNSSuffix = "coder"
+
+// AgentDNSName returns the name that resolves to the given agent:
+// <agent>.<workspace>.<owner>.coder. Names are case-insensitive, so they are
+// always lowercase.
+func AgentDNSName(agentName, workspaceName, ownerName string) string {
+	return strings.ToLower(strings.Join([]string{agentName, workspaceName, ownerName, CoderDNSSuffix}, "."))
+}
+
+// IsCoderDNSName reports whether name is under CoderDNSSuffix.
+func IsCoderDNSName(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	return strings.HasSuffix(name, "."+CoderDNSSuffix)
+}
+
+// SetDNSHosts replaces the names resolved by the connection. Every name must
+// be under CoderDNSSuffix. The hosts are also served by the resolver of the
+// netstack, so DNS queries sent through the connection resolve them as well.
+func (c *Conn) SetDNSHosts(hosts map[string][]netip.Addr) error {
+	dnsHosts := make(map[dnsname.FQDN][]netip.Addr, len(hosts))
+	for name, addrs := range hosts {
+		if !IsCoderDNSName(name) {
+			return xerrors.Errorf("name %q is not under %q", name, CoderDNSSuffix)
+		}
+		fqdn, err := dnsname.ToFQDN(strings.ToLower(name))
+		if err != nil {
+			return xerrors.Errorf("parse name %q: %w", name, err)
+		}
+		dnsHosts[fqdn] = addrs
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.dnsHosts = dnsHosts
+	err := c.reconfig()
+	if err != nil {
+		return xerrors.Errorf("reconfig: %w", err)
+	}
+	return nil
+}
+
+// LookupHost returns the addresses of a name set with SetDNSHosts.
+func (c *Conn) LookupHost(name string) ([]netip.Addr, bool) {
+	fqdn, err := dnsname.ToFQDN(strings.ToLower(name))
+	if err != nil {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	addrs, ok := c.dnsHosts[fqdn]
+	return addrs, ok
+}
+
+// DialContext dials address over the connection. The host of the address is
+// either an IP or a name set with SetDNSHosts. The network must be "tcp" or
+// "udp".
+func (c *Conn) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, rawPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, xerrors.Errorf("split host port %q: %w", address, err)
+	}
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		return nil, xerrors.Errorf("parse port %q: %w", rawPort, err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		addrs, ok := c.LookupHost(host)
+		if !ok || len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		ip = addrs[0]
+	}
+
+	ipp := netip.AddrPortFrom(ip, uint16(port))
+	switch network {
+	case "tcp":
+		return c.DialContextTCP(ctx, ipp)
+	case "udp":
+		return c.DialContextUDP(ctx, ipp)
+	default:
+		return nil, xerrors.Errorf("unknown network %q", network)
+	}
+}